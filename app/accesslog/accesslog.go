@@ -0,0 +1,155 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// Formats of the access log lines
+const (
+	FormatCommon   = "common"
+	FormatCombined = "combined"
+	FormatJSON     = "json"
+)
+
+// clfTimeFormat is the time format of the common and combined log formats
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// entry is an access log entry, also being the line written in JSON format
+type entry struct {
+	Time      time.Time `json:"time"`
+	RemoteIP  string    `json:"remote_ip"`
+	User      string    `json:"user,omitempty"`
+	Method    string    `json:"method"`
+	URI       string    `json:"uri"`
+	Protocol  string    `json:"protocol"`
+	Status    int       `json:"status"`
+	Bytes     int       `json:"bytes"`
+	Referer   string    `json:"referer,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Latency   float64   `json:"latency_ms"`
+	RequestID string    `json:"request_id,omitempty"`
+}
+
+// responseRecorder records the status code and the size of the body written by a handler
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Middleware writes a line per served request to the given writer, in the common or combined log format or as JSON.
+// The client IP, user and request ID are taken from the request info of the request context, when set by the logging middleware.
+func Middleware(w io.Writer, format string) (func(http.Handler) http.Handler, error) {
+	var marshal func(entry) []byte
+	switch format {
+	case FormatCommon:
+		marshal = func(e entry) []byte { return []byte(commonLine(e) + "\n") }
+	case FormatCombined:
+		marshal = func(e entry) []byte {
+			return []byte(fmt.Sprintf("%s \"%s\" \"%s\"\n", commonLine(e), orDash(escape(e.Referer)), orDash(escape(e.UserAgent))))
+		}
+	case FormatJSON:
+		marshal = func(e entry) []byte {
+			line, _ := json.Marshal(e)
+			return append(line, '\n')
+		}
+	default:
+		return nil, fmt.Errorf("access log format %q not valid, it must be %s, %s or %s", format, FormatCommon, FormatCombined, FormatJSON)
+	}
+
+	// the lines of concurrent requests are written one at a time, so they are not interleaved
+	var mu sync.Mutex
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &responseRecorder{ResponseWriter: rw, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			info := models.RequestInfoFrom(r.Context())
+			e := entry{
+				Time:      start,
+				RemoteIP:  info.IP,
+				User:      info.ActorID,
+				Method:    r.Method,
+				URI:       r.URL.RequestURI(),
+				Protocol:  r.Proto,
+				Status:    rec.status,
+				Bytes:     rec.bytes,
+				Referer:   r.Referer(),
+				UserAgent: r.UserAgent(),
+				Latency:   float64(time.Since(start).Microseconds()) / 1000,
+				RequestID: info.RequestID,
+			}
+			if e.RemoteIP == "" {
+				e.RemoteIP = remoteHost(r)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			w.Write(marshal(e))
+		})
+	}, nil
+}
+
+// commonLine returns the entry in the common log format, like 127.0.0.1 - user-id [15/Jul/2023:09:00:00 +0000] "GET /health HTTP/1.1" 200 2
+func commonLine(e entry) string {
+	bytes := "-"
+	if e.Bytes > 0 {
+		bytes = strconv.Itoa(e.Bytes)
+	}
+	return fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s",
+		orDash(e.RemoteIP), orDash(escape(e.User)), e.Time.Format(clfTimeFormat), e.Method, escape(e.URI), e.Protocol, e.Status, bytes)
+}
+
+// escape escapes the quotes, backslashes and control characters of a value taken from the request, so it cannot forge log lines
+func escape(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, "\\x%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,96 @@
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(t *testing.T, format string, req *http.Request) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	middleware, err := Middleware(&buf, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("test-body"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	return buf.String()
+}
+
+// TestMiddleware_Common checks that Middleware writes the requests in the common log format, with the request info of the request context
+func TestMiddleware_Common(t *testing.T) {
+	// Arrange
+	req := httptest.NewRequest(http.MethodPost, "http://testing/v1/users?take=1", nil)
+	req = req.WithContext(models.WithRequestInfo(req.Context(), models.RequestInfo{ActorID: "test-id", IP: "203.0.113.1"}))
+
+	// Act
+	line := serve(t, FormatCommon, req)
+
+	// Assert
+	assert.Regexp(t, regexp.MustCompile(`^203\.0\.113\.1 - test-id \[.+\] "POST /v1/users\?take=1 HTTP/1\.1" 201 9\n$`), line)
+}
+
+// TestMiddleware_Combined checks that Middleware writes the requests in the combined log format, escaping the quotes of the request values
+func TestMiddleware_Combined(t *testing.T) {
+	// Arrange
+	req := httptest.NewRequest(http.MethodGet, "http://testing/health", nil)
+	req.Header.Set("Referer", "http://testing/")
+	req.Header.Set("User-Agent", `test "agent"`)
+
+	// Act
+	line := serve(t, FormatCombined, req)
+
+	// Assert
+	assert.Regexp(t, regexp.MustCompile(`^192\.0\.2\.1 - - \[.+\] "GET /health HTTP/1\.1" 201 9 "http://testing/" "test \\"agent\\""\n$`), line)
+}
+
+// TestMiddleware_JSON checks that Middleware writes the requests as JSON lines
+func TestMiddleware_JSON(t *testing.T) {
+	// Arrange
+	req := httptest.NewRequest(http.MethodGet, "http://testing/health", nil)
+	req = req.WithContext(models.WithRequestInfo(req.Context(), models.RequestInfo{RequestID: "test-request-id", IP: "203.0.113.1"}))
+
+	// Act
+	line := serve(t, FormatJSON, req)
+
+	// Assert
+	var e map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "203.0.113.1", e["remote_ip"])
+	assert.Equal(t, "/health", e["uri"])
+	assert.Equal(t, float64(http.StatusCreated), e["status"])
+	assert.Equal(t, float64(9), e["bytes"])
+	assert.Equal(t, "test-request-id", e["request_id"])
+	assert.NotContains(t, e, "user")
+}
+
+// TestMiddleware_InvalidFormat checks that Middleware returns an error when the format is not valid
+func TestMiddleware_InvalidFormat(t *testing.T) {
+	// Act
+	_, err := Middleware(&bytes.Buffer{}, "invalid")
+
+	// Assert
+	assert.ErrorContains(t, err, "not valid")
+}
+
+// TestEscape_ControlCharacters checks that escape escapes the control characters, so a value cannot add lines
+func TestEscape_ControlCharacters(t *testing.T) {
+	// Act
+	escaped := escape("test\nline")
+
+	// Assert
+	assert.Equal(t, `test\x0aline`, escaped)
+}
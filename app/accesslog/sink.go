@@ -0,0 +1,50 @@
+package accesslog
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Outputs of the access log
+const (
+	OutputStdout = "stdout"
+	OutputFile   = "file"
+	OutputSyslog = "syslog"
+)
+
+// NewWriter creates the writer of the access log lines for the configured output:
+// the standard output, a file rotated once it reaches its maximum size, or syslog, local or remote
+func NewWriter(cfg config.AccessLog) (io.WriteCloser, error) {
+	switch cfg.Output {
+	case OutputStdout, "":
+		return nopCloser{os.Stdout}, nil
+	case OutputFile:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("access log path cannot be empty")
+		}
+		return &lumberjack.Logger{
+			Filename:   cfg.Path,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAgeDays,
+			Compress:   cfg.Compress,
+		}, nil
+	case OutputSyslog:
+		return newSyslogWriter(cfg.SyslogNetwork, cfg.SyslogAddress, cfg.SyslogTag)
+	default:
+		return nil, fmt.Errorf("access log output %q not valid, it must be %s, %s or %s", cfg.Output, OutputStdout, OutputFile, OutputSyslog)
+	}
+}
+
+// nopCloser does not close the standard output when closing the access log
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,46 @@
+package accesslog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewWriter_File checks that NewWriter returns a writer appending the lines to the configured file
+func TestNewWriter_File(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "access.log")
+
+	// Act
+	w, err := NewWriter(config.AccessLog{Output: OutputFile, Path: path, MaxSizeMB: 1})
+
+	// Assert
+	assert.Nil(t, err)
+	_, err = w.Write([]byte("test-line\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-line\n", string(content))
+}
+
+// TestNewWriter_EmptyPath checks that NewWriter returns an error when the file output has no path
+func TestNewWriter_EmptyPath(t *testing.T) {
+	// Act
+	_, err := NewWriter(config.AccessLog{Output: OutputFile})
+
+	// Assert
+	assert.ErrorContains(t, err, "path cannot be empty")
+}
+
+// TestNewWriter_InvalidOutput checks that NewWriter returns an error when the output is not valid
+func TestNewWriter_InvalidOutput(t *testing.T) {
+	// Act
+	_, err := NewWriter(config.AccessLog{Output: "invalid"})
+
+	// Assert
+	assert.ErrorContains(t, err, "not valid")
+}
@@ -0,0 +1,14 @@
+//go:build !windows && !plan9
+
+package accesslog
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// newSyslogWriter connects to the syslog server at the given address, or to the local one when the network is empty,
+// writing the lines with the info severity of the local0 facility
+func newSyslogWriter(network, address, tag string) (io.WriteCloser, error) {
+	return syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_LOCAL0, tag)
+}
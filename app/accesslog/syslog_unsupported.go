@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package accesslog
+
+import (
+	"fmt"
+	"io"
+)
+
+// newSyslogWriter fails, as syslog is not supported on this platform
+func newSyslogWriter(network, address, tag string) (io.WriteCloser, error) {
+	return nil, fmt.Errorf("access log output %s not supported on this platform", OutputSyslog)
+}
@@ -2,98 +2,677 @@ package api
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"net/http"
-	"path/filepath"
-	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/accesslog"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/scheduler"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/worker"
+	"github.com/sergicanet9/go-hexagonal-api/app/buildinfo"
+	"github.com/sergicanet9/go-hexagonal-api/app/capture"
+	"github.com/sergicanet9/go-hexagonal-api/app/compress"
+	"github.com/sergicanet9/go-hexagonal-api/app/cors"
 	_ "github.com/sergicanet9/go-hexagonal-api/app/docs" // docs is generated by Swag CLI, needs to be imported.
+	"github.com/sergicanet9/go-hexagonal-api/app/domain"
 	"github.com/sergicanet9/go-hexagonal-api/app/handlers"
+	"github.com/sergicanet9/go-hexagonal-api/app/logging"
+	"github.com/sergicanet9/go-hexagonal-api/app/middleware"
+	"github.com/sergicanet9/go-hexagonal-api/app/preflight"
+	"github.com/sergicanet9/go-hexagonal-api/app/ratelimit"
+	"github.com/sergicanet9/go-hexagonal-api/app/rpc"
+	"github.com/sergicanet9/go-hexagonal-api/app/subscription"
+	"github.com/sergicanet9/go-hexagonal-api/app/upgrade"
 	"github.com/sergicanet9/go-hexagonal-api/config"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/go-hexagonal-api/core/services"
-	"github.com/sergicanet9/go-hexagonal-api/infrastructure/mongo"
-	"github.com/sergicanet9/go-hexagonal-api/infrastructure/postgres"
-	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/awsauth"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/directorysync"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/encryption"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/geoip"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/ldap"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/notify"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/redis"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/retry"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/s3"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+	usersv1 "github.com/sergicanet9/go-hexagonal-api/proto/users/v1"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
 )
 
+// tracingShutdownTimeout is the maximum time given to export the pending spans on shutdown
+const tracingShutdownTimeout = 5 * time.Second
+
+// errDraining is reported by the shutdown health check once the API is shutting down, so no more requests are routed to it
+var errDraining = errors.New("draining the requests before shutting down")
+
 type api struct {
-	config   config.Config
-	services svs
+	config              config.Config
+	logger              zerolog.Logger
+	requestLevel        zerolog.Level
+	requestSampler      *logging.Sampler
+	rateLimit           *ratelimit.Limit
+	corsOrigins         *cors.Origins
+	accessLog           io.WriteCloser
+	accessLogMiddleware func(http.Handler) http.Handler
+	tracerProvider      *sdktrace.TracerProvider
+	userStore           ports.UserRepository
+	keyProvider         encryption.KeyProvider
+	fieldCipher         *encryption.FieldCipher
+	draining            *atomic.Bool
+	limits              ports.LimitStore
+	leases              ports.LeaseStore
+	scheduler           *scheduler.Scheduler
+	workers             *worker.Pool
+	upgrader            *upgrade.Upgrader
+	domains             *domain.Registry
+	services            svs
 }
 
 type svs struct {
-	user ports.UserService
+	user        ports.UserService
+	backup      ports.BackupService
+	job         ports.JobService
+	audit       ports.AuditService
+	retention   ports.RetentionService
+	health      ports.HealthService
+	capture     ports.CaptureService
+	keys        ports.KeyService
+	maintenance ports.MaintenanceService
+	sms         ports.SMSService
+	device      ports.DeviceService
+	billing     ports.BillingService
+	search      ports.SearchService
+	scan        ports.ScanService
+	directory   ports.DirectorySyncService
+	dashboard   ports.DashboardService
+	// organization the organization service, only set when the organizations are stored
+	organization ports.OrganizationService
+	// role the role service, only set when the roles are stored
+	role ports.RoleService
+	// apiKey the API key service, only set when the API keys are stored
+	apiKey ports.APIKeyService
+	// notificationPreferences the notification preferences service, only set when the preferences are stored
+	notificationPreferences ports.NotificationPreferencesService
+	// securityPolicy the security policy service, only set when the policies are stored
+	securityPolicy ports.SecurityPolicyService
 }
 
-// New creates a new API
-func New(ctx context.Context, cfg config.Config) (a api) {
+// New creates a new API, waiting for the database to be reachable and ready.
+// The API is not run, so it does not report itself healthy, until the indexes or the migrations have been verified.
+func New(ctx context.Context, cfg config.Config, logger zerolog.Logger) (a api) {
 	a.config = cfg
+	a.logger = logger
+	a.scheduler = scheduler.New(logger)
+	start := time.Now()
 
-	var userRepo ports.UserRepository
-	switch a.config.Database {
-	case "mongo":
-		db, err := infrastructure.ConnectMongoDB(ctx, a.config.DSN)
+	var err error
+	a.upgrader, err = upgrade.New(logger, a.config.Upgrade.ReusePort, a.config.Upgrade.Timeout.Duration)
+	if err != nil {
+		a.logger.Fatal().Err(err).Msg("inherited listeners not valid")
+	}
+	a.requestLevel, err = logging.ParseLevel(a.config.Log.RequestLevel)
+	if err != nil {
+		a.logger.Fatal().Err(err).Msg("request log level not valid")
+	}
+	a.requestSampler, err = logging.NewSampler(a.config.Log.RequestSampleRatio, a.config.Log.RouteSampleRatios)
+	if err != nil {
+		a.logger.Fatal().Err(err).Msg("request log sampling not valid")
+	}
+	if a.config.RateLimit.Enabled {
+		a.rateLimit, err = ratelimit.NewLimit(a.config.RateLimit.Requests, a.config.RateLimit.Window.Duration)
 		if err != nil {
-			log.Fatal(err)
+			a.logger.Fatal().Err(err).Msg("rate limit not valid")
 		}
+	}
+	a.corsOrigins = cors.NewOrigins(a.config.Middleware.CORS.AllowedOrigins)
 
-		userRepo, err = mongo.NewUserRepository(ctx, db)
+	if a.config.AccessLog.Enabled {
+		a.accessLog, err = accesslog.NewWriter(a.config.AccessLog)
 		if err != nil {
-			log.Fatal(err)
+			a.logger.Fatal().Err(err).Msg("cannot open the access log")
 		}
-	case "postgres":
-		db, err := infrastructure.ConnectPostgresDB(ctx, a.config.DSN)
+		a.accessLogMiddleware, err = accesslog.Middleware(a.accessLog, a.config.AccessLog.Format)
 		if err != nil {
-			log.Fatal(err)
+			a.logger.Fatal().Err(err).Msg("access log not valid")
 		}
+	}
+
+	buildinfo.Publish(buildinfo.Get().WithVersion(a.config.Version))
 
-		_, filePath, _, _ := runtime.Caller(0)
-		migrationsDir := filepath.Join(filePath, "../../..", cfg.PostgresMigrationsDir)
-		err = infrastructure.MigratePostgresDB(db, migrationsDir)
+	// without tracing the instrumentation uses a provider whose spans are not recorded
+	var tp trace.TracerProvider = noop.NewTracerProvider()
+	if a.config.Tracing.Enabled {
+		a.tracerProvider, err = tracing.NewTracerProvider(ctx, a.config.Tracing.Endpoint, a.config.Tracing.Insecure, a.config.Tracing.ServiceName, a.config.Version, a.config.Tracing.SampleRatio, a.config.Tracing.RouteSampleRatios)
 		if err != nil {
-			log.Fatal(err)
+			a.logger.Fatal().Err(err).Msg("cannot create the tracer provider")
 		}
+		tp = a.tracerProvider
+	}
 
-		userRepo = postgres.NewUserRepository(db)
-	default:
-		log.Fatalf("database flag %s not valid", a.config.Database)
+	policy := retry.Policy{
+		Timeout:        a.config.Startup.Timeout.Duration,
+		AttemptTimeout: a.config.Startup.AttemptTimeout.Duration,
+		InitialBackoff: a.config.Startup.InitialBackoff.Duration,
+		MaxBackoff:     a.config.Startup.MaxBackoff.Duration,
+		Logger:         a.logger,
 	}
 
-	a.services.user = services.NewUserService(a.config, userRepo)
+	a.services.health = services.NewHealthService(a.config.Health.CheckTimeout.Duration, a.config.Health.StatusMaxAge.Duration, a.config.Version)
+	a.draining = new(atomic.Bool)
+	a.services.health.Register("shutdown", true, ports.HealthCheckerFunc(func(ctx context.Context) error {
+		if a.draining.Load() {
+			return errDraining
+		}
+		return nil
+	}))
+	s := a.connectDatabase(ctx, policy, tp)
+	a.decorateStores(ctx, &s, tp)
+	a.wireServices(ctx, s, tp)
+
+	a.domains = a.registerDomains(s)
+	if err = a.domains.Migrate(ctx); err != nil {
+		a.logger.Fatal().Err(err).Msg("cannot migrate the domains")
+	}
+	a.domains.RegisterHealthChecks(a.services.health)
+
+	if a.config.Preflight.Enabled {
+		_, err = preflight.Run(ctx, a.logger, a.config.Preflight.Timeout.Duration, append(s.checks, a.preflightChecks()...))
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("dependencies not ready")
+		}
+	}
+
+	a.logger.Info().Dur("elapsed", time.Since(start)).Msg("database ready")
 	return a
 }
 
-// Run API
+// middlewares returns the registry of the middlewares of the API, the ones disabled in the config being registered as nil, so they are skipped
+func (a *api) middlewares() *middleware.Registry {
+	registry := middleware.NewRegistry()
+	if a.tracerProvider != nil {
+		registry.Register(middleware.Tracing, otelhttp.NewMiddleware(a.config.Tracing.ServiceName,
+			otelhttp.WithTracerProvider(a.tracerProvider),
+			otelhttp.WithPropagators(tracing.Propagator),
+			otelhttp.WithSpanNameFormatter(routeSpanName),
+			otelhttp.WithFilter(func(r *http.Request) bool {
+				return r.URL.Path != "/health" && r.URL.Path != "/readyz" && r.URL.Path != "/status" && r.URL.Path != "/version"
+			}),
+		))
+	} else {
+		registry.Register(middleware.Tracing, tracing.Propagation)
+	}
+	registry.Register(middleware.Logging, logging.Middleware(a.logger, a.requestLevel, a.services.keys, a.config.Log.TrustProxyHeaders, a.config.Monitoring.SlowRequestThreshold.Duration, a.requestSampler))
+	registry.Register(middleware.AccessLog, a.accessLogMiddleware)
+
+	var m middleware.Middleware
+	if a.rateLimit != nil {
+		m = ratelimit.Middleware(a.limits, a.logger, a.config.Log.TrustProxyHeaders, a.rateLimit)
+	}
+	registry.Register(middleware.RateLimit, m)
+
+	m = nil
+	if a.services.billing != nil && len(a.config.Billing.SubscriptionRoutes) > 0 {
+		m = subscription.Middleware(a.services.billing, a.config.Billing.SubscriptionRoutes)
+	}
+	registry.Register(middleware.Subscription, m)
+
+	registry.Register(middleware.Capture, capture.Middleware(a.services.capture, a.logger, a.config.Capture.MaxBodySize, a.config.Timeout.Duration))
+	registry.Register(middleware.Recover, logging.Recover(a.logger))
+	c := a.config.Middleware.CORS
+	registry.Register(middleware.CORS, cors.Middleware(a.corsOrigins, c.AllowedMethods, c.AllowedHeaders, c.AllowCredentials, c.MaxAge.Duration))
+	registry.Register(middleware.Compress, compress.Middleware(a.config.Middleware.Compress.Level))
+	registry.Register(middleware.Auth, handlers.Authenticated(a.services.keys))
+	return registry
+}
+
+// usesMiddleware returns whether the middleware is in the chain of every route or of any group
+func (a *api) usesMiddleware(name string) bool {
+	chains := [][]string{a.config.Middleware.Chain}
+	if len(a.config.Middleware.Chain) == 0 {
+		chains[0] = middleware.DefaultChain
+	}
+	for _, chain := range a.config.Middleware.Groups {
+		chains = append(chains, chain)
+	}
+	for _, chain := range chains {
+		for _, m := range chain {
+			if m == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// preflightChecks returns the checks of the dependencies other than the database, the ones not configured being left out:
+// the Redis server, required, and the logins to the SMTP servers of the alerts and of the emails, only reported as the API serves without them
+func (a *api) preflightChecks() []preflight.Check {
+	var checks []preflight.Check
+	if a.config.Preflight.RedisAddress != "" {
+		checks = append(checks, preflight.Check{Name: "redis", Required: true, Run: func(ctx context.Context) (string, error) {
+			return redis.Ping(ctx, a.config.Preflight.RedisAddress, a.config.Preflight.RedisPassword)
+		}})
+	}
+	if a.config.Alerting.SMTPAddress != "" {
+		checks = append(checks, preflight.Check{Name: "smtp", Run: func(ctx context.Context) (string, error) {
+			return notify.CheckSMTP(ctx, a.config.Alerting.SMTPAddress, a.config.Alerting.SMTPUsername, a.config.Alerting.SMTPPassword)
+		}})
+	}
+	if a.config.Email.Provider == "smtp" {
+		checks = append(checks, preflight.Check{Name: "email", Run: func(ctx context.Context) (string, error) {
+			return notify.CheckSMTP(ctx, a.config.Email.SMTPAddress, a.config.Email.SMTPUsername, a.config.Email.SMTPPassword)
+		}})
+	}
+	return checks
+}
+
+// UserService returns the user service of the API, to be shared with the async processes
+func (a *api) UserService() ports.UserService {
+	return a.services.user
+}
+
+// KeyService returns the key service of the API, whose keys are rotated and refreshed by the async processes
+func (a *api) KeyService() ports.KeyService {
+	return a.services.keys
+}
+
+// LeaseStore returns the lease store of the API, to elect the leader of the async processes
+func (a *api) LeaseStore() ports.LeaseStore {
+	return a.leases
+}
+
+// LimitStore returns the limit store of the API, to purge the ended limits in the async processes
+func (a *api) LimitStore() ports.LimitStore {
+	return a.limits
+}
+
+// Scheduler returns the scheduler of the API, whose jobs are registered and run by the async processes and listed by the API
+func (a *api) Scheduler() *scheduler.Scheduler {
+	return a.scheduler
+}
+
+// Upgrade starts a new process of the API on its listeners and waits for it to be ready, so this one can drain and exit
+func (a *api) Upgrade(ctx context.Context) error {
+	return a.upgrader.Upgrade(ctx)
+}
+
+// JobService returns the job service of the API, to queue the alert notifications in the async processes
+func (a *api) JobService() ports.JobService {
+	return a.services.job
+}
+
+// BackupService returns the backup service of the API, whose queued backups and restores are run by the async processes
+func (a *api) BackupService() ports.BackupService {
+	return a.services.backup
+}
+
+// MaintenanceService returns the maintenance service of the API, whose queued tasks are run by the workers of the async processes
+func (a *api) MaintenanceService() ports.MaintenanceService {
+	return a.services.maintenance
+}
+
+// BillingService returns the billing service of the API, syncing the customers of the users in the async processes,
+// nil when Billing.Provider is not set
+func (a *api) BillingService() ports.BillingService {
+	return a.services.billing
+}
+
+// SearchService returns the search service of the API, syncing the search index in the async processes,
+// nil when Search.Enabled is not set
+func (a *api) SearchService() ports.SearchService {
+	return a.services.search
+}
+
+// ScanService returns the scan service of the API, scanning the quarantined uploads in the async processes,
+// nil when Antivirus.Provider is not set
+func (a *api) ScanService() ports.ScanService {
+	return a.services.scan
+}
+
+// DirectorySyncService returns the directory sync service of the API, whose queued syncs are run by the async processes,
+// nil when DirectorySync.Source is not set
+func (a *api) DirectorySyncService() ports.DirectorySyncService {
+	return a.services.directory
+}
+
+// DeviceService returns the device service of the API, deleting the devices whose tokens are no longer valid in the async processes,
+// nil when Push.Enabled is not set
+func (a *api) DeviceService() ports.DeviceService {
+	return a.services.device
+}
+
+// Workers returns the worker pool of the API, running the queued jobs in the async processes
+func (a *api) Workers() *worker.Pool {
+	return a.workers
+}
+
+// RetentionService returns the retention service of the API, to be shared with the async processes
+func (a *api) RetentionService() ports.RetentionService {
+	return a.services.retention
+}
+
+// AuditService returns the audit service of the API, to record the reloads of the config
+func (a *api) AuditService() ports.AuditService {
+	return a.services.audit
+}
+
+// Reconfigure applies the reloadable settings of the given config while the API runs: the log level, the sample ratios of the request logs,
+// the rate limit, when enabled, and the CORS origins. Nothing is applied when any of them is not valid.
+func (a *api) Reconfigure(cfg config.Config) error {
+	if _, err := logging.ParseLevel(cfg.Log.Level); err != nil {
+		return err
+	}
+	if a.rateLimit != nil {
+		if err := ratelimit.Validate(cfg.RateLimit.Requests, cfg.RateLimit.Window.Duration); err != nil {
+			return err
+		}
+	}
+	if err := a.requestSampler.Set(cfg.Log.RequestSampleRatio, cfg.Log.RouteSampleRatios); err != nil {
+		return err
+	}
+	if a.rateLimit != nil {
+		a.rateLimit.Set(cfg.RateLimit.Requests, cfg.RateLimit.Window.Duration)
+	}
+	a.corsOrigins.Set(cfg.Middleware.CORS.AllowedOrigins)
+	return logging.SetLevel(cfg.Log.Level)
+}
+
+// Run API until the context is done, then shuts it down gracefully, returning once the requests in flight are completed
 func (a *api) Run(ctx context.Context, cancel context.CancelFunc) func() error {
 	return func() error {
 		defer cancel()
 
+		// the requests are served with a context only canceled once the server is shut down, so the ones in flight can be completed
+		serveCtx, stopServing := context.WithCancel(context.Background())
+		defer stopServing()
+
 		router := mux.NewRouter()
+		chain := a.config.Middleware.Chain
+		if len(chain) == 0 {
+			chain = middleware.DefaultChain
+		}
+		groups, err := a.middlewares().Groups(chain, a.config.Middleware.Groups)
+		if err != nil {
+			return err
+		}
+		router.Use(mux.MiddlewareFunc(groups))
 
-		handlers.SetHealthRoutes(ctx, a.config, router)
-		handlers.SetUserRoutes(ctx, a.config, router, a.services.user)
+		handlers.SetHealthRoutes(serveCtx, a.config, router, a.services.health)
+		handlers.SetMetricsRoutes(serveCtx, a.config, router, a.services.keys)
+		handlers.SetConfigRoutes(serveCtx, a.config, router, a.services.keys)
+		a.domains.Routes(serveCtx, router)
+		if a.config.Diagnostics.Enabled && a.config.Diagnostics.Port == 0 {
+			handlers.SetDiagnosticsRoutes(serveCtx, a.config, router, a.services.keys)
+		}
 		router.PathPrefix("/swagger").HandlerFunc(httpSwagger.WrapHandler)
+		if a.usesMiddleware(middleware.CORS) {
+			// the preflight requests are routed through the middlewares, which answer them, as no other route matches their method
+			router.Methods(http.MethodOptions).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+		}
 
-		log.Printf("Version: %s", a.config.Version)
-		log.Printf("Environment: %s", a.config.Environment)
-		log.Printf("Database: %s", a.config.Database)
-		log.Printf("Listening on port %d", a.config.Port)
+		a.logger.Info().
+			Str("version", a.config.Version).
+			Str("environment", a.config.Environment).
+			Str("database", a.config.Database).
+			Int("port", a.config.Port).
+			Msg("listening")
 
+		listener, err := a.upgrader.Listen(ctx, "api", a.config.Port)
+		if err != nil {
+			return err
+		}
 		server := &http.Server{
-			Addr:    fmt.Sprintf(":%d", a.config.Port),
 			Handler: router,
 		}
-		go shutdown(ctx, server)
-		return server.ListenAndServe()
+		shutdown := make(chan struct{})
+		go func() {
+			defer close(shutdown)
+			a.shutdown(ctx, server)
+		}()
+		// the connections are queued by the listener until served, so the previous process can drain right away
+		if err := a.upgrader.Ready(); err != nil {
+			a.logger.Error().Err(err).Msg("cannot report the upgrade ready")
+		}
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		<-shutdown
+		return nil
 	}
 }
 
-func shutdown(ctx context.Context, server *http.Server) {
+// RunDiagnostics runs the server of the runtime profiles and metrics on the diagnostics port,
+// so they can be reached without exposing them on the port of the API
+func (a *api) RunDiagnostics(ctx context.Context, cancel context.CancelFunc) func() error {
+	return func() error {
+		defer cancel()
+
+		router := mux.NewRouter()
+		router.Use(logging.Recover(a.logger))
+		handlers.SetMetricsRoutes(ctx, a.config, router, a.services.keys)
+		handlers.SetDiagnosticsRoutes(ctx, a.config, router, a.services.keys)
+
+		a.logger.Info().Int("port", a.config.Diagnostics.Port).Msg("diagnostics listening")
+
+		listener, err := a.upgrader.Listen(ctx, "diagnostics", a.config.Diagnostics.Port)
+		if err != nil {
+			return err
+		}
+		server := &http.Server{
+			Handler: router,
+		}
+		go func() {
+			<-ctx.Done()
+			server.Shutdown(ctx)
+		}()
+		return server.Serve(listener)
+	}
+}
+
+// RunGRPC runs the gRPC server of the users contract on its port, authenticating the calls as the requests of the API
+func (a *api) RunGRPC(ctx context.Context, cancel context.CancelFunc) func() error {
+	return func() error {
+		defer cancel()
+
+		server := grpc.NewServer(
+			grpc.ChainUnaryInterceptor(rpc.UnaryAuthInterceptor(a.services.keys)),
+			grpc.ChainStreamInterceptor(rpc.StreamAuthInterceptor(a.services.keys)),
+		)
+		usersv1.RegisterUserServiceServer(server, rpc.NewUserServer(a.config, a.services.user))
+
+		a.logger.Info().Int("port", a.config.GRPC.Port).Msg("gRPC listening")
+
+		listener, err := a.upgrader.Listen(ctx, "grpc", a.config.GRPC.Port)
+		if err != nil {
+			return err
+		}
+		go func() {
+			<-ctx.Done()
+			server.GracefulStop()
+		}()
+		return server.Serve(listener)
+	}
+}
+
+// notifyMigration notifies the admins, when Notifications.Events has migration, of the migrations applied from a version to another one,
+// or of their failure, posting the notice at once as the jobs cannot be queued before the database is migrated
+func (a *api) notifyMigration(ctx context.Context, from, to int64, migrationErr error) {
+	if !a.config.Notifications.Notifies(ports.AdminEventMigration) {
+		return
+	}
+
+	notice := ports.AdminNotice{
+		Event: ports.AdminEventMigration,
+		Text:  fmt.Sprintf("database migrated from version %d to %d", from, to),
+		Fields: map[string]string{
+			"database": a.config.Database,
+			"from":     strconv.FormatInt(from, 10),
+			"to":       strconv.FormatInt(to, 10),
+			"version":  a.config.Version,
+		},
+		At: time.Now().UTC(),
+	}
+	if migrationErr != nil {
+		notice.Text = fmt.Sprintf("database migration from version %d failed: %s", from, migrationErr)
+		notice.Fields["error"] = migrationErr.Error()
+		delete(notice.Fields, "to")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.config.Notifications.Timeout.Duration)
+	defer cancel()
+	notifier := notify.NewAdminNotifier(a.config.Notifications.SlackWebhookURL, a.config.Notifications.WebhookURLs, a.config.Notifications.WebhookSecret)
+	if err := notifier.NotifyAdmins(ctx, notice); err != nil {
+		a.logger.Error().Err(err).Msg("migration cannot be notified")
+	}
+}
+
+// newS3Storage creates the file storage on S3, signing with the standard AWS environment variables when no access key is set,
+// and in us-east-1, as the S3 compatible services accept, when no region is set
+func newS3Storage(cfg config.Storage) (ports.FileStorage, error) {
+	credentials := awsauth.Credentials{AccessKeyID: cfg.S3AccessKeyID, SecretAccessKey: cfg.S3SecretAccessKey}
+	if credentials.AccessKeyID == "" {
+		credentials = awsauth.EnvCredentials()
+	}
+	region := cfg.S3Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return s3.NewFileStorage(cfg.S3Endpoint, region, cfg.S3Bucket, cfg.S3Prefix, cfg.S3PathStyle, credentials, http.DefaultClient)
+}
+
+// newGeoIPLocator creates the locator of the client IPs, caching the lookups of the ipinfo API, as the MaxMind database is read in memory
+func newGeoIPLocator(cfg config.GeoIP) (ports.GeoIPLocator, error) {
+	locator, err := geoip.NewLocator(cfg.Provider, cfg.DatabasePath, cfg.IPInfoToken)
+	if err != nil || cfg.Provider != "ipinfo" {
+		return locator, err
+	}
+	return geoip.NewCachedLocator(locator, cfg.CacheTTL.Duration, cfg.CacheMaxEntries), nil
+}
+
+// newCredentialVerifier creates the verifier of the passwords of the users of the tenants of the LDAP directories
+func newCredentialVerifier(cfg config.LDAP) (ports.CredentialVerifier, error) {
+	directories := make([]ldap.Directory, 0, len(cfg.Directories))
+	for _, d := range cfg.Directories {
+		directories = append(directories, ldap.Directory(d))
+	}
+	return ldap.NewVerifier(directories, cfg.Timeout.Duration)
+}
+
+// newDirectorySource creates the source of the users of the directory sync, the ldap one listing the entries of the LDAP directories
+func newDirectorySource(cfg config.DirectorySync, ldapCfg config.LDAP) (ports.DirectorySource, error) {
+	switch cfg.Source {
+	case "sftp":
+		return directorysync.NewSFTPSource(cfg.SFTPAddress, cfg.SFTPUsername, cfg.SFTPPassword, cfg.SFTPPrivateKey, cfg.SFTPHostKey, cfg.SFTPPath)
+	case "http":
+		return directorysync.NewHTTPSource(cfg.HTTPURL, cfg.HTTPToken, http.DefaultClient), nil
+	case "ldap":
+		directories := make([]ldap.Directory, 0, len(ldapCfg.Directories))
+		for _, d := range ldapCfg.Directories {
+			directories = append(directories, ldap.Directory(d))
+		}
+		return ldap.NewSource(directories, ldapCfg.Timeout.Duration)
+	default:
+		return nil, fmt.Errorf("directory source %s not valid", cfg.Source)
+	}
+}
+
+func newFieldCipher(ctx context.Context, cfg config.Encryption) (encryption.KeyProvider, *encryption.FieldCipher, error) {
+	provider, err := encryption.NewKeyProvider(cfg.KMSProvider, cfg.LocalMasterKey, cfg.AzureKeyURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(cfg.DataKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("data key not valid: %w", err)
+	}
+
+	dataKey, err := provider.UnwrapKey(ctx, wrappedKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot unwrap data key: %w", err)
+	}
+
+	cipher, err := encryption.NewFieldCipher(dataKey)
+	return provider, cipher, err
+}
+
+// RotateDataKey generates a new data key for the field level encryption, re-encrypts the fields of every user with it
+// and returns it wrapped by the KMS provider, to be set as Encryption.DataKey before starting the API again.
+// The API must not be running meanwhile, or the users it writes would be left encrypted with the previous key.
+func (a *api) RotateDataKey(ctx context.Context) (string, error) {
+	if a.fieldCipher == nil {
+		return "", fmt.Errorf("field level encryption not enabled")
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	wrappedKey, err := a.keyProvider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return "", fmt.Errorf("cannot wrap data key: %w", err)
+	}
+	cipher, err := encryption.NewFieldCipher(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	count, err := encryption.RotateUsers(ctx, a.userStore, a.fieldCipher, cipher, a.config.Encryption.Fields)
+	if err != nil {
+		return "", fmt.Errorf("cannot re-encrypt the users: %w", err)
+	}
+	a.logger.Info().Int("users", count).Msg("users re-encrypted with the new data key")
+	return base64.StdEncoding.EncodeToString(wrappedKey), nil
+}
+
+// routeSpanName names the span of a request after its method and route, like GET /v1/users/{id}, so that the span names do not grow with the IDs
+// and the traces can be sampled per route
+func routeSpanName(_ string, r *http.Request) string {
+	return logging.Route(r)
+}
+
+// shutdown waits for the context to be done, then reports the API not ready and waits for the drain delay,
+// so the load balancers stop routing requests to it, before shutting the server down, giving the requests in flight up to the shutdown timeout
+func (a *api) shutdown(ctx context.Context, server *http.Server) {
 	<-ctx.Done()
-	log.Printf("Shutting down API gracefully...")
-	server.Shutdown(ctx)
+	a.draining.Store(true)
+	a.logger.Info().Dur("drain_delay", a.config.Shutdown.DrainDelay.Duration).Msg("shutting down API gracefully")
+	time.Sleep(a.config.Shutdown.DrainDelay.Duration)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.config.Shutdown.Timeout.Duration)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		a.logger.Error().Err(err).Msg("requests in flight not completed before the shutdown timeout")
+		server.Close()
+	}
+
+	if a.tracerProvider != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := a.tracerProvider.Shutdown(ctx); err != nil {
+			a.logger.Error().Err(err).Msg("cannot export the pending spans")
+		}
+	}
+
+	if a.accessLog != nil {
+		if err := a.accessLog.Close(); err != nil {
+			a.logger.Error().Err(err).Msg("cannot close the access log")
+		}
+	}
 }
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/cors"
+	"github.com/sergicanet9/go-hexagonal-api/app/logging"
+	"github.com/sergicanet9/go-hexagonal-api/app/ratelimit"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// okHandler responds with a 200
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+// testAPI creates an API limiting the requests to 10 per minute and allowing the CORS requests from https://app.example.com
+func testAPI(t *testing.T) *api {
+	sampler, err := logging.NewSampler(1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit, err := ratelimit.NewLimit(10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &api{
+		requestSampler: sampler,
+		rateLimit:      limit,
+		corsOrigins:    cors.NewOrigins([]string{"https://app.example.com"}),
+	}
+}
+
+// TestReconfigure_RateLimitAndCORS checks that Reconfigure applies the rate limit and the CORS origins to the requests served afterwards
+func TestReconfigure_RateLimitAndCORS(t *testing.T) {
+	// Arrange
+	a := testAPI(t)
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, mock.Anything, time.Hour).Return(entities.Limit{Count: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil).Once()
+	handler := ratelimit.Middleware(limitStoreMock, zerolog.Nop(), false, a.rateLimit)(cors.Middleware(a.corsOrigins, []string{http.MethodGet}, nil, false, 0)(okHandler))
+
+	var cfg config.Config
+	cfg.RateLimit.Requests = 100
+	cfg.RateLimit.Window = utils.Duration{Duration: time.Hour}
+	cfg.Middleware.CORS.AllowedOrigins = []string{"https://new.example.com"}
+	req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	req.Header.Set("Origin", "https://new.example.com")
+	rr := httptest.NewRecorder()
+
+	// Act
+	err := a.Reconfigure(cfg)
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "100", rr.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "https://new.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+}
+
+// TestReconfigure_NotValid checks that Reconfigure applies nothing when a setting is not valid
+func TestReconfigure_NotValid(t *testing.T) {
+	// Arrange
+	a := testAPI(t)
+	handler := cors.Middleware(a.corsOrigins, []string{http.MethodGet}, nil, false, 0)(okHandler)
+
+	var cfg config.Config
+	cfg.RateLimit.Requests = 0
+	cfg.RateLimit.Window = utils.Duration{Duration: time.Hour}
+	cfg.Middleware.CORS.AllowedOrigins = []string{"https://new.example.com"}
+	req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rr := httptest.NewRecorder()
+
+	// Act
+	err := a.Reconfigure(cfg)
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.EqualError(t, err, "rate limit requests 0 not valid, it must be greater than 0")
+	assert.Equal(t, "https://app.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+}
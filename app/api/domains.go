@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/app/domain"
+	"github.com/sergicanet9/go-hexagonal-api/app/handlers"
+)
+
+// registerDomains registers the business domains of the API on the services wired, the ones not enabled in the config being left out.
+// The routes of the API itself, like the health and the metrics ones, are set by Run. A new domain is registered here.
+func (a *api) registerDomains(s stores) *domain.Registry {
+	domains := []domain.Domain{
+		{
+			Name: "users",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetUserRoutes(ctx, a.config, router, a.services.keys, a.services.user)
+				if a.config.OIDC.Enabled {
+					handlers.SetOIDCRoutes(ctx, a.config, router, a.services.user)
+				}
+			},
+			Migrate:      s.shardUsers,
+			HealthChecks: []domain.HealthCheck{{Name: "storage", Checker: s.storageHealth}},
+		},
+		{
+			Name: "backups",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetBackupRoutes(ctx, a.config, router, a.services.keys, a.services.backup)
+			},
+		},
+		{
+			Name: "jobs",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetJobRoutes(ctx, a.config, router, a.services.keys, a.services.job)
+				handlers.SetSchedulerRoutes(ctx, a.config, router, a.services.keys, a.scheduler)
+			},
+		},
+		{
+			Name: "audit",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetAuditRoutes(ctx, a.config, router, a.services.keys, a.services.audit)
+			},
+		},
+		{
+			Name: "retention",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetRetentionRoutes(ctx, a.config, router, a.services.keys, a.services.retention)
+			},
+		},
+		{
+			Name: "captures",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetCaptureRoutes(ctx, a.config, router, a.services.keys, a.services.capture)
+			},
+		},
+		{
+			Name: "maintenance",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetMaintenanceRoutes(ctx, a.config, router, a.services.keys, a.services.maintenance)
+			},
+		},
+		{
+			Name: "dashboard",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetDashboardRoutes(ctx, a.config, router, a.services.keys, a.services.dashboard)
+			},
+		},
+	}
+	if a.services.sms != nil {
+		domains = append(domains, domain.Domain{
+			Name: "sms",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetSMSRoutes(ctx, a.config, router, a.services.keys, a.services.sms)
+			},
+		})
+	}
+	if a.services.device != nil {
+		domains = append(domains, domain.Domain{
+			Name: "devices",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetDeviceRoutes(ctx, a.config, router, a.services.keys, a.services.device)
+			},
+		})
+	}
+	if a.services.search != nil {
+		domains = append(domains, domain.Domain{
+			Name: "search",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetSearchRoutes(ctx, a.config, router, a.services.keys, a.services.search)
+			},
+		})
+	}
+	if a.services.directory != nil {
+		domains = append(domains, domain.Domain{
+			Name: "directory",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetDirectorySyncRoutes(ctx, a.config, router, a.services.keys, a.services.directory)
+			},
+		})
+	}
+	if a.services.organization != nil {
+		domains = append(domains, domain.Domain{
+			Name: "organizations",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetOrganizationRoutes(ctx, a.config, router, a.services.keys, a.services.organization)
+			},
+		})
+	}
+	if a.services.role != nil {
+		domains = append(domains, domain.Domain{
+			Name: "roles",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetRoleRoutes(ctx, a.config, router, a.services.keys, a.services.role)
+			},
+		})
+	}
+	if a.services.apiKey != nil {
+		domains = append(domains, domain.Domain{
+			Name: "api_keys",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetAPIKeyRoutes(ctx, a.config, router, a.services.keys, a.services.apiKey)
+			},
+		})
+	}
+	if a.services.notificationPreferences != nil {
+		domains = append(domains, domain.Domain{
+			Name: "notification_preferences",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetNotificationPreferencesRoutes(ctx, a.config, router, a.services.keys, a.services.notificationPreferences)
+			},
+		})
+	}
+	if a.services.securityPolicy != nil {
+		domains = append(domains, domain.Domain{
+			Name: "security_policies",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetSecurityPolicyRoutes(ctx, a.config, router, a.services.keys, a.services.securityPolicy)
+			},
+		})
+	}
+	if a.services.billing != nil {
+		domains = append(domains, domain.Domain{
+			Name: "billing",
+			Routes: func(ctx context.Context, router *mux.Router) {
+				handlers.SetBillingRoutes(ctx, a.config, router, a.services.billing)
+			},
+		})
+	}
+	// gen-resource:domains, the domains of the resources scaffolded by gen-resource are registered above
+
+	registry := domain.NewRegistry()
+	for _, d := range domains {
+		if err := registry.Register(d); err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot register the domains")
+		}
+	}
+	return registry
+}
@@ -0,0 +1,445 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/app/async/worker"
+	"github.com/sergicanet9/go-hexagonal-api/app/preflight"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/core/services"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/analytics"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/antivirus"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/billing"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/email"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/emailcheck"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/encryption"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/events"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/hooks"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/mongo"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/notify"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/postgres"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/push"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/redis"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/retry"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/s3"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/search"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/siem"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/sms"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// stores the adapters the services are built on, created by connectDatabase on the database of the config
+// and decorated by decorateStores. A subsystem with a store of its own adds it here, so it is created with the rest.
+type stores struct {
+	users       ports.UserRepository
+	files       ports.FileStorage
+	jobs        ports.JobRepository
+	audit       ports.AuditRepository
+	userArchive ports.RetentionRepository
+	captures    ports.CaptureRepository
+	devices     ports.DeviceRepository
+	signingKeys ports.SigningKeyStore
+	maintenance ports.MaintenanceStore
+	userChanges ports.UserChangeStream
+	locator     ports.GeoIPLocator
+	checks      []preflight.Check
+	// backupUsers the users repository of the backups, not decrypting the fields encrypted
+	backupUsers ports.UserRepository
+	// storageHealth the health check of the file storage, registered by the users domain
+	storageHealth ports.HealthChecker
+	// shardUsers shards the users collection, run as the migration of the users domain when set
+	shardUsers func(ctx context.Context) error
+	// organizations the repository of the organizations
+	organizations ports.OrganizationRepository
+	// roles the repository of the roles
+	roles ports.RoleRepository
+	// apiKeys the repository of the API keys
+	apiKeys ports.APIKeyRepository
+	// notificationPreferences the repository of the notification preferences of the users
+	notificationPreferences ports.NotificationPreferencesRepository
+	// securityPolicies the repository of the security policies of the tenants
+	securityPolicies ports.SecurityPolicyRepository
+	// gen-resource:stores, the repositories of the resources scaffolded by gen-resource are inserted above
+}
+
+// connectDatabase connects to the database of the config, retrying with the policy, and creates the stores on it,
+// registering the health check of the database. The limit and lease stores are set on the API, as they are used outside the services.
+func (a *api) connectDatabase(ctx context.Context, policy retry.Policy, tp trace.TracerProvider) (s stores) {
+	switch a.config.Database {
+	case "mongo":
+		monitor := mongo.NewCommandMonitor(a.config.Monitoring.SlowQueryThreshold.Duration, a.logger, tp)
+		var db *mongodriver.Database
+		err := policy.Do(ctx, "connection to mongo", func(ctx context.Context) (err error) {
+			db, err = mongo.Connect(ctx, a.config.DSN, monitor)
+			return err
+		})
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot connect to mongo")
+		}
+		mongo.ExportServerStatus(db)
+
+		s.users, err = mongo.NewUserRepository(ctx, db)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the user repository")
+		}
+
+		s.userChanges = mongo.NewUserChangeStream(db, "search")
+
+		if a.config.Sharding.Enabled {
+			s.shardUsers = func(ctx context.Context) error {
+				return mongo.ShardUsers(ctx, db, a.config.Sharding.Key, a.config.Sharding.Hashed)
+			}
+		}
+
+		a.services.health.Register("database", true, mongo.NewHealthChecker(db))
+		s.storageHealth = mongo.NewStorageHealthChecker(db)
+
+		s.files = mongo.NewFileStorage(db)
+		s.userArchive = mongo.NewUserArchiveRepository(db)
+
+		s.jobs, err = mongo.NewJobRepository(ctx, db)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the job repository")
+		}
+
+		s.audit, err = mongo.NewAuditRepository(ctx, db, a.config.Audit.MaxSize, a.config.Audit.MaxDocuments, a.config.Audit.Retention.Duration)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the audit repository")
+		}
+
+		s.captures, err = mongo.NewCaptureRepository(ctx, db, a.config.Capture.TTL.Duration)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the capture repository")
+		}
+
+		s.devices, err = mongo.NewDeviceRepository(ctx, db)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the device repository")
+		}
+
+		s.organizations, err = mongo.NewOrganizationRepository(ctx, db)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the organization repository")
+		}
+
+		s.roles, err = mongo.NewRoleRepository(ctx, db)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the role repository")
+		}
+
+		s.apiKeys, err = mongo.NewAPIKeyRepository(ctx, db)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the API key repository")
+		}
+		s.notificationPreferences = mongo.NewNotificationPreferencesRepository(db)
+		s.securityPolicies = mongo.NewSecurityPolicyRepository(db)
+
+		a.limits, err = mongo.NewLimitStore(ctx, db)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the limit store")
+		}
+		a.leases = mongo.NewLeaseStore(db)
+		s.signingKeys = mongo.NewSigningKeyStore(db)
+		s.maintenance = mongo.NewMaintenanceStore(db)
+		// gen-resource:mongo, the repositories of the resources scaffolded by gen-resource are created above
+
+		err = mongo.VerifyIndexes(ctx, db)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("indexes not verified")
+		}
+
+		s.checks = append(s.checks, preflight.Check{Name: "mongo", Required: true, Run: func(ctx context.Context) (string, error) {
+			return mongo.CheckServer(ctx, db, a.config.Preflight.MinMongoVersion, a.config.Preflight.RequireReplicaSet)
+		}})
+	case "postgres":
+		var db *sql.DB
+		err := policy.Do(ctx, "connection to postgres", func(ctx context.Context) (err error) {
+			db, err = infrastructure.ConnectPostgresDB(ctx, a.config.DSN)
+			if err != nil && db != nil {
+				db.Close()
+			}
+			return err
+		})
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot connect to postgres")
+		}
+
+		_, filePath, _, _ := runtime.Caller(0)
+		migrationsDir := filepath.Join(filePath, "../../..", a.config.PostgresMigrationsDir)
+		from, err := postgres.MigrationVersion(db)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot read the migration version")
+		}
+		err = infrastructure.MigratePostgresDB(db, migrationsDir)
+		if err != nil {
+			a.notifyMigration(ctx, from, from, err)
+			a.logger.Fatal().Err(err).Msg("cannot migrate the database")
+		}
+		to, err := postgres.MigrationVersion(db)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot read the migration version")
+		}
+		if to != from {
+			a.notifyMigration(ctx, from, to, nil)
+		}
+
+		err = postgres.VerifyMigrations(db, migrationsDir)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("migrations not verified")
+		}
+
+		a.services.health.Register("database", true, postgres.NewHealthChecker(db))
+		s.storageHealth = postgres.NewStorageHealthChecker(db)
+
+		s.users = postgres.NewUserRepository(db)
+		s.files = postgres.NewFileStorage(db)
+		s.jobs = postgres.NewJobRepository(db)
+		s.userArchive = postgres.NewUserArchiveRepository(db)
+		s.audit = postgres.NewAuditRepository(db, a.config.Audit.Retention.Duration)
+		s.captures = postgres.NewCaptureRepository(db, a.config.Capture.TTL.Duration)
+		s.devices = postgres.NewDeviceRepository(db)
+		s.organizations = postgres.NewOrganizationRepository(db)
+		s.roles = postgres.NewRoleRepository(db)
+		s.apiKeys = postgres.NewAPIKeyRepository(db)
+		s.notificationPreferences = postgres.NewNotificationPreferencesRepository(db)
+		s.securityPolicies = postgres.NewSecurityPolicyRepository(db)
+		a.limits = postgres.NewLimitStore(db)
+		a.leases = postgres.NewLeaseStore(db)
+		s.signingKeys = postgres.NewSigningKeyStore(db)
+		s.maintenance = postgres.NewMaintenanceStore(db)
+	default:
+		a.logger.Fatal().Msgf("database %q not valid, it must be set to mongo or postgres in the flags or the config files", a.config.Database)
+	}
+
+	return s
+}
+
+// decorateStores replaces the stores of the config, like the s3 file storage, and wraps them with the enabled decorators:
+// the siem export and the geoip location of the audit events, the encryption of the user fields and the repository hooks
+func (a *api) decorateStores(ctx context.Context, s *stores, tp trace.TracerProvider) {
+	var err error
+	if a.config.Storage.Provider == "s3" {
+		s.files, err = newS3Storage(a.config.Storage)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the s3 file storage")
+		}
+		s.storageHealth = s3.NewHealthChecker(s.files)
+	}
+
+	if a.config.SIEM.Sink != "" {
+		sink, err := siem.NewSink(a.config.SIEM.Sink, a.config.SIEM.SyslogNetwork, a.config.SIEM.SyslogAddress, a.config.SIEM.URL, a.config.SIEM.Token, a.config.Version)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the siem sink")
+		}
+		// wrapped before the other decorators, so the events are exported as written, like with their location
+		s.audit = services.NewSIEMAuditRepository(ctx, s.audit, sink, a.logger, a.config.SIEM.BufferSize, a.config.SIEM.BatchSize,
+			a.config.SIEM.FlushInterval.Duration, a.config.SIEM.Timeout.Duration, a.config.SIEM.MaxBackoff.Duration)
+	}
+
+	if a.config.GeoIP.Provider != "" {
+		s.locator, err = newGeoIPLocator(a.config.GeoIP)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the geoip locator")
+		}
+		s.audit = services.NewGeoIPAuditRepository(s.audit, s.locator, a.logger, a.config.GeoIP.Timeout.Duration)
+	}
+
+	a.userStore = s.users
+	// the backups are dumped and restored with the fields as stored, so encrypted when the encryption is enabled
+	s.backupUsers = s.users
+	if a.config.Encryption.Enabled {
+		a.keyProvider, a.fieldCipher, err = newFieldCipher(ctx, a.config.Encryption)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the field cipher")
+		}
+		s.users = encryption.NewUserRepository(s.users, a.fieldCipher, a.config.Encryption.Fields)
+	}
+
+	// the hooks wrap the other decorators, so they see the operations as the services run them
+	var repoHooks []ports.RepositoryHook
+	if a.config.Tracing.Enabled {
+		repoHooks = append(repoHooks, hooks.NewTracingHook(tp))
+	}
+	if a.config.Monitoring.RepositoryMetrics {
+		repoHooks = append(repoHooks, hooks.NewMetricsHook())
+	}
+	if a.config.Repository.Timeout.Duration > 0 || len(a.config.Repository.Timeouts) > 0 {
+		timeouts := make(map[string]time.Duration, len(a.config.Repository.Timeouts))
+		for operation, timeout := range a.config.Repository.Timeouts {
+			timeouts[operation] = timeout.Duration
+		}
+		repoHooks = append(repoHooks, hooks.NewTimeoutHook(a.config.Repository.Timeout.Duration, timeouts))
+	}
+	if len(repoHooks) > 0 {
+		s.users = hooks.NewUserRepository(s.users, repoHooks...)
+		s.backupUsers = hooks.NewUserRepository(s.backupUsers, repoHooks...)
+		s.jobs = hooks.NewJobRepository(s.jobs, repoHooks...)
+	}
+}
+
+// wireServices builds the services on the stores, wrapping the user service with the decorators enabled in the config.
+// A subsystem plugs in by building its service here from the stores and the services it depends on.
+func (a *api) wireServices(ctx context.Context, s stores, tp trace.TracerProvider) {
+	var err error
+	a.services.keys = services.NewKeyService(a.config, a.logger, s.signingKeys)
+	if err := a.services.keys.Refresh(ctx); err != nil {
+		a.logger.Warn().Err(err).Msg("signing keys cannot be read, signing the tokens with JWTSecret")
+	}
+
+	a.services.job = services.NewJobService(a.config, s.jobs)
+	var verifier ports.CredentialVerifier
+	if len(a.config.LDAP.Directories) > 0 {
+		verifier, err = newCredentialVerifier(a.config.LDAP)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the ldap credential verifier")
+		}
+	}
+	a.services.user = services.NewUserService(a.config, a.logger, s.users, s.files, s.audit, a.services.keys, verifier)
+	if a.config.EmailCheck.Mode != "" {
+		emailVerifier, err := emailcheck.NewVerifier(a.config.EmailCheck.CheckMX, a.config.EmailCheck.DisposableDomains, a.config.EmailCheck.DisposableListPath)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the email verifier")
+		}
+		a.services.user = services.NewEmailCheckUserService(a.services.user, emailVerifier, s.audit, a.logger, a.config.EmailCheck.Mode == "reject", a.config.EmailCheck.Timeout.Duration)
+	}
+	if a.config.Antivirus.Provider != "" {
+		scanner, err := antivirus.NewScanner(a.config.Antivirus.Provider, a.config.Antivirus.ClamAVAddress, a.config.Antivirus.Timeout.Duration)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the antivirus scanner")
+		}
+		a.services.scan = services.NewScanService(a.logger, s.files, scanner, s.users, s.audit)
+		a.services.user = services.NewScanUserService(a.services.user, s.files, a.services.job, a.logger)
+	}
+	if a.config.Cache.Enabled {
+		var invalidator ports.Invalidator
+		if a.config.Cache.RedisAddress != "" {
+			invalidator = redis.NewInvalidator(a.config.Cache.RedisAddress, a.config.Cache.RedisPassword, a.config.Cache.Channel, a.logger)
+		}
+		a.services.user = services.NewCachingUserService(ctx, a.services.user, invalidator, a.logger, a.config.Cache.TTL.Duration, a.config.Cache.MaxEntries)
+		// the services built next write to the users through the repository, so the users they change are evicted as well
+		s.users = services.NewCacheEvictingUserRepository(s.users, a.services.user)
+		s.backupUsers = services.NewCacheEvictingUserRepository(s.backupUsers, a.services.user)
+	}
+	if s.securityPolicies != nil {
+		// the one-time codes of MFA are emailed, so the policies only require it when the emails are enabled
+		var mailer ports.Mailer
+		if a.config.Email.Provider != "" {
+			mailer = email.NewQueuedMailer(a.services.job)
+		}
+		a.services.securityPolicy = services.NewSecurityPolicyService(s.securityPolicies, mailer != nil)
+		a.services.user = services.NewSecurityPolicyUserService(a.services.user, s.securityPolicies, mailer, a.limits, a.logger,
+			a.config.JWTSecret, a.config.MFA.CodeTTL.Duration, a.config.MFA.MaxAttempts)
+	}
+	// wrapping the security policies, so the one-time codes not valid are counted as the failed logins, and the tenants lock out their logins even when the config does not
+	if a.config.Lockout.Enabled || s.securityPolicies != nil {
+		var notifier ports.AdminNotifier
+		if a.config.Notifications.Notifies(ports.AdminEventLockout) {
+			notifier = notify.NewQueuedAdminNotifier(a.services.job)
+		}
+		var maxFailures int64
+		if a.config.Lockout.Enabled {
+			maxFailures = a.config.Lockout.MaxFailures
+		}
+		a.services.user = services.NewLockoutUserService(a.services.user, a.limits, s.securityPolicies, notifier, a.logger, maxFailures, a.config.Lockout.Duration.Duration)
+	}
+	if s.locator != nil {
+		a.services.user = services.NewGeoIPUserService(a.services.user, s.locator, a.limits, s.audit, a.logger, a.config.GeoIP.Timeout.Duration, a.config.GeoIP.KnownCountryTTL.Duration)
+	}
+	if s.notificationPreferences != nil {
+		// the senders below then check the preferences of the users before delivering their notifications
+		a.services.notificationPreferences = services.NewNotificationPreferencesService(s.notificationPreferences, a.services.user)
+		a.services.user = services.NewNotificationPreferencesUserService(a.services.user, s.notificationPreferences, a.logger)
+	}
+	if a.config.Email.Provider != "" {
+		mailer := email.NewQueuedMailer(a.services.job)
+		if a.services.notificationPreferences != nil {
+			mailer = services.NewNotificationPreferencesMailer(mailer, a.services.notificationPreferences, a.services.user, a.logger)
+		}
+		a.services.user = services.NewSecurityAlertUserService(a.services.user, mailer, a.logger)
+	}
+	if a.config.Push.Enabled {
+		a.services.device = services.NewDeviceService(s.devices, a.services.user, push.NewQueuedSender(a.services.job))
+		if a.services.notificationPreferences != nil {
+			a.services.device = services.NewNotificationPreferencesDeviceService(a.services.device, a.services.notificationPreferences, a.logger)
+		}
+		a.services.user = services.NewPushUserService(a.services.user, a.services.device, a.limits, a.logger, a.config.Push.KnownDeviceTTL.Duration)
+	}
+	if s.organizations != nil {
+		a.services.organization = services.NewOrganizationService(s.organizations, a.services.user)
+		a.services.user = services.NewOrganizationUserService(a.services.user, s.organizations, a.logger)
+	}
+	if s.roles != nil {
+		a.services.role = services.NewRoleService(s.roles, a.services.user, s.organizations)
+		a.services.user = services.NewRoleUserService(a.services.user, s.roles, s.organizations, a.services.keys, a.config.OIDC.Issuer, a.logger)
+		if a.services.organization != nil {
+			a.services.organization = services.NewRoleOrganizationService(a.services.organization, s.roles, a.logger)
+		}
+	}
+	if s.apiKeys != nil {
+		a.services.apiKey = services.NewAPIKeyService(s.apiKeys, a.services.user)
+		a.services.user = services.NewAPIKeyUserService(a.services.user, s.apiKeys, a.logger)
+		// the API keys are then authenticated by every route, as the tokens are
+		a.services.keys = services.NewAPIKeyTokenKeys(a.services.keys, s.apiKeys, a.services.user, a.logger)
+	}
+	if a.config.Billing.Provider != "" {
+		provider, err := billing.NewProvider(a.config.Billing.Provider, a.config.Billing.StripeSecretKey, a.config.Billing.WebhookSecret, a.config.Billing.WebhookTolerance.Duration)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the billing provider")
+		}
+		a.services.billing = services.NewBillingService(a.logger, s.users, provider)
+		a.services.user = services.NewBillingUserService(a.services.user, a.services.job, a.logger)
+	}
+	if a.config.Search.Enabled && s.userChanges != nil {
+		index := search.NewElasticsearchIndex(a.config.Search.URL, a.config.Search.Index, a.config.Search.Username, a.config.Search.Password, http.DefaultClient)
+		a.services.search = services.NewSearchService(a.logger, s.users, index, s.userChanges, s.organizations)
+	}
+	if a.config.Analytics.Enabled {
+		a.services.user = services.NewAnalyticsUserService(a.services.user, analytics.NewQueuedTracker(a.services.job), a.logger, a.config.Analytics.OptOutDomains)
+	}
+	if a.config.Events.Broker != "" {
+		a.services.user = services.NewEventsUserService(a.services.user, events.NewQueuedPublisher(a.services.job), a.logger)
+	}
+	if a.config.Tracing.Enabled {
+		a.services.user = services.NewTracingUserService(a.services.user, tp)
+	}
+	a.services.backup = services.NewBackupService(a.config, a.logger, s.backupUsers, s.files, s.jobs, s.audit)
+	if a.config.DirectorySync.Source != "" {
+		source, err := newDirectorySource(a.config.DirectorySync, a.config.LDAP)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the directory source")
+		}
+		var publisher ports.EventPublisher
+		if a.config.Events.Broker != "" {
+			publisher = events.NewQueuedPublisher(a.services.job)
+		}
+		a.services.directory = services.NewDirectorySyncService(a.config, a.logger, source, s.users, s.files, s.jobs, s.audit, publisher)
+	}
+	a.services.maintenance = services.NewMaintenanceService(a.config, a.logger, s.maintenance, s.jobs, a.services.job, a.services.user, a.services.keys, s.signingKeys, a.limits, s.audit)
+	a.workers = worker.New(s.jobs, a.logger, a.config.Queue)
+	a.services.audit = services.NewAuditService(s.audit)
+	a.services.dashboard = services.NewDashboardService(a.services.user, s.audit, s.jobs, a.config.Dashboard.MaxAge.Duration, a.config.Timeout.Duration)
+	if a.config.SMS.Provider != "" {
+		smsSender, err := sms.NewSender(a.config.SMS.Provider, a.config.SMS.From, a.config.SMS.TwilioAccountSID, a.config.SMS.TwilioAuthToken, a.config.SMS.StatusCallbackURL)
+		if err != nil {
+			a.logger.Fatal().Err(err).Msg("cannot create the sms sender")
+		}
+		a.services.sms = services.NewSMSService(a.config, a.logger, smsSender, a.limits)
+		if a.services.notificationPreferences != nil {
+			a.services.sms = services.NewNotificationPreferencesSMSService(a.services.sms, a.services.notificationPreferences, a.logger)
+		}
+	}
+	a.services.capture = services.NewCaptureService(a.config, a.logger, s.captures, s.audit)
+	a.services.retention = services.NewRetentionService(a.config, a.logger, map[string]ports.RetentionRepository{
+		entities.EntityNameAuditEvent:  s.audit,
+		entities.EntityNameJob:         s.jobs,
+		entities.EntityNameUserArchive: s.userArchive,
+	}, s.audit)
+}
@@ -0,0 +1,154 @@
+package alerter
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// Rule a rate watched over a rolling window, being the failed events per event when Total is set, or the count of failed events otherwise.
+// Failed and Total return cumulative counters, whose increase over the window is the rate.
+type Rule struct {
+	Name      string
+	Threshold float64
+	// MinTotal is the minimum number of events over the window for a ratio to fire, so a few failures on low traffic do not
+	MinTotal int64
+	Failed   func() int64
+	Total    func() int64
+}
+
+// sample the counters of a rule at a given time
+type sample struct {
+	at     time.Time
+	failed int64
+	total  int64
+}
+
+// watcher keeps the samples of a rule within the window and whether it is firing
+type watcher struct {
+	rule    Rule
+	window  time.Duration
+	samples []sample
+	firing  bool
+}
+
+// observe samples the counters of the rule, returning the alert when the rule starts or stops reaching its threshold
+func (w *watcher) observe(now time.Time) (ports.Alert, bool) {
+	s := sample{at: now, failed: w.rule.Failed()}
+	if w.rule.Total != nil {
+		s.total = w.rule.Total()
+	}
+	w.samples = append(w.samples, s)
+
+	// the newest sample not within the window is kept as its start
+	for len(w.samples) > 1 && !w.samples[1].at.After(now.Add(-w.window)) {
+		w.samples = w.samples[1:]
+	}
+
+	first := w.samples[0]
+	failed := s.failed - first.failed
+	value := float64(failed)
+	reached := value >= w.rule.Threshold
+	if w.rule.Total != nil {
+		total := s.total - first.total
+		value = 0
+		if total > 0 {
+			value = float64(failed) / float64(total)
+		}
+		reached = total > 0 && total >= w.rule.MinTotal && value >= w.rule.Threshold
+	}
+
+	if reached == w.firing {
+		return ports.Alert{}, false
+	}
+	w.firing = reached
+	return ports.Alert{
+		Name:      w.rule.Name,
+		Firing:    reached,
+		Value:     value,
+		Threshold: w.rule.Threshold,
+		Window:    w.window,
+		At:        now,
+	}, true
+}
+
+// Run samples the counters of the rules every interval, notifying through every notifier when a rule starts reaching its threshold over the window,
+// and when it goes back below it. Every notification is given until the next sampling to complete.
+func Run(ctx context.Context, cancel context.CancelFunc, logger zerolog.Logger, rules []Rule, notifiers []ports.Notifier, interval, window time.Duration) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Error().Interface("panic", rec).Msg("recovered panic in async process")
+		}
+	}()
+
+	watchers := make([]*watcher, 0, len(rules))
+	for _, r := range rules {
+		watchers = append(watchers, &watcher{rule: r, window: window})
+	}
+
+	for ctx.Err() == nil {
+		now := time.Now()
+		for _, w := range watchers {
+			alert, changed := w.observe(now)
+			if !changed {
+				continue
+			}
+
+			logger.Warn().
+				Str("alert", alert.Name).
+				Bool("firing", alert.Firing).
+				Float64("value", alert.Value).
+				Float64("threshold", alert.Threshold).
+				Msg("alert")
+			notify(ctx, logger, notifiers, alert, interval)
+		}
+
+		<-time.After(interval)
+	}
+}
+
+func notify(ctx context.Context, logger zerolog.Logger, notifiers []ports.Notifier, alert ports.Alert, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, alert); err != nil {
+			logger.Error().Err(err).Str("alert", alert.Name).Msg("alert notification failed")
+		}
+	}
+}
+
+// Counter returns a function reading a published integer metric, nested in maps under the given keys,
+// like Counter("auth", "logins", "failed"), being zero while not published or counted yet
+func Counter(name string, keys ...string) func() int64 {
+	return func() int64 {
+		v := expvar.Get(name)
+		for _, k := range keys {
+			m, ok := v.(*expvar.Map)
+			if !ok {
+				return 0
+			}
+			v = m.Get(k)
+		}
+		i, ok := v.(*expvar.Int)
+		if !ok {
+			return 0
+		}
+		return i.Value()
+	}
+}
+
+// Sum returns a function adding the values of the given counters
+func Sum(counters ...func() int64) func() int64 {
+	return func() int64 {
+		var sum int64
+		for _, c := range counters {
+			sum += c()
+		}
+		return sum
+	}
+}
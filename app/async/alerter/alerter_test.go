@@ -0,0 +1,132 @@
+package alerter
+
+import (
+	"context"
+	"expvar"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestObserve_RatioFiringThenResolved checks that observe fires once when the ratio over the window reaches the threshold,
+// and resolves once it goes back below it
+func TestObserve_RatioFiringThenResolved(t *testing.T) {
+	// Arrange
+	var failed, total int64
+	w := &watcher{
+		rule: Rule{
+			Name:      "error_rate",
+			Threshold: 0.5,
+			MinTotal:  2,
+			Failed:    func() int64 { return failed },
+			Total:     func() int64 { return total },
+		},
+		window: time.Minute,
+	}
+	start := time.Now()
+	w.observe(start)
+
+	// Act
+	failed, total = 2, 4
+	firing, firingChanged := w.observe(start.Add(10 * time.Second))
+	_, stillFiring := w.observe(start.Add(20 * time.Second))
+	total = 40
+	resolved, resolvedChanged := w.observe(start.Add(30 * time.Second))
+
+	// Assert
+	assert.True(t, firingChanged)
+	assert.Equal(t, ports.Alert{Name: "error_rate", Firing: true, Value: 0.5, Threshold: 0.5, Window: time.Minute, At: start.Add(10 * time.Second)}, firing)
+	assert.False(t, stillFiring)
+	assert.True(t, resolvedChanged)
+	assert.False(t, resolved.Firing)
+}
+
+// TestObserve_MinTotal checks that observe does not fire a ratio while there are fewer events than the minimum over the window
+func TestObserve_MinTotal(t *testing.T) {
+	// Arrange
+	var failed, total int64
+	w := &watcher{
+		rule: Rule{
+			Threshold: 0.5,
+			MinTotal:  10,
+			Failed:    func() int64 { return failed },
+			Total:     func() int64 { return total },
+		},
+		window: time.Minute,
+	}
+	start := time.Now()
+	w.observe(start)
+
+	// Act
+	failed, total = 1, 1
+	_, changed := w.observe(start.Add(time.Second))
+
+	// Assert
+	assert.False(t, changed)
+}
+
+// TestObserve_Window checks that observe only counts the events within the window
+func TestObserve_Window(t *testing.T) {
+	// Arrange
+	var failed int64
+	w := &watcher{
+		rule:   Rule{Threshold: 3, Failed: func() int64 { return failed }},
+		window: time.Minute,
+	}
+	start := time.Now()
+	w.observe(start)
+	failed = 2
+	w.observe(start.Add(30 * time.Second))
+
+	// Act
+	failed = 4
+	_, changed := w.observe(start.Add(2 * time.Minute))
+
+	// Assert
+	assert.False(t, changed)
+	assert.Len(t, w.samples, 2)
+}
+
+// TestRun_NotifiedThenContextCancelled checks that Run notifies the alerts through every notifier and finishes when the context gets cancelled
+func TestRun_NotifiedThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	var failed atomic.Int64
+	rules := []Rule{{Name: "failed_logins", Threshold: 1, Failed: func() int64 { return failed.Add(1) }}}
+	notifier := mocks.NewNotifier(t)
+	notifier.On(testutils.FunctionName(t, ports.Notifier.Notify), mock.Anything, mock.MatchedBy(func(alert ports.Alert) bool {
+		return alert.Name == "failed_logins" && alert.Firing
+	})).Return(nil).Once()
+	expectedError := context.DeadlineExceeded.Error()
+
+	// Act
+	Run(ctx, cancel, zerolog.Nop(), rules, []ports.Notifier{notifier}, time.Millisecond, time.Minute)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
+
+// TestCounter_Ok checks that Counter reads the nested published counter, being zero while it is not published
+func TestCounter_Ok(t *testing.T) {
+	// Arrange
+	m := expvar.NewMap("alerter_test")
+	logins := new(expvar.Map).Init()
+	logins.Add("failed", 3)
+	m.Set("logins", logins)
+
+	// Act
+	value := Counter("alerter_test", "logins", "failed")()
+	missing := Counter("alerter_test", "missing", "failed")()
+
+	// Assert
+	assert.Equal(t, int64(3), value)
+	assert.Zero(t, missing)
+	assert.Equal(t, int64(6), Sum(Counter("alerter_test", "logins", "failed"), Counter("alerter_test", "logins", "failed"))())
+}
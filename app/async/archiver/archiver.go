@@ -0,0 +1,41 @@
+package archiver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/leader"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// Run archives the inactive users periodically with the received archive function.
+// Every archival is given until the next one to complete.
+func Run(ctx context.Context, cancel context.CancelFunc, logger zerolog.Logger, archive func(ctx context.Context) (models.ArchivalResp, error), interval time.Duration) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Error().Interface("panic", rec).Msg("recovered panic in async process")
+		}
+	}()
+
+	for ctx.Err() == nil {
+		<-time.After(interval)
+		start := time.Now()
+
+		archiveCtx, archiveCancel := context.WithTimeout(ctx, interval)
+		resp, err := archive(archiveCtx)
+		archiveCancel()
+		if errors.Is(err, leader.ErrNotLeader) {
+			logger.Debug().Msg("archival skipped, run by the leader")
+			continue
+		}
+		if err != nil {
+			logger.Error().Err(err).Msg("archival failed")
+			continue
+		}
+
+		logger.Info().Int64("archived", resp.ArchivedCount).Dur("elapsed", time.Since(start)).Msg("archival complete")
+	}
+}
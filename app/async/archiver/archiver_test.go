@@ -0,0 +1,52 @@
+package archiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRun_SuccessfulArchivalThenContextCancelled checks that Run finishes and returns the expected error when the context gets cancelled
+// while the archivals were succeeding
+func TestRun_SuccessfulArchivalThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	calls := 0
+	archive := func(ctx context.Context) (models.ArchivalResp, error) {
+		calls++
+		return models.ArchivalResp{ArchivedCount: 1}, nil
+	}
+	expectedError := context.DeadlineExceeded.Error()
+
+	// Act
+	Run(ctx, cancel, zerolog.Nop(), archive, time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+	assert.NotZero(t, calls)
+}
+
+// TestRun_FailedArchivalThenContextCancelled checks that Run keeps going after a failed archival and finishes
+// when the context gets cancelled
+func TestRun_FailedArchivalThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	calls := 0
+	archive := func(ctx context.Context) (models.ArchivalResp, error) {
+		calls++
+		return models.ArchivalResp{}, errors.New("archival error")
+	}
+	expectedError := context.DeadlineExceeded.Error()
+
+	// Act
+	Run(ctx, cancel, zerolog.Nop(), archive, time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+	assert.Greater(t, calls, 1)
+}
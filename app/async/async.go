@@ -3,25 +3,116 @@ package async
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sort"
 	"time"
 
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/alerter"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/archiver"
 	"github.com/sergicanet9/go-hexagonal-api/app/async/healthchecker"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/indexer"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/leader"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/retention"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/scheduler"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/worker"
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/analytics"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/email"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/events"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/notify"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/push"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/sms"
 )
 
+// singletonLease is the name of the lease of the leader running the singleton jobs
+const singletonLease = "singleton_jobs"
+
+// names of the scheduled jobs, as set in Scheduler.Jobs of the config
+const (
+	jobRetention     = "retention"
+	jobLimitCleanup  = "limit_cleanup"
+	jobUserStats     = "user_stats"
+	jobKeyRotation   = "key_rotation"
+	jobKeyRefresh    = "key_refresh"
+	jobDirectorySync = "directory_sync"
+)
+
+// singletonJobs are the scheduled jobs only run on the leader, as they change the data shared by every replica
+var singletonJobs = map[string]bool{jobRetention: true, jobLimitCleanup: true, jobKeyRotation: true, jobDirectorySync: true}
+
 type async struct {
-	config config.Config
+	config           config.Config
+	logger           zerolog.Logger
+	userService      ports.UserService
+	retentionService ports.RetentionService
+	jobService       ports.JobService
+	backupService    ports.BackupService
+	keyService       ports.KeyService
+	maintenance      ports.MaintenanceService
+	deviceService    ports.DeviceService
+	billingService   ports.BillingService
+	searchService    ports.SearchService
+	scanService      ports.ScanService
+	directoryService ports.DirectorySyncService
+	leases           ports.LeaseStore
+	limits           ports.LimitStore
+	scheduler        *scheduler.Scheduler
+	workers          *worker.Pool
+	elector          *leader.Elector
 }
 
-func New(cfg config.Config) async {
+func New(cfg config.Config, logger zerolog.Logger, userService ports.UserService, retentionService ports.RetentionService, jobService ports.JobService, backupService ports.BackupService, keyService ports.KeyService, maintenance ports.MaintenanceService, deviceService ports.DeviceService, billingService ports.BillingService, searchService ports.SearchService, scanService ports.ScanService, directoryService ports.DirectorySyncService, leases ports.LeaseStore, limits ports.LimitStore, scheduler *scheduler.Scheduler, workers *worker.Pool) async {
 	return async{
-		config: cfg,
+		config:           cfg,
+		logger:           logger,
+		userService:      userService,
+		retentionService: retentionService,
+		jobService:       jobService,
+		backupService:    backupService,
+		keyService:       keyService,
+		maintenance:      maintenance,
+		deviceService:    deviceService,
+		billingService:   billingService,
+		searchService:    searchService,
+		scanService:      scanService,
+		directoryService: directoryService,
+		leases:           leases,
+		limits:           limits,
+		scheduler:        scheduler,
+		workers:          workers,
 	}
 }
 
 func (a async) Run(ctx context.Context, cancel context.CancelFunc) func() error {
 	return func() error {
-		go healthchecker.Run(ctx, cancel, fmt.Sprintf("http://:%d/health", a.config.Port), a.config.Async.Interval.Duration)
+		go healthchecker.Run(ctx, cancel, a.logger, fmt.Sprintf("http://:%d/health", a.config.Port), a.config.Async.Interval.Duration)
+		if a.config.Leader.Enabled && (a.config.Archive.Run || a.searchService != nil || a.singletonScheduled()) {
+			a.elector = leader.New(a.leases, a.logger, singletonLease, a.config.Leader.LeaseTTL.Duration)
+			go a.elector.Run(ctx)
+		}
+		if err := a.registerJobs(); err != nil {
+			cancel()
+			return err
+		}
+		go a.scheduler.Run(ctx)
+		if err := a.registerHandlers(ctx); err != nil {
+			cancel()
+			return err
+		}
+		go a.workers.Run(ctx)
+		if a.config.Archive.Run {
+			go archiver.Run(ctx, cancel, a.logger, a.archive, a.config.Archive.Interval.Duration)
+		}
+		if a.searchService != nil {
+			go indexer.Run(ctx, cancel, a.logger, a.syncSearch, a.config.Search.RetryInterval.Duration)
+		}
+		if a.config.Alerting.Run {
+			go alerter.Run(ctx, cancel, a.logger, a.alertRules(), a.notifiers(), a.config.Alerting.Interval.Duration, a.config.Alerting.Window.Duration)
+		}
 
 		for ctx.Err() == nil {
 			<-time.After(1 * time.Second)
@@ -29,3 +120,296 @@ func (a async) Run(ctx context.Context, cancel context.CancelFunc) func() error
 		return fmt.Errorf("async process stopped")
 	}
 }
+
+// registerJobs registers in the scheduler the jobs set in the config, in the order of their names, warning about the ones not known
+func (a async) registerJobs() error {
+	jobs := map[string]scheduler.Func{
+		jobRetention:    retention.Job(a.logger, a.applyRetention, a.config.Retention.DryRun),
+		jobLimitCleanup: a.purgeLimits,
+		jobUserStats:    a.computeUserStats,
+		jobKeyRotation:  a.rotateKeys,
+		jobKeyRefresh:   a.refreshKeys,
+	}
+	if a.directoryService != nil {
+		jobs[jobDirectorySync] = a.syncDirectory
+	}
+
+	names := make([]string, 0, len(a.config.Scheduler.Jobs))
+	for name := range a.config.Scheduler.Jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		run, ok := jobs[name]
+		if !ok {
+			a.logger.Warn().Str("job", name).Msg("scheduled job not known, ignored")
+			continue
+		}
+		job := a.config.Scheduler.Jobs[name]
+		if err := a.scheduler.Register(name, job.Schedule, job.Enabled, run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registerHandlers registers in the worker pool the handlers of the queued jobs:
+// the backups and restores, given Backup.Timeout, the maintenance tasks, given Maintenance.Timeout, the alert notifications, delivered through their channel,
+// the admin notices, posted to the targets of Notifications, each one given Notifications.Timeout,
+// the transactional emails, sent through Email.Provider when set, the push notifications, sent when Push.Enabled is set,
+// the syncs of the billing customers, run when Billing.Provider is set, the scans of the uploads, run when Antivirus.Provider is set,
+// the directory syncs, given DirectorySync.Timeout when DirectorySync.Source is set, the product analytics, sent when Analytics.Enabled is set,
+// and the user lifecycle events, published through Events.Broker when set
+// and closed when the context is done
+func (a async) registerHandlers(ctx context.Context) error {
+	a.workers.Handle(entities.JobTypeBackup, a.config.Backup.Timeout.Duration, a.backupService.Process)
+	a.workers.Handle(entities.JobTypeRestore, a.config.Backup.Timeout.Duration, a.backupService.Process)
+	a.workers.Handle(entities.JobTypeMaintenance, a.config.Maintenance.Timeout.Duration, a.maintenance.Process)
+
+	channels := a.channels()
+	a.workers.Handle(entities.JobTypeNotification, a.config.Queue.Timeout.Duration, func(ctx context.Context, job *entities.Job) error {
+		return notify.Deliver(ctx, channels, job)
+	})
+
+	if len(a.config.Notifications.Events) > 0 {
+		notifier := a.adminNotifier()
+		a.workers.Handle(entities.JobTypeAdminNotice, a.config.Queue.Timeout.Duration, func(ctx context.Context, job *entities.Job) error {
+			ctx, cancel := context.WithTimeout(ctx, a.config.Notifications.Timeout.Duration)
+			defer cancel()
+			return notify.DeliverNotice(ctx, notifier, job)
+		})
+	}
+
+	if a.config.Email.Provider != "" {
+		sender, err := email.NewSender(a.config.Email.Provider, a.config.Email.From, a.config.Email.SMTPAddress, a.config.Email.SMTPUsername, a.config.Email.SMTPPassword,
+			a.config.Email.SendGridAPIKey, a.config.Email.SESRegion)
+		if err != nil {
+			return err
+		}
+		a.workers.Handle(entities.JobTypeEmail, a.config.Queue.Timeout.Duration, func(ctx context.Context, job *entities.Job) error {
+			return email.Deliver(ctx, sender, job)
+		})
+	}
+
+	if a.config.Push.Enabled {
+		sender, err := push.NewSender(a.config.Push.FCMProjectID, a.config.Push.FCMCredentials, a.config.Push.APNsKeyID, a.config.Push.APNsTeamID,
+			a.config.Push.APNsPrivateKey, a.config.Push.APNsTopic, a.config.Push.APNsProduction)
+		if err != nil {
+			return err
+		}
+		a.workers.Handle(entities.JobTypePush, a.config.Queue.Timeout.Duration, func(ctx context.Context, job *entities.Job) error {
+			return push.Deliver(ctx, sender, a.deviceService, job)
+		})
+	}
+
+	if a.billingService != nil {
+		a.workers.Handle(entities.JobTypeBilling, a.config.Queue.Timeout.Duration, a.billingService.Process)
+	}
+
+	if a.scanService != nil {
+		a.workers.Handle(entities.JobTypeScan, a.config.Queue.Timeout.Duration, a.scanService.Process)
+	}
+
+	if a.directoryService != nil {
+		a.workers.Handle(entities.JobTypeDirectorySync, a.config.DirectorySync.Timeout.Duration, a.directoryService.Process)
+	}
+
+	if a.config.Analytics.Enabled {
+		tracker := analytics.NewSegmentTracker(a.config.Analytics.Endpoint, a.config.Analytics.WriteKey, http.DefaultClient)
+		a.workers.Handle(entities.JobTypeAnalytics, a.config.Queue.Timeout.Duration, func(ctx context.Context, job *entities.Job) error {
+			return analytics.Deliver(ctx, tracker, job)
+		})
+	}
+
+	if a.config.Events.Broker != "" {
+		broker, err := events.NewBroker(a.config.Events.Broker, a.config.Events.KafkaBrokers, a.config.Events.ClientID, a.config.Events.KafkaTLS,
+			a.config.Events.NATSURL, a.config.Events.RabbitMQURL, a.config.Events.RabbitMQExchange)
+		if err != nil {
+			return err
+		}
+		publisher, err := events.NewPublisher(broker, a.config.Events.Format, a.config.Events.Topic, a.config.Events.Topics)
+		if err != nil {
+			return err
+		}
+		a.workers.Handle(entities.JobTypeEvent, a.config.Queue.Timeout.Duration, func(ctx context.Context, job *entities.Job) error {
+			return events.Deliver(ctx, publisher, job)
+		})
+		go func() {
+			<-ctx.Done()
+			if err := broker.Close(); err != nil {
+				a.logger.Warn().Err(err).Msg("events broker cannot be closed")
+			}
+		}()
+	}
+	return nil
+}
+
+// singletonScheduled reports whether any of the enabled scheduled jobs only runs on the leader
+func (a async) singletonScheduled() bool {
+	for name, job := range a.config.Scheduler.Jobs {
+		if job.Enabled && singletonJobs[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// leading reports whether the singleton jobs run on this replica, either because it is the leader or because they are not led
+func (a async) leading() bool {
+	return a.elector == nil || a.elector.IsLeader()
+}
+
+// archive archives the inactive users, only on the leader when the singleton jobs are led,
+// while the alerts and the health checks run on every replica as they watch the replica itself
+func (a async) archive(ctx context.Context) (models.ArchivalResp, error) {
+	if !a.leading() {
+		return models.ArchivalResp{}, leader.ErrNotLeader
+	}
+	return a.userService.ArchiveInactive(ctx)
+}
+
+// syncSearch mirrors the users into the search index, only on the leader when the singleton jobs are led,
+// so the changes are not indexed once per replica
+func (a async) syncSearch(ctx context.Context) error {
+	if !a.leading() {
+		return leader.ErrNotLeader
+	}
+	return a.searchService.Sync(ctx)
+}
+
+// applyRetention applies the retention policies, only on the leader when the singleton jobs are led
+func (a async) applyRetention(ctx context.Context, dryRun bool) (models.RetentionResp, error) {
+	if !a.leading() {
+		return models.RetentionResp{}, leader.ErrNotLeader
+	}
+	return a.retentionService.Apply(ctx, dryRun)
+}
+
+// purgeLimits deletes the ended windows of the rate limits and the login lockouts, only on the leader when the singleton jobs are led
+func (a async) purgeLimits(ctx context.Context) error {
+	if !a.leading() {
+		return leader.ErrNotLeader
+	}
+	count, err := a.limits.Purge(ctx)
+	if err != nil {
+		return err
+	}
+	a.logger.Info().Int64("purged", count).Msg("ended limits purged")
+	return nil
+}
+
+// computeUserStats precomputes the user stats exported by the metrics, on every replica as each one exports its own
+func (a async) computeUserStats(ctx context.Context) error {
+	resp, err := a.userService.ComputeStats(ctx)
+	if err != nil {
+		return err
+	}
+	a.logger.Debug().Int64("total", resp.Total).Int64("active", resp.Active).Int64("archived", resp.Archived).Msg("user stats computed")
+	return nil
+}
+
+// rotateKeys creates a new key signing the tokens, only on the leader when the singleton jobs are led,
+// the other replicas picking it up on their next refresh or on the first token signed with it
+func (a async) rotateKeys(ctx context.Context) error {
+	if !a.leading() {
+		return leader.ErrNotLeader
+	}
+	if err := a.keyService.Rotate(ctx); err != nil {
+		return err
+	}
+	a.logger.Info().Msg("signing key rotated")
+	return nil
+}
+
+// refreshKeys reads again the keys signing the tokens, on every replica as each one keeps its own
+func (a async) refreshKeys(ctx context.Context) error {
+	return a.keyService.Refresh(ctx)
+}
+
+// syncDirectory queues a sync of the users with the directory, in dry run when DirectorySync.DryRun is set,
+// only on the leader when the singleton jobs are led, so a single sync is queued per run
+func (a async) syncDirectory(ctx context.Context) error {
+	if !a.leading() {
+		return leader.ErrNotLeader
+	}
+	job, err := a.directoryService.Sync(ctx, a.config.DirectorySync.DryRun)
+	if err != nil {
+		return err
+	}
+	a.logger.Info().Str("job_id", job.ID).Bool("dry_run", a.config.DirectorySync.DryRun).Msg("directory sync queued")
+	return nil
+}
+
+// alertRules returns the rules of the alerter, a zero threshold disabling its rule:
+// the ratio of requests failing with a server error and the count of failed logins
+func (a async) alertRules() []alerter.Rule {
+	var rules []alerter.Rule
+	if a.config.Alerting.ErrorRateThreshold > 0 {
+		var classes []func() int64
+		for _, class := range []string{"1xx", "2xx", "3xx", "4xx", "5xx"} {
+			classes = append(classes, alerter.Counter("requests", class))
+		}
+		rules = append(rules, alerter.Rule{
+			Name:      "error_rate",
+			Threshold: a.config.Alerting.ErrorRateThreshold,
+			MinTotal:  a.config.Alerting.MinRequests,
+			Failed:    alerter.Counter("requests", "5xx"),
+			Total:     alerter.Sum(classes...),
+		})
+	}
+	if a.config.Alerting.FailedLoginsThreshold > 0 {
+		rules = append(rules, alerter.Rule{
+			Name:      "failed_logins",
+			Threshold: a.config.Alerting.FailedLoginsThreshold,
+			Failed:    alerter.Counter("auth", "logins", "failed"),
+		})
+	}
+	return rules
+}
+
+// notifiers returns the notifiers queuing the alerts for every configured channel, in the order of their names,
+// so a channel not reachable is retried by the workers without delaying the alerts
+func (a async) notifiers() []ports.Notifier {
+	channels := a.channels()
+	names := make([]string, 0, len(channels))
+	for name := range channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	notifiers := make([]ports.Notifier, 0, len(names))
+	for _, name := range names {
+		notifiers = append(notifiers, notify.NewQueuedNotifier(a.jobService, name))
+	}
+	return notifiers
+}
+
+// channels returns the notifiers delivering the queued notifications by channel, the ones not configured being left out,
+// the text messages of the sms channel being sent through SMS.Provider, and the admin channel posting the alerts as admin notices
+// when Notifications.Events has alert
+func (a async) channels() map[string]ports.Notifier {
+	channels := map[string]ports.Notifier{}
+	if a.config.Alerting.SlackWebhookURL != "" {
+		channels[notify.ChannelSlack] = notify.NewSlackNotifier(a.config.Alerting.SlackWebhookURL)
+	}
+	if a.config.Alerting.SMTPAddress != "" && len(a.config.Alerting.EmailTo) > 0 {
+		channels[notify.ChannelEmail] = notify.NewEmailNotifier(a.config.Alerting.SMTPAddress, a.config.Alerting.SMTPUsername, a.config.Alerting.SMTPPassword, a.config.Alerting.EmailFrom, a.config.Alerting.EmailTo)
+	}
+	if a.config.SMS.Provider != "" && len(a.config.Alerting.SMSTo) > 0 {
+		sender, err := sms.NewSender(a.config.SMS.Provider, a.config.SMS.From, a.config.SMS.TwilioAccountSID, a.config.SMS.TwilioAuthToken, a.config.SMS.StatusCallbackURL)
+		if err != nil {
+			a.logger.Error().Err(err).Msg("sms channel not configured")
+		} else {
+			channels[notify.ChannelSMS] = notify.NewSMSNotifier(sender, a.config.Alerting.SMSTo)
+		}
+	}
+	if a.config.Notifications.Notifies(ports.AdminEventAlert) {
+		channels[notify.ChannelAdmin] = notify.NewAlertNotifier(a.adminNotifier())
+	}
+	return channels
+}
+
+// adminNotifier returns the notifier posting the admin notices to Notifications.SlackWebhookURL and Notifications.WebhookURLs
+func (a async) adminNotifier() ports.AdminNotifier {
+	return notify.NewAdminNotifier(a.config.Notifications.SlackWebhookURL, a.config.Notifications.WebhookURLs, a.config.Notifications.WebhookSecret)
+}
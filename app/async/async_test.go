@@ -5,26 +5,72 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/leader"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/scheduler"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/worker"
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // TestNew_Ok checks that New creates a new async struct with the expected values
 func TestNew_Ok(t *testing.T) {
 	// Arrange
 	expectedConfig := config.Config{}
+	expectedLogger := zerolog.Nop()
+	expectedUserService := mocks.NewUserService(t)
+	expectedRetentionService := mocks.NewRetentionService(t)
+	expectedLeaseStore := mocks.NewLeaseStore(t)
+	expectedLimitStore := mocks.NewLimitStore(t)
+	expectedJobService := mocks.NewJobService(t)
+	expectedBackupService := mocks.NewBackupService(t)
+	expectedKeyService := mocks.NewKeyService(t)
+	expectedMaintenanceService := mocks.NewMaintenanceService(t)
+	expectedDeviceService := mocks.NewDeviceService(t)
+	expectedBillingService := mocks.NewBillingService(t)
+	expectedSearchService := mocks.NewSearchService(t)
+	expectedScanService := mocks.NewScanService(t)
+	expectedDirectoryService := mocks.NewDirectorySyncService(t)
+	expectedScheduler := scheduler.New(zerolog.Nop())
+	expectedWorkers := worker.New(nil, zerolog.Nop(), config.Queue{})
 
 	// Act
-	async := New(expectedConfig)
+	async := New(expectedConfig, expectedLogger, expectedUserService, expectedRetentionService, expectedJobService, expectedBackupService, expectedKeyService, expectedMaintenanceService, expectedDeviceService, expectedBillingService, expectedSearchService, expectedScanService, expectedDirectoryService, expectedLeaseStore, expectedLimitStore, expectedScheduler, expectedWorkers)
 
 	// Assert
 	assert.Equal(t, expectedConfig, async.config)
+	assert.Equal(t, expectedLogger, async.logger)
+	assert.Equal(t, expectedUserService, async.userService)
+	assert.Equal(t, expectedRetentionService, async.retentionService)
+	assert.Equal(t, expectedJobService, async.jobService)
+	assert.Equal(t, expectedBackupService, async.backupService)
+	assert.Equal(t, expectedKeyService, async.keyService)
+	assert.Equal(t, expectedMaintenanceService, async.maintenance)
+	assert.Equal(t, expectedDeviceService, async.deviceService)
+	assert.Equal(t, expectedBillingService, async.billingService)
+	assert.Equal(t, expectedSearchService, async.searchService)
+	assert.Equal(t, expectedScanService, async.scanService)
+	assert.Equal(t, expectedDirectoryService, async.directoryService)
+	assert.Equal(t, expectedLeaseStore, async.leases)
+	assert.Equal(t, expectedLimitStore, async.limits)
+	assert.Equal(t, expectedScheduler, async.scheduler)
+	assert.Equal(t, expectedWorkers, async.workers)
 }
 
 // TestRun_ContextCancelled checks that Run finishes and returns the expected error when the context gets cancelled
 func TestRun_ContextCancelled(t *testing.T) {
 	// Arrange
-	async := &async{}
+	async := &async{
+		backupService: mocks.NewBackupService(t),
+		maintenance:   mocks.NewMaintenanceService(t),
+		scheduler:     scheduler.New(zerolog.Nop()),
+		workers:       worker.New(nil, zerolog.Nop(), config.Queue{}),
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
 	expectedError := "async process stopped"
@@ -35,3 +81,199 @@ func TestRun_ContextCancelled(t *testing.T) {
 	// Assert
 	assert.Equal(t, expectedError, errFunc().Error())
 }
+
+// TestAlertRules_Ok checks that alertRules only returns the rules with a threshold
+func TestAlertRules_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Alerting.FailedLoginsThreshold = 10
+	async := New(cfg, zerolog.Nop(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	// Act
+	rules := async.alertRules()
+
+	// Assert
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "failed_logins", rules[0].Name)
+	assert.Nil(t, rules[0].Total)
+}
+
+// TestNotifiers_Ok checks that notifiers only queues the alerts of the configured channels
+func TestNotifiers_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Alerting.SlackWebhookURL = "http://testing/webhook"
+	cfg.Alerting.SMTPAddress = "localhost:25"
+	async := New(cfg, zerolog.Nop(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	// Act
+	notifiers := async.notifiers()
+
+	// Assert
+	assert.Len(t, notifiers, 1)
+}
+
+// TestArchive_NotLeader checks that archive does not archive the users when another replica leads the singleton jobs
+func TestArchive_NotLeader(t *testing.T) {
+	// Arrange
+	async := New(config.Config{}, zerolog.Nop(), mocks.NewUserService(t), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	async.elector = leader.New(mocks.NewLeaseStore(t), zerolog.Nop(), singletonLease, time.Minute)
+
+	// Act
+	_, err := async.archive(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, leader.ErrNotLeader)
+}
+
+// TestRegisterJobs_Ok checks that registerJobs registers the known jobs set in the config, in the order of their names, ignoring the other ones
+func TestRegisterJobs_Ok(t *testing.T) {
+	// Arrange
+	var cfg config.Config
+	cfg.Scheduler.Jobs = map[string]config.ScheduledJob{
+		jobUserStats:    {Enabled: true, Schedule: "*/5 * * * *"},
+		jobRetention:    {Enabled: false, Schedule: "@daily"},
+		"unknown_job":   {Enabled: true, Schedule: "@hourly"},
+		jobLimitCleanup: {Enabled: true, Schedule: "*/15 * * * *"},
+	}
+	async := New(cfg, zerolog.Nop(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, scheduler.New(zerolog.Nop()), nil)
+
+	// Act
+	err := async.registerJobs()
+
+	// Assert
+	assert.Nil(t, err)
+	var names []string
+	for _, job := range async.scheduler.GetJobs(context.Background()) {
+		names = append(names, job.Name)
+	}
+	assert.Equal(t, []string{jobLimitCleanup, jobRetention, jobUserStats}, names)
+}
+
+// TestRegisterJobs_InvalidSchedule checks that registerJobs fails when the schedule of a job is not valid
+func TestRegisterJobs_InvalidSchedule(t *testing.T) {
+	// Arrange
+	var cfg config.Config
+	cfg.Scheduler.Jobs = map[string]config.ScheduledJob{
+		jobUserStats: {Enabled: true, Schedule: "every minute"},
+	}
+	async := New(cfg, zerolog.Nop(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, scheduler.New(zerolog.Nop()), nil)
+
+	// Act
+	err := async.registerJobs()
+
+	// Assert
+	assert.ErrorContains(t, err, "of job user_stats not valid")
+}
+
+// TestPurgeLimits_Ok checks that purgeLimits purges the ended limits when leading
+func TestPurgeLimits_Ok(t *testing.T) {
+	// Arrange
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Purge), mock.Anything).Return(int64(3), nil).Once()
+	async := New(config.Config{}, zerolog.Nop(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, limitStoreMock, nil, nil)
+
+	// Act
+	err := async.purgeLimits(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestPurgeLimits_NotLeader checks that purgeLimits does not purge the limits when another replica leads the singleton jobs
+func TestPurgeLimits_NotLeader(t *testing.T) {
+	// Arrange
+	async := New(config.Config{}, zerolog.Nop(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mocks.NewLimitStore(t), nil, nil)
+	async.elector = leader.New(mocks.NewLeaseStore(t), zerolog.Nop(), singletonLease, time.Minute)
+
+	// Act
+	err := async.purgeLimits(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, leader.ErrNotLeader)
+}
+
+// TestComputeUserStats_Ok checks that computeUserStats computes the user stats
+func TestComputeUserStats_Ok(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.ComputeStats), mock.Anything).Return(models.UserStatsResp{Total: 2, Active: 1}, nil).Once()
+	async := New(config.Config{}, zerolog.Nop(), userServiceMock, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	// Act
+	err := async.computeUserStats(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRotateKeys_Ok checks that rotateKeys rotates the signing key when leading
+func TestRotateKeys_Ok(t *testing.T) {
+	// Arrange
+	keyServiceMock := mocks.NewKeyService(t)
+	keyServiceMock.On(testutils.FunctionName(t, ports.KeyService.Rotate), mock.Anything).Return(nil).Once()
+	async := New(config.Config{}, zerolog.Nop(), nil, nil, nil, nil, keyServiceMock, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	// Act
+	err := async.rotateKeys(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRotateKeys_NotLeader checks that rotateKeys does not rotate the signing key when another replica leads the singleton jobs
+func TestRotateKeys_NotLeader(t *testing.T) {
+	// Arrange
+	async := New(config.Config{}, zerolog.Nop(), nil, nil, nil, nil, mocks.NewKeyService(t), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	async.elector = leader.New(mocks.NewLeaseStore(t), zerolog.Nop(), singletonLease, time.Minute)
+
+	// Act
+	err := async.rotateKeys(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, leader.ErrNotLeader)
+}
+
+// TestRefreshKeys_Ok checks that refreshKeys refreshes the signing keys whether leading or not
+func TestRefreshKeys_Ok(t *testing.T) {
+	// Arrange
+	keyServiceMock := mocks.NewKeyService(t)
+	keyServiceMock.On(testutils.FunctionName(t, ports.KeyService.Refresh), mock.Anything).Return(nil).Once()
+	async := New(config.Config{}, zerolog.Nop(), nil, nil, nil, nil, keyServiceMock, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	async.elector = leader.New(mocks.NewLeaseStore(t), zerolog.Nop(), singletonLease, time.Minute)
+
+	// Act
+	err := async.refreshKeys(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSyncDirectory_Ok checks that syncDirectory queues a sync of the directory, in dry run when set in the config
+func TestSyncDirectory_Ok(t *testing.T) {
+	// Arrange
+	directoryServiceMock := mocks.NewDirectorySyncService(t)
+	directoryServiceMock.On(testutils.FunctionName(t, ports.DirectorySyncService.Sync), mock.Anything, true).Return(models.JobResp{ID: "job-id"}, nil).Once()
+	cfg := config.Config{}
+	cfg.DirectorySync.DryRun = true
+	async := New(cfg, zerolog.Nop(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, directoryServiceMock, nil, nil, nil, nil)
+
+	// Act
+	err := async.syncDirectory(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSyncDirectory_NotLeader checks that syncDirectory does not queue a sync when another replica leads the singleton jobs
+func TestSyncDirectory_NotLeader(t *testing.T) {
+	// Arrange
+	async := New(config.Config{}, zerolog.Nop(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mocks.NewDirectorySyncService(t), nil, nil, nil, nil)
+	async.elector = leader.New(mocks.NewLeaseStore(t), zerolog.Nop(), singletonLease, time.Minute)
+
+	// Act
+	err := async.syncDirectory(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, leader.ErrNotLeader)
+}
@@ -2,18 +2,19 @@ package healthchecker
 
 import (
 	"context"
-	"log"
 	"net/http"
 	"time"
+
+	"github.com/rs/zerolog"
 )
 
 const contentType = "application/json"
 
-func Run(ctx context.Context, cancel context.CancelFunc, url string, interval time.Duration) {
+func Run(ctx context.Context, cancel context.CancelFunc, logger zerolog.Logger, url string, interval time.Duration) {
 	defer cancel()
 	defer func() {
 		if rec := recover(); rec != nil {
-			log.Print("recovered panic in async process: %w", rec)
+			logger.Error().Interface("panic", rec).Msg("recovered panic in async process")
 		}
 	}()
 
@@ -24,7 +25,7 @@ func Run(ctx context.Context, cancel context.CancelFunc, url string, interval ti
 
 		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
 		if err != nil {
-			log.Printf("async process failure, error: %s", err)
+			logger.Error().Err(err).Msg("health check failed")
 			continue
 		}
 
@@ -32,16 +33,15 @@ func Run(ctx context.Context, cancel context.CancelFunc, url string, interval ti
 
 		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
-			log.Printf("async process failure, error: %s", err)
+			logger.Error().Err(err).Msg("health check failed")
 			continue
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			log.Printf("async process failure, error: expected status code 200 but got %d", resp.StatusCode)
+			logger.Error().Int("status", resp.StatusCode).Msg("health check failed")
 			continue
 		}
 
-		elapsed := time.Since(start)
-		log.Printf("Health Check complete, time elapsed: %s", elapsed)
+		logger.Debug().Dur("elapsed", time.Since(start)).Msg("health check complete")
 	}
 }
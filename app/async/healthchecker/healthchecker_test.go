@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,7 +18,7 @@ func TestRun_SuccessfulURLThenContextCancelled(t *testing.T) {
 	expectedError := context.DeadlineExceeded.Error()
 
 	// Act
-	Run(ctx, cancel, successURL, time.Second)
+	Run(ctx, cancel, zerolog.Nop(), successURL, time.Second)
 
 	// Assert
 	assert.Equal(t, expectedError, ctx.Err().Error())
@@ -32,7 +33,7 @@ func TestRun_InvalidURLThenContextCancelled(t *testing.T) {
 	expectedError := context.DeadlineExceeded.Error()
 
 	// Act
-	Run(ctx, cancel, notFoundURL, time.Second)
+	Run(ctx, cancel, zerolog.Nop(), notFoundURL, time.Second)
 
 	// Assert
 	assert.Equal(t, expectedError, ctx.Err().Error())
@@ -47,7 +48,7 @@ func TestRun_NotFoundURLThenContextCancelled(t *testing.T) {
 	expectedError := context.DeadlineExceeded.Error()
 
 	// Act
-	Run(ctx, cancel, notFoundURL, time.Second)
+	Run(ctx, cancel, zerolog.Nop(), notFoundURL, time.Second)
 
 	// Assert
 	assert.Equal(t, expectedError, ctx.Err().Error())
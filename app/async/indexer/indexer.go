@@ -0,0 +1,33 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/leader"
+)
+
+// Run keeps the users mirrored into the search index with the received sync function, which only returns once the sync stops,
+// running it again after the interval until the context is done, so a sync failing, or skipped as it is run by the leader, is resumed
+func Run(ctx context.Context, cancel context.CancelFunc, logger zerolog.Logger, sync func(ctx context.Context) error, interval time.Duration) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Error().Interface("panic", rec).Msg("recovered panic in async process")
+		}
+	}()
+
+	for ctx.Err() == nil {
+		err := sync(ctx)
+		switch {
+		case ctx.Err() != nil:
+		case errors.Is(err, leader.ErrNotLeader):
+			logger.Debug().Msg("search sync skipped, run by the leader")
+		case err != nil:
+			logger.Error().Err(err).Msg("search sync failed")
+		}
+		<-time.After(interval)
+	}
+}
@@ -0,0 +1,50 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRun_FailedSyncThenContextCancelled checks that Run runs the sync again after it fails and finishes when the context gets cancelled
+func TestRun_FailedSyncThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	calls := 0
+	sync := func(ctx context.Context) error {
+		calls++
+		return errors.New("sync error")
+	}
+	expectedError := context.DeadlineExceeded.Error()
+
+	// Act
+	Run(ctx, cancel, zerolog.Nop(), sync, time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+	assert.Greater(t, calls, 1)
+}
+
+// TestRun_SyncUntilContextCancelled checks that Run finishes when the context of a running sync gets cancelled
+func TestRun_SyncUntilContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	calls := 0
+	sync := func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	expectedError := context.DeadlineExceeded.Error()
+
+	// Act
+	Run(ctx, cancel, zerolog.Nop(), sync, time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+	assert.Equal(t, 1, calls)
+}
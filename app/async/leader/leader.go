@@ -0,0 +1,98 @@
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// releaseTimeout is the maximum time given to release the lease once stopped
+const releaseTimeout = 5 * time.Second
+
+// ErrNotLeader is returned by the singleton jobs when run by a replica not being the leader
+var ErrNotLeader = errors.New("not the leader")
+
+// Elector elects the leader among the replicas of the API as the one holding a lease, so the singleton jobs only run on one of them
+type Elector struct {
+	store  ports.LeaseStore
+	logger zerolog.Logger
+	name   string
+	holder string
+	ttl    time.Duration
+	leader atomic.Bool
+}
+
+// New creates an elector for the lease of the given name, held for the given TTL by this replica, identified by its host name
+// followed by a random suffix, so restarted replicas do not take over the lease of their previous run
+func New(store ports.LeaseStore, logger zerolog.Logger, name string, ttl time.Duration) *Elector {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	return &Elector{
+		store:  store,
+		logger: logger,
+		name:   name,
+		holder: host + "-" + hex.EncodeToString(suffix),
+		ttl:    ttl,
+	}
+}
+
+// Run tries to acquire the lease, or to renew it when leading, every third of its TTL until the context is done, releasing it then.
+// When the lease cannot be renewed the replica stops leading, as another one takes it over once expired.
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		e.elect(ctx)
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsLeader reports whether this replica holds the lease
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+func (e *Elector) elect(ctx context.Context) {
+	acquireCtx, cancel := context.WithTimeout(ctx, e.ttl/3)
+	acquired, err := e.store.Acquire(acquireCtx, e.name, e.holder, e.ttl)
+	cancel()
+	if err != nil {
+		e.logger.Warn().Err(err).Str("lease", e.name).Msg("lease cannot be acquired")
+		acquired = false
+	}
+
+	if e.leader.Swap(acquired) != acquired {
+		if acquired {
+			e.logger.Info().Str("lease", e.name).Str("holder", e.holder).Msg("leading the singleton jobs")
+		} else {
+			e.logger.Warn().Str("lease", e.name).Str("holder", e.holder).Msg("no longer leading the singleton jobs")
+		}
+	}
+}
+
+func (e *Elector) release() {
+	if !e.leader.Swap(false) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	if err := e.store.Release(ctx, e.name, e.holder); err != nil {
+		e.logger.Error().Err(err).Str("lease", e.name).Msg("lease cannot be released")
+	}
+}
@@ -0,0 +1,72 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestRun_Leading checks that Run leads while the lease is acquired, releasing it once the context is done
+func TestRun_Leading(t *testing.T) {
+	// Arrange
+	leaseStoreMock := mocks.NewLeaseStore(t)
+	elector := New(leaseStoreMock, zerolog.Nop(), "test-lease", time.Minute)
+	leaseStoreMock.On(testutils.FunctionName(t, ports.LeaseStore.Acquire), mock.Anything, "test-lease", elector.holder, time.Minute).Return(true, nil).Once()
+	leaseStoreMock.On(testutils.FunctionName(t, ports.LeaseStore.Release), mock.Anything, "test-lease", elector.holder).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		elector.Run(ctx)
+		close(done)
+	}()
+	assert.Eventually(t, elector.IsLeader, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	// Assert
+	assert.False(t, elector.IsLeader())
+}
+
+// TestRun_HeldByAnother checks that Run does not lead while the lease is held by another replica, nor releases it
+func TestRun_HeldByAnother(t *testing.T) {
+	// Arrange
+	leaseStoreMock := mocks.NewLeaseStore(t)
+	elector := New(leaseStoreMock, zerolog.Nop(), "test-lease", 30*time.Millisecond)
+	leaseStoreMock.On(testutils.FunctionName(t, ports.LeaseStore.Acquire), mock.Anything, "test-lease", elector.holder, 30*time.Millisecond).Return(false, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Act
+	elector.Run(ctx)
+
+	// Assert
+	assert.False(t, elector.IsLeader())
+	leaseStoreMock.AssertNotCalled(t, testutils.FunctionName(t, ports.LeaseStore.Release), mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestElect_Error checks that elect stops leading when the lease cannot be renewed
+func TestElect_Error(t *testing.T) {
+	// Arrange
+	leaseStoreMock := mocks.NewLeaseStore(t)
+	elector := New(leaseStoreMock, zerolog.Nop(), "test-lease", time.Minute)
+	elector.leader.Store(true)
+	leaseStoreMock.On(testutils.FunctionName(t, ports.LeaseStore.Acquire), mock.Anything, "test-lease", elector.holder, time.Minute).Return(false, errors.New("acquire error")).Once()
+
+	// Act
+	elector.elect(context.Background())
+
+	// Assert
+	assert.False(t, elector.IsLeader())
+}
@@ -0,0 +1,33 @@
+package retention
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// Job returns the scheduled job applying the retention policies with the received apply function, logging what every policy did or, in dry run, would do
+func Job(logger zerolog.Logger, apply func(ctx context.Context, dryRun bool) (models.RetentionResp, error), dryRun bool) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		resp, err := apply(ctx, dryRun)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range resp.Results {
+			if r.Error != "" {
+				logger.Error().Str("collection", r.Collection).Str("error", r.Error).Msg("retention policy failed")
+				continue
+			}
+			logger.Info().
+				Str("collection", r.Collection).
+				Str("action", string(r.Action)).
+				Time("before", r.Before).
+				Int64("count", r.Count).
+				Bool("dry_run", resp.DryRun).
+				Msg("retention policy applied")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestJob_SuccessfulApplication checks that the job applies the policies with the configured dry run,
+// succeeding even when a policy fails as the other ones are applied
+func TestJob_SuccessfulApplication(t *testing.T) {
+	// Arrange
+	calls := 0
+	var receivedDryRun bool
+	apply := func(ctx context.Context, dryRun bool) (models.RetentionResp, error) {
+		calls++
+		receivedDryRun = dryRun
+		return models.RetentionResp{
+			DryRun: dryRun,
+			Results: []models.RetentionResultResp{
+				{Collection: entities.EntityNameJob, Action: entities.RetentionPurge, Count: 1},
+				{Collection: entities.EntityNameAuditEvent, Action: entities.RetentionPurge, Error: "expire error"},
+			},
+		}, nil
+	}
+
+	// Act
+	err := Job(zerolog.Nop(), apply, true)(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, calls)
+	assert.True(t, receivedDryRun)
+}
+
+// TestJob_FailedApplication checks that the job fails with the error of the apply function
+func TestJob_FailedApplication(t *testing.T) {
+	// Arrange
+	expectedError := errors.New("apply error")
+	apply := func(ctx context.Context, dryRun bool) (models.RetentionResp, error) {
+		return models.RetentionResp{}, expectedError
+	}
+
+	// Act
+	err := Job(zerolog.Nop(), apply, false)(context.Background())
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
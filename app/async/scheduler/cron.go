@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// descriptors are the shorthands accepted in place of the five fields of a cron expression
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxYears is how far Next looks for a matching time, as an expression like 0 0 30 2 * never matches
+const maxYears = 5
+
+// field allowed range of a field of a cron expression
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 6},
+}
+
+// Schedule times a job is due, parsed from a cron expression
+type Schedule struct {
+	every time.Duration
+	// sets of the allowed values of every field, indexed by value
+	minutes, hours, days, months, weekdays []bool
+	// whether the day of month or the day of week are restricted, as when both are the day matches either of them
+	daysRestricted, weekdaysRestricted bool
+}
+
+// ParseSchedule parses a cron expression in UTC of five fields, minute, hour, day of month, month and day of week (0 being Sunday),
+// each one a * or a list of values and ranges, optionally stepped like */15 or 1-5/2.
+// The descriptors @yearly, @monthly, @weekly, @daily and @hourly are accepted as well, and @every followed by a duration, like @every 90m,
+// schedules the job at that fixed interval from the start.
+// The error returned tells the part of the expression not valid, without the expression.
+func ParseSchedule(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if every, ok := strings.CutPrefix(expr, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(every))
+		if err != nil || d <= 0 {
+			return Schedule{}, fmt.Errorf("@every must be followed by a positive duration")
+		}
+		return Schedule{every: d}, nil
+	}
+	if d, ok := descriptors[expr]; ok {
+		expr = d
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return Schedule{}, fmt.Errorf("it must have 5 fields or be a descriptor")
+	}
+
+	sets := make([][]bool, len(fields))
+	for i, f := range fields {
+		set, err := parseField(parts[i], f)
+		if err != nil {
+			return Schedule{}, err
+		}
+		sets[i] = set
+	}
+
+	return Schedule{
+		minutes:            sets[0],
+		hours:              sets[1],
+		days:               sets[2],
+		months:             sets[3],
+		weekdays:           sets[4],
+		daysRestricted:     !strings.HasPrefix(parts[2], "*"),
+		weekdaysRestricted: !strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// parseField returns the set of the values allowed by a field of a cron expression
+func parseField(value string, f field) ([]bool, error) {
+	set := make([]bool, f.max+1)
+	for _, item := range strings.Split(value, ",") {
+		rng, stepValue, stepped := strings.Cut(item, "/")
+		step := 1
+		if stepped {
+			s, err := strconv.Atoi(stepValue)
+			if err != nil || s < 1 {
+				return nil, fmt.Errorf("step %q of the %s not valid", stepValue, f.name)
+			}
+			step = s
+		}
+
+		from, to := f.min, f.max
+		if rng != "*" {
+			first, last, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = parseValue(first, f); err != nil {
+				return nil, err
+			}
+			to = from
+			if isRange {
+				if to, err = parseValue(last, f); err != nil {
+					return nil, err
+				}
+			} else if stepped {
+				to = f.max
+			}
+			if from > to {
+				return nil, fmt.Errorf("range %q of the %s not valid", rng, f.name)
+			}
+		}
+
+		for v := from; v <= to; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// parseValue parses a value of a field of a cron expression, checking it is in range
+func parseValue(value string, f field) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s %q not valid, it must be between %d and %d", f.name, value, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first time the job is due after the given one, in UTC, or the zero time if it is never due within the next years
+func (s Schedule) Next(after time.Time) time.Time {
+	after = after.UTC()
+	if s.every > 0 {
+		return after.Add(s.every)
+	}
+
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxYears, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !s.months[t.Month()]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !s.hours[t.Hour()]:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !s.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether the day is allowed, matching either the day of month or the day of week when both are restricted, as cron does
+func (s Schedule) dayMatches(t time.Time) bool {
+	day, weekday := s.days[t.Day()], s.weekdays[t.Weekday()]
+	if s.daysRestricted && s.weekdaysRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
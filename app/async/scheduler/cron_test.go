@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNext_Fields checks that Next returns the first time matching every field of the expression
+func TestNext_Fields(t *testing.T) {
+	// Arrange
+	schedule, err := ParseSchedule("30 2-4/2 * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Saturday
+	after := time.Date(2023, 7, 29, 10, 0, 0, 0, time.UTC)
+
+	// Act
+	next := schedule.Next(after)
+
+	// Assert
+	assert.Equal(t, time.Date(2023, 7, 31, 2, 30, 0, 0, time.UTC), next)
+	assert.Equal(t, time.Date(2023, 7, 31, 4, 30, 0, 0, time.UTC), schedule.Next(next))
+}
+
+// TestNext_Steps checks that Next returns the following step of a stepped field, never the given time itself
+func TestNext_Steps(t *testing.T) {
+	// Arrange
+	schedule, err := ParseSchedule("*/15 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Date(2023, 7, 29, 10, 15, 0, 0, time.UTC)
+
+	// Act
+	next := schedule.Next(after)
+
+	// Assert
+	assert.Equal(t, time.Date(2023, 7, 29, 10, 30, 0, 0, time.UTC), next)
+}
+
+// TestNext_DayOfMonthOrWeek checks that Next matches either the day of month or the day of week when both are restricted, as cron does
+func TestNext_DayOfMonthOrWeek(t *testing.T) {
+	// Arrange
+	schedule, err := ParseSchedule("0 0 1 * 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Monday
+	after := time.Date(2023, 7, 24, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	next := schedule.Next(after)
+
+	// Assert
+	assert.Equal(t, time.Date(2023, 7, 30, 0, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC), schedule.Next(next))
+}
+
+// TestNext_Descriptors checks that Next accepts the descriptors and the fixed intervals
+func TestNext_Descriptors(t *testing.T) {
+	// Arrange
+	daily, err := ParseSchedule("@daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+	every, err := ParseSchedule("@every 90m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Date(2023, 12, 31, 22, 10, 0, 0, time.UTC)
+
+	// Act
+	nextDaily := daily.Next(after)
+	nextEvery := every.Next(after)
+
+	// Assert
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), nextDaily)
+	assert.Equal(t, time.Date(2023, 12, 31, 23, 40, 0, 0, time.UTC), nextEvery)
+}
+
+// TestNext_NeverDue checks that Next returns the zero time when the expression never matches
+func TestNext_NeverDue(t *testing.T) {
+	// Arrange
+	schedule, err := ParseSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	next := schedule.Next(time.Date(2023, 7, 29, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.True(t, next.IsZero())
+}
+
+// TestParseSchedule_NotValid checks that ParseSchedule tells the part of the expression not valid
+func TestParseSchedule_NotValid(t *testing.T) {
+	// Arrange
+	expressions := map[string]string{
+		"* * * *":      "it must have 5 fields or be a descriptor",
+		"61 * * * *":   `minute "61" not valid, it must be between 0 and 59`,
+		"* 5-1 * * *":  `range "5-1" of the hour not valid`,
+		"*/0 * * * *":  `step "0" of the minute not valid`,
+		"@every -1m":   "@every must be followed by a positive duration",
+		"* * * JAN *":  `month "JAN" not valid, it must be between 1 and 12`,
+		"* * * * 0,7":  `day of week "7" not valid, it must be between 0 and 6`,
+		"@fortnightly": "it must have 5 fields or be a descriptor",
+	}
+
+	for expr, expectedError := range expressions {
+		// Act
+		_, err := ParseSchedule(expr)
+
+		// Assert
+		assert.EqualError(t, err, expectedError, expr)
+	}
+}
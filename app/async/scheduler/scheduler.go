@@ -0,0 +1,188 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/leader"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// statuses of the runs of the jobs
+const (
+	statusSucceeded = "succeeded"
+	statusFailed    = "failed"
+	statusSkipped   = "skipped"
+)
+
+// Func runs a job once, returning leader.ErrNotLeader when the job is left to the leader
+type Func func(ctx context.Context) error
+
+// job registered in the scheduler, with the state of its runs
+type job struct {
+	name     string
+	expr     string
+	schedule Schedule
+	enabled  bool
+	run      Func
+	running  atomic.Bool
+	overlaps atomic.Int64
+	next     atomic.Pointer[time.Time]
+	last     atomic.Pointer[models.ScheduledRunResp]
+}
+
+// Scheduler in-process cron scheduler running the registered jobs on their schedule.
+// A job never runs twice at once: a run due while the previous one is still running is skipped and counted as an overlap.
+type Scheduler struct {
+	logger zerolog.Logger
+	mu     sync.RWMutex
+	jobs   []*job
+}
+
+// New creates a scheduler without jobs
+func New(logger zerolog.Logger) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+	}
+}
+
+// Register adds a job run on the schedule of the cron expression, the disabled jobs being listed but never run.
+// It fails when the expression is not valid or a job with the same name is already registered.
+func (s *Scheduler) Register(name, expr string, enabled bool, run Func) error {
+	schedule, err := ParseSchedule(expr)
+	if err != nil {
+		return fmt.Errorf("schedule %q of job %s not valid: %w", expr, name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if j.name == name {
+			return fmt.Errorf("job %s already registered", name)
+		}
+	}
+	s.jobs = append(s.jobs, &job{
+		name:     name,
+		expr:     expr,
+		schedule: schedule,
+		enabled:  enabled,
+		run:      run,
+	})
+	return nil
+}
+
+// Run runs the enabled jobs on their schedule until the context is done, then waits for the runs in progress to finish
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.RLock()
+	jobs := s.jobs
+	s.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		if !j.enabled {
+			continue
+		}
+		wg.Add(1)
+		go func(j *job) {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+	wg.Wait()
+}
+
+// GetJobs returns the registered jobs, in the order they were registered, with the result of their last run
+func (s *Scheduler) GetJobs(ctx context.Context) []models.ScheduledJobResp {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resp := make([]models.ScheduledJobResp, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		resp = append(resp, models.ScheduledJobResp{
+			Name:      j.name,
+			Schedule:  j.expr,
+			Enabled:   j.enabled,
+			Running:   j.running.Load(),
+			NextRunAt: j.next.Load(),
+			Overlaps:  j.overlaps.Load(),
+			LastRun:   j.last.Load(),
+		})
+	}
+	return resp
+}
+
+// loop starts the runs of the job whenever it is due, skipping the ones overlapping the previous run
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	var runs sync.WaitGroup
+	defer runs.Wait()
+	defer j.next.Store(nil)
+
+	logger := s.logger.With().Str("job", j.name).Logger()
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			logger.Warn().Str("schedule", j.expr).Msg("scheduled job never due")
+			return
+		}
+		j.next.Store(&next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !j.running.CompareAndSwap(false, true) {
+			j.overlaps.Add(1)
+			logger.Warn().Msg("scheduled job skipped, the previous run is still running")
+			continue
+		}
+		runs.Add(1)
+		go func() {
+			defer runs.Done()
+			defer j.running.Store(false)
+			s.execute(ctx, logger, j)
+		}()
+	}
+}
+
+// execute runs the job once and keeps the result as its last run
+func (s *Scheduler) execute(ctx context.Context, logger zerolog.Logger, j *job) {
+	start := time.Now()
+	err := call(ctx, j.run)
+	run := models.ScheduledRunResp{
+		Status:     statusSucceeded,
+		StartedAt:  start.UTC(),
+		FinishedAt: time.Now().UTC(),
+	}
+
+	switch {
+	case errors.Is(err, leader.ErrNotLeader):
+		run.Status = statusSkipped
+		logger.Debug().Msg("scheduled job skipped, run by the leader")
+	case err != nil:
+		run.Status = statusFailed
+		run.Error = err.Error()
+		logger.Error().Err(err).Msg("scheduled job failed")
+	default:
+		logger.Info().Dur("elapsed", time.Since(start)).Msg("scheduled job complete")
+	}
+	j.last.Store(&run)
+}
+
+// call runs the job function, turning a panic into an error so it does not stop the scheduler
+func call(ctx context.Context, run Func) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("recovered panic: %v", rec)
+		}
+	}()
+	return run(ctx)
+}
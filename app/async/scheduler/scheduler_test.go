@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/leader"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// runFor registers the job on a schedule of a few milliseconds and runs the scheduler for the given time, returning the job once stopped
+func runFor(t *testing.T, enabled bool, run Func, d time.Duration) models.ScheduledJobResp {
+	t.Helper()
+
+	s := New(zerolog.Nop())
+	if err := s.Register("test_job", "@every 5ms", enabled, run); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	s.Run(ctx)
+	return s.GetJobs(context.Background())[0]
+}
+
+// TestRun_Succeeded checks that Run runs the job on its schedule until the context is done, keeping the result of its last run
+func TestRun_Succeeded(t *testing.T) {
+	// Arrange
+	var calls atomic.Int64
+	run := func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}
+
+	// Act
+	job := runFor(t, true, run, 50*time.Millisecond)
+
+	// Assert
+	assert.Greater(t, calls.Load(), int64(1))
+	assert.Equal(t, statusSucceeded, job.LastRun.Status)
+	assert.False(t, job.Running)
+	assert.Nil(t, job.NextRunAt)
+}
+
+// TestRun_Overlap checks that Run skips the runs due while the previous one is still running, counting them as overlaps
+func TestRun_Overlap(t *testing.T) {
+	// Arrange
+	var calls, concurrent, maxConcurrent atomic.Int64
+	run := func(ctx context.Context) error {
+		calls.Add(1)
+		if n := concurrent.Add(1); n > maxConcurrent.Load() {
+			maxConcurrent.Store(n)
+		}
+		defer concurrent.Add(-1)
+		<-time.After(30 * time.Millisecond)
+		return nil
+	}
+
+	// Act
+	job := runFor(t, true, run, 50*time.Millisecond)
+
+	// Assert
+	assert.Equal(t, int64(1), maxConcurrent.Load())
+	assert.Less(t, calls.Load(), int64(3))
+	assert.NotZero(t, job.Overlaps)
+}
+
+// TestRun_Failed checks that Run keeps the error of the failed run, recovering from a panic as from an error
+func TestRun_Failed(t *testing.T) {
+	// Arrange
+	failures := map[string]Func{
+		"run error": func(ctx context.Context) error {
+			return errors.New("run error")
+		},
+		"recovered panic: run panic": func(ctx context.Context) error {
+			panic("run panic")
+		},
+	}
+
+	for expectedError, run := range failures {
+		// Act
+		job := runFor(t, true, run, 20*time.Millisecond)
+
+		// Assert
+		assert.Equal(t, statusFailed, job.LastRun.Status)
+		assert.Equal(t, expectedError, job.LastRun.Error)
+	}
+}
+
+// TestRun_NotLeader checks that Run reports the runs left to the leader as skipped
+func TestRun_NotLeader(t *testing.T) {
+	// Arrange
+	run := func(ctx context.Context) error {
+		return leader.ErrNotLeader
+	}
+
+	// Act
+	job := runFor(t, true, run, 20*time.Millisecond)
+
+	// Assert
+	assert.Equal(t, statusSkipped, job.LastRun.Status)
+	assert.Empty(t, job.LastRun.Error)
+}
+
+// TestRun_Disabled checks that Run never runs a disabled job, still listed
+func TestRun_Disabled(t *testing.T) {
+	// Arrange
+	var calls atomic.Int64
+	run := func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}
+
+	// Act
+	job := runFor(t, false, run, 20*time.Millisecond)
+
+	// Assert
+	assert.Zero(t, calls.Load())
+	assert.False(t, job.Enabled)
+	assert.Nil(t, job.LastRun)
+}
+
+// TestRegister_AlreadyRegistered checks that Register fails when a job with the same name is already registered
+func TestRegister_AlreadyRegistered(t *testing.T) {
+	// Arrange
+	s := New(zerolog.Nop())
+	run := func(ctx context.Context) error { return nil }
+	if err := s.Register("test_job", "@hourly", true, run); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err := s.Register("test_job", "@daily", true, run)
+
+	// Assert
+	assert.EqualError(t, err, "job test_job already registered")
+}
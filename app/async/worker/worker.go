@@ -0,0 +1,218 @@
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// lockGrace is added to the timeout of the jobs to lock them, so a job is only claimed again once its worker is surely gone
+const lockGrace = time.Minute
+
+// updateTimeout is the maximum time given to store the outcome of a job, even once stopped
+const updateTimeout = 5 * time.Second
+
+// Handler runs a claimed job, updating its progress as it goes
+type Handler func(ctx context.Context, job *entities.Job) error
+
+// handler registered for a job type
+type handler struct {
+	run     Handler
+	timeout time.Duration
+}
+
+// Pool pool of workers claiming the due jobs of the registered types from the queue shared by every replica.
+// A failed job is retried after a backoff doubled on every attempt, and left in the dead letter once its last attempt failed.
+type Pool struct {
+	repository ports.JobRepository
+	logger     zerolog.Logger
+	config     config.Queue
+	id         string
+	mu         sync.RWMutex
+	handlers   map[string]handler
+}
+
+// New creates a pool without handlers, identified by the host name followed by a random suffix
+func New(repo ports.JobRepository, logger zerolog.Logger, cfg config.Queue) *Pool {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	return &Pool{
+		repository: repo,
+		logger:     logger,
+		config:     cfg,
+		id:         host + "-" + hex.EncodeToString(suffix),
+		handlers:   map[string]handler{},
+	}
+}
+
+// Handle registers the handler running the jobs of a type, given the timeout to complete every attempt
+func (p *Pool) Handle(jobType string, timeout time.Duration, run Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[jobType] = handler{run: run, timeout: timeout}
+}
+
+// Run runs the workers of the pool until the context is done, then waits for them to requeue the jobs they were running
+func (p *Pool) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < p.config.Workers; i++ {
+		wg.Add(1)
+		go func(worker string) {
+			defer wg.Done()
+			p.loop(ctx, worker)
+		}(fmt.Sprintf("%s-%d", p.id, i))
+	}
+	wg.Wait()
+}
+
+// loop claims and runs the due jobs one at a time, polling the queue every PollInterval while none is due
+func (p *Pool) loop(ctx context.Context, worker string) {
+	logger := p.logger.With().Str("worker", worker).Logger()
+	for ctx.Err() == nil {
+		types, lock := p.registered()
+		now := time.Now().UTC()
+		job, err := p.repository.Claim(ctx, types, worker, now, now.Add(lock+lockGrace))
+		if err == nil {
+			p.process(ctx, logger, job)
+			continue
+		}
+		if !errors.Is(err, wrappers.NonExistentErr) && ctx.Err() == nil {
+			logger.Error().Err(err).Msg("job cannot be claimed")
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(p.config.PollInterval.Duration):
+		}
+	}
+}
+
+// registered returns the registered job types, sorted, and the longest of their timeouts
+func (p *Pool) registered() ([]string, time.Duration) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	types := make([]string, 0, len(p.handlers))
+	var timeout time.Duration
+	for t, h := range p.handlers {
+		types = append(types, t)
+		if h.timeout > timeout {
+			timeout = h.timeout
+		}
+	}
+	sort.Strings(types)
+	return types, timeout
+}
+
+// process runs the claimed job and stores its outcome. A job claimed again after its worker was gone past its last attempt is not run.
+func (p *Pool) process(ctx context.Context, logger zerolog.Logger, job entities.Job) {
+	logger = logger.With().Str("type", job.Type).Str("job_id", job.ID).Int("attempt", job.Attempts).Logger()
+
+	p.mu.RLock()
+	h, ok := p.handlers[job.Type]
+	p.mu.RUnlock()
+
+	var err error
+	switch {
+	case !ok:
+		err = fmt.Errorf("job type %s not handled", job.Type)
+	case job.MaxAttempts > 0 && job.Attempts > job.MaxAttempts:
+		err = fmt.Errorf("job abandoned by its worker on its last attempt")
+	default:
+		err = call(ctx, h, &job)
+	}
+
+	// the job interrupted by the stop is queued again, without counting the attempt
+	if err != nil && ctx.Err() != nil {
+		logger.Warn().Err(err).Msg("job interrupted, queued again")
+		p.requeue(logger, job)
+		return
+	}
+	p.finish(logger, job, err)
+}
+
+// call runs the handler within its timeout, turning a panic into an error so it does not stop the worker
+func call(ctx context.Context, h handler, job *entities.Job) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("recovered panic: %v", rec)
+		}
+	}()
+	return h.run(ctx, job)
+}
+
+// finish stores the outcome of the attempt: succeeded, failed to be retried after the backoff, or dead once it was the last attempt
+func (p *Pool) finish(logger zerolog.Logger, job entities.Job, err error) {
+	now := time.Now().UTC()
+	job.UpdatedAt = now
+	job.Worker = ""
+	job.LockedUntil = nil
+	job.Error = ""
+
+	switch {
+	case err == nil:
+		job.Status = entities.JobStatusSucceeded
+		job.FinishedAt = &now
+		logger.Info().Msg("job succeeded")
+	case job.Attempts >= job.MaxAttempts:
+		job.Status = entities.JobStatusDead
+		job.Error = err.Error()
+		job.FinishedAt = &now
+		logger.Error().Err(err).Msg("job failed on its last attempt, left in the dead letter")
+	default:
+		job.Status = entities.JobStatusFailed
+		job.Error = err.Error()
+		job.RunAt = now.Add(p.backoff(job.Attempts))
+		logger.Warn().Err(err).Time("retry_at", job.RunAt).Msg("job failed, to be retried")
+	}
+	p.update(logger, job)
+}
+
+// requeue queues again a job interrupted by the stop of its worker, to be claimed right away by another one
+func (p *Pool) requeue(logger zerolog.Logger, job entities.Job) {
+	now := time.Now().UTC()
+	job.Status = entities.JobStatusQueued
+	job.Attempts--
+	job.RunAt = now
+	job.UpdatedAt = now
+	job.Worker = ""
+	job.LockedUntil = nil
+	p.update(logger, job)
+}
+
+// update stores the job detached from the context of the workers, as it may be done already
+func (p *Pool) update(logger zerolog.Logger, job entities.Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), updateTimeout)
+	defer cancel()
+	if err := p.repository.Update(ctx, job); err != nil {
+		logger.Error().Err(err).Msg("job cannot be updated")
+	}
+}
+
+// backoff returns the wait before the attempt following the given one, doubled from InitialBackoff on every attempt up to MaxBackoff
+func (p *Pool) backoff(attempt int) time.Duration {
+	backoff := p.config.InitialBackoff.Duration
+	for i := 1; i < attempt && backoff < p.config.MaxBackoff.Duration; i++ {
+		backoff *= 2
+	}
+	if backoff > p.config.MaxBackoff.Duration {
+		backoff = p.config.MaxBackoff.Duration
+	}
+	return backoff
+}
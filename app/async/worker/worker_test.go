@@ -0,0 +1,176 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testConfig returns the queue settings of the tests
+func testConfig() config.Queue {
+	return config.Queue{
+		Workers:        1,
+		PollInterval:   utils.Duration{Duration: 10 * time.Millisecond},
+		MaxAttempts:    3,
+		InitialBackoff: utils.Duration{Duration: time.Second},
+		MaxBackoff:     utils.Duration{Duration: 5 * time.Second},
+	}
+}
+
+// TestRun_Succeeded checks that Run claims the due jobs of the registered types and stores them as succeeded once handled
+func TestRun_Succeeded(t *testing.T) {
+	// Arrange
+	job := entities.Job{ID: "job-id", Type: entities.JobTypeNotification, Status: entities.JobStatusRunning, Attempts: 1, MaxAttempts: 3}
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Claim), mock.Anything, []string{entities.JobTypeNotification}, mock.Anything, mock.Anything, mock.Anything).Return(job, nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updated := make(chan entities.Job, 1)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Update), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		updated <- args.Get(1).(entities.Job)
+		cancel()
+	}).Return(nil).Once()
+
+	pool := New(jobRepositoryMock, zerolog.Nop(), testConfig())
+	var handled string
+	pool.Handle(entities.JobTypeNotification, time.Minute, func(ctx context.Context, job *entities.Job) error {
+		handled = job.ID
+		return nil
+	})
+
+	// Act
+	pool.Run(ctx)
+
+	// Assert
+	result := <-updated
+	assert.Equal(t, job.ID, handled)
+	assert.Equal(t, entities.JobStatusSucceeded, result.Status)
+	assert.NotNil(t, result.FinishedAt)
+	assert.Nil(t, result.LockedUntil)
+}
+
+// TestProcess_Failed checks that process stores a failed job to be retried after the backoff when attempts are left
+func TestProcess_Failed(t *testing.T) {
+	// Arrange
+	expectedError := "job-error"
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Update), mock.Anything, mock.MatchedBy(func(job entities.Job) bool {
+		return job.Status == entities.JobStatusFailed && job.Error == expectedError && job.FinishedAt == nil && job.RunAt.After(time.Now().Add(time.Second))
+	})).Return(nil).Once()
+
+	pool := New(jobRepositoryMock, zerolog.Nop(), testConfig())
+	pool.Handle(entities.JobTypeNotification, time.Minute, func(ctx context.Context, job *entities.Job) error {
+		return errors.New(expectedError)
+	})
+
+	// Act
+	pool.process(context.Background(), zerolog.Nop(), entities.Job{ID: "job-id", Type: entities.JobTypeNotification, Attempts: 2, MaxAttempts: 3})
+
+	// Assert
+	// the expectations of the mock are asserted on cleanup
+}
+
+// TestProcess_Dead checks that process leaves the job in the dead letter when its last attempt fails
+func TestProcess_Dead(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Update), mock.Anything, mock.MatchedBy(func(job entities.Job) bool {
+		return job.Status == entities.JobStatusDead && job.Error == "recovered panic: job-panic" && job.FinishedAt != nil
+	})).Return(nil).Once()
+
+	pool := New(jobRepositoryMock, zerolog.Nop(), testConfig())
+	pool.Handle(entities.JobTypeNotification, time.Minute, func(ctx context.Context, job *entities.Job) error {
+		panic("job-panic")
+	})
+
+	// Act
+	pool.process(context.Background(), zerolog.Nop(), entities.Job{ID: "job-id", Type: entities.JobTypeNotification, Attempts: 3, MaxAttempts: 3})
+
+	// Assert
+	// the expectations of the mock are asserted on cleanup
+}
+
+// TestProcess_Abandoned checks that process does not run a job claimed again past its last attempt, leaving it in the dead letter
+func TestProcess_Abandoned(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Update), mock.Anything, mock.MatchedBy(func(job entities.Job) bool {
+		return job.Status == entities.JobStatusDead
+	})).Return(nil).Once()
+
+	pool := New(jobRepositoryMock, zerolog.Nop(), testConfig())
+	pool.Handle(entities.JobTypeNotification, time.Minute, func(ctx context.Context, job *entities.Job) error {
+		t.Error("abandoned job run")
+		return nil
+	})
+
+	// Act
+	pool.process(context.Background(), zerolog.Nop(), entities.Job{ID: "job-id", Type: entities.JobTypeNotification, Attempts: 4, MaxAttempts: 3})
+
+	// Assert
+	// the expectations of the mock are asserted on cleanup
+}
+
+// TestProcess_Interrupted checks that process queues again the job interrupted by the stop, without counting the attempt
+func TestProcess_Interrupted(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Update), mock.Anything, mock.MatchedBy(func(job entities.Job) bool {
+		return job.Status == entities.JobStatusQueued && job.Attempts == 1 && job.Worker == ""
+	})).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := New(jobRepositoryMock, zerolog.Nop(), testConfig())
+	pool.Handle(entities.JobTypeBackup, time.Minute, func(ctx context.Context, job *entities.Job) error {
+		cancel()
+		return ctx.Err()
+	})
+
+	// Act
+	pool.process(ctx, zerolog.Nop(), entities.Job{ID: "job-id", Type: entities.JobTypeBackup, Attempts: 2, MaxAttempts: 3, Worker: "test-worker"})
+
+	// Assert
+	// the expectations of the mock are asserted on cleanup
+}
+
+// TestLoop_NoJobDue checks that loop polls the queue every PollInterval while no job is due
+func TestLoop_NoJobDue(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Claim), mock.Anything, mock.Anything, "test-worker", mock.Anything, mock.Anything).Return(entities.Job{}, wrappers.NewNonExistentErr(errors.New("no job due")))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Millisecond)
+	defer cancel()
+	pool := New(jobRepositoryMock, zerolog.Nop(), testConfig())
+
+	// Act
+	pool.loop(ctx, "test-worker")
+
+	// Assert
+	assert.GreaterOrEqual(t, len(jobRepositoryMock.Calls), 2)
+}
+
+// TestBackoff_Ok checks that backoff doubles the wait on every attempt up to MaxBackoff
+func TestBackoff_Ok(t *testing.T) {
+	// Arrange
+	pool := New(nil, zerolog.Nop(), testConfig())
+
+	// Act
+	backoffs := []time.Duration{pool.backoff(1), pool.backoff(2), pool.backoff(3), pool.backoff(4)}
+
+	// Assert
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}, backoffs)
+}
@@ -0,0 +1,123 @@
+package buildinfo
+
+import (
+	"expvar"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// metadata of the build, set with the linker flags, like
+// -ldflags "-X github.com/sergicanet9/go-hexagonal-api/app/buildinfo.version=v1.2.0 -X github.com/sergicanet9/go-hexagonal-api/app/buildinfo.commit=4f1c2a9 -X github.com/sergicanet9/go-hexagonal-api/app/buildinfo.date=2023-08-10T09:00:00Z"
+var (
+	version string
+	commit  string
+	date    string
+)
+
+// shortCommit is the length of the commit hash added to the release
+const shortCommit = 12
+
+// Info metadata of the build of the running binary
+type Info struct {
+	Version   string
+	Commit    string
+	Date      string
+	GoVersion string
+}
+
+// Get returns the metadata of the build. The commit and date not set with the linker flags are taken from the VCS settings stamped by the Go toolchain,
+// and the version from the module version, when built with go install.
+func Get() Info {
+	return resolve(version, commit, date, debug.ReadBuildInfo)
+}
+
+func resolve(version, commit, date string, read func() (*debug.BuildInfo, bool)) Info {
+	info := Info{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+	}
+
+	bi, ok := read()
+	if !ok {
+		return info
+	}
+	if info.Version == "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	modified := false
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+			}
+		case "vcs.time":
+			if info.Date == "" {
+				info.Date = s.Value
+			}
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if modified && commit == "" && info.Commit != "" {
+		info.Commit += "-dirty"
+	}
+	return info
+}
+
+// WithVersion returns the metadata with the given version, like the one of the ver flag, overriding the built one when set
+func (i Info) WithVersion(version string) Info {
+	if version != "" {
+		i.Version = version
+	}
+	return i
+}
+
+// Release returns the release the errors are reported with, the version followed by the short commit when known,
+// so the builds of a version not tagged again can be told apart
+func (i Info) Release() string {
+	if i.Commit == "" {
+		return i.Version
+	}
+	c := i.Commit
+	if len(c) > shortCommit {
+		c = c[:shortCommit]
+	}
+	return i.Version + "+" + c
+}
+
+// Fields returns the log fields of the metadata set, so the logs of every build running in the fleet can be told apart
+func (i Info) Fields() map[string]interface{} {
+	fields := make(map[string]interface{})
+	for field, value := range map[string]string{"version": i.Version, "commit": i.Commit, "build_date": i.Date} {
+		if value != "" {
+			fields[field] = value
+		}
+	}
+	return fields
+}
+
+var (
+	published   atomic.Pointer[Info]
+	publishOnce sync.Once
+)
+
+// Publish publishes the metadata as the build_info metric, the labels the other metrics of the replica are reported with
+func Publish(info Info) {
+	published.Store(&info)
+	publishOnce.Do(func() {
+		expvar.Publish("build_info", expvar.Func(func() interface{} {
+			i := published.Load()
+			return map[string]string{
+				"version":    i.Version,
+				"commit":     i.Commit,
+				"build_date": i.Date,
+				"go_version": i.GoVersion,
+			}
+		}))
+	})
+}
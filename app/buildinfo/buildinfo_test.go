@@ -0,0 +1,99 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"expvar"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stamped returns the build info stamped by the Go toolchain for a modified checkout of the given commit
+func stamped() (*debug.BuildInfo, bool) {
+	return &debug.BuildInfo{
+		Main: debug.Module{Version: "(devel)"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "4f1c2a9d8e7b6a5f4c3d2e1f0a9b8c7d6e5f4a3b"},
+			{Key: "vcs.time", Value: "2023-08-10T09:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}, true
+}
+
+// TestResolve_LinkerFlags checks that resolve keeps the metadata set with the linker flags over the one stamped by the Go toolchain
+func TestResolve_LinkerFlags(t *testing.T) {
+	// Act
+	info := resolve("v1.2.0", "abc123", "2023-08-11T10:00:00Z", stamped)
+
+	// Assert
+	assert.Equal(t, "v1.2.0", info.Version)
+	assert.Equal(t, "abc123", info.Commit)
+	assert.Equal(t, "2023-08-11T10:00:00Z", info.Date)
+	assert.NotEmpty(t, info.GoVersion)
+}
+
+// TestResolve_Stamped checks that resolve takes the commit and date not set with the linker flags from the VCS settings, marking the modified checkouts
+func TestResolve_Stamped(t *testing.T) {
+	// Act
+	info := resolve("", "", "", stamped)
+
+	// Assert
+	assert.Equal(t, "", info.Version)
+	assert.Equal(t, "4f1c2a9d8e7b6a5f4c3d2e1f0a9b8c7d6e5f4a3b-dirty", info.Commit)
+	assert.Equal(t, "2023-08-10T09:00:00Z", info.Date)
+}
+
+// TestWithVersion_Ok checks that WithVersion overrides the built version only when the given one is set
+func TestWithVersion_Ok(t *testing.T) {
+	// Arrange
+	info := Info{Version: "v1.2.0"}
+
+	// Act
+	overridden, kept := info.WithVersion("v1.3.0"), info.WithVersion("")
+
+	// Assert
+	assert.Equal(t, "v1.3.0", overridden.Version)
+	assert.Equal(t, "v1.2.0", kept.Version)
+}
+
+// TestRelease_Ok checks that Release adds the short commit to the version when known
+func TestRelease_Ok(t *testing.T) {
+	// Arrange
+	info := Info{Version: "v1.2.0", Commit: "4f1c2a9d8e7b6a5f4c3d2e1f"}
+
+	// Act
+	release := info.Release()
+
+	// Assert
+	assert.Equal(t, "v1.2.0+4f1c2a9d8e7b", release)
+	assert.Equal(t, "v1.2.0", Info{Version: "v1.2.0"}.Release())
+}
+
+// TestFields_Ok checks that Fields returns only the metadata set
+func TestFields_Ok(t *testing.T) {
+	// Arrange
+	info := Info{Version: "v1.2.0", Date: "2023-08-10T09:00:00Z", GoVersion: "go1.20"}
+
+	// Act
+	fields := info.Fields()
+
+	// Assert
+	assert.Equal(t, map[string]interface{}{"version": "v1.2.0", "build_date": "2023-08-10T09:00:00Z"}, fields)
+}
+
+// TestPublish_Ok checks that Publish exports the metadata last published as the build_info metric
+func TestPublish_Ok(t *testing.T) {
+	// Arrange
+	Publish(Info{Version: "v1.2.0"})
+
+	// Act
+	Publish(Info{Version: "v1.3.0", Commit: "abc123", Date: "2023-08-10T09:00:00Z", GoVersion: "go1.20"})
+
+	// Assert
+	var labels map[string]string
+	if err := json.Unmarshal([]byte(expvar.Get("build_info").String()), &labels); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{"version": "v1.3.0", "commit": "abc123", "build_date": "2023-08-10T09:00:00Z", "go_version": "go1.20"}, labels)
+}
@@ -0,0 +1,149 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/logging"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// excludedPrefix is the prefix of the debug routes, whose requests are never captured, so the captures are not captured themselves
+const excludedPrefix = "/v1/debug/"
+
+// truncatedSuffix is appended to the bodies cut to the maximum size
+const truncatedSuffix = "...[truncated]"
+
+// responseRecorder records the status code and the beginning of the body written by a handler
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        []byte
+	truncated   bool
+	maxBodySize int
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body, r.truncated = appendLimited(r.body, b, r.maxBodySize, r.truncated)
+	return r.ResponseWriter.Write(b)
+}
+
+// Middleware captures the requests sampled by the capture service, saving their request and response with their bodies sanitized
+// in the background once served, so the responses are not delayed, within the given timeout.
+// Bodies are cut to the maximum size, and the ones not being JSON or text are only described by their content type.
+// They are sanitized as the log lines are, so passwords, tokens and emails are not captured.
+func Middleware(s ports.CaptureService, logger zerolog.Logger, maxBodySize int, timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, excludedPrefix) || !s.Sampled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var reqBody []byte
+			var reqTruncated bool
+			if r.Body != nil {
+				read, err := io.ReadAll(io.LimitReader(r.Body, int64(maxBodySize)+1))
+				if err != nil {
+					logger.Warn().Err(err).Msg("request body cannot be captured")
+				}
+				reqBody, reqTruncated = appendLimited(nil, read, maxBodySize, false)
+				// the handler still reads the whole body, starting with the part already read
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(read), r.Body), r.Body}
+			}
+
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK, maxBodySize: maxBodySize}
+			next.ServeHTTP(rec, r)
+
+			info := models.RequestInfoFrom(r.Context())
+			req := models.CaptureReq{
+				RequestID:    info.RequestID,
+				ActorID:      info.ActorID,
+				Method:       r.Method,
+				Path:         r.URL.Path,
+				Query:        sanitizeQuery(r.URL.RawQuery),
+				Status:       rec.status,
+				RequestBody:  sanitize(reqBody, r.Header.Get("Content-Type"), reqTruncated),
+				ResponseBody: sanitize(rec.body, rec.Header().Get("Content-Type"), rec.truncated),
+			}
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				defer cancel()
+
+				if err := s.Save(ctx, req); err != nil {
+					logger.Error().Err(err).Str("request_id", req.RequestID).Msg("capture cannot be saved")
+				}
+			}()
+		})
+	}
+}
+
+// appendLimited appends b to body up to the maximum size, returning whether anything was left out
+func appendLimited(body, b []byte, maxSize int, truncated bool) ([]byte, bool) {
+	if n := maxSize - len(body); n < len(b) {
+		if n > 0 {
+			body = append(body, b[:n]...)
+		}
+		return body, true
+	}
+	return append(body, b...), truncated
+}
+
+// sanitizeQuery returns the query unescaped, with the values of its sensitive parameters replaced and the rest redacted as the log lines are
+func sanitizeQuery(query string) string {
+	if query == "" {
+		return ""
+	}
+
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, value, _ := strings.Cut(param, "=")
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if logging.IsSensitive(key) {
+			params[i] = key + "=" + logging.RedactedValue
+			continue
+		}
+		if v, err := url.QueryUnescape(value); err == nil {
+			value = v
+		}
+		params[i] = key + "=" + string(logging.Redact([]byte(value)))
+	}
+	return strings.Join(params, "&")
+}
+
+// sanitize returns the body redacted, or only described by its content type when it is not JSON or text
+func sanitize(body []byte, contentType string, truncated bool) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "" && !strings.HasPrefix(mediaType, "text/") && !strings.HasSuffix(mediaType, "json") {
+		return fmt.Sprintf("[%s body not captured]", mediaType)
+	}
+
+	sanitized := string(logging.Redact(body))
+	if truncated {
+		sanitized += truncatedSuffix
+	}
+	return sanitized
+}
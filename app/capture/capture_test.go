@@ -0,0 +1,142 @@
+package capture
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestMiddleware_Sampled checks that Middleware saves the sanitized request and response of the sampled requests, without changing them
+func TestMiddleware_Sampled(t *testing.T) {
+	// Arrange
+	saved := make(chan models.CaptureReq, 1)
+	captureService := mocks.NewCaptureService(t)
+	captureService.On(testutils.FunctionName(t, ports.CaptureService.Sampled)).Return(true).Once()
+	captureService.On(testutils.FunctionName(t, ports.CaptureService.Save), mock.Anything, mock.AnythingOfType("models.CaptureReq")).Return(nil).Run(func(args mock.Arguments) {
+		saved <- args.Get(1).(models.CaptureReq)
+	}).Once()
+
+	var handlerBody string
+	handler := Middleware(captureService, zerolog.Nop(), 1024, time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		handlerBody = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"token":"test-token","email":"test@example.com"}`))
+	}))
+	req := httptest.NewRequest(http.MethodPost, "http://testing/v1/users/login?q=test%40example.com&api_key=test-key", strings.NewReader(`{"email":"test@example.com","password": "test-password"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(models.WithRequestInfo(req.Context(), models.RequestInfo{RequestID: "test-request-id", ActorID: "test-id"}))
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(t, `{"email":"test@example.com","password": "test-password"}`, handlerBody)
+	assert.Equal(t, `{"token":"test-token","email":"test@example.com"}`, rr.Body.String())
+	select {
+	case capture := <-saved:
+		assert.Equal(t, models.CaptureReq{
+			RequestID:    "test-request-id",
+			ActorID:      "test-id",
+			Method:       http.MethodPost,
+			Path:         "/v1/users/login",
+			Query:        "q=t***@example.com&api_key=[REDACTED]",
+			Status:       http.StatusCreated,
+			RequestBody:  `{"email":"t***@example.com","password": "[REDACTED]"}`,
+			ResponseBody: `{"token":"[REDACTED]","email":"t***@example.com"}`,
+		}, capture)
+	case <-time.After(time.Second):
+		t.Fatal("capture not saved")
+	}
+}
+
+// TestMiddleware_NotSampled checks that Middleware does not capture the requests not sampled
+func TestMiddleware_NotSampled(t *testing.T) {
+	// Arrange
+	captureService := mocks.NewCaptureService(t)
+	captureService.On(testutils.FunctionName(t, ports.CaptureService.Sampled)).Return(false).Once()
+
+	handler := Middleware(captureService, zerolog.Nop(), 1024, time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://testing/v1/users", nil))
+
+	// Assert
+	captureService.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+// TestMiddleware_DebugRoutes checks that Middleware never captures the requests to the debug routes
+func TestMiddleware_DebugRoutes(t *testing.T) {
+	// Arrange
+	captureService := mocks.NewCaptureService(t)
+
+	handler := Middleware(captureService, zerolog.Nop(), 1024, time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://testing/v1/debug/captures", nil))
+
+	// Assert
+	captureService.AssertNotCalled(t, "Sampled")
+}
+
+// TestMiddleware_SaveError checks that Middleware does not fail the request when the capture cannot be saved
+func TestMiddleware_SaveError(t *testing.T) {
+	// Arrange
+	done := make(chan struct{})
+	captureService := mocks.NewCaptureService(t)
+	captureService.On(testutils.FunctionName(t, ports.CaptureService.Sampled)).Return(true).Once()
+	captureService.On(testutils.FunctionName(t, ports.CaptureService.Save), mock.Anything, mock.Anything).Return(context.DeadlineExceeded).Run(func(args mock.Arguments) {
+		close(done)
+	}).Once()
+
+	handler := Middleware(captureService, zerolog.Nop(), 1024, time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "http://testing/v1/users/test-id", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("capture not saved")
+	}
+}
+
+// TestSanitize_Truncated checks that sanitize cuts the bodies exceeding the maximum size
+func TestSanitize_Truncated(t *testing.T) {
+	// Arrange
+	body, truncated := appendLimited(nil, []byte(`{"name":"test"}`), 8, false)
+
+	// Act
+	sanitized := sanitize(body, "application/json", truncated)
+
+	// Assert
+	assert.Equal(t, `{"name":`+truncatedSuffix, sanitized)
+}
+
+// TestSanitize_Binary checks that sanitize only describes the bodies not being JSON or text
+func TestSanitize_Binary(t *testing.T) {
+	// Act
+	sanitized := sanitize([]byte{0x89, 0x50, 0x4e, 0x47}, "image/png", false)
+
+	// Assert
+	assert.Equal(t, "[image/png body not captured]", sanitized)
+}
@@ -0,0 +1,169 @@
+// Package claimsgen generates the claims of the users from their definition in core/entities/claims.yaml, the single source of them:
+// the UserClaim constants along with their definitions and the methods reading them, and the enum values of the claims fields of the models,
+// documented in the OpenAPI document, so adding a claim only touches its definition.
+package claimsgen
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Claim a claim of the users, whose Value is the one stored in their claims, so it must never change once released,
+// grouped by Category and Deprecated when it should no longer be granted
+type Claim struct {
+	Value       int    `yaml:"value"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Category    string `yaml:"category"`
+	Deprecated  bool   `yaml:"deprecated"`
+}
+
+// validName matches the names and the categories of the claims, in snake case, the names as they are set in the tokens
+var validName = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// claimsTag matches the struct tags of the claims fields, named claims in their JSON, with the enum values already set if any
+var claimsTag = regexp.MustCompile("`json:\"claims\"( enums:\"[^\"]*\")?`")
+
+// Parse reads the claims of the definition, sorted by value, checking that their values and names are unique and their names and categories valid
+func Parse(source []byte) ([]Claim, error) {
+	var claims []Claim
+	if err := yaml.Unmarshal(source, &claims); err != nil {
+		return nil, fmt.Errorf("claims not valid: %w", err)
+	}
+	if len(claims) == 0 {
+		return nil, fmt.Errorf("no claims defined")
+	}
+
+	values := map[int]bool{}
+	names := map[string]bool{}
+	for _, c := range claims {
+		switch {
+		case c.Value < 0:
+			return nil, fmt.Errorf("value %d of claim %s cannot be negative", c.Value, c.Name)
+		case !validName.MatchString(c.Name):
+			return nil, fmt.Errorf("name %q of claim %d not valid, it must be in snake case", c.Name, c.Value)
+		case !validName.MatchString(c.Category):
+			return nil, fmt.Errorf("category %q of claim %s not valid, it must be in snake case", c.Category, c.Name)
+		case values[c.Value]:
+			return nil, fmt.Errorf("value %d of claim %s repeated", c.Value, c.Name)
+		case names[c.Name]:
+			return nil, fmt.Errorf("name %s of claim %d repeated", c.Name, c.Value)
+		}
+		values[c.Value] = true
+		names[c.Name] = true
+	}
+
+	sort.Slice(claims, func(i, j int) bool { return claims[i].Value < claims[j].Value })
+	return claims, nil
+}
+
+// GenerateGo generates the source of the UserClaim constants of the package, named after the claims, like AdminClaim for admin,
+// with their UserClaimDefinition, declared in the package, and the methods and functions the API reads them with
+func GenerateGo(claims []Claim, pkg string, source string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("package name %s not valid", pkg)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by claimsgen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+	b.WriteString("// UserClaim claim of the users, granting them access to the routes requiring it\n")
+	b.WriteString("type UserClaim int\n\n")
+	b.WriteString("const (\n")
+	for _, c := range claims {
+		if c.Description != "" {
+			fmt.Fprintf(&b, "// %s %s\n", constName(c), comment(c.Description))
+		}
+		fmt.Fprintf(&b, "%s UserClaim = %d\n", constName(c), c.Value)
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("// userClaimDefinitions the definitions of the claims, sorted by value\n")
+	b.WriteString("var userClaimDefinitions = []UserClaimDefinition{\n")
+	for _, c := range claims {
+		fmt.Fprintf(&b, "{Claim: %s, Name: %s, Description: %s, Category: %s", constName(c), strconv.Quote(c.Name), strconv.Quote(c.Description), strconv.Quote(c.Category))
+		if c.Deprecated {
+			b.WriteString(", Deprecated: true")
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString(`// definition returns the definition of the claim, reporting whether it is defined
+func (claim UserClaim) definition() (UserClaimDefinition, bool) {
+	for _, d := range userClaimDefinitions {
+		if d.Claim == claim {
+			return d, true
+		}
+	}
+	return UserClaimDefinition{}, false
+}
+
+// String returns the name of the claim, empty if not valid
+func (claim UserClaim) String() string {
+	d, _ := claim.definition()
+	return d.Name
+}
+
+// IsValid reports whether the claim is defined, even if deprecated
+func (claim UserClaim) IsValid() bool {
+	_, ok := claim.definition()
+	return ok
+}
+
+// Description returns the description of the claim, empty if not valid
+func (claim UserClaim) Description() string {
+	d, _ := claim.definition()
+	return d.Description
+}
+
+// GetUserClaimDefinitions returns the definitions of the claims, sorted by value
+func GetUserClaimDefinitions() []UserClaimDefinition {
+	return append([]UserClaimDefinition(nil), userClaimDefinitions...)
+}
+`)
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated claims not valid: %w", err)
+	}
+	return formatted, nil
+}
+
+// TagModels sets the enum values of the claims fields of the source of the models, the ones named claims in their JSON,
+// to the values of the claims, reporting whether the source changed
+func TagModels(src []byte, claims []Claim) ([]byte, bool) {
+	values := make([]string, len(claims))
+	for i, c := range claims {
+		values[i] = strconv.Itoa(c.Value)
+	}
+	tag := fmt.Sprintf("`json:\"claims\" enums:\"%s\"`", strings.Join(values, ","))
+
+	tagged := claimsTag.ReplaceAllLiteral(src, []byte(tag))
+	return tagged, string(tagged) != string(src)
+}
+
+// constName returns the name of the constant of the claim, like ReadOnlyClaim for read_only
+func constName(c Claim) string {
+	var b strings.Builder
+	for _, word := range strings.Split(c.Name, "_") {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	b.WriteString("Claim")
+	return b.String()
+}
+
+// comment returns the description as the text of a comment, lower-casing its first letter to follow the name of the constant
+// and keeping it on a single line
+func comment(description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	return strings.ToLower(description[:1]) + description[1:]
+}
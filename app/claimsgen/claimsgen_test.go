@@ -0,0 +1,169 @@
+package claimsgen
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testClaims a definition of two claims, not sorted by value
+const testClaims = `
+- value: 1
+  name: read_only
+  description: Reads the users without changing them
+  category: users
+  deprecated: true
+- value: 0
+  name: admin
+  description: Administers the API
+  category: administration
+`
+
+// TestParse_Ok checks that Parse returns the claims of the definition sorted by value
+func TestParse_Ok(t *testing.T) {
+	// Arrange
+	expectedClaims := []Claim{
+		{Value: 0, Name: "admin", Description: "Administers the API", Category: "administration"},
+		{Value: 1, Name: "read_only", Description: "Reads the users without changing them", Category: "users", Deprecated: true},
+	}
+
+	// Act
+	claims, err := Parse([]byte(testClaims))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedClaims, claims)
+}
+
+// TestParse_RepeatedValue checks that Parse returns an error when two claims have the same value
+func TestParse_RepeatedValue(t *testing.T) {
+	// Arrange
+	source := "- {value: 0, name: admin, category: administration}\n- {value: 0, name: auditor, category: administration}\n"
+
+	// Act
+	_, err := Parse([]byte(source))
+
+	// Assert
+	assert.Equal(t, "value 0 of claim auditor repeated", err.Error())
+}
+
+// TestParse_RepeatedName checks that Parse returns an error when two claims have the same name
+func TestParse_RepeatedName(t *testing.T) {
+	// Arrange
+	source := "- {value: 0, name: admin, category: administration}\n- {value: 1, name: admin, category: administration}\n"
+
+	// Act
+	_, err := Parse([]byte(source))
+
+	// Assert
+	assert.Equal(t, "name admin of claim 1 repeated", err.Error())
+}
+
+// TestParse_InvalidName checks that Parse returns an error when the name of a claim is not in snake case
+func TestParse_InvalidName(t *testing.T) {
+	// Arrange
+	source := "- {value: 0, name: ReadOnly, category: users}\n"
+
+	// Act
+	_, err := Parse([]byte(source))
+
+	// Assert
+	assert.Equal(t, `name "ReadOnly" of claim 0 not valid, it must be in snake case`, err.Error())
+}
+
+// TestParse_InvalidCategory checks that Parse returns an error when the category of a claim is not set
+func TestParse_InvalidCategory(t *testing.T) {
+	// Arrange
+	source := "- {value: 0, name: admin}\n"
+
+	// Act
+	_, err := Parse([]byte(source))
+
+	// Assert
+	assert.Equal(t, `category "" of claim admin not valid, it must be in snake case`, err.Error())
+}
+
+// TestParse_NoClaims checks that Parse returns an error when the definition has no claims
+func TestParse_NoClaims(t *testing.T) {
+	// Act
+	_, err := Parse([]byte(""))
+
+	// Assert
+	assert.Equal(t, "no claims defined", err.Error())
+}
+
+// TestGenerateGo_Ok checks that GenerateGo generates a valid Go file with a constant per claim, named after it
+func TestGenerateGo_Ok(t *testing.T) {
+	// Arrange
+	claims, err := Parse([]byte(testClaims))
+	assert.Nil(t, err)
+
+	// Act
+	generated, err := GenerateGo(claims, "entities", "claims.yaml")
+
+	// Assert
+	assert.Nil(t, err)
+	file, err := parser.ParseFile(token.NewFileSet(), "claims_gen.go", generated, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "entities", file.Name.Name)
+	assert.Contains(t, string(generated), "// Code generated by claimsgen from claims.yaml. DO NOT EDIT.")
+	assert.Contains(t, string(generated), "\t// ReadOnlyClaim reads the users without changing them\n\tReadOnlyClaim UserClaim = 1\n")
+	assert.Contains(t, string(generated), "\t{Claim: AdminClaim, Name: \"admin\", Description: \"Administers the API\", Category: \"administration\"},\n")
+	assert.Contains(t, string(generated), "\t{Claim: ReadOnlyClaim, Name: \"read_only\", Description: \"Reads the users without changing them\", Category: \"users\", Deprecated: true},\n")
+}
+
+// TestGenerateGo_InvalidPackage checks that GenerateGo returns an error when the package name is not an identifier
+func TestGenerateGo_InvalidPackage(t *testing.T) {
+	// Act
+	_, err := GenerateGo(nil, "user-claims", "claims.yaml")
+
+	// Assert
+	assert.Equal(t, "package name user-claims not valid", err.Error())
+}
+
+// TestTagModels_Ok checks that TagModels sets the enum values of the claims fields, replacing the ones already set, and only of those
+func TestTagModels_Ok(t *testing.T) {
+	// Arrange
+	claims, err := Parse([]byte(testClaims))
+	assert.Nil(t, err)
+	src := "type Req struct {\n\tClaims []int64 `json:\"claims\"`\n\tOther *[]int64 `json:\"claims\" enums:\"0\"`\n\tSupported []string `json:\"claims_supported\"`\n}\n"
+	expectedSrc := "type Req struct {\n\tClaims []int64 `json:\"claims\" enums:\"0,1\"`\n\tOther *[]int64 `json:\"claims\" enums:\"0,1\"`\n\tSupported []string `json:\"claims_supported\"`\n}\n"
+
+	// Act
+	tagged, changed := TagModels([]byte(src), claims)
+
+	// Assert
+	assert.True(t, changed)
+	assert.Equal(t, expectedSrc, string(tagged))
+}
+
+// TestGenerate_InSync checks that the claims and the models committed are the ones generated from the definition of the claims,
+// failing when it changed without running go generate ./core/entities
+func TestGenerate_InSync(t *testing.T) {
+	// Arrange
+	source, err := os.ReadFile("../../core/entities/claims.yaml")
+	assert.Nil(t, err)
+	claims, err := Parse(source)
+	assert.Nil(t, err)
+	committed, err := os.ReadFile("../../core/entities/claims_gen.go")
+	assert.Nil(t, err)
+	models, err := filepath.Glob("../../core/models/*.go")
+	assert.Nil(t, err)
+
+	// Act
+	generated, err := GenerateGo(claims, "entities", "claims.yaml")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, string(generated), string(committed))
+	for _, model := range models {
+		src, err := os.ReadFile(model)
+		assert.Nil(t, err)
+		_, changed := TagModels(src, claims)
+		assert.False(t, changed, model)
+	}
+}
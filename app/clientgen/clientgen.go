@@ -0,0 +1,385 @@
+// Package clientgen generates the TypeScript and Go clients of the API from its Swagger 2.0 document,
+// so the clients are regenerated mechanically whenever the handlers, and therefore the document, change.
+package clientgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// kinds of schemas
+const (
+	KindString  = "string"
+	KindInteger = "integer"
+	KindNumber  = "number"
+	KindBoolean = "boolean"
+	KindArray   = "array"
+	KindMap     = "map"
+	KindAny     = "any"
+	KindRef     = "ref"
+	KindFile    = "file"
+)
+
+// API the types and the operations of an API, as read from its document
+type API struct {
+	Types      []Type
+	Operations []Operation
+}
+
+// Type a definition of the document, an object with named fields
+type Type struct {
+	Name   string
+	Fields []Field
+}
+
+// Field of a type, named as in its JSON
+type Field struct {
+	Name   string
+	Schema Schema
+}
+
+// Schema of a value, whose Items are the ones of the arrays or the values of the maps, and whose Ref is the name of the type referenced
+type Schema struct {
+	Kind  string
+	Enum  []string
+	Ref   string
+	Items *Schema
+}
+
+// Param a path or query parameter of an operation
+type Param struct {
+	Name     string
+	Schema   Schema
+	Required bool
+}
+
+// Operation of the API, named after its summary, sending its body as JSON or uploading a file as a multipart form field named File,
+// succeeding with Status and the Result schema, nil when the response has no body, or failing with one of the Failures statuses
+type Operation struct {
+	Name        string
+	Description string
+	Method      string
+	Path        string
+	Secured     bool
+	PathParams  []Param
+	QueryParams []Param
+	Body        *Schema
+	File        string
+	Status      int
+	Result      *Schema
+	Failures    []int
+}
+
+// methods in the order their operations are generated for a path
+var methods = []string{"get", "post", "put", "patch", "delete"}
+
+// acronyms kept uppercased in the names
+var acronyms = map[string]string{
+	"api":  "API",
+	"http": "HTTP",
+	"id":   "ID",
+	"ids":  "IDs",
+	"ip":   "IP",
+	"json": "JSON",
+	"ms":   "MS",
+	"sms":  "SMS",
+	"uri":  "URI",
+	"url":  "URL",
+}
+
+// document the subset of a Swagger 2.0 document read
+type document struct {
+	Paths       map[string]map[string]operation `json:"paths"`
+	Definitions map[string]schema               `json:"definitions"`
+}
+
+type operation struct {
+	Summary     string                `json:"summary"`
+	Description string                `json:"description"`
+	Parameters  []parameter           `json:"parameters"`
+	Responses   map[string]response   `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+}
+
+type parameter struct {
+	In       string  `json:"in"`
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Required bool    `json:"required"`
+	Enum     enum    `json:"enum"`
+	Items    *schema `json:"items"`
+	Schema   *schema `json:"schema"`
+}
+
+// enum the values of an enum of the document, read as their text, whether strings or numbers, like the values of the claims
+type enum []string
+
+func (e *enum) UnmarshalJSON(b []byte) error {
+	var values []json.RawMessage
+	if err := json.Unmarshal(b, &values); err != nil {
+		return err
+	}
+	*e = make(enum, len(values))
+	for i, value := range values {
+		if err := json.Unmarshal(value, &(*e)[i]); err != nil {
+			(*e)[i] = string(value)
+		}
+	}
+	return nil
+}
+
+type response struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Type                 string            `json:"type"`
+	Ref                  string            `json:"$ref"`
+	Enum                 enum              `json:"enum"`
+	Items                *schema           `json:"items"`
+	Properties           map[string]schema `json:"properties"`
+	AdditionalProperties json.RawMessage   `json:"additionalProperties"`
+}
+
+// Parse reads the types and the operations of the Swagger 2.0 document, sorted so the clients generated from the same document are the same.
+// The operations with header parameters or form fields other than files are skipped, as they are the webhooks called by providers
+// signing their requests or the OAuth token endpoint called by the OIDC client libraries, rather than called by the clients.
+func Parse(doc []byte) (API, error) {
+	var d document
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return API{}, fmt.Errorf("document not valid: %w", err)
+	}
+
+	var api API
+	types := make(map[string]string, len(d.Definitions))
+	for definition, s := range d.Definitions {
+		name := refName(definition)
+		if other, ok := types[name]; ok {
+			return API{}, fmt.Errorf("definitions %s and %s have the same name %s", other, definition, name)
+		}
+		types[name] = definition
+
+		t := Type{Name: name}
+		for property, propertySchema := range s.Properties {
+			converted, err := convert(propertySchema)
+			if err != nil {
+				return API{}, fmt.Errorf("property %s of %s not valid: %w", property, definition, err)
+			}
+			t.Fields = append(t.Fields, Field{Name: property, Schema: converted})
+		}
+		sort.Slice(t.Fields, func(i, j int) bool { return t.Fields[i].Name < t.Fields[j].Name })
+		api.Types = append(api.Types, t)
+	}
+	sort.Slice(api.Types, func(i, j int) bool { return api.Types[i].Name < api.Types[j].Name })
+
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	names := make(map[string]string)
+	for _, path := range paths {
+		for _, method := range methods {
+			op, ok := d.Paths[path][method]
+			if !ok {
+				continue
+			}
+			endpoint := strings.ToUpper(method) + " " + path
+			parsed, skip, err := parseOperation(method, path, op)
+			if err != nil {
+				return API{}, fmt.Errorf("operation %s not valid: %w", endpoint, err)
+			}
+			if skip {
+				continue
+			}
+			if other, ok := names[parsed.Name]; ok {
+				return API{}, fmt.Errorf("operations %s and %s have the same name %s", other, endpoint, parsed.Name)
+			}
+			names[parsed.Name] = endpoint
+			api.Operations = append(api.Operations, parsed)
+		}
+	}
+	return api, nil
+}
+
+// parseOperation converts the operation, reporting whether it is skipped
+func parseOperation(method, path string, op operation) (Operation, bool, error) {
+	if op.Summary == "" {
+		return Operation{}, false, fmt.Errorf("summary missing")
+	}
+	if op.Description == "" {
+		op.Description = op.Summary
+	}
+	parsed := Operation{
+		Name:        GoName(op.Summary),
+		Description: op.Description,
+		Method:      strings.ToUpper(method),
+		Path:        path,
+		Secured:     len(op.Security) > 0,
+	}
+
+	for _, p := range op.Parameters {
+		if p.In == "header" || (p.In == "formData" && p.Type != "file") {
+			return Operation{}, true, nil
+		}
+	}
+
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path", "query":
+			s, err := convert(schema{Type: p.Type, Enum: p.Enum, Items: p.Items})
+			if err != nil {
+				return Operation{}, false, fmt.Errorf("parameter %s not valid: %w", p.Name, err)
+			}
+			param := Param{Name: p.Name, Schema: s, Required: p.Required || p.In == "path"}
+			if p.In == "path" {
+				parsed.PathParams = append(parsed.PathParams, param)
+			} else {
+				parsed.QueryParams = append(parsed.QueryParams, param)
+			}
+		case "body":
+			if p.Schema == nil {
+				return Operation{}, false, fmt.Errorf("schema of body %s missing", p.Name)
+			}
+			s, err := convert(*p.Schema)
+			if err != nil {
+				return Operation{}, false, fmt.Errorf("body %s not valid: %w", p.Name, err)
+			}
+			parsed.Body = &s
+		case "formData":
+			parsed.File = p.Name
+		default:
+			return Operation{}, false, fmt.Errorf("parameter %s in %s not supported", p.Name, p.In)
+		}
+	}
+
+	for code, resp := range op.Responses {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			continue
+		}
+		if status < 200 || status > 299 {
+			parsed.Failures = append(parsed.Failures, status)
+			continue
+		}
+		if parsed.Status != 0 && parsed.Status < status {
+			continue
+		}
+		parsed.Status = status
+		parsed.Result = nil
+		if resp.Schema != nil {
+			s, err := convert(*resp.Schema)
+			if err != nil {
+				return Operation{}, false, fmt.Errorf("response %s not valid: %w", code, err)
+			}
+			parsed.Result = &s
+		}
+	}
+	if parsed.Status == 0 {
+		return Operation{}, false, fmt.Errorf("successful response missing")
+	}
+	sort.Ints(parsed.Failures)
+	return parsed, false, nil
+}
+
+// convert converts the schema of the document
+func convert(s schema) (Schema, error) {
+	if s.Ref != "" {
+		return Schema{Kind: KindRef, Ref: refName(s.Ref)}, nil
+	}
+
+	switch s.Type {
+	case "string":
+		return Schema{Kind: KindString, Enum: s.Enum}, nil
+	case "integer":
+		return Schema{Kind: KindInteger}, nil
+	case "number":
+		return Schema{Kind: KindNumber}, nil
+	case "boolean":
+		return Schema{Kind: KindBoolean}, nil
+	case "file":
+		return Schema{Kind: KindFile}, nil
+	case "array":
+		items := Schema{Kind: KindAny}
+		if s.Items != nil {
+			var err error
+			if items, err = convert(*s.Items); err != nil {
+				return Schema{}, err
+			}
+		}
+		return Schema{Kind: KindArray, Items: &items}, nil
+	case "object":
+		values := Schema{Kind: KindAny}
+		additional := bytes.TrimSpace(s.AdditionalProperties)
+		if len(additional) > 0 && !bytes.Equal(additional, []byte("true")) && !bytes.Equal(additional, []byte("false")) {
+			var valuesSchema schema
+			if err := json.Unmarshal(additional, &valuesSchema); err != nil {
+				return Schema{}, err
+			}
+			var err error
+			if values, err = convert(valuesSchema); err != nil {
+				return Schema{}, err
+			}
+		}
+		return Schema{Kind: KindMap, Items: &values}, nil
+	case "":
+		return Schema{Kind: KindAny}, nil
+	default:
+		return Schema{}, fmt.Errorf("type %s not supported", s.Type)
+	}
+}
+
+// refName returns the name of the type of a definition or of a reference to it, without its package, like UserResp for #/definitions/models.UserResp
+func refName(ref string) string {
+	ref = ref[strings.LastIndex(ref, "/")+1:]
+	return ref[strings.LastIndex(ref, ".")+1:]
+}
+
+// GoName returns the exported Go name of the words, like GetUserByID for "Get user by ID" or UserID for user_id
+func GoName(words string) string {
+	var b strings.Builder
+	for _, word := range splitWords(words) {
+		if acronym, ok := acronyms[strings.ToLower(word)]; ok {
+			b.WriteString(acronym)
+			continue
+		}
+		runes := []rune(word)
+		b.WriteString(string(unicode.ToUpper(runes[0])) + string(runes[1:]))
+	}
+	return b.String()
+}
+
+// CamelName returns the camel cased name of the words, like getUserByID for "Get user by ID" or userID for user_id
+func CamelName(words string) string {
+	split := splitWords(words)
+	if len(split) == 0 {
+		return ""
+	}
+	return strings.ToLower(split[0]) + GoName(strings.Join(split[1:], " "))
+}
+
+// splitWords splits the words separated by anything but letters and digits
+func splitWords(words string) []string {
+	return strings.FieldsFunc(words, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// descriptionLines returns the lines of a description, the first one starting lowercased to follow the name of what it describes
+func descriptionLines(description string) []string {
+	lines := strings.Split(strings.TrimSpace(description), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	if first := splitWords(lines[0]); len(first) > 0 && strings.ToUpper(first[0]) != first[0] {
+		runes := []rune(lines[0])
+		lines[0] = string(unicode.ToLower(runes[0])) + string(runes[1:])
+	}
+	return lines
+}
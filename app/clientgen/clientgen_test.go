@@ -0,0 +1,237 @@
+package clientgen
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"testing"
+
+	_ "github.com/sergicanet9/go-hexagonal-api/app/docs"
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggo/swag"
+)
+
+// testDoc a document with an operation of every kind of parameter
+const testDoc = `{
+	"paths": {
+		"/v1/items/{id}": {
+			"get": {
+				"summary": "Get item by ID",
+				"description": "Gets an item by ID",
+				"parameters": [
+					{"in": "path", "name": "id", "type": "string", "required": true},
+					{"in": "query", "name": "kind", "type": "string", "enum": ["a", "b"]},
+					{"in": "query", "name": "min_size", "type": "integer", "required": true}
+				],
+				"responses": {
+					"200": {"schema": {"$ref": "#/definitions/models.ItemResp"}},
+					"400": {"schema": {"type": "object"}}
+				},
+				"security": [{"Bearer": []}]
+			},
+			"put": {
+				"summary": "Upload item file",
+				"parameters": [
+					{"in": "path", "name": "id", "type": "string", "required": true},
+					{"in": "formData", "name": "file", "type": "file", "required": true}
+				],
+				"responses": {"201": {}, "200": {}}
+			}
+		},
+		"/v1/items": {
+			"post": {
+				"summary": "Create items",
+				"parameters": [{"in": "body", "name": "items", "schema": {"type": "array", "items": {"$ref": "#/definitions/models.ItemReq"}}}],
+				"responses": {"201": {"schema": {"type": "array", "items": {"type": "string"}}}}
+			}
+		},
+		"/oauth/token": {
+			"post": {
+				"summary": "Create token",
+				"parameters": [{"in": "formData", "name": "grant_type", "type": "string", "required": true}],
+				"responses": {"200": {}}
+			}
+		},
+		"/v1/webhook": {
+			"post": {
+				"summary": "Receive event",
+				"parameters": [{"in": "header", "name": "X-Signature", "type": "string", "required": true}],
+				"responses": {"200": {}}
+			}
+		}
+	},
+	"definitions": {
+		"models.ItemReq": {"type": "object", "properties": {"name": {"type": "string"}}},
+		"models.ItemResp": {
+			"type": "object",
+			"properties": {
+				"tags": {"type": "object", "additionalProperties": {"type": "string"}},
+				"owner": {"$ref": "#/definitions/models.ItemReq"},
+				"ids": {"type": "array", "items": {"type": "integer"}},
+				"status": {"type": "string", "enum": ["on", "off"]}
+			}
+		}
+	}
+}`
+
+// TestParse_Ok checks that Parse returns the sorted types and operations of the document, skipping the webhooks and the token endpoint
+func TestParse_Ok(t *testing.T) {
+	// Act
+	api, err := Parse([]byte(testDoc))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, api.Types, 2)
+	assert.Equal(t, "ItemResp", api.Types[1].Name)
+	assert.Equal(t, []Field{
+		{Name: "ids", Schema: Schema{Kind: KindArray, Items: &Schema{Kind: KindInteger}}},
+		{Name: "owner", Schema: Schema{Kind: KindRef, Ref: "ItemReq"}},
+		{Name: "status", Schema: Schema{Kind: KindString, Enum: []string{"on", "off"}}},
+		{Name: "tags", Schema: Schema{Kind: KindMap, Items: &Schema{Kind: KindString}}},
+	}, api.Types[1].Fields)
+
+	assert.Len(t, api.Operations, 3)
+	assert.Equal(t, "CreateItems", api.Operations[0].Name)
+	assert.Equal(t, &Schema{Kind: KindArray, Items: &Schema{Kind: KindRef, Ref: "ItemReq"}}, api.Operations[0].Body)
+	get := api.Operations[1]
+	assert.Equal(t, "GetItemByID", get.Name)
+	assert.Equal(t, "GET", get.Method)
+	assert.True(t, get.Secured)
+	assert.Equal(t, []Param{{Name: "id", Schema: Schema{Kind: KindString}, Required: true}}, get.PathParams)
+	assert.Equal(t, []Param{
+		{Name: "kind", Schema: Schema{Kind: KindString, Enum: []string{"a", "b"}}},
+		{Name: "min_size", Schema: Schema{Kind: KindInteger}, Required: true},
+	}, get.QueryParams)
+	assert.Equal(t, 200, get.Status)
+	assert.Equal(t, &Schema{Kind: KindRef, Ref: "ItemResp"}, get.Result)
+	assert.Equal(t, []int{400}, get.Failures)
+	upload := api.Operations[2]
+	assert.Equal(t, "file", upload.File)
+	assert.Equal(t, "Upload item file", upload.Description)
+	assert.Equal(t, 200, upload.Status)
+	assert.Nil(t, upload.Result)
+}
+
+// TestParse_DuplicatedName checks that Parse returns an error when two operations have the same summary
+func TestParse_DuplicatedName(t *testing.T) {
+	// Arrange
+	doc := `{"paths": {
+		"/a": {"get": {"summary": "Get thing", "responses": {"200": {}}}},
+		"/b": {"get": {"summary": "Get thing", "responses": {"200": {}}}}
+	}}`
+
+	// Act
+	_, err := Parse([]byte(doc))
+
+	// Assert
+	assert.Equal(t, "operations GET /a and GET /b have the same name GetThing", err.Error())
+}
+
+// TestParse_UnsupportedType checks that Parse returns an error when a schema has a type not supported
+func TestParse_UnsupportedType(t *testing.T) {
+	// Arrange
+	doc := `{"definitions": {"models.Thing": {"type": "object", "properties": {"value": {"type": "tuple"}}}}}`
+
+	// Act
+	_, err := Parse([]byte(doc))
+
+	// Assert
+	assert.Equal(t, "property value of models.Thing not valid: type tuple not supported", err.Error())
+}
+
+// TestParse_IntegerEnum checks that Parse reads the schemas with enums of numbers, like the claims, as integers
+func TestParse_IntegerEnum(t *testing.T) {
+	// Arrange
+	doc := `{"definitions": {"models.Thing": {"type": "object", "properties": {"claims": {"type": "array", "items": {"type": "integer", "enum": [0, 1]}}}}}}`
+
+	// Act
+	api, err := Parse([]byte(doc))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []Type{{Name: "Thing", Fields: []Field{{Name: "claims", Schema: Schema{Kind: KindArray, Items: &Schema{Kind: KindInteger}}}}}}, api.Types)
+}
+
+// TestGoName_Acronyms checks that GoName and CamelName keep the acronyms uppercased
+func TestGoName_Acronyms(t *testing.T) {
+	assert.Equal(t, "GetUserAvatarURL", GoName("Get user avatar URL"))
+	assert.Equal(t, "InsertedIDs", GoName("inserted_ids"))
+	assert.Equal(t, "userID", CamelName("user_id"))
+	assert.Equal(t, "sendSMSCode", lowerName("SendSMSCode"))
+	assert.Equal(t, "smsCode", lowerName("SMSCode"))
+}
+
+// TestGenerateGo_Ok checks that GenerateGo generates a valid Go file with a method per operation
+func TestGenerateGo_Ok(t *testing.T) {
+	// Arrange
+	api, err := Parse([]byte(testDoc))
+	assert.Nil(t, err)
+
+	// Act
+	client, err := GenerateGo(api, "items")
+
+	// Assert
+	assert.Nil(t, err)
+	file, err := parser.ParseFile(token.NewFileSet(), "items.go", client, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "items", file.Name.Name)
+	assert.Contains(t, string(client), "func (c *Client) GetItemByID(ctx context.Context, id string, params GetItemByIDParams) (ItemResp, error) {")
+	assert.Contains(t, string(client), `query.Set("min_size", strconv.FormatInt(params.MinSize, 10))`)
+	assert.Contains(t, string(client), "\tKind    *string\n")
+	assert.Contains(t, string(client), "func (c *Client) CreateItems(ctx context.Context, body []ItemReq) ([]string, error) {")
+	assert.Contains(t, string(client), "func (c *Client) UploadItemFile(ctx context.Context, id string, file io.Reader, filename, contentType string) error {")
+	assert.Contains(t, string(client), "Owner *ItemReq `json:\"owner,omitempty\"`")
+	assert.NotContains(t, string(client), "ReceiveEvent")
+}
+
+// TestGenerateGo_InvalidPackage checks that GenerateGo returns an error when the package name is not an identifier
+func TestGenerateGo_InvalidPackage(t *testing.T) {
+	// Act
+	_, err := GenerateGo(API{}, "api-client")
+
+	// Assert
+	assert.Equal(t, "package name api-client not valid", err.Error())
+}
+
+// TestGenerateTypeScript_Ok checks that GenerateTypeScript generates an interface per type and a method per operation
+func TestGenerateTypeScript_Ok(t *testing.T) {
+	// Arrange
+	api, err := Parse([]byte(testDoc))
+	assert.Nil(t, err)
+
+	// Act
+	client, err := GenerateTypeScript(api)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Contains(t, string(client), "export interface ItemResp {\n  ids?: number[];\n  owner?: ItemReq;\n  status?: \"on\" | \"off\";\n  tags?: Record<string, string>;\n}")
+	assert.Contains(t, string(client), "export interface GetItemByIDParams {\n  kind?: \"a\" | \"b\";\n  min_size: number;\n}")
+	assert.Contains(t, string(client), "async getItemByID(id: string, params: GetItemByIDParams): Promise<ItemResp> {")
+	assert.Contains(t, string(client), "path: `/v1/items/${encodeURIComponent(id)}`, query: { kind: params.kind, min_size: params.min_size }, status: 200, secured: true")
+	assert.Contains(t, string(client), "async uploadItemFile(id: string, file: Blob, filename: string): Promise<void> {")
+	assert.NotContains(t, string(client), "receiveEvent")
+}
+
+// TestGenerate_InSync checks that the clients committed are the ones generated from the current OpenAPI document,
+// failing when the handlers changed without running make clients
+func TestGenerate_InSync(t *testing.T) {
+	// Arrange
+	doc, err := swag.ReadDoc()
+	assert.Nil(t, err)
+	api, err := Parse([]byte(doc))
+	assert.Nil(t, err)
+	committedGo, err := os.ReadFile("../../clients/apiclient/apiclient.go")
+	assert.Nil(t, err)
+	committedTS, err := os.ReadFile("../../clients/typescript/apiclient.ts")
+	assert.Nil(t, err)
+
+	// Act
+	generatedGo, goErr := GenerateGo(api, "apiclient")
+	generatedTS, tsErr := GenerateTypeScript(api)
+
+	// Assert
+	assert.Nil(t, goErr)
+	assert.Nil(t, tsErr)
+	assert.Equal(t, string(generatedGo), string(committedGo))
+	assert.Equal(t, string(generatedTS), string(committedTS))
+}
@@ -0,0 +1,404 @@
+package clientgen
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// goStatuses names of the statuses in net/http
+var goStatuses = map[int]string{
+	200: "http.StatusOK",
+	201: "http.StatusCreated",
+	202: "http.StatusAccepted",
+	204: "http.StatusNoContent",
+}
+
+// goReserved names of the generated methods that the arguments cannot take
+var goReserved = map[string]bool{
+	"body": true, "c": true, "contentType": true, "ctx": true, "err": true, "file": true, "filename": true,
+	"http": true, "params": true, "query": true, "result": true, "resp": true, "url": true,
+}
+
+// goRuntime the client the generated methods send their requests with
+const goRuntime = `
+// Client of the API. The Token is sent as a bearer token with the requests requiring it, so it is set before them,
+// like with the token returned by the login, and the client is not safe for concurrent use while it is changed.
+type Client struct {
+	// BaseURL of the API, like https://api.example.com
+	BaseURL string
+	// HTTPClient sending the requests, http.DefaultClient when not set
+	HTTPClient *http.Client
+	Token      string
+}
+
+// Error is returned when the API responds with a status other than the expected one, with the message of its error when it has one
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("api responded with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("api responded with status %d: %s", e.StatusCode, e.Message)
+}
+
+// request is a request to the API, sending either a JSON body or a file as a multipart form
+type request struct {
+	method  string
+	path    string
+	query   url.Values
+	body    interface{}
+	file    *formFile
+	status  int
+	secured bool
+}
+
+// formFile is a file uploaded in a multipart form field
+type formFile struct {
+	field       string
+	name        string
+	contentType string
+	content     io.Reader
+}
+
+// do sends the request, decoding the JSON body of the response into out when set
+func (c *Client) do(ctx context.Context, req request, out interface{}) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// send sends the request, returning the response with the expected status, whose body must be closed, or an error otherwise
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	var body io.Reader
+	var contentType string
+	switch {
+	case req.file != nil:
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf("form-data; name=%q; filename=%q", req.file.field, req.file.name))
+		header.Set("Content-Type", req.file.contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(part, req.file.content); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		body, contentType = &buf, writer.FormDataContentType()
+	case req.body != nil:
+		encoded, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, err
+		}
+		body, contentType = bytes.NewReader(encoded), "application/json"
+	}
+
+	endpoint := strings.TrimSuffix(c.BaseURL, "/") + req.path
+	if len(req.query) > 0 {
+		endpoint += "?" + req.query.Encode()
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if req.secured && c.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == req.status {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	var errBody struct {
+		Error string ` + "`json:\"error\"`" + `
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&errBody); err == nil {
+		apiErr.Message = errBody.Error
+	}
+	return nil, apiErr
+}
+`
+
+// GenerateGo generates the Go client of the API, in the package named pkg, formatted by gofmt
+func GenerateGo(api API, pkg string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("package name %s not valid", pkg)
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by gen-client from the OpenAPI document of the API. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "// Package %s is the Go client of the API, generated from its OpenAPI document.\n", pkg)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+	imports := []string{"bytes", "context", "encoding/json", "fmt", "io", "mime/multipart", "net/http", "net/textproto", "net/url"}
+	if goUsesStrconv(api) {
+		imports = append(imports, "strconv")
+	}
+	imports = append(imports, "strings")
+	b.WriteString("import (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&b, "\t%q\n", imp)
+	}
+	b.WriteString(")\n")
+	b.WriteString(goRuntime)
+
+	for _, t := range api.Types {
+		fmt.Fprintf(&b, "\ntype %s struct {\n", t.Name)
+		for _, f := range t.Fields {
+			writeGoEnum(&b, GoName(f.Name), f.Schema)
+			fmt.Fprintf(&b, "\t%s %s `json:\"%s,omitempty\"`\n", GoName(f.Name), goFieldType(f.Schema), f.Name)
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, op := range api.Operations {
+		if len(op.QueryParams) > 0 {
+			fmt.Fprintf(&b, "\n// %sParams are the query parameters of %s, the optional ones being sent when set\n", op.Name, op.Name)
+			fmt.Fprintf(&b, "type %sParams struct {\n", op.Name)
+			for _, p := range op.QueryParams {
+				writeGoEnum(&b, GoName(p.Name), p.Schema)
+				typ := goType(p.Schema)
+				if !p.Required {
+					typ = "*" + typ
+				}
+				fmt.Fprintf(&b, "\t%s %s\n", GoName(p.Name), typ)
+			}
+			b.WriteString("}\n")
+		}
+		if err := writeGoOperation(&b, op); err != nil {
+			return nil, fmt.Errorf("operation %s not generated: %w", op.Name, err)
+		}
+	}
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated client not valid: %w", err)
+	}
+	return formatted, nil
+}
+
+// writeGoOperation writes the method of the client sending the operation
+func writeGoOperation(b *strings.Builder, op Operation) error {
+	if op.File != "" && op.Body != nil {
+		return fmt.Errorf("both a body and a file sent")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, p := range op.PathParams {
+		args = append(args, goArg(p.Name)+" "+goType(p.Schema))
+	}
+	if op.Body != nil {
+		args = append(args, "body "+goType(*op.Body))
+	}
+	if op.File != "" {
+		args = append(args, "file io.Reader", "filename, contentType string")
+	}
+	if len(op.QueryParams) > 0 {
+		args = append(args, "params "+op.Name+"Params")
+	}
+
+	download := op.Result != nil && op.Result.Kind == KindFile
+	var result string
+	switch {
+	case download:
+		result = "io.ReadCloser"
+	case op.Result != nil:
+		result = goType(*op.Result)
+	}
+
+	b.WriteString("\n")
+	for i, line := range descriptionLines(op.Description) {
+		if i == 0 {
+			line = op.Name + " " + line
+		}
+		fmt.Fprintf(b, "// %s\n", line)
+	}
+	if download {
+		b.WriteString("// The content returned must be closed.\n")
+	}
+	if result == "" {
+		fmt.Fprintf(b, "func (c *Client) %s(%s) error {\n", op.Name, strings.Join(args, ", "))
+	} else {
+		fmt.Fprintf(b, "func (c *Client) %s(%s) (%s, error) {\n", op.Name, strings.Join(args, ", "), result)
+	}
+
+	fields := []string{"method: http.Method" + methodName(op.Method), "path: " + goPath(op)}
+	if len(op.QueryParams) > 0 {
+		b.WriteString("\tquery := url.Values{}\n")
+		for _, p := range op.QueryParams {
+			value := "params." + GoName(p.Name)
+			if p.Required {
+				fmt.Fprintf(b, "\tquery.Set(%q, %s)\n", p.Name, goFormat(p.Schema, value))
+				continue
+			}
+			fmt.Fprintf(b, "\tif %s != nil {\n\t\tquery.Set(%q, %s)\n\t}\n", value, p.Name, goFormat(p.Schema, "*"+value))
+		}
+		fields = append(fields, "query: query")
+	}
+	if op.Body != nil {
+		fields = append(fields, "body: body")
+	}
+	if op.File != "" {
+		fields = append(fields, fmt.Sprintf("file: &formFile{field: %q, name: filename, contentType: contentType, content: file}", op.File))
+	}
+	fields = append(fields, "status: "+goStatus(op.Status))
+	if op.Secured {
+		fields = append(fields, "secured: true")
+	}
+	req := "request{" + strings.Join(fields, ", ") + "}"
+
+	switch {
+	case result == "":
+		fmt.Fprintf(b, "\treturn c.do(ctx, %s, nil)\n", req)
+	case download:
+		fmt.Fprintf(b, "\tresp, err := c.send(ctx, %s)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\treturn resp.Body, nil\n", req)
+	default:
+		fmt.Fprintf(b, "\tvar result %s\n\terr := c.do(ctx, %s, &result)\n\treturn result, err\n", result, req)
+	}
+	b.WriteString("}\n")
+	return nil
+}
+
+// writeGoEnum writes the values a field or parameter of the schema takes, when it is an enum
+func writeGoEnum(b *strings.Builder, name string, s Schema) {
+	if len(s.Enum) > 0 {
+		fmt.Fprintf(b, "\t// %s is one of %s\n", name, strings.Join(s.Enum, ", "))
+	}
+}
+
+// goType returns the Go type of the schema
+func goType(s Schema) string {
+	switch s.Kind {
+	case KindString:
+		return "string"
+	case KindInteger:
+		return "int64"
+	case KindNumber:
+		return "float64"
+	case KindBoolean:
+		return "bool"
+	case KindArray:
+		return "[]" + goType(*s.Items)
+	case KindMap:
+		return "map[string]" + goType(*s.Items)
+	case KindRef:
+		return s.Ref
+	case KindFile:
+		return "[]byte"
+	default:
+		return "interface{}"
+	}
+}
+
+// goFieldType returns the Go type of a field of the schema, the types referenced being pointers so they are omitted when not set
+func goFieldType(s Schema) string {
+	if s.Kind == KindRef {
+		return "*" + s.Ref
+	}
+	return goType(s)
+}
+
+// goFormat returns the expression formatting the value of the schema as a query parameter
+func goFormat(s Schema, value string) string {
+	switch s.Kind {
+	case KindInteger:
+		return "strconv.FormatInt(" + value + ", 10)"
+	case KindNumber:
+		return "strconv.FormatFloat(" + value + ", 'f', -1, 64)"
+	case KindBoolean:
+		return "strconv.FormatBool(" + value + ")"
+	default:
+		return value
+	}
+}
+
+// goUsesStrconv reports whether a query parameter is formatted with strconv
+func goUsesStrconv(api API) bool {
+	for _, op := range api.Operations {
+		for _, p := range op.QueryParams {
+			if p.Schema.Kind == KindInteger || p.Schema.Kind == KindNumber || p.Schema.Kind == KindBoolean {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goPath returns the expression of the path of the operation, with its path parameters escaped
+func goPath(op Operation) string {
+	var parts []string
+	literal := ""
+	path := op.Path
+	for path != "" {
+		start := strings.Index(path, "{")
+		end := strings.Index(path, "}")
+		if start < 0 || end < start {
+			literal += path
+			break
+		}
+		literal += path[:start]
+		if literal != "" {
+			parts = append(parts, strconv.Quote(literal))
+			literal = ""
+		}
+		parts = append(parts, "url.PathEscape("+goArg(path[start+1:end])+")")
+		path = path[end+1:]
+	}
+	if literal != "" {
+		parts = append(parts, strconv.Quote(literal))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// goArg returns the name of the argument of a path parameter
+func goArg(name string) string {
+	arg := CamelName(name)
+	if goReserved[arg] || token.IsKeyword(arg) {
+		arg += "Param"
+	}
+	return arg
+}
+
+// goStatus returns the expression of the status
+func goStatus(status int) string {
+	if name, ok := goStatuses[status]; ok {
+		return name
+	}
+	return strconv.Itoa(status)
+}
+
+// methodName returns the name of the method as in the constants of net/http, like Get for GET
+func methodName(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
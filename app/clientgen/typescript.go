@@ -0,0 +1,287 @@
+package clientgen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tsIdentifier matches the property names not needing quotes
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsRuntime the client the generated methods send their requests with
+const tsRuntime = `
+/** ApiError is thrown when the API responds with a status other than the expected one, with the message of its error when it has one */
+export class ApiError extends Error {
+  constructor(readonly status: number, readonly apiMessage: string) {
+    super(apiMessage ? ` + "`api responded with status ${status}: ${apiMessage}`" + ` : ` + "`api responded with status ${status}`" + `);
+    this.name = "ApiError";
+  }
+}
+
+/** Request to the API, sending either a JSON body or a multipart form */
+interface Request {
+  method: string;
+  path: string;
+  query?: Record<string, string | number | boolean | undefined>;
+  body?: unknown;
+  form?: FormData;
+  status: number;
+  secured: boolean;
+}
+
+/** Client of the API, sending its token as a bearer token with the requests requiring it */
+export class Client {
+  /**
+   * @param baseURL of the API, like https://api.example.com
+   * @param token set before the requests requiring it, like with the token returned by the login
+   * @param fetchFn sending the requests, the global fetch by default
+   */
+  constructor(
+    readonly baseURL: string,
+    public token = "",
+    private readonly fetchFn: typeof fetch = (input, init) => fetch(input, init),
+  ) {}
+`
+
+const tsSend = `
+  /** send sends the request, returning the response with the expected status, or throwing an ApiError otherwise */
+  private async send(req: Request): Promise<Response> {
+    let url = this.baseURL.replace(/\/$/, "") + req.path;
+    const query = new URLSearchParams();
+    for (const [key, value] of Object.entries(req.query ?? {})) {
+      if (value !== undefined) {
+        query.set(key, String(value));
+      }
+    }
+    if (query.toString() !== "") {
+      url += "?" + query.toString();
+    }
+
+    const headers: Record<string, string> = { Accept: "application/json" };
+    let body: BodyInit | undefined;
+    if (req.form !== undefined) {
+      body = req.form;
+    } else if (req.body !== undefined) {
+      body = JSON.stringify(req.body);
+      headers["Content-Type"] = "application/json";
+    }
+    if (req.secured && this.token !== "") {
+      headers.Authorization = ` + "`Bearer ${this.token}`" + `;
+    }
+
+    const resp = await this.fetchFn(url, { method: req.method, headers, body });
+    if (resp.status === req.status) {
+      return resp;
+    }
+    let message = "";
+    try {
+      message = ((await resp.json()) as { error?: string }).error ?? "";
+    } catch {
+      // the body is not an error of the API
+    }
+    throw new ApiError(resp.status, message);
+  }
+}
+`
+
+// GenerateTypeScript generates the TypeScript client of the API, using the fetch API
+func GenerateTypeScript(api API) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("// Code generated by gen-client from the OpenAPI document of the API. DO NOT EDIT.\n")
+
+	for _, t := range api.Types {
+		fmt.Fprintf(&b, "\nexport interface %s {\n", t.Name)
+		for _, f := range t.Fields {
+			fmt.Fprintf(&b, "  %s?: %s;\n", tsKey(f.Name), tsType(f.Schema))
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, op := range api.Operations {
+		if len(op.QueryParams) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n/** Query parameters of %s, the optional ones being sent when set */\n", lowerName(op.Name))
+		fmt.Fprintf(&b, "export interface %sParams {\n", op.Name)
+		for _, p := range op.QueryParams {
+			optional := "?"
+			if p.Required {
+				optional = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", tsKey(p.Name), optional, tsType(p.Schema))
+		}
+		b.WriteString("}\n")
+	}
+
+	b.WriteString(tsRuntime)
+	for _, op := range api.Operations {
+		if op.File != "" && op.Body != nil {
+			return nil, fmt.Errorf("operation %s not generated: both a body and a file sent", op.Name)
+		}
+		writeTSOperation(&b, op)
+	}
+	b.WriteString(tsSend)
+	return []byte(b.String()), nil
+}
+
+// writeTSOperation writes the method of the client sending the operation
+func writeTSOperation(b *strings.Builder, op Operation) {
+	var args []string
+	for _, p := range op.PathParams {
+		args = append(args, CamelName(p.Name)+": "+tsType(p.Schema))
+	}
+	if op.Body != nil {
+		args = append(args, "body: "+tsType(*op.Body))
+	}
+	if op.File != "" {
+		args = append(args, "file: Blob", "filename: string")
+	}
+	if len(op.QueryParams) > 0 {
+		params := "params: " + op.Name + "Params"
+		if !hasRequired(op.QueryParams) {
+			params += " = {}"
+		}
+		args = append(args, params)
+	}
+
+	result := "void"
+	if op.Result != nil {
+		result = tsType(*op.Result)
+	}
+
+	b.WriteString("\n")
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(op.Description, "*/", "* /")), "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "  /** %s */\n", strings.TrimSpace(lines[0]))
+	} else {
+		b.WriteString("  /**\n")
+		for _, line := range lines {
+			fmt.Fprintf(b, "   * %s\n", strings.TrimSpace(line))
+		}
+		b.WriteString("   */\n")
+	}
+	fmt.Fprintf(b, "  async %s(%s): Promise<%s> {\n", lowerName(op.Name), strings.Join(args, ", "), result)
+
+	fields := []string{"method: " + strconv.Quote(op.Method), "path: " + tsPath(op.Path)}
+	if len(op.QueryParams) > 0 {
+		var query []string
+		for _, p := range op.QueryParams {
+			query = append(query, tsKey(p.Name)+": params"+tsAccess(p.Name))
+		}
+		fields = append(fields, "query: { "+strings.Join(query, ", ")+" }")
+	}
+	if op.Body != nil {
+		fields = append(fields, "body")
+	}
+	if op.File != "" {
+		b.WriteString("    const form = new FormData();\n")
+		fmt.Fprintf(b, "    form.append(%s, file, filename);\n", strconv.Quote(op.File))
+		fields = append(fields, "form")
+	}
+	fields = append(fields, "status: "+strconv.Itoa(op.Status), "secured: "+strconv.FormatBool(op.Secured))
+	req := "{ " + strings.Join(fields, ", ") + " }"
+
+	switch {
+	case op.Result == nil:
+		fmt.Fprintf(b, "    await this.send(%s);\n", req)
+	case op.Result.Kind == KindFile:
+		fmt.Fprintf(b, "    const resp = await this.send(%s);\n    return resp.blob();\n", req)
+	default:
+		fmt.Fprintf(b, "    const resp = await this.send(%s);\n    return (await resp.json()) as %s;\n", req, result)
+	}
+	b.WriteString("  }\n")
+}
+
+// tsType returns the TypeScript type of the schema, the enums being unions of their values
+func tsType(s Schema) string {
+	switch s.Kind {
+	case KindString:
+		if len(s.Enum) == 0 {
+			return "string"
+		}
+		values := make([]string, len(s.Enum))
+		for i, value := range s.Enum {
+			values[i] = strconv.Quote(value)
+		}
+		return strings.Join(values, " | ")
+	case KindInteger, KindNumber:
+		return "number"
+	case KindBoolean:
+		return "boolean"
+	case KindArray:
+		items := tsType(*s.Items)
+		if strings.Contains(items, " ") {
+			return "(" + items + ")[]"
+		}
+		return items + "[]"
+	case KindMap:
+		return "Record<string, " + tsType(*s.Items) + ">"
+	case KindRef:
+		return s.Ref
+	case KindFile:
+		return "Blob"
+	default:
+		return "unknown"
+	}
+}
+
+// tsPath returns the expression of the path, with its path parameters encoded
+func tsPath(path string) string {
+	var b strings.Builder
+	b.WriteString("`")
+	for path != "" {
+		start := strings.Index(path, "{")
+		end := strings.Index(path, "}")
+		if start < 0 || end < start {
+			b.WriteString(path)
+			break
+		}
+		b.WriteString(path[:start])
+		b.WriteString("${encodeURIComponent(" + CamelName(path[start+1:end]) + ")}")
+		path = path[end+1:]
+	}
+	b.WriteString("`")
+	return b.String()
+}
+
+// tsKey returns the property name, quoted when it is not an identifier
+func tsKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// tsAccess returns the accessor of the property name, like .name or ["Stripe-Signature"]
+func tsAccess(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return "." + name
+	}
+	return "[" + strconv.Quote(name) + "]"
+}
+
+// hasRequired reports whether a parameter is required
+func hasRequired(params []Param) bool {
+	for _, p := range params {
+		if p.Required {
+			return true
+		}
+	}
+	return false
+}
+
+// lowerName returns the Go name starting lowercased, like getUserByID for GetUserByID or smsCode for SMSCode
+func lowerName(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) {
+		upper--
+	}
+	return strings.ToLower(string(runes[:upper])) + string(runes[upper:])
+}
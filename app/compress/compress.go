@@ -0,0 +1,103 @@
+package compress
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Middleware compresses with gzip, at the level, the responses to the requests accepting it, unless already encoded,
+// like the files served as uploaded, or without a body
+func Middleware(level int) func(http.Handler) http.Handler {
+	writers := sync.Pool{New: func() interface{} {
+		// the level is validated with the config
+		zw, _ := gzip.NewWriterLevel(nil, level)
+		return zw
+	}}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !acceptsGzip(r.Header.Get("Accept-Encoding")) || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, pool: &writers}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// compressWriter compresses the body written unless the response is found not to be compressed once its header is written
+type compressWriter struct {
+	http.ResponseWriter
+	pool        *sync.Pool
+	zw          *gzip.Writer
+	wroteHeader bool
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	h := w.Header()
+	if h.Get("Content-Encoding") == "" && status != http.StatusNoContent && status != http.StatusNotModified && status >= http.StatusOK {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		w.zw = w.pool.Get().(*gzip.Writer)
+		w.zw.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.zw == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.zw.Write(b)
+}
+
+// Flush flushes the compressed body written so far, so the streamed responses are still streamed
+func (w *compressWriter) Flush() {
+	if w.zw != nil {
+		w.zw.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the writer wrapped, for the http.ResponseController
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) close() {
+	if w.zw == nil {
+		return
+	}
+	w.zw.Close()
+	w.pool.Put(w.zw)
+	w.zw = nil
+}
+
+// acceptsGzip returns whether the Accept-Encoding header accepts gzip, not being rejected with a 0 quality
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.TrimSpace(coding) != "gzip" {
+			continue
+		}
+		q := strings.ReplaceAll(params, " ", "")
+		return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// body the body of the responses of bodyHandler
+var body = strings.Repeat("test ", 100)
+
+// bodyHandler responds with body
+var bodyHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	io.WriteString(w, body)
+})
+
+// TestMiddleware_Compressed checks that Middleware compresses the responses to the requests accepting gzip
+func TestMiddleware_Compressed(t *testing.T) {
+	// Arrange
+	handler := Middleware(gzip.BestSpeed)(bodyHandler)
+	r := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	r.Header.Set("Accept-Encoding", "br, gzip;q=0.8")
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, r)
+
+	// Assert
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+	zr, err := gzip.NewReader(rr.Body)
+	assert.Nil(t, err)
+	b, err := io.ReadAll(zr)
+	assert.Nil(t, err)
+	assert.Equal(t, body, string(b))
+}
+
+// TestMiddleware_NotAccepted checks that Middleware does not compress the responses to the requests not accepting gzip
+func TestMiddleware_NotAccepted(t *testing.T) {
+	// Arrange
+	handler := Middleware(gzip.BestSpeed)(bodyHandler)
+	r := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	r.Header.Set("Accept-Encoding", "gzip;q=0")
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, r)
+
+	// Assert
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, rr.Body.String())
+}
+
+// TestMiddleware_AlreadyEncoded checks that Middleware does not compress the responses already encoded
+func TestMiddleware_AlreadyEncoded(t *testing.T) {
+	// Arrange
+	handler := Middleware(gzip.BestSpeed)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		io.WriteString(w, "test")
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, r)
+
+	// Assert
+	assert.Equal(t, "br", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "test", rr.Body.String())
+}
@@ -0,0 +1,82 @@
+package cors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Origins the origins allowed, "*" allowing every one, which can be changed while the requests are served
+type Origins struct {
+	allowed atomic.Pointer[map[string]bool]
+}
+
+// NewOrigins creates the origins allowed
+func NewOrigins(origins []string) *Origins {
+	o := &Origins{}
+	o.Set(origins)
+	return o
+}
+
+// Set changes the origins allowed
+func (o *Origins) Set(origins []string) {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+	o.allowed.Store(&allowed)
+}
+
+// Middleware allows the browsers to call the API from the origins allowed, "*" allowing every one, setting the CORS headers
+// of the responses to their requests and answering their preflight requests with a 204, without running the handler.
+// The requests from other origins are served without the headers, so the browsers do not expose the responses.
+func Middleware(origins *Origins, methods, headers []string, credentials bool, maxAge time.Duration) func(http.Handler) http.Handler {
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Origin")
+			allowed := *origins.allowed.Load()
+			if !allowed[origin] && !allowed["*"] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// the credentialed requests are never allowed from the wildcard
+			if allowed["*"] && !credentials {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if credentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+			if allowHeaders != "" {
+				w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+			} else if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				w.Header().Set("Access-Control-Allow-Headers", requested)
+			}
+			if maxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
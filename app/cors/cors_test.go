@@ -0,0 +1,91 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// okHandler responds with a 200
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+// TestMiddleware_Preflight checks that Middleware answers the preflight requests from an allowed origin with a 204 and the allowed methods and headers
+func TestMiddleware_Preflight(t *testing.T) {
+	// Arrange
+	handler := Middleware(NewOrigins([]string{"https://app.test.com"}), []string{"GET", "POST"}, []string{"Authorization"}, true, 10*time.Minute)(okHandler)
+	r := httptest.NewRequest(http.MethodOptions, "/v1/users", nil)
+	r.Header.Set("Origin", "https://app.test.com")
+	r.Header.Set("Access-Control-Request-Method", "POST")
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, r)
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "https://app.test.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rr.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "GET, POST", rr.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization", rr.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", rr.Header().Get("Access-Control-Max-Age"))
+}
+
+// TestMiddleware_Wildcard checks that Middleware allows every origin with the wildcard, serving the request
+func TestMiddleware_Wildcard(t *testing.T) {
+	// Arrange
+	handler := Middleware(NewOrigins([]string{"*"}), []string{"GET"}, nil, false, 0)(okHandler)
+	r := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	r.Header.Set("Origin", "https://other.test.com")
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, r)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+}
+
+// TestMiddleware_OriginNotAllowed checks that Middleware serves the requests from other origins without the CORS headers
+func TestMiddleware_OriginNotAllowed(t *testing.T) {
+	// Arrange
+	handler := Middleware(NewOrigins([]string{"https://app.test.com"}), []string{"GET"}, nil, false, 0)(okHandler)
+	r := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	r.Header.Set("Origin", "https://evil.test.com")
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, r)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", rr.Header().Get("Vary"))
+}
+
+// TestOrigins_Set checks that Middleware allows the origins set while it serves the requests, and no longer the ones replaced
+func TestOrigins_Set(t *testing.T) {
+	// Arrange
+	origins := NewOrigins([]string{"https://app.test.com"})
+	handler := Middleware(origins, []string{"GET"}, nil, false, 0)(okHandler)
+	oldOrigin := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	oldOrigin.Header.Set("Origin", "https://app.test.com")
+	newOrigin := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	newOrigin.Header.Set("Origin", "https://new.test.com")
+	oldRR := httptest.NewRecorder()
+	newRR := httptest.NewRecorder()
+
+	// Act
+	origins.Set([]string{"https://new.test.com"})
+	handler.ServeHTTP(oldRR, oldOrigin)
+	handler.ServeHTTP(newRR, newOrigin)
+
+	// Assert
+	assert.Empty(t, oldRR.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "https://new.test.com", newRR.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,92 @@
+// Package domain registers the business domains of the API, each one with its routes, its migrations and its health checks,
+// so that adding a domain beside the users is registering it, with the server wiring left as it is.
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// HealthCheck a health check of a domain, reported under its name
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Checker  ports.HealthChecker
+}
+
+// Domain a business domain of the API. Routes sets its routes on the router, serving the requests with the context;
+// Migrate, when set, migrates its data before the API is run; HealthChecks are registered in the health service.
+type Domain struct {
+	Name         string
+	Routes       func(ctx context.Context, router *mux.Router)
+	Migrate      func(ctx context.Context) error
+	HealthChecks []HealthCheck
+}
+
+// Registry the domains of the API, in the order they were registered
+type Registry struct {
+	domains []Domain
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register registers the domain, failing without a name or with the name of a domain already registered
+func (r *Registry) Register(d Domain) error {
+	if d.Name == "" {
+		return errors.New("domain without a name")
+	}
+	for _, registered := range r.domains {
+		if registered.Name == d.Name {
+			return fmt.Errorf("domain %q already registered", d.Name)
+		}
+	}
+	r.domains = append(r.domains, d)
+	return nil
+}
+
+// Names returns the names of the domains, in the order they were registered
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.domains))
+	for i, d := range r.domains {
+		names[i] = d.Name
+	}
+	return names
+}
+
+// Migrate runs the migrations of the domains in the order they were registered, stopping at the first one failing
+func (r *Registry) Migrate(ctx context.Context) error {
+	for _, d := range r.domains {
+		if d.Migrate == nil {
+			continue
+		}
+		if err := d.Migrate(ctx); err != nil {
+			return fmt.Errorf("domain %s: %w", d.Name, err)
+		}
+	}
+	return nil
+}
+
+// RegisterHealthChecks registers the health checks of the domains in the health service
+func (r *Registry) RegisterHealthChecks(health ports.HealthService) {
+	for _, d := range r.domains {
+		for _, check := range d.HealthChecks {
+			health.Register(check.Name, check.Critical, check.Checker)
+		}
+	}
+}
+
+// Routes sets the routes of the domains on the router, serving the requests with the context
+func (r *Registry) Routes(ctx context.Context, router *mux.Router) {
+	for _, d := range r.domains {
+		if d.Routes != nil {
+			d.Routes(ctx, router)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegister_Duplicated checks that Register fails with the name of a domain already registered, keeping the first one
+func TestRegister_Duplicated(t *testing.T) {
+	// Arrange
+	registry := NewRegistry()
+	registry.Register(Domain{Name: "users"})
+
+	// Act
+	err := registry.Register(Domain{Name: "users"})
+
+	// Assert
+	assert.Equal(t, `domain "users" already registered`, err.Error())
+	assert.Equal(t, []string{"users"}, registry.Names())
+}
+
+// TestRegister_WithoutName checks that Register fails with a domain without a name
+func TestRegister_WithoutName(t *testing.T) {
+	// Arrange
+	registry := NewRegistry()
+
+	// Act
+	err := registry.Register(Domain{})
+
+	// Assert
+	assert.Equal(t, "domain without a name", err.Error())
+	assert.Empty(t, registry.Names())
+}
+
+// TestMigrate_Order checks that Migrate runs the migrations of the domains in the order they were registered, skipping the domains without any
+func TestMigrate_Order(t *testing.T) {
+	// Arrange
+	var migrated []string
+	registry := NewRegistry()
+	registry.Register(Domain{Name: "users", Migrate: func(ctx context.Context) error {
+		migrated = append(migrated, "users")
+		return nil
+	}})
+	registry.Register(Domain{Name: "jobs"})
+	registry.Register(Domain{Name: "devices", Migrate: func(ctx context.Context) error {
+		migrated = append(migrated, "devices")
+		return nil
+	}})
+
+	// Act
+	err := registry.Migrate(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"users", "devices"}, migrated)
+}
+
+// TestMigrate_Error checks that Migrate stops at the first migration failing, returning its error along with the name of its domain
+func TestMigrate_Error(t *testing.T) {
+	// Arrange
+	expectedErr := errors.New("migration failed")
+	var migrated []string
+	registry := NewRegistry()
+	registry.Register(Domain{Name: "users", Migrate: func(ctx context.Context) error {
+		return expectedErr
+	}})
+	registry.Register(Domain{Name: "devices", Migrate: func(ctx context.Context) error {
+		migrated = append(migrated, "devices")
+		return nil
+	}})
+
+	// Act
+	err := registry.Migrate(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Equal(t, "domain users: migration failed", err.Error())
+	assert.Empty(t, migrated)
+}
+
+// TestRegisterHealthChecks_Ok checks that RegisterHealthChecks registers the health checks of every domain in the health service
+func TestRegisterHealthChecks_Ok(t *testing.T) {
+	// Arrange
+	checker := mocks.NewHealthChecker(t)
+	health := mocks.NewHealthService(t)
+	health.On("Register", "storage", false, checker).Once()
+	health.On("Register", "index", true, checker).Once()
+	registry := NewRegistry()
+	registry.Register(Domain{Name: "users", HealthChecks: []HealthCheck{{Name: "storage", Checker: checker}}})
+	registry.Register(Domain{Name: "jobs"})
+	registry.Register(Domain{Name: "search", HealthChecks: []HealthCheck{{Name: "index", Critical: true, Checker: checker}}})
+
+	// Act
+	registry.RegisterHealthChecks(health)
+
+	// Assert
+	health.AssertExpectations(t)
+}
+
+// TestRoutes_Ok checks that Routes sets the routes of every domain on the router
+func TestRoutes_Ok(t *testing.T) {
+	// Arrange
+	registry := NewRegistry()
+	for _, name := range []string{"users", "jobs"} {
+		path := "/v1/" + name
+		registry.Register(Domain{Name: name, Routes: func(ctx context.Context, router *mux.Router) {
+			router.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+		}})
+	}
+	registry.Register(Domain{Name: "maintenance"})
+	router := mux.NewRouter()
+
+	// Act
+	registry.Routes(context.Background(), router)
+
+	// Assert
+	for _, path := range []string{"/v1/users", "/v1/jobs"} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, rr.Code, path)
+	}
+}
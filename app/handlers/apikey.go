@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetAPIKeyRoutes creates API key routes, the keys of a user being managed by the user itself and by the admins, never with an API key
+func SetAPIKeyRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.APIKeyService) {
+	r.Handle("/v1/api-keys", authenticate(getAPIKeys(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+	r.Handle("/v1/users/{id}/api-keys", authenticate(createAPIKey(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/users/{id}/api-keys", authenticate(getUserAPIKeys(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/users/{id}/api-keys/{key_id}/rotate", authenticate(rotateAPIKey(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/users/{id}/api-keys/{key_id}", authenticate(revokeAPIKey(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodDelete)
+}
+
+// @Summary Get API keys
+// @Description Gets the API keys of every user, without their secrets
+// @Tags API keys
+// @Security Bearer
+// @Success 200 {array} models.APIKeyResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/api-keys [get]
+func getAPIKeys(ctx context.Context, cfg config.Config, s ports.APIKeyService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		response, err := s.GetAll(ctx)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Create API key
+// @Description Creates an API key of a user, granting the claims of its scopes, responding with its secret, which cannot be read again
+// @Tags API keys
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param key body models.CreateAPIKeyReq true "API key"
+// @Success 201 {object} models.APIKeySecretResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/api-keys [post]
+func createAPIKey(ctx context.Context, cfg config.Config, s ports.APIKeyService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var req models.CreateAPIKeyReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		response, err := s.Create(ctx, caller(r), params["id"], req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, response)
+	})
+}
+
+// @Summary Get user API keys
+// @Description Gets the API keys of a user, newest first, without their secrets
+// @Tags API keys
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 {array} models.APIKeyResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/api-keys [get]
+func getUserAPIKeys(ctx context.Context, cfg config.Config, s ports.APIKeyService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.GetByUser(ctx, caller(r), params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Rotate API key
+// @Description Replaces the secret of an API key of a user, keeping its scopes and expiry, responding with the new secret, which cannot be read again
+// @Tags API keys
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param key_id path string true "API key ID"
+// @Success 200 {object} models.APIKeySecretResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/api-keys/{key_id}/rotate [post]
+func rotateAPIKey(ctx context.Context, cfg config.Config, s ports.APIKeyService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.Rotate(ctx, caller(r), params["id"], params["key_id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Revoke API key
+// @Description Revokes an API key of a user, so it no longer authenticates the requests
+// @Tags API keys
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param key_id path string true "API key ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/api-keys/{key_id} [delete]
+func revokeAPIKey(ctx context.Context, cfg config.Config, s ports.APIKeyService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Revoke(ctx, caller(r), params["id"], params["key_id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
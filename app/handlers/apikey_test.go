@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreateAPIKey_Ok checks that CreateAPIKey handler creates the API key of the user for its caller, responding with its secret
+func TestCreateAPIKey_Ok(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	keysMock := mocks.NewTokenKeys(t)
+	keysMock.On(testutils.FunctionName(t, ports.TokenKeys.Parse), mock.Anything, "test-token").Return(jwt.MapClaims{"user_id": "test-user"}, nil).Once()
+	apiKeyService := mocks.NewAPIKeyService(t)
+	apiKeyReq := models.CreateAPIKeyReq{Name: "test"}
+	apiKeyService.On(testutils.FunctionName(t, ports.APIKeyService.Create), mock.Anything, models.Caller{UserID: "test-user"}, "test-user", apiKeyReq).Return(models.APIKeySecretResp{Key: "hak_secret"}, nil).Once()
+
+	SetAPIKeyRoutes(context.Background(), config.Config{}, r, keysMock, apiKeyService)
+
+	rr := httptest.NewRecorder()
+	body, err := json.Marshal(apiKeyReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://testing/v1/users/test-user/api-keys"
+	req := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	req.Header.Add("Authorization", "Bearer test-token")
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusCreated, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+	var resp models.APIKeySecretResp
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "hak_secret", resp.Key; want != got {
+		t.Fatalf("unexpected key: want=%s but got=%s", want, got)
+	}
+}
+
+// TestGetAPIKeys_Unauthorized checks that GetAPIKeys handler returns an unauthorized when the token has no admin claim
+func TestGetAPIKeys_Unauthorized(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	keysMock := mocks.NewTokenKeys(t)
+	keysMock.On(testutils.FunctionName(t, ports.TokenKeys.Parse), mock.Anything, "test-token").Return(jwt.MapClaims{"user_id": "test-user"}, nil).Once()
+
+	SetAPIKeyRoutes(context.Background(), config.Config{}, r, keysMock, mocks.NewAPIKeyService(t))
+
+	rr := httptest.NewRecorder()
+	url := "http://testing/v1/api-keys"
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Add("Authorization", "Bearer test-token")
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusUnauthorized, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+}
+
+// TestRotateAPIKey_Ok checks that RotateAPIKey handler rotates the API key of the path, responding with its new secret
+func TestRotateAPIKey_Ok(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	keysMock := mocks.NewTokenKeys(t)
+	keysMock.On(testutils.FunctionName(t, ports.TokenKeys.Parse), mock.Anything, "test-token").Return(jwt.MapClaims{"user_id": "test-user"}, nil).Once()
+	apiKeyService := mocks.NewAPIKeyService(t)
+	apiKeyService.On(testutils.FunctionName(t, ports.APIKeyService.Rotate), mock.Anything, models.Caller{UserID: "test-user"}, "test-user", "test-key").Return(models.APIKeySecretResp{Key: "hak_rotated"}, nil).Once()
+
+	SetAPIKeyRoutes(context.Background(), config.Config{}, r, keysMock, apiKeyService)
+
+	rr := httptest.NewRecorder()
+	url := "http://testing/v1/users/test-user/api-keys/test-key/rotate"
+	req := httptest.NewRequest(http.MethodPost, url, nil)
+	req.Header.Add("Authorization", "Bearer test-token")
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+}
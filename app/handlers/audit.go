@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// defaultAuditEventsTake is the number of audit events returned when take is not specified
+const defaultAuditEventsTake = 100
+
+// SetAuditRoutes creates audit routes, for the admins and the compliance reviewers
+func SetAuditRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.AuditService) {
+	admin, compliance := jwt.MapClaims{"admin": true}, jwt.MapClaims{"compliance": true}
+	r.Handle("/v1/audit", authenticateAny(getAuditEvents(ctx, cfg, s), keys, admin, compliance)).Methods(http.MethodGet)
+	r.Handle("/v1/audit/export", authenticateAny(exportAuditEvents(ctx, cfg, s), keys, admin, compliance)).Methods(http.MethodGet)
+	r.Handle("/v1/audit/{id}", authenticateAny(getAuditEventByID(ctx, cfg, s), keys, admin, compliance)).Methods(http.MethodGet)
+}
+
+// @Summary Get audit events
+// @Description Gets the security relevant events, newest first
+// @Tags Audit
+// @Security Bearer
+// @Param type query string false "Event type"
+// @Param outcome query string false "Event outcome" Enums(success, failure)
+// @Param user_id query string false "User ID"
+// @Param actor_id query string false "ID of the user performing the action"
+// @Param from query string false "Minimum creation time, in RFC3339"
+// @Param to query string false "Maximum creation time, in RFC3339"
+// @Param skip query int false "Events to skip"
+// @Param take query int false "Events to return, 100 by default"
+// @Success 200 {array} models.AuditEventResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/audit [get]
+func getAuditEvents(ctx context.Context, cfg config.Config, s ports.AuditService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		req := models.GetAuditEventsReq{
+			Type:    r.URL.Query().Get("type"),
+			Outcome: r.URL.Query().Get("outcome"),
+			UserID:  r.URL.Query().Get("user_id"),
+			ActorID: r.URL.Query().Get("actor_id"),
+			Take:    defaultAuditEventsTake,
+		}
+		for param, value := range map[string]*int{"skip": &req.Skip, "take": &req.Take} {
+			if r.URL.Query().Get(param) == "" {
+				continue
+			}
+			v, err := strconv.Atoi(r.URL.Query().Get(param))
+			if err != nil {
+				responseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("%s must be an integer", param)))
+				return
+			}
+			*value = v
+		}
+		var err error
+		req.From, req.To, err = timeRange(r)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+
+		events, err := s.Get(ctx, req)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, events)
+	})
+}
+
+// @Summary Export audit events
+// @Description Exports every security relevant event matching the filters as a CSV file, newest first, for compliance reviews.
+// @Description The details of the events are exported as JSON objects, and the fields starting like a spreadsheet formula are prefixed with a quote.
+// @Tags Audit
+// @Security Bearer
+// @Produce text/csv
+// @Param type query string false "Event type"
+// @Param outcome query string false "Event outcome" Enums(success, failure)
+// @Param user_id query string false "User ID"
+// @Param actor_id query string false "ID of the user performing the action"
+// @Param from query string false "Minimum creation time, in RFC3339"
+// @Param to query string false "Maximum creation time, in RFC3339"
+// @Success 200 {file} file "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/audit/export [get]
+func exportAuditEvents(ctx context.Context, cfg config.Config, s ports.AuditService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		req := models.ExportAuditEventsReq{
+			Type:    r.URL.Query().Get("type"),
+			Outcome: r.URL.Query().Get("outcome"),
+			UserID:  r.URL.Query().Get("user_id"),
+			ActorID: r.URL.Query().Get("actor_id"),
+		}
+		var err error
+		req.From, req.To, err = timeRange(r)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+
+		csv := &csvResponse{w: w, filename: fmt.Sprintf("audit-%s.csv", time.Now().UTC().Format("20060102T150405Z"))}
+		err = s.Export(ctx, req, csv)
+		if err != nil && !csv.written {
+			responseError(w, r, nil, err)
+			return
+		}
+		if err != nil {
+			// the events already sent cannot be taken back, so the connection is aborted for the export not to look complete
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
+
+// @Summary Get audit event by ID
+// @Description Gets a security relevant event by ID
+// @Tags Audit
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 {object} models.AuditEventResp "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/audit/{id} [get]
+func getAuditEventByID(ctx context.Context, cfg config.Config, s ports.AuditService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		event, err := s.GetByID(ctx, mux.Vars(r)["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, event)
+	})
+}
+
+// timeRange returns the from and to query parameters of the request, in RFC3339, nil when not set
+func timeRange(r *http.Request) (from, to *time.Time, err error) {
+	for param, value := range map[string]**time.Time{"from": &from, "to": &to} {
+		if r.URL.Query().Get(param) == "" {
+			continue
+		}
+		v, err := time.Parse(time.RFC3339, r.URL.Query().Get(param))
+		if err != nil {
+			return nil, nil, wrappers.NewValidationErr(fmt.Errorf("%s must be an RFC3339 time", param))
+		}
+		*value = &v
+	}
+	return
+}
+
+// csvResponse writes a CSV file as the response, setting its headers on the first write so errors can still be responded before
+type csvResponse struct {
+	w        http.ResponseWriter
+	filename string
+	written  bool
+}
+
+// Write implements the io.Writer interface
+func (c *csvResponse) Write(p []byte) (int, error) {
+	if !c.written {
+		c.written = true
+		c.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		c.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", c.filename))
+		c.w.WriteHeader(http.StatusOK)
+	}
+	return c.w.Write(p)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// claimsKey the key of the claims of the token of the request in its context
+type claimsKey struct{}
+
+// Authenticated returns a middleware checking the JWT bearer token of the requests without requiring any claim,
+// for the route groups whose routes all require a login
+func Authenticated(keys ports.TokenKeys) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return authenticate(next, keys, jwt.MapClaims{})
+	}
+}
+
+// authenticate checks the JWT bearer token of the request with the key it was signed with, and that it has the required claims,
+// so the tokens signed with a rotated key are accepted until the key expires. The claims of the token are set in the context of the request.
+func authenticate(next http.Handler, keys ports.TokenKeys, claims jwt.MapClaims) http.Handler {
+	return authenticateAny(next, keys, claims)
+}
+
+// authenticateAny checks the token of the request like authenticate, requiring it to have the claims of any of the alternatives
+func authenticateAny(next http.Handler, keys ports.TokenKeys, alternatives ...jwt.MapClaims) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizationHeader := r.Header.Get("Authorization")
+		if authorizationHeader == "" {
+			responseError(w, r, nil, apierror.Unauthorized(fmt.Errorf("an authorization header is required")))
+			return
+		}
+		bearerToken := strings.Split(authorizationHeader, " ")
+		if len(bearerToken) != 2 {
+			responseError(w, r, nil, apierror.Unauthorized(fmt.Errorf("authorization header not properly formated, should be Bearer + {token}")))
+			return
+		}
+
+		tokenClaims, err := keys.Parse(r.Context(), bearerToken[1])
+		if err != nil {
+			responseError(w, r, nil, apierror.Unauthorized(fmt.Errorf("invalid token: %s", err)))
+			return
+		}
+		var missing []string
+		for _, claims := range alternatives {
+			name, ok := missingClaim(tokenClaims, claims)
+			if !ok {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, tokenClaims)))
+				return
+			}
+			missing = append(missing, name)
+		}
+		responseError(w, r, nil, apierror.Unauthorized(fmt.Errorf("required claim %s not found or incorrect", strings.Join(missing, " or "))))
+	})
+}
+
+// missingClaim returns the name of a claim required not found or incorrect in the claims of the token, if any
+func missingClaim(tokenClaims, claims jwt.MapClaims) (string, bool) {
+	for name, value := range claims {
+		if claim, ok := tokenClaims[name]; !(ok && claim == value) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// caller returns the user calling from the claims of the token of the request, checked by authenticate
+func caller(r *http.Request) models.Caller {
+	claims, _ := r.Context().Value(claimsKey{}).(jwt.MapClaims)
+	userID, _ := claims["user_id"].(string)
+	apiKeyID, _ := claims["api_key_id"].(string)
+	return models.Caller{
+		UserID:   userID,
+		Admin:    claims["admin"] == true,
+		APIKeyID: apiKeyID,
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetBackupRoutes creates backup routes
+func SetBackupRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.BackupService) {
+	r.Handle("/v1/backups/{collection}", authenticate(createBackup(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/backups/{collection}/{name}/restore", authenticate(restoreBackup(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+}
+
+// @Summary Create backup
+// @Description Queues the dump of a collection to the file storage, its progress is reported by the returned job
+// @Tags Backups
+// @Security Bearer
+// @Param collection path string true "Collection" Enums(users)
+// @Success 202 {object} models.JobResp "Accepted"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/backups/{collection} [post]
+func createBackup(ctx context.Context, cfg config.Config, s ports.BackupService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.Backup(ctx, params["collection"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusAccepted, response)
+	})
+}
+
+// @Summary Restore backup
+// @Description Queues the replacement of all the documents of a collection with the ones of the named backup, its progress is reported by the returned job
+// @Tags Backups
+// @Security Bearer
+// @Param collection path string true "Collection" Enums(users)
+// @Param name path string true "Backup name"
+// @Success 202 {object} models.JobResp "Accepted"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/backups/{collection}/{name}/restore [post]
+func restoreBackup(ctx context.Context, cfg config.Config, s ports.BackupService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.Restore(ctx, params["collection"], params["name"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusAccepted, response)
+	})
+}
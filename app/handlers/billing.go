@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetBillingRoutes creates billing routes, the webhook being authenticated by the signature of the provider
+func SetBillingRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.BillingService) {
+	r.Handle("/v1/billing/webhook", receiveBillingEvent(ctx, cfg, s)).Methods(http.MethodPost)
+}
+
+// @Summary Receive billing event
+// @Description Receives an event of the billing provider, signed in the Stripe-Signature header, syncing the subscription status of the user of its customer
+// @Tags Billing
+// @Accept json
+// @Param Stripe-Signature header string true "Signature of the provider"
+// @Param event body object true "Event of the provider"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 500 {object} object
+// @Router /v1/billing/webhook [post]
+func receiveBillingEvent(ctx context.Context, cfg config.Config, s ports.BillingService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		err = s.HandleEvent(ctx, body, r.Header.Get("Stripe-Signature"))
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestReceiveBillingEvent_Ok checks that ReceiveBillingEvent handler passes the event and its signature to the service
+func TestReceiveBillingEvent_Ok(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	event := `{"id":"evt_123","type":"customer.subscription.updated"}`
+	billingService := mocks.NewBillingService(t)
+	billingService.On(testutils.FunctionName(t, ports.BillingService.HandleEvent), mock.Anything, []byte(event), "t=1,v1=abc").Return(nil).Once()
+
+	SetBillingRoutes(context.Background(), config.Config{}, r, billingService)
+
+	rr := httptest.NewRecorder()
+	url := "http://testing/v1/billing/webhook"
+	req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(event))
+	req.Header.Set("Stripe-Signature", "t=1,v1=abc")
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+}
+
+// TestReceiveBillingEvent_Unauthorized checks that ReceiveBillingEvent handler returns an unauthorized error when the signature is not valid
+func TestReceiveBillingEvent_Unauthorized(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	billingService := mocks.NewBillingService(t)
+	billingService.On(testutils.FunctionName(t, ports.BillingService.HandleEvent), mock.Anything, mock.Anything, "").
+		Return(wrappers.NewUnauthorizedErr(fmt.Errorf("billing event signature not valid"))).Once()
+
+	SetBillingRoutes(context.Background(), config.Config{}, r, billingService)
+
+	rr := httptest.NewRecorder()
+	url := "http://testing/v1/billing/webhook"
+	req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{}`))
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusUnauthorized, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+}
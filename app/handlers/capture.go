@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// defaultCapturesTake is the number of captures returned when take is not specified
+const defaultCapturesTake = 100
+
+// SetCaptureRoutes creates the debug capture mode routes, only for admins
+func SetCaptureRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.CaptureService) {
+	admin := func(h http.Handler) http.Handler {
+		return authenticate(h, keys, jwt.MapClaims{"admin": true})
+	}
+
+	r.Handle("/v1/debug/capture", admin(getCaptureMode(ctx, cfg, s))).Methods(http.MethodGet)
+	r.Handle("/v1/debug/capture", admin(enableCaptureMode(ctx, cfg, s))).Methods(http.MethodPut)
+	r.Handle("/v1/debug/capture", admin(disableCaptureMode(ctx, cfg, s))).Methods(http.MethodDelete)
+	r.Handle("/v1/debug/captures", admin(getCaptures(ctx, cfg, s))).Methods(http.MethodGet)
+}
+
+// @Summary Get capture mode
+// @Description Gets the debug capture mode of the instance serving the request
+// @Tags Debug
+// @Security Bearer
+// @Success 200 {object} models.CaptureModeResp "OK"
+// @Failure 401 {object} object
+// @Failure 500 {object} object
+// @Router /v1/debug/capture [get]
+func getCaptureMode(ctx context.Context, cfg config.Config, s ports.CaptureService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		utils.ResponseJSON(w, r, nil, http.StatusOK, s.Mode(ctx))
+	})
+}
+
+// @Summary Enable capture mode
+// @Description Captures the sanitized requests and responses of a percentage of the requests served by the instance serving the request, until the duration expires
+// @Tags Debug
+// @Security Bearer
+// @Param mode body models.CaptureModeReq true "Percentage of the requests, up to 100, and duration, like 15m"
+// @Success 200 {object} models.CaptureModeResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/debug/capture [put]
+func enableCaptureMode(ctx context.Context, cfg config.Config, s ports.CaptureService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var mode models.CaptureModeReq
+		err = json.Unmarshal(body, &mode)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		resp, err := s.Enable(ctx, mode)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, resp)
+	})
+}
+
+// @Summary Disable capture mode
+// @Description Disables the debug capture mode of the instance serving the request before it expires, keeping the captures
+// @Tags Debug
+// @Security Bearer
+// @Success 200 "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/debug/capture [delete]
+func disableCaptureMode(ctx context.Context, cfg config.Config, s ports.CaptureService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		err := s.Disable(ctx)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Get captures
+// @Description Gets the captured requests not expired yet, newest first
+// @Tags Debug
+// @Security Bearer
+// @Param skip query int false "Captures to skip"
+// @Param take query int false "Captures to return, 100 by default"
+// @Success 200 {array} models.CaptureResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/debug/captures [get]
+func getCaptures(ctx context.Context, cfg config.Config, s ports.CaptureService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		req := models.GetCapturesReq{
+			Take: defaultCapturesTake,
+		}
+		for param, value := range map[string]*int{"skip": &req.Skip, "take": &req.Take} {
+			if r.URL.Query().Get(param) == "" {
+				continue
+			}
+			v, err := strconv.Atoi(r.URL.Query().Get(param))
+			if err != nil {
+				responseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("%s must be an integer", param)))
+				return
+			}
+			*value = v
+		}
+
+		captures, err := s.Get(ctx, req)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, captures)
+	})
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetConfigRoutes creates config routes
+func SetConfigRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys) {
+	r.Handle("/v1/config", authenticate(getConfig(ctx, cfg), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+}
+
+// @Summary Get config
+// @Description Gets the effective config loaded by the running instance, from the flags and the config files, with the secrets redacted, along with the state of the feature flags
+// @Tags Config
+// @Security Bearer
+// @Success 200 {object} models.ConfigResp "OK"
+// @Failure 401 {object} object
+// @Failure 500 {object} object
+// @Router /v1/config [get]
+func getConfig(ctx context.Context, cfg config.Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := models.ConfigResp{
+			Config:   cfg.Redacted(),
+			Features: cfg.Features(),
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, resp)
+	})
+}
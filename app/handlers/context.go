@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestContext returns the given context, which outlives the request, carrying the span of the request,
+// so that the spans started while serving it are children of it, its request info, so that its operations can be attributed,
+// and its timings, so that its operations are timed
+func requestContext(ctx context.Context, r *http.Request) context.Context {
+	ctx = models.WithRequestInfo(ctx, models.RequestInfoFrom(r.Context()))
+	ctx = models.WithRequestTimings(ctx, models.RequestTimingsFrom(r.Context()))
+	return trace.ContextWithSpan(ctx, trace.SpanFromContext(r.Context()))
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestRequestContext_Ok checks that requestContext returns the given context carrying the span and the request info of the request
+func TestRequestContext_Ok(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	reqCtx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "test-span")
+	info := models.RequestInfo{RequestID: "test-request-id", ActorID: "test-id", IP: "127.0.0.1"}
+	req := httptest.NewRequest(http.MethodGet, "http://testing", nil).WithContext(models.WithRequestInfo(reqCtx, info))
+
+	// Act
+	result := requestContext(ctx, req)
+
+	// Assert
+	assert.Equal(t, span.SpanContext(), trace.SpanContextFromContext(result))
+	assert.Equal(t, info, models.RequestInfoFrom(result))
+	deadline, ok := result.Deadline()
+	expectedDeadline, _ := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, expectedDeadline, deadline)
+}
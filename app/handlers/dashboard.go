@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetDashboardRoutes creates dashboard routes, only for admins
+func SetDashboardRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.DashboardService) {
+	r.Handle("/v1/admin/dashboard", authenticate(getDashboard(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+}
+
+// @Summary Get admin dashboard
+// @Description Gets the user counts, the signups today, the active sessions, the failed logins of the last hour, the webhook failures and the job backlog, aggregated at computed_at and reused for up to Dashboard.MaxAge
+// @Tags Dashboard
+// @Security Bearer
+// @Success 200 {object} models.DashboardResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/admin/dashboard [get]
+func getDashboard(ctx context.Context, cfg config.Config, s ports.DashboardService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		response, err := s.Get(ctx)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
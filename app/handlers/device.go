@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetDeviceRoutes creates device routes
+func SetDeviceRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.DeviceService) {
+	r.Handle("/v1/users/{id}/devices", authenticate(registerDevice(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/users/{id}/devices", authenticate(getDevices(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/users/{id}/devices/{device}", authenticate(deleteDevice(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodDelete)
+}
+
+// @Summary Register device
+// @Description Registers the token of a device of a user, given by FCM or APNs, so it receives the push notifications of the security events of the account
+// @Tags Devices
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param device body models.RegisterDeviceReq true "Device"
+// @Success 201 {object} models.DeviceResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/devices [post]
+func registerDevice(ctx context.Context, cfg config.Config, s ports.DeviceService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var req models.RegisterDeviceReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		response, err := s.Register(ctx, params["id"], req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, response)
+	})
+}
+
+// @Summary Get devices
+// @Description Gets the devices registered by a user, the newest first
+// @Tags Devices
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 {array} models.DeviceResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/devices [get]
+func getDevices(ctx context.Context, cfg config.Config, s ports.DeviceService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.GetByUser(ctx, params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Delete device
+// @Description Deletes a device of a user, so it no longer receives the push notifications
+// @Tags Devices
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param device path string true "Device ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/devices/{device} [delete]
+func deleteDevice(ctx context.Context, cfg config.Config, s ports.DeviceService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Delete(ctx, params["id"], params["device"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
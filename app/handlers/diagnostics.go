@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// SetDiagnosticsRoutes creates the routes of the runtime profiles, only for admins.
+// The cmdline profile is not served, as the command line contains the DSN.
+func SetDiagnosticsRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys) {
+	admin := func(h http.Handler) http.Handler {
+		return authenticate(h, keys, jwt.MapClaims{"admin": true})
+	}
+
+	r.Handle("/debug/pprof/", admin(http.HandlerFunc(pprof.Index))).Methods(http.MethodGet)
+	r.Handle("/debug/pprof/profile", admin(http.HandlerFunc(pprof.Profile))).Methods(http.MethodGet)
+	r.Handle("/debug/pprof/trace", admin(http.HandlerFunc(pprof.Trace))).Methods(http.MethodGet)
+	r.Handle("/debug/pprof/symbol", admin(http.HandlerFunc(pprof.Symbol))).Methods(http.MethodGet, http.MethodPost)
+	r.Handle("/debug/pprof/{profile:[a-z]+}", admin(getProfile())).Methods(http.MethodGet)
+}
+
+// getProfile serves the named runtime profile, like heap or goroutine
+func getProfile() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["profile"]
+		if name == "cmdline" {
+			http.NotFound(w, r)
+			return
+		}
+		pprof.Handler(name).ServeHTTP(w, r)
+	})
+}
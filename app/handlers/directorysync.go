@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// SetDirectorySyncRoutes creates directory sync routes
+func SetDirectorySyncRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.DirectorySyncService) {
+	r.Handle("/v1/directory/syncs", authenticate(createDirectorySync(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/directory/syncs/{id}/report", authenticate(getDirectorySyncReport(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+}
+
+// @Summary Create directory sync
+// @Description Queues a sync of the users with the external directory, creating, updating and deactivating them, only reporting the changes unless dry_run is false. Its status and counts are reported by the returned job
+// @Tags Directory
+// @Security Bearer
+// @Param dry_run query bool false "Only report the changes, true by default"
+// @Success 202 {object} models.JobResp "Accepted"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/directory/syncs [post]
+func createDirectorySync(ctx context.Context, cfg config.Config, s ports.DirectorySyncService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		dryRun := true
+		if param := r.URL.Query().Get("dry_run"); param != "" {
+			var err error
+			dryRun, err = strconv.ParseBool(param)
+			if err != nil {
+				responseError(w, r, nil, wrappers.NewValidationErr(errors.New("dry_run must be a boolean")))
+				return
+			}
+		}
+
+		response, err := s.Sync(ctx, dryRun)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusAccepted, response)
+	})
+}
+
+// @Summary Get directory sync report
+// @Description Gets the report of the users created, updated and deactivated by a finished directory sync or, in dry run, that would be
+// @Tags Directory
+// @Security Bearer
+// @Param id path string true "ID of the job of the sync"
+// @Success 200 {object} models.DirectorySyncReportResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/directory/syncs/{id}/report [get]
+func getDirectorySyncReport(ctx context.Context, cfg config.Config, s ports.DirectorySyncService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.GetReport(ctx, params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// errorStatus returns the status of the kind of the error, or 0 when it is of none of the kinds of apierror
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, apierror.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apierror.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apierror.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, apierror.ErrValidation):
+		return http.StatusBadRequest
+	default:
+		return 0
+	}
+}
+
+// responseError responds with the error and the status of its kind, along with the reason of every field not valid for the validation errors.
+// The errors of none of the kinds keep the status of utils.ResponseError, like a 400 for the wrappers errors not migrated yet.
+func responseError(w http.ResponseWriter, r *http.Request, body []byte, err error) {
+	status := errorStatus(err)
+	if status == 0 {
+		utils.ResponseError(w, r, body, err)
+		return
+	}
+
+	payload := map[string]interface{}{"error": err.Error()}
+	var validationErr *apierror.ValidationError
+	if errors.As(err, &validationErr) {
+		fields := make(map[string]string, len(validationErr.Fields))
+		for _, f := range validationErr.Fields {
+			fields[f.Field] = f.Message
+		}
+		payload["fields"] = fields
+	}
+	utils.ResponseJSON(w, r, body, status, payload)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestResponseError_NotFound checks that responseError responds to a not found error with a 404
+func TestResponseError_NotFound(t *testing.T) {
+	// Arrange
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://testing", nil)
+
+	// Act
+	responseError(rr, req, nil, apierror.NotFound(fmt.Errorf("ID test not found")))
+
+	// Assert
+	if want, got := http.StatusNotFound, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+	var response map[string]string
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "ID test not found", response["error"])
+}
+
+// TestResponseError_Conflict checks that responseError responds to a conflict with a 409
+func TestResponseError_Conflict(t *testing.T) {
+	// Arrange
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://testing", nil)
+
+	// Act
+	responseError(rr, req, nil, apierror.Conflict(fmt.Errorf("email test@test.com already in use")))
+
+	// Assert
+	if want, got := http.StatusConflict, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+}
+
+// TestResponseError_Validation checks that responseError responds to a validation error with a 400 and the reason of every field not valid
+func TestResponseError_Validation(t *testing.T) {
+	// Arrange
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://testing", nil)
+	var errs apierror.ValidationError
+	errs.Add("email", "email cannot be empty")
+	errs.Add("password", "password cannot be empty")
+
+	// Act
+	responseError(rr, req, nil, errs.Err())
+
+	// Assert
+	if want, got := http.StatusBadRequest, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+	var response struct {
+		Error  string            `json:"error"`
+		Fields map[string]string `json:"fields"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "email cannot be empty | password cannot be empty", response.Error)
+	assert.Equal(t, map[string]string{"email": "email cannot be empty", "password": "password cannot be empty"}, response.Fields)
+}
+
+// TestResponseError_Wrappers checks that responseError keeps the status of the wrappers errors, like a 400 for a non existent error
+func TestResponseError_Wrappers(t *testing.T) {
+	// Arrange
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://testing", nil)
+
+	// Act
+	responseError(rr, req, nil, wrappers.NewNonExistentErr(fmt.Errorf("not found")))
+
+	// Assert
+	if want, got := http.StatusBadRequest, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+}
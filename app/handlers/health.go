@@ -6,14 +6,19 @@ import (
 	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/app/buildinfo"
 	"github.com/sergicanet9/go-hexagonal-api/config"
-	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
 )
 
 // SetHealthRoutes creates health routes
-func SetHealthRoutes(ctx context.Context, cfg config.Config, r *mux.Router) {
+func SetHealthRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.HealthService) {
 	r.Handle("/health", healthCheck(ctx, cfg)).Methods(http.MethodGet)
+	r.Handle("/readyz", readinessCheck(ctx, cfg, s)).Methods(http.MethodGet)
+	r.Handle("/status", status(ctx, cfg, s)).Methods(http.MethodGet)
+	r.Handle("/version", version(ctx, cfg)).Methods(http.MethodGet)
 }
 
 // @Summary Health Check
@@ -24,7 +29,7 @@ func SetHealthRoutes(ctx context.Context, cfg config.Config, r *mux.Router) {
 // @Failure 503 {object} object
 // @Router /health [get]
 func healthCheck(ctx context.Context, cfg config.Config) http.Handler {
-	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		r.Header.Add("Version", cfg.Version)
 		r.Header.Add("Environment", cfg.Environment)
 		r.Header.Add("Port", strconv.Itoa(cfg.Port))
@@ -34,3 +39,64 @@ func healthCheck(ctx context.Context, cfg config.Config) http.Handler {
 		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
 	})
 }
+
+// @Summary Readiness Check
+// @Description Runs the health checks of the dependencies, reporting the status and latency of each of them.
+// @Description The API is degraded when only non critical checks fail, and down, responding with 503, when a critical one fails.
+// @Tags Health
+// @Success 200 {object} models.ReadinessResp "OK"
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Failure 503 {object} models.ReadinessResp "Down"
+// @Router /readyz [get]
+func readinessCheck(ctx context.Context, cfg config.Config, s ports.HealthService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		resp := s.Check(ctx)
+		status := http.StatusOK
+		if resp.Status == models.HealthStatusDown {
+			status = http.StatusServiceUnavailable
+		}
+		utils.ResponseJSON(w, r, nil, status, resp)
+	})
+}
+
+// @Summary Status
+// @Description Reports the overall status of the API, ok or degraded, along with its uptime and version, for external status pages.
+// @Description It does not require authentication nor expose the details of the checks, and reuses the last readiness check when recent.
+// @Tags Health
+// @Success 200 {object} models.StatusResp "OK"
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /status [get]
+func status(ctx context.Context, cfg config.Config, s ports.HealthService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		resp := s.Status(ctx)
+		utils.ResponseJSON(w, r, nil, http.StatusOK, resp)
+	})
+}
+
+// @Summary Version
+// @Description Reports the version, commit and build date of the binary serving the request, so the builds running in the fleet can be told apart during a rollout.
+// @Description It does not require authentication.
+// @Tags Health
+// @Success 200 {object} models.VersionResp "OK"
+// @Failure 500 {object} object
+// @Router /version [get]
+func version(ctx context.Context, cfg config.Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := buildinfo.Get().WithVersion(cfg.Version)
+		resp := models.VersionResp{
+			Version:   info.Version,
+			Commit:    info.Commit,
+			BuildDate: info.Date,
+			GoVersion: info.GoVersion,
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, resp)
+	})
+}
@@ -2,12 +2,19 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gorilla/mux"
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // TestHealthCheck_Ok checks that healthCheck handler does not return an error when everything goes as expected
@@ -16,7 +23,7 @@ func TestHealthCheck_Ok(t *testing.T) {
 	r := mux.NewRouter()
 
 	cfg := config.Config{}
-	SetHealthRoutes(context.Background(), cfg, r)
+	SetHealthRoutes(context.Background(), cfg, r, mocks.NewHealthService(t))
 
 	rr := httptest.NewRecorder()
 	url := "http://testing/health"
@@ -30,3 +37,121 @@ func TestHealthCheck_Ok(t *testing.T) {
 		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
 	}
 }
+
+// TestReadinessCheck_Degraded checks that readinessCheck handler returns the checks with an OK status when only non critical ones fail
+func TestReadinessCheck_Degraded(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	healthService := mocks.NewHealthService(t)
+	expectedResponse := models.ReadinessResp{
+		Status: models.HealthStatusDegraded,
+		Checks: map[string]models.HealthCheckResp{
+			"database": {Status: models.HealthStatusUp, Critical: true, LatencyMS: 1},
+			"storage":  {Status: models.HealthStatusDown, LatencyMS: 2, Error: "test-error"},
+		},
+	}
+	healthService.On(testutils.FunctionName(t, ports.HealthService.Check), mock.Anything).Return(expectedResponse).Once()
+
+	cfg := config.Config{}
+	SetHealthRoutes(context.Background(), cfg, r, healthService)
+
+	rr := httptest.NewRecorder()
+	url := "http://testing/readyz"
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+	var response models.ReadinessResp
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("unexpected error parsing the response while calling %s: %s", req.URL, err)
+	}
+	assert.Equal(t, expectedResponse, response)
+}
+
+// TestReadinessCheck_Down checks that readinessCheck handler returns a service unavailable when a critical check fails
+func TestReadinessCheck_Down(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	healthService := mocks.NewHealthService(t)
+	healthService.On(testutils.FunctionName(t, ports.HealthService.Check), mock.Anything).Return(models.ReadinessResp{Status: models.HealthStatusDown}).Once()
+
+	cfg := config.Config{}
+	SetHealthRoutes(context.Background(), cfg, r, healthService)
+
+	rr := httptest.NewRecorder()
+	url := "http://testing/readyz"
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusServiceUnavailable, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+}
+
+// TestStatus_Ok checks that status handler returns the status of the API without requiring authentication
+func TestStatus_Ok(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	healthService := mocks.NewHealthService(t)
+	expectedResponse := models.StatusResp{Status: models.ServiceStatusDegraded, UptimeSeconds: 60, Version: "test-version"}
+	healthService.On(testutils.FunctionName(t, ports.HealthService.Status), mock.Anything).Return(expectedResponse).Once()
+
+	cfg := config.Config{}
+	SetHealthRoutes(context.Background(), cfg, r, healthService)
+
+	rr := httptest.NewRecorder()
+	url := "http://testing/status"
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+	var response models.StatusResp
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("unexpected error parsing the response while calling %s: %s", req.URL, err)
+	}
+	assert.Equal(t, expectedResponse, response)
+}
+
+// TestVersion_Ok checks that version handler returns the metadata of the build with the version of the config
+func TestVersion_Ok(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	cfg := config.Config{}
+	cfg.Version = "test-version"
+	SetHealthRoutes(context.Background(), cfg, r, mocks.NewHealthService(t))
+
+	rr := httptest.NewRecorder()
+	url := "http://testing/version"
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+	var response models.VersionResp
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("unexpected error parsing the response while calling %s: %s", req.URL, err)
+	}
+	assert.Equal(t, "test-version", response.Version)
+	assert.NotEmpty(t, response.GoVersion)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// defaultJobsTake is the number of jobs returned when take is not specified
+const defaultJobsTake = 100
+
+// SetJobRoutes creates job routes
+func SetJobRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.JobService) {
+	r.Handle("/v1/jobs", authenticate(getJobs(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+	r.Handle("/v1/jobs/{id}", authenticate(getJobByID(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+	r.Handle("/v1/jobs/{id}/retry", authenticate(retryJob(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+}
+
+// @Summary Get jobs
+// @Description Gets the queued and run jobs, newest first, the dead letter being the dead ones
+// @Tags Jobs
+// @Security Bearer
+// @Param status query string false "Job status" Enums(queued, running, succeeded, failed, dead)
+// @Param type query string false "Job type"
+// @Param skip query int false "Jobs to skip"
+// @Param take query int false "Jobs to return, 100 by default"
+// @Success 200 {array} models.JobResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/jobs [get]
+func getJobs(ctx context.Context, cfg config.Config, s ports.JobService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		req := models.GetJobsReq{
+			Status: r.URL.Query().Get("status"),
+			Type:   r.URL.Query().Get("type"),
+			Take:   defaultJobsTake,
+		}
+		for param, value := range map[string]*int{"skip": &req.Skip, "take": &req.Take} {
+			if r.URL.Query().Get(param) == "" {
+				continue
+			}
+			v, err := strconv.Atoi(r.URL.Query().Get(param))
+			if err != nil {
+				responseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("%s must be an integer", param)))
+				return
+			}
+			*value = v
+		}
+
+		jobs, err := s.Get(ctx, req)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, jobs)
+	})
+}
+
+// @Summary Get job by ID
+// @Description Gets the status and progress of a job
+// @Tags Jobs
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 {object} models.JobResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/jobs/{id} [get]
+func getJobByID(ctx context.Context, cfg config.Config, s ports.JobService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.GetByID(ctx, params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Retry job
+// @Description Queues again a job left in the dead letter, or a failed one waiting for its next attempt, with all its attempts
+// @Tags Jobs
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 {object} models.JobResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/jobs/{id}/retry [post]
+func retryJob(ctx context.Context, cfg config.Config, s ports.JobService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.Retry(ctx, params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
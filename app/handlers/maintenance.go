@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetMaintenanceRoutes creates maintenance routes
+func SetMaintenanceRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.MaintenanceService) {
+	r.Handle("/v1/maintenance/tasks", authenticate(getMaintenanceTasks(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+	r.Handle("/v1/maintenance/tasks/{task}", authenticate(startMaintenanceTask(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+}
+
+// @Summary Get maintenance tasks
+// @Description Gets the maintenance tasks that can be started
+// @Tags Maintenance
+// @Security Bearer
+// @Success 200 {array} models.MaintenanceTaskResp "OK"
+// @Failure 401 {object} object
+// @Failure 500 {object} object
+// @Router /v1/maintenance/tasks [get]
+func getMaintenanceTasks(ctx context.Context, cfg config.Config, s ports.MaintenanceService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.ResponseJSON(w, r, nil, http.StatusOK, s.Tasks())
+	})
+}
+
+// @Summary Start maintenance task
+// @Description Queues the maintenance task, its progress is reported by the returned job
+// @Tags Maintenance
+// @Security Bearer
+// @Param task path string true "Task" Enums(rebuild_indexes, recompute_stats, flush_caches, replay_notifications, purge_expired)
+// @Success 202 {object} models.JobResp "Accepted"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/maintenance/tasks/{task} [post]
+func startMaintenanceTask(ctx context.Context, cfg config.Config, s ports.MaintenanceService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.Start(ctx, params["task"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusAccepted, response)
+	})
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// hiddenMetrics are the published variables not exported by the metrics endpoint, as the command line contains the DSN
+var hiddenMetrics = map[string]bool{"cmdline": true}
+
+// SetMetricsRoutes creates metrics routes
+func SetMetricsRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys) {
+	r.Handle("/v1/metrics", authenticate(getMetrics(ctx, cfg), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+}
+
+// @Summary Get metrics
+// @Description Gets the runtime metrics, the authentication metrics, the duration of the database commands per collection and operation and the database server status
+// @Tags Metrics
+// @Security Bearer
+// @Success 200 {object} object "OK"
+// @Failure 401 {object} object
+// @Failure 500 {object} object
+// @Router /v1/metrics [get]
+func getMetrics(ctx context.Context, cfg config.Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metrics := make(map[string]json.RawMessage)
+		expvar.Do(func(kv expvar.KeyValue) {
+			if !hiddenMetrics[kv.Key] {
+				metrics[kv.Key] = json.RawMessage(kv.Value.String())
+			}
+		})
+
+		utils.ResponseJSON(w, r, nil, http.StatusOK, metrics)
+	})
+}
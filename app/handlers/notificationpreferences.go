@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetNotificationPreferencesRoutes creates notification preferences routes, the preferences of a user being managed by the user itself and by the admins
+func SetNotificationPreferencesRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.NotificationPreferencesService) {
+	r.Handle("/v1/users/{id}/notification-preferences", authenticate(getNotificationPreferences(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/users/{id}/notification-preferences", authenticate(updateNotificationPreferences(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPatch)
+}
+
+// @Summary Get notification preferences
+// @Description Gets the channels the notifications of every category are delivered to a user through, every channel being enabled for the categories never set
+// @Tags Notification preferences
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 {object} models.NotificationPreferencesResp "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/notification-preferences [get]
+func getNotificationPreferences(ctx context.Context, cfg config.Config, s ports.NotificationPreferencesService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.Get(ctx, caller(r), params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Update notification preferences
+// @Description Enables or disables the channels of the categories of the notifications of a user, the channels and categories not set being left as they are
+// @Tags Notification preferences
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param preferences body models.UpdateNotificationPreferencesReq true "Notification preferences"
+// @Success 200 {object} models.NotificationPreferencesResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/notification-preferences [patch]
+func updateNotificationPreferences(ctx context.Context, cfg config.Config, s ports.NotificationPreferencesService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var req models.UpdateNotificationPreferencesReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		response, err := s.Update(ctx, caller(r), params["id"], req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, response)
+	})
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestGetNotificationPreferences_Ok checks that GetNotificationPreferences handler returns the preferences of the user of the token
+func TestGetNotificationPreferences_Ok(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	keysMock := mocks.NewTokenKeys(t)
+	keysMock.On(testutils.FunctionName(t, ports.TokenKeys.Parse), mock.Anything, "test-token").Return(jwt.MapClaims{"user_id": "test-user"}, nil).Once()
+	preferencesService := mocks.NewNotificationPreferencesService(t)
+	expectedResp := models.NotificationPreferencesResp{UserID: "test-user", Categories: map[string]models.NotificationChannelsResp{entities.NotificationCategorySecurity: {Email: true}}}
+	preferencesService.On(testutils.FunctionName(t, ports.NotificationPreferencesService.Get), mock.Anything, models.Caller{UserID: "test-user"}, "test-user").Return(expectedResp, nil).Once()
+
+	SetNotificationPreferencesRoutes(context.Background(), config.Config{}, r, keysMock, preferencesService)
+
+	rr := httptest.NewRecorder()
+	url := "http://testing/v1/users/test-user/notification-preferences"
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Add("Authorization", "Bearer test-token")
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+	var resp models.NotificationPreferencesResp
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Categories[entities.NotificationCategorySecurity].Email {
+		t.Fatalf("unexpected preferences: %+v", resp)
+	}
+}
+
+// TestUpdateNotificationPreferences_Ok checks that UpdateNotificationPreferences handler updates the preferences of the user with the request
+func TestUpdateNotificationPreferences_Ok(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	keysMock := mocks.NewTokenKeys(t)
+	keysMock.On(testutils.FunctionName(t, ports.TokenKeys.Parse), mock.Anything, "test-token").Return(jwt.MapClaims{"user_id": "test-user"}, nil).Once()
+	preferencesService := mocks.NewNotificationPreferencesService(t)
+	disabled := false
+	preferencesReq := models.UpdateNotificationPreferencesReq{Categories: map[string]models.UpdateNotificationChannelsReq{entities.NotificationCategorySecurity: {Push: &disabled}}}
+	preferencesService.On(testutils.FunctionName(t, ports.NotificationPreferencesService.Update), mock.Anything, models.Caller{UserID: "test-user"}, "test-user", preferencesReq).Return(models.NotificationPreferencesResp{UserID: "test-user"}, nil).Once()
+
+	SetNotificationPreferencesRoutes(context.Background(), config.Config{}, r, keysMock, preferencesService)
+
+	rr := httptest.NewRecorder()
+	body, err := json.Marshal(preferencesReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://testing/v1/users/test-user/notification-preferences"
+	req := httptest.NewRequest(http.MethodPatch, url, bytes.NewReader(body))
+	req.Header.Add("Authorization", "Bearer test-token")
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+}
+
+// TestUpdateNotificationPreferences_Unauthorized checks that UpdateNotificationPreferences handler returns an unauthorized when the caller is another user
+func TestUpdateNotificationPreferences_Unauthorized(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	keysMock := mocks.NewTokenKeys(t)
+	keysMock.On(testutils.FunctionName(t, ports.TokenKeys.Parse), mock.Anything, "test-token").Return(jwt.MapClaims{"user_id": "other-user"}, nil).Once()
+	preferencesService := mocks.NewNotificationPreferencesService(t)
+	preferencesService.On(testutils.FunctionName(t, ports.NotificationPreferencesService.Update), mock.Anything, models.Caller{UserID: "other-user"}, "test-user", models.UpdateNotificationPreferencesReq{}).Return(models.NotificationPreferencesResp{}, apierror.Unauthorized(fmt.Errorf("the notification preferences of user ID test-user are only managed by the user and the admins"))).Once()
+
+	SetNotificationPreferencesRoutes(context.Background(), config.Config{}, r, keysMock, preferencesService)
+
+	rr := httptest.NewRecorder()
+	url := "http://testing/v1/users/test-user/notification-preferences"
+	req := httptest.NewRequest(http.MethodPatch, url, bytes.NewReader([]byte(`{}`)))
+	req.Header.Add("Authorization", "Bearer test-token")
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusUnauthorized, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+}
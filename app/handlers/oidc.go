@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// paths of the token endpoint and of the key set, relative to the issuer
+const (
+	tokenPath = "/oauth/token"
+	jwksPath  = "/.well-known/jwks.json"
+)
+
+// SetOIDCRoutes creates OpenID Connect discovery routes
+func SetOIDCRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.UserService) {
+	r.Handle("/.well-known/openid-configuration", getOpenIDConfiguration(ctx, cfg)).Methods(http.MethodGet)
+	r.Handle(jwksPath, getJWKS(ctx, cfg)).Methods(http.MethodGet)
+	r.Handle(tokenPath, createOAuthToken(ctx, cfg, s)).Methods(http.MethodPost)
+}
+
+// @Summary Get OpenID configuration
+// @Description Gets the OpenID Connect discovery document of the API, describing its issuer, token endpoint and key set,
+// @Description so the OIDC client libraries can configure themselves against it.
+// @Description Only access tokens are issued, by the token endpoint, so neither the openid scope, nor an authorization endpoint, nor id tokens are advertised
+// @Tags OIDC
+// @Success 200 {object} models.OpenIDConfigurationResp "OK"
+// @Router /.well-known/openid-configuration [get]
+func getOpenIDConfiguration(ctx context.Context, cfg config.Config) http.Handler {
+	issuer := strings.TrimSuffix(cfg.OIDC.Issuer, "/")
+	var userClaims []string
+	for _, definition := range entities.GetUserClaimDefinitions() {
+		userClaims = append(userClaims, definition.Name)
+	}
+	sort.Strings(userClaims)
+	claims := append([]string{"sub", "iss", "iat", "exp", "authorized", "user_id"}, userClaims...)
+
+	// no response type is supported, as they are only answered by an authorization endpoint, which the API does not have
+	response := models.OpenIDConfigurationResp{
+		Issuer:                            cfg.OIDC.Issuer,
+		TokenEndpoint:                     issuer + tokenPath,
+		JWKSURI:                           issuer + jwksPath,
+		GrantTypesSupported:               []string{models.GrantTypePassword},
+		ResponseTypesSupported:            []string{},
+		SubjectTypesSupported:             []string{"public"},
+		TokenEndpointAuthMethodsSupported: []string{"none"},
+		ClaimsSupported:                   claims,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Get JSON Web Key Set
+// @Description Gets the public keys verifying the tokens, none as they are signed with HS256 by a secret key,
+// @Description so the tokens are verified by the API itself rather than by the clients
+// @Tags OIDC
+// @Success 200 {object} models.JWKSResp "OK"
+// @Router /.well-known/jwks.json [get]
+func getJWKS(ctx context.Context, cfg config.Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.ResponseJSON(w, r, nil, http.StatusOK, models.JWKSResp{Keys: []models.JWKResp{}})
+	})
+}
+
+// @Summary Create OAuth token
+// @Description Logs in an user with the OAuth password grant, responding with a token as the login does, along with its lifetime in seconds.
+// @Description The failures are responded as OAuth errors.
+// @Tags OIDC
+// @Accept x-www-form-urlencoded
+// @Param grant_type formData string true "Grant type" Enums(password)
+// @Param username formData string true "Email of the user"
+// @Param password formData string true "Password of the user"
+// @Param mfa_code formData string false "One-time code emailed to the user, when the security policy of its tenant requires MFA"
+// @Success 200 {object} models.TokenResp "OK"
+// @Failure 400 {object} models.OAuthErrorResp
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /oauth/token [post]
+func createOAuthToken(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		// the token responses, and the errors, must not be cached
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		if err := r.ParseForm(); err != nil {
+			responseOAuthError(w, r, models.OAuthErrorInvalidRequest, "form cannot be parsed")
+			return
+		}
+		switch grantType := r.PostForm.Get("grant_type"); grantType {
+		case models.GrantTypePassword:
+		case "":
+			responseOAuthError(w, r, models.OAuthErrorInvalidRequest, "grant_type must be set")
+			return
+		default:
+			responseOAuthError(w, r, models.OAuthErrorUnsupportedGrantType, "grant_type "+grantType+" not supported, it must be password")
+			return
+		}
+		credentials := models.LoginUserReq{Email: r.PostForm.Get("username"), Password: r.PostForm.Get("password"), MFACode: r.PostForm.Get("mfa_code")}
+		if credentials.Email == "" || credentials.Password == "" {
+			responseOAuthError(w, r, models.OAuthErrorInvalidRequest, "username and password must be set")
+			return
+		}
+
+		login, err := s.Login(ctx, credentials)
+		switch {
+		case errors.Is(err, wrappers.ValidationErr) || errors.Is(err, wrappers.NonExistentErr):
+			responseOAuthError(w, r, models.OAuthErrorInvalidGrant, "username or password incorrect")
+			return
+		case errors.Is(err, wrappers.UnauthorizedErr):
+			// a locked out email, told as the login does without revealing whether it exists
+			responseOAuthError(w, r, models.OAuthErrorInvalidGrant, err.Error())
+			return
+		case err != nil:
+			responseError(w, r, nil, err)
+			return
+		}
+
+		// the lifetime of the token is the one set by the security policy of the tenant of the user, when set
+		expiresIn := models.TokenLifetime
+		if !login.ExpiresAt.IsZero() {
+			expiresIn = time.Until(login.ExpiresAt).Round(time.Second)
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, models.TokenResp{
+			AccessToken: login.Token,
+			TokenType:   "Bearer",
+			ExpiresIn:   int64(expiresIn.Seconds()),
+		})
+	})
+}
+
+// responseOAuthError responds with the OAuth error as a 400, as RFC 6749 requires for the errors of the token endpoint
+func responseOAuthError(w http.ResponseWriter, r *http.Request, code, description string) {
+	utils.ResponseJSON(w, r, nil, http.StatusBadRequest, models.OAuthErrorResp{Error: code, ErrorDescription: description})
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestGetOpenIDConfiguration_Ok checks that GetOpenIDConfiguration handler returns the endpoints relative to the configured issuer
+func TestGetOpenIDConfiguration_Ok(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	cfg := config.Config{}
+	cfg.OIDC.Issuer = "https://api.example.com/"
+	SetOIDCRoutes(context.Background(), cfg, r, nil)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://testing/.well-known/openid-configuration", nil)
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+	var response models.OpenIDConfigurationResp
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("unexpected error parsing the response while calling %s: %s", req.URL, err)
+	}
+	assert.Equal(t, "https://api.example.com/", response.Issuer)
+	assert.Equal(t, "https://api.example.com/oauth/token", response.TokenEndpoint)
+	assert.Equal(t, "https://api.example.com/.well-known/jwks.json", response.JWKSURI)
+	assert.Equal(t, []string{"password"}, response.GrantTypesSupported)
+	assert.Empty(t, response.ResponseTypesSupported)
+	assert.NotContains(t, rr.Body.String(), "openid")
+	assert.NotContains(t, rr.Body.String(), "id_token")
+	assert.Equal(t, []string{"sub", "iss", "iat", "exp", "authorized", "user_id", "admin", "compliance"}, response.ClaimsSupported)
+}
+
+// TestGetJWKS_Ok checks that GetJWKS handler returns an empty key set, as the tokens are signed with a secret key
+func TestGetJWKS_Ok(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	SetOIDCRoutes(context.Background(), config.Config{}, r, nil)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://testing/.well-known/jwks.json", nil)
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"keys":[]}`, rr.Body.String())
+}
+
+// TestCreateOAuthToken_Ok checks that CreateOAuthToken handler logs in with the password grant, returning the token and its lifetime
+func TestCreateOAuthToken_Ok(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	userService := mocks.NewUserService(t)
+	credentials := models.LoginUserReq{Email: "test@test.com", Password: "test"}
+	userService.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, credentials).Return(models.LoginUserResp{Token: "test-token"}, nil).Once()
+
+	SetOIDCRoutes(context.Background(), config.Config{}, r, userService)
+
+	rr := httptest.NewRecorder()
+	form := url.Values{"grant_type": {"password"}, "username": {credentials.Email}, "password": {credentials.Password}}
+	req := httptest.NewRequest(http.MethodPost, "http://testing/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+	var response models.TokenResp
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("unexpected error parsing the response while calling %s: %s", req.URL, err)
+	}
+	assert.Equal(t, models.TokenResp{AccessToken: "test-token", TokenType: "Bearer", ExpiresIn: 604800}, response)
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+}
+
+// TestCreateOAuthToken_MFACode checks that CreateOAuthToken handler logs in with the one-time code of the form, returning the lifetime of the token of the login
+func TestCreateOAuthToken_MFACode(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	userService := mocks.NewUserService(t)
+	credentials := models.LoginUserReq{Email: "test@test.com", Password: "test", MFACode: "123456"}
+	userService.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, credentials).Return(models.LoginUserResp{Token: "test-token", ExpiresAt: time.Now().Add(time.Hour)}, nil).Once()
+
+	SetOIDCRoutes(context.Background(), config.Config{}, r, userService)
+
+	rr := httptest.NewRecorder()
+	form := url.Values{"grant_type": {"password"}, "username": {credentials.Email}, "password": {credentials.Password}, "mfa_code": {credentials.MFACode}}
+	req := httptest.NewRequest(http.MethodPost, "http://testing/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("unexpected http status code: want=%d but got=%d", want, got)
+	}
+	var response models.TokenResp
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("unexpected error parsing the response while calling %s: %s", req.URL, err)
+	}
+	assert.InDelta(t, 3600, response.ExpiresIn, 1)
+}
+
+// TestCreateOAuthToken_UnsupportedGrantType checks that CreateOAuthToken handler returns an OAuth error when the grant type is not password
+func TestCreateOAuthToken_UnsupportedGrantType(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	SetOIDCRoutes(context.Background(), config.Config{}, r, nil)
+
+	rr := httptest.NewRecorder()
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req := httptest.NewRequest(http.MethodPost, "http://testing/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var response models.OAuthErrorResp
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("unexpected error parsing the response while calling %s: %s", req.URL, err)
+	}
+	assert.Equal(t, models.OAuthErrorUnsupportedGrantType, response.Error)
+}
+
+// TestCreateOAuthToken_InvalidGrant checks that CreateOAuthToken handler returns an OAuth error when the credentials are not valid
+func TestCreateOAuthToken_InvalidGrant(t *testing.T) {
+	// Arrange
+	r := mux.NewRouter()
+
+	userService := mocks.NewUserService(t)
+	userService.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, mock.AnythingOfType("models.LoginUserReq")).Return(models.LoginUserResp{}, wrappers.NewValidationErr(errors.New("password incorrect"))).Once()
+
+	SetOIDCRoutes(context.Background(), config.Config{}, r, userService)
+
+	rr := httptest.NewRecorder()
+	form := url.Values{"grant_type": {"password"}, "username": {"test@test.com"}, "password": {"wrong"}}
+	req := httptest.NewRequest(http.MethodPost, "http://testing/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Act
+	r.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.JSONEq(t, `{"error":"invalid_grant","error_description":"username or password incorrect"}`, rr.Body.String())
+}
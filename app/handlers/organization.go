@@ -0,0 +1,475 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetOrganizationRoutes creates organization routes, the operations being allowed to the callers by their role in the organization
+func SetOrganizationRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.OrganizationService) {
+	r.Handle("/v1/organizations", authenticate(createOrganization(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/organizations", authenticate(getOrganizations(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/organizations/invitations", authenticate(getCallerInvitations(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/organizations/{id}", authenticate(getOrganizationByID(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/organizations/{id}", authenticate(updateOrganization(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPatch)
+	r.Handle("/v1/organizations/{id}", authenticate(deleteOrganization(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodDelete)
+	r.Handle("/v1/organizations/{id}/members", authenticate(getOrganizationMembers(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/organizations/{id}/members/{user_id}", authenticate(updateOrganizationMember(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPatch)
+	r.Handle("/v1/organizations/{id}/members/{user_id}", authenticate(removeOrganizationMember(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodDelete)
+	r.Handle("/v1/organizations/{id}/owner", authenticate(transferOrganizationOwnership(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPut)
+	r.Handle("/v1/organizations/{id}/invitations", authenticate(getOrganizationInvitations(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/organizations/{id}/invitations", authenticate(inviteOrganizationMember(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/organizations/{id}/invitations/accept", authenticate(acceptOrganizationInvitation(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/organizations/{id}/invitations/{user_id}", authenticate(removeOrganizationInvitation(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodDelete)
+}
+
+// @Summary Create organization
+// @Description Creates an organization, the caller being its owner
+// @Tags Organizations
+// @Security Bearer
+// @Param organization body models.CreateOrganizationReq true "Organization"
+// @Success 201 {object} models.CreationResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations [post]
+func createOrganization(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var req models.CreateOrganizationReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		response, err := s.Create(ctx, caller(r), req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, response)
+	})
+}
+
+// @Summary Get organizations
+// @Description Gets the organizations the caller is a member of, with its role in them, every organization for the admins
+// @Tags Organizations
+// @Security Bearer
+// @Success 200 {array} models.OrganizationResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations [get]
+func getOrganizations(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		response, err := s.GetAll(ctx, caller(r))
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Get organization by ID
+// @Description Gets an organization the caller is a member of
+// @Tags Organizations
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 {object} models.OrganizationResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/{id} [get]
+func getOrganizationByID(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.GetByID(ctx, caller(r), params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Update organization
+// @Description Updates an organization the caller is an admin of
+// @Tags Organizations
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param organization body models.UpdateOrganizationReq true "Organization"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/{id} [patch]
+func updateOrganization(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var req models.UpdateOrganizationReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		err = s.Update(ctx, caller(r), params["id"], req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
+
+// @Summary Delete organization
+// @Description Deletes an organization the caller is the owner of, along with its members
+// @Tags Organizations
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/{id} [delete]
+func deleteOrganization(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Delete(ctx, caller(r), params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Get organization members
+// @Description Gets the members of an organization the caller is a member of, with their users, so the users are listed by organization
+// @Tags Organizations
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 {array} models.OrganizationMemberResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/{id}/members [get]
+func getOrganizationMembers(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.GetMembers(ctx, caller(r), params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Update organization member
+// @Description Changes the role of a member of an organization the caller is an admin of, the role of the owner never changing
+// @Tags Organizations
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param user_id path string true "User ID"
+// @Param member body models.UpdateMemberReq true "Member"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 409 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/{id}/members/{user_id} [patch]
+func updateOrganizationMember(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var req models.UpdateMemberReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		err = s.UpdateMember(ctx, caller(r), params["id"], params["user_id"], req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
+
+// @Summary Remove organization member
+// @Description Removes a member from an organization the caller is an admin of, or the caller from an organization it leaves, the owner never being removed
+// @Tags Organizations
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param user_id path string true "User ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 409 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/{id}/members/{user_id} [delete]
+func removeOrganizationMember(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.RemoveMember(ctx, caller(r), params["id"], params["user_id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Transfer organization ownership
+// @Description Makes a member the owner of an organization the caller is the owner of, the caller becoming an admin of it
+// @Tags Organizations
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param owner body models.TransferOwnershipReq true "New owner"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 409 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/{id}/owner [put]
+func transferOrganizationOwnership(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var req models.TransferOwnershipReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		err = s.TransferOwnership(ctx, caller(r), params["id"], req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
+
+// @Summary Get caller invitations
+// @Description Gets the pending invitations of the caller to the organizations
+// @Tags Organizations
+// @Security Bearer
+// @Success 200 {array} models.OrganizationInvitationResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/invitations [get]
+func getCallerInvitations(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		response, err := s.GetCallerInvitations(ctx, caller(r))
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Get organization invitations
+// @Description Gets the pending invitations to an organization the caller is an admin of
+// @Tags Organizations
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 {array} models.OrganizationInvitationResp "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/{id}/invitations [get]
+func getOrganizationInvitations(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.GetInvitations(ctx, caller(r), params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Invite organization member
+// @Description Invites the user with the email to an organization the caller is an admin of, with the admin or member role,
+// @Description the user being a member once it accepts the invitation
+// @Tags Organizations
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param invitation body models.InviteMemberReq true "Invitation"
+// @Success 201 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 409 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/{id}/invitations [post]
+func inviteOrganizationMember(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var req models.InviteMemberReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		err = s.Invite(ctx, caller(r), params["id"], req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, nil)
+	})
+}
+
+// @Summary Accept organization invitation
+// @Description Makes the caller a member of an organization it is invited to, with the role of the invitation
+// @Tags Organizations
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/{id}/invitations/accept [post]
+func acceptOrganizationInvitation(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.AcceptInvitation(ctx, caller(r), params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Remove organization invitation
+// @Description Revokes the invitation of a user to an organization the caller is an admin of, or declines the invitation of the caller
+// @Tags Organizations
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param user_id path string true "User ID"
+// @Success 200 "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/organizations/{id}/invitations/{user_id} [delete]
+func removeOrganizationInvitation(ctx context.Context, cfg config.Config, s ports.OrganizationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.RemoveInvitation(ctx, caller(r), params["id"], params["user_id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
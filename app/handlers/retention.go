@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// SetRetentionRoutes creates retention routes
+func SetRetentionRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.RetentionService) {
+	r.Handle("/v1/retention", authenticate(applyRetention(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+}
+
+// @Summary Apply retention policies
+// @Description Purges or anonymizes the documents older than the limits of the retention policies, only reporting how many they are unless dry_run is false
+// @Tags Retention
+// @Security Bearer
+// @Param dry_run query bool false "Only report the documents, true by default"
+// @Success 200 {object} models.RetentionResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/retention [post]
+func applyRetention(ctx context.Context, cfg config.Config, s ports.RetentionService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		dryRun := true
+		if param := r.URL.Query().Get("dry_run"); param != "" {
+			var err error
+			dryRun, err = strconv.ParseBool(param)
+			if err != nil {
+				responseError(w, r, nil, wrappers.NewValidationErr(errors.New("dry_run must be a boolean")))
+				return
+			}
+		}
+
+		resp, err := s.Apply(ctx, dryRun)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, resp)
+	})
+}
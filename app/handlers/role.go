@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetRoleRoutes creates role routes, only for admins
+func SetRoleRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.RoleService) {
+	r.Handle("/v1/roles", authenticate(createRole(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/roles", authenticate(getRoles(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+	r.Handle("/v1/roles/{id}", authenticate(getRoleByID(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+	r.Handle("/v1/roles/{id}", authenticate(updateRole(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPatch)
+	r.Handle("/v1/roles/{id}", authenticate(deleteRole(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodDelete)
+	r.Handle("/v1/roles/{id}/users/{user_id}", authenticate(assignUserRole(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPut)
+	r.Handle("/v1/roles/{id}/users/{user_id}", authenticate(unassignUserRole(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodDelete)
+	r.Handle("/v1/roles/{id}/organizations/{organization_id}", authenticate(assignOrganizationRole(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPut)
+	r.Handle("/v1/roles/{id}/organizations/{organization_id}", authenticate(unassignOrganizationRole(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodDelete)
+}
+
+// @Summary Create role
+// @Description Creates a role, whose permissions are the names of the claims it grants
+// @Tags Roles
+// @Security Bearer
+// @Param role body models.CreateRoleReq true "Role"
+// @Success 201 {object} models.CreationResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 409 {object} object
+// @Failure 500 {object} object
+// @Router /v1/roles [post]
+func createRole(ctx context.Context, cfg config.Config, s ports.RoleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var req models.CreateRoleReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		response, err := s.Create(ctx, req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, response)
+	})
+}
+
+// @Summary Get roles
+// @Description Gets all the roles, with their assignees
+// @Tags Roles
+// @Security Bearer
+// @Success 200 {array} models.RoleResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/roles [get]
+func getRoles(ctx context.Context, cfg config.Config, s ports.RoleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		response, err := s.GetAll(ctx)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Get role by ID
+// @Description Gets a role, with its assignees
+// @Tags Roles
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 {object} models.RoleResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/roles/{id} [get]
+func getRoleByID(ctx context.Context, cfg config.Config, s ports.RoleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.GetByID(ctx, params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Update role
+// @Description Updates a role, its assignees being granted its new permissions once they log in again
+// @Tags Roles
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param role body models.UpdateRoleReq true "Role"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 409 {object} object
+// @Failure 500 {object} object
+// @Router /v1/roles/{id} [patch]
+func updateRole(ctx context.Context, cfg config.Config, s ports.RoleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var req models.UpdateRoleReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		err = s.Update(ctx, params["id"], req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
+
+// @Summary Delete role
+// @Description Deletes a role, unassigning it from everyone
+// @Tags Roles
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/roles/{id} [delete]
+func deleteRole(ctx context.Context, cfg config.Config, s ports.RoleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Delete(ctx, params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Assign role to user
+// @Description Assigns a role to a user, granting it the permissions of the role once it logs in again
+// @Tags Roles
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param user_id path string true "User ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/roles/{id}/users/{user_id} [put]
+func assignUserRole(ctx context.Context, cfg config.Config, s ports.RoleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Assign(ctx, params["id"], entities.RoleAssigneeUser, params["user_id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Unassign role from user
+// @Description Unassigns a role from a user
+// @Tags Roles
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param user_id path string true "User ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/roles/{id}/users/{user_id} [delete]
+func unassignUserRole(ctx context.Context, cfg config.Config, s ports.RoleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Unassign(ctx, params["id"], entities.RoleAssigneeUser, params["user_id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Assign role to organization
+// @Description Assigns a role to an organization, granting its members the permissions of the role once they log in again
+// @Tags Roles
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param organization_id path string true "Organization ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/roles/{id}/organizations/{organization_id} [put]
+func assignOrganizationRole(ctx context.Context, cfg config.Config, s ports.RoleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Assign(ctx, params["id"], entities.RoleAssigneeOrganization, params["organization_id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Unassign role from organization
+// @Description Unassigns a role from an organization
+// @Tags Roles
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param organization_id path string true "Organization ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/roles/{id}/organizations/{organization_id} [delete]
+func unassignOrganizationRole(ctx context.Context, cfg config.Config, s ports.RoleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Unassign(ctx, params["id"], entities.RoleAssigneeOrganization, params["organization_id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetSchedulerRoutes creates scheduler routes
+func SetSchedulerRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.Scheduler) {
+	r.Handle("/v1/scheduler/jobs", authenticate(getScheduledJobs(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+}
+
+// @Summary Get scheduled jobs
+// @Description Gets the jobs of the scheduler with their schedule, their next run and the result of their last run on the replica serving the request
+// @Tags Scheduler
+// @Security Bearer
+// @Success 200 {array} models.ScheduledJobResp "OK"
+// @Failure 401 {object} object
+// @Failure 500 {object} object
+// @Router /v1/scheduler/jobs [get]
+func getScheduledJobs(ctx context.Context, cfg config.Config, s ports.Scheduler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := s.GetJobs(requestContext(ctx, r))
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// defaultIndexSearchTake is the number of users returned when take is not specified
+const defaultIndexSearchTake = 20
+
+// SetSearchRoutes creates search routes
+func SetSearchRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.SearchService) {
+	r.Handle("/v1/users/search/index", authenticate(searchUsersIndex(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+}
+
+// @Summary Search users in the index
+// @Description Searches users by name, surnames or email in the search index, by relevance and tolerating typos,
+// @Description with the total count of matches and their counts by email domain and by claim
+// @Tags Users
+// @Security Bearer
+// @Param q query string true "Search text"
+// @Param domain query string false "Email domain"
+// @Param claim query int false "Claim"
+// @Param skip query int false "Users to skip"
+// @Param take query int false "Users to return, 20 by default"
+// @Success 200 {object} models.IndexSearchUsersResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/search/index [get]
+func searchUsersIndex(ctx context.Context, cfg config.Config, s ports.SearchService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		req := models.IndexSearchUsersReq{
+			Query:  r.URL.Query().Get("q"),
+			Domain: r.URL.Query().Get("domain"),
+			Take:   defaultIndexSearchTake,
+		}
+		for param, value := range map[string]*int{"skip": &req.Skip, "take": &req.Take} {
+			if r.URL.Query().Get(param) == "" {
+				continue
+			}
+			v, err := strconv.Atoi(r.URL.Query().Get(param))
+			if err != nil {
+				responseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("%s must be an integer", param)))
+				return
+			}
+			*value = v
+		}
+		if r.URL.Query().Get("claim") != "" {
+			claim, err := strconv.ParseInt(r.URL.Query().Get("claim"), 10, 64)
+			if err != nil {
+				responseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("claim must be an integer")))
+				return
+			}
+			req.Claim = &claim
+		}
+
+		resp, err := s.Search(ctx, caller(r), req)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, resp)
+	})
+}
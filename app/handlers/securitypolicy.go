@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetSecurityPolicyRoutes creates security policy routes, only for admins
+func SetSecurityPolicyRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.SecurityPolicyService) {
+	r.Handle("/v1/security-policies", authenticate(getSecurityPolicies(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+	r.Handle("/v1/security-policies/{tenant}", authenticate(getSecurityPolicy(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+	r.Handle("/v1/security-policies/{tenant}", authenticate(putSecurityPolicy(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPut)
+	r.Handle("/v1/security-policies/{tenant}", authenticate(deleteSecurityPolicy(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodDelete)
+}
+
+// @Summary Get security policies
+// @Description Gets the security policies of every tenant having one, sorted by tenant
+// @Tags SecurityPolicies
+// @Security Bearer
+// @Success 200 {array} models.SecurityPolicyResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/security-policies [get]
+func getSecurityPolicies(ctx context.Context, cfg config.Config, s ports.SecurityPolicyService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		response, err := s.GetAll(ctx)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Get security policy
+// @Description Gets the security policy of a tenant, the domain of the emails of its users
+// @Tags SecurityPolicies
+// @Security Bearer
+// @Param tenant path string true "Tenant, like example.com"
+// @Success 200 {object} models.SecurityPolicyResp "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/security-policies/{tenant} [get]
+func getSecurityPolicy(ctx context.Context, cfg config.Config, s ports.SecurityPolicyService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		response, err := s.Get(ctx, params["tenant"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Put security policy
+// @Description Sets the security policy of a tenant, replacing the one it has, applied to the logins and the passwords of its users from then on
+// @Tags SecurityPolicies
+// @Security Bearer
+// @Param tenant path string true "Tenant, like example.com"
+// @Param policy body models.PutSecurityPolicyReq true "Security policy"
+// @Success 200 {object} models.SecurityPolicyResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 409 {object} object
+// @Failure 500 {object} object
+// @Router /v1/security-policies/{tenant} [put]
+func putSecurityPolicy(ctx context.Context, cfg config.Config, s ports.SecurityPolicyService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var req models.PutSecurityPolicyReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		req.Tenant = params["tenant"]
+		response, err := s.Put(ctx, req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, response)
+	})
+}
+
+// @Summary Delete security policy
+// @Description Deletes the security policy of a tenant, its users being applied the settings of the config
+// @Tags SecurityPolicies
+// @Security Bearer
+// @Param tenant path string true "Tenant, like example.com"
+// @Success 200 "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/security-policies/{tenant} [delete]
+func deleteSecurityPolicy(ctx context.Context, cfg config.Config, s ports.SecurityPolicyService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Delete(ctx, params["tenant"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// SetSMSRoutes creates SMS routes, the status callback being authenticated by the signature of the provider
+func SetSMSRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.SMSService) {
+	r.Handle("/v1/sms/codes", authenticate(sendSMSCode(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/sms/codes/check", authenticate(checkSMSCode(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/sms/status", updateSMSStatus(ctx, cfg, s)).Methods(http.MethodPost)
+}
+
+// @Summary Send SMS code
+// @Description Sends a one-time code by text message to the phone
+// @Tags SMS
+// @Security Bearer
+// @Param code body models.SendSMSCodeReq true "Send code request"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/sms/codes [post]
+func sendSMSCode(ctx context.Context, cfg config.Config, s ports.SMSService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var req models.SendSMSCodeReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		err = s.SendCode(ctx, req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
+
+// @Summary Check SMS code
+// @Description Checks the one-time code sent by text message to the phone, each code being accepted once
+// @Tags SMS
+// @Security Bearer
+// @Param code body models.CheckSMSCodeReq true "Check code request"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/sms/codes/check [post]
+func checkSMSCode(ctx context.Context, cfg config.Config, s ports.SMSService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var req models.CheckSMSCodeReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		err = s.CheckCode(ctx, req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
+
+// @Summary Update SMS status
+// @Description Receives the delivery status of a text message, posted by the provider and signed in the X-Twilio-Signature header
+// @Tags SMS
+// @Accept x-www-form-urlencoded
+// @Param X-Twilio-Signature header string true "Signature of the provider"
+// @Param MessageSid formData string true "ID of the message"
+// @Param MessageStatus formData string true "Delivery status"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 500 {object} object
+// @Router /v1/sms/status [post]
+func updateSMSStatus(ctx context.Context, cfg config.Config, s ports.SMSService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		err := r.ParseForm()
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+
+		err = s.UpdateStatus(ctx, r.PostForm, r.Header.Get("X-Twilio-Signature"))
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
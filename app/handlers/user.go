@@ -3,61 +3,78 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/mux"
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
 	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
-	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
 	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
 )
 
 // SetUserRoutes creates user routes
-func SetUserRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.UserService) {
+func SetUserRoutes(ctx context.Context, cfg config.Config, r *mux.Router, keys ports.TokenKeys, s ports.UserService) {
 	r.Handle("/v1/users/login", loginUser(ctx, cfg, s)).Methods(http.MethodPost)
 	r.Handle("/v1/users", createUser(ctx, cfg, s)).Methods(http.MethodPost)
 	r.Handle("/v1/users/many", createManyUsers(ctx, cfg, s)).Methods(http.MethodPost)
-	r.Handle("/v1/users", middlewares.JWT(getAllUsers(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
-	r.Handle("/v1/users/email/{email}", middlewares.JWT(getUserByEmail(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
-	r.Handle("/v1/users/{id}", middlewares.JWT(getUserByID(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
-	r.Handle("/v1/users/{id}", middlewares.JWT(updateUser(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPatch)
-	r.Handle("/v1/users/{id}", middlewares.JWT(deleteUser(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodDelete)
-	r.Handle("/v1/claims", middlewares.JWT(getUserClaims(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/users", authenticate(getAllUsers(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/users/search", authenticate(searchUsers(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/users/nearby", authenticate(getNearbyUsers(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/users/email/{email}", authenticate(getUserByEmail(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/users/email/{email}", authenticate(upsertUser(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPut)
+	r.Handle("/v1/users/{id}", authenticate(getUserByID(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/users/{id}", authenticate(updateUser(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPatch)
+	r.Handle("/v1/users/{id}/merge", authenticate(mergeUsers(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/users/{id}/unarchive", authenticate(unarchiveUser(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/users/{id}/avatar", authenticate(uploadUserAvatar(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPut)
+	r.Handle("/v1/users/{id}/avatar", authenticate(getUserAvatar(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/users/{id}/avatar", authenticate(deleteUserAvatar(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodDelete)
+	r.Handle("/v1/users/{id}/avatar/url", authenticate(getUserAvatarURL(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/users/{id}/avatar/url", authenticate(createUserAvatarUploadURL(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/users/{id}", authenticate(deleteUser(ctx, cfg, s), keys, jwt.MapClaims{"admin": true})).Methods(http.MethodDelete)
+	r.Handle("/v1/claims", authenticate(getUserClaims(ctx, cfg, s), keys, jwt.MapClaims{})).Methods(http.MethodGet)
 }
 
 // @Summary Login user
-// @Description Logs in an user
+// @Description Logs in an user. When the security policy of its tenant requires MFA, a login without mfa_code emails a one-time code to the user
+// @Description and responds with a 401, the login being done again with the code.
 // @Tags Users
 // @Param login body models.LoginUserReq true "Login request"
 // @Success 200 {object} models.LoginUserResp "OK"
 // @Failure 400 {object} object
+// @Failure 401 {object} object
 // @Failure 408 {object} object
 // @Failure 500 {object} object
 // @Router /v1/users/login [post]
 func loginUser(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
-	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
 		defer cancel()
 
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
 			return
 		}
 
 		var credentials models.LoginUserReq
 		err = json.Unmarshal(body, &credentials)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
 			return
 		}
 
 		response, err := s.Login(ctx, credentials)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
 			return
 		}
 		utils.ResponseJSON(w, r, body, http.StatusOK, response)
@@ -71,29 +88,30 @@ func loginUser(ctx context.Context, cfg config.Config, s ports.UserService) http
 // @Success 201 {object} models.CreationResp "OK"
 // @Failure 400 {object} object
 // @Failure 408 {object} object
+// @Failure 409 {object} object
 // @Failure 500 {object} object
 // @Router /v1/users [post]
 func createUser(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
-	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
 		defer cancel()
 
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
 			return
 		}
 
 		var user models.CreateUserReq
 		err = json.Unmarshal(body, &user)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
 			return
 		}
 
 		result, err := s.Create(ctx, user)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
 			return
 		}
 		utils.ResponseJSON(w, r, body, http.StatusCreated, result)
@@ -107,29 +125,30 @@ func createUser(ctx context.Context, cfg config.Config, s ports.UserService) htt
 // @Success 201 {object} models.MultiCreationResp "OK"
 // @Failure 400 {object} object
 // @Failure 408 {object} object
+// @Failure 409 {object} object
 // @Failure 500 {object} object
 // @Router /v1/users/many [post]
 func createManyUsers(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
-	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
 		defer cancel()
 
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
 			return
 		}
 
 		var users []models.CreateUserReq
 		err = json.Unmarshal(body, &users)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
 			return
 		}
 
 		result, err := s.CreateMany(ctx, users)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
 			return
 		}
 		utils.ResponseJSON(w, r, body, http.StatusCreated, result)
@@ -137,7 +156,7 @@ func createManyUsers(ctx context.Context, cfg config.Config, s ports.UserService
 }
 
 // @Summary Get all users
-// @Description Gets all the users
+// @Description Gets the caller along with the members of its organizations, every user for the admins
 // @Tags Users
 // @Security Bearer
 // @Success 200 {array} models.UserResp "OK"
@@ -147,13 +166,87 @@ func createManyUsers(ctx context.Context, cfg config.Config, s ports.UserService
 // @Failure 500 {object} object
 // @Router /v1/users [get]
 func getAllUsers(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
-	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
 		defer cancel()
 
-		users, err := s.GetAll(ctx)
+		users, err := s.GetAll(ctx, caller(r))
 		if err != nil {
-			utils.ResponseError(w, r, nil, err)
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, users)
+	})
+}
+
+// @Summary Search users
+// @Description Searches users by name, surnames or email
+// @Tags Users
+// @Security Bearer
+// @Param q query string true "Search text"
+// @Param mode query string false "Search mode" Enums(fuzzy, autocomplete)
+// @Success 200 {array} models.UserResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/search [get]
+func searchUsers(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		req := models.SearchUsersReq{
+			Query: r.URL.Query().Get("q"),
+			Mode:  r.URL.Query().Get("mode"),
+		}
+
+		users, err := s.Search(ctx, caller(r), req)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, users)
+	})
+}
+
+// @Summary Get nearby users
+// @Description Gets the users located within a radius of a point, sorted by distance
+// @Tags Users
+// @Security Bearer
+// @Param lng query number true "Longitude"
+// @Param lat query number true "Latitude"
+// @Param radius query number true "Radius in meters"
+// @Success 200 {array} models.UserResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/nearby [get]
+func getNearbyUsers(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var req models.NearbyUsersReq
+		var msgs []string
+		for param, value := range map[string]*float64{"lng": &req.Longitude, "lat": &req.Latitude, "radius": &req.Radius} {
+			v, err := strconv.ParseFloat(r.URL.Query().Get(param), 64)
+			if err != nil {
+				msgs = append(msgs, fmt.Sprintf("%s must be a number", param))
+				continue
+			}
+			*value = v
+		}
+		if len(msgs) > 0 {
+			sort.Strings(msgs)
+			responseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | "))))
+			return
+		}
+
+		users, err := s.GetNearby(ctx, caller(r), req)
+		if err != nil {
+			responseError(w, r, nil, err)
 			return
 		}
 		utils.ResponseJSON(w, r, nil, http.StatusOK, users)
@@ -168,24 +261,71 @@ func getAllUsers(ctx context.Context, cfg config.Config, s ports.UserService) ht
 // @Success 200 {object} models.UserResp "OK"
 // @Failure 400 {object} object
 // @Failure 401 {object} object
+// @Failure 404 {object} object
 // @Failure 408 {object} object
 // @Failure 500 {object} object
 // @Router /v1/users/email/{email} [get]
 func getUserByEmail(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
-	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
 		defer cancel()
 
 		var params = mux.Vars(r)
 		user, err := s.GetByEmail(ctx, params["email"])
 		if err != nil {
-			utils.ResponseError(w, r, nil, err)
+			responseError(w, r, nil, err)
+			return
+		}
+		err = s.Authorize(ctx, caller(r), user.ID)
+		if err != nil {
+			responseError(w, r, nil, err)
 			return
 		}
 		utils.ResponseJSON(w, r, nil, http.StatusOK, user)
 	})
 }
 
+// @Summary Upsert user
+// @Description Creates or updates the user with the given email, for idempotent provisioning
+// @Tags Users
+// @Security Bearer
+// @Param email path string true "Email"
+// @Param user body models.UpsertUserReq true "User to be created or updated"
+// @Success 200 {object} models.UpsertionResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 409 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/email/{email} [put]
+func upsertUser(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var user models.UpsertUserReq
+		err = json.Unmarshal(body, &user)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		result, err := s.Upsert(ctx, params["email"], user)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, result)
+	})
+}
+
 // @Summary Get user by ID
 // @Description Gets a user by ID
 // @Tags Users
@@ -194,18 +334,25 @@ func getUserByEmail(ctx context.Context, cfg config.Config, s ports.UserService)
 // @Success 200 {object} models.UserResp "OK"
 // @Failure 400 {object} object
 // @Failure 401 {object} object
+// @Failure 404 {object} object
 // @Failure 408 {object} object
 // @Failure 500 {object} object
 // @Router /v1/users/{id} [get]
 func getUserByID(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
-	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
 		defer cancel()
 
 		var params = mux.Vars(r)
+		err := s.Authorize(ctx, caller(r), params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+
 		user, err := s.GetByID(ctx, params["id"])
 		if err != nil {
-			utils.ResponseError(w, r, nil, err)
+			responseError(w, r, nil, err)
 			return
 		}
 		utils.ResponseJSON(w, r, nil, http.StatusOK, user)
@@ -221,17 +368,19 @@ func getUserByID(ctx context.Context, cfg config.Config, s ports.UserService) ht
 // @Success 200 "OK"
 // @Failure 400 {object} object
 // @Failure 401 {object} object
+// @Failure 404 {object} object
 // @Failure 408 {object} object
+// @Failure 409 {object} object
 // @Failure 500 {object} object
 // @Router /v1/users/{id} [patch]
 func updateUser(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
-	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
 		defer cancel()
 
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
 			return
 		}
 
@@ -239,19 +388,294 @@ func updateUser(ctx context.Context, cfg config.Config, s ports.UserService) htt
 		var user models.UpdateUserReq
 		err = json.Unmarshal(body, &user)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
+			return
+		}
+
+		err = s.AuthorizeUpdate(ctx, caller(r), params["id"], user)
+		if err != nil {
+			responseError(w, r, body, err)
 			return
 		}
 
 		err = s.Update(ctx, params["id"], user)
 		if err != nil {
-			utils.ResponseError(w, r, body, err)
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
+
+// @Summary Merge users
+// @Description Merges the source user into the user with the given ID and deletes the source user, atomically
+// @Tags Users
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param merge body models.MergeUsersReq true "Merge request"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/merge [post]
+func mergeUsers(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var req models.MergeUsersReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		err = s.Merge(ctx, params["id"], req)
+		if err != nil {
+			responseError(w, r, body, err)
 			return
 		}
 		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
 	})
 }
 
+// @Summary Unarchive user
+// @Description Moves an archived user back to the active users
+// @Tags Users
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/unarchive [post]
+func unarchiveUser(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Unarchive(ctx, params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Upload user avatar
+// @Description Uploads the avatar image of a user, replacing the previous one. When the uploads are scanned, it is returned in quarantine, with its scan metadata pending, and replaces the previous one once found clean
+// @Tags Users
+// @Security Bearer
+// @Accept multipart/form-data
+// @Param id path string true "ID"
+// @Param file formData file true "Avatar image"
+// @Success 200 {object} models.FileResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/avatar [put]
+func uploadUserAvatar(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		r.Body = http.MaxBytesReader(w, r.Body, cfg.Storage.MaxUploadSize)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			responseError(w, r, nil, wrappers.NewValidationErr(err))
+			return
+		}
+		defer file.Close()
+
+		avatar := models.UploadAvatarReq{
+			Name:        header.Filename,
+			ContentType: header.Header.Get("Content-Type"),
+			Content:     file,
+		}
+
+		var params = mux.Vars(r)
+		err = s.AuthorizeUpdate(ctx, caller(r), params["id"], models.UpdateUserReq{})
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+
+		response, err := s.UploadAvatar(ctx, params["id"], avatar)
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Get user avatar
+// @Description Gets the avatar image of a user
+// @Tags Users
+// @Security Bearer
+// @Produce image/png,image/jpeg,image/gif
+// @Param id path string true "ID"
+// @Success 200 {file} file "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/avatar [get]
+func getUserAvatar(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Authorize(ctx, caller(r), params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+
+		content, file, err := s.GetAvatar(ctx, params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		defer content.Close()
+
+		w.Header().Set("Content-Type", file.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+		w.WriteHeader(http.StatusOK)
+		io.Copy(w, content)
+	})
+}
+
+// @Summary Delete user avatar
+// @Description Deletes the avatar image of a user
+// @Tags Users
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/avatar [delete]
+func deleteUserAvatar(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.AuthorizeUpdate(ctx, caller(r), params["id"], models.UpdateUserReq{})
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+
+		err = s.DeleteAvatar(ctx, params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Get user avatar URL
+// @Description Gets a presigned URL downloading the avatar image of a user directly from the file storage
+// @Tags Users
+// @Security Bearer
+// @Param id path string true "ID"
+// @Success 200 {object} models.PresignedURLResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/avatar/url [get]
+func getUserAvatarURL(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Authorize(ctx, caller(r), params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+
+		response, err := s.GetAvatarURL(ctx, params["id"])
+		if err != nil {
+			responseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, response)
+	})
+}
+
+// @Summary Create user avatar upload URL
+// @Description Creates a presigned URL uploading the avatar image of a user directly to the file storage, replacing the previous one, with the returned method and headers
+// @Tags Users
+// @Security Bearer
+// @Param id path string true "ID"
+// @Param avatar body models.AvatarUploadURLReq true "Avatar"
+// @Success 201 {object} models.PresignedURLResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/users/{id}/avatar/url [post]
+func createUserAvatarUploadURL(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var req models.AvatarUploadURLReq
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		err = s.AuthorizeUpdate(ctx, caller(r), params["id"], models.UpdateUserReq{})
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+
+		response, err := s.CreateAvatarUploadURL(ctx, params["id"], req)
+		if err != nil {
+			responseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, response)
+	})
+}
+
 // @Summary Delete user
 // @Description Delete a user
 // @Tags Users
@@ -260,18 +684,19 @@ func updateUser(ctx context.Context, cfg config.Config, s ports.UserService) htt
 // @Success 200 "OK"
 // @Failure 400 {object} object
 // @Failure 401 {object} object
+// @Failure 404 {object} object
 // @Failure 408 {object} object
 // @Failure 500 {object} object
 // @Router /v1/users/{id} [delete]
 func deleteUser(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
-	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
 		defer cancel()
 
 		var params = mux.Vars(r)
 		err := s.Delete(ctx, params["id"])
 		if err != nil {
-			utils.ResponseError(w, r, nil, err)
+			responseError(w, r, nil, err)
 			return
 		}
 		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
@@ -279,21 +704,35 @@ func deleteUser(ctx context.Context, cfg config.Config, s ports.UserService) htt
 }
 
 // @Summary Get claims
-// @Description Gets all claims
+// @Description Gets the claims of the users matching the filters, sorted by ID, with their name, description, category and whether they are deprecated
 // @Tags Users
 // @Security Bearer
-// @Success 200 {object} object "OK"
+// @Param category query string false "Category of the claims"
+// @Param deprecated query bool false "Whether the claims are deprecated"
+// @Success 200 {array} models.ClaimResp "OK"
 // @Failure 400 {object} object
 // @Failure 401 {object} object
 // @Failure 408 {object} object
 // @Failure 500 {object} object
 // @Router /v1/claims [get]
 func getUserClaims(ctx context.Context, cfg config.Config, s ports.UserService) http.Handler {
-	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(requestContext(ctx, r), cfg.Timeout.Duration)
 		defer cancel()
 
-		claims := s.GetUserClaims(ctx)
+		req := models.GetClaimsReq{
+			Category: r.URL.Query().Get("category"),
+		}
+		if param := r.URL.Query().Get("deprecated"); param != "" {
+			deprecated, err := strconv.ParseBool(param)
+			if err != nil {
+				responseError(w, r, nil, apierror.Validation("deprecated", "deprecated must be a boolean"))
+				return
+			}
+			req.Deprecated = &deprecated
+		}
+
+		claims := s.GetUserClaims(ctx, req)
 		utils.ResponseJSON(w, r, nil, http.StatusOK, claims)
 	})
 }
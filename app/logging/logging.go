@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/buildinfo"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// New creates the structured logger of the API, writing JSON lines to the standard output,
+// or human readable lines when the console output is enabled.
+// When a reporter is given, the errors logged are reported to it as well.
+// The secrets and personal data of every log line, like passwords, tokens and emails, are redacted before being written or reported.
+// Every log line has the version, commit and build date of the given build, so the logs of every build running in the fleet can be told apart.
+// Its level is set as the global level, so it can be changed with SetLevel while the API runs.
+func New(cfg config.Log, reporter ports.ErrorReporter, build buildinfo.Info) (zerolog.Logger, error) {
+	logger, err := newLogger(os.Stdout, cfg, reporter)
+	if err != nil {
+		return logger, err
+	}
+	zerolog.SetGlobalLevel(logger.GetLevel())
+	return logger.Level(zerolog.TraceLevel).With().Fields(build.Fields()).Logger(), nil
+}
+
+// SetLevel changes the level of the logger of the API, and of any other logger, to the given level name
+func SetLevel(name string) error {
+	level, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	zerolog.SetGlobalLevel(level)
+	return nil
+}
+
+func newLogger(w io.Writer, cfg config.Log, reporter ports.ErrorReporter) (zerolog.Logger, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return zerolog.Nop(), err
+	}
+
+	if cfg.Console {
+		w = zerolog.ConsoleWriter{Out: w, TimeFormat: time.RFC3339}
+	}
+	if reporter != nil {
+		w = zerolog.MultiLevelWriter(w, reportingWriter{reporter: reporter})
+	}
+	return zerolog.New(redactingWriter{w: w}).Level(level).With().Timestamp().Fields(podFields(os.LookupEnv)).Logger(), nil
+}
+
+// podMetadata environment variables set from the Kubernetes downward API, with the name of the log field of each of them
+var podMetadata = map[string]string{
+	"POD_NAME":      "pod",
+	"POD_NAMESPACE": "namespace",
+	"POD_IP":        "pod_ip",
+	"NODE_NAME":     "node",
+}
+
+// podFields returns the log fields of the pod metadata set in the environment, so the logs of every replica can be told apart
+func podFields(lookup func(key string) (string, bool)) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for key, field := range podMetadata {
+		if value, ok := lookup(key); ok && value != "" {
+			fields[field] = value
+		}
+	}
+	return fields
+}
+
+// ParseLevel parses a level name like info or debug, being info when empty
+func ParseLevel(name string) (zerolog.Level, error) {
+	if name == "" {
+		return zerolog.InfoLevel, nil
+	}
+	level, err := zerolog.ParseLevel(name)
+	if err != nil {
+		return zerolog.NoLevel, fmt.Errorf("log level %q not valid", name)
+	}
+	return level, nil
+}
@@ -0,0 +1,115 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewLogger_JSON checks that newLogger creates a logger writing JSON lines from the configured level
+func TestNewLogger_JSON(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	logger, err := newLogger(&buf, config.Log{Level: "warn"}, nil)
+	logger.Info().Msg("info message")
+	logger.Warn().Msg("warn message")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotContains(t, buf.String(), "info message")
+	assert.Contains(t, buf.String(), `"level":"warn"`)
+	assert.Contains(t, buf.String(), `"message":"warn message"`)
+}
+
+// TestNewLogger_Console checks that newLogger creates a logger writing human readable lines when the console output is enabled
+func TestNewLogger_Console(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	logger, err := newLogger(&buf, config.Log{Console: true}, nil)
+	logger.Info().Msg("info message")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "INF")
+	assert.Contains(t, buf.String(), "info message")
+	assert.NotContains(t, buf.String(), `"message"`)
+}
+
+// TestNewLogger_InvalidLevel checks that newLogger returns an error when the level is not valid
+func TestNewLogger_InvalidLevel(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	expectedError := `log level "verbose" not valid`
+
+	// Act
+	_, err := newLogger(&buf, config.Log{Level: "verbose"}, nil)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestSetLevel_Ok checks that SetLevel changes the level of the loggers
+func TestSetLevel_Ok(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	t.Cleanup(func() { zerolog.SetGlobalLevel(zerolog.TraceLevel) })
+
+	// Act
+	err := SetLevel("warn")
+	logger.Info().Msg("info message")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, buf.String())
+}
+
+// TestSetLevel_InvalidLevel checks that SetLevel returns an error and keeps the level when the level is not valid
+func TestSetLevel_InvalidLevel(t *testing.T) {
+	// Act
+	err := SetLevel("verbose")
+
+	// Assert
+	assert.Equal(t, `log level "verbose" not valid`, err.Error())
+	assert.Equal(t, zerolog.TraceLevel, zerolog.GlobalLevel())
+}
+
+// TestParseLevel_Empty checks that ParseLevel returns the info level when no level is specified
+func TestParseLevel_Empty(t *testing.T) {
+	// Act
+	level, err := ParseLevel("")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, zerolog.InfoLevel, level)
+}
+
+// TestPodFields_Ok checks that podFields returns the fields of the pod metadata set in the environment
+func TestPodFields_Ok(t *testing.T) {
+	// Arrange
+	env := map[string]string{
+		"POD_NAME":      "go-hexagonal-api-7d9f8-x2k4p",
+		"POD_NAMESPACE": "default",
+		"NODE_NAME":     "",
+	}
+	lookup := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	// Act
+	fields := podFields(lookup)
+
+	// Assert
+	assert.Equal(t, map[string]interface{}{
+		"pod":       "go-hexagonal-api-7d9f8-x2k4p",
+		"namespace": "default",
+	}, fields)
+}
@@ -0,0 +1,190 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"expvar"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header carrying the ID of a request, taken from the request when valid and always set in the response
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDRegex matches the request IDs accepted from the clients, so they cannot inject arbitrary content into the logs
+var requestIDRegex = regexp.MustCompile(`^[\w.-]{1,64}$`)
+
+// requests counts the served requests per status class, like 2xx or 5xx, exported as requests
+var requests = expvar.NewMap("requests")
+
+// maxErrorBodySize is the maximum size of the body of a server error response kept to log its error
+const maxErrorBodySize = 4096
+
+// statusRecorder records the status code written by a handler and, for server errors, the beginning of the body
+type statusRecorder struct {
+	http.ResponseWriter
+	status    int
+	errorBody []byte
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status >= http.StatusInternalServerError && len(r.errorBody) < maxErrorBodySize {
+		n := maxErrorBodySize - len(r.errorBody)
+		if n > len(b) {
+			n = len(b)
+		}
+		r.errorBody = append(r.errorBody, b[:n]...)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// responseError returns the error of a server error response, or the detail of a problem details one like the ones written for the recovered panics
+func (r *statusRecorder) responseError() string {
+	var body struct {
+		Error  string `json:"error"`
+		Detail string `json:"detail"`
+	}
+	if err := json.Unmarshal(r.errorBody, &body); err != nil {
+		return ""
+	}
+	if body.Error == "" {
+		return body.Detail
+	}
+	return body.Error
+}
+
+// Middleware logs every request once served with its method, path, status, latency, request ID, client IP and, when authenticated, user ID,
+// along with its trace ID when traced.
+// The request ID, user ID and client IP are also set in the request context as its request info, so the operations run for it can be attributed.
+// The client IP is the first address of the X-Forwarded-For header when trusting the proxy headers, or otherwise the remote address.
+// Requests are logged at the given level, except the ones failing with a server error, always logged as errors along with the error of the response,
+// so they are reported when the logger has an error reporter.
+// The user ID is only taken from tokens verified by the given keys.
+// Served requests are also counted per status class in the requests metrics.
+// The requests lasting at least the slow threshold are also logged as warnings with the time spent in their handler, in the database and hashing passwords,
+// timed through the request timings set in the request context. A zero threshold disables the slow request logging.
+// The requests not failing with a server error are only logged when sampled, while the server errors and the slow requests are always logged.
+func Middleware(logger zerolog.Logger, level zerolog.Level, keys ports.TokenKeys, trustProxyHeaders bool, slowThreshold time.Duration, sampler *Sampler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(RequestIDHeader)
+			if !requestIDRegex.MatchString(requestID) {
+				requestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			info := models.RequestInfo{
+				RequestID: requestID,
+				ActorID:   tokenUserID(r, keys),
+				IP:        ClientIP(r, trustProxyHeaders),
+				UserAgent: r.UserAgent(),
+			}
+			timings := &models.RequestTimings{}
+			r = r.WithContext(models.WithRequestTimings(models.WithRequestInfo(r.Context(), info), timings))
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			handlerStart := time.Now()
+			next.ServeHTTP(rec, r)
+			handlerTime := time.Since(handlerStart)
+
+			requests.Add(strconv.Itoa(rec.status/100)+"xx", 1)
+
+			var event *zerolog.Event
+			if rec.status >= http.StatusInternalServerError {
+				event = logger.Error()
+				if err := rec.responseError(); err != "" {
+					event = event.Str(zerolog.ErrorFieldName, err)
+				}
+			} else if sampler.Sampled(Route(r)) {
+				event = logger.WithLevel(level)
+			}
+
+			if event != nil {
+				event = event.
+					Str("method", r.Method).
+					Str("path", r.URL.Path).
+					Int("status", rec.status).
+					Dur("latency", time.Since(start)).
+					Str("request_id", requestID).
+					Str("ip", info.IP)
+				if info.ActorID != "" {
+					event = event.Str("user_id", info.ActorID)
+				}
+				if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+					event = event.Str("trace_id", sc.TraceID().String())
+				}
+				event.Msg("request served")
+			}
+
+			if latency := time.Since(start); slowThreshold > 0 && latency >= slowThreshold {
+				logger.Warn().
+					Str("method", r.Method).
+					Str("path", r.URL.Path).
+					Int("status", rec.status).
+					Str("request_id", requestID).
+					Dur("latency", latency).
+					Dur("handler", handlerTime).
+					Dur("database", timings.Database()).
+					Dur("hashing", timings.Hashing()).
+					Msg("slow request")
+			}
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// ClientIP returns the IP of the client of the request, taken from the X-Forwarded-For header only when trusting the proxy headers
+func ClientIP(r *http.Request, trustProxyHeaders bool) string {
+	if trustProxyHeaders {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			ip, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(ip)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// tokenUserID returns the user ID of the bearer token of the request, empty when there is no valid token
+func tokenUserID(r *http.Request, keys ports.TokenKeys) string {
+	bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if bearer == "" {
+		return ""
+	}
+
+	claims, err := keys.Parse(r.Context(), bearer)
+	if err != nil {
+		return ""
+	}
+
+	userID, _ := claims["user_id"].(string)
+	return userID
+}
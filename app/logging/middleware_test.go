@@ -0,0 +1,297 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/core/services"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// testKeys returns the keys verifying the tokens of the tests, signed with test-secret
+func testKeys() ports.TokenKeys {
+	var cfg config.Config
+	cfg.JWTSecret = "test-secret"
+	return services.NewKeyService(cfg, zerolog.Nop(), nil)
+}
+
+func serve(t *testing.T, buf *bytes.Buffer, level zerolog.Level, status int, req *http.Request) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+
+	handler := Middleware(zerolog.New(buf), level, testKeys(), false, 0, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	entry := map[string]interface{}{}
+	if buf.Len() > 0 {
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rr, entry
+}
+
+// TestMiddleware_Ok checks that Middleware logs the method, path, status, latency, request ID and user ID of a request at the given level
+func TestMiddleware_Ok(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "test-id"}).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://testing/v1/users/test-id", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(RequestIDHeader, "test-request-id")
+
+	// Act
+	rr, entry := serve(t, &buf, zerolog.InfoLevel, http.StatusCreated, req)
+
+	// Assert
+	assert.Equal(t, "test-request-id", rr.Header().Get(RequestIDHeader))
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, http.MethodGet, entry["method"])
+	assert.Equal(t, "/v1/users/test-id", entry["path"])
+	assert.Equal(t, float64(http.StatusCreated), entry["status"])
+	assert.Contains(t, entry, "latency")
+	assert.Equal(t, "test-request-id", entry["request_id"])
+	assert.Equal(t, "test-id", entry["user_id"])
+	assert.Equal(t, "192.0.2.1", entry["ip"])
+}
+
+// TestMiddleware_GeneratedRequestID checks that Middleware generates a request ID when the request does not carry a valid one
+func TestMiddleware_GeneratedRequestID(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	req := httptest.NewRequest(http.MethodGet, "http://testing/health", nil)
+	req.Header.Set(RequestIDHeader, "not valid\n")
+
+	// Act
+	rr, entry := serve(t, &buf, zerolog.InfoLevel, http.StatusOK, req)
+
+	// Assert
+	requestID := rr.Header().Get(RequestIDHeader)
+	assert.Len(t, requestID, 32)
+	assert.Equal(t, requestID, entry["request_id"])
+}
+
+// TestMiddleware_ServerError checks that Middleware logs the requests failing with a server error as errors, whatever the given level
+func TestMiddleware_ServerError(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	req := httptest.NewRequest(http.MethodGet, "http://testing/health", nil)
+
+	// Act
+	_, entry := serve(t, &buf, zerolog.DebugLevel, http.StatusInternalServerError, req)
+
+	// Assert
+	assert.Equal(t, "error", entry["level"])
+}
+
+// TestMiddleware_InvalidToken checks that Middleware does not log the user ID of a token not signed with the JWT secret
+func TestMiddleware_InvalidToken(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "test-id"}).SignedString([]byte("other-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://testing/v1/users", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	// Act
+	_, entry := serve(t, &buf, zerolog.InfoLevel, http.StatusUnauthorized, req)
+
+	// Assert
+	assert.NotContains(t, entry, "user_id")
+}
+
+// TestMiddleware_Traced checks that Middleware logs the trace ID of the traced requests
+func TestMiddleware_Traced(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "test-span")
+	req := httptest.NewRequest(http.MethodGet, "http://testing/health", nil).WithContext(ctx)
+
+	// Act
+	_, entry := serve(t, &buf, zerolog.InfoLevel, http.StatusOK, req)
+
+	// Assert
+	assert.Equal(t, span.SpanContext().TraceID().String(), entry["trace_id"])
+}
+
+// TestMiddleware_ServerErrorBody checks that Middleware logs the error of the responses failing with a server error
+func TestMiddleware_ServerErrorBody(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	handler := Middleware(zerolog.New(&buf), zerolog.InfoLevel, testKeys(), false, 0, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"test error"}`))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "http://testing/v1/users", nil)
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	entry := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "test error", entry["error"])
+}
+
+// TestMiddleware_ProblemDetail checks that Middleware logs the detail of the problem details responses failing with a server error
+func TestMiddleware_ProblemDetail(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	handler := Middleware(zerolog.New(&buf), zerolog.InfoLevel, testKeys(), false, 0, nil)(Recover(zerolog.Nop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("test panic")
+	})))
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://testing/v1/users", nil))
+
+	// Assert
+	entry := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, float64(http.StatusInternalServerError), entry["status"])
+	assert.Equal(t, "an unexpected error happened while serving the request", entry["error"])
+}
+
+// TestMiddleware_SlowRequest checks that Middleware logs the requests exceeding the slow threshold as warnings with their timings
+func TestMiddleware_SlowRequest(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	handler := Middleware(zerolog.New(&buf), zerolog.InfoLevel, testKeys(), false, time.Millisecond, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timings := models.RequestTimingsFrom(r.Context())
+		timings.AddDatabase(2 * time.Millisecond)
+		timings.AddHashing(3 * time.Millisecond)
+		time.Sleep(2 * time.Millisecond)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "http://testing/v1/users", nil)
+	req.Header.Set(RequestIDHeader, "test-request-id")
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+	entry := map[string]interface{}{}
+	if err := json.Unmarshal(lines[1], &entry); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "warn", entry["level"])
+	assert.Equal(t, "slow request", entry["message"])
+	assert.Equal(t, "/v1/users", entry["path"])
+	assert.Equal(t, "test-request-id", entry["request_id"])
+	assert.Equal(t, float64(2), entry["database"])
+	assert.Equal(t, float64(3), entry["hashing"])
+	assert.GreaterOrEqual(t, entry["handler"], float64(2))
+	assert.GreaterOrEqual(t, entry["latency"], entry["handler"])
+}
+
+// TestMiddleware_FastRequest checks that Middleware does not log the requests under the slow threshold as slow
+func TestMiddleware_FastRequest(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	handler := Middleware(zerolog.New(&buf), zerolog.InfoLevel, testKeys(), false, time.Minute, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://testing/v1/users", nil))
+
+	// Assert
+	assert.NotContains(t, buf.String(), "slow request")
+}
+
+// TestMiddleware_NotSampled checks that Middleware does not log the requests not sampled
+func TestMiddleware_NotSampled(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	sampler, err := NewSampler(0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := Middleware(zerolog.New(&buf), zerolog.InfoLevel, testKeys(), false, 0, sampler)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://testing/v1/users/login", nil))
+
+	// Assert
+	assert.Empty(t, buf.String())
+}
+
+// TestMiddleware_NotSampledServerError checks that Middleware logs the requests failing with a server error even when not sampled
+func TestMiddleware_NotSampledServerError(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	sampler, err := NewSampler(0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := Middleware(zerolog.New(&buf), zerolog.InfoLevel, testKeys(), false, 0, sampler)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://testing/v1/users/login", nil))
+
+	// Assert
+	assert.Contains(t, buf.String(), `"level":"error"`)
+	assert.Contains(t, buf.String(), "request served")
+}
+
+// TestMiddleware_RequestInfo checks that Middleware sets the request ID, user ID and client IP in the request context
+func TestMiddleware_RequestInfo(t *testing.T) {
+	// Arrange
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "test-id"}).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info models.RequestInfo
+	handler := Middleware(zerolog.Nop(), zerolog.InfoLevel, testKeys(), false, 0, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info = models.RequestInfoFrom(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "http://testing/v1/users", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(RequestIDHeader, "test-request-id")
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	assert.Equal(t, models.RequestInfo{RequestID: "test-request-id", ActorID: "test-id", IP: "192.0.2.1"}, info)
+}
+
+// TestClientIP_TrustProxyHeaders checks that ClientIP returns the first address of the X-Forwarded-For header when trusting the proxy headers
+func TestClientIP_TrustProxyHeaders(t *testing.T) {
+	// Arrange
+	req := httptest.NewRequest(http.MethodGet, "http://testing/health", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.1, 198.51.100.1")
+
+	// Act
+	ip := ClientIP(req, true)
+
+	// Assert
+	assert.Equal(t, "203.0.113.1", ip)
+}
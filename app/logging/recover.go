@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// problemContentType is the content type of the RFC 7807 problem details responses
+const problemContentType = "application/problem+json"
+
+// panics counts the panics recovered while serving requests, exported as panics
+var panics = expvar.NewInt("panics")
+
+// problem RFC 7807 problem details of a failed request
+type problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	Instance  string `json:"instance"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Recover recovers the panics of the handlers, responding with a 500 problem details response carrying the request ID
+// instead of dropping the request, logging them as errors along with their stack, method, path, request ID and user ID,
+// and counting them in the panics metric.
+// The panic value is only logged, as it can contain internal details.
+// It must run after the logging middleware to log the request info, and panics aborting the handler on purpose are not recovered.
+func Recover(logger zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				panics.Add(1)
+
+				info := models.RequestInfoFrom(r.Context())
+				event := logger.Error().
+					Str(zerolog.ErrorFieldName, fmt.Sprint(rec)).
+					Str("stack", string(debug.Stack())).
+					Str("method", r.Method).
+					Str("path", r.URL.Path).
+					Str("request_id", info.RequestID)
+				if info.ActorID != "" {
+					event = event.Str("user_id", info.ActorID)
+				}
+				event.Msg("panic recovered")
+
+				w.Header().Set("Content-Type", problemContentType)
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(problem{
+					Type:      "about:blank",
+					Title:     http.StatusText(http.StatusInternalServerError),
+					Status:    http.StatusInternalServerError,
+					Detail:    "an unexpected error happened while serving the request",
+					Instance:  r.URL.Path,
+					RequestID: info.RequestID,
+				})
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRecover_Panic checks that Recover responds with a problem details server error, logs the panic with its stack and request info and counts it
+func TestRecover_Panic(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	handler := Recover(zerolog.New(&buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v interface{} = "not a user"
+		_ = v.(*models.UserResp)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "http://testing/v1/users/test-id", nil)
+	req = req.WithContext(models.WithRequestInfo(req.Context(), models.RequestInfo{RequestID: "test-request-id", ActorID: "test-id"}))
+	rr := httptest.NewRecorder()
+	expectedPanics := panics.Value() + 1
+
+	// Act
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, problemContentType, rr.Header().Get("Content-Type"))
+	var body problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusInternalServerError, body.Status)
+	assert.Equal(t, "/v1/users/test-id", body.Instance)
+	assert.Equal(t, "test-request-id", body.RequestID)
+	assert.NotContains(t, body.Detail, "not a user")
+
+	entry := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "error", entry["level"])
+	assert.Contains(t, entry["error"], "interface conversion")
+	assert.Contains(t, entry["stack"], "runtime/debug.Stack")
+	assert.Equal(t, "test-request-id", entry["request_id"])
+	assert.Equal(t, "test-id", entry["user_id"])
+	assert.Equal(t, expectedPanics, panics.Value())
+}
+
+// TestRecover_NoPanic checks that Recover does not change the responses of the handlers not panicking
+func TestRecover_NoPanic(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	handler := Recover(zerolog.New(&buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "http://testing/v1/users", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Empty(t, buf.String())
+}
+
+// TestRecover_AbortHandler checks that Recover does not recover the panics aborting the handler on purpose
+func TestRecover_AbortHandler(t *testing.T) {
+	// Arrange
+	handler := Recover(zerolog.Nop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	// Act
+	var recovered interface{}
+	func() {
+		defer func() { recovered = recover() }()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://testing/v1/users", nil))
+	}()
+
+	// Assert
+	assert.Equal(t, http.ErrAbortHandler, recovered)
+}
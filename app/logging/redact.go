@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"io"
+	"regexp"
+
+	"github.com/rs/zerolog"
+)
+
+// RedactedValue replaces the values of the sensitive fields and the tokens found in the log lines
+const RedactedValue = "[REDACTED]"
+
+// sensitiveWords are the words making a field sensitive when its name contains them, like password, new_password or access_token
+const sensitiveWords = `(?:password|passwd|secret|token|authorization|cookie|api_?key|dsn)`
+
+// sensitiveNames are the names making a field sensitive only when it is named after them, like the one-time codes code and mfa_code,
+// since words like code are also part of names such as status_code
+const sensitiveNames = `(?:(?:mfa_?)?code)`
+
+var (
+	// sensitiveNameRegex matches the sensitive field names
+	sensitiveNameRegex = regexp.MustCompile(`(?i)` + sensitiveWords + `|^` + sensitiveNames + `$`)
+
+	// sensitiveFieldRegex matches the sensitive fields of a JSON log line along with their string, number or boolean value
+	sensitiveFieldRegex = regexp.MustCompile(`(?i)("(?:[^"\\]*` + sensitiveWords + `[^"\\]*|` + sensitiveNames + `)":\s*)("(?:[^"\\]|\\.)*"|-?\d[\d.eE+-]*|true|false)`)
+
+	// bearerRegex matches the bearer tokens, like in the Authorization headers
+	bearerRegex = regexp.MustCompile(`(?i)(bearer\s+)[\w.~+/=-]+`)
+
+	// jwtRegex matches the JSON web tokens found without their bearer scheme
+	jwtRegex = regexp.MustCompile(`eyJ[\w-]*\.[\w-]+\.[\w-]+`)
+
+	// emailRegex matches the email addresses, capturing the first character of the local part and the domain
+	emailRegex = regexp.MustCompile(`([A-Za-z0-9])[A-Za-z0-9._%+-]*(@[A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+)
+
+// redactingWriter redacts the secrets and the personal data of the JSON log lines before writing them, so it is enforced for every log line
+// regardless of the call site: the values of the sensitive fields and the tokens are replaced, and the emails are masked keeping their
+// first character and domain, like f***@example.com
+type redactingWriter struct {
+	w io.Writer
+}
+
+// Write implements the io.Writer interface
+func (w redactingWriter) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(Redact(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteLevel implements the zerolog.LevelWriter interface, keeping the level for the writers it is written to
+func (w redactingWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	lw, ok := w.w.(zerolog.LevelWriter)
+	if !ok {
+		return w.Write(p)
+	}
+	if _, err := lw.WriteLevel(level, Redact(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// IsSensitive returns whether the values of the field of the given name are redacted
+func IsSensitive(name string) bool {
+	return sensitiveNameRegex.MatchString(name)
+}
+
+// Redact returns the JSON document, like a log line, with its secrets and personal data redacted as the log lines are
+func Redact(p []byte) []byte {
+	p = sensitiveFieldRegex.ReplaceAll(p, []byte(`$1"`+RedactedValue+`"`))
+	p = bearerRegex.ReplaceAll(p, []byte("${1}"+RedactedValue))
+	p = jwtRegex.ReplaceAll(p, []byte(RedactedValue))
+	return emailRegex.ReplaceAll(p, []byte("$1***$2"))
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// fatalFlushTimeout is the maximum time given to deliver the reported errors before a fatal log exits
+const fatalFlushTimeout = 2 * time.Second
+
+// reportedFields are the fields of a log line not reported as tags, as they are part of the report itself
+var reportedFields = map[string]bool{
+	zerolog.LevelFieldName:     true,
+	zerolog.TimestampFieldName: true,
+	zerolog.MessageFieldName:   true,
+	zerolog.ErrorFieldName:     true,
+	"user_id":                  true,
+	"stack":                    true,
+}
+
+// reportingWriter reports the log lines of errors, with their message, error, user ID, stack and the rest of their fields as tags
+type reportingWriter struct {
+	reporter ports.ErrorReporter
+}
+
+// Write implements the io.Writer interface, the log lines without a level are not reported
+func (w reportingWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// WriteLevel implements the zerolog.LevelWriter interface
+func (w reportingWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < zerolog.ErrorLevel || level == zerolog.NoLevel {
+		return len(p), nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(p, &fields); err != nil {
+		return len(p), nil
+	}
+
+	report := ports.ErrorReport{Tags: make(map[string]string)}
+	report.Message, _ = fields[zerolog.MessageFieldName].(string)
+	report.Error, _ = fields[zerolog.ErrorFieldName].(string)
+	report.UserID, _ = fields["user_id"].(string)
+	report.Stack, _ = fields["stack"].(string)
+	for k, v := range fields {
+		if !reportedFields[k] {
+			report.Tags[k] = fmt.Sprint(v)
+		}
+	}
+	w.reporter.Report(report)
+
+	// the process exits right after a fatal log line is written
+	if level >= zerolog.FatalLevel {
+		w.reporter.Flush(fatalFlushTimeout)
+	}
+	return len(p), nil
+}
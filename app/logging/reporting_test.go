@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewLogger_Reporter checks that the logger reports the errors logged with their message, error, user ID, stack and the rest of their fields as tags
+func TestNewLogger_Reporter(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	expectedReport := ports.ErrorReport{
+		Message: "job failed",
+		Error:   "test error",
+		UserID:  "test-id",
+		Stack:   "test stack",
+		Tags:    map[string]string{"job_id": "test-job", "status": "500"},
+	}
+
+	reporterMock := mocks.NewErrorReporter(t)
+	reporterMock.On("Report", expectedReport).Once()
+
+	logger, err := newLogger(&buf, config.Log{}, reporterMock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	logger.Error().Err(errors.New("test error")).Str("user_id", "test-id").Str("stack", "test stack").Str("job_id", "test-job").Int("status", 500).Msg("job failed")
+
+	// Assert
+	assert.Contains(t, buf.String(), `"message":"job failed"`)
+}
+
+// TestNewLogger_ReporterNotError checks that the logger does not report the log lines under the error level
+func TestNewLogger_ReporterNotError(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	reporterMock := mocks.NewErrorReporter(t)
+
+	logger, err := newLogger(&buf, config.Log{}, reporterMock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	logger.Warn().Msg("slow query")
+
+	// Assert
+	reporterMock.AssertNotCalled(t, "Report", mock.Anything)
+}
+
+// TestReportingWriter_Fatal checks that the writer waits for the reported errors to be delivered when the log line is fatal, as the process exits right after
+func TestReportingWriter_Fatal(t *testing.T) {
+	// Arrange
+	reporterMock := mocks.NewErrorReporter(t)
+	reporterMock.On("Report", mock.AnythingOfType("ports.ErrorReport")).Once()
+	reporterMock.On("Flush", fatalFlushTimeout).Return(true).Once()
+	w := reportingWriter{reporter: reporterMock}
+
+	// Act
+	_, err := w.WriteLevel(zerolog.FatalLevel, []byte(`{"level":"fatal","message":"stopped"}`))
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gorilla/mux"
+)
+
+// Sampler decides which served requests are logged, with the ratio of their route, like POST /v1/users/login,
+// or with the default ratio for the other routes, so high traffic routes do not flood the logs.
+// Its ratios can be changed while the requests are served.
+// A nil Sampler logs every request.
+type Sampler struct {
+	ratios atomic.Pointer[sampleRatios]
+	random func() float64
+}
+
+// sampleRatios default and per route ratios of a sampler
+type sampleRatios struct {
+	ratio  float64
+	routes map[string]float64
+}
+
+// NewSampler creates a sampler logging the requests with the given ratios, from 0 to 1
+func NewSampler(ratio float64, routes map[string]float64) (*Sampler, error) {
+	s := &Sampler{
+		random: rand.Float64,
+	}
+	if err := s.Set(ratio, routes); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Set changes the ratios of the sampler, from 0 to 1, keeping the current ones when not valid
+func (s *Sampler) Set(ratio float64, routes map[string]float64) error {
+	if ratio < 0 || ratio > 1 {
+		return fmt.Errorf("sample ratio %v not valid, it must be between 0 and 1", ratio)
+	}
+	for route, r := range routes {
+		if r < 0 || r > 1 {
+			return fmt.Errorf("sample ratio %v of route %s not valid, it must be between 0 and 1", r, route)
+		}
+	}
+
+	s.ratios.Store(&sampleRatios{
+		ratio:  ratio,
+		routes: routes,
+	})
+	return nil
+}
+
+// Sampled returns whether a request of the given route is logged
+func (s *Sampler) Sampled(route string) bool {
+	if s == nil {
+		return true
+	}
+
+	ratios := s.ratios.Load()
+	ratio := ratios.ratio
+	if r, ok := ratios.routes[route]; ok {
+		ratio = r
+	}
+	return ratio >= 1 || s.random() < ratio
+}
+
+// Route returns the route of a request, its method and path template, like GET /v1/users/{id}, so that it does not vary with the IDs,
+// or only its method when it does not match any route
+func Route(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return r.Method + " " + template
+		}
+	}
+	return r.Method
+}
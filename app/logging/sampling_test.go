@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewSampler_InvalidRatio checks that NewSampler returns an error when the sample ratio is not between 0 and 1
+func TestNewSampler_InvalidRatio(t *testing.T) {
+	// Arrange
+	expectedError := "sample ratio 1.5 not valid, it must be between 0 and 1"
+
+	// Act
+	_, err := NewSampler(1.5, nil)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestNewSampler_InvalidRouteRatio checks that NewSampler returns an error when the sample ratio of a route is not between 0 and 1
+func TestNewSampler_InvalidRouteRatio(t *testing.T) {
+	// Arrange
+	expectedError := "sample ratio -1 of route POST /v1/users/login not valid, it must be between 0 and 1"
+
+	// Act
+	_, err := NewSampler(1, map[string]float64{"POST /v1/users/login": -1})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestSampled_Route checks that Sampled samples the requests of a route with its ratio and the other ones with the default ratio
+func TestSampled_Route(t *testing.T) {
+	// Arrange
+	sampler, err := NewSampler(0.5, map[string]float64{"POST /v1/users/login": 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sampler.random = func() float64 { return 0.3 }
+
+	// Act
+	route := sampler.Sampled("POST /v1/users/login")
+	other := sampler.Sampled("GET /v1/users")
+
+	// Assert
+	assert.False(t, route)
+	assert.True(t, other)
+}
+
+// TestSet_Ok checks that Set changes the ratios the requests are sampled with
+func TestSet_Ok(t *testing.T) {
+	// Arrange
+	sampler, err := NewSampler(1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sampler.random = func() float64 { return 0.3 }
+
+	// Act
+	err = sampler.Set(0.1, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, sampler.Sampled("GET /v1/users"))
+}
+
+// TestSet_InvalidRatio checks that Set returns an error and keeps the current ratios when the new ones are not valid
+func TestSet_InvalidRatio(t *testing.T) {
+	// Arrange
+	sampler, err := NewSampler(1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sampler.random = func() float64 { return 0.3 }
+	expectedError := "sample ratio 2 of route GET /v1/users not valid, it must be between 0 and 1"
+
+	// Act
+	err = sampler.Set(0.1, map[string]float64{"GET /v1/users": 2})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.True(t, sampler.Sampled("GET /v1/users"))
+}
+
+// TestSampled_Nil checks that a nil sampler samples every request
+func TestSampled_Nil(t *testing.T) {
+	// Arrange
+	var sampler *Sampler
+
+	// Act
+	sampled := sampler.Sampled("POST /v1/users/login")
+
+	// Assert
+	assert.True(t, sampled)
+}
+
+// TestRoute_Ok checks that Route returns the method and path template of the route matched by the request
+func TestRoute_Ok(t *testing.T) {
+	// Arrange
+	var route string
+	r := mux.NewRouter()
+	r.HandleFunc("/v1/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		route = Route(r)
+	})
+
+	// Act
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://testing/v1/users/test-id", nil))
+
+	// Assert
+	assert.Equal(t, "GET /v1/users/{id}", route)
+}
+
+// TestRoute_NotMatched checks that Route returns only the method of a request not matching any route
+func TestRoute_NotMatched(t *testing.T) {
+	// Act
+	route := Route(httptest.NewRequest(http.MethodGet, "http://testing/unknown", nil))
+
+	// Assert
+	assert.Equal(t, http.MethodGet, route)
+}
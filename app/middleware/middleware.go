@@ -0,0 +1,122 @@
+// Package middleware composes the middlewares the requests run through from their names in the config,
+// in a chain for every route and in chains of their own for the route groups under a path prefix.
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// The names of the middlewares of the API
+const (
+	Tracing      = "tracing"
+	Logging      = "logging"
+	AccessLog    = "accesslog"
+	RateLimit    = "ratelimit"
+	Subscription = "subscription"
+	Capture      = "capture"
+	Recover      = "recover"
+	CORS         = "cors"
+	Compress     = "compress"
+	Auth         = "auth"
+)
+
+// Names the names of the middlewares that can be chained
+var Names = []string{Tracing, Logging, AccessLog, RateLimit, Subscription, Capture, Recover, CORS, Compress, Auth}
+
+// DefaultChain the chain of the requests when none is set in the config, the middlewares of the API in the order they always ran
+var DefaultChain = []string{Tracing, Logging, AccessLog, RateLimit, Subscription, Capture, Recover}
+
+// Middleware wraps a handler
+type Middleware func(http.Handler) http.Handler
+
+// Registry the middlewares by name
+type Registry struct {
+	middlewares map[string]Middleware
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		middlewares: map[string]Middleware{},
+	}
+}
+
+// Register registers the middleware under the name. A nil middleware, like one disabled in the config, is skipped in the chains.
+func (r *Registry) Register(name string, m Middleware) {
+	r.middlewares[name] = m
+}
+
+// Chain returns a middleware running the named ones in order, the first one being the outermost,
+// failing with a name not registered
+func (r *Registry) Chain(names []string) (Middleware, error) {
+	var chain []Middleware
+	for _, name := range names {
+		m, ok := r.middlewares[name]
+		if !ok {
+			return nil, fmt.Errorf("middleware %q not registered", name)
+		}
+		if m != nil {
+			chain = append(chain, m)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		for i := len(chain) - 1; i >= 0; i-- {
+			next = chain[i](next)
+		}
+		return next
+	}, nil
+}
+
+// group the chain of the routes under a path prefix
+type group struct {
+	prefix string
+	chain  Middleware
+}
+
+// Groups returns a middleware running the requests through the chain of the group whose path prefix is the longest one
+// of the path, matched on whole path segments so /v1/admin is not a prefix of /v1/administrators, or through the default chain when none is
+func (r *Registry) Groups(defaultChain []string, groups map[string][]string) (Middleware, error) {
+	fallback, err := r.Chain(defaultChain)
+	if err != nil {
+		return nil, err
+	}
+	var chains []group
+	for prefix, names := range groups {
+		chain, err := r.Chain(names)
+		if err != nil {
+			return nil, fmt.Errorf("group %s: %w", prefix, err)
+		}
+		chains = append(chains, group{prefix: prefix, chain: chain})
+	}
+	sort.Slice(chains, func(i, j int) bool { return len(chains[i].prefix) > len(chains[j].prefix) })
+
+	return func(next http.Handler) http.Handler {
+		handlers := make([]http.Handler, len(chains))
+		for i, g := range chains {
+			handlers[i] = g.chain(next)
+		}
+		handler := fallback(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for i, g := range chains {
+				if hasPathPrefix(r.URL.Path, g.prefix) {
+					handlers[i].ServeHTTP(w, r)
+					return
+				}
+			}
+			handler.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// hasPathPrefix reports whether the path is the prefix or is under it, the prefix ending a segment of the path
+func hasPathPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
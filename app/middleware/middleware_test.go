@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tagging returns a middleware appending the tag to the X-Chain header of the response, before running the next handler
+func tagging(tag string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Chain", tag)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// okHandler responds with a 200
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+// TestChain_Order checks that Chain runs the middlewares in order, the first one being the outermost, skipping the nil ones
+func TestChain_Order(t *testing.T) {
+	// Arrange
+	registry := NewRegistry()
+	registry.Register(Logging, tagging("logging"))
+	registry.Register(RateLimit, nil)
+	registry.Register(Recover, tagging("recover"))
+	rr := httptest.NewRecorder()
+
+	// Act
+	chain, err := registry.Chain([]string{Recover, RateLimit, Logging})
+
+	// Assert
+	assert.Nil(t, err)
+	chain(okHandler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/users", nil))
+	assert.Equal(t, []string{"recover", "logging"}, rr.Header().Values("X-Chain"))
+}
+
+// TestChain_NotRegistered checks that Chain fails with a middleware not registered
+func TestChain_NotRegistered(t *testing.T) {
+	// Arrange
+	registry := NewRegistry()
+
+	// Act
+	_, err := registry.Chain([]string{CORS})
+
+	// Assert
+	assert.EqualError(t, err, `middleware "cors" not registered`)
+}
+
+// TestGroups_LongestPrefix checks that Groups runs the requests through the chain of the longest path prefix of the path
+func TestGroups_LongestPrefix(t *testing.T) {
+	// Arrange
+	registry := NewRegistry()
+	registry.Register(Logging, tagging("logging"))
+	registry.Register(Auth, tagging("auth"))
+	registry.Register(CORS, tagging("cors"))
+	groups, err := registry.Groups([]string{Logging}, map[string][]string{
+		"/v1":       {Logging, CORS},
+		"/v1/admin": {Logging, Auth},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+
+	// Act
+	groups(okHandler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/admin/jobs", nil))
+
+	// Assert
+	assert.Equal(t, []string{"logging", "auth"}, rr.Header().Values("X-Chain"))
+}
+
+// TestGroups_Default checks that Groups runs the requests not under any group through the default chain
+func TestGroups_Default(t *testing.T) {
+	// Arrange
+	registry := NewRegistry()
+	registry.Register(Logging, tagging("logging"))
+	registry.Register(Auth, tagging("auth"))
+	groups, err := registry.Groups([]string{Logging}, map[string][]string{
+		"/v1/admin": {Auth},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+
+	// Act
+	groups(okHandler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	// Assert
+	assert.Equal(t, []string{"logging"}, rr.Header().Values("X-Chain"))
+}
+
+// TestGroups_PathSegments checks that Groups matches the path prefixes on whole path segments, the prefix itself included
+func TestGroups_PathSegments(t *testing.T) {
+	// Arrange
+	registry := NewRegistry()
+	registry.Register(Logging, tagging("logging"))
+	registry.Register(Auth, tagging("auth"))
+	groups, err := registry.Groups([]string{Logging}, map[string][]string{
+		"/v1/admin": {Auth},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := httptest.NewRecorder()
+	prefix := httptest.NewRecorder()
+
+	// Act
+	groups(okHandler).ServeHTTP(other, httptest.NewRequest(http.MethodGet, "/v1/administrators", nil))
+	groups(okHandler).ServeHTTP(prefix, httptest.NewRequest(http.MethodGet, "/v1/admin", nil))
+
+	// Assert
+	assert.Equal(t, []string{"logging"}, other.Header().Values("X-Chain"))
+	assert.Equal(t, []string{"auth"}, prefix.Header().Values("X-Chain"))
+}
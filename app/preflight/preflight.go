@@ -0,0 +1,76 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Check of a dependency verified at startup, returning a detail of the dependency reported in the summary, like its version.
+// A required check failing stops the API from starting, while the other ones are only reported.
+type Check struct {
+	Name     string
+	Required bool
+	Run      func(ctx context.Context) (string, error)
+}
+
+// Result of a check
+type Result struct {
+	Name     string
+	Required bool
+	Detail   string
+	Err      error
+	Elapsed  time.Duration
+}
+
+// Run runs the checks one after the other, each one given at most timeout, and logs a line per check followed by the summary,
+// returning an error naming the failed required checks, if any
+func Run(ctx context.Context, logger zerolog.Logger, timeout time.Duration, checks []Check) ([]Result, error) {
+	results := make([]Result, 0, len(checks))
+	var failed []string
+	passed := 0
+	for _, check := range checks {
+		result := run(ctx, timeout, check)
+		results = append(results, result)
+
+		event := logger.Info()
+		switch {
+		case result.Err == nil:
+			passed++
+		case result.Required:
+			event = logger.Error()
+			failed = append(failed, fmt.Sprintf("%s: %s", result.Name, result.Err))
+		default:
+			event = logger.Warn()
+		}
+		event.Str("check", result.Name).Bool("required", result.Required).Str("detail", result.Detail).Err(result.Err).Dur("elapsed", result.Elapsed).Msg("preflight check")
+	}
+
+	logger.Info().Int("passed", passed).Int("failed", len(results)-passed).Int("required_failed", len(failed)).Msg("preflight checks finished")
+	if len(failed) > 0 {
+		return results, fmt.Errorf("preflight checks failed: %s", strings.Join(failed, "; "))
+	}
+	return results, nil
+}
+
+// run runs the check given at most timeout, zero meaning that only the context bounds it
+func run(ctx context.Context, timeout time.Duration, check Check) Result {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	detail, err := check.Run(ctx)
+	return Result{
+		Name:     check.Name,
+		Required: check.Required,
+		Detail:   detail,
+		Err:      err,
+		Elapsed:  time.Since(start),
+	}
+}
@@ -0,0 +1,82 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRun_Ok checks that Run returns the result of every check, in their order, when all of them pass
+func TestRun_Ok(t *testing.T) {
+	// Arrange
+	checks := []Check{
+		{Name: "database", Required: true, Run: func(ctx context.Context) (string, error) { return "version 6.0.5", nil }},
+		{Name: "smtp", Run: func(ctx context.Context) (string, error) { return "starttls, authenticated", nil }},
+	}
+
+	// Act
+	results, err := Run(context.Background(), zerolog.Nop(), time.Second, checks)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "database", results[0].Name)
+	assert.Equal(t, "version 6.0.5", results[0].Detail)
+	assert.Equal(t, "smtp", results[1].Name)
+	assert.Nil(t, results[1].Err)
+}
+
+// TestRun_RequiredFailed checks that Run runs every check and returns an error naming the failed required ones
+func TestRun_RequiredFailed(t *testing.T) {
+	// Arrange
+	ran := false
+	checks := []Check{
+		{Name: "database", Required: true, Run: func(ctx context.Context) (string, error) { return "", errors.New("standalone server not supported") }},
+		{Name: "redis", Required: true, Run: func(ctx context.Context) (string, error) { ran = true; return "version 7.2.0", nil }},
+	}
+
+	// Act
+	results, err := Run(context.Background(), zerolog.Nop(), time.Second, checks)
+
+	// Assert
+	assert.Equal(t, "preflight checks failed: database: standalone server not supported", err.Error())
+	assert.Len(t, results, 2)
+	assert.True(t, ran)
+}
+
+// TestRun_OptionalFailed checks that Run does not return an error when only the checks not required fail
+func TestRun_OptionalFailed(t *testing.T) {
+	// Arrange
+	checks := []Check{
+		{Name: "smtp", Run: func(ctx context.Context) (string, error) { return "plain", errors.New("535 authentication failed") }},
+	}
+
+	// Act
+	results, err := Run(context.Background(), zerolog.Nop(), time.Second, checks)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "535 authentication failed", results[0].Err.Error())
+}
+
+// TestRun_Timeout checks that Run gives every check at most the timeout
+func TestRun_Timeout(t *testing.T) {
+	// Arrange
+	checks := []Check{
+		{Name: "redis", Required: true, Run: func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}},
+	}
+
+	// Act
+	results, err := Run(context.Background(), zerolog.Nop(), 10*time.Millisecond, checks)
+
+	// Assert
+	assert.ErrorContains(t, err, "redis: context deadline exceeded")
+	assert.ErrorIs(t, results[0].Err, context.DeadlineExceeded)
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/logging"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// limitedPrefix prefixes the routes of the API that are rate limited, so the probes, the metrics and the docs never are
+const limitedPrefix = "/v1/"
+
+// keyPrefix prefixes the keys of the requests of every client in the limit store
+const keyPrefix = "ip:"
+
+// Limit the requests allowed to every client per window, which can be changed while the requests are served
+type Limit struct {
+	rate atomic.Pointer[rate]
+}
+
+// rate requests per window of a Limit
+type rate struct {
+	requests int64
+	window   time.Duration
+}
+
+// NewLimit creates a limit of the given requests per window
+func NewLimit(requests int64, window time.Duration) (*Limit, error) {
+	l := &Limit{}
+	if err := l.Set(requests, window); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Set changes the requests per window of the limit, keeping the current ones when not valid
+func (l *Limit) Set(requests int64, window time.Duration) error {
+	if err := Validate(requests, window); err != nil {
+		return err
+	}
+	l.rate.Store(&rate{requests: requests, window: window})
+	return nil
+}
+
+// Validate checks that the requests per window of a limit are valid
+func Validate(requests int64, window time.Duration) error {
+	if requests <= 0 {
+		return fmt.Errorf("rate limit requests %d not valid, it must be greater than 0", requests)
+	}
+	if window <= 0 {
+		return fmt.Errorf("rate limit window %s not valid, it must be greater than 0", window)
+	}
+	return nil
+}
+
+// Middleware limits the requests of every client IP, taken from the X-Forwarded-For header only when trusting the proxy headers as the logging middleware does,
+// to the requests per window of the limit, responding to the exceeding ones with a 429 and the seconds to wait in the Retry-After header.
+// The IP is not read from the request info, so the limit holds wherever the middleware is in the chain, with or without the logging one.
+// The requests are counted in the limit store, so the limit is enforced across every replica of the API.
+// When the store fails the requests are served anyway, as the limit does not protect the API from its own dependencies.
+func Middleware(store ports.LimitStore, logger zerolog.Logger, trustProxyHeaders bool, l *Limit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, limitedPrefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rate := l.rate.Load()
+			requests := rate.requests
+			limit, err := store.Hit(r.Context(), keyPrefix+logging.ClientIP(r, trustProxyHeaders), rate.window)
+			if err != nil {
+				logger.Warn().Err(err).Msg("request rate cannot be limited")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			remaining := requests - limit.Count
+			if remaining < 0 {
+				remaining = 0
+			}
+			w.Header().Set("X-RateLimit-Limit", strconv.FormatInt(requests, 10))
+			w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+			if limit.Count > requests {
+				retryAfter := int64(time.Until(limit.ExpiresAt).Seconds()) + 1
+				w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+				utils.ResponseJSON(w, r, nil, http.StatusTooManyRequests, map[string]string{"error": "too many requests, try again later"})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRequest creates a request to the given path from the given client IP
+func newRequest(path, ip string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	r.RemoteAddr = ip + ":1234"
+	return r
+}
+
+// testLimit creates a limit of the given requests per window
+func testLimit(t *testing.T, requests int64, window time.Duration) *Limit {
+	l, err := NewLimit(requests, window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+// okHandler responds with a 200
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+// TestMiddleware_Allowed checks that Middleware serves the requests within the limit, reporting the remaining ones
+func TestMiddleware_Allowed(t *testing.T) {
+	// Arrange
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "ip:10.0.0.1", time.Minute).Return(entities.Limit{Count: 3, ExpiresAt: time.Now().Add(time.Minute)}, nil).Once()
+
+	handler := Middleware(limitStoreMock, zerolog.Nop(), false, testLimit(t, 10, time.Minute))(okHandler)
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, newRequest("/v1/users", "10.0.0.1"))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "10", rr.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "7", rr.Header().Get("X-RateLimit-Remaining"))
+}
+
+// TestMiddleware_TrustProxyHeaders checks that Middleware counts the requests of the client of the X-Forwarded-For header when trusting the proxy headers,
+// without the request info of the logging middleware
+func TestMiddleware_TrustProxyHeaders(t *testing.T) {
+	// Arrange
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "ip:203.0.113.1", time.Minute).Return(entities.Limit{Count: 1, ExpiresAt: time.Now().Add(time.Minute)}, nil).Once()
+
+	handler := Middleware(limitStoreMock, zerolog.Nop(), true, testLimit(t, 10, time.Minute))(okHandler)
+	req := newRequest("/v1/users", "10.0.0.1")
+	req.Header.Set("X-Forwarded-For", "203.0.113.1, 10.0.0.1")
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	limitStoreMock.AssertExpectations(t)
+}
+
+// TestMiddleware_Limited checks that Middleware responds to the requests exceeding the limit with a 429 and the seconds to wait
+func TestMiddleware_Limited(t *testing.T) {
+	// Arrange
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "ip:10.0.0.1", time.Minute).Return(entities.Limit{Count: 11, ExpiresAt: time.Now().Add(30 * time.Second)}, nil).Once()
+
+	handler := Middleware(limitStoreMock, zerolog.Nop(), false, testLimit(t, 10, time.Minute))(okHandler)
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, newRequest("/v1/users", "10.0.0.1"))
+
+	// Assert
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+}
+
+// TestMiddleware_NotLimitedRoute checks that Middleware does not count the requests out of the versioned routes, like the probes
+func TestMiddleware_NotLimitedRoute(t *testing.T) {
+	// Arrange
+	handler := Middleware(mocks.NewLimitStore(t), zerolog.Nop(), false, testLimit(t, 10, time.Minute))(okHandler)
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, newRequest("/readyz", "10.0.0.1"))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+// TestMiddleware_StoreError checks that Middleware serves the requests when the limit store fails
+func TestMiddleware_StoreError(t *testing.T) {
+	// Arrange
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "ip:10.0.0.1", time.Minute).Return(entities.Limit{}, errors.New("store error")).Once()
+
+	handler := Middleware(limitStoreMock, zerolog.Nop(), false, testLimit(t, 10, time.Minute))(okHandler)
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, newRequest("/v1/users", "10.0.0.1"))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+// TestLimit_Set checks that Middleware limits the requests to the requests per window set while it serves them,
+// keeping the current ones when the new ones are not valid
+func TestLimit_Set(t *testing.T) {
+	// Arrange
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "ip:10.0.0.1", time.Hour).Return(entities.Limit{Count: 3, ExpiresAt: time.Now().Add(time.Hour)}, nil).Once()
+
+	limit := testLimit(t, 10, time.Minute)
+	handler := Middleware(limitStoreMock, zerolog.Nop(), false, limit)(okHandler)
+	rr := httptest.NewRecorder()
+
+	// Act
+	setErr := limit.Set(2, time.Hour)
+	invalidErr := limit.Set(0, time.Minute)
+	handler.ServeHTTP(rr, newRequest("/v1/users", "10.0.0.1"))
+
+	// Assert
+	assert.Nil(t, setErr)
+	assert.EqualError(t, invalidErr, "rate limit requests 0 not valid, it must be greater than 0")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("X-RateLimit-Limit"))
+}
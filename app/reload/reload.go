@@ -0,0 +1,141 @@
+package reload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// Reloader reloads the config while the API runs, applying its reloadable settings, like the log level,
+// and recording every change in the audit log, the ones of the other settings being logged as applied on restart
+type Reloader struct {
+	mu      sync.Mutex
+	current config.Config
+	read    func() (config.Config, error)
+	apply   func(cfg config.Config) error
+	audit   ports.AuditService
+	logger  zerolog.Logger
+}
+
+// New creates a reloader of the given running config, reading the new one with the read function and applying its reloadable settings with the apply function
+func New(cfg config.Config, read func() (config.Config, error), apply func(cfg config.Config) error, audit ports.AuditService, logger zerolog.Logger) *Reloader {
+	return &Reloader{
+		current: cfg,
+		read:    read,
+		apply:   apply,
+		audit:   audit,
+		logger:  logger,
+	}
+}
+
+// Reload reads the config again and, when any setting changed, applies the new one and records the changes in the audit log,
+// with their new values redacted when secret.
+// Nothing is applied when the new config cannot be read or applied, so the running one is kept.
+func (r *Reloader) Reload(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := r.read()
+	if err != nil {
+		return err
+	}
+
+	changes := config.Changes(r.current, cfg)
+	if len(changes) == 0 {
+		r.logger.Debug().Msg("config unchanged")
+		return nil
+	}
+
+	if err := r.apply(cfg); err != nil {
+		return fmt.Errorf("config not valid: %w", err)
+	}
+	r.current = cfg
+
+	var applied, restart []string
+	details := make(map[string]string, len(changes)+1)
+	for _, c := range changes {
+		details[c.Setting] = c.Value
+		if c.Reloadable {
+			applied = append(applied, c.Setting)
+		} else {
+			restart = append(restart, c.Setting)
+		}
+	}
+	if len(restart) > 0 {
+		details["restart_required"] = strings.Join(restart, ",")
+		r.logger.Warn().Strs("settings", restart).Msg("settings changed, restart to apply them")
+	}
+	if err := r.audit.Record(ctx, entities.AuditConfigReloaded, details); err != nil {
+		r.logger.Error().Err(err).Str("type", entities.AuditConfigReloaded).Msg("audit event cannot be written")
+	}
+	r.logger.Info().Strs("settings", applied).Msg("config reloaded")
+	return nil
+}
+
+// Run reloads the config on SIGHUP and, with a non zero interval, when any of the config files is modified, checked every interval,
+// until the context is done, logging the failures
+func (r *Reloader) Run(ctx context.Context, files []string, interval time.Duration) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	r.watch(ctx, signals, files, interval)
+}
+
+// watch reloads the config on every received signal and whenever the modification time of any of the files changes
+func (r *Reloader) watch(ctx context.Context, signals <-chan os.Signal, files []string, interval time.Duration) {
+	var ticks <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	modified := modTimes(files)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			r.logger.Info().Msg("config reload requested")
+		case <-ticks:
+			current := modTimes(files)
+			if current == modified {
+				continue
+			}
+			modified = current
+			r.logger.Info().Msg("config files modified")
+		}
+
+		r.mu.Lock()
+		timeout := r.current.Timeout.Duration
+		r.mu.Unlock()
+
+		reloadCtx, cancel := context.WithTimeout(ctx, timeout)
+		if err := r.Reload(reloadCtx); err != nil {
+			r.logger.Error().Err(err).Msg("config not reloaded")
+		}
+		cancel()
+	}
+}
+
+// modTimes returns the modification times of the files, joined to be compared, the missing ones being left out
+func modTimes(files []string) string {
+	var times []string
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil {
+			times = append(times, f+"@"+info.ModTime().String())
+		}
+	}
+	return strings.Join(times, ",")
+}
@@ -0,0 +1,184 @@
+package reload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testConfig returns a config with the given log level
+func testConfig(level string) config.Config {
+	var cfg config.Config
+	cfg.Log.Level = level
+	cfg.Timeout.Duration = time.Second
+	return cfg
+}
+
+// TestReload_Ok checks that Reload applies the new config and records its changes in the audit log, telling the ones applied on restart
+func TestReload_Ok(t *testing.T) {
+	// Arrange
+	newConfig := testConfig("debug")
+	newConfig.Async.Run = true
+
+	auditServiceMock := mocks.NewAuditService(t)
+	auditServiceMock.On(testutils.FunctionName(t, ports.AuditService.Record), context.Background(), entities.AuditConfigReloaded, map[string]string{
+		"Log.Level":        "debug",
+		"Async.Run":        "true",
+		"restart_required": "Async.Run",
+	}).Return(nil).Once()
+
+	var applied config.Config
+	r := New(testConfig("info"), func() (config.Config, error) { return newConfig, nil }, func(cfg config.Config) error {
+		applied = cfg
+		return nil
+	}, auditServiceMock, zerolog.Nop())
+
+	// Act
+	err := r.Reload(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, newConfig, applied)
+	assert.Equal(t, newConfig, r.current)
+}
+
+// TestReload_Unchanged checks that Reload neither applies nor records anything when no setting changed
+func TestReload_Unchanged(t *testing.T) {
+	// Arrange
+	applied := false
+	r := New(testConfig("info"), func() (config.Config, error) { return testConfig("info"), nil }, func(cfg config.Config) error {
+		applied = true
+		return nil
+	}, mocks.NewAuditService(t), zerolog.Nop())
+
+	// Act
+	err := r.Reload(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, applied)
+}
+
+// TestReload_ReadError checks that Reload returns the error and keeps the running config when the new one cannot be read
+func TestReload_ReadError(t *testing.T) {
+	// Arrange
+	r := New(testConfig("info"), func() (config.Config, error) { return config.Config{}, errors.New("read-error") }, nil, mocks.NewAuditService(t), zerolog.Nop())
+
+	// Act
+	err := r.Reload(context.Background())
+
+	// Assert
+	assert.Equal(t, "read-error", err.Error())
+	assert.Equal(t, testConfig("info"), r.current)
+}
+
+// TestReload_ApplyError checks that Reload returns an error and neither records nor keeps the new config when it cannot be applied
+func TestReload_ApplyError(t *testing.T) {
+	// Arrange
+	r := New(testConfig("info"), func() (config.Config, error) { return testConfig("verbose"), nil }, func(cfg config.Config) error {
+		return errors.New("apply-error")
+	}, mocks.NewAuditService(t), zerolog.Nop())
+
+	// Act
+	err := r.Reload(context.Background())
+
+	// Assert
+	assert.Equal(t, "config not valid: apply-error", err.Error())
+	assert.Equal(t, testConfig("info"), r.current)
+}
+
+// TestReload_AuditError checks that Reload logs the audit event that cannot be written without failing
+func TestReload_AuditError(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	auditServiceMock := mocks.NewAuditService(t)
+	auditServiceMock.On(testutils.FunctionName(t, ports.AuditService.Record), context.Background(), entities.AuditConfigReloaded, mock.Anything).Return(errors.New("audit-error")).Once()
+
+	r := New(testConfig("info"), func() (config.Config, error) { return testConfig("debug"), nil }, func(cfg config.Config) error {
+		return nil
+	}, auditServiceMock, zerolog.New(&buf))
+
+	// Act
+	err := r.Reload(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `"message":"audit event cannot be written"`)
+	assert.Contains(t, buf.String(), `"message":"config reloaded"`)
+}
+
+// TestWatch_Signal checks that watch reloads the config on every received signal until the context is done
+func TestWatch_Signal(t *testing.T) {
+	// Arrange
+	reads := make(chan struct{}, 1)
+	r := New(testConfig("info"), func() (config.Config, error) {
+		reads <- struct{}{}
+		return testConfig("info"), nil
+	}, nil, mocks.NewAuditService(t), zerolog.Nop())
+
+	signals := make(chan os.Signal, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		r.watch(ctx, signals, nil, 0)
+		close(done)
+	}()
+	signals <- os.Interrupt
+
+	// Assert
+	<-reads
+	cancel()
+	<-done
+}
+
+// TestWatch_FileModified checks that watch reloads the config when one of the files is modified
+func TestWatch_FileModified(t *testing.T) {
+	// Arrange
+	file := path.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reads := make(chan struct{}, 1)
+	r := New(testConfig("info"), func() (config.Config, error) {
+		reads <- struct{}{}
+		return testConfig("info"), nil
+	}, nil, mocks.NewAuditService(t), zerolog.Nop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		r.watch(ctx, nil, []string{file}, time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := os.Chtimes(file, time.Now(), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	select {
+	case <-reads:
+	case <-time.After(time.Second):
+		t.Error("config not reloaded")
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,313 @@
+// Package resourcegen scaffolds a new resource stored in MongoDB following the conventions of the users: its entity, its request
+// and response models with their mapping, its ports, its mongo repository, its service and handlers, their tests and mocks,
+// and its wiring as a domain of the API, so the resource is served once generated and is then extended by hand.
+package resourcegen
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+var templates = template.Must(template.ParseFS(templatesFS, "templates/*.tmpl"))
+
+// validName matches the names of the resources, in snake case and singular, like api_key
+var validName = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// initialisms the words of the names written in upper case in the Go identifiers and the docs, like the API of APIKey
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "id": true, "ip": true, "json": true, "oidc": true,
+	"sms": true, "sql": true, "uri": true, "url": true, "uuid": true,
+}
+
+// Resource the names of a resource, derived from its name and its plural in snake case by New
+type Resource struct {
+	// Module the path of the module of the API, set by Write from its go.mod
+	Module string
+	// Name the name, like api_key
+	Name string
+	// Type and Types the Go types, singular and plural, like APIKey and APIKeys
+	Type, Types string
+	// Var and Vars the Go variables, singular and plural, like apiKey and apiKeys
+	Var, Vars string
+	// Words, PluralWords and Capitalized the name in the docs, like API key, API keys and API key
+	Words, PluralWords, Capitalized string
+	// Tag the tag of the routes in the OpenAPI document, like API keys
+	Tag string
+	// Path the path of the routes under /v1, like api-keys
+	Path string
+	// Collection the mongo collection of the resource, like api_keys
+	Collection string
+	// File the base name of the Go files, like apikey
+	File string
+}
+
+// File a file generated, at its path relative to the root of the module
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// files the files generated from the templates, by the name of the template, at their path of the resource
+var files = map[string]func(r Resource) string{
+	"entity.go.tmpl":          func(r Resource) string { return filepath.Join("core", "entities", r.File+".go") },
+	"models.go.tmpl":          func(r Resource) string { return filepath.Join("core", "models", r.File+".go") },
+	"mapping.go.tmpl":         func(r Resource) string { return filepath.Join("core", "mapping", r.File+".go") },
+	"mapping_test.go.tmpl":    func(r Resource) string { return filepath.Join("core", "mapping", r.File+"_test.go") },
+	"ports.go.tmpl":           func(r Resource) string { return filepath.Join("core", "ports", r.File+".go") },
+	"service.go.tmpl":         func(r Resource) string { return filepath.Join("core", "services", r.File+".go") },
+	"service_test.go.tmpl":    func(r Resource) string { return filepath.Join("core", "services", r.File+"_test.go") },
+	"mongo.go.tmpl":           func(r Resource) string { return filepath.Join("infrastructure", "mongo", r.File+".go") },
+	"handlers.go.tmpl":        func(r Resource) string { return filepath.Join("app", "handlers", r.File+".go") },
+	"handlers_test.go.tmpl":   func(r Resource) string { return filepath.Join("app", "handlers", r.File+"_test.go") },
+	"wiring.go.tmpl":          func(r Resource) string { return filepath.Join("app", "api", r.File+".go") },
+	"repository_mock.go.tmpl": func(r Resource) string { return filepath.Join("test", "mocks", r.Type+"Repository.go") },
+	"service_mock.go.tmpl":    func(r Resource) string { return filepath.Join("test", "mocks", r.Type+"Service.go") },
+}
+
+// insertion lines inserted in a file of the API before the line with the marker, indented as it
+type insertion struct {
+	path   string
+	marker string
+	lines  func(r Resource) []string
+}
+
+// insertions wire the resource: its repository in the stores, created on mongo, and its domain, registered when the repository is set
+var insertions = []insertion{
+	{
+		path:   filepath.Join("app", "api", "wiring.go"),
+		marker: "gen-resource:stores",
+		lines: func(r Resource) []string {
+			return []string{fmt.Sprintf("%s ports.%sRepository", r.Vars, r.Type)}
+		},
+	},
+	{
+		path:   filepath.Join("app", "api", "wiring.go"),
+		marker: "gen-resource:mongo",
+		lines: func(r Resource) []string {
+			return []string{fmt.Sprintf("s.%s = mongo.New%sRepository(db)", r.Vars, r.Type)}
+		},
+	},
+	{
+		path:   filepath.Join("app", "api", "domains.go"),
+		marker: "gen-resource:domains",
+		lines: func(r Resource) []string {
+			return []string{
+				fmt.Sprintf("if s.%s != nil {", r.Vars),
+				fmt.Sprintf("\tdomains = append(domains, a.%sDomain(s.%s))", r.Vars, r.Vars),
+				"}",
+			}
+		},
+	},
+}
+
+// New returns the names of the resource of the name, in snake case and singular, and of its plural,
+// derived from the name when empty, like api_keys for api_key or categories for category
+func New(name, plural string) (Resource, error) {
+	if !validName.MatchString(name) {
+		return Resource{}, fmt.Errorf("name %q not valid, it must be in snake case, like api_key", name)
+	}
+	if plural == "" {
+		plural = pluralize(name)
+	}
+	if !validName.MatchString(plural) {
+		return Resource{}, fmt.Errorf("plural %q not valid, it must be in snake case, like api_keys", plural)
+	}
+	if plural == name {
+		return Resource{}, fmt.Errorf("plural %q cannot be the name", plural)
+	}
+
+	words, pluralWords := strings.Split(name, "_"), strings.Split(plural, "_")
+	r := Resource{
+		Name:        name,
+		Type:        camel(words, true),
+		Types:       camel(pluralWords, true),
+		Var:         camel(words, false),
+		Vars:        camel(pluralWords, false),
+		Words:       prose(words),
+		PluralWords: prose(pluralWords),
+		Path:        strings.ReplaceAll(plural, "_", "-"),
+		Collection:  plural,
+		File:        strings.ReplaceAll(name, "_", ""),
+	}
+	r.Capitalized = capitalize(r.Words)
+	r.Tag = capitalize(r.PluralWords)
+	return r, nil
+}
+
+// Generate generates the files of the resource, formatted and sorted by path
+func Generate(r Resource) ([]File, error) {
+	var generated []File
+	for name, path := range files {
+		var b bytes.Buffer
+		if err := templates.ExecuteTemplate(&b, name, r); err != nil {
+			return nil, err
+		}
+		content, err := format.Source(b.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%s not valid: %w", name, err)
+		}
+		generated = append(generated, File{Path: path(r), Content: content})
+	}
+	sort.Slice(generated, func(i, j int) bool { return generated[i].Path < generated[j].Path })
+	return generated, nil
+}
+
+// Write writes the files of the resource to the module of the directory and wires it in the API, returning the paths written.
+// Nothing is written when any of the files exists already or the API cannot be wired, as the markers of the wiring are missing.
+func Write(dir string, r Resource) ([]string, error) {
+	module, err := modulePath(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	r.Module = module
+
+	generated, err := Generate(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range generated {
+		if _, err := os.Stat(filepath.Join(dir, f.Path)); err == nil {
+			return nil, fmt.Errorf("%s already exists", f.Path)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	wired := map[string][]byte{}
+	var wiredPaths []string
+	for _, ins := range insertions {
+		src, ok := wired[ins.path]
+		if !ok {
+			if src, err = os.ReadFile(filepath.Join(dir, ins.path)); err != nil {
+				return nil, err
+			}
+			wiredPaths = append(wiredPaths, ins.path)
+		}
+		if src, err = insert(src, ins.marker, ins.lines(r)); err != nil {
+			return nil, fmt.Errorf("%s: %w", ins.path, err)
+		}
+		wired[ins.path] = src
+	}
+	for path, src := range wired {
+		if wired[path], err = format.Source(src); err != nil {
+			return nil, fmt.Errorf("%s not valid once wired: %w", path, err)
+		}
+	}
+
+	var written []string
+	for _, f := range generated {
+		path := filepath.Join(dir, f.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, f.Path)
+	}
+	for _, path := range wiredPaths {
+		if err := os.WriteFile(filepath.Join(dir, path), wired[path], 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// insert inserts the lines before the line with the marker, indented as it, failing when the marker is missing
+func insert(src []byte, marker string, lines []string) ([]byte, error) {
+	var out bytes.Buffer
+	found := false
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !found && strings.Contains(line, marker) {
+			found = true
+			indent := line[:len(line)-len(strings.TrimLeft(line, "\t "))]
+			for _, l := range lines {
+				out.WriteString(indent + l + "\n")
+			}
+		}
+		out.WriteString(line + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("marker %s not found", marker)
+	}
+	return out.Bytes(), nil
+}
+
+// modulePath reads the path of the module from its go.mod
+func modulePath(goMod string) (string, error) {
+	src, err := os.ReadFile(goMod)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(src), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	return "", fmt.Errorf("module path not found in %s", goMod)
+}
+
+// pluralize returns the plural of the name in snake case, pluralizing its last word with the rules of the regular English plurals
+func pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "z"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsAny(name[len(name)-2:len(name)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	default:
+		return name + "s"
+	}
+}
+
+// camel joins the words in camel case, upper for the exported identifiers, the initialisms being in upper case but when first in a lower one
+func camel(words []string, upper bool) string {
+	var b strings.Builder
+	for i, w := range words {
+		switch {
+		case i == 0 && !upper:
+			b.WriteString(w)
+		case initialisms[w]:
+			b.WriteString(strings.ToUpper(w))
+		default:
+			b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+	return b.String()
+}
+
+// prose joins the words with spaces, the initialisms being in upper case
+func prose(words []string) string {
+	out := make([]string, len(words))
+	for i, w := range words {
+		if initialisms[w] {
+			w = strings.ToUpper(w)
+		}
+		out[i] = w
+	}
+	return strings.Join(out, " ")
+}
+
+// capitalize returns the text with its first letter in upper case
+func capitalize(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
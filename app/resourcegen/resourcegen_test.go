@@ -0,0 +1,225 @@
+package resourcegen
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testWiring the files of the API wired by Write, with their markers
+var testWiring = map[string]string{
+	"go.mod": "module example.com/api\n\ngo 1.20\n",
+	"app/api/wiring.go": `package api
+
+type stores struct {
+	users ports.UserRepository
+	// gen-resource:stores
+}
+
+func (a *api) connectDatabase() (s stores) {
+	switch a.config.Database {
+	case "mongo":
+		s.users = mongo.NewUserRepository(db)
+		// gen-resource:mongo
+	}
+	return s
+}
+`,
+	"app/api/domains.go": `package api
+
+func (a *api) registerDomains(s stores) {
+	var domains []domain.Domain
+	// gen-resource:domains
+}
+`,
+}
+
+// writeTree writes the files to a temporary directory, returning it
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// TestNew_Ok checks that New derives the names of the resource, with its initialisms in upper case and its plural from its name
+func TestNew_Ok(t *testing.T) {
+	// Arrange
+	expected := Resource{
+		Name:        "api_key",
+		Type:        "APIKey",
+		Types:       "APIKeys",
+		Var:         "apiKey",
+		Vars:        "apiKeys",
+		Words:       "API key",
+		PluralWords: "API keys",
+		Capitalized: "API key",
+		Tag:         "API keys",
+		Path:        "api-keys",
+		Collection:  "api_keys",
+		File:        "apikey",
+	}
+
+	// Act
+	r, err := New("api_key", "")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expected, r)
+}
+
+// TestNew_Plural checks that New pluralizes the names with the rules of the regular English plurals, unless the plural is given
+func TestNew_Plural(t *testing.T) {
+	// Arrange
+	names := map[string]string{"category": "categories", "day": "days", "address": "addresses", "batch": "batches"}
+
+	for name, plural := range names {
+		// Act
+		r, err := New(name, "")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, plural, r.Collection, name)
+	}
+	r, err := New("person", "people")
+	assert.Nil(t, err)
+	assert.Equal(t, "People", r.Types)
+}
+
+// TestNew_InvalidName checks that New returns an error when the name is not in snake case
+func TestNew_InvalidName(t *testing.T) {
+	// Act
+	_, err := New("ApiKey", "")
+
+	// Assert
+	assert.Equal(t, `name "ApiKey" not valid, it must be in snake case, like api_key`, err.Error())
+}
+
+// TestGenerate_Ok checks that Generate generates the files of every layer of the resource, as valid Go sources of their packages
+func TestGenerate_Ok(t *testing.T) {
+	// Arrange
+	r, err := New("project", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Module = "example.com/api"
+
+	// Act
+	files, err := Generate(r)
+
+	// Assert
+	assert.Nil(t, err)
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, filepath.ToSlash(f.Path))
+		parsed, err := parser.ParseFile(token.NewFileSet(), f.Path, f.Content, parser.PackageClauseOnly)
+		if assert.Nil(t, err, f.Path) {
+			assert.Equal(t, filepath.Base(filepath.Dir(f.Path)), parsed.Name.Name, f.Path)
+		}
+	}
+	assert.Equal(t, []string{
+		"app/api/project.go",
+		"app/handlers/project.go",
+		"app/handlers/project_test.go",
+		"core/entities/project.go",
+		"core/mapping/project.go",
+		"core/mapping/project_test.go",
+		"core/models/project.go",
+		"core/ports/project.go",
+		"core/services/project.go",
+		"core/services/project_test.go",
+		"infrastructure/mongo/project.go",
+		"test/mocks/ProjectRepository.go",
+		"test/mocks/ProjectService.go",
+	}, paths)
+}
+
+// TestWrite_Ok checks that Write writes the files of the resource and wires its repository and its domain before the markers
+func TestWrite_Ok(t *testing.T) {
+	// Arrange
+	dir := writeTree(t, testWiring)
+	r, err := New("project", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	written, err := Write(dir, r)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, written, 15)
+	entity, err := os.ReadFile(filepath.Join(dir, "core", "entities", "project.go"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(entity), `const EntityNameProject = "projects"`)
+	ports, err := os.ReadFile(filepath.Join(dir, "core", "ports", "project.go"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(ports), `"example.com/api/core/models"`)
+	wiring, err := os.ReadFile(filepath.Join(dir, "app", "api", "wiring.go"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(wiring), "\tusers    ports.UserRepository\n\tprojects ports.ProjectRepository\n\t// gen-resource:stores\n")
+	assert.Contains(t, string(wiring), "\t\ts.projects = mongo.NewProjectRepository(db)\n\t\t// gen-resource:mongo\n")
+	domains, err := os.ReadFile(filepath.Join(dir, "app", "api", "domains.go"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(domains), "\tif s.projects != nil {\n\t\tdomains = append(domains, a.projectsDomain(s.projects))\n\t}\n\t// gen-resource:domains\n")
+}
+
+// TestWrite_Existing checks that Write returns an error, writing nothing, when a file of the resource exists already
+func TestWrite_Existing(t *testing.T) {
+	// Arrange
+	files := map[string]string{"core/entities/project.go": "package entities\n"}
+	for path, content := range testWiring {
+		files[path] = content
+	}
+	dir := writeTree(t, files)
+	r, err := New("project", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err = Write(dir, r)
+
+	// Assert
+	assert.Equal(t, filepath.Join("core", "entities", "project.go")+" already exists", err.Error())
+	_, err = os.Stat(filepath.Join(dir, "core", "models", "project.go"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// TestWrite_MissingMarker checks that Write returns an error, writing nothing, when a marker of the wiring is missing
+func TestWrite_MissingMarker(t *testing.T) {
+	// Arrange
+	files := map[string]string{}
+	for path, content := range testWiring {
+		files[path] = strings.ReplaceAll(content, "// gen-resource:domains", "")
+	}
+	dir := writeTree(t, files)
+	r, err := New("project", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err = Write(dir, r)
+
+	// Assert
+	assert.Equal(t, filepath.Join("app", "api", "domains.go")+": marker gen-resource:domains not found", err.Error())
+	_, err = os.Stat(filepath.Join(dir, "core", "entities", "project.go"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	wiring, err := os.ReadFile(filepath.Join(dir, "app", "api", "wiring.go"))
+	assert.Nil(t, err)
+	assert.Equal(t, testWiring["app/api/wiring.go"], string(wiring))
+}
@@ -0,0 +1,129 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	usersv1 "github.com/sergicanet9/go-hexagonal-api/proto/users/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// callerKey the key of the user calling in the context of the authenticated calls
+type callerKey struct{}
+
+// publicMethods are the methods called without a token, as the matching endpoints
+var publicMethods = map[string]bool{
+	usersv1.UserService_Login_FullMethodName:      true,
+	usersv1.UserService_CreateUser_FullMethodName: true,
+}
+
+// methodClaims are the claims of the token required to call the methods, as the ones of the matching endpoints,
+// the methods neither listed nor public being denied
+var methodClaims = map[string]jwt.MapClaims{
+	usersv1.UserService_GetUser_FullMethodName:        {},
+	usersv1.UserService_GetUserByEmail_FullMethodName: {},
+	usersv1.UserService_ListUsers_FullMethodName:      {},
+	usersv1.UserService_SearchUsers_FullMethodName:    {},
+	usersv1.UserService_UpdateUser_FullMethodName:     {},
+	usersv1.UserService_DeleteUser_FullMethodName:     {"admin": true},
+}
+
+// UnaryAuthInterceptor authenticates the unary calls with the token of their authorization metadata
+func UnaryAuthInterceptor(keys ports.TokenKeys) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, keys, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor authenticates the streaming calls with the token of their authorization metadata
+func StreamAuthInterceptor(keys ports.TokenKeys) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), keys, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate returns the context of the call carrying its request info, so that its operations can be attributed,
+// failing with Unauthenticated when the method requires a token not set or not valid, and with PermissionDenied when it is without the claims required
+// or the method is not known
+func authenticate(ctx context.Context, keys ports.TokenKeys, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	info := models.RequestInfo{
+		RequestID: firstValue(md, "x-request-id"),
+		UserAgent: firstValue(md, "user-agent"),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		info.IP, _, _ = net.SplitHostPort(p.Addr.String())
+	}
+
+	if !publicMethods[method] {
+		claims, ok := methodClaims[method]
+		if !ok {
+			return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("method %s not allowed", method))
+		}
+
+		authorization := firstValue(md, "authorization")
+		if authorization == "" {
+			return nil, status.Error(codes.Unauthenticated, "an authorization metadata is required")
+		}
+		bearerToken := strings.Split(authorization, " ")
+		if len(bearerToken) != 2 {
+			return nil, status.Error(codes.Unauthenticated, "authorization metadata not properly formated, should be Bearer + {token}")
+		}
+
+		tokenClaims, err := keys.Parse(ctx, bearerToken[1])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, fmt.Sprintf("invalid token: %s", err))
+		}
+		for name, value := range claims {
+			if claim, ok := tokenClaims[name]; !(ok && claim == value) {
+				return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("required claim %s not found or incorrect", name))
+			}
+		}
+		info.ActorID, _ = tokenClaims["user_id"].(string)
+		apiKeyID, _ := tokenClaims["api_key_id"].(string)
+		ctx = context.WithValue(ctx, callerKey{}, models.Caller{UserID: info.ActorID, Admin: tokenClaims["admin"] == true, APIKeyID: apiKeyID})
+	}
+
+	return models.WithRequestInfo(ctx, info), nil
+}
+
+// caller returns the user calling from the context of the call, set by authenticate
+func caller(ctx context.Context) models.Caller {
+	c, _ := ctx.Value(callerKey{}).(models.Caller)
+	return c
+}
+
+// firstValue returns the first value of the metadata key, empty when not set
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// serverStream is a server stream with the context of its authenticated call
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
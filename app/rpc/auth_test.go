@@ -0,0 +1,164 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	usersv1 "github.com/sergicanet9/go-hexagonal-api/proto/users/v1"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TestUnaryAuthInterceptor_Ok checks that UnaryAuthInterceptor calls the method when its token is verified by the keys and has the required claims,
+// attributing the call to the user of the token, its caller
+func TestUnaryAuthInterceptor_Ok(t *testing.T) {
+	// Arrange
+	keysMock := mocks.NewTokenKeys(t)
+	keysMock.On(testutils.FunctionName(t, ports.TokenKeys.Parse), mock.Anything, "test-token").Return(jwt.MapClaims{"admin": true, "user_id": "test-id"}, nil).Once()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer test-token"))
+	info := &grpc.UnaryServerInfo{FullMethod: usersv1.UserService_DeleteUser_FullMethodName}
+	var actorID string
+	var c models.Caller
+
+	// Act
+	_, err := UnaryAuthInterceptor(keysMock)(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		actorID = models.RequestInfoFrom(ctx).ActorID
+		c = caller(ctx)
+		return nil, nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", actorID)
+	assert.Equal(t, models.Caller{UserID: "test-id", Admin: true}, c)
+}
+
+// TestUnaryAuthInterceptor_Public checks that UnaryAuthInterceptor calls the public methods without a token
+func TestUnaryAuthInterceptor_Public(t *testing.T) {
+	// Arrange
+	keysMock := mocks.NewTokenKeys(t)
+	info := &grpc.UnaryServerInfo{FullMethod: usersv1.UserService_Login_FullMethodName}
+	var called bool
+
+	// Act
+	_, err := UnaryAuthInterceptor(keysMock)(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, called)
+}
+
+// TestUnaryAuthInterceptor_UnknownMethod checks that UnaryAuthInterceptor fails with PermissionDenied for the methods neither public nor listed
+func TestUnaryAuthInterceptor_UnknownMethod(t *testing.T) {
+	// Arrange
+	keysMock := mocks.NewTokenKeys(t)
+	info := &grpc.UnaryServerInfo{FullMethod: "/users.v1.UserService/NewMethod"}
+	var called bool
+
+	// Act
+	_, err := UnaryAuthInterceptor(keysMock)(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+
+	// Assert
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.False(t, called)
+}
+
+// TestUnaryAuthInterceptor_MissingToken checks that UnaryAuthInterceptor fails with Unauthenticated when the authorization metadata is not set
+func TestUnaryAuthInterceptor_MissingToken(t *testing.T) {
+	// Arrange
+	keysMock := mocks.NewTokenKeys(t)
+	info := &grpc.UnaryServerInfo{FullMethod: usersv1.UserService_GetUser_FullMethodName}
+
+	// Act
+	_, err := UnaryAuthInterceptor(keysMock)(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	// Assert
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+// TestUnaryAuthInterceptor_InvalidToken checks that UnaryAuthInterceptor fails with Unauthenticated when the token is not verified by the keys
+func TestUnaryAuthInterceptor_InvalidToken(t *testing.T) {
+	// Arrange
+	keysMock := mocks.NewTokenKeys(t)
+	keysMock.On(testutils.FunctionName(t, ports.TokenKeys.Parse), mock.Anything, "test-token").Return(nil, errors.New("signature is invalid")).Once()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer test-token"))
+	info := &grpc.UnaryServerInfo{FullMethod: usersv1.UserService_GetUser_FullMethodName}
+
+	// Act
+	_, err := UnaryAuthInterceptor(keysMock)(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	// Assert
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "invalid token: signature is invalid", status.Convert(err).Message())
+}
+
+// TestUnaryAuthInterceptor_MissingClaim checks that UnaryAuthInterceptor fails with PermissionDenied when the token does not have the required claims
+func TestUnaryAuthInterceptor_MissingClaim(t *testing.T) {
+	// Arrange
+	keysMock := mocks.NewTokenKeys(t)
+	keysMock.On(testutils.FunctionName(t, ports.TokenKeys.Parse), mock.Anything, "test-token").Return(jwt.MapClaims{"user_id": "test-id"}, nil).Once()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer test-token"))
+	info := &grpc.UnaryServerInfo{FullMethod: usersv1.UserService_DeleteUser_FullMethodName}
+
+	// Act
+	_, err := UnaryAuthInterceptor(keysMock)(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	// Assert
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+// TestStreamAuthInterceptor_Ok checks that StreamAuthInterceptor calls the method with a stream carrying the context of the authenticated call
+func TestStreamAuthInterceptor_Ok(t *testing.T) {
+	// Arrange
+	keysMock := mocks.NewTokenKeys(t)
+	keysMock.On(testutils.FunctionName(t, ports.TokenKeys.Parse), mock.Anything, "test-token").Return(jwt.MapClaims{"user_id": "test-id"}, nil).Once()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer test-token"))
+	info := &grpc.StreamServerInfo{FullMethod: usersv1.UserService_ListUsers_FullMethodName}
+	var actorID string
+
+	// Act
+	err := StreamAuthInterceptor(keysMock)(nil, &testStream{ctx: ctx}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		actorID = models.RequestInfoFrom(stream.Context()).ActorID
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", actorID)
+}
+
+// testStream is a server stream of a call with the given context
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,186 @@
+// Package rpc serves the gRPC contracts of the API, as the handlers serve its REST endpoints
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	usersv1 "github.com/sergicanet9/go-hexagonal-api/proto/users/v1"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// userServer adapter of the users contract, serving it with the user service
+type userServer struct {
+	usersv1.UnimplementedUserServiceServer
+	cfg     config.Config
+	service ports.UserService
+}
+
+// NewUserServer creates the server of the users contract, its calls timing out as the requests of the API
+func NewUserServer(cfg config.Config, s ports.UserService) usersv1.UserServiceServer {
+	return &userServer{
+		cfg:     cfg,
+		service: s,
+	}
+}
+
+func (s *userServer) Login(ctx context.Context, req *usersv1.LoginRequest) (*usersv1.LoginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout.Duration)
+	defer cancel()
+
+	resp, err := s.service.Login(ctx, models.LoginUserReq{Email: req.Email, Password: req.Password})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &usersv1.LoginResponse{User: toUser(resp.User), Token: resp.Token}, nil
+}
+
+func (s *userServer) CreateUser(ctx context.Context, req *usersv1.CreateUserRequest) (*usersv1.CreateUserResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout.Duration)
+	defer cancel()
+
+	resp, err := s.service.Create(ctx, models.CreateUserReq{
+		Name:     req.Name,
+		Surnames: req.Surnames,
+		Email:    req.Email,
+		Password: req.Password,
+		Claims:   req.Claims,
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &usersv1.CreateUserResponse{Id: resp.InsertedID}, nil
+}
+
+func (s *userServer) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.GetUserResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout.Duration)
+	defer cancel()
+
+	if err := s.service.Authorize(ctx, caller(ctx), req.Id); err != nil {
+		return nil, statusError(err)
+	}
+	user, err := s.service.GetByID(ctx, req.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &usersv1.GetUserResponse{User: toUser(user)}, nil
+}
+
+func (s *userServer) GetUserByEmail(ctx context.Context, req *usersv1.GetUserByEmailRequest) (*usersv1.GetUserByEmailResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout.Duration)
+	defer cancel()
+
+	user, err := s.service.GetByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if err := s.service.Authorize(ctx, caller(ctx), user.ID); err != nil {
+		return nil, statusError(err)
+	}
+	return &usersv1.GetUserByEmailResponse{User: toUser(user)}, nil
+}
+
+// ListUsers streams the users once read, the timeout only applying to their read
+func (s *userServer) ListUsers(req *usersv1.ListUsersRequest, stream usersv1.UserService_ListUsersServer) error {
+	ctx, cancel := context.WithTimeout(stream.Context(), s.cfg.Timeout.Duration)
+	defer cancel()
+
+	users, err := s.service.GetAll(ctx, caller(ctx))
+	if err != nil {
+		return statusError(err)
+	}
+	for _, user := range users {
+		if err := stream.Send(&usersv1.ListUsersResponse{User: toUser(user)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *userServer) SearchUsers(ctx context.Context, req *usersv1.SearchUsersRequest) (*usersv1.SearchUsersResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout.Duration)
+	defer cancel()
+
+	users, err := s.service.Search(ctx, caller(ctx), models.SearchUsersReq{Query: req.Query, Mode: req.Mode})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &usersv1.SearchUsersResponse{Users: make([]*usersv1.User, len(users))}
+	for i, user := range users {
+		resp.Users[i] = toUser(user)
+	}
+	return resp, nil
+}
+
+func (s *userServer) UpdateUser(ctx context.Context, req *usersv1.UpdateUserRequest) (*usersv1.UpdateUserResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout.Duration)
+	defer cancel()
+
+	user := models.UpdateUserReq{
+		Name:        req.Name,
+		Surnames:    req.Surnames,
+		Email:       req.Email,
+		OldPassword: req.OldPassword,
+		NewPassword: req.NewPassword,
+	}
+	if req.Claims != nil {
+		user.Claims = &req.Claims.Values
+	}
+	if err := s.service.AuthorizeUpdate(ctx, caller(ctx), req.Id, user); err != nil {
+		return nil, statusError(err)
+	}
+	if err := s.service.Update(ctx, req.Id, user); err != nil {
+		return nil, statusError(err)
+	}
+	return &usersv1.UpdateUserResponse{}, nil
+}
+
+func (s *userServer) DeleteUser(ctx context.Context, req *usersv1.DeleteUserRequest) (*usersv1.DeleteUserResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout.Duration)
+	defer cancel()
+
+	if err := s.service.Delete(ctx, req.Id); err != nil {
+		return nil, statusError(err)
+	}
+	return &usersv1.DeleteUserResponse{}, nil
+}
+
+// toUser returns the user of the contract
+func toUser(user models.UserResp) *usersv1.User {
+	return &usersv1.User{
+		Id:        user.ID,
+		Name:      user.Name,
+		Surnames:  user.Surnames,
+		Email:     user.Email,
+		Claims:    user.Claims,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+	}
+}
+
+// statusError returns the status of the error, with the code matching the status of the endpoints failing with it
+func statusError(err error) error {
+	var code codes.Code
+	switch {
+	case errors.Is(err, apierror.ErrConflict):
+		code = codes.AlreadyExists
+	case errors.Is(err, wrappers.ValidationErr):
+		code = codes.InvalidArgument
+	case errors.Is(err, wrappers.NonExistentErr):
+		code = codes.NotFound
+	case errors.Is(err, wrappers.UnauthorizedErr):
+		code = codes.Unauthenticated
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	default:
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,146 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	usersv1 "github.com/sergicanet9/go-hexagonal-api/proto/users/v1"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testConfig returns a config whose calls do not time out in the tests
+func testConfig() config.Config {
+	cfg := config.Config{}
+	cfg.Timeout.Duration = time.Minute
+	return cfg
+}
+
+// TestLogin_Ok checks that Login returns the user logged in with its token
+func TestLogin_Ok(t *testing.T) {
+	// Arrange
+	createdAt := time.Date(2023, 8, 20, 9, 0, 0, 0, time.UTC)
+	userService := mocks.NewUserService(t)
+	userService.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, models.LoginUserReq{Email: "test@example.com", Password: "test-password"}).
+		Return(models.LoginUserResp{User: models.UserResp{ID: "test-id", Email: "test@example.com", Claims: []int64{1}, CreatedAt: createdAt}, Token: "test-token"}, nil).Once()
+
+	server := NewUserServer(testConfig(), userService)
+
+	// Act
+	resp, err := server.Login(context.Background(), &usersv1.LoginRequest{Email: "test@example.com", Password: "test-password"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-token", resp.Token)
+	assert.Equal(t, "test-id", resp.User.Id)
+	assert.Equal(t, []int64{1}, resp.User.Claims)
+	assert.Equal(t, createdAt, resp.User.CreatedAt.AsTime())
+}
+
+// TestGetUser_NotFound checks that GetUser fails with NotFound when the user does not exist
+func TestGetUser_NotFound(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	userService.On(testutils.FunctionName(t, ports.UserService.Authorize), mock.Anything, models.Caller{}, "test-id").Return(nil).Once()
+	userService.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").
+		Return(models.UserResp{}, wrappers.NewNonExistentErr(fmt.Errorf("user not found"))).Once()
+
+	server := NewUserServer(testConfig(), userService)
+
+	// Act
+	_, err := server.GetUser(context.Background(), &usersv1.GetUserRequest{Id: "test-id"})
+
+	// Assert
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "user not found", status.Convert(err).Message())
+}
+
+// TestCreateUser_InvalidArgument checks that CreateUser fails with InvalidArgument when the user is not valid
+func TestCreateUser_InvalidArgument(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	userService.On(testutils.FunctionName(t, ports.UserService.Create), mock.Anything, models.CreateUserReq{Email: "test", Password: "test-password"}).
+		Return(models.CreationResp{}, wrappers.NewValidationErr(fmt.Errorf("email not valid"))).Once()
+
+	server := NewUserServer(testConfig(), userService)
+
+	// Act
+	_, err := server.CreateUser(context.Background(), &usersv1.CreateUserRequest{Email: "test", Password: "test-password"})
+
+	// Assert
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// TestUpdateUser_Ok checks that UpdateUser passes only the fields set to the service, the claims as a whole
+func TestUpdateUser_Ok(t *testing.T) {
+	// Arrange
+	name := "test"
+	claims := []int64{1, 2}
+	userService := mocks.NewUserService(t)
+	userService.On(testutils.FunctionName(t, ports.UserService.AuthorizeUpdate), mock.Anything, models.Caller{}, "test-id", models.UpdateUserReq{Name: &name, Claims: &claims}).Return(nil).Once()
+	userService.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", models.UpdateUserReq{Name: &name, Claims: &claims}).Return(nil).Once()
+
+	server := NewUserServer(testConfig(), userService)
+
+	// Act
+	_, err := server.UpdateUser(context.Background(), &usersv1.UpdateUserRequest{Id: "test-id", Name: &name, Claims: &usersv1.Claims{Values: claims}})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestListUsers_Ok checks that ListUsers streams every user
+func TestListUsers_Ok(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	userService.On(testutils.FunctionName(t, ports.UserService.GetAll), mock.Anything, models.Caller{}).
+		Return([]models.UserResp{{ID: "test-id-1"}, {ID: "test-id-2"}}, nil).Once()
+
+	server := NewUserServer(testConfig(), userService)
+	stream := &listUsersStream{testStream: testStream{ctx: context.Background()}}
+
+	// Act
+	err := server.ListUsers(&usersv1.ListUsersRequest{}, stream)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"test-id-1", "test-id-2"}, stream.IDs)
+}
+
+// TestUpdateUser_NotAuthorized checks that UpdateUser fails with NotFound without updating the user when the caller is not allowed to it
+func TestUpdateUser_NotAuthorized(t *testing.T) {
+	// Arrange
+	name := "test"
+	userService := mocks.NewUserService(t)
+	userService.On(testutils.FunctionName(t, ports.UserService.AuthorizeUpdate), mock.Anything, models.Caller{}, "test-id", models.UpdateUserReq{Name: &name}).
+		Return(wrappers.NewNonExistentErr(fmt.Errorf("ID test-id not found"))).Once()
+
+	server := NewUserServer(testConfig(), userService)
+
+	// Act
+	_, err := server.UpdateUser(context.Background(), &usersv1.UpdateUserRequest{Id: "test-id", Name: &name})
+
+	// Assert
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// listUsersStream is a ListUsers server stream recording the IDs of the users sent
+type listUsersStream struct {
+	testStream
+	IDs []string
+}
+
+func (s *listUsersStream) Send(resp *usersv1.ListUsersResponse) error {
+	s.IDs = append(s.IDs, resp.User.Id)
+	return nil
+}
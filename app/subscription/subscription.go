@@ -0,0 +1,47 @@
+package subscription
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// Middleware requires the users requesting the routes starting with any of the given prefixes to have a subscription granting access,
+// as synced from the billing provider, responding to the others with a 402.
+// The user is the one of the token, as set in the request info by the logging middleware, so the requests without a valid token
+// are left for the routes to refuse them, and the requests are refused when the subscription cannot be read.
+func Middleware(s ports.BillingService, prefixes []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := models.RequestInfoFrom(r.Context()).ActorID
+			if userID == "" || !required(r.URL.Path, prefixes) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			subscribed, err := s.Subscribed(r.Context(), userID)
+			if err != nil {
+				utils.ResponseError(w, r, nil, err)
+				return
+			}
+			if !subscribed {
+				utils.ResponseJSON(w, r, nil, http.StatusPaymentRequired, map[string]string{"error": "an active subscription is required"})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// required reports whether the path starts with any of the prefixes
+func required(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
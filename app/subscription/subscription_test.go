@@ -0,0 +1,100 @@
+package subscription
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRequest creates a request to the given path whose request info has the given user
+func newRequest(path, userID string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	return r.WithContext(models.WithRequestInfo(r.Context(), models.RequestInfo{ActorID: userID}))
+}
+
+// okHandler responds with a 200
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+// TestMiddleware_Subscribed checks that Middleware serves the requests of the users with a subscription granting access
+func TestMiddleware_Subscribed(t *testing.T) {
+	// Arrange
+	billingServiceMock := mocks.NewBillingService(t)
+	billingServiceMock.On(testutils.FunctionName(t, ports.BillingService.Subscribed), mock.Anything, "test-id").Return(true, nil).Once()
+
+	handler := Middleware(billingServiceMock, []string{"/v1/reports"})(okHandler)
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, newRequest("/v1/reports/monthly", "test-id"))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+// TestMiddleware_NotSubscribed checks that Middleware responds with a 402 to the users without a subscription granting access
+func TestMiddleware_NotSubscribed(t *testing.T) {
+	// Arrange
+	billingServiceMock := mocks.NewBillingService(t)
+	billingServiceMock.On(testutils.FunctionName(t, ports.BillingService.Subscribed), mock.Anything, "test-id").Return(false, nil).Once()
+
+	handler := Middleware(billingServiceMock, []string{"/v1/reports"})(okHandler)
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, newRequest("/v1/reports", "test-id"))
+
+	// Assert
+	assert.Equal(t, http.StatusPaymentRequired, rr.Code)
+}
+
+// TestMiddleware_NotRequiredRoute checks that Middleware does not check the subscription of the requests to other routes
+func TestMiddleware_NotRequiredRoute(t *testing.T) {
+	// Arrange
+	handler := Middleware(mocks.NewBillingService(t), []string{"/v1/reports"})(okHandler)
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, newRequest("/v1/users", "test-id"))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+// TestMiddleware_Anonymous checks that Middleware leaves the requests without a user to be refused by the routes
+func TestMiddleware_Anonymous(t *testing.T) {
+	// Arrange
+	handler := Middleware(mocks.NewBillingService(t), []string{"/v1/reports"})(okHandler)
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, newRequest("/v1/reports", ""))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+// TestMiddleware_ServiceError checks that Middleware refuses the requests when the subscription cannot be checked
+func TestMiddleware_ServiceError(t *testing.T) {
+	// Arrange
+	billingServiceMock := mocks.NewBillingService(t)
+	billingServiceMock.On(testutils.FunctionName(t, ports.BillingService.Subscribed), mock.Anything, "test-id").Return(false, errors.New("service error")).Once()
+
+	handler := Middleware(billingServiceMock, []string{"/v1/reports"})(okHandler)
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, newRequest("/v1/reports", "test-id"))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
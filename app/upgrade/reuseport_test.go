@@ -0,0 +1,40 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package upgrade
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListen_ReusePort checks that Listen lets several processes listen on the same port when reusing it
+func TestListen_ReusePort(t *testing.T) {
+	// Arrange
+	first, err := New(zerolog.Nop(), true, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := first.Listen(context.Background(), "api", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	second, err := New(zerolog.Nop(), true, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	other, err := second.Listen(context.Background(), "api", l.Addr().(*net.TCPAddr).Port)
+
+	// Assert
+	assert.Nil(t, err)
+	if err == nil {
+		other.Close()
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package upgrade
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+)
+
+// reusePort fails, as SO_REUSEPORT is not supported on this platform
+func reusePort(network, address string, c syscall.RawConn) error {
+	return fmt.Errorf("SO_REUSEPORT not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,252 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// environment variables through which the upgraded process inherits the listeners and the pipe to report it is ready
+const (
+	listenersEnv = "API_INHERITED_LISTENERS"
+	readyEnv     = "API_INHERITED_READY_FD"
+)
+
+// filer is a listener whose socket can be handed to another process
+type filer interface {
+	File() (*os.File, error)
+}
+
+// Upgrader hands the listeners of the process to a new copy of its binary, so the binary can be restarted without refusing connections:
+// the upgraded process listens on the inherited sockets and reports it is ready, then this one drains.
+type Upgrader struct {
+	logger    zerolog.Logger
+	reusePort bool
+	timeout   time.Duration
+	mu        sync.Mutex
+	inherited map[string]*os.File
+	listeners map[string]filer
+	ready     *os.File
+	upgrading atomic.Bool
+}
+
+// Inherited reports whether the process was started by an upgrade, inheriting the listeners of the previous one
+func Inherited() bool {
+	return os.Getenv(listenersEnv) != ""
+}
+
+// New creates an upgrader, picking up the listeners inherited from the previous process, if any.
+// The listeners created are set to reuse their port when reusePort is set, and the upgraded processes are given the timeout to be ready.
+func New(logger zerolog.Logger, reusePort bool, timeout time.Duration) (*Upgrader, error) {
+	u := &Upgrader{
+		logger:    logger,
+		reusePort: reusePort,
+		timeout:   timeout,
+		inherited: map[string]*os.File{},
+		listeners: map[string]filer{},
+	}
+
+	if value := os.Getenv(listenersEnv); value != "" {
+		for _, entry := range strings.Split(value, ",") {
+			name, fd, err := parseEntry(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%s not valid: %w", listenersEnv, err)
+			}
+			u.inherited[name] = os.NewFile(fd, name)
+		}
+	}
+	if value := os.Getenv(readyEnv); value != "" {
+		fd, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s not valid: %w", readyEnv, err)
+		}
+		u.ready = os.NewFile(uintptr(fd), "ready")
+	}
+	return u, nil
+}
+
+// parseEntry parses an inherited listener, like api=3
+func parseEntry(entry string) (string, uintptr, error) {
+	name, value, ok := strings.Cut(entry, "=")
+	if !ok || name == "" {
+		return "", 0, fmt.Errorf("listener %q must be like name=fd", entry)
+	}
+	fd, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return "", 0, fmt.Errorf("descriptor of listener %s not valid: %w", name, err)
+	}
+	return name, uintptr(fd), nil
+}
+
+// Listen returns the listener of the given name, the one inherited from the previous process when upgraded, or a new one on the TCP port otherwise
+func (u *Upgrader) Listen(ctx context.Context, name string, port int) (net.Listener, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var l net.Listener
+	var err error
+	if f, ok := u.inherited[name]; ok {
+		delete(u.inherited, name)
+		// the listener uses a duplicate of the inherited descriptor
+		l, err = net.FileListener(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("inherited listener %s not valid: %w", name, err)
+		}
+		u.logger.Info().Str("listener", name).Str("address", l.Addr().String()).Msg("listener inherited")
+	} else {
+		lc := net.ListenConfig{}
+		if u.reusePort {
+			lc.Control = reusePort
+		}
+		if l, err = lc.Listen(ctx, "tcp", fmt.Sprintf(":%d", port)); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := l.(filer)
+	if !ok {
+		l.Close()
+		return nil, fmt.Errorf("listener %s cannot be handed over", name)
+	}
+	u.listeners[name] = f
+	return l, nil
+}
+
+// Ready reports to the previous process, if upgraded, that this one serves, so the previous one can drain
+func (u *Upgrader) Ready() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.ready == nil {
+		return nil
+	}
+	defer func() {
+		u.ready.Close()
+		u.ready = nil
+	}()
+	_, err := u.ready.Write([]byte{1})
+	return err
+}
+
+// Upgrade starts the executable of the process again, with the same arguments and environment, handing it the listeners,
+// and waits up to the timeout for it to be ready, killing it otherwise. Once it returns without error, this process should drain and exit.
+func (u *Upgrader) Upgrade(ctx context.Context) error {
+	if !u.upgrading.CompareAndSwap(false, true) {
+		return errors.New("upgrade already in progress")
+	}
+	defer u.upgrading.Store(false)
+
+	path, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot find the executable: %w", err)
+	}
+
+	files, entries, err := u.files()
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// the extra files are given the descriptors following stdin, stdout and stderr, in order
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, w)
+	cmd.Env = append(environ(),
+		listenersEnv+"="+strings.Join(entries, ","),
+		fmt.Sprintf("%s=%d", readyEnv, 3+len(files)),
+	)
+	err = cmd.Start()
+	w.Close()
+	if err != nil {
+		return fmt.Errorf("cannot start the upgraded process: %w", err)
+	}
+	go cmd.Wait()
+	logger := u.logger.With().Int("pid", cmd.Process.Pid).Logger()
+	logger.Info().Msg("upgraded process started")
+
+	// the pipe is closed without being written when the upgraded process exits before being ready
+	ready := make(chan error, 1)
+	go func() {
+		_, err := r.Read(make([]byte, 1))
+		ready <- err
+	}()
+
+	timer := time.NewTimer(u.timeout)
+	defer timer.Stop()
+	select {
+	case err = <-ready:
+		if err != nil {
+			err = fmt.Errorf("upgraded process exited before being ready: %w", err)
+		}
+	case <-timer.C:
+		err = fmt.Errorf("upgraded process not ready after %s", u.timeout)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
+		cmd.Process.Kill()
+		return err
+	}
+	logger.Info().Msg("upgraded process ready")
+	return nil
+}
+
+// files returns duplicates of the sockets of the listeners, in the order of their names, and the entries telling their descriptors in the upgraded process
+func (u *Upgrader) files() ([]*os.File, []string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	names := make([]string, 0, len(u.listeners))
+	for name := range u.listeners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var files []*os.File
+	var entries []string
+	for _, name := range names {
+		f, err := u.listeners[name].File()
+		if err != nil {
+			return files, nil, fmt.Errorf("cannot hand over listener %s: %w", name, err)
+		}
+		files = append(files, f)
+		entries = append(entries, fmt.Sprintf("%s=%d", name, 3+len(files)-1))
+	}
+	return files, entries, nil
+}
+
+// environ returns the environment of the process without the variables of the upgrades, replaced for the upgraded process
+func environ() []string {
+	var env []string
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, listenersEnv+"=") || strings.HasPrefix(e, readyEnv+"=") {
+			continue
+		}
+		env = append(env, e)
+	}
+	return env
+}
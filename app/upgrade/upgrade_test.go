@@ -0,0 +1,125 @@
+package upgrade
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListen_New checks that Listen creates a new listener when the process was not upgraded, to be handed over on upgrade
+func TestListen_New(t *testing.T) {
+	// Arrange
+	u, err := New(zerolog.Nop(), false, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	l, err := u.Listen(context.Background(), "api", 0)
+
+	// Assert
+	assert.Nil(t, err)
+	defer l.Close()
+	assert.Contains(t, u.listeners, "api")
+	assert.False(t, Inherited())
+}
+
+// TestListen_Inherited checks that Listen returns the listener inherited from the previous process
+func TestListen_Inherited(t *testing.T) {
+	// Arrange
+	previous, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer previous.Close()
+	f, err := previous.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(listenersEnv, "api="+strconv.Itoa(int(f.Fd())))
+
+	u, err := New(zerolog.Nop(), false, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	l, err := u.Listen(context.Background(), "api", 0)
+
+	// Assert
+	assert.Nil(t, err)
+	defer l.Close()
+	assert.Equal(t, previous.Addr().String(), l.Addr().String())
+	assert.True(t, Inherited())
+}
+
+// TestNew_InvalidListeners checks that New returns an error when the inherited listeners are not valid
+func TestNew_InvalidListeners(t *testing.T) {
+	// Arrange
+	t.Setenv(listenersEnv, "api")
+
+	// Act
+	_, err := New(zerolog.Nop(), false, time.Minute)
+
+	// Assert
+	assert.EqualError(t, err, `API_INHERITED_LISTENERS not valid: listener "api" must be like name=fd`)
+}
+
+// TestReady_Ok checks that Ready reports the previous process through the inherited pipe, only once
+func TestReady_Ok(t *testing.T) {
+	// Arrange
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	t.Setenv(readyEnv, strconv.Itoa(int(w.Fd())))
+
+	u, err := New(zerolog.Nop(), false, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = u.Ready()
+	secondErr := u.Ready()
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, secondErr)
+	n, _ := r.Read(make([]byte, 1))
+	assert.Equal(t, 1, n)
+}
+
+// TestFiles_Ok checks that files returns the sockets of the listeners with their descriptors in the upgraded process, in the order of their names
+func TestFiles_Ok(t *testing.T) {
+	// Arrange
+	u, err := New(zerolog.Nop(), false, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"diagnostics", "api"} {
+		l, err := u.Listen(context.Background(), name, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+	}
+
+	// Act
+	files, entries, err := u.files()
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, []string{"api=3", "diagnostics=4"}, entries)
+	for _, f := range files {
+		f.Close()
+	}
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// GetAuditEvents gets a page of the audit events matching the filters set in the request, newest first
+func (c *Client) GetAuditEvents(ctx context.Context, req models.GetAuditEventsReq) ([]models.AuditEventResp, error) {
+	query := auditFilters(req.Type, req.Outcome, req.UserID, req.ActorID, req.From, req.To)
+	setPage(query, req.Skip, req.Take)
+	var resp []models.AuditEventResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/audit", query: query}, &resp)
+	return resp, err
+}
+
+// ExportAuditEvents exports the audit events matching the filters set in the request as a CSV file, whose content must be closed
+func (c *Client) ExportAuditEvents(ctx context.Context, req models.ExportAuditEventsReq) (io.ReadCloser, error) {
+	query := auditFilters(req.Type, req.Outcome, req.UserID, req.ActorID, req.From, req.To)
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: "/v1/audit/export", query: query})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// CreateBackup starts the backup of the collection, returning its job
+func (c *Client) CreateBackup(ctx context.Context, collection string) (models.JobResp, error) {
+	var resp models.JobResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/backups/" + url.PathEscape(collection), status: http.StatusAccepted}, &resp)
+	return resp, err
+}
+
+// RestoreBackup starts the restore of the collection from the backup with the name, returning its job
+func (c *Client) RestoreBackup(ctx context.Context, collection, name string) (models.JobResp, error) {
+	var resp models.JobResp
+	path := "/v1/backups/" + url.PathEscape(collection) + "/" + url.PathEscape(name) + "/restore"
+	err := c.do(ctx, request{method: http.MethodPost, path: path, status: http.StatusAccepted}, &resp)
+	return resp, err
+}
+
+// GetJobs gets a page of the jobs matching the filters set in the request
+func (c *Client) GetJobs(ctx context.Context, req models.GetJobsReq) ([]models.JobResp, error) {
+	query := url.Values{}
+	if req.Status != "" {
+		query.Set("status", req.Status)
+	}
+	if req.Type != "" {
+		query.Set("type", req.Type)
+	}
+	setPage(query, req.Skip, req.Take)
+	var resp []models.JobResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/jobs", query: query}, &resp)
+	return resp, err
+}
+
+// GetJobByID gets the job with the ID
+func (c *Client) GetJobByID(ctx context.Context, ID string) (models.JobResp, error) {
+	var resp models.JobResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/jobs/" + url.PathEscape(ID)}, &resp)
+	return resp, err
+}
+
+// RetryJob queues again the failed or dead job with the ID
+func (c *Client) RetryJob(ctx context.Context, ID string) (models.JobResp, error) {
+	var resp models.JobResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/jobs/" + url.PathEscape(ID) + "/retry"}, &resp)
+	return resp, err
+}
+
+// GetMaintenanceTasks gets the maintenance tasks that can be started
+func (c *Client) GetMaintenanceTasks(ctx context.Context) ([]models.MaintenanceTaskResp, error) {
+	var resp []models.MaintenanceTaskResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/maintenance/tasks"}, &resp)
+	return resp, err
+}
+
+// StartMaintenanceTask starts the maintenance task with the name, returning its job
+func (c *Client) StartMaintenanceTask(ctx context.Context, task string) (models.JobResp, error) {
+	var resp models.JobResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/maintenance/tasks/" + url.PathEscape(task), status: http.StatusAccepted}, &resp)
+	return resp, err
+}
+
+// ApplyRetention applies the retention policies, or only reports the documents they apply to in a dry run
+func (c *Client) ApplyRetention(ctx context.Context, dryRun bool) (models.RetentionResp, error) {
+	var resp models.RetentionResp
+	query := url.Values{"dry_run": {strconv.FormatBool(dryRun)}}
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/retention", query: query}, &resp)
+	return resp, err
+}
+
+// GetScheduledJobs gets the scheduled jobs with their last runs
+func (c *Client) GetScheduledJobs(ctx context.Context) ([]models.ScheduledJobResp, error) {
+	var resp []models.ScheduledJobResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/scheduler/jobs"}, &resp)
+	return resp, err
+}
+
+// GetConfig gets the running config, without its secrets, and the features enabled
+func (c *Client) GetConfig(ctx context.Context) (models.ConfigResp, error) {
+	var resp models.ConfigResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/config"}, &resp)
+	return resp, err
+}
+
+// GetMetrics gets the runtime metrics
+func (c *Client) GetMetrics(ctx context.Context) (map[string]interface{}, error) {
+	var resp map[string]interface{}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/metrics"}, &resp)
+	return resp, err
+}
+
+// GetCaptureMode gets the capture mode
+func (c *Client) GetCaptureMode(ctx context.Context) (models.CaptureModeResp, error) {
+	var resp models.CaptureModeResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/debug/capture"}, &resp)
+	return resp, err
+}
+
+// EnableCaptureMode enables the capture of a percentage of the requests for a duration
+func (c *Client) EnableCaptureMode(ctx context.Context, req models.CaptureModeReq) (models.CaptureModeResp, error) {
+	var resp models.CaptureModeResp
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/debug/capture", body: req}, &resp)
+	return resp, err
+}
+
+// DisableCaptureMode disables the capture of the requests
+func (c *Client) DisableCaptureMode(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/debug/capture"}, nil)
+}
+
+// GetCaptures gets a page of the requests captured, newest first
+func (c *Client) GetCaptures(ctx context.Context, req models.GetCapturesReq) ([]models.CaptureResp, error) {
+	query := url.Values{}
+	setPage(query, req.Skip, req.Take)
+	var resp []models.CaptureResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/debug/captures", query: query}, &resp)
+	return resp, err
+}
+
+// auditFilters returns the query parameters of the audit event filters set
+func auditFilters(eventType, outcome, userID, actorID string, from, to *time.Time) url.Values {
+	query := url.Values{}
+	for param, value := range map[string]string{"type": eventType, "outcome": outcome, "user_id": userID, "actor_id": actorID} {
+		if value != "" {
+			query.Set(param, value)
+		}
+	}
+	for param, value := range map[string]*time.Time{"from": from, "to": to} {
+		if value != nil {
+			query.Set(param, value.Format(time.RFC3339))
+		}
+	}
+	return query
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetAuditEvents_Ok checks that GetAuditEvents sends the filters set as query parameters, with the times in RFC3339
+func TestGetAuditEvents_Ok(t *testing.T) {
+	// Arrange
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`[{"id":"test-id","type":"user.login"}]`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithToken("test-token"))
+	from := time.Date(2023, 8, 20, 9, 0, 0, 0, time.UTC)
+
+	// Act
+	resp, err := c.GetAuditEvents(context.Background(), models.GetAuditEventsReq{Type: "user.login", UserID: "test-user", From: &from, Skip: 5, Take: 10})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "from=2023-08-20T09%3A00%3A00Z&skip=5&take=10&type=user.login&user_id=test-user", query)
+	assert.Equal(t, "test-id", resp[0].ID)
+}
+
+// TestCreateBackup_Accepted checks that CreateBackup returns the job of the backup accepted
+func TestCreateBackup_Accepted(t *testing.T) {
+	// Arrange
+	var method, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"test-id","type":"backup","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithToken("test-token"))
+
+	// Act
+	resp, err := c.CreateBackup(context.Background(), "users")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/v1/backups/users", path)
+	assert.Equal(t, "test-id", resp.ID)
+}
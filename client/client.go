@@ -0,0 +1,347 @@
+// Package client is the Go client of the API, wrapping its REST endpoints with typed methods taking and returning the models of the API.
+// The client authenticates the requests with the token of the last login, logging in again when the token is refused if it has the credentials,
+// retries the requests failing with a server error or rate limited, and pages through the listings.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// defaults of the clients created without the options setting them
+const (
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+)
+
+// Error is returned when the API responds with a status other than the expected one, with the message of its error when it has one
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("api responded with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("api responded with status %d: %s", e.StatusCode, e.Message)
+}
+
+// IsStatus reports whether the error was returned as the API responded with the given status
+func IsStatus(err error, status int) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+// Client of the API, safe for concurrent use
+type Client struct {
+	baseURL        string
+	httpClient     *http.Client
+	userAgent      string
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	mu          sync.RWMutex
+	token       string
+	credentials *models.LoginUserReq
+}
+
+// Option sets an optional setting of a client
+type Option func(c *Client)
+
+// WithHTTPClient sets the HTTP client sending the requests, http.DefaultClient by default
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken sets the token authenticating the requests, like a long lived one of a service account
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithCredentials sets the credentials the client logs in with before its first authenticated request, and again whenever its token is refused
+func WithCredentials(email, password string) Option {
+	return func(c *Client) {
+		c.credentials = &models.LoginUserReq{Email: email, Password: password}
+	}
+}
+
+// WithRetries sets the maximum number of times a request is retried, 3 by default, zero disabling the retries,
+// and the wait before the first retry, doubled before every other one up to the maximum wait
+func WithRetries(maxRetries int, initialBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.initialBackoff = initialBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New creates a client of the API served at the base URL, like https://api.example.com
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		httpClient:     http.DefaultClient,
+		userAgent:      "go-hexagonal-api-client",
+		maxRetries:     defaultMaxRetries,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Token returns the token authenticating the requests, empty until set or logged in
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// SetToken sets the token authenticating the requests
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+// request is a request to the API
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	// body is the JSON body, or the raw one when contentType is set
+	body        interface{}
+	contentType string
+	// public is set for the requests not requiring a token
+	public bool
+	// status is the status of the successful responses, 200 when not set
+	status int
+}
+
+// do sends the request, decoding the JSON body of the response into out when set.
+// The authenticated requests are sent with the token, logging in first when there is none and the client has the credentials,
+// and are sent again once when the token is refused and the client can log in again.
+func (c *Client) do(ctx context.Context, req request, out interface{}) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// send sends the request, returning the response with the expected status, whose body must be closed, or an error otherwise
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	if !req.public && c.Token() == "" && c.hasCredentials() {
+		if err := c.relogin(ctx, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	token := c.Token()
+	resp, err := c.sendWithRetries(ctx, req, token)
+	if IsStatus(err, http.StatusUnauthorized) && !req.public && c.hasCredentials() {
+		if err := c.relogin(ctx, token); err != nil {
+			return nil, err
+		}
+		resp, err = c.sendWithRetries(ctx, req, c.Token())
+	}
+	return resp, err
+}
+
+// sendWithRetries sends the request with the token, retrying it while it fails with a retryable error,
+// waiting the backoff, or the Retry-After of the rate limited responses
+func (c *Client) sendWithRetries(ctx context.Context, req request, token string) (*http.Response, error) {
+	body, err := encodeBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	backoff := c.initialBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.attempt(ctx, req, body, token)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= c.maxRetries || !retryable(req.method, err) {
+			return nil, err
+		}
+
+		wait := backoff
+		var retryErr *retryAfterError
+		if errors.As(err, &retryErr) && retryErr.after > 0 {
+			wait = retryErr.after
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, err
+		}
+
+		backoff *= 2
+		if c.maxBackoff > 0 && backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+// attempt sends the request once
+func (c *Client) attempt(ctx context.Context, req request, body []byte, token string) (*http.Response, error) {
+	endpoint := c.baseURL + req.path
+	if len(req.query) > 0 {
+		endpoint += "?" + req.query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		contentType := req.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if token != "" && !req.public {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+
+	status := req.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if resp.StatusCode == status {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	var errBody struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&errBody); err == nil {
+		apiErr.Message = errBody.Error
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return nil, &retryAfterError{err: apiErr, after: time.Duration(seconds) * time.Second}
+	}
+	return nil, apiErr
+}
+
+// hasCredentials reports whether the client can log in
+func (c *Client) hasCredentials() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.credentials != nil
+}
+
+// relogin logs in with the credentials of the client unless its token changed from the refused one, as another request already logged in again
+func (c *Client) relogin(ctx context.Context, refused string) error {
+	c.mu.RLock()
+	credentials := *c.credentials
+	current := c.token
+	c.mu.RUnlock()
+	if current != refused {
+		return nil
+	}
+
+	_, err := c.Login(ctx, credentials)
+	return err
+}
+
+// encodeBody returns the body of the request, nil when it has none
+func encodeBody(req request) ([]byte, error) {
+	switch body := req.body.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return body, nil
+	default:
+		return json.Marshal(body)
+	}
+}
+
+// transportError is returned when a request cannot be sent or its response cannot be received
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// retryAfterError is returned when a request is rate limited, with the wait requested by the API
+type retryAfterError struct {
+	err   *Error
+	after time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// retryable reports whether a request failing with the error is retried: the rate limited ones, never processed,
+// and the idempotent ones failing with a server error or a transport error
+func retryable(method string, err error) bool {
+	var retryErr *retryAfterError
+	if errors.As(err, &retryErr) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+	var apiErr *Error
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusBadGateway || apiErr.StatusCode == http.StatusServiceUnavailable ||
+		apiErr.StatusCode == http.StatusGatewayTimeout || apiErr.StatusCode == http.StatusInternalServerError)
+}
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLogin_Ok checks that Login stores the token of the user, sending it with the following requests
+func TestLogin_Ok(t *testing.T) {
+	// Arrange
+	var credentials models.LoginUserReq
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/users/login":
+			json.NewDecoder(r.Body).Decode(&credentials)
+			w.Write([]byte(`{"user":{"id":"test-id"},"token":"test-token"}`))
+		default:
+			authorization = r.Header.Get("Authorization")
+			w.Write([]byte(`{"id":"test-id"}`))
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()))
+
+	// Act
+	resp, err := c.Login(context.Background(), models.LoginUserReq{Email: "test@example.com", Password: "test-password"})
+	_, getErr := c.GetUserByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, getErr)
+	assert.Equal(t, "test-id", resp.User.ID)
+	assert.Equal(t, "test@example.com", credentials.Email)
+	assert.Equal(t, "test-token", c.Token())
+	assert.Equal(t, "Bearer test-token", authorization)
+}
+
+// TestClient_Relogin checks that a client with credentials logs in before its first request, and again when its token is refused
+func TestClient_Relogin(t *testing.T) {
+	// Arrange
+	var logins int
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/users/login" {
+			logins++
+			w.Write([]byte(`{"token":"token-` + strconv.Itoa(logins) + `"}`))
+			return
+		}
+		requests = append(requests, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") == "Bearer token-1" && len(requests) > 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"token expired"}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithCredentials("test@example.com", "test-password"))
+
+	// Act
+	_, err := c.GetAllUsers(context.Background())
+	_, secondErr := c.GetAllUsers(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, secondErr)
+	assert.Equal(t, 2, logins)
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-1", "Bearer token-2"}, requests)
+}
+
+// TestClient_Unauthorized checks that a client without credentials returns the error of the token refused
+func TestClient_Unauthorized(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"token expired"}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithToken("test-token"))
+
+	// Act
+	_, err := c.GetAllUsers(context.Background())
+
+	// Assert
+	assert.True(t, IsStatus(err, http.StatusUnauthorized))
+	assert.Equal(t, "api responded with status 401: token expired", err.Error())
+}
+
+// TestClient_RetryServerError checks that the idempotent requests failing with a server error are retried
+func TestClient_RetryServerError(t *testing.T) {
+	// Arrange
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"test-id"}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithRetries(3, time.Millisecond, 2*time.Millisecond))
+
+	// Act
+	resp, err := c.GetJobByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.ID)
+	assert.Equal(t, 3, attempts)
+}
+
+// TestClient_RetriesExhausted checks that the error of the last attempt is returned once the retries are exhausted
+func TestClient_RetriesExhausted(t *testing.T) {
+	// Arrange
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithRetries(2, time.Millisecond, time.Millisecond))
+
+	// Act
+	_, err := c.GetJobByID(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, IsStatus(err, http.StatusBadGateway))
+	assert.Equal(t, 3, attempts)
+}
+
+// TestClient_NoRetryNotIdempotent checks that the requests that are not idempotent are not retried after a server error
+func TestClient_NoRetryNotIdempotent(t *testing.T) {
+	// Arrange
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"test-error"}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithRetries(3, time.Millisecond, time.Millisecond))
+
+	// Act
+	_, err := c.CreateUser(context.Background(), models.CreateUserReq{Email: "test@example.com"})
+
+	// Assert
+	assert.True(t, IsStatus(err, http.StatusInternalServerError))
+	assert.Equal(t, "api responded with status 500: test-error", err.Error())
+	assert.Equal(t, 1, attempts)
+}
+
+// TestClient_RetryRateLimited checks that the rate limited requests are retried, whatever their method
+func TestClient_RetryRateLimited(t *testing.T) {
+	// Arrange
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"inserted_id":"test-id"}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithRetries(3, time.Millisecond, time.Millisecond))
+
+	// Act
+	resp, err := c.CreateUser(context.Background(), models.CreateUserReq{Email: "test@example.com"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.InsertedID)
+	assert.Equal(t, 2, attempts)
+}
+
+// TestClient_ContextCanceled checks that the retries stop when the context is done
+func TestClient_ContextCanceled(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithRetries(3, time.Hour, time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	_, err := c.GetJobByID(ctx, "test-id")
+
+	// Assert
+	assert.True(t, IsStatus(err, http.StatusServiceUnavailable))
+}
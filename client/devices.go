@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// RegisterDevice registers a device of the user with the ID receiving its push notifications
+func (c *Client) RegisterDevice(ctx context.Context, userID string, device models.RegisterDeviceReq) (models.DeviceResp, error) {
+	var resp models.DeviceResp
+	err := c.do(ctx, request{method: http.MethodPost, path: userPath(userID) + "/devices", body: device, status: http.StatusCreated}, &resp)
+	return resp, err
+}
+
+// GetDevices gets the devices registered by the user with the ID
+func (c *Client) GetDevices(ctx context.Context, userID string) ([]models.DeviceResp, error) {
+	var resp []models.DeviceResp
+	err := c.do(ctx, request{method: http.MethodGet, path: userPath(userID) + "/devices"}, &resp)
+	return resp, err
+}
+
+// DeleteDevice deletes the device with the ID of the user with the ID
+func (c *Client) DeleteDevice(ctx context.Context, userID, deviceID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: userPath(userID) + "/devices/" + url.PathEscape(deviceID)}, nil)
+}
+
+// SendSMSCode sends a verification code to the phone by SMS
+func (c *Client) SendSMSCode(ctx context.Context, req models.SendSMSCodeReq) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/v1/sms/codes", body: req}, nil)
+}
+
+// CheckSMSCode checks the verification code sent to the phone
+func (c *Client) CheckSMSCode(ctx context.Context, req models.CheckSMSCodeReq) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/v1/sms/codes/check", body: req}, nil)
+}
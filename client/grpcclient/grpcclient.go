@@ -0,0 +1,181 @@
+// Package grpcclient is the Go client of the gRPC contracts of the API, generated in the proto package,
+// with interceptors authenticating the calls with the token of the last login, logging in again when the token is refused if they have the credentials.
+package grpcclient
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+
+	usersv1 "github.com/sergicanet9/go-hexagonal-api/proto/users/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// publicMethods are the methods called without a token
+var publicMethods = map[string]bool{
+	usersv1.UserService_Login_FullMethodName:      true,
+	usersv1.UserService_CreateUser_FullMethodName: true,
+}
+
+// Authenticator authenticates the calls of a connection, safe for concurrent use
+type Authenticator struct {
+	mu          sync.Mutex
+	token       string
+	credentials *usersv1.LoginRequest
+}
+
+// AuthOption sets an optional setting of an authenticator
+type AuthOption func(a *Authenticator)
+
+// WithToken sets the token authenticating the calls, like a long lived one of a service account
+func WithToken(token string) AuthOption {
+	return func(a *Authenticator) {
+		a.token = token
+	}
+}
+
+// WithCredentials sets the credentials the authenticator logs in with before its first authenticated call, and again whenever its token is refused
+func WithCredentials(email, password string) AuthOption {
+	return func(a *Authenticator) {
+		a.credentials = &usersv1.LoginRequest{Email: email, Password: password}
+	}
+}
+
+// NewAuthenticator creates an authenticator, whose interceptors are set when dialing the API
+func NewAuthenticator(opts ...AuthOption) *Authenticator {
+	a := &Authenticator{}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Token returns the token authenticating the calls, empty until set or logged in
+func (a *Authenticator) Token() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.token
+}
+
+// SetToken sets the token authenticating the calls
+func (a *Authenticator) SetToken(token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = token
+}
+
+// UnaryClientInterceptor authenticates the unary calls, calling them again once when their token is refused and the authenticator can log in again
+func (a *Authenticator) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if publicMethods[method] {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		token, err := a.currentToken(ctx, cc, "")
+		if err != nil {
+			return err
+		}
+		err = invoker(withToken(ctx, token), method, req, reply, cc, opts...)
+		if status.Code(err) == codes.Unauthenticated && a.canLogin() {
+			if token, err = a.currentToken(ctx, cc, token); err != nil {
+				return err
+			}
+			err = invoker(withToken(ctx, token), method, req, reply, cc, opts...)
+		}
+		return err
+	}
+}
+
+// StreamClientInterceptor authenticates the streaming calls, which are not called again when their token is refused, as their messages may have been received already
+func (a *Authenticator) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if publicMethods[method] {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		token, err := a.currentToken(ctx, cc, "")
+		if err != nil {
+			return nil, err
+		}
+		return streamer(withToken(ctx, token), desc, cc, method, opts...)
+	}
+}
+
+// currentToken returns the token authenticating the calls, logging in first when it is the refused one, empty before the first login, and the authenticator has the credentials.
+// The logins are serialized, so the calls refused at once log in again only once.
+func (a *Authenticator) currentToken(ctx context.Context, cc *grpc.ClientConn, refused string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != refused || a.credentials == nil {
+		return a.token, nil
+	}
+
+	resp, err := usersv1.NewUserServiceClient(cc).Login(ctx, a.credentials)
+	if err != nil {
+		return "", err
+	}
+	a.token = resp.Token
+	return a.token, nil
+}
+
+// canLogin reports whether the authenticator has the credentials
+func (a *Authenticator) canLogin() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.credentials != nil
+}
+
+// withToken returns the context of a call sending the token in its authorization metadata, when set
+func withToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// Client of the gRPC contracts of the API
+type Client struct {
+	usersv1.UserServiceClient
+	conn *grpc.ClientConn
+	auth *Authenticator
+}
+
+// Dial connects to the gRPC server of the API at the target, like api.example.com:9090, authenticating the calls with the authenticator.
+// The connection uses TLS unless other transport credentials are set in the dial options.
+func Dial(target string, auth *Authenticator, opts ...grpc.DialOption) (*Client, error) {
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}))}
+	dialOpts = append(dialOpts, opts...)
+	dialOpts = append(dialOpts,
+		grpc.WithChainUnaryInterceptor(auth.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(auth.StreamClientInterceptor()),
+	)
+
+	conn, err := grpc.Dial(target, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		UserServiceClient: usersv1.NewUserServiceClient(conn),
+		conn:              conn,
+		auth:              auth,
+	}, nil
+}
+
+// Login logs in the user, authenticating the following calls of the client with its token
+func (c *Client) Login(ctx context.Context, in *usersv1.LoginRequest, opts ...grpc.CallOption) (*usersv1.LoginResponse, error) {
+	resp, err := c.UserServiceClient.Login(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.auth.SetToken(resp.Token)
+	return resp, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
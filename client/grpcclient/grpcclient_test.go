@@ -0,0 +1,154 @@
+package grpcclient
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	usersv1 "github.com/sergicanet9/go-hexagonal-api/proto/users/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testServer is a users server accepting the token of its last login, recording the authorization of the calls
+type testServer struct {
+	usersv1.UnimplementedUserServiceServer
+	mu             sync.Mutex
+	logins         int
+	token          string
+	authorizations []string
+}
+
+func (s *testServer) Login(ctx context.Context, req *usersv1.LoginRequest) (*usersv1.LoginResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.Password != "test-password" {
+		return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+	}
+	s.logins++
+	s.token = "token-" + strconv.Itoa(s.logins)
+	return &usersv1.LoginResponse{User: &usersv1.User{Email: req.Email}, Token: s.token}, nil
+}
+
+func (s *testServer) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.GetUserResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return &usersv1.GetUserResponse{User: &usersv1.User{Id: req.Id}}, nil
+}
+
+func (s *testServer) ListUsers(req *usersv1.ListUsersRequest, stream usersv1.UserService_ListUsersServer) error {
+	if err := s.authorize(stream.Context()); err != nil {
+		return err
+	}
+	return stream.Send(&usersv1.ListUsersResponse{User: &usersv1.User{Id: "test-id"}})
+}
+
+// authorize records the authorization of the call, refusing it when it is not the one of the last login
+func (s *testServer) authorize(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	md, _ := metadata.FromIncomingContext(ctx)
+	authorization := ""
+	if values := md.Get("authorization"); len(values) > 0 {
+		authorization = values[0]
+	}
+	s.authorizations = append(s.authorizations, authorization)
+	if authorization != "Bearer "+s.token {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return nil
+}
+
+// dial serves the server on an in-memory listener, returning a client connected to it
+func dial(t *testing.T, server *testServer, auth *Authenticator) *Client {
+	listener := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	usersv1.RegisterUserServiceServer(s, server)
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+
+	c, err := Dial("bufnet", auth,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// TestDial_Credentials checks that a client with credentials logs in before its first call, sending the token of the login
+func TestDial_Credentials(t *testing.T) {
+	// Arrange
+	server := &testServer{}
+	c := dial(t, server, NewAuthenticator(WithCredentials("test@example.com", "test-password")))
+
+	// Act
+	resp, err := c.GetUser(context.Background(), &usersv1.GetUserRequest{Id: "test-id"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.User.Id)
+	assert.Equal(t, 1, server.logins)
+	assert.Equal(t, []string{"Bearer token-1"}, server.authorizations)
+}
+
+// TestDial_Relogin checks that a client with credentials logs in again when its token is refused, calling again once
+func TestDial_Relogin(t *testing.T) {
+	// Arrange
+	server := &testServer{token: "token-0"}
+	c := dial(t, server, NewAuthenticator(WithToken("expired-token"), WithCredentials("test@example.com", "test-password")))
+
+	// Act
+	_, err := c.GetUser(context.Background(), &usersv1.GetUserRequest{Id: "test-id"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, server.logins)
+	assert.Equal(t, []string{"Bearer expired-token", "Bearer token-1"}, server.authorizations)
+}
+
+// TestDial_Unauthenticated checks that a client without credentials returns the status of the token refused
+func TestDial_Unauthenticated(t *testing.T) {
+	// Arrange
+	server := &testServer{token: "token-0"}
+	c := dial(t, server, NewAuthenticator(WithToken("expired-token")))
+
+	// Act
+	_, err := c.GetUser(context.Background(), &usersv1.GetUserRequest{Id: "test-id"})
+
+	// Assert
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, 0, server.logins)
+}
+
+// TestLogin_Ok checks that Login stores the token of the user, sending it with the following calls, streaming ones included
+func TestLogin_Ok(t *testing.T) {
+	// Arrange
+	server := &testServer{}
+	c := dial(t, server, NewAuthenticator())
+
+	// Act
+	_, err := c.Login(context.Background(), &usersv1.LoginRequest{Email: "test@example.com", Password: "test-password"})
+	stream, streamErr := c.ListUsers(context.Background(), &usersv1.ListUsersRequest{})
+	resp, recvErr := stream.Recv()
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, streamErr)
+	assert.Nil(t, recvErr)
+	assert.Equal(t, "test-id", resp.User.Id)
+	assert.Equal(t, "token-1", c.auth.Token())
+	assert.Equal(t, []string{"Bearer token-1"}, server.authorizations)
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// Health checks that the API is running
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodGet, path: "/health", public: true}, nil)
+}
+
+// Readiness runs the health checks of the dependencies of the API, failing with a 503 Error when it is down
+func (c *Client) Readiness(ctx context.Context) (models.ReadinessResp, error) {
+	var resp models.ReadinessResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/readyz", public: true}, &resp)
+	return resp, err
+}
+
+// Status gets the status, uptime and version of the API
+func (c *Client) Status(ctx context.Context) (models.StatusResp, error) {
+	var resp models.StatusResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/status", public: true}, &resp)
+	return resp, err
+}
+
+// Version gets the build information of the API
+func (c *Client) Version(ctx context.Context) (models.VersionResp, error) {
+	var resp models.VersionResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/version", public: true}, &resp)
+	return resp, err
+}
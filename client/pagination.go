@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// defaultPageSize is the size of the pages requested by the Each methods when the request does not set one
+const defaultPageSize = 100
+
+// EachAuditEvent calls fn with every audit event matching the filters set in the request, newest first, starting after its skip
+// and requesting pages of its take, until fn returns an error, which is returned
+func (c *Client) EachAuditEvent(ctx context.Context, req models.GetAuditEventsReq, fn func(event models.AuditEventResp) error) error {
+	req.Take = pageSize(req.Take)
+	for {
+		page, err := c.GetAuditEvents(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, event := range page {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		if len(page) < req.Take {
+			return nil
+		}
+		req.Skip += len(page)
+	}
+}
+
+// EachJob calls fn with every job matching the filters set in the request, starting after its skip
+// and requesting pages of its take, until fn returns an error, which is returned
+func (c *Client) EachJob(ctx context.Context, req models.GetJobsReq, fn func(job models.JobResp) error) error {
+	req.Take = pageSize(req.Take)
+	for {
+		page, err := c.GetJobs(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, job := range page {
+			if err := fn(job); err != nil {
+				return err
+			}
+		}
+		if len(page) < req.Take {
+			return nil
+		}
+		req.Skip += len(page)
+	}
+}
+
+// EachCapture calls fn with every request captured, newest first, starting after the skip of the request
+// and requesting pages of its take, until fn returns an error, which is returned
+func (c *Client) EachCapture(ctx context.Context, req models.GetCapturesReq, fn func(capture models.CaptureResp) error) error {
+	req.Take = pageSize(req.Take)
+	for {
+		page, err := c.GetCaptures(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, capture := range page {
+			if err := fn(capture); err != nil {
+				return err
+			}
+		}
+		if len(page) < req.Take {
+			return nil
+		}
+		req.Skip += len(page)
+	}
+}
+
+// EachIndexedUser calls fn with every user matching the search of the index, by relevance, starting after the skip of the request
+// and requesting pages of its take, until fn returns an error, which is returned
+func (c *Client) EachIndexedUser(ctx context.Context, req models.IndexSearchUsersReq, fn func(user models.UserResp) error) error {
+	req.Take = pageSize(req.Take)
+	for {
+		page, err := c.SearchUsersIndex(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, user := range page.Users {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+		req.Skip += len(page.Users)
+		if len(page.Users) < req.Take || int64(req.Skip) >= page.Total {
+			return nil
+		}
+	}
+}
+
+// pageSize returns the size of the pages requested, the take of the request when set
+func pageSize(take int) int {
+	if take > 0 {
+		return take
+	}
+	return defaultPageSize
+}
+
+// setPage sets the skip and take query parameters when set
+func setPage(query url.Values, skip, take int) {
+	if skip > 0 {
+		query.Set("skip", strconv.Itoa(skip))
+	}
+	if take > 0 {
+		query.Set("take", strconv.Itoa(take))
+	}
+}
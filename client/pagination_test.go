@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEachJob_Ok checks that EachJob requests the pages until a short one, calling fn with every job
+func TestEachJob_Ok(t *testing.T) {
+	// Arrange
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		switch r.URL.Query().Get("skip") {
+		case "":
+			w.Write([]byte(`[{"id":"1"},{"id":"2"}]`))
+		default:
+			w.Write([]byte(`[{"id":"3"}]`))
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithToken("test-token"))
+	var IDs []string
+
+	// Act
+	err := c.EachJob(context.Background(), models.GetJobsReq{Status: "dead", Take: 2}, func(job models.JobResp) error {
+		IDs = append(IDs, job.ID)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, IDs)
+	assert.Equal(t, []string{"status=dead&take=2", "skip=2&status=dead&take=2"}, queries)
+}
+
+// TestEachJob_FnError checks that EachJob stops at the first error of fn, returning it
+func TestEachJob_FnError(t *testing.T) {
+	// Arrange
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[{"id":"1"},{"id":"2"}]`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithToken("test-token"))
+	expectedErr := errors.New("test-error")
+
+	// Act
+	err := c.EachJob(context.Background(), models.GetJobsReq{Take: 2}, func(job models.JobResp) error {
+		return expectedErr
+	})
+
+	// Assert
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, 1, requests)
+}
+
+// TestEachIndexedUser_Ok checks that EachIndexedUser stops once the total of the matches is reached
+func TestEachIndexedUser_Ok(t *testing.T) {
+	// Arrange
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(fmt.Sprintf(`{"total":4,"users":[{"id":"%d"},{"id":"%d"}]}`, 2*requests-1, 2*requests)))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithToken("test-token"))
+	var IDs []string
+
+	// Act
+	err := c.EachIndexedUser(context.Background(), models.IndexSearchUsersReq{Query: "test", Take: 2}, func(user models.UserResp) error {
+		IDs = append(IDs, user.ID)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2", "3", "4"}, IDs)
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// Login logs in the user, authenticating the following requests of the client with its token
+func (c *Client) Login(ctx context.Context, credentials models.LoginUserReq) (models.LoginUserResp, error) {
+	var resp models.LoginUserResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/users/login", body: credentials, public: true}, &resp)
+	if err != nil {
+		return models.LoginUserResp{}, err
+	}
+	c.SetToken(resp.Token)
+	return resp, nil
+}
+
+// CreateUser creates a user, signing it up
+func (c *Client) CreateUser(ctx context.Context, user models.CreateUserReq) (models.CreationResp, error) {
+	var resp models.CreationResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/users", body: user, public: true, status: http.StatusCreated}, &resp)
+	return resp, err
+}
+
+// CreateManyUsers creates several users at once
+func (c *Client) CreateManyUsers(ctx context.Context, users []models.CreateUserReq) (models.MultiCreationResp, error) {
+	var resp models.MultiCreationResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/users/many", body: users, public: true, status: http.StatusCreated}, &resp)
+	return resp, err
+}
+
+// GetAllUsers gets all the users
+func (c *Client) GetAllUsers(ctx context.Context) ([]models.UserResp, error) {
+	var resp []models.UserResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users"}, &resp)
+	return resp, err
+}
+
+// SearchUsers searches the users by name, surnames or email in the database
+func (c *Client) SearchUsers(ctx context.Context, req models.SearchUsersReq) ([]models.UserResp, error) {
+	query := url.Values{"q": {req.Query}}
+	if req.Mode != "" {
+		query.Set("mode", req.Mode)
+	}
+	var resp []models.UserResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/search", query: query}, &resp)
+	return resp, err
+}
+
+// SearchUsersIndex searches a page of the users by name, surnames or email in the search index, with the facets of the matches
+func (c *Client) SearchUsersIndex(ctx context.Context, req models.IndexSearchUsersReq) (models.IndexSearchUsersResp, error) {
+	query := url.Values{"q": {req.Query}}
+	if req.Domain != "" {
+		query.Set("domain", req.Domain)
+	}
+	if req.Claim != nil {
+		query.Set("claim", strconv.FormatInt(*req.Claim, 10))
+	}
+	setPage(query, req.Skip, req.Take)
+	var resp models.IndexSearchUsersResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/search/index", query: query}, &resp)
+	return resp, err
+}
+
+// GetNearbyUsers gets the users located within the radius, in meters, of a point, sorted by distance
+func (c *Client) GetNearbyUsers(ctx context.Context, req models.NearbyUsersReq) ([]models.UserResp, error) {
+	query := url.Values{
+		"lng":    {strconv.FormatFloat(req.Longitude, 'f', -1, 64)},
+		"lat":    {strconv.FormatFloat(req.Latitude, 'f', -1, 64)},
+		"radius": {strconv.FormatFloat(req.Radius, 'f', -1, 64)},
+	}
+	var resp []models.UserResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/nearby", query: query}, &resp)
+	return resp, err
+}
+
+// GetUserByEmail gets the user with the email
+func (c *Client) GetUserByEmail(ctx context.Context, email string) (models.UserResp, error) {
+	var resp models.UserResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/email/" + url.PathEscape(email)}, &resp)
+	return resp, err
+}
+
+// UpsertUser creates the user with the email, or updates it when it already exists
+func (c *Client) UpsertUser(ctx context.Context, email string, user models.UpsertUserReq) (models.UpsertionResp, error) {
+	var resp models.UpsertionResp
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/users/email/" + url.PathEscape(email), body: user}, &resp)
+	return resp, err
+}
+
+// GetUserByID gets the user with the ID
+func (c *Client) GetUserByID(ctx context.Context, ID string) (models.UserResp, error) {
+	var resp models.UserResp
+	err := c.do(ctx, request{method: http.MethodGet, path: userPath(ID)}, &resp)
+	return resp, err
+}
+
+// UpdateUser updates the fields set in the request of the user with the ID
+func (c *Client) UpdateUser(ctx context.Context, ID string, user models.UpdateUserReq) error {
+	return c.do(ctx, request{method: http.MethodPatch, path: userPath(ID), body: user}, nil)
+}
+
+// MergeUsers merges the source user of the request into the user with the ID
+func (c *Client) MergeUsers(ctx context.Context, ID string, req models.MergeUsersReq) error {
+	return c.do(ctx, request{method: http.MethodPost, path: userPath(ID) + "/merge", body: req}, nil)
+}
+
+// UnarchiveUser restores the archived user with the ID
+func (c *Client) UnarchiveUser(ctx context.Context, ID string) error {
+	return c.do(ctx, request{method: http.MethodPost, path: userPath(ID) + "/unarchive"}, nil)
+}
+
+// DeleteUser deletes the user with the ID
+func (c *Client) DeleteUser(ctx context.Context, ID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: userPath(ID)}, nil)
+}
+
+// UploadUserAvatar uploads the avatar image of the user with the ID, named and typed as in the request
+func (c *Client) UploadUserAvatar(ctx context.Context, ID string, avatar models.UploadAvatarReq) (models.FileResp, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, avatar.Name))
+	header.Set("Content-Type", avatar.ContentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return models.FileResp{}, err
+	}
+	if _, err := io.Copy(part, avatar.Content); err != nil {
+		return models.FileResp{}, err
+	}
+	if err := writer.Close(); err != nil {
+		return models.FileResp{}, err
+	}
+
+	var resp models.FileResp
+	err = c.do(ctx, request{method: http.MethodPut, path: userPath(ID) + "/avatar", body: body.Bytes(), contentType: writer.FormDataContentType()}, &resp)
+	return resp, err
+}
+
+// GetUserAvatar gets the avatar image of the user with the ID, whose content must be closed, with its content type
+func (c *Client) GetUserAvatar(ctx context.Context, ID string) (io.ReadCloser, string, error) {
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: userPath(ID) + "/avatar"})
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.Body, resp.Header.Get("Content-Type"), nil
+}
+
+// DeleteUserAvatar deletes the avatar image of the user with the ID
+func (c *Client) DeleteUserAvatar(ctx context.Context, ID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: userPath(ID) + "/avatar"}, nil)
+}
+
+// GetUserAvatarURL gets a presigned URL downloading the avatar image of the user with the ID
+func (c *Client) GetUserAvatarURL(ctx context.Context, ID string) (models.PresignedURLResp, error) {
+	var resp models.PresignedURLResp
+	err := c.do(ctx, request{method: http.MethodGet, path: userPath(ID) + "/avatar/url"}, &resp)
+	return resp, err
+}
+
+// CreateUserAvatarUploadURL creates a presigned URL uploading the avatar image of the user with the ID directly to the storage
+func (c *Client) CreateUserAvatarUploadURL(ctx context.Context, ID string, req models.AvatarUploadURLReq) (models.PresignedURLResp, error) {
+	var resp models.PresignedURLResp
+	err := c.do(ctx, request{method: http.MethodPost, path: userPath(ID) + "/avatar/url", body: req, status: http.StatusCreated}, &resp)
+	return resp, err
+}
+
+// GetUserClaims gets the claims of the users matching the filters set, sorted by ID
+func (c *Client) GetUserClaims(ctx context.Context, req models.GetClaimsReq) ([]models.ClaimResp, error) {
+	query := url.Values{}
+	if req.Category != "" {
+		query.Set("category", req.Category)
+	}
+	if req.Deprecated != nil {
+		query.Set("deprecated", strconv.FormatBool(*req.Deprecated))
+	}
+	var resp []models.ClaimResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/claims", query: query}, &resp)
+	return resp, err
+}
+
+// userPath returns the path of the user with the ID
+func userPath(ID string) string {
+	return "/v1/users/" + url.PathEscape(ID)
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetUserByEmail_Ok checks that GetUserByEmail escapes the email in the path
+func TestGetUserByEmail_Ok(t *testing.T) {
+	// Arrange
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		w.Write([]byte(`{"id":"test-id","email":"test+1@example.com"}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithToken("test-token"))
+
+	// Act
+	resp, err := c.GetUserByEmail(context.Background(), "test+1@example.com")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test+1@example.com", resp.Email)
+	assert.Equal(t, "/v1/users/email/test+1@example.com", path)
+}
+
+// TestSearchUsersIndex_Ok checks that SearchUsersIndex sends the query and filters set as query parameters
+func TestSearchUsersIndex_Ok(t *testing.T) {
+	// Arrange
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"total":1,"users":[{"id":"test-id"}],"facets":{"domain":[{"value":"example.com","count":1}]}}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithToken("test-token"))
+	claim := int64(1)
+
+	// Act
+	resp, err := c.SearchUsersIndex(context.Background(), models.IndexSearchUsersReq{Query: "test", Domain: "example.com", Claim: &claim, Take: 10})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "claim=1&domain=example.com&q=test&take=10", query)
+	assert.Equal(t, int64(1), resp.Total)
+	assert.Equal(t, "test-id", resp.Users[0].ID)
+	assert.Equal(t, []models.FacetResp{{Value: "example.com", Count: 1}}, resp.Facets["domain"])
+}
+
+// TestUploadUserAvatar_Ok checks that UploadUserAvatar sends the avatar as the file of a multipart form, with its name and content type
+func TestUploadUserAvatar_Ok(t *testing.T) {
+	// Arrange
+	var name, contentType, content string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body, _ := io.ReadAll(file)
+		name, contentType, content = header.Filename, header.Header.Get("Content-Type"), string(body)
+		w.Write([]byte(`{"key":"avatars/test-id","name":"avatar.png"}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithToken("test-token"))
+
+	// Act
+	resp, err := c.UploadUserAvatar(context.Background(), "test-id", models.UploadAvatarReq{Name: "avatar.png", ContentType: "image/png", Content: strings.NewReader("test-content")})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "avatars/test-id", resp.Key)
+	assert.Equal(t, "avatar.png", name)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, "test-content", content)
+}
+
+// TestGetUserAvatar_Ok checks that GetUserAvatar returns the content of the avatar with its content type
+func TestGetUserAvatar_Ok(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("test-content"))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithHTTPClient(server.Client()), WithToken("test-token"))
+
+	// Act
+	content, contentType, err := c.GetUserAvatar(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	defer content.Close()
+	body, _ := io.ReadAll(content)
+	assert.Equal(t, "test-content", string(body))
+	assert.Equal(t, "image/png", contentType)
+}
@@ -0,0 +1,1389 @@
+// Code generated by gen-client from the OpenAPI document of the API. DO NOT EDIT.
+
+// Package apiclient is the Go client of the API, generated from its OpenAPI document.
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Client of the API. The Token is sent as a bearer token with the requests requiring it, so it is set before them,
+// like with the token returned by the login, and the client is not safe for concurrent use while it is changed.
+type Client struct {
+	// BaseURL of the API, like https://api.example.com
+	BaseURL string
+	// HTTPClient sending the requests, http.DefaultClient when not set
+	HTTPClient *http.Client
+	Token      string
+}
+
+// Error is returned when the API responds with a status other than the expected one, with the message of its error when it has one
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("api responded with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("api responded with status %d: %s", e.StatusCode, e.Message)
+}
+
+// request is a request to the API, sending either a JSON body or a file as a multipart form
+type request struct {
+	method  string
+	path    string
+	query   url.Values
+	body    interface{}
+	file    *formFile
+	status  int
+	secured bool
+}
+
+// formFile is a file uploaded in a multipart form field
+type formFile struct {
+	field       string
+	name        string
+	contentType string
+	content     io.Reader
+}
+
+// do sends the request, decoding the JSON body of the response into out when set
+func (c *Client) do(ctx context.Context, req request, out interface{}) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// send sends the request, returning the response with the expected status, whose body must be closed, or an error otherwise
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	var body io.Reader
+	var contentType string
+	switch {
+	case req.file != nil:
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf("form-data; name=%q; filename=%q", req.file.field, req.file.name))
+		header.Set("Content-Type", req.file.contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(part, req.file.content); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		body, contentType = &buf, writer.FormDataContentType()
+	case req.body != nil:
+		encoded, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, err
+		}
+		body, contentType = bytes.NewReader(encoded), "application/json"
+	}
+
+	endpoint := strings.TrimSuffix(c.BaseURL, "/") + req.path
+	if len(req.query) > 0 {
+		endpoint += "?" + req.query.Encode()
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if req.secured && c.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == req.status {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	var errBody struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&errBody); err == nil {
+		apiErr.Message = errBody.Error
+	}
+	return nil, apiErr
+}
+
+type APIKeyResp struct {
+	CreatedAt  string   `json:"created_at,omitempty"`
+	ExpiresAt  string   `json:"expires_at,omitempty"`
+	ID         string   `json:"id,omitempty"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Prefix     string   `json:"prefix,omitempty"`
+	RevokedAt  string   `json:"revoked_at,omitempty"`
+	Scopes     []string `json:"scopes,omitempty"`
+	UserID     string   `json:"user_id,omitempty"`
+}
+
+type APIKeySecretResp struct {
+	APIKey     string   `json:"api_key,omitempty"`
+	CreatedAt  string   `json:"created_at,omitempty"`
+	ExpiresAt  string   `json:"expires_at,omitempty"`
+	ID         string   `json:"id,omitempty"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Prefix     string   `json:"prefix,omitempty"`
+	RevokedAt  string   `json:"revoked_at,omitempty"`
+	Scopes     []string `json:"scopes,omitempty"`
+	UserID     string   `json:"user_id,omitempty"`
+}
+
+type AuditEventResp struct {
+	ActorID   string            `json:"actor_id,omitempty"`
+	CreatedAt string            `json:"created_at,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	ID        string            `json:"id,omitempty"`
+	IP        string            `json:"ip,omitempty"`
+	// Outcome is one of success, failure
+	Outcome string `json:"outcome,omitempty"`
+	Type    string `json:"type,omitempty"`
+	UserID  string `json:"user_id,omitempty"`
+}
+
+type AvatarUploadURLReq struct {
+	ContentType string `json:"content_type,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+}
+
+type CaptureModeReq struct {
+	Duration   string  `json:"duration,omitempty"`
+	Percentage float64 `json:"percentage,omitempty"`
+}
+
+type CaptureModeResp struct {
+	Enabled    bool    `json:"enabled,omitempty"`
+	ExpiresAt  string  `json:"expires_at,omitempty"`
+	Percentage float64 `json:"percentage,omitempty"`
+}
+
+type CaptureResp struct {
+	ActorID      string `json:"actor_id,omitempty"`
+	CreatedAt    string `json:"created_at,omitempty"`
+	ID           string `json:"id,omitempty"`
+	Method       string `json:"method,omitempty"`
+	Path         string `json:"path,omitempty"`
+	Query        string `json:"query,omitempty"`
+	RequestBody  string `json:"request_body,omitempty"`
+	RequestID    string `json:"request_id,omitempty"`
+	ResponseBody string `json:"response_body,omitempty"`
+	Status       int64  `json:"status,omitempty"`
+}
+
+type CheckSMSCodeReq struct {
+	Code  string `json:"code,omitempty"`
+	Phone string `json:"phone,omitempty"`
+	// Purpose is one of otp, phone_verification
+	Purpose string `json:"purpose,omitempty"`
+}
+
+type ClaimResp struct {
+	Category    string `json:"category,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Description string `json:"description,omitempty"`
+	ID          int64  `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+type ConfigResp struct {
+	Config   map[string]interface{} `json:"config,omitempty"`
+	Features map[string]bool        `json:"features,omitempty"`
+}
+
+type CreateAPIKeyReq struct {
+	ExpiresAt string   `json:"expires_at,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Scopes    []string `json:"scopes,omitempty"`
+}
+
+type CreateOrganizationReq struct {
+	Name string `json:"name,omitempty"`
+}
+
+type CreateRoleReq struct {
+	Description string   `json:"description,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+type CreateUserReq struct {
+	Claims   []int64   `json:"claims,omitempty"`
+	Email    string    `json:"email,omitempty"`
+	Location *GeoPoint `json:"location,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Password string    `json:"password,omitempty"`
+	Surnames string    `json:"surnames,omitempty"`
+}
+
+type CreationResp struct {
+	InsertedID string `json:"inserted_id,omitempty"`
+}
+
+type DashboardResp struct {
+	ComputedAt                string         `json:"computed_at,omitempty"`
+	FailedLoginsLastHour      int64          `json:"failed_logins_last_hour,omitempty"`
+	JobBacklog                int64          `json:"job_backlog,omitempty"`
+	LoginsWithinTokenLifetime int64          `json:"logins_within_token_lifetime,omitempty"`
+	SignupsToday              int64          `json:"signups_today,omitempty"`
+	Users                     *UserStatsResp `json:"users,omitempty"`
+	WebhookFailures           int64          `json:"webhook_failures,omitempty"`
+}
+
+type DeviceResp struct {
+	CreatedAt string `json:"created_at,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Platform  string `json:"platform,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+type DirectorySyncChangeResp struct {
+	Email    string   `json:"email,omitempty"`
+	Fields   []string `json:"fields,omitempty"`
+	ID       string   `json:"id,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Surnames string   `json:"surnames,omitempty"`
+}
+
+type DirectorySyncReportResp struct {
+	Created     []DirectorySyncChangeResp `json:"created,omitempty"`
+	Deactivated []DirectorySyncChangeResp `json:"deactivated,omitempty"`
+	DryRun      bool                      `json:"dry_run,omitempty"`
+	GeneratedAt string                    `json:"generated_at,omitempty"`
+	JobID       string                    `json:"job_id,omitempty"`
+	Skipped     int64                     `json:"skipped,omitempty"`
+	Unchanged   int64                     `json:"unchanged,omitempty"`
+	Updated     []DirectorySyncChangeResp `json:"updated,omitempty"`
+}
+
+type FacetResp struct {
+	Count int64  `json:"count,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+type FileResp struct {
+	ContentType string            `json:"content_type,omitempty"`
+	CreatedAt   string            `json:"created_at,omitempty"`
+	Key         string            `json:"key,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Size        int64             `json:"size,omitempty"`
+}
+
+type GeoPoint struct {
+	Coordinates []float64 `json:"coordinates,omitempty"`
+	Type        string    `json:"type,omitempty"`
+}
+
+type HealthCheckResp struct {
+	Critical  bool    `json:"critical,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMS float64 `json:"latency_ms,omitempty"`
+	// Status is one of up, down
+	Status string `json:"status,omitempty"`
+}
+
+type IndexSearchUsersResp struct {
+	Facets map[string][]FacetResp `json:"facets,omitempty"`
+	Total  int64                  `json:"total,omitempty"`
+	Users  []UserResp             `json:"users,omitempty"`
+}
+
+type InviteMemberReq struct {
+	Email string `json:"email,omitempty"`
+	// Role is one of admin, member
+	Role string `json:"role,omitempty"`
+}
+
+type JWKResp struct {
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Kty string `json:"kty,omitempty"`
+	Use string `json:"use,omitempty"`
+}
+
+type JWKSResp struct {
+	Keys []JWKResp `json:"keys,omitempty"`
+}
+
+type JobResp struct {
+	Attempts    int64             `json:"attempts,omitempty"`
+	CreatedAt   string            `json:"created_at,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	FinishedAt  string            `json:"finished_at,omitempty"`
+	ID          string            `json:"id,omitempty"`
+	LockedUntil string            `json:"locked_until,omitempty"`
+	MaxAttempts int64             `json:"max_attempts,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Processed   int64             `json:"processed,omitempty"`
+	RunAt       string            `json:"run_at,omitempty"`
+	// Status is one of queued, running, succeeded, failed, dead
+	Status    string `json:"status,omitempty"`
+	Type      string `json:"type,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+	Worker    string `json:"worker,omitempty"`
+}
+
+type LoginUserReq struct {
+	Email    string `json:"email,omitempty"`
+	MfaCode  string `json:"mfa_code,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+type LoginUserResp struct {
+	ExpiresAt string    `json:"expires_at,omitempty"`
+	Token     string    `json:"token,omitempty"`
+	User      *UserResp `json:"user,omitempty"`
+}
+
+type MaintenanceTaskResp struct {
+	Description string `json:"description,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+type MergeUsersReq struct {
+	SourceID string `json:"source_id,omitempty"`
+}
+
+type MultiCreationResp struct {
+	InsertedIDs []string `json:"inserted_ids,omitempty"`
+}
+
+type NotificationChannelsResp struct {
+	Email bool `json:"email,omitempty"`
+	Push  bool `json:"push,omitempty"`
+	SMS   bool `json:"sms,omitempty"`
+}
+
+type NotificationPreferencesResp struct {
+	Categories map[string]NotificationChannelsResp `json:"categories,omitempty"`
+	UpdatedAt  string                              `json:"updated_at,omitempty"`
+	UserID     string                              `json:"user_id,omitempty"`
+}
+
+type OAuthErrorResp struct {
+	// Error is one of invalid_request, invalid_grant, unsupported_grant_type
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+type OpenIDConfigurationResp struct {
+	ClaimsSupported                   []string `json:"claims_supported,omitempty"`
+	GrantTypesSupported               []string `json:"grant_types_supported,omitempty"`
+	Issuer                            string   `json:"issuer,omitempty"`
+	JwksURI                           string   `json:"jwks_uri,omitempty"`
+	ResponseTypesSupported            []string `json:"response_types_supported,omitempty"`
+	SubjectTypesSupported             []string `json:"subject_types_supported,omitempty"`
+	TokenEndpoint                     string   `json:"token_endpoint,omitempty"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported,omitempty"`
+}
+
+type OrganizationInvitationResp struct {
+	InvitedAt        string `json:"invited_at,omitempty"`
+	InvitedBy        string `json:"invited_by,omitempty"`
+	OrganizationID   string `json:"organization_id,omitempty"`
+	OrganizationName string `json:"organization_name,omitempty"`
+	// Role is one of admin, member
+	Role   string `json:"role,omitempty"`
+	UserID string `json:"user_id,omitempty"`
+}
+
+type OrganizationMemberResp struct {
+	JoinedAt string `json:"joined_at,omitempty"`
+	// Role is one of owner, admin, member
+	Role string    `json:"role,omitempty"`
+	User *UserResp `json:"user,omitempty"`
+}
+
+type OrganizationResp struct {
+	CreatedAt    string `json:"created_at,omitempty"`
+	ID           string `json:"id,omitempty"`
+	MembersCount int64  `json:"members_count,omitempty"`
+	Name         string `json:"name,omitempty"`
+	OwnerID      string `json:"owner_id,omitempty"`
+	// Role is one of owner, admin, member
+	Role      string `json:"role,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+type PasswordPolicyReq struct {
+	MinLength        int64 `json:"min_length,omitempty"`
+	RequireDigit     bool  `json:"require_digit,omitempty"`
+	RequireLowercase bool  `json:"require_lowercase,omitempty"`
+	RequireSymbol    bool  `json:"require_symbol,omitempty"`
+	RequireUppercase bool  `json:"require_uppercase,omitempty"`
+}
+
+type PasswordPolicyResp struct {
+	MinLength        int64 `json:"min_length,omitempty"`
+	RequireDigit     bool  `json:"require_digit,omitempty"`
+	RequireLowercase bool  `json:"require_lowercase,omitempty"`
+	RequireSymbol    bool  `json:"require_symbol,omitempty"`
+	RequireUppercase bool  `json:"require_uppercase,omitempty"`
+}
+
+type PresignedURLResp struct {
+	ExpiresAt string            `json:"expires_at,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Method    string            `json:"method,omitempty"`
+	URL       string            `json:"url,omitempty"`
+}
+
+type PutSecurityPolicyReq struct {
+	LockoutDuration    string             `json:"lockout_duration,omitempty"`
+	LockoutMaxFailures int64              `json:"lockout_max_failures,omitempty"`
+	MfaRequired        bool               `json:"mfa_required,omitempty"`
+	Password           *PasswordPolicyReq `json:"password,omitempty"`
+	SessionLifetime    string             `json:"session_lifetime,omitempty"`
+}
+
+type ReadinessResp struct {
+	Checks map[string]HealthCheckResp `json:"checks,omitempty"`
+	// Status is one of up, degraded, down
+	Status string `json:"status,omitempty"`
+}
+
+type RegisterDeviceReq struct {
+	Name string `json:"name,omitempty"`
+	// Platform is one of fcm, apns
+	Platform string `json:"platform,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+type RetentionResp struct {
+	DryRun  bool                  `json:"dry_run,omitempty"`
+	Results []RetentionResultResp `json:"results,omitempty"`
+}
+
+type RetentionResultResp struct {
+	// Action is one of purge, anonymize
+	Action     string `json:"action,omitempty"`
+	Before     string `json:"before,omitempty"`
+	Collection string `json:"collection,omitempty"`
+	Count      int64  `json:"count,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type RoleResp struct {
+	CreatedAt       string   `json:"created_at,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	ID              string   `json:"id,omitempty"`
+	Name            string   `json:"name,omitempty"`
+	OrganizationIDs []string `json:"organization_ids,omitempty"`
+	Permissions     []string `json:"permissions,omitempty"`
+	UpdatedAt       string   `json:"updated_at,omitempty"`
+	UserIDs         []string `json:"user_ids,omitempty"`
+}
+
+type ScheduledJobResp struct {
+	Enabled   bool              `json:"enabled,omitempty"`
+	LastRun   *ScheduledRunResp `json:"last_run,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	NextRunAt string            `json:"next_run_at,omitempty"`
+	Overlaps  int64             `json:"overlaps,omitempty"`
+	Running   bool              `json:"running,omitempty"`
+	Schedule  string            `json:"schedule,omitempty"`
+}
+
+type ScheduledRunResp struct {
+	Error      string `json:"error,omitempty"`
+	FinishedAt string `json:"finished_at,omitempty"`
+	StartedAt  string `json:"started_at,omitempty"`
+	// Status is one of succeeded, failed, skipped
+	Status string `json:"status,omitempty"`
+}
+
+type SecurityPolicyResp struct {
+	CreatedAt          string              `json:"created_at,omitempty"`
+	LockoutDuration    string              `json:"lockout_duration,omitempty"`
+	LockoutMaxFailures int64               `json:"lockout_max_failures,omitempty"`
+	MfaRequired        bool                `json:"mfa_required,omitempty"`
+	Password           *PasswordPolicyResp `json:"password,omitempty"`
+	SessionLifetime    string              `json:"session_lifetime,omitempty"`
+	Tenant             string              `json:"tenant,omitempty"`
+	UpdatedAt          string              `json:"updated_at,omitempty"`
+}
+
+type SendSMSCodeReq struct {
+	Phone string `json:"phone,omitempty"`
+	// Purpose is one of otp, phone_verification
+	Purpose string `json:"purpose,omitempty"`
+	UserID  string `json:"user_id,omitempty"`
+}
+
+type StatusResp struct {
+	// Status is one of ok, degraded
+	Status        string `json:"status,omitempty"`
+	UptimeSeconds int64  `json:"uptime_seconds,omitempty"`
+	Version       string `json:"version,omitempty"`
+}
+
+type TokenResp struct {
+	AccessToken string `json:"access_token,omitempty"`
+	ExpiresIn   int64  `json:"expires_in,omitempty"`
+	TokenType   string `json:"token_type,omitempty"`
+}
+
+type TransferOwnershipReq struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
+type UpdateMemberReq struct {
+	// Role is one of admin, member
+	Role string `json:"role,omitempty"`
+}
+
+type UpdateNotificationChannelsReq struct {
+	Email bool `json:"email,omitempty"`
+	Push  bool `json:"push,omitempty"`
+	SMS   bool `json:"sms,omitempty"`
+}
+
+type UpdateNotificationPreferencesReq struct {
+	Categories map[string]UpdateNotificationChannelsReq `json:"categories,omitempty"`
+}
+
+type UpdateOrganizationReq struct {
+	Name string `json:"name,omitempty"`
+}
+
+type UpdateRoleReq struct {
+	Description string   `json:"description,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+type UpdateUserReq struct {
+	Claims      []int64   `json:"claims,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	Location    *GeoPoint `json:"location,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	NewPassword string    `json:"new_password,omitempty"`
+	OldPassword string    `json:"old_password,omitempty"`
+	Surnames    string    `json:"surnames,omitempty"`
+}
+
+type UpsertUserReq struct {
+	Claims   []int64   `json:"claims,omitempty"`
+	Location *GeoPoint `json:"location,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Password string    `json:"password,omitempty"`
+	Surnames string    `json:"surnames,omitempty"`
+}
+
+type UpsertionResp struct {
+	ID string `json:"id,omitempty"`
+}
+
+type UserResp struct {
+	BillingCustomerID  string    `json:"billing_customer_id,omitempty"`
+	Claims             []int64   `json:"claims,omitempty"`
+	CreatedAt          string    `json:"created_at,omitempty"`
+	Email              string    `json:"email,omitempty"`
+	ID                 string    `json:"id,omitempty"`
+	LastLoginAt        string    `json:"last_login_at,omitempty"`
+	Location           *GeoPoint `json:"location,omitempty"`
+	Name               string    `json:"name,omitempty"`
+	SubscriptionStatus string    `json:"subscription_status,omitempty"`
+	Surnames           string    `json:"surnames,omitempty"`
+	UpdatedAt          string    `json:"updated_at,omitempty"`
+}
+
+type UserStatsResp struct {
+	Active     int64  `json:"active,omitempty"`
+	Archived   int64  `json:"archived,omitempty"`
+	ComputedAt string `json:"computed_at,omitempty"`
+	Total      int64  `json:"total,omitempty"`
+}
+
+type VersionResp struct {
+	BuildDate string `json:"build_date,omitempty"`
+	Commit    string `json:"commit,omitempty"`
+	GoVersion string `json:"go_version,omitempty"`
+	Version   string `json:"version,omitempty"`
+}
+
+// GetJSONWebKeySet gets the public keys verifying the tokens, none as they are signed with HS256 by a secret key,
+// so the tokens are verified by the API itself rather than by the clients
+func (c *Client) GetJSONWebKeySet(ctx context.Context) (JWKSResp, error) {
+	var result JWKSResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/.well-known/jwks.json", status: http.StatusOK}, &result)
+	return result, err
+}
+
+// GetOpenIDConfiguration gets the OpenID Connect discovery document of the API, describing its issuer, token endpoint and key set,
+// so the OIDC client libraries can configure themselves against it.
+// Only access tokens are issued, by the token endpoint, so neither the openid scope, nor an authorization endpoint, nor id tokens are advertised
+func (c *Client) GetOpenIDConfiguration(ctx context.Context) (OpenIDConfigurationResp, error) {
+	var result OpenIDConfigurationResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/.well-known/openid-configuration", status: http.StatusOK}, &result)
+	return result, err
+}
+
+// HealthCheck runs a Health Check
+func (c *Client) HealthCheck(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodGet, path: "/health", status: http.StatusOK}, nil)
+}
+
+// ReadinessCheck runs the health checks of the dependencies, reporting the status and latency of each of them.
+// The API is degraded when only non critical checks fail, and down, responding with 503, when a critical one fails.
+func (c *Client) ReadinessCheck(ctx context.Context) (ReadinessResp, error) {
+	var result ReadinessResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/readyz", status: http.StatusOK}, &result)
+	return result, err
+}
+
+// Status reports the overall status of the API, ok or degraded, along with its uptime and version, for external status pages.
+// It does not require authentication nor expose the details of the checks, and reuses the last readiness check when recent.
+func (c *Client) Status(ctx context.Context) (StatusResp, error) {
+	var result StatusResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/status", status: http.StatusOK}, &result)
+	return result, err
+}
+
+// GetAdminDashboard gets the user counts, the signups today, the active sessions, the failed logins of the last hour, the webhook failures and the job backlog, aggregated at computed_at and reused for up to Dashboard.MaxAge
+func (c *Client) GetAdminDashboard(ctx context.Context) (DashboardResp, error) {
+	var result DashboardResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/admin/dashboard", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetAPIKeys gets the API keys of every user, without their secrets
+func (c *Client) GetAPIKeys(ctx context.Context) ([]APIKeyResp, error) {
+	var result []APIKeyResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/api-keys", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetAuditEventsParams are the query parameters of GetAuditEvents, the optional ones being sent when set
+type GetAuditEventsParams struct {
+	Type *string
+	// Outcome is one of success, failure
+	Outcome *string
+	UserID  *string
+	ActorID *string
+	From    *string
+	To      *string
+	Skip    *int64
+	Take    *int64
+}
+
+// GetAuditEvents gets the security relevant events, newest first
+func (c *Client) GetAuditEvents(ctx context.Context, params GetAuditEventsParams) ([]AuditEventResp, error) {
+	query := url.Values{}
+	if params.Type != nil {
+		query.Set("type", *params.Type)
+	}
+	if params.Outcome != nil {
+		query.Set("outcome", *params.Outcome)
+	}
+	if params.UserID != nil {
+		query.Set("user_id", *params.UserID)
+	}
+	if params.ActorID != nil {
+		query.Set("actor_id", *params.ActorID)
+	}
+	if params.From != nil {
+		query.Set("from", *params.From)
+	}
+	if params.To != nil {
+		query.Set("to", *params.To)
+	}
+	if params.Skip != nil {
+		query.Set("skip", strconv.FormatInt(*params.Skip, 10))
+	}
+	if params.Take != nil {
+		query.Set("take", strconv.FormatInt(*params.Take, 10))
+	}
+	var result []AuditEventResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/audit", query: query, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// ExportAuditEventsParams are the query parameters of ExportAuditEvents, the optional ones being sent when set
+type ExportAuditEventsParams struct {
+	Type *string
+	// Outcome is one of success, failure
+	Outcome *string
+	UserID  *string
+	ActorID *string
+	From    *string
+	To      *string
+}
+
+// ExportAuditEvents exports every security relevant event matching the filters as a CSV file, newest first, for compliance reviews.
+// The details of the events are exported as JSON objects, and the fields starting like a spreadsheet formula are prefixed with a quote.
+// The content returned must be closed.
+func (c *Client) ExportAuditEvents(ctx context.Context, params ExportAuditEventsParams) (io.ReadCloser, error) {
+	query := url.Values{}
+	if params.Type != nil {
+		query.Set("type", *params.Type)
+	}
+	if params.Outcome != nil {
+		query.Set("outcome", *params.Outcome)
+	}
+	if params.UserID != nil {
+		query.Set("user_id", *params.UserID)
+	}
+	if params.ActorID != nil {
+		query.Set("actor_id", *params.ActorID)
+	}
+	if params.From != nil {
+		query.Set("from", *params.From)
+	}
+	if params.To != nil {
+		query.Set("to", *params.To)
+	}
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: "/v1/audit/export", query: query, status: http.StatusOK, secured: true})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// GetAuditEventByID gets a security relevant event by ID
+func (c *Client) GetAuditEventByID(ctx context.Context, id string) (AuditEventResp, error) {
+	var result AuditEventResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/audit/" + url.PathEscape(id), status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// CreateBackup queues the dump of a collection to the file storage, its progress is reported by the returned job
+func (c *Client) CreateBackup(ctx context.Context, collection string) (JobResp, error) {
+	var result JobResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/backups/" + url.PathEscape(collection), status: http.StatusAccepted, secured: true}, &result)
+	return result, err
+}
+
+// RestoreBackup queues the replacement of all the documents of a collection with the ones of the named backup, its progress is reported by the returned job
+func (c *Client) RestoreBackup(ctx context.Context, collection string, name string) (JobResp, error) {
+	var result JobResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/backups/" + url.PathEscape(collection) + "/" + url.PathEscape(name) + "/restore", status: http.StatusAccepted, secured: true}, &result)
+	return result, err
+}
+
+// GetClaimsParams are the query parameters of GetClaims, the optional ones being sent when set
+type GetClaimsParams struct {
+	Category   *string
+	Deprecated *bool
+}
+
+// GetClaims gets the claims of the users matching the filters, sorted by ID, with their name, description, category and whether they are deprecated
+func (c *Client) GetClaims(ctx context.Context, params GetClaimsParams) ([]ClaimResp, error) {
+	query := url.Values{}
+	if params.Category != nil {
+		query.Set("category", *params.Category)
+	}
+	if params.Deprecated != nil {
+		query.Set("deprecated", strconv.FormatBool(*params.Deprecated))
+	}
+	var result []ClaimResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/claims", query: query, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetConfig gets the effective config loaded by the running instance, from the flags and the config files, with the secrets redacted, along with the state of the feature flags
+func (c *Client) GetConfig(ctx context.Context) (ConfigResp, error) {
+	var result ConfigResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/config", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetCaptureMode gets the debug capture mode of the instance serving the request
+func (c *Client) GetCaptureMode(ctx context.Context) (CaptureModeResp, error) {
+	var result CaptureModeResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/debug/capture", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// EnableCaptureMode captures the sanitized requests and responses of a percentage of the requests served by the instance serving the request, until the duration expires
+func (c *Client) EnableCaptureMode(ctx context.Context, body CaptureModeReq) (CaptureModeResp, error) {
+	var result CaptureModeResp
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/debug/capture", body: body, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// DisableCaptureMode disables the debug capture mode of the instance serving the request before it expires, keeping the captures
+func (c *Client) DisableCaptureMode(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/debug/capture", status: http.StatusOK, secured: true}, nil)
+}
+
+// GetCapturesParams are the query parameters of GetCaptures, the optional ones being sent when set
+type GetCapturesParams struct {
+	Skip *int64
+	Take *int64
+}
+
+// GetCaptures gets the captured requests not expired yet, newest first
+func (c *Client) GetCaptures(ctx context.Context, params GetCapturesParams) ([]CaptureResp, error) {
+	query := url.Values{}
+	if params.Skip != nil {
+		query.Set("skip", strconv.FormatInt(*params.Skip, 10))
+	}
+	if params.Take != nil {
+		query.Set("take", strconv.FormatInt(*params.Take, 10))
+	}
+	var result []CaptureResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/debug/captures", query: query, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// CreateDirectorySyncParams are the query parameters of CreateDirectorySync, the optional ones being sent when set
+type CreateDirectorySyncParams struct {
+	DryRun *bool
+}
+
+// CreateDirectorySync queues a sync of the users with the external directory, creating, updating and deactivating them, only reporting the changes unless dry_run is false. Its status and counts are reported by the returned job
+func (c *Client) CreateDirectorySync(ctx context.Context, params CreateDirectorySyncParams) (JobResp, error) {
+	query := url.Values{}
+	if params.DryRun != nil {
+		query.Set("dry_run", strconv.FormatBool(*params.DryRun))
+	}
+	var result JobResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/directory/syncs", query: query, status: http.StatusAccepted, secured: true}, &result)
+	return result, err
+}
+
+// GetDirectorySyncReport gets the report of the users created, updated and deactivated by a finished directory sync or, in dry run, that would be
+func (c *Client) GetDirectorySyncReport(ctx context.Context, id string) (DirectorySyncReportResp, error) {
+	var result DirectorySyncReportResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/directory/syncs/" + url.PathEscape(id) + "/report", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetJobsParams are the query parameters of GetJobs, the optional ones being sent when set
+type GetJobsParams struct {
+	// Status is one of queued, running, succeeded, failed, dead
+	Status *string
+	Type   *string
+	Skip   *int64
+	Take   *int64
+}
+
+// GetJobs gets the queued and run jobs, newest first, the dead letter being the dead ones
+func (c *Client) GetJobs(ctx context.Context, params GetJobsParams) ([]JobResp, error) {
+	query := url.Values{}
+	if params.Status != nil {
+		query.Set("status", *params.Status)
+	}
+	if params.Type != nil {
+		query.Set("type", *params.Type)
+	}
+	if params.Skip != nil {
+		query.Set("skip", strconv.FormatInt(*params.Skip, 10))
+	}
+	if params.Take != nil {
+		query.Set("take", strconv.FormatInt(*params.Take, 10))
+	}
+	var result []JobResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/jobs", query: query, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetJobByID gets the status and progress of a job
+func (c *Client) GetJobByID(ctx context.Context, id string) (JobResp, error) {
+	var result JobResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/jobs/" + url.PathEscape(id), status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// RetryJob queues again a job left in the dead letter, or a failed one waiting for its next attempt, with all its attempts
+func (c *Client) RetryJob(ctx context.Context, id string) (JobResp, error) {
+	var result JobResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/jobs/" + url.PathEscape(id) + "/retry", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetMaintenanceTasks gets the maintenance tasks that can be started
+func (c *Client) GetMaintenanceTasks(ctx context.Context) ([]MaintenanceTaskResp, error) {
+	var result []MaintenanceTaskResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/maintenance/tasks", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// StartMaintenanceTask queues the maintenance task, its progress is reported by the returned job
+func (c *Client) StartMaintenanceTask(ctx context.Context, task string) (JobResp, error) {
+	var result JobResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/maintenance/tasks/" + url.PathEscape(task), status: http.StatusAccepted, secured: true}, &result)
+	return result, err
+}
+
+// GetMetrics gets the runtime metrics, the authentication metrics, the duration of the database commands per collection and operation and the database server status
+func (c *Client) GetMetrics(ctx context.Context) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/metrics", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetOrganizations gets the organizations the caller is a member of, with its role in them, every organization for the admins
+func (c *Client) GetOrganizations(ctx context.Context) ([]OrganizationResp, error) {
+	var result []OrganizationResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/organizations", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// CreateOrganization creates an organization, the caller being its owner
+func (c *Client) CreateOrganization(ctx context.Context, body CreateOrganizationReq) (CreationResp, error) {
+	var result CreationResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/organizations", body: body, status: http.StatusCreated, secured: true}, &result)
+	return result, err
+}
+
+// GetCallerInvitations gets the pending invitations of the caller to the organizations
+func (c *Client) GetCallerInvitations(ctx context.Context) ([]OrganizationInvitationResp, error) {
+	var result []OrganizationInvitationResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/organizations/invitations", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetOrganizationByID gets an organization the caller is a member of
+func (c *Client) GetOrganizationByID(ctx context.Context, id string) (OrganizationResp, error) {
+	var result OrganizationResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/organizations/" + url.PathEscape(id), status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// UpdateOrganization updates an organization the caller is an admin of
+func (c *Client) UpdateOrganization(ctx context.Context, id string, body UpdateOrganizationReq) error {
+	return c.do(ctx, request{method: http.MethodPatch, path: "/v1/organizations/" + url.PathEscape(id), body: body, status: http.StatusOK, secured: true}, nil)
+}
+
+// DeleteOrganization deletes an organization the caller is the owner of, along with its members
+func (c *Client) DeleteOrganization(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/organizations/" + url.PathEscape(id), status: http.StatusOK, secured: true}, nil)
+}
+
+// GetOrganizationInvitations gets the pending invitations to an organization the caller is an admin of
+func (c *Client) GetOrganizationInvitations(ctx context.Context, id string) ([]OrganizationInvitationResp, error) {
+	var result []OrganizationInvitationResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/organizations/" + url.PathEscape(id) + "/invitations", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// InviteOrganizationMember invites the user with the email to an organization the caller is an admin of, with the admin or member role,
+// the user being a member once it accepts the invitation
+func (c *Client) InviteOrganizationMember(ctx context.Context, id string, body InviteMemberReq) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/v1/organizations/" + url.PathEscape(id) + "/invitations", body: body, status: http.StatusCreated, secured: true}, nil)
+}
+
+// AcceptOrganizationInvitation makes the caller a member of an organization it is invited to, with the role of the invitation
+func (c *Client) AcceptOrganizationInvitation(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/v1/organizations/" + url.PathEscape(id) + "/invitations/accept", status: http.StatusOK, secured: true}, nil)
+}
+
+// RemoveOrganizationInvitation revokes the invitation of a user to an organization the caller is an admin of, or declines the invitation of the caller
+func (c *Client) RemoveOrganizationInvitation(ctx context.Context, id string, userID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/organizations/" + url.PathEscape(id) + "/invitations/" + url.PathEscape(userID), status: http.StatusOK, secured: true}, nil)
+}
+
+// GetOrganizationMembers gets the members of an organization the caller is a member of, with their users, so the users are listed by organization
+func (c *Client) GetOrganizationMembers(ctx context.Context, id string) ([]OrganizationMemberResp, error) {
+	var result []OrganizationMemberResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/organizations/" + url.PathEscape(id) + "/members", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// UpdateOrganizationMember changes the role of a member of an organization the caller is an admin of, the role of the owner never changing
+func (c *Client) UpdateOrganizationMember(ctx context.Context, id string, userID string, body UpdateMemberReq) error {
+	return c.do(ctx, request{method: http.MethodPatch, path: "/v1/organizations/" + url.PathEscape(id) + "/members/" + url.PathEscape(userID), body: body, status: http.StatusOK, secured: true}, nil)
+}
+
+// RemoveOrganizationMember removes a member from an organization the caller is an admin of, or the caller from an organization it leaves, the owner never being removed
+func (c *Client) RemoveOrganizationMember(ctx context.Context, id string, userID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/organizations/" + url.PathEscape(id) + "/members/" + url.PathEscape(userID), status: http.StatusOK, secured: true}, nil)
+}
+
+// TransferOrganizationOwnership makes a member the owner of an organization the caller is the owner of, the caller becoming an admin of it
+func (c *Client) TransferOrganizationOwnership(ctx context.Context, id string, body TransferOwnershipReq) error {
+	return c.do(ctx, request{method: http.MethodPut, path: "/v1/organizations/" + url.PathEscape(id) + "/owner", body: body, status: http.StatusOK, secured: true}, nil)
+}
+
+// ApplyRetentionPoliciesParams are the query parameters of ApplyRetentionPolicies, the optional ones being sent when set
+type ApplyRetentionPoliciesParams struct {
+	DryRun *bool
+}
+
+// ApplyRetentionPolicies purges or anonymizes the documents older than the limits of the retention policies, only reporting how many they are unless dry_run is false
+func (c *Client) ApplyRetentionPolicies(ctx context.Context, params ApplyRetentionPoliciesParams) (RetentionResp, error) {
+	query := url.Values{}
+	if params.DryRun != nil {
+		query.Set("dry_run", strconv.FormatBool(*params.DryRun))
+	}
+	var result RetentionResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/retention", query: query, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetRoles gets all the roles, with their assignees
+func (c *Client) GetRoles(ctx context.Context) ([]RoleResp, error) {
+	var result []RoleResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/roles", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// CreateRole creates a role, whose permissions are the names of the claims it grants
+func (c *Client) CreateRole(ctx context.Context, body CreateRoleReq) (CreationResp, error) {
+	var result CreationResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/roles", body: body, status: http.StatusCreated, secured: true}, &result)
+	return result, err
+}
+
+// GetRoleByID gets a role, with its assignees
+func (c *Client) GetRoleByID(ctx context.Context, id string) (RoleResp, error) {
+	var result RoleResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/roles/" + url.PathEscape(id), status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// UpdateRole updates a role, its assignees being granted its new permissions once they log in again
+func (c *Client) UpdateRole(ctx context.Context, id string, body UpdateRoleReq) error {
+	return c.do(ctx, request{method: http.MethodPatch, path: "/v1/roles/" + url.PathEscape(id), body: body, status: http.StatusOK, secured: true}, nil)
+}
+
+// DeleteRole deletes a role, unassigning it from everyone
+func (c *Client) DeleteRole(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/roles/" + url.PathEscape(id), status: http.StatusOK, secured: true}, nil)
+}
+
+// AssignRoleToOrganization assigns a role to an organization, granting its members the permissions of the role once they log in again
+func (c *Client) AssignRoleToOrganization(ctx context.Context, id string, organizationID string) error {
+	return c.do(ctx, request{method: http.MethodPut, path: "/v1/roles/" + url.PathEscape(id) + "/organizations/" + url.PathEscape(organizationID), status: http.StatusOK, secured: true}, nil)
+}
+
+// UnassignRoleFromOrganization unassigns a role from an organization
+func (c *Client) UnassignRoleFromOrganization(ctx context.Context, id string, organizationID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/roles/" + url.PathEscape(id) + "/organizations/" + url.PathEscape(organizationID), status: http.StatusOK, secured: true}, nil)
+}
+
+// AssignRoleToUser assigns a role to a user, granting it the permissions of the role once it logs in again
+func (c *Client) AssignRoleToUser(ctx context.Context, id string, userID string) error {
+	return c.do(ctx, request{method: http.MethodPut, path: "/v1/roles/" + url.PathEscape(id) + "/users/" + url.PathEscape(userID), status: http.StatusOK, secured: true}, nil)
+}
+
+// UnassignRoleFromUser unassigns a role from a user
+func (c *Client) UnassignRoleFromUser(ctx context.Context, id string, userID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/roles/" + url.PathEscape(id) + "/users/" + url.PathEscape(userID), status: http.StatusOK, secured: true}, nil)
+}
+
+// GetScheduledJobs gets the jobs of the scheduler with their schedule, their next run and the result of their last run on the replica serving the request
+func (c *Client) GetScheduledJobs(ctx context.Context) ([]ScheduledJobResp, error) {
+	var result []ScheduledJobResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/scheduler/jobs", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetSecurityPolicies gets the security policies of every tenant having one, sorted by tenant
+func (c *Client) GetSecurityPolicies(ctx context.Context) ([]SecurityPolicyResp, error) {
+	var result []SecurityPolicyResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/security-policies", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetSecurityPolicy gets the security policy of a tenant, the domain of the emails of its users
+func (c *Client) GetSecurityPolicy(ctx context.Context, tenant string) (SecurityPolicyResp, error) {
+	var result SecurityPolicyResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/security-policies/" + url.PathEscape(tenant), status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// PutSecurityPolicy sets the security policy of a tenant, replacing the one it has, applied to the logins and the passwords of its users from then on
+func (c *Client) PutSecurityPolicy(ctx context.Context, tenant string, body PutSecurityPolicyReq) (SecurityPolicyResp, error) {
+	var result SecurityPolicyResp
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/security-policies/" + url.PathEscape(tenant), body: body, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// DeleteSecurityPolicy deletes the security policy of a tenant, its users being applied the settings of the config
+func (c *Client) DeleteSecurityPolicy(ctx context.Context, tenant string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/security-policies/" + url.PathEscape(tenant), status: http.StatusOK, secured: true}, nil)
+}
+
+// SendSMSCode sends a one-time code by text message to the phone
+func (c *Client) SendSMSCode(ctx context.Context, body SendSMSCodeReq) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/v1/sms/codes", body: body, status: http.StatusOK, secured: true}, nil)
+}
+
+// CheckSMSCode checks the one-time code sent by text message to the phone, each code being accepted once
+func (c *Client) CheckSMSCode(ctx context.Context, body CheckSMSCodeReq) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/v1/sms/codes/check", body: body, status: http.StatusOK, secured: true}, nil)
+}
+
+// GetAllUsers gets the caller along with the members of its organizations, every user for the admins
+func (c *Client) GetAllUsers(ctx context.Context) ([]UserResp, error) {
+	var result []UserResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// CreateUser creates a new user
+func (c *Client) CreateUser(ctx context.Context, body CreateUserReq) (CreationResp, error) {
+	var result CreationResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/users", body: body, status: http.StatusCreated}, &result)
+	return result, err
+}
+
+// GetUserByEmail gets a user by email
+func (c *Client) GetUserByEmail(ctx context.Context, email string) (UserResp, error) {
+	var result UserResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/email/" + url.PathEscape(email), status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// UpsertUser creates or updates the user with the given email, for idempotent provisioning
+func (c *Client) UpsertUser(ctx context.Context, email string, body UpsertUserReq) (UpsertionResp, error) {
+	var result UpsertionResp
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/users/email/" + url.PathEscape(email), body: body, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// LoginUser logs in an user. When the security policy of its tenant requires MFA, a login without mfa_code emails a one-time code to the user
+// and responds with a 401, the login being done again with the code.
+func (c *Client) LoginUser(ctx context.Context, body LoginUserReq) (LoginUserResp, error) {
+	var result LoginUserResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/users/login", body: body, status: http.StatusOK}, &result)
+	return result, err
+}
+
+// CreateManyUsers creates many users atomically
+func (c *Client) CreateManyUsers(ctx context.Context, body []CreateUserReq) (MultiCreationResp, error) {
+	var result MultiCreationResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/users/many", body: body, status: http.StatusCreated}, &result)
+	return result, err
+}
+
+// GetNearbyUsersParams are the query parameters of GetNearbyUsers, the optional ones being sent when set
+type GetNearbyUsersParams struct {
+	Lng    float64
+	Lat    float64
+	Radius float64
+}
+
+// GetNearbyUsers gets the users located within a radius of a point, sorted by distance
+func (c *Client) GetNearbyUsers(ctx context.Context, params GetNearbyUsersParams) ([]UserResp, error) {
+	query := url.Values{}
+	query.Set("lng", strconv.FormatFloat(params.Lng, 'f', -1, 64))
+	query.Set("lat", strconv.FormatFloat(params.Lat, 'f', -1, 64))
+	query.Set("radius", strconv.FormatFloat(params.Radius, 'f', -1, 64))
+	var result []UserResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/nearby", query: query, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// SearchUsersParams are the query parameters of SearchUsers, the optional ones being sent when set
+type SearchUsersParams struct {
+	Q string
+	// Mode is one of fuzzy, autocomplete
+	Mode *string
+}
+
+// SearchUsers searches users by name, surnames or email
+func (c *Client) SearchUsers(ctx context.Context, params SearchUsersParams) ([]UserResp, error) {
+	query := url.Values{}
+	query.Set("q", params.Q)
+	if params.Mode != nil {
+		query.Set("mode", *params.Mode)
+	}
+	var result []UserResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/search", query: query, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// SearchUsersInTheIndexParams are the query parameters of SearchUsersInTheIndex, the optional ones being sent when set
+type SearchUsersInTheIndexParams struct {
+	Q      string
+	Domain *string
+	Claim  *int64
+	Skip   *int64
+	Take   *int64
+}
+
+// SearchUsersInTheIndex searches users by name, surnames or email in the search index, by relevance and tolerating typos,
+// with the total count of matches and their counts by email domain and by claim
+func (c *Client) SearchUsersInTheIndex(ctx context.Context, params SearchUsersInTheIndexParams) (IndexSearchUsersResp, error) {
+	query := url.Values{}
+	query.Set("q", params.Q)
+	if params.Domain != nil {
+		query.Set("domain", *params.Domain)
+	}
+	if params.Claim != nil {
+		query.Set("claim", strconv.FormatInt(*params.Claim, 10))
+	}
+	if params.Skip != nil {
+		query.Set("skip", strconv.FormatInt(*params.Skip, 10))
+	}
+	if params.Take != nil {
+		query.Set("take", strconv.FormatInt(*params.Take, 10))
+	}
+	var result IndexSearchUsersResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/search/index", query: query, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetUserByID gets a user by ID
+func (c *Client) GetUserByID(ctx context.Context, id string) (UserResp, error) {
+	var result UserResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/" + url.PathEscape(id), status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// UpdateUser updates a user
+func (c *Client) UpdateUser(ctx context.Context, id string, body UpdateUserReq) error {
+	return c.do(ctx, request{method: http.MethodPatch, path: "/v1/users/" + url.PathEscape(id), body: body, status: http.StatusOK, secured: true}, nil)
+}
+
+// DeleteUser delete a user
+func (c *Client) DeleteUser(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/users/" + url.PathEscape(id), status: http.StatusOK, secured: true}, nil)
+}
+
+// GetUserAPIKeys gets the API keys of a user, newest first, without their secrets
+func (c *Client) GetUserAPIKeys(ctx context.Context, id string) ([]APIKeyResp, error) {
+	var result []APIKeyResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/" + url.PathEscape(id) + "/api-keys", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// CreateAPIKey creates an API key of a user, granting the claims of its scopes, responding with its secret, which cannot be read again
+func (c *Client) CreateAPIKey(ctx context.Context, id string, body CreateAPIKeyReq) (APIKeySecretResp, error) {
+	var result APIKeySecretResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/users/" + url.PathEscape(id) + "/api-keys", body: body, status: http.StatusCreated, secured: true}, &result)
+	return result, err
+}
+
+// RevokeAPIKey revokes an API key of a user, so it no longer authenticates the requests
+func (c *Client) RevokeAPIKey(ctx context.Context, id string, keyID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/users/" + url.PathEscape(id) + "/api-keys/" + url.PathEscape(keyID), status: http.StatusOK, secured: true}, nil)
+}
+
+// RotateAPIKey replaces the secret of an API key of a user, keeping its scopes and expiry, responding with the new secret, which cannot be read again
+func (c *Client) RotateAPIKey(ctx context.Context, id string, keyID string) (APIKeySecretResp, error) {
+	var result APIKeySecretResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/users/" + url.PathEscape(id) + "/api-keys/" + url.PathEscape(keyID) + "/rotate", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// GetUserAvatar gets the avatar image of a user
+// The content returned must be closed.
+func (c *Client) GetUserAvatar(ctx context.Context, id string) (io.ReadCloser, error) {
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: "/v1/users/" + url.PathEscape(id) + "/avatar", status: http.StatusOK, secured: true})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// UploadUserAvatar uploads the avatar image of a user, replacing the previous one. When the uploads are scanned, it is returned in quarantine, with its scan metadata pending, and replaces the previous one once found clean
+func (c *Client) UploadUserAvatar(ctx context.Context, id string, file io.Reader, filename, contentType string) (FileResp, error) {
+	var result FileResp
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/users/" + url.PathEscape(id) + "/avatar", file: &formFile{field: "file", name: filename, contentType: contentType, content: file}, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// DeleteUserAvatar deletes the avatar image of a user
+func (c *Client) DeleteUserAvatar(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/users/" + url.PathEscape(id) + "/avatar", status: http.StatusOK, secured: true}, nil)
+}
+
+// GetUserAvatarURL gets a presigned URL downloading the avatar image of a user directly from the file storage
+func (c *Client) GetUserAvatarURL(ctx context.Context, id string) (PresignedURLResp, error) {
+	var result PresignedURLResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/" + url.PathEscape(id) + "/avatar/url", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// CreateUserAvatarUploadURL creates a presigned URL uploading the avatar image of a user directly to the file storage, replacing the previous one, with the returned method and headers
+func (c *Client) CreateUserAvatarUploadURL(ctx context.Context, id string, body AvatarUploadURLReq) (PresignedURLResp, error) {
+	var result PresignedURLResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/users/" + url.PathEscape(id) + "/avatar/url", body: body, status: http.StatusCreated, secured: true}, &result)
+	return result, err
+}
+
+// GetDevices gets the devices registered by a user, the newest first
+func (c *Client) GetDevices(ctx context.Context, id string) ([]DeviceResp, error) {
+	var result []DeviceResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/" + url.PathEscape(id) + "/devices", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// RegisterDevice registers the token of a device of a user, given by FCM or APNs, so it receives the push notifications of the security events of the account
+func (c *Client) RegisterDevice(ctx context.Context, id string, body RegisterDeviceReq) (DeviceResp, error) {
+	var result DeviceResp
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/users/" + url.PathEscape(id) + "/devices", body: body, status: http.StatusCreated, secured: true}, &result)
+	return result, err
+}
+
+// DeleteDevice deletes a device of a user, so it no longer receives the push notifications
+func (c *Client) DeleteDevice(ctx context.Context, id string, device string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/users/" + url.PathEscape(id) + "/devices/" + url.PathEscape(device), status: http.StatusOK, secured: true}, nil)
+}
+
+// MergeUsers merges the source user into the user with the given ID and deletes the source user, atomically
+func (c *Client) MergeUsers(ctx context.Context, id string, body MergeUsersReq) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/v1/users/" + url.PathEscape(id) + "/merge", body: body, status: http.StatusOK, secured: true}, nil)
+}
+
+// GetNotificationPreferences gets the channels the notifications of every category are delivered to a user through, every channel being enabled for the categories never set
+func (c *Client) GetNotificationPreferences(ctx context.Context, id string) (NotificationPreferencesResp, error) {
+	var result NotificationPreferencesResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/users/" + url.PathEscape(id) + "/notification-preferences", status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// UpdateNotificationPreferences enables or disables the channels of the categories of the notifications of a user, the channels and categories not set being left as they are
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, id string, body UpdateNotificationPreferencesReq) (NotificationPreferencesResp, error) {
+	var result NotificationPreferencesResp
+	err := c.do(ctx, request{method: http.MethodPatch, path: "/v1/users/" + url.PathEscape(id) + "/notification-preferences", body: body, status: http.StatusOK, secured: true}, &result)
+	return result, err
+}
+
+// UnarchiveUser moves an archived user back to the active users
+func (c *Client) UnarchiveUser(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/v1/users/" + url.PathEscape(id) + "/unarchive", status: http.StatusOK, secured: true}, nil)
+}
+
+// Version reports the version, commit and build date of the binary serving the request, so the builds running in the fleet can be told apart during a rollout.
+// It does not require authentication.
+func (c *Client) Version(ctx context.Context) (VersionResp, error) {
+	var result VersionResp
+	err := c.do(ctx, request{method: http.MethodGet, path: "/version", status: http.StatusOK}, &result)
+	return result, err
+}
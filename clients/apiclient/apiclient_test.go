@@ -0,0 +1,76 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetUserByID_Ok checks that the generated client sends the token with the secured requests, decoding the response
+func TestGetUserByID_Ok(t *testing.T) {
+	// Arrange
+	var path, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{"id":"test id","claims":[1]}`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL + "/", HTTPClient: server.Client(), Token: "test-token"}
+
+	// Act
+	user, err := c.GetUserByID(context.Background(), "test id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "/v1/users/test%20id", path)
+	assert.Equal(t, "Bearer test-token", authorization)
+	assert.Equal(t, UserResp{ID: "test id", Claims: []int64{1}}, user)
+}
+
+// TestSearchUsersInTheIndex_Query checks that the generated client sends the required query parameters and only the optional ones set
+func TestSearchUsersInTheIndex_Query(t *testing.T) {
+	// Arrange
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"total":0}`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, HTTPClient: server.Client()}
+	claim := int64(2)
+
+	// Act
+	_, err := c.SearchUsersInTheIndex(context.Background(), SearchUsersInTheIndexParams{Q: "john", Claim: &claim})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]string{"q": {"john"}, "claim": {"2"}}, query)
+}
+
+// TestCreateUser_Error checks that the generated client returns the error of the API when the status is not the expected one
+func TestCreateUser_Error(t *testing.T) {
+	// Arrange
+	var body CreateUserReq
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"email not valid"}`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, HTTPClient: server.Client()}
+
+	// Act
+	_, err := c.CreateUser(context.Background(), CreateUserReq{Email: "test"})
+
+	// Assert
+	assert.Equal(t, "test", body.Email)
+	assert.Equal(t, &Error{StatusCode: http.StatusBadRequest, Message: "email not valid"}, err)
+}
@@ -0,0 +1,1150 @@
+// Code generated by gen-client from the OpenAPI document of the API. DO NOT EDIT.
+
+export interface APIKeyResp {
+  created_at?: string;
+  expires_at?: string;
+  id?: string;
+  last_used_at?: string;
+  name?: string;
+  prefix?: string;
+  revoked_at?: string;
+  scopes?: string[];
+  user_id?: string;
+}
+
+export interface APIKeySecretResp {
+  api_key?: string;
+  created_at?: string;
+  expires_at?: string;
+  id?: string;
+  last_used_at?: string;
+  name?: string;
+  prefix?: string;
+  revoked_at?: string;
+  scopes?: string[];
+  user_id?: string;
+}
+
+export interface AuditEventResp {
+  actor_id?: string;
+  created_at?: string;
+  details?: Record<string, string>;
+  id?: string;
+  ip?: string;
+  outcome?: "success" | "failure";
+  type?: string;
+  user_id?: string;
+}
+
+export interface AvatarUploadURLReq {
+  content_type?: string;
+  name?: string;
+  size?: number;
+}
+
+export interface CaptureModeReq {
+  duration?: string;
+  percentage?: number;
+}
+
+export interface CaptureModeResp {
+  enabled?: boolean;
+  expires_at?: string;
+  percentage?: number;
+}
+
+export interface CaptureResp {
+  actor_id?: string;
+  created_at?: string;
+  id?: string;
+  method?: string;
+  path?: string;
+  query?: string;
+  request_body?: string;
+  request_id?: string;
+  response_body?: string;
+  status?: number;
+}
+
+export interface CheckSMSCodeReq {
+  code?: string;
+  phone?: string;
+  purpose?: "otp" | "phone_verification";
+}
+
+export interface ClaimResp {
+  category?: string;
+  deprecated?: boolean;
+  description?: string;
+  id?: number;
+  name?: string;
+}
+
+export interface ConfigResp {
+  config?: Record<string, unknown>;
+  features?: Record<string, boolean>;
+}
+
+export interface CreateAPIKeyReq {
+  expires_at?: string;
+  name?: string;
+  scopes?: string[];
+}
+
+export interface CreateOrganizationReq {
+  name?: string;
+}
+
+export interface CreateRoleReq {
+  description?: string;
+  name?: string;
+  permissions?: string[];
+}
+
+export interface CreateUserReq {
+  claims?: number[];
+  email?: string;
+  location?: GeoPoint;
+  name?: string;
+  password?: string;
+  surnames?: string;
+}
+
+export interface CreationResp {
+  inserted_id?: string;
+}
+
+export interface DashboardResp {
+  computed_at?: string;
+  failed_logins_last_hour?: number;
+  job_backlog?: number;
+  logins_within_token_lifetime?: number;
+  signups_today?: number;
+  users?: UserStatsResp;
+  webhook_failures?: number;
+}
+
+export interface DeviceResp {
+  created_at?: string;
+  id?: string;
+  name?: string;
+  platform?: string;
+  updated_at?: string;
+}
+
+export interface DirectorySyncChangeResp {
+  email?: string;
+  fields?: string[];
+  id?: string;
+  name?: string;
+  surnames?: string;
+}
+
+export interface DirectorySyncReportResp {
+  created?: DirectorySyncChangeResp[];
+  deactivated?: DirectorySyncChangeResp[];
+  dry_run?: boolean;
+  generated_at?: string;
+  job_id?: string;
+  skipped?: number;
+  unchanged?: number;
+  updated?: DirectorySyncChangeResp[];
+}
+
+export interface FacetResp {
+  count?: number;
+  value?: string;
+}
+
+export interface FileResp {
+  content_type?: string;
+  created_at?: string;
+  key?: string;
+  metadata?: Record<string, string>;
+  name?: string;
+  size?: number;
+}
+
+export interface GeoPoint {
+  coordinates?: number[];
+  type?: string;
+}
+
+export interface HealthCheckResp {
+  critical?: boolean;
+  error?: string;
+  latency_ms?: number;
+  status?: "up" | "down";
+}
+
+export interface IndexSearchUsersResp {
+  facets?: Record<string, FacetResp[]>;
+  total?: number;
+  users?: UserResp[];
+}
+
+export interface InviteMemberReq {
+  email?: string;
+  role?: "admin" | "member";
+}
+
+export interface JWKResp {
+  alg?: string;
+  kid?: string;
+  kty?: string;
+  use?: string;
+}
+
+export interface JWKSResp {
+  keys?: JWKResp[];
+}
+
+export interface JobResp {
+  attempts?: number;
+  created_at?: string;
+  error?: string;
+  finished_at?: string;
+  id?: string;
+  locked_until?: string;
+  max_attempts?: number;
+  metadata?: Record<string, string>;
+  processed?: number;
+  run_at?: string;
+  status?: "queued" | "running" | "succeeded" | "failed" | "dead";
+  type?: string;
+  updated_at?: string;
+  worker?: string;
+}
+
+export interface LoginUserReq {
+  email?: string;
+  mfa_code?: string;
+  password?: string;
+}
+
+export interface LoginUserResp {
+  expires_at?: string;
+  token?: string;
+  user?: UserResp;
+}
+
+export interface MaintenanceTaskResp {
+  description?: string;
+  name?: string;
+}
+
+export interface MergeUsersReq {
+  source_id?: string;
+}
+
+export interface MultiCreationResp {
+  inserted_ids?: string[];
+}
+
+export interface NotificationChannelsResp {
+  email?: boolean;
+  push?: boolean;
+  sms?: boolean;
+}
+
+export interface NotificationPreferencesResp {
+  categories?: Record<string, NotificationChannelsResp>;
+  updated_at?: string;
+  user_id?: string;
+}
+
+export interface OAuthErrorResp {
+  error?: "invalid_request" | "invalid_grant" | "unsupported_grant_type";
+  error_description?: string;
+}
+
+export interface OpenIDConfigurationResp {
+  claims_supported?: string[];
+  grant_types_supported?: string[];
+  issuer?: string;
+  jwks_uri?: string;
+  response_types_supported?: string[];
+  subject_types_supported?: string[];
+  token_endpoint?: string;
+  token_endpoint_auth_methods_supported?: string[];
+}
+
+export interface OrganizationInvitationResp {
+  invited_at?: string;
+  invited_by?: string;
+  organization_id?: string;
+  organization_name?: string;
+  role?: "admin" | "member";
+  user_id?: string;
+}
+
+export interface OrganizationMemberResp {
+  joined_at?: string;
+  role?: "owner" | "admin" | "member";
+  user?: UserResp;
+}
+
+export interface OrganizationResp {
+  created_at?: string;
+  id?: string;
+  members_count?: number;
+  name?: string;
+  owner_id?: string;
+  role?: "owner" | "admin" | "member";
+  updated_at?: string;
+}
+
+export interface PasswordPolicyReq {
+  min_length?: number;
+  require_digit?: boolean;
+  require_lowercase?: boolean;
+  require_symbol?: boolean;
+  require_uppercase?: boolean;
+}
+
+export interface PasswordPolicyResp {
+  min_length?: number;
+  require_digit?: boolean;
+  require_lowercase?: boolean;
+  require_symbol?: boolean;
+  require_uppercase?: boolean;
+}
+
+export interface PresignedURLResp {
+  expires_at?: string;
+  headers?: Record<string, string>;
+  method?: string;
+  url?: string;
+}
+
+export interface PutSecurityPolicyReq {
+  lockout_duration?: string;
+  lockout_max_failures?: number;
+  mfa_required?: boolean;
+  password?: PasswordPolicyReq;
+  session_lifetime?: string;
+}
+
+export interface ReadinessResp {
+  checks?: Record<string, HealthCheckResp>;
+  status?: "up" | "degraded" | "down";
+}
+
+export interface RegisterDeviceReq {
+  name?: string;
+  platform?: "fcm" | "apns";
+  token?: string;
+}
+
+export interface RetentionResp {
+  dry_run?: boolean;
+  results?: RetentionResultResp[];
+}
+
+export interface RetentionResultResp {
+  action?: "purge" | "anonymize";
+  before?: string;
+  collection?: string;
+  count?: number;
+  error?: string;
+}
+
+export interface RoleResp {
+  created_at?: string;
+  description?: string;
+  id?: string;
+  name?: string;
+  organization_ids?: string[];
+  permissions?: string[];
+  updated_at?: string;
+  user_ids?: string[];
+}
+
+export interface ScheduledJobResp {
+  enabled?: boolean;
+  last_run?: ScheduledRunResp;
+  name?: string;
+  next_run_at?: string;
+  overlaps?: number;
+  running?: boolean;
+  schedule?: string;
+}
+
+export interface ScheduledRunResp {
+  error?: string;
+  finished_at?: string;
+  started_at?: string;
+  status?: "succeeded" | "failed" | "skipped";
+}
+
+export interface SecurityPolicyResp {
+  created_at?: string;
+  lockout_duration?: string;
+  lockout_max_failures?: number;
+  mfa_required?: boolean;
+  password?: PasswordPolicyResp;
+  session_lifetime?: string;
+  tenant?: string;
+  updated_at?: string;
+}
+
+export interface SendSMSCodeReq {
+  phone?: string;
+  purpose?: "otp" | "phone_verification";
+  user_id?: string;
+}
+
+export interface StatusResp {
+  status?: "ok" | "degraded";
+  uptime_seconds?: number;
+  version?: string;
+}
+
+export interface TokenResp {
+  access_token?: string;
+  expires_in?: number;
+  token_type?: string;
+}
+
+export interface TransferOwnershipReq {
+  user_id?: string;
+}
+
+export interface UpdateMemberReq {
+  role?: "admin" | "member";
+}
+
+export interface UpdateNotificationChannelsReq {
+  email?: boolean;
+  push?: boolean;
+  sms?: boolean;
+}
+
+export interface UpdateNotificationPreferencesReq {
+  categories?: Record<string, UpdateNotificationChannelsReq>;
+}
+
+export interface UpdateOrganizationReq {
+  name?: string;
+}
+
+export interface UpdateRoleReq {
+  description?: string;
+  name?: string;
+  permissions?: string[];
+}
+
+export interface UpdateUserReq {
+  claims?: number[];
+  email?: string;
+  location?: GeoPoint;
+  name?: string;
+  new_password?: string;
+  old_password?: string;
+  surnames?: string;
+}
+
+export interface UpsertUserReq {
+  claims?: number[];
+  location?: GeoPoint;
+  name?: string;
+  password?: string;
+  surnames?: string;
+}
+
+export interface UpsertionResp {
+  id?: string;
+}
+
+export interface UserResp {
+  billing_customer_id?: string;
+  claims?: number[];
+  created_at?: string;
+  email?: string;
+  id?: string;
+  last_login_at?: string;
+  location?: GeoPoint;
+  name?: string;
+  subscription_status?: string;
+  surnames?: string;
+  updated_at?: string;
+}
+
+export interface UserStatsResp {
+  active?: number;
+  archived?: number;
+  computed_at?: string;
+  total?: number;
+}
+
+export interface VersionResp {
+  build_date?: string;
+  commit?: string;
+  go_version?: string;
+  version?: string;
+}
+
+/** Query parameters of getAuditEvents, the optional ones being sent when set */
+export interface GetAuditEventsParams {
+  type?: string;
+  outcome?: "success" | "failure";
+  user_id?: string;
+  actor_id?: string;
+  from?: string;
+  to?: string;
+  skip?: number;
+  take?: number;
+}
+
+/** Query parameters of exportAuditEvents, the optional ones being sent when set */
+export interface ExportAuditEventsParams {
+  type?: string;
+  outcome?: "success" | "failure";
+  user_id?: string;
+  actor_id?: string;
+  from?: string;
+  to?: string;
+}
+
+/** Query parameters of getClaims, the optional ones being sent when set */
+export interface GetClaimsParams {
+  category?: string;
+  deprecated?: boolean;
+}
+
+/** Query parameters of getCaptures, the optional ones being sent when set */
+export interface GetCapturesParams {
+  skip?: number;
+  take?: number;
+}
+
+/** Query parameters of createDirectorySync, the optional ones being sent when set */
+export interface CreateDirectorySyncParams {
+  dry_run?: boolean;
+}
+
+/** Query parameters of getJobs, the optional ones being sent when set */
+export interface GetJobsParams {
+  status?: "queued" | "running" | "succeeded" | "failed" | "dead";
+  type?: string;
+  skip?: number;
+  take?: number;
+}
+
+/** Query parameters of applyRetentionPolicies, the optional ones being sent when set */
+export interface ApplyRetentionPoliciesParams {
+  dry_run?: boolean;
+}
+
+/** Query parameters of getNearbyUsers, the optional ones being sent when set */
+export interface GetNearbyUsersParams {
+  lng: number;
+  lat: number;
+  radius: number;
+}
+
+/** Query parameters of searchUsers, the optional ones being sent when set */
+export interface SearchUsersParams {
+  q: string;
+  mode?: "fuzzy" | "autocomplete";
+}
+
+/** Query parameters of searchUsersInTheIndex, the optional ones being sent when set */
+export interface SearchUsersInTheIndexParams {
+  q: string;
+  domain?: string;
+  claim?: number;
+  skip?: number;
+  take?: number;
+}
+
+/** ApiError is thrown when the API responds with a status other than the expected one, with the message of its error when it has one */
+export class ApiError extends Error {
+  constructor(readonly status: number, readonly apiMessage: string) {
+    super(apiMessage ? `api responded with status ${status}: ${apiMessage}` : `api responded with status ${status}`);
+    this.name = "ApiError";
+  }
+}
+
+/** Request to the API, sending either a JSON body or a multipart form */
+interface Request {
+  method: string;
+  path: string;
+  query?: Record<string, string | number | boolean | undefined>;
+  body?: unknown;
+  form?: FormData;
+  status: number;
+  secured: boolean;
+}
+
+/** Client of the API, sending its token as a bearer token with the requests requiring it */
+export class Client {
+  /**
+   * @param baseURL of the API, like https://api.example.com
+   * @param token set before the requests requiring it, like with the token returned by the login
+   * @param fetchFn sending the requests, the global fetch by default
+   */
+  constructor(
+    readonly baseURL: string,
+    public token = "",
+    private readonly fetchFn: typeof fetch = (input, init) => fetch(input, init),
+  ) {}
+
+  /**
+   * Gets the public keys verifying the tokens, none as they are signed with HS256 by a secret key,
+   * so the tokens are verified by the API itself rather than by the clients
+   */
+  async getJSONWebKeySet(): Promise<JWKSResp> {
+    const resp = await this.send({ method: "GET", path: `/.well-known/jwks.json`, status: 200, secured: false });
+    return (await resp.json()) as JWKSResp;
+  }
+
+  /**
+   * Gets the OpenID Connect discovery document of the API, describing its issuer, token endpoint and key set,
+   * so the OIDC client libraries can configure themselves against it.
+   * Only access tokens are issued, by the token endpoint, so neither the openid scope, nor an authorization endpoint, nor id tokens are advertised
+   */
+  async getOpenIDConfiguration(): Promise<OpenIDConfigurationResp> {
+    const resp = await this.send({ method: "GET", path: `/.well-known/openid-configuration`, status: 200, secured: false });
+    return (await resp.json()) as OpenIDConfigurationResp;
+  }
+
+  /** Runs a Health Check */
+  async healthCheck(): Promise<void> {
+    await this.send({ method: "GET", path: `/health`, status: 200, secured: false });
+  }
+
+  /**
+   * Runs the health checks of the dependencies, reporting the status and latency of each of them.
+   * The API is degraded when only non critical checks fail, and down, responding with 503, when a critical one fails.
+   */
+  async readinessCheck(): Promise<ReadinessResp> {
+    const resp = await this.send({ method: "GET", path: `/readyz`, status: 200, secured: false });
+    return (await resp.json()) as ReadinessResp;
+  }
+
+  /**
+   * Reports the overall status of the API, ok or degraded, along with its uptime and version, for external status pages.
+   * It does not require authentication nor expose the details of the checks, and reuses the last readiness check when recent.
+   */
+  async status(): Promise<StatusResp> {
+    const resp = await this.send({ method: "GET", path: `/status`, status: 200, secured: false });
+    return (await resp.json()) as StatusResp;
+  }
+
+  /** Gets the user counts, the signups today, the active sessions, the failed logins of the last hour, the webhook failures and the job backlog, aggregated at computed_at and reused for up to Dashboard.MaxAge */
+  async getAdminDashboard(): Promise<DashboardResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/admin/dashboard`, status: 200, secured: true });
+    return (await resp.json()) as DashboardResp;
+  }
+
+  /** Gets the API keys of every user, without their secrets */
+  async getAPIKeys(): Promise<APIKeyResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/api-keys`, status: 200, secured: true });
+    return (await resp.json()) as APIKeyResp[];
+  }
+
+  /** Gets the security relevant events, newest first */
+  async getAuditEvents(params: GetAuditEventsParams = {}): Promise<AuditEventResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/audit`, query: { type: params.type, outcome: params.outcome, user_id: params.user_id, actor_id: params.actor_id, from: params.from, to: params.to, skip: params.skip, take: params.take }, status: 200, secured: true });
+    return (await resp.json()) as AuditEventResp[];
+  }
+
+  /**
+   * Exports every security relevant event matching the filters as a CSV file, newest first, for compliance reviews.
+   * The details of the events are exported as JSON objects, and the fields starting like a spreadsheet formula are prefixed with a quote.
+   */
+  async exportAuditEvents(params: ExportAuditEventsParams = {}): Promise<Blob> {
+    const resp = await this.send({ method: "GET", path: `/v1/audit/export`, query: { type: params.type, outcome: params.outcome, user_id: params.user_id, actor_id: params.actor_id, from: params.from, to: params.to }, status: 200, secured: true });
+    return resp.blob();
+  }
+
+  /** Gets a security relevant event by ID */
+  async getAuditEventByID(id: string): Promise<AuditEventResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/audit/${encodeURIComponent(id)}`, status: 200, secured: true });
+    return (await resp.json()) as AuditEventResp;
+  }
+
+  /** Queues the dump of a collection to the file storage, its progress is reported by the returned job */
+  async createBackup(collection: "users"): Promise<JobResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/backups/${encodeURIComponent(collection)}`, status: 202, secured: true });
+    return (await resp.json()) as JobResp;
+  }
+
+  /** Queues the replacement of all the documents of a collection with the ones of the named backup, its progress is reported by the returned job */
+  async restoreBackup(collection: "users", name: string): Promise<JobResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/backups/${encodeURIComponent(collection)}/${encodeURIComponent(name)}/restore`, status: 202, secured: true });
+    return (await resp.json()) as JobResp;
+  }
+
+  /** Gets the claims of the users matching the filters, sorted by ID, with their name, description, category and whether they are deprecated */
+  async getClaims(params: GetClaimsParams = {}): Promise<ClaimResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/claims`, query: { category: params.category, deprecated: params.deprecated }, status: 200, secured: true });
+    return (await resp.json()) as ClaimResp[];
+  }
+
+  /** Gets the effective config loaded by the running instance, from the flags and the config files, with the secrets redacted, along with the state of the feature flags */
+  async getConfig(): Promise<ConfigResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/config`, status: 200, secured: true });
+    return (await resp.json()) as ConfigResp;
+  }
+
+  /** Gets the debug capture mode of the instance serving the request */
+  async getCaptureMode(): Promise<CaptureModeResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/debug/capture`, status: 200, secured: true });
+    return (await resp.json()) as CaptureModeResp;
+  }
+
+  /** Captures the sanitized requests and responses of a percentage of the requests served by the instance serving the request, until the duration expires */
+  async enableCaptureMode(body: CaptureModeReq): Promise<CaptureModeResp> {
+    const resp = await this.send({ method: "PUT", path: `/v1/debug/capture`, body, status: 200, secured: true });
+    return (await resp.json()) as CaptureModeResp;
+  }
+
+  /** Disables the debug capture mode of the instance serving the request before it expires, keeping the captures */
+  async disableCaptureMode(): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/debug/capture`, status: 200, secured: true });
+  }
+
+  /** Gets the captured requests not expired yet, newest first */
+  async getCaptures(params: GetCapturesParams = {}): Promise<CaptureResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/debug/captures`, query: { skip: params.skip, take: params.take }, status: 200, secured: true });
+    return (await resp.json()) as CaptureResp[];
+  }
+
+  /** Queues a sync of the users with the external directory, creating, updating and deactivating them, only reporting the changes unless dry_run is false. Its status and counts are reported by the returned job */
+  async createDirectorySync(params: CreateDirectorySyncParams = {}): Promise<JobResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/directory/syncs`, query: { dry_run: params.dry_run }, status: 202, secured: true });
+    return (await resp.json()) as JobResp;
+  }
+
+  /** Gets the report of the users created, updated and deactivated by a finished directory sync or, in dry run, that would be */
+  async getDirectorySyncReport(id: string): Promise<DirectorySyncReportResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/directory/syncs/${encodeURIComponent(id)}/report`, status: 200, secured: true });
+    return (await resp.json()) as DirectorySyncReportResp;
+  }
+
+  /** Gets the queued and run jobs, newest first, the dead letter being the dead ones */
+  async getJobs(params: GetJobsParams = {}): Promise<JobResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/jobs`, query: { status: params.status, type: params.type, skip: params.skip, take: params.take }, status: 200, secured: true });
+    return (await resp.json()) as JobResp[];
+  }
+
+  /** Gets the status and progress of a job */
+  async getJobByID(id: string): Promise<JobResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/jobs/${encodeURIComponent(id)}`, status: 200, secured: true });
+    return (await resp.json()) as JobResp;
+  }
+
+  /** Queues again a job left in the dead letter, or a failed one waiting for its next attempt, with all its attempts */
+  async retryJob(id: string): Promise<JobResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/jobs/${encodeURIComponent(id)}/retry`, status: 200, secured: true });
+    return (await resp.json()) as JobResp;
+  }
+
+  /** Gets the maintenance tasks that can be started */
+  async getMaintenanceTasks(): Promise<MaintenanceTaskResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/maintenance/tasks`, status: 200, secured: true });
+    return (await resp.json()) as MaintenanceTaskResp[];
+  }
+
+  /** Queues the maintenance task, its progress is reported by the returned job */
+  async startMaintenanceTask(task: "rebuild_indexes" | "recompute_stats" | "flush_caches" | "replay_notifications" | "purge_expired"): Promise<JobResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/maintenance/tasks/${encodeURIComponent(task)}`, status: 202, secured: true });
+    return (await resp.json()) as JobResp;
+  }
+
+  /** Gets the runtime metrics, the authentication metrics, the duration of the database commands per collection and operation and the database server status */
+  async getMetrics(): Promise<Record<string, unknown>> {
+    const resp = await this.send({ method: "GET", path: `/v1/metrics`, status: 200, secured: true });
+    return (await resp.json()) as Record<string, unknown>;
+  }
+
+  /** Gets the organizations the caller is a member of, with its role in them, every organization for the admins */
+  async getOrganizations(): Promise<OrganizationResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/organizations`, status: 200, secured: true });
+    return (await resp.json()) as OrganizationResp[];
+  }
+
+  /** Creates an organization, the caller being its owner */
+  async createOrganization(body: CreateOrganizationReq): Promise<CreationResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/organizations`, body, status: 201, secured: true });
+    return (await resp.json()) as CreationResp;
+  }
+
+  /** Gets the pending invitations of the caller to the organizations */
+  async getCallerInvitations(): Promise<OrganizationInvitationResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/organizations/invitations`, status: 200, secured: true });
+    return (await resp.json()) as OrganizationInvitationResp[];
+  }
+
+  /** Gets an organization the caller is a member of */
+  async getOrganizationByID(id: string): Promise<OrganizationResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/organizations/${encodeURIComponent(id)}`, status: 200, secured: true });
+    return (await resp.json()) as OrganizationResp;
+  }
+
+  /** Updates an organization the caller is an admin of */
+  async updateOrganization(id: string, body: UpdateOrganizationReq): Promise<void> {
+    await this.send({ method: "PATCH", path: `/v1/organizations/${encodeURIComponent(id)}`, body, status: 200, secured: true });
+  }
+
+  /** Deletes an organization the caller is the owner of, along with its members */
+  async deleteOrganization(id: string): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/organizations/${encodeURIComponent(id)}`, status: 200, secured: true });
+  }
+
+  /** Gets the pending invitations to an organization the caller is an admin of */
+  async getOrganizationInvitations(id: string): Promise<OrganizationInvitationResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/organizations/${encodeURIComponent(id)}/invitations`, status: 200, secured: true });
+    return (await resp.json()) as OrganizationInvitationResp[];
+  }
+
+  /**
+   * Invites the user with the email to an organization the caller is an admin of, with the admin or member role,
+   * the user being a member once it accepts the invitation
+   */
+  async inviteOrganizationMember(id: string, body: InviteMemberReq): Promise<void> {
+    await this.send({ method: "POST", path: `/v1/organizations/${encodeURIComponent(id)}/invitations`, body, status: 201, secured: true });
+  }
+
+  /** Makes the caller a member of an organization it is invited to, with the role of the invitation */
+  async acceptOrganizationInvitation(id: string): Promise<void> {
+    await this.send({ method: "POST", path: `/v1/organizations/${encodeURIComponent(id)}/invitations/accept`, status: 200, secured: true });
+  }
+
+  /** Revokes the invitation of a user to an organization the caller is an admin of, or declines the invitation of the caller */
+  async removeOrganizationInvitation(id: string, userID: string): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/organizations/${encodeURIComponent(id)}/invitations/${encodeURIComponent(userID)}`, status: 200, secured: true });
+  }
+
+  /** Gets the members of an organization the caller is a member of, with their users, so the users are listed by organization */
+  async getOrganizationMembers(id: string): Promise<OrganizationMemberResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/organizations/${encodeURIComponent(id)}/members`, status: 200, secured: true });
+    return (await resp.json()) as OrganizationMemberResp[];
+  }
+
+  /** Changes the role of a member of an organization the caller is an admin of, the role of the owner never changing */
+  async updateOrganizationMember(id: string, userID: string, body: UpdateMemberReq): Promise<void> {
+    await this.send({ method: "PATCH", path: `/v1/organizations/${encodeURIComponent(id)}/members/${encodeURIComponent(userID)}`, body, status: 200, secured: true });
+  }
+
+  /** Removes a member from an organization the caller is an admin of, or the caller from an organization it leaves, the owner never being removed */
+  async removeOrganizationMember(id: string, userID: string): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/organizations/${encodeURIComponent(id)}/members/${encodeURIComponent(userID)}`, status: 200, secured: true });
+  }
+
+  /** Makes a member the owner of an organization the caller is the owner of, the caller becoming an admin of it */
+  async transferOrganizationOwnership(id: string, body: TransferOwnershipReq): Promise<void> {
+    await this.send({ method: "PUT", path: `/v1/organizations/${encodeURIComponent(id)}/owner`, body, status: 200, secured: true });
+  }
+
+  /** Purges or anonymizes the documents older than the limits of the retention policies, only reporting how many they are unless dry_run is false */
+  async applyRetentionPolicies(params: ApplyRetentionPoliciesParams = {}): Promise<RetentionResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/retention`, query: { dry_run: params.dry_run }, status: 200, secured: true });
+    return (await resp.json()) as RetentionResp;
+  }
+
+  /** Gets all the roles, with their assignees */
+  async getRoles(): Promise<RoleResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/roles`, status: 200, secured: true });
+    return (await resp.json()) as RoleResp[];
+  }
+
+  /** Creates a role, whose permissions are the names of the claims it grants */
+  async createRole(body: CreateRoleReq): Promise<CreationResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/roles`, body, status: 201, secured: true });
+    return (await resp.json()) as CreationResp;
+  }
+
+  /** Gets a role, with its assignees */
+  async getRoleByID(id: string): Promise<RoleResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/roles/${encodeURIComponent(id)}`, status: 200, secured: true });
+    return (await resp.json()) as RoleResp;
+  }
+
+  /** Updates a role, its assignees being granted its new permissions once they log in again */
+  async updateRole(id: string, body: UpdateRoleReq): Promise<void> {
+    await this.send({ method: "PATCH", path: `/v1/roles/${encodeURIComponent(id)}`, body, status: 200, secured: true });
+  }
+
+  /** Deletes a role, unassigning it from everyone */
+  async deleteRole(id: string): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/roles/${encodeURIComponent(id)}`, status: 200, secured: true });
+  }
+
+  /** Assigns a role to an organization, granting its members the permissions of the role once they log in again */
+  async assignRoleToOrganization(id: string, organizationID: string): Promise<void> {
+    await this.send({ method: "PUT", path: `/v1/roles/${encodeURIComponent(id)}/organizations/${encodeURIComponent(organizationID)}`, status: 200, secured: true });
+  }
+
+  /** Unassigns a role from an organization */
+  async unassignRoleFromOrganization(id: string, organizationID: string): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/roles/${encodeURIComponent(id)}/organizations/${encodeURIComponent(organizationID)}`, status: 200, secured: true });
+  }
+
+  /** Assigns a role to a user, granting it the permissions of the role once it logs in again */
+  async assignRoleToUser(id: string, userID: string): Promise<void> {
+    await this.send({ method: "PUT", path: `/v1/roles/${encodeURIComponent(id)}/users/${encodeURIComponent(userID)}`, status: 200, secured: true });
+  }
+
+  /** Unassigns a role from a user */
+  async unassignRoleFromUser(id: string, userID: string): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/roles/${encodeURIComponent(id)}/users/${encodeURIComponent(userID)}`, status: 200, secured: true });
+  }
+
+  /** Gets the jobs of the scheduler with their schedule, their next run and the result of their last run on the replica serving the request */
+  async getScheduledJobs(): Promise<ScheduledJobResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/scheduler/jobs`, status: 200, secured: true });
+    return (await resp.json()) as ScheduledJobResp[];
+  }
+
+  /** Gets the security policies of every tenant having one, sorted by tenant */
+  async getSecurityPolicies(): Promise<SecurityPolicyResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/security-policies`, status: 200, secured: true });
+    return (await resp.json()) as SecurityPolicyResp[];
+  }
+
+  /** Gets the security policy of a tenant, the domain of the emails of its users */
+  async getSecurityPolicy(tenant: string): Promise<SecurityPolicyResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/security-policies/${encodeURIComponent(tenant)}`, status: 200, secured: true });
+    return (await resp.json()) as SecurityPolicyResp;
+  }
+
+  /** Sets the security policy of a tenant, replacing the one it has, applied to the logins and the passwords of its users from then on */
+  async putSecurityPolicy(tenant: string, body: PutSecurityPolicyReq): Promise<SecurityPolicyResp> {
+    const resp = await this.send({ method: "PUT", path: `/v1/security-policies/${encodeURIComponent(tenant)}`, body, status: 200, secured: true });
+    return (await resp.json()) as SecurityPolicyResp;
+  }
+
+  /** Deletes the security policy of a tenant, its users being applied the settings of the config */
+  async deleteSecurityPolicy(tenant: string): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/security-policies/${encodeURIComponent(tenant)}`, status: 200, secured: true });
+  }
+
+  /** Sends a one-time code by text message to the phone */
+  async sendSMSCode(body: SendSMSCodeReq): Promise<void> {
+    await this.send({ method: "POST", path: `/v1/sms/codes`, body, status: 200, secured: true });
+  }
+
+  /** Checks the one-time code sent by text message to the phone, each code being accepted once */
+  async checkSMSCode(body: CheckSMSCodeReq): Promise<void> {
+    await this.send({ method: "POST", path: `/v1/sms/codes/check`, body, status: 200, secured: true });
+  }
+
+  /** Gets the caller along with the members of its organizations, every user for the admins */
+  async getAllUsers(): Promise<UserResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/users`, status: 200, secured: true });
+    return (await resp.json()) as UserResp[];
+  }
+
+  /** Creates a new user */
+  async createUser(body: CreateUserReq): Promise<CreationResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/users`, body, status: 201, secured: false });
+    return (await resp.json()) as CreationResp;
+  }
+
+  /** Gets a user by email */
+  async getUserByEmail(email: string): Promise<UserResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/users/email/${encodeURIComponent(email)}`, status: 200, secured: true });
+    return (await resp.json()) as UserResp;
+  }
+
+  /** Creates or updates the user with the given email, for idempotent provisioning */
+  async upsertUser(email: string, body: UpsertUserReq): Promise<UpsertionResp> {
+    const resp = await this.send({ method: "PUT", path: `/v1/users/email/${encodeURIComponent(email)}`, body, status: 200, secured: true });
+    return (await resp.json()) as UpsertionResp;
+  }
+
+  /**
+   * Logs in an user. When the security policy of its tenant requires MFA, a login without mfa_code emails a one-time code to the user
+   * and responds with a 401, the login being done again with the code.
+   */
+  async loginUser(body: LoginUserReq): Promise<LoginUserResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/users/login`, body, status: 200, secured: false });
+    return (await resp.json()) as LoginUserResp;
+  }
+
+  /** Creates many users atomically */
+  async createManyUsers(body: CreateUserReq[]): Promise<MultiCreationResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/users/many`, body, status: 201, secured: false });
+    return (await resp.json()) as MultiCreationResp;
+  }
+
+  /** Gets the users located within a radius of a point, sorted by distance */
+  async getNearbyUsers(params: GetNearbyUsersParams): Promise<UserResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/users/nearby`, query: { lng: params.lng, lat: params.lat, radius: params.radius }, status: 200, secured: true });
+    return (await resp.json()) as UserResp[];
+  }
+
+  /** Searches users by name, surnames or email */
+  async searchUsers(params: SearchUsersParams): Promise<UserResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/users/search`, query: { q: params.q, mode: params.mode }, status: 200, secured: true });
+    return (await resp.json()) as UserResp[];
+  }
+
+  /**
+   * Searches users by name, surnames or email in the search index, by relevance and tolerating typos,
+   * with the total count of matches and their counts by email domain and by claim
+   */
+  async searchUsersInTheIndex(params: SearchUsersInTheIndexParams): Promise<IndexSearchUsersResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/users/search/index`, query: { q: params.q, domain: params.domain, claim: params.claim, skip: params.skip, take: params.take }, status: 200, secured: true });
+    return (await resp.json()) as IndexSearchUsersResp;
+  }
+
+  /** Gets a user by ID */
+  async getUserByID(id: string): Promise<UserResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/users/${encodeURIComponent(id)}`, status: 200, secured: true });
+    return (await resp.json()) as UserResp;
+  }
+
+  /** Updates a user */
+  async updateUser(id: string, body: UpdateUserReq): Promise<void> {
+    await this.send({ method: "PATCH", path: `/v1/users/${encodeURIComponent(id)}`, body, status: 200, secured: true });
+  }
+
+  /** Delete a user */
+  async deleteUser(id: string): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/users/${encodeURIComponent(id)}`, status: 200, secured: true });
+  }
+
+  /** Gets the API keys of a user, newest first, without their secrets */
+  async getUserAPIKeys(id: string): Promise<APIKeyResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/users/${encodeURIComponent(id)}/api-keys`, status: 200, secured: true });
+    return (await resp.json()) as APIKeyResp[];
+  }
+
+  /** Creates an API key of a user, granting the claims of its scopes, responding with its secret, which cannot be read again */
+  async createAPIKey(id: string, body: CreateAPIKeyReq): Promise<APIKeySecretResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/users/${encodeURIComponent(id)}/api-keys`, body, status: 201, secured: true });
+    return (await resp.json()) as APIKeySecretResp;
+  }
+
+  /** Revokes an API key of a user, so it no longer authenticates the requests */
+  async revokeAPIKey(id: string, keyID: string): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/users/${encodeURIComponent(id)}/api-keys/${encodeURIComponent(keyID)}`, status: 200, secured: true });
+  }
+
+  /** Replaces the secret of an API key of a user, keeping its scopes and expiry, responding with the new secret, which cannot be read again */
+  async rotateAPIKey(id: string, keyID: string): Promise<APIKeySecretResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/users/${encodeURIComponent(id)}/api-keys/${encodeURIComponent(keyID)}/rotate`, status: 200, secured: true });
+    return (await resp.json()) as APIKeySecretResp;
+  }
+
+  /** Gets the avatar image of a user */
+  async getUserAvatar(id: string): Promise<Blob> {
+    const resp = await this.send({ method: "GET", path: `/v1/users/${encodeURIComponent(id)}/avatar`, status: 200, secured: true });
+    return resp.blob();
+  }
+
+  /** Uploads the avatar image of a user, replacing the previous one. When the uploads are scanned, it is returned in quarantine, with its scan metadata pending, and replaces the previous one once found clean */
+  async uploadUserAvatar(id: string, file: Blob, filename: string): Promise<FileResp> {
+    const form = new FormData();
+    form.append("file", file, filename);
+    const resp = await this.send({ method: "PUT", path: `/v1/users/${encodeURIComponent(id)}/avatar`, form, status: 200, secured: true });
+    return (await resp.json()) as FileResp;
+  }
+
+  /** Deletes the avatar image of a user */
+  async deleteUserAvatar(id: string): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/users/${encodeURIComponent(id)}/avatar`, status: 200, secured: true });
+  }
+
+  /** Gets a presigned URL downloading the avatar image of a user directly from the file storage */
+  async getUserAvatarURL(id: string): Promise<PresignedURLResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/users/${encodeURIComponent(id)}/avatar/url`, status: 200, secured: true });
+    return (await resp.json()) as PresignedURLResp;
+  }
+
+  /** Creates a presigned URL uploading the avatar image of a user directly to the file storage, replacing the previous one, with the returned method and headers */
+  async createUserAvatarUploadURL(id: string, body: AvatarUploadURLReq): Promise<PresignedURLResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/users/${encodeURIComponent(id)}/avatar/url`, body, status: 201, secured: true });
+    return (await resp.json()) as PresignedURLResp;
+  }
+
+  /** Gets the devices registered by a user, the newest first */
+  async getDevices(id: string): Promise<DeviceResp[]> {
+    const resp = await this.send({ method: "GET", path: `/v1/users/${encodeURIComponent(id)}/devices`, status: 200, secured: true });
+    return (await resp.json()) as DeviceResp[];
+  }
+
+  /** Registers the token of a device of a user, given by FCM or APNs, so it receives the push notifications of the security events of the account */
+  async registerDevice(id: string, body: RegisterDeviceReq): Promise<DeviceResp> {
+    const resp = await this.send({ method: "POST", path: `/v1/users/${encodeURIComponent(id)}/devices`, body, status: 201, secured: true });
+    return (await resp.json()) as DeviceResp;
+  }
+
+  /** Deletes a device of a user, so it no longer receives the push notifications */
+  async deleteDevice(id: string, device: string): Promise<void> {
+    await this.send({ method: "DELETE", path: `/v1/users/${encodeURIComponent(id)}/devices/${encodeURIComponent(device)}`, status: 200, secured: true });
+  }
+
+  /** Merges the source user into the user with the given ID and deletes the source user, atomically */
+  async mergeUsers(id: string, body: MergeUsersReq): Promise<void> {
+    await this.send({ method: "POST", path: `/v1/users/${encodeURIComponent(id)}/merge`, body, status: 200, secured: true });
+  }
+
+  /** Gets the channels the notifications of every category are delivered to a user through, every channel being enabled for the categories never set */
+  async getNotificationPreferences(id: string): Promise<NotificationPreferencesResp> {
+    const resp = await this.send({ method: "GET", path: `/v1/users/${encodeURIComponent(id)}/notification-preferences`, status: 200, secured: true });
+    return (await resp.json()) as NotificationPreferencesResp;
+  }
+
+  /** Enables or disables the channels of the categories of the notifications of a user, the channels and categories not set being left as they are */
+  async updateNotificationPreferences(id: string, body: UpdateNotificationPreferencesReq): Promise<NotificationPreferencesResp> {
+    const resp = await this.send({ method: "PATCH", path: `/v1/users/${encodeURIComponent(id)}/notification-preferences`, body, status: 200, secured: true });
+    return (await resp.json()) as NotificationPreferencesResp;
+  }
+
+  /** Moves an archived user back to the active users */
+  async unarchiveUser(id: string): Promise<void> {
+    await this.send({ method: "POST", path: `/v1/users/${encodeURIComponent(id)}/unarchive`, status: 200, secured: true });
+  }
+
+  /**
+   * Reports the version, commit and build date of the binary serving the request, so the builds running in the fleet can be told apart during a rollout.
+   * It does not require authentication.
+   */
+  async version(): Promise<VersionResp> {
+    const resp = await this.send({ method: "GET", path: `/version`, status: 200, secured: false });
+    return (await resp.json()) as VersionResp;
+  }
+
+  /** send sends the request, returning the response with the expected status, or throwing an ApiError otherwise */
+  private async send(req: Request): Promise<Response> {
+    let url = this.baseURL.replace(/\/$/, "") + req.path;
+    const query = new URLSearchParams();
+    for (const [key, value] of Object.entries(req.query ?? {})) {
+      if (value !== undefined) {
+        query.set(key, String(value));
+      }
+    }
+    if (query.toString() !== "") {
+      url += "?" + query.toString();
+    }
+
+    const headers: Record<string, string> = { Accept: "application/json" };
+    let body: BodyInit | undefined;
+    if (req.form !== undefined) {
+      body = req.form;
+    } else if (req.body !== undefined) {
+      body = JSON.stringify(req.body);
+      headers["Content-Type"] = "application/json";
+    }
+    if (req.secured && this.token !== "") {
+      headers.Authorization = `Bearer ${this.token}`;
+    }
+
+    const resp = await this.fetchFn(url, { method: req.method, headers, body });
+    if (resp.status === req.status) {
+      return resp;
+    }
+    let message = "";
+    try {
+      message = ((await resp.json()) as { error?: string }).error ?? "";
+    } catch {
+      // the body is not an error of the API
+    }
+    throw new ApiError(resp.status, message);
+  }
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/sergicanet9/go-hexagonal-api/app/claimsgen"
+)
+
+// claimsgen generates the claims of the users from their definition, run by go generate in core/entities.
+// It is a program of its own, not a command of the API, so it builds even when the generated code does not.
+func main() {
+	var opts struct {
+		Source  string `long:"source" default:"claims.yaml" description:"YAML file defining the claims"`
+		Output  string `long:"output" default:"claims_gen.go" description:"Go file to write the claims to"`
+		Package string `long:"package" default:"entities" description:"Package of the Go file"`
+		Models  string `long:"models" default:"../models" description:"Directory of the models whose claims fields are tagged with the enum values of the claims"`
+	}
+
+	args, err := flags.Parse(&opts)
+	if err != nil {
+		log.Fatal(fmt.Errorf("provided flags not valid: %s, %w", args, err))
+	}
+
+	source, err := os.ReadFile(opts.Source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	claims, err := claimsgen.Parse(source)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	generated, err := claimsgen.GenerateGo(claims, opts.Package, filepath.Base(opts.Source))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(opts.Output, generated, 0o644); err != nil {
+		log.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(opts.Models, "*.go"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if tagged, changed := claimsgen.TagModels(src, claims); changed {
+			if err := os.WriteFile(file, tagged, 0o644); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/app/api"
+	"github.com/sergicanet9/go-hexagonal-api/app/buildinfo"
+	"github.com/sergicanet9/go-hexagonal-api/app/clientgen"
+	"github.com/sergicanet9/go-hexagonal-api/app/docs"
+	"github.com/sergicanet9/go-hexagonal-api/app/resourcegen"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/encryption"
+	"github.com/sergicanet9/go-hexagonal-api/test/contract"
+	"github.com/swaggo/swag"
+)
+
+// migrateCommand prepares the database without serving the API
+type migrateCommand struct{}
+
+// Execute migrates the database, as done when the API is created
+func (c *migrateCommand) Execute(args []string) error {
+	env := load(0)
+	defer env.close()
+	api.New(context.Background(), env.cfg, env.logger)
+	env.logger.Info().Str("database", env.cfg.Database).Msg("database migrated")
+	return nil
+}
+
+// seedCommand creates or updates the users of a JSON file
+type seedCommand struct {
+	File string `long:"file" description:"JSON file with the list of users, as in the creation requests" default:"build/seed/users.json"`
+}
+
+// Execute seeds the users, so it can be run again without duplicating them
+func (c *seedCommand) Execute(args []string) error {
+	env := load(0)
+	defer env.close()
+
+	var users []models.CreateUserReq
+	b, err := os.ReadFile(c.File)
+	if err != nil {
+		env.logger.Fatal().Err(err).Msg("cannot read the seed file")
+	}
+	if err := json.Unmarshal(b, &users); err != nil {
+		env.logger.Fatal().Err(err).Msgf("seed file %s not valid", c.File)
+	}
+
+	ctx := context.Background()
+	a := api.New(ctx, env.cfg, env.logger)
+	for _, user := range users {
+		_, err := a.UserService().Upsert(ctx, user.Email, models.UpsertUserReq{
+			Name:     user.Name,
+			Surnames: user.Surnames,
+			Password: user.Password,
+			Claims:   user.Claims,
+			Location: user.Location,
+		})
+		if err != nil {
+			env.logger.Fatal().Err(err).Msgf("cannot seed user %s", user.Email)
+		}
+	}
+	env.logger.Info().Int("users", len(users)).Msg("users seeded")
+	return nil
+}
+
+// createAdminCommand creates or updates a user with the admin claim
+type createAdminCommand struct {
+	Email    string `long:"email" description:"Email of the admin" required:"true"`
+	Password string `long:"password" env:"API_ADMIN_PASSWORD" description:"Password of the admin, better set by its environment variable" required:"true"`
+	Name     string `long:"name" description:"Name of the admin"`
+	Surnames string `long:"surnames" description:"Surnames of the admin"`
+}
+
+// Execute creates the admin, replacing the user with the same email if any
+func (c *createAdminCommand) Execute(args []string) error {
+	env := load(0)
+	defer env.close()
+
+	ctx := context.Background()
+	a := api.New(ctx, env.cfg, env.logger)
+	resp, err := a.UserService().Upsert(ctx, c.Email, models.UpsertUserReq{
+		Name:     c.Name,
+		Surnames: c.Surnames,
+		Password: c.Password,
+		Claims:   []int64{int64(entities.AdminClaim)},
+	})
+	if err != nil {
+		env.logger.Fatal().Err(err).Msg("cannot create the admin")
+	}
+	env.logger.Info().Str("user_id", resp.ID).Msg("admin created")
+	return nil
+}
+
+// genOpenAPICommand writes the OpenAPI document generated from the handlers
+type genOpenAPICommand struct {
+	Output string `long:"output" short:"o" description:"File to write the document to, the standard output if not set"`
+}
+
+// Execute writes the OpenAPI document, without needing the config or the database
+func (c *genOpenAPICommand) Execute(args []string) error {
+	docs.SwaggerInfo.Version = buildinfo.Get().WithVersion(opts.Version).Version
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(c.Output, []byte(doc+"\n"))
+}
+
+// genClientCommand writes a client of the API generated from its OpenAPI document
+type genClientCommand struct {
+	Lang    string `long:"lang" required:"true" choice:"go" choice:"typescript" description:"Language of the client"`
+	Output  string `long:"output" short:"o" description:"File to write the client to, the standard output if not set"`
+	Package string `long:"package" default:"apiclient" description:"Package of the Go client"`
+}
+
+// Execute generates the client from the OpenAPI document, without needing the config or the database
+func (c *genClientCommand) Execute(args []string) error {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		return err
+	}
+	parsed, err := clientgen.Parse([]byte(doc))
+	if err != nil {
+		return err
+	}
+
+	var client []byte
+	switch c.Lang {
+	case "go":
+		client, err = clientgen.GenerateGo(parsed, c.Package)
+	case "typescript":
+		client, err = clientgen.GenerateTypeScript(parsed)
+	}
+	if err != nil {
+		return err
+	}
+	return writeOutput(c.Output, client)
+}
+
+// genResourceCommand scaffolds a new resource stored in MongoDB, following the conventions of the users
+type genResourceCommand struct {
+	Name   string `long:"name" required:"true" description:"Name of the resource, in snake case and singular, like api_key"`
+	Plural string `long:"plural" description:"Plural of the name, like api_keys, derived from the name if not set"`
+	Dir    string `long:"dir" default:"." description:"Root directory of the module of the API"`
+}
+
+// Execute writes the files of the resource and wires it in the API, printing their paths, without needing the config or the database
+func (c *genResourceCommand) Execute(args []string) error {
+	r, err := resourcegen.New(c.Name, c.Plural)
+	if err != nil {
+		return err
+	}
+	written, err := resourcegen.Write(c.Dir, r)
+	for _, path := range written {
+		fmt.Println(path)
+	}
+	if err != nil {
+		return err
+	}
+	bootstrap.Info().Str("resource", r.Name).Str("path", "/v1/"+r.Path).Msg("resource generated, run make swagger to document its routes")
+	return nil
+}
+
+// verifyContractCommand verifies that a test instance honors the OpenAPI document
+type verifyContractCommand struct {
+	URL        string   `long:"url" required:"true" description:"Base URL of the test instance, like http://localhost:8080"`
+	Token      string   `long:"token" env:"API_CONTRACT_TOKEN" description:"Token of an admin, sent with the secured operations"`
+	Document   string   `long:"document" description:"OpenAPI document verified, like the one a consumer was built against, the one of this build if not set"`
+	Iterations int      `long:"iterations" default:"20" description:"Fuzzed requests sent to every operation"`
+	Seed       int64    `long:"seed" description:"Seed of the values generated, to reproduce a verification, random if not set"`
+	Skip       []string `long:"skip" description:"Operation not verified, like DeleteUser, can be repeated"`
+}
+
+// Execute sends the operations of the document to the instance, printing the failures found, without needing the config or the database
+func (c *verifyContractCommand) Execute(args []string) error {
+	cfg := contract.Config{BaseURL: c.URL, Token: c.Token, Iterations: c.Iterations, Seed: c.Seed, Skip: c.Skip}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	if c.Document != "" {
+		doc, err := os.ReadFile(c.Document)
+		if err != nil {
+			return err
+		}
+		cfg.Document = doc
+	}
+
+	report, err := contract.Verify(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	for _, failure := range report.Failures {
+		fmt.Println(failure)
+	}
+	bootstrap.Info().Int("requests", report.Requests).Int("rate_limited", report.RateLimited).Int("failures", len(report.Failures)).
+		Int64("seed", cfg.Seed).Msg("contract verified")
+	if len(report.Failures) > 0 {
+		return fmt.Errorf("%d requests did not honor the document", len(report.Failures))
+	}
+	return nil
+}
+
+// writeOutput writes the content to the file, or to the standard output when not set
+func writeOutput(file string, content []byte) error {
+	var w io.Writer = os.Stdout
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	_, err := w.Write(content)
+	return err
+}
+
+// rotateKeysCommand rotates the data key of the field level encryption
+type rotateKeysCommand struct{}
+
+// Execute re-encrypts the users with a new data key and prints it, to be set as Encryption.DataKey before starting the API again
+func (c *rotateKeysCommand) Execute(args []string) error {
+	env := load(0)
+	defer env.close()
+
+	ctx := context.Background()
+	a := api.New(ctx, env.cfg, env.logger)
+	dataKey, err := a.RotateDataKey(ctx)
+	if err != nil {
+		env.logger.Fatal().Err(err).Msg("cannot rotate the data key")
+	}
+	fmt.Println(dataKey)
+	return nil
+}
+
+// encryptValueCommand encrypts a value to be set in the config files
+type encryptValueCommand struct {
+	Value string `long:"value" env:"API_PLAIN_VALUE" description:"Value to encrypt, better set by its environment variable, read from the standard input if not set"`
+}
+
+// Execute prints the value encrypted with the KMS provider of the Encryption section, read from the config files, the environment variables and the flags,
+// without the secrets provider nor the database
+func (c *encryptValueCommand) Execute(args []string) error {
+	cfg, err := config.ReadConfig(opts.Version, opts.Environment, 0, opts.Database, opts.DSN, "config", opts.Set)
+	if err != nil {
+		bootstrap.Fatal().Err(err).Msgf("cannot parse config file for env %s", opts.Environment)
+	}
+	provider, err := encryption.NewKeyProvider(cfg.Encryption.KMSProvider, cfg.Encryption.LocalMasterKey, cfg.Encryption.AzureKeyURL)
+	if err != nil {
+		bootstrap.Fatal().Err(err).Msg("cannot create the KMS provider")
+	}
+
+	value := c.Value
+	if value == "" {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		value = strings.TrimRight(string(b), "\r\n")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout.Duration)
+	defer cancel()
+	envelope, err := encryption.SealEnvelope(ctx, provider, value)
+	if err != nil {
+		bootstrap.Fatal().Err(err).Msg("cannot encrypt the value")
+	}
+	fmt.Println(config.EncryptedPrefix + envelope + config.EncryptedSuffix)
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/encryption"
+)
+
+// datakey generates a new data key for field level encryption, wrapped with the given local master key
+func main() {
+	var opts struct {
+		MasterKey string `long:"master-key" description:"Base64 encoded 32 bytes local master key" required:"true"`
+	}
+
+	args, err := flags.Parse(&opts)
+	if err != nil {
+		log.Fatal(fmt.Errorf("provided flags not valid: %s, %w", args, err))
+	}
+
+	masterKey, err := base64.StdEncoding.DecodeString(opts.MasterKey)
+	if err != nil {
+		log.Fatal(fmt.Errorf("master key not valid: %w", err))
+	}
+
+	provider, err := encryption.NewLocalKeyProvider(masterKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		log.Fatal(err)
+	}
+
+	wrappedKey, err := provider.WrapKey(context.Background(), dataKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(base64.StdEncoding.EncodeToString(wrappedKey))
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/buildinfo"
+	"github.com/sergicanet9/go-hexagonal-api/app/logging"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/encryption"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/mongo"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/reporting"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/secrets"
+)
+
+// environment the commands are run in: the resolved config, the logger created from it, the secrets it was resolved with
+// and the embedded Mongo started for it, if any
+type environment struct {
+	cfg             config.Config
+	logger          zerolog.Logger
+	secretsProvider secrets.Provider
+	secretValues    map[string]string
+	embeddedMongo   *mongo.EmbeddedMongo
+}
+
+// load resolves the config of the options for the given port, 0 when not serving the API, with the secrets of its provider,
+// validates it, creates the logger from it and starts the embedded Mongo when no Mongo DSN is set,
+// exiting on failure as no command can be run without them
+func load(port int) (env environment) {
+	build := buildinfo.Get()
+	if opts.Version == "" {
+		opts.Version = build.Version
+	}
+	if opts.Version == "" || opts.Environment == "" {
+		bootstrap.Fatal().Msg("the env flag is required, and the ver flag when the version is not set at build time")
+	}
+	build = build.WithVersion(opts.Version)
+
+	var err error
+	env.cfg, err = config.ReadConfig(opts.Version, opts.Environment, port, opts.Database, opts.DSN, "config", opts.Set)
+	if err != nil {
+		bootstrap.Fatal().Err(err).Msgf("cannot parse config file for env %s", opts.Environment)
+	}
+
+	cfg := env.cfg
+	if cfg.Secrets.Provider != "" {
+		env.secretsProvider, err = secrets.NewProvider(cfg.Secrets.Provider, cfg.Secrets.VaultAddress, cfg.Secrets.VaultToken, cfg.Secrets.VaultMount, cfg.Secrets.VaultPath,
+			cfg.Secrets.AWSRegion, cfg.Secrets.AWSSecretID, cfg.Secrets.AWSParameterPath, cfg.Secrets.CacheTTL.Duration)
+		if err != nil {
+			bootstrap.Fatal().Err(err).Msg("cannot create secrets provider")
+		}
+		secretsCtx, secretsCancel := context.WithTimeout(context.Background(), cfg.Timeout.Duration)
+		env.secretValues, err = env.secretsProvider.Secrets(secretsCtx)
+		secretsCancel()
+		if err != nil {
+			bootstrap.Fatal().Err(err).Msg("cannot fetch secrets")
+		}
+		if err := env.cfg.ApplySecrets(env.secretValues); err != nil {
+			bootstrap.Fatal().Err(err).Msg("secrets not valid")
+		}
+	}
+	if err := env.cfg.DecryptValues(decrypter(env.cfg)); err != nil {
+		bootstrap.Fatal().Err(err).Msg("cannot decrypt the config")
+	}
+	if err := env.cfg.Validate(); err != nil {
+		bootstrap.Fatal().Err(err).Msg("config not valid")
+	}
+
+	var reporter ports.ErrorReporter
+	if env.cfg.Reporting.Enabled {
+		reporter, err = reporting.NewSentryReporter(env.cfg.Reporting.DSN, env.cfg.Environment, build.Release(), env.cfg.Reporting.SampleRate)
+		if err != nil {
+			bootstrap.Fatal().Err(err).Msg("cannot create error reporter")
+		}
+	}
+
+	env.logger, err = logging.New(env.cfg.Log, reporter, build)
+	if err != nil {
+		bootstrap.Fatal().Err(err).Msg("cannot create logger")
+	}
+
+	if env.cfg.Database == "mongo" && env.cfg.DSN == "" && env.cfg.EmbeddedMongo.Enabled {
+		env.logger.Info().Str("image", env.cfg.EmbeddedMongo.Image).Msg("starting embedded mongo")
+		env.embeddedMongo, err = mongo.StartEmbedded(env.cfg.EmbeddedMongo.Image, env.cfg.EmbeddedMongo.Timeout.Duration, env.cfg.EmbeddedMongo.Expiry.Duration)
+		if err != nil {
+			env.logger.Fatal().Err(err).Msg("cannot start embedded mongo")
+		}
+		env.cfg.DSN = env.embeddedMongo.DSN
+		env.logger.Warn().Msg("embedded mongo started, its data is lost when stopped")
+	}
+	return env
+}
+
+// close stops the embedded Mongo, if any
+func (env environment) close() {
+	if env.embeddedMongo == nil {
+		return
+	}
+	if err := env.embeddedMongo.Stop(); err != nil {
+		env.logger.Error().Err(err).Msg("cannot stop embedded mongo")
+	}
+}
+
+// readConfig resolves the config again, as when reloading it, keeping the secrets fetched and the embedded Mongo started when loaded
+func (env environment) readConfig() (config.Config, error) {
+	cfg, err := config.ReadConfig(opts.Version, opts.Environment, env.cfg.Port, opts.Database, opts.DSN, "config", opts.Set)
+	if err != nil {
+		return cfg, err
+	}
+	if err := cfg.ApplySecrets(env.secretValues); err != nil {
+		return cfg, err
+	}
+	if err := cfg.DecryptValues(decrypter(cfg)); err != nil {
+		return cfg, err
+	}
+	if env.embeddedMongo != nil && cfg.DSN == "" {
+		cfg.DSN = env.embeddedMongo.DSN
+	}
+	return cfg, nil
+}
+
+// decrypter returns the function decrypting the encrypted values of the config with the KMS provider of its Encryption section,
+// created only once a value is to be decrypted, so the provider is not required when no value is encrypted
+func decrypter(cfg config.Config) func(envelope string) (string, error) {
+	var provider encryption.KeyProvider
+	return func(envelope string) (string, error) {
+		if provider == nil {
+			var err error
+			provider, err = encryption.NewKeyProvider(cfg.Encryption.KMSProvider, cfg.Encryption.LocalMasterKey, cfg.Encryption.AzureKeyURL)
+			if err != nil {
+				return "", err
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout.Duration)
+		defer cancel()
+		return encryption.OpenEnvelope(ctx, provider, envelope)
+	}
+}
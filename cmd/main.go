@@ -1,17 +1,27 @@
 package main
 
 import (
-	"context"
-	"fmt"
-	"log"
+	"errors"
+	"os"
 
-	"github.com/hashicorp/go-multierror"
 	"github.com/jessevdk/go-flags"
-	"github.com/sergicanet9/go-hexagonal-api/app/api"
-	"github.com/sergicanet9/go-hexagonal-api/app/async"
-	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/rs/zerolog"
 )
 
+// options shared by the commands, selecting the config they are run with
+type options struct {
+	Version     string   `long:"ver" env:"API_VERSION" description:"Version, overriding the one set at build time"`
+	Environment string   `long:"env" env:"API_ENVIRONMENT" description:"Environment, whose settings are read from config/config.{env}.json"`
+	Database    string   `long:"db" env:"API_DATABASE" description:"The database adapter to use, overriding the one of the config files" choice:"mongo" choice:"postgres"`
+	DSN         string   `long:"dsn" env:"API_DSN" description:"DSN of the selected database, required unless set by the secrets provider"`
+	Set         []string `long:"set" description:"Setting overriding the config files and the environment variables, like Log.Level=debug, can be repeated"`
+}
+
+var opts options
+
+// bootstrap logs the failures happening before the configured logger is created
+var bootstrap = zerolog.New(os.Stderr).With().Timestamp().Logger()
+
 // @title Go Hexagonal API
 // @description Powered by scv-go-tools - https://github.com/sergicanet9/scv-go-tools
 
@@ -19,36 +29,24 @@ import (
 // @in header
 // @name Authorization
 func main() {
-	var opts struct {
-		Version     string `long:"ver" description:"Version" required:"true"`
-		Environment string `long:"env" description:"Environment" choice:"local" choice:"dev" required:"true"`
-		Port        int    `long:"port" description:"Running port" required:"true"`
-		Database    string `long:"db" description:"The database adapter to use" choice:"mongo" choice:"postgres" required:"true"`
-		DSN         string `long:"dsn" description:"DSN of the selected database" required:"true"`
-	}
-
-	args, err := flags.Parse(&opts)
+	parser := flags.NewParser(&opts, flags.Default)
+	parser.AddCommand("serve", "Serve the API", "Serve the API along with the diagnostics and the async processes enabled in the config", &serveCommand{})
+	parser.AddCommand("migrate", "Migrate the database", "Create the MongoDB indexes or apply the PostgreSQL migrations, and verify them", &migrateCommand{})
+	parser.AddCommand("seed", "Seed the users", "Create or update, matching them by email, the users of a JSON file", &seedCommand{})
+	parser.AddCommand("create-admin", "Create an admin", "Create a user with the admin claim, or grant it to the user with the same email", &createAdminCommand{})
+	parser.AddCommand("gen-openapi", "Generate the OpenAPI document", "Write the Swagger 2.0 document of the API, as served under /swagger", &genOpenAPICommand{})
+	parser.AddCommand("gen-client", "Generate a client", "Write the Go or TypeScript client of the API, generated from its OpenAPI document", &genClientCommand{})
+	parser.AddCommand("gen-resource", "Scaffold a resource", "Write the entity, models, ports, mongo repository, service, handlers, tests and mocks of a new resource, and wire it in the API", &genResourceCommand{})
+	parser.AddCommand("verify-contract", "Verify the API contract", "Send the operations of the OpenAPI document, built from it and fuzzed, to a test instance, failing when it does not honor the document", &verifyContractCommand{})
+	parser.AddCommand("encrypt-value", "Encrypt a setting", "Encrypt a value with the KMS provider of the config, printing it to be set in the config files", &encryptValueCommand{})
+	parser.AddCommand("rotate-keys", "Rotate the data key", "Re-encrypt the encrypted fields of the users with a new data key, printing it wrapped by the KMS provider, with the API stopped", &rotateKeysCommand{})
+
+	args, err := parser.Parse()
 	if err != nil {
-		log.Fatal(fmt.Errorf("provided flags not valid: %s, %w", args, err))
-	}
-
-	cfg, err := config.ReadConfig(opts.Version, opts.Environment, opts.Port, opts.Database, opts.DSN, "config")
-	if err != nil {
-		log.Fatal(fmt.Errorf("cannot parse config file for env %s: %w", opts.Environment, err))
-	}
-
-	var g multierror.Group
-	ctx, cancel := context.WithCancel(context.Background())
-
-	a := api.New(ctx, cfg)
-	g.Go(a.Run(ctx, cancel))
-
-	if cfg.Async.Run {
-		async := async.New(cfg)
-		g.Go(async.Run(ctx, cancel))
-	}
-
-	if err := g.Wait().ErrorOrNil(); err != nil {
-		log.Fatal(err)
+		var flagsErr *flags.Error
+		if errors.As(err, &flagsErr) && flagsErr.Type == flags.ErrHelp {
+			return
+		}
+		bootstrap.Fatal().Err(err).Msgf("provided flags not valid: %s", args)
 	}
 }
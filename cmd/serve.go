@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/api"
+	"github.com/sergicanet9/go-hexagonal-api/app/async"
+	"github.com/sergicanet9/go-hexagonal-api/app/reload"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/secrets"
+)
+
+// serveCommand serves the API until stopped
+type serveCommand struct {
+	Port int `long:"port" env:"API_PORT" description:"Running port" required:"true"`
+}
+
+// Execute serves the API
+func (c *serveCommand) Execute(args []string) error {
+	env := load(c.Port)
+	cfg, logger := env.cfg, env.logger
+
+	var g multierror.Group
+	ctx, cancel := context.WithCancel(context.Background())
+	terminated := notifyTermination(ctx, cancel, logger)
+
+	if env.secretsProvider != nil && cfg.Secrets.RenewInterval.Duration > 0 {
+		go secrets.KeepRenewed(ctx, env.secretsProvider, cfg.Secrets.RenewInterval.Duration, env.secretValues, logger)
+	}
+
+	a := api.New(ctx, cfg, logger)
+	g.Go(a.Run(ctx, cancel))
+	if cfg.Upgrade.Enabled && upgradeSignal != nil {
+		go notifyUpgrade(ctx, cancel, terminated, a.Upgrade, logger)
+	}
+
+	reloader := reload.New(cfg, env.readConfig, a.Reconfigure, a.AuditService(), logger)
+	go reloader.Run(ctx, config.Files("config", opts.Environment), cfg.Reload.Interval.Duration)
+
+	if cfg.Diagnostics.Enabled && cfg.Diagnostics.Port != 0 {
+		g.Go(a.RunDiagnostics(ctx, cancel))
+	}
+
+	if cfg.GRPC.Enabled {
+		g.Go(a.RunGRPC(ctx, cancel))
+	}
+
+	if cfg.Async.Run {
+		async := async.New(cfg, logger, a.UserService(), a.RetentionService(), a.JobService(), a.BackupService(), a.KeyService(), a.MaintenanceService(), a.DeviceService(), a.BillingService(), a.SearchService(), a.ScanService(), a.DirectorySyncService(), a.LeaseStore(), a.LimitStore(), a.Scheduler(), a.Workers())
+		g.Go(async.Run(ctx, cancel))
+	}
+
+	err := g.Wait().ErrorOrNil()
+	env.close()
+	// once terminated, the processes stopping with the API are not failures
+	if err != nil && !terminated.Load() {
+		logger.Fatal().Err(err).Msg("stopped")
+	}
+	logger.Info().Msg("stopped")
+	return nil
+}
+
+// notifyTermination cancels the context on SIGTERM, as sent by Kubernetes or docker stop, or on interrupt,
+// reporting whether the termination was requested
+func notifyTermination(ctx context.Context, cancel context.CancelFunc, logger zerolog.Logger) *atomic.Bool {
+	terminated := new(atomic.Bool)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case sig := <-signals:
+			terminated.Store(true)
+			logger.Info().Str("signal", sig.String()).Msg("termination requested")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return terminated
+}
+
+// notifyUpgrade starts a new process of the binary on every upgrade signal, cancelling the context once it is ready
+// so this one drains as when terminated, or keeping this one serving when the upgrade fails
+func notifyUpgrade(ctx context.Context, cancel context.CancelFunc, terminated *atomic.Bool, upgrade func(ctx context.Context) error, logger zerolog.Logger) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, upgradeSignal)
+	defer signal.Stop(signals)
+	for {
+		select {
+		case <-signals:
+			logger.Info().Msg("upgrade requested")
+			if err := upgrade(ctx); err != nil {
+				logger.Error().Err(err).Msg("upgrade failed, still serving")
+				continue
+			}
+			terminated.Store(true)
+			cancel()
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows && !plan9
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// upgradeSignal requests a zero downtime restart of the binary
+var upgradeSignal os.Signal = syscall.SIGUSR2
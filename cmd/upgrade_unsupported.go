@@ -0,0 +1,8 @@
+//go:build windows || plan9
+
+package main
+
+import "os"
+
+// upgradeSignal is not set, as the listeners cannot be handed to another process on this platform
+var upgradeSignal os.Signal
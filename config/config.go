@@ -2,39 +2,618 @@ package config
 
 import (
 	"fmt"
-	"path"
+	"os"
 
 	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
 )
 
+type AccessLog struct {
+	Enabled       bool
+	Format        string
+	Output        string
+	Path          string
+	MaxSizeMB     int
+	MaxBackups    int
+	MaxAgeDays    int
+	Compress      bool
+	SyslogNetwork string
+	SyslogAddress string
+	SyslogTag     string
+}
+
 type Async struct {
 	Run      bool
 	Interval utils.Duration
 }
 
+type Alerting struct {
+	Run                   bool
+	Interval              utils.Duration
+	Window                utils.Duration
+	ErrorRateThreshold    float64
+	MinRequests           int64
+	FailedLoginsThreshold float64
+	SlackWebhookURL       string `secret:"true"`
+	SMTPAddress           string
+	SMTPUsername          string
+	SMTPPassword          string `secret:"true"`
+	EmailFrom             string
+	EmailTo               []string
+	SMSTo                 []string
+}
+
+// Analytics settings of the product analytics of the signups, logins and profile changes of the users, sent when Enabled
+// to the source of WriteKey at the Endpoint of Segment or of a destination compatible with its HTTP Tracking API,
+// except for the users whose email domain is any of OptOutDomains
+type Analytics struct {
+	Enabled       bool
+	WriteKey      string `secret:"true"`
+	Endpoint      string
+	OptOutDomains []string
+}
+
+// Antivirus settings of the scanning of the uploaded files by the Provider, only clamav for now, streaming them to clamd at ClamAVAddress,
+// a host and port or the path of its unix socket, each scan given at most Timeout. The uploads stay in quarantine until found clean by the workers.
+type Antivirus struct {
+	Provider      string
+	ClamAVAddress string
+	Timeout       utils.Duration
+}
+
+type Archive struct {
+	Run              bool
+	Interval         utils.Duration
+	InactivityPeriod utils.Duration
+}
+
+type Audit struct {
+	MaxSize      int64
+	MaxDocuments int64
+	Retention    utils.Duration
+}
+
+type Backup struct {
+	Timeout utils.Duration
+}
+
+// Billing settings of the customers of the users in the billing Provider, only stripe for now, authenticated with StripeSecretKey,
+// created or updated as the users are, and of the status of their subscriptions, synced from the events posted to its webhook,
+// signed with WebhookSecret no longer than WebhookTolerance ago. The routes starting with any of SubscriptionRoutes require an active subscription.
+type Billing struct {
+	Provider           string
+	StripeSecretKey    string `secret:"true"`
+	WebhookSecret      string `secret:"true"`
+	WebhookTolerance   utils.Duration
+	SubscriptionRoutes []string
+}
+
+// Cache settings of the users cached in memory by ID, up to MaxEntries for TTL, evicted in every replica once changed
+// through the pub/sub Channel of the Redis server at RedisAddress, or only in the replica changing them when it is not set
+type Cache struct {
+	Enabled       bool
+	TTL           utils.Duration
+	MaxEntries    int
+	RedisAddress  string
+	RedisPassword string `secret:"true"`
+	Channel       string
+}
+
+type Capture struct {
+	MaxDuration utils.Duration
+	TTL         utils.Duration
+	MaxBodySize int
+}
+
+// Dashboard settings of the admin dashboard, whose figures are aggregated again once the last ones are older than MaxAge
+type Dashboard struct {
+	MaxAge utils.Duration
+}
+
+type Diagnostics struct {
+	Enabled bool
+	Port    int
+}
+
+// GeoIP settings of the location of the client IPs by the Provider, maxmind reading the MaxMind database at DatabasePath, like GeoLite2-City.mmdb,
+// or ipinfo calling its API with IPInfoToken, its lookups cached for CacheTTL up to CacheMaxEntries, each lookup given at most Timeout.
+// The audit events are located, and a login from a country its user did not log in from for KnownCountryTTL is recorded as suspicious.
+type GeoIP struct {
+	Provider        string
+	DatabasePath    string
+	IPInfoToken     string `secret:"true"`
+	CacheTTL        utils.Duration
+	CacheMaxEntries int
+	Timeout         utils.Duration
+	KnownCountryTTL utils.Duration
+}
+
+// GRPC settings of the gRPC server of the users contract, served on its own port when Enabled
+type GRPC struct {
+	Enabled bool
+	Port    int
+}
+
+// Email settings of the transactional emails, like the security alerts, sent from From through Provider, smtp, sendgrid or ses,
+// none being sent when it is not set
+// DirectorySync settings of the sync of the users of Domains with the ones listed by Source: sftp, a CSV file at SFTPPath on the server at SFTPAddress,
+// authenticated as SFTPUsername with SFTPPassword or SFTPPrivateKey and verified by its SFTPHostKey, in the authorized_keys format, http, a JSON array or a CSV file
+// fetched from HTTPURL with the bearer HTTPToken, or ldap, the entries of LDAP.Directories, whose domains are synced when Domains is not set.
+// The CSV files have a header row naming their email, name and surnames columns. The syncs are queued by the admins or by the directory_sync scheduled job,
+// in dry run when DryRun is set, each one given at most Timeout, and aborted when more than MaxDeactivations users would be deactivated, unless it is 0.
+type DirectorySync struct {
+	Source           string
+	Domains          []string
+	DryRun           bool
+	MaxDeactivations int
+	Timeout          utils.Duration
+	SFTPAddress      string
+	SFTPUsername     string
+	SFTPPassword     string `secret:"true"`
+	SFTPPrivateKey   string `secret:"true"`
+	SFTPHostKey      string
+	SFTPPath         string
+	HTTPURL          string
+	HTTPToken        string `secret:"true"`
+}
+
+type Email struct {
+	Provider       string
+	From           string
+	SMTPAddress    string
+	SMTPUsername   string
+	SMTPPassword   string `secret:"true"`
+	SendGridAPIKey string `secret:"true"`
+	SESRegion      string
+}
+
+// EmailCheck settings of the deliverability check of the emails of the users created or changing it, in Mode reject, refusing the undeliverable ones,
+// or flag, recording them to the audit log, none being checked when it is not set. Their syntax is checked, their domain against the built-in disposable
+// domains, DisposableDomains and the ones listed in the file at DisposableListPath, one per line, and its mail servers when CheckMX is set,
+// each check given at most Timeout.
+type EmailCheck struct {
+	Mode               string
+	CheckMX            bool
+	DisposableDomains  []string
+	DisposableListPath string
+	Timeout            utils.Duration
+}
+
+type EmbeddedMongo struct {
+	Enabled bool
+	Image   string
+	Timeout utils.Duration
+	Expiry  utils.Duration
+}
+
+type Encryption struct {
+	Enabled        bool
+	Fields         []string
+	KMSProvider    string
+	DataKey        string `secret:"true"`
+	LocalMasterKey string `secret:"true"`
+	AzureKeyURL    string
+}
+
+// Events settings of the user lifecycle events, published through Broker, kafka, nats or rabbitmq, encoded as Format, json or avro,
+// to the topic of their type in Topics or else to Topic, none being published when it is not set
+type Events struct {
+	Broker           string
+	Format           string
+	Topic            string
+	Topics           map[string]string
+	ClientID         string
+	KafkaBrokers     []string
+	KafkaTLS         bool
+	NATSURL          string `secret:"true"`
+	RabbitMQURL      string `secret:"true"`
+	RabbitMQExchange string
+}
+
+type Hashing struct {
+	Workers int
+}
+
+type Health struct {
+	CheckTimeout utils.Duration
+	StatusMaxAge utils.Duration
+}
+
+// KeyRotation settings of the keys signing the tokens, rotated by the key_rotation scheduled job:
+// the previous keys keep verifying the tokens for Overlap, and a key not known is looked up in the store at most every MinRefreshInterval
+type KeyRotation struct {
+	Overlap            utils.Duration
+	MinRefreshInterval utils.Duration
+}
+
+// LDAP settings of the LDAP or Active Directory servers verifying the passwords of the users of their tenants instead of their local passwords,
+// each verification given at most Timeout
+type LDAP struct {
+	Directories []LDAPDirectory
+	Timeout     utils.Duration
+}
+
+// LDAPDirectory settings of the directory of a tenant, the users whose email is of one of its Domains, at URL, ldap:// or ldaps://, starting TLS with StartTLS.
+// The entry of a user is searched under BaseDN by its email in UserAttribute, mail by default or userPrincipalName for Active Directory, and of ObjectClass when set,
+// bound as BindDN with BindPassword, or anonymously when empty. Its NameAttribute and SurnamesAttribute, givenName and sn by default, are synced into the user.
+type LDAPDirectory struct {
+	Domains           []string
+	URL               string
+	StartTLS          bool
+	BindDN            string
+	BindPassword      string `secret:"true"`
+	BaseDN            string
+	UserAttribute     string
+	ObjectClass       string
+	NameAttribute     string
+	SurnamesAttribute string
+}
+
+type Leader struct {
+	Enabled  bool
+	LeaseTTL utils.Duration
+}
+
+type Lockout struct {
+	Enabled     bool
+	MaxFailures int64
+	Duration    utils.Duration
+}
+
+// MFA settings of the one-time codes emailed at login to the users whose tenant requires MFA in its security policy:
+// a code is valid for CodeTTL, and at most MaxAttempts codes are sent or checked for a user per CodeTTL.
+type MFA struct {
+	CodeTTL     utils.Duration
+	MaxAttempts int64
+}
+
+type Log struct {
+	Level              string
+	RequestLevel       string
+	RequestSampleRatio float64
+	RouteSampleRatios  map[string]float64
+	Console            bool
+	TrustProxyHeaders  bool
+}
+
+// Maintenance settings of the maintenance tasks started by the admins, whose jobs are given Timeout
+type Maintenance struct {
+	Timeout utils.Duration
+}
+
+// Middleware settings of the middlewares the requests run through, by name and in order, the first one being the outermost:
+// Chain for every route, or the chain of Groups of the longest path prefix of the route. The CORS and Compress middlewares are set by their settings.
+type Middleware struct {
+	Chain    []string
+	Groups   map[string][]string
+	CORS     CORS
+	Compress Compress
+}
+
+// CORS settings of the requests allowed from the browsers of other origins, AllowedOrigins, "*" allowing every one, with AllowedMethods and AllowedHeaders,
+// and with their cookies when AllowCredentials, the browsers caching the preflight responses for MaxAge
+type CORS struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           utils.Duration
+}
+
+// Compress settings of the gzip compression of the responses, at Level, from 1, the fastest, to 9, the smallest
+type Compress struct {
+	Level int
+}
+
+type Monitoring struct {
+	SlowQueryThreshold   utils.Duration
+	SlowRequestThreshold utils.Duration
+	RepositoryMetrics    bool
+}
+
+// Notifications settings of the notices of the Events of interest for the admins, lockout, alert or migration,
+// posted to SlackWebhookURL and to every one of WebhookURLs, signed with WebhookSecret when set, each post given at most Timeout
+type Notifications struct {
+	Events          []string
+	SlackWebhookURL string   `secret:"true"`
+	WebhookURLs     []string `secret:"true"`
+	WebhookSecret   string   `secret:"true"`
+	Timeout         utils.Duration
+}
+
+// Notifies reports whether the admins are notified of the event
+func (n Notifications) Notifies(event string) bool {
+	for _, e := range n.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// OIDC settings of the OpenID Connect discovery document served when Enabled, describing the Issuer, the public base URL of the API
+// set as the iss claim of the tokens, along with its OAuth token endpoint, so the OIDC client libraries can configure themselves
+type OIDC struct {
+	Enabled bool
+	Issuer  string
+}
+
+// Preflight settings of the checks of the dependencies run at startup, before listening, each one given at most Timeout:
+// the Mongo server at least MinMongoVersion and, with RequireReplicaSet, a replica set or a mongos router,
+// the Redis server at RedisAddress, when set, and the login to Alerting.SMTPAddress, when set, only reported when failing.
+type Preflight struct {
+	Enabled           bool
+	Timeout           utils.Duration
+	MinMongoVersion   string
+	RequireReplicaSet bool
+	RedisAddress      string
+	RedisPassword     string `secret:"true"`
+}
+
+// Queue settings of the job queue, whose jobs are run by Workers workers per replica and retried on failure
+// after a backoff doubled from InitialBackoff up to MaxBackoff, until MaxAttempts attempts failed.
+// Timeout is given to the jobs other than the backups and restores, which are given Backup.Timeout.
+type Queue struct {
+	Workers        int
+	PollInterval   utils.Duration
+	MaxAttempts    int
+	InitialBackoff utils.Duration
+	MaxBackoff     utils.Duration
+	Timeout        utils.Duration
+}
+
+type RateLimit struct {
+	Enabled  bool
+	Requests int64
+	Window   utils.Duration
+}
+
+type Reload struct {
+	Interval utils.Duration
+}
+
+// Repository settings of the deadlines of the operations of the repositories, Timeout by default and the ones of Timeouts
+// for the operations named after their collection and operation, like users.Dump, a 0 one setting no deadline
+type Repository struct {
+	Timeout  utils.Duration
+	Timeouts map[string]utils.Duration
+}
+
+type Reporting struct {
+	Enabled    bool
+	DSN        string `secret:"true"`
+	SampleRate float64
+}
+
+type Retention struct {
+	DryRun   bool
+	Policies []RetentionPolicy
+}
+
+type RetentionPolicy struct {
+	Collection string
+	Action     string
+	MaxAge     utils.Duration
+}
+
+// ScheduledJob settings of a job of the scheduler, run on the schedule of its cron expression, like */15 * * * * or @daily, in UTC
+type ScheduledJob struct {
+	Enabled  bool
+	Schedule string
+}
+
+type Scheduler struct {
+	Jobs map[string]ScheduledJob
+}
+
+// Search settings of the users mirrored when Enabled into the Index of the Elasticsearch or OpenSearch cluster at URL,
+// authenticated with Username and Password when set, as they change in the change stream of the users collection,
+// so only available with mongo on a replica set or a sharded cluster. A sync failing is resumed after RetryInterval.
+type Search struct {
+	Enabled       bool
+	URL           string
+	Index         string
+	Username      string
+	Password      string `secret:"true"`
+	RetryInterval utils.Duration
+}
+
+type Secrets struct {
+	Provider         string
+	VaultAddress     string
+	VaultToken       string `secret:"true"`
+	VaultMount       string
+	VaultPath        string
+	AWSRegion        string
+	AWSSecretID      string
+	AWSParameterPath string
+	CacheTTL         utils.Duration
+	RenewInterval    utils.Duration
+}
+
+// Sharding settings of the shard key the users collection is sharded by on MongoDB sharded clusters,
+// the email by default, ranged unless Hashed is set, requiring the preflight checks of MongoDB 7.1 or later
+type Sharding struct {
+	Enabled bool
+	Key     string
+	Hashed  bool
+}
+
+// Push settings of the push notifications of the security events, the logins from a device not logging in for KnownDeviceTTL and the password changes,
+// sent to the devices registered by the users when Enabled: through Firebase Cloud Messaging, authorized with FCMCredentials, the JSON key of a service account,
+// to FCMProjectID or the project of the key, and through the Apple Push Notification service, authorized with APNsPrivateKey, the PEM of the .p8 key APNsKeyID of APNsTeamID,
+// to the app of APNsTopic, its bundle ID, in the production environment with APNsProduction or the sandbox one.
+type Push struct {
+	Enabled        bool
+	FCMProjectID   string
+	FCMCredentials string `secret:"true"`
+	APNsKeyID      string
+	APNsTeamID     string
+	APNsPrivateKey string `secret:"true"`
+	APNsTopic      string
+	APNsProduction bool
+	KnownDeviceTTL utils.Duration
+}
+
+type Shutdown struct {
+	DrainDelay utils.Duration
+	Timeout    utils.Duration
+}
+
+// SIEM settings of the export of the audit events to the Sink, syslog sending them as CEF messages to the syslog server at SyslogAddress over SyslogNetwork,
+// udp, tcp or tls, or http posting them as JSON to URL with Token, none being exported when it is not set. Up to BufferSize events are buffered
+// and sent in batches of BatchSize at least every FlushInterval, each attempt given at most Timeout and the failed ones retried backing off up to MaxBackoff.
+type SIEM struct {
+	Sink          string
+	SyslogNetwork string
+	SyslogAddress string
+	URL           string
+	Token         string `secret:"true"`
+	BufferSize    int
+	BatchSize     int
+	FlushInterval utils.Duration
+	Timeout       utils.Duration
+	MaxBackoff    utils.Duration
+}
+
+// SMS settings of the text messages sent from From through Provider, twilio, none being sent when it is not set:
+// at most MaxPerRecipient messages are sent to a phone number, and MaxPerClient requested from an IP, per Window, and its one-time codes, valid for CodeTTL, can be checked MaxCheckAttempts times per Window.
+// The provider posts the delivery statuses to StatusCallbackURL, the public URL of /v1/sms/status, when set.
+type SMS struct {
+	Provider          string
+	From              string
+	TwilioAccountSID  string
+	TwilioAuthToken   string `secret:"true"`
+	StatusCallbackURL string
+	MaxPerRecipient   int64
+	MaxPerClient      int64
+	MaxCheckAttempts  int64
+	Window            utils.Duration
+	CodeTTL           utils.Duration
+}
+
+type Startup struct {
+	Timeout        utils.Duration
+	AttemptTimeout utils.Duration
+	InitialBackoff utils.Duration
+	MaxBackoff     utils.Duration
+}
+
+// Storage settings of the file storage: the database, or the Bucket of S3 in S3Region, or at S3Endpoint for the S3 compatible services like MinIO,
+// when Provider is s3, presigning URLs valid for PresignExpiry
+type Storage struct {
+	MaxUploadSize     int64
+	Provider          string
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3Prefix          string
+	S3PathStyle       bool
+	S3AccessKeyID     string
+	S3SecretAccessKey string `secret:"true"`
+	PresignExpiry     utils.Duration
+}
+
+// Upgrade settings of the zero downtime restarts: when Enabled, SIGUSR2 starts the binary again, handing it the listeners,
+// and drains this process once the new one serves, or keeps serving if it is not ready within Timeout.
+// ReusePort sets SO_REUSEPORT on the listeners, so other processes can listen on the same ports and share the connections.
+type Upgrade struct {
+	Enabled   bool
+	ReusePort bool
+	Timeout   utils.Duration
+}
+
+type Tracing struct {
+	Enabled           bool
+	Endpoint          string
+	Insecure          bool
+	ServiceName       string
+	SampleRatio       float64
+	RouteSampleRatios map[string]float64
+}
+
+// Config of the API.
+// Fields tagged as secret are redacted when the config is inspected.
 type Config struct {
 	// set in flags
 	Version     string
 	Environment string
 	Port        int
-	Database    string
-	DSN         string
+	Database    string // or in json config files
+	DSN         string `secret:"true"`
 
 	// set in json config files
 	config
 }
 
 type config struct {
+	Database              string
 	PostgresMigrationsDir string
-	JWTSecret             string
+	JWTSecret             string `secret:"true"`
 	Timeout               utils.Duration
+	AccessLog             AccessLog
+	Alerting              Alerting
+	Analytics             Analytics
+	Antivirus             Antivirus
 	Async                 Async
+	Archive               Archive
+	Audit                 Audit
+	Backup                Backup
+	Billing               Billing
+	Cache                 Cache
+	Capture               Capture
+	Dashboard             Dashboard
+	Diagnostics           Diagnostics
+	DirectorySync         DirectorySync
+	Email                 Email
+	EmailCheck            EmailCheck
+	EmbeddedMongo         EmbeddedMongo
+	Encryption            Encryption
+	Events                Events
+	GeoIP                 GeoIP
+	GRPC                  GRPC
+	Hashing               Hashing
+	Health                Health
+	KeyRotation           KeyRotation
+	LDAP                  LDAP
+	Leader                Leader
+	Lockout               Lockout
+	Log                   Log
+	MFA                   MFA
+	Maintenance           Maintenance
+	Middleware            Middleware
+	Storage               Storage
+	Monitoring            Monitoring
+	Notifications         Notifications
+	OIDC                  OIDC
+	Preflight             Preflight
+	Push                  Push
+	Queue                 Queue
+	RateLimit             RateLimit
+	ReadPreferences       map[string]string
+	Reload                Reload
+	Repository            Repository
+	Reporting             Reporting
+	Retention             Retention
+	Scheduler             Scheduler
+	Search                Search
+	Secrets               Secrets
+	Sharding              Sharding
+	Shutdown              Shutdown
+	SIEM                  SIEM
+	SMS                   SMS
+	Startup               Startup
+	Tracing               Tracing
+	Upgrade               Upgrade
 }
 
 // ReadConfig from the project´s JSON config files.
 // Default values are specified in the default configuration file, config/config.json
-// and can be overrided with values specified in the environment configuration files, config/config.{env}.json.
-func ReadConfig(version, env string, port int, database, dsn, configPath string) (Config, error) {
+// and can be overrided with values specified in the environment configuration files, config/config.{env}.json,
+// then with the ones of the optional local configuration file, config/config.override.json, not to be committed,
+// then with the environment variables named after the settings, like API_LOG_LEVEL, and finally with the given Section.Field=value settings,
+// like Log.Level=debug, set in the flags.
+// The database adapter can be set either in the config files or in the flags.
+func ReadConfig(version, env string, port int, database, dsn, configPath string, sets []string) (Config, error) {
 	var c Config
 	c.Version = version
 	c.Environment = env
@@ -44,15 +623,24 @@ func ReadConfig(version, env string, port int, database, dsn, configPath string)
 
 	var cfg config
 
-	if err := utils.LoadJSON(path.Join(configPath, "config.json"), &cfg); err != nil {
-		return c, fmt.Errorf("error parsing configuration, %s", err)
+	if err := loadLayers(&cfg, configPath, env); err != nil {
+		return c, err
 	}
 
-	if err := utils.LoadJSON(path.Join(configPath, "config."+env+".json"), &cfg); err != nil {
-		return c, fmt.Errorf("error parsing environment configuration, %s", err)
+	if err := applyEnv(&cfg, os.LookupEnv); err != nil {
+		return c, fmt.Errorf("error overriding configuration, %s", err)
+	}
+
+	if err := applySets(&cfg, sets); err != nil {
+		return c, fmt.Errorf("error overriding configuration, %s", err)
 	}
 
 	c.config = cfg
 
+	// the database flag, when provided, overrides the one of the config files
+	if c.Database == "" {
+		c.Database = cfg.Database
+	}
+
 	return c, nil
 }
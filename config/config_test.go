@@ -2,9 +2,11 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"path"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -19,7 +21,7 @@ func TestReadConfig_Ok(t *testing.T) {
 	}
 
 	// Act
-	cfg, err := ReadConfig("", expectedConfig.Environment, 0, "", "", path.Join(path.Dir(filePath)))
+	cfg, err := ReadConfig("", expectedConfig.Environment, 0, "", "", path.Join(path.Dir(filePath)), nil)
 
 	// Assert
 	assert.NotEmpty(t, cfg)
@@ -34,7 +36,7 @@ func TestReadConfig_NonExistentConfigPath(t *testing.T) {
 	expectedError := fmt.Sprintf("error parsing configuration, ignoring config file %s/config.json: stat %s/config.json: no such file or directory", invalidPath, invalidPath)
 
 	// Act
-	_, err := ReadConfig("", "", 0, "", "", invalidPath)
+	_, err := ReadConfig("", "", 0, "", "", invalidPath, nil)
 
 	// Assert
 	assert.Equal(t, expectedError, err.Error())
@@ -52,7 +54,170 @@ func TestReadConfig_NonExistentEnvironmentFile(t *testing.T) {
 	expectedError := fmt.Sprintf("error parsing environment configuration, ignoring config file %s/config.invalid-environment.json: stat %s/config.invalid-environment.json: no such file or directory", path.Join(path.Dir(filePath)), path.Join(path.Dir(filePath)))
 
 	// Act
-	_, err := ReadConfig("", expectedConfig.Environment, 0, "", "", path.Join(path.Dir(filePath)))
+	_, err := ReadConfig("", expectedConfig.Environment, 0, "", "", path.Join(path.Dir(filePath)), nil)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestReadConfig_DatabaseFromConfigFiles checks that ReadConfig takes the database from the config files when the flag is not provided
+func TestReadConfig_DatabaseFromConfigFiles(t *testing.T) {
+	// Arrange
+	configPath := t.TempDir()
+	writeConfigFile(t, configPath, "config.json", `{"Database": "mongo"}`)
+	writeConfigFile(t, configPath, "config.test.json", `{"Database": "postgres"}`)
+
+	// Act
+	cfg, err := ReadConfig("", "test", 0, "", "", configPath, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "postgres", cfg.Database)
+}
+
+// TestReadConfig_DatabaseFlagOverride checks that the database flag overrides the one of the config files
+func TestReadConfig_DatabaseFlagOverride(t *testing.T) {
+	// Arrange
+	configPath := t.TempDir()
+	writeConfigFile(t, configPath, "config.json", `{"Database": "mongo"}`)
+	writeConfigFile(t, configPath, "config.test.json", `{}`)
+
+	// Act
+	cfg, err := ReadConfig("", "test", 0, "postgres", "", configPath, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "postgres", cfg.Database)
+}
+
+func writeConfigFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path.Join(dir, name), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestReadConfig_Overrides checks that the environment variables override the config files, and the settings of the flags override both
+func TestReadConfig_Overrides(t *testing.T) {
+	// Arrange
+	configPath := t.TempDir()
+	writeConfigFile(t, configPath, "config.json", `{"JWTSecret": "file-secret", "Timeout": "1s", "Log": {"Level": "info", "RequestLevel": "info"}, "Alerting": {"EmailTo": ["file@test.com"]}}`)
+	writeConfigFile(t, configPath, "config.test.json", `{"Log": {"Level": "warn"}}`)
+	t.Setenv("API_JWTSECRET", "env-secret")
+	t.Setenv("API_TIMEOUT", "5s")
+	t.Setenv("API_LOG_LEVEL", "debug")
+	t.Setenv("API_LOG_REQUESTLEVEL", "debug")
+	t.Setenv("API_ALERTING_EMAILTO", "ops@test.com, oncall@test.com")
+	t.Setenv("API_TRACING_SAMPLERATIO", "0.5")
+	t.Setenv("API_READPREFERENCES", `{"GetAll": "primary"}`)
+
+	// Act
+	cfg, err := ReadConfig("", "test", 0, "", "", configPath, []string{"log.requestlevel=error", "Tracing.Enabled=true"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "env-secret", cfg.JWTSecret)
+	assert.Equal(t, 5*time.Second, cfg.Timeout.Duration)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.Equal(t, "error", cfg.Log.RequestLevel)
+	assert.Equal(t, []string{"ops@test.com", "oncall@test.com"}, cfg.Alerting.EmailTo)
+	assert.Equal(t, 0.5, cfg.Tracing.SampleRatio)
+	assert.True(t, cfg.Tracing.Enabled)
+	assert.Equal(t, map[string]string{"GetAll": "primary"}, cfg.ReadPreferences)
+}
+
+// TestReadConfig_Layers checks that ReadConfig merges the sections and maps of the environment and local config files over the default ones,
+// replacing the lists as a whole
+func TestReadConfig_Layers(t *testing.T) {
+	// Arrange
+	configPath := t.TempDir()
+	writeConfigFile(t, configPath, "config.json", `{"Log": {"Level": "info", "Console": true}, "ReadPreferences": {"GetAll": "secondary", "Search": "secondary"},
+		"Retention": {"Policies": [{"Collection": "jobs", "Action": "purge", "MaxAge": "1h"}, {"Collection": "audit_events", "Action": "purge"}]}}`)
+	writeConfigFile(t, configPath, "config.test.json", `{"log": {"level": "warn"}, "ReadPreferences": {"GetAll": "primary"}, "Retention": {"Policies": [{"Collection": "users_archive"}]}}`)
+	writeConfigFile(t, configPath, "config.override.json", `{"Log": {"Level": "debug"}}`)
+
+	// Act
+	cfg, err := ReadConfig("", "test", 0, "", "", configPath, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.True(t, cfg.Log.Console)
+	assert.Equal(t, map[string]string{"GetAll": "primary", "Search": "secondary"}, cfg.ReadPreferences)
+	assert.Equal(t, []RetentionPolicy{{Collection: "users_archive"}}, cfg.Retention.Policies)
+}
+
+// TestReadConfig_InvalidLocalFile checks that ReadConfig returns an error when the local config file is not valid
+func TestReadConfig_InvalidLocalFile(t *testing.T) {
+	// Arrange
+	configPath := t.TempDir()
+	writeConfigFile(t, configPath, "config.json", `{}`)
+	writeConfigFile(t, configPath, "config.test.json", `{}`)
+	writeConfigFile(t, configPath, "config.override.json", `[]`)
+
+	// Act
+	_, err := ReadConfig("", "test", 0, "", "", configPath, nil)
+
+	// Assert
+	assert.ErrorContains(t, err, "error parsing local configuration, ")
+}
+
+// TestReadConfig_InvalidType checks that ReadConfig returns an error when a setting of the merged config files does not match its type
+func TestReadConfig_InvalidType(t *testing.T) {
+	// Arrange
+	configPath := t.TempDir()
+	writeConfigFile(t, configPath, "config.json", `{"Log": {"Level": "info"}}`)
+	writeConfigFile(t, configPath, "config.test.json", `{"Log": {"Level": 1}}`)
+
+	// Act
+	_, err := ReadConfig("", "test", 0, "", "", configPath, nil)
+
+	// Assert
+	assert.ErrorContains(t, err, "error parsing configuration, json: cannot unmarshal number into Go struct field")
+}
+
+// TestReadConfig_InvalidEnvironmentVariable checks that ReadConfig returns an error when an environment variable does not match the type of its setting
+func TestReadConfig_InvalidEnvironmentVariable(t *testing.T) {
+	// Arrange
+	configPath := t.TempDir()
+	writeConfigFile(t, configPath, "config.json", `{}`)
+	writeConfigFile(t, configPath, "config.test.json", `{}`)
+	t.Setenv("API_HASHING_WORKERS", "many")
+	expectedError := "error overriding configuration, environment variable API_HASHING_WORKERS not valid: invalid character 'm' looking for beginning of value"
+
+	// Act
+	_, err := ReadConfig("", "test", 0, "", "", configPath, nil)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestReadConfig_UnknownSetting checks that ReadConfig returns an error when a setting of the flags does not exist
+func TestReadConfig_UnknownSetting(t *testing.T) {
+	// Arrange
+	configPath := t.TempDir()
+	writeConfigFile(t, configPath, "config.json", `{}`)
+	writeConfigFile(t, configPath, "config.test.json", `{}`)
+	expectedError := "error overriding configuration, setting Log.Unknown not found"
+
+	// Act
+	_, err := ReadConfig("", "test", 0, "", "", configPath, []string{"Log.Unknown=true"})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestReadConfig_InvalidSetting checks that ReadConfig returns an error when a setting of the flags is not an assignment
+func TestReadConfig_InvalidSetting(t *testing.T) {
+	// Arrange
+	configPath := t.TempDir()
+	writeConfigFile(t, configPath, "config.json", `{}`)
+	writeConfigFile(t, configPath, "config.test.json", `{}`)
+	expectedError := `error overriding configuration, setting "Log.Level" not valid, it must be like Section.Field=value`
+
+	// Act
+	_, err := ReadConfig("", "test", 0, "", "", configPath, []string{"Log.Level"})
 
 	// Assert
 	assert.Equal(t, expectedError, err.Error())
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EncryptedPrefix and EncryptedSuffix enclose the encrypted values of the settings, like ENC[AQAg...],
+// the envelopes sealed with the KMS provider of the Encryption section
+const (
+	EncryptedPrefix = "ENC["
+	EncryptedSuffix = "]"
+)
+
+// DecryptValues replaces the encrypted values of the settings, including the ones in lists and maps, with their plaintext,
+// decrypting the envelope between EncryptedPrefix and EncryptedSuffix with the given function,
+// so the config files can be committed with settings too sensitive to be kept in plaintext but not worth a secrets manager
+func (c *Config) DecryptValues(decrypt func(envelope string) (string, error)) error {
+	return decryptValue(reflect.ValueOf(c).Elem(), "", decrypt)
+}
+
+// decryptValue decrypts the encrypted strings of the given setting, or of the fields, elements or map values within it
+func decryptValue(v reflect.Value, name string, decrypt func(envelope string) (string, error)) error {
+	switch {
+	case v.Kind() == reflect.String:
+		raw := v.String()
+		if !strings.HasPrefix(raw, EncryptedPrefix) || !strings.HasSuffix(raw, EncryptedSuffix) {
+			return nil
+		}
+		value, err := decrypt(strings.TrimSuffix(strings.TrimPrefix(raw, EncryptedPrefix), EncryptedSuffix))
+		if err != nil {
+			return fmt.Errorf("cannot decrypt setting %s: %w", name, err)
+		}
+		v.SetString(value)
+	case isSection(v.Type()):
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			// the fields of the config files are decrypted as well, being embedded
+			if !field.IsExported() && !field.Anonymous {
+				continue
+			}
+			fieldName := name
+			if !field.Anonymous {
+				fieldName = strings.TrimPrefix(name+"."+field.Name, ".")
+			}
+			if err := decryptValue(v.Field(i), fieldName, decrypt); err != nil {
+				return err
+			}
+		}
+	case v.Kind() == reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := decryptValue(v.Index(i), fmt.Sprintf("%s[%d]", name, i), decrypt); err != nil {
+				return err
+			}
+		}
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		// the map values cannot be set in place, so they are decrypted in a copy set back in the map
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			if err := decryptValue(elem, fmt.Sprintf("%s[%s]", name, iter.Key().String()), decrypt); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+	return nil
+}
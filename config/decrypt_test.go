@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// reverse decrypts the envelopes of the tests, being their value reversed
+func reverse(envelope string) (string, error) {
+	if envelope == "" {
+		return "", errors.New("test-error")
+	}
+	runes := []rune(envelope)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes), nil
+}
+
+// TestDecryptValues_Ok checks that DecryptValues replaces the encrypted values of the settings, the ones of the lists and maps included, keeping the other ones
+func TestDecryptValues_Ok(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.DSN = "ENC[tset/tsohlacol//:sergtsop]"
+	cfg.JWTSecret = "ENC[terces-tset]"
+	cfg.Alerting.SMTPPassword = "ENC[drowssap-tset]"
+	cfg.Alerting.SMTPUsername = "test-username"
+	cfg.Encryption.Fields = []string{"email", "ENC[eman]"}
+	cfg.ReadPreferences = map[string]string{"GetAll": "ENC[yradnoces]"}
+	cfg.Retention.Policies = []RetentionPolicy{{Collection: "ENC[sboj]"}}
+
+	// Act
+	err := cfg.DecryptValues(reverse)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "postgres://localhost/test", cfg.DSN)
+	assert.Equal(t, "test-secret", cfg.JWTSecret)
+	assert.Equal(t, "test-password", cfg.Alerting.SMTPPassword)
+	assert.Equal(t, "test-username", cfg.Alerting.SMTPUsername)
+	assert.Equal(t, []string{"email", "name"}, cfg.Encryption.Fields)
+	assert.Equal(t, map[string]string{"GetAll": "secondary"}, cfg.ReadPreferences)
+	assert.Equal(t, "jobs", cfg.Retention.Policies[0].Collection)
+}
+
+// TestDecryptValues_Error checks that DecryptValues returns an error naming the setting that cannot be decrypted
+func TestDecryptValues_Error(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.Alerting.SMTPPassword = "ENC[]"
+
+	// Act
+	err := cfg.DecryptValues(reverse)
+
+	// Assert
+	assert.EqualError(t, err, "cannot decrypt setting Alerting.SMTPPassword: test-error")
+}
+
+// TestDecryptValues_NotEncrypted checks that DecryptValues does not decrypt anything when no value is encrypted
+func TestDecryptValues_NotEncrypted(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.JWTSecret = "ENC[test-secret"
+	cfg.Alerting.SMTPPassword = "test-password"
+
+	// Act
+	err := cfg.DecryptValues(func(envelope string) (string, error) {
+		t.Fatalf("unexpected decryption of %s", envelope)
+		return "", nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(cfg.JWTSecret, EncryptedPrefix))
+}
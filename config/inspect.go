@@ -0,0 +1,79 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// redactedValue replaces the values of the secret fields that are set
+const redactedValue = "[REDACTED]"
+
+// durationType is rendered as its string, like in the config files
+var durationType = reflect.TypeOf(utils.Duration{})
+
+// Redacted returns the effective config as a map of its sections and fields, named as in the config files,
+// with the values of the secret fields replaced when set, so that it can be inspected without exposing them
+func (c Config) Redacted() map[string]interface{} {
+	result := redactStruct(reflect.ValueOf(c))
+	// the fields of the config files are shadowed by the ones of the flags with the same name, like Database
+	for k, value := range redactStruct(reflect.ValueOf(c.config)) {
+		if _, ok := result[k]; !ok {
+			result[k] = value
+		}
+	}
+	return result
+}
+
+// Features returns the state of the feature flags, being the Enabled or Run fields of the sections, like Tracing or Archive
+func (c Config) Features() map[string]bool {
+	features := make(map[string]bool)
+	v := reflect.ValueOf(c.config)
+	for i := 0; i < v.NumField(); i++ {
+		section := v.Field(i)
+		if section.Kind() != reflect.Struct {
+			continue
+		}
+		for _, flag := range []string{"Enabled", "Run"} {
+			if f := section.FieldByName(flag); f.IsValid() && f.Kind() == reflect.Bool {
+				features[v.Type().Field(i).Name] = f.Bool()
+			}
+		}
+	}
+	return features
+}
+
+// redactStruct returns the exported fields of a struct
+func redactStruct(v reflect.Value) map[string]interface{} {
+	result := make(map[string]interface{})
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		result[field.Name] = redactValue(v.Field(i), field.Tag.Get("secret") == "true")
+	}
+	return result
+}
+
+func redactValue(v reflect.Value, secret bool) interface{} {
+	switch {
+	case secret:
+		if v.IsZero() {
+			return v.Interface()
+		}
+		return redactedValue
+	case v.Type() == durationType:
+		return v.Interface().(utils.Duration).String()
+	case v.Kind() == reflect.Struct:
+		return redactStruct(v)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct:
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			values[i] = redactStruct(v.Index(i))
+		}
+		return values
+	default:
+		return v.Interface()
+	}
+}
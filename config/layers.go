@@ -0,0 +1,88 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// layer config file merged over the previous ones
+type layer struct {
+	file     string
+	name     string
+	optional bool
+}
+
+// Files returns the config files read for the given environment, from the lowest to the highest precedence:
+// the defaults, the overlay of the environment and the optional local overrides
+func Files(configPath, env string) []string {
+	return []string{
+		path.Join(configPath, "config.json"),
+		path.Join(configPath, "config."+env+".json"),
+		path.Join(configPath, "config.override.json"),
+	}
+}
+
+// loadLayers reads the config files of the environment into the config, each one merged over the previous ones.
+// The objects, like the sections or the maps, are merged key by key, matching the keys ignoring case as the settings are,
+// while any other value, including the lists, like Retention.Policies, is replaced as a whole,
+// so the result only depends on the files and not on the values they replace.
+func loadLayers(cfg *config, configPath, env string) error {
+	files := Files(configPath, env)
+	layers := []layer{
+		{file: files[0], name: "configuration"},
+		{file: files[1], name: "environment configuration"},
+		{file: files[2], name: "local configuration", optional: true},
+	}
+
+	merged := make(map[string]interface{})
+	for _, l := range layers {
+		if l.optional {
+			if _, err := os.Stat(l.file); errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+		}
+
+		var values map[string]interface{}
+		if err := utils.LoadJSON(l.file, &values); err != nil {
+			return fmt.Errorf("error parsing %s, %s", l.name, err)
+		}
+		mergeObjects(merged, values)
+	}
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("error parsing configuration, %s", err)
+	}
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return fmt.Errorf("error parsing configuration, %s", err)
+	}
+	return nil
+}
+
+// mergeObjects merges the values of the overlay into the base object, merging the objects of both and replacing any other value
+func mergeObjects(base, overlay map[string]interface{}) {
+	for key, value := range overlay {
+		for k := range base {
+			if k != key && strings.EqualFold(k, key) {
+				base[key] = base[k]
+				delete(base, k)
+				break
+			}
+		}
+
+		baseObject, ok := base[key].(map[string]interface{})
+		overlayObject, isObject := value.(map[string]interface{})
+		if ok && isObject {
+			mergeObjects(baseObject, overlayObject)
+			continue
+		}
+		base[key] = value
+	}
+}
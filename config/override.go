@@ -0,0 +1,123 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EnvPrefix prefixes the environment variables overriding the settings of the config files, like API_LOG_LEVEL for Log.Level
+const EnvPrefix = "API_"
+
+// applyEnv overrides the settings of the config files with the environment variables named after their section and field in upper case,
+// like API_LOG_LEVEL for Log.Level or API_JWTSECRET for JWTSecret
+func applyEnv(cfg *config, lookup func(key string) (string, bool)) error {
+	v := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if isSection(field.Type) {
+			for j := 0; j < field.Type.NumField(); j++ {
+				key := EnvPrefix + strings.ToUpper(field.Name+"_"+field.Type.Field(j).Name)
+				if err := applyEnvValue(v.Field(i).Field(j), key, lookup); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := applyEnvValue(v.Field(i), EnvPrefix+strings.ToUpper(field.Name), lookup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyEnvValue(v reflect.Value, key string, lookup func(key string) (string, bool)) error {
+	raw, ok := lookup(key)
+	if !ok {
+		return nil
+	}
+	if err := setValue(v, raw); err != nil {
+		return fmt.Errorf("environment variable %s not valid: %w", key, err)
+	}
+	return nil
+}
+
+// applySets overrides the settings of the config files with the given Section.Field=value assignments, like Log.Level=debug,
+// matching the names ignoring case
+func applySets(cfg *config, sets []string) error {
+	for _, set := range sets {
+		path, raw, ok := strings.Cut(set, "=")
+		if !ok {
+			return fmt.Errorf("setting %q not valid, it must be like Section.Field=value", set)
+		}
+		if err := setPath(reflect.ValueOf(cfg).Elem(), path, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplySecrets overrides the settings with the secrets fetched from a secrets manager, keyed by the setting they set
+// like JWTSecret, DSN or Alerting.SMTPPassword, matching the names ignoring case
+func (c *Config) ApplySecrets(secrets map[string]string) error {
+	for path, raw := range secrets {
+		if err := setPath(reflect.ValueOf(c).Elem(), path, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setPath sets the setting of the given Section.Field path of the root struct from its raw value
+func setPath(root reflect.Value, path, raw string) error {
+	v := root
+	for _, name := range strings.Split(path, ".") {
+		if !isSection(v.Type()) {
+			return fmt.Errorf("setting %s not found", path)
+		}
+		v = v.FieldByNameFunc(func(field string) bool { return strings.EqualFold(field, name) })
+		if !v.IsValid() || !v.CanSet() {
+			return fmt.Errorf("setting %s not found", path)
+		}
+	}
+	if err := setValue(v, raw); err != nil {
+		return fmt.Errorf("setting %s not valid: %w", path, err)
+	}
+	return nil
+}
+
+// setValue sets a setting from its raw value: strings and durations as they are, lists of strings separated by commas,
+// and any other value, including the lists and maps, as in the config files, like 0.5, true or {"GetAll": "primary"}
+func setValue(v reflect.Value, raw string) error {
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+		return nil
+	case v.Type() == durationType:
+		b, _ := json.Marshal(raw)
+		raw = string(b)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(raw), "["):
+		values := reflect.MakeSlice(v.Type(), 0, 0)
+		for _, s := range strings.Split(raw, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = reflect.Append(values, reflect.ValueOf(s).Convert(v.Type().Elem()))
+			}
+		}
+		v.Set(values)
+		return nil
+	}
+
+	// the value is replaced instead of merged, like the maps would be
+	value := reflect.New(v.Type())
+	if err := json.Unmarshal([]byte(raw), value.Interface()); err != nil {
+		return err
+	}
+	v.Set(value.Elem())
+	return nil
+}
+
+// isSection returns whether a field of the config files is a section of settings, like Log, and not a setting itself
+func isSection(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != durationType
+}
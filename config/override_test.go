@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplySecrets_Ok checks that ApplySecrets overrides the settings of the secrets, including the ones of the flags like the DSN
+func TestApplySecrets_Ok(t *testing.T) {
+	// Arrange
+	cfg := Config{DSN: "flag-dsn"}
+	cfg.JWTSecret = "file-secret"
+
+	// Act
+	err := cfg.ApplySecrets(map[string]string{"DSN": "vault-dsn", "jwtsecret": "vault-secret", "Alerting.SMTPPassword": "vault-password"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "vault-dsn", cfg.DSN)
+	assert.Equal(t, "vault-secret", cfg.JWTSecret)
+	assert.Equal(t, "vault-password", cfg.Alerting.SMTPPassword)
+}
+
+// TestApplySecrets_UnknownSetting checks that ApplySecrets returns an error when a secret does not match any setting
+func TestApplySecrets_UnknownSetting(t *testing.T) {
+	// Arrange
+	cfg := Config{}
+	expectedError := "setting config not found"
+
+	// Act
+	err := cfg.ApplySecrets(map[string]string{"config": "{}"})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
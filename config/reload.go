@@ -0,0 +1,79 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// reloadable settings, applied while the API runs when the config is reloaded, the other ones being applied on restart
+var reloadable = map[string]bool{
+	"Log.Level":                      true,
+	"Log.RequestSampleRatio":         true,
+	"Log.RouteSampleRatios":          true,
+	"RateLimit.Requests":             true,
+	"RateLimit.Window":               true,
+	"Middleware.CORS.AllowedOrigins": true,
+}
+
+// Change of a setting between two configs, with its new value as in the config files, redacted when secret
+type Change struct {
+	Setting    string
+	Value      string
+	Reloadable bool
+}
+
+// Changes returns the settings changed from the old config to the new one, sorted by their Section.Field name
+func Changes(old, new Config) []Change {
+	changes := appendChanges(nil, "", reflect.ValueOf(old), reflect.ValueOf(new))
+	// the fields of the config files are shadowed by the ones of the flags with the same name, like Database
+	shadowed := make(map[string]bool)
+	for i := 0; i < reflect.TypeOf(old).NumField(); i++ {
+		shadowed[reflect.TypeOf(old).Field(i).Name] = true
+	}
+	for _, c := range appendChanges(nil, "", reflect.ValueOf(old.config), reflect.ValueOf(new.config)) {
+		if !shadowed[c.Setting] {
+			changes = append(changes, c)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Setting < changes[j].Setting })
+	return changes
+}
+
+// appendChanges appends the changed exported fields of two structs, prefixing their names with the given one
+func appendChanges(changes []Change, prefix string, old, new reflect.Value) []Change {
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := prefix + field.Name
+		if isSection(field.Type) {
+			changes = appendChanges(changes, name+".", old.Field(i), new.Field(i))
+			continue
+		}
+		if reflect.DeepEqual(old.Field(i).Interface(), new.Field(i).Interface()) {
+			continue
+		}
+		changes = append(changes, Change{
+			Setting:    name,
+			Value:      settingValue(new.Field(i), field.Tag.Get("secret") == "true"),
+			Reloadable: reloadable[name],
+		})
+	}
+	return changes
+}
+
+// settingValue returns a setting as in the config files, the strings as they are and any other value as JSON
+func settingValue(v reflect.Value, secret bool) string {
+	value := redactValue(v, secret)
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
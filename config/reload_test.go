@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChanges_Ok checks that Changes returns the changed settings sorted by name, with their new value redacted when secret,
+// telling the reloadable ones
+func TestChanges_Ok(t *testing.T) {
+	// Arrange
+	var old Config
+	old.Log.Level = "info"
+	old.Timeout = utils.Duration{Duration: 5 * time.Second}
+	old.Alerting.Run = true
+
+	new := old
+	new.DSN = "test-dsn"
+	new.Log.Level = "debug"
+	new.Log.RouteSampleRatios = map[string]float64{"GET /v1/users": 0.1}
+	new.RateLimit.Requests = 100
+	new.RateLimit.Window = utils.Duration{Duration: time.Minute}
+	new.Middleware.CORS.AllowedOrigins = []string{"https://app.example.com"}
+	new.Timeout = utils.Duration{Duration: 10 * time.Second}
+	new.config.Database = "postgres"
+
+	expectedChanges := []Change{
+		{Setting: "DSN", Value: redactedValue},
+		{Setting: "Log.Level", Value: "debug", Reloadable: true},
+		{Setting: "Log.RouteSampleRatios", Value: `{"GET /v1/users":0.1}`, Reloadable: true},
+		{Setting: "Middleware.CORS.AllowedOrigins", Value: `["https://app.example.com"]`, Reloadable: true},
+		{Setting: "RateLimit.Requests", Value: "100", Reloadable: true},
+		{Setting: "RateLimit.Window", Value: "1m0s", Reloadable: true},
+		{Setting: "Timeout", Value: "10s"},
+	}
+
+	// Act
+	changes := Changes(old, new)
+
+	// Assert
+	assert.Equal(t, expectedChanges, changes)
+}
+
+// TestChanges_Unchanged checks that Changes returns no change for equal configs
+func TestChanges_Unchanged(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.Log.RouteSampleRatios = map[string]float64{"GET /v1/users": 0.1}
+
+	// Act
+	changes := Changes(cfg, cfg)
+
+	// Assert
+	assert.Empty(t, changes)
+}
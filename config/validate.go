@@ -0,0 +1,728 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/scheduler"
+	"github.com/sergicanet9/go-hexagonal-api/app/middleware"
+	"github.com/sergicanet9/go-hexagonal-api/app/upgrade"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+)
+
+// versionPattern matches the dotted versions, like 4.2 or 6.0.5
+var versionPattern = regexp.MustCompile(`^\d+(\.\d+)*$`)
+
+// shardKeyUpdateVersion is the first MongoDB version updating a single document of a sharded collection, its shard key included,
+// without the shard key in the filter, as the users are updated by _id only
+const shardKeyUpdateVersion = "7.1"
+
+// ValidationError report of every setting of the config not being valid
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "settings not valid: " + strings.Join(e.Problems, " | ")
+}
+
+// Validate checks the fully resolved config before anything is started with it, returning a ValidationError reporting every problem found:
+// the required secrets set, except the Mongo DSN when the embedded Mongo is started instead, the URLs, addresses and schedules parsed, the durations and ratios in range, the names matching their choices,
+// and the ports of the API, the diagnostics and the gRPC server free to listen on, unless Port is 0, as for the commands not serving the API,
+// or shared with other processes, when reusing them or when upgraded from the process holding them.
+func (c Config) Validate() error {
+	var msgs []string
+
+	if c.Port != 0 {
+		checkFree := !c.Upgrade.ReusePort && !upgrade.Inherited()
+		msgs = append(msgs, validatePort("Port", c.Port, checkFree)...)
+		if c.Diagnostics.Enabled && c.Diagnostics.Port != 0 {
+			if c.Diagnostics.Port == c.Port {
+				msgs = append(msgs, "Diagnostics.Port must be different from Port")
+			} else {
+				msgs = append(msgs, validatePort("Diagnostics.Port", c.Diagnostics.Port, checkFree)...)
+			}
+		}
+		if c.GRPC.Enabled {
+			if c.GRPC.Port == c.Port || (c.Diagnostics.Enabled && c.GRPC.Port == c.Diagnostics.Port) {
+				msgs = append(msgs, "GRPC.Port must be different from Port and Diagnostics.Port")
+			} else {
+				msgs = append(msgs, validatePort("GRPC.Port", c.GRPC.Port, checkFree)...)
+			}
+		}
+	}
+
+	switch c.Database {
+	case "mongo":
+		if c.EmbeddedMongo.Enabled && c.DSN == "" {
+			if c.EmbeddedMongo.Image == "" {
+				msgs = append(msgs, "EmbeddedMongo.Image must be set")
+			}
+		} else {
+			msgs = append(msgs, validateURL("DSN", c.DSN, "mongodb", "mongodb+srv")...)
+		}
+	case "postgres":
+		// postgres also accepts the key=value connection strings
+		if strings.Contains(c.DSN, "://") || c.DSN == "" {
+			msgs = append(msgs, validateURL("DSN", c.DSN, "postgres", "postgresql")...)
+		}
+	default:
+		msgs = append(msgs, fmt.Sprintf("Database %q not valid, it must be mongo or postgres", c.Database))
+	}
+	if c.JWTSecret == "" {
+		msgs = append(msgs, "JWTSecret must be set")
+	}
+
+	msgs = append(msgs, validateDurations("", reflect.ValueOf(c.config))...)
+	if c.Timeout.Duration <= 0 {
+		msgs = append(msgs, "Timeout must be greater than 0")
+	}
+	if c.Shutdown.Timeout.Duration <= 0 {
+		msgs = append(msgs, "Shutdown.Timeout must be greater than 0")
+	}
+	msgs = append(msgs, validateInterval("EmbeddedMongo.Timeout", c.EmbeddedMongo.Enabled, c.EmbeddedMongo.Timeout)...)
+	msgs = append(msgs, validateInterval("Async.Interval", c.Async.Run, c.Async.Interval)...)
+	msgs = append(msgs, validateInterval("Archive.Interval", c.Archive.Run, c.Archive.Interval)...)
+	msgs = append(msgs, validateInterval("Alerting.Interval", c.Alerting.Run, c.Alerting.Interval)...)
+	msgs = append(msgs, validateInterval("Alerting.Window", c.Alerting.Run, c.Alerting.Window)...)
+	msgs = append(msgs, validateInterval("Leader.LeaseTTL", c.Leader.Enabled, c.Leader.LeaseTTL)...)
+	msgs = append(msgs, validateInterval("Lockout.Duration", c.Lockout.Enabled, c.Lockout.Duration)...)
+	msgs = append(msgs, validateInterval("Cache.TTL", c.Cache.Enabled, c.Cache.TTL)...)
+	msgs = append(msgs, validateInterval("RateLimit.Window", c.RateLimit.Enabled, c.RateLimit.Window)...)
+	msgs = append(msgs, validateInterval("Queue.PollInterval", c.Async.Run, c.Queue.PollInterval)...)
+	msgs = append(msgs, validateInterval("Queue.Timeout", c.Async.Run, c.Queue.Timeout)...)
+	msgs = append(msgs, validateInterval("Maintenance.Timeout", c.Async.Run, c.Maintenance.Timeout)...)
+	msgs = append(msgs, validateInterval("Upgrade.Timeout", c.Upgrade.Enabled, c.Upgrade.Timeout)...)
+	msgs = append(msgs, validateInterval("Preflight.Timeout", c.Preflight.Enabled, c.Preflight.Timeout)...)
+	msgs = append(msgs, validateInterval("KeyRotation.Overlap", c.Scheduler.Jobs["key_rotation"].Enabled, c.KeyRotation.Overlap)...)
+	if c.Queue.InitialBackoff.Duration > c.Queue.MaxBackoff.Duration {
+		msgs = append(msgs, "Queue.InitialBackoff cannot be greater than Queue.MaxBackoff")
+	}
+	if c.Startup.InitialBackoff.Duration > c.Startup.MaxBackoff.Duration {
+		msgs = append(msgs, "Startup.InitialBackoff cannot be greater than Startup.MaxBackoff")
+	}
+
+	msgs = append(msgs, validateLevel("Log.Level", c.Log.Level)...)
+	msgs = append(msgs, validateLevel("Log.RequestLevel", c.Log.RequestLevel)...)
+	msgs = append(msgs, validateRatio("Log.RequestSampleRatio", c.Log.RequestSampleRatio)...)
+	for route, ratio := range c.Log.RouteSampleRatios {
+		msgs = append(msgs, validateRatio(fmt.Sprintf("Log.RouteSampleRatios[%s]", route), ratio)...)
+	}
+	if c.Tracing.Enabled {
+		msgs = append(msgs, validateAddress("Tracing.Endpoint", c.Tracing.Endpoint)...)
+		msgs = append(msgs, validateRatio("Tracing.SampleRatio", c.Tracing.SampleRatio)...)
+		for route, ratio := range c.Tracing.RouteSampleRatios {
+			msgs = append(msgs, validateRatio(fmt.Sprintf("Tracing.RouteSampleRatios[%s]", route), ratio)...)
+		}
+	}
+	if c.Reporting.Enabled {
+		msgs = append(msgs, validateURL("Reporting.DSN", c.Reporting.DSN, "http", "https")...)
+		msgs = append(msgs, validateRatio("Reporting.SampleRate", c.Reporting.SampleRate)...)
+	}
+
+	if c.Preflight.Enabled {
+		if c.Preflight.MinMongoVersion != "" && !versionPattern.MatchString(c.Preflight.MinMongoVersion) {
+			msgs = append(msgs, fmt.Sprintf("Preflight.MinMongoVersion %q not valid, it must be a dotted version like 4.2", c.Preflight.MinMongoVersion))
+		}
+		if c.Preflight.RedisAddress != "" {
+			msgs = append(msgs, validateAddress("Preflight.RedisAddress", c.Preflight.RedisAddress)...)
+		}
+	}
+
+	if c.Sharding.Enabled && c.Database == "mongo" && (!c.Preflight.Enabled || olderVersion(c.Preflight.MinMongoVersion, shardKeyUpdateVersion)) {
+		msgs = append(msgs, fmt.Sprintf("Sharding.Enabled requires Preflight.Enabled with Preflight.MinMongoVersion %s or later, as the users are updated without their shard key", shardKeyUpdateVersion))
+	}
+
+	if c.Alerting.SlackWebhookURL != "" {
+		msgs = append(msgs, validateURL("Alerting.SlackWebhookURL", c.Alerting.SlackWebhookURL, "http", "https")...)
+	}
+	if c.Alerting.SMTPAddress != "" {
+		msgs = append(msgs, validateAddress("Alerting.SMTPAddress", c.Alerting.SMTPAddress)...)
+	}
+
+	for _, event := range c.Notifications.Events {
+		if event != "lockout" && event != "alert" && event != "migration" {
+			msgs = append(msgs, fmt.Sprintf("Notifications.Events %q not valid, it must be lockout, alert or migration", event))
+		}
+	}
+	if len(c.Notifications.Events) > 0 {
+		if c.Notifications.SlackWebhookURL == "" && len(c.Notifications.WebhookURLs) == 0 {
+			msgs = append(msgs, "Notifications.SlackWebhookURL or Notifications.WebhookURLs must be set")
+		}
+		msgs = append(msgs, validateInterval("Notifications.Timeout", true, c.Notifications.Timeout)...)
+	}
+	if c.Notifications.SlackWebhookURL != "" {
+		msgs = append(msgs, validateURL("Notifications.SlackWebhookURL", c.Notifications.SlackWebhookURL, "https")...)
+	}
+	for i, URL := range c.Notifications.WebhookURLs {
+		msgs = append(msgs, validateURL(fmt.Sprintf("Notifications.WebhookURLs[%d]", i), URL, "http", "https")...)
+	}
+
+	if c.Email.Provider != "" && c.Email.From == "" {
+		msgs = append(msgs, "Email.From must be set")
+	}
+	switch c.Email.Provider {
+	case "":
+	case "smtp":
+		msgs = append(msgs, validateAddress("Email.SMTPAddress", c.Email.SMTPAddress)...)
+	case "sendgrid":
+		if c.Email.SendGridAPIKey == "" {
+			msgs = append(msgs, "Email.SendGridAPIKey must be set")
+		}
+	case "ses":
+		if c.Email.SESRegion == "" {
+			msgs = append(msgs, "Email.SESRegion must be set")
+		}
+	default:
+		msgs = append(msgs, fmt.Sprintf("Email.Provider %q not valid, it must be smtp, sendgrid or ses", c.Email.Provider))
+	}
+	if c.Email.Provider != "" {
+		if c.MFA.MaxAttempts < 1 {
+			msgs = append(msgs, "MFA.MaxAttempts must be greater than 0")
+		}
+		msgs = append(msgs, validateInterval("MFA.CodeTTL", true, c.MFA.CodeTTL)...)
+	}
+
+	if c.Events.Broker != "" {
+		if c.Events.Topic == "" {
+			msgs = append(msgs, "Events.Topic must be set")
+		}
+		if c.Events.Format != "json" && c.Events.Format != "avro" {
+			msgs = append(msgs, fmt.Sprintf("Events.Format %q not valid, it must be json or avro", c.Events.Format))
+		}
+	}
+	switch c.Events.Broker {
+	case "":
+	case "kafka":
+		if len(c.Events.KafkaBrokers) == 0 {
+			msgs = append(msgs, "Events.KafkaBrokers must be set")
+		}
+		for i, broker := range c.Events.KafkaBrokers {
+			msgs = append(msgs, validateAddress(fmt.Sprintf("Events.KafkaBrokers[%d]", i), broker)...)
+		}
+	case "nats":
+		msgs = append(msgs, validateURL("Events.NATSURL", c.Events.NATSURL, "nats", "tls")...)
+	case "rabbitmq":
+		msgs = append(msgs, validateURL("Events.RabbitMQURL", c.Events.RabbitMQURL, "amqp", "amqps")...)
+	default:
+		msgs = append(msgs, fmt.Sprintf("Events.Broker %q not valid, it must be kafka, nats or rabbitmq", c.Events.Broker))
+	}
+
+	switch c.SMS.Provider {
+	case "":
+	case "twilio":
+		if c.SMS.From == "" {
+			msgs = append(msgs, "SMS.From must be set")
+		}
+		if c.SMS.TwilioAccountSID == "" || c.SMS.TwilioAuthToken == "" {
+			msgs = append(msgs, "SMS.TwilioAccountSID and SMS.TwilioAuthToken must be set")
+		}
+	default:
+		msgs = append(msgs, fmt.Sprintf("SMS.Provider %q not valid, it must be twilio", c.SMS.Provider))
+	}
+	if c.SMS.Provider != "" {
+		if c.SMS.StatusCallbackURL != "" {
+			msgs = append(msgs, validateURL("SMS.StatusCallbackURL", c.SMS.StatusCallbackURL, "https")...)
+		}
+		if c.SMS.MaxPerRecipient < 1 {
+			msgs = append(msgs, "SMS.MaxPerRecipient must be greater than 0")
+		}
+		if c.SMS.MaxPerClient < 1 {
+			msgs = append(msgs, "SMS.MaxPerClient must be greater than 0")
+		}
+		if c.SMS.MaxCheckAttempts < 1 {
+			msgs = append(msgs, "SMS.MaxCheckAttempts must be greater than 0")
+		}
+		msgs = append(msgs, validateInterval("SMS.Window", true, c.SMS.Window)...)
+		msgs = append(msgs, validateInterval("SMS.CodeTTL", true, c.SMS.CodeTTL)...)
+	} else if len(c.Alerting.SMSTo) > 0 {
+		msgs = append(msgs, "SMS.Provider must be set to send the alerts to Alerting.SMSTo")
+	}
+
+	domains := map[string]bool{}
+	for i, directory := range c.LDAP.Directories {
+		name := fmt.Sprintf("LDAP.Directories[%d]", i)
+		if len(directory.Domains) == 0 {
+			msgs = append(msgs, name+".Domains must be set")
+		}
+		for _, domain := range directory.Domains {
+			if domains[strings.ToLower(domain)] {
+				msgs = append(msgs, fmt.Sprintf("%s.Domains %q already set in another directory", name, domain))
+			}
+			domains[strings.ToLower(domain)] = true
+		}
+		msgs = append(msgs, validateURL(name+".URL", directory.URL, "ldap", "ldaps")...)
+		if directory.StartTLS && strings.HasPrefix(directory.URL, "ldaps:") {
+			msgs = append(msgs, name+".StartTLS cannot be set with an ldaps URL")
+		}
+		if directory.BaseDN == "" {
+			msgs = append(msgs, name+".BaseDN must be set")
+		}
+	}
+	if len(c.LDAP.Directories) > 0 {
+		msgs = append(msgs, validateInterval("LDAP.Timeout", true, c.LDAP.Timeout)...)
+	}
+
+	if c.Push.Enabled {
+		if c.Push.FCMCredentials == "" && c.Push.APNsPrivateKey == "" {
+			msgs = append(msgs, "Push.FCMCredentials or Push.APNsPrivateKey must be set")
+		}
+		if c.Push.APNsPrivateKey != "" && (c.Push.APNsKeyID == "" || c.Push.APNsTeamID == "" || c.Push.APNsTopic == "") {
+			msgs = append(msgs, "Push.APNsKeyID, Push.APNsTeamID and Push.APNsTopic must be set")
+		}
+		msgs = append(msgs, validateInterval("Push.KnownDeviceTTL", true, c.Push.KnownDeviceTTL)...)
+	}
+
+	switch c.Billing.Provider {
+	case "":
+		if len(c.Billing.SubscriptionRoutes) > 0 {
+			msgs = append(msgs, "Billing.Provider must be set to require a subscription in Billing.SubscriptionRoutes")
+		}
+	case "stripe":
+		if c.Billing.StripeSecretKey == "" {
+			msgs = append(msgs, "Billing.StripeSecretKey must be set")
+		}
+	default:
+		msgs = append(msgs, fmt.Sprintf("Billing.Provider %q not valid, it must be stripe", c.Billing.Provider))
+	}
+	if c.Billing.Provider != "" {
+		if c.Billing.WebhookSecret == "" {
+			msgs = append(msgs, "Billing.WebhookSecret must be set")
+		}
+		msgs = append(msgs, validateInterval("Billing.WebhookTolerance", true, c.Billing.WebhookTolerance)...)
+		for i, route := range c.Billing.SubscriptionRoutes {
+			if !strings.HasPrefix(route, "/") {
+				msgs = append(msgs, fmt.Sprintf("Billing.SubscriptionRoutes[%d] %q not valid, it must start with /", i, route))
+			}
+		}
+	}
+
+	if c.Analytics.Enabled {
+		if c.Analytics.WriteKey == "" {
+			msgs = append(msgs, "Analytics.WriteKey must be set")
+		}
+		msgs = append(msgs, validateURL("Analytics.Endpoint", c.Analytics.Endpoint, "https", "http")...)
+	}
+
+	if c.Encryption.Enabled {
+		if c.Encryption.DataKey == "" {
+			msgs = append(msgs, "Encryption.DataKey must be set")
+		}
+		switch c.Encryption.KMSProvider {
+		case "local":
+			if c.Encryption.LocalMasterKey == "" {
+				msgs = append(msgs, "Encryption.LocalMasterKey must be set")
+			}
+		case "azure":
+			msgs = append(msgs, validateURL("Encryption.AzureKeyURL", c.Encryption.AzureKeyURL, "https")...)
+		default:
+			msgs = append(msgs, fmt.Sprintf("Encryption.KMSProvider %q not valid, it must be local or azure", c.Encryption.KMSProvider))
+		}
+	}
+
+	if c.Search.Enabled {
+		if c.Database != "mongo" {
+			msgs = append(msgs, "Search.Enabled requires Database mongo, as the users are synced from its change stream")
+		}
+		msgs = append(msgs, validateURL("Search.URL", c.Search.URL, "http", "https")...)
+		if c.Search.Index == "" {
+			msgs = append(msgs, "Search.Index must be set")
+		}
+		msgs = append(msgs, validateInterval("Search.RetryInterval", true, c.Search.RetryInterval)...)
+	}
+
+	if c.OIDC.Enabled {
+		msgs = append(msgs, validateURL("OIDC.Issuer", c.OIDC.Issuer, "https", "http")...)
+		if u, err := url.Parse(c.OIDC.Issuer); err == nil && (u.RawQuery != "" || u.Fragment != "") {
+			msgs = append(msgs, "OIDC.Issuer cannot have a query nor a fragment")
+		}
+	}
+
+	switch c.GeoIP.Provider {
+	case "":
+	case "maxmind":
+		if c.GeoIP.DatabasePath == "" {
+			msgs = append(msgs, "GeoIP.DatabasePath must be set")
+		}
+	case "ipinfo":
+		if c.GeoIP.IPInfoToken == "" {
+			msgs = append(msgs, "GeoIP.IPInfoToken must be set")
+		}
+		msgs = append(msgs, validateInterval("GeoIP.CacheTTL", true, c.GeoIP.CacheTTL)...)
+		if c.GeoIP.CacheMaxEntries <= 0 {
+			msgs = append(msgs, "GeoIP.CacheMaxEntries must be greater than 0")
+		}
+	default:
+		msgs = append(msgs, fmt.Sprintf("GeoIP.Provider %q not valid, it must be maxmind or ipinfo", c.GeoIP.Provider))
+	}
+	if c.GeoIP.Provider != "" {
+		msgs = append(msgs, validateInterval("GeoIP.Timeout", true, c.GeoIP.Timeout)...)
+		msgs = append(msgs, validateInterval("GeoIP.KnownCountryTTL", true, c.GeoIP.KnownCountryTTL)...)
+	}
+
+	switch c.SIEM.Sink {
+	case "":
+	case "syslog":
+		if c.SIEM.SyslogNetwork != "udp" && c.SIEM.SyslogNetwork != "tcp" && c.SIEM.SyslogNetwork != "tls" {
+			msgs = append(msgs, fmt.Sprintf("SIEM.SyslogNetwork %q not valid, it must be udp, tcp or tls", c.SIEM.SyslogNetwork))
+		}
+		msgs = append(msgs, validateAddress("SIEM.SyslogAddress", c.SIEM.SyslogAddress)...)
+	case "http":
+		msgs = append(msgs, validateURL("SIEM.URL", c.SIEM.URL, "https", "http")...)
+	default:
+		msgs = append(msgs, fmt.Sprintf("SIEM.Sink %q not valid, it must be syslog or http", c.SIEM.Sink))
+	}
+	if c.SIEM.Sink != "" {
+		if c.SIEM.BufferSize <= 0 {
+			msgs = append(msgs, "SIEM.BufferSize must be greater than 0")
+		}
+		if c.SIEM.BatchSize <= 0 {
+			msgs = append(msgs, "SIEM.BatchSize must be greater than 0")
+		}
+		msgs = append(msgs, validateInterval("SIEM.FlushInterval", true, c.SIEM.FlushInterval)...)
+		msgs = append(msgs, validateInterval("SIEM.Timeout", true, c.SIEM.Timeout)...)
+	}
+
+	switch c.EmailCheck.Mode {
+	case "":
+	case "reject", "flag":
+		msgs = append(msgs, validateInterval("EmailCheck.Timeout", true, c.EmailCheck.Timeout)...)
+	default:
+		msgs = append(msgs, fmt.Sprintf("EmailCheck.Mode %q not valid, it must be reject or flag", c.EmailCheck.Mode))
+	}
+
+	switch c.Antivirus.Provider {
+	case "":
+	case "clamav":
+		if !strings.HasPrefix(c.Antivirus.ClamAVAddress, "/") {
+			msgs = append(msgs, validateAddress("Antivirus.ClamAVAddress", c.Antivirus.ClamAVAddress)...)
+		}
+		msgs = append(msgs, validateInterval("Antivirus.Timeout", true, c.Antivirus.Timeout)...)
+	default:
+		msgs = append(msgs, fmt.Sprintf("Antivirus.Provider %q not valid, it must be clamav", c.Antivirus.Provider))
+	}
+
+	switch c.DirectorySync.Source {
+	case "":
+		if c.Scheduler.Jobs["directory_sync"].Enabled {
+			msgs = append(msgs, "DirectorySync.Source must be set to run the directory_sync scheduled job")
+		}
+	case "sftp":
+		msgs = append(msgs, validateAddress("DirectorySync.SFTPAddress", c.DirectorySync.SFTPAddress)...)
+		if c.DirectorySync.SFTPUsername == "" {
+			msgs = append(msgs, "DirectorySync.SFTPUsername must be set")
+		}
+		if c.DirectorySync.SFTPPassword == "" && c.DirectorySync.SFTPPrivateKey == "" {
+			msgs = append(msgs, "DirectorySync.SFTPPassword or DirectorySync.SFTPPrivateKey must be set")
+		}
+		if c.DirectorySync.SFTPHostKey == "" {
+			msgs = append(msgs, "DirectorySync.SFTPHostKey must be set")
+		}
+		if c.DirectorySync.SFTPPath == "" {
+			msgs = append(msgs, "DirectorySync.SFTPPath must be set")
+		}
+	case "http":
+		msgs = append(msgs, validateURL("DirectorySync.HTTPURL", c.DirectorySync.HTTPURL, "https", "http")...)
+	case "ldap":
+		if len(c.LDAP.Directories) == 0 {
+			msgs = append(msgs, "LDAP.Directories must be set to sync the users of the directories")
+		}
+	default:
+		msgs = append(msgs, fmt.Sprintf("DirectorySync.Source %q not valid, it must be sftp, http or ldap", c.DirectorySync.Source))
+	}
+	if c.DirectorySync.Source != "" {
+		if len(c.DirectorySync.Domains) == 0 && c.DirectorySync.Source != "ldap" {
+			msgs = append(msgs, "DirectorySync.Domains must be set")
+		}
+		if c.DirectorySync.MaxDeactivations < 0 {
+			msgs = append(msgs, "DirectorySync.MaxDeactivations cannot be negative")
+		}
+		msgs = append(msgs, validateInterval("DirectorySync.Timeout", true, c.DirectorySync.Timeout)...)
+	}
+
+	switch c.Secrets.Provider {
+	case "":
+	case "vault":
+		msgs = append(msgs, validateURL("Secrets.VaultAddress", c.Secrets.VaultAddress, "http", "https")...)
+		if c.Secrets.VaultToken == "" {
+			msgs = append(msgs, "Secrets.VaultToken must be set")
+		}
+	case "aws-secretsmanager", "aws-ssm":
+		if c.Secrets.AWSRegion == "" {
+			msgs = append(msgs, "Secrets.AWSRegion must be set")
+		}
+	default:
+		msgs = append(msgs, fmt.Sprintf("Secrets.Provider %q not valid, it must be vault, aws-secretsmanager or aws-ssm", c.Secrets.Provider))
+	}
+
+	switch c.Storage.Provider {
+	case "":
+	case "s3":
+		if c.Storage.S3Bucket == "" {
+			msgs = append(msgs, "Storage.S3Bucket must be set")
+		}
+		if c.Storage.S3Endpoint != "" {
+			msgs = append(msgs, validateURL("Storage.S3Endpoint", c.Storage.S3Endpoint, "http", "https")...)
+		} else if c.Storage.S3Region == "" {
+			msgs = append(msgs, "Storage.S3Region or Storage.S3Endpoint must be set")
+		}
+		if (c.Storage.S3AccessKeyID == "") != (c.Storage.S3SecretAccessKey == "") {
+			msgs = append(msgs, "Storage.S3AccessKeyID and Storage.S3SecretAccessKey must be set together")
+		}
+		msgs = append(msgs, validateInterval("Storage.PresignExpiry", true, c.Storage.PresignExpiry)...)
+	default:
+		msgs = append(msgs, fmt.Sprintf("Storage.Provider %q not valid, it must be s3", c.Storage.Provider))
+	}
+
+	if c.Cache.Enabled && c.Cache.MaxEntries < 1 {
+		msgs = append(msgs, "Cache.MaxEntries must be greater than 0")
+	}
+	if c.Cache.Enabled && c.Cache.RedisAddress != "" {
+		msgs = append(msgs, validateAddress("Cache.RedisAddress", c.Cache.RedisAddress)...)
+		if c.Cache.Channel == "" {
+			msgs = append(msgs, "Cache.Channel must be set")
+		}
+	}
+	if c.Lockout.Enabled && c.Lockout.MaxFailures < 1 {
+		msgs = append(msgs, "Lockout.MaxFailures must be greater than 0")
+	}
+	if c.RateLimit.Enabled && c.RateLimit.Requests < 1 {
+		msgs = append(msgs, "RateLimit.Requests must be greater than 0")
+	}
+	if c.Async.Run && c.Queue.Workers < 1 {
+		msgs = append(msgs, "Queue.Workers must be greater than 0")
+	}
+	if c.Queue.MaxAttempts < 1 {
+		msgs = append(msgs, "Queue.MaxAttempts must be greater than 0")
+	}
+	if c.Hashing.Workers < 0 {
+		msgs = append(msgs, "Hashing.Workers cannot be negative")
+	}
+	names := make([]string, 0, len(c.Scheduler.Jobs))
+	for name := range c.Scheduler.Jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		schedule := c.Scheduler.Jobs[name].Schedule
+		if _, err := scheduler.ParseSchedule(schedule); err != nil {
+			msgs = append(msgs, fmt.Sprintf("Scheduler.Jobs[%s].Schedule %q not valid: %s", name, schedule, err))
+		}
+	}
+	msgs = append(msgs, validateMiddleware(c.Middleware)...)
+	msgs = append(msgs, validateRepository(c.Repository)...)
+	for i, p := range c.Retention.Policies {
+		if p.Action != "purge" && p.Action != "anonymize" {
+			msgs = append(msgs, fmt.Sprintf("Retention.Policies[%d].Action %q not valid, it must be purge or anonymize", i, p.Action))
+		}
+		if c.Scheduler.Jobs["retention"].Enabled && p.MaxAge.Duration <= 0 {
+			msgs = append(msgs, fmt.Sprintf("Retention.Policies[%d].MaxAge must be greater than 0", i))
+		}
+	}
+
+	if len(msgs) > 0 {
+		return &ValidationError{Problems: msgs}
+	}
+	return nil
+}
+
+// validateMiddleware checks that the chains only name middlewares of the API, once each, and that the path prefixes of the groups are paths,
+// the CORS and Compress settings being checked only when any chain runs them
+func validateMiddleware(m Middleware) []string {
+	msgs := validateChain("Middleware.Chain", m.Chain)
+	used := map[string]bool{}
+	for _, name := range m.Chain {
+		used[name] = true
+	}
+	prefixes := make([]string, 0, len(m.Groups))
+	for prefix := range m.Groups {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(prefix, "/") {
+			msgs = append(msgs, fmt.Sprintf("Middleware.Groups[%s] not valid, the path prefix must start with /", prefix))
+		}
+		msgs = append(msgs, validateChain(fmt.Sprintf("Middleware.Groups[%s]", prefix), m.Groups[prefix])...)
+		for _, name := range m.Groups[prefix] {
+			used[name] = true
+		}
+	}
+
+	if used[middleware.CORS] {
+		if len(m.CORS.AllowedOrigins) == 0 {
+			msgs = append(msgs, "Middleware.CORS.AllowedOrigins must be set")
+		}
+		if len(m.CORS.AllowedMethods) == 0 {
+			msgs = append(msgs, "Middleware.CORS.AllowedMethods must be set")
+		}
+	}
+	if used[middleware.Compress] && (m.Compress.Level < 1 || m.Compress.Level > 9) {
+		msgs = append(msgs, fmt.Sprintf("Middleware.Compress.Level %d not valid, it must be between 1 and 9", m.Compress.Level))
+	}
+	return msgs
+}
+
+// validateRepository checks that the operations of the timeouts are named after their collection and operation
+func validateRepository(r Repository) []string {
+	var msgs []string
+	operations := make([]string, 0, len(r.Timeouts))
+	for operation := range r.Timeouts {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+	for _, operation := range operations {
+		collection, name, ok := strings.Cut(operation, ".")
+		if !ok || collection == "" || name == "" {
+			msgs = append(msgs, fmt.Sprintf("Repository.Timeouts[%s] not valid, the operation must be named like collection.Operation", operation))
+		}
+	}
+	return msgs
+}
+
+// validateChain checks that the chain only names middlewares of the API, once each
+func validateChain(name string, chain []string) []string {
+	var msgs []string
+	seen := map[string]bool{}
+	for _, m := range chain {
+		known := false
+		for _, n := range middleware.Names {
+			known = known || n == m
+		}
+		switch {
+		case !known:
+			msgs = append(msgs, fmt.Sprintf("%s middleware %q not valid, it must be one of %s", name, m, strings.Join(middleware.Names, ", ")))
+		case seen[m]:
+			msgs = append(msgs, fmt.Sprintf("%s middleware %q repeated", name, m))
+		}
+		seen[m] = true
+	}
+	return msgs
+}
+
+// validatePort checks that the port is in range and, when checkFree is set, free to listen on
+func validatePort(name string, port int, checkFree bool) []string {
+	if port < 1 || port > 65535 {
+		return []string{fmt.Sprintf("%s %d not valid, it must be between 1 and 65535", name, port)}
+	}
+	if !checkFree {
+		return nil
+	}
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return []string{fmt.Sprintf("%s %d not free: %s", name, port, err)}
+	}
+	l.Close()
+	return nil
+}
+
+// validateURL checks that the URL is set, absolute and with one of the given schemes, without revealing it, as it may hold credentials
+func validateURL(name, value string, schemes ...string) []string {
+	if value == "" {
+		return []string{fmt.Sprintf("%s must be set", name)}
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return []string{fmt.Sprintf("%s not valid, it must be an absolute URL", name)}
+	}
+	for _, s := range schemes {
+		if u.Scheme == s {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("%s scheme %q not valid, it must be %s", name, u.Scheme, strings.Join(schemes, " or "))}
+}
+
+// validateAddress checks that the address is a host and port, like localhost:4318
+func validateAddress(name, value string) []string {
+	if _, _, err := net.SplitHostPort(value); err != nil {
+		return []string{fmt.Sprintf("%s %q not valid, it must be a host and port", name, value)}
+	}
+	return nil
+}
+
+// validateInterval checks that the interval of an enabled periodic process is greater than 0
+func validateInterval(name string, enabled bool, interval utils.Duration) []string {
+	if enabled && interval.Duration <= 0 {
+		return []string{fmt.Sprintf("%s must be greater than 0", name)}
+	}
+	return nil
+}
+
+// validateLevel checks that the log level is empty or a level name, like info or debug
+func validateLevel(name, level string) []string {
+	if level == "" {
+		return nil
+	}
+	if _, err := zerolog.ParseLevel(level); err != nil {
+		return []string{fmt.Sprintf("%s %q not valid", name, level)}
+	}
+	return nil
+}
+
+// validateRatio checks that the ratio is between 0 and 1
+func validateRatio(name string, ratio float64) []string {
+	if ratio < 0 || ratio > 1 {
+		return []string{fmt.Sprintf("%s %v not valid, it must be between 0 and 1", name, ratio)}
+	}
+	return nil
+}
+
+// validateDurations checks that none of the durations of the struct, including the ones of its sections, lists and maps, is negative
+func validateDurations(prefix string, v reflect.Value) []string {
+	var msgs []string
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := prefix + field.Name
+		f := v.Field(i)
+		switch {
+		case f.Type() == durationType:
+			if f.Interface().(utils.Duration).Duration < 0 {
+				msgs = append(msgs, fmt.Sprintf("%s cannot be negative", name))
+			}
+		case f.Kind() == reflect.Struct:
+			msgs = append(msgs, validateDurations(name+".", f)...)
+		case f.Kind() == reflect.Map && f.Type().Elem() == durationType:
+			keys := f.MapKeys()
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+			for _, k := range keys {
+				if f.MapIndex(k).Interface().(utils.Duration).Duration < 0 {
+					msgs = append(msgs, fmt.Sprintf("%s[%s] cannot be negative", name, k))
+				}
+			}
+		case f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.Struct:
+			for j := 0; j < f.Len(); j++ {
+				msgs = append(msgs, validateDurations(fmt.Sprintf("%s[%d].", name, j), f.Index(j))...)
+			}
+		}
+	}
+	return msgs
+}
+
+// olderVersion reports whether the dotted version is older than min, a missing number counting as 0
+func olderVersion(version, min string) bool {
+	parts, minParts := strings.Split(version, "."), strings.Split(min, ".")
+	for i := 0; i < len(parts) || i < len(minParts); i++ {
+		var got, want int
+		if i < len(parts) {
+			got, _ = strconv.Atoi(parts[i])
+		}
+		if i < len(minParts) {
+			want, _ = strconv.Atoi(minParts[i])
+		}
+		if got != want {
+			return got < want
+		}
+	}
+	return false
+}
@@ -0,0 +1,435 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// freePort returns a port free to listen on
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// TestValidate_ConfigFiles checks that Validate accepts the config files of every environment
+func TestValidate_ConfigFiles(t *testing.T) {
+	// Arrange
+	_, filePath, _, _ := runtime.Caller(0)
+	for _, env := range []string{"local", "dev"} {
+		cfg, err := ReadConfig("", env, freePort(t), "mongo", "mongodb://localhost:27017/test", path.Dir(filePath), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		err = cfg.Validate()
+
+		// Assert
+		assert.Nil(t, err, env)
+	}
+}
+
+// TestValidate_Problems checks that Validate reports every setting not being valid at once
+func TestValidate_Problems(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.Port = 70000
+	cfg.GRPC.Enabled = true
+	cfg.Database = "postgres"
+	cfg.DSN = "mysql://localhost"
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Shutdown.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Queue.MaxAttempts = 1
+	cfg.Async.Run = true
+	cfg.Capture.TTL = utils.Duration{Duration: -time.Hour}
+	cfg.Cache.Enabled = true
+	cfg.Cache.RedisAddress = "localhost"
+	cfg.Log.Level = "verbose"
+	cfg.Log.RequestSampleRatio = 2
+	cfg.Alerting.SlackWebhookURL = "hooks.slack.com/services/test"
+	cfg.Notifications.Events = []string{"lockout", "restart"}
+	cfg.Notifications.WebhookURLs = []string{"ftp://localhost/hook"}
+	cfg.Email.Provider = "sendgrid"
+	cfg.Events.Broker = "rabbitmq"
+	cfg.Events.RabbitMQURL = "http://localhost:5672"
+	cfg.SMS.Provider = "twilio"
+	cfg.LDAP.Directories = []LDAPDirectory{{Domains: []string{"example.com"}, URL: "ldaps://ldap.example.com", StartTLS: true}, {Domains: []string{"Example.com"}, URL: "ldap.example.com"}}
+	cfg.Push.Enabled = true
+	cfg.Push.APNsPrivateKey = "test-key"
+	cfg.Billing.Provider = "stripe"
+	cfg.Billing.StripeSecretKey = "sk_test"
+	cfg.Billing.SubscriptionRoutes = []string{"v1/reports"}
+	cfg.Analytics.Enabled = true
+	cfg.Analytics.Endpoint = "api.segment.io"
+	cfg.Search.Enabled = true
+	cfg.Search.URL = "ftp://localhost:9200"
+	cfg.OIDC.Enabled = true
+	cfg.OIDC.Issuer = "https://api.example.com?tenant=test"
+	cfg.GeoIP.Provider = "ipinfo"
+	cfg.GeoIP.CacheMaxEntries = 100
+	cfg.SIEM.Sink = "syslog"
+	cfg.SIEM.SyslogNetwork = "udp"
+	cfg.SIEM.SyslogAddress = "localhost:514"
+	cfg.SIEM.BatchSize = 100
+	cfg.EmailCheck.Mode = "warn"
+	cfg.Antivirus.Provider = "clamav"
+	cfg.Antivirus.ClamAVAddress = "localhost"
+	cfg.DirectorySync.Source = "sftp"
+	cfg.DirectorySync.SFTPAddress = "sftp.example.com:22"
+	cfg.DirectorySync.SFTPUsername = "sync"
+	cfg.DirectorySync.SFTPPassword = "test-password"
+	cfg.DirectorySync.SFTPPath = "/exports/users.csv"
+	cfg.DirectorySync.MaxDeactivations = -1
+	cfg.Secrets.Provider = "aws-ssm"
+	cfg.Storage.Provider = "s3"
+	cfg.Storage.S3AccessKeyID = "test-key"
+	cfg.Retention.Policies = []RetentionPolicy{{Collection: "jobs", Action: "delete"}}
+
+	expectedProblems := []string{
+		"Port 70000 not valid, it must be between 1 and 65535",
+		"GRPC.Port 0 not valid, it must be between 1 and 65535",
+		`DSN scheme "mysql" not valid, it must be postgres or postgresql`,
+		"JWTSecret must be set",
+		"Capture.TTL cannot be negative",
+		"Async.Interval must be greater than 0",
+		"Cache.TTL must be greater than 0",
+		"Queue.PollInterval must be greater than 0",
+		"Queue.Timeout must be greater than 0",
+		"Maintenance.Timeout must be greater than 0",
+		`Log.Level "verbose" not valid`,
+		"Log.RequestSampleRatio 2 not valid, it must be between 0 and 1",
+		"Alerting.SlackWebhookURL not valid, it must be an absolute URL",
+		`Notifications.Events "restart" not valid, it must be lockout, alert or migration`,
+		"Notifications.Timeout must be greater than 0",
+		`Notifications.WebhookURLs[0] scheme "ftp" not valid, it must be http or https`,
+		"Email.From must be set",
+		"Email.SendGridAPIKey must be set",
+		"MFA.MaxAttempts must be greater than 0",
+		"MFA.CodeTTL must be greater than 0",
+		"Events.Topic must be set",
+		`Events.Format "" not valid, it must be json or avro`,
+		`Events.RabbitMQURL scheme "http" not valid, it must be amqp or amqps`,
+		"SMS.From must be set",
+		"SMS.TwilioAccountSID and SMS.TwilioAuthToken must be set",
+		"SMS.MaxPerRecipient must be greater than 0",
+		"SMS.MaxPerClient must be greater than 0",
+		"SMS.MaxCheckAttempts must be greater than 0",
+		"SMS.Window must be greater than 0",
+		"SMS.CodeTTL must be greater than 0",
+		"LDAP.Directories[0].StartTLS cannot be set with an ldaps URL",
+		"LDAP.Directories[0].BaseDN must be set",
+		`LDAP.Directories[1].Domains "Example.com" already set in another directory`,
+		"LDAP.Directories[1].URL not valid, it must be an absolute URL",
+		"LDAP.Directories[1].BaseDN must be set",
+		"LDAP.Timeout must be greater than 0",
+		"Push.APNsKeyID, Push.APNsTeamID and Push.APNsTopic must be set",
+		"Push.KnownDeviceTTL must be greater than 0",
+		"Billing.WebhookSecret must be set",
+		"Billing.WebhookTolerance must be greater than 0",
+		`Billing.SubscriptionRoutes[0] "v1/reports" not valid, it must start with /`,
+		"Analytics.WriteKey must be set",
+		"Analytics.Endpoint not valid, it must be an absolute URL",
+		"Search.Enabled requires Database mongo, as the users are synced from its change stream",
+		`Search.URL scheme "ftp" not valid, it must be http or https`,
+		"Search.Index must be set",
+		"Search.RetryInterval must be greater than 0",
+		"OIDC.Issuer cannot have a query nor a fragment",
+		"GeoIP.IPInfoToken must be set",
+		"GeoIP.CacheTTL must be greater than 0",
+		"GeoIP.Timeout must be greater than 0",
+		"GeoIP.KnownCountryTTL must be greater than 0",
+		"SIEM.BufferSize must be greater than 0",
+		"SIEM.FlushInterval must be greater than 0",
+		"SIEM.Timeout must be greater than 0",
+		`EmailCheck.Mode "warn" not valid, it must be reject or flag`,
+		`Antivirus.ClamAVAddress "localhost" not valid, it must be a host and port`,
+		"Antivirus.Timeout must be greater than 0",
+		"DirectorySync.SFTPHostKey must be set",
+		"DirectorySync.Domains must be set",
+		"DirectorySync.MaxDeactivations cannot be negative",
+		"DirectorySync.Timeout must be greater than 0",
+		"Secrets.AWSRegion must be set",
+		"Storage.S3Bucket must be set",
+		"Storage.S3Region or Storage.S3Endpoint must be set",
+		"Storage.S3AccessKeyID and Storage.S3SecretAccessKey must be set together",
+		"Storage.PresignExpiry must be greater than 0",
+		"Cache.MaxEntries must be greater than 0",
+		`Cache.RedisAddress "localhost" not valid, it must be a host and port`,
+		"Cache.Channel must be set",
+		"Queue.Workers must be greater than 0",
+		`Retention.Policies[0].Action "delete" not valid, it must be purge or anonymize`,
+	}
+
+	// Act
+	err := cfg.Validate()
+
+	// Assert
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, expectedProblems, validationErr.Problems)
+	assert.Contains(t, err.Error(), "settings not valid: Port 70000 not valid")
+}
+
+// TestValidate_PortInUse checks that Validate reports the port of the API when it is not free to listen on
+func TestValidate_PortInUse(t *testing.T) {
+	// Arrange
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var cfg Config
+	cfg.Port = l.Addr().(*net.TCPAddr).Port
+	cfg.Database = "postgres"
+	cfg.DSN = "host=localhost user=test"
+	cfg.JWTSecret = "test-secret"
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Shutdown.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Queue.MaxAttempts = 1
+
+	// Act
+	err = cfg.Validate()
+
+	// Assert
+	assert.ErrorContains(t, err, fmt.Sprintf("settings not valid: Port %d not free", cfg.Port))
+}
+
+// TestValidate_ReusePort checks that Validate does not report the port of the API in use when it is reused, as when restarting with another process
+func TestValidate_ReusePort(t *testing.T) {
+	// Arrange
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var cfg Config
+	cfg.Port = l.Addr().(*net.TCPAddr).Port
+	cfg.Database = "postgres"
+	cfg.DSN = "host=localhost user=test"
+	cfg.JWTSecret = "test-secret"
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Shutdown.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Queue.MaxAttempts = 1
+	cfg.Upgrade.ReusePort = true
+
+	// Act
+	err = cfg.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidate_NoPort checks that Validate does not check the ports when Port is 0, as for the commands not serving the API
+func TestValidate_NoPort(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.Database = "postgres"
+	cfg.DSN = "host=localhost user=test"
+	cfg.JWTSecret = "test-secret"
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Shutdown.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Queue.MaxAttempts = 1
+	cfg.Diagnostics.Enabled = true
+	cfg.Diagnostics.Port = 70000
+
+	// Act
+	err := cfg.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidate_EmbeddedMongo checks that Validate does not require the Mongo DSN when the embedded Mongo is started instead
+func TestValidate_EmbeddedMongo(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.Database = "mongo"
+	cfg.JWTSecret = "test-secret"
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Shutdown.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Queue.MaxAttempts = 1
+	cfg.EmbeddedMongo.Enabled = true
+	cfg.EmbeddedMongo.Image = "mongo:6.0"
+	cfg.EmbeddedMongo.Timeout = utils.Duration{Duration: time.Minute}
+
+	// Act
+	err := cfg.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidate_Schedules checks that Validate reports the schedules of the jobs not valid, requiring the max ages of the retention policies when the retention job is enabled
+func TestValidate_Schedules(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.Database = "postgres"
+	cfg.DSN = "host=localhost user=test"
+	cfg.JWTSecret = "test-secret"
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Shutdown.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Queue.MaxAttempts = 1
+	cfg.Scheduler.Jobs = map[string]ScheduledJob{
+		"user_stats": {Enabled: true, Schedule: "*/5 * * *"},
+		"retention":  {Enabled: true, Schedule: "0 3 * * 8"},
+	}
+	cfg.Retention.Policies = []RetentionPolicy{{Collection: "jobs", Action: "purge"}}
+
+	expectedProblems := []string{
+		`Scheduler.Jobs[retention].Schedule "0 3 * * 8" not valid: day of week "8" not valid, it must be between 0 and 6`,
+		`Scheduler.Jobs[user_stats].Schedule "*/5 * * *" not valid: it must have 5 fields or be a descriptor`,
+		"Retention.Policies[0].MaxAge must be greater than 0",
+	}
+
+	// Act
+	err := cfg.Validate()
+
+	// Assert
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, expectedProblems, validationErr.Problems)
+}
+
+// TestValidate_Preflight checks that Validate reports the preflight settings not being valid when the preflight checks are enabled
+func TestValidate_Preflight(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.Database = "postgres"
+	cfg.DSN = "host=localhost user=test"
+	cfg.JWTSecret = "test-secret"
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Shutdown.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Queue.MaxAttempts = 1
+	cfg.Preflight.Enabled = true
+	cfg.Preflight.MinMongoVersion = "v4"
+	cfg.Preflight.RedisAddress = "localhost"
+
+	expectedProblems := []string{
+		"Preflight.Timeout must be greater than 0",
+		`Preflight.MinMongoVersion "v4" not valid, it must be a dotted version like 4.2`,
+		`Preflight.RedisAddress "localhost" not valid, it must be a host and port`,
+	}
+
+	// Act
+	err := cfg.Validate()
+
+	// Assert
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, expectedProblems, validationErr.Problems)
+}
+
+// TestValidate_Sharding checks that Validate requires the preflight check of a MongoDB version updating the shard key without it in the filter
+// when the users are sharded
+func TestValidate_Sharding(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.Database = "mongo"
+	cfg.DSN = "mongodb://localhost:27017"
+	cfg.JWTSecret = "test-secret"
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Shutdown.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Queue.MaxAttempts = 1
+	cfg.Sharding.Enabled = true
+	cfg.Preflight.Enabled = true
+	cfg.Preflight.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Preflight.MinMongoVersion = "7.0.12"
+
+	expectedProblems := []string{
+		"Sharding.Enabled requires Preflight.Enabled with Preflight.MinMongoVersion 7.1 or later, as the users are updated without their shard key",
+	}
+
+	// Act
+	olderErr := cfg.Validate()
+	cfg.Preflight.MinMongoVersion = "7.1"
+	err := cfg.Validate()
+
+	// Assert
+	var validationErr *ValidationError
+	assert.True(t, errors.As(olderErr, &validationErr))
+	assert.Equal(t, expectedProblems, validationErr.Problems)
+	assert.Nil(t, err)
+}
+
+// TestValidate_Middleware checks that Validate reports the middlewares not known or repeated, the groups not being paths,
+// and the settings of the CORS and Compress middlewares when chained
+func TestValidate_Middleware(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.Database = "postgres"
+	cfg.DSN = "host=localhost user=test"
+	cfg.JWTSecret = "test-secret"
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Shutdown.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Queue.MaxAttempts = 1
+	cfg.Middleware.Chain = []string{"logging", "cors", "logging"}
+	cfg.Middleware.Groups = map[string][]string{
+		"v1/files":  {"compress"},
+		"/v1/users": {"gzip"},
+	}
+
+	expectedProblems := []string{
+		`Middleware.Chain middleware "logging" repeated`,
+		"Middleware.Groups[/v1/users] middleware \"gzip\" not valid, it must be one of tracing, logging, accesslog, ratelimit, subscription, capture, recover, cors, compress, auth",
+		"Middleware.Groups[v1/files] not valid, the path prefix must start with /",
+		"Middleware.CORS.AllowedOrigins must be set",
+		"Middleware.CORS.AllowedMethods must be set",
+		"Middleware.Compress.Level 0 not valid, it must be between 1 and 9",
+	}
+
+	// Act
+	err := cfg.Validate()
+
+	// Assert
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, expectedProblems, validationErr.Problems)
+}
+
+// TestValidate_Repository checks that Validate reports the timeouts being negative or of operations not named like collection.Operation
+func TestValidate_Repository(t *testing.T) {
+	// Arrange
+	var cfg Config
+	cfg.Database = "postgres"
+	cfg.DSN = "host=localhost user=test"
+	cfg.JWTSecret = "test-secret"
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Shutdown.Timeout = utils.Duration{Duration: time.Second}
+	cfg.Queue.MaxAttempts = 1
+	cfg.Repository.Timeout = utils.Duration{Duration: -time.Second}
+	cfg.Repository.Timeouts = map[string]utils.Duration{
+		"Dump":          {Duration: time.Minute},
+		"users.Archive": {Duration: -time.Minute},
+		"users.Dump":    {Duration: 0},
+	}
+
+	expectedProblems := []string{
+		"Repository.Timeout cannot be negative",
+		"Repository.Timeouts[users.Archive] cannot be negative",
+		"Repository.Timeouts[Dump] not valid, the operation must be named like collection.Operation",
+	}
+
+	// Act
+	err := cfg.Validate()
+
+	// Assert
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, expectedProblems, validationErr.Problems)
+}
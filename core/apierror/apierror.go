@@ -0,0 +1,108 @@
+// Package apierror defines the errors the service layer fails with, by kind, so the adapters serving the API map them to their status
+// in one place instead of matching their messages. The errors of every kind also match the wrappers error the API mapped the kind to before,
+// so the callers still checking those keep working.
+package apierror
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// The kinds of the errors, matched with errors.Is by the errors of the kind
+var (
+	// ErrNotFound the resource requested does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict the request conflicts with the state of a resource, like an email already in use
+	ErrConflict = errors.New("conflict")
+	// ErrUnauthorized the request is not authenticated, or not allowed to the credentials
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrValidation the request is not valid, the errors of the kind being a *ValidationError
+	ErrValidation = errors.New("validation failed")
+)
+
+// kindError an error of a kind, matching its sentinel and the wrappers error of the kind
+type kindError struct {
+	kind    error
+	wrapper error
+	err     error
+}
+
+func (e kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e kindError) Unwrap() error {
+	return e.err
+}
+
+func (e kindError) Is(target error) bool {
+	return target == e.kind || target == e.wrapper
+}
+
+// NotFound wraps the error as an ErrNotFound, returning nil for a nil error
+func NotFound(err error) error {
+	return wrap(ErrNotFound, wrappers.NonExistentErr, err)
+}
+
+// Conflict wraps the error as an ErrConflict, returning nil for a nil error
+func Conflict(err error) error {
+	return wrap(ErrConflict, wrappers.ValidationErr, err)
+}
+
+// Unauthorized wraps the error as an ErrUnauthorized, returning nil for a nil error
+func Unauthorized(err error) error {
+	return wrap(ErrUnauthorized, wrappers.UnauthorizedErr, err)
+}
+
+func wrap(kind, wrapper, err error) error {
+	if err == nil {
+		return nil
+	}
+	return kindError{kind: kind, wrapper: wrapper, err: err}
+}
+
+// FieldError the reason a field of a request is not valid
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError the fields of a request not being valid, in the order they were checked
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Add adds the reason the field is not valid
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// Err returns the error when any field was added, or nil otherwise
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error returns the reasons of the fields, separated by pipes
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Message
+	}
+	return strings.Join(msgs, " | ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation || target == wrappers.ValidationErr
+}
+
+// Validation returns a validation error of a single field
+func Validation(field, message string) error {
+	var e ValidationError
+	e.Add(field, message)
+	return &e
+}
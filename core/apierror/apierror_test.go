@@ -0,0 +1,77 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNotFound_Kind checks that NotFound returns an error matching ErrNotFound and the non existent error of wrappers, with the message of the error
+func TestNotFound_Kind(t *testing.T) {
+	// Arrange
+	cause := fmt.Errorf("ID test not found")
+
+	// Act
+	err := NotFound(cause)
+
+	// Assert
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.False(t, errors.Is(err, ErrConflict))
+	assert.True(t, errors.Is(err, cause))
+	assert.Equal(t, "ID test not found", err.Error())
+}
+
+// TestConflict_NilErr checks that Conflict returns nil for a nil error
+func TestConflict_NilErr(t *testing.T) {
+	// Act
+	err := Conflict(nil)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUnauthorized_Kind checks that Unauthorized returns an error matching ErrUnauthorized and the unauthorized error of wrappers
+func TestUnauthorized_Kind(t *testing.T) {
+	// Act
+	err := Unauthorized(fmt.Errorf("invalid token"))
+
+	// Assert
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.True(t, errors.Is(err, wrappers.UnauthorizedErr))
+}
+
+// TestValidationError_Fields checks that a ValidationError matches ErrValidation and the validation error of wrappers,
+// its message joining the reasons of its fields in order
+func TestValidationError_Fields(t *testing.T) {
+	// Arrange
+	var errs ValidationError
+	errs.Add("email", "email cannot be empty")
+	errs.Add("password", "password cannot be empty")
+
+	// Act
+	err := fmt.Errorf("create: %w", errs.Err())
+
+	// Assert
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []FieldError{{Field: "email", Message: "email cannot be empty"}, {Field: "password", Message: "password cannot be empty"}}, validationErr.Fields)
+	assert.Equal(t, "create: email cannot be empty | password cannot be empty", err.Error())
+}
+
+// TestValidationError_NoFields checks that Err returns nil when no field was added
+func TestValidationError_NoFields(t *testing.T) {
+	// Arrange
+	var errs ValidationError
+
+	// Act
+	err := errs.Err()
+
+	// Assert
+	assert.Nil(t, err)
+}
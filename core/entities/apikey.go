@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+// EntityNameAPIKey contains the name of the entity
+const EntityNameAPIKey = "api_keys"
+
+// APIKeyPrefix the prefix of the secrets of the API keys, telling them apart from the JWT tokens in the authorization headers
+const APIKeyPrefix = "hak_"
+
+// APIKey struct, a key authenticating the requests as its user with the claims of its scopes, until it expires or is revoked.
+// Only the hash of its secret is stored, along with the beginning of the secret telling the keys apart.
+type APIKey struct {
+	ID         string     `bson:"_id,omitempty"`
+	UserID     string     `bson:"user_id"`
+	Name       string     `bson:"name"`
+	Prefix     string     `bson:"prefix"`
+	SecretHash string     `bson:"secret_hash"`
+	Scopes     []string   `bson:"scopes"`
+	ExpiresAt  *time.Time `bson:"expires_at,omitempty"`
+	LastUsedAt *time.Time `bson:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `bson:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `bson:"created_at"`
+	UpdatedAt  time.Time  `bson:"updated_at"`
+}
+
+// Active reports whether the API key authenticates the requests at the time, neither revoked nor expired
+func (k APIKey) Active(at time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || at.Before(*k.ExpiresAt))
+}
@@ -0,0 +1,51 @@
+package entities
+
+import "time"
+
+// EntityNameAuditEvent contains the name of the entity
+const EntityNameAuditEvent = "audit_events"
+
+// audit event types
+const (
+	AuditLoginSucceeded     = "login_succeeded"
+	AuditLoginFailed        = "login_failed"
+	AuditLoginSuspicious    = "login_suspicious"
+	AuditEmailFlagged       = "email_flagged"
+	AuditUserCreated        = "user_created"
+	AuditUserUpserted       = "user_upserted"
+	AuditUserUpdated        = "user_updated"
+	AuditClaimsChanged      = "claims_changed"
+	AuditPasswordChanged    = "password_changed"
+	AuditUserMerged         = "user_merged"
+	AuditUserDeleted        = "user_deleted"
+	AuditUsersArchived      = "users_archived"
+	AuditUserUnarchived     = "user_unarchived"
+	AuditBackupStarted      = "backup_started"
+	AuditRestoreStarted     = "restore_started"
+	AuditRetentionApplied   = "retention_applied"
+	AuditCaptureEnabled     = "capture_enabled"
+	AuditCaptureDisabled    = "capture_disabled"
+	AuditConfigReloaded     = "config_reloaded"
+	AuditMaintenanceStarted = "maintenance_started"
+	AuditUploadInfected     = "upload_infected"
+	AuditUserDeactivated    = "user_deactivated"
+	AuditDirectorySynced    = "directory_synced"
+)
+
+// audit event outcomes
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEvent struct, a security relevant event performed by an actor, the authenticated user of the request, on a target user
+type AuditEvent struct {
+	ID        string            `bson:"_id,omitempty"`
+	Type      string            `bson:"type"`
+	Outcome   string            `bson:"outcome"`
+	UserID    string            `bson:"user_id,omitempty"`
+	ActorID   string            `bson:"actor_id,omitempty"`
+	IP        string            `bson:"ip,omitempty"`
+	Details   map[string]string `bson:"details,omitempty"`
+	CreatedAt time.Time         `bson:"created_at"`
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// EntityNameCapture contains the name of the entity
+const EntityNameCapture = "captures"
+
+// Capture struct, the sanitized request and response of a request captured in debug capture mode
+type Capture struct {
+	ID           string    `bson:"_id,omitempty"`
+	RequestID    string    `bson:"request_id"`
+	ActorID      string    `bson:"actor_id,omitempty"`
+	Method       string    `bson:"method"`
+	Path         string    `bson:"path"`
+	Query        string    `bson:"query,omitempty"`
+	Status       int       `bson:"status"`
+	RequestBody  string    `bson:"request_body,omitempty"`
+	ResponseBody string    `bson:"response_body,omitempty"`
+	CreatedAt    time.Time `bson:"created_at"`
+}
@@ -0,0 +1,52 @@
+// Code generated by claimsgen from claims.yaml. DO NOT EDIT.
+
+package entities
+
+// UserClaim claim of the users, granting them access to the routes requiring it
+type UserClaim int
+
+const (
+	// AdminClaim administers the API, managing the users and running its jobs
+	AdminClaim UserClaim = 0
+	// ComplianceClaim reviews the audit log for compliance, browsing and exporting its events
+	ComplianceClaim UserClaim = 1
+)
+
+// userClaimDefinitions the definitions of the claims, sorted by value
+var userClaimDefinitions = []UserClaimDefinition{
+	{Claim: AdminClaim, Name: "admin", Description: "Administers the API, managing the users and running its jobs", Category: "administration"},
+	{Claim: ComplianceClaim, Name: "compliance", Description: "Reviews the audit log for compliance, browsing and exporting its events", Category: "compliance"},
+}
+
+// definition returns the definition of the claim, reporting whether it is defined
+func (claim UserClaim) definition() (UserClaimDefinition, bool) {
+	for _, d := range userClaimDefinitions {
+		if d.Claim == claim {
+			return d, true
+		}
+	}
+	return UserClaimDefinition{}, false
+}
+
+// String returns the name of the claim, empty if not valid
+func (claim UserClaim) String() string {
+	d, _ := claim.definition()
+	return d.Name
+}
+
+// IsValid reports whether the claim is defined, even if deprecated
+func (claim UserClaim) IsValid() bool {
+	_, ok := claim.definition()
+	return ok
+}
+
+// Description returns the description of the claim, empty if not valid
+func (claim UserClaim) Description() string {
+	d, _ := claim.definition()
+	return d.Description
+}
+
+// GetUserClaimDefinitions returns the definitions of the claims, sorted by value
+func GetUserClaimDefinitions() []UserClaimDefinition {
+	return append([]UserClaimDefinition(nil), userClaimDefinitions...)
+}
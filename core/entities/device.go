@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// EntityNameDevice contains the name of the entity
+const EntityNameDevice = "devices"
+
+// platforms of the devices the push notifications are sent to
+const (
+	DevicePlatformFCM  = "fcm"
+	DevicePlatformAPNs = "apns"
+)
+
+// Device struct, a device of a user receiving the push notifications through the token given by its platform.
+// Its ID is derived from the platform and the token, so a token registered again is the same device.
+type Device struct {
+	ID        string    `bson:"_id"`
+	UserID    string    `bson:"user_id"`
+	Platform  string    `bson:"platform"`
+	Token     string    `bson:"token"`
+	Name      string    `bson:"name,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// EntityNameFile contains the name of the entity
+const EntityNameFile = "files"
+
+// File struct
+type File struct {
+	Key         string            `bson:"key"`
+	Name        string            `bson:"name"`
+	ContentType string            `bson:"content_type"`
+	Size        int64             `bson:"size"`
+	Metadata    map[string]string `bson:"metadata"`
+	CreatedAt   time.Time         `bson:"created_at"`
+}
@@ -0,0 +1,54 @@
+package entities
+
+import "time"
+
+// EntityNameJob contains the name of the entity
+const EntityNameJob = "jobs"
+
+// job types
+const (
+	JobTypeBackup        = "backup"
+	JobTypeRestore       = "restore"
+	JobTypeNotification  = "notification"
+	JobTypeMaintenance   = "maintenance"
+	JobTypeEmail         = "email"
+	JobTypeEvent         = "event"
+	JobTypeAdminNotice   = "admin_notice"
+	JobTypePush          = "push"
+	JobTypeBilling       = "billing"
+	JobTypeAnalytics     = "analytics"
+	JobTypeScan          = "scan"
+	JobTypeDirectorySync = "directory_sync"
+)
+
+// JobStatus type
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	// JobStatusFailed is the status of a job whose last attempt failed, queued again until its next attempt is due
+	JobStatusFailed JobStatus = "failed"
+	// JobStatusDead is the status of a job whose every attempt failed, left in the dead letter until retried
+	JobStatusDead JobStatus = "dead"
+)
+
+// Job struct, an operation queued to run in the background by the workers, whose progress is reported while it runs.
+// A worker claims the job until LockedUntil, so when it stops before finishing it the job is claimed again once the lock expires.
+type Job struct {
+	ID          string            `bson:"_id,omitempty"`
+	Type        string            `bson:"type"`
+	Status      JobStatus         `bson:"status"`
+	Processed   int64             `bson:"processed"`
+	Metadata    map[string]string `bson:"metadata"`
+	Error       string            `bson:"error,omitempty"`
+	Attempts    int               `bson:"attempts"`
+	MaxAttempts int               `bson:"max_attempts"`
+	RunAt       time.Time         `bson:"run_at"`
+	Worker      string            `bson:"worker,omitempty"`
+	LockedUntil *time.Time        `bson:"locked_until,omitempty"`
+	CreatedAt   time.Time         `bson:"created_at"`
+	UpdatedAt   time.Time         `bson:"updated_at"`
+	FinishedAt  *time.Time        `bson:"finished_at,omitempty"`
+}
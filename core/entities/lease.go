@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// EntityNameLease contains the name of the entity
+const EntityNameLease = "leases"
+
+// Lease struct, held by a single replica of the API until it expires
+type Lease struct {
+	Name      string    `bson:"_id"`
+	Holder    string    `bson:"holder"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// EntityNameLimit contains the name of the entity
+const EntityNameLimit = "limits"
+
+// Limit struct, the hits of a key of a rate limit or a login lockout counted within its current window
+type Limit struct {
+	Key       string    `bson:"_id"`
+	Count     int64     `bson:"count"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
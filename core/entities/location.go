@@ -0,0 +1,36 @@
+package entities
+
+// GeoPointType is the GeoJSON type of a point
+const GeoPointType = "Point"
+
+// GeoPoint GeoJSON point, with coordinates in [longitude, latitude] order
+type GeoPoint struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+}
+
+// NewGeoPoint creates a GeoJSON point from its longitude and latitude
+func NewGeoPoint(longitude, latitude float64) GeoPoint {
+	return GeoPoint{
+		Type:        GeoPointType,
+		Coordinates: []float64{longitude, latitude},
+	}
+}
+
+// IsValid checks that the point is a GeoJSON point with coordinates inside the valid ranges
+func (p GeoPoint) IsValid() bool {
+	if p.Type != GeoPointType || len(p.Coordinates) != 2 {
+		return false
+	}
+	return IsValidLongitude(p.Coordinates[0]) && IsValidLatitude(p.Coordinates[1])
+}
+
+// IsValidLongitude checks that the longitude is between -180 and 180 degrees
+func IsValidLongitude(longitude float64) bool {
+	return longitude >= -180 && longitude <= 180
+}
+
+// IsValidLatitude checks that the latitude is between -90 and 90 degrees
+func IsValidLatitude(latitude float64) bool {
+	return latitude >= -90 && latitude <= 90
+}
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewGeoPoint_Ok checks that NewGeoPoint creates a GeoJSON point with the coordinates in [longitude, latitude] order
+func TestNewGeoPoint_Ok(t *testing.T) {
+	// Arrange
+	longitude, latitude := 2.17, 41.38
+
+	// Act
+	point := NewGeoPoint(longitude, latitude)
+
+	// Assert
+	assert.Equal(t, GeoPoint{Type: "Point", Coordinates: []float64{longitude, latitude}}, point)
+	assert.True(t, point.IsValid())
+}
+
+// TestGeoPointIsValid_Invalid checks that IsValid returns false when the point is not a valid GeoJSON point
+func TestGeoPointIsValid_Invalid(t *testing.T) {
+	// Arrange
+	points := []GeoPoint{
+		{Type: "LineString", Coordinates: []float64{0, 0}},
+		{Type: GeoPointType, Coordinates: []float64{0}},
+		{Type: GeoPointType, Coordinates: []float64{181, 0}},
+		{Type: GeoPointType, Coordinates: []float64{0, -91}},
+	}
+
+	for _, point := range points {
+		// Act
+		isValid := point.IsValid()
+
+		// Assert
+		assert.False(t, isValid)
+	}
+}
@@ -0,0 +1,64 @@
+package entities
+
+import "time"
+
+// EntityNameNotificationPreferences contains the name of the entity
+const EntityNameNotificationPreferences = "notification_preferences"
+
+// channels the notifications are delivered through
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelSMS   = "sms"
+	NotificationChannelPush  = "push"
+)
+
+// categories of the notifications sent to the users
+const (
+	// NotificationCategorySecurity the alerts of the changes of the password or the email and of the sign-ins from new devices
+	NotificationCategorySecurity = "security"
+	// NotificationCategoryVerification the one-time and verification codes
+	NotificationCategoryVerification = "verification"
+)
+
+// NotificationCategories the categories of the notifications the users set their preferences for
+var NotificationCategories = []string{NotificationCategorySecurity, NotificationCategoryVerification}
+
+// NotificationChannels the toggles of the channels the notifications of a category are delivered through
+type NotificationChannels struct {
+	Email bool `bson:"email"`
+	SMS   bool `bson:"sms"`
+	Push  bool `bson:"push"`
+}
+
+// AllNotificationChannels returns the toggles of a category the user did not set, delivered through every channel
+func AllNotificationChannels() NotificationChannels {
+	return NotificationChannels{Email: true, SMS: true, Push: true}
+}
+
+// Allows reports whether the channel is enabled, the unknown ones never being
+func (c NotificationChannels) Allows(channel string) bool {
+	switch channel {
+	case NotificationChannelEmail:
+		return c.Email
+	case NotificationChannelSMS:
+		return c.SMS
+	case NotificationChannelPush:
+		return c.Push
+	}
+	return false
+}
+
+// NotificationPreferences struct, the channels the notifications of every category are delivered to a user through, stored by the ID of the user
+type NotificationPreferences struct {
+	UserID     string                          `bson:"_id"`
+	Categories map[string]NotificationChannels `bson:"categories"`
+	UpdatedAt  time.Time                       `bson:"updated_at"`
+}
+
+// Channels returns the toggles of the category, every channel being enabled for the categories not set
+func (p NotificationPreferences) Channels(category string) NotificationChannels {
+	if channels, ok := p.Categories[category]; ok {
+		return channels
+	}
+	return AllNotificationChannels()
+}
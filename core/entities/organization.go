@@ -0,0 +1,63 @@
+package entities
+
+import "time"
+
+// EntityNameOrganization contains the name of the entity
+const EntityNameOrganization = "organizations"
+
+// roles of the members of the organizations, each one allowed what the ones below it are
+const (
+	// OrganizationRoleOwner the creator of the organization or the member it was transferred to, the only one deleting it,
+	// never removed from it
+	OrganizationRoleOwner = "owner"
+	// OrganizationRoleAdmin updates the organization and manages its members
+	OrganizationRoleAdmin = "admin"
+	// OrganizationRoleMember reads the organization and its members
+	OrganizationRoleMember = "member"
+)
+
+// OrganizationMember struct, a user member of an organization with a role
+type OrganizationMember struct {
+	UserID   string    `bson:"user_id"`
+	Role     string    `bson:"role"`
+	JoinedAt time.Time `bson:"joined_at"`
+}
+
+// OrganizationInvitation struct, a user invited to an organization with a role, member once it accepts
+type OrganizationInvitation struct {
+	UserID    string    `bson:"user_id"`
+	Role      string    `bson:"role"`
+	InvitedBy string    `bson:"invited_by"`
+	InvitedAt time.Time `bson:"invited_at"`
+}
+
+// Organization struct, a group of users whose members and pending invitations are stored along with it, its owner among the members
+type Organization struct {
+	ID          string                   `bson:"_id,omitempty"`
+	Name        string                   `bson:"name"`
+	OwnerID     string                   `bson:"owner_id"`
+	Members     []OrganizationMember     `bson:"members"`
+	Invitations []OrganizationInvitation `bson:"invitations,omitempty"`
+	CreatedAt   time.Time                `bson:"created_at"`
+	UpdatedAt   time.Time                `bson:"updated_at"`
+}
+
+// Member returns the member of the organization with the user ID, and whether the user is a member
+func (o Organization) Member(userID string) (OrganizationMember, bool) {
+	for _, m := range o.Members {
+		if m.UserID == userID {
+			return m, true
+		}
+	}
+	return OrganizationMember{}, false
+}
+
+// Invitation returns the pending invitation of the user to the organization, and whether the user is invited
+func (o Organization) Invitation(userID string) (OrganizationInvitation, bool) {
+	for _, i := range o.Invitations {
+		if i.UserID == userID {
+			return i, true
+		}
+	}
+	return OrganizationInvitation{}, false
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// EntityNameResumeToken contains the name of the entity
+const EntityNameResumeToken = "resume_tokens"
+
+// ResumeToken struct, the position of a change stream after the last change handled by its consumer, named after it
+type ResumeToken struct {
+	Name      string    `bson:"_id"`
+	Token     []byte    `bson:"token"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
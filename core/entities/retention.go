@@ -0,0 +1,13 @@
+package entities
+
+// RetentionAction type, what a retention policy does with the documents older than its limit
+type RetentionAction string
+
+const (
+	RetentionPurge     RetentionAction = "purge"
+	RetentionAnonymize RetentionAction = "anonymize"
+)
+
+func (action RetentionAction) IsValid() bool {
+	return action == RetentionPurge || action == RetentionAnonymize
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// EntityNameRole contains the name of the entity
+const EntityNameRole = "roles"
+
+// kinds of the assignees of the roles
+const (
+	// RoleAssigneeUser a user, granted the role itself
+	RoleAssigneeUser = "users"
+	// RoleAssigneeOrganization an organization, whose members are granted the role
+	RoleAssigneeOrganization = "organizations"
+)
+
+// Role struct, a named set of permissions, the names of the claims it grants, assigned to users and organizations,
+// whose IDs are stored along with it
+type Role struct {
+	ID              string    `bson:"_id,omitempty"`
+	Name            string    `bson:"name"`
+	Description     string    `bson:"description"`
+	Permissions     []string  `bson:"permissions"`
+	UserIDs         []string  `bson:"user_ids"`
+	OrganizationIDs []string  `bson:"organization_ids"`
+	CreatedAt       time.Time `bson:"created_at"`
+	UpdatedAt       time.Time `bson:"updated_at"`
+}
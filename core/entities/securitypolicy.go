@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+)
+
+// EntityNameSecurityPolicy contains the name of the entity
+const EntityNameSecurityPolicy = "security_policies"
+
+// PasswordPolicy the rules the passwords of the users of a tenant must follow, the zero one allowing any password
+type PasswordPolicy struct {
+	MinLength        int  `bson:"min_length"`
+	RequireUppercase bool `bson:"require_uppercase"`
+	RequireLowercase bool `bson:"require_lowercase"`
+	RequireDigit     bool `bson:"require_digit"`
+	RequireSymbol    bool `bson:"require_symbol"`
+}
+
+// Violations returns the rules the password breaks, in the order they are declared
+func (p PasswordPolicy) Violations(password string) []string {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	var violations []string
+	if len([]rune(password)) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("password must be at least %d characters long", p.MinLength))
+	}
+	if p.RequireUppercase && !upper {
+		violations = append(violations, "password must contain an uppercase letter")
+	}
+	if p.RequireLowercase && !lower {
+		violations = append(violations, "password must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		violations = append(violations, "password must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		violations = append(violations, "password must contain a symbol")
+	}
+	return violations
+}
+
+// SecurityPolicy struct, the security settings of the users of a tenant, the domain of their emails, stored by the tenant.
+// The settings left zero apply the ones of the config: the lifetime of the tokens, and the lockout when enabled.
+type SecurityPolicy struct {
+	Tenant             string         `bson:"_id"`
+	Password           PasswordPolicy `bson:"password"`
+	SessionLifetime    time.Duration  `bson:"session_lifetime"`
+	MFARequired        bool           `bson:"mfa_required"`
+	LockoutMaxFailures int64          `bson:"lockout_max_failures"`
+	LockoutDuration    time.Duration  `bson:"lockout_duration"`
+	CreatedAt          time.Time      `bson:"created_at"`
+	UpdatedAt          time.Time      `bson:"updated_at"`
+}
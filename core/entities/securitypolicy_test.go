@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPasswordPolicyViolations_Ok checks that Violations returns no rule for a password following every rule of the policy
+func TestPasswordPolicyViolations_Ok(t *testing.T) {
+	// Arrange
+	policy := PasswordPolicy{MinLength: 12, RequireUppercase: true, RequireLowercase: true, RequireDigit: true, RequireSymbol: true}
+
+	// Act
+	violations := policy.Violations("Correct-horse-1")
+
+	// Assert
+	assert.Empty(t, violations)
+}
+
+// TestPasswordPolicyViolations_Broken checks that Violations returns every rule of the policy the password breaks, in order
+func TestPasswordPolicyViolations_Broken(t *testing.T) {
+	// Arrange
+	policy := PasswordPolicy{MinLength: 12, RequireUppercase: true, RequireLowercase: true, RequireDigit: true, RequireSymbol: true}
+	expectedViolations := []string{
+		"password must be at least 12 characters long",
+		"password must contain an uppercase letter",
+		"password must contain a digit",
+		"password must contain a symbol",
+	}
+
+	// Act
+	violations := policy.Violations("horse")
+
+	// Assert
+	assert.Equal(t, expectedViolations, violations)
+}
@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// EntityNameSigningKey contains the name of the entity
+const EntityNameSigningKey = "signing_keys"
+
+// SigningKey struct, signing the tokens while the newest one, then only verifying them until it expires
+type SigningKey struct {
+	ID        string     `bson:"_id"`
+	Secret    string     `bson:"secret"`
+	CreatedAt time.Time  `bson:"created_at"`
+	ExpiresAt *time.Time `bson:"expires_at,omitempty"`
+}
@@ -7,37 +7,54 @@ import (
 // EntityNameUser contains the name of the entity
 const EntityNameUser = "users"
 
-// UserClaim type
-type UserClaim int
-
-const (
-	admin = iota
-)
-
-func (claim UserClaim) String() string {
-	return [...]string{"admin"}[claim]
+// EntityNameUserArchive contains the name of the archived entity
+const EntityNameUserArchive = "users_archive"
+
+// the claims of the users, UserClaim, and their definitions are generated from claims.yaml
+//go:generate go run ../../cmd/claimsgen --source=claims.yaml --output=claims_gen.go --models=../models
+
+// UserClaimDefinition definition of a claim of the users, grouped by Category and Deprecated when it should no longer be granted
+type UserClaimDefinition struct {
+	Claim       UserClaim
+	Name        string
+	Description string
+	Category    string
+	Deprecated  bool
 }
 
-func (claim UserClaim) IsValid() bool {
-	return claim >= admin && claim <= admin
+// User struct
+type User struct {
+	ID           string     `bson:"_id,omitempty"`
+	Name         string     `bson:"name"`
+	Surnames     string     `bson:"surnames"`
+	Email        string     `bson:"email"`
+	PasswordHash string     `bson:"password_hash"`
+	Claims       []int64    `bson:"claims"`
+	Location     *GeoPoint  `bson:"location,omitempty"`
+	LastLoginAt  *time.Time `bson:"last_login_at,omitempty"`
+	CreatedAt    time.Time  `bson:"created_at"`
+	UpdatedAt    time.Time  `bson:"updated_at"`
+	// BillingCustomerID is the ID of the customer of the user in the billing provider, and SubscriptionStatus the status of its subscription,
+	// as of SubscriptionUpdatedAt, both only set by the billing service
+	BillingCustomerID     string     `bson:"billing_customer_id,omitempty"`
+	SubscriptionStatus    string     `bson:"subscription_status,omitempty"`
+	SubscriptionUpdatedAt *time.Time `bson:"subscription_updated_at,omitempty"`
 }
 
-func GetUserClaims() map[int]string {
-	claims := make(map[int]string)
-	for i, claim := range [...]string{"admin"} {
-		claims[i] = claim
-	}
-	return claims
+// statuses of the subscriptions granting access to the routes requiring one, as named by the billing provider
+const (
+	SubscriptionStatusActive   = "active"
+	SubscriptionStatusTrialing = "trialing"
+)
+
+// Subscribed reports whether the subscription of the user grants access, being active or in its trial
+func (u User) Subscribed() bool {
+	return u.SubscriptionStatus == SubscriptionStatusActive || u.SubscriptionStatus == SubscriptionStatusTrialing
 }
 
-// User struct
-type User struct {
-	ID           string    `bson:"_id,omitempty"`
-	Name         string    `bson:"name"`
-	Surnames     string    `bson:"surnames"`
-	Email        string    `bson:"email"`
-	PasswordHash string    `bson:"password_hash"`
-	Claims       []int64   `bson:"claims"`
-	CreatedAt    time.Time `bson:"created_at"`
-	UpdatedAt    time.Time `bson:"updated_at"`
+// UserStats counts of the users, the active ones having logged in or been updated since the time they were counted from
+type UserStats struct {
+	Total    int64
+	Active   int64
+	Archived int64
 }
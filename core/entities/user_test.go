@@ -32,14 +32,35 @@ func TestIsValid_Ok(t *testing.T) {
 	assert.True(t, isValid)
 }
 
-// TestGetUserClaims_Ok checks that GetUserClaims returns the expected response when everything goes as expected
-func TestGetUserClaims_Ok(t *testing.T) {
+// TestGetUserClaimDefinitions_Ok checks that GetUserClaimDefinitions returns the definitions of the claims, sorted by value
+func TestGetUserClaimDefinitions_Ok(t *testing.T) {
+	// Act
+	resp := GetUserClaimDefinitions()
+
+	// Assert
+	assert.Equal(t, AdminClaim, resp[0].Claim)
+	assert.Equal(t, "admin", resp[0].Name)
+	assert.Equal(t, "administration", resp[0].Category)
+	assert.False(t, resp[0].Deprecated)
+}
+
+// TestGetUserClaimDefinitions_Copy checks that changing the definitions returned does not change the ones of the claims
+func TestGetUserClaimDefinitions_Copy(t *testing.T) {
 	// Arrange
-	expectedClaims := map[int]string{0: "admin"}
+	resp := GetUserClaimDefinitions()
+
+	// Act
+	resp[0].Name = "changed"
+
+	// Assert
+	assert.Equal(t, "admin", AdminClaim.String())
+}
 
+// TestString_NotValid checks that String returns an empty name for a claim not defined
+func TestString_NotValid(t *testing.T) {
 	// Act
-	resp := GetUserClaims()
+	claim := UserClaim(-1).String()
 
 	// Assert
-	assert.Equal(t, expectedClaims, resp)
+	assert.Empty(t, claim)
 }
@@ -0,0 +1,21 @@
+package mapping
+
+import (
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// APIKeyResp maps a stored API key to its response, without its secret
+func APIKeyResp(key entities.APIKey) models.APIKeyResp {
+	return models.APIKeyResp{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     nonNil(key.Scopes),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,24 @@
+package mapping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAPIKeyResp_Ok checks that APIKeyResp maps the stored API key to its response, without its secret
+func TestAPIKeyResp_Ok(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	key := entities.APIKey{ID: "test-key", UserID: "test-user", Name: "test", Prefix: "hak_prefix", SecretHash: "test-hash", LastUsedAt: &now, CreatedAt: now}
+	expectedResp := models.APIKeyResp{ID: "test-key", UserID: "test-user", Name: "test", Prefix: "hak_prefix", Scopes: []string{}, LastUsedAt: &now, CreatedAt: now}
+
+	// Act
+	resp := APIKeyResp(key)
+
+	// Assert
+	assert.Equal(t, expectedResp, resp)
+}
@@ -0,0 +1,17 @@
+package mapping
+
+import (
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// ClaimResp maps the definition of a claim to its response
+func ClaimResp(definition entities.UserClaimDefinition) models.ClaimResp {
+	return models.ClaimResp{
+		ID:          int(definition.Claim),
+		Name:        definition.Name,
+		Description: definition.Description,
+		Category:    definition.Category,
+		Deprecated:  definition.Deprecated,
+	}
+}
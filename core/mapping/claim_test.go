@@ -0,0 +1,22 @@
+package mapping
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClaimResp_Ok checks that ClaimResp maps every field of the definition of the claim to its response, its value as its ID
+func TestClaimResp_Ok(t *testing.T) {
+	// Arrange
+	definition := entities.UserClaimDefinition{Claim: 3, Name: "read_only", Description: "Reads the users", Category: "users", Deprecated: true}
+	expectedResp := models.ClaimResp{ID: 3, Name: "read_only", Description: "Reads the users", Category: "users", Deprecated: true}
+
+	// Act
+	resp := ClaimResp(definition)
+
+	// Assert
+	assert.Equal(t, expectedResp, resp)
+}
@@ -0,0 +1,22 @@
+package mapping
+
+import (
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// NotificationPreferencesResp maps the stored preferences of a user to their response, holding every category,
+// the ones not set with every channel enabled
+func NotificationPreferencesResp(preferences entities.NotificationPreferences) models.NotificationPreferencesResp {
+	resp := models.NotificationPreferencesResp{
+		UserID:     preferences.UserID,
+		Categories: make(map[string]models.NotificationChannelsResp, len(entities.NotificationCategories)),
+	}
+	for _, category := range entities.NotificationCategories {
+		resp.Categories[category] = models.NotificationChannelsResp(preferences.Channels(category))
+	}
+	if !preferences.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &preferences.UpdatedAt
+	}
+	return resp
+}
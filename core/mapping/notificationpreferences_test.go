@@ -0,0 +1,47 @@
+package mapping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNotificationPreferencesResp_Ok checks that NotificationPreferencesResp maps every category, the ones not set with every channel enabled
+func TestNotificationPreferencesResp_Ok(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	preferences := entities.NotificationPreferences{
+		UserID:     "test-user",
+		Categories: map[string]entities.NotificationChannels{entities.NotificationCategorySecurity: {Email: true}},
+		UpdatedAt:  now,
+	}
+	expectedResp := models.NotificationPreferencesResp{
+		UserID: "test-user",
+		Categories: map[string]models.NotificationChannelsResp{
+			entities.NotificationCategorySecurity:     {Email: true},
+			entities.NotificationCategoryVerification: {Email: true, SMS: true, Push: true},
+		},
+		UpdatedAt: &now,
+	}
+
+	// Act
+	resp := NotificationPreferencesResp(preferences)
+
+	// Assert
+	assert.Equal(t, expectedResp, resp)
+}
+
+// TestNotificationPreferencesResp_NeverSet checks that NotificationPreferencesResp enables every channel and leaves the update time empty for the preferences never set
+func TestNotificationPreferencesResp_NeverSet(t *testing.T) {
+	// Act
+	resp := NotificationPreferencesResp(entities.NotificationPreferences{UserID: "test-user"})
+
+	// Assert
+	assert.Nil(t, resp.UpdatedAt)
+	for _, category := range entities.NotificationCategories {
+		assert.Equal(t, models.NotificationChannelsResp{Email: true, SMS: true, Push: true}, resp.Categories[category])
+	}
+}
@@ -0,0 +1,41 @@
+package mapping
+
+import (
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// OrganizationResp maps a stored organization to its response, with the role in it of the user with the ID
+func OrganizationResp(organization entities.Organization, userID string) models.OrganizationResp {
+	member, _ := organization.Member(userID)
+	return models.OrganizationResp{
+		ID:           organization.ID,
+		Name:         organization.Name,
+		OwnerID:      organization.OwnerID,
+		Role:         member.Role,
+		MembersCount: len(organization.Members),
+		CreatedAt:    organization.CreatedAt,
+		UpdatedAt:    organization.UpdatedAt,
+	}
+}
+
+// OrganizationMemberResp maps a member of an organization, along with its user, to its response
+func OrganizationMemberResp(member entities.OrganizationMember, user models.UserResp) models.OrganizationMemberResp {
+	return models.OrganizationMemberResp{
+		User:     user,
+		Role:     member.Role,
+		JoinedAt: member.JoinedAt,
+	}
+}
+
+// OrganizationInvitationResp maps a pending invitation to an organization to its response
+func OrganizationInvitationResp(organization entities.Organization, invitation entities.OrganizationInvitation) models.OrganizationInvitationResp {
+	return models.OrganizationInvitationResp{
+		OrganizationID:   organization.ID,
+		OrganizationName: organization.Name,
+		UserID:           invitation.UserID,
+		Role:             invitation.Role,
+		InvitedBy:        invitation.InvitedBy,
+		InvitedAt:        invitation.InvitedAt,
+	}
+}
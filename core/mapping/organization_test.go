@@ -0,0 +1,62 @@
+package mapping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOrganizationResp_Ok checks that OrganizationResp maps the stored organization to its response, with the role of the user and its members count
+func TestOrganizationResp_Ok(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	organization := entities.Organization{
+		ID:      "test-organization",
+		Name:    "test",
+		OwnerID: "test-owner",
+		Members: []entities.OrganizationMember{
+			{UserID: "test-owner", Role: entities.OrganizationRoleOwner},
+			{UserID: "test-user", Role: entities.OrganizationRoleAdmin},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	expectedResp := models.OrganizationResp{ID: "test-organization", Name: "test", OwnerID: "test-owner", Role: entities.OrganizationRoleAdmin, MembersCount: 2, CreatedAt: now, UpdatedAt: now}
+
+	// Act
+	resp := OrganizationResp(organization, "test-user")
+
+	// Assert
+	assert.Equal(t, expectedResp, resp)
+}
+
+// TestOrganizationResp_NotMember checks that OrganizationResp maps the organization without a role when the user is not a member
+func TestOrganizationResp_NotMember(t *testing.T) {
+	// Arrange
+	organization := entities.Organization{ID: "test-organization", Members: []entities.OrganizationMember{{UserID: "test-owner", Role: entities.OrganizationRoleOwner}}}
+
+	// Act
+	resp := OrganizationResp(organization, "test-user")
+
+	// Assert
+	assert.Empty(t, resp.Role)
+	assert.Equal(t, 1, resp.MembersCount)
+}
+
+// TestOrganizationInvitationResp_Ok checks that OrganizationInvitationResp maps the invitation along with its organization
+func TestOrganizationInvitationResp_Ok(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	organization := entities.Organization{ID: "test-organization", Name: "test"}
+	invitation := entities.OrganizationInvitation{UserID: "test-user", Role: entities.OrganizationRoleMember, InvitedBy: "test-owner", InvitedAt: now}
+	expectedResp := models.OrganizationInvitationResp{OrganizationID: "test-organization", OrganizationName: "test", UserID: "test-user", Role: entities.OrganizationRoleMember, InvitedBy: "test-owner", InvitedAt: now}
+
+	// Act
+	resp := OrganizationInvitationResp(organization, invitation)
+
+	// Assert
+	assert.Equal(t, expectedResp, resp)
+}
@@ -0,0 +1,28 @@
+package mapping
+
+import (
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// RoleResp maps a stored role to its response, with empty lists instead of nil ones
+func RoleResp(role entities.Role) models.RoleResp {
+	return models.RoleResp{
+		ID:              role.ID,
+		Name:            role.Name,
+		Description:     role.Description,
+		Permissions:     nonNil(role.Permissions),
+		UserIDs:         nonNil(role.UserIDs),
+		OrganizationIDs: nonNil(role.OrganizationIDs),
+		CreatedAt:       role.CreatedAt,
+		UpdatedAt:       role.UpdatedAt,
+	}
+}
+
+// nonNil returns the values, or an empty list when nil, so they are responded as an empty array instead of null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
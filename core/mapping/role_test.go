@@ -0,0 +1,23 @@
+package mapping
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRoleResp_Ok checks that RoleResp maps the stored role to its response, with empty lists instead of nil ones
+func TestRoleResp_Ok(t *testing.T) {
+	// Arrange
+	role := entities.Role{ID: "test-role-id", Name: "test-role", Permissions: []string{"admin"}}
+
+	// Act
+	resp := RoleResp(role)
+
+	// Assert
+	assert.Equal(t, "test-role", resp.Name)
+	assert.Equal(t, []string{"admin"}, resp.Permissions)
+	assert.Equal(t, []string{}, resp.UserIDs)
+	assert.Equal(t, []string{}, resp.OrganizationIDs)
+}
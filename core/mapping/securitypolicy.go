@@ -0,0 +1,56 @@
+package mapping
+
+import (
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// SecurityPolicy maps a validated put security policy request to the policy stored, the durations left empty being zero
+func SecurityPolicy(req models.PutSecurityPolicyReq) entities.SecurityPolicy {
+	sessionLifetime, _ := time.ParseDuration(req.SessionLifetime)
+	lockoutDuration, _ := time.ParseDuration(req.LockoutDuration)
+	return entities.SecurityPolicy{
+		Tenant: req.Tenant,
+		Password: entities.PasswordPolicy{
+			MinLength:        req.Password.MinLength,
+			RequireUppercase: req.Password.RequireUppercase,
+			RequireLowercase: req.Password.RequireLowercase,
+			RequireDigit:     req.Password.RequireDigit,
+			RequireSymbol:    req.Password.RequireSymbol,
+		},
+		SessionLifetime:    sessionLifetime,
+		MFARequired:        req.MFARequired,
+		LockoutMaxFailures: req.LockoutMaxFailures,
+		LockoutDuration:    lockoutDuration,
+	}
+}
+
+// SecurityPolicyResp maps a stored security policy to its response, the zero durations being left empty
+func SecurityPolicyResp(policy entities.SecurityPolicy) models.SecurityPolicyResp {
+	return models.SecurityPolicyResp{
+		Tenant: policy.Tenant,
+		Password: models.PasswordPolicyResp{
+			MinLength:        policy.Password.MinLength,
+			RequireUppercase: policy.Password.RequireUppercase,
+			RequireLowercase: policy.Password.RequireLowercase,
+			RequireDigit:     policy.Password.RequireDigit,
+			RequireSymbol:    policy.Password.RequireSymbol,
+		},
+		SessionLifetime:    formatDuration(policy.SessionLifetime),
+		MFARequired:        policy.MFARequired,
+		LockoutMaxFailures: policy.LockoutMaxFailures,
+		LockoutDuration:    formatDuration(policy.LockoutDuration),
+		CreatedAt:          policy.CreatedAt,
+		UpdatedAt:          policy.UpdatedAt,
+	}
+}
+
+// formatDuration formats the duration like 24h0m0s, or empty when zero
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
@@ -0,0 +1,61 @@
+package mapping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSecurityPolicy_Ok checks that SecurityPolicy parses the durations of the request, leaving zero the ones not set
+func TestSecurityPolicy_Ok(t *testing.T) {
+	// Arrange
+	req := models.PutSecurityPolicyReq{
+		Tenant:          "example.com",
+		Password:        models.PasswordPolicyReq{MinLength: 12, RequireSymbol: true},
+		SessionLifetime: "8h",
+		MFARequired:     true,
+	}
+	expectedPolicy := entities.SecurityPolicy{
+		Tenant:          "example.com",
+		Password:        entities.PasswordPolicy{MinLength: 12, RequireSymbol: true},
+		SessionLifetime: 8 * time.Hour,
+		MFARequired:     true,
+	}
+
+	// Act
+	policy := SecurityPolicy(req)
+
+	// Assert
+	assert.Equal(t, expectedPolicy, policy)
+}
+
+// TestSecurityPolicyResp_Ok checks that SecurityPolicyResp formats the durations set, leaving empty the zero ones
+func TestSecurityPolicyResp_Ok(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	policy := entities.SecurityPolicy{
+		Tenant:             "example.com",
+		Password:           entities.PasswordPolicy{MinLength: 12},
+		LockoutMaxFailures: 3,
+		LockoutDuration:    time.Hour,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	expectedResp := models.SecurityPolicyResp{
+		Tenant:             "example.com",
+		Password:           models.PasswordPolicyResp{MinLength: 12},
+		LockoutMaxFailures: 3,
+		LockoutDuration:    "1h0m0s",
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	// Act
+	resp := SecurityPolicyResp(policy)
+
+	// Assert
+	assert.Equal(t, expectedResp, resp)
+}
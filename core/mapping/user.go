@@ -0,0 +1,59 @@
+// Package mapping maps the request and response models of the API to the entities stored and back, field by field,
+// so the fields of an entity only reach a response when mapped here, and the ones never to be exposed, like the password hash,
+// are not even part of the responses.
+package mapping
+
+import (
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// User maps a creation request to the user to store, with its password set as the hash, to be hashed before storing it
+func User(req models.CreateUserReq) entities.User {
+	return entities.User{
+		Name:         req.Name,
+		Surnames:     req.Surnames,
+		Email:        req.Email,
+		PasswordHash: req.Password,
+		Claims:       req.Claims,
+		Location:     req.Location,
+	}
+}
+
+// UpsertedUser maps an upsert request to the user to store, with its password set as the hash, to be hashed before storing it
+func UpsertedUser(req models.UpsertUserReq) entities.User {
+	return entities.User{
+		Name:         req.Name,
+		Surnames:     req.Surnames,
+		Email:        req.Email,
+		PasswordHash: req.Password,
+		Claims:       req.Claims,
+		Location:     req.Location,
+	}
+}
+
+// UserResp maps a stored user to its response
+func UserResp(user entities.User) models.UserResp {
+	return models.UserResp{
+		ID:                 user.ID,
+		Name:               user.Name,
+		Surnames:           user.Surnames,
+		Email:              user.Email,
+		Claims:             user.Claims,
+		Location:           user.Location,
+		LastLoginAt:        user.LastLoginAt,
+		CreatedAt:          user.CreatedAt,
+		UpdatedAt:          user.UpdatedAt,
+		BillingCustomerID:  user.BillingCustomerID,
+		SubscriptionStatus: user.SubscriptionStatus,
+	}
+}
+
+// UsersResp maps the users read from a repository, as pointers to them, to their responses
+func UsersResp(users []interface{}) []models.UserResp {
+	resp := make([]models.UserResp, len(users))
+	for i, v := range users {
+		resp[i] = UserResp(*(v.(*entities.User)))
+	}
+	return resp
+}
@@ -0,0 +1,106 @@
+package mapping
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUser_Ok checks that User maps the creation request to the user to store, with the password as its hash
+func TestUser_Ok(t *testing.T) {
+	// Arrange
+	location := &entities.GeoPoint{Type: entities.GeoPointType, Coordinates: []float64{2.17, 41.38}}
+	req := models.CreateUserReq{Name: "test", Surnames: "test", Email: "test@test.com", Password: "password", Claims: []int64{0}, Location: location}
+	expectedUser := entities.User{Name: "test", Surnames: "test", Email: "test@test.com", PasswordHash: "password", Claims: []int64{0}, Location: location}
+
+	// Act
+	user := User(req)
+
+	// Assert
+	assert.Equal(t, expectedUser, user)
+}
+
+// TestUpsertedUser_Ok checks that UpsertedUser maps the upsert request to the user to store, with the password as its hash
+func TestUpsertedUser_Ok(t *testing.T) {
+	// Arrange
+	req := models.UpsertUserReq{Name: "test", Surnames: "test", Email: "test@test.com", Password: "password", Claims: []int64{0}}
+	expectedUser := entities.User{Name: "test", Surnames: "test", Email: "test@test.com", PasswordHash: "password", Claims: []int64{0}}
+
+	// Act
+	user := UpsertedUser(req)
+
+	// Assert
+	assert.Equal(t, expectedUser, user)
+}
+
+// TestUserResp_Ok checks that UserResp maps every exposed field of the stored user to its response
+func TestUserResp_Ok(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	location := &entities.GeoPoint{Type: entities.GeoPointType, Coordinates: []float64{2.17, 41.38}}
+	user := entities.User{
+		ID:                    "test-id",
+		Name:                  "test",
+		Surnames:              "test",
+		Email:                 "test@test.com",
+		PasswordHash:          "test-hash",
+		Claims:                []int64{0},
+		Location:              location,
+		LastLoginAt:           &now,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+		BillingCustomerID:     "test-customer",
+		SubscriptionStatus:    entities.SubscriptionStatusActive,
+		SubscriptionUpdatedAt: &now,
+	}
+	expectedResp := models.UserResp{
+		ID:                 "test-id",
+		Name:               "test",
+		Surnames:           "test",
+		Email:              "test@test.com",
+		Claims:             []int64{0},
+		Location:           location,
+		LastLoginAt:        &now,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+		BillingCustomerID:  "test-customer",
+		SubscriptionStatus: entities.SubscriptionStatusActive,
+	}
+
+	// Act
+	resp := UserResp(user)
+
+	// Assert
+	assert.Equal(t, expectedResp, resp)
+}
+
+// TestUserResp_NoPasswordHash checks that the password hash of the stored user never reaches its response once encoded
+func TestUserResp_NoPasswordHash(t *testing.T) {
+	// Arrange
+	user := entities.User{ID: "test-id", Email: "test@test.com", PasswordHash: "test-hash"}
+
+	// Act
+	b, err := json.Marshal(UserResp(user))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "test-hash")
+	assert.NotContains(t, string(b), "password")
+}
+
+// TestUsersResp_Ok checks that UsersResp maps the users read from a repository in their order
+func TestUsersResp_Ok(t *testing.T) {
+	// Arrange
+	users := []interface{}{&entities.User{ID: "first", PasswordHash: "test-hash"}, &entities.User{ID: "second"}}
+	expectedResp := []models.UserResp{{ID: "first"}, {ID: "second"}}
+
+	// Act
+	resp := UsersResp(users)
+
+	// Assert
+	assert.Equal(t, expectedResp, resp)
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+)
+
+// APIKeyResp API key response struct, mapped from the stored API key by the mapping package, without its secret
+type APIKeyResp struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIKeySecretResp API key response struct of its creation and rotation, the only ones with its secret, which cannot be read again
+type APIKeySecretResp struct {
+	APIKeyResp
+	Key string `json:"api_key"`
+}
+
+// CreateAPIKeyReq API key request struct, whose scopes are the names of the claims granted by the key, never expiring when ExpiresAt is not set
+type CreateAPIKeyReq struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Validate checks that a given CreateAPIKeyReq is valid
+func (req CreateAPIKeyReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if req.Name == "" {
+		errs.Add("name", "name cannot be empty")
+	}
+	validateClaimNames(&errs, "scopes", "scope", req.Scopes)
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		errs.Add("expires_at", "expires_at must be in the future")
+	}
+
+	return errs.Err()
+}
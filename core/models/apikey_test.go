@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateCreateAPIKeyReq_InvalidRequest checks that Validate returns an error when a scope is not a claim or the expiry is in the past
+func TestValidateCreateAPIKeyReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	expiresAt := time.Now().Add(-time.Hour)
+	req := CreateAPIKeyReq{Name: "test", Scopes: []string{"unknown"}, ExpiresAt: &expiresAt}
+	expectedError := "scope unknown is not a claim that can be granted | expires_at must be in the future"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrValidation)
+	assert.Equal(t, expectedError, err.Error())
+}
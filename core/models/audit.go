@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// MaxAuditEventsTake is the maximum number of audit events returned at once
+const MaxAuditEventsTake = 1000
+
+// AuditEventResp audit event response struct
+type AuditEventResp struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	Outcome   string            `json:"outcome" enums:"success,failure"`
+	UserID    string            `json:"user_id,omitempty"`
+	ActorID   string            `json:"actor_id,omitempty"`
+	IP        string            `json:"ip,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// GetAuditEventsReq get audit events request struct, empty filters match every event
+type GetAuditEventsReq struct {
+	Type    string
+	Outcome string
+	UserID  string
+	ActorID string
+	From    *time.Time
+	To      *time.Time
+	Skip    int
+	Take    int
+}
+
+// Validate checks that a given GetAuditEventsReq is valid
+func (req GetAuditEventsReq) Validate() error {
+	msgs := validateAuditFilters(req.Outcome, req.From, req.To)
+
+	if req.Skip < 0 {
+		msgs = append(msgs, "skip cannot be negative")
+	}
+	if req.Take < 1 || req.Take > MaxAuditEventsTake {
+		msgs = append(msgs, fmt.Sprintf("take must be between 1 and %d", MaxAuditEventsTake))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// ExportAuditEventsReq export audit events request struct, empty filters match every event
+type ExportAuditEventsReq struct {
+	Type    string
+	Outcome string
+	UserID  string
+	ActorID string
+	From    *time.Time
+	To      *time.Time
+}
+
+// Validate checks that a given ExportAuditEventsReq is valid
+func (req ExportAuditEventsReq) Validate() error {
+	msgs := validateAuditFilters(req.Outcome, req.From, req.To)
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// validateAuditFilters returns the messages of the audit event filters not being valid
+func validateAuditFilters(outcome string, from, to *time.Time) []string {
+	var msgs []string
+
+	if outcome != "" && outcome != entities.AuditOutcomeSuccess && outcome != entities.AuditOutcomeFailure {
+		msgs = append(msgs, fmt.Sprintf("outcome must be %s or %s", entities.AuditOutcomeSuccess, entities.AuditOutcomeFailure))
+	}
+	if from != nil && to != nil && from.After(*to) {
+		msgs = append(msgs, "from cannot be after to")
+	}
+
+	return msgs
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateGetAuditEventsReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateGetAuditEventsReq_Ok(t *testing.T) {
+	// Arrange
+	req := GetAuditEventsReq{
+		Type: "login_failed",
+		Take: 10,
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateGetAuditEventsReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateGetAuditEventsReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	from := time.Now()
+	to := from.Add(-time.Hour)
+	req := GetAuditEventsReq{
+		Outcome: "unknown",
+		From:    &from,
+		To:      &to,
+		Skip:    -1,
+		Take:    MaxAuditEventsTake + 1,
+	}
+	expectedError := "outcome must be success or failure | from cannot be after to | skip cannot be negative | take must be between 1 and 1000"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateExportAuditEventsReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateExportAuditEventsReq_Ok(t *testing.T) {
+	// Arrange
+	req := ExportAuditEventsReq{
+		Type:    "login_failed",
+		Outcome: "failure",
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateExportAuditEventsReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateExportAuditEventsReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	from := time.Now()
+	to := from.Add(-time.Hour)
+	req := ExportAuditEventsReq{
+		Outcome: "unknown",
+		From:    &from,
+		To:      &to,
+	}
+	expectedError := "outcome must be success or failure | from cannot be after to"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
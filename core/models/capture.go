@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// MaxCapturesTake is the maximum number of captures returned at once
+const MaxCapturesTake = 1000
+
+// CaptureModeReq capture mode request struct, enabling the debug capture mode for a percentage of the requests during a duration, like 15m
+type CaptureModeReq struct {
+	Percentage float64 `json:"percentage"`
+	Duration   string  `json:"duration"`
+}
+
+// Validate checks that a given CaptureModeReq is valid, up to the maximum duration
+func (req CaptureModeReq) Validate(maxDuration time.Duration) error {
+	var msgs []string
+
+	if req.Percentage <= 0 || req.Percentage > 100 {
+		msgs = append(msgs, "percentage must be greater than 0 and up to 100")
+	}
+	if d, err := time.ParseDuration(req.Duration); err != nil || d <= 0 || d > maxDuration {
+		msgs = append(msgs, fmt.Sprintf("duration must be a positive duration up to %s, like 15m", maxDuration))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// CaptureModeResp capture mode response struct
+type CaptureModeResp struct {
+	Enabled    bool       `json:"enabled"`
+	Percentage float64    `json:"percentage,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// CaptureReq capture request struct, the sanitized request and response of a request captured in debug capture mode
+type CaptureReq struct {
+	RequestID    string
+	ActorID      string
+	Method       string
+	Path         string
+	Query        string
+	Status       int
+	RequestBody  string
+	ResponseBody string
+}
+
+// CaptureResp capture response struct
+type CaptureResp struct {
+	ID           string    `json:"id"`
+	RequestID    string    `json:"request_id"`
+	ActorID      string    `json:"actor_id,omitempty"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	Query        string    `json:"query,omitempty"`
+	Status       int       `json:"status"`
+	RequestBody  string    `json:"request_body,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// GetCapturesReq get captures request struct
+type GetCapturesReq struct {
+	Skip int
+	Take int
+}
+
+// Validate checks that a given GetCapturesReq is valid
+func (req GetCapturesReq) Validate() error {
+	var msgs []string
+
+	if req.Skip < 0 {
+		msgs = append(msgs, "skip cannot be negative")
+	}
+	if req.Take < 1 || req.Take > MaxCapturesTake {
+		msgs = append(msgs, fmt.Sprintf("take must be between 1 and %d", MaxCapturesTake))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
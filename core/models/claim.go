@@ -0,0 +1,16 @@
+package models
+
+// ClaimResp claim response struct, describing a claim of the users for the front-ends to build their permission pickers with
+type ClaimResp struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	Deprecated  bool   `json:"deprecated"`
+}
+
+// GetClaimsReq get claims request struct, empty filters match every claim
+type GetClaimsReq struct {
+	Category   string
+	Deprecated *bool
+}
@@ -9,3 +9,13 @@ type CreationResp struct {
 type MultiCreationResp struct {
 	InsertedIDs []string `json:"inserted_ids"`
 }
+
+// ArchivalResp archival response struct
+type ArchivalResp struct {
+	ArchivedCount int64 `json:"archived_count"`
+}
+
+// UpsertionResp upsertion response struct
+type UpsertionResp struct {
+	ID string `json:"id"`
+}
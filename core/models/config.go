@@ -0,0 +1,7 @@
+package models
+
+// ConfigResp config response struct
+type ConfigResp struct {
+	Config   map[string]interface{} `json:"config"`
+	Features map[string]bool        `json:"features"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// DashboardResp admin dashboard response struct, the figures being aggregated at ComputedAt:
+// the signups since midnight UTC, the logins succeeded within the token lifetime, the failed logins of the last hour,
+// the failed deliveries of the jobs posting to the webhooks and the jobs waiting to be run
+type DashboardResp struct {
+	Users                     UserStatsResp `json:"users"`
+	SignupsToday              int64         `json:"signups_today"`
+	LoginsWithinTokenLifetime int64         `json:"logins_within_token_lifetime"`
+	FailedLoginsLastHour      int64         `json:"failed_logins_last_hour"`
+	WebhookFailures           int64         `json:"webhook_failures"`
+	JobBacklog                int64         `json:"job_backlog"`
+	ComputedAt                time.Time     `json:"computed_at"`
+}
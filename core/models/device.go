@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// maxDeviceTokenLength is the length of the longest device token accepted, the FCM ones being the longest, of about 160 characters
+const maxDeviceTokenLength = 4096
+
+// RegisterDeviceReq register device request struct
+type RegisterDeviceReq struct {
+	Platform string `json:"platform" enums:"fcm,apns"`
+	Token    string `json:"token"`
+	Name     string `json:"name"`
+}
+
+// Validate checks that a given RegisterDeviceReq is valid
+func (req RegisterDeviceReq) Validate() error {
+	var msgs []string
+
+	if req.Platform != entities.DevicePlatformFCM && req.Platform != entities.DevicePlatformAPNs {
+		msgs = append(msgs, "platform must be fcm or apns")
+	}
+	if req.Token == "" {
+		msgs = append(msgs, "token cannot be empty")
+	} else if len(req.Token) > maxDeviceTokenLength {
+		msgs = append(msgs, fmt.Sprintf("token cannot be longer than %d characters", maxDeviceTokenLength))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// DeviceResp device response struct, without its token
+type DeviceResp struct {
+	ID        string    `json:"id"`
+	Platform  string    `json:"platform"`
+	Name      string    `json:"name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewDeviceResp returns the response of a device
+func NewDeviceResp(device entities.Device) DeviceResp {
+	return DeviceResp{
+		ID:        device.ID,
+		Platform:  device.Platform,
+		Name:      device.Name,
+		CreatedAt: device.CreatedAt,
+		UpdatedAt: device.UpdatedAt,
+	}
+}
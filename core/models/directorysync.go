@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// DirectorySyncReportResp directory sync report response struct, listing the changes made by a sync or, in dry run, that would be made
+type DirectorySyncReportResp struct {
+	JobID       string                    `json:"job_id"`
+	DryRun      bool                      `json:"dry_run"`
+	Created     []DirectorySyncChangeResp `json:"created"`
+	Updated     []DirectorySyncChangeResp `json:"updated"`
+	Deactivated []DirectorySyncChangeResp `json:"deactivated"`
+	Unchanged   int                       `json:"unchanged"`
+	Skipped     int                       `json:"skipped"`
+	GeneratedAt time.Time                 `json:"generated_at"`
+}
+
+// DirectorySyncChangeResp directory sync change response struct of a user, with the fields updated when it is an update
+type DirectorySyncChangeResp struct {
+	ID       string   `json:"id,omitempty"`
+	Email    string   `json:"email"`
+	Name     string   `json:"name"`
+	Surnames string   `json:"surnames"`
+	Fields   []string `json:"fields,omitempty"`
+}
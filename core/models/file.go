@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// FileResp file response struct
+type FileResp struct {
+	Key         string            `json:"key"`
+	Name        string            `json:"name"`
+	ContentType string            `json:"content_type"`
+	Size        int64             `json:"size"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// UploadAvatarReq upload avatar request struct
+type UploadAvatarReq struct {
+	Name        string
+	ContentType string
+	Content     io.Reader
+}
+
+// Validate checks that a given UploadAvatarReq is valid
+func (req UploadAvatarReq) Validate() error {
+	var msgs []string
+
+	if req.Content == nil {
+		msgs = append(msgs, "file cannot be empty")
+	}
+	if !strings.HasPrefix(req.ContentType, "image/") {
+		msgs = append(msgs, "file must be an image")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// AvatarUploadURLReq avatar upload URL request struct
+type AvatarUploadURLReq struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// Validate checks that a given AvatarUploadURLReq is valid
+func (req AvatarUploadURLReq) Validate() error {
+	var msgs []string
+
+	if req.Size <= 0 {
+		msgs = append(msgs, "size must be greater than 0")
+	}
+	if !strings.HasPrefix(req.ContentType, "image/") {
+		msgs = append(msgs, "file must be an image")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// PresignedURLResp presigned URL response struct, the headers having to be sent with the request to the URL
+type PresignedURLResp struct {
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateUploadAvatarReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateUploadAvatarReq_Ok(t *testing.T) {
+	// Arrange
+	req := UploadAvatarReq{
+		ContentType: "image/png",
+		Content:     strings.NewReader("test"),
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateUploadAvatarReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateUploadAvatarReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := UploadAvatarReq{}
+	expectedError := "file cannot be empty | file must be an image"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
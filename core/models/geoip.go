@@ -0,0 +1,7 @@
+package models
+
+// GeoLocation location of an IP, with the ISO 3166-1 alpha-2 code of its country and the English name of its city, empty when not known
+type GeoLocation struct {
+	Country string
+	City    string
+}
@@ -0,0 +1,43 @@
+package models
+
+// statuses of the health checks and of the readiness
+const (
+	HealthStatusUp       = "up"
+	HealthStatusDegraded = "degraded"
+	HealthStatusDown     = "down"
+)
+
+// statuses of the API reported by its public status
+const (
+	ServiceStatusOK       = "ok"
+	ServiceStatusDegraded = "degraded"
+)
+
+// ReadinessResp readiness response struct
+type ReadinessResp struct {
+	Status string                     `json:"status" enums:"up,degraded,down"`
+	Checks map[string]HealthCheckResp `json:"checks"`
+}
+
+// HealthCheckResp health check response struct
+type HealthCheckResp struct {
+	Status    string  `json:"status" enums:"up,down"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// StatusResp public status response struct
+type StatusResp struct {
+	Status        string `json:"status" enums:"ok,degraded"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+	Version       string `json:"version"`
+}
+
+// VersionResp version response struct, with the metadata of the build serving the request
+type VersionResp struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+	GoVersion string `json:"go_version"`
+}
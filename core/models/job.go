@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// MaxJobsTake is the maximum number of jobs returned at once
+const MaxJobsTake = 1000
+
+// JobResp job response struct
+type JobResp struct {
+	ID          string             `json:"id"`
+	Type        string             `json:"type"`
+	Status      entities.JobStatus `json:"status" swaggertype:"string" enums:"queued,running,succeeded,failed,dead"`
+	Processed   int64              `json:"processed"`
+	Metadata    map[string]string  `json:"metadata,omitempty"`
+	Error       string             `json:"error,omitempty"`
+	Attempts    int                `json:"attempts"`
+	MaxAttempts int                `json:"max_attempts"`
+	RunAt       time.Time          `json:"run_at"`
+	Worker      string             `json:"worker,omitempty"`
+	LockedUntil *time.Time         `json:"locked_until,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	FinishedAt  *time.Time         `json:"finished_at,omitempty"`
+}
+
+// GetJobsReq get jobs request struct, empty filters match every job
+type GetJobsReq struct {
+	Status string
+	Type   string
+	Skip   int
+	Take   int
+}
+
+// Validate checks that a given GetJobsReq is valid
+func (req GetJobsReq) Validate() error {
+	var msgs []string
+
+	switch entities.JobStatus(req.Status) {
+	case "", entities.JobStatusQueued, entities.JobStatusRunning, entities.JobStatusSucceeded, entities.JobStatusFailed, entities.JobStatusDead:
+	default:
+		msgs = append(msgs, fmt.Sprintf("status %s not valid, valid statuses: queued, running, succeeded, failed, dead", req.Status))
+	}
+	if req.Skip < 0 {
+		msgs = append(msgs, "skip cannot be negative")
+	}
+	if req.Take < 1 || req.Take > MaxJobsTake {
+		msgs = append(msgs, fmt.Sprintf("take must be between 1 and %d", MaxJobsTake))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
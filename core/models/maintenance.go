@@ -0,0 +1,7 @@
+package models
+
+// MaintenanceTaskResp maintenance task response struct
+type MaintenanceTaskResp struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
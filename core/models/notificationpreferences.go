@@ -0,0 +1,64 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// NotificationChannelsResp toggles of the channels of a category response struct
+type NotificationChannelsResp struct {
+	Email bool `json:"email"`
+	SMS   bool `json:"sms"`
+	Push  bool `json:"push"`
+}
+
+// NotificationPreferencesResp notification preferences response struct, holding every category, mapped from the stored preferences by the mapping package
+type NotificationPreferencesResp struct {
+	UserID     string                              `json:"user_id"`
+	Categories map[string]NotificationChannelsResp `json:"categories"`
+	UpdatedAt  *time.Time                          `json:"updated_at,omitempty"`
+}
+
+// UpdateNotificationChannelsReq update toggles of the channels of a category request struct, whose fields not set are left as they are
+type UpdateNotificationChannelsReq struct {
+	Email *bool `json:"email"`
+	SMS   *bool `json:"sms"`
+	Push  *bool `json:"push"`
+}
+
+// UpdateNotificationPreferencesReq update notification preferences request struct, whose categories not set are left as they are
+type UpdateNotificationPreferencesReq struct {
+	Categories map[string]UpdateNotificationChannelsReq `json:"categories"`
+}
+
+// Validate checks that a given UpdateNotificationPreferencesReq is valid
+func (req UpdateNotificationPreferencesReq) Validate() error {
+	var errs apierror.ValidationError
+
+	names := make([]string, 0, len(req.Categories))
+	for name := range req.Categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !isNotificationCategory(name) {
+			errs.Add("categories", fmt.Sprintf("category %s is not a notification category", name))
+		}
+	}
+
+	return errs.Err()
+}
+
+// isNotificationCategory reports whether the name is the one of a category of the notifications
+func isNotificationCategory(name string) bool {
+	for _, category := range entities.NotificationCategories {
+		if category == name {
+			return true
+		}
+	}
+	return false
+}
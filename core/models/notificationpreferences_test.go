@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateUpdateNotificationPreferencesReq_Ok checks that Validate accepts the known categories
+func TestValidateUpdateNotificationPreferencesReq_Ok(t *testing.T) {
+	// Arrange
+	disabled := false
+	req := UpdateNotificationPreferencesReq{Categories: map[string]UpdateNotificationChannelsReq{entities.NotificationCategorySecurity: {Push: &disabled}}}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateUpdateNotificationPreferencesReq_InvalidRequest checks that Validate returns an error for every unknown category, sorted by name
+func TestValidateUpdateNotificationPreferencesReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := UpdateNotificationPreferencesReq{Categories: map[string]UpdateNotificationChannelsReq{"newsletter": {}, "marketing": {}}}
+	expectedError := "category marketing is not a notification category | category newsletter is not a notification category"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrValidation)
+	assert.Equal(t, expectedError, err.Error())
+}
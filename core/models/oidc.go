@@ -0,0 +1,74 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// TokenLifetime lifetime of the tokens signed at login, unless the security policy of the tenant of the user sets a shorter one
+const TokenLifetime = 168 * time.Hour
+
+type tokenLifetimeKey struct{}
+
+// WithTokenLifetime returns a copy of the context signing the tokens of the logins with the given lifetime instead of TokenLifetime
+func WithTokenLifetime(ctx context.Context, lifetime time.Duration) context.Context {
+	return context.WithValue(ctx, tokenLifetimeKey{}, lifetime)
+}
+
+// TokenLifetimeFrom returns the lifetime of the tokens signed at login with the context, TokenLifetime when not set
+func TokenLifetimeFrom(ctx context.Context) time.Duration {
+	if lifetime, ok := ctx.Value(tokenLifetimeKey{}).(time.Duration); ok {
+		return lifetime
+	}
+	return TokenLifetime
+}
+
+// OAuth grant types accepted by the token endpoint
+const (
+	GrantTypePassword = "password"
+)
+
+// OAuth errors of the token endpoint, as named by RFC 6749
+const (
+	OAuthErrorInvalidRequest       = "invalid_request"
+	OAuthErrorInvalidGrant         = "invalid_grant"
+	OAuthErrorUnsupportedGrantType = "unsupported_grant_type"
+)
+
+// OpenIDConfigurationResp OpenID Connect discovery document response struct
+type OpenIDConfigurationResp struct {
+	Issuer                            string   `json:"issuer"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// JWKSResp JSON Web Key Set response struct
+type JWKSResp struct {
+	Keys []JWKResp `json:"keys"`
+}
+
+// JWKResp JSON Web Key response struct
+type JWKResp struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+}
+
+// TokenResp OAuth token response struct
+type TokenResp struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// OAuthErrorResp OAuth error response struct
+type OAuthErrorResp struct {
+	Error            string `json:"error" enums:"invalid_request,invalid_grant,unsupported_grant_type"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
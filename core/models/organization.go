@@ -0,0 +1,139 @@
+package models
+
+import (
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// invalidMemberRoleMsg is the validation message of a role that cannot be granted to a member
+const invalidMemberRoleMsg = "role must be admin or member"
+
+// Caller the user calling an operation scoped by the organizations, from the claims of its token.
+// The admins of the API are allowed in every organization, as its owner.
+// The ID of the API key is set when the user calls with one of its API keys instead of a token.
+type Caller struct {
+	UserID   string
+	Admin    bool
+	APIKeyID string
+}
+
+// OrganizationResp organization response struct, mapped from the stored organization by the mapping package,
+// with the role of the caller in it, empty when not a member
+type OrganizationResp struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	OwnerID      string    `json:"owner_id"`
+	Role         string    `json:"role,omitempty" enums:"owner,admin,member"`
+	MembersCount int       `json:"members_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CreateOrganizationReq organization request struct, the caller creating it being its owner
+type CreateOrganizationReq struct {
+	Name string `json:"name"`
+}
+
+// Validate checks that a given CreateOrganizationReq is valid
+func (req CreateOrganizationReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if req.Name == "" {
+		errs.Add("name", "name cannot be empty")
+	}
+
+	return errs.Err()
+}
+
+// UpdateOrganizationReq update organization request struct, whose fields not set are left as they are
+type UpdateOrganizationReq struct {
+	Name *string `json:"name"`
+}
+
+// Validate checks that a given UpdateOrganizationReq is valid
+func (req UpdateOrganizationReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if req.Name != nil && *req.Name == "" {
+		errs.Add("name", "name cannot be empty")
+	}
+
+	return errs.Err()
+}
+
+// OrganizationMemberResp organization member response struct, with the user member
+type OrganizationMemberResp struct {
+	User     UserResp  `json:"user"`
+	Role     string    `json:"role" enums:"owner,admin,member"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// InviteMemberReq invite member request struct, inviting the user with the email to the organization with the role,
+// the user being a member once it accepts
+type InviteMemberReq struct {
+	Email string `json:"email"`
+	Role  string `json:"role" enums:"admin,member"`
+}
+
+// Validate checks that a given InviteMemberReq is valid
+func (req InviteMemberReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if req.Email == "" {
+		errs.Add("email", "email cannot be empty")
+	}
+	if !isMemberRole(req.Role) {
+		errs.Add("role", invalidMemberRoleMsg)
+	}
+
+	return errs.Err()
+}
+
+// UpdateMemberReq update member request struct, changing the role of the member
+type UpdateMemberReq struct {
+	Role string `json:"role" enums:"admin,member"`
+}
+
+// Validate checks that a given UpdateMemberReq is valid
+func (req UpdateMemberReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if !isMemberRole(req.Role) {
+		errs.Add("role", invalidMemberRoleMsg)
+	}
+
+	return errs.Err()
+}
+
+// TransferOwnershipReq transfer ownership request struct, the member becoming the owner of the organization
+type TransferOwnershipReq struct {
+	UserID string `json:"user_id"`
+}
+
+// Validate checks that a given TransferOwnershipReq is valid
+func (req TransferOwnershipReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if req.UserID == "" {
+		errs.Add("user_id", "user_id cannot be empty")
+	}
+
+	return errs.Err()
+}
+
+// OrganizationInvitationResp organization invitation response struct, a pending invitation of a user to an organization
+type OrganizationInvitationResp struct {
+	OrganizationID   string    `json:"organization_id"`
+	OrganizationName string    `json:"organization_name"`
+	UserID           string    `json:"user_id"`
+	Role             string    `json:"role" enums:"admin,member"`
+	InvitedBy        string    `json:"invited_by"`
+	InvitedAt        time.Time `json:"invited_at"`
+}
+
+// isMemberRole returns whether the role can be granted to a member, the owner one being only granted by a transfer of the ownership
+func isMemberRole(role string) bool {
+	return role == entities.OrganizationRoleAdmin || role == entities.OrganizationRoleMember
+}
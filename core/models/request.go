@@ -0,0 +1,25 @@
+package models
+
+import "context"
+
+type requestInfoKey struct{}
+
+// RequestInfo identifies the request an operation runs for, the actor being its authenticated user, if any,
+// and the user agent the client it was sent from
+type RequestInfo struct {
+	RequestID string
+	ActorID   string
+	IP        string
+	UserAgent string
+}
+
+// WithRequestInfo returns a copy of the context carrying the given request info
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the request info carried by the context, empty when the operation does not run for a request
+func RequestInfoFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
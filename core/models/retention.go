@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// RetentionResp retention response struct, reporting what every policy did or, in dry run, would do
+type RetentionResp struct {
+	DryRun  bool                  `json:"dry_run"`
+	Results []RetentionResultResp `json:"results"`
+}
+
+// RetentionResultResp retention result response struct of a policy
+type RetentionResultResp struct {
+	Collection string                   `json:"collection"`
+	Action     entities.RetentionAction `json:"action" swaggertype:"string" enums:"purge,anonymize"`
+	Before     time.Time                `json:"before"`
+	Count      int64                    `json:"count"`
+	Error      string                   `json:"error,omitempty"`
+}
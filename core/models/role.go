@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// RoleResp role response struct, mapped from the stored role by the mapping package
+type RoleResp struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	Permissions     []string  `json:"permissions"`
+	UserIDs         []string  `json:"user_ids"`
+	OrganizationIDs []string  `json:"organization_ids"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CreateRoleReq role request struct, whose permissions are the names of the claims granted by the role
+type CreateRoleReq struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// Validate checks that a given CreateRoleReq is valid
+func (req CreateRoleReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if req.Name == "" {
+		errs.Add("name", "name cannot be empty")
+	}
+	validateClaimNames(&errs, "permissions", "permission", req.Permissions)
+
+	return errs.Err()
+}
+
+// UpdateRoleReq update role request struct, whose fields not set are left as they are
+type UpdateRoleReq struct {
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	Permissions *[]string `json:"permissions"`
+}
+
+// Validate checks that a given UpdateRoleReq is valid
+func (req UpdateRoleReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if req.Name != nil && *req.Name == "" {
+		errs.Add("name", "name cannot be empty")
+	}
+	if req.Permissions != nil {
+		validateClaimNames(&errs, "permissions", "permission", *req.Permissions)
+	}
+
+	return errs.Err()
+}
+
+// validateClaimNames adds an error to the field for every name that is not the name of a claim, or is of a deprecated one, which can no longer be granted
+func validateClaimNames(errs *apierror.ValidationError, field, noun string, names []string) {
+	definitions := entities.GetUserClaimDefinitions()
+	for _, name := range names {
+		valid := false
+		for _, definition := range definitions {
+			if definition.Name == name && !definition.Deprecated {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			errs.Add(field, fmt.Sprintf("%s %s is not a claim that can be granted", noun, name))
+		}
+	}
+}
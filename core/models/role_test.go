@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateCreateRoleReq_Ok checks that Validate does not return an error when the permissions are names of claims
+func TestValidateCreateRoleReq_Ok(t *testing.T) {
+	// Arrange
+	req := CreateRoleReq{Name: "test-role", Permissions: []string{"admin"}}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateUpdateRoleReq_InvalidRequest checks that Validate returns an error when the name is empty or a permission is not the name of a claim
+func TestValidateUpdateRoleReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	name := ""
+	permissions := []string{"unknown"}
+	req := UpdateRoleReq{Name: &name, Permissions: &permissions}
+	expectedError := "name cannot be empty | permission unknown is not a claim that can be granted"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrValidation)
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ScheduledJobResp scheduled job response struct, with the result of its last run and the count of the runs skipped
+// because the previous one was still running
+type ScheduledJobResp struct {
+	Name      string            `json:"name"`
+	Schedule  string            `json:"schedule"`
+	Enabled   bool              `json:"enabled"`
+	Running   bool              `json:"running"`
+	NextRunAt *time.Time        `json:"next_run_at,omitempty"`
+	Overlaps  int64             `json:"overlaps"`
+	LastRun   *ScheduledRunResp `json:"last_run,omitempty"`
+}
+
+// ScheduledRunResp scheduled run response struct, skipped when the run was left to the leader
+type ScheduledRunResp struct {
+	Status     string    `json:"status" enums:"succeeded,failed,skipped"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error,omitempty"`
+}
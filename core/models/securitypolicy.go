@@ -0,0 +1,83 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+)
+
+// maxPasswordMinLength the longest minimum length of the passwords, bcrypt hashing only their first 72 bytes
+const maxPasswordMinLength = 72
+
+// PasswordPolicyResp password policy response struct
+type PasswordPolicyResp struct {
+	MinLength        int  `json:"min_length"`
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireDigit     bool `json:"require_digit"`
+	RequireSymbol    bool `json:"require_symbol"`
+}
+
+// SecurityPolicyResp security policy response struct, the durations being formatted like 24h0m0s and empty when applying the ones of the config
+type SecurityPolicyResp struct {
+	Tenant             string             `json:"tenant"`
+	Password           PasswordPolicyResp `json:"password"`
+	SessionLifetime    string             `json:"session_lifetime,omitempty"`
+	MFARequired        bool               `json:"mfa_required"`
+	LockoutMaxFailures int64              `json:"lockout_max_failures,omitempty"`
+	LockoutDuration    string             `json:"lockout_duration,omitempty"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
+
+// PasswordPolicyReq password policy request struct
+type PasswordPolicyReq struct {
+	MinLength        int  `json:"min_length"`
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireDigit     bool `json:"require_digit"`
+	RequireSymbol    bool `json:"require_symbol"`
+}
+
+// PutSecurityPolicyReq put security policy request struct, replacing the policy of the tenant.
+// The durations are like 24h, and the settings left empty apply the ones of the config, the lockout ones being set together.
+type PutSecurityPolicyReq struct {
+	Tenant             string            `json:"-"`
+	Password           PasswordPolicyReq `json:"password"`
+	SessionLifetime    string            `json:"session_lifetime"`
+	MFARequired        bool              `json:"mfa_required"`
+	LockoutMaxFailures int64             `json:"lockout_max_failures"`
+	LockoutDuration    string            `json:"lockout_duration"`
+}
+
+// Validate checks that a given PutSecurityPolicyReq is valid
+func (req PutSecurityPolicyReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if req.Tenant == "" || strings.Contains(req.Tenant, "@") {
+		errs.Add("tenant", "tenant must be an email domain, like example.com")
+	}
+	if req.Password.MinLength < 0 || req.Password.MinLength > maxPasswordMinLength {
+		errs.Add("password.min_length", fmt.Sprintf("password.min_length must be between 0 and %d", maxPasswordMinLength))
+	}
+	if req.SessionLifetime != "" {
+		if d, err := time.ParseDuration(req.SessionLifetime); err != nil || d <= 0 || d > TokenLifetime {
+			errs.Add("session_lifetime", fmt.Sprintf("session_lifetime must be a positive duration up to %s, like 24h", TokenLifetime))
+		}
+	}
+	if req.LockoutMaxFailures < 0 {
+		errs.Add("lockout_max_failures", "lockout_max_failures cannot be negative")
+	}
+	if req.LockoutDuration != "" {
+		if d, err := time.ParseDuration(req.LockoutDuration); err != nil || d <= 0 {
+			errs.Add("lockout_duration", "lockout_duration must be a positive duration, like 15m")
+		}
+	}
+	if (req.LockoutMaxFailures > 0) != (req.LockoutDuration != "") {
+		errs.Add("lockout_duration", "lockout_max_failures and lockout_duration must be set together")
+	}
+
+	return errs.Err()
+}
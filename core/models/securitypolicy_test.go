@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidatePutSecurityPolicyReq_Ok checks that Validate accepts a policy setting every setting
+func TestValidatePutSecurityPolicyReq_Ok(t *testing.T) {
+	// Arrange
+	req := PutSecurityPolicyReq{
+		Tenant:             "example.com",
+		Password:           PasswordPolicyReq{MinLength: 12, RequireDigit: true},
+		SessionLifetime:    "8h",
+		MFARequired:        true,
+		LockoutMaxFailures: 3,
+		LockoutDuration:    "1h",
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidatePutSecurityPolicyReq_InvalidRequest checks that Validate returns an error for every setting not valid
+func TestValidatePutSecurityPolicyReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := PutSecurityPolicyReq{
+		Tenant:             "user@example.com",
+		Password:           PasswordPolicyReq{MinLength: 100},
+		SessionLifetime:    "720h",
+		LockoutMaxFailures: 3,
+	}
+	expectedFields := []apierror.FieldError{
+		{Field: "tenant", Message: "tenant must be an email domain, like example.com"},
+		{Field: "password.min_length", Message: "password.min_length must be between 0 and 72"},
+		{Field: "session_lifetime", Message: "session_lifetime must be a positive duration up to 168h0m0s, like 24h"},
+		{Field: "lockout_duration", Message: "lockout_max_failures and lockout_duration must be set together"},
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	var validationErr *apierror.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, expectedFields, validationErr.Fields)
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// purposes of the one-time codes sent by text message
+const (
+	SMSPurposeOTP               = "otp"
+	SMSPurposePhoneVerification = "phone_verification"
+)
+
+// phonePattern matches the phone numbers in E.164 format, like +34600000000
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// SendSMSCodeReq send SMS code request struct
+type SendSMSCodeReq struct {
+	Phone   string `json:"phone"`
+	Purpose string `json:"purpose" enums:"otp,phone_verification"`
+	// ID of the user the code is sent to, the caller by default
+	UserID string `json:"user_id,omitempty"`
+}
+
+// Validate checks that a given SendSMSCodeReq is valid
+func (req SendSMSCodeReq) Validate() error {
+	msgs := validateSMSCode(req.Phone, req.Purpose)
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// CheckSMSCodeReq check SMS code request struct
+type CheckSMSCodeReq struct {
+	Phone   string `json:"phone"`
+	Purpose string `json:"purpose" enums:"otp,phone_verification"`
+	Code    string `json:"code"`
+}
+
+// Validate checks that a given CheckSMSCodeReq is valid
+func (req CheckSMSCodeReq) Validate() error {
+	msgs := validateSMSCode(req.Phone, req.Purpose)
+	if req.Code == "" {
+		msgs = append(msgs, "code cannot be empty")
+	}
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// validateSMSCode returns the problems of the phone and the purpose of a code
+func validateSMSCode(phone, purpose string) []string {
+	var msgs []string
+	if !phonePattern.MatchString(phone) {
+		msgs = append(msgs, "phone must be in E.164 format, like +34600000000")
+	}
+	if purpose != SMSPurposeOTP && purpose != SMSPurposePhoneVerification {
+		msgs = append(msgs, "purpose must be otp or phone_verification")
+	}
+	return msgs
+}
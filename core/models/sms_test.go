@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateSendSMSCodeReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateSendSMSCodeReq_Ok(t *testing.T) {
+	// Arrange
+	req := SendSMSCodeReq{Phone: "+34600000000", Purpose: SMSPurposePhoneVerification}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateCheckSMSCodeReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateCheckSMSCodeReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := CheckSMSCodeReq{Phone: "600 00 00 00", Purpose: "login"}
+	expectedError := "phone must be in E.164 format, like +34600000000 | purpose must be otp or phone_verification | code cannot be empty"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
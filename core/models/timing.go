@@ -0,0 +1,57 @@
+package models
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type requestTimingsKey struct{}
+
+// RequestTimings time spent by a request in the database and hashing passwords, accumulated by the operations run for it,
+// possibly concurrently. The methods of a nil RequestTimings do nothing, so operations not run for a request are not timed.
+type RequestTimings struct {
+	database atomic.Int64
+	hashing  atomic.Int64
+}
+
+// WithRequestTimings returns a copy of the context carrying the given request timings
+func WithRequestTimings(ctx context.Context, timings *RequestTimings) context.Context {
+	return context.WithValue(ctx, requestTimingsKey{}, timings)
+}
+
+// RequestTimingsFrom returns the request timings carried by the context, nil when the operation does not run for a request
+func RequestTimingsFrom(ctx context.Context) *RequestTimings {
+	timings, _ := ctx.Value(requestTimingsKey{}).(*RequestTimings)
+	return timings
+}
+
+// AddDatabase adds the duration of a database command
+func (t *RequestTimings) AddDatabase(d time.Duration) {
+	if t != nil {
+		t.database.Add(int64(d))
+	}
+}
+
+// AddHashing adds the duration of a password hash or comparison
+func (t *RequestTimings) AddHashing(d time.Duration) {
+	if t != nil {
+		t.hashing.Add(int64(d))
+	}
+}
+
+// Database returns the time spent in the database
+func (t *RequestTimings) Database() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return time.Duration(t.database.Load())
+}
+
+// Hashing returns the time spent hashing passwords
+func (t *RequestTimings) Hashing() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return time.Duration(t.hashing.Load())
+}
@@ -2,82 +2,126 @@ package models
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
-	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
 )
 
-// UserResp user response struct
+const (
+	// SearchModeFuzzy matches whole words tolerating typos
+	SearchModeFuzzy = "fuzzy"
+	// SearchModeAutocomplete matches words starting with the query
+	SearchModeAutocomplete = "autocomplete"
+)
+
+// GeoPoint GeoJSON point, with coordinates in [longitude, latitude] order
+type GeoPoint = entities.GeoPoint
+
+// invalidLocationMsg is the validation message of a location that is not a valid GeoJSON point
+const invalidLocationMsg = "location must be a GeoJSON Point with [longitude, latitude] coordinates"
+
+// UserResp user response struct, mapped from the stored user by the mapping package
 type UserResp struct {
-	ID           string    `json:"id"`
-	Name         string    `json:"name"`
-	Surnames     string    `json:"surnames"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	Claims       []int64   `json:"claims"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Surnames    string     `json:"surnames"`
+	Email       string     `json:"email"`
+	Claims      []int64    `json:"claims" enums:"0,1"`
+	Location    *GeoPoint  `json:"location,omitempty"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	BillingCustomerID  string `json:"billing_customer_id,omitempty"`
+	SubscriptionStatus string `json:"subscription_status,omitempty"`
 }
 
-// CreateUserReq user request struct
+// CreateUserReq user request struct, mapped to the user to store by the mapping package
 type CreateUserReq struct {
-	ID           string    `json:"-"`
-	Name         string    `json:"name"`
-	Surnames     string    `json:"surnames"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"password"`
-	Claims       []int64   `json:"claims"`
-	CreatedAt    time.Time `json:"-"`
-	UpdatedAt    time.Time `json:"-"`
+	Name     string    `json:"name"`
+	Surnames string    `json:"surnames"`
+	Email    string    `json:"email"`
+	Password string    `json:"password"`
+	Claims   []int64   `json:"claims" enums:"0,1"`
+	Location *GeoPoint `json:"location"`
 }
 
 // Validate checks that a given CreateUserReq is valid
 func (req CreateUserReq) Validate() error {
-	var msgs []string
+	var errs apierror.ValidationError
 
 	if req.Email == "" {
-		msgs = append(msgs, "email cannot be empty")
+		errs.Add("email", "email cannot be empty")
+	}
+	if req.Password == "" {
+		errs.Add("password", "password cannot be empty")
 	}
-	if req.PasswordHash == "" {
-		msgs = append(msgs, "password cannot be empty")
+	if req.Location != nil && !req.Location.IsValid() {
+		errs.Add("location", invalidLocationMsg)
 	}
 
-	if len(msgs) > 0 {
-		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	return errs.Err()
+}
+
+// UpsertUserReq upsert user request struct
+type UpsertUserReq struct {
+	Name     string    `json:"name"`
+	Surnames string    `json:"surnames"`
+	Email    string    `json:"-"`
+	Password string    `json:"password"`
+	Claims   []int64   `json:"claims" enums:"0,1"`
+	Location *GeoPoint `json:"location"`
+}
+
+// Validate checks that a given UpsertUserReq is valid
+func (req UpsertUserReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if req.Email == "" {
+		errs.Add("email", "email cannot be empty")
+	}
+	if req.Location != nil && !req.Location.IsValid() {
+		errs.Add("location", invalidLocationMsg)
 	}
 
-	return nil
+	return errs.Err()
 }
 
-// LoginUserReq login user request struct
+// LoginUserReq login user request struct, with the one-time code emailed to the user when the security policy of its tenant requires MFA
 type LoginUserReq struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	MFACode  string `json:"mfa_code,omitempty"`
 }
 
 // Validate checks that a given LoginUserReq is valid
 func (req LoginUserReq) Validate() error {
-	var msgs []string
+	var errs apierror.ValidationError
 
 	if req.Email == "" {
-		msgs = append(msgs, "email cannot be empty")
+		errs.Add("email", "email cannot be empty")
 	}
 	if req.Password == "" {
-		msgs = append(msgs, "password cannot be empty")
+		errs.Add("password", "password cannot be empty")
 	}
 
-	if len(msgs) > 0 {
-		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
-	}
+	return errs.Err()
+}
 
-	return nil
+// UserStatsResp user stats response struct, the active users having logged in or been updated during the inactivity period of the archival
+type UserStatsResp struct {
+	Total      int64     `json:"total"`
+	Active     int64     `json:"active"`
+	Archived   int64     `json:"archived"`
+	ComputedAt time.Time `json:"computed_at"`
 }
 
 // LoginUserResp login user response struct
 type LoginUserResp struct {
-	User  UserResp `json:"user"`
-	Token string   `json:"token"`
+	User      UserResp  `json:"user"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 // UpdateUserReq update user request struct
@@ -88,7 +132,118 @@ type UpdateUserReq struct {
 	Email       *string    `json:"email"`
 	OldPassword *string    `json:"old_password"`
 	NewPassword *string    `json:"new_password"`
-	Claims      *[]int64   `json:"claims"`
+	Claims      *[]int64   `json:"claims" enums:"0,1"`
+	Location    *GeoPoint  `json:"location"`
 	CreatedAt   *time.Time `json:"-"`
 	UpdatedAt   *time.Time `json:"-"`
 }
+
+// Validate checks that a given UpdateUserReq is valid
+func (req UpdateUserReq) Validate() error {
+	if req.Location != nil && !req.Location.IsValid() {
+		return apierror.Validation("location", invalidLocationMsg)
+	}
+
+	return nil
+}
+
+// MergeUsersReq merge users request struct
+type MergeUsersReq struct {
+	SourceID string `json:"source_id"`
+}
+
+// Validate checks that a given MergeUsersReq is valid
+func (req MergeUsersReq) Validate() error {
+	if req.SourceID == "" {
+		return apierror.Validation("source_id", "source_id cannot be empty")
+	}
+
+	return nil
+}
+
+// SearchUsersReq search users request struct
+type SearchUsersReq struct {
+	Query string
+	Mode  string
+}
+
+// Validate checks that a given SearchUsersReq is valid
+func (req SearchUsersReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if req.Query == "" {
+		errs.Add("q", "query cannot be empty")
+	}
+	if req.Mode != "" && req.Mode != SearchModeFuzzy && req.Mode != SearchModeAutocomplete {
+		errs.Add("mode", fmt.Sprintf("mode must be %s or %s", SearchModeFuzzy, SearchModeAutocomplete))
+	}
+
+	return errs.Err()
+}
+
+// MaxIndexSearchTake is the maximum number of users returned at once by a search of the index
+const MaxIndexSearchTake = 100
+
+// IndexSearchUsersReq search users in the index request struct, filtered by the domain of the email and by a claim when set
+type IndexSearchUsersReq struct {
+	Query  string
+	Domain string
+	Claim  *int64
+	Skip   int
+	Take   int
+}
+
+// Validate checks that a given IndexSearchUsersReq is valid
+func (req IndexSearchUsersReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if req.Query == "" {
+		errs.Add("q", "query cannot be empty")
+	}
+	if req.Skip < 0 {
+		errs.Add("skip", "skip cannot be negative")
+	}
+	if req.Take < 1 || req.Take > MaxIndexSearchTake {
+		errs.Add("take", fmt.Sprintf("take must be between 1 and %d", MaxIndexSearchTake))
+	}
+
+	return errs.Err()
+}
+
+// FacetResp facet response struct, the count of the matching users with a value
+type FacetResp struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// IndexSearchUsersResp search users in the index response struct, the users of the page by relevance,
+// with the total count of matching users and their counts by email domain and by claim
+type IndexSearchUsersResp struct {
+	Total  int64                  `json:"total"`
+	Users  []UserResp             `json:"users"`
+	Facets map[string][]FacetResp `json:"facets"`
+}
+
+// NearbyUsersReq nearby users request struct
+type NearbyUsersReq struct {
+	Longitude float64
+	Latitude  float64
+	Radius    float64
+}
+
+// Validate checks that a given NearbyUsersReq is valid
+func (req NearbyUsersReq) Validate() error {
+	var errs apierror.ValidationError
+
+	if !entities.IsValidLongitude(req.Longitude) {
+		errs.Add("lng", "lng must be between -180 and 180")
+	}
+	if !entities.IsValidLatitude(req.Latitude) {
+		errs.Add("lat", "lat must be between -90 and 90")
+	}
+	if req.Radius <= 0 {
+		errs.Add("radius", "radius must be greater than 0")
+	}
+
+	return errs.Err()
+}
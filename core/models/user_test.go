@@ -1,9 +1,11 @@
 package models
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 
-	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -11,8 +13,8 @@ import (
 func TestValidateCreateUserReq_Ok(t *testing.T) {
 	// Arrange
 	req := CreateUserReq{
-		Email:        "test@test.com",
-		PasswordHash: "test",
+		Email:    "test@test.com",
+		Password: "test",
 	}
 
 	// Act
@@ -33,7 +35,7 @@ func TestValidateCreateUserReq_InvalidRequest(t *testing.T) {
 
 	// Assert
 	assert.NotEmpty(t, err)
-	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
 	assert.Equal(t, expectedError, err.Error())
 }
 
@@ -63,6 +65,226 @@ func TestValidateLoginUserReq_InvalidRequest(t *testing.T) {
 
 	// Assert
 	assert.NotEmpty(t, err)
-	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateUpsertUserReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateUpsertUserReq_Ok(t *testing.T) {
+	// Arrange
+	req := UpsertUserReq{
+		Email: "test@test.com",
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateUpsertUserReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateUpsertUserReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := UpsertUserReq{}
+	expectedError := "email cannot be empty"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateMergeUsersReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateMergeUsersReq_Ok(t *testing.T) {
+	// Arrange
+	req := MergeUsersReq{
+		SourceID: "test-id",
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateMergeUsersReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateMergeUsersReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := MergeUsersReq{}
+	expectedError := "source_id cannot be empty"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateSearchUsersReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateSearchUsersReq_Ok(t *testing.T) {
+	// Arrange
+	req := SearchUsersReq{
+		Query: "test",
+		Mode:  SearchModeFuzzy,
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateSearchUsersReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateSearchUsersReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := SearchUsersReq{
+		Mode: "invalid",
+	}
+	expectedError := "query cannot be empty | mode must be fuzzy or autocomplete"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
 	assert.Equal(t, expectedError, err.Error())
 }
+
+// TestValidateIndexSearchUsersReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateIndexSearchUsersReq_Ok(t *testing.T) {
+	// Arrange
+	req := IndexSearchUsersReq{
+		Query:  "test",
+		Domain: "example.com",
+		Take:   10,
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateIndexSearchUsersReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateIndexSearchUsersReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := IndexSearchUsersReq{
+		Skip: -1,
+		Take: MaxIndexSearchTake + 1,
+	}
+	expectedError := "query cannot be empty | skip cannot be negative | take must be between 1 and 100"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateNearbyUsersReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateNearbyUsersReq_Ok(t *testing.T) {
+	// Arrange
+	req := NearbyUsersReq{
+		Longitude: 2.17,
+		Latitude:  41.38,
+		Radius:    1000,
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateNearbyUsersReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateNearbyUsersReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := NearbyUsersReq{
+		Longitude: 200,
+		Latitude:  -100,
+	}
+	expectedError := "lng must be between -180 and 180 | lat must be between -90 and 90 | radius must be greater than 0"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateUpdateUserReq_InvalidLocation checks that Validate returns an error when the received location is not valid
+func TestValidateUpdateUserReq_InvalidLocation(t *testing.T) {
+	// Arrange
+	req := UpdateUserReq{
+		Location: &GeoPoint{Type: "Point", Coordinates: []float64{0}},
+	}
+	expectedError := "location must be a GeoJSON Point with [longitude, latitude] coordinates"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// FuzzValidateCreateUserReq checks that any JSON body decoded into a CreateUserReq is validated without panicking,
+// the error returned being a validation error
+func FuzzValidateCreateUserReq(f *testing.F) {
+	f.Add([]byte(`{"email":"test@test.com","password":"test"}`))
+	f.Add([]byte(`{"email":"test@test.com","password":"test","location":{"type":"Point","coordinates":[2.17,41.38]}}`))
+	f.Add([]byte(`{"location":{"type":"Point","coordinates":[]}}`))
+	f.Add([]byte(`{"location":{"type":"Point","coordinates":[1e309]}}`))
+	f.Add([]byte(`{"claims":[9223372036854775807]}`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		var req CreateUserReq
+		if err := json.Unmarshal(body, &req); err != nil {
+			return
+		}
+
+		err := req.Validate()
+
+		if err != nil {
+			assert.True(t, errors.Is(err, apierror.ErrValidation))
+		}
+	})
+}
+
+// FuzzValidateUpdateUserReq checks that any JSON body decoded into an UpdateUserReq is validated without panicking,
+// the error returned being a validation error
+func FuzzValidateUpdateUserReq(f *testing.F) {
+	f.Add([]byte(`{"name":"test","location":{"type":"Point","coordinates":[2.17,41.38]}}`))
+	f.Add([]byte(`{"location":{"type":"Point","coordinates":[0]}}`))
+	f.Add([]byte(`{"location":{"type":"LineString","coordinates":[0,0,0]}}`))
+	f.Add([]byte(`{"location":null,"claims":null}`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		var req UpdateUserReq
+		if err := json.Unmarshal(body, &req); err != nil {
+			return
+		}
+
+		err := req.Validate()
+
+		if err != nil {
+			assert.True(t, errors.Is(err, apierror.ErrValidation))
+		}
+	})
+}
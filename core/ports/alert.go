@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Alert a watched rate starting or stopping to reach its threshold over a rolling window
+type Alert struct {
+	Name      string
+	Firing    bool
+	Value     float64
+	Threshold float64
+	Window    time.Duration
+	At        time.Time
+}
+
+// Notifier interface of a channel the alerts are notified through
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// types of the analytics messages, as named by the Segment spec
+const (
+	AnalyticsIdentify = "identify"
+	AnalyticsTrack    = "track"
+)
+
+// names of the events tracked, as named by the Segment spec
+const (
+	AnalyticsSignedUp       = "Signed Up"
+	AnalyticsSignedIn       = "Signed In"
+	AnalyticsProfileUpdated = "Profile Updated"
+)
+
+// AnalyticsMessage an identify message, setting the traits of a user, or a track message, recording an event of a user with its properties,
+// identified by its ID so the destination discards the ones sent more than once
+type AnalyticsMessage struct {
+	ID         string
+	Type       string
+	UserID     string
+	Event      string
+	Traits     map[string]string
+	Properties map[string]string
+	Timestamp  time.Time
+}
+
+// AnalyticsTracker interface of a product analytics destination the messages are sent to
+type AnalyticsTracker interface {
+	Send(ctx context.Context, message AnalyticsMessage) error
+}
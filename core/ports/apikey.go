@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+)
+
+// APIKeyRepository interface
+type APIKeyRepository interface {
+	repository.Repository
+	// GetByUser returns the API keys of the user, newest first
+	GetByUser(ctx context.Context, userID string) ([]entities.APIKey, error)
+	// GetBySecretHash returns the API key whose secret has the hash, failing with a non existent error when not found
+	GetBySecretHash(ctx context.Context, secretHash string) (entities.APIKey, error)
+	// Rotate replaces the secret of the API key with the specified ID, failing with a non existent error when it is not found or revoked
+	Rotate(ctx context.Context, ID, prefix, secretHash string, at time.Time) error
+	// Revoke revokes the API key with the specified ID, failing with a non existent error when it is not found or revoked already
+	Revoke(ctx context.Context, ID string, at time.Time) error
+	// Touch sets when the API key with the specified ID was last used
+	Touch(ctx context.Context, ID string, at time.Time) error
+	// RevokeByUser revokes every API key of the user not revoked yet
+	RevokeByUser(ctx context.Context, userID string, at time.Time) error
+}
+
+// APIKeyService interface, whose operations on the API keys of a user are allowed to the user itself and to the admins
+type APIKeyService interface {
+	Create(ctx context.Context, caller models.Caller, userID string, req models.CreateAPIKeyReq) (models.APIKeySecretResp, error)
+	GetByUser(ctx context.Context, caller models.Caller, userID string) ([]models.APIKeyResp, error)
+	GetAll(ctx context.Context) ([]models.APIKeyResp, error)
+	Rotate(ctx context.Context, caller models.Caller, userID, ID string) (models.APIKeySecretResp, error)
+	Revoke(ctx context.Context, caller models.Caller, userID, ID string) error
+}
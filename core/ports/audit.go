@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// AuditFilter filter of the audit events, empty fields match every event and the time range includes both ends
+type AuditFilter struct {
+	Type    string
+	Outcome string
+	UserID  string
+	ActorID string
+	From    *time.Time
+	To      *time.Time
+}
+
+// AuditRepository interface
+// Events are immutable, once written they can only be expired by the retention, and are returned newest first, in the order they were written.
+type AuditRepository interface {
+	RetentionRepository
+	Write(ctx context.Context, event entities.AuditEvent) error
+	GetByID(ctx context.Context, ID string) (entities.AuditEvent, error)
+	Get(ctx context.Context, filter AuditFilter, skip, take *int) ([]entities.AuditEvent, error)
+	Count(ctx context.Context, filter AuditFilter) (int64, error)
+}
+
+// AuditService interface
+// Exports write every event matching the filters as CSV, newest first.
+// Record writes the events not performed on a user, like the reloads of the config, attributed to the actor of the request of the context, if any.
+type AuditService interface {
+	Get(ctx context.Context, req models.GetAuditEventsReq) ([]models.AuditEventResp, error)
+	GetByID(ctx context.Context, ID string) (models.AuditEventResp, error)
+	Export(ctx context.Context, req models.ExportAuditEventsReq, w io.Writer) error
+	Record(ctx context.Context, eventType string, details map[string]string) error
+}
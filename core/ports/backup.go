@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// BackupService interface
+// Backups and restores are queued, the returned job reports their progress once a worker runs them through Process.
+type BackupService interface {
+	Backup(ctx context.Context, collection string) (models.JobResp, error)
+	Restore(ctx context.Context, collection, name string) (models.JobResp, error)
+	Process(ctx context.Context, job *entities.Job) error
+}
@@ -0,0 +1,51 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// ErrBillingSignatureNotValid is returned by the billing providers when an event posted to the webhook was not signed by them
+var ErrBillingSignatureNotValid = errors.New("billing event signature not valid")
+
+// BillingCustomer the customer of a user in the billing provider, its ID being empty until created
+type BillingCustomer struct {
+	ID     string
+	UserID string
+	Email  string
+	Name   string
+}
+
+// BillingEvent an event of the billing provider, the subscription ones changing the status of the subscription of a customer
+type BillingEvent struct {
+	ID                 string
+	Type               string
+	CustomerID         string
+	SubscriptionStatus string
+	CreatedAt          time.Time
+}
+
+// BillingProvider interface of the provider the users are billed through
+type BillingProvider interface {
+	// SaveCustomer creates the customer, or updates it when its ID is set, returning its ID
+	SaveCustomer(ctx context.Context, customer BillingCustomer) (string, error)
+	// ParseEvent verifies the signature of an event posted to the webhook, returning it
+	ParseEvent(payload []byte, signature string) (BillingEvent, error)
+	// SubscriptionEvent reports whether the type of an event is one changing the status of a subscription
+	SubscriptionEvent(eventType string) bool
+}
+
+// BillingService interface
+type BillingService interface {
+	// SyncCustomer creates or updates the customer of the user in the billing provider
+	SyncCustomer(ctx context.Context, userID string) error
+	// HandleEvent syncs the status of the subscription of the event posted to the webhook
+	HandleEvent(ctx context.Context, payload []byte, signature string) error
+	// Subscribed reports whether the user has a subscription granting access
+	Subscribed(ctx context.Context, userID string) (bool, error)
+	// Process runs a billing job claimed by a worker
+	Process(ctx context.Context, job *entities.Job) error
+}
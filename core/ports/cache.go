@@ -0,0 +1,13 @@
+package ports
+
+import "context"
+
+// InvalidateAll is the key invalidating every entry of the caches
+const InvalidateAll = "*"
+
+// Invalidator interface of a channel the replicas broadcast the keys evicted from their caches through,
+// so the other replicas evict their cached copies too
+type Invalidator interface {
+	Invalidate(ctx context.Context, keys ...string) error
+	Listen(ctx context.Context, evict func(key string)) error
+}
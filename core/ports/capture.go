@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// CaptureRepository interface
+// Captures expire once older than the TTL of the repository, and are returned newest first.
+type CaptureRepository interface {
+	Write(ctx context.Context, capture entities.Capture) error
+	Get(ctx context.Context, skip, take *int) ([]entities.Capture, error)
+}
+
+// CaptureService interface of the debug capture mode, capturing the requests and responses of a sampled percentage of the traffic
+// until it expires or is disabled
+type CaptureService interface {
+	Enable(ctx context.Context, req models.CaptureModeReq) (models.CaptureModeResp, error)
+	Disable(ctx context.Context) error
+	Mode(ctx context.Context) models.CaptureModeResp
+	// Sampled returns whether the request being served has to be captured, never when the capture mode is disabled or expired
+	Sampled() bool
+	Save(ctx context.Context, req models.CaptureReq) error
+	Get(ctx context.Context, req models.GetCapturesReq) ([]models.CaptureResp, error)
+}
@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Transactor interface
+type Transactor interface {
+	// WithTransaction runs fn atomically, propagating the transaction through the context it receives.
+	// Calls nested inside an ongoing transaction join it instead of starting a new one.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ReadPreference tells the repositories which replicas can serve the reads of an operation.
+// Repositories without replicas ignore it, and reads inside a transaction are always served by the primary.
+type ReadPreference string
+
+const (
+	ReadPrimary            ReadPreference = "primary"
+	ReadPrimaryPreferred   ReadPreference = "primaryPreferred"
+	ReadSecondary          ReadPreference = "secondary"
+	ReadSecondaryPreferred ReadPreference = "secondaryPreferred"
+	ReadNearest            ReadPreference = "nearest"
+)
+
+type readPreferenceKey struct{}
+
+// WithReadPreference returns a copy of the context whose reads are served with the given read preference
+func WithReadPreference(ctx context.Context, rp ReadPreference) context.Context {
+	return context.WithValue(ctx, readPreferenceKey{}, rp)
+}
+
+// ReadPreferenceFrom returns the read preference of the context, if any
+func ReadPreferenceFrom(ctx context.Context) (ReadPreference, bool) {
+	rp, ok := ctx.Value(readPreferenceKey{}).(ReadPreference)
+	return rp, ok
+}
+
+type operationTimeoutKey struct{}
+
+// WithOperationTimeout returns a copy of the context whose repository operations are given the timeout instead of the one configured for them,
+// a 0 timeout leaving them bounded only by the deadline of the context, like the one of the job running them
+func WithOperationTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, operationTimeoutKey{}, timeout)
+}
+
+// OperationTimeoutFrom returns the operation timeout of the context, if any
+func OperationTimeoutFrom(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(operationTimeoutKey{}).(time.Duration)
+	return timeout, ok
+}
@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReadPreferenceFrom_Ok checks that ReadPreferenceFrom returns the read preference set with WithReadPreference
+func TestReadPreferenceFrom_Ok(t *testing.T) {
+	// Arrange
+	ctx := WithReadPreference(context.Background(), ReadSecondaryPreferred)
+
+	// Act
+	rp, ok := ReadPreferenceFrom(ctx)
+
+	// Assert
+	assert.True(t, ok)
+	assert.Equal(t, ReadSecondaryPreferred, rp)
+}
+
+// TestReadPreferenceFrom_NotSet checks that ReadPreferenceFrom reports when the context has no read preference
+func TestReadPreferenceFrom_NotSet(t *testing.T) {
+	// Act
+	_, ok := ReadPreferenceFrom(context.Background())
+
+	// Assert
+	assert.False(t, ok)
+}
+
+// TestOperationTimeoutFrom_Ok checks that OperationTimeoutFrom returns the timeout set with WithOperationTimeout, even a 0 one
+func TestOperationTimeoutFrom_Ok(t *testing.T) {
+	// Arrange
+	ctx := WithOperationTimeout(context.Background(), 0)
+
+	// Act
+	timeout, ok := OperationTimeoutFrom(ctx)
+
+	// Assert
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), timeout)
+}
+
+// TestOperationTimeoutFrom_NotSet checks that OperationTimeoutFrom reports when the context has no operation timeout
+func TestOperationTimeoutFrom_NotSet(t *testing.T) {
+	// Act
+	_, ok := OperationTimeoutFrom(context.Background())
+
+	// Assert
+	assert.False(t, ok)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// DashboardService interface
+// Get returns the figures of the admin dashboard, aggregated again only once the last ones are older than their max age.
+type DashboardService interface {
+	Get(ctx context.Context) (models.DashboardResp, error)
+}
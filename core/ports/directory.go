@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDirectoryCredentialsNotValid is returned by the credential verifiers when the directory refuses the password, or does not know the user of the email
+var ErrDirectoryCredentialsNotValid = errors.New("directory credentials not valid")
+
+// DirectoryUser the attributes of a user read from its directory, synced into the local user
+type DirectoryUser struct {
+	Name     string
+	Surnames string
+}
+
+// CredentialVerifier interface of the directories verifying the passwords of the users of their tenants instead of their local passwords
+type CredentialVerifier interface {
+	// Handles reports whether the password of the email is verified by the directory of its tenant
+	Handles(email string) bool
+	// Verify checks the password of the email against the directory of its tenant, returning the attributes of its user
+	Verify(ctx context.Context, email, password string) (DirectoryUser, error)
+}
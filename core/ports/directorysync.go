@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// DirectoryEntry a user listed by an external directory, reconciled into the local user of its email by the directory sync
+type DirectoryEntry struct {
+	Email    string
+	Name     string
+	Surnames string
+}
+
+// DirectorySource interface of the external directories listing their users, like a CSV file over SFTP, an HTTP feed or an LDAP server
+type DirectorySource interface {
+	// Users returns every user of the directory, failing instead of returning a partial list
+	Users(ctx context.Context) ([]DirectoryEntry, error)
+}
+
+// DirectorySyncService interface
+// Syncs are queued, the returned job reports their status and counts once a worker runs them through Process,
+// and the report of the changes made or, in dry run, that would be made, is returned by GetReport once the job succeeded.
+type DirectorySyncService interface {
+	Sync(ctx context.Context, dryRun bool) (models.JobResp, error)
+	GetReport(ctx context.Context, jobID string) (models.DirectorySyncReportResp, error)
+	Process(ctx context.Context, job *entities.Job) error
+}
@@ -0,0 +1,29 @@
+package ports
+
+import "context"
+
+// templates of the transactional emails
+const (
+	EmailTemplateVerification  = "verification"
+	EmailTemplatePasswordReset = "password_reset"
+	EmailTemplateSecurityAlert = "security_alert"
+	EmailTemplateLoginCode     = "login_code"
+)
+
+// Email a transactional email, with a plain text body and, when set, an HTML alternative
+type Email struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// EmailSender interface of a provider the emails are sent through
+type EmailSender interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// Mailer interface sending the transactional emails rendered from a template with the given data
+type Mailer interface {
+	Send(ctx context.Context, template, to string, data map[string]string) error
+}
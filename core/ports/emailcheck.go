@@ -0,0 +1,17 @@
+package ports
+
+import "context"
+
+// reasons of the emails found undeliverable by an email verifier
+const (
+	EmailReasonSyntax       = "invalid_syntax"
+	EmailReasonDisposable   = "disposable_domain"
+	EmailReasonNoMailServer = "no_mail_server"
+)
+
+// EmailVerifier interface of the checks of the deliverability of the emails, like their syntax, the MX records of their domain
+// or the lists of disposable domains
+type EmailVerifier interface {
+	// Verify returns the reason the email is not deliverable, empty when it is, and an error when it cannot be verified, like on a DNS timeout
+	Verify(ctx context.Context, email string) (string, error)
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// types of the user lifecycle events
+const (
+	EventUserCreated = "user.created"
+	EventUserUpdated = "user.updated"
+	EventUserDeleted = "user.deleted"
+	EventUserLogin   = "user.login"
+)
+
+// Event a user lifecycle event, identified by its ID so the consumers can discard the ones published more than once
+type Event struct {
+	ID         string
+	Type       string
+	UserID     string
+	OccurredAt time.Time
+	Data       map[string]string
+}
+
+// EventPublisher interface of a broker the events are published to
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// GeoIPLocator interface of the lookups of the location of the IPs.
+// The IPs not located, like the private ones, return an empty location without error.
+type GeoIPLocator interface {
+	Locate(ctx context.Context, ip string) (models.GeoLocation, error)
+}
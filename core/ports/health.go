@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// HealthChecker interface of a dependency whose health can be checked
+type HealthChecker interface {
+	Check(ctx context.Context) error
+}
+
+// HealthCheckerFunc adapter to use a function as a health checker
+type HealthCheckerFunc func(ctx context.Context) error
+
+// Check calls f
+func (f HealthCheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthService interface
+// Critical checks failing make the API down, while the other ones only make it degraded.
+type HealthService interface {
+	Register(name string, critical bool, checker HealthChecker)
+	Check(ctx context.Context) models.ReadinessResp
+	Status(ctx context.Context) models.StatusResp
+}
@@ -0,0 +1,18 @@
+package ports
+
+import "context"
+
+// RepositoryOperation identifies a repository operation passed to the hooks, like users.GetByID
+type RepositoryOperation struct {
+	Collection string
+	Name       string
+}
+
+// RepositoryHook interface of the cross-cutting concerns run around every repository operation, like tracing or metrics
+type RepositoryHook interface {
+	// Before runs before the operation, returning the context the operation and the After hook receive.
+	// Returning an error aborts the operation, which fails with it.
+	Before(ctx context.Context, op RepositoryOperation) (context.Context, error)
+	// After runs once the operation finishes, receiving its error, if any
+	After(ctx context.Context, op RepositoryOperation, err error)
+}
@@ -0,0 +1,48 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// JobFilter filter of the jobs, empty fields match every job
+type JobFilter struct {
+	Status entities.JobStatus
+	Type   string
+}
+
+// JobCount count of the jobs of a type in a status
+type JobCount struct {
+	Type   string
+	Status entities.JobStatus
+	Count  int64
+}
+
+// JobRepository interface
+// Jobs are returned newest first.
+type JobRepository interface {
+	RetentionRepository
+	Create(ctx context.Context, job entities.Job) (string, error)
+	Update(ctx context.Context, job entities.Job) error
+	GetByID(ctx context.Context, ID string) (entities.Job, error)
+	Get(ctx context.Context, filter JobFilter, skip, take *int) ([]entities.Job, error)
+	// Claim atomically locks for the worker, until the given time, the job of one of the types due the earliest:
+	// a queued or failed job whose next attempt is due, or a running job whose lock has expired, counting a new attempt.
+	// It returns a NonExistentErr when no job is due.
+	Claim(ctx context.Context, types []string, worker string, now, lockedUntil time.Time) (entities.Job, error)
+	// Count counts the jobs in any of the statuses by type and status, leaving out the types and statuses having none.
+	Count(ctx context.Context, statuses []entities.JobStatus) ([]JobCount, error)
+}
+
+// JobService interface
+// Jobs are enqueued to be run by the workers of the async processes, retried with an exponential backoff when they fail,
+// and left in the dead letter once every attempt failed, until retried.
+type JobService interface {
+	GetByID(ctx context.Context, ID string) (models.JobResp, error)
+	Get(ctx context.Context, req models.GetJobsReq) ([]models.JobResp, error)
+	Enqueue(ctx context.Context, jobType string, metadata map[string]string) (models.JobResp, error)
+	Retry(ctx context.Context, ID string) (models.JobResp, error)
+}
@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// SigningKeyStore interface of the keys signing the tokens, shared by every replica of the API
+type SigningKeyStore interface {
+	// Get returns the keys not expired, the newest first
+	Get(ctx context.Context) ([]entities.SigningKey, error)
+	// Rotate adds the key, setting the expiry of the previous ones not expiring yet after the given overlap, and deletes the expired ones
+	Rotate(ctx context.Context, key entities.SigningKey, overlap time.Duration) error
+	// Purge deletes the expired keys, returning how many were deleted
+	Purge(ctx context.Context) (int64, error)
+}
+
+// TokenKeys interface of the keys signing and verifying the tokens
+type TokenKeys interface {
+	// Sign returns the token of the claims, signed with the newest key
+	Sign(claims jwt.MapClaims) (string, error)
+	// Parse verifies the token with the key it was signed with, returning its claims
+	Parse(ctx context.Context, token string) (jwt.MapClaims, error)
+}
+
+// KeyService interface of the service rotating the keys signing the tokens
+type KeyService interface {
+	TokenKeys
+	// Refresh reads the keys from the store, so the ones rotated by another replica are used
+	Refresh(ctx context.Context) error
+	// Rotate creates a new key signing the tokens, keeping the previous ones verifying them during the overlap
+	Rotate(ctx context.Context) error
+}
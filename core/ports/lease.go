@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// LeaseStore interface of the leases shared by every replica of the API, each one held by a single replica until it expires
+type LeaseStore interface {
+	// Acquire takes the lease for the holder, or renews it when already held by it, for the given TTL,
+	// returning false when held by another holder and not expired
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	// Release gives up the lease when held by the holder, so another one can take it without waiting for it to expire
+	Release(ctx context.Context, name, holder string) error
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// LimitStore interface of the counters of the rate limits and the login lockouts, shared by every replica of the API,
+// so the limits are enforced the same whichever replica serves the requests.
+// The hits of a key are counted within a fixed window, started by its first hit and ending after the given duration.
+type LimitStore interface {
+	// Hit atomically counts a hit of the key, starting a new window when the current one has ended, and returns the hits of the window
+	Hit(ctx context.Context, key string, window time.Duration) (entities.Limit, error)
+	// Get returns the hits of the current window of the key, none when ended or never hit
+	Get(ctx context.Context, key string) (entities.Limit, error)
+	Reset(ctx context.Context, key string) error
+	// Purge deletes the ended windows of every key, returning how many were deleted
+	Purge(ctx context.Context) (int64, error)
+}
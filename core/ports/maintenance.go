@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// MaintenanceStore interface of the maintenance operations of the database
+type MaintenanceStore interface {
+	// Collections returns the names of the collections or tables of the database, in alphabetical order
+	Collections(ctx context.Context) ([]string, error)
+	// RebuildIndexes rebuilds the indexes of the collection or table
+	RebuildIndexes(ctx context.Context, collection string) error
+}
+
+// MaintenanceService interface
+// Maintenance tasks are queued, the returned job reports their progress once a worker runs them through Process.
+type MaintenanceService interface {
+	Tasks() []models.MaintenanceTaskResp
+	Start(ctx context.Context, task string) (models.JobResp, error)
+	Process(ctx context.Context, job *entities.Job) error
+}
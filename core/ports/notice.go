@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// events the admins are notified of
+const (
+	AdminEventLockout   = "lockout"
+	AdminEventAlert     = "alert"
+	AdminEventMigration = "migration"
+)
+
+// AdminNotice an event of interest for the admins, like a locked out email, an alert or the result of the migrations,
+// described by its text and detailed by its fields
+type AdminNotice struct {
+	Event  string
+	Text   string
+	Fields map[string]string
+	At     time.Time
+}
+
+// AdminNotifier interface of the targets the admin notices are posted to
+type AdminNotifier interface {
+	NotifyAdmins(ctx context.Context, notice AdminNotice) error
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// NotificationPreferencesRepository interface
+type NotificationPreferencesRepository interface {
+	// Get returns the preferences of the user, failing with a non existent error when never set
+	Get(ctx context.Context, userID string) (entities.NotificationPreferences, error)
+	// Update sets the given channels, by category and channel, at once, leaving the other ones as they are, and returns the preferences updated,
+	// creating them when never set. The channels not given of a category never set are enabled.
+	Update(ctx context.Context, userID string, channels map[string]map[string]bool, at time.Time) (entities.NotificationPreferences, error)
+	// Delete deletes the preferences of the user, if any
+	Delete(ctx context.Context, userID string) error
+}
+
+// NotificationPreferencesService interface, whose operations on the preferences of a user are allowed to the user itself and to the admins
+type NotificationPreferencesService interface {
+	Get(ctx context.Context, caller models.Caller, userID string) (models.NotificationPreferencesResp, error)
+	Update(ctx context.Context, caller models.Caller, userID string, req models.UpdateNotificationPreferencesReq) (models.NotificationPreferencesResp, error)
+	// Allows reports whether the notifications of the category are delivered to the user through the channel, every one being delivered when never set
+	Allows(ctx context.Context, userID, category, channel string) (bool, error)
+}
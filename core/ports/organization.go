@@ -0,0 +1,55 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+)
+
+// OrganizationRepository interface
+type OrganizationRepository interface {
+	repository.Repository
+	// GetByMember returns the organizations the user is a member of, sorted by name
+	GetByMember(ctx context.Context, userID string) ([]entities.Organization, error)
+	// GetByInvitee returns the organizations the user has a pending invitation to, sorted by name
+	GetByInvitee(ctx context.Context, userID string) ([]entities.Organization, error)
+	// Invite adds the pending invitation to the organization with the specified ID,
+	// failing with a non existent error when the organization is not found or the user is a member or invited already
+	Invite(ctx context.Context, ID string, invitation entities.OrganizationInvitation) error
+	// AcceptInvitation makes the user a member of the organization with the specified ID with the role it was invited with,
+	// failing with a non existent error when the user has no pending invitation to it
+	AcceptInvitation(ctx context.Context, ID, userID string, joinedAt time.Time) error
+	// RemoveInvitation removes the pending invitation of the user to the organization with the specified ID,
+	// failing with a non existent error when the user has none
+	RemoveInvitation(ctx context.Context, ID, userID string) error
+	// TransferOwnership makes the member the owner of the organization with the specified ID, the previous owner becoming an admin,
+	// failing with a non existent error when the organization is not owned by the owner or the user is not a member
+	TransferOwnership(ctx context.Context, ID, ownerID, userID string) error
+	// UpdateMemberRole sets the role of the member of the organization with the specified ID, failing with a non existent error when not a member
+	UpdateMemberRole(ctx context.Context, ID, userID, role string) error
+	// RemoveMember removes the member from the organization with the specified ID, failing with a non existent error when not a member
+	RemoveMember(ctx context.Context, ID, userID string) error
+	// RemoveUser removes the user from every organization it is a member of or invited to
+	RemoveUser(ctx context.Context, userID string) error
+}
+
+// OrganizationService interface, whose operations are allowed to the caller by its role in the organization
+type OrganizationService interface {
+	Create(ctx context.Context, caller models.Caller, req models.CreateOrganizationReq) (models.CreationResp, error)
+	GetAll(ctx context.Context, caller models.Caller) ([]models.OrganizationResp, error)
+	GetByID(ctx context.Context, caller models.Caller, ID string) (models.OrganizationResp, error)
+	Update(ctx context.Context, caller models.Caller, ID string, req models.UpdateOrganizationReq) error
+	Delete(ctx context.Context, caller models.Caller, ID string) error
+	GetMembers(ctx context.Context, caller models.Caller, ID string) ([]models.OrganizationMemberResp, error)
+	UpdateMember(ctx context.Context, caller models.Caller, ID, userID string, req models.UpdateMemberReq) error
+	RemoveMember(ctx context.Context, caller models.Caller, ID, userID string) error
+	TransferOwnership(ctx context.Context, caller models.Caller, ID string, req models.TransferOwnershipReq) error
+	GetInvitations(ctx context.Context, caller models.Caller, ID string) ([]models.OrganizationInvitationResp, error)
+	GetCallerInvitations(ctx context.Context, caller models.Caller) ([]models.OrganizationInvitationResp, error)
+	Invite(ctx context.Context, caller models.Caller, ID string, req models.InviteMemberReq) error
+	AcceptInvitation(ctx context.Context, caller models.Caller, ID string) error
+	RemoveInvitation(ctx context.Context, caller models.Caller, ID, userID string) error
+}
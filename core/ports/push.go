@@ -0,0 +1,49 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// ErrDeviceTokenNotValid is returned by the push senders when the platform no longer accepts the token of the device,
+// as the app was uninstalled or the token expired, so the device is removed
+var ErrDeviceTokenNotValid = errors.New("device token not valid")
+
+// PushNotification a notification shown on the devices of a user, along with its data for the app.
+// Its category, when set, is the one of the notification preferences of the user it is checked against.
+type PushNotification struct {
+	Title    string
+	Body     string
+	Data     map[string]string
+	Category string
+}
+
+// PushSender interface of a platform the push notifications are sent through
+type PushSender interface {
+	Send(ctx context.Context, device entities.Device, notification PushNotification) error
+}
+
+// DeviceRepository interface of the devices of the users
+type DeviceRepository interface {
+	// Upsert creates the device or, when its ID exists, moves it to its user and updates its name
+	Upsert(ctx context.Context, device entities.Device) error
+	// GetByUser returns the devices of the user, the newest first
+	GetByUser(ctx context.Context, userID string) ([]entities.Device, error)
+	// Delete deletes the device of the user, failing with a non existent error when not found
+	Delete(ctx context.Context, userID, ID string) error
+	// DeleteByUser deletes every device of the user
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+// DeviceService interface
+type DeviceService interface {
+	Register(ctx context.Context, userID string, req models.RegisterDeviceReq) (models.DeviceResp, error)
+	GetByUser(ctx context.Context, userID string) ([]models.DeviceResp, error)
+	Delete(ctx context.Context, userID, ID string) error
+	DeleteByUser(ctx context.Context, userID string) error
+	// Notify sends the notification to every device of the user
+	Notify(ctx context.Context, userID string, notification PushNotification) error
+}
@@ -0,0 +1,18 @@
+package ports
+
+import "time"
+
+// ErrorReport an unexpected error along with the context it happened in
+type ErrorReport struct {
+	Message string
+	Error   string
+	UserID  string
+	Stack   string
+	Tags    map[string]string
+}
+
+// ErrorReporter interface of the error tracking service the unexpected errors are reported to
+type ErrorReporter interface {
+	Report(report ErrorReport)
+	Flush(timeout time.Duration) bool
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// RetentionRepository interface of the repositories whose old documents are purged or anonymized by the retention policies
+type RetentionRepository interface {
+	// Expire purges or anonymizes the documents older than before, returning how many they were.
+	// Documents already anonymized are not anonymized again. In dry run they are only counted.
+	Expire(ctx context.Context, action entities.RetentionAction, before time.Time, dryRun bool) (int64, error)
+}
+
+// RetentionService interface
+type RetentionService interface {
+	Apply(ctx context.Context, dryRun bool) (models.RetentionResp, error)
+}
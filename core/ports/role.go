@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+)
+
+// RoleRepository interface
+type RoleRepository interface {
+	repository.Repository
+	// GetByAssignees returns the roles assigned to the user or to any of the organizations, sorted by name
+	GetByAssignees(ctx context.Context, userID string, organizationIDs []string) ([]entities.Role, error)
+	// Assign assigns the role with the specified ID to the assignee of the kind, failing with a non existent error when the role is not found
+	Assign(ctx context.Context, ID, kind, assigneeID string) error
+	// Unassign unassigns the role with the specified ID from the assignee of the kind, failing with a non existent error when not assigned to it
+	Unassign(ctx context.Context, ID, kind, assigneeID string) error
+	// RemoveAssignee unassigns every role assigned to the assignee of the kind
+	RemoveAssignee(ctx context.Context, kind, assigneeID string) error
+}
+
+// RoleService interface
+type RoleService interface {
+	Create(ctx context.Context, req models.CreateRoleReq) (models.CreationResp, error)
+	GetAll(ctx context.Context) ([]models.RoleResp, error)
+	GetByID(ctx context.Context, ID string) (models.RoleResp, error)
+	Update(ctx context.Context, ID string, req models.UpdateRoleReq) error
+	Delete(ctx context.Context, ID string) error
+	Assign(ctx context.Context, ID, kind, assigneeID string) error
+	Unassign(ctx context.Context, ID, kind, assigneeID string) error
+}
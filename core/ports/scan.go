@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// MalwareScanner interface of the scanners of the uploaded files, like ClamAV
+type MalwareScanner interface {
+	// Scan returns the name of the malware found in the content, empty when it is clean
+	Scan(ctx context.Context, content io.Reader) (string, error)
+}
+
+// ScanService interface
+// The uploads are stored in quarantine and queued to be scanned by the workers, being moved to their key once found clean,
+// or deleted when infected.
+type ScanService interface {
+	Process(ctx context.Context, job *entities.Job) error
+}
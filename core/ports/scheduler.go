@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// Scheduler interface of the jobs run on a schedule by the async process
+type Scheduler interface {
+	GetJobs(ctx context.Context) []models.ScheduledJobResp
+}
@@ -0,0 +1,86 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// user changes
+const (
+	UserChangeSaved   = "saved"
+	UserChangeDeleted = "deleted"
+)
+
+// search facets
+const (
+	SearchFacetDomain = "domain"
+	SearchFacetClaims = "claims"
+)
+
+// UserChange a change of a user committed to the database, either saved, when created, replaced or updated, or deleted
+type UserChange struct {
+	Operation string
+	ID        string
+}
+
+// SearchDocument a user as mirrored into the search index, without its password or any field only used internally
+type SearchDocument struct {
+	ID        string
+	Name      string
+	Surnames  string
+	Email     string
+	Domain    string
+	Claims    []int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SearchQuery a full text query of the search index, filtered by the domain of the email, by a claim and by the IDs of the users when set
+type SearchQuery struct {
+	Text   string
+	Domain string
+	Claim  *int64
+	IDs    []string
+	Skip   int
+	Take   int
+}
+
+// SearchFacet the count of the documents matching a query with a value of a facet
+type SearchFacet struct {
+	Value string
+	Count int64
+}
+
+// SearchResult the documents of a page of the matches of a query, by relevance, with the total count of matches and their facets by name
+type SearchResult struct {
+	Total     int64
+	Documents []SearchDocument
+	Facets    map[string][]SearchFacet
+}
+
+// UserChangeStream interface
+type UserChangeStream interface {
+	// Watch calls handle with every change of the users as it is committed until the context is done or an error is returned,
+	// resuming after the last change handled by a previous watch. When there is none, backfill is called once the stream is open,
+	// so the users saved before are handled too and the changes committed meanwhile are not missed.
+	Watch(ctx context.Context, backfill func(ctx context.Context) error, handle func(ctx context.Context, change UserChange) error) error
+}
+
+// SearchIndex interface
+type SearchIndex interface {
+	// Index creates or replaces the document of a user
+	Index(ctx context.Context, document SearchDocument) error
+	// Delete deletes the document of a user, not failing when it was not indexed
+	Delete(ctx context.Context, ID string) error
+	Search(ctx context.Context, query SearchQuery) (SearchResult, error)
+}
+
+// SearchService interface
+type SearchService interface {
+	// Sync mirrors the users into the search index as they change until the context is done or the sync fails
+	Sync(ctx context.Context) error
+	// Search returns the users matching the search the caller is allowed to
+	Search(ctx context.Context, caller models.Caller, req models.IndexSearchUsersReq) (models.IndexSearchUsersResp, error)
+}
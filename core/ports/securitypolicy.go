@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// SecurityPolicyRepository interface of the security policies, stored by their tenant
+type SecurityPolicyRepository interface {
+	// GetAll returns every security policy, sorted by tenant
+	GetAll(ctx context.Context) ([]entities.SecurityPolicy, error)
+	// Get returns the security policy of the tenant, failing with a non existent error when it has none
+	Get(ctx context.Context, tenant string) (entities.SecurityPolicy, error)
+	// Upsert stores the security policy, replacing the one of its tenant
+	Upsert(ctx context.Context, policy entities.SecurityPolicy) error
+	// Delete deletes the security policy of the tenant, failing with a non existent error when it has none
+	Delete(ctx context.Context, tenant string) error
+}
+
+// SecurityPolicyService interface
+type SecurityPolicyService interface {
+	GetAll(ctx context.Context) ([]models.SecurityPolicyResp, error)
+	Get(ctx context.Context, tenant string) (models.SecurityPolicyResp, error)
+	Put(ctx context.Context, req models.PutSecurityPolicyReq) (models.SecurityPolicyResp, error)
+	Delete(ctx context.Context, tenant string) error
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// SIEMSink interface of the destinations the audit events are exported to, like a syslog server or the HTTP collector of a SIEM
+type SIEMSink interface {
+	// Send delivers a batch of events, failing as a whole so the batch is sent again
+	Send(ctx context.Context, events []entities.AuditEvent) error
+}
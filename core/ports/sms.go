@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// SMSSender interface of a provider the text messages are sent through
+type SMSSender interface {
+	// Send sends the message to the phone number, returning the ID given to it by the provider
+	Send(ctx context.Context, to, body string) (string, error)
+	// VerifyCallback reports whether a delivery status callback posted to the URL with the form was signed by the provider
+	VerifyCallback(url string, form url.Values, signature string) bool
+}
+
+// SMSService interface
+type SMSService interface {
+	SendCode(ctx context.Context, req models.SendSMSCodeReq) error
+	CheckCode(ctx context.Context, req models.CheckSMSCodeReq) error
+	UpdateStatus(ctx context.Context, form url.Values, signature string) error
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// FileStorage interface
+// Files are identified by a key, storing a file with an existing key replaces it.
+type FileStorage interface {
+	Store(ctx context.Context, file entities.File, content io.Reader) (entities.File, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, entities.File, error)
+	Stat(ctx context.Context, key string) (entities.File, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// FilePresigner interface of a file storage presigning URLs the clients download and upload its files with directly,
+// so large files do not pass through the API. The headers returned with an upload URL must be sent with the upload.
+type FilePresigner interface {
+	PresignGet(ctx context.Context, key string, expires time.Duration) (string, error)
+	PresignPut(ctx context.Context, file entities.File, expires time.Duration) (string, map[string]string, error)
+}
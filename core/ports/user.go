@@ -2,7 +2,10 @@ package ports
 
 import (
 	"context"
+	"io"
+	"time"
 
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
 	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/scv-go-tools/v3/repository"
 )
@@ -10,7 +13,28 @@ import (
 // UserRepositoy interface
 type UserRepository interface {
 	repository.Repository
+	Transactor
 	CreateMany(ctx context.Context, entities []interface{}) ([]string, error)
+	GetProjected(ctx context.Context, filter map[string]interface{}, projection map[string]interface{}, skip, take *int) ([]interface{}, error)
+	GetByIDProjected(ctx context.Context, ID string, projection map[string]interface{}) (interface{}, error)
+	Upsert(ctx context.Context, filter map[string]interface{}, entity interface{}) (string, error)
+	Search(ctx context.Context, text string, autocomplete bool, projection map[string]interface{}, skip, take *int) ([]interface{}, error)
+	GetNearby(ctx context.Context, longitude, latitude, radius float64, projection map[string]interface{}, skip, take *int) ([]interface{}, error)
+	UpdateLastLogin(ctx context.Context, ID string, at time.Time) error
+	// UpdateNames sets the name and the surnames of the user with the specified ID, leaving its other fields untouched
+	UpdateNames(ctx context.Context, ID, name, surnames string, at time.Time) error
+	// UpdateBillingCustomer sets the ID of the customer of the user with the specified ID in the billing provider
+	UpdateBillingCustomer(ctx context.Context, ID, customerID string) error
+	// UpdateSubscription sets the status of the subscription of the user of the customer as of the given time,
+	// failing with a non existent error when no user of the customer has a status older than it, as the events of the provider can come out of order
+	UpdateSubscription(ctx context.Context, customerID, status string, at time.Time) error
+	Archive(ctx context.Context, inactiveSince, at time.Time) (int64, error)
+	// ArchiveByID moves the user with the specified ID to the archive, failing with a non existent error when it is not found
+	ArchiveByID(ctx context.Context, ID string, at time.Time) error
+	Unarchive(ctx context.Context, ID string, at time.Time) error
+	Dump(ctx context.Context, fn func(user entities.User) error) error
+	Restore(ctx context.Context, users []entities.User) error
+	Stats(ctx context.Context, activeSince time.Time) (entities.UserStats, error)
 }
 
 // UserService interface
@@ -18,10 +42,29 @@ type UserService interface {
 	Login(ctx context.Context, credentials models.LoginUserReq) (models.LoginUserResp, error)
 	Create(ctx context.Context, user models.CreateUserReq) (models.CreationResp, error)
 	CreateMany(ctx context.Context, users []models.CreateUserReq) (models.MultiCreationResp, error)
-	GetAll(ctx context.Context) ([]models.UserResp, error)
+	// GetAll returns the users the caller is allowed to
+	GetAll(ctx context.Context, caller models.Caller) ([]models.UserResp, error)
 	GetByEmail(ctx context.Context, email string) (models.UserResp, error)
+	// Search returns the users matching the search the caller is allowed to
+	Search(ctx context.Context, caller models.Caller, req models.SearchUsersReq) ([]models.UserResp, error)
+	// GetNearby returns the users located near the point the caller is allowed to
+	GetNearby(ctx context.Context, caller models.Caller, req models.NearbyUsersReq) ([]models.UserResp, error)
 	GetByID(ctx context.Context, ID string) (models.UserResp, error)
+	// Authorize checks that the caller is allowed to the user with the specified ID
+	Authorize(ctx context.Context, caller models.Caller, ID string) error
+	// AuthorizeUpdate checks that the caller is allowed to update the user with the specified ID with the given changes
+	AuthorizeUpdate(ctx context.Context, caller models.Caller, ID string, user models.UpdateUserReq) error
+	Upsert(ctx context.Context, email string, user models.UpsertUserReq) (models.UpsertionResp, error)
 	Update(ctx context.Context, ID string, user models.UpdateUserReq) error
+	Merge(ctx context.Context, ID string, req models.MergeUsersReq) error
 	Delete(ctx context.Context, ID string) error
-	GetUserClaims(ctx context.Context) map[int]string
+	ArchiveInactive(ctx context.Context) (models.ArchivalResp, error)
+	ComputeStats(ctx context.Context) (models.UserStatsResp, error)
+	Unarchive(ctx context.Context, ID string) error
+	UploadAvatar(ctx context.Context, ID string, avatar models.UploadAvatarReq) (models.FileResp, error)
+	GetAvatar(ctx context.Context, ID string) (io.ReadCloser, models.FileResp, error)
+	DeleteAvatar(ctx context.Context, ID string) error
+	GetAvatarURL(ctx context.Context, ID string) (models.PresignedURLResp, error)
+	CreateAvatarUploadURL(ctx context.Context, ID string, req models.AvatarUploadURLReq) (models.PresignedURLResp, error)
+	GetUserClaims(ctx context.Context, req models.GetClaimsReq) []models.ClaimResp
 }
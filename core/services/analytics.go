@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// analyticsUserService decorator of an user service that tracks the product analytics of the users
+type analyticsUserService struct {
+	ports.UserService
+	tracker ports.AnalyticsTracker
+	logger  zerolog.Logger
+	optOut  map[string]bool
+}
+
+// NewAnalyticsUserService wraps a user service tracking the signups, logins and profile changes of the users not opted out, without their personal data
+func NewAnalyticsUserService(service ports.UserService, tracker ports.AnalyticsTracker, logger zerolog.Logger, optOutDomains []string) ports.UserService {
+	optOut := make(map[string]bool, len(optOutDomains))
+	for _, domain := range optOutDomains {
+		optOut[strings.ToLower(domain)] = true
+	}
+	return &analyticsUserService{
+		UserService: service,
+		tracker:     tracker,
+		logger:      logger,
+		optOut:      optOut,
+	}
+}
+
+func (s *analyticsUserService) Login(ctx context.Context, credentials models.LoginUserReq) (models.LoginUserResp, error) {
+	resp, err := s.UserService.Login(ctx, credentials)
+	if err == nil && s.tracked(resp.User.Email) {
+		s.send(ctx, ports.AnalyticsTrack, resp.User.ID, ports.AnalyticsSignedIn, nil, nil)
+	}
+	return resp, err
+}
+
+func (s *analyticsUserService) Create(ctx context.Context, user models.CreateUserReq) (models.CreationResp, error) {
+	resp, err := s.UserService.Create(ctx, user)
+	if err == nil {
+		s.signedUp(ctx, resp.InsertedID, user.Email, user.Claims)
+	}
+	return resp, err
+}
+
+func (s *analyticsUserService) CreateMany(ctx context.Context, users []models.CreateUserReq) (models.MultiCreationResp, error) {
+	resp, err := s.UserService.CreateMany(ctx, users)
+	if err == nil {
+		for i, ID := range resp.InsertedIDs {
+			if i < len(users) {
+				s.signedUp(ctx, ID, users[i].Email, users[i].Claims)
+			}
+		}
+	}
+	return resp, err
+}
+
+// Upsert tracks Signed Up when no user had the email before, Profile Updated otherwise
+func (s *analyticsUserService) Upsert(ctx context.Context, email string, user models.UpsertUserReq) (models.UpsertionResp, error) {
+	_, getErr := s.UserService.GetByEmail(ctx, email)
+	resp, err := s.UserService.Upsert(ctx, email, user)
+	if err != nil {
+		return resp, err
+	}
+
+	if errors.Is(getErr, wrappers.NonExistentErr) {
+		s.signedUp(ctx, resp.ID, email, user.Claims)
+	} else {
+		s.profileUpdated(ctx, resp.ID, []string{"upserted"})
+	}
+	return resp, nil
+}
+
+// Update tracks Profile Updated with the names of the fields set in the request, identifying the user again with its traits
+func (s *analyticsUserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) error {
+	if err := s.UserService.Update(ctx, ID, user); err != nil {
+		return err
+	}
+
+	s.profileUpdated(ctx, ID, updatedFields(user))
+	return nil
+}
+
+// signedUp identifies the user created and tracks its Signed Up event, unless its tenant opted out
+func (s *analyticsUserService) signedUp(ctx context.Context, userID, email string, claims []int64) {
+	if !s.tracked(email) {
+		return
+	}
+	s.send(ctx, ports.AnalyticsIdentify, userID, "", analyticsTraits(email, claims), nil)
+	s.send(ctx, ports.AnalyticsTrack, userID, ports.AnalyticsSignedUp, nil, nil)
+}
+
+// profileUpdated identifies the user updated and tracks its Profile Updated event, unless its tenant opted out.
+// The user is read again, as its tenant and claims can have been changed.
+func (s *analyticsUserService) profileUpdated(ctx context.Context, userID string, fields []string) {
+	user, err := s.UserService.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("user", userID).Msg("analytics cannot be sent")
+		return
+	}
+	if !s.tracked(user.Email) {
+		return
+	}
+	s.send(ctx, ports.AnalyticsIdentify, userID, "", analyticsTraits(user.Email, user.Claims), nil)
+	s.send(ctx, ports.AnalyticsTrack, userID, ports.AnalyticsProfileUpdated, nil, map[string]string{"fields": strings.Join(fields, ",")})
+}
+
+// tracked reports whether the analytics of the user with the email are sent, its tenant not having opted out
+func (s *analyticsUserService) tracked(email string) bool {
+	return !s.optOut[emailDomain(email)]
+}
+
+// send sends the message with a random ID, logging the failures instead of returning them
+func (s *analyticsUserService) send(ctx context.Context, messageType, userID, event string, traits, properties map[string]string) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		s.logger.Error().Err(err).Str("user", userID).Msg("analytics cannot be sent")
+		return
+	}
+
+	err := s.tracker.Send(ctx, ports.AnalyticsMessage{
+		ID:         hex.EncodeToString(id),
+		Type:       messageType,
+		UserID:     userID,
+		Event:      event,
+		Traits:     traits,
+		Properties: properties,
+		Timestamp:  time.Now().UTC(),
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Str("user", userID).Str("type", messageType).Str("event", event).Msg("analytics cannot be sent")
+	}
+}
+
+// analyticsTraits returns the traits of a user without personal data: its tenant and whether it is an admin
+func analyticsTraits(email string, claims []int64) map[string]string {
+	return map[string]string{
+		"tenant": emailDomain(email),
+		"admin":  strconv.FormatBool(containsClaim(claims, int64(entities.AdminClaim))),
+	}
+}
+
+// emailDomain returns the lowercased domain of the email, its tenant
+func emailDomain(email string) string {
+	return strings.ToLower(email[strings.LastIndex(email, "@")+1:])
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// analyticsOf returns a matcher of the analytics messages of the given type, user and event with the given traits and properties
+func analyticsOf(messageType, userID, event string, traits, properties map[string]string) interface{} {
+	return mock.MatchedBy(func(message ports.AnalyticsMessage) bool {
+		return message.ID != "" && message.Type == messageType && message.UserID == userID && message.Event == event && !message.Timestamp.IsZero() &&
+			assert.ObjectsAreEqual(traits, message.Traits) && assert.ObjectsAreEqual(properties, message.Properties)
+	})
+}
+
+// TestAnalyticsCreate_Ok checks that Create identifies the created user, with its tenant and admin traits only, and tracks Signed Up
+func TestAnalyticsCreate_Ok(t *testing.T) {
+	// Arrange
+	req := models.CreateUserReq{Name: "test", Surnames: "test", Email: "test@Example.com", Claims: []int64{int64(entities.AdminClaim)}}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), mock.Anything, req).Return(models.CreationResp{InsertedID: "test-id"}, nil).Once()
+	trackerMock := mocks.NewAnalyticsTracker(t)
+	trackerMock.On(testutils.FunctionName(t, ports.AnalyticsTracker.Send), mock.Anything,
+		analyticsOf(ports.AnalyticsIdentify, "test-id", "", map[string]string{"tenant": "example.com", "admin": "true"}, nil)).Return(nil).Once()
+	trackerMock.On(testutils.FunctionName(t, ports.AnalyticsTracker.Send), mock.Anything,
+		analyticsOf(ports.AnalyticsTrack, "test-id", ports.AnalyticsSignedUp, nil, nil)).Return(nil).Once()
+
+	service := NewAnalyticsUserService(userServiceMock, trackerMock, zerolog.Nop(), nil)
+
+	// Act
+	resp, err := service.Create(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.InsertedID)
+}
+
+// TestAnalyticsCreate_OptedOut checks that Create does not send any analytics for the users of a tenant opted out
+func TestAnalyticsCreate_OptedOut(t *testing.T) {
+	// Arrange
+	req := models.CreateUserReq{Email: "test@example.com"}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), mock.Anything, req).Return(models.CreationResp{InsertedID: "test-id"}, nil).Once()
+
+	service := NewAnalyticsUserService(userServiceMock, mocks.NewAnalyticsTracker(t), zerolog.Nop(), []string{"Example.com"})
+
+	// Act
+	_, err := service.Create(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestAnalyticsLogin_Ok checks that Login tracks Signed In for the logged user
+func TestAnalyticsLogin_Ok(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, req).
+		Return(models.LoginUserResp{User: models.UserResp{ID: "test-id", Email: "test@example.com"}}, nil).Once()
+	trackerMock := mocks.NewAnalyticsTracker(t)
+	trackerMock.On(testutils.FunctionName(t, ports.AnalyticsTracker.Send), mock.Anything,
+		analyticsOf(ports.AnalyticsTrack, "test-id", ports.AnalyticsSignedIn, nil, nil)).Return(nil).Once()
+
+	service := NewAnalyticsUserService(userServiceMock, trackerMock, zerolog.Nop(), []string{"other.com"})
+
+	// Act
+	_, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestAnalyticsUpdate_Ok checks that Update identifies the user again and tracks Profile Updated with the names of the fields set, not their values
+func TestAnalyticsUpdate_Ok(t *testing.T) {
+	// Arrange
+	name := "new name"
+	email := "new@example.com"
+	req := models.UpdateUserReq{Name: &name, Email: &email}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", req).Return(nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.UserResp{ID: "test-id", Email: email}, nil).Once()
+	trackerMock := mocks.NewAnalyticsTracker(t)
+	trackerMock.On(testutils.FunctionName(t, ports.AnalyticsTracker.Send), mock.Anything,
+		analyticsOf(ports.AnalyticsIdentify, "test-id", "", map[string]string{"tenant": "example.com", "admin": "false"}, nil)).Return(nil).Once()
+	trackerMock.On(testutils.FunctionName(t, ports.AnalyticsTracker.Send), mock.Anything,
+		analyticsOf(ports.AnalyticsTrack, "test-id", ports.AnalyticsProfileUpdated, nil, map[string]string{"fields": "name,email"})).Return(nil).Once()
+
+	service := NewAnalyticsUserService(userServiceMock, trackerMock, zerolog.Nop(), nil)
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestAnalyticsUpdate_SendError checks that Update does not fail when the analytics cannot be sent
+func TestAnalyticsUpdate_SendError(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", mock.Anything).Return(nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.UserResp{ID: "test-id", Email: "test@example.com"}, nil).Once()
+	trackerMock := mocks.NewAnalyticsTracker(t)
+	trackerMock.On(testutils.FunctionName(t, ports.AnalyticsTracker.Send), mock.Anything, mock.Anything).Return(errors.New("send error")).Twice()
+
+	service := NewAnalyticsUserService(userServiceMock, trackerMock, zerolog.Nop(), nil)
+
+	// Act
+	err := service.Update(context.Background(), "test-id", models.UpdateUserReq{})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestAnalyticsUpdate_Error checks that Update does not send any analytics when the user is not updated
+func TestAnalyticsUpdate_Error(t *testing.T) {
+	// Arrange
+	expectedError := errors.New("update error")
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", mock.Anything).Return(expectedError).Once()
+
+	service := NewAnalyticsUserService(userServiceMock, mocks.NewAnalyticsTracker(t), zerolog.Nop(), nil)
+
+	// Act
+	err := service.Update(context.Background(), "test-id", models.UpdateUserReq{})
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
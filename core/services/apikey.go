@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/mapping"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// apiKeyPrefixLength the length of the beginning of the secrets stored to tell the API keys apart, the prefix included
+const apiKeyPrefixLength = len(entities.APIKeyPrefix) + 8
+
+// apiKeyTouchInterval the interval the last use of an API key is stored at most once every, so the keys used on every request are not written on every request
+const apiKeyTouchInterval = time.Minute
+
+// apiKeyService adapter of the API key service
+type apiKeyService struct {
+	repository ports.APIKeyRepository
+	users      ports.UserService
+}
+
+// NewAPIKeyService creates a new API key service, checking through the user service that the scopes of the keys are claims of their users
+func NewAPIKeyService(repository ports.APIKeyRepository, users ports.UserService) ports.APIKeyService {
+	return &apiKeyService{
+		repository: repository,
+		users:      users,
+	}
+}
+
+// Create an API key of a user, responding with its secret, which cannot be read again.
+// The keys are only granted the claims of their user, whoever creates them.
+func (s *apiKeyService) Create(ctx context.Context, caller models.Caller, userID string, req models.CreateAPIKeyReq) (resp models.APIKeySecretResp, err error) {
+	if err = authorizeAPIKeys(caller, userID); err != nil {
+		return
+	}
+	if err = req.Validate(); err != nil {
+		return
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return
+	}
+	for _, scope := range req.Scopes {
+		if !hasClaim(user.Claims, scope) {
+			return resp, apierror.Unauthorized(fmt.Errorf("scope %s is not a claim of the user", scope))
+		}
+	}
+
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		return
+	}
+	now := time.Now().UTC()
+	key := entities.APIKey{
+		UserID:     userID,
+		Name:       req.Name,
+		Prefix:     secret[:apiKeyPrefixLength],
+		SecretHash: hashAPIKeySecret(secret),
+		Scopes:     req.Scopes,
+		ExpiresAt:  req.ExpiresAt,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+	key.ID, err = s.repository.Create(ctx, key)
+	if err != nil {
+		return
+	}
+
+	resp = models.APIKeySecretResp{
+		APIKeyResp: mapping.APIKeyResp(key),
+		Key:        secret,
+	}
+	return
+}
+
+// GetByUser returns the API keys of a user, newest first, without their secrets
+func (s *apiKeyService) GetByUser(ctx context.Context, caller models.Caller, userID string) ([]models.APIKeyResp, error) {
+	if err := authorizeAPIKeys(caller, userID); err != nil {
+		return nil, err
+	}
+	keys, err := s.repository.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]models.APIKeyResp, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, mapping.APIKeyResp(key))
+	}
+	return resp, nil
+}
+
+// GetAll returns the API keys of every user, without their secrets
+func (s *apiKeyService) GetAll(ctx context.Context) ([]models.APIKeyResp, error) {
+	result, err := s.repository.Get(ctx, map[string]interface{}{}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return nil, err
+	}
+
+	resp := make([]models.APIKeyResp, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, mapping.APIKeyResp(*r.(*entities.APIKey)))
+	}
+	return resp, nil
+}
+
+// Rotate replaces the secret of an API key of a user, keeping its scopes and expiry, responding with the new secret.
+// The previous secret no longer authenticates the requests.
+func (s *apiKeyService) Rotate(ctx context.Context, caller models.Caller, userID, ID string) (resp models.APIKeySecretResp, err error) {
+	key, err := s.userKey(ctx, caller, userID, ID)
+	if err != nil {
+		return
+	}
+
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		return
+	}
+	now := time.Now().UTC()
+	key.Prefix = secret[:apiKeyPrefixLength]
+	err = s.repository.Rotate(ctx, ID, key.Prefix, hashAPIKeySecret(secret), now)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("API key ID %s not found or revoked", ID))
+	}
+	if err != nil {
+		return
+	}
+
+	resp = models.APIKeySecretResp{
+		APIKeyResp: mapping.APIKeyResp(key),
+		Key:        secret,
+	}
+	return
+}
+
+// Revoke an API key of a user, so it no longer authenticates the requests, while still listed
+func (s *apiKeyService) Revoke(ctx context.Context, caller models.Caller, userID, ID string) (err error) {
+	if _, err = s.userKey(ctx, caller, userID, ID); err != nil {
+		return
+	}
+
+	err = s.repository.Revoke(ctx, ID, time.Now().UTC())
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("API key ID %s not found or revoked", ID))
+	}
+	return
+}
+
+// userKey returns the API key with the ID when it is one of the user and the caller is allowed to manage them
+func (s *apiKeyService) userKey(ctx context.Context, caller models.Caller, userID, ID string) (key entities.APIKey, err error) {
+	if err = authorizeAPIKeys(caller, userID); err != nil {
+		return
+	}
+	result, err := s.repository.GetByID(ctx, ID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("API key ID %s not found", ID))
+	}
+	if err != nil {
+		return
+	}
+	key = *result.(*entities.APIKey)
+	if key.UserID != userID {
+		err = apierror.NotFound(fmt.Errorf("API key ID %s not found", ID))
+	}
+	return
+}
+
+// authorizeAPIKeys returns an unauthorized error unless the caller is the user or an admin, calling with a token,
+// so an API key never creates, rotates or revokes keys, wider than its own scopes or not
+func authorizeAPIKeys(caller models.Caller, userID string) error {
+	if caller.APIKeyID != "" {
+		return apierror.Unauthorized(fmt.Errorf("the API keys cannot be managed with an API key"))
+	}
+	if caller.Admin || caller.UserID == userID {
+		return nil
+	}
+	return apierror.Unauthorized(fmt.Errorf("the API keys of user ID %s are only managed by the user and the admins", userID))
+}
+
+// hasClaim reports whether the claims contain the one with the name
+func hasClaim(claims []int64, name string) bool {
+	for _, claim := range claims {
+		if entities.UserClaim(claim).String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// newAPIKeySecret returns a random secret of an API key, starting with its prefix
+func newAPIKeySecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return entities.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashAPIKeySecret returns the hash the secret of an API key is stored and looked up by
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiKeyTokenKeys decorator of a key service that also authenticates the API keys
+type apiKeyTokenKeys struct {
+	ports.KeyService
+	repository ports.APIKeyRepository
+	users      ports.UserService
+	logger     zerolog.Logger
+}
+
+// NewAPIKeyTokenKeys wraps a key service parsing the API keys into the claims of their users and scopes
+func NewAPIKeyTokenKeys(keys ports.KeyService, repository ports.APIKeyRepository, users ports.UserService, logger zerolog.Logger) ports.KeyService {
+	return &apiKeyTokenKeys{
+		KeyService: keys,
+		repository: repository,
+		users:      users,
+		logger:     logger,
+	}
+}
+
+// Parse returns the claims of an active API key, only its scopes still claimed by its user, or the ones of a token verified by the decorated service
+func (k *apiKeyTokenKeys) Parse(ctx context.Context, token string) (jwt.MapClaims, error) {
+	if !strings.HasPrefix(token, entities.APIKeyPrefix) {
+		return k.KeyService.Parse(ctx, token)
+	}
+
+	key, err := k.repository.GetBySecretHash(ctx, hashAPIKeySecret(token))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if !key.Active(now) {
+		return nil, fmt.Errorf("API key %s revoked or expired", key.Prefix)
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := k.repository.Touch(ctx, key.ID, now); err != nil {
+			k.logger.Warn().Err(err).Str("api_key", key.ID).Msg("last use of the API key cannot be stored")
+		}
+	}
+
+	claims := jwt.MapClaims{
+		"authorized": true,
+		"user_id":    key.UserID,
+		"sub":        key.UserID,
+		"api_key_id": key.ID,
+	}
+	if key.ExpiresAt != nil {
+		claims["exp"] = key.ExpiresAt.Unix()
+	}
+	user, err := k.users.GetByID(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range key.Scopes {
+		if hasClaim(user.Claims, scope) {
+			claims[scope] = true
+		}
+	}
+	return claims, nil
+}
+
+// apiKeyUserService decorator of an user service that revokes the API keys of the users deleted
+type apiKeyUserService struct {
+	ports.UserService
+	repository ports.APIKeyRepository
+	logger     zerolog.Logger
+}
+
+// NewAPIKeyUserService wraps a user service revoking the API keys of the users deleted
+func NewAPIKeyUserService(service ports.UserService, repository ports.APIKeyRepository, logger zerolog.Logger) ports.UserService {
+	return &apiKeyUserService{
+		UserService: service,
+		repository:  repository,
+		logger:      logger,
+	}
+}
+
+func (s *apiKeyUserService) Delete(ctx context.Context, ID string) error {
+	if err := s.UserService.Delete(ctx, ID); err != nil {
+		return err
+	}
+	if err := s.repository.RevokeByUser(ctx, ID, time.Now().UTC()); err != nil {
+		s.logger.Error().Err(err).Str("user", ID).Msg("API keys of the deleted user cannot be revoked")
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreateAPIKey_Ok checks that Create stores the hash of the secret of the key, responding with the secret
+func TestCreateAPIKey_Ok(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-user").Return(models.UserResp{ID: "test-user", Claims: []int64{0}}, nil).Once()
+	var stored entities.APIKey
+	apiKeyRepositoryMock := mocks.NewAPIKeyRepository(t)
+	apiKeyRepositoryMock.On(testutils.FunctionName(t, ports.APIKeyRepository.Create), context.Background(), mock.MatchedBy(func(key entities.APIKey) bool {
+		stored = key
+		return true
+	})).Return("test-key", nil).Once()
+
+	service := NewAPIKeyService(apiKeyRepositoryMock, userServiceMock)
+
+	// Act
+	resp, err := service.Create(context.Background(), models.Caller{UserID: "test-user"}, "test-user", models.CreateAPIKeyReq{Name: "test", Scopes: []string{"admin"}})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-key", resp.ID)
+	assert.True(t, strings.HasPrefix(resp.Key, entities.APIKeyPrefix))
+	assert.Equal(t, hashAPIKeySecret(resp.Key), stored.SecretHash)
+	assert.Equal(t, resp.Key[:apiKeyPrefixLength], resp.Prefix)
+	assert.Equal(t, []string{"admin"}, resp.Scopes)
+}
+
+// TestCreateAPIKey_ScopeNotGranted checks that Create returns an unauthorized error when a user grants its key a claim it does not have
+func TestCreateAPIKey_ScopeNotGranted(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-user").Return(models.UserResp{ID: "test-user"}, nil).Once()
+
+	service := NewAPIKeyService(mocks.NewAPIKeyRepository(t), userServiceMock)
+
+	// Act
+	_, err := service.Create(context.Background(), models.Caller{UserID: "test-user"}, "test-user", models.CreateAPIKeyReq{Name: "test", Scopes: []string{"admin"}})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestCreateAPIKey_OtherUser checks that Create returns an unauthorized error when a user creates a key of another user
+func TestCreateAPIKey_OtherUser(t *testing.T) {
+	// Arrange
+	service := NewAPIKeyService(mocks.NewAPIKeyRepository(t), mocks.NewUserService(t))
+
+	// Act
+	_, err := service.Create(context.Background(), models.Caller{UserID: "test-user"}, "other-user", models.CreateAPIKeyReq{Name: "test"})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestCreateAPIKey_WithAPIKey checks that Create returns an unauthorized error when the caller calls with an API key,
+// even one of the user with the admin scope
+func TestCreateAPIKey_WithAPIKey(t *testing.T) {
+	// Arrange
+	service := NewAPIKeyService(mocks.NewAPIKeyRepository(t), mocks.NewUserService(t))
+
+	// Act
+	_, err := service.Create(context.Background(), models.Caller{UserID: "test-user", Admin: true, APIKeyID: "test-key"}, "test-user", models.CreateAPIKeyReq{Name: "test", Scopes: []string{"admin"}})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestRotateAPIKey_Ok checks that Rotate replaces the secret of the key of the user, responding with the new one
+func TestRotateAPIKey_Ok(t *testing.T) {
+	// Arrange
+	apiKeyRepositoryMock := mocks.NewAPIKeyRepository(t)
+	apiKeyRepositoryMock.On(testutils.FunctionName(t, ports.APIKeyRepository.GetByID), context.Background(), "test-key").Return(&entities.APIKey{ID: "test-key", UserID: "test-user", Prefix: "hak_previous"}, nil).Once()
+	apiKeyRepositoryMock.On(testutils.FunctionName(t, ports.APIKeyRepository.Rotate), context.Background(), "test-key", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	service := NewAPIKeyService(apiKeyRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	resp, err := service.Rotate(context.Background(), models.Caller{Admin: true}, "test-user", "test-key")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotEqual(t, "hak_previous", resp.Prefix)
+	assert.Equal(t, resp.Key[:apiKeyPrefixLength], resp.Prefix)
+}
+
+// TestRevokeAPIKey_OtherUserKey checks that Revoke returns a not found error when the key is not one of the user
+func TestRevokeAPIKey_OtherUserKey(t *testing.T) {
+	// Arrange
+	apiKeyRepositoryMock := mocks.NewAPIKeyRepository(t)
+	apiKeyRepositoryMock.On(testutils.FunctionName(t, ports.APIKeyRepository.GetByID), context.Background(), "test-key").Return(&entities.APIKey{ID: "test-key", UserID: "other-user"}, nil).Once()
+
+	service := NewAPIKeyService(apiKeyRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.Revoke(context.Background(), models.Caller{UserID: "test-user"}, "test-user", "test-key")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+// TestRevokeAPIKey_WithAPIKey checks that Revoke returns an unauthorized error when the caller calls with an API key
+func TestRevokeAPIKey_WithAPIKey(t *testing.T) {
+	// Arrange
+	service := NewAPIKeyService(mocks.NewAPIKeyRepository(t), mocks.NewUserService(t))
+
+	// Act
+	err := service.Revoke(context.Background(), models.Caller{UserID: "test-user", APIKeyID: "test-key"}, "test-user", "other-key")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestParseAPIKey_Ok checks that the decorated Parse returns the claims of the user and scopes of the key of the secret, storing its use
+func TestParseAPIKey_Ok(t *testing.T) {
+	// Arrange
+	secret := entities.APIKeyPrefix + "test-secret"
+	apiKeyRepositoryMock := mocks.NewAPIKeyRepository(t)
+	apiKeyRepositoryMock.On(testutils.FunctionName(t, ports.APIKeyRepository.GetBySecretHash), context.Background(), hashAPIKeySecret(secret)).Return(entities.APIKey{ID: "test-key", UserID: "test-user", Scopes: []string{"admin"}}, nil).Once()
+	apiKeyRepositoryMock.On(testutils.FunctionName(t, ports.APIKeyRepository.Touch), context.Background(), "test-key", mock.AnythingOfType("time.Time")).Return(nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-user").Return(models.UserResp{ID: "test-user", Claims: []int64{0}}, nil).Once()
+
+	keys := NewAPIKeyTokenKeys(mocks.NewKeyService(t), apiKeyRepositoryMock, userServiceMock, zerolog.Nop())
+
+	// Act
+	claims, err := keys.Parse(context.Background(), secret)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-user", claims["user_id"])
+	assert.Equal(t, "test-key", claims["api_key_id"])
+	assert.Equal(t, true, claims["admin"])
+}
+
+// TestParseAPIKey_Demoted checks that the decorated Parse does not grant the scopes of the key that are no longer claims of its user
+func TestParseAPIKey_Demoted(t *testing.T) {
+	// Arrange
+	secret := entities.APIKeyPrefix + "test-secret"
+	lastUsedAt := time.Now().UTC()
+	apiKeyRepositoryMock := mocks.NewAPIKeyRepository(t)
+	apiKeyRepositoryMock.On(testutils.FunctionName(t, ports.APIKeyRepository.GetBySecretHash), context.Background(), hashAPIKeySecret(secret)).Return(entities.APIKey{ID: "test-key", UserID: "test-user", Scopes: []string{"admin"}, LastUsedAt: &lastUsedAt}, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-user").Return(models.UserResp{ID: "test-user"}, nil).Once()
+
+	keys := NewAPIKeyTokenKeys(mocks.NewKeyService(t), apiKeyRepositoryMock, userServiceMock, zerolog.Nop())
+
+	// Act
+	claims, err := keys.Parse(context.Background(), secret)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-user", claims["user_id"])
+	assert.NotContains(t, claims, "admin")
+}
+
+// TestParseAPIKey_Expired checks that the decorated Parse returns an error when the key of the secret is expired
+func TestParseAPIKey_Expired(t *testing.T) {
+	// Arrange
+	secret := entities.APIKeyPrefix + "test-secret"
+	expiresAt := time.Now().UTC().Add(-time.Minute)
+	apiKeyRepositoryMock := mocks.NewAPIKeyRepository(t)
+	apiKeyRepositoryMock.On(testutils.FunctionName(t, ports.APIKeyRepository.GetBySecretHash), context.Background(), hashAPIKeySecret(secret)).Return(entities.APIKey{ID: "test-key", ExpiresAt: &expiresAt}, nil).Once()
+
+	keys := NewAPIKeyTokenKeys(mocks.NewKeyService(t), apiKeyRepositoryMock, mocks.NewUserService(t), zerolog.Nop())
+
+	// Act
+	_, err := keys.Parse(context.Background(), secret)
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+// TestParseAPIKey_Token checks that the decorated Parse verifies the tokens that are not secrets of API keys with the decorated keys
+func TestParseAPIKey_Token(t *testing.T) {
+	// Arrange
+	keyServiceMock := mocks.NewKeyService(t)
+	keyServiceMock.On(testutils.FunctionName(t, ports.KeyService.Parse), context.Background(), "test-token").Return(jwt.MapClaims{"user_id": "test-user"}, nil).Once()
+
+	keys := NewAPIKeyTokenKeys(keyServiceMock, mocks.NewAPIKeyRepository(t), mocks.NewUserService(t), zerolog.Nop())
+
+	// Act
+	claims, err := keys.Parse(context.Background(), "test-token")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-user", claims["user_id"])
+}
+
+// TestDeleteAPIKeyUser_Ok checks that the decorated Delete revokes the API keys of the user deleted, not failing when it cannot
+func TestDeleteAPIKeyUser_Ok(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Delete), context.Background(), "test-user").Return(nil).Once()
+	apiKeyRepositoryMock := mocks.NewAPIKeyRepository(t)
+	apiKeyRepositoryMock.On(testutils.FunctionName(t, ports.APIKeyRepository.RevokeByUser), context.Background(), "test-user", mock.AnythingOfType("time.Time")).Return(assert.AnError).Once()
+
+	service := NewAPIKeyUserService(userServiceMock, apiKeyRepositoryMock, zerolog.Nop())
+
+	// Act
+	err := service.Delete(context.Background(), "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+}
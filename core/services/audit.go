@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// auditService adapter of an audit service
+type auditService struct {
+	repository ports.AuditRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(repo ports.AuditRepository) ports.AuditService {
+	return &auditService{
+		repository: repo,
+	}
+}
+
+// Get audit events, newest first
+func (s *auditService) Get(ctx context.Context, req models.GetAuditEventsReq) (resp []models.AuditEventResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	filter := ports.AuditFilter{
+		Type:    req.Type,
+		Outcome: req.Outcome,
+		UserID:  req.UserID,
+		ActorID: req.ActorID,
+		From:    req.From,
+		To:      req.To,
+	}
+	events, err := s.repository.Get(ctx, filter, &req.Skip, &req.Take)
+	if err != nil {
+		return
+	}
+
+	resp = make([]models.AuditEventResp, 0, len(events))
+	for _, e := range events {
+		resp = append(resp, models.AuditEventResp(e))
+	}
+	return
+}
+
+// GetByID gets an audit event by ID
+func (s *auditService) GetByID(ctx context.Context, ID string) (resp models.AuditEventResp, err error) {
+	event, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = apierror.NotFound(fmt.Errorf("audit event ID %s not found", ID))
+		}
+		return
+	}
+
+	resp = models.AuditEventResp(event)
+	return
+}
+
+// Record writes a successful event not performed on a user to the audit log
+func (s *auditService) Record(ctx context.Context, eventType string, details map[string]string) error {
+	return s.repository.Write(ctx, newAuditEvent(ctx, eventType, entities.AuditOutcomeSuccess, "", details))
+}
+
+// auditCSVHeader is the header of the exported audit events
+var auditCSVHeader = []string{"id", "created_at", "type", "outcome", "user_id", "actor_id", "ip", "details"}
+
+// Export writes every audit event matching the filters of the request as CSV, newest first, reading them in pages of the maximum take.
+// The time range is closed at the start of the export, so the events written meanwhile do not shift the pages.
+func (s *auditService) Export(ctx context.Context, req models.ExportAuditEventsReq, w io.Writer) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	filter := ports.AuditFilter{
+		Type:    req.Type,
+		Outcome: req.Outcome,
+		UserID:  req.UserID,
+		ActorID: req.ActorID,
+		From:    req.From,
+		To:      req.To,
+	}
+	if filter.To == nil {
+		now := time.Now().UTC()
+		filter.To = &now
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(auditCSVHeader); err != nil {
+		return err
+	}
+	take := models.MaxAuditEventsTake
+	for skip := 0; ; skip += take {
+		events, err := s.repository.Get(ctx, filter, &skip, &take)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			if err := cw.Write(auditCSVRecord(e)); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+		if len(events) < take {
+			return nil
+		}
+	}
+}
+
+// auditCSVRecord returns the CSV record of an audit event, with its details as a JSON object
+func auditCSVRecord(e entities.AuditEvent) []string {
+	var details string
+	if len(e.Details) > 0 {
+		b, _ := json.Marshal(e.Details)
+		details = string(b)
+	}
+	record := []string{e.ID, e.CreatedAt.Format(time.RFC3339Nano), e.Type, e.Outcome, e.UserID, e.ActorID, e.IP, details}
+	for i, field := range record {
+		record[i] = escapeCSVFormula(field)
+	}
+	return record
+}
+
+// escapeCSVFormula prefixes with a quote the fields that spreadsheets would run as formulas, like the emails of failed logins set by anyone
+func escapeCSVFormula(field string) string {
+	if field != "" && strings.ContainsRune("=+-@\t\r", rune(field[0])) {
+		return "'" + field
+	}
+	return field
+}
+
+// record writes a succeeded security relevant event on the target user to the audit log, performed by the actor of the request of the context
+func record(ctx context.Context, logger zerolog.Logger, audit ports.AuditRepository, eventType, userID string, details map[string]string) {
+	recordOutcome(ctx, logger, audit, eventType, entities.AuditOutcomeSuccess, userID, details)
+}
+
+// recordFailure writes a failed security relevant event on the target user to the audit log, attempted by the actor of the request of the context
+func recordFailure(ctx context.Context, logger zerolog.Logger, audit ports.AuditRepository, eventType, userID string, details map[string]string) {
+	recordOutcome(ctx, logger, audit, eventType, entities.AuditOutcomeFailure, userID, details)
+}
+
+// recordOutcome writes a security relevant event to the audit log.
+// Failing to write it is logged without failing the audited operation, and nothing is recorded without an audit repository.
+func recordOutcome(ctx context.Context, logger zerolog.Logger, audit ports.AuditRepository, eventType, outcome, userID string, details map[string]string) {
+	if audit == nil {
+		return
+	}
+
+	if err := audit.Write(ctx, newAuditEvent(ctx, eventType, outcome, userID, details)); err != nil {
+		logger.Error().Err(err).Str("type", eventType).Str("user_id", userID).Msg("audit event cannot be written")
+	}
+}
+
+// newAuditEvent creates an audit event on the target user, attributed to the actor of the request of the context
+func newAuditEvent(ctx context.Context, eventType, outcome, userID string, details map[string]string) entities.AuditEvent {
+	info := models.RequestInfoFrom(ctx)
+	return entities.AuditEvent{
+		Type:      eventType,
+		Outcome:   outcome,
+		UserID:    userID,
+		ActorID:   info.ActorID,
+		IP:        info.IP,
+		Details:   details,
+		CreatedAt: time.Now().UTC(),
+	}
+}
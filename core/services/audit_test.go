@@ -0,0 +1,256 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewAuditService_Ok checks that NewAuditService creates a new auditService struct
+func TestNewAuditService_Ok(t *testing.T) {
+	// Arrange
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+
+	// Act
+	service := NewAuditService(auditRepositoryMock)
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestGetAuditEvents_Ok checks that Get returns the events matching the filters of the request
+func TestGetAuditEvents_Ok(t *testing.T) {
+	// Arrange
+	req := models.GetAuditEventsReq{
+		Type: entities.AuditLoginFailed,
+		Skip: 10,
+		Take: 5,
+	}
+	expectedEvents := []entities.AuditEvent{{ID: "test-id", Type: entities.AuditLoginFailed}}
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Get), context.Background(), ports.AuditFilter{Type: req.Type}, &req.Skip, &req.Take).Return(expectedEvents, nil).Once()
+
+	service := &auditService{
+		repository: auditRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Get(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.AuditEventResp{models.AuditEventResp(expectedEvents[0])}, resp)
+}
+
+// TestGetAuditEvents_InvalidRequest checks that Get returns an error when the received request is not valid
+func TestGetAuditEvents_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &auditService{}
+
+	// Act
+	_, err := service.Get(context.Background(), models.GetAuditEventsReq{})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestGetAuditEventByID_Ok checks that GetByID returns the event with the given ID
+func TestGetAuditEventByID_Ok(t *testing.T) {
+	// Arrange
+	expectedEvent := entities.AuditEvent{ID: "test-id", Type: entities.AuditUserDeleted}
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.GetByID), context.Background(), "test-id").Return(expectedEvent, nil).Once()
+
+	service := &auditService{
+		repository: auditRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.AuditEventResp(expectedEvent), resp)
+}
+
+// TestGetAuditEventByID_NotFound checks that GetByID returns a not found error naming the ID when the event does not exist
+func TestGetAuditEventByID_NotFound(t *testing.T) {
+	// Arrange
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.GetByID), context.Background(), "test-id").Return(entities.AuditEvent{}, wrappers.NewNonExistentErr(errors.New("no documents"))).Once()
+
+	service := &auditService{
+		repository: auditRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+	assert.Equal(t, "audit event ID test-id not found", err.Error())
+}
+
+// TestExportAuditEvents_Ok checks that Export writes as CSV every event matching the filters, reading them page by page
+func TestExportAuditEvents_Ok(t *testing.T) {
+	// Arrange
+	req := models.ExportAuditEventsReq{
+		Type: entities.AuditLoginFailed,
+	}
+	createdAt := time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC)
+	firstPage := make([]entities.AuditEvent, models.MaxAuditEventsTake)
+	for i := range firstPage {
+		firstPage[i] = entities.AuditEvent{ID: "test-id", Type: entities.AuditLoginFailed, Outcome: entities.AuditOutcomeFailure, CreatedAt: createdAt}
+	}
+	secondPage := []entities.AuditEvent{{
+		ID:        "last-id",
+		Type:      entities.AuditLoginFailed,
+		Outcome:   entities.AuditOutcomeFailure,
+		IP:        "127.0.0.1",
+		Details:   map[string]string{"email": "=cmd@test.com", "reason": "password incorrect"},
+		CreatedAt: createdAt,
+	}}
+
+	filterMatcher := mock.MatchedBy(func(filter ports.AuditFilter) bool {
+		return filter.Type == req.Type && filter.From == nil && filter.To != nil
+	})
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Get), context.Background(), filterMatcher, mock.MatchedBy(func(skip *int) bool { return *skip == 0 }), mock.Anything).Return(firstPage, nil).Once()
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Get), context.Background(), filterMatcher, mock.MatchedBy(func(skip *int) bool { return *skip == models.MaxAuditEventsTake }), mock.Anything).Return(secondPage, nil).Once()
+
+	service := &auditService{
+		repository: auditRepositoryMock,
+	}
+	var buf bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), req, &buf)
+
+	// Assert
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, models.MaxAuditEventsTake+2)
+	assert.Equal(t, "id,created_at,type,outcome,user_id,actor_id,ip,details", lines[0])
+	assert.Equal(t, "test-id,2023-07-15T09:00:00Z,login_failed,failure,,,,", lines[1])
+	assert.Equal(t, `last-id,2023-07-15T09:00:00Z,login_failed,failure,,,127.0.0.1,"{""email"":""=cmd@test.com"",""reason"":""password incorrect""}"`, lines[len(lines)-1])
+}
+
+// TestExportAuditEvents_InvalidRequest checks that Export returns an error without writing when the received request is not valid
+func TestExportAuditEvents_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &auditService{}
+	var buf bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), models.ExportAuditEventsReq{Outcome: "unknown"}, &buf)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Empty(t, buf.String())
+}
+
+// TestExportAuditEvents_GetError checks that Export returns an error without writing when the first page cannot be read
+func TestExportAuditEvents_GetError(t *testing.T) {
+	// Arrange
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Get), context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("repository-error")).Once()
+
+	service := &auditService{
+		repository: auditRepositoryMock,
+	}
+	var buf bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), models.ExportAuditEventsReq{}, &buf)
+
+	// Assert
+	assert.Equal(t, "repository-error", err.Error())
+	assert.Empty(t, buf.String())
+}
+
+// TestRecordAuditEvent_Ok checks that Record writes a successful event with the given details and no target user
+func TestRecordAuditEvent_Ok(t *testing.T) {
+	// Arrange
+	details := map[string]string{"Log.Level": "debug"}
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(event entities.AuditEvent) bool {
+		return event.Type == entities.AuditConfigReloaded && event.Outcome == entities.AuditOutcomeSuccess && event.UserID == "" &&
+			event.Details["Log.Level"] == "debug" && !event.CreatedAt.IsZero()
+	})).Return(errors.New("repository-error")).Once()
+
+	service := &auditService{
+		repository: auditRepositoryMock,
+	}
+
+	// Act
+	err := service.Record(context.Background(), entities.AuditConfigReloaded, details)
+
+	// Assert
+	assert.Equal(t, "repository-error", err.Error())
+}
+
+// TestEscapeCSVFormula_Ok checks that escapeCSVFormula prefixes with a quote only the fields starting like a formula
+func TestEscapeCSVFormula_Ok(t *testing.T) {
+	// Act
+	escaped := escapeCSVFormula("=HYPERLINK(\"http://test\")")
+	plain := escapeCSVFormula("test@test.com")
+
+	// Assert
+	assert.Equal(t, "'=HYPERLINK(\"http://test\")", escaped)
+	assert.Equal(t, "test@test.com", plain)
+}
+
+// TestRecord_WriteError checks that record does not panic nor fail when the event cannot be written
+func TestRecord_WriteError(t *testing.T) {
+	// Arrange
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(event entities.AuditEvent) bool {
+		return event.Type == entities.AuditUserDeleted && event.Outcome == entities.AuditOutcomeSuccess && event.UserID == "test-id" && !event.CreatedAt.IsZero()
+	})).Return(errors.New("repository-error")).Once()
+
+	// Act
+	record(context.Background(), zerolog.Nop(), auditRepositoryMock, entities.AuditUserDeleted, "test-id", nil)
+
+	// Assert
+	// the expectations of the mock are asserted on cleanup
+}
+
+// TestRecordFailure_RequestInfo checks that recordFailure writes a failed event with the actor and the IP of the request of the context
+func TestRecordFailure_RequestInfo(t *testing.T) {
+	// Arrange
+	ctx := models.WithRequestInfo(context.Background(), models.RequestInfo{RequestID: "test-request", ActorID: "test-actor", IP: "10.0.0.1"})
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), ctx, mock.MatchedBy(func(event entities.AuditEvent) bool {
+		return event.Type == entities.AuditPasswordChanged && event.Outcome == entities.AuditOutcomeFailure &&
+			event.UserID == "test-id" && event.ActorID == "test-actor" && event.IP == "10.0.0.1"
+	})).Return(nil).Once()
+
+	// Act
+	recordFailure(ctx, zerolog.Nop(), auditRepositoryMock, entities.AuditPasswordChanged, "test-id", nil)
+
+	// Assert
+	// the expectations of the mock are asserted on cleanup
+}
+
+// TestRecord_NoRepository checks that record does nothing without an audit repository
+func TestRecord_NoRepository(t *testing.T) {
+	// Act
+	record(context.Background(), zerolog.Nop(), nil, entities.AuditUserDeleted, "test-id", nil)
+}
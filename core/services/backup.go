@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// backupNameLayout is the time layout of the names of the backups
+const backupNameLayout = "20060102T150405Z"
+
+// progressInterval is the number of documents processed between progress reports of a job
+const progressInterval = 1000
+
+// backupNameRegex matches the valid backup names, preventing them from pointing outside the backups of the collection
+var backupNameRegex = regexp.MustCompile(`^[\w-]+$`)
+
+// backupService adapter of a backup service.
+// Backups are stored as JSON lines files, one document per line, in the file storage.
+type backupService struct {
+	config  config.Config
+	logger  zerolog.Logger
+	users   ports.UserRepository
+	storage ports.FileStorage
+	jobs    ports.JobRepository
+	audit   ports.AuditRepository
+}
+
+// NewBackupService creates a new backup service
+func NewBackupService(cfg config.Config, logger zerolog.Logger, users ports.UserRepository, storage ports.FileStorage, jobs ports.JobRepository, audit ports.AuditRepository) ports.BackupService {
+	return &backupService{
+		config:  cfg,
+		logger:  logger,
+		users:   users,
+		storage: storage,
+		jobs:    jobs,
+		audit:   audit,
+	}
+}
+
+// Backup queues the dump of a collection to the file storage, the backup is named after the time it was queued
+func (s *backupService) Backup(ctx context.Context, collection string) (resp models.JobResp, err error) {
+	if err = validateBackupCollection(collection); err != nil {
+		return
+	}
+
+	name := time.Now().UTC().Format(backupNameLayout)
+	job, err := s.startJob(ctx, entities.JobTypeBackup, map[string]string{"collection": collection, "backup": name})
+	if err != nil {
+		return
+	}
+	record(ctx, s.logger, s.audit, entities.AuditBackupStarted, "", job.Metadata)
+
+	resp = models.JobResp(job)
+	return
+}
+
+// Restore queues the replacement of all the documents of a collection with the ones of the named backup
+func (s *backupService) Restore(ctx context.Context, collection, name string) (resp models.JobResp, err error) {
+	if err = validateBackupCollection(collection); err != nil {
+		return
+	}
+	if !backupNameRegex.MatchString(name) {
+		err = wrappers.NewValidationErr(fmt.Errorf("backup name %s not valid", name))
+		return
+	}
+
+	_, err = s.storage.Stat(ctx, backupKey(collection, name))
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("backup %s of collection %s not found", name, collection))
+		}
+		return
+	}
+
+	job, err := s.startJob(ctx, entities.JobTypeRestore, map[string]string{"collection": collection, "backup": name})
+	if err != nil {
+		return
+	}
+	record(ctx, s.logger, s.audit, entities.AuditRestoreStarted, "", job.Metadata)
+
+	resp = models.JobResp(job)
+	return
+}
+
+func (s *backupService) startJob(ctx context.Context, jobType string, metadata map[string]string) (job entities.Job, err error) {
+	job = newQueuedJob(s.config, jobType, metadata)
+	job.ID, err = s.jobs.Create(ctx, job)
+	return
+}
+
+// Process runs a backup or restore job claimed by a worker, counting its documents from the start on every attempt
+func (s *backupService) Process(ctx context.Context, job *entities.Job) error {
+	job.Processed = 0
+	collection, name := job.Metadata["collection"], job.Metadata["backup"]
+	switch job.Type {
+	case entities.JobTypeBackup:
+		return s.backup(ctx, job, collection, name)
+	case entities.JobTypeRestore:
+		return s.restore(ctx, job, collection, name)
+	default:
+		return fmt.Errorf("job type %s not processed by the backups", job.Type)
+	}
+}
+
+// processed counts a processed document, reporting the progress of the job every progressInterval documents
+func (s *backupService) processed(ctx context.Context, job *entities.Job) {
+	job.Processed++
+	if job.Processed%progressInterval != 0 {
+		return
+	}
+
+	job.UpdatedAt = time.Now().UTC()
+	if err := s.jobs.Update(ctx, *job); err != nil {
+		s.logger.Error().Err(err).Str("type", job.Type).Str("job_id", job.ID).Msg("job cannot be updated")
+	}
+}
+
+// backup streams the dumped documents to the file storage
+func (s *backupService) backup(ctx context.Context, job *entities.Job, collection, name string) error {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		encoder := json.NewEncoder(pw)
+		// the dump is only bounded by the timeout of the job, as it takes as long as the collection is big
+		err := s.users.Dump(ports.WithOperationTimeout(ctx, 0), func(user entities.User) error {
+			if err := encoder.Encode(user); err != nil {
+				return err
+			}
+			s.processed(ctx, job)
+			return nil
+		})
+		pw.CloseWithError(err)
+	}()
+
+	file := entities.File{
+		Key:         backupKey(collection, name),
+		Name:        name + ".jsonl",
+		ContentType: "application/x-ndjson",
+		Metadata:    map[string]string{"collection": collection},
+	}
+	_, err := s.storage.Store(ctx, file, pr)
+
+	// unblocks the dump if the storage stopped reading before the end
+	pr.CloseWithError(err)
+	<-done
+	return err
+}
+
+// restore reads all the documents of the backup before replacing the collection with them,
+// so a corrupted backup is detected without modifying the collection
+func (s *backupService) restore(ctx context.Context, job *entities.Job, collection, name string) error {
+	content, _, err := s.storage.Open(ctx, backupKey(collection, name))
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	var users []entities.User
+	decoder := json.NewDecoder(content)
+	for {
+		var user entities.User
+		err = decoder.Decode(&user)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("backup %s of collection %s not valid: %w", name, collection, err)
+		}
+		users = append(users, user)
+		s.processed(ctx, job)
+	}
+
+	return s.users.Restore(ports.WithOperationTimeout(ctx, 0), users)
+}
+
+func validateBackupCollection(collection string) error {
+	if collection != entities.EntityNameUser {
+		return wrappers.NewValidationErr(fmt.Errorf("collection %s cannot be backed up, valid collections: %s", collection, entities.EntityNameUser))
+	}
+	return nil
+}
+
+func backupKey(collection, name string) string {
+	return fmt.Sprintf("backups/%s/%s.jsonl", collection, name)
+}
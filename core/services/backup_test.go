@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewBackupService_Ok checks that NewBackupService creates a new backupService struct
+func TestNewBackupService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	fileStorageMock := mocks.NewFileStorage(t)
+	jobRepositoryMock := mocks.NewJobRepository(t)
+
+	// Act
+	service := NewBackupService(cfg, zerolog.Nop(), userRepositoryMock, fileStorageMock, jobRepositoryMock, mocks.NewAuditRepository(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestBackup_Ok checks that Backup returns the queued job of the backup of the collection
+func TestBackup_Ok(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Create), context.Background(), mock.MatchedBy(func(job entities.Job) bool {
+		return job.Status == entities.JobStatusQueued && job.MaxAttempts == 3 && !job.RunAt.IsZero()
+	})).Return("job-id", nil).Once()
+
+	cfg := config.Config{}
+	cfg.Queue.MaxAttempts = 3
+	service := &backupService{
+		config: cfg,
+		jobs:   jobRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Backup(context.Background(), entities.EntityNameUser)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "job-id", resp.ID)
+	assert.Equal(t, entities.JobTypeBackup, resp.Type)
+	assert.Equal(t, entities.JobStatusQueued, resp.Status)
+	assert.Equal(t, entities.EntityNameUser, resp.Metadata["collection"])
+	assert.NotEmpty(t, resp.Metadata["backup"])
+}
+
+// bufferingStorage is a file storage reading the whole content before storing it,
+// so the mock never formats a pipe still being written by the dump
+type bufferingStorage struct {
+	*mocks.FileStorage
+}
+
+func (s bufferingStorage) Store(ctx context.Context, file entities.File, content io.Reader) (entities.File, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return entities.File{}, err
+	}
+	return s.FileStorage.Store(ctx, file, strings.NewReader(string(data)))
+}
+
+// TestProcess_Backup checks that Process stores every dumped user of a backup job
+func TestProcess_Backup(t *testing.T) {
+	// Arrange
+	user := entities.User{ID: "test-id", Email: "test@test.com", PasswordHash: "test-hash"}
+
+	var stored string
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Dump), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(func(entities.User) error)(user)
+	}).Return(nil).Once()
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Store), mock.Anything, mock.MatchedBy(func(file entities.File) bool {
+		return file.Key == "backups/users/test.jsonl"
+	}), mock.AnythingOfType("*strings.Reader")).Run(func(args mock.Arguments) {
+		content, _ := io.ReadAll(args.Get(2).(io.Reader))
+		stored = string(content)
+	}).Return(entities.File{}, nil).Once()
+
+	service := &backupService{
+		users:   userRepositoryMock,
+		storage: bufferingStorage{fileStorageMock},
+	}
+	job := entities.Job{ID: "job-id", Type: entities.JobTypeBackup, Processed: 5, Metadata: map[string]string{"collection": entities.EntityNameUser, "backup": "test"}}
+
+	// Act
+	err := service.Process(context.Background(), &job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), job.Processed)
+	assert.Contains(t, stored, user.PasswordHash)
+}
+
+// TestBackup_InvalidCollection checks that Backup returns an error when the collection cannot be backed up
+func TestBackup_InvalidCollection(t *testing.T) {
+	// Arrange
+	service := &backupService{}
+
+	// Act
+	_, err := service.Backup(context.Background(), "invalid")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestBackup_CreateJobError checks that Backup returns an error when the job cannot be created
+func TestBackup_CreateJobError(t *testing.T) {
+	// Arrange
+	expectedError := "repository-error"
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Create), context.Background(), mock.AnythingOfType("entities.Job")).Return("", errors.New(expectedError)).Once()
+
+	service := &backupService{
+		jobs: jobRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Backup(context.Background(), entities.EntityNameUser)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestBackup_StoreError checks that backup returns the error of the storage without leaving the dump blocked
+func TestBackup_StoreError(t *testing.T) {
+	// Arrange
+	expectedError := "storage-error"
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Dump), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(func(entities.User) error)(entities.User{})
+	}).Return(nil).Once()
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Store), mock.Anything, mock.Anything, mock.Anything).Return(entities.File{}, errors.New(expectedError)).Once()
+
+	service := &backupService{
+		users:   userRepositoryMock,
+		storage: fileStorageMock,
+	}
+
+	// Act
+	err := service.backup(context.Background(), &entities.Job{}, entities.EntityNameUser, "test")
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestRestore_Ok checks that Restore returns the queued job of the restore of the backup
+func TestRestore_Ok(t *testing.T) {
+	// Arrange
+	name := "20230625T100000Z"
+	key := fmt.Sprintf("backups/users/%s.jsonl", name)
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Stat), context.Background(), key).Return(entities.File{Key: key}, nil).Once()
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Create), context.Background(), mock.AnythingOfType("entities.Job")).Return("job-id", nil).Once()
+
+	service := &backupService{
+		storage: fileStorageMock,
+		jobs:    jobRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Restore(context.Background(), entities.EntityNameUser, name)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "job-id", resp.ID)
+	assert.Equal(t, entities.JobTypeRestore, resp.Type)
+	assert.Equal(t, entities.JobStatusQueued, resp.Status)
+	assert.Equal(t, name, resp.Metadata["backup"])
+}
+
+// TestProcess_Restore checks that Process replaces the users with the ones of the backup of a restore job
+func TestProcess_Restore(t *testing.T) {
+	// Arrange
+	key := "backups/users/test.jsonl"
+	content := `{"ID":"test-id-1","Email":"test1@test.com"}` + "\n" + `{"ID":"test-id-2","Email":"test2@test.com"}` + "\n"
+	expectedUsers := []entities.User{{ID: "test-id-1", Email: "test1@test.com"}, {ID: "test-id-2", Email: "test2@test.com"}}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Restore), mock.Anything, expectedUsers).Return(nil).Once()
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Open), mock.Anything, key).Return(io.NopCloser(strings.NewReader(content)), entities.File{Key: key}, nil).Once()
+
+	service := &backupService{
+		users:   userRepositoryMock,
+		storage: fileStorageMock,
+	}
+	job := entities.Job{ID: "job-id", Type: entities.JobTypeRestore, Metadata: map[string]string{"collection": entities.EntityNameUser, "backup": "test"}}
+
+	// Act
+	err := service.Process(context.Background(), &job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), job.Processed)
+}
+
+// TestRestore_InvalidName checks that Restore returns an error when the backup name is not valid
+func TestRestore_InvalidName(t *testing.T) {
+	// Arrange
+	service := &backupService{}
+
+	// Act
+	_, err := service.Restore(context.Background(), entities.EntityNameUser, "../avatars")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestRestore_NotFound checks that Restore returns a non existent error when the backup does not exist
+func TestRestore_NotFound(t *testing.T) {
+	// Arrange
+	name := "test"
+	expectedError := fmt.Sprintf("backup %s of collection %s not found", name, entities.EntityNameUser)
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Stat), context.Background(), "backups/users/test.jsonl").Return(entities.File{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := &backupService{
+		storage: fileStorageMock,
+	}
+
+	// Act
+	_, err := service.Restore(context.Background(), entities.EntityNameUser, name)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestRestore_InvalidBackup checks that restore returns an error without modifying the users when the backup cannot be decoded
+func TestRestore_InvalidBackup(t *testing.T) {
+	// Arrange
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Open), context.Background(), "backups/users/test.jsonl").Return(io.NopCloser(strings.NewReader("invalid")), entities.File{}, nil).Once()
+
+	service := &backupService{
+		users:   mocks.NewUserRepository(t),
+		storage: fileStorageMock,
+	}
+
+	// Act
+	err := service.restore(context.Background(), &entities.Job{}, entities.EntityNameUser, "test")
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+// TestProcess_UnknownType checks that Process returns an error when the job is neither a backup nor a restore
+func TestProcess_UnknownType(t *testing.T) {
+	// Arrange
+	service := &backupService{}
+	job := entities.Job{Type: entities.JobTypeNotification}
+
+	// Act
+	err := service.Process(context.Background(), &job)
+
+	// Assert
+	assert.EqualError(t, err, "job type notification not processed by the backups")
+}
+
+// TestProcessed_ReportsProgress checks that processed updates the job every progressInterval documents
+func TestProcessed_ReportsProgress(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Update), context.Background(), mock.MatchedBy(func(job entities.Job) bool {
+		return job.Processed == progressInterval
+	})).Return(nil).Once()
+
+	service := &backupService{
+		jobs: jobRepositoryMock,
+	}
+	job := entities.Job{Processed: progressInterval - 2}
+
+	// Act
+	service.processed(context.Background(), &job)
+	service.processed(context.Background(), &job)
+	service.processed(context.Background(), &job)
+
+	// Assert
+	assert.Equal(t, int64(progressInterval+1), job.Processed)
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// billingService adapter of the billing service, linking the users to their customers in the billing provider
+// and keeping the status of their subscriptions as posted by it
+type billingService struct {
+	logger     zerolog.Logger
+	repository ports.UserRepository
+	provider   ports.BillingProvider
+}
+
+// NewBillingService creates a new billing service.
+// The users are read from the repository rather than through the user service, so the status of their subscriptions is never a cached one.
+func NewBillingService(logger zerolog.Logger, repository ports.UserRepository, provider ports.BillingProvider) ports.BillingService {
+	return &billingService{
+		logger:     logger,
+		repository: repository,
+		provider:   provider,
+	}
+}
+
+// SyncCustomer creates the customer of the user in the billing provider, storing its ID on the user, or updates it when it already exists
+func (s *billingService) SyncCustomer(ctx context.Context, userID string) error {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	customerID, err := s.provider.SaveCustomer(ctx, ports.BillingCustomer{
+		ID:     user.BillingCustomerID,
+		UserID: userID,
+		Email:  user.Email,
+		Name:   strings.TrimSpace(user.Name + " " + user.Surnames),
+	})
+	if err != nil {
+		return err
+	}
+	if user.BillingCustomerID != "" {
+		return nil
+	}
+	return s.repository.UpdateBillingCustomer(ctx, userID, customerID)
+}
+
+// HandleEvent verifies the event posted to the webhook and sets the status of the subscription of its customer,
+// ignoring the other events and the ones of customers not linked to a user or older than the status already set
+func (s *billingService) HandleEvent(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.provider.ParseEvent(payload, signature)
+	if errors.Is(err, ports.ErrBillingSignatureNotValid) {
+		return wrappers.NewUnauthorizedErr(err)
+	}
+	if err != nil {
+		return wrappers.NewValidationErr(err)
+	}
+	if !s.provider.SubscriptionEvent(event.Type) {
+		return nil
+	}
+
+	err = s.repository.UpdateSubscription(ctx, event.CustomerID, event.SubscriptionStatus, event.CreatedAt)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		s.logger.Debug().Str("event", event.ID).Str("customer", event.CustomerID).Msg("billing event ignored, its customer is not linked or its status is newer")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.logger.Info().Str("event", event.ID).Str("customer", event.CustomerID).Str("status", event.SubscriptionStatus).Msg("subscription status updated")
+	return nil
+}
+
+// Subscribed reports whether the subscription of the user is active or in its trial
+func (s *billingService) Subscribed(ctx context.Context, userID string) (bool, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user.Subscribed(), nil
+}
+
+// Process syncs the customer of the user of a billing job, unless the user was deleted meanwhile
+func (s *billingService) Process(ctx context.Context, job *entities.Job) error {
+	err := s.SyncCustomer(ctx, job.Metadata["user_id"])
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return nil
+	}
+	return err
+}
+
+// user returns the user with the given ID from the repository
+func (s *billingService) user(ctx context.Context, ID string) (entities.User, error) {
+	result, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		return entities.User{}, err
+	}
+	user, ok := result.(*entities.User)
+	if !ok {
+		return entities.User{}, fmt.Errorf("user %s not valid", ID)
+	}
+	return *user, nil
+}
+
+// billingUserService decorator of an user service that syncs the billing customers of the users
+type billingUserService struct {
+	ports.UserService
+	jobs   ports.JobService
+	logger zerolog.Logger
+}
+
+// NewBillingUserService wraps a user service queuing the sync of the customers of the users created or updated
+func NewBillingUserService(service ports.UserService, jobs ports.JobService, logger zerolog.Logger) ports.UserService {
+	return &billingUserService{
+		UserService: service,
+		jobs:        jobs,
+		logger:      logger,
+	}
+}
+
+func (s *billingUserService) Create(ctx context.Context, user models.CreateUserReq) (models.CreationResp, error) {
+	resp, err := s.UserService.Create(ctx, user)
+	if err == nil {
+		s.sync(ctx, resp.InsertedID)
+	}
+	return resp, err
+}
+
+func (s *billingUserService) CreateMany(ctx context.Context, users []models.CreateUserReq) (models.MultiCreationResp, error) {
+	resp, err := s.UserService.CreateMany(ctx, users)
+	if err == nil {
+		for _, ID := range resp.InsertedIDs {
+			s.sync(ctx, ID)
+		}
+	}
+	return resp, err
+}
+
+func (s *billingUserService) Upsert(ctx context.Context, email string, user models.UpsertUserReq) (models.UpsertionResp, error) {
+	resp, err := s.UserService.Upsert(ctx, email, user)
+	if err == nil {
+		s.sync(ctx, resp.ID)
+	}
+	return resp, err
+}
+
+func (s *billingUserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) error {
+	if err := s.UserService.Update(ctx, ID, user); err != nil {
+		return err
+	}
+	if user.Name != nil || user.Surnames != nil || user.Email != nil {
+		s.sync(ctx, ID)
+	}
+	return nil
+}
+
+func (s *billingUserService) Merge(ctx context.Context, ID string, req models.MergeUsersReq) error {
+	if err := s.UserService.Merge(ctx, ID, req); err != nil {
+		return err
+	}
+	s.sync(ctx, ID)
+	return nil
+}
+
+// sync queues the billing job of the user, logging the failures instead of returning them
+func (s *billingUserService) sync(ctx context.Context, userID string) {
+	if _, err := s.jobs.Enqueue(ctx, entities.JobTypeBilling, map[string]string{"user_id": userID}); err != nil {
+		s.logger.Error().Err(err).Str("user", userID).Msg("billing customer sync cannot be queued")
+	}
+}
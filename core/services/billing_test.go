@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestSyncCustomer_Create checks that SyncCustomer creates the customer of a user without one, storing its ID on the user
+func TestSyncCustomer_Create(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "test-id").Return(&entities.User{ID: "test-id", Name: "test", Surnames: "user", Email: "test@test.com"}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateBillingCustomer), mock.Anything, "test-id", "cus_123").Return(nil).Once()
+	providerMock := mocks.NewBillingProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.BillingProvider.SaveCustomer), mock.Anything, ports.BillingCustomer{UserID: "test-id", Email: "test@test.com", Name: "test user"}).Return("cus_123", nil).Once()
+
+	service := NewBillingService(zerolog.Nop(), userRepositoryMock, providerMock)
+
+	// Act
+	err := service.SyncCustomer(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSyncCustomer_Update checks that SyncCustomer updates the customer of a user with one, without storing its ID again
+func TestSyncCustomer_Update(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "test-id").Return(&entities.User{ID: "test-id", Email: "test@test.com", BillingCustomerID: "cus_123"}, nil).Once()
+	providerMock := mocks.NewBillingProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.BillingProvider.SaveCustomer), mock.Anything, ports.BillingCustomer{ID: "cus_123", UserID: "test-id", Email: "test@test.com"}).Return("cus_123", nil).Once()
+
+	service := NewBillingService(zerolog.Nop(), userRepositoryMock, providerMock)
+
+	// Act
+	err := service.SyncCustomer(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSyncCustomer_ProviderError checks that SyncCustomer returns the error of the provider, so the job is retried
+func TestSyncCustomer_ProviderError(t *testing.T) {
+	// Arrange
+	expectedError := errors.New("provider error")
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "test-id").Return(&entities.User{ID: "test-id"}, nil).Once()
+	providerMock := mocks.NewBillingProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.BillingProvider.SaveCustomer), mock.Anything, mock.Anything).Return("", expectedError).Once()
+
+	service := NewBillingService(zerolog.Nop(), userRepositoryMock, providerMock)
+
+	// Act
+	err := service.SyncCustomer(context.Background(), "test-id")
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
+
+// TestProcessBilling_UserDeleted checks that Process does not fail the job of a user deleted meanwhile
+func TestProcessBilling_UserDeleted(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "test-id").Return(nil, wrappers.NewNonExistentErr(fmt.Errorf("not found"))).Once()
+
+	service := NewBillingService(zerolog.Nop(), userRepositoryMock, mocks.NewBillingProvider(t))
+
+	// Act
+	err := service.Process(context.Background(), &entities.Job{Type: entities.JobTypeBilling, Metadata: map[string]string{"user_id": "test-id"}})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestHandleEvent_Subscription checks that HandleEvent sets the status of the subscription of the customer as of the event
+func TestHandleEvent_Subscription(t *testing.T) {
+	// Arrange
+	event := ports.BillingEvent{ID: "evt_123", Type: "customer.subscription.updated", CustomerID: "cus_123", SubscriptionStatus: "active", CreatedAt: time.Now().UTC()}
+	providerMock := mocks.NewBillingProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.BillingProvider.ParseEvent), []byte("test-payload"), "test-signature").Return(event, nil).Once()
+	providerMock.On(testutils.FunctionName(t, ports.BillingProvider.SubscriptionEvent), event.Type).Return(true).Once()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateSubscription), mock.Anything, "cus_123", "active", event.CreatedAt).Return(nil).Once()
+
+	service := NewBillingService(zerolog.Nop(), userRepositoryMock, providerMock)
+
+	// Act
+	err := service.HandleEvent(context.Background(), []byte("test-payload"), "test-signature")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestHandleEvent_NotLinked checks that HandleEvent ignores the events of customers not linked to a user or older than the status set
+func TestHandleEvent_NotLinked(t *testing.T) {
+	// Arrange
+	event := ports.BillingEvent{ID: "evt_123", Type: "customer.subscription.deleted", CustomerID: "cus_123", SubscriptionStatus: "canceled"}
+	providerMock := mocks.NewBillingProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.BillingProvider.ParseEvent), mock.Anything, mock.Anything).Return(event, nil).Once()
+	providerMock.On(testutils.FunctionName(t, ports.BillingProvider.SubscriptionEvent), event.Type).Return(true).Once()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateSubscription), mock.Anything, "cus_123", "canceled", event.CreatedAt).Return(wrappers.NewNonExistentErr(fmt.Errorf("not found"))).Once()
+
+	service := NewBillingService(zerolog.Nop(), userRepositoryMock, providerMock)
+
+	// Act
+	err := service.HandleEvent(context.Background(), []byte("test-payload"), "test-signature")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestHandleEvent_OtherEvent checks that HandleEvent ignores the events not changing a subscription
+func TestHandleEvent_OtherEvent(t *testing.T) {
+	// Arrange
+	providerMock := mocks.NewBillingProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.BillingProvider.ParseEvent), mock.Anything, mock.Anything).Return(ports.BillingEvent{Type: "invoice.paid"}, nil).Once()
+	providerMock.On(testutils.FunctionName(t, ports.BillingProvider.SubscriptionEvent), "invoice.paid").Return(false).Once()
+
+	service := NewBillingService(zerolog.Nop(), mocks.NewUserRepository(t), providerMock)
+
+	// Act
+	err := service.HandleEvent(context.Background(), []byte("test-payload"), "test-signature")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestHandleEvent_SignatureNotValid checks that HandleEvent returns an unauthorized error when the event was not signed by the provider
+func TestHandleEvent_SignatureNotValid(t *testing.T) {
+	// Arrange
+	providerMock := mocks.NewBillingProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.BillingProvider.ParseEvent), mock.Anything, mock.Anything).Return(ports.BillingEvent{}, ports.ErrBillingSignatureNotValid).Once()
+
+	service := NewBillingService(zerolog.Nop(), mocks.NewUserRepository(t), providerMock)
+
+	// Act
+	err := service.HandleEvent(context.Background(), []byte("test-payload"), "test-signature")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.UnauthorizedErr))
+}
+
+// TestSubscribed_Ok checks that Subscribed reports whether the subscription of the user is active or in its trial
+func TestSubscribed_Ok(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "test-trialing").Return(&entities.User{SubscriptionStatus: entities.SubscriptionStatusTrialing}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "test-past-due").Return(&entities.User{SubscriptionStatus: "past_due"}, nil).Once()
+
+	service := NewBillingService(zerolog.Nop(), userRepositoryMock, mocks.NewBillingProvider(t))
+
+	// Act
+	trialing, trialingErr := service.Subscribed(context.Background(), "test-trialing")
+	pastDue, pastDueErr := service.Subscribed(context.Background(), "test-past-due")
+
+	// Assert
+	assert.Nil(t, trialingErr)
+	assert.True(t, trialing)
+	assert.Nil(t, pastDueErr)
+	assert.False(t, pastDue)
+}
+
+// TestBillingCreate_Ok checks that Create queues the sync of the customer of the created user
+func TestBillingCreate_Ok(t *testing.T) {
+	// Arrange
+	req := models.CreateUserReq{Email: "test@example.com"}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), mock.Anything, req).Return(models.CreationResp{InsertedID: "test-id"}, nil).Once()
+	jobServiceMock := mocks.NewJobService(t)
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Enqueue), mock.Anything, entities.JobTypeBilling, map[string]string{"user_id": "test-id"}).Return(models.JobResp{}, nil).Once()
+
+	service := NewBillingUserService(userServiceMock, jobServiceMock, zerolog.Nop())
+
+	// Act
+	resp, err := service.Create(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.InsertedID)
+}
+
+// TestBillingUpdate_NotBilledFields checks that Update does not queue the sync of the customer when the fields it has are not updated
+func TestBillingUpdate_NotBilledFields(t *testing.T) {
+	// Arrange
+	claims := []int64{0}
+	req := models.UpdateUserReq{Claims: &claims}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", req).Return(nil).Once()
+
+	service := NewBillingUserService(userServiceMock, mocks.NewJobService(t), zerolog.Nop())
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestBillingUpdate_EnqueueError checks that Update does not fail when the sync of the customer cannot be queued
+func TestBillingUpdate_EnqueueError(t *testing.T) {
+	// Arrange
+	email := "new@example.com"
+	req := models.UpdateUserReq{Email: &email}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", req).Return(nil).Once()
+	jobServiceMock := mocks.NewJobService(t)
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Enqueue), mock.Anything, entities.JobTypeBilling, map[string]string{"user_id": "test-id"}).Return(models.JobResp{}, errors.New("enqueue error")).Once()
+
+	service := NewBillingUserService(userServiceMock, jobServiceMock, zerolog.Nop())
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// cachedUser a user cached until it expires
+type cachedUser struct {
+	user      models.UserResp
+	expiresAt time.Time
+}
+
+// cachingUserService decorator of an user service that caches the users read by ID
+type cachingUserService struct {
+	ports.UserService
+	invalidator ports.Invalidator
+	logger      zerolog.Logger
+	ttl         time.Duration
+	maxEntries  int
+	mu          sync.Mutex
+	users       map[string]cachedUser
+	generation  uint64
+}
+
+// NewCachingUserService wraps a user service caching up to maxEntries users for ttl, evicted in every replica through the invalidator when set
+func NewCachingUserService(ctx context.Context, service ports.UserService, invalidator ports.Invalidator, logger zerolog.Logger, ttl time.Duration, maxEntries int) ports.UserService {
+	s := &cachingUserService{
+		UserService: service,
+		invalidator: invalidator,
+		logger:      logger,
+		ttl:         ttl,
+		maxEntries:  maxEntries,
+		users:       map[string]cachedUser{},
+	}
+	if invalidator != nil {
+		go func() {
+			if err := invalidator.Listen(ctx, s.invalidated); err != nil && ctx.Err() == nil {
+				s.logger.Error().Err(err).Msg("cache invalidations cannot be listened to")
+			}
+		}()
+	}
+	return s
+}
+
+// GetByID user, cached unless a user is evicted while reading it
+func (s *cachingUserService) GetByID(ctx context.Context, ID string) (models.UserResp, error) {
+	s.mu.Lock()
+	cached, ok := s.users[ID]
+	generation := s.generation
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		cacheHit()
+		return cached.user, nil
+	}
+	cacheMissed()
+
+	user, err := s.UserService.GetByID(ctx, ID)
+	if err != nil {
+		return user, err
+	}
+
+	s.mu.Lock()
+	if len(s.users) >= s.maxEntries {
+		s.evictExpired()
+	}
+	if generation == s.generation && len(s.users) < s.maxEntries {
+		s.users[ID] = cachedUser{user: user, expiresAt: time.Now().Add(s.ttl)}
+	}
+	s.mu.Unlock()
+	return user, nil
+}
+
+func (s *cachingUserService) Login(ctx context.Context, credentials models.LoginUserReq) (models.LoginUserResp, error) {
+	resp, err := s.UserService.Login(ctx, credentials)
+	if resp.User.ID != "" {
+		s.invalidate(ctx, resp.User.ID)
+	}
+	return resp, err
+}
+
+func (s *cachingUserService) Upsert(ctx context.Context, email string, user models.UpsertUserReq) (models.UpsertionResp, error) {
+	resp, err := s.UserService.Upsert(ctx, email, user)
+	if err == nil {
+		s.invalidate(ctx, resp.ID)
+	}
+	return resp, err
+}
+
+func (s *cachingUserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) error {
+	defer s.invalidate(ctx, ID)
+	return s.UserService.Update(ctx, ID, user)
+}
+
+func (s *cachingUserService) Merge(ctx context.Context, ID string, req models.MergeUsersReq) error {
+	defer s.invalidate(ctx, ID, req.SourceID)
+	return s.UserService.Merge(ctx, ID, req)
+}
+
+func (s *cachingUserService) Delete(ctx context.Context, ID string) error {
+	defer s.invalidate(ctx, ID)
+	return s.UserService.Delete(ctx, ID)
+}
+
+// ArchiveInactive invalidates every user, as the archived ones are not known
+func (s *cachingUserService) ArchiveInactive(ctx context.Context) (models.ArchivalResp, error) {
+	defer s.invalidate(ctx, ports.InvalidateAll)
+	return s.UserService.ArchiveInactive(ctx)
+}
+
+func (s *cachingUserService) Unarchive(ctx context.Context, ID string) error {
+	defer s.invalidate(ctx, ID)
+	return s.UserService.Unarchive(ctx, ID)
+}
+
+func (s *cachingUserService) UploadAvatar(ctx context.Context, ID string, avatar models.UploadAvatarReq) (models.FileResp, error) {
+	defer s.invalidate(ctx, ID)
+	return s.UserService.UploadAvatar(ctx, ID, avatar)
+}
+
+func (s *cachingUserService) DeleteAvatar(ctx context.Context, ID string) error {
+	defer s.invalidate(ctx, ID)
+	return s.UserService.DeleteAvatar(ctx, ID)
+}
+
+// invalidate evicts the users from the cache and broadcasts their IDs to the other replicas, whether the change succeeded or not,
+// as a failed change can have been applied partially. A failure to broadcast them is logged, the other replicas evicting them once expired.
+func (s *cachingUserService) invalidate(ctx context.Context, IDs ...string) {
+	for _, ID := range IDs {
+		s.evict(ID)
+	}
+	if s.invalidator == nil {
+		return
+	}
+	if err := s.invalidator.Invalidate(ctx, IDs...); err != nil {
+		s.logger.Error().Err(err).Strs("users", IDs).Msg("cache invalidation cannot be broadcast")
+	}
+}
+
+// invalidated evicts a user invalidated by a replica, or every user for InvalidateAll
+func (s *cachingUserService) invalidated(ID string) {
+	cacheInvalidated()
+	s.evict(ID)
+}
+
+// evict evicts a user from the cache, or every user for InvalidateAll
+func (s *cachingUserService) evict(ID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+	if ID == ports.InvalidateAll {
+		s.users = map[string]cachedUser{}
+		return
+	}
+	delete(s.users, ID)
+}
+
+// evictExpired evicts the expired users, called with the lock held once the cache is full
+func (s *cachingUserService) evictExpired() {
+	now := time.Now()
+	for ID, cached := range s.users {
+		if !now.Before(cached.expiresAt) {
+			delete(s.users, ID)
+		}
+	}
+}
+
+// cacheEvictingUserRepository decorator of an user repository that evicts from the cache the users changed through it
+type cacheEvictingUserRepository struct {
+	ports.UserRepository
+	cache *cachingUserService
+}
+
+// NewCacheEvictingUserRepository wraps a user repository evicting the users it changes, unless the service is not a caching one
+func NewCacheEvictingUserRepository(repository ports.UserRepository, service ports.UserService) ports.UserRepository {
+	cache, ok := service.(*cachingUserService)
+	if !ok {
+		return repository
+	}
+	return &cacheEvictingUserRepository{UserRepository: repository, cache: cache}
+}
+
+func (r *cacheEvictingUserRepository) Update(ctx context.Context, ID string, user interface{}) error {
+	defer r.cache.invalidate(ctx, ID)
+	return r.UserRepository.Update(ctx, ID, user)
+}
+
+func (r *cacheEvictingUserRepository) Upsert(ctx context.Context, filter map[string]interface{}, user interface{}) (string, error) {
+	ID, err := r.UserRepository.Upsert(ctx, filter, user)
+	if err == nil {
+		r.cache.invalidate(ctx, ID)
+	}
+	return ID, err
+}
+
+func (r *cacheEvictingUserRepository) Delete(ctx context.Context, ID string) error {
+	defer r.cache.invalidate(ctx, ID)
+	return r.UserRepository.Delete(ctx, ID)
+}
+
+func (r *cacheEvictingUserRepository) UpdateLastLogin(ctx context.Context, ID string, at time.Time) error {
+	defer r.cache.invalidate(ctx, ID)
+	return r.UserRepository.UpdateLastLogin(ctx, ID, at)
+}
+
+func (r *cacheEvictingUserRepository) UpdateNames(ctx context.Context, ID, name, surnames string, at time.Time) error {
+	defer r.cache.invalidate(ctx, ID)
+	return r.UserRepository.UpdateNames(ctx, ID, name, surnames, at)
+}
+
+func (r *cacheEvictingUserRepository) UpdateBillingCustomer(ctx context.Context, ID, customerID string) error {
+	defer r.cache.invalidate(ctx, ID)
+	return r.UserRepository.UpdateBillingCustomer(ctx, ID, customerID)
+}
+
+// UpdateSubscription invalidates every user, as the user of the customer is not known
+func (r *cacheEvictingUserRepository) UpdateSubscription(ctx context.Context, customerID, status string, at time.Time) error {
+	defer r.cache.invalidate(ctx, ports.InvalidateAll)
+	return r.UserRepository.UpdateSubscription(ctx, customerID, status, at)
+}
+
+// Archive invalidates every user, as the archived ones are not known
+func (r *cacheEvictingUserRepository) Archive(ctx context.Context, inactiveSince, at time.Time) (int64, error) {
+	defer r.cache.invalidate(ctx, ports.InvalidateAll)
+	return r.UserRepository.Archive(ctx, inactiveSince, at)
+}
+
+func (r *cacheEvictingUserRepository) ArchiveByID(ctx context.Context, ID string, at time.Time) error {
+	defer r.cache.invalidate(ctx, ID)
+	return r.UserRepository.ArchiveByID(ctx, ID, at)
+}
+
+func (r *cacheEvictingUserRepository) Unarchive(ctx context.Context, ID string, at time.Time) error {
+	defer r.cache.invalidate(ctx, ID)
+	return r.UserRepository.Unarchive(ctx, ID, at)
+}
+
+// Restore invalidates every user, as every one is replaced
+func (r *cacheEvictingUserRepository) Restore(ctx context.Context, users []entities.User) error {
+	defer r.cache.invalidate(ctx, ports.InvalidateAll)
+	return r.UserRepository.Restore(ctx, users)
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCachingGetByID_Cached checks that GetByID reads a user through the user service only once while cached
+func TestCachingGetByID_Cached(t *testing.T) {
+	// Arrange
+	user := models.UserResp{ID: "test-id", Name: "test"}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(user, nil).Once()
+	hits := cacheHits.Value()
+
+	service := NewCachingUserService(context.Background(), userServiceMock, nil, zerolog.Nop(), time.Minute, 10)
+
+	// Act
+	first, firstErr := service.GetByID(context.Background(), "test-id")
+	second, secondErr := service.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, firstErr)
+	assert.Nil(t, secondErr)
+	assert.Equal(t, user, first)
+	assert.Equal(t, user, second)
+	assert.Equal(t, hits+1, cacheHits.Value())
+}
+
+// TestCachingGetByID_Expired checks that GetByID reads a user again through the user service once expired
+func TestCachingGetByID_Expired(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.UserResp{ID: "test-id"}, nil).Twice()
+
+	service := NewCachingUserService(context.Background(), userServiceMock, nil, zerolog.Nop(), time.Nanosecond, 10)
+
+	// Act
+	_, firstErr := service.GetByID(context.Background(), "test-id")
+	time.Sleep(time.Millisecond)
+	_, secondErr := service.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, firstErr)
+	assert.Nil(t, secondErr)
+}
+
+// TestCachingGetByID_Full checks that GetByID does not cache more users than the maximum
+func TestCachingGetByID_Full(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "first-id").Return(models.UserResp{ID: "first-id"}, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "second-id").Return(models.UserResp{ID: "second-id"}, nil).Twice()
+
+	service := NewCachingUserService(context.Background(), userServiceMock, nil, zerolog.Nop(), time.Minute, 1)
+
+	// Act
+	service.GetByID(context.Background(), "first-id")
+	service.GetByID(context.Background(), "second-id")
+	_, err := service.GetByID(context.Background(), "second-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestCachingUpdate_Invalidated checks that Update evicts the user, even when failing, and broadcasts its ID to the other replicas
+func TestCachingUpdate_Invalidated(t *testing.T) {
+	// Arrange
+	expectedError := errors.New("update error")
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.UserResp{ID: "test-id"}, nil).Twice()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", models.UpdateUserReq{}).Return(expectedError).Once()
+	listening := make(chan struct{})
+	invalidatorMock := mocks.NewInvalidator(t)
+	invalidatorMock.On(testutils.FunctionName(t, ports.Invalidator.Listen), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		close(listening)
+	}).Return(nil).Once()
+	invalidatorMock.On(testutils.FunctionName(t, ports.Invalidator.Invalidate), mock.Anything, "test-id").Return(nil).Once()
+
+	service := NewCachingUserService(context.Background(), userServiceMock, invalidatorMock, zerolog.Nop(), time.Minute, 10)
+	<-listening
+	service.GetByID(context.Background(), "test-id")
+
+	// Act
+	err := service.Update(context.Background(), "test-id", models.UpdateUserReq{})
+	_, getErr := service.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+	assert.Nil(t, getErr)
+}
+
+// TestCachingListen_Invalidated checks that the users invalidated by the other replicas are evicted
+func TestCachingListen_Invalidated(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.UserResp{ID: "test-id"}, nil).Twice()
+	evictions := make(chan func(string))
+	invalidatorMock := mocks.NewInvalidator(t)
+	invalidatorMock.On(testutils.FunctionName(t, ports.Invalidator.Listen), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		evictions <- args.Get(1).(func(string))
+	}).Return(nil).Once()
+	invalidations := cacheInvalidations.Value()
+
+	service := NewCachingUserService(context.Background(), userServiceMock, invalidatorMock, zerolog.Nop(), time.Minute, 10)
+	evict := <-evictions
+	service.GetByID(context.Background(), "test-id")
+
+	// Act
+	evict("test-id")
+	_, err := service.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, invalidations+1, cacheInvalidations.Value())
+}
+
+// TestCacheEvictingUpdateNames_Evicted checks that the users updated through the repository are evicted from the cache, even when failing
+func TestCacheEvictingUpdateNames_Evicted(t *testing.T) {
+	// Arrange
+	expectedError := errors.New("update error")
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.UserResp{ID: "test-id"}, nil).Twice()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateNames), mock.Anything, "test-id", "test", "test", mock.Anything).Return(expectedError).Once()
+
+	service := NewCachingUserService(context.Background(), userServiceMock, nil, zerolog.Nop(), time.Minute, 10)
+	repository := NewCacheEvictingUserRepository(userRepositoryMock, service)
+	service.GetByID(context.Background(), "test-id")
+
+	// Act
+	err := repository.UpdateNames(context.Background(), "test-id", "test", "test", time.Now())
+	_, getErr := service.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+	assert.Nil(t, getErr)
+}
+
+// TestCacheEvictingRestore_InvalidatesAll checks that a restore through the repository evicts every user and broadcasts it to the other replicas
+func TestCacheEvictingRestore_InvalidatesAll(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.UserResp{ID: "test-id"}, nil).Twice()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Restore), mock.Anything, mock.Anything).Return(nil).Once()
+	invalidatorMock := mocks.NewInvalidator(t)
+	invalidatorMock.On(testutils.FunctionName(t, ports.Invalidator.Listen), mock.Anything, mock.Anything).Return(nil).Maybe()
+	invalidatorMock.On(testutils.FunctionName(t, ports.Invalidator.Invalidate), mock.Anything, ports.InvalidateAll).Return(nil).Once()
+
+	service := NewCachingUserService(context.Background(), userServiceMock, invalidatorMock, zerolog.Nop(), time.Minute, 10)
+	repository := NewCacheEvictingUserRepository(userRepositoryMock, service)
+	service.GetByID(context.Background(), "test-id")
+
+	// Act
+	err := repository.Restore(context.Background(), nil)
+	_, getErr := service.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, getErr)
+}
+
+// TestNewCacheEvictingUserRepository_NotCaching checks that the repository is returned as it is when the service does not cache the users
+func TestNewCacheEvictingUserRepository_NotCaching(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+
+	// Act
+	repository := NewCacheEvictingUserRepository(userRepositoryMock, mocks.NewUserService(t))
+
+	// Assert
+	assert.Equal(t, userRepositoryMock, repository)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// captureService adapter of a capture service.
+// The capture mode is kept in memory, so it only applies to the instance it was enabled on, and it is disabled on restart.
+type captureService struct {
+	config     config.Config
+	logger     zerolog.Logger
+	repository ports.CaptureRepository
+	audit      ports.AuditRepository
+	random     func() float64
+
+	mu         sync.RWMutex
+	percentage float64
+	expiresAt  time.Time
+}
+
+// NewCaptureService creates a new capture service, with the capture mode disabled
+func NewCaptureService(cfg config.Config, logger zerolog.Logger, repo ports.CaptureRepository, audit ports.AuditRepository) ports.CaptureService {
+	return &captureService{
+		config:     cfg,
+		logger:     logger,
+		repository: repo,
+		audit:      audit,
+		random:     rand.Float64,
+	}
+}
+
+// Enable enables the capture mode for the percentage of the requests and the duration of the request, replacing the current one
+func (s *captureService) Enable(ctx context.Context, req models.CaptureModeReq) (resp models.CaptureModeResp, err error) {
+	if err = req.Validate(s.config.Capture.MaxDuration.Duration); err != nil {
+		return
+	}
+	duration, _ := time.ParseDuration(req.Duration)
+
+	s.mu.Lock()
+	s.percentage = req.Percentage
+	s.expiresAt = time.Now().UTC().Add(duration)
+	s.mu.Unlock()
+
+	record(ctx, s.logger, s.audit, entities.AuditCaptureEnabled, "", map[string]string{
+		"percentage": strconv.FormatFloat(req.Percentage, 'f', -1, 64),
+		"duration":   duration.String(),
+	})
+	resp = s.Mode(ctx)
+	return
+}
+
+// Disable disables the capture mode before it expires, the captures already written are kept until their TTL
+func (s *captureService) Disable(ctx context.Context) error {
+	s.mu.Lock()
+	s.percentage = 0
+	s.expiresAt = time.Time{}
+	s.mu.Unlock()
+
+	record(ctx, s.logger, s.audit, entities.AuditCaptureDisabled, "", nil)
+	return nil
+}
+
+// Mode returns the current capture mode, disabled once expired
+func (s *captureService) Mode(ctx context.Context) models.CaptureModeResp {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !time.Now().Before(s.expiresAt) {
+		return models.CaptureModeResp{}
+	}
+	expiresAt := s.expiresAt
+	return models.CaptureModeResp{
+		Enabled:    true,
+		Percentage: s.percentage,
+		ExpiresAt:  &expiresAt,
+	}
+}
+
+// Sampled returns whether the request being served has to be captured, as many as the percentage of the capture mode while not expired
+func (s *captureService) Sampled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return time.Now().Before(s.expiresAt) && s.random()*100 < s.percentage
+}
+
+// Save writes a captured request
+func (s *captureService) Save(ctx context.Context, req models.CaptureReq) error {
+	return s.repository.Write(ctx, entities.Capture{
+		RequestID:    req.RequestID,
+		ActorID:      req.ActorID,
+		Method:       req.Method,
+		Path:         req.Path,
+		Query:        req.Query,
+		Status:       req.Status,
+		RequestBody:  req.RequestBody,
+		ResponseBody: req.ResponseBody,
+		CreatedAt:    time.Now().UTC(),
+	})
+}
+
+// Get captured requests, newest first
+func (s *captureService) Get(ctx context.Context, req models.GetCapturesReq) (resp []models.CaptureResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	captures, err := s.repository.Get(ctx, &req.Skip, &req.Take)
+	if err != nil {
+		return
+	}
+
+	resp = make([]models.CaptureResp, 0, len(captures))
+	for _, c := range captures {
+		resp = append(resp, models.CaptureResp(c))
+	}
+	return
+}
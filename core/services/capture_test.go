@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func captureConfig(maxDuration time.Duration) config.Config {
+	cfg := config.Config{}
+	cfg.Capture.MaxDuration = utils.Duration{Duration: maxDuration}
+	return cfg
+}
+
+// TestNewCaptureService_Ok checks that NewCaptureService creates a new captureService struct with the capture mode disabled
+func TestNewCaptureService_Ok(t *testing.T) {
+	// Act
+	service := NewCaptureService(config.Config{}, zerolog.Nop(), mocks.NewCaptureRepository(t), mocks.NewAuditRepository(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+	assert.False(t, service.Mode(context.Background()).Enabled)
+	assert.False(t, service.Sampled())
+}
+
+// TestEnable_Ok checks that Enable enables the capture mode until the duration expires and records it in the audit log
+func TestEnable_Ok(t *testing.T) {
+	// Arrange
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(event entities.AuditEvent) bool {
+		return event.Type == entities.AuditCaptureEnabled && event.Details["percentage"] == "12.5" && event.Details["duration"] == "15m0s"
+	})).Return(nil).Once()
+
+	service := &captureService{
+		config: captureConfig(time.Hour),
+		audit:  auditRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Enable(context.Background(), models.CaptureModeReq{Percentage: 12.5, Duration: "15m"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, resp.Enabled)
+	assert.Equal(t, 12.5, resp.Percentage)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), *resp.ExpiresAt, time.Minute)
+}
+
+// TestEnable_InvalidRequest checks that Enable returns an error when the percentage is not valid or the duration exceeds the maximum
+func TestEnable_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &captureService{
+		config: captureConfig(time.Hour),
+	}
+	expectedError := "percentage must be greater than 0 and up to 100 | duration must be a positive duration up to 1h0m0s, like 15m"
+
+	// Act
+	_, err := service.Enable(context.Background(), models.CaptureModeReq{Percentage: 101, Duration: "2h"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, expectedError, err.Error())
+	assert.False(t, service.Mode(context.Background()).Enabled)
+}
+
+// TestDisable_Ok checks that Disable disables the capture mode and records it in the audit log
+func TestDisable_Ok(t *testing.T) {
+	// Arrange
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(event entities.AuditEvent) bool {
+		return event.Type == entities.AuditCaptureDisabled
+	})).Return(nil).Once()
+
+	service := &captureService{
+		audit:      auditRepositoryMock,
+		percentage: 100,
+		expiresAt:  time.Now().Add(time.Hour),
+	}
+
+	// Act
+	err := service.Disable(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, service.Mode(context.Background()).Enabled)
+	assert.False(t, service.Sampled())
+}
+
+// TestMode_Expired checks that Mode returns the capture mode disabled once expired
+func TestMode_Expired(t *testing.T) {
+	// Arrange
+	service := &captureService{
+		percentage: 100,
+		expiresAt:  time.Now().Add(-time.Second),
+	}
+
+	// Act
+	resp := service.Mode(context.Background())
+
+	// Assert
+	assert.Equal(t, models.CaptureModeResp{}, resp)
+}
+
+// TestSampled_Percentage checks that Sampled only samples the requests under the percentage of the capture mode
+func TestSampled_Percentage(t *testing.T) {
+	// Arrange
+	random := 0.3
+	service := &captureService{
+		random:     func() float64 { return random },
+		percentage: 25,
+		expiresAt:  time.Now().Add(time.Hour),
+	}
+
+	// Act
+	notSampled := service.Sampled()
+	random = 0.2
+	sampled := service.Sampled()
+
+	// Assert
+	assert.False(t, notSampled)
+	assert.True(t, sampled)
+}
+
+// TestSave_Ok checks that Save writes the capture with its creation time
+func TestSave_Ok(t *testing.T) {
+	// Arrange
+	req := models.CaptureReq{RequestID: "test-request-id", Method: "POST", Path: "/v1/users", Status: 201, RequestBody: `{"name":"test"}`}
+
+	captureRepositoryMock := mocks.NewCaptureRepository(t)
+	captureRepositoryMock.On(testutils.FunctionName(t, ports.CaptureRepository.Write), context.Background(), mock.MatchedBy(func(capture entities.Capture) bool {
+		return capture.RequestID == req.RequestID && capture.RequestBody == req.RequestBody && time.Since(capture.CreatedAt) < time.Minute
+	})).Return(nil).Once()
+
+	service := &captureService{
+		repository: captureRepositoryMock,
+	}
+
+	// Act
+	err := service.Save(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestGetCaptures_Ok checks that Get returns the captures of the page of the request
+func TestGetCaptures_Ok(t *testing.T) {
+	// Arrange
+	req := models.GetCapturesReq{Skip: 10, Take: 5}
+	expectedCaptures := []entities.Capture{{ID: "test-id", RequestID: "test-request-id"}}
+
+	captureRepositoryMock := mocks.NewCaptureRepository(t)
+	captureRepositoryMock.On(testutils.FunctionName(t, ports.CaptureRepository.Get), context.Background(), &req.Skip, &req.Take).Return(expectedCaptures, nil).Once()
+
+	service := &captureService{
+		repository: captureRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Get(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.CaptureResp{models.CaptureResp(expectedCaptures[0])}, resp)
+}
+
+// TestGetCaptures_InvalidRequest checks that Get returns an error when the received request is not valid
+func TestGetCaptures_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &captureService{}
+
+	// Act
+	_, err := service.Get(context.Background(), models.GetCapturesReq{})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"golang.org/x/sync/singleflight"
+)
+
+// webhookJobTypes the types of the jobs posting to the webhooks: the alerts and the admin notices
+var webhookJobTypes = map[string]bool{
+	entities.JobTypeNotification: true,
+	entities.JobTypeAdminNotice:  true,
+}
+
+// dashboardService adapter of a dashboard service
+type dashboardService struct {
+	users  ports.UserService
+	audit  ports.AuditRepository
+	jobs   ports.JobRepository
+	maxAge time.Duration
+	// timeout bounds every aggregation, run detached from the request starting it
+	timeout time.Duration
+	now     func() time.Time
+	// group runs a single aggregation at a time, the concurrent requests waiting for its figures
+	group singleflight.Group
+	// mu guards the last figures
+	mu   sync.Mutex
+	last *models.DashboardResp
+}
+
+// NewDashboardService creates a new dashboard service, aggregating the figures from the users, the audit events and the jobs,
+// reusing the last ones up to the given max age and aggregating them within the given timeout
+func NewDashboardService(users ports.UserService, audit ports.AuditRepository, jobs ports.JobRepository, maxAge, timeout time.Duration) ports.DashboardService {
+	return &dashboardService{
+		users:   users,
+		audit:   audit,
+		jobs:    jobs,
+		maxAge:  maxAge,
+		timeout: timeout,
+		now:     time.Now,
+	}
+}
+
+// Get returns the last figures when they are not older than the max age, aggregating them again otherwise.
+// The requests arriving during an aggregation wait for it, or until their context is done, the aggregation not being canceled
+// along with the request starting it, so the ones waiting for it are not failed.
+func (s *dashboardService) Get(ctx context.Context) (models.DashboardResp, error) {
+	s.mu.Lock()
+	last := s.last
+	s.mu.Unlock()
+	now := s.now().UTC()
+	if last != nil && now.Sub(last.ComputedAt) <= s.maxAge {
+		return *last, nil
+	}
+
+	ch := s.group.DoChan("dashboard", func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.timeout)
+		defer cancel()
+
+		resp, err := s.aggregate(ctx, now)
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.last = &resp
+		s.mu.Unlock()
+		return resp, nil
+	})
+	select {
+	case result := <-ch:
+		if result.Err != nil {
+			return models.DashboardResp{}, result.Err
+		}
+		return result.Val.(models.DashboardResp), nil
+	case <-ctx.Done():
+		return models.DashboardResp{}, ctx.Err()
+	}
+}
+
+// aggregate computes the figures at the given time. The logins succeeded within the token lifetime are counted as audited,
+// one per login and not per user, the ones of a shorter session lifetime included.
+func (s *dashboardService) aggregate(ctx context.Context, now time.Time) (resp models.DashboardResp, err error) {
+	resp.ComputedAt = now
+	resp.Users, err = s.users.ComputeStats(ctx)
+	if err != nil {
+		return
+	}
+
+	midnight := now.Truncate(24 * time.Hour)
+	resp.SignupsToday, err = s.audit.Count(ctx, ports.AuditFilter{Type: entities.AuditUserCreated, Outcome: entities.AuditOutcomeSuccess, From: &midnight})
+	if err != nil {
+		return
+	}
+	loginsFrom := now.Add(-models.TokenLifetime)
+	resp.LoginsWithinTokenLifetime, err = s.audit.Count(ctx, ports.AuditFilter{Type: entities.AuditLoginSucceeded, From: &loginsFrom})
+	if err != nil {
+		return
+	}
+	lastHour := now.Add(-time.Hour)
+	resp.FailedLoginsLastHour, err = s.audit.Count(ctx, ports.AuditFilter{Type: entities.AuditLoginFailed, From: &lastHour})
+	if err != nil {
+		return
+	}
+
+	counts, err := s.jobs.Count(ctx, []entities.JobStatus{entities.JobStatusQueued, entities.JobStatusFailed, entities.JobStatusDead})
+	if err != nil {
+		return
+	}
+	for _, count := range counts {
+		if count.Status != entities.JobStatusDead {
+			resp.JobBacklog += count.Count
+		}
+		if count.Status != entities.JobStatusQueued && webhookJobTypes[count.Type] {
+			resp.WebhookFailures += count.Count
+		}
+	}
+	return
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestGetDashboard_Ok checks that Get aggregates the figures from the users, the audit events and the jobs
+func TestGetDashboard_Ok(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	midnight := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	loginsFrom := now.Add(-models.TokenLifetime)
+	lastHour := now.Add(-time.Hour)
+
+	userServiceMock := mocks.NewUserService(t)
+	stats := models.UserStatsResp{Total: 10, Active: 8, Archived: 2}
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.ComputeStats), mock.Anything).Return(stats, nil).Once()
+	auditMock := mocks.NewAuditRepository(t)
+	auditMock.On(testutils.FunctionName(t, ports.AuditRepository.Count), mock.Anything, ports.AuditFilter{Type: entities.AuditUserCreated, Outcome: entities.AuditOutcomeSuccess, From: &midnight}).Return(int64(3), nil).Once()
+	auditMock.On(testutils.FunctionName(t, ports.AuditRepository.Count), mock.Anything, ports.AuditFilter{Type: entities.AuditLoginSucceeded, From: &loginsFrom}).Return(int64(7), nil).Once()
+	auditMock.On(testutils.FunctionName(t, ports.AuditRepository.Count), mock.Anything, ports.AuditFilter{Type: entities.AuditLoginFailed, From: &lastHour}).Return(int64(4), nil).Once()
+	jobsMock := mocks.NewJobRepository(t)
+	jobsMock.On(testutils.FunctionName(t, ports.JobRepository.Count), mock.Anything, []entities.JobStatus{entities.JobStatusQueued, entities.JobStatusFailed, entities.JobStatusDead}).Return([]ports.JobCount{
+		{Type: entities.JobTypeEmail, Status: entities.JobStatusQueued, Count: 5},
+		{Type: entities.JobTypeAdminNotice, Status: entities.JobStatusQueued, Count: 1},
+		{Type: entities.JobTypeAdminNotice, Status: entities.JobStatusFailed, Count: 2},
+		{Type: entities.JobTypeNotification, Status: entities.JobStatusDead, Count: 1},
+		{Type: entities.JobTypeEmail, Status: entities.JobStatusDead, Count: 6},
+	}, nil).Once()
+
+	service := &dashboardService{users: userServiceMock, audit: auditMock, jobs: jobsMock, maxAge: time.Minute, timeout: time.Minute, now: func() time.Time { return now }}
+
+	// Act
+	resp, err := service.Get(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	expected := models.DashboardResp{
+		Users:                     stats,
+		SignupsToday:              3,
+		LoginsWithinTokenLifetime: 7,
+		FailedLoginsLastHour:      4,
+		WebhookFailures:           3,
+		JobBacklog:                8,
+		ComputedAt:                now,
+	}
+	assert.Equal(t, expected, resp)
+}
+
+// TestGetDashboard_Cached checks that Get reuses the last figures while they are not older than the max age, aggregating them again afterwards
+func TestGetDashboard_Cached(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.ComputeStats), mock.Anything).Return(models.UserStatsResp{Total: 1}, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.ComputeStats), mock.Anything).Return(models.UserStatsResp{Total: 2}, nil).Once()
+	auditMock := mocks.NewAuditRepository(t)
+	auditMock.On(testutils.FunctionName(t, ports.AuditRepository.Count), mock.Anything, mock.Anything).Return(int64(0), nil).Times(6)
+	jobsMock := mocks.NewJobRepository(t)
+	jobsMock.On(testutils.FunctionName(t, ports.JobRepository.Count), mock.Anything, mock.Anything).Return([]ports.JobCount{}, nil).Twice()
+
+	service := &dashboardService{users: userServiceMock, audit: auditMock, jobs: jobsMock, maxAge: time.Minute, timeout: time.Minute, now: func() time.Time { return now }}
+
+	// Act
+	first, err := service.Get(context.Background())
+	assert.Nil(t, err)
+	now = now.Add(time.Minute)
+	cached, err := service.Get(context.Background())
+	assert.Nil(t, err)
+	now = now.Add(time.Second)
+	aggregated, err := service.Get(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, first, cached)
+	assert.Equal(t, int64(2), aggregated.Users.Total)
+	assert.Equal(t, now, aggregated.ComputedAt)
+}
+
+// TestGetDashboard_CanceledWhileWaiting checks that Get returns the error of the context of a request canceled while waiting for an aggregation in progress,
+// which still completes for the request that started it
+func TestGetDashboard_CanceledWhileWaiting(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.ComputeStats), mock.Anything).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Return(models.UserStatsResp{Total: 1}, nil).Once()
+	auditMock := mocks.NewAuditRepository(t)
+	auditMock.On(testutils.FunctionName(t, ports.AuditRepository.Count), mock.Anything, mock.Anything).Return(int64(0), nil).Times(3)
+	jobsMock := mocks.NewJobRepository(t)
+	jobsMock.On(testutils.FunctionName(t, ports.JobRepository.Count), mock.Anything, mock.Anything).Return([]ports.JobCount{}, nil).Once()
+
+	service := &dashboardService{users: userServiceMock, audit: auditMock, jobs: jobsMock, maxAge: time.Minute, timeout: time.Minute, now: func() time.Time { return now }}
+	first := make(chan error)
+	go func() {
+		_, err := service.Get(context.Background())
+		first <- err
+	}()
+	<-started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, err := service.Get(ctx)
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled)
+	close(release)
+	assert.Nil(t, <-first)
+}
+
+// TestGetDashboard_StarterCanceled checks that an aggregation is not canceled along with the request that started it,
+// so the requests waiting for it get its figures, aggregated with the values of the context of the starter
+func TestGetDashboard_StarterCanceled(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	starterCtx, cancelStarter := context.WithCancel(models.WithRequestInfo(context.Background(), models.RequestInfo{RequestID: "test-request"}))
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.ComputeStats), mock.MatchedBy(func(ctx context.Context) bool {
+		return models.RequestInfoFrom(ctx).RequestID == "test-request"
+	})).Run(func(args mock.Arguments) {
+		close(started)
+		<-release
+		assert.Nil(t, args.Get(0).(context.Context).Err())
+	}).Return(models.UserStatsResp{Total: 1}, nil).Once()
+	auditMock := mocks.NewAuditRepository(t)
+	auditMock.On(testutils.FunctionName(t, ports.AuditRepository.Count), mock.Anything, mock.Anything).Return(int64(0), nil).Times(3)
+	jobsMock := mocks.NewJobRepository(t)
+	jobsMock.On(testutils.FunctionName(t, ports.JobRepository.Count), mock.Anything, mock.Anything).Return([]ports.JobCount{}, nil).Once()
+
+	service := &dashboardService{users: userServiceMock, audit: auditMock, jobs: jobsMock, maxAge: time.Minute, timeout: time.Minute, now: func() time.Time { return now }}
+	starter := make(chan error)
+	go func() {
+		_, err := service.Get(starterCtx)
+		starter <- err
+	}()
+	<-started
+	waiter := make(chan models.DashboardResp)
+	go func() {
+		resp, _ := service.Get(context.Background())
+		waiter <- resp
+	}()
+
+	// Act
+	cancelStarter()
+	starterErr := <-starter
+	close(release)
+	resp := <-waiter
+
+	// Assert
+	assert.ErrorIs(t, starterErr, context.Canceled)
+	assert.Equal(t, int64(1), resp.Users.Total)
+}
+
+// TestGetDashboard_Error checks that Get returns the error of an aggregation, keeping no figures so the next call aggregates them again
+func TestGetDashboard_Error(t *testing.T) {
+	// Arrange
+	expectedError := errors.New("repository error")
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.ComputeStats), mock.Anything).Return(models.UserStatsResp{}, nil).Once()
+	auditMock := mocks.NewAuditRepository(t)
+	auditMock.On(testutils.FunctionName(t, ports.AuditRepository.Count), mock.Anything, mock.Anything).Return(int64(0), expectedError).Once()
+
+	service := NewDashboardService(userServiceMock, auditMock, mocks.NewJobRepository(t), time.Minute, time.Minute)
+
+	// Act
+	_, err := service.Get(context.Background())
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+	assert.Nil(t, service.(*dashboardService).last)
+}
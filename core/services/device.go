@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// deviceService adapter of the device service, sending the push notifications to the devices of the users through the sender
+type deviceService struct {
+	repository ports.DeviceRepository
+	users      ports.UserService
+	sender     ports.PushSender
+}
+
+// NewDeviceService creates a new device service
+func NewDeviceService(repository ports.DeviceRepository, users ports.UserService, sender ports.PushSender) ports.DeviceService {
+	return &deviceService{
+		repository: repository,
+		users:      users,
+		sender:     sender,
+	}
+}
+
+// Register the device of a user, moving it from the user it was registered by, if any, as a device only receives the notifications of the user logged in
+func (s *deviceService) Register(ctx context.Context, userID string, req models.RegisterDeviceReq) (resp models.DeviceResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if _, err = s.users.GetByID(ctx, userID); err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	device := entities.Device{
+		ID:        deviceID(req.Platform, req.Token),
+		UserID:    userID,
+		Platform:  req.Platform,
+		Token:     req.Token,
+		Name:      req.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err = s.repository.Upsert(ctx, device); err != nil {
+		return
+	}
+
+	resp = models.NewDeviceResp(device)
+	return
+}
+
+// GetByUser returns the devices of a user, the newest first
+func (s *deviceService) GetByUser(ctx context.Context, userID string) ([]models.DeviceResp, error) {
+	devices, err := s.repository.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]models.DeviceResp, 0, len(devices))
+	for _, device := range devices {
+		resp = append(resp, models.NewDeviceResp(device))
+	}
+	return resp, nil
+}
+
+// Delete a device of a user, so it no longer receives its notifications
+func (s *deviceService) Delete(ctx context.Context, userID, ID string) error {
+	return s.repository.Delete(ctx, userID, ID)
+}
+
+// DeleteByUser deletes every device of a user
+func (s *deviceService) DeleteByUser(ctx context.Context, userID string) error {
+	return s.repository.DeleteByUser(ctx, userID)
+}
+
+// Notify sends the notification to every device of a user, even when sending it to some of them fails, returning their errors joined
+func (s *deviceService) Notify(ctx context.Context, userID string, notification ports.PushNotification) error {
+	devices, err := s.repository.GetByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, device := range devices {
+		if err := s.sender.Send(ctx, device, notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deviceID returns the ID of the device of the platform and the token, the hex of the first half of their SHA-256
+func deviceID(platform, token string) string {
+	sum := sha256.Sum256([]byte(platform + ":" + token))
+	return hex.EncodeToString(sum[:16])
+}
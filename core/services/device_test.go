@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestRegisterDevice_Ok checks that Register upserts the device of the user with the ID of its platform and token, not returning the token
+func TestRegisterDevice_Ok(t *testing.T) {
+	// Arrange
+	req := models.RegisterDeviceReq{Platform: entities.DevicePlatformFCM, Token: "test-token", Name: "test device"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-user").Return(models.UserResp{ID: "test-user"}, nil).Once()
+	deviceRepositoryMock := mocks.NewDeviceRepository(t)
+	deviceRepositoryMock.On(testutils.FunctionName(t, ports.DeviceRepository.Upsert), context.Background(), mock.MatchedBy(func(device entities.Device) bool {
+		return device.ID == deviceID(entities.DevicePlatformFCM, "test-token") && device.UserID == "test-user" && device.Token == "test-token"
+	})).Return(nil).Once()
+
+	service := NewDeviceService(deviceRepositoryMock, userServiceMock, mocks.NewPushSender(t))
+
+	// Act
+	resp, err := service.Register(context.Background(), "test-user", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, deviceID(entities.DevicePlatformFCM, "test-token"), resp.ID)
+	assert.Equal(t, "test device", resp.Name)
+}
+
+// TestRegisterDevice_InvalidPlatform checks that Register returns a validation error when the platform is not fcm or apns
+func TestRegisterDevice_InvalidPlatform(t *testing.T) {
+	// Arrange
+	req := models.RegisterDeviceReq{Platform: "wns", Token: "test-token"}
+	service := NewDeviceService(mocks.NewDeviceRepository(t), mocks.NewUserService(t), mocks.NewPushSender(t))
+
+	// Act
+	_, err := service.Register(context.Background(), "test-user", req)
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+}
+
+// TestRegisterDevice_UserNotFound checks that Register returns the error of the user service when the user does not exist
+func TestRegisterDevice_UserNotFound(t *testing.T) {
+	// Arrange
+	req := models.RegisterDeviceReq{Platform: entities.DevicePlatformAPNs, Token: "test-token"}
+	expectedError := wrappers.NewNonExistentErr(fmt.Errorf("user not found"))
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-user").Return(models.UserResp{}, expectedError).Once()
+
+	service := NewDeviceService(mocks.NewDeviceRepository(t), userServiceMock, mocks.NewPushSender(t))
+
+	// Act
+	_, err := service.Register(context.Background(), "test-user", req)
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
+
+// TestNotifyDevices_PartialFailure checks that Notify sends the notification to every device of the user, returning the errors of the ones failing
+func TestNotifyDevices_PartialFailure(t *testing.T) {
+	// Arrange
+	devices := []entities.Device{{ID: "first"}, {ID: "second"}}
+	notification := ports.PushNotification{Title: "test title"}
+	expectedError := errors.New("platform not reachable")
+
+	deviceRepositoryMock := mocks.NewDeviceRepository(t)
+	deviceRepositoryMock.On(testutils.FunctionName(t, ports.DeviceRepository.GetByUser), context.Background(), "test-user").Return(devices, nil).Once()
+	pushSenderMock := mocks.NewPushSender(t)
+	pushSenderMock.On(testutils.FunctionName(t, ports.PushSender.Send), context.Background(), devices[0], notification).Return(expectedError).Once()
+	pushSenderMock.On(testutils.FunctionName(t, ports.PushSender.Send), context.Background(), devices[1], notification).Return(nil).Once()
+
+	service := NewDeviceService(deviceRepositoryMock, mocks.NewUserService(t), pushSenderMock)
+
+	// Act
+	err := service.Notify(context.Background(), "test-user", notification)
+
+	// Assert
+	assert.ErrorIs(t, err, expectedError)
+}
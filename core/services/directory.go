@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// validateDirectoryLogin returns the user of the credentials verified by the directory of its tenant, or the reason of the failure along with the error.
+// The local password is never checked, so the users disabled in the directory cannot log in. The user is created on its first login,
+// without a local password, and its name and surnames are synced from the directory on every login.
+func (s *userService) validateDirectoryLogin(ctx context.Context, credentials models.LoginUserReq) (entities.User, string, error) {
+	attributes, err := s.verifier.Verify(ctx, credentials.Email, credentials.Password)
+	if errors.Is(err, ports.ErrDirectoryCredentialsNotValid) {
+		return entities.User{}, loginFailurePasswordIncorrect, wrappers.NewValidationErr(fmt.Errorf("password incorrect"))
+	}
+	if err != nil {
+		return entities.User{}, loginFailureError, err
+	}
+
+	user, err := s.getByEmail(ctx, credentials.Email, nil)
+	switch {
+	case errors.Is(err, wrappers.NonExistentErr):
+		user, err = s.createDirectoryUser(ctx, credentials.Email, attributes)
+	case err == nil:
+		user, err = s.syncDirectoryUser(ctx, user, attributes)
+	}
+	if err != nil {
+		return entities.User{}, loginFailureError, err
+	}
+	return user, "", nil
+}
+
+// createDirectoryUser creates the user of the email with the attributes of its directory
+func (s *userService) createDirectoryUser(ctx context.Context, email string, attributes ports.DirectoryUser) (entities.User, error) {
+	now := time.Now().UTC()
+	user := entities.User{
+		Name:      attributes.Name,
+		Surnames:  attributes.Surnames,
+		Email:     normalizeEmail(email),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	insertedID, err := s.repository.Create(ctx, user)
+	if err != nil {
+		return entities.User{}, err
+	}
+	signups.Add(1)
+	record(ctx, s.logger, s.audit, entities.AuditUserCreated, insertedID, map[string]string{"source": "directory"})
+
+	user.ID = insertedID
+	return user, nil
+}
+
+// syncDirectoryUser updates the name and the surnames of the user with the ones of its directory, when set and changed
+func (s *userService) syncDirectoryUser(ctx context.Context, user entities.User, attributes ports.DirectoryUser) (entities.User, error) {
+	var fields []string
+	if attributes.Name != "" && attributes.Name != user.Name {
+		user.Name = attributes.Name
+		fields = append(fields, "name")
+	}
+	if attributes.Surnames != "" && attributes.Surnames != user.Surnames {
+		user.Surnames = attributes.Surnames
+		fields = append(fields, "surnames")
+	}
+	if len(fields) == 0 {
+		return user, nil
+	}
+
+	ID := user.ID
+	user.ID = ""
+	user.UpdatedAt = time.Now().UTC()
+	if err := s.repository.Update(ctx, ID, user); err != nil {
+		return entities.User{}, err
+	}
+	record(ctx, s.logger, s.audit, entities.AuditUserUpdated, ID, map[string]string{"fields": strings.Join(fields, ","), "source": "directory"})
+
+	user.ID = ID
+	return user, nil
+}
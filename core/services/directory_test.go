@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestDirectoryLogin_FirstLogin checks that Login creates the user verified by its directory on its first login, with the attributes of the directory
+func TestDirectoryLogin_FirstLogin(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{Email: "Test@Example.com", Password: "test"}
+	filter := map[string]interface{}{"email": "test@example.com"}
+
+	credentialVerifierMock := mocks.NewCredentialVerifier(t)
+	credentialVerifierMock.On(testutils.FunctionName(t, ports.CredentialVerifier.Handles), req.Email).Return(true).Once()
+	credentialVerifierMock.On(testutils.FunctionName(t, ports.CredentialVerifier.Verify), context.Background(), req.Email, req.Password).Return(ports.DirectoryUser{Name: "Test", Surnames: "User"}, nil).Once()
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), filter, map[string]interface{}(nil), nilPointer, nilPointer).Return(nil, wrappers.NonExistentErr).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), context.Background(), mock.MatchedBy(func(user entities.User) bool {
+		return user.Email == "test@example.com" && user.Name == "Test" && user.Surnames == "User" && user.PasswordHash == ""
+	})).Return("test-id", nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateLastLogin), context.Background(), "test-id", mock.AnythingOfType("time.Time")).Return(nil).Once()
+	tokenKeysMock := mocks.NewTokenKeys(t)
+	tokenKeysMock.On(testutils.FunctionName(t, ports.TokenKeys.Sign), mock.Anything).Return("test-token", nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		keys:       tokenKeysMock,
+		verifier:   credentialVerifierMock,
+	}
+
+	// Act
+	resp, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.User.ID)
+	assert.Equal(t, "Test", resp.User.Name)
+	assert.Equal(t, "test-token", resp.Token)
+}
+
+// TestDirectoryLogin_Sync checks that Login updates the attributes of the user changed in its directory without checking its local password
+func TestDirectoryLogin_Sync(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	filter := map[string]interface{}{"email": req.Email}
+	stored := entities.User{ID: "test-id", Email: req.Email, Name: "Old", Surnames: "User", PasswordHash: "not-a-hash"}
+
+	credentialVerifierMock := mocks.NewCredentialVerifier(t)
+	credentialVerifierMock.On(testutils.FunctionName(t, ports.CredentialVerifier.Handles), req.Email).Return(true).Once()
+	credentialVerifierMock.On(testutils.FunctionName(t, ports.CredentialVerifier.Verify), context.Background(), req.Email, req.Password).Return(ports.DirectoryUser{Name: "New", Surnames: "User"}, nil).Once()
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), filter, map[string]interface{}(nil), nilPointer, nilPointer).Return([]interface{}{&stored}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), "test-id", mock.MatchedBy(func(user entities.User) bool {
+		return user.Name == "New" && user.ID == ""
+	})).Return(nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateLastLogin), context.Background(), "test-id", mock.AnythingOfType("time.Time")).Return(nil).Once()
+	tokenKeysMock := mocks.NewTokenKeys(t)
+	tokenKeysMock.On(testutils.FunctionName(t, ports.TokenKeys.Sign), mock.Anything).Return("test-token", nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		keys:       tokenKeysMock,
+		verifier:   credentialVerifierMock,
+	}
+
+	// Act
+	resp, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.User.ID)
+	assert.Equal(t, "New", resp.User.Name)
+}
+
+// TestDirectoryLogin_PasswordIncorrect checks that Login returns the same error as for an incorrect local password when the directory refuses the credentials
+func TestDirectoryLogin_PasswordIncorrect(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{Email: "test@example.com", Password: "incorrect-password"}
+	incorrect := counter(loginFailures, loginFailurePasswordIncorrect)
+
+	credentialVerifierMock := mocks.NewCredentialVerifier(t)
+	credentialVerifierMock.On(testutils.FunctionName(t, ports.CredentialVerifier.Handles), req.Email).Return(true).Once()
+	credentialVerifierMock.On(testutils.FunctionName(t, ports.CredentialVerifier.Verify), context.Background(), req.Email, req.Password).Return(ports.DirectoryUser{}, ports.ErrDirectoryCredentialsNotValid).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: mocks.NewUserRepository(t),
+		verifier:   credentialVerifierMock,
+	}
+
+	// Act
+	_, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "password incorrect", err.Error())
+	assert.Equal(t, incorrect+1, counter(loginFailures, loginFailurePasswordIncorrect))
+}
+
+// TestDirectoryUpdate_PasswordManaged checks that Update refuses to change the password of a user whose password is verified by its directory
+func TestDirectoryUpdate_PasswordManaged(t *testing.T) {
+	// Arrange
+	oldPassword, newPassword := "old", "new"
+	req := models.UpdateUserReq{OldPassword: &oldPassword, NewPassword: &newPassword}
+	stored := entities.User{ID: "test-id", Email: "test@example.com"}
+
+	credentialVerifierMock := mocks.NewCredentialVerifier(t)
+	credentialVerifierMock.On(testutils.FunctionName(t, ports.CredentialVerifier.Handles), "test@example.com").Return(true).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), "test-id", map[string]interface{}(nil)).Return(&stored, nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		verifier:   credentialVerifierMock,
+	}
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "the password of test@example.com is managed by its directory", err.Error())
+}
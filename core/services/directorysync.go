@@ -0,0 +1,303 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// directorySyncSource value of the source detail of the audit events of the changes made by the directory sync
+const directorySyncSource = "directory_sync"
+
+// directorySyncService adapter of the directory sync service, reconciling the local users of the synced domains with the users of the source.
+// The reports are stored as JSON files in the file storage, at the key set in the report metadata of their job.
+type directorySyncService struct {
+	config           config.Config
+	logger           zerolog.Logger
+	source           ports.DirectorySource
+	users            ports.UserRepository
+	storage          ports.FileStorage
+	jobs             ports.JobRepository
+	audit            ports.AuditRepository
+	publisher        ports.EventPublisher
+	domains          map[string]bool
+	maxDeactivations int
+}
+
+// NewDirectorySyncService creates a new directory sync service of the users of DirectorySync.Domains or, when not set and the source is ldap,
+// of the domains of LDAP.Directories, so the local users of any other domain are never changed by a sync.
+// The lifecycle events of the users changed are published through the publisher, when set.
+func NewDirectorySyncService(cfg config.Config, logger zerolog.Logger, source ports.DirectorySource, users ports.UserRepository, storage ports.FileStorage, jobs ports.JobRepository, audit ports.AuditRepository, publisher ports.EventPublisher) ports.DirectorySyncService {
+	domains := map[string]bool{}
+	for _, domain := range cfg.DirectorySync.Domains {
+		domains[strings.ToLower(domain)] = true
+	}
+	if len(domains) == 0 && cfg.DirectorySync.Source == "ldap" {
+		for _, directory := range cfg.LDAP.Directories {
+			for _, domain := range directory.Domains {
+				domains[strings.ToLower(domain)] = true
+			}
+		}
+	}
+
+	return &directorySyncService{
+		config:           cfg,
+		logger:           logger,
+		source:           source,
+		users:            users,
+		storage:          storage,
+		jobs:             jobs,
+		audit:            audit,
+		publisher:        publisher,
+		domains:          domains,
+		maxDeactivations: cfg.DirectorySync.MaxDeactivations,
+	}
+}
+
+// Sync queues a sync of the users with the source which, in dry run, only reports the changes it would make
+func (s *directorySyncService) Sync(ctx context.Context, dryRun bool) (resp models.JobResp, err error) {
+	job := newQueuedJob(s.config, entities.JobTypeDirectorySync, map[string]string{"dry_run": strconv.FormatBool(dryRun)})
+	job.ID, err = s.jobs.Create(ctx, job)
+	if err != nil {
+		return
+	}
+
+	resp = models.JobResp(job)
+	return
+}
+
+// GetReport returns the report of the sync of the job, once it succeeded
+func (s *directorySyncService) GetReport(ctx context.Context, jobID string) (resp models.DirectorySyncReportResp, err error) {
+	job, err := s.jobs.GetByID(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("directory sync %s not found", jobID))
+		}
+		return
+	}
+	if job.Type != entities.JobTypeDirectorySync {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("directory sync %s not found", jobID))
+		return
+	}
+	if job.Metadata["report"] == "" {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("report of directory sync %s not found, the sync is %s", jobID, job.Status))
+		return
+	}
+
+	content, _, err := s.storage.Open(ctx, job.Metadata["report"])
+	if err != nil {
+		return
+	}
+	defer content.Close()
+
+	err = json.NewDecoder(content).Decode(&resp)
+	return
+}
+
+// Process runs a sync job claimed by a worker, diffing the users of the source with the local users of the synced domains:
+// the users only in the source are created, without a password, the ones whose name or surnames changed are updated,
+// and the local users no longer in the source are deactivated, moving them to the archive, where they can be unarchived from.
+// The sync is aborted without changes when the source lists no users or when more users than DirectorySync.MaxDeactivations would be deactivated,
+// so a source emptied by mistake does not deactivate every user. Every change is counted as processed, and a failed change fails the job,
+// its next attempt diffing the users again. The counts and the key of the report are set in the metadata of the job.
+func (s *directorySyncService) Process(ctx context.Context, job *entities.Job) error {
+	job.Processed = 0
+	dryRun := job.Metadata["dry_run"] == "true"
+
+	report, err := s.diff(ctx)
+	if err != nil {
+		return err
+	}
+	report.JobID, report.DryRun = job.ID, dryRun
+	if s.maxDeactivations > 0 && len(report.Deactivated) > s.maxDeactivations {
+		return fmt.Errorf("%d users to deactivate, more than the %d allowed by DirectorySync.MaxDeactivations, sync aborted", len(report.Deactivated), s.maxDeactivations)
+	}
+
+	if !dryRun {
+		if err := s.apply(ctx, job, report); err != nil {
+			return err
+		}
+		record(ctx, s.logger, s.audit, entities.AuditDirectorySynced, "", map[string]string{
+			"job_id":      job.ID,
+			"created":     strconv.Itoa(len(report.Created)),
+			"updated":     strconv.Itoa(len(report.Updated)),
+			"deactivated": strconv.Itoa(len(report.Deactivated)),
+		})
+	}
+
+	report.GeneratedAt = time.Now().UTC()
+	key := directorySyncReportKey(job.ID)
+	content, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	file := entities.File{
+		Key:         key,
+		Name:        job.ID + ".json",
+		ContentType: "application/json",
+		Metadata:    map[string]string{"job_id": job.ID},
+	}
+	if _, err := s.storage.Store(ctx, file, bytes.NewReader(content)); err != nil {
+		return err
+	}
+
+	if job.Metadata == nil {
+		job.Metadata = map[string]string{}
+	}
+	job.Metadata["created"] = strconv.Itoa(len(report.Created))
+	job.Metadata["updated"] = strconv.Itoa(len(report.Updated))
+	job.Metadata["deactivated"] = strconv.Itoa(len(report.Deactivated))
+	job.Metadata["unchanged"] = strconv.Itoa(report.Unchanged)
+	job.Metadata["skipped"] = strconv.Itoa(report.Skipped)
+	job.Metadata["report"] = key
+	return nil
+}
+
+// diff returns the report of the changes reconciling the local users of the synced domains with the users of the source, sorted by email.
+// The users of the source without a valid email or out of the synced domains are skipped, as well as the repeated ones.
+func (s *directorySyncService) diff(ctx context.Context) (models.DirectorySyncReportResp, error) {
+	var report models.DirectorySyncReportResp
+
+	entries, err := s.source.Users(ctx)
+	if err != nil {
+		return report, fmt.Errorf("directory users cannot be listed: %w", err)
+	}
+	if len(entries) == 0 {
+		return report, errors.New("directory listed no users, sync aborted")
+	}
+
+	listed := map[string]ports.DirectoryEntry{}
+	for _, entry := range entries {
+		entry.Email = normalizeEmail(entry.Email)
+		if _, ok := listed[entry.Email]; ok || !s.synced(entry.Email) {
+			report.Skipped++
+			continue
+		}
+		listed[entry.Email] = entry
+	}
+
+	err = s.users.Dump(ports.WithOperationTimeout(ctx, 0), func(user entities.User) error {
+		email := normalizeEmail(user.Email)
+		if !s.synced(email) {
+			return nil
+		}
+
+		entry, ok := listed[email]
+		if !ok {
+			report.Deactivated = append(report.Deactivated, models.DirectorySyncChangeResp{ID: user.ID, Email: user.Email, Name: user.Name, Surnames: user.Surnames})
+			return nil
+		}
+		delete(listed, email)
+
+		change := models.DirectorySyncChangeResp{ID: user.ID, Email: user.Email, Name: user.Name, Surnames: user.Surnames}
+		if entry.Name != "" && entry.Name != user.Name {
+			change.Name = entry.Name
+			change.Fields = append(change.Fields, "name")
+		}
+		if entry.Surnames != "" && entry.Surnames != user.Surnames {
+			change.Surnames = entry.Surnames
+			change.Fields = append(change.Fields, "surnames")
+		}
+		if len(change.Fields) == 0 {
+			report.Unchanged++
+			return nil
+		}
+		report.Updated = append(report.Updated, change)
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	for _, entry := range listed {
+		report.Created = append(report.Created, models.DirectorySyncChangeResp{Email: entry.Email, Name: entry.Name, Surnames: entry.Surnames})
+	}
+	for _, changes := range [][]models.DirectorySyncChangeResp{report.Created, report.Updated, report.Deactivated} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Email < changes[j].Email })
+	}
+	return report, nil
+}
+
+// synced reports whether the email is valid and of one of the synced domains
+func (s *directorySyncService) synced(email string) bool {
+	at := strings.LastIndex(email, "@")
+	return at > 0 && s.domains[email[at+1:]]
+}
+
+// apply makes the changes of the report, recording every one to the audit log and publishing its event, the created users getting their IDs in the report.
+// Only the name and the surnames of the updated users are set, so the changes made to their other fields meanwhile are kept.
+// The users deactivated meanwhile by other means are left as they are.
+func (s *directorySyncService) apply(ctx context.Context, job *entities.Job, report models.DirectorySyncReportResp) error {
+	now := time.Now().UTC()
+	details := map[string]string{"source": directorySyncSource, "job_id": job.ID}
+
+	for i, change := range report.Created {
+		user := entities.User{
+			Name:      change.Name,
+			Surnames:  change.Surnames,
+			Email:     change.Email,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		insertedID, err := s.users.Create(ctx, user)
+		if err != nil {
+			return fmt.Errorf("user %s cannot be created: %w", change.Email, err)
+		}
+		report.Created[i].ID = insertedID
+		record(ctx, s.logger, s.audit, entities.AuditUserCreated, insertedID, details)
+		publish(ctx, s.publisher, s.logger, ports.EventUserCreated, insertedID, map[string]string{"source": directorySyncSource})
+		s.processed(ctx, job)
+	}
+
+	for _, change := range report.Updated {
+		if err := s.users.UpdateNames(ctx, change.ID, change.Name, change.Surnames, now); err != nil {
+			return fmt.Errorf("user %s cannot be updated: %w", change.Email, err)
+		}
+		record(ctx, s.logger, s.audit, entities.AuditUserUpdated, change.ID, map[string]string{"fields": strings.Join(change.Fields, ","), "source": directorySyncSource, "job_id": job.ID})
+		publish(ctx, s.publisher, s.logger, ports.EventUserUpdated, change.ID, map[string]string{"fields": strings.Join(change.Fields, ","), "source": directorySyncSource})
+		s.processed(ctx, job)
+	}
+
+	for _, change := range report.Deactivated {
+		err := s.users.ArchiveByID(ctx, change.ID, now)
+		if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+			return fmt.Errorf("user %s cannot be deactivated: %w", change.Email, err)
+		}
+		if err == nil {
+			record(ctx, s.logger, s.audit, entities.AuditUserDeactivated, change.ID, details)
+			publish(ctx, s.publisher, s.logger, ports.EventUserDeleted, change.ID, map[string]string{"source": directorySyncSource, "archived": "true"})
+		}
+		s.processed(ctx, job)
+	}
+	return nil
+}
+
+// processed counts a change made, reporting the progress of the job every progressInterval changes
+func (s *directorySyncService) processed(ctx context.Context, job *entities.Job) {
+	job.Processed++
+	if job.Processed%progressInterval != 0 {
+		return
+	}
+
+	job.UpdatedAt = time.Now().UTC()
+	if err := s.jobs.Update(ctx, *job); err != nil {
+		s.logger.Error().Err(err).Str("type", job.Type).Str("job_id", job.ID).Msg("job cannot be updated")
+	}
+}
+
+func directorySyncReportKey(jobID string) string {
+	return fmt.Sprintf("directory-sync/%s.json", jobID)
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// directorySyncUsersMock returns a user repository mock dumping the given users
+func directorySyncUsersMock(t *testing.T, users ...entities.User) *mocks.UserRepository {
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Dump), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		for _, user := range users {
+			args.Get(1).(func(entities.User) error)(user)
+		}
+	}).Return(nil).Once()
+	return userRepositoryMock
+}
+
+// TestNewDirectorySyncService_LDAPDomains checks that NewDirectorySyncService syncs the domains of the LDAP directories when the ldap source has no domains set
+func TestNewDirectorySyncService_LDAPDomains(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.DirectorySync.Source = "ldap"
+	cfg.LDAP.Directories = []config.LDAPDirectory{{Domains: []string{"Example.com"}}}
+
+	// Act
+	service := NewDirectorySyncService(cfg, zerolog.Nop(), mocks.NewDirectorySource(t), mocks.NewUserRepository(t), mocks.NewFileStorage(t), mocks.NewJobRepository(t), mocks.NewAuditRepository(t), nil)
+
+	// Assert
+	assert.Equal(t, map[string]bool{"example.com": true}, service.(*directorySyncService).domains)
+}
+
+// TestDirectorySync_Ok checks that Sync returns the queued job of the sync, in dry run when asked
+func TestDirectorySync_Ok(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Create), context.Background(), mock.MatchedBy(func(job entities.Job) bool {
+		return job.Type == entities.JobTypeDirectorySync && job.Status == entities.JobStatusQueued
+	})).Return("job-id", nil).Once()
+
+	service := &directorySyncService{jobs: jobRepositoryMock}
+
+	// Act
+	resp, err := service.Sync(context.Background(), true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "job-id", resp.ID)
+	assert.Equal(t, map[string]string{"dry_run": "true"}, resp.Metadata)
+}
+
+// TestDirectorySyncProcess_Ok checks that Process creates the users only in the source, updates the changed ones and deactivates the ones no longer in it,
+// leaving the users of the domains not synced as they are, publishes their events and stores the report of the changes
+func TestDirectorySyncProcess_Ok(t *testing.T) {
+	// Arrange
+	sourceMock := mocks.NewDirectorySource(t)
+	sourceMock.On(testutils.FunctionName(t, ports.DirectorySource.Users), mock.Anything).Return([]ports.DirectoryEntry{
+		{Email: "New@Example.com", Name: "New", Surnames: "User"},
+		{Email: "changed@example.com", Name: "Changed", Surnames: "User"},
+		{Email: "same@example.com", Name: "Same"},
+		{Email: "other@other.com", Name: "Other"},
+	}, nil).Once()
+	userRepositoryMock := directorySyncUsersMock(t,
+		entities.User{ID: "changed-id", Email: "changed@example.com", Name: "Old", Surnames: "User", PasswordHash: "test-hash"},
+		entities.User{ID: "same-id", Email: "same@example.com", Name: "Same", Surnames: "User"},
+		entities.User{ID: "gone-id", Email: "gone@example.com", Name: "Gone"},
+		entities.User{ID: "other-id", Email: "other-local@other.com", Name: "Other"},
+	)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), mock.Anything, mock.MatchedBy(func(user entities.User) bool {
+		return user.Email == "new@example.com" && user.Name == "New" && user.PasswordHash == ""
+	})).Return("new-id", nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateNames), mock.Anything, "changed-id", "Changed", "User", mock.Anything).Return(nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.ArchiveByID), mock.Anything, "gone-id", mock.Anything).Return(nil).Once()
+	var stored models.DirectorySyncReportResp
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Store), mock.Anything, mock.MatchedBy(func(file entities.File) bool {
+		return file.Key == "directory-sync/job-id.json"
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		json.NewDecoder(args.Get(2).(io.Reader)).Decode(&stored)
+	}).Return(entities.File{}, nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), mock.Anything, mock.MatchedBy(func(event entities.AuditEvent) bool {
+		return event.Type == entities.AuditUserDeactivated && event.UserID == "gone-id" && event.Details["source"] == "directory_sync"
+	})).Return(nil).Once()
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), mock.Anything, mock.Anything).Return(nil).Times(3)
+	var published []string
+	publisherMock := mocks.NewEventPublisher(t)
+	publisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, mock.MatchedBy(func(event ports.Event) bool {
+		return event.Data["source"] == "directory_sync"
+	})).Run(func(args mock.Arguments) {
+		event := args.Get(1).(ports.Event)
+		published = append(published, event.Type+" "+event.UserID)
+	}).Return(nil).Times(3)
+
+	cfg := config.Config{}
+	cfg.DirectorySync.Domains = []string{"example.com"}
+	service := NewDirectorySyncService(cfg, zerolog.Nop(), sourceMock, userRepositoryMock, fileStorageMock, mocks.NewJobRepository(t), auditRepositoryMock, publisherMock)
+	job := &entities.Job{ID: "job-id", Type: entities.JobTypeDirectorySync, Metadata: map[string]string{"dry_run": "false"}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), job.Processed)
+	assert.Equal(t, map[string]string{"dry_run": "false", "created": "1", "updated": "1", "deactivated": "1", "unchanged": "1", "skipped": "1", "report": "directory-sync/job-id.json"}, job.Metadata)
+	assert.Equal(t, []models.DirectorySyncChangeResp{{ID: "new-id", Email: "new@example.com", Name: "New", Surnames: "User"}}, stored.Created)
+	assert.Equal(t, []models.DirectorySyncChangeResp{{ID: "changed-id", Email: "changed@example.com", Name: "Changed", Surnames: "User", Fields: []string{"name"}}}, stored.Updated)
+	assert.Equal(t, []models.DirectorySyncChangeResp{{ID: "gone-id", Email: "gone@example.com", Name: "Gone"}}, stored.Deactivated)
+	assert.Equal(t, "job-id", stored.JobID)
+	assert.Equal(t, []string{"user.created new-id", "user.updated changed-id", "user.deleted gone-id"}, published)
+}
+
+// TestDirectorySyncProcess_DryRun checks that Process only stores the report of the changes in dry run
+func TestDirectorySyncProcess_DryRun(t *testing.T) {
+	// Arrange
+	sourceMock := mocks.NewDirectorySource(t)
+	sourceMock.On(testutils.FunctionName(t, ports.DirectorySource.Users), mock.Anything).Return([]ports.DirectoryEntry{{Email: "new@example.com"}}, nil).Once()
+	userRepositoryMock := directorySyncUsersMock(t, entities.User{ID: "gone-id", Email: "gone@example.com"})
+	var stored models.DirectorySyncReportResp
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Store), mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		json.NewDecoder(args.Get(2).(io.Reader)).Decode(&stored)
+	}).Return(entities.File{}, nil).Once()
+
+	cfg := config.Config{}
+	cfg.DirectorySync.Domains = []string{"example.com"}
+	service := NewDirectorySyncService(cfg, zerolog.Nop(), sourceMock, userRepositoryMock, fileStorageMock, mocks.NewJobRepository(t), mocks.NewAuditRepository(t), nil)
+	job := &entities.Job{ID: "job-id", Type: entities.JobTypeDirectorySync, Metadata: map[string]string{"dry_run": "true"}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), job.Processed)
+	assert.True(t, stored.DryRun)
+	assert.Equal(t, 1, len(stored.Created))
+	assert.Equal(t, 1, len(stored.Deactivated))
+}
+
+// TestDirectorySyncProcess_EmptySource checks that Process aborts the sync without reading the local users when the source lists no users
+func TestDirectorySyncProcess_EmptySource(t *testing.T) {
+	// Arrange
+	sourceMock := mocks.NewDirectorySource(t)
+	sourceMock.On(testutils.FunctionName(t, ports.DirectorySource.Users), mock.Anything).Return(nil, nil).Once()
+
+	service := &directorySyncService{source: sourceMock, users: mocks.NewUserRepository(t)}
+	job := &entities.Job{ID: "job-id", Metadata: map[string]string{"dry_run": "false"}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Equal(t, "directory listed no users, sync aborted", err.Error())
+}
+
+// TestDirectorySyncProcess_SourceError checks that Process returns an error when the source cannot list its users
+func TestDirectorySyncProcess_SourceError(t *testing.T) {
+	// Arrange
+	sourceMock := mocks.NewDirectorySource(t)
+	sourceMock.On(testutils.FunctionName(t, ports.DirectorySource.Users), mock.Anything).Return(nil, errors.New("source error")).Once()
+
+	service := &directorySyncService{source: sourceMock}
+	job := &entities.Job{ID: "job-id", Metadata: map[string]string{"dry_run": "false"}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Equal(t, "directory users cannot be listed: source error", err.Error())
+}
+
+// TestDirectorySyncProcess_TooManyDeactivations checks that Process aborts the sync without changes when more users than allowed would be deactivated
+func TestDirectorySyncProcess_TooManyDeactivations(t *testing.T) {
+	// Arrange
+	sourceMock := mocks.NewDirectorySource(t)
+	sourceMock.On(testutils.FunctionName(t, ports.DirectorySource.Users), mock.Anything).Return([]ports.DirectoryEntry{{Email: "kept@example.com"}}, nil).Once()
+	userRepositoryMock := directorySyncUsersMock(t,
+		entities.User{ID: "first-id", Email: "first@example.com"},
+		entities.User{ID: "second-id", Email: "second@example.com"},
+	)
+
+	cfg := config.Config{}
+	cfg.DirectorySync.Domains = []string{"example.com"}
+	cfg.DirectorySync.MaxDeactivations = 1
+	service := NewDirectorySyncService(cfg, zerolog.Nop(), sourceMock, userRepositoryMock, mocks.NewFileStorage(t), mocks.NewJobRepository(t), mocks.NewAuditRepository(t), nil)
+	job := &entities.Job{ID: "job-id", Metadata: map[string]string{"dry_run": "false"}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Equal(t, "2 users to deactivate, more than the 1 allowed by DirectorySync.MaxDeactivations, sync aborted", err.Error())
+}
+
+// TestGetReport_Ok checks that GetReport returns the stored report of a finished sync
+func TestGetReport_Ok(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.GetByID), context.Background(), "job-id").Return(entities.Job{
+		ID:       "job-id",
+		Type:     entities.JobTypeDirectorySync,
+		Status:   entities.JobStatusSucceeded,
+		Metadata: map[string]string{"report": "directory-sync/job-id.json"},
+	}, nil).Once()
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Open), context.Background(), "directory-sync/job-id.json").
+		Return(io.NopCloser(strings.NewReader(`{"job_id":"job-id","dry_run":true,"unchanged":2}`)), entities.File{}, nil).Once()
+
+	service := &directorySyncService{jobs: jobRepositoryMock, storage: fileStorageMock}
+
+	// Act
+	resp, err := service.GetReport(context.Background(), "job-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.DirectorySyncReportResp{JobID: "job-id", DryRun: true, Unchanged: 2}, resp)
+}
+
+// TestGetReport_NotFinished checks that GetReport returns a NonExistent error when the sync has no report yet
+func TestGetReport_NotFinished(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.GetByID), context.Background(), "job-id").Return(entities.Job{
+		ID:     "job-id",
+		Type:   entities.JobTypeDirectorySync,
+		Status: entities.JobStatusRunning,
+	}, nil).Once()
+
+	service := &directorySyncService{jobs: jobRepositoryMock}
+
+	// Act
+	_, err := service.GetReport(context.Background(), "job-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Contains(t, err.Error(), "report of directory sync job-id not found, the sync is running")
+}
+
+// TestGetReport_OtherJob checks that GetReport returns a NonExistent error when the job is not a directory sync
+func TestGetReport_OtherJob(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.GetByID), context.Background(), "job-id").Return(entities.Job{
+		ID:       "job-id",
+		Type:     entities.JobTypeBackup,
+		Metadata: map[string]string{"report": "backups/users/test.jsonl"},
+	}, nil).Once()
+
+	service := &directorySyncService{jobs: jobRepositoryMock}
+
+	// Act
+	_, err := service.GetReport(context.Background(), "job-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
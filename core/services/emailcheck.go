@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// emailCheckUserService decorator of an user service that checks the deliverability of the emails
+type emailCheckUserService struct {
+	ports.UserService
+	verifier ports.EmailVerifier
+	audit    ports.AuditRepository
+	logger   zerolog.Logger
+	reject   bool
+	timeout  time.Duration
+}
+
+// NewEmailCheckUserService wraps a user service verifying the new emails, refused when reject is set or flagged otherwise
+func NewEmailCheckUserService(service ports.UserService, verifier ports.EmailVerifier, audit ports.AuditRepository, logger zerolog.Logger, reject bool, timeout time.Duration) ports.UserService {
+	return &emailCheckUserService{
+		UserService: service,
+		verifier:    verifier,
+		audit:       audit,
+		logger:      logger,
+		reject:      reject,
+		timeout:     timeout,
+	}
+}
+
+func (s *emailCheckUserService) Create(ctx context.Context, user models.CreateUserReq) (models.CreationResp, error) {
+	reason, err := s.check(ctx, user.Email)
+	if err != nil {
+		return models.CreationResp{}, err
+	}
+	resp, err := s.UserService.Create(ctx, user)
+	if err == nil && reason != "" {
+		s.flag(ctx, resp.InsertedID, user.Email, reason)
+	}
+	return resp, err
+}
+
+func (s *emailCheckUserService) CreateMany(ctx context.Context, users []models.CreateUserReq) (models.MultiCreationResp, error) {
+	reasons := make([]string, len(users))
+	for i, user := range users {
+		reason, err := s.check(ctx, user.Email)
+		if err != nil {
+			return models.MultiCreationResp{}, err
+		}
+		reasons[i] = reason
+	}
+	resp, err := s.UserService.CreateMany(ctx, users)
+	if err != nil {
+		return resp, err
+	}
+	for i, ID := range resp.InsertedIDs {
+		if i < len(reasons) && reasons[i] != "" {
+			s.flag(ctx, ID, users[i].Email, reasons[i])
+		}
+	}
+	return resp, nil
+}
+
+func (s *emailCheckUserService) Upsert(ctx context.Context, email string, user models.UpsertUserReq) (models.UpsertionResp, error) {
+	reason, err := s.check(ctx, email)
+	if err != nil {
+		return models.UpsertionResp{}, err
+	}
+	resp, err := s.UserService.Upsert(ctx, email, user)
+	if err == nil && reason != "" {
+		s.flag(ctx, resp.ID, email, reason)
+	}
+	return resp, err
+}
+
+func (s *emailCheckUserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) error {
+	if user.Email == nil {
+		return s.UserService.Update(ctx, ID, user)
+	}
+	reason, err := s.check(ctx, *user.Email)
+	if err != nil {
+		return err
+	}
+	if err = s.UserService.Update(ctx, ID, user); err != nil {
+		return err
+	}
+	if reason != "" {
+		s.flag(ctx, ID, *user.Email, reason)
+	}
+	return nil
+}
+
+// check verifies the email, returning the reason it is not deliverable when it is to be flagged,
+// and a validation error when it is to be rejected. The emails left empty are validated by the decorated service.
+func (s *emailCheckUserService) check(ctx context.Context, email string) (string, error) {
+	email = normalizeEmail(email)
+	if email == "" {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	reason, err := s.verifier.Verify(ctx, email)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("email", email).Msg("email deliverability cannot be verified")
+		return "", nil
+	}
+	if reason == "" {
+		return "", nil
+	}
+
+	undeliverableEmail(reason)
+	if s.reject {
+		return "", wrappers.NewValidationErr(fmt.Errorf("email %s is not deliverable: %s", email, reason))
+	}
+	return reason, nil
+}
+
+// flag records the undeliverable email of the user to the audit log
+func (s *emailCheckUserService) flag(ctx context.Context, userID, email, reason string) {
+	s.logger.Warn().Str("user", userID).Str("reason", reason).Msg("undeliverable email flagged")
+	record(ctx, s.logger, s.audit, entities.AuditEmailFlagged, userID, map[string]string{"email": normalizeEmail(email), "reason": reason})
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// undeliverableCount returns the count of undeliverable emails with the reason
+func undeliverableCount(reason string) int64 {
+	if count, ok := undeliverable.Get(reason).(*expvar.Int); ok {
+		return count.Value()
+	}
+	return 0
+}
+
+// TestEmailCheckCreate_Rejected checks that Create refuses an undeliverable email with a validation error when rejecting them, without creating the user
+func TestEmailCheckCreate_Rejected(t *testing.T) {
+	// Arrange
+	verifierMock := mocks.NewEmailVerifier(t)
+	verifierMock.On(testutils.FunctionName(t, ports.EmailVerifier.Verify), mock.Anything, "test@mailinator.com").Return(ports.EmailReasonDisposable, nil).Once()
+	undeliverableBefore := undeliverableCount(ports.EmailReasonDisposable)
+
+	service := NewEmailCheckUserService(mocks.NewUserService(t), verifierMock, nil, zerolog.Nop(), true, time.Second)
+
+	// Act
+	_, err := service.Create(context.Background(), models.CreateUserReq{Email: " Test@Mailinator.com", Password: "test"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "email test@mailinator.com is not deliverable: disposable_domain", err.Error())
+	assert.Equal(t, undeliverableBefore+1, undeliverableCount(ports.EmailReasonDisposable))
+}
+
+// TestEmailCheckCreate_Flagged checks that Create creates the user of an undeliverable email when flagging them, recording it to the audit log
+func TestEmailCheckCreate_Flagged(t *testing.T) {
+	// Arrange
+	user := models.CreateUserReq{Email: "test@unknown.example.com", Password: "test"}
+	verifierMock := mocks.NewEmailVerifier(t)
+	verifierMock.On(testutils.FunctionName(t, ports.EmailVerifier.Verify), mock.Anything, user.Email).Return(ports.EmailReasonNoMailServer, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), context.Background(), user).Return(models.CreationResp{InsertedID: "test-id"}, nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(e entities.AuditEvent) bool {
+		return e.Type == entities.AuditEmailFlagged && e.UserID == "test-id" && e.Details["reason"] == ports.EmailReasonNoMailServer
+	})).Return(nil).Once()
+
+	service := NewEmailCheckUserService(userServiceMock, verifierMock, auditRepositoryMock, zerolog.Nop(), false, time.Second)
+
+	// Act
+	resp, err := service.Create(context.Background(), user)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.InsertedID)
+}
+
+// TestEmailCheckCreate_VerifierError checks that Create creates the user without flagging it when its email cannot be verified
+func TestEmailCheckCreate_VerifierError(t *testing.T) {
+	// Arrange
+	user := models.CreateUserReq{Email: "test@example.com", Password: "test"}
+	verifierMock := mocks.NewEmailVerifier(t)
+	verifierMock.On(testutils.FunctionName(t, ports.EmailVerifier.Verify), mock.Anything, user.Email).Return("", errors.New("dns error")).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), context.Background(), user).Return(models.CreationResp{InsertedID: "test-id"}, nil).Once()
+
+	service := NewEmailCheckUserService(userServiceMock, verifierMock, mocks.NewAuditRepository(t), zerolog.Nop(), true, time.Second)
+
+	// Act
+	_, err := service.Create(context.Background(), user)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestEmailCheckUpdate_EmailNotChanged checks that Update does not verify the email when it is not changed
+func TestEmailCheckUpdate_EmailNotChanged(t *testing.T) {
+	// Arrange
+	name := "test"
+	user := models.UpdateUserReq{Name: &name}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), context.Background(), "test-id", user).Return(nil).Once()
+
+	service := NewEmailCheckUserService(userServiceMock, mocks.NewEmailVerifier(t), nil, zerolog.Nop(), true, time.Second)
+
+	// Act
+	err := service.Update(context.Background(), "test-id", user)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestEmailCheckUpdate_Rejected checks that Update refuses to change the email to an undeliverable one when rejecting them
+func TestEmailCheckUpdate_Rejected(t *testing.T) {
+	// Arrange
+	email := "test@-example.com"
+	verifierMock := mocks.NewEmailVerifier(t)
+	verifierMock.On(testutils.FunctionName(t, ports.EmailVerifier.Verify), mock.Anything, email).Return(ports.EmailReasonSyntax, nil).Once()
+
+	service := NewEmailCheckUserService(mocks.NewUserService(t), verifierMock, nil, zerolog.Nop(), true, time.Second)
+
+	// Act
+	err := service.Update(context.Background(), "test-id", models.UpdateUserReq{Email: &email})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestEmailCheckCreateMany_Flagged checks that CreateMany flags only the users created with an undeliverable email
+func TestEmailCheckCreateMany_Flagged(t *testing.T) {
+	// Arrange
+	users := []models.CreateUserReq{{Email: "test@example.com", Password: "test"}, {Email: "test@yopmail.com", Password: "test"}}
+	verifierMock := mocks.NewEmailVerifier(t)
+	verifierMock.On(testutils.FunctionName(t, ports.EmailVerifier.Verify), mock.Anything, "test@example.com").Return("", nil).Once()
+	verifierMock.On(testutils.FunctionName(t, ports.EmailVerifier.Verify), mock.Anything, "test@yopmail.com").Return(ports.EmailReasonDisposable, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.CreateMany), context.Background(), users).Return(models.MultiCreationResp{InsertedIDs: []string{"test-id-1", "test-id-2"}}, nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(e entities.AuditEvent) bool {
+		return e.Type == entities.AuditEmailFlagged && e.UserID == "test-id-2"
+	})).Return(nil).Once()
+
+	service := NewEmailCheckUserService(userServiceMock, verifierMock, auditRepositoryMock, zerolog.Nop(), false, time.Second)
+
+	// Act
+	_, err := service.CreateMany(context.Background(), users)
+
+	// Assert
+	assert.Nil(t, err)
+}
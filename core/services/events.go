@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// eventsUserService decorator of an user service that publishes the lifecycle events of the users
+type eventsUserService struct {
+	ports.UserService
+	publisher ports.EventPublisher
+	logger    zerolog.Logger
+}
+
+// NewEventsUserService wraps a user service publishing the events of the users through the publisher
+func NewEventsUserService(service ports.UserService, publisher ports.EventPublisher, logger zerolog.Logger) ports.UserService {
+	return &eventsUserService{
+		UserService: service,
+		publisher:   publisher,
+		logger:      logger,
+	}
+}
+
+func (s *eventsUserService) Login(ctx context.Context, credentials models.LoginUserReq) (models.LoginUserResp, error) {
+	resp, err := s.UserService.Login(ctx, credentials)
+	if err == nil {
+		s.publish(ctx, ports.EventUserLogin, resp.User.ID, nil)
+	}
+	return resp, err
+}
+
+func (s *eventsUserService) Create(ctx context.Context, user models.CreateUserReq) (models.CreationResp, error) {
+	resp, err := s.UserService.Create(ctx, user)
+	if err == nil {
+		s.publish(ctx, ports.EventUserCreated, resp.InsertedID, nil)
+	}
+	return resp, err
+}
+
+func (s *eventsUserService) CreateMany(ctx context.Context, users []models.CreateUserReq) (models.MultiCreationResp, error) {
+	resp, err := s.UserService.CreateMany(ctx, users)
+	if err == nil {
+		for _, ID := range resp.InsertedIDs {
+			s.publish(ctx, ports.EventUserCreated, ID, nil)
+		}
+	}
+	return resp, err
+}
+
+// Upsert publishes user.created when no user had the email before, user.updated otherwise
+func (s *eventsUserService) Upsert(ctx context.Context, email string, user models.UpsertUserReq) (models.UpsertionResp, error) {
+	_, getErr := s.UserService.GetByEmail(ctx, email)
+	resp, err := s.UserService.Upsert(ctx, email, user)
+	if err != nil {
+		return resp, err
+	}
+
+	if errors.Is(getErr, wrappers.NonExistentErr) {
+		s.publish(ctx, ports.EventUserCreated, resp.ID, nil)
+	} else {
+		s.publish(ctx, ports.EventUserUpdated, resp.ID, map[string]string{"fields": "upserted"})
+	}
+	return resp, nil
+}
+
+// Update publishes user.updated with the names of the fields set in the request, like email,password
+func (s *eventsUserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) error {
+	if err := s.UserService.Update(ctx, ID, user); err != nil {
+		return err
+	}
+
+	s.publish(ctx, ports.EventUserUpdated, ID, map[string]string{"fields": strings.Join(updatedFields(user), ",")})
+	return nil
+}
+
+// Merge publishes user.updated for the target user and user.deleted for the merged one
+func (s *eventsUserService) Merge(ctx context.Context, ID string, req models.MergeUsersReq) error {
+	if err := s.UserService.Merge(ctx, ID, req); err != nil {
+		return err
+	}
+	s.publish(ctx, ports.EventUserUpdated, ID, map[string]string{"fields": "merged", "merged_from": req.SourceID})
+	s.publish(ctx, ports.EventUserDeleted, req.SourceID, map[string]string{"merged_into": ID})
+	return nil
+}
+
+func (s *eventsUserService) Delete(ctx context.Context, ID string) error {
+	if err := s.UserService.Delete(ctx, ID); err != nil {
+		return err
+	}
+	s.publish(ctx, ports.EventUserDeleted, ID, nil)
+	return nil
+}
+
+func (s *eventsUserService) publish(ctx context.Context, eventType, userID string, data map[string]string) {
+	publish(ctx, s.publisher, s.logger, eventType, userID, data)
+}
+
+// publish publishes the event of the user with a random ID through the publisher, when set, logging the failures instead of returning them
+func publish(ctx context.Context, publisher ports.EventPublisher, logger zerolog.Logger, eventType, userID string, data map[string]string) {
+	if publisher == nil {
+		return
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		logger.Error().Err(err).Str("event", eventType).Msg("event cannot be published")
+		return
+	}
+
+	err := publisher.Publish(ctx, ports.Event{
+		ID:         hex.EncodeToString(id),
+		Type:       eventType,
+		UserID:     userID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("event", eventType).Str("user", userID).Msg("event cannot be published")
+	}
+}
+
+// updatedFields returns the names of the fields set in the update request
+func updatedFields(user models.UpdateUserReq) []string {
+	var fields []string
+	for _, field := range []struct {
+		name string
+		set  bool
+	}{
+		{"name", user.Name != nil},
+		{"surnames", user.Surnames != nil},
+		{"email", user.Email != nil},
+		{"password", user.NewPassword != nil},
+		{"claims", user.Claims != nil},
+		{"location", user.Location != nil},
+	} {
+		if field.set {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// eventOf returns a matcher of the events of the given type and user with the given data
+func eventOf(eventType, userID string, data map[string]string) interface{} {
+	return mock.MatchedBy(func(event ports.Event) bool {
+		return event.ID != "" && event.Type == eventType && event.UserID == userID && !event.OccurredAt.IsZero() && assert.ObjectsAreEqual(data, event.Data)
+	})
+}
+
+// TestEventsCreate_Ok checks that Create publishes user.created for the created user
+func TestEventsCreate_Ok(t *testing.T) {
+	// Arrange
+	req := models.CreateUserReq{Email: "test@example.com"}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), mock.Anything, req).Return(models.CreationResp{InsertedID: "test-id"}, nil).Once()
+	publisherMock := mocks.NewEventPublisher(t)
+	publisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, eventOf(ports.EventUserCreated, "test-id", nil)).Return(nil).Once()
+
+	service := NewEventsUserService(userServiceMock, publisherMock, zerolog.Nop())
+
+	// Act
+	resp, err := service.Create(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.InsertedID)
+}
+
+// TestEventsCreate_Error checks that Create does not publish any event when the user is not created
+func TestEventsCreate_Error(t *testing.T) {
+	// Arrange
+	expectedError := errors.New("create error")
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), mock.Anything, mock.Anything).Return(models.CreationResp{}, expectedError).Once()
+
+	service := NewEventsUserService(userServiceMock, mocks.NewEventPublisher(t), zerolog.Nop())
+
+	// Act
+	_, err := service.Create(context.Background(), models.CreateUserReq{})
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
+
+// TestEventsLogin_Ok checks that Login publishes user.login for the logged user
+func TestEventsLogin_Ok(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, req).Return(models.LoginUserResp{User: models.UserResp{ID: "test-id"}}, nil).Once()
+	publisherMock := mocks.NewEventPublisher(t)
+	publisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, eventOf(ports.EventUserLogin, "test-id", nil)).Return(nil).Once()
+
+	service := NewEventsUserService(userServiceMock, publisherMock, zerolog.Nop())
+
+	// Act
+	_, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestEventsUpdate_Ok checks that Update publishes user.updated naming the fields set in the request
+func TestEventsUpdate_Ok(t *testing.T) {
+	// Arrange
+	email, password := "new@example.com", "new-password"
+	req := models.UpdateUserReq{Email: &email, NewPassword: &password}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", req).Return(nil).Once()
+	publisherMock := mocks.NewEventPublisher(t)
+	publisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, eventOf(ports.EventUserUpdated, "test-id", map[string]string{"fields": "email,password"})).Return(nil).Once()
+
+	service := NewEventsUserService(userServiceMock, publisherMock, zerolog.Nop())
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestEventsUpsert_Created checks that Upsert publishes user.created when no user had the email
+func TestEventsUpsert_Created(t *testing.T) {
+	// Arrange
+	req := models.UpsertUserReq{}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByEmail), mock.Anything, "test@example.com").Return(models.UserResp{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Upsert), mock.Anything, "test@example.com", req).Return(models.UpsertionResp{ID: "test-id"}, nil).Once()
+	publisherMock := mocks.NewEventPublisher(t)
+	publisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, eventOf(ports.EventUserCreated, "test-id", nil)).Return(nil).Once()
+
+	service := NewEventsUserService(userServiceMock, publisherMock, zerolog.Nop())
+
+	// Act
+	_, err := service.Upsert(context.Background(), "test@example.com", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestEventsMerge_Ok checks that Merge publishes user.updated for the target user and user.deleted for the merged one
+func TestEventsMerge_Ok(t *testing.T) {
+	// Arrange
+	req := models.MergeUsersReq{SourceID: "source-id"}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Merge), mock.Anything, "test-id", req).Return(nil).Once()
+	publisherMock := mocks.NewEventPublisher(t)
+	publisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, eventOf(ports.EventUserUpdated, "test-id", map[string]string{"fields": "merged", "merged_from": "source-id"})).Return(nil).Once()
+	publisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, eventOf(ports.EventUserDeleted, "source-id", map[string]string{"merged_into": "test-id"})).Return(nil).Once()
+
+	service := NewEventsUserService(userServiceMock, publisherMock, zerolog.Nop())
+
+	// Act
+	err := service.Merge(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestEventsDelete_PublishError checks that Delete does not fail when the event cannot be published
+func TestEventsDelete_PublishError(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Delete), mock.Anything, "test-id").Return(nil).Once()
+	publisherMock := mocks.NewEventPublisher(t)
+	publisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, eventOf(ports.EventUserDeleted, "test-id", nil)).Return(errors.New("publish error")).Once()
+
+	service := NewEventsUserService(userServiceMock, publisherMock, zerolog.Nop())
+
+	// Act
+	err := service.Delete(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// limit store key prefixes of the countries the users logged in from, and of the new countries each user logged in from
+const (
+	knownCountryKeyPrefix = "country:"
+	newCountryKeyPrefix   = "countries:"
+)
+
+// geoIPAuditRepository decorator of an audit repository that locates the IP of the events
+type geoIPAuditRepository struct {
+	ports.AuditRepository
+	locator ports.GeoIPLocator
+	logger  zerolog.Logger
+	timeout time.Duration
+}
+
+// NewGeoIPAuditRepository wraps an audit repository adding the location of the IP to the details of the events
+func NewGeoIPAuditRepository(repo ports.AuditRepository, locator ports.GeoIPLocator, logger zerolog.Logger, timeout time.Duration) ports.AuditRepository {
+	return &geoIPAuditRepository{
+		AuditRepository: repo,
+		locator:         locator,
+		logger:          logger,
+		timeout:         timeout,
+	}
+}
+
+func (r *geoIPAuditRepository) Write(ctx context.Context, event entities.AuditEvent) error {
+	if event.IP == "" {
+		return r.AuditRepository.Write(ctx, event)
+	}
+
+	location := locate(ctx, r.locator, r.logger, r.timeout, event.IP)
+	if location.Country != "" {
+		details := make(map[string]string, len(event.Details)+2)
+		for key, value := range event.Details {
+			details[key] = value
+		}
+		details["country"] = location.Country
+		if location.City != "" {
+			details["city"] = location.City
+		}
+		event.Details = details
+	}
+	return r.AuditRepository.Write(ctx, event)
+}
+
+// geoIPUserService decorator of an user service that records the logins from a new country as suspicious
+type geoIPUserService struct {
+	ports.UserService
+	locator         ports.GeoIPLocator
+	limits          ports.LimitStore
+	audit           ports.AuditRepository
+	logger          zerolog.Logger
+	timeout         time.Duration
+	knownCountryTTL time.Duration
+}
+
+// NewGeoIPUserService wraps a user service recording the logins from a country not logged in from for the TTL
+func NewGeoIPUserService(service ports.UserService, locator ports.GeoIPLocator, limits ports.LimitStore, audit ports.AuditRepository, logger zerolog.Logger, timeout, knownCountryTTL time.Duration) ports.UserService {
+	return &geoIPUserService{
+		UserService:     service,
+		locator:         locator,
+		limits:          limits,
+		audit:           audit,
+		logger:          logger,
+		timeout:         timeout,
+		knownCountryTTL: knownCountryTTL,
+	}
+}
+
+func (s *geoIPUserService) Login(ctx context.Context, credentials models.LoginUserReq) (models.LoginUserResp, error) {
+	resp, err := s.UserService.Login(ctx, credentials)
+	if err != nil {
+		return resp, err
+	}
+
+	info := models.RequestInfoFrom(ctx)
+	if info.IP == "" {
+		return resp, nil
+	}
+	location := locate(ctx, s.locator, s.logger, s.timeout, info.IP)
+	if location.Country == "" {
+		return resp, nil
+	}
+
+	logins, err := s.limits.Hit(ctx, knownCountryKeyPrefix+resp.User.ID+":"+location.Country, s.knownCountryTTL)
+	if err != nil {
+		s.logger.Error().Err(err).Str("user", resp.User.ID).Msg("country of the login cannot be counted")
+		return resp, nil
+	}
+	if logins.Count > 1 {
+		return resp, nil
+	}
+	countries, err := s.limits.Hit(ctx, newCountryKeyPrefix+resp.User.ID, s.knownCountryTTL)
+	if err != nil {
+		s.logger.Error().Err(err).Str("user", resp.User.ID).Msg("countries of the user cannot be counted")
+		return resp, nil
+	}
+	if countries.Count > 1 {
+		suspiciousLogin()
+		s.logger.Warn().Str("user", resp.User.ID).Str("country", location.Country).Msg("login from a new country")
+
+		// the user logging in is the actor of the login, as the request is not authenticated yet
+		info.ActorID = resp.User.ID
+		record(models.WithRequestInfo(ctx, info), s.logger, s.audit, entities.AuditLoginSuspicious, resp.User.ID, map[string]string{"reason": "new country"})
+	}
+	return resp, nil
+}
+
+// locate returns the location of the IP, empty when it cannot be located, logging the failure as the operation goes on without it
+func locate(ctx context.Context, locator ports.GeoIPLocator, logger zerolog.Logger, timeout time.Duration, ip string) models.GeoLocation {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	location, err := locator.Locate(ctx, ip)
+	if err != nil {
+		logger.Warn().Err(err).Str("ip", ip).Msg("ip cannot be located")
+		return models.GeoLocation{}
+	}
+	return location
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestGeoIPAuditWrite_Located checks that Write adds the country and the city of the IP of the event to a copy of its details
+func TestGeoIPAuditWrite_Located(t *testing.T) {
+	// Arrange
+	details := map[string]string{"email": "test@example.com"}
+	event := entities.AuditEvent{Type: entities.AuditLoginFailed, IP: "81.2.69.142", Details: details}
+
+	locatorMock := mocks.NewGeoIPLocator(t)
+	locatorMock.On(testutils.FunctionName(t, ports.GeoIPLocator.Locate), mock.Anything, "81.2.69.142").Return(models.GeoLocation{Country: "GB", City: "London"}, nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(e entities.AuditEvent) bool {
+		return e.Details["email"] == "test@example.com" && e.Details["country"] == "GB" && e.Details["city"] == "London"
+	})).Return(nil).Once()
+
+	repo := NewGeoIPAuditRepository(auditRepositoryMock, locatorMock, zerolog.Nop(), time.Second)
+
+	// Act
+	err := repo.Write(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"email": "test@example.com"}, details)
+}
+
+// TestGeoIPAuditWrite_LocatorError checks that Write writes the event without its location when the IP cannot be located
+func TestGeoIPAuditWrite_LocatorError(t *testing.T) {
+	// Arrange
+	event := entities.AuditEvent{Type: entities.AuditLoginFailed, IP: "81.2.69.142"}
+
+	locatorMock := mocks.NewGeoIPLocator(t)
+	locatorMock.On(testutils.FunctionName(t, ports.GeoIPLocator.Locate), mock.Anything, "81.2.69.142").Return(models.GeoLocation{}, errors.New("locator error")).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), event).Return(nil).Once()
+
+	repo := NewGeoIPAuditRepository(auditRepositoryMock, locatorMock, zerolog.Nop(), time.Second)
+
+	// Act
+	err := repo.Write(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestGeoIPLogin_NewCountry checks that Login records a suspicious login when the user logs in from a new country after another one
+func TestGeoIPLogin_NewCountry(t *testing.T) {
+	// Arrange
+	ctx := models.WithRequestInfo(context.Background(), models.RequestInfo{IP: "81.2.69.142"})
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	expectedResp := models.LoginUserResp{User: models.UserResp{ID: "test-user"}, Token: "test-token"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), ctx, credentials).Return(expectedResp, nil).Once()
+	locatorMock := mocks.NewGeoIPLocator(t)
+	locatorMock.On(testutils.FunctionName(t, ports.GeoIPLocator.Locate), mock.Anything, "81.2.69.142").Return(models.GeoLocation{Country: "GB", City: "London"}, nil).Once()
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), ctx, "country:test-user:GB", time.Hour).Return(entities.Limit{Count: 1}, nil).Once()
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), ctx, "countries:test-user", time.Hour).Return(entities.Limit{Count: 2}, nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), mock.Anything, mock.MatchedBy(func(e entities.AuditEvent) bool {
+		return e.Type == entities.AuditLoginSuspicious && e.UserID == "test-user" && e.ActorID == "test-user" && e.IP == "81.2.69.142"
+	})).Return(nil).Once()
+
+	service := NewGeoIPUserService(userServiceMock, locatorMock, limitStoreMock, auditRepositoryMock, zerolog.Nop(), time.Second, time.Hour)
+	suspiciousBefore := suspicious.Value()
+
+	// Act
+	resp, err := service.Login(ctx, credentials)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResp, resp)
+	assert.Equal(t, suspiciousBefore+1, suspicious.Value())
+}
+
+// TestGeoIPLogin_FirstCountry checks that Login does not record a suspicious login when the user logs in from its first country
+func TestGeoIPLogin_FirstCountry(t *testing.T) {
+	// Arrange
+	ctx := models.WithRequestInfo(context.Background(), models.RequestInfo{IP: "81.2.69.142"})
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), ctx, credentials).Return(models.LoginUserResp{User: models.UserResp{ID: "test-user"}}, nil).Once()
+	locatorMock := mocks.NewGeoIPLocator(t)
+	locatorMock.On(testutils.FunctionName(t, ports.GeoIPLocator.Locate), mock.Anything, "81.2.69.142").Return(models.GeoLocation{Country: "GB"}, nil).Once()
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), ctx, "country:test-user:GB", time.Hour).Return(entities.Limit{Count: 1}, nil).Once()
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), ctx, "countries:test-user", time.Hour).Return(entities.Limit{Count: 1}, nil).Once()
+
+	service := NewGeoIPUserService(userServiceMock, locatorMock, limitStoreMock, mocks.NewAuditRepository(t), zerolog.Nop(), time.Second, time.Hour)
+
+	// Act
+	_, err := service.Login(ctx, credentials)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestGeoIPLogin_KnownCountry checks that Login does not record a suspicious login when the user already logged in from the country
+func TestGeoIPLogin_KnownCountry(t *testing.T) {
+	// Arrange
+	ctx := models.WithRequestInfo(context.Background(), models.RequestInfo{IP: "81.2.69.142"})
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), ctx, credentials).Return(models.LoginUserResp{User: models.UserResp{ID: "test-user"}}, nil).Once()
+	locatorMock := mocks.NewGeoIPLocator(t)
+	locatorMock.On(testutils.FunctionName(t, ports.GeoIPLocator.Locate), mock.Anything, "81.2.69.142").Return(models.GeoLocation{Country: "GB"}, nil).Once()
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), ctx, "country:test-user:GB", time.Hour).Return(entities.Limit{Count: 3}, nil).Once()
+
+	service := NewGeoIPUserService(userServiceMock, locatorMock, limitStoreMock, mocks.NewAuditRepository(t), zerolog.Nop(), time.Second, time.Hour)
+
+	// Act
+	_, err := service.Login(ctx, credentials)
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// hashingPool bounded pool of workers running the password hashes and comparisons, so a burst of signups or a credential stuffing attack
+// queues them instead of saturating every core and starving the other requests.
+// Hashes wait for a free worker as long as the context of their request allows, failing with its error otherwise.
+// A nil hashingPool runs them right away.
+type hashingPool struct {
+	workers chan struct{}
+}
+
+// newHashingPool creates a hashing pool with the given number of workers
+func newHashingPool(workers int) *hashingPool {
+	hashingWorkers.Set(int64(workers))
+	return &hashingPool{
+		workers: make(chan struct{}, workers),
+	}
+}
+
+// run runs the hash in a worker once one is free, or returns the error of the context when done before
+func (p *hashingPool) run(ctx context.Context, hash func()) error {
+	if p == nil {
+		hash()
+		return nil
+	}
+
+	start := time.Now()
+	hashingQueued.Add(1)
+	select {
+	case p.workers <- struct{}{}:
+		hashingQueued.Add(-1)
+		hashingWait.observe(time.Since(start))
+	case <-ctx.Done():
+		hashingQueued.Add(-1)
+		hashingTimeouts.Add(1)
+		return fmt.Errorf("password hashing not started: %w", ctx.Err())
+	}
+
+	hashingActive.Add(1)
+	defer func() {
+		hashingActive.Add(-1)
+		<-p.workers
+	}()
+	hash()
+	return nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewHashingPool_Ok checks that newHashingPool creates a pool with the given number of workers and exports it
+func TestNewHashingPool_Ok(t *testing.T) {
+	// Arrange
+	workers := 3
+
+	// Act
+	pool := newHashingPool(workers)
+
+	// Assert
+	assert.Equal(t, workers, cap(pool.workers))
+	assert.Equal(t, int64(workers), hashingWorkers.Value())
+}
+
+// TestRun_Ok checks that run runs the hash in a worker and releases it once done
+func TestRun_Ok(t *testing.T) {
+	// Arrange
+	pool := newHashingPool(1)
+	var active int64
+	hashed := false
+
+	// Act
+	err := pool.run(context.Background(), func() {
+		active = hashingActive.Value()
+		hashed = true
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, hashed)
+	assert.Equal(t, int64(1), active)
+	assert.Equal(t, int64(0), hashingActive.Value())
+	assert.Equal(t, 0, len(pool.workers))
+}
+
+// TestRun_NilPool checks that run runs the hash right away when there is no pool
+func TestRun_NilPool(t *testing.T) {
+	// Arrange
+	var pool *hashingPool
+	hashed := false
+
+	// Act
+	err := pool.run(context.Background(), func() { hashed = true })
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, hashed)
+}
+
+// TestRun_ContextDone checks that run returns the error of the context and counts a timeout when no worker gets free before it is done
+func TestRun_ContextDone(t *testing.T) {
+	// Arrange
+	pool := newHashingPool(1)
+	pool.workers <- struct{}{}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	timeouts := hashingTimeouts.Value()
+	hashed := false
+
+	// Act
+	err := pool.run(ctx, func() { hashed = true })
+
+	// Assert
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, hashed)
+	assert.Equal(t, timeouts+1, hashingTimeouts.Value())
+	assert.Equal(t, int64(0), hashingQueued.Value())
+}
+
+// TestRun_Queued checks that run waits for a worker to get free, counting the hash as queued meanwhile
+func TestRun_Queued(t *testing.T) {
+	// Arrange
+	pool := newHashingPool(1)
+	pool.workers <- struct{}{}
+	done := make(chan error)
+
+	// Act
+	go func() {
+		done <- pool.run(context.Background(), func() {})
+	}()
+
+	// Assert
+	assert.Eventually(t, func() bool { return hashingQueued.Value() == 1 }, time.Second, time.Millisecond)
+	<-pool.workers
+	assert.Nil(t, <-done)
+	assert.Equal(t, int64(0), hashingQueued.Value())
+}
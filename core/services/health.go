@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// healthCheck a registered health check
+type healthCheck struct {
+	name     string
+	critical bool
+	checker  ports.HealthChecker
+}
+
+// healthService adapter of a health service
+type healthService struct {
+	timeout      time.Duration
+	statusMaxAge time.Duration
+	version      string
+	started      time.Time
+	mu           sync.RWMutex
+	checks       []healthCheck
+	last         *models.ReadinessResp
+	lastChecked  time.Time
+}
+
+// NewHealthService creates a new health service, giving every check up to the given timeout, or the deadline of the context if zero.
+// The public status of the given version reuses the last readiness check up to the given max age, and its uptime counts from now.
+func NewHealthService(timeout, statusMaxAge time.Duration, version string) ports.HealthService {
+	return &healthService{
+		timeout:      timeout,
+		statusMaxAge: statusMaxAge,
+		version:      version,
+		started:      time.Now(),
+	}
+}
+
+// Register adds a health check, replacing the one with the same name, if any
+func (s *healthService) Register(name string, critical bool, checker ports.HealthChecker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	check := healthCheck{name: name, critical: critical, checker: checker}
+	for i, c := range s.checks {
+		if c.name == name {
+			s.checks[i] = check
+			return
+		}
+	}
+	s.checks = append(s.checks, check)
+}
+
+// Check runs all the health checks concurrently, being up when all of them succeed,
+// down when a critical one fails, and degraded when only non critical ones fail
+func (s *healthService) Check(ctx context.Context) models.ReadinessResp {
+	s.mu.RLock()
+	checks := append([]healthCheck(nil), s.checks...)
+	s.mu.RUnlock()
+
+	results := make([]models.HealthCheckResp, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c healthCheck) {
+			defer wg.Done()
+			results[i] = s.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	resp := models.ReadinessResp{
+		Status: models.HealthStatusUp,
+		Checks: make(map[string]models.HealthCheckResp, len(checks)),
+	}
+	for i, c := range checks {
+		resp.Checks[c.name] = results[i]
+		if results[i].Status == models.HealthStatusUp {
+			continue
+		}
+		if c.critical {
+			resp.Status = models.HealthStatusDown
+		} else if resp.Status == models.HealthStatusUp {
+			resp.Status = models.HealthStatusDegraded
+		}
+	}
+
+	readinessChecked(resp)
+	s.mu.Lock()
+	s.last = &resp
+	s.lastChecked = time.Now()
+	s.mu.Unlock()
+	return resp
+}
+
+// Status reports the API ok when up, or degraded otherwise, along with its uptime and version, without the details of the checks.
+// The checks are only run when the last ones are older than the max age, so it stays cheap however often it is polled.
+func (s *healthService) Status(ctx context.Context) models.StatusResp {
+	s.mu.RLock()
+	last, lastChecked := s.last, s.lastChecked
+	s.mu.RUnlock()
+
+	if last == nil || time.Since(lastChecked) > s.statusMaxAge {
+		readiness := s.Check(ctx)
+		last = &readiness
+	}
+
+	status := models.ServiceStatusOK
+	if last.Status != models.HealthStatusUp {
+		status = models.ServiceStatusDegraded
+	}
+	return models.StatusResp{
+		Status:        status,
+		UptimeSeconds: int64(time.Since(s.started).Seconds()),
+		Version:       s.version,
+	}
+}
+
+func (s *healthService) run(ctx context.Context, c healthCheck) (resp models.HealthCheckResp) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	resp = models.HealthCheckResp{Status: models.HealthStatusUp, Critical: c.critical}
+	start := time.Now()
+	defer func() {
+		if rec := recover(); rec != nil {
+			resp.Status = models.HealthStatusDown
+			resp.Error = "check panicked"
+		}
+		resp.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	}()
+
+	if err := c.checker.Check(ctx); err != nil {
+		resp.Status = models.HealthStatusDown
+		resp.Error = err.Error()
+	}
+	return
+}
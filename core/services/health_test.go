@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+func checker(err error) ports.HealthChecker {
+	return ports.HealthCheckerFunc(func(ctx context.Context) error {
+		return err
+	})
+}
+
+// TestNewHealthService_Ok checks that NewHealthService creates a new healthService struct
+func TestNewHealthService_Ok(t *testing.T) {
+	// Act
+	service := NewHealthService(time.Second, time.Minute, "test-version")
+
+	// Assert
+	s, ok := service.(*healthService)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, s.timeout)
+	assert.Equal(t, time.Minute, s.statusMaxAge)
+	assert.Equal(t, "test-version", s.version)
+	assert.WithinDuration(t, time.Now(), s.started, time.Second)
+}
+
+// TestCheck_Up checks that Check reports the API up, along with every check, when all of them succeed
+func TestCheck_Up(t *testing.T) {
+	// Arrange
+	service := NewHealthService(time.Second, time.Minute, "test-version")
+	service.Register("database", true, checker(nil))
+	service.Register("storage", false, checker(nil))
+	before := counter(readinessChecks, models.HealthStatusUp)
+
+	// Act
+	resp := service.Check(context.Background())
+
+	// Assert
+	assert.Equal(t, models.HealthStatusUp, resp.Status)
+	assert.Len(t, resp.Checks, 2)
+	assert.Equal(t, models.HealthStatusUp, resp.Checks["database"].Status)
+	assert.True(t, resp.Checks["database"].Critical)
+	assert.Equal(t, before+1, counter(readinessChecks, models.HealthStatusUp))
+	assert.Equal(t, resp, *lastReadiness.Load())
+}
+
+// TestCheck_Degraded checks that Check reports the API degraded when only non critical checks fail
+func TestCheck_Degraded(t *testing.T) {
+	// Arrange
+	service := NewHealthService(time.Second, time.Minute, "test-version")
+	service.Register("database", true, checker(nil))
+	service.Register("storage", false, checker(errors.New("test-error")))
+	before := counter(readinessChecks, models.HealthStatusDegraded)
+
+	// Act
+	resp := service.Check(context.Background())
+
+	// Assert
+	assert.Equal(t, models.HealthStatusDegraded, resp.Status)
+	assert.Equal(t, models.HealthCheckResp{Status: models.HealthStatusDown, Error: "test-error", LatencyMS: resp.Checks["storage"].LatencyMS}, resp.Checks["storage"])
+	assert.Equal(t, before+1, counter(readinessChecks, models.HealthStatusDegraded))
+}
+
+// TestCheck_Down checks that Check reports the API down when a critical check fails
+func TestCheck_Down(t *testing.T) {
+	// Arrange
+	service := NewHealthService(time.Second, time.Minute, "test-version")
+	service.Register("database", true, checker(errors.New("test-error")))
+	service.Register("storage", false, checker(errors.New("test-error")))
+
+	// Act
+	resp := service.Check(context.Background())
+
+	// Assert
+	assert.Equal(t, models.HealthStatusDown, resp.Status)
+}
+
+// TestCheck_Timeout checks that Check gives up on the checks lasting longer than the timeout
+func TestCheck_Timeout(t *testing.T) {
+	// Arrange
+	service := NewHealthService(10*time.Millisecond, time.Minute, "test-version")
+	service.Register("database", true, ports.HealthCheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	// Act
+	resp := service.Check(context.Background())
+
+	// Assert
+	assert.Equal(t, models.HealthStatusDown, resp.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), resp.Checks["database"].Error)
+}
+
+// TestCheck_Panic checks that Check reports a check panicking as down
+func TestCheck_Panic(t *testing.T) {
+	// Arrange
+	service := NewHealthService(time.Second, time.Minute, "test-version")
+	service.Register("storage", false, ports.HealthCheckerFunc(func(ctx context.Context) error {
+		panic("test-panic")
+	}))
+
+	// Act
+	resp := service.Check(context.Background())
+
+	// Assert
+	assert.Equal(t, models.HealthStatusDegraded, resp.Status)
+	assert.Equal(t, "check panicked", resp.Checks["storage"].Error)
+}
+
+// TestRegister_Replace checks that Register replaces the check with the same name
+func TestRegister_Replace(t *testing.T) {
+	// Arrange
+	service := NewHealthService(time.Second, time.Minute, "test-version")
+	service.Register("database", true, checker(errors.New("test-error")))
+
+	// Act
+	service.Register("database", true, checker(nil))
+
+	// Assert
+	resp := service.Check(context.Background())
+	assert.Equal(t, models.HealthStatusUp, resp.Status)
+	assert.Len(t, resp.Checks, 1)
+}
+
+// TestStatus_Ok checks that Status reports the API ok, along with its uptime and version, when every check succeeds
+func TestStatus_Ok(t *testing.T) {
+	// Arrange
+	service := NewHealthService(time.Second, time.Minute, "test-version")
+	service.Register("database", true, checker(nil))
+	service.(*healthService).started = time.Now().Add(-time.Hour)
+
+	// Act
+	resp := service.Status(context.Background())
+
+	// Assert
+	assert.Equal(t, models.StatusResp{Status: models.ServiceStatusOK, UptimeSeconds: 3600, Version: "test-version"}, resp)
+}
+
+// TestStatus_Degraded checks that Status reports the API degraded when a check fails, whether critical or not
+func TestStatus_Degraded(t *testing.T) {
+	// Arrange
+	service := NewHealthService(time.Second, time.Minute, "test-version")
+	service.Register("database", true, checker(errors.New("test-error")))
+
+	// Act
+	resp := service.Status(context.Background())
+
+	// Assert
+	assert.Equal(t, models.ServiceStatusDegraded, resp.Status)
+}
+
+// TestStatus_LastCheck checks that Status reuses the last readiness check while not older than the max age
+func TestStatus_LastCheck(t *testing.T) {
+	// Arrange
+	service := NewHealthService(time.Second, time.Minute, "test-version")
+	calls := 0
+	service.Register("database", true, ports.HealthCheckerFunc(func(ctx context.Context) error {
+		calls++
+		return nil
+	}))
+	service.Check(context.Background())
+
+	// Act
+	resp := service.Status(context.Background())
+
+	// Assert
+	assert.Equal(t, models.ServiceStatusOK, resp.Status)
+	assert.Equal(t, 1, calls)
+}
+
+// TestStatus_Expired checks that Status runs the checks again when the last ones are older than the max age
+func TestStatus_Expired(t *testing.T) {
+	// Arrange
+	service := NewHealthService(time.Second, 0, "test-version")
+	calls := 0
+	service.Register("database", true, ports.HealthCheckerFunc(func(ctx context.Context) error {
+		calls++
+		return errors.New("test-error")
+	}))
+	service.Check(context.Background())
+	service.(*healthService).lastChecked = time.Now().Add(-time.Second)
+
+	// Act
+	resp := service.Status(context.Background())
+
+	// Assert
+	assert.Equal(t, models.ServiceStatusDegraded, resp.Status)
+	assert.Equal(t, 2, calls)
+}
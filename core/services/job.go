@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// jobService adapter of a job service
+type jobService struct {
+	config     config.Config
+	repository ports.JobRepository
+}
+
+// NewJobService creates a new job service
+func NewJobService(cfg config.Config, repo ports.JobRepository) ports.JobService {
+	return &jobService{
+		config:     cfg,
+		repository: repo,
+	}
+}
+
+// GetByID job
+func (s *jobService) GetByID(ctx context.Context, ID string) (resp models.JobResp, err error) {
+	job, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("job ID %s not found", ID))
+		}
+		return
+	}
+
+	resp = models.JobResp(job)
+	return
+}
+
+// Get jobs, newest first
+func (s *jobService) Get(ctx context.Context, req models.GetJobsReq) (resp []models.JobResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	filter := ports.JobFilter{
+		Status: entities.JobStatus(req.Status),
+		Type:   req.Type,
+	}
+	jobs, err := s.repository.Get(ctx, filter, &req.Skip, &req.Take)
+	if err != nil {
+		return
+	}
+
+	resp = make([]models.JobResp, 0, len(jobs))
+	for _, j := range jobs {
+		resp = append(resp, models.JobResp(j))
+	}
+	return
+}
+
+// Enqueue queues a job to be run as soon as a worker is free
+func (s *jobService) Enqueue(ctx context.Context, jobType string, metadata map[string]string) (resp models.JobResp, err error) {
+	job := newQueuedJob(s.config, jobType, metadata)
+	job.ID, err = s.repository.Create(ctx, job)
+	if err != nil {
+		return
+	}
+
+	resp = models.JobResp(job)
+	return
+}
+
+// Retry queues again a job left in the dead letter, or a failed one waiting for its next attempt, with all its attempts
+func (s *jobService) Retry(ctx context.Context, ID string) (resp models.JobResp, err error) {
+	job, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("job ID %s not found", ID))
+		}
+		return
+	}
+	if job.Status != entities.JobStatusDead && job.Status != entities.JobStatusFailed {
+		err = wrappers.NewValidationErr(fmt.Errorf("job ID %s is %s, only dead or failed jobs can be retried", ID, job.Status))
+		return
+	}
+
+	now := time.Now().UTC()
+	job.Status = entities.JobStatusQueued
+	job.Attempts = 0
+	job.Error = ""
+	job.RunAt = now
+	job.UpdatedAt = now
+	job.FinishedAt = nil
+	if err = s.repository.Update(ctx, job); err != nil {
+		return
+	}
+
+	resp = models.JobResp(job)
+	return
+}
+
+// newQueuedJob returns a job queued to run now, with the attempts of the config
+func newQueuedJob(cfg config.Config, jobType string, metadata map[string]string) entities.Job {
+	now := time.Now().UTC()
+	return entities.Job{
+		Type:        jobType,
+		Status:      entities.JobStatusQueued,
+		Metadata:    metadata,
+		MaxAttempts: cfg.Queue.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewJobService_Ok checks that NewJobService creates a new jobService struct
+func TestNewJobService_Ok(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+
+	// Act
+	service := NewJobService(config.Config{}, jobRepositoryMock)
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestGetJobByID_Ok checks that GetByID returns the expected job when a valid ID is received
+func TestGetJobByID_Ok(t *testing.T) {
+	// Arrange
+	expectedJob := entities.Job{ID: "job-id", Type: entities.JobTypeBackup, Status: entities.JobStatusRunning, Processed: 10}
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.GetByID), context.Background(), expectedJob.ID).Return(expectedJob, nil).Once()
+
+	service := &jobService{
+		repository: jobRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByID(context.Background(), expectedJob.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.JobResp(expectedJob), resp)
+}
+
+// TestGetJobByID_NotFound checks that GetByID returns a non existent error when the job does not exist
+func TestGetJobByID_NotFound(t *testing.T) {
+	// Arrange
+	ID := "job-id"
+	expectedError := fmt.Sprintf("job ID %s not found", ID)
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.GetByID), context.Background(), ID).Return(entities.Job{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := &jobService{
+		repository: jobRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetByID(context.Background(), ID)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetJobs_Ok checks that Get returns the jobs matching the filters of the request
+func TestGetJobs_Ok(t *testing.T) {
+	// Arrange
+	req := models.GetJobsReq{Status: string(entities.JobStatusDead), Take: 10}
+	expectedJobs := []entities.Job{{ID: "job-id", Type: entities.JobTypeNotification, Status: entities.JobStatusDead}}
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Get), context.Background(), ports.JobFilter{Status: entities.JobStatusDead}, &req.Skip, &req.Take).Return(expectedJobs, nil).Once()
+
+	service := &jobService{
+		repository: jobRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Get(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.JobResp{models.JobResp(expectedJobs[0])}, resp)
+}
+
+// TestGetJobs_InvalidRequest checks that Get returns a validation error when the request is not valid
+func TestGetJobs_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &jobService{}
+
+	// Act
+	_, err := service.Get(context.Background(), models.GetJobsReq{Status: "invalid", Take: 10})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestEnqueue_Ok checks that Enqueue creates a job queued to run now with the attempts of the config
+func TestEnqueue_Ok(t *testing.T) {
+	// Arrange
+	metadata := map[string]string{"channel": "slack"}
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Create), context.Background(), mock.MatchedBy(func(job entities.Job) bool {
+		return job.Type == entities.JobTypeNotification && job.Status == entities.JobStatusQueued && job.MaxAttempts == 5 && !job.RunAt.IsZero()
+	})).Return("job-id", nil).Once()
+
+	cfg := config.Config{}
+	cfg.Queue.MaxAttempts = 5
+	service := &jobService{
+		config:     cfg,
+		repository: jobRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Enqueue(context.Background(), entities.JobTypeNotification, metadata)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "job-id", resp.ID)
+	assert.Equal(t, metadata, resp.Metadata)
+}
+
+// TestRetry_Ok checks that Retry queues again a dead job with all its attempts
+func TestRetry_Ok(t *testing.T) {
+	// Arrange
+	job := entities.Job{ID: "job-id", Status: entities.JobStatusDead, Attempts: 5, MaxAttempts: 5, Error: "job-error"}
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.GetByID), context.Background(), job.ID).Return(job, nil).Once()
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Update), context.Background(), mock.MatchedBy(func(job entities.Job) bool {
+		return job.Status == entities.JobStatusQueued && job.Attempts == 0 && job.Error == "" && job.FinishedAt == nil
+	})).Return(nil).Once()
+
+	service := &jobService{
+		repository: jobRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Retry(context.Background(), job.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, entities.JobStatusQueued, resp.Status)
+}
+
+// TestRetry_NotRetriable checks that Retry returns a validation error when the job is neither dead nor failed
+func TestRetry_NotRetriable(t *testing.T) {
+	// Arrange
+	job := entities.Job{ID: "job-id", Status: entities.JobStatusSucceeded}
+	expectedError := "job ID job-id is succeeded, only dead or failed jobs can be retried"
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.GetByID), context.Background(), job.ID).Return(job, nil).Once()
+
+	service := &jobService{
+		repository: jobRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Retry(context.Background(), job.ID)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// keyIDHeader is the header of the tokens naming the key they were signed with, the tokens without it being signed with JWTSecret
+const keyIDHeader = "kid"
+
+// keyService adapter of a key service, signing the tokens with the newest key of the store, or with JWTSecret until a key is rotated.
+// JWTSecret is then retired like a rotated key, verifying the tokens it signed for KeyRotation.Overlap.
+type keyService struct {
+	config      config.Config
+	logger      zerolog.Logger
+	store       ports.SigningKeyStore
+	mu          sync.RWMutex
+	keys        map[string][]byte
+	current     string
+	refreshedAt time.Time
+	// secretExpiresAt the end of the overlap of the oldest key of the store, zero while no key is rotated
+	secretExpiresAt time.Time
+}
+
+// NewKeyService creates a new key service, without keys until refreshed
+func NewKeyService(cfg config.Config, logger zerolog.Logger, store ports.SigningKeyStore) ports.KeyService {
+	return &keyService{
+		config: cfg,
+		logger: logger,
+		store:  store,
+		keys:   map[string][]byte{},
+	}
+}
+
+// Sign returns the token of the claims signed with the newest key, naming it in its header
+func (s *keyService) Sign(claims jwt.MapClaims) (string, error) {
+	s.mu.RLock()
+	kid, key := s.current, s.keys[s.current]
+	s.mu.RUnlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if kid == "" {
+		return token.SignedString([]byte(s.config.JWTSecret))
+	}
+	token.Header[keyIDHeader] = kid
+	return token.SignedString(key)
+}
+
+// Parse verifies the token with the key named in its header, refreshing the keys when it is not known,
+// as it can have been rotated by another replica, at most every KeyRotation.MinRefreshInterval
+func (s *keyService) Parse(ctx context.Context, token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, ok := t.Header[keyIDHeader].(string)
+		if !ok {
+			return s.secret()
+		}
+		if key, ok := s.key(kid); ok {
+			return key, nil
+		}
+		if s.refreshDue() {
+			if err := s.Refresh(ctx); err != nil {
+				s.logger.Warn().Err(err).Msg("signing keys cannot be refreshed")
+			}
+		}
+		if key, ok := s.key(kid); ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("signing key %s not found", kid)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Refresh reads the keys not expired from the store, the newest one signing the tokens from then on
+func (s *keyService) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	s.refreshedAt = time.Now()
+	s.mu.Unlock()
+
+	stored, err := s.store.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string][]byte, len(stored))
+	for _, k := range stored {
+		secret, err := base64.StdEncoding.DecodeString(k.Secret)
+		if err != nil {
+			return fmt.Errorf("signing key %s not valid: %w", k.ID, err)
+		}
+		keys[k.ID] = secret
+	}
+	current := ""
+	var secretExpiresAt time.Time
+	if len(stored) > 0 {
+		current = stored[0].ID
+		secretExpiresAt = stored[len(stored)-1].CreatedAt.Add(s.config.KeyRotation.Overlap.Duration)
+	}
+
+	s.mu.Lock()
+	changed := current != s.current
+	s.keys, s.current, s.secretExpiresAt = keys, current, secretExpiresAt
+	s.mu.Unlock()
+	if changed {
+		s.logger.Info().Str("kid", current).Int("keys", len(keys)).Msg("signing key changed")
+	}
+	return nil
+}
+
+// Rotate creates a new random key signing the tokens, keeping the previous ones verifying them for KeyRotation.Overlap
+func (s *keyService) Rotate(ctx context.Context) error {
+	id := make([]byte, 8)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+
+	key := entities.SigningKey{
+		ID:        hex.EncodeToString(id),
+		Secret:    base64.StdEncoding.EncodeToString(secret),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.store.Rotate(ctx, key, s.config.KeyRotation.Overlap.Duration); err != nil {
+		return err
+	}
+	return s.Refresh(ctx)
+}
+
+// secret returns JWTSecret to verify the tokens not naming a key, until the overlap of the oldest key of the store is over,
+// as no token has been signed with it since that key was rotated
+func (s *keyService) secret() (interface{}, error) {
+	s.mu.RLock()
+	expiresAt := s.secretExpiresAt
+	s.mu.RUnlock()
+	if !expiresAt.IsZero() && time.Now().After(expiresAt) {
+		return nil, errors.New("tokens signed with JWTSecret are no longer accepted")
+	}
+	return []byte(s.config.JWTSecret), nil
+}
+
+// key returns the key with the given ID, if known
+func (s *keyService) key(kid string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// refreshDue reports whether the keys can be refreshed again to look up a key not known
+func (s *keyService) refreshDue() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return time.Since(s.refreshedAt) >= s.config.KeyRotation.MinRefreshInterval.Duration
+}
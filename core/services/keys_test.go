@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// keysConfig returns the config of the key service tests
+func keysConfig() config.Config {
+	var cfg config.Config
+	cfg.JWTSecret = "test-secret"
+	cfg.KeyRotation.Overlap = utils.Duration{Duration: time.Hour}
+	cfg.KeyRotation.MinRefreshInterval = utils.Duration{Duration: time.Minute}
+	return cfg
+}
+
+// signedWith returns a token of the claims signed with the given key
+func signedWith(t *testing.T, kid string, key []byte, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// TestNewKeyService_Ok checks that NewKeyService creates a new keyService struct
+func TestNewKeyService_Ok(t *testing.T) {
+	// Act
+	service := NewKeyService(keysConfig(), zerolog.Nop(), mocks.NewSigningKeyStore(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestSign_JWTSecret checks that Sign signs the tokens with JWTSecret, without naming the key, until a key is rotated
+func TestSign_JWTSecret(t *testing.T) {
+	// Arrange
+	service := NewKeyService(keysConfig(), zerolog.Nop(), mocks.NewSigningKeyStore(t))
+
+	// Act
+	token, err := service.Sign(jwt.MapClaims{"user_id": "test-user"})
+
+	// Assert
+	assert.Nil(t, err)
+	parsed, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) { return []byte("test-secret"), nil })
+	assert.Nil(t, err)
+	assert.NotContains(t, parsed.Header, "kid")
+}
+
+// TestSign_NewestKey checks that Sign signs the tokens with the newest key once refreshed, which Parse verifies
+func TestSign_NewestKey(t *testing.T) {
+	// Arrange
+	storeMock := mocks.NewSigningKeyStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.SigningKeyStore.Get), mock.Anything).Return([]entities.SigningKey{
+		{ID: "new", Secret: base64.StdEncoding.EncodeToString([]byte("new-secret"))},
+		{ID: "old", Secret: base64.StdEncoding.EncodeToString([]byte("old-secret"))},
+	}, nil).Once()
+
+	service := NewKeyService(keysConfig(), zerolog.Nop(), storeMock)
+	if err := service.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	token, err := service.Sign(jwt.MapClaims{"user_id": "test-user"})
+
+	// Assert
+	assert.Nil(t, err)
+	parsed, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) { return []byte("new-secret"), nil })
+	assert.Nil(t, err)
+	assert.Equal(t, "new", parsed.Header["kid"])
+	claims, err := service.Parse(context.Background(), token)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-user", claims["user_id"])
+}
+
+// TestParse_PreviousKey checks that Parse verifies the tokens signed with a previous key not expired,
+// and with JWTSecret when not naming a key within the overlap of the oldest key
+func TestParse_PreviousKey(t *testing.T) {
+	// Arrange
+	storeMock := mocks.NewSigningKeyStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.SigningKeyStore.Get), mock.Anything).Return([]entities.SigningKey{
+		{ID: "new", Secret: base64.StdEncoding.EncodeToString([]byte("new-secret")), CreatedAt: time.Now()},
+		{ID: "old", Secret: base64.StdEncoding.EncodeToString([]byte("old-secret")), CreatedAt: time.Now().Add(-time.Minute)},
+	}, nil).Once()
+
+	service := NewKeyService(keysConfig(), zerolog.Nop(), storeMock)
+	if err := service.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	static, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"admin": true}).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	oldClaims, oldErr := service.Parse(context.Background(), signedWith(t, "old", []byte("old-secret"), jwt.MapClaims{"user_id": "test-user"}))
+	staticClaims, staticErr := service.Parse(context.Background(), static)
+
+	// Assert
+	assert.Nil(t, oldErr)
+	assert.Equal(t, "test-user", oldClaims["user_id"])
+	assert.Nil(t, staticErr)
+	assert.Equal(t, true, staticClaims["admin"])
+}
+
+// TestParse_JWTSecretRetired checks that Parse returns an error for the tokens not naming a key once the overlap of the oldest key is over
+func TestParse_JWTSecretRetired(t *testing.T) {
+	// Arrange
+	storeMock := mocks.NewSigningKeyStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.SigningKeyStore.Get), mock.Anything).Return([]entities.SigningKey{
+		{ID: "new", Secret: base64.StdEncoding.EncodeToString([]byte("new-secret")), CreatedAt: time.Now().Add(-time.Hour - time.Minute)},
+	}, nil).Once()
+
+	service := NewKeyService(keysConfig(), zerolog.Nop(), storeMock)
+	if err := service.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	static, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"admin": true}).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err = service.Parse(context.Background(), static)
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+// TestParse_RotatedByAnotherReplica checks that Parse refreshes the keys to verify a token signed with a key not known yet
+func TestParse_RotatedByAnotherReplica(t *testing.T) {
+	// Arrange
+	storeMock := mocks.NewSigningKeyStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.SigningKeyStore.Get), mock.Anything).Return([]entities.SigningKey{
+		{ID: "new", Secret: base64.StdEncoding.EncodeToString([]byte("new-secret"))},
+	}, nil).Once()
+
+	service := NewKeyService(keysConfig(), zerolog.Nop(), storeMock)
+
+	// Act
+	claims, err := service.Parse(context.Background(), signedWith(t, "new", []byte("new-secret"), jwt.MapClaims{"user_id": "test-user"}))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-user", claims["user_id"])
+}
+
+// TestParse_KeyNotFound checks that Parse returns an error when the key of the token is not found, refreshing the keys at most once per MinRefreshInterval
+func TestParse_KeyNotFound(t *testing.T) {
+	// Arrange
+	storeMock := mocks.NewSigningKeyStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.SigningKeyStore.Get), mock.Anything).Return([]entities.SigningKey{}, nil).Once()
+
+	service := NewKeyService(keysConfig(), zerolog.Nop(), storeMock)
+	token := signedWith(t, "unknown", []byte("unknown-secret"), jwt.MapClaims{"user_id": "test-user"})
+
+	// Act
+	_, first := service.Parse(context.Background(), token)
+	_, second := service.Parse(context.Background(), token)
+
+	// Assert
+	assert.ErrorContains(t, first, "signing key unknown not found")
+	assert.ErrorContains(t, second, "signing key unknown not found")
+}
+
+// TestParse_WrongSecret checks that Parse returns an error when the token is not signed with the key it names
+func TestParse_WrongSecret(t *testing.T) {
+	// Arrange
+	storeMock := mocks.NewSigningKeyStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.SigningKeyStore.Get), mock.Anything).Return([]entities.SigningKey{
+		{ID: "new", Secret: base64.StdEncoding.EncodeToString([]byte("new-secret"))},
+	}, nil).Once()
+
+	service := NewKeyService(keysConfig(), zerolog.Nop(), storeMock)
+	if err := service.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err := service.Parse(context.Background(), signedWith(t, "new", []byte("wrong-secret"), jwt.MapClaims{"user_id": "test-user"}))
+
+	// Assert
+	assert.ErrorContains(t, err, "signature is invalid")
+}
+
+// TestRotate_Ok checks that Rotate stores a new key with the overlap of the config and signs the tokens with it
+func TestRotate_Ok(t *testing.T) {
+	// Arrange
+	var rotated entities.SigningKey
+	storeMock := mocks.NewSigningKeyStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.SigningKeyStore.Rotate), mock.Anything, mock.AnythingOfType("entities.SigningKey"), time.Hour).Run(func(args mock.Arguments) {
+		rotated = args.Get(1).(entities.SigningKey)
+	}).Return(nil).Once()
+	storeMock.On(testutils.FunctionName(t, ports.SigningKeyStore.Get), mock.Anything).Return(func(ctx context.Context) []entities.SigningKey {
+		return []entities.SigningKey{rotated}
+	}, nil).Once()
+
+	service := NewKeyService(keysConfig(), zerolog.Nop(), storeMock)
+
+	// Act
+	err := service.Rotate(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, rotated.ID, 16)
+	token, err := service.Sign(jwt.MapClaims{"user_id": "test-user"})
+	assert.Nil(t, err)
+	parsed, _ := jwt.Parse(token, nil)
+	assert.Equal(t, rotated.ID, parsed.Header["kid"])
+}
+
+// TestRotate_Error checks that Rotate returns an error when the key cannot be stored
+func TestRotate_Error(t *testing.T) {
+	// Arrange
+	storeMock := mocks.NewSigningKeyStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.SigningKeyStore.Rotate), mock.Anything, mock.AnythingOfType("entities.SigningKey"), time.Hour).Return(errors.New("store error")).Once()
+
+	service := NewKeyService(keysConfig(), zerolog.Nop(), storeMock)
+
+	// Act
+	err := service.Rotate(context.Background())
+
+	// Assert
+	assert.EqualError(t, err, "store error")
+}
+
+// FuzzParse checks that Parse verifies any token without panicking, only accepting the ones signed with the JWT secret or a stored key
+func FuzzParse(f *testing.F) {
+	valid, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "test-user", "admin": true}).SignedString([]byte("test-secret"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	withKeyID := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "test-user"})
+	withKeyID.Header[keyIDHeader] = "test-key"
+	named, err := withKeyID.SignedString([]byte("test-key-secret"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(valid)
+	f.Add(named)
+	f.Add("")
+	f.Add("a.b.c")
+	f.Add("eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJhZG1pbiI6dHJ1ZX0.")
+	f.Add("eyJhbGciOiJIUzI1NiIsImtpZCI6MX0.eyJleHAiOiJub3ctYS1udW1iZXIifQ.c2ln")
+
+	f.Fuzz(func(t *testing.T, token string) {
+		storeMock := mocks.NewSigningKeyStore(t)
+		storeMock.On(testutils.FunctionName(t, ports.SigningKeyStore.Get), mock.Anything).Return([]entities.SigningKey{{ID: "test-key", Secret: base64.StdEncoding.EncodeToString([]byte("test-key-secret")), CreatedAt: time.Now()}}, nil).Maybe()
+		service := NewKeyService(keysConfig(), zerolog.Nop(), storeMock)
+
+		claims, err := service.Parse(context.Background(), token)
+
+		if err == nil {
+			assert.NotNil(t, claims)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// lockoutKeyPrefix prefixes the keys of the failed logins of every email in the limit store
+const lockoutKeyPrefix = "login:"
+
+// errLockedOut is returned on the logins of a locked out email, whatever the password, without telling whether the email exists
+var errLockedOut = errors.New("too many failed logins, try again later")
+
+// lockoutUserService decorator of an user service that locks out the emails failing to log in
+type lockoutUserService struct {
+	ports.UserService
+	limits      ports.LimitStore
+	policies    ports.SecurityPolicyRepository
+	notifier    ports.AdminNotifier
+	logger      zerolog.Logger
+	maxFailures int64
+	duration    time.Duration
+}
+
+// NewLockoutUserService wraps a user service refusing the logins of an email for duration after max failures, or the ones of its security policy
+func NewLockoutUserService(service ports.UserService, limits ports.LimitStore, policies ports.SecurityPolicyRepository, notifier ports.AdminNotifier, logger zerolog.Logger,
+	maxFailures int64, duration time.Duration) ports.UserService {
+	return &lockoutUserService{
+		UserService: service,
+		limits:      limits,
+		policies:    policies,
+		notifier:    notifier,
+		logger:      logger,
+		maxFailures: maxFailures,
+		duration:    duration,
+	}
+}
+
+func (s *lockoutUserService) Login(ctx context.Context, credentials models.LoginUserReq) (resp models.LoginUserResp, err error) {
+	if credentials.Validate() != nil {
+		return s.UserService.Login(ctx, credentials)
+	}
+
+	maxFailures, duration, err := s.thresholds(ctx, credentials.Email)
+	if err != nil {
+		return
+	}
+	if maxFailures < 1 {
+		return s.UserService.Login(ctx, credentials)
+	}
+
+	key := lockoutKeyPrefix + normalizeEmail(credentials.Email)
+	failures, err := s.limits.Get(ctx, key)
+	if err != nil {
+		return
+	}
+	if failures.Count >= maxFailures {
+		loginFailed(loginFailureLockedOut)
+		err = wrappers.NewUnauthorizedErr(errLockedOut)
+		return
+	}
+
+	resp, err = s.UserService.Login(ctx, credentials)
+	switch {
+	case errors.Is(err, wrappers.NonExistentErr) || errors.Is(err, wrappers.ValidationErr):
+		failures, hitErr := s.limits.Hit(ctx, key, duration)
+		if hitErr != nil {
+			s.logger.Error().Err(hitErr).Msg("failed login cannot be counted")
+			return
+		}
+		if failures.Count == maxFailures {
+			lockedOut()
+			s.logger.Warn().Str("email", normalizeEmail(credentials.Email)).Time("until", failures.ExpiresAt).Msg("logins locked out")
+			s.notifyLockout(ctx, normalizeEmail(credentials.Email), maxFailures, failures.ExpiresAt)
+		}
+	case err == nil && failures.Count > 0:
+		if resetErr := s.limits.Reset(ctx, key); resetErr != nil {
+			s.logger.Error().Err(resetErr).Msg("failed logins cannot be reset")
+		}
+	}
+	return
+}
+
+// thresholds returns the failures locking out the logins of the email and for how long, the ones of the security policy of its tenant when it sets them
+func (s *lockoutUserService) thresholds(ctx context.Context, email string) (int64, time.Duration, error) {
+	if s.policies == nil {
+		return s.maxFailures, s.duration, nil
+	}
+	policy, err := tenantPolicy(ctx, s.policies, email)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if policy.LockoutMaxFailures > 0 {
+		return policy.LockoutMaxFailures, policy.LockoutDuration, nil
+	}
+	return s.maxFailures, s.duration, nil
+}
+
+// notifyLockout notifies the admins of the lockout of the email after the failures, a failure being logged as the lockout applies anyway
+func (s *lockoutUserService) notifyLockout(ctx context.Context, email string, failures int64, until time.Time) {
+	if s.notifier == nil {
+		return
+	}
+	err := s.notifier.NotifyAdmins(ctx, ports.AdminNotice{
+		Event: ports.AdminEventLockout,
+		Text:  fmt.Sprintf("logins of %s locked out after %d failures", email, failures),
+		Fields: map[string]string{
+			"email": email,
+			"until": until.UTC().Format(time.RFC3339),
+		},
+		At: time.Now().UTC(),
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Msg("lockout cannot be notified")
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestLockoutLogin_LockedOut checks that Login refuses the logins of an email with too many failures without checking its password
+func TestLockoutLogin_LockedOut(t *testing.T) {
+	// Arrange
+	credentials := models.LoginUserReq{Email: "Test@Example.com", Password: "test"}
+	lockedOutFailures := counter(loginFailures, loginFailureLockedOut)
+
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Get), mock.Anything, "login:test@example.com").Return(entities.Limit{Count: 5}, nil).Once()
+
+	service := NewLockoutUserService(mocks.NewUserService(t), limitStoreMock, nil, nil, zerolog.Nop(), 5, time.Minute)
+
+	// Act
+	_, err := service.Login(context.Background(), credentials)
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.UnauthorizedErr)
+	assert.ErrorContains(t, err, errLockedOut.Error())
+	assert.Equal(t, lockedOutFailures+1, counter(loginFailures, loginFailureLockedOut))
+}
+
+// TestLockoutLogin_Failure checks that Login counts the failed logins, counting the lockout and notifying the admins once the max failures are reached
+func TestLockoutLogin_Failure(t *testing.T) {
+	// Arrange
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	expectedError := wrappers.NewValidationErr(fmt.Errorf("incorrect password"))
+	lockoutsBefore := lockouts.Value()
+
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Get), mock.Anything, "login:test@example.com").Return(entities.Limit{Count: 4}, nil).Once()
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "login:test@example.com", time.Minute).Return(entities.Limit{Count: 5}, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, credentials).Return(models.LoginUserResp{}, expectedError).Once()
+	adminNotifierMock := mocks.NewAdminNotifier(t)
+	adminNotifierMock.On(testutils.FunctionName(t, ports.AdminNotifier.NotifyAdmins), mock.Anything, mock.MatchedBy(func(notice ports.AdminNotice) bool {
+		return notice.Event == ports.AdminEventLockout && notice.Fields["email"] == "test@example.com"
+	})).Return(nil).Once()
+
+	service := NewLockoutUserService(userServiceMock, limitStoreMock, nil, adminNotifierMock, zerolog.Nop(), 5, time.Minute)
+
+	// Act
+	_, err := service.Login(context.Background(), credentials)
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+	assert.Equal(t, lockoutsBefore+1, lockouts.Value())
+}
+
+// TestLockoutLogin_Succeeded checks that Login resets the failed logins of the email once it succeeds
+func TestLockoutLogin_Succeeded(t *testing.T) {
+	// Arrange
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	expectedResp := models.LoginUserResp{Token: "test-token"}
+
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Get), mock.Anything, "login:test@example.com").Return(entities.Limit{Count: 2}, nil).Once()
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Reset), mock.Anything, "login:test@example.com").Return(nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, credentials).Return(expectedResp, nil).Once()
+
+	service := NewLockoutUserService(userServiceMock, limitStoreMock, nil, nil, zerolog.Nop(), 5, time.Minute)
+
+	// Act
+	resp, err := service.Login(context.Background(), credentials)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResp, resp)
+}
+
+// TestLockoutLogin_TenantPolicy checks that Login locks out the logins of an email with the thresholds of the security policy of its tenant
+func TestLockoutLogin_TenantPolicy(t *testing.T) {
+	// Arrange
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	expectedError := wrappers.NewValidationErr(fmt.Errorf("incorrect password"))
+	lockoutsBefore := lockouts.Value()
+
+	securityPolicyRepositoryMock := mocks.NewSecurityPolicyRepository(t)
+	securityPolicyRepositoryMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), mock.Anything, "example.com").Return(entities.SecurityPolicy{Tenant: "example.com", LockoutMaxFailures: 3, LockoutDuration: time.Hour}, nil).Once()
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Get), mock.Anything, "login:test@example.com").Return(entities.Limit{Count: 2}, nil).Once()
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "login:test@example.com", time.Hour).Return(entities.Limit{Count: 3}, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, credentials).Return(models.LoginUserResp{}, expectedError).Once()
+
+	service := NewLockoutUserService(userServiceMock, limitStoreMock, securityPolicyRepositoryMock, nil, zerolog.Nop(), 5, time.Minute)
+
+	// Act
+	_, err := service.Login(context.Background(), credentials)
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+	assert.Equal(t, lockoutsBefore+1, lockouts.Value())
+}
+
+// TestLockoutLogin_Disabled checks that Login does not count the failures of an email when neither the config nor the policy of its tenant sets a max
+func TestLockoutLogin_Disabled(t *testing.T) {
+	// Arrange
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	expectedError := wrappers.NewValidationErr(fmt.Errorf("incorrect password"))
+
+	securityPolicyRepositoryMock := mocks.NewSecurityPolicyRepository(t)
+	securityPolicyRepositoryMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), mock.Anything, "example.com").Return(entities.SecurityPolicy{}, wrappers.NewNonExistentErr(fmt.Errorf("not found"))).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, credentials).Return(models.LoginUserResp{}, expectedError).Once()
+
+	service := NewLockoutUserService(userServiceMock, mocks.NewLimitStore(t), securityPolicyRepositoryMock, nil, zerolog.Nop(), 0, 0)
+
+	// Act
+	_, err := service.Login(context.Background(), credentials)
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
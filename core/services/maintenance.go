@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// maintenance tasks, in the order they are listed
+const (
+	taskRebuildIndexes      = "rebuild_indexes"
+	taskRecomputeStats      = "recompute_stats"
+	taskFlushCaches         = "flush_caches"
+	taskReplayNotifications = "replay_notifications"
+	taskPurgeExpired        = "purge_expired"
+)
+
+// replayBatchSize is the number of dead notifications queued again at once
+const replayBatchSize = 100
+
+// maintenanceTasks are the maintenance tasks with their descriptions, in the order they are listed
+var maintenanceTasks = []models.MaintenanceTaskResp{
+	{Name: taskRebuildIndexes, Description: "Rebuilds the indexes of every collection or table of the database, counting the rebuilt ones"},
+	{Name: taskRecomputeStats, Description: "Recomputes the user stats exported by the metrics of the replica running the task"},
+	{Name: taskFlushCaches, Description: "Reads again the keys signing the tokens cached by the replica running the task"},
+	{Name: taskReplayNotifications, Description: "Queues again the alert notifications left in the dead letter, counting the replayed ones"},
+	{Name: taskPurgeExpired, Description: "Deletes the expired signing keys and the ended windows of the rate limits and the login lockouts, counting the deleted ones"},
+}
+
+// maintenanceService adapter of a maintenance service
+type maintenanceService struct {
+	config      config.Config
+	logger      zerolog.Logger
+	store       ports.MaintenanceStore
+	jobs        ports.JobRepository
+	jobService  ports.JobService
+	users       ports.UserService
+	keys        ports.KeyService
+	signingKeys ports.SigningKeyStore
+	limits      ports.LimitStore
+	audit       ports.AuditRepository
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(cfg config.Config, logger zerolog.Logger, store ports.MaintenanceStore, jobs ports.JobRepository, jobService ports.JobService, users ports.UserService, keys ports.KeyService, signingKeys ports.SigningKeyStore, limits ports.LimitStore, audit ports.AuditRepository) ports.MaintenanceService {
+	return &maintenanceService{
+		config:      cfg,
+		logger:      logger,
+		store:       store,
+		jobs:        jobs,
+		jobService:  jobService,
+		users:       users,
+		keys:        keys,
+		signingKeys: signingKeys,
+		limits:      limits,
+		audit:       audit,
+	}
+}
+
+// Tasks returns the maintenance tasks that can be started
+func (s *maintenanceService) Tasks() []models.MaintenanceTaskResp {
+	return maintenanceTasks
+}
+
+// Start queues the maintenance task
+func (s *maintenanceService) Start(ctx context.Context, task string) (resp models.JobResp, err error) {
+	if !knownTask(task) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("maintenance task %s not found", task))
+		return
+	}
+
+	job := newQueuedJob(s.config, entities.JobTypeMaintenance, map[string]string{"task": task})
+	job.ID, err = s.jobs.Create(ctx, job)
+	if err != nil {
+		return
+	}
+	record(ctx, s.logger, s.audit, entities.AuditMaintenanceStarted, "", job.Metadata)
+
+	resp = models.JobResp(job)
+	return
+}
+
+// Process runs a maintenance job claimed by a worker, counting its progress from the start on every attempt
+func (s *maintenanceService) Process(ctx context.Context, job *entities.Job) error {
+	job.Processed = 0
+	switch job.Metadata["task"] {
+	case taskRebuildIndexes:
+		return s.rebuildIndexes(ctx, job)
+	case taskRecomputeStats:
+		if _, err := s.users.ComputeStats(ctx); err != nil {
+			return err
+		}
+		job.Processed = 1
+		return nil
+	case taskFlushCaches:
+		if err := s.keys.Refresh(ctx); err != nil {
+			return err
+		}
+		job.Processed = 1
+		return nil
+	case taskReplayNotifications:
+		return s.replayNotifications(ctx, job)
+	case taskPurgeExpired:
+		return s.purgeExpired(ctx, job)
+	default:
+		return fmt.Errorf("maintenance task %s not known", job.Metadata["task"])
+	}
+}
+
+// rebuildIndexes rebuilds the indexes of the collections one after the other, reporting the progress after each one
+func (s *maintenanceService) rebuildIndexes(ctx context.Context, job *entities.Job) error {
+	collections, err := s.store.Collections(ctx)
+	if err != nil {
+		return err
+	}
+	for _, collection := range collections {
+		start := time.Now()
+		if err := s.store.RebuildIndexes(ctx, collection); err != nil {
+			return fmt.Errorf("cannot rebuild the indexes of %s: %w", collection, err)
+		}
+		s.logger.Info().Str("collection", collection).Dur("elapsed", time.Since(start)).Msg("indexes rebuilt")
+		s.progress(ctx, job, 1)
+	}
+	return nil
+}
+
+// replayNotifications queues again the dead notifications, in batches, reporting the progress after each one.
+// The replayed notifications leave the dead letter, so every batch is read from its start.
+func (s *maintenanceService) replayNotifications(ctx context.Context, job *entities.Job) error {
+	for {
+		dead, err := s.jobService.Get(ctx, models.GetJobsReq{
+			Status: string(entities.JobStatusDead),
+			Type:   entities.JobTypeNotification,
+			Take:   replayBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+		for _, notification := range dead {
+			if _, err := s.jobService.Retry(ctx, notification.ID); err != nil {
+				return fmt.Errorf("cannot replay the notification %s: %w", notification.ID, err)
+			}
+		}
+		s.progress(ctx, job, int64(len(dead)))
+		if len(dead) < replayBatchSize {
+			return nil
+		}
+	}
+}
+
+// purgeExpired deletes the expired signing keys and the ended limits, reporting the progress after each one
+func (s *maintenanceService) purgeExpired(ctx context.Context, job *entities.Job) error {
+	keys, err := s.signingKeys.Purge(ctx)
+	if err != nil {
+		return err
+	}
+	s.progress(ctx, job, keys)
+
+	limits, err := s.limits.Purge(ctx)
+	if err != nil {
+		return err
+	}
+	s.progress(ctx, job, limits)
+	s.logger.Info().Int64("signing_keys", keys).Int64("limits", limits).Msg("expired records purged")
+	return nil
+}
+
+// progress counts the processed items of the job and reports its progress
+func (s *maintenanceService) progress(ctx context.Context, job *entities.Job, processed int64) {
+	job.Processed += processed
+	job.UpdatedAt = time.Now().UTC()
+	if err := s.jobs.Update(ctx, *job); err != nil {
+		s.logger.Error().Err(err).Str("type", job.Type).Str("job_id", job.ID).Msg("job cannot be updated")
+	}
+}
+
+// knownTask reports whether the task is one of the maintenance tasks
+func knownTask(task string) bool {
+	for _, t := range maintenanceTasks {
+		if t.Name == task {
+			return true
+		}
+	}
+	return false
+}
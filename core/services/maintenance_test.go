@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewMaintenanceService_Ok checks that NewMaintenanceService creates a new maintenanceService struct
+func TestNewMaintenanceService_Ok(t *testing.T) {
+	// Act
+	service := NewMaintenanceService(config.Config{}, zerolog.Nop(), mocks.NewMaintenanceStore(t), mocks.NewJobRepository(t), mocks.NewJobService(t), mocks.NewUserService(t), mocks.NewKeyService(t), mocks.NewSigningKeyStore(t), mocks.NewLimitStore(t), mocks.NewAuditRepository(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestTasks_Ok checks that Tasks returns every maintenance task in order
+func TestTasks_Ok(t *testing.T) {
+	// Arrange
+	service := &maintenanceService{}
+
+	// Act
+	tasks := service.Tasks()
+
+	// Assert
+	var names []string
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+	assert.Equal(t, []string{taskRebuildIndexes, taskRecomputeStats, taskFlushCaches, taskReplayNotifications, taskPurgeExpired}, names)
+}
+
+// TestStartMaintenance_Ok checks that Start returns the queued job of the maintenance task
+func TestStartMaintenance_Ok(t *testing.T) {
+	// Arrange
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Create), context.Background(), mock.MatchedBy(func(job entities.Job) bool {
+		return job.Type == entities.JobTypeMaintenance && job.Status == entities.JobStatusQueued && job.MaxAttempts == 3
+	})).Return("job-id", nil).Once()
+
+	cfg := config.Config{}
+	cfg.Queue.MaxAttempts = 3
+	service := &maintenanceService{
+		config: cfg,
+		jobs:   jobRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Start(context.Background(), taskRebuildIndexes)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "job-id", resp.ID)
+	assert.Equal(t, taskRebuildIndexes, resp.Metadata["task"])
+}
+
+// TestStartMaintenance_NotFound checks that Start returns a non existent error when the task is not known
+func TestStartMaintenance_NotFound(t *testing.T) {
+	// Arrange
+	service := &maintenanceService{jobs: mocks.NewJobRepository(t)}
+
+	// Act
+	_, err := service.Start(context.Background(), "drop_database")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, "maintenance task drop_database not found", err.Error())
+}
+
+// TestProcessMaintenance_RebuildIndexes checks that Process rebuilds the indexes of every collection, reporting the progress after each one
+func TestProcessMaintenance_RebuildIndexes(t *testing.T) {
+	// Arrange
+	maintenanceStoreMock := mocks.NewMaintenanceStore(t)
+	maintenanceStoreMock.On(testutils.FunctionName(t, ports.MaintenanceStore.Collections), mock.Anything).Return([]string{"jobs", "users"}, nil).Once()
+	maintenanceStoreMock.On(testutils.FunctionName(t, ports.MaintenanceStore.RebuildIndexes), mock.Anything, "jobs").Return(nil).Once()
+	maintenanceStoreMock.On(testutils.FunctionName(t, ports.MaintenanceStore.RebuildIndexes), mock.Anything, "users").Return(nil).Once()
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Update), mock.Anything, mock.Anything).Return(nil).Twice()
+
+	service := &maintenanceService{
+		logger: zerolog.Nop(),
+		store:  maintenanceStoreMock,
+		jobs:   jobRepositoryMock,
+	}
+	job := &entities.Job{Type: entities.JobTypeMaintenance, Metadata: map[string]string{"task": taskRebuildIndexes}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), job.Processed)
+}
+
+// TestProcessMaintenance_RebuildIndexesError checks that Process stops at the first collection whose indexes cannot be rebuilt
+func TestProcessMaintenance_RebuildIndexesError(t *testing.T) {
+	// Arrange
+	maintenanceStoreMock := mocks.NewMaintenanceStore(t)
+	maintenanceStoreMock.On(testutils.FunctionName(t, ports.MaintenanceStore.Collections), mock.Anything).Return([]string{"jobs", "users"}, nil).Once()
+	maintenanceStoreMock.On(testutils.FunctionName(t, ports.MaintenanceStore.RebuildIndexes), mock.Anything, "jobs").Return(errors.New("compact failed")).Once()
+
+	service := &maintenanceService{
+		logger: zerolog.Nop(),
+		store:  maintenanceStoreMock,
+	}
+	job := &entities.Job{Type: entities.JobTypeMaintenance, Metadata: map[string]string{"task": taskRebuildIndexes}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Equal(t, "cannot rebuild the indexes of jobs: compact failed", err.Error())
+	assert.Equal(t, int64(0), job.Processed)
+}
+
+// TestProcessMaintenance_RecomputeStats checks that Process recomputes the user stats
+func TestProcessMaintenance_RecomputeStats(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.ComputeStats), mock.Anything).Return(models.UserStatsResp{Total: 2}, nil).Once()
+
+	service := &maintenanceService{users: userServiceMock}
+	job := &entities.Job{Type: entities.JobTypeMaintenance, Metadata: map[string]string{"task": taskRecomputeStats}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), job.Processed)
+}
+
+// TestProcessMaintenance_FlushCaches checks that Process reads the signing keys again
+func TestProcessMaintenance_FlushCaches(t *testing.T) {
+	// Arrange
+	keyServiceMock := mocks.NewKeyService(t)
+	keyServiceMock.On(testutils.FunctionName(t, ports.KeyService.Refresh), mock.Anything).Return(nil).Once()
+
+	service := &maintenanceService{keys: keyServiceMock}
+	job := &entities.Job{Type: entities.JobTypeMaintenance, Metadata: map[string]string{"task": taskFlushCaches}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), job.Processed)
+}
+
+// TestProcessMaintenance_ReplayNotifications checks that Process queues again every dead notification
+func TestProcessMaintenance_ReplayNotifications(t *testing.T) {
+	// Arrange
+	jobServiceMock := mocks.NewJobService(t)
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Get), mock.Anything, models.GetJobsReq{
+		Status: string(entities.JobStatusDead),
+		Type:   entities.JobTypeNotification,
+		Take:   replayBatchSize,
+	}).Return([]models.JobResp{{ID: "job-1"}, {ID: "job-2"}}, nil).Once()
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Retry), mock.Anything, "job-1").Return(models.JobResp{}, nil).Once()
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Retry), mock.Anything, "job-2").Return(models.JobResp{}, nil).Once()
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Update), mock.Anything, mock.Anything).Return(nil).Once()
+
+	service := &maintenanceService{
+		logger:     zerolog.Nop(),
+		jobs:       jobRepositoryMock,
+		jobService: jobServiceMock,
+	}
+	job := &entities.Job{Type: entities.JobTypeMaintenance, Metadata: map[string]string{"task": taskReplayNotifications}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), job.Processed)
+}
+
+// TestProcessMaintenance_PurgeExpired checks that Process deletes the expired signing keys and the ended limits, counting both
+func TestProcessMaintenance_PurgeExpired(t *testing.T) {
+	// Arrange
+	signingKeyStoreMock := mocks.NewSigningKeyStore(t)
+	signingKeyStoreMock.On(testutils.FunctionName(t, ports.SigningKeyStore.Purge), mock.Anything).Return(int64(1), nil).Once()
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Purge), mock.Anything).Return(int64(4), nil).Once()
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Update), mock.Anything, mock.Anything).Return(nil).Twice()
+
+	service := &maintenanceService{
+		logger:      zerolog.Nop(),
+		jobs:        jobRepositoryMock,
+		signingKeys: signingKeyStoreMock,
+		limits:      limitStoreMock,
+	}
+	job := &entities.Job{Type: entities.JobTypeMaintenance, Metadata: map[string]string{"task": taskPurgeExpired}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), job.Processed)
+}
+
+// TestProcessMaintenance_UnknownTask checks that Process fails when the task of the job is not known
+func TestProcessMaintenance_UnknownTask(t *testing.T) {
+	// Arrange
+	service := &maintenanceService{}
+	job := &entities.Job{Type: entities.JobTypeMaintenance, Metadata: map[string]string{"task": "drop_database"}}
+
+	// Act
+	err := service.Process(context.Background(), job)
+
+	// Assert
+	assert.Equal(t, "maintenance task drop_database not known", err.Error())
+}
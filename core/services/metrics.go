@@ -0,0 +1,247 @@
+package services
+
+import (
+	"encoding/json"
+	"expvar"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// login failure reasons of the auth metrics
+const (
+	loginFailureInvalidRequest    = "invalid_request"
+	loginFailureUserNotFound      = "user_not_found"
+	loginFailurePasswordIncorrect = "password_incorrect"
+	loginFailureError             = "error"
+	loginFailureLockedOut         = "locked_out"
+)
+
+// bcryptBucketsMS are the upper bounds, in milliseconds, of the buckets of the bcrypt durations,
+// around the tens of milliseconds a hash takes with the default cost
+var bcryptBucketsMS = []float64{10, 25, 50, 100, 250, 500, 1000}
+
+// hashingWaitBucketsMS are the upper bounds, in milliseconds, of the buckets of the time waited for a hashing worker
+var hashingWaitBucketsMS = []float64{1, 10, 50, 100, 500, 1000, 5000}
+
+// metrics of the authentication flows, exported together as auth
+var (
+	logins          = new(expvar.Map).Init()
+	loginFailures   = new(expvar.Map).Init()
+	signups         = new(expvar.Int)
+	passwordChanges = new(expvar.Int)
+	lockouts        = new(expvar.Int)
+	suspicious      = new(expvar.Int)
+	undeliverable   = new(expvar.Map).Init()
+	bcryptHash      = newDurationHistogram(bcryptBucketsMS)
+	bcryptCompare   = newDurationHistogram(bcryptBucketsMS)
+)
+
+// metrics of the hashing pool, exported together as hashing_pool in auth: its workers, the ones running a hash,
+// the hashes waiting for one, the hashes whose request ended while waiting and the time waited
+var (
+	hashingWorkers  = new(expvar.Int)
+	hashingActive   = new(expvar.Int)
+	hashingQueued   = new(expvar.Int)
+	hashingTimeouts = new(expvar.Int)
+	hashingWait     = newDurationHistogram(hashingWaitBucketsMS)
+)
+
+// metrics of the readiness checks, exported together as health: the count of checks per resulting status,
+// so the degraded or down states are noticed even if they are over when scraped, and the result of the last check
+var (
+	readinessChecks = new(expvar.Map).Init()
+	lastReadiness   atomic.Pointer[models.ReadinessResp]
+)
+
+// metrics of the text messages, exported together as sms: the count of messages sent per purpose
+// and the count of delivery statuses reported by the provider per status
+var (
+	smsSent     = new(expvar.Map).Init()
+	smsStatuses = new(expvar.Map).Init()
+)
+
+// metrics of the user cache, exported together as cache: the users read from it, the ones read through the user service,
+// and the invalidations received from the replicas
+var (
+	cacheHits          = new(expvar.Int)
+	cacheMisses        = new(expvar.Int)
+	cacheInvalidations = new(expvar.Int)
+)
+
+// metrics of the export of the audit events to the SIEM, exported together as siem: the events exported, the ones dropped
+// as the buffer was full or their last attempt failed, and the failed attempts
+var (
+	siemExported = new(expvar.Int)
+	siemDropped  = new(expvar.Int)
+	siemFailures = new(expvar.Int)
+)
+
+// precomputed stats, exported together as stats: the last user stats, computed by the scheduled stats job
+var lastUserStats atomic.Pointer[models.UserStatsResp]
+
+func init() {
+	stats := expvar.NewMap("stats")
+	stats.Set("users", expvar.Func(func() interface{} {
+		return lastUserStats.Load()
+	}))
+
+	health := expvar.NewMap("health")
+	health.Set("readiness_checks", readinessChecks)
+	health.Set("last_readiness", expvar.Func(func() interface{} {
+		return lastReadiness.Load()
+	}))
+
+	auth := expvar.NewMap("auth")
+	auth.Set("logins", logins)
+	auth.Set("login_failures", loginFailures)
+	auth.Set("signups", signups)
+	auth.Set("password_changes", passwordChanges)
+	auth.Set("lockouts", lockouts)
+	auth.Set("suspicious_logins", suspicious)
+	auth.Set("undeliverable_emails", undeliverable)
+	auth.Set("bcrypt_hash_ms", bcryptHash)
+	auth.Set("bcrypt_compare_ms", bcryptCompare)
+
+	hashingPool := new(expvar.Map).Init()
+	hashingPool.Set("workers", hashingWorkers)
+	hashingPool.Set("active", hashingActive)
+	hashingPool.Set("queued", hashingQueued)
+	hashingPool.Set("timeouts", hashingTimeouts)
+	hashingPool.Set("wait_ms", hashingWait)
+	auth.Set("hashing_pool", hashingPool)
+
+	sms := expvar.NewMap("sms")
+	sms.Set("sent", smsSent)
+	sms.Set("statuses", smsStatuses)
+
+	cache := expvar.NewMap("cache")
+	cache.Set("hits", cacheHits)
+	cache.Set("misses", cacheMisses)
+	cache.Set("invalidations", cacheInvalidations)
+
+	siem := expvar.NewMap("siem")
+	siem.Set("exported", siemExported)
+	siem.Set("dropped", siemDropped)
+	siem.Set("failures", siemFailures)
+}
+
+// loginSucceeded counts a succeeded login
+func loginSucceeded() {
+	logins.Add("succeeded", 1)
+}
+
+// loginFailed counts a failed login with its reason
+func loginFailed(reason string) {
+	logins.Add("failed", 1)
+	loginFailures.Add(reason, 1)
+}
+
+// lockedOut counts an email whose logins have been locked out
+func lockedOut() {
+	lockouts.Add(1)
+}
+
+// suspiciousLogin counts a login recorded as suspicious
+func suspiciousLogin() {
+	suspicious.Add(1)
+}
+
+// undeliverableEmail counts an undeliverable email of a signup or an email change with the reason, whether it was rejected or flagged
+func undeliverableEmail(reason string) {
+	undeliverable.Add(reason, 1)
+}
+
+// smsSentFor counts a text message sent for the purpose
+func smsSentFor(purpose string) {
+	smsSent.Add(purpose, 1)
+}
+
+// smsStatusReported counts a delivery status reported by the SMS provider
+func smsStatusReported(status string) {
+	smsStatuses.Add(status, 1)
+}
+
+// cacheHit counts a user read from the cache
+func cacheHit() {
+	cacheHits.Add(1)
+}
+
+// cacheMissed counts a user read through the user service as not cached
+func cacheMissed() {
+	cacheMisses.Add(1)
+}
+
+// cacheInvalidated counts an invalidation received from a replica
+func cacheInvalidated() {
+	cacheInvalidations.Add(1)
+}
+
+// readinessChecked counts a readiness check with its resulting status and keeps it as the last one
+func readinessChecked(resp models.ReadinessResp) {
+	readinessChecks.Add(resp.Status, 1)
+	lastReadiness.Store(&resp)
+}
+
+// userStatsComputed keeps the user stats as the last ones
+func userStatsComputed(resp models.UserStatsResp) {
+	lastUserStats.Store(&resp)
+}
+
+// durationHistogram distribution of durations over fixed buckets, exported with the cumulative count of every bucket like Prometheus does
+type durationHistogram struct {
+	mu       sync.Mutex
+	boundsMS []float64
+	counts   []int64
+	count    int64
+	sumMS    float64
+}
+
+func newDurationHistogram(boundsMS []float64) *durationHistogram {
+	return &durationHistogram{
+		boundsMS: boundsMS,
+		counts:   make([]int64, len(boundsMS)),
+	}
+}
+
+func (h *durationHistogram) observe(duration time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ms := float64(duration) / float64(time.Millisecond)
+	h.count++
+	h.sumMS += ms
+	for i, bound := range h.boundsMS {
+		if ms <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+}
+
+// String implements the expvar.Var interface
+func (h *durationHistogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets := make(map[string]int64, len(h.boundsMS)+1)
+	var cumulative int64
+	for i, bound := range h.boundsMS {
+		cumulative += h.counts[i]
+		buckets[strconv.FormatFloat(bound, 'f', -1, 64)] = cumulative
+	}
+	buckets["+Inf"] = h.count
+
+	b, err := json.Marshal(struct {
+		Count   int64            `json:"count"`
+		SumMS   float64          `json:"sum_ms"`
+		Buckets map[string]int64 `json:"buckets"`
+	}{h.count, h.sumMS, buckets})
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
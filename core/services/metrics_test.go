@@ -0,0 +1,56 @@
+package services
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// counter returns the value of a counter of a map of the auth metrics, zero when it has not been counted yet
+func counter(m *expvar.Map, key string) int64 {
+	v, ok := m.Get(key).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+	return v.Value()
+}
+
+// TestDurationHistogram_Ok checks that the histogram exports the count, the sum and the cumulative count of every bucket
+func TestDurationHistogram_Ok(t *testing.T) {
+	// Arrange
+	h := newDurationHistogram([]float64{10, 100})
+
+	// Act
+	h.observe(5 * time.Millisecond)
+	h.observe(50 * time.Millisecond)
+	h.observe(time.Second)
+
+	// Assert
+	var result struct {
+		Count   int64            `json:"count"`
+		SumMS   float64          `json:"sum_ms"`
+		Buckets map[string]int64 `json:"buckets"`
+	}
+	err := json.Unmarshal([]byte(h.String()), &result)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), result.Count)
+	assert.Equal(t, float64(1055), result.SumMS)
+	assert.Equal(t, map[string]int64{"10": 1, "100": 2, "+Inf": 3}, result.Buckets)
+}
+
+// TestLoginFailed_Ok checks that loginFailed counts a failed login and its reason
+func TestLoginFailed_Ok(t *testing.T) {
+	// Arrange
+	failed := counter(logins, "failed")
+	reason := counter(loginFailures, loginFailurePasswordIncorrect)
+
+	// Act
+	loginFailed(loginFailurePasswordIncorrect)
+
+	// Assert
+	assert.Equal(t, failed+1, counter(logins, "failed"))
+	assert.Equal(t, reason+1, counter(loginFailures, loginFailurePasswordIncorrect))
+}
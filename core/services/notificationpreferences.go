@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/mapping"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// emailTemplateCategories are the categories of the notifications sent with the email templates, the ones not listed,
+// like the password resets requested by the users, being always delivered
+var emailTemplateCategories = map[string]string{
+	ports.EmailTemplateSecurityAlert: entities.NotificationCategorySecurity,
+	ports.EmailTemplateVerification:  entities.NotificationCategoryVerification,
+}
+
+// notificationPreferencesService adapter of the notification preferences service
+type notificationPreferencesService struct {
+	repository ports.NotificationPreferencesRepository
+	users      ports.UserService
+}
+
+// NewNotificationPreferencesService creates a new notification preferences service, checking through the user service that their users exist
+func NewNotificationPreferencesService(repository ports.NotificationPreferencesRepository, users ports.UserService) ports.NotificationPreferencesService {
+	return &notificationPreferencesService{
+		repository: repository,
+		users:      users,
+	}
+}
+
+// Get the notification preferences of a user, with every category, the ones never set having every channel enabled
+func (s *notificationPreferencesService) Get(ctx context.Context, caller models.Caller, userID string) (resp models.NotificationPreferencesResp, err error) {
+	if err = authorizeNotificationPreferences(caller, userID); err != nil {
+		return
+	}
+	if _, err = s.users.GetByID(ctx, userID); err != nil {
+		return
+	}
+
+	preferences, err := s.get(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	resp = mapping.NotificationPreferencesResp(preferences)
+	return
+}
+
+// Update the channels of the categories of the notification preferences of a user set in the request, the other ones being left as they are,
+// in a single update so the concurrent ones do not override each other
+func (s *notificationPreferencesService) Update(ctx context.Context, caller models.Caller, userID string, req models.UpdateNotificationPreferencesReq) (resp models.NotificationPreferencesResp, err error) {
+	if err = authorizeNotificationPreferences(caller, userID); err != nil {
+		return
+	}
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if _, err = s.users.GetByID(ctx, userID); err != nil {
+		return
+	}
+
+	channels := make(map[string]map[string]bool, len(req.Categories))
+	for category, update := range req.Categories {
+		toggles := map[string]bool{}
+		for channel, value := range map[string]*bool{
+			entities.NotificationChannelEmail: update.Email,
+			entities.NotificationChannelSMS:   update.SMS,
+			entities.NotificationChannelPush:  update.Push,
+		} {
+			if value != nil {
+				toggles[channel] = *value
+			}
+		}
+		channels[category] = toggles
+	}
+	preferences, err := s.repository.Update(ctx, userID, channels, time.Now().UTC())
+	if err != nil {
+		return
+	}
+
+	resp = mapping.NotificationPreferencesResp(preferences)
+	return
+}
+
+// Allows reports whether the notifications of the category are delivered to the user through the channel
+func (s *notificationPreferencesService) Allows(ctx context.Context, userID, category, channel string) (bool, error) {
+	preferences, err := s.get(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return preferences.Channels(category).Allows(channel), nil
+}
+
+// get returns the stored preferences of the user, or the ones of a user that never set them
+func (s *notificationPreferencesService) get(ctx context.Context, userID string) (entities.NotificationPreferences, error) {
+	preferences, err := s.repository.Get(ctx, userID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return entities.NotificationPreferences{UserID: userID}, nil
+	}
+	return preferences, err
+}
+
+// authorizeNotificationPreferences returns an unauthorized error unless the caller is the user or an admin
+func authorizeNotificationPreferences(caller models.Caller, userID string) error {
+	if caller.Admin || caller.UserID == userID {
+		return nil
+	}
+	return apierror.Unauthorized(fmt.Errorf("the notification preferences of user ID %s are only managed by the user and the admins", userID))
+}
+
+// notificationPreferencesMailer decorator of a mailer that drops the emails disabled by their recipients
+type notificationPreferencesMailer struct {
+	ports.Mailer
+	preferences ports.NotificationPreferencesService
+	users       ports.UserService
+	logger      zerolog.Logger
+}
+
+// NewNotificationPreferencesMailer wraps a mailer checking the preferences of the users the emails are sent to
+func NewNotificationPreferencesMailer(mailer ports.Mailer, preferences ports.NotificationPreferencesService, users ports.UserService, logger zerolog.Logger) ports.Mailer {
+	return &notificationPreferencesMailer{
+		Mailer:      mailer,
+		preferences: preferences,
+		users:       users,
+		logger:      logger,
+	}
+}
+
+func (m *notificationPreferencesMailer) Send(ctx context.Context, template, to string, data map[string]string) error {
+	category, ok := emailTemplateCategories[template]
+	if !ok {
+		return m.Mailer.Send(ctx, template, to, data)
+	}
+
+	user, err := m.users.GetByEmail(ctx, to)
+	if err != nil {
+		if !errors.Is(err, apierror.ErrNotFound) {
+			m.logger.Error().Err(err).Str("template", template).Msg("recipient of the email cannot be read, sending it anyway")
+		}
+		return m.Mailer.Send(ctx, template, to, data)
+	}
+	if !allowsNotification(ctx, m.preferences, m.logger, user.ID, category, entities.NotificationChannelEmail) {
+		return nil
+	}
+	return m.Mailer.Send(ctx, template, to, data)
+}
+
+// notificationPreferencesDeviceService decorator of a device service that drops the push notifications disabled by their users
+type notificationPreferencesDeviceService struct {
+	ports.DeviceService
+	preferences ports.NotificationPreferencesService
+	logger      zerolog.Logger
+}
+
+// NewNotificationPreferencesDeviceService wraps a device service checking the preferences of the users notified
+func NewNotificationPreferencesDeviceService(service ports.DeviceService, preferences ports.NotificationPreferencesService, logger zerolog.Logger) ports.DeviceService {
+	return &notificationPreferencesDeviceService{
+		DeviceService: service,
+		preferences:   preferences,
+		logger:        logger,
+	}
+}
+
+func (s *notificationPreferencesDeviceService) Notify(ctx context.Context, userID string, notification ports.PushNotification) error {
+	if notification.Category != "" && !allowsNotification(ctx, s.preferences, s.logger, userID, notification.Category, entities.NotificationChannelPush) {
+		return nil
+	}
+	return s.DeviceService.Notify(ctx, userID, notification)
+}
+
+// notificationPreferencesSMSService decorator of a SMS service that refuses the codes disabled by their recipients
+type notificationPreferencesSMSService struct {
+	ports.SMSService
+	preferences ports.NotificationPreferencesService
+	logger      zerolog.Logger
+}
+
+// NewNotificationPreferencesSMSService wraps a SMS service checking the preferences of the recipients of the codes
+func NewNotificationPreferencesSMSService(service ports.SMSService, preferences ports.NotificationPreferencesService, logger zerolog.Logger) ports.SMSService {
+	return &notificationPreferencesSMSService{
+		SMSService:  service,
+		preferences: preferences,
+		logger:      logger,
+	}
+}
+
+func (s *notificationPreferencesSMSService) SendCode(ctx context.Context, req models.SendSMSCodeReq) error {
+	userID := req.UserID
+	if userID == "" {
+		userID = models.RequestInfoFrom(ctx).ActorID
+	}
+	if userID != "" && !allowsNotification(ctx, s.preferences, s.logger, userID, entities.NotificationCategoryVerification, entities.NotificationChannelSMS) {
+		return apierror.Conflict(fmt.Errorf("the text messages of the %s notifications are disabled in the notification preferences", entities.NotificationCategoryVerification))
+	}
+	return s.SMSService.SendCode(ctx, req)
+}
+
+// allowsNotification reports whether the notifications of the category are delivered to the user through the channel,
+// logging the failures to read the preferences instead of returning them, as the notifications are then delivered
+func allowsNotification(ctx context.Context, preferences ports.NotificationPreferencesService, logger zerolog.Logger, userID, category, channel string) bool {
+	allowed, err := preferences.Allows(ctx, userID, category, channel)
+	if err != nil {
+		logger.Error().Err(err).Str("user", userID).Str("category", category).Str("channel", channel).Msg("notification preferences cannot be read, delivering anyway")
+		return true
+	}
+	if !allowed {
+		logger.Debug().Str("user", userID).Str("category", category).Str("channel", channel).Msg("notification disabled by the notification preferences")
+	}
+	return allowed
+}
+
+// notificationPreferencesUserService decorator of an user service that deletes the preferences of the users deleted
+type notificationPreferencesUserService struct {
+	ports.UserService
+	repository ports.NotificationPreferencesRepository
+	logger     zerolog.Logger
+}
+
+// NewNotificationPreferencesUserService wraps a user service deleting the notification preferences of the users deleted
+func NewNotificationPreferencesUserService(service ports.UserService, repository ports.NotificationPreferencesRepository, logger zerolog.Logger) ports.UserService {
+	return &notificationPreferencesUserService{
+		UserService: service,
+		repository:  repository,
+		logger:      logger,
+	}
+}
+
+func (s *notificationPreferencesUserService) Delete(ctx context.Context, ID string) error {
+	if err := s.UserService.Delete(ctx, ID); err != nil {
+		return err
+	}
+	if err := s.repository.Delete(ctx, ID); err != nil {
+		s.logger.Error().Err(err).Str("user", ID).Msg("notification preferences of the deleted user cannot be deleted")
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestGetNotificationPreferences_NeverSet checks that Get enables every channel of every category of a user that never set the preferences
+func TestGetNotificationPreferences_NeverSet(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-user").Return(models.UserResp{ID: "test-user"}, nil).Once()
+	repositoryMock := mocks.NewNotificationPreferencesRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.NotificationPreferencesRepository.Get), context.Background(), "test-user").Return(entities.NotificationPreferences{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := NewNotificationPreferencesService(repositoryMock, userServiceMock)
+
+	// Act
+	resp, err := service.Get(context.Background(), models.Caller{UserID: "test-user"}, "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-user", resp.UserID)
+	assert.Equal(t, models.NotificationChannelsResp{Email: true, SMS: true, Push: true}, resp.Categories[entities.NotificationCategorySecurity])
+}
+
+// TestGetNotificationPreferences_OtherUser checks that Get returns an unauthorized error when a user reads the preferences of another user
+func TestGetNotificationPreferences_OtherUser(t *testing.T) {
+	// Arrange
+	service := NewNotificationPreferencesService(mocks.NewNotificationPreferencesRepository(t), mocks.NewUserService(t))
+
+	// Act
+	_, err := service.Get(context.Background(), models.Caller{UserID: "test-user"}, "other-user")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestUpdateNotificationPreferences_Ok checks that Update only sends the channels set in the request to the repository, in a single update
+func TestUpdateNotificationPreferences_Ok(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-user").Return(models.UserResp{ID: "test-user"}, nil).Once()
+	repositoryMock := mocks.NewNotificationPreferencesRepository(t)
+	channels := map[string]map[string]bool{entities.NotificationCategorySecurity: {entities.NotificationChannelPush: false}}
+	updated := entities.NotificationPreferences{
+		UserID: "test-user",
+		Categories: map[string]entities.NotificationChannels{
+			entities.NotificationCategorySecurity:     {Email: true, SMS: true},
+			entities.NotificationCategoryVerification: {Email: true},
+		},
+	}
+	repositoryMock.On(testutils.FunctionName(t, ports.NotificationPreferencesRepository.Update), context.Background(), "test-user", channels, mock.AnythingOfType("time.Time")).Return(updated, nil).Once()
+
+	service := NewNotificationPreferencesService(repositoryMock, userServiceMock)
+	disabled := false
+	req := models.UpdateNotificationPreferencesReq{Categories: map[string]models.UpdateNotificationChannelsReq{entities.NotificationCategorySecurity: {Push: &disabled}}}
+
+	// Act
+	resp, err := service.Update(context.Background(), models.Caller{Admin: true}, "test-user", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.NotificationChannelsResp{Email: true, SMS: true}, resp.Categories[entities.NotificationCategorySecurity])
+	assert.Equal(t, models.NotificationChannelsResp{Email: true}, resp.Categories[entities.NotificationCategoryVerification])
+}
+
+// TestUpdateNotificationPreferences_InvalidRequest checks that Update returns a validation error when a category is not known
+func TestUpdateNotificationPreferences_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := NewNotificationPreferencesService(mocks.NewNotificationPreferencesRepository(t), mocks.NewUserService(t))
+	req := models.UpdateNotificationPreferencesReq{Categories: map[string]models.UpdateNotificationChannelsReq{"newsletter": {}}}
+
+	// Act
+	_, err := service.Update(context.Background(), models.Caller{UserID: "test-user"}, "test-user", req)
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrValidation)
+}
+
+// TestAllowsNotification_Disabled checks that Allows reports the channels disabled in the stored preferences
+func TestAllowsNotification_Disabled(t *testing.T) {
+	// Arrange
+	repositoryMock := mocks.NewNotificationPreferencesRepository(t)
+	stored := entities.NotificationPreferences{
+		UserID:     "test-user",
+		Categories: map[string]entities.NotificationChannels{entities.NotificationCategorySecurity: {Email: true}},
+	}
+	repositoryMock.On(testutils.FunctionName(t, ports.NotificationPreferencesRepository.Get), context.Background(), "test-user").Return(stored, nil).Twice()
+
+	service := NewNotificationPreferencesService(repositoryMock, mocks.NewUserService(t))
+
+	// Act
+	email, emailErr := service.Allows(context.Background(), "test-user", entities.NotificationCategorySecurity, entities.NotificationChannelEmail)
+	push, pushErr := service.Allows(context.Background(), "test-user", entities.NotificationCategorySecurity, entities.NotificationChannelPush)
+
+	// Assert
+	assert.Nil(t, emailErr)
+	assert.Nil(t, pushErr)
+	assert.True(t, email)
+	assert.False(t, push)
+}
+
+// TestNotificationPreferencesMailer_Disabled checks that the mailer drops the emails of a category whose recipient disabled the email channel
+func TestNotificationPreferencesMailer_Disabled(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByEmail), context.Background(), "test@test.com").Return(models.UserResp{ID: "test-user"}, nil).Once()
+	preferencesMock := mocks.NewNotificationPreferencesService(t)
+	preferencesMock.On(testutils.FunctionName(t, ports.NotificationPreferencesService.Allows), context.Background(), "test-user", entities.NotificationCategorySecurity, entities.NotificationChannelEmail).Return(false, nil).Once()
+	mailerMock := mocks.NewMailer(t)
+
+	mailer := NewNotificationPreferencesMailer(mailerMock, preferencesMock, userServiceMock, zerolog.Nop())
+
+	// Act
+	err := mailer.Send(context.Background(), ports.EmailTemplateSecurityAlert, "test@test.com", nil)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestNotificationPreferencesMailer_NotAUser checks that the mailer sends the emails to the addresses not being of any user, like the previous email of a user
+func TestNotificationPreferencesMailer_NotAUser(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByEmail), context.Background(), "old@test.com").Return(models.UserResp{}, apierror.NotFound(fmt.Errorf("email old@test.com not found"))).Once()
+	mailerMock := mocks.NewMailer(t)
+	mailerMock.On(testutils.FunctionName(t, ports.Mailer.Send), context.Background(), ports.EmailTemplateSecurityAlert, "old@test.com", map[string]string(nil)).Return(nil).Once()
+
+	mailer := NewNotificationPreferencesMailer(mailerMock, mocks.NewNotificationPreferencesService(t), userServiceMock, zerolog.Nop())
+
+	// Act
+	err := mailer.Send(context.Background(), ports.EmailTemplateSecurityAlert, "old@test.com", nil)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestNotificationPreferencesMailer_NoCategory checks that the mailer sends the emails without a category, like the password resets, without reading the preferences
+func TestNotificationPreferencesMailer_NoCategory(t *testing.T) {
+	// Arrange
+	mailerMock := mocks.NewMailer(t)
+	mailerMock.On(testutils.FunctionName(t, ports.Mailer.Send), context.Background(), ports.EmailTemplatePasswordReset, "test@test.com", map[string]string(nil)).Return(nil).Once()
+
+	mailer := NewNotificationPreferencesMailer(mailerMock, mocks.NewNotificationPreferencesService(t), mocks.NewUserService(t), zerolog.Nop())
+
+	// Act
+	err := mailer.Send(context.Background(), ports.EmailTemplatePasswordReset, "test@test.com", nil)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestNotificationPreferencesDeviceService_Disabled checks that Notify drops the push notifications of a category whose user disabled the push channel
+func TestNotificationPreferencesDeviceService_Disabled(t *testing.T) {
+	// Arrange
+	preferencesMock := mocks.NewNotificationPreferencesService(t)
+	preferencesMock.On(testutils.FunctionName(t, ports.NotificationPreferencesService.Allows), context.Background(), "test-user", entities.NotificationCategorySecurity, entities.NotificationChannelPush).Return(false, nil).Once()
+
+	service := NewNotificationPreferencesDeviceService(mocks.NewDeviceService(t), preferencesMock, zerolog.Nop())
+
+	// Act
+	err := service.Notify(context.Background(), "test-user", ports.PushNotification{Title: "test", Category: entities.NotificationCategorySecurity})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestNotificationPreferencesDeviceService_PreferencesError checks that Notify sends the push notification when the preferences cannot be read
+func TestNotificationPreferencesDeviceService_PreferencesError(t *testing.T) {
+	// Arrange
+	notification := ports.PushNotification{Title: "test", Category: entities.NotificationCategorySecurity}
+	preferencesMock := mocks.NewNotificationPreferencesService(t)
+	preferencesMock.On(testutils.FunctionName(t, ports.NotificationPreferencesService.Allows), context.Background(), "test-user", entities.NotificationCategorySecurity, entities.NotificationChannelPush).Return(false, errors.New("database down")).Once()
+	deviceServiceMock := mocks.NewDeviceService(t)
+	deviceServiceMock.On(testutils.FunctionName(t, ports.DeviceService.Notify), context.Background(), "test-user", notification).Return(nil).Once()
+
+	service := NewNotificationPreferencesDeviceService(deviceServiceMock, preferencesMock, zerolog.Nop())
+
+	// Act
+	err := service.Notify(context.Background(), "test-user", notification)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestNotificationPreferencesSMSService_Disabled checks that SendCode returns a conflict when the user requesting the code disabled the text messages of the verification codes
+func TestNotificationPreferencesSMSService_Disabled(t *testing.T) {
+	// Arrange
+	ctx := models.WithRequestInfo(context.Background(), models.RequestInfo{ActorID: "test-user"})
+	preferencesMock := mocks.NewNotificationPreferencesService(t)
+	preferencesMock.On(testutils.FunctionName(t, ports.NotificationPreferencesService.Allows), ctx, "test-user", entities.NotificationCategoryVerification, entities.NotificationChannelSMS).Return(false, nil).Once()
+
+	service := NewNotificationPreferencesSMSService(mocks.NewSMSService(t), preferencesMock, zerolog.Nop())
+
+	// Act
+	err := service.SendCode(ctx, models.SendSMSCodeReq{Phone: "+34600000000", Purpose: models.SMSPurposeOTP})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrConflict)
+}
+
+// TestNotificationPreferencesSMSService_Recipient checks that SendCode checks the preferences of the recipient set in the request instead of the ones of the actor
+func TestNotificationPreferencesSMSService_Recipient(t *testing.T) {
+	// Arrange
+	ctx := models.WithRequestInfo(context.Background(), models.RequestInfo{ActorID: "admin-user"})
+	req := models.SendSMSCodeReq{Phone: "+34600000000", Purpose: models.SMSPurposeOTP, UserID: "test-user"}
+	preferencesMock := mocks.NewNotificationPreferencesService(t)
+	preferencesMock.On(testutils.FunctionName(t, ports.NotificationPreferencesService.Allows), ctx, "test-user", entities.NotificationCategoryVerification, entities.NotificationChannelSMS).Return(true, nil).Once()
+	smsServiceMock := mocks.NewSMSService(t)
+	smsServiceMock.On(testutils.FunctionName(t, ports.SMSService.SendCode), ctx, req).Return(nil).Once()
+
+	service := NewNotificationPreferencesSMSService(smsServiceMock, preferencesMock, zerolog.Nop())
+
+	// Act
+	err := service.SendCode(ctx, req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestNotificationPreferencesUserService_Delete checks that Delete deletes the notification preferences of the deleted user
+func TestNotificationPreferencesUserService_Delete(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Delete), context.Background(), "test-user").Return(nil).Once()
+	repositoryMock := mocks.NewNotificationPreferencesRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.NotificationPreferencesRepository.Delete), context.Background(), "test-user").Return(nil).Once()
+
+	service := NewNotificationPreferencesUserService(userServiceMock, repositoryMock, zerolog.Nop())
+
+	// Act
+	err := service.Delete(context.Background(), "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+}
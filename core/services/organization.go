@@ -0,0 +1,524 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/mapping"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// organizationRoleRanks the ranks of the roles of the members, a member being allowed what the roles of lower rank are
+var organizationRoleRanks = map[string]int{
+	entities.OrganizationRoleMember: 1,
+	entities.OrganizationRoleAdmin:  2,
+	entities.OrganizationRoleOwner:  3,
+}
+
+// organizationService adapter of the organization service, allowing the operations to the callers by their role in the organization
+type organizationService struct {
+	repository ports.OrganizationRepository
+	users      ports.UserService
+}
+
+// NewOrganizationService creates a new organization service, inviting the users found by email through the user service
+func NewOrganizationService(repository ports.OrganizationRepository, users ports.UserService) ports.OrganizationService {
+	return &organizationService{
+		repository: repository,
+		users:      users,
+	}
+}
+
+// Create an organization, the caller being its owner
+func (s *organizationService) Create(ctx context.Context, caller models.Caller, req models.CreateOrganizationReq) (resp models.CreationResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	organization := entities.Organization{
+		Name:    req.Name,
+		OwnerID: caller.UserID,
+		Members: []entities.OrganizationMember{
+			{UserID: caller.UserID, Role: entities.OrganizationRoleOwner, JoinedAt: now},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	insertedID, err := s.repository.Create(ctx, organization)
+	if err != nil {
+		return
+	}
+
+	resp = models.CreationResp{
+		InsertedID: insertedID,
+	}
+	return
+}
+
+// GetAll returns the organizations the caller is a member of, every one of them for the admins
+func (s *organizationService) GetAll(ctx context.Context, caller models.Caller) ([]models.OrganizationResp, error) {
+	var organizations []entities.Organization
+	if caller.Admin {
+		result, err := s.repository.Get(ctx, map[string]interface{}{}, nil, nil)
+		if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+			return nil, err
+		}
+		for _, r := range result {
+			organizations = append(organizations, *r.(*entities.Organization))
+		}
+	} else {
+		var err error
+		if organizations, err = s.repository.GetByMember(ctx, caller.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := make([]models.OrganizationResp, 0, len(organizations))
+	for _, organization := range organizations {
+		resp = append(resp, mapping.OrganizationResp(organization, caller.UserID))
+	}
+	return resp, nil
+}
+
+// GetByID returns an organization the caller is a member of
+func (s *organizationService) GetByID(ctx context.Context, caller models.Caller, ID string) (models.OrganizationResp, error) {
+	organization, err := s.authorize(ctx, caller, ID, entities.OrganizationRoleMember)
+	if err != nil {
+		return models.OrganizationResp{}, err
+	}
+	return mapping.OrganizationResp(organization, caller.UserID), nil
+}
+
+// Update an organization the caller is an admin of, setting only its fields so its members are left as they are
+func (s *organizationService) Update(ctx context.Context, caller models.Caller, ID string, req models.UpdateOrganizationReq) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if _, err = s.authorize(ctx, caller, ID, entities.OrganizationRoleAdmin); err != nil {
+		return
+	}
+
+	fields := map[string]interface{}{"updated_at": time.Now().UTC()}
+	if req.Name != nil {
+		fields["name"] = *req.Name
+	}
+	err = s.repository.Update(ctx, ID, fields)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("organization ID %s not found", ID))
+	}
+	return
+}
+
+// Delete an organization the caller is the owner of, along with its members
+func (s *organizationService) Delete(ctx context.Context, caller models.Caller, ID string) (err error) {
+	if _, err = s.authorize(ctx, caller, ID, entities.OrganizationRoleOwner); err != nil {
+		return
+	}
+
+	err = s.repository.Delete(ctx, ID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("organization ID %s not found", ID))
+	}
+	return
+}
+
+// GetMembers returns the members of an organization the caller is a member of, along with their users,
+// skipping the members whose user is not found, as deleted while being removed from their organizations
+func (s *organizationService) GetMembers(ctx context.Context, caller models.Caller, ID string) ([]models.OrganizationMemberResp, error) {
+	organization, err := s.authorize(ctx, caller, ID, entities.OrganizationRoleMember)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]models.OrganizationMemberResp, 0, len(organization.Members))
+	for _, member := range organization.Members {
+		user, err := s.users.GetByID(ctx, member.UserID)
+		if errors.Is(err, apierror.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, mapping.OrganizationMemberResp(member, user))
+	}
+	return resp, nil
+}
+
+// UpdateMember changes the role of a member of an organization the caller is an admin of, the role of the owner never changing
+func (s *organizationService) UpdateMember(ctx context.Context, caller models.Caller, ID, userID string, req models.UpdateMemberReq) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	organization, err := s.authorize(ctx, caller, ID, entities.OrganizationRoleAdmin)
+	if err != nil {
+		return
+	}
+	if err = memberOf(organization, userID); err != nil {
+		return
+	}
+	if userID == organization.OwnerID {
+		return apierror.Conflict(fmt.Errorf("the role of the owner of organization ID %s cannot be changed", ID))
+	}
+
+	err = s.repository.UpdateMemberRole(ctx, ID, userID, req.Role)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("user ID %s not a member of organization ID %s", userID, ID))
+	}
+	return
+}
+
+// RemoveMember removes a member from an organization the caller is an admin of, or the caller itself leaving it,
+// the owner never being removed
+func (s *organizationService) RemoveMember(ctx context.Context, caller models.Caller, ID, userID string) (err error) {
+	role := entities.OrganizationRoleAdmin
+	if userID == caller.UserID {
+		role = entities.OrganizationRoleMember
+	}
+	organization, err := s.authorize(ctx, caller, ID, role)
+	if err != nil {
+		return
+	}
+	if err = memberOf(organization, userID); err != nil {
+		return
+	}
+	if userID == organization.OwnerID {
+		return apierror.Conflict(fmt.Errorf("the owner of organization ID %s cannot be removed", ID))
+	}
+
+	err = s.repository.RemoveMember(ctx, ID, userID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("user ID %s not a member of organization ID %s", userID, ID))
+	}
+	return
+}
+
+// TransferOwnership makes a member the owner of an organization the caller is the owner of, the caller becoming an admin of it
+func (s *organizationService) TransferOwnership(ctx context.Context, caller models.Caller, ID string, req models.TransferOwnershipReq) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	organization, err := s.authorize(ctx, caller, ID, entities.OrganizationRoleOwner)
+	if err != nil {
+		return
+	}
+	if err = memberOf(organization, req.UserID); err != nil {
+		return
+	}
+	if req.UserID == organization.OwnerID {
+		return apierror.Conflict(fmt.Errorf("user ID %s already the owner of organization ID %s", req.UserID, ID))
+	}
+
+	err = s.repository.TransferOwnership(ctx, ID, organization.OwnerID, req.UserID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		// transferred or the member removed meanwhile
+		err = apierror.Conflict(fmt.Errorf("the ownership of organization ID %s cannot be transferred to user ID %s", ID, req.UserID))
+	}
+	return
+}
+
+// GetInvitations returns the pending invitations to an organization the caller is an admin of
+func (s *organizationService) GetInvitations(ctx context.Context, caller models.Caller, ID string) ([]models.OrganizationInvitationResp, error) {
+	organization, err := s.authorize(ctx, caller, ID, entities.OrganizationRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]models.OrganizationInvitationResp, 0, len(organization.Invitations))
+	for _, invitation := range organization.Invitations {
+		resp = append(resp, mapping.OrganizationInvitationResp(organization, invitation))
+	}
+	return resp, nil
+}
+
+// GetCallerInvitations returns the pending invitations of the caller to the organizations
+func (s *organizationService) GetCallerInvitations(ctx context.Context, caller models.Caller) ([]models.OrganizationInvitationResp, error) {
+	organizations, err := s.repository.GetByInvitee(ctx, caller.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]models.OrganizationInvitationResp, 0, len(organizations))
+	for _, organization := range organizations {
+		if invitation, ok := organization.Invitation(caller.UserID); ok {
+			resp = append(resp, mapping.OrganizationInvitationResp(organization, invitation))
+		}
+	}
+	return resp, nil
+}
+
+// Invite the user with the email to an organization the caller is an admin of, with the role of the request,
+// the user being a member once it accepts the invitation
+func (s *organizationService) Invite(ctx context.Context, caller models.Caller, ID string, req models.InviteMemberReq) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	organization, err := s.authorize(ctx, caller, ID, entities.OrganizationRoleAdmin)
+	if err != nil {
+		return
+	}
+	user, err := s.users.GetByEmail(ctx, req.Email)
+	if err != nil {
+		return
+	}
+	if _, ok := organization.Member(user.ID); ok {
+		return apierror.Conflict(fmt.Errorf("user %s already a member of organization ID %s", req.Email, ID))
+	}
+	if _, ok := organization.Invitation(user.ID); ok {
+		return apierror.Conflict(fmt.Errorf("user %s already invited to organization ID %s", req.Email, ID))
+	}
+
+	invitation := entities.OrganizationInvitation{UserID: user.ID, Role: req.Role, InvitedBy: caller.UserID, InvitedAt: time.Now().UTC()}
+	err = s.repository.Invite(ctx, ID, invitation)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		// invited concurrently or the organization deleted meanwhile
+		err = apierror.Conflict(fmt.Errorf("user %s cannot be invited to organization ID %s", req.Email, ID))
+	}
+	return
+}
+
+// AcceptInvitation makes the caller a member of an organization it is invited to, with the role of the invitation
+func (s *organizationService) AcceptInvitation(ctx context.Context, caller models.Caller, ID string) (err error) {
+	err = s.repository.AcceptInvitation(ctx, ID, caller.UserID, time.Now().UTC())
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("no invitation of user ID %s to organization ID %s", caller.UserID, ID))
+	}
+	return
+}
+
+// RemoveInvitation revokes the invitation of a user to an organization the caller is an admin of, or the caller itself declining it
+func (s *organizationService) RemoveInvitation(ctx context.Context, caller models.Caller, ID, userID string) (err error) {
+	if userID != caller.UserID {
+		var organization entities.Organization
+		if organization, err = s.authorize(ctx, caller, ID, entities.OrganizationRoleAdmin); err != nil {
+			return
+		}
+		if _, ok := organization.Invitation(userID); !ok {
+			return apierror.NotFound(fmt.Errorf("no invitation of user ID %s to organization ID %s", userID, ID))
+		}
+	}
+
+	err = s.repository.RemoveInvitation(ctx, ID, userID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("no invitation of user ID %s to organization ID %s", userID, ID))
+	}
+	return
+}
+
+// authorize returns the organization with the ID when the caller has at least the role in it, the admins of the API having any.
+// The organizations the caller is not a member of are not found, so their existence is not disclosed.
+func (s *organizationService) authorize(ctx context.Context, caller models.Caller, ID, role string) (organization entities.Organization, err error) {
+	result, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = apierror.NotFound(fmt.Errorf("organization ID %s not found", ID))
+		}
+		return
+	}
+	organization = *result.(*entities.Organization)
+
+	if caller.Admin {
+		return
+	}
+	member, ok := organization.Member(caller.UserID)
+	if !ok {
+		err = apierror.NotFound(fmt.Errorf("organization ID %s not found", ID))
+		return
+	}
+	if organizationRoleRanks[member.Role] < organizationRoleRanks[role] {
+		err = apierror.Unauthorized(fmt.Errorf("the %s role is required in organization ID %s", role, ID))
+	}
+	return
+}
+
+// memberOf returns a not found error when the user is not a member of the organization
+func memberOf(organization entities.Organization, userID string) error {
+	if _, ok := organization.Member(userID); !ok {
+		return apierror.NotFound(fmt.Errorf("user ID %s not a member of organization ID %s", userID, organization.ID))
+	}
+	return nil
+}
+
+// organizationUserService decorator of an user service that scopes the users to the organizations of the callers
+type organizationUserService struct {
+	ports.UserService
+	organizations ports.OrganizationRepository
+	logger        zerolog.Logger
+}
+
+// NewOrganizationUserService wraps a user service scoping the users to the organizations of the callers
+func NewOrganizationUserService(service ports.UserService, organizations ports.OrganizationRepository, logger zerolog.Logger) ports.UserService {
+	return &organizationUserService{
+		UserService:   service,
+		organizations: organizations,
+		logger:        logger,
+	}
+}
+
+// GetAll returns every user to the admins, and the caller along with the members of its organizations to the others,
+// skipping the members whose user is not found, as deleted while being removed from their organizations
+func (s *organizationUserService) GetAll(ctx context.Context, caller models.Caller) ([]models.UserResp, error) {
+	if caller.Admin {
+		return s.UserService.GetAll(ctx, caller)
+	}
+	IDs, err := sharingUserIDs(ctx, s.organizations, caller.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]models.UserResp, 0, len(IDs))
+	for _, ID := range IDs {
+		user, err := s.UserService.GetByID(ctx, ID)
+		if errors.Is(err, apierror.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, user)
+	}
+	return resp, nil
+}
+
+// Search returns the users found the caller is allowed to
+func (s *organizationUserService) Search(ctx context.Context, caller models.Caller, req models.SearchUsersReq) ([]models.UserResp, error) {
+	users, err := s.UserService.Search(ctx, caller, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.sharing(ctx, caller, users)
+}
+
+// GetNearby returns the users found near the point the caller is allowed to
+func (s *organizationUserService) GetNearby(ctx context.Context, caller models.Caller, req models.NearbyUsersReq) ([]models.UserResp, error) {
+	users, err := s.UserService.GetNearby(ctx, caller, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.sharing(ctx, caller, users)
+}
+
+// Authorize allows the admins, and the others for themselves and the members of their organizations,
+// the users they are not allowed to being not found
+func (s *organizationUserService) Authorize(ctx context.Context, caller models.Caller, ID string) error {
+	if err := s.UserService.Authorize(ctx, caller, ID); err != nil || caller.Admin {
+		return err
+	}
+	IDs, err := sharingUserIDs(ctx, s.organizations, caller.UserID)
+	if err != nil {
+		return err
+	}
+	for _, sharing := range IDs {
+		if sharing == ID {
+			return nil
+		}
+	}
+	return apierror.NotFound(fmt.Errorf("ID %s not found", ID))
+}
+
+// AuthorizeUpdate allows the caller to update the users it is allowed to, so the members of its organizations are only read
+func (s *organizationUserService) AuthorizeUpdate(ctx context.Context, caller models.Caller, ID string, user models.UpdateUserReq) error {
+	if err := s.Authorize(ctx, caller, ID); err != nil {
+		return err
+	}
+	return s.UserService.AuthorizeUpdate(ctx, caller, ID, user)
+}
+
+// Delete the user when it owns no organization, removing it from the organizations it is a member of or invited to
+func (s *organizationUserService) Delete(ctx context.Context, ID string) error {
+	if err := s.notOwner(ctx, ID); err != nil {
+		return err
+	}
+	if err := s.UserService.Delete(ctx, ID); err != nil {
+		return err
+	}
+	s.removeUser(ctx, ID)
+	return nil
+}
+
+// Merge the source user when it owns no organization, removing it from the organizations it is a member of or invited to
+func (s *organizationUserService) Merge(ctx context.Context, ID string, req models.MergeUsersReq) error {
+	if err := s.notOwner(ctx, req.SourceID); err != nil {
+		return err
+	}
+	if err := s.UserService.Merge(ctx, ID, req); err != nil {
+		return err
+	}
+	s.removeUser(ctx, req.SourceID)
+	return nil
+}
+
+// notOwner returns a conflict error when the user owns any organization, whose ownership has to be transferred first
+func (s *organizationUserService) notOwner(ctx context.Context, userID string) error {
+	result, err := s.organizations.Get(ctx, map[string]interface{}{"owner_id": userID}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return err
+	}
+	if len(result) < 1 {
+		return nil
+	}
+
+	IDs := make([]string, 0, len(result))
+	for _, r := range result {
+		IDs = append(IDs, r.(*entities.Organization).ID)
+	}
+	return apierror.Conflict(fmt.Errorf("user ID %s owns organizations %s, whose ownership has to be transferred first", userID, strings.Join(IDs, ", ")))
+}
+
+// removeUser removes the user deleted from its organizations, logging the errors as the user is already deleted
+func (s *organizationUserService) removeUser(ctx context.Context, userID string) {
+	if err := s.organizations.RemoveUser(ctx, userID); err != nil {
+		s.logger.Error().Err(err).Str("user", userID).Msg("deleted user cannot be removed from its organizations")
+	}
+}
+
+// sharing returns the users the caller is allowed to, keeping their order, every one of them for the admins
+func (s *organizationUserService) sharing(ctx context.Context, caller models.Caller, users []models.UserResp) ([]models.UserResp, error) {
+	if caller.Admin {
+		return users, nil
+	}
+	IDs, err := sharingUserIDs(ctx, s.organizations, caller.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[string]bool, len(IDs))
+	for _, ID := range IDs {
+		allowed[ID] = true
+	}
+	resp := make([]models.UserResp, 0, len(users))
+	for _, user := range users {
+		if allowed[user.ID] {
+			resp = append(resp, user)
+		}
+	}
+	return resp, nil
+}
+
+// sharingUserIDs returns the ID of the user followed by the ones of the members of its organizations, without repetitions
+func sharingUserIDs(ctx context.Context, repository ports.OrganizationRepository, userID string) ([]string, error) {
+	organizations, err := repository.GetByMember(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	IDs := []string{userID}
+	seen := map[string]bool{userID: true}
+	for _, organization := range organizations {
+		for _, member := range organization.Members {
+			if !seen[member.UserID] {
+				seen[member.UserID] = true
+				IDs = append(IDs, member.UserID)
+			}
+		}
+	}
+	return IDs, nil
+}
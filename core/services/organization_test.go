@@ -0,0 +1,658 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testOrganization returns an organization owned by test-owner, with test-admin as an admin, test-member as a member
+// and test-invitee invited as a member
+func testOrganization() *entities.Organization {
+	return &entities.Organization{
+		ID:      "test-organization",
+		Name:    "test organization",
+		OwnerID: "test-owner",
+		Members: []entities.OrganizationMember{
+			{UserID: "test-owner", Role: entities.OrganizationRoleOwner},
+			{UserID: "test-admin", Role: entities.OrganizationRoleAdmin},
+			{UserID: "test-member", Role: entities.OrganizationRoleMember},
+		},
+		Invitations: []entities.OrganizationInvitation{
+			{UserID: "test-invitee", Role: entities.OrganizationRoleMember, InvitedBy: "test-admin"},
+		},
+	}
+}
+
+// TestCreateOrganization_Ok checks that Create creates the organization with the caller as its owner and only member
+func TestCreateOrganization_Ok(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.Create), context.Background(), mock.MatchedBy(func(organization entities.Organization) bool {
+		return organization.OwnerID == "test-user" && len(organization.Members) == 1 &&
+			organization.Members[0].UserID == "test-user" && organization.Members[0].Role == entities.OrganizationRoleOwner
+	})).Return("test-organization", nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	resp, err := service.Create(context.Background(), models.Caller{UserID: "test-user"}, models.CreateOrganizationReq{Name: "test organization"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-organization", resp.InsertedID)
+}
+
+// TestCreateOrganization_InvalidRequest checks that Create returns a validation error when the name is empty
+func TestCreateOrganization_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := NewOrganizationService(mocks.NewOrganizationRepository(t), mocks.NewUserService(t))
+
+	// Act
+	_, err := service.Create(context.Background(), models.Caller{UserID: "test-user"}, models.CreateOrganizationReq{})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrValidation)
+}
+
+// TestGetAllOrganizations_Member checks that GetAll returns the organizations the caller is a member of, with its role in them
+func TestGetAllOrganizations_Member(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByMember), context.Background(), "test-admin").Return([]entities.Organization{*testOrganization()}, nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	resp, err := service.GetAll(context.Background(), models.Caller{UserID: "test-admin"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, entities.OrganizationRoleAdmin, resp[0].Role)
+	assert.Equal(t, 3, resp[0].MembersCount)
+}
+
+// TestGetAllOrganizations_Admin checks that GetAll returns every organization to the admins of the API, none when there are not any
+func TestGetAllOrganizations_Admin(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(assert.AnError)).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	resp, err := service.GetAll(context.Background(), models.Caller{UserID: "test-user", Admin: true})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, resp)
+}
+
+// TestGetOrganizationByID_NotMember checks that GetByID returns a not found error when the caller is not a member of the organization
+func TestGetOrganizationByID_NotMember(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	_, err := service.GetByID(context.Background(), models.Caller{UserID: "test-user"}, "test-organization")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+// TestGetOrganizationByID_NotFound checks that GetByID returns a not found error when the organization does not exist
+func TestGetOrganizationByID_NotFound(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(nil, wrappers.NewNonExistentErr(assert.AnError)).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	_, err := service.GetByID(context.Background(), models.Caller{UserID: "test-member"}, "test-organization")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+	assert.Equal(t, "organization ID test-organization not found", err.Error())
+}
+
+// TestUpdateOrganization_Ok checks that Update sets the name of the organization when the caller is one of its admins
+func TestUpdateOrganization_Ok(t *testing.T) {
+	// Arrange
+	name := "new name"
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.Update), context.Background(), "test-organization", mock.MatchedBy(func(fields map[string]interface{}) bool {
+		_, members := fields["members"]
+		return fields["name"] == name && !members
+	})).Return(nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.Update(context.Background(), models.Caller{UserID: "test-admin"}, "test-organization", models.UpdateOrganizationReq{Name: &name})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdateOrganization_Member checks that Update returns an unauthorized error when the caller is a member but not an admin
+func TestUpdateOrganization_Member(t *testing.T) {
+	// Arrange
+	name := "new name"
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.Update(context.Background(), models.Caller{UserID: "test-member"}, "test-organization", models.UpdateOrganizationReq{Name: &name})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestDeleteOrganization_Admin checks that Delete returns an unauthorized error when the caller is an admin but not the owner
+func TestDeleteOrganization_Admin(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.Delete(context.Background(), models.Caller{UserID: "test-admin"}, "test-organization")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestDeleteOrganization_AdminOfTheAPI checks that Delete deletes the organization when the caller is an admin of the API, not being a member
+func TestDeleteOrganization_AdminOfTheAPI(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.Delete), context.Background(), "test-organization").Return(nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.Delete(context.Background(), models.Caller{UserID: "test-user", Admin: true}, "test-organization")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestGetOrganizationMembers_Ok checks that GetMembers returns the members with their users, skipping the ones whose user is not found
+func TestGetOrganizationMembers_Ok(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-owner").Return(models.UserResp{ID: "test-owner"}, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-admin").Return(models.UserResp{}, apierror.NotFound(assert.AnError)).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-member").Return(models.UserResp{ID: "test-member"}, nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, userServiceMock)
+
+	// Act
+	resp, err := service.GetMembers(context.Background(), models.Caller{UserID: "test-member"}, "test-organization")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp, 2)
+	assert.Equal(t, "test-owner", resp[0].User.ID)
+	assert.Equal(t, entities.OrganizationRoleOwner, resp[0].Role)
+	assert.Equal(t, "test-member", resp[1].User.ID)
+}
+
+// TestUpdateOrganizationMember_Owner checks that UpdateMember returns a conflict error when the member is the owner
+func TestUpdateOrganizationMember_Owner(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.UpdateMember(context.Background(), models.Caller{UserID: "test-admin"}, "test-organization", "test-owner", models.UpdateMemberReq{Role: entities.OrganizationRoleMember})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrConflict)
+}
+
+// TestUpdateOrganizationMember_Ok checks that UpdateMember sets the role of the member
+func TestUpdateOrganizationMember_Ok(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.UpdateMemberRole), context.Background(), "test-organization", "test-member", entities.OrganizationRoleAdmin).Return(nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.UpdateMember(context.Background(), models.Caller{UserID: "test-owner"}, "test-organization", "test-member", models.UpdateMemberReq{Role: entities.OrganizationRoleAdmin})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRemoveOrganizationMember_Leave checks that RemoveMember removes the caller from the organization when leaving it, not being an admin
+func TestRemoveOrganizationMember_Leave(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.RemoveMember), context.Background(), "test-organization", "test-member").Return(nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.RemoveMember(context.Background(), models.Caller{UserID: "test-member"}, "test-organization", "test-member")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRemoveOrganizationMember_Owner checks that RemoveMember returns a conflict error when the member is the owner, even leaving
+func TestRemoveOrganizationMember_Owner(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.RemoveMember(context.Background(), models.Caller{UserID: "test-owner"}, "test-organization", "test-owner")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrConflict)
+}
+
+// TestRemoveOrganizationMember_NotMember checks that RemoveMember returns a not found error when the user is not a member
+func TestRemoveOrganizationMember_NotMember(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.RemoveMember(context.Background(), models.Caller{UserID: "test-admin"}, "test-organization", "test-user")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+// TestTransferOrganizationOwnership_Ok checks that TransferOwnership makes the member the owner in place of the current one
+func TestTransferOrganizationOwnership_Ok(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.TransferOwnership), context.Background(), "test-organization", "test-owner", "test-member").Return(nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.TransferOwnership(context.Background(), models.Caller{UserID: "test-owner"}, "test-organization", models.TransferOwnershipReq{UserID: "test-member"})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestTransferOrganizationOwnership_Admin checks that TransferOwnership returns an unauthorized error when the caller is not the owner
+func TestTransferOrganizationOwnership_Admin(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.TransferOwnership(context.Background(), models.Caller{UserID: "test-admin"}, "test-organization", models.TransferOwnershipReq{UserID: "test-admin"})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestTransferOrganizationOwnership_NotMember checks that TransferOwnership returns a not found error when the user is not a member
+func TestTransferOrganizationOwnership_NotMember(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.TransferOwnership(context.Background(), models.Caller{UserID: "test-owner"}, "test-organization", models.TransferOwnershipReq{UserID: "test-invitee"})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+// TestGetOrganizationInvitations_Member checks that GetInvitations returns an unauthorized error when the caller is not an admin
+func TestGetOrganizationInvitations_Member(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	_, err := service.GetInvitations(context.Background(), models.Caller{UserID: "test-member"}, "test-organization")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestGetCallerOrganizationInvitations_Ok checks that GetCallerInvitations returns the invitations of the caller along with their organizations
+func TestGetCallerOrganizationInvitations_Ok(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByInvitee), context.Background(), "test-invitee").Return([]entities.Organization{*testOrganization()}, nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	resp, err := service.GetCallerInvitations(context.Background(), models.Caller{UserID: "test-invitee"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, "test-organization", resp[0].OrganizationID)
+	assert.Equal(t, "test-admin", resp[0].InvitedBy)
+}
+
+// TestInviteOrganizationMember_Ok checks that Invite invites the user with the email with the role of the request, without adding it as a member
+func TestInviteOrganizationMember_Ok(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.Invite), context.Background(), "test-organization", mock.MatchedBy(func(invitation entities.OrganizationInvitation) bool {
+		return invitation.UserID == "test-user" && invitation.Role == entities.OrganizationRoleMember && invitation.InvitedBy == "test-owner"
+	})).Return(nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByEmail), context.Background(), "test@test.com").Return(models.UserResp{ID: "test-user"}, nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, userServiceMock)
+
+	// Act
+	err := service.Invite(context.Background(), models.Caller{UserID: "test-owner"}, "test-organization", models.InviteMemberReq{Email: "test@test.com", Role: entities.OrganizationRoleMember})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestInviteOrganizationMember_AlreadyMember checks that Invite returns a conflict error when the user is a member already
+func TestInviteOrganizationMember_AlreadyMember(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByEmail), context.Background(), "test@test.com").Return(models.UserResp{ID: "test-member"}, nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, userServiceMock)
+
+	// Act
+	err := service.Invite(context.Background(), models.Caller{UserID: "test-admin"}, "test-organization", models.InviteMemberReq{Email: "test@test.com", Role: entities.OrganizationRoleAdmin})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrConflict)
+}
+
+// TestInviteOrganizationMember_AlreadyInvited checks that Invite returns a conflict error when the user is invited already
+func TestInviteOrganizationMember_AlreadyInvited(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByEmail), context.Background(), "test@test.com").Return(models.UserResp{ID: "test-invitee"}, nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, userServiceMock)
+
+	// Act
+	err := service.Invite(context.Background(), models.Caller{UserID: "test-admin"}, "test-organization", models.InviteMemberReq{Email: "test@test.com", Role: entities.OrganizationRoleMember})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrConflict)
+}
+
+// TestInviteOrganizationMember_OwnerRole checks that Invite returns a validation error when the role is the owner one
+func TestInviteOrganizationMember_OwnerRole(t *testing.T) {
+	// Arrange
+	service := NewOrganizationService(mocks.NewOrganizationRepository(t), mocks.NewUserService(t))
+
+	// Act
+	err := service.Invite(context.Background(), models.Caller{UserID: "test-owner"}, "test-organization", models.InviteMemberReq{Email: "test@test.com", Role: entities.OrganizationRoleOwner})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrValidation)
+}
+
+// TestAcceptOrganizationInvitation_NotInvited checks that AcceptInvitation returns a not found error when the caller is not invited
+func TestAcceptOrganizationInvitation_NotInvited(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.AcceptInvitation), context.Background(), "test-organization", "test-user", mock.AnythingOfType("time.Time")).Return(wrappers.NewNonExistentErr(assert.AnError)).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.AcceptInvitation(context.Background(), models.Caller{UserID: "test-user"}, "test-organization")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+// TestRemoveOrganizationInvitation_Decline checks that RemoveInvitation removes the invitation of the caller declining it, not being a member
+func TestRemoveOrganizationInvitation_Decline(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.RemoveInvitation), context.Background(), "test-organization", "test-invitee").Return(nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.RemoveInvitation(context.Background(), models.Caller{UserID: "test-invitee"}, "test-organization", "test-invitee")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRemoveOrganizationInvitation_Member checks that RemoveInvitation returns an unauthorized error when a member not admin revokes an invitation
+func TestRemoveOrganizationInvitation_Member(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(testOrganization(), nil).Once()
+
+	service := NewOrganizationService(organizationRepositoryMock, mocks.NewUserService(t))
+
+	// Act
+	err := service.RemoveInvitation(context.Background(), models.Caller{UserID: "test-member"}, "test-organization", "test-invitee")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestGetAllOrganizationUsers_Member checks that the decorated GetAll returns the caller along with the members of its organizations,
+// skipping the ones whose user is not found
+func TestGetAllOrganizationUsers_Member(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByMember), context.Background(), "test-member").Return([]entities.Organization{*testOrganization()}, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-member").Return(models.UserResp{ID: "test-member"}, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-owner").Return(models.UserResp{ID: "test-owner"}, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-admin").Return(models.UserResp{}, apierror.NotFound(assert.AnError)).Once()
+
+	service := NewOrganizationUserService(userServiceMock, organizationRepositoryMock, zerolog.Nop())
+
+	// Act
+	resp, err := service.GetAll(context.Background(), models.Caller{UserID: "test-member"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.UserResp{{ID: "test-member"}, {ID: "test-owner"}}, resp)
+}
+
+// TestGetAllOrganizationUsers_Admin checks that the decorated GetAll returns every user to the admins
+func TestGetAllOrganizationUsers_Admin(t *testing.T) {
+	// Arrange
+	caller := models.Caller{UserID: "test-user", Admin: true}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetAll), context.Background(), caller).Return([]models.UserResp{{ID: "test-user"}, {ID: "test-other"}}, nil).Once()
+
+	service := NewOrganizationUserService(userServiceMock, mocks.NewOrganizationRepository(t), zerolog.Nop())
+
+	// Act
+	resp, err := service.GetAll(context.Background(), caller)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp, 2)
+}
+
+// TestSearchOrganizationUsers_Member checks that the decorated Search only returns the caller and the members of its organizations
+func TestSearchOrganizationUsers_Member(t *testing.T) {
+	// Arrange
+	caller := models.Caller{UserID: "test-member"}
+	req := models.SearchUsersReq{Query: "test"}
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByMember), context.Background(), "test-member").Return([]entities.Organization{*testOrganization()}, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Search), context.Background(), caller, req).
+		Return([]models.UserResp{{ID: "test-outsider"}, {ID: "test-owner"}, {ID: "test-member"}}, nil).Once()
+
+	service := NewOrganizationUserService(userServiceMock, organizationRepositoryMock, zerolog.Nop())
+
+	// Act
+	resp, err := service.Search(context.Background(), caller, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.UserResp{{ID: "test-owner"}, {ID: "test-member"}}, resp)
+}
+
+// TestGetNearbyOrganizationUsers_Admin checks that the decorated GetNearby returns every user found to the admins
+func TestGetNearbyOrganizationUsers_Admin(t *testing.T) {
+	// Arrange
+	caller := models.Caller{UserID: "test-user", Admin: true}
+	req := models.NearbyUsersReq{Longitude: 2.17, Latitude: 41.38, Radius: 1000}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetNearby), context.Background(), caller, req).
+		Return([]models.UserResp{{ID: "test-outsider"}, {ID: "test-user"}}, nil).Once()
+
+	service := NewOrganizationUserService(userServiceMock, mocks.NewOrganizationRepository(t), zerolog.Nop())
+
+	// Act
+	resp, err := service.GetNearby(context.Background(), caller, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp, 2)
+}
+
+// TestAuthorizeOrganizationUser_NotSharing checks that the decorated Authorize returns a not found error when the user is not
+// a member of any organization of the caller, and allows the members of them
+func TestAuthorizeOrganizationUser_NotSharing(t *testing.T) {
+	// Arrange
+	caller := models.Caller{UserID: "test-member"}
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByMember), context.Background(), "test-member").Return([]entities.Organization{*testOrganization()}, nil).Twice()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Authorize), context.Background(), caller, mock.AnythingOfType("string")).Return(nil).Twice()
+
+	service := NewOrganizationUserService(userServiceMock, organizationRepositoryMock, zerolog.Nop())
+
+	// Act
+	notSharingErr := service.Authorize(context.Background(), caller, "test-invitee")
+	sharingErr := service.Authorize(context.Background(), caller, "test-owner")
+
+	// Assert
+	assert.ErrorIs(t, notSharingErr, apierror.ErrNotFound)
+	assert.Nil(t, sharingErr)
+}
+
+// TestAuthorizeUpdateOrganizationUser_Member checks that the decorated AuthorizeUpdate does not allow the members of the organizations
+// of the caller to be updated, only read, and returns a not found error for the users not sharing any
+func TestAuthorizeUpdateOrganizationUser_Member(t *testing.T) {
+	// Arrange
+	caller := models.Caller{UserID: "test-member"}
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByMember), context.Background(), "test-member").Return([]entities.Organization{*testOrganization()}, nil).Twice()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Authorize), context.Background(), caller, mock.AnythingOfType("string")).Return(nil).Twice()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.AuthorizeUpdate), context.Background(), caller, "test-owner", models.UpdateUserReq{}).
+		Return(apierror.Unauthorized(assert.AnError)).Once()
+
+	service := NewOrganizationUserService(userServiceMock, organizationRepositoryMock, zerolog.Nop())
+
+	// Act
+	notSharingErr := service.AuthorizeUpdate(context.Background(), caller, "test-invitee", models.UpdateUserReq{})
+	memberErr := service.AuthorizeUpdate(context.Background(), caller, "test-owner", models.UpdateUserReq{})
+
+	// Assert
+	assert.ErrorIs(t, notSharingErr, apierror.ErrNotFound)
+	assert.ErrorIs(t, memberErr, apierror.ErrUnauthorized)
+}
+
+// TestDeleteOrganizationUser_Ok checks that the decorated Delete removes the user deleted from its organizations, not failing when it cannot
+func TestDeleteOrganizationUser_Ok(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Delete), context.Background(), "test-user").Return(nil).Once()
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.Get), context.Background(), map[string]interface{}{"owner_id": "test-user"}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(assert.AnError)).Once()
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.RemoveUser), context.Background(), "test-user").Return(assert.AnError).Once()
+
+	service := NewOrganizationUserService(userServiceMock, organizationRepositoryMock, zerolog.Nop())
+
+	// Act
+	err := service.Delete(context.Background(), "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeleteOrganizationUser_Owner checks that the decorated Delete returns a conflict error without deleting the user when it owns an organization
+func TestDeleteOrganizationUser_Owner(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.Get), context.Background(), map[string]interface{}{"owner_id": "test-owner"}, (*int)(nil), (*int)(nil)).Return([]interface{}{testOrganization()}, nil).Once()
+
+	service := NewOrganizationUserService(mocks.NewUserService(t), organizationRepositoryMock, zerolog.Nop())
+
+	// Act
+	err := service.Delete(context.Background(), "test-owner")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrConflict)
+}
+
+// TestMergeOrganizationUser_Owner checks that the decorated Merge returns a conflict error without merging when the source user owns an organization
+func TestMergeOrganizationUser_Owner(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.Get), context.Background(), map[string]interface{}{"owner_id": "test-owner"}, (*int)(nil), (*int)(nil)).Return([]interface{}{testOrganization()}, nil).Once()
+
+	service := NewOrganizationUserService(mocks.NewUserService(t), organizationRepositoryMock, zerolog.Nop())
+
+	// Act
+	err := service.Merge(context.Background(), "test-member", models.MergeUsersReq{SourceID: "test-owner"})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrConflict)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// knownDeviceKeyPrefix prefixes the keys of the devices the users logged in from in the limit store
+const knownDeviceKeyPrefix = "device:"
+
+// events of the push notifications of the security events, sent in their data
+const (
+	pushEventNewDeviceLogin  = "new_device_login"
+	pushEventPasswordChanged = "password_changed"
+)
+
+// pushUserService decorator of an user service that notifies the users of the security events of their account
+type pushUserService struct {
+	ports.UserService
+	devices        ports.DeviceService
+	limits         ports.LimitStore
+	logger         zerolog.Logger
+	knownDeviceTTL time.Duration
+}
+
+// NewPushUserService wraps a user service notifying the logins from a new device and the password changes to the devices of the users
+func NewPushUserService(service ports.UserService, devices ports.DeviceService, limits ports.LimitStore, logger zerolog.Logger, knownDeviceTTL time.Duration) ports.UserService {
+	return &pushUserService{
+		UserService:    service,
+		devices:        devices,
+		limits:         limits,
+		logger:         logger,
+		knownDeviceTTL: knownDeviceTTL,
+	}
+}
+
+func (s *pushUserService) Login(ctx context.Context, credentials models.LoginUserReq) (models.LoginUserResp, error) {
+	resp, err := s.UserService.Login(ctx, credentials)
+	if err != nil {
+		return resp, err
+	}
+
+	userAgent := models.RequestInfoFrom(ctx).UserAgent
+	if userAgent == "" {
+		return resp, nil
+	}
+	sum := sha256.Sum256([]byte(userAgent))
+	logins, err := s.limits.Hit(ctx, knownDeviceKeyPrefix+resp.User.ID+":"+hex.EncodeToString(sum[:16]), s.knownDeviceTTL)
+	if err != nil {
+		s.logger.Error().Err(err).Str("user", resp.User.ID).Msg("device of the login cannot be counted")
+		return resp, nil
+	}
+	if logins.Count == 1 {
+		s.notify(ctx, resp.User.ID, pushEventNewDeviceLogin, ports.PushNotification{
+			Title: "New sign-in to your account",
+			Body:  "Your account was signed in from a new device: " + userAgent,
+		})
+	}
+	return resp, nil
+}
+
+func (s *pushUserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) error {
+	if err := s.UserService.Update(ctx, ID, user); err != nil {
+		return err
+	}
+	if user.NewPassword != nil {
+		s.notify(ctx, ID, pushEventPasswordChanged, ports.PushNotification{
+			Title: "Password changed",
+			Body:  "The password of your account was changed",
+		})
+	}
+	return nil
+}
+
+func (s *pushUserService) Delete(ctx context.Context, ID string) error {
+	if err := s.UserService.Delete(ctx, ID); err != nil {
+		return err
+	}
+	if err := s.devices.DeleteByUser(ctx, ID); err != nil {
+		s.logger.Error().Err(err).Str("user", ID).Msg("devices of the deleted user cannot be deleted")
+	}
+	return nil
+}
+
+// notify sends the notification of the event to the devices of the user, logging the failures instead of returning them as the operation is already done
+func (s *pushUserService) notify(ctx context.Context, userID, event string, notification ports.PushNotification) {
+	notification.Data = map[string]string{
+		"event": event,
+		"at":    time.Now().UTC().Format(time.RFC3339),
+	}
+	notification.Category = entities.NotificationCategorySecurity
+	if err := s.devices.Notify(ctx, userID, notification); err != nil {
+		s.logger.Error().Err(err).Str("user", userID).Str("event", event).Msg("push notification cannot be sent")
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestPushLogin_NewDevice checks that Login notifies the user of the first login from the user agent of the request
+func TestPushLogin_NewDevice(t *testing.T) {
+	// Arrange
+	ctx := models.WithRequestInfo(context.Background(), models.RequestInfo{UserAgent: "test-agent"})
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	expectedResp := models.LoginUserResp{User: models.UserResp{ID: "test-user"}, Token: "test-token"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), ctx, credentials).Return(expectedResp, nil).Once()
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), ctx, mock.MatchedBy(func(key string) bool {
+		return len(key) == len("device:test-user:")+32
+	}), time.Hour).Return(entities.Limit{Count: 1}, nil).Once()
+	deviceServiceMock := mocks.NewDeviceService(t)
+	deviceServiceMock.On(testutils.FunctionName(t, ports.DeviceService.Notify), ctx, "test-user", mock.MatchedBy(func(n ports.PushNotification) bool {
+		return n.Data["event"] == pushEventNewDeviceLogin
+	})).Return(nil).Once()
+
+	service := NewPushUserService(userServiceMock, deviceServiceMock, limitStoreMock, zerolog.Nop(), time.Hour)
+
+	// Act
+	resp, err := service.Login(ctx, credentials)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResp, resp)
+}
+
+// TestPushLogin_KnownDevice checks that Login does not notify the user of the logins from a user agent already logged in from
+func TestPushLogin_KnownDevice(t *testing.T) {
+	// Arrange
+	ctx := models.WithRequestInfo(context.Background(), models.RequestInfo{UserAgent: "test-agent"})
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), ctx, credentials).Return(models.LoginUserResp{User: models.UserResp{ID: "test-user"}}, nil).Once()
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), ctx, mock.Anything, time.Hour).Return(entities.Limit{Count: 2}, nil).Once()
+
+	service := NewPushUserService(userServiceMock, mocks.NewDeviceService(t), limitStoreMock, zerolog.Nop(), time.Hour)
+
+	// Act
+	_, err := service.Login(ctx, credentials)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestPushUpdate_PasswordChanged checks that Update notifies the user once its password is changed
+func TestPushUpdate_PasswordChanged(t *testing.T) {
+	// Arrange
+	password := "new-password"
+	req := models.UpdateUserReq{NewPassword: &password}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), context.Background(), "test-user", req).Return(nil).Once()
+	deviceServiceMock := mocks.NewDeviceService(t)
+	deviceServiceMock.On(testutils.FunctionName(t, ports.DeviceService.Notify), context.Background(), "test-user", mock.MatchedBy(func(n ports.PushNotification) bool {
+		return n.Data["event"] == pushEventPasswordChanged
+	})).Return(nil).Once()
+
+	service := NewPushUserService(userServiceMock, deviceServiceMock, mocks.NewLimitStore(t), zerolog.Nop(), time.Hour)
+
+	// Act
+	err := service.Update(context.Background(), "test-user", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestPushDelete_Ok checks that Delete deletes the devices of the user deleted
+func TestPushDelete_Ok(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Delete), context.Background(), "test-user").Return(nil).Once()
+	deviceServiceMock := mocks.NewDeviceService(t)
+	deviceServiceMock.On(testutils.FunctionName(t, ports.DeviceService.DeleteByUser), context.Background(), "test-user").Return(nil).Once()
+
+	service := NewPushUserService(userServiceMock, deviceServiceMock, mocks.NewLimitStore(t), zerolog.Nop(), time.Hour)
+
+	// Act
+	err := service.Delete(context.Background(), "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+}
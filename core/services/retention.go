@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// retentionService adapter of a retention service, applying the retention policies of the config to the repositories of their collections
+type retentionService struct {
+	config       config.Config
+	logger       zerolog.Logger
+	repositories map[string]ports.RetentionRepository
+	audit        ports.AuditRepository
+}
+
+// NewRetentionService creates a new retention service with the repositories per collection name
+func NewRetentionService(cfg config.Config, logger zerolog.Logger, repos map[string]ports.RetentionRepository, audit ports.AuditRepository) ports.RetentionService {
+	return &retentionService{
+		config:       cfg,
+		logger:       logger,
+		repositories: repos,
+		audit:        audit,
+	}
+}
+
+// Apply purges or anonymizes, as every retention policy says, the documents older than its maximum age, or only counts them in dry run.
+// A failing policy is reported in its result without stopping the others.
+func (s *retentionService) Apply(ctx context.Context, dryRun bool) (resp models.RetentionResp, err error) {
+	if err = s.validatePolicies(); err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	resp.DryRun = dryRun
+	resp.Results = make([]models.RetentionResultResp, 0, len(s.config.Retention.Policies))
+	details := map[string]string{}
+	for _, p := range s.config.Retention.Policies {
+		result := models.RetentionResultResp{
+			Collection: p.Collection,
+			Action:     entities.RetentionAction(p.Action),
+			Before:     now.Add(-p.MaxAge.Duration),
+		}
+
+		count, expireErr := s.repositories[p.Collection].Expire(ctx, result.Action, result.Before, dryRun)
+		if expireErr != nil {
+			result.Error = expireErr.Error()
+		} else {
+			result.Count = count
+			details[p.Collection+"."+p.Action] = strconv.FormatInt(count, 10)
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if !dryRun {
+		record(ctx, s.logger, s.audit, entities.AuditRetentionApplied, "", details)
+	}
+	return
+}
+
+func (s *retentionService) validatePolicies() error {
+	for _, p := range s.config.Retention.Policies {
+		if _, ok := s.repositories[p.Collection]; !ok {
+			return wrappers.NewValidationErr(fmt.Errorf("retention of collection %s not supported", p.Collection))
+		}
+		if !entities.RetentionAction(p.Action).IsValid() {
+			return wrappers.NewValidationErr(fmt.Errorf("retention action %s of collection %s not valid", p.Action, p.Collection))
+		}
+		if p.MaxAge.Duration <= 0 {
+			return wrappers.NewValidationErr(fmt.Errorf("retention maximum age of collection %s must be positive", p.Collection))
+		}
+	}
+	return nil
+}
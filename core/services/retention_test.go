@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func retentionConfig(policies ...config.RetentionPolicy) config.Config {
+	cfg := config.Config{}
+	cfg.Retention.Policies = policies
+	return cfg
+}
+
+// TestNewRetentionService_Ok checks that NewRetentionService creates a new retentionService struct
+func TestNewRetentionService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	repos := map[string]ports.RetentionRepository{entities.EntityNameJob: mocks.NewJobRepository(t)}
+
+	// Act
+	service := NewRetentionService(cfg, zerolog.Nop(), repos, mocks.NewAuditRepository(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestApply_Ok checks that Apply expires the documents older than the maximum age of every policy and records it in the audit log
+func TestApply_Ok(t *testing.T) {
+	// Arrange
+	cfg := retentionConfig(
+		config.RetentionPolicy{Collection: entities.EntityNameJob, Action: "purge", MaxAge: utils.Duration{Duration: time.Hour}},
+		config.RetentionPolicy{Collection: entities.EntityNameUserArchive, Action: "anonymize", MaxAge: utils.Duration{Duration: 24 * time.Hour}},
+	)
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Expire), context.Background(), entities.RetentionPurge, mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= time.Hour && time.Since(before) < time.Hour+time.Minute
+	}), false).Return(int64(2), nil).Once()
+	userArchiveRepositoryMock := mocks.NewRetentionRepository(t)
+	userArchiveRepositoryMock.On(testutils.FunctionName(t, ports.RetentionRepository.Expire), context.Background(), entities.RetentionAnonymize, mock.AnythingOfType("time.Time"), false).Return(int64(3), nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(event entities.AuditEvent) bool {
+		return event.Type == entities.AuditRetentionApplied && event.Details["jobs.purge"] == "2" && event.Details["users_archive.anonymize"] == "3"
+	})).Return(nil).Once()
+
+	service := &retentionService{
+		config: cfg,
+		repositories: map[string]ports.RetentionRepository{
+			entities.EntityNameJob:         jobRepositoryMock,
+			entities.EntityNameUserArchive: userArchiveRepositoryMock,
+		},
+		audit: auditRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Apply(context.Background(), false)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, resp.DryRun)
+	assert.Len(t, resp.Results, 2)
+	assert.Equal(t, int64(2), resp.Results[0].Count)
+	assert.Equal(t, entities.RetentionAnonymize, resp.Results[1].Action)
+	assert.Equal(t, int64(3), resp.Results[1].Count)
+}
+
+// TestApply_DryRun checks that Apply only counts the documents in dry run, without recording it in the audit log
+func TestApply_DryRun(t *testing.T) {
+	// Arrange
+	cfg := retentionConfig(config.RetentionPolicy{Collection: entities.EntityNameJob, Action: "purge", MaxAge: utils.Duration{Duration: time.Hour}})
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Expire), context.Background(), entities.RetentionPurge, mock.AnythingOfType("time.Time"), true).Return(int64(5), nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+
+	service := &retentionService{
+		config:       cfg,
+		repositories: map[string]ports.RetentionRepository{entities.EntityNameJob: jobRepositoryMock},
+		audit:        auditRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Apply(context.Background(), true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, int64(5), resp.Results[0].Count)
+	auditRepositoryMock.AssertNotCalled(t, testutils.FunctionName(t, ports.AuditRepository.Write), mock.Anything, mock.Anything)
+}
+
+// TestApply_ExpireError checks that Apply reports a failing policy in its result and goes on with the others
+func TestApply_ExpireError(t *testing.T) {
+	// Arrange
+	cfg := retentionConfig(
+		config.RetentionPolicy{Collection: entities.EntityNameAuditEvent, Action: "purge", MaxAge: utils.Duration{Duration: time.Hour}},
+		config.RetentionPolicy{Collection: entities.EntityNameJob, Action: "purge", MaxAge: utils.Duration{Duration: time.Hour}},
+	)
+	expectedError := "expire error"
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Expire), context.Background(), entities.RetentionPurge, mock.AnythingOfType("time.Time"), true).Return(int64(0), errors.New(expectedError)).Once()
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.Expire), context.Background(), entities.RetentionPurge, mock.AnythingOfType("time.Time"), true).Return(int64(1), nil).Once()
+
+	service := &retentionService{
+		config: cfg,
+		repositories: map[string]ports.RetentionRepository{
+			entities.EntityNameAuditEvent: auditRepositoryMock,
+			entities.EntityNameJob:        jobRepositoryMock,
+		},
+		audit: auditRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Apply(context.Background(), true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedError, resp.Results[0].Error)
+	assert.Equal(t, int64(1), resp.Results[1].Count)
+}
+
+// TestApply_NotSupportedCollection checks that Apply returns a validation error, without expiring anything, when the collection of a policy is not supported
+func TestApply_NotSupportedCollection(t *testing.T) {
+	// Arrange
+	expectedError := "retention of collection sessions not supported"
+	service := &retentionService{
+		config:       retentionConfig(config.RetentionPolicy{Collection: "sessions", Action: "purge", MaxAge: utils.Duration{Duration: time.Hour}}),
+		repositories: map[string]ports.RetentionRepository{entities.EntityNameJob: mocks.NewJobRepository(t)},
+	}
+
+	// Act
+	_, err := service.Apply(context.Background(), true)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestApply_InvalidAction checks that Apply returns a validation error, without expiring anything, when the action of a policy is not valid
+func TestApply_InvalidAction(t *testing.T) {
+	// Arrange
+	expectedError := "retention action archive of collection jobs not valid"
+	service := &retentionService{
+		config:       retentionConfig(config.RetentionPolicy{Collection: entities.EntityNameJob, Action: "archive", MaxAge: utils.Duration{Duration: time.Hour}}),
+		repositories: map[string]ports.RetentionRepository{entities.EntityNameJob: mocks.NewJobRepository(t)},
+	}
+
+	// Act
+	_, err := service.Apply(context.Background(), true)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestApply_ZeroMaxAge checks that Apply returns a validation error, without expiring anything, when the maximum age of a policy is not positive
+func TestApply_ZeroMaxAge(t *testing.T) {
+	// Arrange
+	expectedError := "retention maximum age of collection jobs must be positive"
+	service := &retentionService{
+		config:       retentionConfig(config.RetentionPolicy{Collection: entities.EntityNameJob, Action: "purge"}),
+		repositories: map[string]ports.RetentionRepository{entities.EntityNameJob: mocks.NewJobRepository(t)},
+	}
+
+	// Act
+	_, err := service.Apply(context.Background(), true)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/mapping"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// roleService adapter of the role service
+type roleService struct {
+	repository    ports.RoleRepository
+	users         ports.UserService
+	organizations ports.OrganizationRepository
+}
+
+// NewRoleService creates a new role service, checking that the users and organizations the roles are assigned to exist
+func NewRoleService(repository ports.RoleRepository, users ports.UserService, organizations ports.OrganizationRepository) ports.RoleService {
+	return &roleService{
+		repository:    repository,
+		users:         users,
+		organizations: organizations,
+	}
+}
+
+// Create a role, not assigned to anyone yet
+func (s *roleService) Create(ctx context.Context, req models.CreateRoleReq) (resp models.CreationResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	role := entities.Role{
+		Name:            req.Name,
+		Description:     req.Description,
+		Permissions:     req.Permissions,
+		UserIDs:         []string{},
+		OrganizationIDs: []string{},
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+	insertedID, err := s.repository.Create(ctx, role)
+	if err != nil {
+		return
+	}
+
+	resp = models.CreationResp{
+		InsertedID: insertedID,
+	}
+	return
+}
+
+// GetAll returns every role
+func (s *roleService) GetAll(ctx context.Context) ([]models.RoleResp, error) {
+	result, err := s.repository.Get(ctx, map[string]interface{}{}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return nil, err
+	}
+
+	resp := make([]models.RoleResp, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, mapping.RoleResp(*r.(*entities.Role)))
+	}
+	return resp, nil
+}
+
+// GetByID returns a role
+func (s *roleService) GetByID(ctx context.Context, ID string) (models.RoleResp, error) {
+	result, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = apierror.NotFound(fmt.Errorf("role ID %s not found", ID))
+		}
+		return models.RoleResp{}, err
+	}
+	return mapping.RoleResp(*result.(*entities.Role)), nil
+}
+
+// Update a role, setting only its fields so its assignees are left as they are
+func (s *roleService) Update(ctx context.Context, ID string, req models.UpdateRoleReq) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	fields := map[string]interface{}{"updated_at": time.Now().UTC()}
+	if req.Name != nil {
+		fields["name"] = *req.Name
+	}
+	if req.Description != nil {
+		fields["description"] = *req.Description
+	}
+	if req.Permissions != nil {
+		fields["permissions"] = *req.Permissions
+	}
+	err = s.repository.Update(ctx, ID, fields)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("role ID %s not found", ID))
+	}
+	return
+}
+
+// Delete a role, unassigning it from everyone
+func (s *roleService) Delete(ctx context.Context, ID string) (err error) {
+	err = s.repository.Delete(ctx, ID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("role ID %s not found", ID))
+	}
+	return
+}
+
+// Assign a role to a user or to an organization, granting it to its members, once they log in again
+func (s *roleService) Assign(ctx context.Context, ID, kind, assigneeID string) (err error) {
+	switch kind {
+	case entities.RoleAssigneeUser:
+		_, err = s.users.GetByID(ctx, assigneeID)
+	case entities.RoleAssigneeOrganization:
+		_, err = s.organizations.GetByID(ctx, assigneeID)
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = apierror.NotFound(fmt.Errorf("organization ID %s not found", assigneeID))
+		}
+	default:
+		err = apierror.Validation("kind", fmt.Sprintf("unknown kind of assignee %s", kind))
+	}
+	if err != nil {
+		return
+	}
+
+	err = s.repository.Assign(ctx, ID, kind, assigneeID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("role ID %s not found", ID))
+	}
+	return
+}
+
+// Unassign a role from a user or an organization
+func (s *roleService) Unassign(ctx context.Context, ID, kind, assigneeID string) (err error) {
+	err = s.repository.Unassign(ctx, ID, kind, assigneeID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(fmt.Errorf("role ID %s not assigned to %s ID %s", ID, kind, assigneeID))
+	}
+	return
+}
+
+// roleUserService decorator of an user service that signs the roles of the users into their tokens
+type roleUserService struct {
+	ports.UserService
+	roles         ports.RoleRepository
+	organizations ports.OrganizationRepository
+	keys          ports.TokenKeys
+	issuer        string
+	logger        zerolog.Logger
+}
+
+// NewRoleUserService wraps a user service adding the roles of the users and of their organizations to the tokens of their logins
+func NewRoleUserService(service ports.UserService, roles ports.RoleRepository, organizations ports.OrganizationRepository, keys ports.TokenKeys, issuer string, logger zerolog.Logger) ports.UserService {
+	return &roleUserService{
+		UserService:   service,
+		roles:         roles,
+		organizations: organizations,
+		keys:          keys,
+		issuer:        issuer,
+		logger:        logger,
+	}
+}
+
+// Login user, signing its roles into its token when they can be looked up
+func (s *roleUserService) Login(ctx context.Context, credentials models.LoginUserReq) (models.LoginUserResp, error) {
+	resp, err := s.UserService.Login(ctx, credentials)
+	if err != nil {
+		return resp, err
+	}
+
+	roles, err := s.userRoles(ctx, resp.User.ID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("user", resp.User.ID).Msg("roles of the user cannot be looked up, the token is signed without them")
+		return resp, nil
+	}
+	if len(roles) == 0 {
+		return resp, nil
+	}
+
+	token, err := createToken(s.keys, s.issuer, resp.User.ID, resp.User.Claims, resp.ExpiresAt, roles...)
+	if err != nil {
+		return models.LoginUserResp{}, err
+	}
+	resp.Token = token
+	return resp, nil
+}
+
+// userRoles returns the roles assigned to the user or to the organizations it is a member of
+func (s *roleUserService) userRoles(ctx context.Context, userID string) ([]entities.Role, error) {
+	var organizationIDs []string
+	if s.organizations != nil {
+		organizations, err := s.organizations.GetByMember(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, organization := range organizations {
+			organizationIDs = append(organizationIDs, organization.ID)
+		}
+	}
+	return s.roles.GetByAssignees(ctx, userID, organizationIDs)
+}
+
+func (s *roleUserService) Delete(ctx context.Context, ID string) error {
+	if err := s.UserService.Delete(ctx, ID); err != nil {
+		return err
+	}
+	if err := s.roles.RemoveAssignee(ctx, entities.RoleAssigneeUser, ID); err != nil {
+		s.logger.Error().Err(err).Str("user", ID).Msg("roles of the deleted user cannot be unassigned")
+	}
+	return nil
+}
+
+// roleOrganizationService decorator of an organization service that unassigns the roles of the organizations deleted
+type roleOrganizationService struct {
+	ports.OrganizationService
+	roles  ports.RoleRepository
+	logger zerolog.Logger
+}
+
+// NewRoleOrganizationService wraps an organization service unassigning the roles of the organizations deleted
+func NewRoleOrganizationService(service ports.OrganizationService, roles ports.RoleRepository, logger zerolog.Logger) ports.OrganizationService {
+	return &roleOrganizationService{
+		OrganizationService: service,
+		roles:               roles,
+		logger:              logger,
+	}
+}
+
+func (s *roleOrganizationService) Delete(ctx context.Context, caller models.Caller, ID string) error {
+	if err := s.OrganizationService.Delete(ctx, caller, ID); err != nil {
+		return err
+	}
+	if err := s.roles.RemoveAssignee(ctx, entities.RoleAssigneeOrganization, ID); err != nil {
+		s.logger.Error().Err(err).Str("organization", ID).Msg("roles of the deleted organization cannot be unassigned")
+	}
+	return nil
+}
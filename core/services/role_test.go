@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreateRole_Ok checks that Create creates the role with its permissions and without assignees
+func TestCreateRole_Ok(t *testing.T) {
+	// Arrange
+	roleRepositoryMock := mocks.NewRoleRepository(t)
+	roleRepositoryMock.On(testutils.FunctionName(t, ports.RoleRepository.Create), context.Background(), mock.MatchedBy(func(role entities.Role) bool {
+		return role.Name == "test-role" && len(role.Permissions) == 1 && role.Permissions[0] == "admin" && len(role.UserIDs) == 0 && role.OrganizationIDs != nil
+	})).Return("test-role-id", nil).Once()
+
+	service := NewRoleService(roleRepositoryMock, mocks.NewUserService(t), mocks.NewOrganizationRepository(t))
+
+	// Act
+	resp, err := service.Create(context.Background(), models.CreateRoleReq{Name: "test-role", Permissions: []string{"admin"}})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-role-id", resp.InsertedID)
+}
+
+// TestCreateRole_UnknownPermission checks that Create returns a validation error when a permission is not the name of a claim
+func TestCreateRole_UnknownPermission(t *testing.T) {
+	// Arrange
+	service := NewRoleService(mocks.NewRoleRepository(t), mocks.NewUserService(t), mocks.NewOrganizationRepository(t))
+
+	// Act
+	_, err := service.Create(context.Background(), models.CreateRoleReq{Name: "test-role", Permissions: []string{"unknown"}})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrValidation)
+}
+
+// TestGetRoleByID_NotFound checks that GetByID returns a not found error when the role is not found
+func TestGetRoleByID_NotFound(t *testing.T) {
+	// Arrange
+	roleRepositoryMock := mocks.NewRoleRepository(t)
+	roleRepositoryMock.On(testutils.FunctionName(t, ports.RoleRepository.GetByID), context.Background(), "test-role-id").Return(nil, wrappers.NewNonExistentErr(assert.AnError)).Once()
+
+	service := NewRoleService(roleRepositoryMock, mocks.NewUserService(t), mocks.NewOrganizationRepository(t))
+
+	// Act
+	_, err := service.GetByID(context.Background(), "test-role-id")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+// TestUpdateRole_Ok checks that Update only sets the fields of the request
+func TestUpdateRole_Ok(t *testing.T) {
+	// Arrange
+	description := "test description"
+	roleRepositoryMock := mocks.NewRoleRepository(t)
+	roleRepositoryMock.On(testutils.FunctionName(t, ports.RoleRepository.Update), context.Background(), "test-role-id", mock.MatchedBy(func(fields map[string]interface{}) bool {
+		_, name := fields["name"]
+		_, permissions := fields["permissions"]
+		return fields["description"] == description && !name && !permissions
+	})).Return(nil).Once()
+
+	service := NewRoleService(roleRepositoryMock, mocks.NewUserService(t), mocks.NewOrganizationRepository(t))
+
+	// Act
+	err := service.Update(context.Background(), "test-role-id", models.UpdateRoleReq{Description: &description})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestAssignRole_User checks that Assign assigns the role to the user once found
+func TestAssignRole_User(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-user").Return(models.UserResp{ID: "test-user"}, nil).Once()
+	roleRepositoryMock := mocks.NewRoleRepository(t)
+	roleRepositoryMock.On(testutils.FunctionName(t, ports.RoleRepository.Assign), context.Background(), "test-role-id", entities.RoleAssigneeUser, "test-user").Return(nil).Once()
+
+	service := NewRoleService(roleRepositoryMock, userServiceMock, mocks.NewOrganizationRepository(t))
+
+	// Act
+	err := service.Assign(context.Background(), "test-role-id", entities.RoleAssigneeUser, "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestAssignRole_OrganizationNotFound checks that Assign returns a not found error when the organization is not found
+func TestAssignRole_OrganizationNotFound(t *testing.T) {
+	// Arrange
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByID), context.Background(), "test-organization").Return(nil, wrappers.NewNonExistentErr(assert.AnError)).Once()
+
+	service := NewRoleService(mocks.NewRoleRepository(t), mocks.NewUserService(t), organizationRepositoryMock)
+
+	// Act
+	err := service.Assign(context.Background(), "test-role-id", entities.RoleAssigneeOrganization, "test-organization")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+// TestUnassignRole_NotAssigned checks that Unassign returns a not found error when the role is not assigned to the assignee
+func TestUnassignRole_NotAssigned(t *testing.T) {
+	// Arrange
+	roleRepositoryMock := mocks.NewRoleRepository(t)
+	roleRepositoryMock.On(testutils.FunctionName(t, ports.RoleRepository.Unassign), context.Background(), "test-role-id", entities.RoleAssigneeUser, "test-user").Return(wrappers.NewNonExistentErr(assert.AnError)).Once()
+
+	service := NewRoleService(roleRepositoryMock, mocks.NewUserService(t), mocks.NewOrganizationRepository(t))
+
+	// Act
+	err := service.Unassign(context.Background(), "test-role-id", entities.RoleAssigneeUser, "test-user")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+// TestLoginRoleUser_Ok checks that the decorated Login signs the token again with the roles of the user and of its organizations,
+// granting their permissions
+func TestLoginRoleUser_Ok(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{Email: "test@test.com", Password: "test"}
+	expiresAt := time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC)
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), context.Background(), req).Return(models.LoginUserResp{User: models.UserResp{ID: "test-user"}, Token: "test-token", ExpiresAt: expiresAt}, nil).Once()
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByMember), context.Background(), "test-user").Return([]entities.Organization{{ID: "test-organization"}}, nil).Once()
+	roleRepositoryMock := mocks.NewRoleRepository(t)
+	roleRepositoryMock.On(testutils.FunctionName(t, ports.RoleRepository.GetByAssignees), context.Background(), "test-user", []string{"test-organization"}).Return([]entities.Role{{Name: "test-role", Permissions: []string{"admin"}}}, nil).Once()
+	tokenKeysMock := mocks.NewTokenKeys(t)
+	tokenKeysMock.On(testutils.FunctionName(t, ports.TokenKeys.Sign), mock.MatchedBy(func(claims jwt.MapClaims) bool {
+		roles, _ := claims["roles"].([]string)
+		return claims["admin"] == true && len(roles) == 1 && roles[0] == "test-role" && claims["exp"] == expiresAt.Unix()
+	})).Return("test-role-token", nil).Once()
+
+	service := NewRoleUserService(userServiceMock, roleRepositoryMock, organizationRepositoryMock, tokenKeysMock, "", zerolog.Nop())
+
+	// Act
+	resp, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-role-token", resp.Token)
+}
+
+// TestLoginRoleUser_NoRoles checks that the decorated Login keeps the token of the login when the user has no roles
+func TestLoginRoleUser_NoRoles(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{Email: "test@test.com", Password: "test"}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), context.Background(), req).Return(models.LoginUserResp{User: models.UserResp{ID: "test-user"}, Token: "test-token"}, nil).Once()
+	roleRepositoryMock := mocks.NewRoleRepository(t)
+	roleRepositoryMock.On(testutils.FunctionName(t, ports.RoleRepository.GetByAssignees), context.Background(), "test-user", []string(nil)).Return([]entities.Role{}, nil).Once()
+
+	service := NewRoleUserService(userServiceMock, roleRepositoryMock, nil, mocks.NewTokenKeys(t), "", zerolog.Nop())
+
+	// Act
+	resp, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-token", resp.Token)
+}
+
+// TestDeleteRoleOrganization_Ok checks that the decorated Delete unassigns the roles of the organization deleted, not failing when it cannot
+func TestDeleteRoleOrganization_Ok(t *testing.T) {
+	// Arrange
+	caller := models.Caller{UserID: "test-owner"}
+	organizationServiceMock := mocks.NewOrganizationService(t)
+	organizationServiceMock.On(testutils.FunctionName(t, ports.OrganizationService.Delete), context.Background(), caller, "test-organization").Return(nil).Once()
+	roleRepositoryMock := mocks.NewRoleRepository(t)
+	roleRepositoryMock.On(testutils.FunctionName(t, ports.RoleRepository.RemoveAssignee), context.Background(), entities.RoleAssigneeOrganization, "test-organization").Return(assert.AnError).Once()
+
+	service := NewRoleOrganizationService(organizationServiceMock, roleRepositoryMock, zerolog.Nop())
+
+	// Act
+	err := service.Delete(context.Background(), caller, "test-organization")
+
+	// Assert
+	assert.Nil(t, err)
+}
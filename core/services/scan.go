@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// quarantineKeyPrefix prefix of the keys of the uploads stored until they are scanned
+const quarantineKeyPrefix = "quarantine/"
+
+// scanStatusPending value of the scan metadata of the uploads stored in quarantine
+const scanStatusPending = "pending"
+
+// scanService adapter of the scan service, moving the uploads out of the quarantine once the scanner finds them clean
+type scanService struct {
+	logger     zerolog.Logger
+	storage    ports.FileStorage
+	scanner    ports.MalwareScanner
+	repository ports.UserRepository
+	audit      ports.AuditRepository
+}
+
+// NewScanService creates a new scan service.
+// The users are read from the repository, so the upload of a user deleted while it was scanned is discarded instead of stored.
+func NewScanService(logger zerolog.Logger, storage ports.FileStorage, scanner ports.MalwareScanner, repository ports.UserRepository, audit ports.AuditRepository) ports.ScanService {
+	return &scanService{
+		logger:     logger,
+		storage:    storage,
+		scanner:    scanner,
+		repository: repository,
+		audit:      audit,
+	}
+}
+
+// Process scans the upload quarantined by a scan job, storing it at its key when clean and deleting it when infected,
+// recording the infected ones to the audit log. A failed scan fails the job, so the upload is scanned again on its next attempt.
+func (s *scanService) Process(ctx context.Context, job *entities.Job) error {
+	userID, key, quarantineKey := job.Metadata["user_id"], job.Metadata["key"], job.Metadata["quarantine_key"]
+
+	content, file, err := s.storage.Open(ctx, quarantineKey)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	signature, err := s.scanner.Scan(ctx, content)
+	content.Close()
+	if err != nil {
+		return fmt.Errorf("upload %s cannot be scanned: %w", quarantineKey, err)
+	}
+
+	if signature != "" {
+		s.logger.Warn().Str("user", userID).Str("key", key).Str("malware", signature).Msg("infected upload deleted")
+		record(ctx, s.logger, s.audit, entities.AuditUploadInfected, userID, map[string]string{"key": key, "malware": signature})
+		return s.discard(ctx, quarantineKey)
+	}
+
+	if _, err = s.repository.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return s.discard(ctx, quarantineKey)
+		}
+		return err
+	}
+
+	content, file, err = s.storage.Open(ctx, quarantineKey)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	metadata := make(map[string]string, len(file.Metadata))
+	for k, v := range file.Metadata {
+		if k != "scan" {
+			metadata[k] = v
+		}
+	}
+	_, err = s.storage.Store(ctx, entities.File{Key: key, Name: file.Name, ContentType: file.ContentType, Metadata: metadata}, content)
+	if err != nil {
+		return err
+	}
+	return s.discard(ctx, quarantineKey)
+}
+
+// discard deletes an upload from the quarantine, unless it was already deleted
+func (s *scanService) discard(ctx context.Context, quarantineKey string) error {
+	err := s.storage.Delete(ctx, quarantineKey)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return nil
+	}
+	return err
+}
+
+// scanUserService decorator of an user service that quarantines the avatars until scanned
+type scanUserService struct {
+	ports.UserService
+	storage ports.FileStorage
+	jobs    ports.JobService
+	logger  zerolog.Logger
+}
+
+// NewScanUserService wraps a user service storing the avatars in quarantine and queuing their scan
+func NewScanUserService(service ports.UserService, storage ports.FileStorage, jobs ports.JobService, logger zerolog.Logger) ports.UserService {
+	return &scanUserService{
+		UserService: service,
+		storage:     storage,
+		jobs:        jobs,
+		logger:      logger,
+	}
+}
+
+func (s *scanUserService) UploadAvatar(ctx context.Context, ID string, avatar models.UploadAvatarReq) (resp models.FileResp, err error) {
+	if err = avatar.Validate(); err != nil {
+		return
+	}
+
+	_, err = s.UserService.GetByID(ctx, ID)
+	if err != nil {
+		return
+	}
+
+	file := entities.File{
+		Key:         quarantineKeyPrefix + avatarKey(ID),
+		Name:        avatar.Name,
+		ContentType: avatar.ContentType,
+		Metadata:    map[string]string{"user_id": ID, "scan": scanStatusPending},
+	}
+	file, err = s.storage.Store(ctx, file, avatar.Content)
+	if err != nil {
+		return
+	}
+
+	_, err = s.jobs.Enqueue(ctx, entities.JobTypeScan, map[string]string{"user_id": ID, "key": avatarKey(ID), "quarantine_key": file.Key})
+	if err != nil {
+		s.logger.Error().Err(err).Str("user", ID).Msg("avatar scan cannot be queued")
+		if deleteErr := s.storage.Delete(ctx, file.Key); deleteErr != nil {
+			s.logger.Error().Err(deleteErr).Str("key", file.Key).Msg("quarantined avatar cannot be deleted")
+		}
+		return
+	}
+
+	resp = models.FileResp(file)
+	return
+}
+
+func (s *scanUserService) CreateAvatarUploadURL(ctx context.Context, ID string, req models.AvatarUploadURLReq) (models.PresignedURLResp, error) {
+	return models.PresignedURLResp{}, wrappers.NewValidationErr(errors.New("direct uploads not supported while the uploads are scanned"))
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// scanJob returns the scan job of the avatar of the test user
+func scanJob() *entities.Job {
+	return &entities.Job{Type: entities.JobTypeScan, Metadata: map[string]string{"user_id": "test-id", "key": "avatars/test-id", "quarantine_key": "quarantine/avatars/test-id"}}
+}
+
+// TestProcessScan_Clean checks that Process stores a clean upload at its key without its scan metadata, deleting it from the quarantine
+func TestProcessScan_Clean(t *testing.T) {
+	// Arrange
+	quarantined := entities.File{Key: "quarantine/avatars/test-id", Name: "avatar.png", ContentType: "image/png", Metadata: map[string]string{"user_id": "test-id", "scan": "pending"}}
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Open), context.Background(), quarantined.Key).Return(io.NopCloser(strings.NewReader("content")), quarantined, nil).Twice()
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Store), context.Background(), entities.File{Key: "avatars/test-id", Name: "avatar.png", ContentType: "image/png", Metadata: map[string]string{"user_id": "test-id"}}, mock.Anything).Return(entities.File{}, nil).Once()
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Delete), context.Background(), quarantined.Key).Return(nil).Once()
+	scannerMock := mocks.NewMalwareScanner(t)
+	scannerMock.On(testutils.FunctionName(t, ports.MalwareScanner.Scan), context.Background(), mock.Anything).Return("", nil).Once()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(&entities.User{ID: "test-id"}, nil).Once()
+
+	service := NewScanService(zerolog.Nop(), fileStorageMock, scannerMock, userRepositoryMock, nil)
+
+	// Act
+	err := service.Process(context.Background(), scanJob())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestProcessScan_Infected checks that Process deletes an infected upload from the quarantine, recording it to the audit log
+func TestProcessScan_Infected(t *testing.T) {
+	// Arrange
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Open), context.Background(), "quarantine/avatars/test-id").Return(io.NopCloser(strings.NewReader("content")), entities.File{}, nil).Once()
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Delete), context.Background(), "quarantine/avatars/test-id").Return(nil).Once()
+	scannerMock := mocks.NewMalwareScanner(t)
+	scannerMock.On(testutils.FunctionName(t, ports.MalwareScanner.Scan), context.Background(), mock.Anything).Return("Eicar-Signature", nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(e entities.AuditEvent) bool {
+		return e.Type == entities.AuditUploadInfected && e.UserID == "test-id" && e.Details["malware"] == "Eicar-Signature"
+	})).Return(nil).Once()
+
+	service := NewScanService(zerolog.Nop(), fileStorageMock, scannerMock, mocks.NewUserRepository(t), auditRepositoryMock)
+
+	// Act
+	err := service.Process(context.Background(), scanJob())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestProcessScan_ScannerError checks that Process fails the job when the upload cannot be scanned, keeping it in quarantine
+func TestProcessScan_ScannerError(t *testing.T) {
+	// Arrange
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Open), context.Background(), "quarantine/avatars/test-id").Return(io.NopCloser(strings.NewReader("content")), entities.File{}, nil).Once()
+	scannerMock := mocks.NewMalwareScanner(t)
+	scannerMock.On(testutils.FunctionName(t, ports.MalwareScanner.Scan), context.Background(), mock.Anything).Return("", errors.New("scanner error")).Once()
+
+	service := NewScanService(zerolog.Nop(), fileStorageMock, scannerMock, mocks.NewUserRepository(t), nil)
+
+	// Act
+	err := service.Process(context.Background(), scanJob())
+
+	// Assert
+	assert.Equal(t, "upload quarantine/avatars/test-id cannot be scanned: scanner error", err.Error())
+}
+
+// TestProcessScan_UserDeleted checks that Process discards a clean upload of a user deleted meanwhile
+func TestProcessScan_UserDeleted(t *testing.T) {
+	// Arrange
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Open), context.Background(), "quarantine/avatars/test-id").Return(io.NopCloser(strings.NewReader("content")), entities.File{}, nil).Once()
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Delete), context.Background(), "quarantine/avatars/test-id").Return(nil).Once()
+	scannerMock := mocks.NewMalwareScanner(t)
+	scannerMock.On(testutils.FunctionName(t, ports.MalwareScanner.Scan), context.Background(), mock.Anything).Return("", nil).Once()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(nil, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := NewScanService(zerolog.Nop(), fileStorageMock, scannerMock, userRepositoryMock, nil)
+
+	// Act
+	err := service.Process(context.Background(), scanJob())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestScanUploadAvatar_Quarantined checks that UploadAvatar stores the avatar in quarantine and queues its scan
+func TestScanUploadAvatar_Quarantined(t *testing.T) {
+	// Arrange
+	quarantined := entities.File{Key: "quarantine/avatars/test-id", Name: "avatar.png", ContentType: "image/png", Metadata: map[string]string{"user_id": "test-id", "scan": "pending"}}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-id").Return(models.UserResp{ID: "test-id"}, nil).Once()
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Store), context.Background(), quarantined, mock.Anything).Return(quarantined, nil).Once()
+	jobServiceMock := mocks.NewJobService(t)
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Enqueue), context.Background(), entities.JobTypeScan, scanJob().Metadata).Return(models.JobResp{}, nil).Once()
+
+	service := NewScanUserService(userServiceMock, fileStorageMock, jobServiceMock, zerolog.Nop())
+
+	// Act
+	resp, err := service.UploadAvatar(context.Background(), "test-id", models.UploadAvatarReq{Name: "avatar.png", ContentType: "image/png", Content: strings.NewReader("content")})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.FileResp(quarantined), resp)
+}
+
+// TestScanUploadAvatar_EnqueueError checks that UploadAvatar deletes the quarantined avatar when its scan cannot be queued
+func TestScanUploadAvatar_EnqueueError(t *testing.T) {
+	// Arrange
+	expectedError := errors.New("queue error")
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), context.Background(), "test-id").Return(models.UserResp{ID: "test-id"}, nil).Once()
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Store), context.Background(), mock.Anything, mock.Anything).Return(entities.File{Key: "quarantine/avatars/test-id"}, nil).Once()
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Delete), context.Background(), "quarantine/avatars/test-id").Return(nil).Once()
+	jobServiceMock := mocks.NewJobService(t)
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Enqueue), context.Background(), entities.JobTypeScan, mock.Anything).Return(models.JobResp{}, expectedError).Once()
+
+	service := NewScanUserService(userServiceMock, fileStorageMock, jobServiceMock, zerolog.Nop())
+
+	// Act
+	_, err := service.UploadAvatar(context.Background(), "test-id", models.UploadAvatarReq{Name: "avatar.png", ContentType: "image/png", Content: strings.NewReader("content")})
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
+
+// TestScanCreateAvatarUploadURL_NotSupported checks that CreateAvatarUploadURL returns a validation error, as the direct uploads cannot be scanned
+func TestScanCreateAvatarUploadURL_NotSupported(t *testing.T) {
+	// Arrange
+	service := NewScanUserService(mocks.NewUserService(t), mocks.NewFileStorage(t), mocks.NewJobService(t), zerolog.Nop())
+
+	// Act
+	_, err := service.CreateAvatarUploadURL(context.Background(), "test-id", models.AvatarUploadURLReq{Name: "avatar.png", ContentType: "image/png", Size: 10})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
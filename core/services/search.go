@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// searchService adapter of the search service, mirroring the users into a search index and searching them there
+type searchService struct {
+	logger     zerolog.Logger
+	repository ports.UserRepository
+	index      ports.SearchIndex
+	stream     ports.UserChangeStream
+
+	// organizations the repository of the organizations scoping the searches, only set when the organizations are stored
+	organizations ports.OrganizationRepository
+}
+
+// NewSearchService creates a new search service.
+// The users changed are read again from the repository rather than from the changes, so the ones stored encrypted are indexed decrypted
+// and a user changed several times is indexed as it is when read.
+// When the organizations are stored, the users not admin only find themselves and the members of their organizations.
+func NewSearchService(logger zerolog.Logger, repository ports.UserRepository, index ports.SearchIndex, stream ports.UserChangeStream, organizations ports.OrganizationRepository) ports.SearchService {
+	return &searchService{
+		logger:        logger,
+		repository:    repository,
+		index:         index,
+		stream:        stream,
+		organizations: organizations,
+	}
+}
+
+// Sync mirrors the users into the index as they change, indexing all of them first on the first sync
+func (s *searchService) Sync(ctx context.Context) error {
+	return s.stream.Watch(ctx, s.backfill, s.apply)
+}
+
+// Search searches the users in the index by relevance, returning the documents indexed rather than the users stored.
+// The users the caller is not allowed to are filtered by the index, so they are not counted either.
+func (s *searchService) Search(ctx context.Context, caller models.Caller, req models.IndexSearchUsersReq) (resp models.IndexSearchUsersResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	query := ports.SearchQuery{
+		Text:   req.Query,
+		Domain: req.Domain,
+		Claim:  req.Claim,
+		Skip:   req.Skip,
+		Take:   req.Take,
+	}
+	if s.organizations != nil && !caller.Admin {
+		if query.IDs, err = sharingUserIDs(ctx, s.organizations, caller.UserID); err != nil {
+			return
+		}
+	}
+
+	result, err := s.index.Search(ctx, query)
+	if err != nil {
+		return
+	}
+
+	resp.Total = result.Total
+	resp.Users = make([]models.UserResp, len(result.Documents))
+	for i, document := range result.Documents {
+		resp.Users[i] = models.UserResp{
+			ID:        document.ID,
+			Name:      document.Name,
+			Surnames:  document.Surnames,
+			Email:     document.Email,
+			Claims:    document.Claims,
+			CreatedAt: document.CreatedAt,
+			UpdatedAt: document.UpdatedAt,
+		}
+	}
+	resp.Facets = make(map[string][]models.FacetResp, len(result.Facets))
+	for name, facets := range result.Facets {
+		resp.Facets[name] = make([]models.FacetResp, len(facets))
+		for i, facet := range facets {
+			resp.Facets[name][i] = models.FacetResp(facet)
+		}
+	}
+	return
+}
+
+// backfill indexes every user stored
+func (s *searchService) backfill(ctx context.Context) error {
+	start := time.Now()
+	var count int64
+	err := s.repository.Dump(ports.WithOperationTimeout(ctx, 0), func(user entities.User) error {
+		count++
+		return s.apply(ctx, ports.UserChange{Operation: ports.UserChangeSaved, ID: user.ID})
+	})
+	if err != nil {
+		return fmt.Errorf("search index cannot be backfilled: %w", err)
+	}
+	s.logger.Info().Int64("indexed", count).Dur("elapsed", time.Since(start)).Msg("search index backfilled")
+	return nil
+}
+
+// apply indexes the user changed, or deletes its document when the user was deleted, or archived, since
+func (s *searchService) apply(ctx context.Context, change ports.UserChange) error {
+	if change.Operation == ports.UserChangeDeleted {
+		return s.index.Delete(ctx, change.ID)
+	}
+
+	result, err := s.repository.GetByID(ctx, change.ID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return s.index.Delete(ctx, change.ID)
+	}
+	if err != nil {
+		return err
+	}
+	user, ok := result.(*entities.User)
+	if !ok {
+		return fmt.Errorf("user %s not valid", change.ID)
+	}
+
+	return s.index.Index(ctx, ports.SearchDocument{
+		ID:        user.ID,
+		Name:      user.Name,
+		Surnames:  user.Surnames,
+		Email:     user.Email,
+		Domain:    emailDomain(user.Email),
+		Claims:    user.Claims,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+}
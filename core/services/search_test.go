@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// watchWith returns a watch of the change stream calling the backfill when requested, then handling the changes
+func watchWith(backfill bool, changes ...ports.UserChange) func(context.Context, func(context.Context) error, func(context.Context, ports.UserChange) error) error {
+	return func(ctx context.Context, backfillFn func(context.Context) error, handle func(context.Context, ports.UserChange) error) error {
+		if backfill {
+			if err := backfillFn(ctx); err != nil {
+				return err
+			}
+		}
+		for _, change := range changes {
+			if err := handle(ctx, change); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// TestSync_Changes checks that Sync indexes the users saved, as read from the repository, and deletes the documents of the users deleted
+func TestSync_Changes(t *testing.T) {
+	// Arrange
+	user := entities.User{ID: "saved-id", Name: "test", Email: "test@Example.com", Claims: []int64{1}}
+	streamMock := mocks.NewUserChangeStream(t)
+	watch := watchWith(false, ports.UserChange{Operation: ports.UserChangeSaved, ID: "saved-id"}, ports.UserChange{Operation: ports.UserChangeDeleted, ID: "deleted-id"})
+	streamMock.On(testutils.FunctionName(t, ports.UserChangeStream.Watch), mock.Anything, mock.Anything, mock.Anything).Return(watch).Once()
+	repositoryMock := mocks.NewUserRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "saved-id").Return(&user, nil).Once()
+	indexMock := mocks.NewSearchIndex(t)
+	expectedDocument := ports.SearchDocument{ID: "saved-id", Name: "test", Email: "test@Example.com", Domain: "example.com", Claims: []int64{1}}
+	indexMock.On(testutils.FunctionName(t, ports.SearchIndex.Index), mock.Anything, expectedDocument).Return(nil).Once()
+	indexMock.On(testutils.FunctionName(t, ports.SearchIndex.Delete), mock.Anything, "deleted-id").Return(nil).Once()
+
+	service := NewSearchService(zerolog.Nop(), repositoryMock, indexMock, streamMock, nil)
+
+	// Act
+	err := service.Sync(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSync_Backfill checks that Sync indexes every user stored when the change stream asks for a backfill
+func TestSync_Backfill(t *testing.T) {
+	// Arrange
+	user := entities.User{ID: "test-id", Email: "test@example.com"}
+	streamMock := mocks.NewUserChangeStream(t)
+	streamMock.On(testutils.FunctionName(t, ports.UserChangeStream.Watch), mock.Anything, mock.Anything, mock.Anything).Return(watchWith(true)).Once()
+	repositoryMock := mocks.NewUserRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Dump), mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(entities.User) error) error {
+		return fn(user)
+	}).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "test-id").Return(&user, nil).Once()
+	indexMock := mocks.NewSearchIndex(t)
+	indexMock.On(testutils.FunctionName(t, ports.SearchIndex.Index), mock.Anything, mock.Anything).Return(nil).Once()
+
+	service := NewSearchService(zerolog.Nop(), repositoryMock, indexMock, streamMock, nil)
+
+	// Act
+	err := service.Sync(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSync_NotExistent checks that Sync deletes the document of a user saved but no longer stored, as it was deleted or archived since
+func TestSync_NotExistent(t *testing.T) {
+	// Arrange
+	streamMock := mocks.NewUserChangeStream(t)
+	watch := watchWith(false, ports.UserChange{Operation: ports.UserChangeSaved, ID: "test-id"})
+	streamMock.On(testutils.FunctionName(t, ports.UserChangeStream.Watch), mock.Anything, mock.Anything, mock.Anything).Return(watch).Once()
+	repositoryMock := mocks.NewUserRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "test-id").Return(nil, wrappers.NewNonExistentErr(fmt.Errorf("not found"))).Once()
+	indexMock := mocks.NewSearchIndex(t)
+	indexMock.On(testutils.FunctionName(t, ports.SearchIndex.Delete), mock.Anything, "test-id").Return(nil).Once()
+
+	service := NewSearchService(zerolog.Nop(), repositoryMock, indexMock, streamMock, nil)
+
+	// Act
+	err := service.Sync(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSync_IndexError checks that Sync stops with the error of a change not indexed, so it is handled again once resumed
+func TestSync_IndexError(t *testing.T) {
+	// Arrange
+	user := entities.User{ID: "test-id", Email: "test@example.com"}
+	expectedError := errors.New("index error")
+	streamMock := mocks.NewUserChangeStream(t)
+	watch := watchWith(false, ports.UserChange{Operation: ports.UserChangeSaved, ID: "test-id"})
+	streamMock.On(testutils.FunctionName(t, ports.UserChangeStream.Watch), mock.Anything, mock.Anything, mock.Anything).Return(watch).Once()
+	repositoryMock := mocks.NewUserRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "test-id").Return(&user, nil).Once()
+	indexMock := mocks.NewSearchIndex(t)
+	indexMock.On(testutils.FunctionName(t, ports.SearchIndex.Index), mock.Anything, mock.Anything).Return(expectedError).Once()
+
+	service := NewSearchService(zerolog.Nop(), repositoryMock, indexMock, streamMock, nil)
+
+	// Act
+	err := service.Sync(context.Background())
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
+
+// TestSearchIndex_Ok checks that Search returns the documents matched in the index as users, with the total and the facets
+func TestSearchIndex_Ok(t *testing.T) {
+	// Arrange
+	req := models.IndexSearchUsersReq{Query: "test", Domain: "example.com", Take: 10}
+	indexMock := mocks.NewSearchIndex(t)
+	indexMock.On(testutils.FunctionName(t, ports.SearchIndex.Search), mock.Anything, ports.SearchQuery{Text: "test", Domain: "example.com", Take: 10}).Return(ports.SearchResult{
+		Total:     1,
+		Documents: []ports.SearchDocument{{ID: "test-id", Name: "test", Email: "test@example.com", Domain: "example.com"}},
+		Facets:    map[string][]ports.SearchFacet{ports.SearchFacetDomain: {{Value: "example.com", Count: 1}}},
+	}, nil).Once()
+	expectedResp := models.IndexSearchUsersResp{
+		Total:  1,
+		Users:  []models.UserResp{{ID: "test-id", Name: "test", Email: "test@example.com"}},
+		Facets: map[string][]models.FacetResp{ports.SearchFacetDomain: {{Value: "example.com", Count: 1}}},
+	}
+
+	service := NewSearchService(zerolog.Nop(), nil, indexMock, nil, nil)
+
+	// Act
+	resp, err := service.Search(context.Background(), models.Caller{UserID: "test-id"}, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResp, resp)
+}
+
+// TestSearchIndex_Organizations checks that Search filters the index by the caller and the members of its organizations when they are stored
+func TestSearchIndex_Organizations(t *testing.T) {
+	// Arrange
+	req := models.IndexSearchUsersReq{Query: "test", Take: 10}
+	organizationRepositoryMock := mocks.NewOrganizationRepository(t)
+	organizationRepositoryMock.On(testutils.FunctionName(t, ports.OrganizationRepository.GetByMember), mock.Anything, "test-member").Return([]entities.Organization{*testOrganization()}, nil).Once()
+	indexMock := mocks.NewSearchIndex(t)
+	indexMock.On(testutils.FunctionName(t, ports.SearchIndex.Search), mock.Anything, ports.SearchQuery{Text: "test", IDs: []string{"test-member", "test-owner", "test-admin"}, Take: 10}).
+		Return(ports.SearchResult{}, nil).Once()
+
+	service := NewSearchService(zerolog.Nop(), nil, indexMock, nil, organizationRepositoryMock)
+
+	// Act
+	_, err := service.Search(context.Background(), models.Caller{UserID: "test-member"}, req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSearchIndex_InvalidRequest checks that Search returns a validation error without querying the index when the request is not valid
+func TestSearchIndex_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := NewSearchService(zerolog.Nop(), nil, mocks.NewSearchIndex(t), nil, nil)
+
+	// Act
+	_, err := service.Search(context.Background(), models.Caller{UserID: "test-id"}, models.IndexSearchUsersReq{})
+
+	// Assert
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// securityAlertTimeLayout is the layout of the time of the changes in the security alerts
+const securityAlertTimeLayout = "2 January 2006 at 15:04 MST"
+
+// securityAlertUserService decorator of an user service that alerts the users of the changes of their credentials
+type securityAlertUserService struct {
+	ports.UserService
+	mailer ports.Mailer
+	logger zerolog.Logger
+}
+
+// NewSecurityAlertUserService wraps a user service emailing a security alert to the previous email of the users whose password or email changes
+func NewSecurityAlertUserService(service ports.UserService, mailer ports.Mailer, logger zerolog.Logger) ports.UserService {
+	return &securityAlertUserService{
+		UserService: service,
+		mailer:      mailer,
+		logger:      logger,
+	}
+}
+
+func (s *securityAlertUserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) error {
+	if user.NewPassword == nil && user.Email == nil {
+		return s.UserService.Update(ctx, ID, user)
+	}
+
+	previous, getErr := s.UserService.GetByID(ctx, ID)
+	if err := s.UserService.Update(ctx, ID, user); err != nil {
+		return err
+	}
+	if getErr != nil {
+		s.logger.Error().Err(getErr).Str("user", ID).Msg("security alert cannot be sent")
+		return nil
+	}
+
+	at := time.Now().UTC().Format(securityAlertTimeLayout)
+	if user.NewPassword != nil {
+		s.alert(ctx, previous, "password changed", "The password of your account was changed", at)
+	}
+	if user.Email != nil && normalizeEmail(*user.Email) != previous.Email {
+		s.alert(ctx, previous, "email changed", fmt.Sprintf("The email of your account was changed to %s", normalizeEmail(*user.Email)), at)
+	}
+	return nil
+}
+
+// alert sends the security alert to the user, logging the failures instead of returning them as the change is already done
+func (s *securityAlertUserService) alert(ctx context.Context, user models.UserResp, event, detail, at string) {
+	err := s.mailer.Send(ctx, ports.EmailTemplateSecurityAlert, user.Email, map[string]string{
+		"name":   user.Name,
+		"event":  event,
+		"detail": detail,
+		"at":     at,
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Str("user", user.ID).Str("event", event).Msg("security alert cannot be sent")
+	}
+}
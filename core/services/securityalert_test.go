@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestSecurityAlertUpdate_PasswordChanged checks that Update emails a security alert to the user once its password is changed
+func TestSecurityAlertUpdate_PasswordChanged(t *testing.T) {
+	// Arrange
+	password := "new-password"
+	req := models.UpdateUserReq{NewPassword: &password}
+	user := models.UserResp{ID: "test-id", Name: "test", Email: "test@example.com"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(user, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", req).Return(nil).Once()
+	mailerMock := mocks.NewMailer(t)
+	mailerMock.On(testutils.FunctionName(t, ports.Mailer.Send), mock.Anything, ports.EmailTemplateSecurityAlert, "test@example.com", mock.MatchedBy(func(data map[string]string) bool {
+		return data["name"] == "test" && data["event"] == "password changed" && data["at"] != ""
+	})).Return(nil).Once()
+
+	service := NewSecurityAlertUserService(userServiceMock, mailerMock, zerolog.Nop())
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSecurityAlertUpdate_EmailChanged checks that Update emails the security alert to the previous email of the user once it is changed
+func TestSecurityAlertUpdate_EmailChanged(t *testing.T) {
+	// Arrange
+	email := "New@Example.com"
+	req := models.UpdateUserReq{Email: &email}
+	user := models.UserResp{ID: "test-id", Name: "test", Email: "test@example.com"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(user, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", req).Return(nil).Once()
+	mailerMock := mocks.NewMailer(t)
+	mailerMock.On(testutils.FunctionName(t, ports.Mailer.Send), mock.Anything, ports.EmailTemplateSecurityAlert, "test@example.com", mock.MatchedBy(func(data map[string]string) bool {
+		return data["event"] == "email changed" && data["detail"] == "The email of your account was changed to new@example.com"
+	})).Return(nil).Once()
+
+	service := NewSecurityAlertUserService(userServiceMock, mailerMock, zerolog.Nop())
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSecurityAlertUpdate_OtherFields checks that Update does not email any alert when neither the password nor the email is changed
+func TestSecurityAlertUpdate_OtherFields(t *testing.T) {
+	// Arrange
+	name := "new-name"
+	req := models.UpdateUserReq{Name: &name}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", req).Return(nil).Once()
+
+	service := NewSecurityAlertUserService(userServiceMock, mocks.NewMailer(t), zerolog.Nop())
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSecurityAlertUpdate_UpdateError checks that Update does not email any alert when the change fails
+func TestSecurityAlertUpdate_UpdateError(t *testing.T) {
+	// Arrange
+	password := "new-password"
+	req := models.UpdateUserReq{NewPassword: &password}
+	expectedError := fmt.Errorf("incorrect password")
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.UserResp{Email: "test@example.com"}, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", req).Return(expectedError).Once()
+
+	service := NewSecurityAlertUserService(userServiceMock, mocks.NewMailer(t), zerolog.Nop())
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
+
+// TestSecurityAlertUpdate_SendError checks that Update does not return an error when the alert cannot be sent, as the change is already done
+func TestSecurityAlertUpdate_SendError(t *testing.T) {
+	// Arrange
+	password := "new-password"
+	req := models.UpdateUserReq{NewPassword: &password}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.UserResp{Email: "test@example.com"}, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", req).Return(nil).Once()
+	mailerMock := mocks.NewMailer(t)
+	mailerMock.On(testutils.FunctionName(t, ports.Mailer.Send), mock.Anything, ports.EmailTemplateSecurityAlert, "test@example.com", mock.Anything).Return(fmt.Errorf("queue not reachable")).Once()
+
+	service := NewSecurityAlertUserService(userServiceMock, mailerMock, zerolog.Nop())
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/mapping"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// keys prefixes of the MFA limits in the limit store: the codes sent or checked for a user and the codes already used
+const (
+	mfaAttemptsKeyPrefix = "mfa:"
+	mfaUsedKeyPrefix     = "mfa-used:"
+)
+
+var (
+	errMFACodeSent        = errors.New("a one-time code has been emailed to the user, log in again with it as mfa_code")
+	errMFACodeNotValid    = errors.New("one-time code not valid or expired")
+	errTooManyMFAAttempts = errors.New("too many one-time codes sent or checked, try again later")
+)
+
+// securityPolicyService adapter of the security policy service
+type securityPolicyService struct {
+	repository ports.SecurityPolicyRepository
+	mfa        bool
+}
+
+// NewSecurityPolicyService creates a new security policy service, the policies only requiring MFA when mfa is set,
+// as the one-time codes of the logins are emailed
+func NewSecurityPolicyService(repository ports.SecurityPolicyRepository, mfa bool) ports.SecurityPolicyService {
+	return &securityPolicyService{
+		repository: repository,
+		mfa:        mfa,
+	}
+}
+
+// GetAll the security policies, sorted by tenant
+func (s *securityPolicyService) GetAll(ctx context.Context) (resp []models.SecurityPolicyResp, err error) {
+	policies, err := s.repository.GetAll(ctx)
+	if err != nil {
+		return
+	}
+
+	resp = make([]models.SecurityPolicyResp, 0, len(policies))
+	for _, policy := range policies {
+		resp = append(resp, mapping.SecurityPolicyResp(policy))
+	}
+	return
+}
+
+// Get the security policy of a tenant
+func (s *securityPolicyService) Get(ctx context.Context, tenant string) (resp models.SecurityPolicyResp, err error) {
+	policy, err := s.repository.Get(ctx, strings.ToLower(tenant))
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = apierror.NotFound(err)
+	}
+	if err != nil {
+		return
+	}
+
+	resp = mapping.SecurityPolicyResp(policy)
+	return
+}
+
+// Put the security policy of a tenant, replacing the one it has, if any
+func (s *securityPolicyService) Put(ctx context.Context, req models.PutSecurityPolicyReq) (resp models.SecurityPolicyResp, err error) {
+	req.Tenant = strings.ToLower(req.Tenant)
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if req.MFARequired && !s.mfa {
+		err = apierror.Conflict(fmt.Errorf("MFA cannot be required while the emails are not enabled, as the one-time codes are emailed"))
+		return
+	}
+
+	now := time.Now().UTC()
+	policy := mapping.SecurityPolicy(req)
+	policy.CreatedAt = now
+	policy.UpdatedAt = now
+	current, err := s.repository.Get(ctx, req.Tenant)
+	switch {
+	case err == nil:
+		policy.CreatedAt = current.CreatedAt
+	case !errors.Is(err, wrappers.NonExistentErr):
+		return
+	}
+	if err = s.repository.Upsert(ctx, policy); err != nil {
+		return
+	}
+
+	resp = mapping.SecurityPolicyResp(policy)
+	return
+}
+
+// Delete the security policy of a tenant, its users being applied the settings of the config
+func (s *securityPolicyService) Delete(ctx context.Context, tenant string) error {
+	err := s.repository.Delete(ctx, strings.ToLower(tenant))
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return apierror.NotFound(err)
+	}
+	return err
+}
+
+// tenantPolicy returns the security policy of the tenant of the email, or an empty one applying the settings of the config when it has none
+func tenantPolicy(ctx context.Context, policies ports.SecurityPolicyRepository, email string) (entities.SecurityPolicy, error) {
+	tenant := emailDomain(normalizeEmail(email))
+	policy, err := policies.Get(ctx, tenant)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return entities.SecurityPolicy{Tenant: tenant}, nil
+	}
+	return policy, err
+}
+
+// securityPolicyUserService decorator of an user service that applies the security policies of the tenants
+type securityPolicyUserService struct {
+	ports.UserService
+	policies    ports.SecurityPolicyRepository
+	mailer      ports.Mailer
+	limits      ports.LimitStore
+	logger      zerolog.Logger
+	key         []byte
+	codeTTL     time.Duration
+	maxAttempts int64
+	now         func() time.Time
+}
+
+// NewSecurityPolicyUserService wraps a user service applying the password rules, the token lifetime and the MFA of the policies
+func NewSecurityPolicyUserService(service ports.UserService, policies ports.SecurityPolicyRepository, mailer ports.Mailer, limits ports.LimitStore, logger zerolog.Logger,
+	secret string, codeTTL time.Duration, maxAttempts int64) ports.UserService {
+	return &securityPolicyUserService{
+		UserService: service,
+		policies:    policies,
+		mailer:      mailer,
+		limits:      limits,
+		logger:      logger,
+		key:         codeKey(secret, mfaCodeKeyLabel),
+		codeTTL:     codeTTL,
+		maxAttempts: maxAttempts,
+		now:         time.Now,
+	}
+}
+
+// Login user with the token lifetime of its tenant, checking the one-time code after the password when the tenant requires MFA
+func (s *securityPolicyUserService) Login(ctx context.Context, credentials models.LoginUserReq) (resp models.LoginUserResp, err error) {
+	if credentials.Validate() != nil {
+		return s.UserService.Login(ctx, credentials)
+	}
+
+	policy, err := tenantPolicy(ctx, s.policies, credentials.Email)
+	if err != nil {
+		return
+	}
+	if policy.SessionLifetime > 0 {
+		ctx = models.WithTokenLifetime(ctx, policy.SessionLifetime)
+	}
+
+	resp, err = s.UserService.Login(ctx, credentials)
+	if err != nil || !policy.MFARequired {
+		return
+	}
+	if err = s.checkMFA(ctx, resp.User, credentials.MFACode); err != nil {
+		return models.LoginUserResp{}, err
+	}
+	return
+}
+
+func (s *securityPolicyUserService) Create(ctx context.Context, user models.CreateUserReq) (models.CreationResp, error) {
+	if err := s.checkPassword(ctx, user.Email, user.Password); err != nil {
+		return models.CreationResp{}, err
+	}
+	return s.UserService.Create(ctx, user)
+}
+
+func (s *securityPolicyUserService) CreateMany(ctx context.Context, users []models.CreateUserReq) (models.MultiCreationResp, error) {
+	for _, user := range users {
+		if err := s.checkPassword(ctx, user.Email, user.Password); err != nil {
+			return models.MultiCreationResp{}, err
+		}
+	}
+	return s.UserService.CreateMany(ctx, users)
+}
+
+func (s *securityPolicyUserService) Upsert(ctx context.Context, email string, user models.UpsertUserReq) (models.UpsertionResp, error) {
+	if err := s.checkPassword(ctx, email, user.Password); err != nil {
+		return models.UpsertionResp{}, err
+	}
+	return s.UserService.Upsert(ctx, email, user)
+}
+
+// Update checks the new password, if any, against the policy of the tenant of the user, or of its new email when also updated
+func (s *securityPolicyUserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) error {
+	if user.NewPassword != nil {
+		email := ""
+		if user.Email != nil {
+			email = *user.Email
+		} else {
+			current, err := s.UserService.GetByID(ctx, ID)
+			if err != nil {
+				return err
+			}
+			email = current.Email
+		}
+		if err := s.checkPassword(ctx, email, *user.NewPassword); err != nil {
+			return err
+		}
+	}
+	return s.UserService.Update(ctx, ID, user)
+}
+
+// checkPassword returns a validation error with the rules of the policy of the tenant of the email the password breaks.
+// An empty email or password is left to the validation of the decorated service.
+func (s *securityPolicyUserService) checkPassword(ctx context.Context, email, password string) error {
+	if email == "" || password == "" {
+		return nil
+	}
+
+	policy, err := tenantPolicy(ctx, s.policies, email)
+	if err != nil {
+		return err
+	}
+	var errs apierror.ValidationError
+	for _, violation := range policy.Password.Violations(password) {
+		errs.Add("password", violation)
+	}
+	return errs.Err()
+}
+
+// checkMFA emails a one-time code to the user when no code is given, failing as unauthorized until the login is done again with it,
+// or checks the code given
+func (s *securityPolicyUserService) checkMFA(ctx context.Context, user models.UserResp, code string) error {
+	if s.mailer == nil {
+		return fmt.Errorf("MFA required for %s, but the emails are not enabled to send the one-time codes", user.Email)
+	}
+
+	attemptsKey := mfaAttemptsKeyPrefix + user.ID
+	attempts, err := s.limits.Hit(ctx, attemptsKey, s.codeTTL)
+	if err != nil {
+		return err
+	}
+	if attempts.Count > s.maxAttempts {
+		return apierror.Unauthorized(errTooManyMFAAttempts)
+	}
+
+	period := s.now().UnixNano() / int64(s.codeTTL)
+	if code == "" {
+		err = s.mailer.Send(ctx, ports.EmailTemplateLoginCode, user.Email, map[string]string{
+			"name":    user.Name,
+			"code":    oneTimeCode(s.key, mfaAttemptsKeyPrefix+user.ID, period),
+			"expires": s.codeTTL.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("cannot send the one-time code: %w", err)
+		}
+		return apierror.Unauthorized(errMFACodeSent)
+	}
+
+	for _, p := range []int64{period, period - 1} {
+		if !hmac.Equal([]byte(oneTimeCode(s.key, mfaAttemptsKeyPrefix+user.ID, p)), []byte(code)) {
+			continue
+		}
+
+		used, err := s.limits.Hit(ctx, mfaUsedKeyPrefix+user.ID+":"+strconv.FormatInt(p, 10), 2*s.codeTTL)
+		if err != nil {
+			return err
+		}
+		if used.Count > 1 {
+			break
+		}
+		if err := s.limits.Reset(ctx, attemptsKey); err != nil {
+			s.logger.Error().Err(err).Msg("mfa attempts cannot be reset")
+		}
+		return nil
+	}
+	return wrappers.NewValidationErr(errMFACodeNotValid)
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestPutSecurityPolicy_Ok checks that Put stores the policy of the lowercased tenant with its durations parsed
+func TestPutSecurityPolicy_Ok(t *testing.T) {
+	// Arrange
+	req := models.PutSecurityPolicyReq{
+		Tenant:             "Example.com",
+		Password:           models.PasswordPolicyReq{MinLength: 12, RequireDigit: true},
+		SessionLifetime:    "8h",
+		MFARequired:        true,
+		LockoutMaxFailures: 3,
+		LockoutDuration:    "1h",
+	}
+	repositoryMock := mocks.NewSecurityPolicyRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), context.Background(), "example.com").Return(entities.SecurityPolicy{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Upsert), context.Background(), mock.MatchedBy(func(policy entities.SecurityPolicy) bool {
+		return policy.Tenant == "example.com" && policy.Password.MinLength == 12 && policy.SessionLifetime == 8*time.Hour &&
+			policy.MFARequired && policy.LockoutMaxFailures == 3 && policy.LockoutDuration == time.Hour && !policy.CreatedAt.IsZero()
+	})).Return(nil).Once()
+
+	service := NewSecurityPolicyService(repositoryMock, true)
+
+	// Act
+	resp, err := service.Put(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com", resp.Tenant)
+	assert.Equal(t, "8h0m0s", resp.SessionLifetime)
+	assert.Equal(t, "1h0m0s", resp.LockoutDuration)
+}
+
+// TestPutSecurityPolicy_Replaced checks that Put keeps the creation time of the policy replaced
+func TestPutSecurityPolicy_Replaced(t *testing.T) {
+	// Arrange
+	createdAt := time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC)
+	repositoryMock := mocks.NewSecurityPolicyRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), context.Background(), "example.com").Return(entities.SecurityPolicy{Tenant: "example.com", CreatedAt: createdAt}, nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Upsert), context.Background(), mock.MatchedBy(func(policy entities.SecurityPolicy) bool {
+		return policy.CreatedAt.Equal(createdAt) && policy.UpdatedAt.After(createdAt)
+	})).Return(nil).Once()
+
+	service := NewSecurityPolicyService(repositoryMock, false)
+
+	// Act
+	resp, err := service.Put(context.Background(), models.PutSecurityPolicyReq{Tenant: "example.com"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, createdAt, resp.CreatedAt)
+}
+
+// TestPutSecurityPolicy_MFANotEnabled checks that Put returns a conflict when the policy requires MFA while the emails are not enabled
+func TestPutSecurityPolicy_MFANotEnabled(t *testing.T) {
+	// Arrange
+	service := NewSecurityPolicyService(mocks.NewSecurityPolicyRepository(t), false)
+
+	// Act
+	_, err := service.Put(context.Background(), models.PutSecurityPolicyReq{Tenant: "example.com", MFARequired: true})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrConflict)
+}
+
+// TestPutSecurityPolicy_InvalidRequest checks that Put returns a validation error when the request is not valid
+func TestPutSecurityPolicy_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := NewSecurityPolicyService(mocks.NewSecurityPolicyRepository(t), true)
+
+	// Act
+	_, err := service.Put(context.Background(), models.PutSecurityPolicyReq{Tenant: "example.com", SessionLifetime: "forever"})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrValidation)
+}
+
+// TestGetSecurityPolicy_NotFound checks that Get returns a not found error when the tenant has no policy
+func TestGetSecurityPolicy_NotFound(t *testing.T) {
+	// Arrange
+	repositoryMock := mocks.NewSecurityPolicyRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), context.Background(), "example.com").Return(entities.SecurityPolicy{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := NewSecurityPolicyService(repositoryMock, true)
+
+	// Act
+	_, err := service.Get(context.Background(), "Example.com")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+// TestDeleteSecurityPolicy_NotFound checks that Delete returns a not found error when the tenant has no policy
+func TestDeleteSecurityPolicy_NotFound(t *testing.T) {
+	// Arrange
+	repositoryMock := mocks.NewSecurityPolicyRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Delete), context.Background(), "example.com").Return(wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := NewSecurityPolicyService(repositoryMock, true)
+
+	// Act
+	err := service.Delete(context.Background(), "example.com")
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+// TestLoginSecurityPolicyUser_SessionLifetime checks that the decorated Login signs the token with the session lifetime of the tenant
+func TestLoginSecurityPolicyUser_SessionLifetime(t *testing.T) {
+	// Arrange
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	expectedResp := models.LoginUserResp{User: models.UserResp{ID: "test-user"}, Token: "test-token"}
+	policiesMock := mocks.NewSecurityPolicyRepository(t)
+	policiesMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), mock.Anything, "example.com").Return(entities.SecurityPolicy{Tenant: "example.com", SessionLifetime: time.Hour}, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), mock.MatchedBy(func(ctx context.Context) bool {
+		return models.TokenLifetimeFrom(ctx) == time.Hour
+	}), credentials).Return(expectedResp, nil).Once()
+
+	service := NewSecurityPolicyUserService(userServiceMock, policiesMock, nil, nil, zerolog.Nop(), "test-secret", time.Minute, 5)
+
+	// Act
+	resp, err := service.Login(context.Background(), credentials)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResp, resp)
+}
+
+// TestLoginSecurityPolicyUser_MFACodeSent checks that the decorated Login emails a one-time code and fails as unauthorized when the tenant requires MFA
+func TestLoginSecurityPolicyUser_MFACodeSent(t *testing.T) {
+	// Arrange
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	user := models.UserResp{ID: "test-user", Name: "test", Email: "test@example.com"}
+	policiesMock := mocks.NewSecurityPolicyRepository(t)
+	policiesMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), mock.Anything, "example.com").Return(entities.SecurityPolicy{Tenant: "example.com", MFARequired: true}, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, credentials).Return(models.LoginUserResp{User: user, Token: "test-token"}, nil).Once()
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "mfa:test-user", time.Minute).Return(entities.Limit{Count: 1}, nil).Once()
+	now := time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC)
+	expectedCode := oneTimeCode(codeKey("test-secret", mfaCodeKeyLabel), "mfa:test-user", now.UnixNano()/int64(time.Minute))
+	mailerMock := mocks.NewMailer(t)
+	mailerMock.On(testutils.FunctionName(t, ports.Mailer.Send), mock.Anything, ports.EmailTemplateLoginCode, "test@example.com", map[string]string{"name": "test", "code": expectedCode, "expires": "1m0s"}).Return(nil).Once()
+
+	service := NewSecurityPolicyUserService(userServiceMock, policiesMock, mailerMock, limitStoreMock, zerolog.Nop(), "test-secret", time.Minute, 5)
+	service.(*securityPolicyUserService).now = func() time.Time { return now }
+
+	// Act
+	resp, err := service.Login(context.Background(), credentials)
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+	assert.Empty(t, resp.Token)
+}
+
+// TestLoginSecurityPolicyUser_MFACodeOk checks that the decorated Login accepts the one-time code of the previous period, resetting the attempts
+func TestLoginSecurityPolicyUser_MFACodeOk(t *testing.T) {
+	// Arrange
+	now := time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC)
+	period := now.UnixNano() / int64(time.Minute)
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test", MFACode: oneTimeCode(codeKey("test-secret", mfaCodeKeyLabel), "mfa:test-user", period-1)}
+	expectedResp := models.LoginUserResp{User: models.UserResp{ID: "test-user", Email: "test@example.com"}, Token: "test-token"}
+	policiesMock := mocks.NewSecurityPolicyRepository(t)
+	policiesMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), mock.Anything, "example.com").Return(entities.SecurityPolicy{Tenant: "example.com", MFARequired: true}, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, credentials).Return(expectedResp, nil).Once()
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "mfa:test-user", time.Minute).Return(entities.Limit{Count: 2}, nil).Once()
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "mfa-used:test-user:"+strconv.FormatInt(period-1, 10), 2*time.Minute).Return(entities.Limit{Count: 1}, nil).Once()
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Reset), mock.Anything, "mfa:test-user").Return(nil).Once()
+
+	service := NewSecurityPolicyUserService(userServiceMock, policiesMock, mocks.NewMailer(t), limitStoreMock, zerolog.Nop(), "test-secret", time.Minute, 5)
+	service.(*securityPolicyUserService).now = func() time.Time { return now }
+
+	// Act
+	resp, err := service.Login(context.Background(), credentials)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResp, resp)
+}
+
+// TestLoginSecurityPolicyUser_MFACodeNotValid checks that the decorated Login fails as an incorrect password does when the one-time code is not valid
+func TestLoginSecurityPolicyUser_MFACodeNotValid(t *testing.T) {
+	// Arrange
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test", MFACode: "000000"}
+	policiesMock := mocks.NewSecurityPolicyRepository(t)
+	policiesMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), mock.Anything, "example.com").Return(entities.SecurityPolicy{Tenant: "example.com", MFARequired: true}, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, credentials).Return(models.LoginUserResp{User: models.UserResp{ID: "test-user"}, Token: "test-token"}, nil).Once()
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "mfa:test-user", time.Minute).Return(entities.Limit{Count: 2}, nil).Once()
+
+	service := NewSecurityPolicyUserService(userServiceMock, policiesMock, mocks.NewMailer(t), limitStoreMock, zerolog.Nop(), "test-secret", time.Minute, 5)
+	service.(*securityPolicyUserService).now = func() time.Time { return time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC) }
+
+	// Act
+	resp, err := service.Login(context.Background(), credentials)
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+	assert.Empty(t, resp.Token)
+}
+
+// TestLoginSecurityPolicyUser_TooManyMFAAttempts checks that the decorated Login fails as unauthorized once too many codes were sent or checked
+func TestLoginSecurityPolicyUser_TooManyMFAAttempts(t *testing.T) {
+	// Arrange
+	credentials := models.LoginUserReq{Email: "test@example.com", Password: "test"}
+	policiesMock := mocks.NewSecurityPolicyRepository(t)
+	policiesMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), mock.Anything, "example.com").Return(entities.SecurityPolicy{Tenant: "example.com", MFARequired: true}, nil).Once()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Login), mock.Anything, credentials).Return(models.LoginUserResp{User: models.UserResp{ID: "test-user"}, Token: "test-token"}, nil).Once()
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "mfa:test-user", time.Minute).Return(entities.Limit{Count: 6}, nil).Once()
+
+	service := NewSecurityPolicyUserService(userServiceMock, policiesMock, mocks.NewMailer(t), limitStoreMock, zerolog.Nop(), "test-secret", time.Minute, 5)
+
+	// Act
+	_, err := service.Login(context.Background(), credentials)
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+	assert.ErrorContains(t, err, errTooManyMFAAttempts.Error())
+}
+
+// TestCreateSecurityPolicyUser_PasswordNotValid checks that the decorated Create returns a validation error with the rules of the tenant the password breaks
+func TestCreateSecurityPolicyUser_PasswordNotValid(t *testing.T) {
+	// Arrange
+	req := models.CreateUserReq{Name: "test", Email: "test@Example.com", Password: "test"}
+	policiesMock := mocks.NewSecurityPolicyRepository(t)
+	policiesMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), mock.Anything, "example.com").Return(entities.SecurityPolicy{Tenant: "example.com", Password: entities.PasswordPolicy{MinLength: 8, RequireDigit: true}}, nil).Once()
+
+	service := NewSecurityPolicyUserService(mocks.NewUserService(t), policiesMock, nil, nil, zerolog.Nop(), "test-secret", time.Minute, 5)
+
+	// Act
+	_, err := service.Create(context.Background(), req)
+
+	// Assert
+	var validationErr *apierror.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []apierror.FieldError{
+		{Field: "password", Message: "password must be at least 8 characters long"},
+		{Field: "password", Message: "password must contain a digit"},
+	}, validationErr.Fields)
+}
+
+// TestUpdateSecurityPolicyUser_NewPassword checks that the decorated Update checks the new password against the policy of the tenant of the user
+func TestUpdateSecurityPolicyUser_NewPassword(t *testing.T) {
+	// Arrange
+	newPassword := "Longer-password-1"
+	req := models.UpdateUserReq{NewPassword: &newPassword}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-user").Return(models.UserResp{ID: "test-user", Email: "test@example.com"}, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-user", req).Return(nil).Once()
+	policiesMock := mocks.NewSecurityPolicyRepository(t)
+	policiesMock.On(testutils.FunctionName(t, ports.SecurityPolicyRepository.Get), mock.Anything, "example.com").Return(entities.SecurityPolicy{Tenant: "example.com", Password: entities.PasswordPolicy{MinLength: 12, RequireSymbol: true}}, nil).Once()
+
+	service := NewSecurityPolicyUserService(userServiceMock, policiesMock, nil, nil, zerolog.Nop(), "test-secret", time.Minute, 5)
+
+	// Act
+	err := service.Update(context.Background(), "test-user", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// siemInitialBackoff is the wait after the first failed export of a batch, doubled after every other failure
+const siemInitialBackoff = time.Second
+
+// siemAuditRepository decorator of an audit repository that exports the events to a SIEM
+type siemAuditRepository struct {
+	ports.AuditRepository
+	sink          ports.SIEMSink
+	logger        zerolog.Logger
+	events        chan entities.AuditEvent
+	batchSize     int
+	flushInterval time.Duration
+	timeout       time.Duration
+	maxBackoff    time.Duration
+	done          chan struct{}
+}
+
+// NewSIEMAuditRepository wraps an audit repository exporting the events to the sink in batches, dropping them while the buffer is full
+func NewSIEMAuditRepository(ctx context.Context, repo ports.AuditRepository, sink ports.SIEMSink, logger zerolog.Logger, bufferSize, batchSize int, flushInterval, timeout, maxBackoff time.Duration) ports.AuditRepository {
+	r := &siemAuditRepository{
+		AuditRepository: repo,
+		sink:            sink,
+		logger:          logger,
+		events:          make(chan entities.AuditEvent, bufferSize),
+		batchSize:       batchSize,
+		flushInterval:   flushInterval,
+		timeout:         timeout,
+		maxBackoff:      maxBackoff,
+		done:            make(chan struct{}),
+	}
+	go r.run(ctx)
+	return r
+}
+
+func (r *siemAuditRepository) Write(ctx context.Context, event entities.AuditEvent) error {
+	err := r.AuditRepository.Write(ctx, event)
+	select {
+	case r.events <- event:
+	default:
+		siemDropped.Add(1)
+		r.logger.Warn().Str("type", event.Type).Msg("siem buffer full, audit event not exported")
+	}
+	return err
+}
+
+// run sends the buffered events in batches until the context is done, sending then the events left within the timeout
+func (r *siemAuditRepository) run(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]entities.AuditEvent, 0, r.batchSize)
+	for {
+		select {
+		case event := <-r.events:
+			batch = append(batch, event)
+			if len(batch) < r.batchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-ctx.Done():
+			r.flush(batch)
+			return
+		}
+
+		if !r.send(ctx, batch) {
+			r.flush(batch)
+			return
+		}
+		batch = make([]entities.AuditEvent, 0, r.batchSize)
+	}
+}
+
+// send exports the batch, retrying until it succeeds or the context is done, reporting whether it was sent
+func (r *siemAuditRepository) send(ctx context.Context, batch []entities.AuditEvent) bool {
+	backoff := siemInitialBackoff
+	if r.maxBackoff > 0 && backoff > r.maxBackoff {
+		backoff = r.maxBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		err := r.attempt(ctx, batch)
+		if err == nil {
+			siemExported.Add(int64(len(batch)))
+			return true
+		}
+		siemFailures.Add(1)
+		r.logger.Warn().Err(err).Int("attempt", attempt).Int("events", len(batch)).Dur("backoff", backoff).Msg("audit events cannot be exported, retrying")
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return false
+		}
+		backoff *= 2
+		if r.maxBackoff > 0 && backoff > r.maxBackoff {
+			backoff = r.maxBackoff
+		}
+	}
+}
+
+// flush exports the batch along with the events left in the buffer in a last attempt, as the context is done, dropping them when it fails
+func (r *siemAuditRepository) flush(batch []entities.AuditEvent) {
+	for len(r.events) > 0 {
+		batch = append(batch, <-r.events)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := r.attempt(context.Background(), batch); err != nil {
+		siemDropped.Add(int64(len(batch)))
+		r.logger.Error().Err(err).Int("events", len(batch)).Msg("audit events left in the siem buffer cannot be exported")
+		return
+	}
+	siemExported.Add(int64(len(batch)))
+}
+
+// attempt sends the batch within the timeout
+func (r *siemAuditRepository) attempt(ctx context.Context, batch []entities.AuditEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.sink.Send(ctx, batch)
+}
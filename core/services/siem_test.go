@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// receiveBatch waits for a batch sent to the sink
+func receiveBatch(t *testing.T, batches chan []entities.AuditEvent) []entities.AuditEvent {
+	t.Helper()
+
+	select {
+	case batch := <-batches:
+		return batch
+	case <-time.After(5 * time.Second):
+		t.Fatal("batch not sent")
+		return nil
+	}
+}
+
+// TestSIEMWrite_BatchFull checks that Write writes the events and exports them once a batch is full
+func TestSIEMWrite_BatchFull(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := []entities.AuditEvent{{Type: entities.AuditLoginSucceeded}, {Type: entities.AuditLoginFailed}}
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.Anything).Return(nil).Twice()
+	batches := make(chan []entities.AuditEvent, 1)
+	sinkMock := mocks.NewSIEMSink(t)
+	sinkMock.On(testutils.FunctionName(t, ports.SIEMSink.Send), mock.Anything, events).Return(nil).Once().Run(func(args mock.Arguments) {
+		batches <- args.Get(1).([]entities.AuditEvent)
+	})
+
+	repo := NewSIEMAuditRepository(ctx, auditRepositoryMock, sinkMock, zerolog.Nop(), 10, 2, time.Hour, time.Second, time.Millisecond)
+
+	// Act
+	for _, event := range events {
+		assert.Nil(t, repo.Write(context.Background(), event))
+	}
+
+	// Assert
+	assert.Equal(t, events, receiveBatch(t, batches))
+}
+
+// TestSIEMWrite_FlushInterval checks that the events of a batch not full are exported once the flush interval elapses
+func TestSIEMWrite_FlushInterval(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	event := entities.AuditEvent{Type: entities.AuditLoginSucceeded}
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), event).Return(nil).Once()
+	batches := make(chan []entities.AuditEvent, 1)
+	sinkMock := mocks.NewSIEMSink(t)
+	sinkMock.On(testutils.FunctionName(t, ports.SIEMSink.Send), mock.Anything, []entities.AuditEvent{event}).Return(nil).Once().Run(func(args mock.Arguments) {
+		batches <- args.Get(1).([]entities.AuditEvent)
+	})
+
+	repo := NewSIEMAuditRepository(ctx, auditRepositoryMock, sinkMock, zerolog.Nop(), 10, 100, 10*time.Millisecond, time.Second, time.Millisecond)
+
+	// Act
+	err := repo.Write(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []entities.AuditEvent{event}, receiveBatch(t, batches))
+}
+
+// TestSIEMWrite_Retried checks that a batch whose export fails is sent again
+func TestSIEMWrite_Retried(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	event := entities.AuditEvent{Type: entities.AuditLoginSucceeded}
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), event).Return(nil).Once()
+	batches := make(chan []entities.AuditEvent, 1)
+	sinkMock := mocks.NewSIEMSink(t)
+	sinkMock.On(testutils.FunctionName(t, ports.SIEMSink.Send), mock.Anything, mock.Anything).Return(errors.New("sink error")).Once()
+	sinkMock.On(testutils.FunctionName(t, ports.SIEMSink.Send), mock.Anything, []entities.AuditEvent{event}).Return(nil).Once().Run(func(args mock.Arguments) {
+		batches <- args.Get(1).([]entities.AuditEvent)
+	})
+	failuresBefore := siemFailures.Value()
+
+	repo := NewSIEMAuditRepository(ctx, auditRepositoryMock, sinkMock, zerolog.Nop(), 10, 1, time.Hour, time.Second, time.Millisecond)
+
+	// Act
+	err := repo.Write(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []entities.AuditEvent{event}, receiveBatch(t, batches))
+	assert.Equal(t, failuresBefore+1, siemFailures.Value())
+}
+
+// TestSIEMWrite_BufferFull checks that Write drops the events exceeding the buffer, without failing
+func TestSIEMWrite_BufferFull(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.Anything).Return(nil).Twice()
+
+	repo := NewSIEMAuditRepository(ctx, auditRepositoryMock, mocks.NewSIEMSink(t), zerolog.Nop(), 1, 1, time.Hour, time.Second, time.Millisecond)
+	<-repo.(*siemAuditRepository).done
+	droppedBefore := siemDropped.Value()
+
+	// Act
+	firstErr := repo.Write(context.Background(), entities.AuditEvent{Type: entities.AuditLoginSucceeded})
+	secondErr := repo.Write(context.Background(), entities.AuditEvent{Type: entities.AuditLoginFailed})
+
+	// Assert
+	assert.Nil(t, firstErr)
+	assert.Nil(t, secondErr)
+	assert.Equal(t, droppedBefore+1, siemDropped.Value())
+}
+
+// TestSIEMWrite_WriteError checks that Write exports an event even when it cannot be written, returning the error
+func TestSIEMWrite_WriteError(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	event := entities.AuditEvent{Type: entities.AuditLoginFailed}
+	expectedError := errors.New("repository error")
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), event).Return(expectedError).Once()
+	sinkMock := mocks.NewSIEMSink(t)
+	sinkMock.On(testutils.FunctionName(t, ports.SIEMSink.Send), mock.Anything, []entities.AuditEvent{event}).Return(nil).Once()
+
+	repo := NewSIEMAuditRepository(ctx, auditRepositoryMock, sinkMock, zerolog.Nop(), 10, 100, time.Hour, time.Second, time.Millisecond)
+
+	// Act
+	err := repo.Write(context.Background(), event)
+	cancel()
+	<-repo.(*siemAuditRepository).done
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
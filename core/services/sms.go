@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"golang.org/x/crypto/hkdf"
+)
+
+// keys prefixes of the SMS limits in the limit store: the messages sent to a phone and requested by a client, the checks of its codes and the codes already used
+const (
+	smsSentKeyPrefix   = "sms:"
+	smsClientKeyPrefix = "sms-client:"
+	smsChecksKeyPrefix = "sms-checks:"
+	smsUsedKeyPrefix   = "sms-used:"
+)
+
+// labels the keys of the one-time codes are derived from JWTSecret with, by the codes they sign
+const (
+	smsCodeKeyLabel = "sms-code"
+	mfaCodeKeyLabel = "mfa-code"
+)
+
+// smsMessages are the messages of the one-time codes by purpose, given the code and its validity
+var smsMessages = map[string]string{
+	models.SMSPurposeOTP:               "Your one-time code is %s, valid for %s. Do not share it with anyone.",
+	models.SMSPurposePhoneVerification: "Your phone verification code is %s, valid for %s.",
+}
+
+// smsFailedStatuses are the delivery statuses of the messages that did not reach their phone
+var smsFailedStatuses = map[string]bool{"failed": true, "undelivered": true}
+
+var (
+	errTooManySMS       = errors.New("too many messages sent to the phone, try again later")
+	errTooManySMSClient = errors.New("too many messages requested, try again later")
+	errTooManySMSChecks = errors.New("too many codes checked for the phone, try again later")
+	errSMSCodeNotValid  = errors.New("code not valid or expired")
+)
+
+// smsService adapter of the SMS service.
+// The codes are not stored: a code is the HMAC, with a key derived from JWTSecret, of its purpose, its phone and the CodeTTL period it is sent in,
+// so any replica can check it, and it is accepted during that period and the next one, then only once.
+type smsService struct {
+	config config.Config
+	logger zerolog.Logger
+	sender ports.SMSSender
+	limits ports.LimitStore
+	key    []byte
+	now    func() time.Time
+}
+
+// NewSMSService creates a new SMS service
+func NewSMSService(cfg config.Config, logger zerolog.Logger, sender ports.SMSSender, limits ports.LimitStore) ports.SMSService {
+	return &smsService{
+		config: cfg,
+		logger: logger,
+		sender: sender,
+		limits: limits,
+		key:    codeKey(cfg.JWTSecret, smsCodeKeyLabel),
+		now:    time.Now,
+	}
+}
+
+// SendCode sends a one-time code for the purpose to the phone, at most SMS.MaxPerRecipient times per SMS.Window,
+// and at most SMS.MaxPerClient times per SMS.Window for the IP of the request, so a client cannot send them to many phones
+func (s *smsService) SendCode(ctx context.Context, req models.SendSMSCodeReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	if ip := models.RequestInfoFrom(ctx).IP; ip != "" {
+		requested, err := s.limits.Hit(ctx, smsClientKeyPrefix+ip, s.config.SMS.Window.Duration)
+		if err != nil {
+			return err
+		}
+		if requested.Count > s.config.SMS.MaxPerClient {
+			return wrappers.NewValidationErr(errTooManySMSClient)
+		}
+	}
+
+	sent, err := s.limits.Hit(ctx, smsSentKeyPrefix+req.Phone, s.config.SMS.Window.Duration)
+	if err != nil {
+		return err
+	}
+	if sent.Count > s.config.SMS.MaxPerRecipient {
+		return wrappers.NewValidationErr(errTooManySMS)
+	}
+
+	code := s.code(req.Purpose, req.Phone, s.period(s.now()))
+	sid, err := s.sender.Send(ctx, req.Phone, fmt.Sprintf(smsMessages[req.Purpose], code, s.config.SMS.CodeTTL.Duration))
+	if err != nil {
+		return fmt.Errorf("cannot send the code: %w", err)
+	}
+	smsSentFor(req.Purpose)
+	s.logger.Debug().Str("sid", sid).Str("purpose", req.Purpose).Msg("sms code sent")
+	return nil
+}
+
+// CheckCode checks the code sent for the purpose to the phone, at most SMS.MaxCheckAttempts times per SMS.Window,
+// so the codes cannot be guessed, the checks being reset once a code is accepted
+func (s *smsService) CheckCode(ctx context.Context, req models.CheckSMSCodeReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	checksKey := smsChecksKeyPrefix + req.Purpose + ":" + req.Phone
+	checks, err := s.limits.Hit(ctx, checksKey, s.config.SMS.Window.Duration)
+	if err != nil {
+		return err
+	}
+	if checks.Count > s.config.SMS.MaxCheckAttempts {
+		return wrappers.NewValidationErr(errTooManySMSChecks)
+	}
+
+	period := s.period(s.now())
+	for _, p := range []int64{period, period - 1} {
+		if !hmac.Equal([]byte(s.code(req.Purpose, req.Phone, p)), []byte(req.Code)) {
+			continue
+		}
+
+		used, err := s.limits.Hit(ctx, smsUsedKeyPrefix+req.Purpose+":"+req.Phone+":"+strconv.FormatInt(p, 10), 2*s.config.SMS.CodeTTL.Duration)
+		if err != nil {
+			return err
+		}
+		if used.Count > 1 {
+			break
+		}
+		if err := s.limits.Reset(ctx, checksKey); err != nil {
+			s.logger.Error().Err(err).Msg("sms code checks cannot be reset")
+		}
+		return nil
+	}
+	return wrappers.NewValidationErr(errSMSCodeNotValid)
+}
+
+// UpdateStatus counts the delivery status posted by the provider, warning about the messages that did not reach their phone
+func (s *smsService) UpdateStatus(ctx context.Context, form url.Values, signature string) error {
+	if !s.sender.VerifyCallback(s.config.SMS.StatusCallbackURL, form, signature) {
+		return wrappers.NewUnauthorizedErr(fmt.Errorf("sms status callback signature not valid"))
+	}
+
+	status := form.Get("MessageStatus")
+	smsStatusReported(status)
+	if smsFailedStatuses[status] {
+		s.logger.Warn().Str("sid", form.Get("MessageSid")).Str("status", status).Str("error_code", form.Get("ErrorCode")).Msg("sms not delivered")
+		return nil
+	}
+	s.logger.Debug().Str("sid", form.Get("MessageSid")).Str("status", status).Msg("sms status updated")
+	return nil
+}
+
+// period returns the SMS.CodeTTL period of the time
+func (s *smsService) period(t time.Time) int64 {
+	return t.UnixNano() / int64(s.config.SMS.CodeTTL.Duration)
+}
+
+// code returns the six digits code of the purpose and the phone for the period
+func (s *smsService) code(purpose, phone string, period int64) string {
+	return oneTimeCode(s.key, purpose+":"+phone, period)
+}
+
+// codeKey derives the key of the one-time codes labeled from the secret with HKDF, so the codes are not HMACs with the secret signing the tokens
+func codeKey(secret, label string) []byte {
+	key := make([]byte, sha256.Size)
+	// reading at most 255 hashes from HKDF never fails
+	_, _ = io.ReadFull(hkdf.New(sha256.New, []byte(secret), nil, []byte(label)), key)
+	return key
+}
+
+// oneTimeCode returns the six digits code of the subject for the period, truncating its HMAC with the key like HOTP does
+func oneTimeCode(key []byte, subject string, period int64) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s:%d", subject, period)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000)
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// smsTestNow is the time the SMS tests run at
+var smsTestNow = time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC)
+
+// newTestSMSService returns an SMS service running at smsTestNow, with codes valid for 5 minutes
+func newTestSMSService(sender ports.SMSSender, limits ports.LimitStore) *smsService {
+	var cfg config.Config
+	cfg.JWTSecret = "test-secret"
+	cfg.SMS.MaxPerRecipient = 3
+	cfg.SMS.MaxPerClient = 10
+	cfg.SMS.MaxCheckAttempts = 5
+	cfg.SMS.Window = utils.Duration{Duration: time.Hour}
+	cfg.SMS.CodeTTL = utils.Duration{Duration: 5 * time.Minute}
+	cfg.SMS.StatusCallbackURL = "https://api.test.com/v1/sms/status"
+
+	s := NewSMSService(cfg, zerolog.Nop(), sender, limits).(*smsService)
+	s.now = func() time.Time { return smsTestNow }
+	return s
+}
+
+// TestSMSSendCode_Ok checks that SendCode sends the code of the current period to the phone, counting the message
+func TestSMSSendCode_Ok(t *testing.T) {
+	// Arrange
+	req := models.SendSMSCodeReq{Phone: "+34600000000", Purpose: models.SMSPurposePhoneVerification}
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "sms:+34600000000", time.Hour).Return(entities.Limit{Count: 3}, nil).Once()
+	senderMock := mocks.NewSMSSender(t)
+	service := newTestSMSService(senderMock, limitStoreMock)
+	code := service.code(req.Purpose, req.Phone, service.period(smsTestNow))
+	senderMock.On(testutils.FunctionName(t, ports.SMSSender.Send), mock.Anything, "+34600000000", fmt.Sprintf("Your phone verification code is %s, valid for 5m0s.", code)).Return("SM123", nil).Once()
+	sentBefore := counter(smsSent, models.SMSPurposePhoneVerification)
+
+	// Act
+	err := service.SendCode(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, code, 6)
+	assert.Equal(t, sentBefore+1, counter(smsSent, models.SMSPurposePhoneVerification))
+}
+
+// TestSMSSendCode_TooMany checks that SendCode does not send the code once the phone got SMS.MaxPerRecipient messages in the window
+func TestSMSSendCode_TooMany(t *testing.T) {
+	// Arrange
+	req := models.SendSMSCodeReq{Phone: "+34600000000", Purpose: models.SMSPurposeOTP}
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "sms:+34600000000", time.Hour).Return(entities.Limit{Count: 4}, nil).Once()
+	service := newTestSMSService(mocks.NewSMSSender(t), limitStoreMock)
+
+	// Act
+	err := service.SendCode(context.Background(), req)
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+	assert.ErrorContains(t, err, errTooManySMS.Error())
+}
+
+// TestSMSSendCode_TooManyFromClient checks that SendCode does not send the code once the IP of the request asked for SMS.MaxPerClient messages in the window
+func TestSMSSendCode_TooManyFromClient(t *testing.T) {
+	// Arrange
+	ctx := models.WithRequestInfo(context.Background(), models.RequestInfo{IP: "203.0.113.7"})
+	req := models.SendSMSCodeReq{Phone: "+34600000000", Purpose: models.SMSPurposeOTP}
+	limitStoreMock := mocks.NewLimitStore(t)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), ctx, "sms-client:203.0.113.7", time.Hour).Return(entities.Limit{Count: 11}, nil).Once()
+	service := newTestSMSService(mocks.NewSMSSender(t), limitStoreMock)
+
+	// Act
+	err := service.SendCode(ctx, req)
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+	assert.ErrorContains(t, err, errTooManySMSClient.Error())
+}
+
+// TestCodeKey_Labels checks that the keys of the codes are not the secret, and differ by label
+func TestCodeKey_Labels(t *testing.T) {
+	// Act
+	smsKey := codeKey("test-secret", smsCodeKeyLabel)
+	mfaKey := codeKey("test-secret", mfaCodeKeyLabel)
+
+	// Assert
+	assert.Len(t, smsKey, 32)
+	assert.NotEqual(t, smsKey, mfaKey)
+	assert.NotEqual(t, oneTimeCode([]byte("test-secret"), "otp:+34600000000", 1), oneTimeCode(smsKey, "otp:+34600000000", 1))
+}
+
+// TestSMSSendCode_InvalidRequest checks that SendCode returns a validation error without counting the message when the request is not valid
+func TestSMSSendCode_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := newTestSMSService(mocks.NewSMSSender(t), mocks.NewLimitStore(t))
+
+	// Act
+	err := service.SendCode(context.Background(), models.SendSMSCodeReq{Phone: "600000000", Purpose: models.SMSPurposeOTP})
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+}
+
+// TestSMSCheckCode_Ok checks that CheckCode accepts the code of the previous period once, resetting the checks of the phone
+func TestSMSCheckCode_Ok(t *testing.T) {
+	// Arrange
+	limitStoreMock := mocks.NewLimitStore(t)
+	service := newTestSMSService(mocks.NewSMSSender(t), limitStoreMock)
+	period := service.period(smsTestNow) - 1
+	req := models.CheckSMSCodeReq{Phone: "+34600000000", Purpose: models.SMSPurposeOTP, Code: service.code(models.SMSPurposeOTP, "+34600000000", period)}
+
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "sms-checks:otp:+34600000000", time.Hour).Return(entities.Limit{Count: 2}, nil).Once()
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "sms-used:otp:+34600000000:"+strconv.FormatInt(period, 10), 10*time.Minute).Return(entities.Limit{Count: 1}, nil).Once()
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Reset), mock.Anything, "sms-checks:otp:+34600000000").Return(nil).Once()
+
+	// Act
+	err := service.CheckCode(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSMSCheckCode_AlreadyUsed checks that CheckCode refuses a code already accepted
+func TestSMSCheckCode_AlreadyUsed(t *testing.T) {
+	// Arrange
+	limitStoreMock := mocks.NewLimitStore(t)
+	service := newTestSMSService(mocks.NewSMSSender(t), limitStoreMock)
+	period := service.period(smsTestNow)
+	req := models.CheckSMSCodeReq{Phone: "+34600000000", Purpose: models.SMSPurposeOTP, Code: service.code(models.SMSPurposeOTP, "+34600000000", period)}
+
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "sms-checks:otp:+34600000000", time.Hour).Return(entities.Limit{Count: 1}, nil).Once()
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "sms-used:otp:+34600000000:"+strconv.FormatInt(period, 10), 10*time.Minute).Return(entities.Limit{Count: 2}, nil).Once()
+
+	// Act
+	err := service.CheckCode(context.Background(), req)
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+	assert.ErrorContains(t, err, errSMSCodeNotValid.Error())
+}
+
+// TestSMSCheckCode_Expired checks that CheckCode refuses the codes sent before the previous period and the codes of other purposes
+func TestSMSCheckCode_Expired(t *testing.T) {
+	// Arrange
+	limitStoreMock := mocks.NewLimitStore(t)
+	service := newTestSMSService(mocks.NewSMSSender(t), limitStoreMock)
+	period := service.period(smsTestNow)
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "sms-checks:otp:+34600000000", time.Hour).Return(entities.Limit{Count: 1}, nil).Twice()
+
+	// Act
+	expiredErr := service.CheckCode(context.Background(), models.CheckSMSCodeReq{Phone: "+34600000000", Purpose: models.SMSPurposeOTP, Code: service.code(models.SMSPurposeOTP, "+34600000000", period-2)})
+	purposeErr := service.CheckCode(context.Background(), models.CheckSMSCodeReq{Phone: "+34600000000", Purpose: models.SMSPurposeOTP, Code: service.code(models.SMSPurposePhoneVerification, "+34600000000", period)})
+
+	// Assert
+	assert.ErrorContains(t, expiredErr, errSMSCodeNotValid.Error())
+	assert.ErrorContains(t, purposeErr, errSMSCodeNotValid.Error())
+}
+
+// TestSMSCheckCode_TooMany checks that CheckCode refuses every code once SMS.MaxCheckAttempts have been checked in the window
+func TestSMSCheckCode_TooMany(t *testing.T) {
+	// Arrange
+	limitStoreMock := mocks.NewLimitStore(t)
+	service := newTestSMSService(mocks.NewSMSSender(t), limitStoreMock)
+	req := models.CheckSMSCodeReq{Phone: "+34600000000", Purpose: models.SMSPurposeOTP, Code: service.code(models.SMSPurposeOTP, "+34600000000", service.period(smsTestNow))}
+	limitStoreMock.On(testutils.FunctionName(t, ports.LimitStore.Hit), mock.Anything, "sms-checks:otp:+34600000000", time.Hour).Return(entities.Limit{Count: 6}, nil).Once()
+
+	// Act
+	err := service.CheckCode(context.Background(), req)
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+	assert.ErrorContains(t, err, errTooManySMSChecks.Error())
+}
+
+// TestSMSUpdateStatus_Ok checks that UpdateStatus counts the delivery status of a callback signed by the provider
+func TestSMSUpdateStatus_Ok(t *testing.T) {
+	// Arrange
+	form := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"undelivered"}, "ErrorCode": {"30003"}}
+	senderMock := mocks.NewSMSSender(t)
+	senderMock.On(testutils.FunctionName(t, ports.SMSSender.VerifyCallback), "https://api.test.com/v1/sms/status", form, "test-signature").Return(true).Once()
+	service := newTestSMSService(senderMock, mocks.NewLimitStore(t))
+	undeliveredBefore := counter(smsStatuses, "undelivered")
+
+	// Act
+	err := service.UpdateStatus(context.Background(), form, "test-signature")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, undeliveredBefore+1, counter(smsStatuses, "undelivered"))
+}
+
+// TestSMSUpdateStatus_SignatureNotValid checks that UpdateStatus returns an unauthorized error when the callback is not signed by the provider
+func TestSMSUpdateStatus_SignatureNotValid(t *testing.T) {
+	// Arrange
+	form := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"delivered"}}
+	senderMock := mocks.NewSMSSender(t)
+	senderMock.On(testutils.FunctionName(t, ports.SMSSender.VerifyCallback), "https://api.test.com/v1/sms/status", form, "test-signature").Return(false).Once()
+	service := newTestSMSService(senderMock, mocks.NewLimitStore(t))
+
+	// Act
+	err := service.UpdateStatus(context.Background(), form, "test-signature")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.UnauthorizedErr)
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"io"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer of the service spans
+const tracerName = "github.com/sergicanet9/go-hexagonal-api/core/services"
+
+// tracingUserService decorator of an user service that traces every method
+type tracingUserService struct {
+	service ports.UserService
+	tracer  trace.Tracer
+}
+
+// NewTracingUserService wraps a user service tracing every method as a span named after it
+func NewTracingUserService(service ports.UserService, tp trace.TracerProvider) ports.UserService {
+	return &tracingUserService{
+		service: service,
+		tracer:  tp.Tracer(tracerName),
+	}
+}
+
+// start starts the span of a method, returning the function that ends it with the error of the method
+func (s *tracingUserService) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, func(err error)) {
+	ctx, span := s.tracer.Start(ctx, "UserService."+name, trace.WithAttributes(attrs...))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+func userIDAttribute(ID string) attribute.KeyValue {
+	return attribute.String("user.id", ID)
+}
+
+func (s *tracingUserService) Login(ctx context.Context, credentials models.LoginUserReq) (resp models.LoginUserResp, err error) {
+	ctx, end := s.start(ctx, "Login")
+	defer func() { end(err) }()
+	return s.service.Login(ctx, credentials)
+}
+
+func (s *tracingUserService) Create(ctx context.Context, user models.CreateUserReq) (resp models.CreationResp, err error) {
+	ctx, end := s.start(ctx, "Create")
+	defer func() { end(err) }()
+	return s.service.Create(ctx, user)
+}
+
+func (s *tracingUserService) CreateMany(ctx context.Context, users []models.CreateUserReq) (resp models.MultiCreationResp, err error) {
+	ctx, end := s.start(ctx, "CreateMany", attribute.Int("users.count", len(users)))
+	defer func() { end(err) }()
+	return s.service.CreateMany(ctx, users)
+}
+
+func (s *tracingUserService) GetAll(ctx context.Context, caller models.Caller) (resp []models.UserResp, err error) {
+	ctx, end := s.start(ctx, "GetAll")
+	defer func() { end(err) }()
+	return s.service.GetAll(ctx, caller)
+}
+
+func (s *tracingUserService) GetByEmail(ctx context.Context, email string) (resp models.UserResp, err error) {
+	ctx, end := s.start(ctx, "GetByEmail")
+	defer func() { end(err) }()
+	return s.service.GetByEmail(ctx, email)
+}
+
+func (s *tracingUserService) Search(ctx context.Context, caller models.Caller, req models.SearchUsersReq) (resp []models.UserResp, err error) {
+	ctx, end := s.start(ctx, "Search")
+	defer func() { end(err) }()
+	return s.service.Search(ctx, caller, req)
+}
+
+func (s *tracingUserService) GetNearby(ctx context.Context, caller models.Caller, req models.NearbyUsersReq) (resp []models.UserResp, err error) {
+	ctx, end := s.start(ctx, "GetNearby")
+	defer func() { end(err) }()
+	return s.service.GetNearby(ctx, caller, req)
+}
+
+func (s *tracingUserService) GetByID(ctx context.Context, ID string) (resp models.UserResp, err error) {
+	ctx, end := s.start(ctx, "GetByID", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.GetByID(ctx, ID)
+}
+
+func (s *tracingUserService) Authorize(ctx context.Context, caller models.Caller, ID string) (err error) {
+	ctx, end := s.start(ctx, "Authorize", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.Authorize(ctx, caller, ID)
+}
+
+func (s *tracingUserService) AuthorizeUpdate(ctx context.Context, caller models.Caller, ID string, user models.UpdateUserReq) (err error) {
+	ctx, end := s.start(ctx, "AuthorizeUpdate", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.AuthorizeUpdate(ctx, caller, ID, user)
+}
+
+func (s *tracingUserService) Upsert(ctx context.Context, email string, user models.UpsertUserReq) (resp models.UpsertionResp, err error) {
+	ctx, end := s.start(ctx, "Upsert")
+	defer func() { end(err) }()
+	return s.service.Upsert(ctx, email, user)
+}
+
+func (s *tracingUserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) (err error) {
+	ctx, end := s.start(ctx, "Update", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.Update(ctx, ID, user)
+}
+
+func (s *tracingUserService) Merge(ctx context.Context, ID string, req models.MergeUsersReq) (err error) {
+	ctx, end := s.start(ctx, "Merge", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.Merge(ctx, ID, req)
+}
+
+func (s *tracingUserService) Delete(ctx context.Context, ID string) (err error) {
+	ctx, end := s.start(ctx, "Delete", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.Delete(ctx, ID)
+}
+
+func (s *tracingUserService) ArchiveInactive(ctx context.Context) (resp models.ArchivalResp, err error) {
+	ctx, end := s.start(ctx, "ArchiveInactive")
+	defer func() { end(err) }()
+	return s.service.ArchiveInactive(ctx)
+}
+
+func (s *tracingUserService) ComputeStats(ctx context.Context) (resp models.UserStatsResp, err error) {
+	ctx, end := s.start(ctx, "ComputeStats")
+	defer func() { end(err) }()
+	return s.service.ComputeStats(ctx)
+}
+
+func (s *tracingUserService) Unarchive(ctx context.Context, ID string) (err error) {
+	ctx, end := s.start(ctx, "Unarchive", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.Unarchive(ctx, ID)
+}
+
+func (s *tracingUserService) UploadAvatar(ctx context.Context, ID string, avatar models.UploadAvatarReq) (resp models.FileResp, err error) {
+	ctx, end := s.start(ctx, "UploadAvatar", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.UploadAvatar(ctx, ID, avatar)
+}
+
+func (s *tracingUserService) GetAvatar(ctx context.Context, ID string) (content io.ReadCloser, resp models.FileResp, err error) {
+	ctx, end := s.start(ctx, "GetAvatar", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.GetAvatar(ctx, ID)
+}
+
+func (s *tracingUserService) DeleteAvatar(ctx context.Context, ID string) (err error) {
+	ctx, end := s.start(ctx, "DeleteAvatar", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.DeleteAvatar(ctx, ID)
+}
+
+func (s *tracingUserService) GetAvatarURL(ctx context.Context, ID string) (resp models.PresignedURLResp, err error) {
+	ctx, end := s.start(ctx, "GetAvatarURL", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.GetAvatarURL(ctx, ID)
+}
+
+func (s *tracingUserService) CreateAvatarUploadURL(ctx context.Context, ID string, req models.AvatarUploadURLReq) (resp models.PresignedURLResp, err error) {
+	ctx, end := s.start(ctx, "CreateAvatarUploadURL", userIDAttribute(ID))
+	defer func() { end(err) }()
+	return s.service.CreateAvatarUploadURL(ctx, ID, req)
+}
+
+func (s *tracingUserService) GetUserClaims(ctx context.Context, req models.GetClaimsReq) []models.ClaimResp {
+	ctx, end := s.start(ctx, "GetUserClaims")
+	defer end(nil)
+	return s.service.GetUserClaims(ctx, req)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestTracingUserService_Ok checks that the tracing user service runs the method with the context of a span named after it, child of the span of the context
+func TestTracingUserService_Ok(t *testing.T) {
+	// Arrange
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	expectedResp := models.UserResp{ID: "test-id"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On("GetByID", mock.MatchedBy(func(ctx context.Context) bool {
+		return trace.SpanFromContext(ctx).SpanContext().SpanID() != parent.SpanContext().SpanID()
+	}), expectedResp.ID).Return(expectedResp, nil).Once()
+
+	service := NewTracingUserService(userServiceMock, tp)
+
+	// Act
+	resp, err := service.GetByID(ctx, expectedResp.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResp, resp)
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "UserService.GetByID", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("user.id", expectedResp.ID))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+// TestTracingUserService_Error checks that the tracing user service records the error of the method in its span
+func TestTracingUserService_Error(t *testing.T) {
+	// Arrange
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	expectedError := errors.New("delete error")
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On("Delete", mock.Anything, "test-id").Return(expectedError).Once()
+
+	service := NewTracingUserService(userServiceMock, tp)
+
+	// Act
+	err := service.Delete(context.Background(), "test-id")
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "UserService.Delete", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, expectedError.Error(), spans[0].Status().Description)
+}
@@ -4,83 +4,160 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
 	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/mapping"
 	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// readProjection excludes the fields that are not needed in general user reads
+var readProjection = map[string]interface{}{"password_hash": 0}
+
 // userService adapter of an user service
 type userService struct {
 	config     config.Config
+	logger     zerolog.Logger
 	repository ports.UserRepository
+	storage    ports.FileStorage
+	audit      ports.AuditRepository
+	keys       ports.TokenKeys
+	verifier   ports.CredentialVerifier
+	hashing    *hashingPool
 }
 
-// NewUserService creates a new user service
-func NewUserService(cfg config.Config, repo ports.UserRepository) ports.UserService {
+// NewUserService creates a new user service, hashing the passwords with Hashing.Workers workers, or half the CPUs when not set,
+// and signing the tokens with the given keys. The passwords of the emails handled by the verifier, when not nil, are verified by their directory instead.
+func NewUserService(cfg config.Config, logger zerolog.Logger, repo ports.UserRepository, storage ports.FileStorage, audit ports.AuditRepository, keys ports.TokenKeys, verifier ports.CredentialVerifier) ports.UserService {
+	workers := cfg.Hashing.Workers
+	if workers <= 0 {
+		workers = (runtime.NumCPU() + 1) / 2
+	}
 	return &userService{
 		config:     cfg,
+		logger:     logger,
 		repository: repo,
+		storage:    storage,
+		audit:      audit,
+		keys:       keys,
+		verifier:   verifier,
+		hashing:    newHashingPool(workers),
 	}
 }
 
 // Login user
 func (s *userService) Login(ctx context.Context, credentials models.LoginUserReq) (resp models.LoginUserResp, err error) {
-	user, err := s.validateLogin(ctx, credentials)
+	user, reason, err := s.validateLogin(ctx, credentials)
 	if err != nil {
+		loginFailed(reason)
+		recordFailure(ctx, s.logger, s.audit, entities.AuditLoginFailed, "", map[string]string{"email": normalizeEmail(credentials.Email), "reason": err.Error()})
 		return
 	}
 
-	token, err := createToken(user.ID, s.config.JWTSecret, user.Claims)
+	now := time.Now().UTC()
+	expiresAt := now.Add(models.TokenLifetimeFrom(ctx))
+	token, err := createToken(s.keys, s.config.OIDC.Issuer, user.ID, user.Claims, expiresAt)
 	if err != nil {
+		loginFailed(loginFailureError)
 		return
 	}
 
+	err = s.repository.UpdateLastLogin(ctx, user.ID, now)
+	if err != nil {
+		loginFailed(loginFailureError)
+		return
+	}
+	user.LastLoginAt = &now
+	loginSucceeded()
+
+	// the user logging in is the actor of the login, as the request is not authenticated yet
+	info := models.RequestInfoFrom(ctx)
+	info.ActorID = user.ID
+	record(models.WithRequestInfo(ctx, info), s.logger, s.audit, entities.AuditLoginSucceeded, user.ID, nil)
+
 	resp = models.LoginUserResp{
-		User:  user,
-		Token: token,
+		User:      mapping.UserResp(user),
+		Token:     token,
+		ExpiresAt: expiresAt,
 	}
 
 	return
 }
 
-func (s *userService) validateLogin(ctx context.Context, credentials models.LoginUserReq) (models.UserResp, error) {
+// validateLogin returns the user of the credentials, or the reason of the failure along with the error
+func (s *userService) validateLogin(ctx context.Context, credentials models.LoginUserReq) (entities.User, string, error) {
 	if err := credentials.Validate(); err != nil {
-		return models.UserResp{}, err
+		return entities.User{}, loginFailureInvalidRequest, err
+	}
+	if s.verifier != nil && s.verifier.Handles(credentials.Email) {
+		return s.validateDirectoryLogin(ctx, credentials)
 	}
 
-	user, err := s.GetByEmail(ctx, credentials.Email)
+	user, err := s.getByEmail(ctx, credentials.Email, nil)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		// not reported as a not found, so a login fails with the same status whether the email or the password is wrong
+		return entities.User{}, loginFailureUserNotFound, wrappers.NewNonExistentErr(err)
+	}
 	if err != nil {
-		return models.UserResp{}, err
+		return entities.User{}, loginFailureError, err
 	}
 
-	err = validatePassword(credentials.Password, user.PasswordHash)
+	err = s.validatePassword(ctx, credentials.Password, user.PasswordHash)
+	if errors.Is(err, wrappers.ValidationErr) {
+		return entities.User{}, loginFailurePasswordIncorrect, err
+	}
 	if err != nil {
-		return models.UserResp{}, err
+		return entities.User{}, loginFailureError, err
 	}
 
-	return user, nil
+	return user, "", nil
 }
 
-func validatePassword(password, hash string) error {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+// validatePassword compares the password with the hash in the hashing pool
+func (s *userService) validatePassword(ctx context.Context, password, hash string) error {
+	var err error
+	poolErr := s.hashing.run(ctx, func() {
+		start := time.Now()
+		err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		elapsed := time.Since(start)
+		bcryptCompare.observe(elapsed)
+		models.RequestTimingsFrom(ctx).AddHashing(elapsed)
+	})
+	if poolErr != nil {
+		return poolErr
+	}
 	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
 		err = fmt.Errorf("password incorrect")
 	}
 	return wrappers.NewValidationErr(err)
 }
 
-func createToken(userid string, jwtSecret string, claims []int64) (string, error) {
+// createToken signs a token of the user with its claims expiring at the given time, issued by the issuer when set.
+// The names of the roles are set in the roles claim, and their permissions granted as the claims of the user are.
+func createToken(keys ports.TokenKeys, issuer, userid string, claims []int64, expiresAt time.Time, roles ...entities.Role) (string, error) {
 	var err error
+	now := time.Now().UTC()
 	addClaims := jwt.MapClaims{}
 	addClaims["authorized"] = true
 	addClaims["user_id"] = userid
-	addClaims["exp"] = time.Now().UTC().Add(time.Hour * 168).Unix()
+	addClaims["sub"] = userid
+	addClaims["iat"] = now.Unix()
+	addClaims["exp"] = expiresAt.Unix()
+	if issuer != "" {
+		addClaims["iss"] = issuer
+	}
 
 	err = validateClaims(claims)
 	if err != nil {
@@ -89,10 +166,27 @@ func createToken(userid string, jwtSecret string, claims []int64) (string, error
 	for _, claim := range claims {
 		addClaims[entities.UserClaim(claim).String()] = true
 	}
+	if len(roles) > 0 {
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			names = append(names, role.Name)
+			for _, permission := range role.Permissions {
+				addClaims[permission] = true
+			}
+		}
+		addClaims["roles"] = names
+	}
 
-	add := jwt.NewWithClaims(jwt.SigningMethodHS256, addClaims)
-	token, err := add.SignedString([]byte(jwtSecret))
-	return token, err
+	return keys.Sign(addClaims)
+}
+
+// joinClaims returns the claims separated by commas, like 0,1
+func joinClaims(claims []int64) string {
+	values := make([]string, 0, len(claims))
+	for _, c := range claims {
+		values = append(values, strconv.FormatInt(c, 10))
+	}
+	return strings.Join(values, ",")
 }
 
 func validateClaims(claims []int64) error {
@@ -105,12 +199,13 @@ func validateClaims(claims []int64) error {
 }
 
 // Create user
-func (s *userService) Create(ctx context.Context, user models.CreateUserReq) (resp models.CreationResp, err error) {
-	if err = user.Validate(); err != nil {
+func (s *userService) Create(ctx context.Context, req models.CreateUserReq) (resp models.CreationResp, err error) {
+	if err = req.Validate(); err != nil {
 		return
 	}
 
-	err = hashPassword(&user.PasswordHash)
+	user := mapping.User(req)
+	err = s.hashPassword(ctx, &user.PasswordHash)
 	if err != nil {
 		return
 	}
@@ -121,12 +216,15 @@ func (s *userService) Create(ctx context.Context, user models.CreateUserReq) (re
 	}
 
 	now := time.Now().UTC()
+	user.Email = normalizeEmail(user.Email)
 	user.CreatedAt = now
 	user.UpdatedAt = now
-	insertedID, err := s.repository.Create(ctx, entities.User(user))
+	insertedID, err := s.repository.Create(ctx, user)
 	if err != nil {
 		return
 	}
+	signups.Add(1)
+	record(ctx, s.logger, s.audit, entities.AuditUserCreated, insertedID, nil)
 
 	resp = models.CreationResp{
 		InsertedID: insertedID,
@@ -135,8 +233,25 @@ func (s *userService) Create(ctx context.Context, user models.CreateUserReq) (re
 	return
 }
 
-func hashPassword(password *string) error {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+// normalizeEmail lower-cases an email, so addresses only differing in case belong to the same user
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// hashPassword replaces the password with its hash, hashed in the hashing pool
+func (s *userService) hashPassword(ctx context.Context, password *string) error {
+	var bytes []byte
+	var err error
+	poolErr := s.hashing.run(ctx, func() {
+		start := time.Now()
+		bytes, err = bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+		elapsed := time.Since(start)
+		bcryptHash.observe(elapsed)
+		models.RequestTimingsFrom(ctx).AddHashing(elapsed)
+	})
+	if poolErr != nil {
+		return poolErr
+	}
 	if err != nil {
 		return err
 	}
@@ -149,12 +264,13 @@ func (s *userService) CreateMany(ctx context.Context, users []models.CreateUserR
 	var create []interface{}
 	now := time.Now().UTC()
 
-	for _, user := range users {
-		if err = user.Validate(); err != nil {
+	for _, req := range users {
+		if err = req.Validate(); err != nil {
 			return
 		}
 
-		err = hashPassword(&user.PasswordHash)
+		user := mapping.User(req)
+		err = s.hashPassword(ctx, &user.PasswordHash)
 		if err != nil {
 			return
 		}
@@ -164,16 +280,21 @@ func (s *userService) CreateMany(ctx context.Context, users []models.CreateUserR
 			return
 		}
 
+		user.Email = normalizeEmail(user.Email)
 		user.CreatedAt = now
 		user.UpdatedAt = now
 
-		create = append(create, entities.User(user))
+		create = append(create, user)
 	}
 
 	insertedIDs, err := s.repository.CreateMany(ctx, create)
 	if err != nil {
 		return
 	}
+	signups.Add(int64(len(insertedIDs)))
+	for _, id := range insertedIDs {
+		record(ctx, s.logger, s.audit, entities.AuditUserCreated, id, nil)
+	}
 
 	resp = models.MultiCreationResp{
 		InsertedIDs: insertedIDs,
@@ -181,9 +302,10 @@ func (s *userService) CreateMany(ctx context.Context, users []models.CreateUserR
 	return
 }
 
-// GetAll users
-func (s *userService) GetAll(ctx context.Context) (resp []models.UserResp, err error) {
-	result, err := s.repository.Get(ctx, map[string]interface{}{}, nil, nil)
+// GetAll users, every one of them whatever the caller, the organizations scoping them
+func (s *userService) GetAll(ctx context.Context, caller models.Caller) (resp []models.UserResp, err error) {
+	ctx = s.withReadPreference(ctx, "GetAll")
+	result, err := s.repository.GetProjected(ctx, map[string]interface{}{}, readProjection, nil, nil)
 	if err != nil {
 		if errors.Is(err, wrappers.NonExistentErr) {
 			err = nil
@@ -191,73 +313,207 @@ func (s *userService) GetAll(ctx context.Context) (resp []models.UserResp, err e
 		return
 	}
 
-	resp = make([]models.UserResp, len(result))
-	for i, v := range result {
-		resp[i] = models.UserResp(*(v.(*entities.User)))
-	}
+	resp = mapping.UsersResp(result)
 
 	return
 }
 
+// withReadPreference applies to the context the read preference configured for the operation, if any,
+// so heavy listings can be served by secondary replicas while the primary keeps serving the logins
+func (s *userService) withReadPreference(ctx context.Context, operation string) context.Context {
+	if rp, ok := s.config.ReadPreferences[operation]; ok {
+		return ports.WithReadPreference(ctx, ports.ReadPreference(rp))
+	}
+	return ctx
+}
+
 // GetByEmail user
-func (s *userService) GetByEmail(ctx context.Context, email string) (resp models.UserResp, err error) {
-	filter := map[string]interface{}{"email": email}
-	result, err := s.repository.Get(ctx, filter, nil, nil)
+func (s *userService) GetByEmail(ctx context.Context, email string) (models.UserResp, error) {
+	user, err := s.getByEmail(ctx, email, readProjection)
+	if err != nil {
+		return models.UserResp{}, err
+	}
+	return mapping.UserResp(user), nil
+}
+
+func (s *userService) getByEmail(ctx context.Context, email string, projection map[string]interface{}) (user entities.User, err error) {
+	filter := map[string]interface{}{"email": normalizeEmail(email)}
+	result, err := s.repository.GetProjected(ctx, filter, projection, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = apierror.NotFound(fmt.Errorf("email %s not found", email))
+		}
+		return
+	}
+
+	user = *(result[0].(*entities.User))
+
+	return
+}
+
+// Search users by name, surnames or email
+func (s *userService) Search(ctx context.Context, caller models.Caller, req models.SearchUsersReq) (resp []models.UserResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	ctx = s.withReadPreference(ctx, "Search")
+	autocomplete := req.Mode == models.SearchModeAutocomplete
+	result, err := s.repository.Search(ctx, req.Query, autocomplete, readProjection, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	resp = mapping.UsersResp(result)
+
+	return
+}
+
+// GetNearby gets the users located within the radius, in meters, of a point, sorted by distance
+func (s *userService) GetNearby(ctx context.Context, caller models.Caller, req models.NearbyUsersReq) (resp []models.UserResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	ctx = s.withReadPreference(ctx, "GetNearby")
+	result, err := s.repository.GetNearby(ctx, req.Longitude, req.Latitude, req.Radius, readProjection, nil, nil)
 	if err != nil {
 		if errors.Is(err, wrappers.NonExistentErr) {
-			err = wrappers.NewNonExistentErr(fmt.Errorf("email %s not found", email))
+			err = nil
 		}
 		return
 	}
 
-	resp = models.UserResp(*(result[0].(*entities.User)))
+	resp = mapping.UsersResp(result)
 
 	return
 }
 
 // GetByID user
-func (s *userService) GetByID(ctx context.Context, ID string) (resp models.UserResp, err error) {
-	user, err := s.repository.GetByID(ctx, ID)
+func (s *userService) GetByID(ctx context.Context, ID string) (models.UserResp, error) {
+	user, err := s.getByID(ctx, ID, readProjection)
+	if err != nil {
+		return models.UserResp{}, err
+	}
+	return mapping.UserResp(user), nil
+}
+
+// Authorize allows every caller to every user, the organizations scoping them
+func (s *userService) Authorize(ctx context.Context, caller models.Caller, ID string) error {
+	return nil
+}
+
+// AuthorizeUpdate allows the admins to update every user, and the others to update themselves but not their claims
+func (s *userService) AuthorizeUpdate(ctx context.Context, caller models.Caller, ID string, user models.UpdateUserReq) error {
+	if caller.Admin {
+		return nil
+	}
+	if caller.UserID != ID {
+		return apierror.Unauthorized(fmt.Errorf("user ID %s is only updated by the user and the admins", ID))
+	}
+	if user.Claims != nil {
+		return apierror.Unauthorized(fmt.Errorf("the claims of user ID %s are only updated by the admins", ID))
+	}
+	return nil
+}
+
+func (s *userService) getByID(ctx context.Context, ID string, projection map[string]interface{}) (user entities.User, err error) {
+	result, err := s.repository.GetByIDProjected(ctx, ID, projection)
 	if err != nil {
 		if errors.Is(err, wrappers.NonExistentErr) {
-			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+			err = apierror.NotFound(fmt.Errorf("ID %s not found", ID))
 		}
 		return
 	}
 
-	resp = models.UserResp(*user.(*entities.User))
+	user = *result.(*entities.User)
+
+	return
+}
+
+// Upsert user by email
+func (s *userService) Upsert(ctx context.Context, email string, user models.UpsertUserReq) (resp models.UpsertionResp, err error) {
+	email = normalizeEmail(email)
+	user.Email = email
+	if err = user.Validate(); err != nil {
+		return
+	}
+
+	if user.Password != "" {
+		err = s.hashPassword(ctx, &user.Password)
+		if err != nil {
+			return
+		}
+	}
+
+	err = validateClaims(user.Claims)
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	entity := mapping.UpsertedUser(user)
+	entity.CreatedAt = now
+	entity.UpdatedAt = now
+
+	filter := map[string]interface{}{"email": email}
+	id, err := s.repository.Upsert(ctx, filter, entity)
+	if err != nil {
+		return
+	}
+	record(ctx, s.logger, s.audit, entities.AuditUserUpserted, id, nil)
+
+	resp = models.UpsertionResp{
+		ID: id,
+	}
 
 	return
 }
 
 // Update user
 func (s *userService) Update(ctx context.Context, ID string, user models.UpdateUserReq) (err error) {
-	dbUser, err := s.GetByID(ctx, ID)
+	if err = user.Validate(); err != nil {
+		return
+	}
+
+	dbUser, err := s.getByID(ctx, ID, nil)
 	if err != nil {
 		return
 	}
 
+	var fields []string
 	if user.Name != nil {
 		dbUser.Name = *user.Name
+		fields = append(fields, "name")
 	}
 	if user.Surnames != nil {
 		dbUser.Surnames = *user.Surnames
+		fields = append(fields, "surnames")
 	}
 	if user.Email != nil {
-		dbUser.Email = *user.Email
+		dbUser.Email = normalizeEmail(*user.Email)
+		fields = append(fields, "email")
 	}
 	if user.NewPassword != nil {
-		err = validatePassword(*user.OldPassword, dbUser.PasswordHash)
+		if s.verifier != nil && s.verifier.Handles(dbUser.Email) {
+			return wrappers.NewValidationErr(fmt.Errorf("the password of %s is managed by its directory", dbUser.Email))
+		}
+		err = s.validatePassword(ctx, *user.OldPassword, dbUser.PasswordHash)
 		if err != nil {
+			recordFailure(ctx, s.logger, s.audit, entities.AuditPasswordChanged, ID, map[string]string{"reason": err.Error()})
 			return
 		}
 
-		err = hashPassword(user.NewPassword)
+		err = s.hashPassword(ctx, user.NewPassword)
 		if err != nil {
 			return
 		}
 
 		dbUser.PasswordHash = *user.NewPassword
+		fields = append(fields, "password")
 	}
 	if user.Claims != nil {
 		err = validateClaims(*user.Claims)
@@ -265,26 +521,312 @@ func (s *userService) Update(ctx context.Context, ID string, user models.UpdateU
 			return err
 		}
 		dbUser.Claims = *user.Claims
+		fields = append(fields, "claims")
+	}
+	if user.Location != nil {
+		dbUser.Location = user.Location
+		fields = append(fields, "location")
 	}
 	dbUser.ID = ""
 	dbUser.UpdatedAt = time.Now().UTC()
+	clearBilling(&dbUser)
 
-	err = s.repository.Update(ctx, ID, entities.User(dbUser))
-	return err
+	err = s.repository.Update(ctx, ID, dbUser)
+	if err != nil {
+		return
+	}
+	record(ctx, s.logger, s.audit, entities.AuditUserUpdated, ID, map[string]string{"fields": strings.Join(fields, ",")})
+	if user.NewPassword != nil {
+		passwordChanges.Add(1)
+		record(ctx, s.logger, s.audit, entities.AuditPasswordChanged, ID, nil)
+	}
+	if user.Claims != nil {
+		record(ctx, s.logger, s.audit, entities.AuditClaimsChanged, ID, map[string]string{"claims": joinClaims(*user.Claims)})
+	}
+	return
+}
+
+// Merge moves the data of the source user into the user with the given ID and deletes the source user, atomically.
+// Empty fields of the target user are filled with the ones of the source user, and claims are combined.
+func (s *userService) Merge(ctx context.Context, ID string, req models.MergeUsersReq) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if req.SourceID == ID {
+		return wrappers.NewValidationErr(fmt.Errorf("a user cannot be merged into itself"))
+	}
+
+	err = s.repository.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.getByID(ctx, ID, nil)
+		if err != nil {
+			return err
+		}
+
+		source, err := s.getByID(ctx, req.SourceID, nil)
+		if err != nil {
+			return err
+		}
+
+		if target.Name == "" {
+			target.Name = source.Name
+		}
+		if target.Surnames == "" {
+			target.Surnames = source.Surnames
+		}
+		if target.Location == nil {
+			target.Location = source.Location
+		}
+		for _, claim := range source.Claims {
+			if !containsClaim(target.Claims, claim) {
+				target.Claims = append(target.Claims, claim)
+			}
+		}
+		target.ID = ""
+		target.UpdatedAt = time.Now().UTC()
+		clearBilling(&target)
+
+		err = s.repository.Update(ctx, ID, target)
+		if err != nil {
+			return err
+		}
+
+		err = s.repository.Delete(ctx, req.SourceID)
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = apierror.NotFound(fmt.Errorf("ID %s not found", req.SourceID))
+		}
+		return err
+	})
+	if err != nil {
+		return
+	}
+	record(ctx, s.logger, s.audit, entities.AuditUserMerged, ID, map[string]string{"source_id": req.SourceID})
+
+	// files are not part of the transaction, so the avatar of the source user is removed once it is committed
+	err = s.removeAvatar(ctx, req.SourceID)
+	return
+}
+
+// clearBilling clears the billing fields of a user read to be updated, so they are left as they are in the repository,
+// as the billing service can have synced them meanwhile
+func clearBilling(user *entities.User) {
+	user.BillingCustomerID = ""
+	user.SubscriptionStatus = ""
+	user.SubscriptionUpdatedAt = nil
+}
+
+func containsClaim(claims []int64, claim int64) bool {
+	for _, c := range claims {
+		if c == claim {
+			return true
+		}
+	}
+	return false
 }
 
 // Delete user
 func (s *userService) Delete(ctx context.Context, ID string) (err error) {
 	err = s.repository.Delete(ctx, ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = apierror.NotFound(fmt.Errorf("ID %s not found", ID))
+		}
+		return
+	}
+	record(ctx, s.logger, s.audit, entities.AuditUserDeleted, ID, nil)
+
+	err = s.removeAvatar(ctx, ID)
+	return
+}
+
+// ArchiveInactive moves the users that have not logged in nor been updated during the configured inactivity period to the archive
+func (s *userService) ArchiveInactive(ctx context.Context) (resp models.ArchivalResp, err error) {
+	now := time.Now().UTC()
+	count, err := s.repository.Archive(ports.WithOperationTimeout(ctx, 0), now.Add(-s.config.Archive.InactivityPeriod.Duration), now)
+	if err != nil {
+		return
+	}
+	record(ctx, s.logger, s.audit, entities.AuditUsersArchived, "", map[string]string{"count": strconv.FormatInt(count, 10)})
+
+	resp = models.ArchivalResp{
+		ArchivedCount: count,
+	}
+	return
+}
+
+// ComputeStats counts the users, the active ones being the ones that would not be archived, keeping them as the last stats exported by the metrics
+func (s *userService) ComputeStats(ctx context.Context) (resp models.UserStatsResp, err error) {
+	now := time.Now().UTC()
+	stats, err := s.repository.Stats(ctx, now.Add(-s.config.Archive.InactivityPeriod.Duration))
+	if err != nil {
+		return
+	}
+
+	resp = models.UserStatsResp{
+		Total:      stats.Total,
+		Active:     stats.Active,
+		Archived:   stats.Archived,
+		ComputedAt: now,
+	}
+	userStatsComputed(resp)
+	return
+}
+
+// Unarchive moves an archived user back to the active users
+func (s *userService) Unarchive(ctx context.Context, ID string) (err error) {
+	err = s.repository.Unarchive(ctx, ID, time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = apierror.NotFound(fmt.Errorf("archived user ID %s not found", ID))
+		}
+		return
+	}
+	record(ctx, s.logger, s.audit, entities.AuditUserUnarchived, ID, nil)
+
+	return
+}
+
+// UploadAvatar of a user, replacing the previous one if any
+func (s *userService) UploadAvatar(ctx context.Context, ID string, avatar models.UploadAvatarReq) (resp models.FileResp, err error) {
+	if err = avatar.Validate(); err != nil {
+		return
+	}
+
+	_, err = s.getByID(ctx, ID, readProjection)
+	if err != nil {
+		return
+	}
+
+	file := entities.File{
+		Key:         avatarKey(ID),
+		Name:        avatar.Name,
+		ContentType: avatar.ContentType,
+		Metadata:    map[string]string{"user_id": ID},
+	}
+	file, err = s.storage.Store(ctx, file, avatar.Content)
+	if err != nil {
+		return
+	}
+
+	resp = models.FileResp(file)
+	return
+}
+
+// GetAvatar of a user, the returned content must be closed by the caller
+func (s *userService) GetAvatar(ctx context.Context, ID string) (content io.ReadCloser, resp models.FileResp, err error) {
+	content, file, err := s.storage.Open(ctx, avatarKey(ID))
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = apierror.NotFound(fmt.Errorf("avatar of user ID %s not found", ID))
+		}
+		return
+	}
+
+	resp = models.FileResp(file)
+	return
+}
+
+// DeleteAvatar of a user
+func (s *userService) DeleteAvatar(ctx context.Context, ID string) (err error) {
+	err = s.storage.Delete(ctx, avatarKey(ID))
 	if errors.Is(err, wrappers.NonExistentErr) {
-		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+		err = apierror.NotFound(fmt.Errorf("avatar of user ID %s not found", ID))
+	}
+
+	return
+}
+
+// GetAvatarURL of a user, a URL downloading its avatar directly from the file storage, valid for Storage.PresignExpiry
+func (s *userService) GetAvatarURL(ctx context.Context, ID string) (resp models.PresignedURLResp, err error) {
+	presigner, err := s.presigner()
+	if err != nil {
+		return
+	}
+	if _, err = s.storage.Stat(ctx, avatarKey(ID)); err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = apierror.NotFound(fmt.Errorf("avatar of user ID %s not found", ID))
+		}
+		return
+	}
+
+	expiry := s.config.Storage.PresignExpiry.Duration
+	URL, err := presigner.PresignGet(ctx, avatarKey(ID), expiry)
+	if err != nil {
+		return
 	}
 
+	resp = models.PresignedURLResp{URL: URL, Method: http.MethodGet, ExpiresAt: time.Now().UTC().Add(expiry)}
 	return
 }
 
-// GetClaims user
-func (s *userService) GetUserClaims(ctx context.Context) (claims map[int]string) {
-	claims = entities.GetUserClaims()
+// CreateAvatarUploadURL of a user, a URL uploading its avatar directly to the file storage, replacing the previous one, valid for Storage.PresignExpiry.
+// The avatar must be uploaded with the headers returned, which fix its content type and size.
+func (s *userService) CreateAvatarUploadURL(ctx context.Context, ID string, req models.AvatarUploadURLReq) (resp models.PresignedURLResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if req.Size > s.config.Storage.MaxUploadSize {
+		err = wrappers.NewValidationErr(fmt.Errorf("size cannot be greater than %d", s.config.Storage.MaxUploadSize))
+		return
+	}
+	presigner, err := s.presigner()
+	if err != nil {
+		return
+	}
+	_, err = s.getByID(ctx, ID, readProjection)
+	if err != nil {
+		return
+	}
+
+	file := entities.File{
+		Key:         avatarKey(ID),
+		Name:        req.Name,
+		ContentType: req.ContentType,
+		Size:        req.Size,
+		Metadata:    map[string]string{"user_id": ID},
+	}
+	expiry := s.config.Storage.PresignExpiry.Duration
+	URL, headers, err := presigner.PresignPut(ctx, file, expiry)
+	if err != nil {
+		return
+	}
+
+	resp = models.PresignedURLResp{URL: URL, Method: http.MethodPut, Headers: headers, ExpiresAt: time.Now().UTC().Add(expiry)}
 	return
 }
+
+// presigner returns the file storage as a presigner, failing when it cannot presign URLs
+func (s *userService) presigner() (ports.FilePresigner, error) {
+	presigner, ok := s.storage.(ports.FilePresigner)
+	if !ok {
+		return nil, wrappers.NewValidationErr(errors.New("presigned URLs not supported by the file storage"))
+	}
+	return presigner, nil
+}
+
+// removeAvatar deletes the avatar of a user if it has one
+func (s *userService) removeAvatar(ctx context.Context, ID string) error {
+	err := s.storage.Delete(ctx, avatarKey(ID))
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = nil
+	}
+	return err
+}
+
+func avatarKey(ID string) string {
+	return "avatars/" + ID
+}
+
+// GetUserClaims gets the claims of the users matching the filters, sorted by value
+func (s *userService) GetUserClaims(ctx context.Context, req models.GetClaimsReq) []models.ClaimResp {
+	claims := []models.ClaimResp{}
+	for _, definition := range entities.GetUserClaimDefinitions() {
+		if req.Category != "" && definition.Category != req.Category {
+			continue
+		}
+		if req.Deprecated != nil && definition.Deprecated != *req.Deprecated {
+			continue
+		}
+		claims = append(claims, mapping.ClaimResp(definition))
+	}
+	return claims
+}
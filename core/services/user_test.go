@@ -2,14 +2,24 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
 	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/mapping"
 	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
 	"github.com/sergicanet9/scv-go-tools/v3/testutils"
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
 	"github.com/stretchr/testify/assert"
@@ -21,9 +31,10 @@ func TestNewUserService_Ok(t *testing.T) {
 	// Arrange
 	cfg := config.Config{}
 	userRepositoryMock := mocks.NewUserRepository(t)
+	fileStorageMock := mocks.NewFileStorage(t)
 
 	// Act
-	service := NewUserService(cfg, userRepositoryMock)
+	service := NewUserService(cfg, zerolog.Nop(), userRepositoryMock, fileStorageMock, mocks.NewAuditRepository(t), mocks.NewTokenKeys(t), nil)
 
 	// Assert
 	assert.NotEmpty(t, service)
@@ -48,19 +59,153 @@ func TestLogin_Ok(t *testing.T) {
 
 	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), filter, nilPointer, nilPointer).Return(result, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), filter, map[string]interface{}(nil), nilPointer, nilPointer).Return(result, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateLastLogin), context.Background(), expectedUser.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+	tokenKeysMock := mocks.NewTokenKeys(t)
+	tokenKeysMock.On(testutils.FunctionName(t, ports.TokenKeys.Sign), mock.Anything).Return("test-token", nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
 		repository: userRepositoryMock,
+		keys:       tokenKeysMock,
 	}
+	succeeded := counter(logins, "succeeded")
+	compared := bcryptCompare.count
 
 	// Act
 	resp, err := service.Login(context.Background(), req)
 
 	// Assert
 	assert.Nil(t, err)
-	assert.Equal(t, models.UserResp(expectedUser), resp.User)
+	assert.NotNil(t, resp.User.LastLoginAt)
+	resp.User.LastLoginAt = nil
+	assert.Equal(t, mapping.UserResp(expectedUser), resp.User)
+	assert.Equal(t, "test-token", resp.Token)
+	assert.Equal(t, succeeded+1, counter(logins, "succeeded"))
+	assert.Equal(t, compared+1, bcryptCompare.count)
+}
+
+// TestLogin_Issuer checks that Login signs the token with the subject and, when the OIDC issuer is configured, the issuer claims
+func TestLogin_Issuer(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{
+		Email:    "test@test.com",
+		Password: "test",
+	}
+
+	filter := map[string]interface{}{"email": req.Email}
+	expectedUser := entities.User{
+		ID:           "test-id",
+		Email:        req.Email,
+		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
+	}
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), filter, map[string]interface{}(nil), nilPointer, nilPointer).Return([]interface{}{&expectedUser}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateLastLogin), context.Background(), expectedUser.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+	var signed jwt.MapClaims
+	tokenKeysMock := mocks.NewTokenKeys(t)
+	tokenKeysMock.On(testutils.FunctionName(t, ports.TokenKeys.Sign), mock.Anything).Run(func(args mock.Arguments) {
+		signed = args.Get(0).(jwt.MapClaims)
+	}).Return("test-token", nil).Once()
+
+	cfg := config.Config{}
+	cfg.OIDC.Issuer = "https://api.example.com"
+	service := &userService{
+		config:     cfg,
+		repository: userRepositoryMock,
+		keys:       tokenKeysMock,
+	}
+
+	// Act
+	_, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "https://api.example.com", signed["iss"])
+	assert.Equal(t, "test-id", signed["sub"])
+	assert.NotNil(t, signed["iat"])
+}
+
+// TestLogin_TokenLifetime checks that Login signs the token expiring after the lifetime set in the context, responding with its expiration
+func TestLogin_TokenLifetime(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{
+		Email:    "test@test.com",
+		Password: "test",
+	}
+
+	filter := map[string]interface{}{"email": req.Email}
+	expectedUser := entities.User{
+		ID:           "test-id",
+		Email:        req.Email,
+		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
+	}
+	ctx := models.WithTokenLifetime(context.Background(), time.Hour)
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), ctx, filter, map[string]interface{}(nil), nilPointer, nilPointer).Return([]interface{}{&expectedUser}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateLastLogin), ctx, expectedUser.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+	var signed jwt.MapClaims
+	tokenKeysMock := mocks.NewTokenKeys(t)
+	tokenKeysMock.On(testutils.FunctionName(t, ports.TokenKeys.Sign), mock.Anything).Run(func(args mock.Arguments) {
+		signed = args.Get(0).(jwt.MapClaims)
+	}).Return("test-token", nil).Once()
+
+	service := &userService{
+		repository: userRepositoryMock,
+		keys:       tokenKeysMock,
+	}
+
+	// Act
+	resp, err := service.Login(ctx, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), resp.ExpiresAt, time.Minute)
+	assert.Equal(t, resp.ExpiresAt.Unix(), signed["exp"])
+}
+
+// TestLogin_UpdateLastLoginError checks that Login returns an error when the last login cannot be recorded
+func TestLogin_UpdateLastLoginError(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{
+		Email:    "test@test.com",
+		Password: "test",
+	}
+
+	filter := map[string]interface{}{"email": req.Email}
+	var result []interface{}
+	expectedUser := entities.User{
+		Email:        req.Email,
+		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
+		Claims:       []int64{0},
+	}
+	result = append(result, &expectedUser)
+
+	expectedError := "repository error"
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), filter, map[string]interface{}(nil), nilPointer, nilPointer).Return(result, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateLastLogin), context.Background(), expectedUser.ID, mock.AnythingOfType("time.Time")).Return(errors.New(expectedError)).Once()
+	tokenKeysMock := mocks.NewTokenKeys(t)
+	tokenKeysMock.On(testutils.FunctionName(t, ports.TokenKeys.Sign), mock.Anything).Return("test-token", nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		keys:       tokenKeysMock,
+	}
+
+	// Act
+	_, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
 }
 
 // TestLogin_NotFound checks that Login returns an error when the user is not found
@@ -76,12 +221,13 @@ func TestLogin_NotFound(t *testing.T) {
 
 	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), filter, nilPointer, nilPointer).Return(nil, wrappers.NonExistentErr).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), filter, map[string]interface{}(nil), nilPointer, nilPointer).Return(nil, wrappers.NonExistentErr).Once()
 
 	service := &userService{
 		config:     config.Config{},
 		repository: userRepositoryMock,
 	}
+	notFound := counter(loginFailures, loginFailureUserNotFound)
 
 	// Act
 	_, err := service.Login(context.Background(), req)
@@ -90,6 +236,38 @@ func TestLogin_NotFound(t *testing.T) {
 	assert.NotEmpty(t, err)
 	assert.IsType(t, wrappers.NonExistentErr, err)
 	assert.Equal(t, expectedError, err.Error())
+	assert.Equal(t, notFound+1, counter(loginFailures, loginFailureUserNotFound))
+}
+
+// TestLogin_AuditFailure checks that Login records a failed login event when the user cannot log in
+func TestLogin_AuditFailure(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{
+		Email:    "test@test.com",
+		Password: "test",
+	}
+
+	filter := map[string]interface{}{"email": req.Email}
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), filter, map[string]interface{}(nil), nilPointer, nilPointer).Return(nil, wrappers.NonExistentErr).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(event entities.AuditEvent) bool {
+		return event.Type == entities.AuditLoginFailed && event.Details["email"] == req.Email && event.Details["reason"] != ""
+	})).Return(nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		audit:      auditRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.NotNil(t, err)
 }
 
 // TestLogin_InvalidRequest checks that Login returns an error when the received request is not valid
@@ -112,7 +290,7 @@ func TestLogin_InvalidRequest(t *testing.T) {
 
 	// Assert
 	assert.NotEmpty(t, err)
-	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
 	assert.Equal(t, expectedError, err.Error())
 }
 
@@ -136,7 +314,7 @@ func TestLogin_IncorrectPassword(t *testing.T) {
 
 	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), filter, nilPointer, nilPointer).Return(result, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), filter, map[string]interface{}(nil), nilPointer, nilPointer).Return(result, nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -152,6 +330,49 @@ func TestLogin_IncorrectPassword(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestLogin_HashingTimeout checks that Login returns the error of the context, counting it as an error and not as an incorrect password,
+// when no hashing worker gets free before it is done
+func TestLogin_HashingTimeout(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{
+		Email:    "test@test.com",
+		Password: "test",
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	filter := map[string]interface{}{"email": req.Email}
+	var result []interface{}
+	expectedUser := entities.User{
+		Email:        req.Email,
+		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
+	}
+	result = append(result, &expectedUser)
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), ctx, filter, map[string]interface{}(nil), nilPointer, nilPointer).Return(result, nil).Once()
+
+	pool := newHashingPool(1)
+	pool.workers <- struct{}{}
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		hashing:    pool,
+	}
+
+	errorFailures := counter(loginFailures, loginFailureError)
+	incorrectFailures := counter(loginFailures, loginFailurePasswordIncorrect)
+
+	// Act
+	_, err := service.Login(ctx, req)
+
+	// Assert
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, errorFailures+1, counter(loginFailures, loginFailureError))
+	assert.Equal(t, incorrectFailures, counter(loginFailures, loginFailurePasswordIncorrect))
+}
+
 // TestLogin_InvalidClaims checks that Login returns an error when the claims returned from the repository are not valid
 func TestLogin_InvalidClaims(t *testing.T) {
 	// Arrange
@@ -173,7 +394,7 @@ func TestLogin_InvalidClaims(t *testing.T) {
 
 	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), filter, nilPointer, nilPointer).Return(result, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), filter, map[string]interface{}(nil), nilPointer, nilPointer).Return(result, nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -193,8 +414,8 @@ func TestLogin_InvalidClaims(t *testing.T) {
 func TestCreate_Ok(t *testing.T) {
 	// Arrange
 	req := models.CreateUserReq{
-		Email:        "test@test.com",
-		PasswordHash: "test",
+		Email:    "test@test.com",
+		Password: "test",
 	}
 
 	expectedResponse := models.CreationResp{
@@ -208,6 +429,8 @@ func TestCreate_Ok(t *testing.T) {
 		config:     config.Config{},
 		repository: userRepositoryMock,
 	}
+	created := signups.Value()
+	hashed := bcryptHash.count
 
 	// Act
 	resp, err := service.Create(context.Background(), req)
@@ -215,14 +438,67 @@ func TestCreate_Ok(t *testing.T) {
 	// Assert
 	assert.Nil(t, err)
 	assert.Equal(t, expectedResponse, resp)
+	assert.Equal(t, created+1, signups.Value())
+	assert.Equal(t, hashed+1, bcryptHash.count)
+}
+
+// TestCreate_RequestTimings checks that Create adds the time spent hashing the password to the request timings of the context
+func TestCreate_RequestTimings(t *testing.T) {
+	// Arrange
+	req := models.CreateUserReq{
+		Email:    "test@test.com",
+		Password: "test",
+	}
+	timings := &models.RequestTimings{}
+	ctx := models.WithRequestTimings(context.Background(), timings)
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), ctx, mock.AnythingOfType("entities.User")).Return("new-id", nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Create(ctx, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Greater(t, timings.Hashing(), time.Duration(0))
+}
+
+// TestCreate_NormalizedEmail checks that Create stores the email in lower case, so it cannot be registered again with other case
+func TestCreate_NormalizedEmail(t *testing.T) {
+	// Arrange
+	req := models.CreateUserReq{
+		Email:    " Test@Test.COM",
+		Password: "test",
+	}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), context.Background(), mock.MatchedBy(func(user entities.User) bool {
+		return user.Email == "test@test.com"
+	})).Return("new-id", nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
 }
 
 // TestCreate_CreateError checks that Create returns an error when the Create function from the repository fails
 func TestCreate_CreateError(t *testing.T) {
 	// Arrange
 	req := models.CreateUserReq{
-		Email:        "test@test.com",
-		PasswordHash: "test",
+		Email:    "test@test.com",
+		Password: "test",
 	}
 
 	expectedError := "repository-error"
@@ -247,8 +523,8 @@ func TestCreate_CreateError(t *testing.T) {
 func TestCreate_InvalidRequest(t *testing.T) {
 	// Arrange
 	req := models.CreateUserReq{
-		Email:        "",
-		PasswordHash: "",
+		Email:    "",
+		Password: "",
 	}
 
 	expectedError := "email cannot be empty | password cannot be empty"
@@ -263,7 +539,7 @@ func TestCreate_InvalidRequest(t *testing.T) {
 
 	// Assert
 	assert.NotEmpty(t, err)
-	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
 	assert.Equal(t, expectedError, err.Error())
 }
 
@@ -271,9 +547,9 @@ func TestCreate_InvalidRequest(t *testing.T) {
 func TestCreate_InvalidClaims(t *testing.T) {
 	// Arrange
 	req := models.CreateUserReq{
-		Email:        "test@test.com",
-		PasswordHash: "test",
-		Claims:       []int64{3},
+		Email:    "test@test.com",
+		Password: "test",
+		Claims:   []int64{3},
 	}
 
 	expectedError := "claim 3 is not valid"
@@ -297,8 +573,8 @@ func TestCreateMany_Ok(t *testing.T) {
 	// Arrange
 	req := []models.CreateUserReq{
 		{
-			Email:        "test@test.com",
-			PasswordHash: "test",
+			Email:    "test@test.com",
+			Password: "test",
 		},
 	}
 
@@ -327,8 +603,8 @@ func TestCreateMany_CreateManyError(t *testing.T) {
 	// Arrange
 	req := []models.CreateUserReq{
 		{
-			Email:        "test@test.com",
-			PasswordHash: "test",
+			Email:    "test@test.com",
+			Password: "test",
 		},
 	}
 
@@ -355,8 +631,8 @@ func TestCreateMany_InvalidRequest(t *testing.T) {
 	// Arrange
 	req := []models.CreateUserReq{
 		{
-			Email:        "",
-			PasswordHash: "",
+			Email:    "",
+			Password: "",
 		},
 	}
 
@@ -372,7 +648,7 @@ func TestCreateMany_InvalidRequest(t *testing.T) {
 
 	// Assert
 	assert.NotEmpty(t, err)
-	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
 	assert.Equal(t, expectedError, err.Error())
 }
 
@@ -381,9 +657,9 @@ func TestCreateMany_InvalidClaims(t *testing.T) {
 	// Arrange
 	req := []models.CreateUserReq{
 		{
-			Email:        "test@test.com",
-			PasswordHash: "test",
-			Claims:       []int64{3},
+			Email:    "test@test.com",
+			Password: "test",
+			Claims:   []int64{3},
 		},
 	}
 
@@ -416,7 +692,7 @@ func TestGetAll_Ok(t *testing.T) {
 
 	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), map[string]interface{}{}, nilPointer, nilPointer).Return(result, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), map[string]interface{}{}, readProjection, nilPointer, nilPointer).Return(result, nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -424,45 +700,45 @@ func TestGetAll_Ok(t *testing.T) {
 	}
 
 	// Act
-	resp, err := service.GetAll(context.Background())
+	resp, err := service.GetAll(context.Background(), models.Caller{UserID: "test-user", Admin: true})
 
 	// Assert
 	assert.Nil(t, err)
-	assert.Equal(t, models.UserResp(expectedUser), resp[0])
+	assert.Equal(t, mapping.UserResp(expectedUser), resp[0])
 }
 
-// TestGetAll_NoResourcesFound checks that GetAll does not return an error when the repository does not return an user
-func TestGetAll_NoResourcesFound(t *testing.T) {
+// TestGetAll_ReadPreference checks that GetAll reads with the read preference configured for the operation
+func TestGetAll_ReadPreference(t *testing.T) {
 	// Arrange
+	cfg := config.Config{}
+	cfg.ReadPreferences = map[string]string{"GetAll": string(ports.ReadSecondaryPreferred)}
+	withReadPreference := mock.MatchedBy(func(ctx context.Context) bool {
+		rp, ok := ports.ReadPreferenceFrom(ctx)
+		return ok && rp == ports.ReadSecondaryPreferred
+	})
+
 	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), map[string]interface{}{}, nilPointer, nilPointer).Return(nil, wrappers.NonExistentErr).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), withReadPreference, map[string]interface{}{}, readProjection, nilPointer, nilPointer).Return([]interface{}{}, nil).Once()
 
 	service := &userService{
-		config:     config.Config{},
+		config:     cfg,
 		repository: userRepositoryMock,
 	}
 
 	// Act
-	resp, err := service.GetAll(context.Background())
+	_, err := service.GetAll(context.Background(), models.Caller{UserID: "test-user", Admin: true})
 
 	// Assert
 	assert.Nil(t, err)
-	assert.Equal(t, 0, len(resp))
 }
 
-// TestGetByID_Ok checks that GetByID returns the expected response when a valid ID is received
-func TestGetByID_Ok(t *testing.T) {
+// TestGetAll_NoResourcesFound checks that GetAll does not return an error when the repository does not return an user
+func TestGetAll_NoResourcesFound(t *testing.T) {
 	// Arrange
-	expectedUser := entities.User{
-		ID:           "test-id",
-		Email:        "test@test.com",
-		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
-		Claims:       []int64{0},
-	}
-
+	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), expectedUser.ID).Return(&expectedUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), map[string]interface{}{}, readProjection, nilPointer, nilPointer).Return(nil, wrappers.NonExistentErr).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -470,21 +746,27 @@ func TestGetByID_Ok(t *testing.T) {
 	}
 
 	// Act
-	resp, err := service.GetByID(context.Background(), expectedUser.ID)
+	resp, err := service.GetAll(context.Background(), models.Caller{UserID: "test-user", Admin: true})
 
 	// Assert
 	assert.Nil(t, err)
-	assert.Equal(t, models.UserResp(expectedUser), resp)
+	assert.Equal(t, 0, len(resp))
 }
 
-// TestGetByID_Ok checks that GetByID returns tan error when the provided ID does not exist
-func TestGetByID_NotFound(t *testing.T) {
+// TestSearch_Ok checks that Search returns the expected response when everything goes as expected
+func TestSearch_Ok(t *testing.T) {
 	// Arrange
-	nonExistentID := "non-existent-id"
-	expectedError := fmt.Sprintf("ID %s not found", nonExistentID)
+	req := models.SearchUsersReq{
+		Query: "test",
+		Mode:  models.SearchModeAutocomplete,
+	}
+	expectedUser := entities.User{
+		Name: "test",
+	}
 
+	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), nonExistentID).Return(nil, wrappers.NonExistentErr).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Search), context.Background(), req.Query, true, readProjection, nilPointer, nilPointer).Return([]interface{}{&expectedUser}, nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -492,39 +774,23 @@ func TestGetByID_NotFound(t *testing.T) {
 	}
 
 	// Act
-	_, err := service.GetByID(context.Background(), nonExistentID)
+	resp, err := service.Search(context.Background(), models.Caller{}, req)
 
 	// Assert
-	assert.NotEmpty(t, err)
-	assert.IsType(t, wrappers.NonExistentErr, err)
-	assert.Equal(t, expectedError, err.Error())
+	assert.Nil(t, err)
+	assert.Equal(t, []models.UserResp{mapping.UserResp(expectedUser)}, resp)
 }
 
-// TestUpdate_Ok checks that Update does not return an error when everything goes as expected
-
-func TestUpdate_Ok(t *testing.T) {
+// TestSearch_NoResults checks that Search does not return an error when no users match the query
+func TestSearch_NoResults(t *testing.T) {
 	// Arrange
-	testParam := "test"
-	testEmail := "test@test.com"
-	testClaims := []int64{0}
-
-	req := models.UpdateUserReq{
-		ID:          "test-id",
-		Name:        &testParam,
-		Surnames:    &testParam,
-		Email:       &testEmail,
-		NewPassword: &testParam,
-		OldPassword: &testParam,
-		Claims:      &testClaims,
-	}
-
-	existingUser := entities.User{
-		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
+	req := models.SearchUsersReq{
+		Query: "test",
 	}
 
+	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), req.ID).Return(&existingUser, nil).Once()
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), req.ID, mock.AnythingOfType("entities.User")).Return(nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Search), context.Background(), req.Query, false, readProjection, nilPointer, nilPointer).Return(nil, wrappers.NonExistentErr).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -532,63 +798,657 @@ func TestUpdate_Ok(t *testing.T) {
 	}
 
 	// Act
-	err := service.Update(context.Background(), req.ID, req)
+	resp, err := service.Search(context.Background(), models.Caller{}, req)
 
 	// Assert
 	assert.Nil(t, err)
+	assert.Empty(t, resp)
 }
 
-// TestUpdate_NotFound checks that Update returns an error when the provided ID does not exist
-func TestUpdate_NotFound(t *testing.T) {
+// TestSearch_InvalidRequest checks that Search returns an error when the received request is not valid
+func TestSearch_InvalidRequest(t *testing.T) {
 	// Arrange
-	nonExistentID := "non-existent-id"
-	expectedError := fmt.Sprintf("ID %s not found", nonExistentID)
-
-	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), nonExistentID).Return(nil, wrappers.NonExistentErr).Once()
+	req := models.SearchUsersReq{}
+	expectedError := "query cannot be empty"
 
 	service := &userService{
 		config:     config.Config{},
-		repository: userRepositoryMock,
+		repository: nil,
 	}
 
 	// Act
-	err := service.Update(context.Background(), nonExistentID, models.UpdateUserReq{})
+	_, err := service.Search(context.Background(), models.Caller{}, req)
 
 	// Assert
 	assert.NotEmpty(t, err)
-	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
 	assert.Equal(t, expectedError, err.Error())
 }
 
-// TestUpdate_IncorrectPassword checks that Update returns an error when the provided old password is not correct
-func TestUpdate_IncorrectPassword(t *testing.T) {
+// TestGetNearby_Ok checks that GetNearby returns the expected response when everything goes as expected
+func TestGetNearby_Ok(t *testing.T) {
+	// Arrange
+	req := models.NearbyUsersReq{
+		Longitude: 2.17,
+		Latitude:  41.38,
+		Radius:    1000,
+	}
+	location := entities.NewGeoPoint(2.171, 41.381)
+	expectedUser := entities.User{
+		Name:     "test",
+		Location: &location,
+	}
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetNearby), context.Background(), req.Longitude, req.Latitude, req.Radius, readProjection, nilPointer, nilPointer).Return([]interface{}{&expectedUser}, nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetNearby(context.Background(), models.Caller{}, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.UserResp{mapping.UserResp(expectedUser)}, resp)
+}
+
+// TestGetNearby_InvalidRequest checks that GetNearby returns an error when the received request is not valid
+func TestGetNearby_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := models.NearbyUsersReq{
+		Longitude: 2.17,
+		Latitude:  41.38,
+	}
+	expectedError := "radius must be greater than 0"
+
+	service := &userService{
+		config:     config.Config{},
+		repository: nil,
+	}
+
+	// Act
+	_, err := service.GetNearby(context.Background(), models.Caller{}, req)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetByID_Ok checks that GetByID returns the expected response when a valid ID is received
+func TestGetByID_Ok(t *testing.T) {
+	// Arrange
+	expectedUser := entities.User{
+		ID:           "test-id",
+		Email:        "test@test.com",
+		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
+		Claims:       []int64{0},
+	}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), expectedUser.ID, readProjection).Return(&expectedUser, nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByID(context.Background(), expectedUser.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, mapping.UserResp(expectedUser), resp)
+}
+
+// TestGetByID_Ok checks that GetByID returns tan error when the provided ID does not exist
+func TestGetByID_NotFound(t *testing.T) {
+	// Arrange
+	nonExistentID := "non-existent-id"
+	expectedError := fmt.Sprintf("ID %s not found", nonExistentID)
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), nonExistentID, readProjection).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetByID(context.Background(), nonExistentID)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrNotFound))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpsert_Ok checks that Upsert returns the expected response when a valid request is received
+func TestUpsert_Ok(t *testing.T) {
+	// Arrange
+	testEmail := "test@test.com"
+	req := models.UpsertUserReq{
+		Name:     "test",
+		Password: "test",
+		Claims:   []int64{0},
+	}
+	expectedResponse := models.UpsertionResp{
+		ID: "test-id",
+	}
+
+	filter := map[string]interface{}{"email": testEmail}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Upsert), context.Background(), filter, mock.AnythingOfType("entities.User")).Return(expectedResponse.ID, nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Upsert(context.Background(), testEmail, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestUpsert_NormalizedEmail checks that Upsert matches and stores the email in lower case
+func TestUpsert_NormalizedEmail(t *testing.T) {
+	// Arrange
+	req := models.UpsertUserReq{
+		Name:   "test",
+		Claims: []int64{0},
+	}
+
+	filter := map[string]interface{}{"email": "test@test.com"}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Upsert), context.Background(), filter, mock.MatchedBy(func(user entities.User) bool {
+		return user.Email == "test@test.com"
+	})).Return("test-id", nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Upsert(context.Background(), "Test@Test.com", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpsert_InvalidRequest checks that Upsert returns an error when the received request is not valid
+func TestUpsert_InvalidRequest(t *testing.T) {
+	// Arrange
+	expectedError := "email cannot be empty"
+
+	service := &userService{
+		config:     config.Config{},
+		repository: nil,
+	}
+
+	// Act
+	_, err := service.Upsert(context.Background(), "", models.UpsertUserReq{})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrValidation))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpsert_InvalidClaims checks that Upsert returns an error when the received claims are not valid
+func TestUpsert_InvalidClaims(t *testing.T) {
+	// Arrange
+	req := models.UpsertUserReq{
+		Claims: []int64{3},
+	}
+
+	expectedError := "claim 3 is not valid"
+
+	service := &userService{
+		config:     config.Config{},
+		repository: nil,
+	}
+
+	// Act
+	_, err := service.Upsert(context.Background(), "test@test.com", req)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpsert_UpsertError checks that Upsert returns an error when the Upsert function from the repository fails
+func TestUpsert_UpsertError(t *testing.T) {
+	// Arrange
+	testEmail := "test@test.com"
+	expectedError := "repository-error"
+
+	filter := map[string]interface{}{"email": testEmail}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Upsert), context.Background(), filter, mock.AnythingOfType("entities.User")).Return("", fmt.Errorf(expectedError)).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Upsert(context.Background(), testEmail, models.UpsertUserReq{})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpdate_Ok checks that Update does not return an error when everything goes as expected
+
+func TestUpdate_Ok(t *testing.T) {
+	// Arrange
+	testParam := "test"
+	testEmail := "test@test.com"
+	testClaims := []int64{0}
+
+	req := models.UpdateUserReq{
+		ID:          "test-id",
+		Name:        &testParam,
+		Surnames:    &testParam,
+		Email:       &testEmail,
+		NewPassword: &testParam,
+		OldPassword: &testParam,
+		Claims:      &testClaims,
+	}
+
+	existingUser := entities.User{
+		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
+	}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), req.ID, map[string]interface{}(nil)).Return(&existingUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), req.ID, mock.AnythingOfType("entities.User")).Return(nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	for _, eventType := range []string{entities.AuditUserUpdated, entities.AuditPasswordChanged, entities.AuditClaimsChanged} {
+		eventType := eventType
+		auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(event entities.AuditEvent) bool {
+			return event.Type == eventType && event.UserID == req.ID && event.Outcome == entities.AuditOutcomeSuccess
+		})).Return(nil).Once()
+	}
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		audit:      auditRepositoryMock,
+	}
+
+	// Act
+	err := service.Update(context.Background(), req.ID, req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdate_NotFound checks that Update returns an error when the provided ID does not exist
+func TestUpdate_NotFound(t *testing.T) {
+	// Arrange
+	nonExistentID := "non-existent-id"
+	expectedError := fmt.Sprintf("ID %s not found", nonExistentID)
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), nonExistentID, map[string]interface{}(nil)).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	err := service.Update(context.Background(), nonExistentID, models.UpdateUserReq{})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrNotFound))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpdate_IncorrectPassword checks that Update returns an error when the provided old password is not correct
+func TestUpdate_IncorrectPassword(t *testing.T) {
 	// Arrange
 	newPassword := "new-password"
 	incorrectOldPassword := "incorrect-password"
 
-	req := models.UpdateUserReq{
-		ID:          "test-id",
-		NewPassword: &newPassword,
-		OldPassword: &incorrectOldPassword,
+	req := models.UpdateUserReq{
+		ID:          "test-id",
+		NewPassword: &newPassword,
+		OldPassword: &incorrectOldPassword,
+	}
+
+	existingUser := entities.User{
+		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
+	}
+
+	expectedError := "password incorrect"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), req.ID, map[string]interface{}(nil)).Return(&existingUser, nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(event entities.AuditEvent) bool {
+		return event.Type == entities.AuditPasswordChanged && event.Outcome == entities.AuditOutcomeFailure && event.Details["reason"] == expectedError
+	})).Return(nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		audit:      auditRepositoryMock,
+	}
+
+	// Act
+	err := service.Update(context.Background(), req.ID, req)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpdate_InvalidClaims checks that Update returns an error when the provided new claims are not valid
+func TestUpdate_InvalidClaims(t *testing.T) {
+	// Arrange
+	invalidClaims := []int64{3}
+
+	req := models.UpdateUserReq{
+		ID:     "test-id",
+		Claims: &invalidClaims,
+	}
+
+	expectedError := "claim 3 is not valid"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), req.ID, map[string]interface{}(nil)).Return(&entities.User{}, nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	err := service.Update(context.Background(), req.ID, req)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestAuthorizeUpdate_Ok checks that AuthorizeUpdate allows the users to update themselves, and the admins to update the claims of every user
+func TestAuthorizeUpdate_Ok(t *testing.T) {
+	// Arrange
+	claims := []int64{1}
+	service := &userService{}
+
+	// Act
+	selfErr := service.AuthorizeUpdate(context.Background(), models.Caller{UserID: "test-id"}, "test-id", models.UpdateUserReq{})
+	adminErr := service.AuthorizeUpdate(context.Background(), models.Caller{UserID: "admin-id", Admin: true}, "test-id", models.UpdateUserReq{Claims: &claims})
+
+	// Assert
+	assert.Nil(t, selfErr)
+	assert.Nil(t, adminErr)
+}
+
+// TestAuthorizeUpdate_OtherUser checks that AuthorizeUpdate returns an unauthorized error when the caller is neither the user nor an admin
+func TestAuthorizeUpdate_OtherUser(t *testing.T) {
+	// Arrange
+	service := &userService{}
+
+	// Act
+	err := service.AuthorizeUpdate(context.Background(), models.Caller{UserID: "other-id"}, "test-id", models.UpdateUserReq{})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestAuthorizeUpdate_Claims checks that AuthorizeUpdate returns an unauthorized error when a user not admin updates its own claims
+func TestAuthorizeUpdate_Claims(t *testing.T) {
+	// Arrange
+	claims := []int64{0}
+	service := &userService{}
+
+	// Act
+	err := service.AuthorizeUpdate(context.Background(), models.Caller{UserID: "test-id"}, "test-id", models.UpdateUserReq{Claims: &claims})
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrUnauthorized)
+}
+
+// TestMerge_Ok checks that Merge updates the target user and deletes the source user inside a transaction
+func TestMerge_Ok(t *testing.T) {
+	// Arrange
+	targetID := "target-id"
+	req := models.MergeUsersReq{
+		SourceID: "source-id",
+	}
+
+	targetUser := entities.User{
+		Email: "target@test.com",
+	}
+	sourceUser := entities.User{
+		Name:   "source",
+		Email:  "source@test.com",
+		Claims: []int64{0},
+	}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.WithTransaction), context.Background(), mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), targetID, map[string]interface{}(nil)).Return(&targetUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), req.SourceID, map[string]interface{}(nil)).Return(&sourceUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), targetID, mock.MatchedBy(func(u entities.User) bool {
+		return u.Name == sourceUser.Name && u.Email == targetUser.Email && len(u.Claims) == 1
+	})).Return(nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), context.Background(), req.SourceID).Return(nil).Once()
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Delete), context.Background(), "avatars/"+req.SourceID).Return(wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		storage:    fileStorageMock,
+	}
+
+	// Act
+	err := service.Merge(context.Background(), targetID, req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestMerge_SameUser checks that Merge returns an error when the source and target users are the same
+func TestMerge_SameUser(t *testing.T) {
+	// Arrange
+	testID := "test-id"
+	req := models.MergeUsersReq{
+		SourceID: testID,
+	}
+	expectedError := "a user cannot be merged into itself"
+
+	service := &userService{
+		config:     config.Config{},
+		repository: nil,
+	}
+
+	// Act
+	err := service.Merge(context.Background(), testID, req)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestMerge_SourceNotFound checks that Merge returns an error when the source user is not found
+func TestMerge_SourceNotFound(t *testing.T) {
+	// Arrange
+	targetID := "target-id"
+	req := models.MergeUsersReq{
+		SourceID: "source-id",
+	}
+	expectedError := fmt.Sprintf("ID %s not found", req.SourceID)
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.WithTransaction), context.Background(), mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), targetID, map[string]interface{}(nil)).Return(&entities.User{}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), req.SourceID, map[string]interface{}(nil)).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	err := service.Merge(context.Background(), targetID, req)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrNotFound))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestDelete_Ok checks that Delete does not return an error when everything goes as expected
+func TestDelete_Ok(t *testing.T) {
+	// Arrange
+	testID := "test-id"
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), context.Background(), testID).Return(nil).Once()
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Delete), context.Background(), "avatars/"+testID).Return(nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		storage:    fileStorageMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), testID)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDelete_Audit checks that Delete records a user deleted event once the user is deleted
+func TestDelete_Audit(t *testing.T) {
+	// Arrange
+	testID := "test-id"
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), context.Background(), testID).Return(nil).Once()
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Delete), context.Background(), "avatars/"+testID).Return(nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), context.Background(), mock.MatchedBy(func(event entities.AuditEvent) bool {
+		return event.Type == entities.AuditUserDeleted && event.UserID == testID
+	})).Return(nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		storage:    fileStorageMock,
+		audit:      auditRepositoryMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), testID)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDelete_NotFound checks that Delete returns an error when the provided ID does not exist
+func TestDelete_NotFound(t *testing.T) {
+	// Arrange
+	nonExistentID := "non-existent-id"
+	expectedError := fmt.Sprintf("ID %s not found", nonExistentID)
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), context.Background(), nonExistentID).Return(wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), nonExistentID)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrNotFound))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUploadAvatar_Ok checks that UploadAvatar stores the avatar under the key of the user when everything goes as expected
+func TestUploadAvatar_Ok(t *testing.T) {
+	// Arrange
+	testID := "test-id"
+	req := models.UploadAvatarReq{
+		Name:        "avatar.png",
+		ContentType: "image/png",
+		Content:     strings.NewReader("test-content"),
 	}
 
-	existingUser := entities.User{
-		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
+	expectedFile := entities.File{
+		Key:         "avatars/" + testID,
+		Name:        req.Name,
+		ContentType: req.ContentType,
+		Size:        12,
+		Metadata:    map[string]string{"user_id": testID},
 	}
 
-	expectedError := "password incorrect"
-
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), req.ID).Return(&existingUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), testID, readProjection).Return(&entities.User{}, nil).Once()
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Store), context.Background(), mock.MatchedBy(func(f entities.File) bool {
+		return f.Key == expectedFile.Key && f.ContentType == expectedFile.ContentType && f.Metadata["user_id"] == testID
+	}), req.Content).Return(expectedFile, nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
 		repository: userRepositoryMock,
+		storage:    fileStorageMock,
 	}
 
 	// Act
-	err := service.Update(context.Background(), req.ID, req)
+	resp, err := service.UploadAvatar(context.Background(), testID, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.FileResp(expectedFile), resp)
+}
+
+// TestUploadAvatar_InvalidContentType checks that UploadAvatar returns an error when the received file is not an image
+func TestUploadAvatar_InvalidContentType(t *testing.T) {
+	// Arrange
+	req := models.UploadAvatarReq{
+		ContentType: "text/plain",
+		Content:     strings.NewReader("test-content"),
+	}
+	expectedError := "file must be an image"
+
+	service := &userService{
+		config:     config.Config{},
+		repository: nil,
+	}
+
+	// Act
+	_, err := service.UploadAvatar(context.Background(), "test-id", req)
 
 	// Assert
 	assert.NotEmpty(t, err)
@@ -596,41 +1456,325 @@ func TestUpdate_IncorrectPassword(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
-// TestUpdate_InvalidClaims checks that Update returns an error when the provided new claims are not valid
-func TestUpdate_InvalidClaims(t *testing.T) {
+// TestUploadAvatar_UserNotFound checks that UploadAvatar returns an error when the user does not exist
+func TestUploadAvatar_UserNotFound(t *testing.T) {
 	// Arrange
-	invalidClaims := []int64{3}
+	nonExistentID := "non-existent-id"
+	req := models.UploadAvatarReq{
+		ContentType: "image/png",
+		Content:     strings.NewReader("test-content"),
+	}
+	expectedError := fmt.Sprintf("ID %s not found", nonExistentID)
 
-	req := models.UpdateUserReq{
-		ID:     "test-id",
-		Claims: &invalidClaims,
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), nonExistentID, readProjection).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
 	}
 
-	expectedError := "claim 3 is not valid"
+	// Act
+	_, err := service.UploadAvatar(context.Background(), nonExistentID, req)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrNotFound))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetAvatar_Ok checks that GetAvatar returns the content and the info of the avatar when everything goes as expected
+func TestGetAvatar_Ok(t *testing.T) {
+	// Arrange
+	testID := "test-id"
+	expectedContent := io.NopCloser(strings.NewReader("test-content"))
+	expectedFile := entities.File{Key: "avatars/" + testID, ContentType: "image/png"}
+
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Open), context.Background(), expectedFile.Key).Return(expectedContent, expectedFile, nil).Once()
+
+	service := &userService{
+		config:  config.Config{},
+		storage: fileStorageMock,
+	}
+
+	// Act
+	content, resp, err := service.GetAvatar(context.Background(), testID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedContent, content)
+	assert.Equal(t, models.FileResp(expectedFile), resp)
+}
+
+// TestGetAvatar_NotFound checks that GetAvatar returns an error when the user has no avatar
+func TestGetAvatar_NotFound(t *testing.T) {
+	// Arrange
+	testID := "test-id"
+	expectedError := fmt.Sprintf("avatar of user ID %s not found", testID)
+
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Open), context.Background(), "avatars/"+testID).Return(nil, entities.File{}, wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:  config.Config{},
+		storage: fileStorageMock,
+	}
+
+	// Act
+	_, _, err := service.GetAvatar(context.Background(), testID)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrNotFound))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestDeleteAvatar_Ok checks that DeleteAvatar does not return an error when everything goes as expected
+func TestDeleteAvatar_Ok(t *testing.T) {
+	// Arrange
+	testID := "test-id"
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Delete), context.Background(), "avatars/"+testID).Return(nil).Once()
+
+	service := &userService{
+		config:  config.Config{},
+		storage: fileStorageMock,
+	}
+
+	// Act
+	err := service.DeleteAvatar(context.Background(), testID)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeleteAvatar_NotFound checks that DeleteAvatar returns an error when the user has no avatar
+func TestDeleteAvatar_NotFound(t *testing.T) {
+	// Arrange
+	testID := "test-id"
+	expectedError := fmt.Sprintf("avatar of user ID %s not found", testID)
+
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Delete), context.Background(), "avatars/"+testID).Return(wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:  config.Config{},
+		storage: fileStorageMock,
+	}
+
+	// Act
+	err := service.DeleteAvatar(context.Background(), testID)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrNotFound))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// presigningStorage is a file storage presigning URLs, as the one of S3
+type presigningStorage struct {
+	*mocks.FileStorage
+	*mocks.FilePresigner
+}
+
+// TestGetAvatarURL_Ok checks that GetAvatarURL returns a presigned URL of the avatar when everything goes as expected
+func TestGetAvatarURL_Ok(t *testing.T) {
+	// Arrange
+	testID := "test-id"
+	expectedURL := "https://bucket.s3.amazonaws.com/avatars/test-id?X-Amz-Signature=test"
+	cfg := config.Config{}
+	cfg.Storage.PresignExpiry = utils.Duration{Duration: 15 * time.Minute}
+
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Stat), context.Background(), "avatars/"+testID).Return(entities.File{}, nil).Once()
+	filePresignerMock := mocks.NewFilePresigner(t)
+	filePresignerMock.On(testutils.FunctionName(t, ports.FilePresigner.PresignGet), context.Background(), "avatars/"+testID, 15*time.Minute).Return(expectedURL, nil).Once()
+
+	service := &userService{
+		config:  cfg,
+		storage: presigningStorage{fileStorageMock, filePresignerMock},
+	}
+
+	// Act
+	resp, err := service.GetAvatarURL(context.Background(), testID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedURL, resp.URL)
+	assert.Equal(t, http.MethodGet, resp.Method)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), resp.ExpiresAt, time.Minute)
+}
+
+// TestGetAvatarURL_NotFound checks that GetAvatarURL returns an error when the user has no avatar
+func TestGetAvatarURL_NotFound(t *testing.T) {
+	// Arrange
+	testID := "test-id"
+	expectedError := fmt.Sprintf("avatar of user ID %s not found", testID)
+
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Stat), context.Background(), "avatars/"+testID).Return(entities.File{}, wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:  config.Config{},
+		storage: presigningStorage{fileStorageMock, mocks.NewFilePresigner(t)},
+	}
+
+	// Act
+	_, err := service.GetAvatarURL(context.Background(), testID)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.True(t, errors.Is(err, apierror.ErrNotFound))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetAvatarURL_NotSupported checks that GetAvatarURL returns an error when the file storage cannot presign URLs
+func TestGetAvatarURL_NotSupported(t *testing.T) {
+	// Arrange
+	service := &userService{
+		config:  config.Config{},
+		storage: mocks.NewFileStorage(t),
+	}
+
+	// Act
+	_, err := service.GetAvatarURL(context.Background(), "test-id")
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "presigned URLs not supported by the file storage", err.Error())
+}
+
+// TestCreateAvatarUploadURL_Ok checks that CreateAvatarUploadURL returns a presigned URL uploading the avatar and its headers when everything goes as expected
+func TestCreateAvatarUploadURL_Ok(t *testing.T) {
+	// Arrange
+	testID := "test-id"
+	req := models.AvatarUploadURLReq{Name: "avatar.png", ContentType: "image/png", Size: 12}
+	expectedURL := "https://bucket.s3.amazonaws.com/avatars/test-id?X-Amz-Signature=test"
+	expectedHeaders := map[string]string{"Content-Type": "image/png", "Content-Length": "12"}
+	cfg := config.Config{}
+	cfg.Storage.MaxUploadSize = 1024
+	cfg.Storage.PresignExpiry = utils.Duration{Duration: 15 * time.Minute}
 
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), req.ID).Return(&entities.User{}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), testID, readProjection).Return(&entities.User{}, nil).Once()
+	filePresignerMock := mocks.NewFilePresigner(t)
+	filePresignerMock.On(testutils.FunctionName(t, ports.FilePresigner.PresignPut), context.Background(), mock.MatchedBy(func(f entities.File) bool {
+		return f.Key == "avatars/"+testID && f.ContentType == req.ContentType && f.Size == req.Size && f.Metadata["user_id"] == testID
+	}), 15*time.Minute).Return(expectedURL, expectedHeaders, nil).Once()
 
 	service := &userService{
-		config:     config.Config{},
+		config:     cfg,
 		repository: userRepositoryMock,
+		storage:    presigningStorage{mocks.NewFileStorage(t), filePresignerMock},
 	}
 
 	// Act
-	err := service.Update(context.Background(), req.ID, req)
+	resp, err := service.CreateAvatarUploadURL(context.Background(), testID, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedURL, resp.URL)
+	assert.Equal(t, http.MethodPut, resp.Method)
+	assert.Equal(t, expectedHeaders, resp.Headers)
+}
+
+// TestCreateAvatarUploadURL_TooLarge checks that CreateAvatarUploadURL returns an error when the size exceeds the maximum upload size
+func TestCreateAvatarUploadURL_TooLarge(t *testing.T) {
+	// Arrange
+	req := models.AvatarUploadURLReq{Name: "avatar.png", ContentType: "image/png", Size: 2048}
+	cfg := config.Config{}
+	cfg.Storage.MaxUploadSize = 1024
+
+	service := &userService{
+		config:  cfg,
+		storage: presigningStorage{mocks.NewFileStorage(t), mocks.NewFilePresigner(t)},
+	}
+
+	// Act
+	_, err := service.CreateAvatarUploadURL(context.Background(), "test-id", req)
 
 	// Assert
 	assert.NotEmpty(t, err)
 	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "size cannot be greater than 1024", err.Error())
+}
+
+// TestCreateAvatarUploadURL_InvalidContentType checks that CreateAvatarUploadURL returns an error when the file is not an image
+func TestCreateAvatarUploadURL_InvalidContentType(t *testing.T) {
+	// Arrange
+	req := models.AvatarUploadURLReq{Name: "avatar.txt", ContentType: "text/plain", Size: 12}
+
+	service := &userService{
+		config:  config.Config{},
+		storage: presigningStorage{mocks.NewFileStorage(t), mocks.NewFilePresigner(t)},
+	}
+
+	// Act
+	_, err := service.CreateAvatarUploadURL(context.Background(), "test-id", req)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "file must be an image", err.Error())
+}
+
+// TestArchiveInactive_Ok checks that ArchiveInactive archives the users inactive since the configured period and returns the archived count
+func TestArchiveInactive_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Archive.InactivityPeriod = utils.Duration{Duration: 24 * time.Hour}
+	expectedCount := int64(3)
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Archive), ports.WithOperationTimeout(context.Background(), 0), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			inactiveSince, at := args.Get(1).(time.Time), args.Get(2).(time.Time)
+			assert.Equal(t, cfg.Archive.InactivityPeriod.Duration, at.Sub(inactiveSince))
+		}).Return(expectedCount, nil).Once()
+
+	service := &userService{
+		config:     cfg,
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.ArchiveInactive(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedCount, resp.ArchivedCount)
+}
+
+// TestArchiveInactive_RepositoryError checks that ArchiveInactive returns an error when the repository fails
+func TestArchiveInactive_RepositoryError(t *testing.T) {
+	// Arrange
+	expectedError := "repository error"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Archive), ports.WithOperationTimeout(context.Background(), 0), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(int64(0), errors.New(expectedError)).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.ArchiveInactive(context.Background())
+
+	// Assert
+	assert.NotEmpty(t, err)
 	assert.Equal(t, expectedError, err.Error())
 }
 
-// TestDelete_Ok checks that Delete does not return an error when everything goes as expected
-func TestDelete_Ok(t *testing.T) {
+// TestUnarchive_Ok checks that Unarchive does not return an error when everything goes as expected
+func TestUnarchive_Ok(t *testing.T) {
 	// Arrange
 	testID := "test-id"
+
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), context.Background(), testID).Return(nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Unarchive), context.Background(), testID, mock.AnythingOfType("time.Time")).Return(nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -638,20 +1782,20 @@ func TestDelete_Ok(t *testing.T) {
 	}
 
 	// Act
-	err := service.Delete(context.Background(), testID)
+	err := service.Unarchive(context.Background(), testID)
 
 	// Assert
 	assert.Nil(t, err)
 }
 
-// TestDelete_NotFound checks that Delete returns an error when the provided ID does not exist
-func TestDelete_NotFound(t *testing.T) {
+// TestUnarchive_NotFound checks that Unarchive returns an error when the provided ID is not archived
+func TestUnarchive_NotFound(t *testing.T) {
 	// Arrange
 	nonExistentID := "non-existent-id"
-	expectedError := fmt.Sprintf("ID %s not found", nonExistentID)
+	expectedError := fmt.Sprintf("archived user ID %s not found", nonExistentID)
 
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), context.Background(), nonExistentID).Return(wrappers.NonExistentErr).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Unarchive), context.Background(), nonExistentID, mock.AnythingOfType("time.Time")).Return(wrappers.NonExistentErr).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -659,11 +1803,11 @@ func TestDelete_NotFound(t *testing.T) {
 	}
 
 	// Act
-	err := service.Delete(context.Background(), nonExistentID)
+	err := service.Unarchive(context.Background(), nonExistentID)
 
 	// Assert
 	assert.NotEmpty(t, err)
-	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.True(t, errors.Is(err, apierror.ErrNotFound))
 	assert.Equal(t, expectedError, err.Error())
 }
 
@@ -675,11 +1819,83 @@ func TestGetUserClaims_Ok(t *testing.T) {
 		repository: nil,
 	}
 
-	expectedClaims := map[int]string{0: "admin"}
+	expectedClaims := []models.ClaimResp{
+		{ID: 0, Name: "admin", Description: entities.AdminClaim.Description(), Category: "administration"},
+		{ID: 1, Name: "compliance", Description: entities.ComplianceClaim.Description(), Category: "compliance"},
+	}
 
 	// Act
-	resp := service.GetUserClaims(context.Background())
+	resp := service.GetUserClaims(context.Background(), models.GetClaimsReq{})
 
 	// Assert
 	assert.Equal(t, expectedClaims, resp)
 }
+
+// TestGetUserClaims_Filtered checks that GetUserClaims only returns the claims of the category and deprecation filtered, an empty list when none matches
+func TestGetUserClaims_Filtered(t *testing.T) {
+	// Arrange
+	service := &userService{
+		config:     config.Config{},
+		repository: nil,
+	}
+	deprecated := true
+
+	// Act
+	byCategory := service.GetUserClaims(context.Background(), models.GetClaimsReq{Category: "administration"})
+	byOtherCategory := service.GetUserClaims(context.Background(), models.GetClaimsReq{Category: "billing"})
+	byDeprecated := service.GetUserClaims(context.Background(), models.GetClaimsReq{Deprecated: &deprecated})
+
+	// Assert
+	assert.Len(t, byCategory, 1)
+	assert.Equal(t, []models.ClaimResp{}, byOtherCategory)
+	assert.Equal(t, []models.ClaimResp{}, byDeprecated)
+}
+
+// TestComputeStats_Ok checks that ComputeStats counts the users active during the inactivity period and keeps them as the last stats
+func TestComputeStats_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Archive.InactivityPeriod = utils.Duration{Duration: 24 * time.Hour}
+	expectedStats := entities.UserStats{Total: 3, Active: 2, Archived: 1}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Stats), context.Background(), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			activeSince := args.Get(1).(time.Time)
+			assert.WithinDuration(t, time.Now().Add(-cfg.Archive.InactivityPeriod.Duration), activeSince, time.Minute)
+		}).Return(expectedStats, nil).Once()
+
+	service := &userService{
+		config:     cfg,
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.ComputeStats(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.UserStatsResp{Total: 3, Active: 2, Archived: 1, ComputedAt: resp.ComputedAt}, resp)
+	assert.Equal(t, &resp, lastUserStats.Load())
+}
+
+// TestComputeStats_RepositoryError checks that ComputeStats returns an error when the repository fails
+func TestComputeStats_RepositoryError(t *testing.T) {
+	// Arrange
+	expectedError := "repository error"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Stats), context.Background(), mock.AnythingOfType("time.Time")).Return(entities.UserStats{}, errors.New(expectedError)).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.ComputeStats(context.Background())
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,66 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// queuedTracker adapter of an analytics tracker queuing the messages as analytics jobs, sent by the workers and retried while the destination is not reachable
+type queuedTracker struct {
+	jobs ports.JobService
+}
+
+// NewQueuedTracker creates a tracker queuing the messages to be sent by the workers
+func NewQueuedTracker(jobs ports.JobService) ports.AnalyticsTracker {
+	return &queuedTracker{jobs: jobs}
+}
+
+// Send queues the message
+func (t *queuedTracker) Send(ctx context.Context, message ports.AnalyticsMessage) error {
+	traits, err := json.Marshal(message.Traits)
+	if err != nil {
+		return err
+	}
+	properties, err := json.Marshal(message.Properties)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.jobs.Enqueue(ctx, entities.JobTypeAnalytics, map[string]string{
+		"id":         message.ID,
+		"type":       message.Type,
+		"user_id":    message.UserID,
+		"event":      message.Event,
+		"traits":     string(traits),
+		"properties": string(properties),
+		"timestamp":  message.Timestamp.UTC().Format(time.RFC3339Nano),
+	})
+	return err
+}
+
+// Deliver sends an analytics job through the tracker
+func Deliver(ctx context.Context, tracker ports.AnalyticsTracker, job *entities.Job) error {
+	timestamp, err := time.Parse(time.RFC3339Nano, job.Metadata["timestamp"])
+	if err != nil {
+		return fmt.Errorf("analytics message not valid: %w", err)
+	}
+	message := ports.AnalyticsMessage{
+		ID:        job.Metadata["id"],
+		Type:      job.Metadata["type"],
+		UserID:    job.Metadata["user_id"],
+		Event:     job.Metadata["event"],
+		Timestamp: timestamp,
+	}
+	if err := json.Unmarshal([]byte(job.Metadata["traits"]), &message.Traits); err != nil {
+		return fmt.Errorf("analytics message not valid: %w", err)
+	}
+	if err := json.Unmarshal([]byte(job.Metadata["properties"]), &message.Properties); err != nil {
+		return fmt.Errorf("analytics message not valid: %w", err)
+	}
+	return tracker.Send(ctx, message)
+}
@@ -0,0 +1,61 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestQueuedSend_Ok checks that Send queues an analytics job that Deliver sends as the same message
+func TestQueuedSend_Ok(t *testing.T) {
+	// Arrange
+	message := ports.AnalyticsMessage{
+		ID:        "test-message",
+		Type:      ports.AnalyticsIdentify,
+		UserID:    "test-id",
+		Traits:    map[string]string{"tenant": "example.com", "admin": "false"},
+		Timestamp: time.Date(2023, 8, 20, 9, 0, 0, 0, time.UTC),
+	}
+
+	var job entities.Job
+	jobServiceMock := mocks.NewJobService(t)
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Enqueue), context.Background(), entities.JobTypeAnalytics, mock.Anything).Run(func(args mock.Arguments) {
+		job = entities.Job{Type: entities.JobTypeAnalytics, Metadata: args.Get(2).(map[string]string)}
+	}).Return(models.JobResp{}, nil).Once()
+
+	trackerMock := mocks.NewAnalyticsTracker(t)
+	trackerMock.On(testutils.FunctionName(t, ports.AnalyticsTracker.Send), context.Background(), message).Return(nil).Once()
+
+	// Act
+	err := NewQueuedTracker(jobServiceMock).Send(context.Background(), message)
+	deliverErr := Deliver(context.Background(), trackerMock, &job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, deliverErr)
+}
+
+// TestDeliver_Error checks that Deliver returns the error of the tracker, so the job is retried
+func TestDeliver_Error(t *testing.T) {
+	// Arrange
+	job := entities.Job{Metadata: map[string]string{"timestamp": "2023-08-20T09:00:00Z", "traits": "null", "properties": "null"}}
+	expectedError := errors.New("endpoint not reachable")
+
+	trackerMock := mocks.NewAnalyticsTracker(t)
+	trackerMock.On(testutils.FunctionName(t, ports.AnalyticsTracker.Send), context.Background(), mock.Anything).Return(expectedError).Once()
+
+	// Act
+	err := Deliver(context.Background(), trackerMock, &job)
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
@@ -0,0 +1,99 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+)
+
+// anonymizedIP is the IP sent in the context of the messages, so the destination does not geolocate them from the IP of the API
+const anonymizedIP = "0.0.0.0"
+
+// libraryName is the name of the library sending the messages, as reported in their context
+const libraryName = "go-hexagonal-api"
+
+// segmentTracker adapter of an analytics tracker sending the messages to the HTTP Tracking API of Segment,
+// or of any destination compatible with it, like RudderStack or Jitsu
+type segmentTracker struct {
+	endpoint string
+	writeKey string
+	client   *http.Client
+}
+
+// NewSegmentTracker creates a tracker sending the messages to the source of the given write key at the endpoint, https://api.segment.io for Segment
+func NewSegmentTracker(endpoint, writeKey string, client *http.Client) ports.AnalyticsTracker {
+	return &segmentTracker{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		writeKey: writeKey,
+		client:   client,
+	}
+}
+
+// segmentMessage is the body of an identify or a track call
+type segmentMessage struct {
+	Type       string            `json:"type"`
+	MessageID  string            `json:"messageId"`
+	UserID     string            `json:"userId"`
+	Event      string            `json:"event,omitempty"`
+	Traits     map[string]string `json:"traits,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+	Timestamp  string            `json:"timestamp"`
+	Context    segmentContext    `json:"context"`
+}
+
+type segmentContext struct {
+	IP      string         `json:"ip"`
+	Library segmentLibrary `json:"library"`
+}
+
+type segmentLibrary struct {
+	Name string `json:"name"`
+}
+
+// Send posts the message to the endpoint of its type, /v1/identify or /v1/track, authenticated with the write key as the username
+func (t *segmentTracker) Send(ctx context.Context, message ports.AnalyticsMessage) error {
+	body, err := json.Marshal(segmentMessage{
+		Type:       message.Type,
+		MessageID:  message.ID,
+		UserID:     message.UserID,
+		Event:      message.Event,
+		Traits:     message.Traits,
+		Properties: message.Properties,
+		Timestamp:  message.Timestamp.UTC().Format(time.RFC3339Nano),
+		Context: segmentContext{
+			IP:      anonymizedIP,
+			Library: segmentLibrary{Name: libraryName},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/v1/%s", t.endpoint, message.Type), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(t.writeKey, "")
+	tracing.InjectHeader(ctx, req.Header)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("analytics endpoint responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
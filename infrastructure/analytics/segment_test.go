@@ -0,0 +1,70 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSegmentSend_Ok checks that Send posts the message to the endpoint of its type, authenticated with the write key and with an anonymized IP
+func TestSegmentSend_Ok(t *testing.T) {
+	// Arrange
+	var path, writeKey, password string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		writeKey, password, _ = r.BasicAuth()
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	tracker := NewSegmentTracker(server.URL+"/", "test-key", server.Client())
+	message := ports.AnalyticsMessage{
+		ID:         "test-message",
+		Type:       ports.AnalyticsTrack,
+		UserID:     "test-id",
+		Event:      ports.AnalyticsProfileUpdated,
+		Properties: map[string]string{"fields": "name"},
+		Timestamp:  time.Date(2023, 8, 20, 9, 0, 0, 0, time.UTC),
+	}
+
+	// Act
+	err := tracker.Send(context.Background(), message)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "/v1/track", path)
+	assert.Equal(t, "test-key", writeKey)
+	assert.Equal(t, "", password)
+	assert.Equal(t, "test-message", body["messageId"])
+	assert.Equal(t, "test-id", body["userId"])
+	assert.Equal(t, ports.AnalyticsProfileUpdated, body["event"])
+	assert.Equal(t, map[string]interface{}{"fields": "name"}, body["properties"])
+	assert.Equal(t, "2023-08-20T09:00:00Z", body["timestamp"])
+	assert.Equal(t, anonymizedIP, body["context"].(map[string]interface{})["ip"])
+}
+
+// TestSegmentSend_Error checks that Send returns an error when the endpoint does not accept the message
+func TestSegmentSend_Error(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("invalid write key"))
+	}))
+	defer server.Close()
+
+	tracker := NewSegmentTracker(server.URL, "test-key", server.Client())
+
+	// Act
+	err := tracker.Send(context.Background(), ports.AnalyticsMessage{Type: ports.AnalyticsIdentify, UserID: "test-id"})
+
+	// Assert
+	assert.EqualError(t, err, "analytics endpoint responded with status 400: invalid write key")
+}
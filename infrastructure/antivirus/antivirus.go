@@ -0,0 +1,18 @@
+package antivirus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// NewScanner creates the MalwareScanner of the given provider, only clamav for now, reaching clamd at the given address
+func NewScanner(provider, clamAVAddress string, timeout time.Duration) (ports.MalwareScanner, error) {
+	switch provider {
+	case "clamav":
+		return NewClamAVScanner(clamAVAddress, timeout), nil
+	default:
+		return nil, fmt.Errorf("antivirus provider %s not valid", provider)
+	}
+}
@@ -0,0 +1,110 @@
+package antivirus
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// clamAVChunkSize is the size of the chunks the content is streamed to clamd in
+const clamAVChunkSize = 64 << 10
+
+// clamAVScanner adapter of a malware scanner streaming the content to clamd, the daemon of ClamAV, with its INSTREAM command
+type clamAVScanner struct {
+	address string
+	timeout time.Duration
+	dialer  net.Dialer
+}
+
+// NewClamAVScanner creates a scanner reaching clamd at the address, a host and port or the path of its unix socket,
+// every scan given at most the timeout
+func NewClamAVScanner(address string, timeout time.Duration) ports.MalwareScanner {
+	return &clamAVScanner{
+		address: address,
+		timeout: timeout,
+	}
+}
+
+// Scan streams the content to clamd, returning the name of the signature found, empty when clamd reports it clean.
+// The content larger than the StreamMaxLength of clamd is refused with an error, so it is never taken as clean.
+func (s *clamAVScanner) Scan(ctx context.Context, content io.Reader) (string, error) {
+	network := "tcp"
+	if strings.HasPrefix(s.address, "/") {
+		network = "unix"
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	conn, err := s.dialer.DialContext(ctx, network, s.address)
+	if err != nil {
+		return "", fmt.Errorf("clamav cannot be reached: %w", err)
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", err
+	}
+
+	if err := stream(conn, content); err != nil {
+		// clamd closes the connection once the content exceeds its limit, replying why
+		if reply, replyErr := readReply(conn); replyErr == nil && reply != "" {
+			return "", fmt.Errorf("clamav responded: %s", reply)
+		}
+		return "", err
+	}
+
+	reply, err := readReply(conn)
+	if err != nil {
+		return "", fmt.Errorf("clamav reply cannot be read: %w", err)
+	}
+	switch {
+	case reply == "stream: OK":
+		return "", nil
+	case strings.HasPrefix(reply, "stream: ") && strings.HasSuffix(reply, " FOUND"):
+		return strings.TrimSuffix(strings.TrimPrefix(reply, "stream: "), " FOUND"), nil
+	default:
+		return "", fmt.Errorf("clamav responded: %s", reply)
+	}
+}
+
+// stream sends the INSTREAM command with the content in chunks prefixed by their size, ended by an empty chunk
+func stream(conn net.Conn, content io.Reader) error {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+	chunk := make([]byte, 4+clamAVChunkSize)
+	for {
+		n, err := content.Read(chunk[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk[:4], uint32(n))
+			if _, err := conn.Write(chunk[:4+n]); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("content cannot be read: %w", err)
+		}
+	}
+	_, err := conn.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// readReply reads the reply of clamd, ended by a null byte as the command was prefixed with z
+func readReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimSpace(strings.TrimRight(reply, "\x00")), nil
+}
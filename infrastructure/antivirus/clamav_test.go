@@ -0,0 +1,124 @@
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// eicar is the EICAR test file, detected by every antivirus without being malware
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// newTestClamd listens as clamd, reading the streamed content of a connection and replying with the reply function of it
+func newTestClamd(t *testing.T, reply func(command string, content []byte) string) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		command, _ := r.ReadString(0)
+		var content bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil || size == 0 {
+				break
+			}
+			io.CopyN(&content, r, int64(size))
+		}
+		conn.Write([]byte(reply(command, content.Bytes()) + "\x00"))
+	}()
+	return l.Addr().String()
+}
+
+// TestClamAVScan_Clean checks that Scan streams the content in chunks to clamd, returning no signature when it is clean
+func TestClamAVScan_Clean(t *testing.T) {
+	// Arrange
+	content := strings.Repeat("clean content ", clamAVChunkSize/7)
+	var command string
+	var received []byte
+	address := newTestClamd(t, func(c string, b []byte) string {
+		command, received = c, b
+		return "stream: OK"
+	})
+	s := NewClamAVScanner(address, time.Second)
+
+	// Act
+	signature, err := s.Scan(context.Background(), strings.NewReader(content))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, signature)
+	assert.Equal(t, "zINSTREAM\x00", command)
+	assert.Equal(t, content, string(received))
+}
+
+// TestClamAVScan_Infected checks that Scan returns the signature found by clamd
+func TestClamAVScan_Infected(t *testing.T) {
+	// Arrange
+	address := newTestClamd(t, func(c string, b []byte) string {
+		if string(b) == eicar {
+			return "stream: Eicar-Signature FOUND"
+		}
+		return "stream: OK"
+	})
+	s := NewClamAVScanner(address, time.Second)
+
+	// Act
+	signature, err := s.Scan(context.Background(), strings.NewReader(eicar))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Eicar-Signature", signature)
+}
+
+// TestClamAVScan_Error checks that Scan returns an error when clamd does not report the content as clean nor infected
+func TestClamAVScan_Error(t *testing.T) {
+	// Arrange
+	address := newTestClamd(t, func(c string, b []byte) string {
+		return "INSTREAM size limit exceeded. ERROR"
+	})
+	s := NewClamAVScanner(address, time.Second)
+
+	// Act
+	_, err := s.Scan(context.Background(), strings.NewReader("content"))
+
+	// Assert
+	assert.Equal(t, "clamav responded: INSTREAM size limit exceeded. ERROR", err.Error())
+}
+
+// TestClamAVScan_Unreachable checks that Scan returns an error when clamd cannot be reached
+func TestClamAVScan_Unreachable(t *testing.T) {
+	// Arrange
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	l.Close()
+	s := NewClamAVScanner(address, time.Second)
+
+	// Act
+	_, err = s.Scan(context.Background(), strings.NewReader("content"))
+
+	// Assert
+	assert.Contains(t, err.Error(), "clamav cannot be reached")
+}
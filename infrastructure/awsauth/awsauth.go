@@ -0,0 +1,154 @@
+package awsauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnsignedPayload is the payload hash of the presigned requests, whose body is not known when signed
+const UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// Credentials credentials signing the requests to AWS
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// EnvCredentials returns the credentials of the standard AWS environment variables
+func EnvCredentials() Credentials {
+	return Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// SignV4 signs the request with AWS Signature Version 4, signing its host, content type and AWS headers
+func SignV4(req *http.Request, body []byte, service, region string, credentials Credentials, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+	scope, signedHeaders, signature := sign(req, headers, sha256Hex(body), service, region, credentials, amzDate)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// PresignV4 returns the URL of the request presigned with AWS Signature Version 4 in its query, valid for expires,
+// signing its host and every header set, which the clients must send as set
+func PresignV4(req *http.Request, service, region string, credentials Credentials, now time.Time, expires time.Duration) string {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := sortedNames(headers)
+
+	query := req.URL.Query()
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", credentials.AccessKeyID+"/"+scopeOf(amzDate[:8], region, service))
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires/time.Second)))
+	query.Set("X-Amz-SignedHeaders", strings.Join(names, ";"))
+	if credentials.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+	req.URL.RawQuery = canonicalQuery(query)
+
+	_, _, signature := sign(req, headers, UnsignedPayload, service, region, credentials, amzDate)
+	req.URL.RawQuery += "&X-Amz-Signature=" + signature
+	return req.URL.String()
+}
+
+// sign returns the scope, the signed headers and the signature of the request with the given headers and payload hash
+func sign(req *http.Request, headers map[string]string, payloadHash, service, region string, credentials Credentials, amzDate string) (string, string, string) {
+	date := amzDate[:8]
+	names := sortedNames(headers)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{req.Method, path, canonicalQuery(req.URL.Query()), canonicalHeaders.String(), signedHeaders, payloadHash}, "\n")
+	scope := scopeOf(date, region, service)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := []byte("AWS4" + credentials.SecretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalQuery returns the query sorted by name and value, encoded as AWS requires it
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, Escape(name)+"="+Escape(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// Escape percent-encodes every byte of s but the unreserved characters, as AWS requires it in the paths and queries signed
+func Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func scopeOf(date, region, service string) string {
+	return strings.Join([]string{date, region, service, "aws4_request"}, "/")
+}
+
+func sortedNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
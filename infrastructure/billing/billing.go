@@ -0,0 +1,19 @@
+package billing
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// NewProvider creates the BillingProvider of the given provider, only stripe for now, verifying the events of the webhook with the given secret
+func NewProvider(provider, stripeSecretKey, webhookSecret string, webhookTolerance time.Duration) (ports.BillingProvider, error) {
+	switch provider {
+	case "stripe":
+		return NewStripeProvider(stripeSecretKey, webhookSecret, webhookTolerance, http.DefaultClient), nil
+	default:
+		return nil, fmt.Errorf("billing provider %s not valid", provider)
+	}
+}
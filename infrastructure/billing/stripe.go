@@ -0,0 +1,154 @@
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+)
+
+// stripeEndpoint is the endpoint of the REST API of Stripe
+const stripeEndpoint = "https://api.stripe.com"
+
+// stripeSubscriptionEventPrefix prefixes the types of the events of Stripe whose object is a subscription
+const stripeSubscriptionEventPrefix = "customer.subscription."
+
+// stripeProvider adapter of a billing provider managing the customers through the API of Stripe and verifying the events of its webhook endpoint
+type stripeProvider struct {
+	endpoint      string
+	secretKey     string
+	webhookSecret string
+	tolerance     time.Duration
+	client        *http.Client
+}
+
+// NewStripeProvider creates a provider authenticated with the given secret key, verifying the events with the signing secret of the webhook endpoint
+// and refusing the ones signed longer than the tolerance ago, so a captured event cannot be replayed
+func NewStripeProvider(secretKey, webhookSecret string, tolerance time.Duration, client *http.Client) ports.BillingProvider {
+	return &stripeProvider{
+		endpoint:      stripeEndpoint,
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		tolerance:     tolerance,
+		client:        client,
+	}
+}
+
+// SaveCustomer creates or updates the customer with the email and the name of the user, its ID set in the metadata.
+// The creations are sent with an idempotency key derived from the user, so a retried creation does not create a second customer.
+func (p *stripeProvider) SaveCustomer(ctx context.Context, customer ports.BillingCustomer) (string, error) {
+	form := url.Values{
+		"email":             {customer.Email},
+		"name":              {customer.Name},
+		"metadata[user_id]": {customer.UserID},
+	}
+
+	endpoint := p.endpoint + "/v1/customers"
+	if customer.ID != "" {
+		endpoint += "/" + url.PathEscape(customer.ID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+p.secretKey)
+	if customer.ID == "" {
+		req.Header.Set("Idempotency-Key", "customer-"+customer.UserID)
+	}
+	tracing.InjectHeader(ctx, req.Header)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("stripe responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var saved struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&saved); err != nil {
+		return "", err
+	}
+	return saved.ID, nil
+}
+
+// ParseEvent checks the Stripe-Signature of the event, t=timestamp followed by one v1=signature per signing secret of the endpoint:
+// the hex of the HMAC-SHA256 with the secret of the timestamp and the payload joined by a dot
+func (p *stripeProvider) ParseEvent(payload []byte, signature string) (ports.BillingEvent, error) {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ports.BillingEvent{}, fmt.Errorf("%w: header not valid", ports.ErrBillingSignatureNotValid)
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	valid := false
+	for _, s := range signatures {
+		if hmac.Equal([]byte(expected), []byte(s)) {
+			valid = true
+		}
+	}
+	if !valid {
+		return ports.BillingEvent{}, ports.ErrBillingSignatureNotValid
+	}
+	if age := time.Since(time.Unix(signedAt, 0)); age > p.tolerance {
+		return ports.BillingEvent{}, fmt.Errorf("%w: signed %s ago", ports.ErrBillingSignatureNotValid, age.Round(time.Second))
+	}
+
+	var event struct {
+		ID      string `json:"id"`
+		Type    string `json:"type"`
+		Created int64  `json:"created"`
+		Data    struct {
+			Object struct {
+				Customer string `json:"customer"`
+				Status   string `json:"status"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return ports.BillingEvent{}, fmt.Errorf("stripe event not valid: %w", err)
+	}
+	return ports.BillingEvent{
+		ID:                 event.ID,
+		Type:               event.Type,
+		CustomerID:         event.Data.Object.Customer,
+		SubscriptionStatus: event.Data.Object.Status,
+		CreatedAt:          time.Unix(event.Created, 0).UTC(),
+	}, nil
+}
+
+// SubscriptionEvent reports whether the event is a customer.subscription one, every one of them carrying the subscription with its status
+func (p *stripeProvider) SubscriptionEvent(eventType string) bool {
+	return strings.HasPrefix(eventType, stripeSubscriptionEventPrefix)
+}
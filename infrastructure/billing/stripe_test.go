@@ -0,0 +1,170 @@
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// testEvent is an event of Stripe updating the subscription of a customer
+const testEvent = `{"id":"evt_123","type":"customer.subscription.updated","created":1692525600,"data":{"object":{"id":"sub_123","object":"subscription","customer":"cus_123","status":"active"}}}`
+
+// sign returns the Stripe-Signature of the payload signed at the given time with the secret
+func sign(secret string, at time.Time, payload string) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// TestStripeSaveCustomer_Create checks that SaveCustomer creates the customer with the secret key and an idempotency key of the user, returning its ID
+func TestStripeSaveCustomer_Create(t *testing.T) {
+	// Arrange
+	var path, authorization, idempotencyKey string
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		idempotencyKey = r.Header.Get("Idempotency-Key")
+		r.ParseForm()
+		form = r.PostForm
+		w.Write([]byte(`{"id":"cus_123","object":"customer"}`))
+	}))
+	defer server.Close()
+
+	p := NewStripeProvider("sk_test", "whsec_test", 5*time.Minute, server.Client()).(*stripeProvider)
+	p.endpoint = server.URL
+
+	// Act
+	id, err := p.SaveCustomer(context.Background(), ports.BillingCustomer{UserID: "test-id", Email: "test@test.com", Name: "test name"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "cus_123", id)
+	assert.Equal(t, "/v1/customers", path)
+	assert.Equal(t, "Bearer sk_test", authorization)
+	assert.Equal(t, "customer-test-id", idempotencyKey)
+	assert.Equal(t, "test@test.com", form.Get("email"))
+	assert.Equal(t, "test name", form.Get("name"))
+	assert.Equal(t, "test-id", form.Get("metadata[user_id]"))
+}
+
+// TestStripeSaveCustomer_Update checks that SaveCustomer updates the customer when its ID is set, without an idempotency key
+func TestStripeSaveCustomer_Update(t *testing.T) {
+	// Arrange
+	var path, idempotencyKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		idempotencyKey = r.Header.Get("Idempotency-Key")
+		w.Write([]byte(`{"id":"cus_123","object":"customer"}`))
+	}))
+	defer server.Close()
+
+	p := NewStripeProvider("sk_test", "whsec_test", 5*time.Minute, server.Client()).(*stripeProvider)
+	p.endpoint = server.URL
+
+	// Act
+	id, err := p.SaveCustomer(context.Background(), ports.BillingCustomer{ID: "cus_123", UserID: "test-id", Email: "test@test.com"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "cus_123", id)
+	assert.Equal(t, "/v1/customers/cus_123", path)
+	assert.Empty(t, idempotencyKey)
+}
+
+// TestStripeSaveCustomer_ErrorStatus checks that SaveCustomer returns an error with the response of Stripe when it does not save the customer
+func TestStripeSaveCustomer_ErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"message":"Invalid API Key provided"}}`))
+	}))
+	defer server.Close()
+
+	p := NewStripeProvider("sk_test", "whsec_test", 5*time.Minute, server.Client()).(*stripeProvider)
+	p.endpoint = server.URL
+
+	// Act
+	_, err := p.SaveCustomer(context.Background(), ports.BillingCustomer{UserID: "test-id"})
+
+	// Assert
+	assert.EqualError(t, err, `stripe responded with status 401: {"error":{"message":"Invalid API Key provided"}}`)
+}
+
+// TestStripeParseEvent_Ok checks that ParseEvent returns the subscription event signed with the secret
+func TestStripeParseEvent_Ok(t *testing.T) {
+	// Arrange
+	p := NewStripeProvider("sk_test", "whsec_test", 5*time.Minute, nil).(*stripeProvider)
+	now := time.Now()
+
+	// Act
+	event, err := p.ParseEvent([]byte(testEvent), sign("whsec_test", now, testEvent)+",v0=ignored")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, ports.BillingEvent{
+		ID:                 "evt_123",
+		Type:               "customer.subscription.updated",
+		CustomerID:         "cus_123",
+		SubscriptionStatus: "active",
+		CreatedAt:          time.Unix(1692525600, 0).UTC(),
+	}, event)
+	assert.True(t, p.SubscriptionEvent(event.Type))
+}
+
+// TestStripeParseEvent_SignatureNotValid checks that ParseEvent refuses an event signed with another secret
+func TestStripeParseEvent_SignatureNotValid(t *testing.T) {
+	// Arrange
+	p := NewStripeProvider("sk_test", "whsec_test", 5*time.Minute, nil)
+
+	// Act
+	_, err := p.ParseEvent([]byte(testEvent), sign("whsec_other", time.Now(), testEvent))
+
+	// Assert
+	assert.True(t, errors.Is(err, ports.ErrBillingSignatureNotValid))
+}
+
+// TestStripeParseEvent_TooOld checks that ParseEvent refuses an event signed longer than the tolerance ago, so it cannot be replayed
+func TestStripeParseEvent_TooOld(t *testing.T) {
+	// Arrange
+	p := NewStripeProvider("sk_test", "whsec_test", 5*time.Minute, nil)
+
+	// Act
+	_, err := p.ParseEvent([]byte(testEvent), sign("whsec_test", time.Now().Add(-10*time.Minute), testEvent))
+
+	// Assert
+	assert.True(t, errors.Is(err, ports.ErrBillingSignatureNotValid))
+}
+
+// TestStripeParseEvent_HeaderNotValid checks that ParseEvent refuses an event without a timestamp in its signature
+func TestStripeParseEvent_HeaderNotValid(t *testing.T) {
+	// Arrange
+	p := NewStripeProvider("sk_test", "whsec_test", 5*time.Minute, nil)
+
+	// Act
+	_, err := p.ParseEvent([]byte(testEvent), "v1=abc")
+
+	// Assert
+	assert.True(t, errors.Is(err, ports.ErrBillingSignatureNotValid))
+}
+
+// TestNewProvider_NotValid checks that NewProvider returns an error when the provider is not known
+func TestNewProvider_NotValid(t *testing.T) {
+	// Act
+	_, err := NewProvider("paddle", "", "", time.Minute)
+
+	// Assert
+	assert.EqualError(t, err, "billing provider paddle not valid")
+}
@@ -0,0 +1,59 @@
+package directorysync
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// parseCSV returns the users of a CSV file whose header row names its email, name and surnames columns, in any order and case,
+// only the email one being required
+func parseCSV(r io.Reader) ([]ports.DirectoryEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv file empty, a header row is expected")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("csv file not valid: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		// the byte order mark written by some spreadsheets is part of the first column name
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, errors.New("csv header row has no email column")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var users []ports.DirectoryEntry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return users, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv file not valid: %w", err)
+		}
+		users = append(users, ports.DirectoryEntry{
+			Email:    field(record, "email"),
+			Name:     field(record, "name"),
+			Surnames: field(record, "surnames"),
+		})
+	}
+}
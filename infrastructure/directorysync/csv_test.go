@@ -0,0 +1,37 @@
+package directorysync
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseCSV_ColumnsInAnyOrder checks that parseCSV reads the columns named by the header row, regardless of their order, their case and a byte order mark
+func TestParseCSV_ColumnsInAnyOrder(t *testing.T) {
+	// Act
+	users, err := parseCSV(strings.NewReader("\ufeffSurnames,Department,EMAIL\nUser,Sales,test@example.com\n"))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []ports.DirectoryEntry{{Email: "test@example.com", Surnames: "User"}}, users)
+}
+
+// TestParseCSV_NoEmailColumn checks that parseCSV returns an error when the header row has no email column
+func TestParseCSV_NoEmailColumn(t *testing.T) {
+	// Act
+	_, err := parseCSV(strings.NewReader("name,surnames\nTest,User\n"))
+
+	// Assert
+	assert.Equal(t, "csv header row has no email column", err.Error())
+}
+
+// TestParseCSV_Empty checks that parseCSV returns an error when the file is empty
+func TestParseCSV_Empty(t *testing.T) {
+	// Act
+	_, err := parseCSV(strings.NewReader(""))
+
+	// Assert
+	assert.Equal(t, "csv file empty, a header row is expected", err.Error())
+}
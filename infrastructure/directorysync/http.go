@@ -0,0 +1,73 @@
+package directorysync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// httpEntry a user of the JSON feeds
+type httpEntry struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Surnames string `json:"surnames"`
+}
+
+// httpSource adapter of a directory source fetching the users from an HTTP feed, like an export of an HR system
+type httpSource struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPSource creates a source fetching the users from the URL, authenticated with the token as a bearer one when set
+func NewHTTPSource(URL, token string, client *http.Client) ports.DirectorySource {
+	return &httpSource{
+		url:    URL,
+		token:  token,
+		client: client,
+	}
+}
+
+// Users fetches the feed, a CSV file when its content type is text/csv, or a JSON array of objects with their email, name and surnames otherwise
+func (s *httpSource) Users(ctx context.Context) ([]ports.DirectoryEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json, text/csv")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return nil, fmt.Errorf("directory feed responded with status %d: %s", res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type")); mediaType == "text/csv" {
+		return parseCSV(res.Body)
+	}
+
+	var entries []httpEntry
+	if err := json.NewDecoder(res.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("directory feed not valid: %w", err)
+	}
+	users := make([]ports.DirectoryEntry, 0, len(entries))
+	for _, e := range entries {
+		users = append(users, ports.DirectoryEntry(e))
+	}
+	return users, nil
+}
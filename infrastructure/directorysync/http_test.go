@@ -0,0 +1,67 @@
+package directorysync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHTTPUsers_JSON checks that Users fetches the feed authenticated with the token, decoding it as JSON
+func TestHTTPUsers_JSON(t *testing.T) {
+	// Arrange
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"email":"test@example.com","name":"Test","surnames":"User","department":"Sales"}]`))
+	}))
+	defer server.Close()
+	source := NewHTTPSource(server.URL, "test-token", server.Client())
+
+	// Act
+	users, err := source.Users(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer test-token", authorization)
+	assert.Equal(t, []ports.DirectoryEntry{{Email: "test@example.com", Name: "Test", Surnames: "User"}}, users)
+}
+
+// TestHTTPUsers_CSV checks that Users parses the feed as CSV when its content type is text/csv
+func TestHTTPUsers_CSV(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Write([]byte("email,name,surnames\ntest@example.com,Test,User\n"))
+	}))
+	defer server.Close()
+	source := NewHTTPSource(server.URL, "", server.Client())
+
+	// Act
+	users, err := source.Users(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []ports.DirectoryEntry{{Email: "test@example.com", Name: "Test", Surnames: "User"}}, users)
+}
+
+// TestHTTPUsers_ErrorStatus checks that Users returns an error when the feed does not respond with a 2xx status
+func TestHTTPUsers_ErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	source := NewHTTPSource(server.URL, "test-token", server.Client())
+
+	// Act
+	users, err := source.Users(context.Background())
+
+	// Assert
+	assert.Nil(t, users)
+	assert.Equal(t, "directory feed responded with status 401: invalid token", err.Error())
+}
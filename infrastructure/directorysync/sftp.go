@@ -0,0 +1,294 @@
+package directorysync
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"golang.org/x/crypto/ssh"
+)
+
+// types of the SFTP packets, as of version 3 of the protocol
+const (
+	sftpInit    = 1
+	sftpVersion = 2
+	sftpOpen    = 3
+	sftpClose   = 4
+	sftpRead    = 5
+	sftpStatus  = 101
+	sftpHandle  = 102
+	sftpData    = 103
+)
+
+// SFTP status codes and open flags
+const (
+	sftpStatusOK  = 0
+	sftpStatusEOF = 1
+	sftpFlagRead  = 1
+)
+
+// sftpProtocolVersion is the version of the SFTP protocol spoken, supported by every server
+const sftpProtocolVersion = 3
+
+// sftpChunkSize is the number of bytes asked by every read, below the 32KiB every server is required to support
+const sftpChunkSize = 32 * 1024
+
+// sftpMaxPacketSize bounds the packets read from the server
+const sftpMaxPacketSize = 256 * 1024
+
+// sftpSource adapter of a directory source reading the users from a CSV file on an SFTP server, like the exports dropped by an HR system
+type sftpSource struct {
+	address string
+	path    string
+	config  *ssh.ClientConfig
+	dialer  net.Dialer
+}
+
+// NewSFTPSource creates a source reading the CSV file at the path on the SFTP server at the address, authenticated as the username
+// with the password or the private key, in PEM, and verifying the server by its host key, in the authorized_keys format
+func NewSFTPSource(address, username, password, privateKey, hostKey, path string) (ports.DirectorySource, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+	if err != nil {
+		return nil, fmt.Errorf("sftp host key not valid: %w", err)
+	}
+
+	var auth []ssh.AuthMethod
+	if privateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+		if err != nil {
+			return nil, fmt.Errorf("sftp private key not valid: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if password != "" {
+		auth = append(auth, ssh.Password(password))
+	}
+
+	return &sftpSource{
+		address: address,
+		path:    path,
+		config: &ssh.ClientConfig{
+			User:            username,
+			Auth:            auth,
+			HostKeyCallback: ssh.FixedHostKey(key),
+		},
+	}, nil
+}
+
+// Users reads the CSV file over a connection opened for the sync
+func (s *sftpSource) Users(ctx context.Context) ([]ports.DirectoryEntry, error) {
+	conn, err := s.dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return nil, fmt.Errorf("sftp server cannot be reached: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, s.address, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("ssh handshake failed: %w", err)
+	}
+	client := ssh.NewClient(sshConn, channels, requests)
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	w, err := session.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	r, err := session.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		return nil, fmt.Errorf("sftp subsystem not available: %w", err)
+	}
+
+	c := &sftpClient{w: w, r: r}
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	file, err := c.open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("sftp file %s cannot be opened: %w", s.path, err)
+	}
+	defer file.Close()
+
+	users, err := parseCSV(file)
+	if err != nil {
+		return nil, fmt.Errorf("sftp file %s: %w", s.path, err)
+	}
+	return users, nil
+}
+
+// sftpClient a client of the SFTP protocol over the channel of a session, sending one request at a time
+type sftpClient struct {
+	w  io.Writer
+	r  io.Reader
+	id uint32
+}
+
+// sftpFile a file opened for reading, read sequentially
+type sftpFile struct {
+	client *sftpClient
+	handle string
+	offset uint64
+}
+
+// init negotiates the version of the protocol
+func (c *sftpClient) init() error {
+	if err := writePacket(c.w, sftpInit, appendUint32(nil, sftpProtocolVersion)); err != nil {
+		return err
+	}
+	kind, payload, err := readPacket(c.r)
+	if err != nil {
+		return err
+	}
+	if kind != sftpVersion || len(payload) < 4 {
+		return fmt.Errorf("sftp packet %d not expected", kind)
+	}
+	if version := binary.BigEndian.Uint32(payload); version < sftpProtocolVersion {
+		return fmt.Errorf("sftp version %d not supported", version)
+	}
+	return nil
+}
+
+// request sends the request of the given type with a new ID before the payload, returning the type and the payload of its response after the ID
+func (c *sftpClient) request(kind byte, payload []byte) (byte, []byte, error) {
+	c.id++
+	if err := writePacket(c.w, kind, append(appendUint32(nil, c.id), payload...)); err != nil {
+		return 0, nil, err
+	}
+	respKind, resp, err := readPacket(c.r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(resp) < 4 || binary.BigEndian.Uint32(resp) != c.id {
+		return 0, nil, errors.New("sftp response not matching the request")
+	}
+	return respKind, resp[4:], nil
+}
+
+// open opens the file at the path for reading
+func (c *sftpClient) open(path string) (*sftpFile, error) {
+	payload := appendString(nil, path)
+	payload = appendUint32(payload, sftpFlagRead)
+	payload = appendUint32(payload, 0) // no attributes
+	kind, resp, err := c.request(sftpOpen, payload)
+	if err != nil {
+		return nil, err
+	}
+	if kind != sftpHandle {
+		return nil, statusError(kind, resp)
+	}
+	handle, _, ok := readString(resp)
+	if !ok {
+		return nil, errors.New("sftp handle not valid")
+	}
+	return &sftpFile{client: c, handle: handle}, nil
+}
+
+// Read reads the next chunk of the file
+func (f *sftpFile) Read(p []byte) (int, error) {
+	size := len(p)
+	if size > sftpChunkSize {
+		size = sftpChunkSize
+	}
+	payload := appendString(nil, f.handle)
+	payload = binary.BigEndian.AppendUint64(payload, f.offset)
+	payload = appendUint32(payload, uint32(size))
+	kind, resp, err := f.client.request(sftpRead, payload)
+	if err != nil {
+		return 0, err
+	}
+	if kind == sftpStatus && len(resp) >= 4 && binary.BigEndian.Uint32(resp) == sftpStatusEOF {
+		return 0, io.EOF
+	}
+	if kind != sftpData {
+		return 0, statusError(kind, resp)
+	}
+	data, _, ok := readString(resp)
+	if !ok || len(data) > len(p) {
+		return 0, errors.New("sftp data not valid")
+	}
+	f.offset += uint64(len(data))
+	return copy(p, data), nil
+}
+
+// Close closes the handle of the file
+func (f *sftpFile) Close() error {
+	kind, resp, err := f.client.request(sftpClose, appendString(nil, f.handle))
+	if err != nil {
+		return err
+	}
+	return statusError(kind, resp)
+}
+
+// statusError returns the error of a response, nil when it is a status ok
+func statusError(kind byte, resp []byte) error {
+	if kind != sftpStatus || len(resp) < 4 {
+		return fmt.Errorf("sftp packet %d not expected", kind)
+	}
+	code := binary.BigEndian.Uint32(resp)
+	if code == sftpStatusOK {
+		return nil
+	}
+	message, _, _ := readString(resp[4:])
+	return fmt.Errorf("sftp status %d: %s", code, strings.TrimSpace(message))
+}
+
+// writePacket writes a packet of the given type, prefixed by its length
+func writePacket(w io.Writer, kind byte, payload []byte) error {
+	packet := appendUint32(make([]byte, 0, 5+len(payload)), uint32(1+len(payload)))
+	packet = append(packet, kind)
+	_, err := w.Write(append(packet, payload...))
+	return err
+}
+
+// readPacket reads the next packet, returning its type and its payload
+func readPacket(r io.Reader) (byte, []byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size < 1 || size > sftpMaxPacketSize {
+		return 0, nil, fmt.Errorf("sftp packet length %d not valid", size)
+	}
+	packet := make([]byte, size)
+	if _, err := io.ReadFull(r, packet); err != nil {
+		return 0, nil, err
+	}
+	return packet[0], packet[1:], nil
+}
+
+func appendUint32(b []byte, n uint32) []byte {
+	return binary.BigEndian.AppendUint32(b, n)
+}
+
+func appendString(b []byte, s string) []byte {
+	return append(appendUint32(b, uint32(len(s))), s...)
+}
+
+// readString reads a string prefixed by its length, returning the rest of the buffer
+func readString(b []byte) (string, []byte, bool) {
+	if len(b) < 4 {
+		return "", nil, false
+	}
+	size := binary.BigEndian.Uint32(b)
+	if uint32(len(b)-4) < size {
+		return "", nil, false
+	}
+	return string(b[4 : 4+size]), b[4+size:], true
+}
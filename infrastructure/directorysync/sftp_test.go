@@ -0,0 +1,187 @@
+package directorysync
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+// newFakeSFTPServer starts an SSH server accepting the test-password of any user and serving the given files by path through its sftp subsystem,
+// returning its address and its host key
+func newFakeSFTPServer(t *testing.T, files map[string]string) (string, string) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if string(password) != "test-password" {
+				return nil, errors.New("password incorrect")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, config, files)
+		}
+	}()
+	return listener.Addr().String(), string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+}
+
+// serveSSH accepts the sessions of the connection, serving the files to the ones requesting the sftp subsystem
+func serveSSH(conn net.Conn, config *ssh.ServerConfig, files map[string]string) {
+	defer conn.Close()
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only sessions")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				subsystem, _, _ := readString(req.Payload)
+				req.Reply(req.Type == "subsystem" && subsystem == "sftp", nil)
+				if req.Type == "subsystem" && subsystem == "sftp" {
+					go serveSFTP(channel, files)
+				}
+			}
+		}()
+	}
+}
+
+// serveSFTP answers the init, open, read and close requests of the files
+func serveSFTP(channel ssh.Channel, files map[string]string) {
+	defer channel.Close()
+	status := func(id uint32, code uint32, message string) []byte {
+		return appendString(appendUint32(appendUint32(nil, id), code), message)
+	}
+	for {
+		kind, payload, err := readPacket(channel)
+		if err != nil {
+			return
+		}
+		if kind == sftpInit {
+			writePacket(channel, sftpVersion, appendUint32(nil, sftpProtocolVersion))
+			continue
+		}
+
+		id := binary.BigEndian.Uint32(payload)
+		path, rest, _ := readString(payload[4:])
+		content, ok := files[path]
+		switch {
+		case kind == sftpOpen && ok:
+			writePacket(channel, sftpHandle, appendString(appendUint32(nil, id), path))
+		case kind == sftpOpen:
+			writePacket(channel, sftpStatus, status(id, 2, "no such file"))
+		case kind == sftpRead:
+			offset, size := binary.BigEndian.Uint64(rest), binary.BigEndian.Uint32(rest[8:])
+			if offset >= uint64(len(content)) {
+				writePacket(channel, sftpStatus, status(id, sftpStatusEOF, "eof"))
+				continue
+			}
+			end := offset + uint64(size)
+			if end > uint64(len(content)) {
+				end = uint64(len(content))
+			}
+			writePacket(channel, sftpData, appendString(appendUint32(nil, id), content[offset:end]))
+		case kind == sftpClose:
+			writePacket(channel, sftpStatus, status(id, sftpStatusOK, ""))
+		}
+	}
+}
+
+// TestSFTPUsers_Ok checks that Users reads the users of the CSV file on the server, across several reads
+func TestSFTPUsers_Ok(t *testing.T) {
+	// Arrange
+	content := "email,name,surnames\n" + strings.Repeat("filler@example.com,Filler,User\n", 2000) + "test@example.com,Test,\"User, Jr.\"\n"
+	address, hostKey := newFakeSFTPServer(t, map[string]string{"/exports/users.csv": content})
+	source, err := NewSFTPSource(address, "sync", "test-password", "", hostKey, "/exports/users.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	users, err := source.Users(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2001, len(users))
+	assert.Equal(t, ports.DirectoryEntry{Email: "test@example.com", Name: "Test", Surnames: "User, Jr."}, users[2000])
+}
+
+// TestSFTPUsers_FileNotFound checks that Users returns an error when the file does not exist on the server
+func TestSFTPUsers_FileNotFound(t *testing.T) {
+	// Arrange
+	address, hostKey := newFakeSFTPServer(t, map[string]string{})
+	source, err := NewSFTPSource(address, "sync", "test-password", "", hostKey, "/exports/users.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	users, err := source.Users(context.Background())
+
+	// Assert
+	assert.Nil(t, users)
+	assert.Equal(t, "sftp file /exports/users.csv cannot be opened: sftp status 2: no such file", err.Error())
+}
+
+// TestSFTPUsers_HostKeyMismatch checks that Users refuses a server whose host key is not the expected one
+func TestSFTPUsers_HostKeyMismatch(t *testing.T) {
+	// Arrange
+	address, _ := newFakeSFTPServer(t, map[string]string{"/exports/users.csv": "email\ntest@example.com\n"})
+	_, otherHostKey := newFakeSFTPServer(t, map[string]string{})
+	source, err := NewSFTPSource(address, "sync", "test-password", "", otherHostKey, "/exports/users.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	users, err := source.Users(context.Background())
+
+	// Assert
+	assert.Nil(t, users)
+	assert.Contains(t, err.Error(), "ssh handshake failed")
+}
+
+// TestNewSFTPSource_InvalidHostKey checks that NewSFTPSource returns an error when the host key is not in the authorized_keys format
+func TestNewSFTPSource_InvalidHostKey(t *testing.T) {
+	// Act
+	_, err := NewSFTPSource("localhost:22", "sync", "test-password", "", "not-a-key", "/exports/users.csv")
+
+	// Assert
+	assert.Contains(t, err.Error(), "sftp host key not valid")
+}
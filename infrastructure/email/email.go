@@ -0,0 +1,22 @@
+package email
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// NewSender creates the EmailSender of the given provider, smtp, sendgrid or ses, sending the emails from the given address
+func NewSender(provider, from, smtpAddress, smtpUsername, smtpPassword, sendGridAPIKey, sesRegion string) (ports.EmailSender, error) {
+	switch provider {
+	case "smtp":
+		return NewSMTPSender(smtpAddress, smtpUsername, smtpPassword, from), nil
+	case "sendgrid":
+		return NewSendGridSender(sendGridAPIKey, from, http.DefaultClient), nil
+	case "ses":
+		return NewSESSender(sesRegion, from, http.DefaultClient), nil
+	default:
+		return nil, fmt.Errorf("email provider %s not valid", provider)
+	}
+}
@@ -0,0 +1,58 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// queuedMailer adapter of a mailer rendering the emails and queuing them as email jobs, sent through the email sender by the workers
+// and retried when the provider is not reachable
+type queuedMailer struct {
+	jobs ports.JobService
+}
+
+// NewQueuedMailer creates a mailer queuing the emails rendered from the embedded templates
+func NewQueuedMailer(jobs ports.JobService) ports.Mailer {
+	return &queuedMailer{
+		jobs: jobs,
+	}
+}
+
+// Send renders the email, failing when the template or any of its data is missing, and queues it
+func (m *queuedMailer) Send(ctx context.Context, template, to string, data map[string]string) error {
+	email, err := Render(template, to, data)
+	if err != nil {
+		return err
+	}
+
+	recipients, err := json.Marshal(email.To)
+	if err != nil {
+		return err
+	}
+	_, err = m.jobs.Enqueue(ctx, entities.JobTypeEmail, map[string]string{
+		"template": template,
+		"to":       string(recipients),
+		"subject":  email.Subject,
+		"text":     email.Text,
+		"html":     email.HTML,
+	})
+	return err
+}
+
+// Deliver sends an email job through the sender
+func Deliver(ctx context.Context, sender ports.EmailSender, job *entities.Job) error {
+	var to []string
+	if err := json.Unmarshal([]byte(job.Metadata["to"]), &to); err != nil {
+		return fmt.Errorf("email not valid: %w", err)
+	}
+	return sender.Send(ctx, ports.Email{
+		To:      to,
+		Subject: job.Metadata["subject"],
+		Text:    job.Metadata["text"],
+		HTML:    job.Metadata["html"],
+	})
+}
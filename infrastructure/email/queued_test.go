@@ -0,0 +1,72 @@
+package email
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestQueuedSend_Ok checks that Send queues an email job that Deliver sends as the rendered email through the sender
+func TestQueuedSend_Ok(t *testing.T) {
+	// Arrange
+	data := map[string]string{"name": "test", "link": "https://test.com/verify", "expires": "1 hour"}
+	expectedEmail, err := Render(ports.EmailTemplateVerification, "user@test.com", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var job entities.Job
+	jobServiceMock := mocks.NewJobService(t)
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Enqueue), context.Background(), entities.JobTypeEmail, mock.Anything).Run(func(args mock.Arguments) {
+		job = entities.Job{Type: entities.JobTypeEmail, Metadata: args.Get(2).(map[string]string)}
+	}).Return(models.JobResp{}, nil).Once()
+
+	senderMock := mocks.NewEmailSender(t)
+	senderMock.On(testutils.FunctionName(t, ports.EmailSender.Send), context.Background(), expectedEmail).Return(nil).Once()
+
+	// Act
+	err = NewQueuedMailer(jobServiceMock).Send(context.Background(), ports.EmailTemplateVerification, "user@test.com", data)
+	deliverErr := Deliver(context.Background(), senderMock, &job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, deliverErr)
+	assert.Equal(t, ports.EmailTemplateVerification, job.Metadata["template"])
+}
+
+// TestQueuedSend_MissingData checks that Send does not queue the email when it cannot be rendered
+func TestQueuedSend_MissingData(t *testing.T) {
+	// Act
+	err := NewQueuedMailer(mocks.NewJobService(t)).Send(context.Background(), ports.EmailTemplateVerification, "user@test.com", nil)
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+// TestDeliver_NotValid checks that Deliver returns an error when the recipients of the email job are not valid
+func TestDeliver_NotValid(t *testing.T) {
+	// Arrange
+	job := entities.Job{Metadata: map[string]string{"to": "user@test.com"}}
+
+	// Act
+	err := Deliver(context.Background(), mocks.NewEmailSender(t), &job)
+
+	// Assert
+	assert.ErrorContains(t, err, "email not valid")
+}
+
+// TestNewSender_NotValid checks that NewSender returns an error when the provider is not known
+func TestNewSender_NotValid(t *testing.T) {
+	// Act
+	_, err := NewSender("mailgun", "api@test.com", "", "", "", "", "")
+
+	// Assert
+	assert.EqualError(t, err, "email provider mailgun not valid")
+}
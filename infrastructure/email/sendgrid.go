@@ -0,0 +1,84 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+)
+
+// sendGridEndpoint is the endpoint of the mail send API of SendGrid
+const sendGridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+
+// sendGridSender adapter of an email sender sending the emails through the mail send API of SendGrid
+type sendGridSender struct {
+	endpoint string
+	apiKey   string
+	from     string
+	client   *http.Client
+}
+
+// NewSendGridSender creates a sender sending the emails from the given address through SendGrid, authenticating with the given API key
+func NewSendGridSender(apiKey, from string, client *http.Client) ports.EmailSender {
+	return &sendGridSender{
+		endpoint: sendGridEndpoint,
+		apiKey:   apiKey,
+		from:     from,
+		client:   client,
+	}
+}
+
+// Send sends the email, propagating the trace of the context, if any
+func (s *sendGridSender) Send(ctx context.Context, email ports.Email) error {
+	type address struct {
+		Email string `json:"email"`
+	}
+	type content struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	to := make([]address, 0, len(email.To))
+	for _, recipient := range email.To {
+		to = append(to, address{Email: recipient})
+	}
+	contents := []content{{Type: "text/plain", Value: email.Text}}
+	if email.HTML != "" {
+		contents = append(contents, content{Type: "text/html", Value: email.HTML})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"personalizations": []map[string]interface{}{{"to": to}},
+		"from":             address{Email: s.from},
+		"subject":          email.Subject,
+		"content":          contents,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	tracing.InjectHeader(ctx, req.Header)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sendgrid responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
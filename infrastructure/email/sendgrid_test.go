@@ -0,0 +1,74 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSendGridSend_Ok checks that Send posts the email to SendGrid with the API key, with its text and HTML contents
+func TestSendGridSend_Ok(t *testing.T) {
+	// Arrange
+	var authorization string
+	var body struct {
+		Personalizations []struct {
+			To []struct {
+				Email string `json:"email"`
+			} `json:"to"`
+		} `json:"personalizations"`
+		From struct {
+			Email string `json:"email"`
+		} `json:"from"`
+		Subject string `json:"subject"`
+		Content []struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		} `json:"content"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	s := NewSendGridSender("test-key", "api@test.com", server.Client()).(*sendGridSender)
+	s.endpoint = server.URL
+
+	// Act
+	err := s.Send(context.Background(), ports.Email{To: []string{"user@test.com"}, Subject: "Test subject", Text: "test text", HTML: "<p>test html</p>"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer test-key", authorization)
+	assert.Equal(t, "user@test.com", body.Personalizations[0].To[0].Email)
+	assert.Equal(t, "api@test.com", body.From.Email)
+	assert.Equal(t, "Test subject", body.Subject)
+	assert.Len(t, body.Content, 2)
+	assert.Equal(t, "text/plain", body.Content[0].Type)
+	assert.Equal(t, "<p>test html</p>", body.Content[1].Value)
+}
+
+// TestSendGridSend_ErrorStatus checks that Send returns an error with the response of SendGrid when it does not accept the email
+func TestSendGridSend_ErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"errors":[{"message":"invalid key"}]}`))
+	}))
+	defer server.Close()
+
+	s := NewSendGridSender("test-key", "api@test.com", server.Client()).(*sendGridSender)
+	s.endpoint = server.URL
+
+	// Act
+	err := s.Send(context.Background(), ports.Email{To: []string{"user@test.com"}})
+
+	// Assert
+	assert.EqualError(t, err, `sendgrid responded with status 401: {"errors":[{"message":"invalid key"}]}`)
+}
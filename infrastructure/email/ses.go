@@ -0,0 +1,83 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/awsauth"
+)
+
+// sesSender adapter of an email sender sending the emails through the SendEmail API v2 of Amazon SES, signing the requests with Signature Version 4
+type sesSender struct {
+	endpoint    string
+	region      string
+	from        string
+	credentials awsauth.Credentials
+	client      *http.Client
+	now         func() time.Time
+}
+
+// NewSESSender creates a sender sending the emails from the given verified identity through Amazon SES in the given region,
+// authenticating with the standard AWS environment variables
+func NewSESSender(region, from string, client *http.Client) ports.EmailSender {
+	return &sesSender{
+		endpoint:    fmt.Sprintf("https://email.%s.amazonaws.com", region),
+		region:      region,
+		from:        from,
+		credentials: awsauth.EnvCredentials(),
+		client:      client,
+		now:         time.Now,
+	}
+}
+
+// Send sends the email
+func (s *sesSender) Send(ctx context.Context, email ports.Email) error {
+	type content struct {
+		Data    string `json:"Data"`
+		Charset string `json:"Charset"`
+	}
+	emailBody := map[string]content{"Text": {Data: email.Text, Charset: "UTF-8"}}
+	if email.HTML != "" {
+		emailBody["Html"] = content{Data: email.HTML, Charset: "UTF-8"}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"FromEmailAddress": s.from,
+		"Destination":      map[string][]string{"ToAddresses": email.To},
+		"Content": map[string]interface{}{
+			"Simple": map[string]interface{}{
+				"Subject": content{Data: email.Subject, Charset: "UTF-8"},
+				"Body":    emailBody,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	awsauth.SignV4(req, body, "ses", s.region, s.credentials, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("ses responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
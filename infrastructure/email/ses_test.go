@@ -0,0 +1,66 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/awsauth"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSESSend_Ok checks that Send posts the email to the outbound emails of SES, signed for the ses service of the region
+func TestSESSend_Ok(t *testing.T) {
+	// Arrange
+	var path, authorization string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"MessageId":"test-id"}`))
+	}))
+	defer server.Close()
+
+	s := NewSESSender("eu-west-1", "api@test.com", server.Client()).(*sesSender)
+	s.endpoint = server.URL
+	s.credentials = awsauth.Credentials{AccessKeyID: "test-key-id", SecretAccessKey: "test-secret-key"}
+	s.now = func() time.Time { return time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC) }
+
+	// Act
+	err := s.Send(context.Background(), ports.Email{To: []string{"user@test.com"}, Subject: "Test subject", Text: "test text"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "/v2/email/outbound-emails", path)
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=test-key-id/20230715/eu-west-1/ses/aws4_request"), authorization)
+	assert.Equal(t, "api@test.com", body["FromEmailAddress"])
+	assert.Equal(t, map[string]interface{}{"ToAddresses": []interface{}{"user@test.com"}}, body["Destination"])
+	simple := body["Content"].(map[string]interface{})["Simple"].(map[string]interface{})
+	assert.Equal(t, "Test subject", simple["Subject"].(map[string]interface{})["Data"])
+	assert.NotContains(t, simple["Body"], "Html")
+}
+
+// TestSESSend_ErrorStatus checks that Send returns an error with the response of SES when it does not accept the email
+func TestSESSend_ErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"Email address is not verified."}`))
+	}))
+	defer server.Close()
+
+	s := NewSESSender("eu-west-1", "api@test.com", server.Client()).(*sesSender)
+	s.endpoint = server.URL
+
+	// Act
+	err := s.Send(context.Background(), ports.Email{To: []string{"user@test.com"}})
+
+	// Assert
+	assert.EqualError(t, err, `ses responded with status 400: {"message":"Email address is not verified."}`)
+}
@@ -0,0 +1,86 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+)
+
+// smtpSender adapter of an email sender sending the emails through an SMTP server
+type smtpSender struct {
+	address string
+	auth    smtp.Auth
+	from    string
+	send    func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPSender creates a sender sending the emails from the given address through the SMTP server at address,
+// authenticating with the username and password when set
+func NewSMTPSender(address, username, password, from string) ports.EmailSender {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := net.SplitHostPort(address)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpSender{
+		address: address,
+		auth:    auth,
+		from:    from,
+		send:    smtp.SendMail,
+	}
+}
+
+// Send sends the email, the SMTP client not supporting the cancellation of the context once started.
+// The trace of the context, if any, is propagated in the Traceparent and Tracestate headers of the email.
+func (s *smtpSender) Send(ctx context.Context, email ports.Email) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	msg, err := message(ctx, s.from, email)
+	if err != nil {
+		return err
+	}
+	return s.send(s.address, s.auth, s.from, email.To, msg)
+}
+
+// message returns the MIME message of the email, a multipart alternative of its text and HTML bodies when it has both
+func message(ctx context.Context, from string, email ports.Email) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n%s",
+		from, strings.Join(email.To, ", "), mime.QEncoding.Encode("UTF-8", email.Subject), tracing.MIMEHeaders(ctx))
+	if email.HTML == "" {
+		fmt.Fprintf(&b, "Content-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n", email.Text)
+		return b.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", email.Text},
+		{"text/html; charset=UTF-8", email.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	b.Write(body.Bytes())
+	return b.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package email
+
+import (
+	"context"
+	"net/smtp"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSMTPSend_Text checks that Send sends a plain text email from the configured address when it has no HTML body
+func TestSMTPSend_Text(t *testing.T) {
+	// Arrange
+	s := NewSMTPSender("localhost:25", "", "", "api@test.com").(*smtpSender)
+	var sentFrom, sentMsg string
+	var sentTo []string
+	s.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sentFrom, sentTo, sentMsg = from, to, string(msg)
+		return nil
+	}
+
+	// Act
+	err := s.Send(context.Background(), ports.Email{To: []string{"user@test.com"}, Subject: "Test subject", Text: "test text"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "api@test.com", sentFrom)
+	assert.Equal(t, []string{"user@test.com"}, sentTo)
+	assert.Contains(t, sentMsg, "To: user@test.com\r\nSubject: Test subject\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\ntest text\r\n")
+}
+
+// TestSMTPSend_HTML checks that Send sends a multipart alternative of the text and HTML bodies, encoding the subject not being ASCII
+func TestSMTPSend_HTML(t *testing.T) {
+	// Arrange
+	s := NewSMTPSender("localhost:25", "", "", "api@test.com").(*smtpSender)
+	var sentMsg string
+	s.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sentMsg = string(msg)
+		return nil
+	}
+
+	// Act
+	err := s.Send(context.Background(), ports.Email{To: []string{"user@test.com"}, Subject: "Contraseña", Text: "test text", HTML: "<p>test html</p>"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Contains(t, sentMsg, "Subject: =?UTF-8?q?Contrase=C3=B1a?=\r\n")
+	assert.Contains(t, sentMsg, "Content-Type: multipart/alternative; boundary=")
+	assert.Contains(t, sentMsg, "Content-Type: text/plain; charset=UTF-8\r\n\r\ntest text")
+	assert.Contains(t, sentMsg, "Content-Type: text/html; charset=UTF-8\r\n\r\n<p>test html</p>")
+}
+
+// TestSMTPSend_ContextCancelled checks that Send does not send the email once the context is cancelled
+func TestSMTPSend_ContextCancelled(t *testing.T) {
+	// Arrange
+	s := NewSMTPSender("localhost:25", "", "", "api@test.com").(*smtpSender)
+	s.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		t.Fatal("email sent")
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	err := s.Send(ctx, ports.Email{To: []string{"user@test.com"}})
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled)
+}
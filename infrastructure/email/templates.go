@@ -0,0 +1,70 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// emailTemplate template of a transactional email, its subject and text body being rendered as plain text
+// and its HTML body escaping the data
+type emailTemplate struct {
+	text *template.Template
+	html *htmltemplate.Template
+}
+
+// templates the transactional emails are rendered from, by name, each file defining the subject, text and html templates
+var templates = mustParseTemplates()
+
+// mustParseTemplates parses the embedded templates, a data key missing failing their rendering
+func mustParseTemplates() map[string]emailTemplate {
+	files, err := templateFiles.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+
+	parsed := make(map[string]emailTemplate, len(files))
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Name(), path.Ext(f.Name()))
+		file := path.Join("templates", f.Name())
+		parsed[name] = emailTemplate{
+			text: template.Must(template.New(name).Option("missingkey=error").ParseFS(templateFiles, file)),
+			html: htmltemplate.Must(htmltemplate.New(name).Option("missingkey=error").ParseFS(templateFiles, file)),
+		}
+	}
+	return parsed
+}
+
+// Render returns the email to the given recipient rendered from the template with the given name and data
+func Render(name, to string, data map[string]string) (ports.Email, error) {
+	t, ok := templates[name]
+	if !ok {
+		return ports.Email{}, fmt.Errorf("email template %s not found", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return ports.Email{}, err
+	}
+	if err := t.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return ports.Email{}, err
+	}
+	if err := t.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return ports.Email{}, err
+	}
+	return ports.Email{
+		To:      []string{to},
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()),
+		HTML:    strings.TrimSpace(html.String()),
+	}, nil
+}
@@ -0,0 +1,60 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRender_Ok checks that Render renders the subject and the bodies of the template, escaping the data only in the HTML body
+func TestRender_Ok(t *testing.T) {
+	// Arrange
+	data := map[string]string{"name": "Tom & Jerry", "event": "password changed", "detail": "The password of your account was changed", "at": "15 July 2023 at 09:00 UTC"}
+
+	// Act
+	email, err := Render(ports.EmailTemplateSecurityAlert, "user@test.com", data)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"user@test.com"}, email.To)
+	assert.Equal(t, "Security alert: password changed", email.Subject)
+	assert.Contains(t, email.Text, "Hello Tom & Jerry,")
+	assert.Contains(t, email.Text, "The password of your account was changed on 15 July 2023 at 09:00 UTC.")
+	assert.Contains(t, email.HTML, "<p>Hello Tom &amp; Jerry,</p>")
+}
+
+// TestRender_EveryTemplate checks that every template of the transactional emails is embedded
+func TestRender_EveryTemplate(t *testing.T) {
+	// Arrange
+	data := map[string]string{"name": "test", "link": "https://test.com", "expires": "1 hour", "event": "test", "detail": "test", "at": "test", "code": "123456"}
+
+	for _, name := range []string{ports.EmailTemplateVerification, ports.EmailTemplatePasswordReset, ports.EmailTemplateSecurityAlert, ports.EmailTemplateLoginCode} {
+		// Act
+		email, err := Render(name, "user@test.com", data)
+
+		// Assert
+		assert.Nil(t, err, name)
+		assert.NotEmpty(t, email.Subject, name)
+		assert.NotEmpty(t, email.Text, name)
+		assert.NotEmpty(t, email.HTML, name)
+	}
+}
+
+// TestRender_MissingData checks that Render returns an error when any data of the template is missing
+func TestRender_MissingData(t *testing.T) {
+	// Act
+	_, err := Render(ports.EmailTemplatePasswordReset, "user@test.com", map[string]string{"name": "test"})
+
+	// Assert
+	assert.ErrorContains(t, err, `map has no entry for key "expires"`)
+}
+
+// TestRender_TemplateNotFound checks that Render returns an error when the template does not exist
+func TestRender_TemplateNotFound(t *testing.T) {
+	// Act
+	_, err := Render("welcome", "user@test.com", nil)
+
+	// Assert
+	assert.EqualError(t, err, "email template welcome not found")
+}
@@ -0,0 +1,67 @@
+package emailcheck
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// builtinDisposableDomains the best known disposable email services, extended through the config
+var builtinDisposableDomains = []string{
+	"10minutemail.com",
+	"discard.email",
+	"dispostable.com",
+	"emailondeck.com",
+	"fakeinbox.com",
+	"getnada.com",
+	"guerrillamail.com",
+	"guerrillamail.net",
+	"mailinator.com",
+	"maildrop.cc",
+	"mailnesia.com",
+	"mintemail.com",
+	"mohmal.com",
+	"sharklasers.com",
+	"temp-mail.org",
+	"tempmail.com",
+	"throwawaymail.com",
+	"trashmail.com",
+	"yopmail.com",
+}
+
+// disposableSet returns the set of the built-in disposable domains, the given ones and the ones listed in the file at path, if set.
+// The file lists a domain per line, ignoring the blank lines and the ones starting with #, like the lists maintained by the community.
+func disposableSet(domains []string, path string) (map[string]bool, error) {
+	set := make(map[string]bool, len(builtinDisposableDomains)+len(domains))
+	add := func(domain string) {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" && !strings.HasPrefix(domain, "#") {
+			set[domain] = true
+		}
+	}
+	for _, domain := range builtinDisposableDomains {
+		add(domain)
+	}
+	for _, domain := range domains {
+		add(domain)
+	}
+	if path == "" {
+		return set, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("disposable domains list cannot be opened: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		add(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("disposable domains list cannot be read: %w", err)
+	}
+	return set, nil
+}
@@ -0,0 +1,133 @@
+package emailcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/mail"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// length limits of the addresses and of their local part, as of RFC 5321
+const (
+	maxAddressLength   = 254
+	maxLocalPartLength = 64
+)
+
+// resolver the DNS lookups of the verifier, implemented by net.Resolver
+type resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// verifier adapter of an email verifier checking the syntax of the emails, their domain against the disposable ones
+// and, when checkMX is set, the mail servers of their domain in the DNS
+type verifier struct {
+	disposable map[string]bool
+	checkMX    bool
+	resolver   resolver
+}
+
+// NewVerifier creates an email verifier refusing the built-in disposable domains, the given ones and the ones listed in the file at disposableListPath, if set,
+// and the domains without mail servers when checkMX is set
+func NewVerifier(checkMX bool, disposableDomains []string, disposableListPath string) (ports.EmailVerifier, error) {
+	disposable, err := disposableSet(disposableDomains, disposableListPath)
+	if err != nil {
+		return nil, err
+	}
+	return &verifier{
+		disposable: disposable,
+		checkMX:    checkMX,
+		resolver:   net.DefaultResolver,
+	}, nil
+}
+
+// Verify checks the syntax, the domain and the mail servers of the email in that order, returning the reason of the first check failed
+func (v *verifier) Verify(ctx context.Context, email string) (string, error) {
+	domain, ok := parseDomain(email)
+	if !ok {
+		return ports.EmailReasonSyntax, nil
+	}
+	if v.isDisposable(domain) {
+		return ports.EmailReasonDisposable, nil
+	}
+	if !v.checkMX {
+		return "", nil
+	}
+	return v.verifyMailServer(ctx, domain)
+}
+
+// parseDomain returns the lower-cased domain of a bare address, like test@example.com, reporting whether it is valid
+func parseDomain(email string) (string, bool) {
+	if len(email) > maxAddressLength {
+		return "", false
+	}
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Name != "" || address.Address != email {
+		return "", false
+	}
+	at := strings.LastIndex(email, "@")
+	if at > maxLocalPartLength {
+		return "", false
+	}
+
+	domain := strings.ToLower(email[at+1:])
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "", false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "", false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return "", false
+			}
+		}
+	}
+	return domain, true
+}
+
+// isDisposable reports whether the domain, or any domain it is a subdomain of, is disposable
+func (v *verifier) isDisposable(domain string) bool {
+	for {
+		if v.disposable[domain] {
+			return true
+		}
+		dot := strings.Index(domain, ".")
+		if dot < 0 {
+			return false
+		}
+		domain = domain[dot+1:]
+	}
+}
+
+// verifyMailServer checks that the domain receives emails, through its MX records or, without them, its address records as of RFC 5321.
+// A domain whose only MX record is the null one, as of RFC 7505, explicitly receives no emails.
+func (v *verifier) verifyMailServer(ctx context.Context, domain string) (string, error) {
+	records, err := v.resolver.LookupMX(ctx, domain)
+	if err == nil && len(records) > 0 {
+		if len(records) == 1 && (records[0].Host == "." || records[0].Host == "") {
+			return ports.EmailReasonNoMailServer, nil
+		}
+		return "", nil
+	}
+	if err != nil && !notFound(err) {
+		return "", err
+	}
+
+	_, err = v.resolver.LookupHost(ctx, domain)
+	if notFound(err) {
+		return ports.EmailReasonNoMailServer, nil
+	}
+	return "", err
+}
+
+// notFound reports whether the DNS lookup failed because the name or its records do not exist
+func notFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,158 @@
+package emailcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// testResolver resolves the MX and address records of its domains, any other domain not being found
+type testResolver struct {
+	mx    map[string][]*net.MX
+	hosts map[string][]string
+	err   error
+}
+
+func (r testResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if records, ok := r.mx[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r testResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addresses, ok := r.hosts[host]; ok {
+		return addresses, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+// newTestVerifier creates a verifier checking the MX records through the resolver
+func newTestVerifier(t *testing.T, r resolver, disposableDomains ...string) *verifier {
+	t.Helper()
+
+	v, err := NewVerifier(true, disposableDomains, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.(*verifier).resolver = r
+	return v.(*verifier)
+}
+
+// TestVerify_Deliverable checks that Verify returns no reason for the emails whose domain has mail servers, either MX records or an address
+func TestVerify_Deliverable(t *testing.T) {
+	// Arrange
+	v := newTestVerifier(t, testResolver{
+		mx:    map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+		hosts: map[string][]string{"example.org": {"93.184.216.34"}},
+	})
+
+	// Act
+	mxReason, mxErr := v.Verify(context.Background(), "test.user+tag@Example.com")
+	hostReason, hostErr := v.Verify(context.Background(), "test@example.org")
+
+	// Assert
+	assert.Nil(t, mxErr)
+	assert.Empty(t, mxReason)
+	assert.Nil(t, hostErr)
+	assert.Empty(t, hostReason)
+}
+
+// TestVerify_InvalidSyntax checks that Verify refuses the emails that are not bare addresses with a valid domain
+func TestVerify_InvalidSyntax(t *testing.T) {
+	// Arrange
+	v := newTestVerifier(t, testResolver{})
+
+	// Act
+	noAt, _ := v.Verify(context.Background(), "test.example.com")
+	named, _ := v.Verify(context.Background(), "Test <test@example.com>")
+	noTLD, _ := v.Verify(context.Background(), "test@localhost")
+	badLabel, _ := v.Verify(context.Background(), "test@-example.com")
+
+	// Assert
+	assert.Equal(t, ports.EmailReasonSyntax, noAt)
+	assert.Equal(t, ports.EmailReasonSyntax, named)
+	assert.Equal(t, ports.EmailReasonSyntax, noTLD)
+	assert.Equal(t, ports.EmailReasonSyntax, badLabel)
+}
+
+// TestVerify_Disposable checks that Verify refuses the built-in disposable domains, the configured ones and their subdomains
+func TestVerify_Disposable(t *testing.T) {
+	// Arrange
+	v := newTestVerifier(t, testResolver{}, "Throwaway.test")
+
+	// Act
+	builtin, _ := v.Verify(context.Background(), "test@mailinator.com")
+	configured, _ := v.Verify(context.Background(), "test@throwaway.test")
+	subdomain, _ := v.Verify(context.Background(), "test@eu.yopmail.com")
+
+	// Assert
+	assert.Equal(t, ports.EmailReasonDisposable, builtin)
+	assert.Equal(t, ports.EmailReasonDisposable, configured)
+	assert.Equal(t, ports.EmailReasonDisposable, subdomain)
+}
+
+// TestVerify_NoMailServer checks that Verify refuses the domains without mail servers and the ones with the null MX record
+func TestVerify_NoMailServer(t *testing.T) {
+	// Arrange
+	v := newTestVerifier(t, testResolver{mx: map[string][]*net.MX{"nomail.example.com": {{Host: ".", Pref: 0}}}})
+
+	// Act
+	unknown, unknownErr := v.Verify(context.Background(), "test@unknown.example.com")
+	nullMX, nullMXErr := v.Verify(context.Background(), "test@nomail.example.com")
+
+	// Assert
+	assert.Nil(t, unknownErr)
+	assert.Equal(t, ports.EmailReasonNoMailServer, unknown)
+	assert.Nil(t, nullMXErr)
+	assert.Equal(t, ports.EmailReasonNoMailServer, nullMX)
+}
+
+// TestVerify_LookupError checks that Verify returns an error when the MX records cannot be looked up
+func TestVerify_LookupError(t *testing.T) {
+	// Arrange
+	expectedError := &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}
+	v := newTestVerifier(t, testResolver{err: expectedError})
+
+	// Act
+	_, err := v.Verify(context.Background(), "test@example.com")
+
+	// Assert
+	assert.True(t, errors.Is(err, expectedError))
+}
+
+// TestNewVerifier_DisposableList checks that NewVerifier reads the disposable domains of the list file, skipping its comments and blank lines
+func TestNewVerifier_DisposableList(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "disposable.txt")
+	if err := os.WriteFile(path, []byte("# disposable domains\n\nlisted.test\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	v, err := NewVerifier(false, nil, path)
+
+	// Assert
+	assert.Nil(t, err)
+	listed, _ := v.Verify(context.Background(), "test@listed.test")
+	assert.Equal(t, ports.EmailReasonDisposable, listed)
+	assert.False(t, v.(*verifier).disposable["# disposable domains"])
+}
+
+// TestNewVerifier_ListNotFound checks that NewVerifier returns an error when the disposable domains list cannot be opened
+func TestNewVerifier_ListNotFound(t *testing.T) {
+	// Act
+	_, err := NewVerifier(false, nil, filepath.Join(t.TempDir(), "missing.txt"))
+
+	// Assert
+	assert.Contains(t, err.Error(), "disposable domains list cannot be opened")
+}
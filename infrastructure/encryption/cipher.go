@@ -0,0 +1,83 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// prefix marks the values encrypted by a FieldCipher, so plaintext values stored before enabling encryption can still be read
+const prefix = "enc:"
+
+// FieldCipher encrypts and decrypts field values deterministically with AES-256-GCM.
+// The nonce is derived from an HMAC of the plaintext, so equal values produce equal ciphertexts
+// and encrypted fields keep working in equality filters and unique indexes.
+type FieldCipher struct {
+	aead   cipher.AEAD
+	macKey []byte
+}
+
+// NewFieldCipher creates a FieldCipher from a 32 bytes data key
+func NewFieldCipher(dataKey []byte) (*FieldCipher, error) {
+	if len(dataKey) != 32 {
+		return nil, fmt.Errorf("data key must be 32 bytes long, got %d", len(dataKey))
+	}
+
+	block, err := aes.NewCipher(derive(dataKey, "encryption"))
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FieldCipher{
+		aead:   aead,
+		macKey: derive(dataKey, "nonce"),
+	}, nil
+}
+
+// Encrypt returns the encrypted representation of the given value. Empty values are kept empty.
+func (c *FieldCipher) Encrypt(value string) string {
+	if value == "" || strings.HasPrefix(value, prefix) {
+		return value
+	}
+
+	nonce := derive(c.macKey, value)[:c.aead.NonceSize()]
+	sealed := c.aead.Seal(nonce, nonce, []byte(value), nil)
+	return prefix + base64.RawStdEncoding.EncodeToString(sealed)
+}
+
+// Decrypt returns the plaintext of the given value. Values not encrypted by a FieldCipher are returned as they are.
+func (c *FieldCipher) Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, prefix) {
+		return value, nil
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, prefix))
+	if err != nil {
+		return "", fmt.Errorf("encrypted value not valid: %w", err)
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return "", fmt.Errorf("encrypted value not valid: too short")
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("encrypted value not valid: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+func derive(key []byte, label string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(label))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,99 @@
+package encryption
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testDataKey = []byte("0123456789abcdef0123456789abcdef")
+
+// TestNewFieldCipher_InvalidKey checks that NewFieldCipher returns an error when the received key does not have the expected length
+func TestNewFieldCipher_InvalidKey(t *testing.T) {
+	// Arrange
+	expectedError := "data key must be 32 bytes long, got 3"
+
+	// Act
+	_, err := NewFieldCipher([]byte("key"))
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestEncrypt_Deterministic checks that Encrypt returns the same ciphertext for the same value, and that Decrypt reverts it
+func TestEncrypt_Deterministic(t *testing.T) {
+	// Arrange
+	cipher, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := "test@test.com"
+
+	// Act
+	first := cipher.Encrypt(value)
+	second := cipher.Encrypt(value)
+	plaintext, err := cipher.Decrypt(first)
+
+	// Assert
+	assert.True(t, strings.HasPrefix(first, prefix))
+	assert.NotContains(t, first, value)
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, cipher.Encrypt("other@test.com"))
+	assert.Nil(t, err)
+	assert.Equal(t, value, plaintext)
+}
+
+// TestEncrypt_AlreadyEncrypted checks that Encrypt does not encrypt twice empty or already encrypted values
+func TestEncrypt_AlreadyEncrypted(t *testing.T) {
+	// Arrange
+	cipher, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted := cipher.Encrypt("test@test.com")
+
+	// Act
+	empty := cipher.Encrypt("")
+	twice := cipher.Encrypt(encrypted)
+
+	// Assert
+	assert.Empty(t, empty)
+	assert.Equal(t, encrypted, twice)
+}
+
+// TestDecrypt_Plaintext checks that Decrypt returns values not encrypted as they are
+func TestDecrypt_Plaintext(t *testing.T) {
+	// Arrange
+	cipher, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := "test@test.com"
+
+	// Act
+	plaintext, err := cipher.Decrypt(value)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, value, plaintext)
+}
+
+// TestDecrypt_WrongKey checks that Decrypt returns an error when the value was encrypted with another key
+func TestDecrypt_WrongKey(t *testing.T) {
+	// Arrange
+	cipher, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCipher, err := NewFieldCipher([]byte("fedcba9876543210fedcba9876543210"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err = otherCipher.Decrypt(cipher.Encrypt("test@test.com"))
+
+	// Assert
+	assert.NotEmpty(t, err)
+}
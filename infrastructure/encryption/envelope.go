@@ -0,0 +1,97 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+)
+
+// envelopeVersion is the first byte of the envelopes, so their format can be changed keeping the previous ones readable
+const envelopeVersion = 1
+
+// SealEnvelope encrypts a value with a new data key, wrapped by the KMS provider, returning the envelope holding both of them in base64.
+// As every value has its own data key, the envelopes can be decrypted with the master key only, without a data key to distribute.
+func SealEnvelope(ctx context.Context, provider KeyProvider, value string) (string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	wrappedKey, err := provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return "", err
+	}
+	if len(wrappedKey) > 0xFFFF {
+		return "", fmt.Errorf("wrapped key too long")
+	}
+
+	aead, err := envelopeAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	envelope := []byte{envelopeVersion}
+	envelope = binary.BigEndian.AppendUint16(envelope, uint16(len(wrappedKey)))
+	envelope = append(envelope, wrappedKey...)
+	envelope = append(envelope, nonce...)
+	envelope = aead.Seal(envelope, nonce, []byte(value), nil)
+	return base64.StdEncoding.EncodeToString(envelope), nil
+}
+
+// OpenEnvelope decrypts a value sealed by SealEnvelope, unwrapping its data key with the KMS provider
+func OpenEnvelope(ctx context.Context, provider KeyProvider, envelope string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(envelope)
+	if err != nil {
+		return "", fmt.Errorf("envelope not valid: %w", err)
+	}
+	if len(b) < 3 {
+		return "", fmt.Errorf("envelope not valid: too short")
+	}
+	if b[0] != envelopeVersion {
+		return "", fmt.Errorf("envelope version %d not supported", b[0])
+	}
+	keyLen := int(binary.BigEndian.Uint16(b[1:3]))
+	b = b[3:]
+	if len(b) < keyLen {
+		return "", fmt.Errorf("envelope not valid: too short")
+	}
+
+	dataKey, err := provider.UnwrapKey(ctx, b[:keyLen])
+	if err != nil {
+		return "", fmt.Errorf("cannot unwrap data key: %w", err)
+	}
+	aead, err := envelopeAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	b = b[keyLen:]
+	if len(b) < aead.NonceSize() {
+		return "", fmt.Errorf("envelope not valid: too short")
+	}
+
+	nonce, ciphertext := b[:aead.NonceSize()], b[aead.NonceSize():]
+	value, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("envelope not valid: %w", err)
+	}
+	return string(value), nil
+}
+
+// envelopeAEAD returns the AES-256-GCM cipher of the data key of an envelope
+func envelopeAEAD(dataKey []byte) (cipher.AEAD, error) {
+	if len(dataKey) != 32 {
+		return nil, fmt.Errorf("data key must be 32 bytes long, got %d", len(dataKey))
+	}
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,77 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSealEnvelope_Ok checks that a value sealed in an envelope is opened back to the original value, with a different envelope every time
+func TestSealEnvelope_Ok(t *testing.T) {
+	// Arrange
+	provider, err := NewLocalKeyProvider(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := "test-value"
+
+	// Act
+	first, err := SealEnvelope(context.Background(), provider, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SealEnvelope(context.Background(), provider, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, err := OpenEnvelope(context.Background(), provider, first)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, value, opened)
+	assert.NotEqual(t, first, second)
+}
+
+// TestOpenEnvelope_WrongMasterKey checks that OpenEnvelope returns an error when the data key was wrapped with another master key
+func TestOpenEnvelope_WrongMasterKey(t *testing.T) {
+	// Arrange
+	provider, err := NewLocalKeyProvider(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewLocalKeyProvider([]byte("fedcba9876543210fedcba9876543210"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope, err := SealEnvelope(context.Background(), provider, "test-value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err = OpenEnvelope(context.Background(), other, envelope)
+
+	// Assert
+	assert.ErrorContains(t, err, "cannot unwrap data key")
+}
+
+// TestOpenEnvelope_NotValid checks that OpenEnvelope returns an error when the envelope is not valid
+func TestOpenEnvelope_NotValid(t *testing.T) {
+	// Arrange
+	provider, err := NewLocalKeyProvider(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, notBase64 := OpenEnvelope(context.Background(), provider, "not base64!")
+	_, tooShort := OpenEnvelope(context.Background(), provider, base64.StdEncoding.EncodeToString([]byte{envelopeVersion, 0, 40}))
+	_, unknownVersion := OpenEnvelope(context.Background(), provider, base64.StdEncoding.EncodeToString([]byte{9, 0, 0}))
+
+	// Assert
+	assert.ErrorContains(t, notBase64, "envelope not valid")
+	assert.EqualError(t, tooShort, "envelope not valid: too short")
+	assert.EqualError(t, unknownVersion, "envelope version 9 not supported")
+}
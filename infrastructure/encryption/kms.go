@@ -0,0 +1,186 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	azureAPIVersion    = "7.4"
+	azureTokenURL      = "http://169.254.169.254/metadata/identity/oauth2/token"
+	azureVaultResource = "https://vault.azure.net"
+)
+
+// KeyProvider wraps and unwraps the data key used for field level encryption with a master key it manages
+type KeyProvider interface {
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error)
+}
+
+// NewKeyProvider creates the KeyProvider with the given name
+func NewKeyProvider(name, localMasterKey, azureKeyURL string) (KeyProvider, error) {
+	switch name {
+	case "local":
+		masterKey, err := base64.StdEncoding.DecodeString(localMasterKey)
+		if err != nil {
+			return nil, fmt.Errorf("local master key not valid: %w", err)
+		}
+		return NewLocalKeyProvider(masterKey)
+	case "azure":
+		return NewAzureKeyProvider(azureKeyURL, azureTokenURL, http.DefaultClient), nil
+	default:
+		return nil, fmt.Errorf("kms provider %s not valid", name)
+	}
+}
+
+// LocalKeyProvider wraps data keys with a master key held in the configuration, intended for local development
+type LocalKeyProvider struct {
+	aead cipher.AEAD
+}
+
+// NewLocalKeyProvider creates a KeyProvider from a 32 bytes master key
+func NewLocalKeyProvider(masterKey []byte) (*LocalKeyProvider, error) {
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("local master key not valid: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalKeyProvider{aead: aead}, nil
+}
+
+// WrapKey encrypts a data key with the master key
+func (p *LocalKeyProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return p.aead.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+// UnwrapKey decrypts a data key with the master key
+func (p *LocalKeyProvider) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	if len(wrappedKey) < p.aead.NonceSize() {
+		return nil, fmt.Errorf("wrapped key not valid: too short")
+	}
+
+	nonce, ciphertext := wrappedKey[:p.aead.NonceSize()], wrappedKey[p.aead.NonceSize():]
+	return p.aead.Open(nil, nonce, ciphertext, nil)
+}
+
+// azureKeyProvider unwraps data keys with an Azure Key Vault key, authenticating with the managed identity of the host
+type azureKeyProvider struct {
+	keyURL   string
+	tokenURL string
+	client   *http.Client
+}
+
+// NewAzureKeyProvider creates a KeyProvider for the given Azure Key Vault key URL
+func NewAzureKeyProvider(keyURL, tokenURL string, client *http.Client) KeyProvider {
+	return &azureKeyProvider{
+		keyURL:   strings.TrimSuffix(keyURL, "/"),
+		tokenURL: tokenURL,
+		client:   client,
+	}
+}
+
+// WrapKey encrypts a data key calling the wrapkey operation of Azure Key Vault
+func (p *azureKeyProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	key, err := p.keyOperation(ctx, "wrapkey", dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot wrap key: %w", err)
+	}
+	return key, nil
+}
+
+// UnwrapKey decrypts a data key calling the unwrapkey operation of Azure Key Vault
+func (p *azureKeyProvider) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	key, err := p.keyOperation(ctx, "unwrapkey", wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unwrap key: %w", err)
+	}
+	return key, nil
+}
+
+// keyOperation calls the given key operation of Azure Key Vault with the given key, returning the resulting one
+func (p *azureKeyProvider) keyOperation(ctx context.Context, operation string, key []byte) ([]byte, error) {
+	token, err := p.token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get azure access token: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"alg":   "RSA-OAEP-256",
+		"value": base64.RawURLEncoding.EncodeToString(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s?api-version=%s", p.keyURL, operation, azureAPIVersion), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var result struct {
+		Value string `json:"value"`
+	}
+	if err := p.do(req, &result); err != nil {
+		return nil, err
+	}
+
+	return base64.RawURLEncoding.DecodeString(result.Value)
+}
+
+func (p *azureKeyProvider) token(ctx context.Context) (string, error) {
+	query := url.Values{}
+	query.Set("api-version", "2018-02-01")
+	query.Set("resource", azureVaultResource)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.tokenURL+"?"+query.Encode(), http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata", "true")
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := p.do(req, &result); err != nil {
+		return "", err
+	}
+	return result.AccessToken, nil
+}
+
+func (p *azureKeyProvider) do(req *http.Request, target interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, target)
+}
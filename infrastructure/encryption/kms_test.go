@@ -0,0 +1,121 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewKeyProvider_InvalidProvider checks that NewKeyProvider returns an error when the received provider is not valid
+func TestNewKeyProvider_InvalidProvider(t *testing.T) {
+	// Arrange
+	expectedError := "kms provider invalid not valid"
+
+	// Act
+	_, err := NewKeyProvider("invalid", "", "")
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestLocalKeyProvider_WrapUnwrap checks that a data key wrapped by the local provider is unwrapped back to the original key
+func TestLocalKeyProvider_WrapUnwrap(t *testing.T) {
+	// Arrange
+	provider, err := NewKeyProvider("local", base64.StdEncoding.EncodeToString(testDataKey), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataKey := []byte("fedcba9876543210fedcba9876543210")
+
+	// Act
+	wrapped, err := provider.WrapKey(context.Background(), dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unwrapped, err := provider.UnwrapKey(context.Background(), wrapped)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+}
+
+// TestAzureKeyProvider_UnwrapKey checks that the Azure provider authenticates and returns the key unwrapped by Key Vault
+func TestAzureKeyProvider_UnwrapKey(t *testing.T) {
+	// Arrange
+	expectedKey := []byte("unwrapped-key")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			json.NewEncoder(w).Encode(map[string]string{"access_token": "test-token"})
+		case "/keys/test/unwrapkey":
+			if r.Header.Get("Authorization") != "Bearer test-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"value": base64.RawURLEncoding.EncodeToString(expectedKey)})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewAzureKeyProvider(server.URL+"/keys/test", server.URL+"/token", server.Client())
+
+	// Act
+	key, err := provider.UnwrapKey(context.Background(), []byte("wrapped-key"))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedKey, key)
+}
+
+// TestAzureKeyProvider_WrapKey checks that the Azure provider sends the data key to Key Vault and returns it wrapped
+func TestAzureKeyProvider_WrapKey(t *testing.T) {
+	// Arrange
+	expectedKey := []byte("wrapped-key")
+	var sent map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			json.NewEncoder(w).Encode(map[string]string{"access_token": "test-token"})
+		case "/keys/test/wrapkey":
+			json.NewDecoder(r.Body).Decode(&sent)
+			json.NewEncoder(w).Encode(map[string]string{"value": base64.RawURLEncoding.EncodeToString(expectedKey)})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewAzureKeyProvider(server.URL+"/keys/test", server.URL+"/token", server.Client())
+
+	// Act
+	key, err := provider.WrapKey(context.Background(), []byte("data-key"))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedKey, key)
+	assert.Equal(t, map[string]string{"alg": "RSA-OAEP-256", "value": base64.RawURLEncoding.EncodeToString([]byte("data-key"))}, sent)
+}
+
+// TestAzureKeyProvider_TokenError checks that the Azure provider returns an error when the access token cannot be obtained
+func TestAzureKeyProvider_TokenError(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	provider := NewAzureKeyProvider(server.URL+"/keys/test", server.URL+"/token", server.Client())
+
+	// Act
+	_, err := provider.UnwrapKey(context.Background(), []byte("wrapped-key"))
+
+	// Assert
+	assert.NotEmpty(t, err)
+}
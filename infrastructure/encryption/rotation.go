@@ -0,0 +1,34 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// RotateUsers re-encrypts the given fields of every user of the repository, the one of the database, from the current data key to the new one,
+// replacing all the users in a single transaction, so they are never left encrypted with different keys.
+// It returns the number of users re-encrypted.
+func RotateUsers(ctx context.Context, repo ports.UserRepository, current, new *FieldCipher, fields []string) (int, error) {
+	from := NewUserRepository(repo, current, fields).(*userRepository)
+	to := NewUserRepository(repo, new, fields).(*userRepository)
+
+	var users []entities.User
+	err := repo.Dump(ctx, func(user entities.User) error {
+		if err := from.decrypt(&user); err != nil {
+			return fmt.Errorf("user %s cannot be decrypted with the current data key: %w", user.ID, err)
+		}
+		users = append(users, to.encrypt(user).(entities.User))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := repo.Restore(ctx, users); err != nil {
+		return 0, err
+	}
+	return len(users), nil
+}
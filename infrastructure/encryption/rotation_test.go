@@ -0,0 +1,85 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testCiphers returns the ciphers of the current and the new data keys
+func testCiphers(t *testing.T) (*FieldCipher, *FieldCipher) {
+	t.Helper()
+
+	current, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := NewFieldCipher([]byte("fedcba9876543210fedcba9876543210"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return current, new
+}
+
+// TestRotateUsers_Ok checks that RotateUsers replaces every user with its fields encrypted with the new data key
+func TestRotateUsers_Ok(t *testing.T) {
+	// Arrange
+	current, new := testCiphers(t)
+	stored := entities.User{ID: "test-id", Name: "test", Email: current.Encrypt("test@test.com")}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Dump), context.Background(), mock.Anything).Return(func(ctx context.Context, fn func(user entities.User) error) error {
+		return fn(stored)
+	}).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Restore), context.Background(), []entities.User{
+		{ID: "test-id", Name: "test", Email: new.Encrypt("test@test.com")},
+	}).Return(nil).Once()
+
+	// Act
+	count, err := RotateUsers(context.Background(), userRepositoryMock, current, new, []string{"email"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+}
+
+// TestRotateUsers_NotCurrentKey checks that RotateUsers returns an error without replacing any user when one is not encrypted with the current data key
+func TestRotateUsers_NotCurrentKey(t *testing.T) {
+	// Arrange
+	current, new := testCiphers(t)
+	stored := entities.User{ID: "test-id", Email: new.Encrypt("test@test.com")}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Dump), context.Background(), mock.Anything).Return(func(ctx context.Context, fn func(user entities.User) error) error {
+		return fn(stored)
+	}).Once()
+
+	// Act
+	_, err := RotateUsers(context.Background(), userRepositoryMock, current, new, []string{"email"})
+
+	// Assert
+	assert.ErrorContains(t, err, "user test-id cannot be decrypted with the current data key")
+}
+
+// TestRotateUsers_RestoreError checks that RotateUsers returns the error of the repository when the users cannot be replaced
+func TestRotateUsers_RestoreError(t *testing.T) {
+	// Arrange
+	current, new := testCiphers(t)
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Dump), context.Background(), mock.Anything).Return(nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Restore), context.Background(), []entities.User(nil)).Return(errors.New("repository-error")).Once()
+
+	// Act
+	_, err := RotateUsers(context.Background(), userRepositoryMock, current, new, []string{"email"})
+
+	// Assert
+	assert.Equal(t, "repository-error", err.Error())
+}
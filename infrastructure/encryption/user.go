@@ -0,0 +1,188 @@
+package encryption
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// userRepository decorator of an user repository that encrypts the configured fields before storing them
+// and decrypts them after reading them, so the wrapped adapter only ever sees ciphertext.
+// Restore is not decorated, so the backups, dumped from the wrapped adapter, keep the fields encrypted.
+type userRepository struct {
+	ports.UserRepository
+	cipher *FieldCipher
+	fields map[string]bool
+}
+
+// NewUserRepository wraps a user repository encrypting the given fields, identified by their bson name
+func NewUserRepository(repo ports.UserRepository, cipher *FieldCipher, fields []string) ports.UserRepository {
+	r := &userRepository{
+		UserRepository: repo,
+		cipher:         cipher,
+		fields:         make(map[string]bool),
+	}
+	for _, f := range fields {
+		r.fields[f] = true
+	}
+	return r
+}
+
+func (r *userRepository) Create(ctx context.Context, user interface{}) (string, error) {
+	return r.UserRepository.Create(ctx, r.encrypt(user))
+}
+
+func (r *userRepository) CreateMany(ctx context.Context, users []interface{}) ([]string, error) {
+	encrypted := make([]interface{}, len(users))
+	for i, u := range users {
+		encrypted[i] = r.encrypt(u)
+	}
+	return r.UserRepository.CreateMany(ctx, encrypted)
+}
+
+func (r *userRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	result, err := r.UserRepository.Get(ctx, r.encryptFilter(filter), skip, take)
+	if err != nil {
+		return nil, err
+	}
+	return r.decryptAll(result)
+}
+
+func (r *userRepository) GetProjected(ctx context.Context, filter map[string]interface{}, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	result, err := r.UserRepository.GetProjected(ctx, r.encryptFilter(filter), projection, skip, take)
+	if err != nil {
+		return nil, err
+	}
+	return r.decryptAll(result)
+}
+
+func (r *userRepository) Search(ctx context.Context, text string, autocomplete bool, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	result, err := r.UserRepository.Search(ctx, text, autocomplete, projection, skip, take)
+	if err != nil {
+		return nil, err
+	}
+	return r.decryptAll(result)
+}
+
+func (r *userRepository) GetNearby(ctx context.Context, longitude, latitude, radius float64, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	result, err := r.UserRepository.GetNearby(ctx, longitude, latitude, radius, projection, skip, take)
+	if err != nil {
+		return nil, err
+	}
+	return r.decryptAll(result)
+}
+
+func (r *userRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	result, err := r.UserRepository.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	return result, r.decrypt(result)
+}
+
+func (r *userRepository) GetByIDProjected(ctx context.Context, ID string, projection map[string]interface{}) (interface{}, error) {
+	result, err := r.UserRepository.GetByIDProjected(ctx, ID, projection)
+	if err != nil {
+		return nil, err
+	}
+	return result, r.decrypt(result)
+}
+
+func (r *userRepository) Update(ctx context.Context, ID string, user interface{}) error {
+	return r.UserRepository.Update(ctx, ID, r.encrypt(user))
+}
+
+func (r *userRepository) UpdateNames(ctx context.Context, ID, name, surnames string, at time.Time) error {
+	return r.UserRepository.UpdateNames(ctx, ID, r.encryptField("name", name), r.encryptField("surnames", surnames), at)
+}
+
+func (r *userRepository) Upsert(ctx context.Context, filter map[string]interface{}, user interface{}) (string, error) {
+	return r.UserRepository.Upsert(ctx, r.encryptFilter(filter), r.encrypt(user))
+}
+
+// Dump decrypts the users before passing them to fn, like the other reads
+func (r *userRepository) Dump(ctx context.Context, fn func(user entities.User) error) error {
+	return r.UserRepository.Dump(ctx, func(user entities.User) error {
+		if err := r.decrypt(&user); err != nil {
+			return err
+		}
+		return fn(user)
+	})
+}
+
+// encrypt returns a copy of the given user with the configured fields encrypted
+func (r *userRepository) encrypt(user interface{}) interface{} {
+	u, ok := user.(entities.User)
+	if !ok {
+		return user
+	}
+
+	v := reflect.ValueOf(&u).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); r.encrypted(v.Type().Field(i)) && f.Kind() == reflect.String {
+			f.SetString(r.cipher.Encrypt(f.String()))
+		}
+	}
+	return u
+}
+
+// decrypt decrypts in place the configured fields of the given user
+func (r *userRepository) decrypt(user interface{}) error {
+	u, ok := user.(*entities.User)
+	if !ok {
+		return nil
+	}
+
+	v := reflect.ValueOf(u).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); r.encrypted(v.Type().Field(i)) && f.Kind() == reflect.String {
+			plaintext, err := r.cipher.Decrypt(f.String())
+			if err != nil {
+				return err
+			}
+			f.SetString(plaintext)
+		}
+	}
+	return nil
+}
+
+func (r *userRepository) decryptAll(users []interface{}) ([]interface{}, error) {
+	for _, u := range users {
+		if err := r.decrypt(u); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+// encryptFilter returns a copy of the given filter with the values of the configured fields encrypted
+func (r *userRepository) encryptFilter(filter map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(filter))
+	for k, v := range filter {
+		if s, ok := v.(string); ok && r.fields[k] {
+			v = r.cipher.Encrypt(s)
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// encryptField returns the value of the given field encrypted when the field is configured
+func (r *userRepository) encryptField(field, value string) string {
+	if !r.fields[field] {
+		return value
+	}
+	return r.cipher.Encrypt(value)
+}
+
+func (r *userRepository) encrypted(field reflect.StructField) bool {
+	return r.fields[bsonName(field)]
+}
+
+func bsonName(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("bson"), ",")[0]
+}
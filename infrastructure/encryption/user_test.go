@@ -0,0 +1,192 @@
+package encryption
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/core/services"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreate_EncryptsFields checks that Create stores the configured fields encrypted and leaves the rest untouched
+func TestCreate_EncryptsFields(t *testing.T) {
+	// Arrange
+	cipher, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := entities.User{Name: "test", Email: "test@test.com"}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), context.Background(), entities.User{Name: user.Name, Email: cipher.Encrypt(user.Email)}).Return("test-id", nil).Once()
+
+	repo := NewUserRepository(userRepositoryMock, cipher, []string{"email"})
+
+	// Act
+	id, err := repo.Create(context.Background(), user)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", id)
+}
+
+// TestUpdateNames_EncryptsFields checks that UpdateNames encrypts the name and the surnames when configured
+func TestUpdateNames_EncryptsFields(t *testing.T) {
+	// Arrange
+	cipher, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateNames), context.Background(), "test-id", "test", cipher.Encrypt("surnames"), now).Return(nil).Once()
+
+	repo := NewUserRepository(userRepositoryMock, cipher, []string{"surnames"})
+
+	// Act
+	err = repo.UpdateNames(context.Background(), "test-id", "test", "surnames", now)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestGetProjected_EncryptsFilterAndDecryptsResult checks that GetProjected filters by the encrypted values and returns the users decrypted
+func TestGetProjected_EncryptsFilterAndDecryptsResult(t *testing.T) {
+	// Arrange
+	cipher, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := "test@test.com"
+	stored := []interface{}{&entities.User{Email: cipher.Encrypt(email)}}
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetProjected), context.Background(), map[string]interface{}{"email": cipher.Encrypt(email)}, map[string]interface{}(nil), nilPointer, nilPointer).Return(stored, nil).Once()
+
+	repo := NewUserRepository(userRepositoryMock, cipher, []string{"email"})
+
+	// Act
+	result, err := repo.GetProjected(context.Background(), map[string]interface{}{"email": email}, nil, nil, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, email, result[0].(*entities.User).Email)
+}
+
+// TestSearch_DecryptsResult checks that Search returns the found users decrypted
+func TestSearch_DecryptsResult(t *testing.T) {
+	// Arrange
+	cipher, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := "test@test.com"
+	stored := []interface{}{&entities.User{Name: "test", Email: cipher.Encrypt(email)}}
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Search), context.Background(), "test", false, map[string]interface{}(nil), nilPointer, nilPointer).Return(stored, nil).Once()
+
+	repo := NewUserRepository(userRepositoryMock, cipher, []string{"email"})
+
+	// Act
+	result, err := repo.Search(context.Background(), "test", false, nil, nil, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, email, result[0].(*entities.User).Email)
+}
+
+// TestGetByIDProjected_DecryptError checks that GetByIDProjected returns an error when a stored value cannot be decrypted
+func TestGetByIDProjected_DecryptError(t *testing.T) {
+	// Arrange
+	cipher, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByIDProjected), context.Background(), "test-id", mock.Anything).Return(&entities.User{Email: prefix + "corrupted"}, nil).Once()
+
+	repo := NewUserRepository(userRepositoryMock, cipher, []string{"email"})
+
+	// Act
+	_, err = repo.GetByIDProjected(context.Background(), "test-id", nil)
+
+	// Assert
+	assert.NotEmpty(t, err)
+}
+
+// TestDump_DecryptsUsers checks that Dump passes the users decrypted
+func TestDump_DecryptsUsers(t *testing.T) {
+	// Arrange
+	cipher, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := "test@test.com"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Dump), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(func(entities.User) error)(entities.User{ID: "test-id", Email: cipher.Encrypt(email)})
+	}).Return(nil).Once()
+
+	repo := NewUserRepository(userRepositoryMock, cipher, []string{"email"})
+
+	// Act
+	var dumped []entities.User
+	err = repo.Dump(context.Background(), func(user entities.User) error {
+		dumped = append(dumped, user)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []entities.User{{ID: "test-id", Email: email}}, dumped)
+}
+
+// TestDump_DirectorySync checks that the directory sync matches the local users stored with the email encrypted,
+// leaving them unchanged instead of deactivating them and creating them again
+func TestDump_DirectorySync(t *testing.T) {
+	// Arrange
+	cipher, err := NewFieldCipher(testDataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sourceMock := mocks.NewDirectorySource(t)
+	sourceMock.On(testutils.FunctionName(t, ports.DirectorySource.Users), mock.Anything).Return([]ports.DirectoryEntry{{Email: "same@example.com", Name: "Same"}}, nil).Once()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Dump), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(func(entities.User) error)(entities.User{ID: "same-id", Email: cipher.Encrypt("same@example.com"), Name: "Same"})
+	}).Return(nil).Once()
+	fileStorageMock := mocks.NewFileStorage(t)
+	fileStorageMock.On(testutils.FunctionName(t, ports.FileStorage.Store), mock.Anything, mock.Anything, mock.Anything).Return(entities.File{}, nil).Once()
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Write), mock.Anything, mock.Anything).Return(nil).Once()
+
+	cfg := config.Config{}
+	cfg.DirectorySync.Domains = []string{"example.com"}
+	repo := NewUserRepository(userRepositoryMock, cipher, []string{"email"})
+	service := services.NewDirectorySyncService(cfg, zerolog.Nop(), sourceMock, repo, fileStorageMock, mocks.NewJobRepository(t), auditRepositoryMock, nil)
+	job := &entities.Job{ID: "job-id", Type: entities.JobTypeDirectorySync, Metadata: map[string]string{"dry_run": "false"}}
+
+	// Act
+	err = service.Process(context.Background(), job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "1", job.Metadata["unchanged"])
+	assert.Equal(t, "0", job.Metadata["created"])
+	assert.Equal(t, "0", job.Metadata["deactivated"])
+}
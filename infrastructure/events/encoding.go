@@ -0,0 +1,129 @@
+package events
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// SchemaVersion is the version of the schema of the events, increased on every change not compatible with the previous one
+const SchemaVersion = 1
+
+// content types of the encoded events
+const (
+	contentTypeJSON = "application/json"
+	contentTypeAvro = "avro/binary"
+)
+
+// UserEventSchema is the Avro schema of the events, in its parsing canonical form so its fingerprint is the one of the schema
+//
+//go:embed user_event.avsc
+var UserEventSchema string
+
+// schemaFingerprint is the CRC-64-AVRO fingerprint of the schema, heading the events encoded with Avro
+var schemaFingerprint = fingerprint(strings.TrimSpace(UserEventSchema))
+
+// encoder encodes an event, returning its content type
+type encoder func(event ports.Event) ([]byte, string, error)
+
+// newEncoder returns the encoder of the given format, json or avro
+func newEncoder(format string) (encoder, error) {
+	switch format {
+	case "json":
+		return encodeJSON, nil
+	case "avro":
+		return encodeAvro, nil
+	default:
+		return nil, fmt.Errorf("events format %s not valid", format)
+	}
+}
+
+// jsonEvent is the JSON encoding of an event, versioned by its schema version
+type jsonEvent struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	Version    int               `json:"version"`
+	UserID     string            `json:"user_id"`
+	OccurredAt time.Time         `json:"occurred_at"`
+	Data       map[string]string `json:"data"`
+}
+
+// encodeJSON encodes the event as a JSON object
+func encodeJSON(event ports.Event) ([]byte, string, error) {
+	data := event.Data
+	if data == nil {
+		data = map[string]string{}
+	}
+	value, err := json.Marshal(jsonEvent{
+		ID:         event.ID,
+		Type:       event.Type,
+		Version:    SchemaVersion,
+		UserID:     event.UserID,
+		OccurredAt: event.OccurredAt.UTC(),
+		Data:       data,
+	})
+	return value, contentTypeJSON, err
+}
+
+// encodeAvro encodes the event with the single object encoding of Avro: its binary encoding headed by the marker C3 01
+// and the fingerprint of the schema, so a consumer knowing the schema can decode it without a schema registry.
+// OccurredAt is encoded as the milliseconds since the Unix epoch.
+func encodeAvro(event ports.Event) ([]byte, string, error) {
+	value := []byte{0xc3, 0x01}
+	value = binary.LittleEndian.AppendUint64(value, schemaFingerprint)
+	value = appendAvroString(value, event.ID)
+	value = appendAvroString(value, event.Type)
+	value = appendAvroLong(value, SchemaVersion)
+	value = appendAvroString(value, event.UserID)
+	value = appendAvroLong(value, event.OccurredAt.UnixMilli())
+
+	keys := make([]string, 0, len(event.Data))
+	for key := range event.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		value = appendAvroLong(value, int64(len(keys)))
+		for _, key := range keys {
+			value = appendAvroString(value, key)
+			value = appendAvroString(value, event.Data[key])
+		}
+	}
+	value = appendAvroLong(value, 0)
+	return value, contentTypeAvro, nil
+}
+
+// appendAvroLong appends the zigzag varint encoding of an Avro int or long
+func appendAvroLong(b []byte, v int64) []byte {
+	return binary.AppendVarint(b, v)
+}
+
+// appendAvroString appends an Avro string, its length followed by its UTF-8 bytes
+func appendAvroString(b []byte, s string) []byte {
+	return append(appendAvroLong(b, int64(len(s))), s...)
+}
+
+// fingerprint returns the CRC-64-AVRO fingerprint of a schema in its parsing canonical form
+func fingerprint(schema string) uint64 {
+	const empty = 0xc15d213aa4d7a795
+	var table [256]uint64
+	for i := range table {
+		fp := uint64(i)
+		for j := 0; j < 8; j++ {
+			fp = (fp >> 1) ^ (empty & -(fp & 1))
+		}
+		table[i] = fp
+	}
+
+	fp := uint64(empty)
+	for i := 0; i < len(schema); i++ {
+		fp = (fp >> 8) ^ table[byte(fp)^schema[i]]
+	}
+	return fp
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFingerprint_Ok checks that fingerprint returns the CRC-64-AVRO fingerprint of the Avro specification test vectors
+func TestFingerprint_Ok(t *testing.T) {
+	// Act
+	null := fingerprint(`"null"`)
+	str := fingerprint(`"string"`)
+
+	// Assert
+	assert.Equal(t, uint64(7195948357588979594), null)
+	assert.Equal(t, uint64(0x8f014872634503c7), str)
+}
+
+// TestEncodeJSON_Ok checks that encodeJSON encodes the event as a JSON object with the schema version
+func TestEncodeJSON_Ok(t *testing.T) {
+	// Arrange
+	event := ports.Event{ID: "1", Type: ports.EventUserCreated, UserID: "u1", OccurredAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	// Act
+	value, contentType, err := encodeJSON(event)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, contentTypeJSON, contentType)
+	assert.JSONEq(t, `{"id":"1","type":"user.created","version":1,"user_id":"u1","occurred_at":"2026-01-02T03:04:05Z","data":{}}`, string(value))
+}
+
+// TestEncodeAvro_Ok checks that encodeAvro encodes the event with the single object encoding, headed by the fingerprint of the schema
+func TestEncodeAvro_Ok(t *testing.T) {
+	// Arrange
+	event := ports.Event{ID: "1", Type: "t", UserID: "u", OccurredAt: time.UnixMilli(1), Data: map[string]string{"b": "2", "a": "1"}}
+	expected := []byte{0xc3, 0x01}
+	expected = binary.LittleEndian.AppendUint64(expected, schemaFingerprint)
+	expected = append(expected,
+		2, '1', // id
+		2, 't', // type
+		2,      // version
+		2, 'u', // user_id
+		2,                                    // occurred_at
+		4, 2, 'a', 2, '1', 2, 'b', 2, '2', 0, // data
+	)
+
+	// Act
+	value, contentType, err := encodeAvro(event)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, contentTypeAvro, contentType)
+	assert.Equal(t, expected, value)
+}
+
+// TestUserEventSchema_Ok checks that the schema is valid JSON naming the fields of the events in the order they are encoded with Avro
+func TestUserEventSchema_Ok(t *testing.T) {
+	// Arrange
+	var schema struct {
+		Fields []struct {
+			Name string `json:"name"`
+		} `json:"fields"`
+	}
+
+	// Act
+	err := json.Unmarshal([]byte(UserEventSchema), &schema)
+
+	// Assert
+	assert.Nil(t, err)
+	var names []string
+	for _, field := range schema.Fields {
+		names = append(names, field.Name)
+	}
+	assert.Equal(t, []string{"id", "type", "version", "user_id", "occurred_at", "data"}, names)
+}
+
+// TestNewEncoder_NotValid checks that newEncoder returns an error when the format is not known
+func TestNewEncoder_NotValid(t *testing.T) {
+	// Act
+	_, err := newEncoder("protobuf")
+
+	// Assert
+	assert.EqualError(t, err, "events format protobuf not valid")
+}
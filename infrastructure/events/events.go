@@ -0,0 +1,100 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// defaultTimeout is the time given to a request to a broker when its context has no deadline
+const defaultTimeout = 30 * time.Second
+
+// Message a message sent to a broker, identified by its ID so the brokers supporting it discard the ones sent more than once
+type Message struct {
+	ID      string
+	Topic   string
+	Key     string
+	Value   []byte
+	Headers map[string]string
+}
+
+// Broker interface of a message broker the encoded events are sent to
+type Broker interface {
+	Send(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// NewBroker creates the Broker of the given kind, kafka, nats or rabbitmq, connecting to the given Kafka brokers, NATS server or RabbitMQ server
+// and identifying itself with the client ID
+func NewBroker(kind string, kafkaBrokers []string, clientID string, kafkaTLS bool, natsURL, rabbitMQURL, rabbitMQExchange string) (Broker, error) {
+	switch kind {
+	case "kafka":
+		return NewKafkaBroker(kafkaBrokers, clientID, kafkaTLS), nil
+	case "nats":
+		return NewNATSBroker(natsURL, clientID)
+	case "rabbitmq":
+		return NewRabbitMQBroker(rabbitMQURL, rabbitMQExchange, clientID)
+	default:
+		return nil, fmt.Errorf("events broker %s not valid", kind)
+	}
+}
+
+// setDeadline applies the deadline of the context to the connection, or the default one when it has none
+func setDeadline(ctx context.Context, conn net.Conn) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	return conn.SetDeadline(deadline)
+}
+
+// publisher adapter of an event publisher encoding the events and sending them to the topic of their type, keyed by their user ID
+type publisher struct {
+	broker Broker
+	encode encoder
+	topic  string
+	topics map[string]string
+}
+
+// NewPublisher creates an event publisher sending the events encoded in the given format, json or avro, to the given broker.
+// The events are sent to the topic of their type in topics, or to the default topic when their type has none.
+func NewPublisher(broker Broker, format, topic string, topics map[string]string) (ports.EventPublisher, error) {
+	encode, err := newEncoder(format)
+	if err != nil {
+		return nil, err
+	}
+	return &publisher{
+		broker: broker,
+		encode: encode,
+		topic:  topic,
+		topics: topics,
+	}, nil
+}
+
+// Publish encodes the event and sends it, naming its content type, type and schema version in the headers of the message
+func (p *publisher) Publish(ctx context.Context, event ports.Event) error {
+	value, contentType, err := p.encode(event)
+	if err != nil {
+		return err
+	}
+
+	topic, ok := p.topics[event.Type]
+	if !ok {
+		topic = p.topic
+	}
+	return p.broker.Send(ctx, Message{
+		ID:    event.ID,
+		Topic: topic,
+		Key:   event.UserID,
+		Value: value,
+		Headers: map[string]string{
+			"content-type":   contentType,
+			"event-type":     event.Type,
+			"schema-version": strconv.Itoa(SchemaVersion),
+		},
+	})
+}
@@ -0,0 +1,457 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// API keys and versions of the Kafka requests sent by the producer, the lowest versions still supported by Kafka 4
+const (
+	kafkaProduce         = 0
+	kafkaMetadata        = 3
+	kafkaProduceVersion  = 3
+	kafkaMetadataVersion = 4
+)
+
+// kafkaMaxResponseSize is the size of the largest response read from a broker
+const kafkaMaxResponseSize = 64 << 20
+
+// castagnoli is the table of the CRC-32C checksums of the record batches
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// kafkaBroker adapter of a broker producing the messages to a Kafka cluster with its wire protocol, one at a time.
+// A message is produced to the partition of its key, as the default partitioner of the Java client does, so the messages of a user are kept in order,
+// and acknowledged by every in-sync replica. The leaders of the partitions are looked up from the bootstrap brokers and looked up again after any failure.
+type kafkaBroker struct {
+	bootstrap     []string
+	clientID      string
+	tls           bool
+	dialer        net.Dialer
+	mu            sync.Mutex
+	correlationID int32
+	brokers       map[int32]string
+	leaders       map[string][]int32
+	conns         map[string]net.Conn
+}
+
+// NewKafkaBroker creates a broker producing the messages to the Kafka cluster of the given bootstrap brokers, like localhost:9092,
+// identifying itself with the client ID and connecting with TLS when set
+func NewKafkaBroker(bootstrap []string, clientID string, useTLS bool) Broker {
+	return &kafkaBroker{
+		bootstrap: bootstrap,
+		clientID:  clientID,
+		tls:       useTLS,
+		dialer:    net.Dialer{Timeout: 10 * time.Second},
+		brokers:   map[int32]string{},
+		leaders:   map[string][]int32{},
+		conns:     map[string]net.Conn{},
+	}
+}
+
+// Send produces the message to the partition of its key of its topic
+func (k *kafkaBroker) Send(ctx context.Context, msg Message) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	leaders, err := k.partitions(ctx, msg.Topic)
+	if err != nil {
+		return err
+	}
+	partition := partitionOf([]byte(msg.Key), len(leaders))
+	address, ok := k.brokers[leaders[partition]]
+	if !ok {
+		delete(k.leaders, msg.Topic)
+		return fmt.Errorf("kafka leader %d of %s-%d not known", leaders[partition], msg.Topic, partition)
+	}
+
+	if err := k.produce(ctx, address, msg, int32(partition)); err != nil {
+		delete(k.leaders, msg.Topic)
+		return err
+	}
+	return nil
+}
+
+// Close closes the connections to the brokers
+func (k *kafkaBroker) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	var errs []error
+	for address, conn := range k.conns {
+		errs = append(errs, conn.Close())
+		delete(k.conns, address)
+	}
+	return errors.Join(errs...)
+}
+
+// partitions returns the leader of every partition of the topic, looking them up when not known
+func (k *kafkaBroker) partitions(ctx context.Context, topic string) ([]int32, error) {
+	if leaders, ok := k.leaders[topic]; ok {
+		return leaders, nil
+	}
+
+	var req kafkaEncoder
+	req.int32(1)
+	req.string(topic)
+	req.int8(0) // allow_auto_topic_creation
+
+	var errs []error
+	for _, address := range k.bootstrap {
+		resp, err := k.roundTrip(ctx, address, kafkaMetadata, kafkaMetadataVersion, req.Bytes())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return k.readMetadata(resp, topic)
+	}
+	return nil, fmt.Errorf("kafka metadata of %s cannot be read: %w", topic, errors.Join(errs...))
+}
+
+// readMetadata reads the brokers and the leaders of the partitions of the topic from a metadata response
+func (k *kafkaBroker) readMetadata(resp []byte, topic string) ([]int32, error) {
+	d := kafkaDecoder{r: bytes.NewReader(resp)}
+	d.int32() // throttle_time_ms
+	for i := d.int32(); i > 0 && d.err == nil; i-- {
+		nodeID, host, port := d.int32(), d.string(), d.int32()
+		d.string() // rack
+		k.brokers[nodeID] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.string() // cluster_id
+	d.int32()  // controller_id
+
+	var leaders []int32
+	var topicErr int16
+	for i := d.int32(); i > 0 && d.err == nil; i-- {
+		errorCode, name := d.int16(), d.string()
+		d.int8() // is_internal
+		partitions := make([]int32, d.int32())
+		for range partitions {
+			d.int16() // error_code
+			index, leader := d.int32(), d.int32()
+			d.int32s() // replica_nodes
+			d.int32s() // isr_nodes
+			if index >= 0 && int(index) < len(partitions) {
+				partitions[index] = leader
+			}
+			if leader < 0 && errorCode == 0 {
+				errorCode = 5 // LEADER_NOT_AVAILABLE
+			}
+		}
+		if name == topic {
+			leaders, topicErr = partitions, errorCode
+		}
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("kafka metadata not valid: %w", d.err)
+	}
+	if topicErr != 0 {
+		return nil, fmt.Errorf("kafka metadata of %s failed with error code %d", topic, topicErr)
+	}
+	if len(leaders) == 0 {
+		return nil, fmt.Errorf("kafka topic %s has no partitions", topic)
+	}
+	k.leaders[topic] = leaders
+	return leaders, nil
+}
+
+// produce produces the message to the partition through its leader, waiting for every in-sync replica to acknowledge it
+func (k *kafkaBroker) produce(ctx context.Context, address string, msg Message, partition int32) error {
+	timeout := defaultTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	batch := recordBatch(msg, time.Now())
+
+	var req kafkaEncoder
+	req.int16(-1) // transactional_id
+	req.int16(-1) // acks, all
+	req.int32(int32(timeout / time.Millisecond))
+	req.int32(1)
+	req.string(msg.Topic)
+	req.int32(1)
+	req.int32(partition)
+	req.int32(int32(len(batch)))
+	req.Write(batch)
+
+	resp, err := k.roundTrip(ctx, address, kafkaProduce, kafkaProduceVersion, req.Bytes())
+	if err != nil {
+		return err
+	}
+
+	d := kafkaDecoder{r: bytes.NewReader(resp)}
+	for i := d.int32(); i > 0 && d.err == nil; i-- {
+		d.string() // name
+		for j := d.int32(); j > 0 && d.err == nil; j-- {
+			index, errorCode := d.int32(), d.int16()
+			d.int64() // base_offset
+			d.int64() // log_append_time_ms
+			if errorCode != 0 && d.err == nil {
+				return fmt.Errorf("kafka produce to %s-%d failed with error code %d", msg.Topic, index, errorCode)
+			}
+		}
+	}
+	if d.err != nil {
+		return fmt.Errorf("kafka produce response not valid: %w", d.err)
+	}
+	return nil
+}
+
+// roundTrip sends the request to the broker at address and returns the body of its response,
+// closing the connection on any failure so it is opened again by the next request
+func (k *kafkaBroker) roundTrip(ctx context.Context, address string, apiKey, apiVersion int16, body []byte) ([]byte, error) {
+	conn, err := k.conn(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := k.exchange(ctx, conn, apiKey, apiVersion, body)
+	if err != nil {
+		conn.Close()
+		delete(k.conns, address)
+		return nil, fmt.Errorf("kafka broker %s: %w", address, err)
+	}
+	return resp, nil
+}
+
+// exchange writes the request with its header and reads the response with the same correlation ID
+func (k *kafkaBroker) exchange(ctx context.Context, conn net.Conn, apiKey, apiVersion int16, body []byte) ([]byte, error) {
+	if err := setDeadline(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	k.correlationID++
+	var req kafkaEncoder
+	req.int16(apiKey)
+	req.int16(apiVersion)
+	req.int32(k.correlationID)
+	req.string(k.clientID)
+	req.Write(body)
+
+	frame := binary.BigEndian.AppendUint32(nil, uint32(req.Len()))
+	if _, err := conn.Write(append(frame, req.Bytes()...)); err != nil {
+		return nil, err
+	}
+
+	var size int32
+	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if size < 4 || size > kafkaMaxResponseSize {
+		return nil, fmt.Errorf("response size %d not valid", size)
+	}
+	resp := make([]byte, size)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return nil, err
+	}
+	if correlationID := int32(binary.BigEndian.Uint32(resp)); correlationID != k.correlationID {
+		return nil, fmt.Errorf("response correlation ID %d not expected, %d was", correlationID, k.correlationID)
+	}
+	return resp[4:], nil
+}
+
+// conn returns the connection to the broker at address, opening it when not open
+func (k *kafkaBroker) conn(ctx context.Context, address string) (net.Conn, error) {
+	if conn, ok := k.conns[address]; ok {
+		return conn, nil
+	}
+
+	conn, err := k.dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if k.tls {
+		host, _, _ := net.SplitHostPort(address)
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+	k.conns[address] = conn
+	return conn, nil
+}
+
+// recordBatch returns the record batch, in the format of the magic 2, holding the message as its only record
+func recordBatch(msg Message, now time.Time) []byte {
+	var record []byte
+	record = append(record, 0)              // attributes
+	record = binary.AppendVarint(record, 0) // timestamp_delta
+	record = binary.AppendVarint(record, 0) // offset_delta
+	if msg.Key == "" {
+		record = binary.AppendVarint(record, -1)
+	} else {
+		record = appendVarintBytes(record, []byte(msg.Key))
+	}
+	record = appendVarintBytes(record, msg.Value)
+	names := make([]string, 0, len(msg.Headers))
+	for name := range msg.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	record = binary.AppendVarint(record, int64(len(names)))
+	for _, name := range names {
+		record = appendVarintBytes(record, []byte(name))
+		record = appendVarintBytes(record, []byte(msg.Headers[name]))
+	}
+
+	timestamp := now.UnixMilli()
+	var checked kafkaEncoder
+	checked.int16(0) // attributes
+	checked.int32(0) // last_offset_delta
+	checked.int64(timestamp)
+	checked.int64(timestamp)
+	checked.int64(-1) // producer_id
+	checked.int16(-1) // producer_epoch
+	checked.int32(-1) // base_sequence
+	checked.int32(1)
+	checked.Write(appendVarintBytes(nil, record))
+
+	var batch kafkaEncoder
+	batch.int64(0)                                // base_offset
+	batch.int32(int32(4 + 1 + 4 + checked.Len())) // batch_length
+	batch.int32(-1)                               // partition_leader_epoch
+	batch.int8(2)                                 // magic
+	batch.int32(int32(crc32.Checksum(checked.Bytes(), castagnoli)))
+	batch.Write(checked.Bytes())
+	return batch.Bytes()
+}
+
+// appendVarintBytes appends the bytes headed by their length as a zigzag varint
+func appendVarintBytes(b, v []byte) []byte {
+	return append(binary.AppendVarint(b, int64(len(v))), v...)
+}
+
+// partitionOf returns the partition of the key among the given count, the positive murmur2 hash of the key modulo the count
+func partitionOf(key []byte, count int) int {
+	return int(murmur2(key)&0x7fffffff) % count
+}
+
+// murmur2 returns the murmur2 hash of the data, as the Java client of Kafka computes it
+func murmur2(data []byte) int32 {
+	const (
+		seed uint32 = 0x9747b28c
+		m    uint32 = 0x5bd1e995
+		r           = 24
+	)
+	length := len(data)
+	h := seed ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
+
+// kafkaEncoder encodes the big endian fields of the Kafka requests
+type kafkaEncoder struct {
+	bytes.Buffer
+}
+
+func (e *kafkaEncoder) int8(v int8) {
+	e.WriteByte(byte(v))
+}
+
+func (e *kafkaEncoder) int16(v int16) {
+	binary.Write(e, binary.BigEndian, v)
+}
+
+func (e *kafkaEncoder) int32(v int32) {
+	binary.Write(e, binary.BigEndian, v)
+}
+
+func (e *kafkaEncoder) int64(v int64) {
+	binary.Write(e, binary.BigEndian, v)
+}
+
+func (e *kafkaEncoder) string(v string) {
+	e.int16(int16(len(v)))
+	e.WriteString(v)
+}
+
+// kafkaDecoder decodes the big endian fields of the Kafka responses, keeping the first error so it is checked once decoded
+type kafkaDecoder struct {
+	r   io.Reader
+	err error
+}
+
+func (d *kafkaDecoder) read(v interface{}) {
+	if d.err == nil {
+		d.err = binary.Read(d.r, binary.BigEndian, v)
+	}
+}
+
+func (d *kafkaDecoder) int8() (v int8) {
+	d.read(&v)
+	return
+}
+
+func (d *kafkaDecoder) int16() (v int16) {
+	d.read(&v)
+	return
+}
+
+func (d *kafkaDecoder) int32() (v int32) {
+	d.read(&v)
+	return
+}
+
+func (d *kafkaDecoder) int64() (v int64) {
+	d.read(&v)
+	return
+}
+
+// string decodes a string, empty when null
+func (d *kafkaDecoder) string() string {
+	n := d.int16()
+	if n <= 0 || d.err != nil {
+		return ""
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		d.err = err
+	}
+	return string(b)
+}
+
+// int32s decodes an array of int32
+func (d *kafkaDecoder) int32s() []int32 {
+	n := d.int32()
+	if n <= 0 || d.err != nil {
+		return nil
+	}
+	v := make([]int32, n)
+	d.read(v)
+	return v
+}
@@ -0,0 +1,215 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeKafka broker answering the metadata requests with a topic of the given partitions led by itself,
+// and the produce requests with the given error code, recording the partitions and the record batches produced to
+type fakeKafka struct {
+	listener   net.Listener
+	topic      string
+	partitions int
+	errorCode  int16
+	produced   chan fakeProduce
+}
+
+// fakeProduce a produce request received by the fake broker
+type fakeProduce struct {
+	clientID  string
+	partition int32
+	batch     []byte
+}
+
+func newFakeKafka(t *testing.T, topic string, partitions int, errorCode int16) *fakeKafka {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	f := &fakeKafka{listener: listener, topic: topic, partitions: partitions, errorCode: errorCode, produced: make(chan fakeProduce, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeKafka) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var size int32
+		if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+			return
+		}
+		req := make([]byte, size)
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+
+		d := kafkaDecoder{r: bytes.NewReader(req)}
+		apiKey, _, correlationID, clientID := d.int16(), d.int16(), d.int32(), d.string()
+
+		var resp kafkaEncoder
+		resp.int32(correlationID)
+		switch apiKey {
+		case kafkaMetadata:
+			host, port, _ := net.SplitHostPort(f.listener.Addr().String())
+			portNumber, _ := strconv.Atoi(port)
+			resp.int32(0) // throttle_time_ms
+			resp.int32(1)
+			resp.int32(1)
+			resp.string(host)
+			resp.int32(int32(portNumber))
+			resp.int16(-1) // rack
+			resp.int16(-1) // cluster_id
+			resp.int32(1)  // controller_id
+			resp.int32(1)
+			resp.int16(0)
+			resp.string(f.topic)
+			resp.int8(0)
+			resp.int32(int32(f.partitions))
+			for i := 0; i < f.partitions; i++ {
+				resp.int16(0)
+				resp.int32(int32(i))
+				resp.int32(1)
+				resp.int32(0) // replica_nodes
+				resp.int32(0) // isr_nodes
+			}
+		case kafkaProduce:
+			d.int16() // transactional_id
+			d.int16() // acks
+			d.int32() // timeout_ms
+			d.int32()
+			d.string()
+			d.int32()
+			partition := d.int32()
+			batch := make([]byte, d.int32())
+			d.read(batch)
+			f.produced <- fakeProduce{clientID: clientID, partition: partition, batch: batch}
+
+			resp.int32(1)
+			resp.string(f.topic)
+			resp.int32(1)
+			resp.int32(partition)
+			resp.int16(f.errorCode)
+			resp.int64(0)
+			resp.int64(-1)
+			resp.int32(0) // throttle_time_ms
+		}
+
+		frame := binary.BigEndian.AppendUint32(nil, uint32(resp.Len()))
+		if _, err := conn.Write(append(frame, resp.Bytes()...)); err != nil {
+			return
+		}
+	}
+}
+
+// TestKafkaSend_Ok checks that Send produces the message as a record batch to the partition of its key, led by the broker
+func TestKafkaSend_Ok(t *testing.T) {
+	// Arrange
+	fake := newFakeKafka(t, "users", 3, 0)
+	broker := NewKafkaBroker([]string{fake.listener.Addr().String()}, "test-client", false)
+	defer broker.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	msg := Message{Topic: "users", Key: "foobar", Value: []byte("value"), Headers: map[string]string{"event-type": "user.created"}}
+
+	// Act
+	err := broker.Send(ctx, msg)
+	secondErr := broker.Send(ctx, msg)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, secondErr)
+	produced := <-fake.produced
+	assert.Equal(t, "test-client", produced.clientID)
+	assert.Equal(t, int32(partitionOf([]byte("foobar"), 3)), produced.partition)
+	assert.Equal(t, recordBatch(msg, time.UnixMilli(0))[61:], produced.batch[61:])
+}
+
+// TestKafkaSend_ProduceError checks that Send returns an error when the broker does not acknowledge the message
+func TestKafkaSend_ProduceError(t *testing.T) {
+	// Arrange
+	fake := newFakeKafka(t, "users", 1, 6)
+	broker := NewKafkaBroker([]string{fake.listener.Addr().String()}, "test-client", false)
+	defer broker.Close()
+
+	// Act
+	err := broker.Send(context.Background(), Message{Topic: "users", Key: "1"})
+
+	// Assert
+	assert.EqualError(t, err, "kafka produce to users-0 failed with error code 6")
+}
+
+// TestKafkaSend_UnknownTopic checks that Send returns an error when the topic is not known by the cluster
+func TestKafkaSend_UnknownTopic(t *testing.T) {
+	// Arrange
+	fake := newFakeKafka(t, "users", 1, 0)
+	broker := NewKafkaBroker([]string{fake.listener.Addr().String()}, "test-client", false)
+	defer broker.Close()
+
+	// Act
+	err := broker.Send(context.Background(), Message{Topic: "logins", Key: "1"})
+
+	// Assert
+	assert.EqualError(t, err, "kafka topic logins has no partitions")
+}
+
+// TestKafkaSend_NotReachable checks that Send returns an error when no bootstrap broker is reachable
+func TestKafkaSend_NotReachable(t *testing.T) {
+	// Arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+	broker := NewKafkaBroker([]string{address}, "test-client", false)
+
+	// Act
+	err = broker.Send(context.Background(), Message{Topic: "users", Key: "1"})
+
+	// Assert
+	assert.ErrorContains(t, err, "kafka metadata of users cannot be read")
+}
+
+// TestRecordBatch_Ok checks that recordBatch returns a batch of the magic 2 whose CRC-32C checksums it from its attributes
+func TestRecordBatch_Ok(t *testing.T) {
+	// Act
+	batch := recordBatch(Message{Topic: "users", Key: "1", Value: []byte("value")}, time.Now())
+
+	// Assert
+	assert.Equal(t, int32(len(batch)-12), int32(binary.BigEndian.Uint32(batch[8:])))
+	assert.Equal(t, byte(2), batch[16])
+	assert.Equal(t, crc32.Checksum(batch[21:], castagnoli), binary.BigEndian.Uint32(batch[17:]))
+}
+
+// TestMurmur2_Ok checks that murmur2 returns the hashes of the Java client of Kafka
+func TestMurmur2_Ok(t *testing.T) {
+	// Act
+	short := murmur2([]byte("21"))
+	word := murmur2([]byte("foobar"))
+	long := murmur2([]byte("a-little-bit-long-string"))
+
+	// Assert
+	assert.Equal(t, int32(-973932308), short)
+	assert.Equal(t, int32(-790332482), word)
+	assert.Equal(t, int32(-985981536), long)
+}
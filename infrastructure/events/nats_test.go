@@ -0,0 +1,149 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeNATS server answering the messages published with the given acknowledgement, or with a 503 status when empty,
+// recording the CONNECT and the messages published
+type fakeNATS struct {
+	listener  net.Listener
+	ack       string
+	connects  chan string
+	published chan string
+}
+
+func newFakeNATS(t *testing.T, ack string) *fakeNATS {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	f := &fakeNATS{listener: listener, ack: ack, connects: make(chan string, 10), published: make(chan string, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeNATS) serve(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"headers\":true}\r\n")
+	reader := bufio.NewReader(conn)
+	var inbox string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		op, args, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		switch op {
+		case "CONNECT":
+			f.connects <- args
+		case "SUB":
+			inbox = strings.Fields(args)[0]
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "HPUB":
+			fields := strings.Fields(args)
+			size, _ := strconv.Atoi(fields[3])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			f.published <- fields[0] + " " + fields[1] + "\n" + string(payload[:size])
+
+			fmt.Fprint(conn, "PING\r\n")
+			if f.ack == "" {
+				fmt.Fprintf(conn, "HMSG %s 1 16 16\r\nNATS/1.0 503\r\n\r\n\r\n", inbox)
+			} else {
+				fmt.Fprintf(conn, "MSG %s 1 %d\r\n%s\r\n", inbox, len(f.ack), f.ack)
+			}
+		}
+	}
+}
+
+// TestNATSSend_Ok checks that Send publishes the message with its ID and headers to the subject of its topic, answering the PINGs of the server
+func TestNATSSend_Ok(t *testing.T) {
+	// Arrange
+	fake := newFakeNATS(t, `{"stream":"USERS","seq":1}`)
+	broker, err := NewNATSBroker("nats://user:password@"+fake.listener.Addr().String(), "test-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Act
+	err = broker.Send(ctx, Message{ID: "1", Topic: "users", Key: "u1", Value: []byte("value"), Headers: map[string]string{"event-type": "user.created"}})
+
+	// Assert
+	assert.Nil(t, err)
+	connect := <-fake.connects
+	assert.Contains(t, connect, `"name":"test-client"`)
+	assert.Contains(t, connect, `"user":"user","pass":"password"`)
+	published := <-fake.published
+	assert.True(t, strings.HasPrefix(published, "users _INBOX."))
+	assert.True(t, strings.HasSuffix(published, "\nNATS/1.0\r\nNats-Msg-Id: 1\r\nevent-type: user.created\r\n\r\nvalue"))
+}
+
+// TestNATSSend_StreamError checks that Send returns the error of the stream acknowledging the message
+func TestNATSSend_StreamError(t *testing.T) {
+	// Arrange
+	fake := newFakeNATS(t, `{"error":{"code":400,"description":"bad request"}}`)
+	broker, err := NewNATSBroker("nats://"+fake.listener.Addr().String(), "test-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+
+	// Act
+	err = broker.Send(context.Background(), Message{Topic: "users"})
+
+	// Assert
+	assert.EqualError(t, err, "nats publish to users: stream error 400: bad request")
+}
+
+// TestNATSSend_NoStream checks that Send returns an error when no stream listens to the subject of the topic
+func TestNATSSend_NoStream(t *testing.T) {
+	// Arrange
+	fake := newFakeNATS(t, "")
+	broker, err := NewNATSBroker("nats://"+fake.listener.Addr().String(), "test-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+
+	// Act
+	err = broker.Send(context.Background(), Message{Topic: "users"})
+
+	// Assert
+	assert.EqualError(t, err, "nats publish to users: no stream listens to the subject")
+}
+
+// TestNewNATSBroker_NotValid checks that NewNATSBroker returns an error when the scheme of the URL is not nats or tls
+func TestNewNATSBroker_NotValid(t *testing.T) {
+	// Act
+	_, err := NewNATSBroker("http://localhost:4222", "test-client")
+
+	// Assert
+	assert.EqualError(t, err, "nats url scheme http not valid")
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// queuedPublisher adapter of an event publisher queuing the events as event jobs, published by the workers and retried while the broker is not reachable,
+// so the job queue is the outbox of the events and none is lost when the broker is down
+type queuedPublisher struct {
+	jobs ports.JobService
+}
+
+// NewQueuedPublisher creates an event publisher queuing the events
+func NewQueuedPublisher(jobs ports.JobService) ports.EventPublisher {
+	return &queuedPublisher{
+		jobs: jobs,
+	}
+}
+
+// Publish queues the event
+func (p *queuedPublisher) Publish(ctx context.Context, event ports.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	_, err = p.jobs.Enqueue(ctx, entities.JobTypeEvent, map[string]string{
+		"id":          event.ID,
+		"type":        event.Type,
+		"user_id":     event.UserID,
+		"occurred_at": event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		"data":        string(data),
+	})
+	return err
+}
+
+// Deliver publishes an event job through the publisher
+func Deliver(ctx context.Context, publisher ports.EventPublisher, job *entities.Job) error {
+	occurredAt, err := time.Parse(time.RFC3339Nano, job.Metadata["occurred_at"])
+	if err != nil {
+		return fmt.Errorf("event not valid: %w", err)
+	}
+	var data map[string]string
+	if err := json.Unmarshal([]byte(job.Metadata["data"]), &data); err != nil {
+		return fmt.Errorf("event not valid: %w", err)
+	}
+	return publisher.Publish(ctx, ports.Event{
+		ID:         job.Metadata["id"],
+		Type:       job.Metadata["type"],
+		UserID:     job.Metadata["user_id"],
+		OccurredAt: occurredAt,
+		Data:       data,
+	})
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestQueuedPublish_Ok checks that Publish queues an event job that Deliver publishes as the same event through the publisher
+func TestQueuedPublish_Ok(t *testing.T) {
+	// Arrange
+	event := ports.Event{
+		ID:         "1",
+		Type:       ports.EventUserUpdated,
+		UserID:     "u1",
+		OccurredAt: time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC),
+		Data:       map[string]string{"fields": "email"},
+	}
+
+	var job entities.Job
+	jobServiceMock := mocks.NewJobService(t)
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Enqueue), context.Background(), entities.JobTypeEvent, mock.Anything).Run(func(args mock.Arguments) {
+		job = entities.Job{Type: entities.JobTypeEvent, Metadata: args.Get(2).(map[string]string)}
+	}).Return(models.JobResp{}, nil).Once()
+
+	publisherMock := mocks.NewEventPublisher(t)
+	publisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), context.Background(), event).Return(nil).Once()
+
+	// Act
+	err := NewQueuedPublisher(jobServiceMock).Publish(context.Background(), event)
+	deliverErr := Deliver(context.Background(), publisherMock, &job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, deliverErr)
+}
+
+// TestDeliver_NotValid checks that Deliver returns an error when the time of the event job is not valid
+func TestDeliver_NotValid(t *testing.T) {
+	// Arrange
+	job := entities.Job{Metadata: map[string]string{"occurred_at": "yesterday", "data": "{}"}}
+
+	// Act
+	err := Deliver(context.Background(), mocks.NewEventPublisher(t), &job)
+
+	// Assert
+	assert.ErrorContains(t, err, "event not valid")
+}
@@ -0,0 +1,205 @@
+package events
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRabbitMQ server confirming the messages published, returning them first when returned is set, and nacking them when nacked is set,
+// recording the vhost opened and the messages published
+type fakeRabbitMQ struct {
+	listener  net.Listener
+	returned  bool
+	nacked    bool
+	vhosts    chan string
+	published chan fakePublish
+}
+
+// fakePublish a message published to the fake server
+type fakePublish struct {
+	exchange   string
+	routingKey string
+	properties []byte
+	body       []byte
+}
+
+func newFakeRabbitMQ(t *testing.T, returned, nacked bool) *fakeRabbitMQ {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	f := &fakeRabbitMQ{listener: listener, returned: returned, nacked: nacked, vhosts: make(chan string, 10), published: make(chan fakePublish, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+// serve answers the client with the frames of a rabbitMQBroker over the server side of the connection
+func (f *fakeRabbitMQ) serve(conn net.Conn) {
+	defer conn.Close()
+	server := &rabbitMQBroker{conn: conn}
+	if _, err := io.ReadFull(conn, make([]byte, 8)); err != nil {
+		return
+	}
+
+	var start amqpEncoder
+	start.octet(0)
+	start.octet(9)
+	start.table(nil)
+	start.longstr("PLAIN")
+	start.longstr("en_US")
+	server.writeMethod(0, amqpConnection, amqpConnectionStart, start.Bytes())
+	if _, err := server.readMethod(0, amqpConnection, amqpConnectionStartOk); err != nil {
+		return
+	}
+	var tune amqpEncoder
+	tune.short(0)
+	tune.long(4096)
+	tune.short(60)
+	server.writeMethod(0, amqpConnection, amqpConnectionTune, tune.Bytes())
+	if _, err := server.readMethod(0, amqpConnection, amqpConnectionTuneOk); err != nil {
+		return
+	}
+	open, err := server.readMethod(0, amqpConnection, amqpConnectionOpen)
+	if err != nil {
+		return
+	}
+	f.vhosts <- string(open[1 : 1+open[0]])
+	server.writeMethod(0, amqpConnection, amqpConnectionOpenOk, []byte{0})
+	if _, err := server.readMethod(1, amqpChannel, amqpChannelOpen); err != nil {
+		return
+	}
+	server.writeMethod(1, amqpChannel, amqpChannelOpenOk, []byte{0, 0, 0, 0})
+	if _, err := server.readMethod(1, amqpConfirm, amqpConfirmSelect); err != nil {
+		return
+	}
+	server.writeMethod(1, amqpConfirm, amqpConfirmSelectOk, nil)
+
+	for tag := uint64(1); ; tag++ {
+		publish, err := server.readMethod(1, amqpBasic, amqpBasicPublish)
+		if err != nil {
+			return
+		}
+		exchange := string(publish[3 : 3+publish[2]])
+		keyAt := 3 + int(publish[2])
+		msg := fakePublish{exchange: exchange, routingKey: string(publish[keyAt+1 : keyAt+1+int(publish[keyAt])])}
+
+		_, _, header, err := server.readFrame()
+		if err != nil {
+			return
+		}
+		msg.properties = header[12:]
+		for size := binary.BigEndian.Uint64(header[4:]); uint64(len(msg.body)) < size; {
+			_, _, body, err := server.readFrame()
+			if err != nil {
+				return
+			}
+			msg.body = append(msg.body, body...)
+		}
+		f.published <- msg
+
+		if f.returned {
+			var ret amqpEncoder
+			ret.short(312)
+			ret.shortstr("NO_ROUTE")
+			ret.shortstr(msg.exchange)
+			ret.shortstr(msg.routingKey)
+			server.writeMethod(1, amqpBasic, amqpBasicReturn, ret.Bytes())
+			server.writeFrame(amqpFrameHeader, 1, header)
+		}
+		method := uint16(amqpBasicAck)
+		if f.nacked {
+			method = amqpBasicNack
+		}
+		server.writeMethod(1, amqpBasic, method, append(binary.BigEndian.AppendUint64(nil, tag), 0))
+	}
+}
+
+// TestRabbitMQSend_Ok checks that Send publishes the message to the exchange with its topic as routing key, split in frames, and waits for its confirmation
+func TestRabbitMQSend_Ok(t *testing.T) {
+	// Arrange
+	fake := newFakeRabbitMQ(t, false, false)
+	broker, err := NewRabbitMQBroker("amqp://guest:guest@"+fake.listener.Addr().String()+"/events", "user-events", "test-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	value := make([]byte, 10000)
+	msg := Message{ID: "1", Topic: "user.created", Value: value, Headers: map[string]string{"content-type": "application/json"}}
+
+	// Act
+	err = broker.Send(ctx, msg)
+	secondErr := broker.Send(ctx, msg)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, secondErr)
+	assert.Equal(t, "events", <-fake.vhosts)
+	published := <-fake.published
+	assert.Equal(t, "user-events", published.exchange)
+	assert.Equal(t, "user.created", published.routingKey)
+	assert.Equal(t, value, published.body)
+	assert.Equal(t, []byte{0xb0, 0xc0}, published.properties[:2])
+	assert.Equal(t, "application/json", string(published.properties[3:19]))
+}
+
+// TestRabbitMQSend_Returned checks that Send returns an error when the message is returned as not routed to any queue
+func TestRabbitMQSend_Returned(t *testing.T) {
+	// Arrange
+	fake := newFakeRabbitMQ(t, true, false)
+	broker, err := NewRabbitMQBroker("amqp://guest:guest@"+fake.listener.Addr().String(), "", "test-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+
+	// Act
+	err = broker.Send(context.Background(), Message{Topic: "users", Value: []byte("value")})
+
+	// Assert
+	assert.EqualError(t, err, "rabbitmq publish to users: message returned: 312 NO_ROUTE")
+	assert.Equal(t, "/", <-fake.vhosts)
+}
+
+// TestRabbitMQSend_Nacked checks that Send returns an error when the server does not confirm the message
+func TestRabbitMQSend_Nacked(t *testing.T) {
+	// Arrange
+	fake := newFakeRabbitMQ(t, false, true)
+	broker, err := NewRabbitMQBroker("amqp://guest:guest@"+fake.listener.Addr().String(), "", "test-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+
+	// Act
+	err = broker.Send(context.Background(), Message{Topic: "users", Value: []byte("value")})
+
+	// Assert
+	assert.EqualError(t, err, "rabbitmq publish to users: message not confirmed")
+}
+
+// TestNewRabbitMQBroker_NotValid checks that NewRabbitMQBroker returns an error when the scheme of the URL is not amqp or amqps
+func TestNewRabbitMQBroker_NotValid(t *testing.T) {
+	// Act
+	_, err := NewRabbitMQBroker("http://localhost:5672", "", "test-client")
+
+	// Assert
+	assert.EqualError(t, err, "rabbitmq url scheme http not valid")
+}
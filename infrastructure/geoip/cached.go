@@ -0,0 +1,65 @@
+package geoip
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// cachedLocation a location cached until it expires
+type cachedLocation struct {
+	location  models.GeoLocation
+	expiresAt time.Time
+}
+
+// cachedLocator decorator of a GeoIP locator caching the locations in memory, so an IP is not looked up on every event of its requests
+type cachedLocator struct {
+	ports.GeoIPLocator
+	ttl        time.Duration
+	maxEntries int
+	mu         sync.Mutex
+	locations  map[string]cachedLocation
+}
+
+// NewCachedLocator wraps a locator caching up to maxEntries locations for ttl. The failed lookups are not cached.
+func NewCachedLocator(locator ports.GeoIPLocator, ttl time.Duration, maxEntries int) ports.GeoIPLocator {
+	return &cachedLocator{
+		GeoIPLocator: locator,
+		ttl:          ttl,
+		maxEntries:   maxEntries,
+		locations:    map[string]cachedLocation{},
+	}
+}
+
+// Locate returns the cached location of the IP, looking it up through the decorated locator when not cached or expired
+func (l *cachedLocator) Locate(ctx context.Context, ip string) (models.GeoLocation, error) {
+	now := time.Now()
+	l.mu.Lock()
+	cached, ok := l.locations[ip]
+	l.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.location, nil
+	}
+
+	location, err := l.GeoIPLocator.Locate(ctx, ip)
+	if err != nil {
+		return location, err
+	}
+
+	l.mu.Lock()
+	if len(l.locations) >= l.maxEntries {
+		for key, c := range l.locations {
+			if !now.Before(c.expiresAt) {
+				delete(l.locations, key)
+			}
+		}
+	}
+	if len(l.locations) < l.maxEntries {
+		l.locations[ip] = cachedLocation{location: location, expiresAt: now.Add(l.ttl)}
+	}
+	l.mu.Unlock()
+	return location, nil
+}
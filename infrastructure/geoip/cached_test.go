@@ -0,0 +1,51 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCachedLocate_Cached checks that Locate looks up an IP once while cached
+func TestCachedLocate_Cached(t *testing.T) {
+	// Arrange
+	expected := models.GeoLocation{Country: "GB", City: "London"}
+	locatorMock := mocks.NewGeoIPLocator(t)
+	locatorMock.On(testutils.FunctionName(t, ports.GeoIPLocator.Locate), context.Background(), "81.2.69.142").Return(expected, nil).Once()
+
+	l := NewCachedLocator(locatorMock, time.Hour, 10)
+
+	// Act
+	first, firstErr := l.Locate(context.Background(), "81.2.69.142")
+	second, secondErr := l.Locate(context.Background(), "81.2.69.142")
+
+	// Assert
+	assert.Nil(t, firstErr)
+	assert.Nil(t, secondErr)
+	assert.Equal(t, expected, first)
+	assert.Equal(t, expected, second)
+}
+
+// TestCachedLocate_ErrorNotCached checks that Locate looks up again an IP whose lookup failed
+func TestCachedLocate_ErrorNotCached(t *testing.T) {
+	// Arrange
+	locatorMock := mocks.NewGeoIPLocator(t)
+	locatorMock.On(testutils.FunctionName(t, ports.GeoIPLocator.Locate), context.Background(), "81.2.69.142").Return(models.GeoLocation{}, errors.New("locator error")).Twice()
+
+	l := NewCachedLocator(locatorMock, time.Hour, 10)
+
+	// Act
+	_, firstErr := l.Locate(context.Background(), "81.2.69.142")
+	_, secondErr := l.Locate(context.Background(), "81.2.69.142")
+
+	// Assert
+	assert.Equal(t, "locator error", firstErr.Error())
+	assert.Equal(t, "locator error", secondErr.Error())
+}
@@ -0,0 +1,20 @@
+package geoip
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// NewLocator creates the GeoIPLocator of the given provider, maxmind reading the database at the given path or ipinfo calling its API with the token
+func NewLocator(provider, databasePath, ipinfoToken string) (ports.GeoIPLocator, error) {
+	switch provider {
+	case "maxmind":
+		return NewMaxMindLocator(databasePath)
+	case "ipinfo":
+		return NewIPInfoLocator(ipinfoToken, http.DefaultClient), nil
+	default:
+		return nil, fmt.Errorf("geoip provider %s not valid", provider)
+	}
+}
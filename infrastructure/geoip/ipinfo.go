@@ -0,0 +1,74 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+)
+
+// ipinfoEndpoint is the endpoint of the API of ipinfo
+const ipinfoEndpoint = "https://ipinfo.io"
+
+// ipinfoLocator adapter of a GeoIP locator looking up the IPs through the API of ipinfo
+type ipinfoLocator struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+// NewIPInfoLocator creates a locator calling the API of ipinfo authenticated with the token
+func NewIPInfoLocator(token string, client *http.Client) ports.GeoIPLocator {
+	return &ipinfoLocator{
+		endpoint: ipinfoEndpoint,
+		token:    token,
+		client:   client,
+	}
+}
+
+// Locate looks up the country and the city of the IP, the private ones, named bogons by ipinfo, not being located
+func (l *ipinfoLocator) Locate(ctx context.Context, ip string) (models.GeoLocation, error) {
+	if net.ParseIP(ip) == nil {
+		return models.GeoLocation{}, fmt.Errorf("ip %q not valid", ip)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s/json", l.endpoint, url.PathEscape(ip)), nil)
+	if err != nil {
+		return models.GeoLocation{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+l.token)
+	tracing.InjectHeader(ctx, req.Header)
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return models.GeoLocation{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return models.GeoLocation{}, fmt.Errorf("ipinfo responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var location struct {
+		Country string `json:"country"`
+		City    string `json:"city"`
+		Bogon   bool   `json:"bogon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&location); err != nil {
+		return models.GeoLocation{}, fmt.Errorf("ipinfo response cannot be decoded: %w", err)
+	}
+	if location.Bogon {
+		return models.GeoLocation{}, nil
+	}
+	return models.GeoLocation{Country: location.Country, City: location.City}, nil
+}
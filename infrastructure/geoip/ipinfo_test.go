@@ -0,0 +1,73 @@
+package geoip
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIPInfoLocate_Ok checks that Locate looks up the IP authenticated with the token, returning its country and city
+func TestIPInfoLocate_Ok(t *testing.T) {
+	// Arrange
+	var path, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{"ip":"81.2.69.142","city":"London","region":"England","country":"GB","loc":"51.5085,-0.1257"}`))
+	}))
+	defer server.Close()
+
+	l := NewIPInfoLocator("test-token", server.Client()).(*ipinfoLocator)
+	l.endpoint = server.URL
+
+	// Act
+	location, err := l.Locate(context.Background(), "81.2.69.142")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.GeoLocation{Country: "GB", City: "London"}, location)
+	assert.Equal(t, "/81.2.69.142/json", path)
+	assert.Equal(t, "Bearer test-token", authorization)
+}
+
+// TestIPInfoLocate_Bogon checks that Locate returns an empty location for the private IPs
+func TestIPInfoLocate_Bogon(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ip":"10.0.0.1","bogon":true}`))
+	}))
+	defer server.Close()
+
+	l := NewIPInfoLocator("test-token", server.Client()).(*ipinfoLocator)
+	l.endpoint = server.URL
+
+	// Act
+	location, err := l.Locate(context.Background(), "10.0.0.1")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.GeoLocation{}, location)
+}
+
+// TestIPInfoLocate_Error checks that Locate returns the error of ipinfo when it does not respond with 200
+func TestIPInfoLocate_Error(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("rate limit exceeded"))
+	}))
+	defer server.Close()
+
+	l := NewIPInfoLocator("test-token", server.Client()).(*ipinfoLocator)
+	l.endpoint = server.URL
+
+	// Act
+	_, err := l.Locate(context.Background(), "81.2.69.142")
+
+	// Assert
+	assert.Equal(t, "ipinfo responded with status 429: rate limit exceeded", err.Error())
+}
@@ -0,0 +1,306 @@
+package geoip
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// metadataMarker precedes the metadata at the end of a MaxMind database
+var metadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// dataSectionSeparator the zeros between the search tree and the data section of a MaxMind database
+const dataSectionSeparator = 16
+
+// types of the fields of the data section of a MaxMind database
+const (
+	mmdbExtended = iota
+	mmdbPointer
+	mmdbString
+	mmdbDouble
+	mmdbBytes
+	mmdbUint16
+	mmdbUint32
+	mmdbMap
+	mmdbInt32
+	mmdbUint64
+	mmdbUint128
+	mmdbArray
+	mmdbContainer
+	mmdbEndMarker
+	mmdbBoolean
+	mmdbFloat
+)
+
+// maxMindLocator adapter of a GeoIP locator looking up the IPs in a MaxMind database, like GeoLite2-City.mmdb, read in memory
+type maxMindLocator struct {
+	tree       []byte
+	data       []byte
+	nodeCount  uint
+	recordSize uint
+	ipv4Start  uint
+	ipVersion  uint
+}
+
+// NewMaxMindLocator creates a locator reading the MaxMind database, in the MaxMind DB format of the GeoIP2 and GeoLite2 databases, at the path
+func NewMaxMindLocator(path string) (ports.GeoIPLocator, error) {
+	db, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newMaxMindLocator(db)
+}
+
+// newMaxMindLocator creates a locator of the database, reading its metadata
+func newMaxMindLocator(db []byte) (*maxMindLocator, error) {
+	start := bytes.LastIndex(db, metadataMarker)
+	if start < 0 {
+		return nil, errors.New("maxmind database metadata not found")
+	}
+	metadata := db[start+len(metadataMarker):]
+	value, _, err := decode(metadata, 0)
+	if err != nil {
+		return nil, fmt.Errorf("maxmind database metadata not valid: %w", err)
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("maxmind database metadata not valid: not a map")
+	}
+
+	l := &maxMindLocator{
+		nodeCount:  toUint(fields["node_count"]),
+		recordSize: toUint(fields["record_size"]),
+		ipVersion:  toUint(fields["ip_version"]),
+	}
+	if l.recordSize != 24 && l.recordSize != 28 && l.recordSize != 32 {
+		return nil, fmt.Errorf("maxmind database record size %d not supported", l.recordSize)
+	}
+	if l.ipVersion != 4 && l.ipVersion != 6 {
+		return nil, fmt.Errorf("maxmind database ip version %d not supported", l.ipVersion)
+	}
+	treeSize := l.nodeCount * l.recordSize / 4
+	if treeSize+dataSectionSeparator > uint(start) {
+		return nil, errors.New("maxmind database search tree not valid: larger than the database")
+	}
+	l.tree = db[:treeSize]
+	l.data = db[treeSize+dataSectionSeparator : start]
+
+	// the IPv4 addresses of an IPv6 database are the ones of the ::/96 subnet, whose node is found once
+	if l.ipVersion == 6 {
+		for i := 0; i < 96 && l.ipv4Start < l.nodeCount; i++ {
+			l.ipv4Start = l.record(l.ipv4Start, 0)
+		}
+	}
+	return l, nil
+}
+
+// Locate looks up the country and the city of the IP in the database
+func (l *maxMindLocator) Locate(ctx context.Context, ip string) (models.GeoLocation, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return models.GeoLocation{}, fmt.Errorf("ip %q not valid", ip)
+	}
+
+	address, node := parsed.To4(), l.ipv4Start
+	if address == nil {
+		if l.ipVersion == 4 {
+			return models.GeoLocation{}, nil
+		}
+		address, node = parsed.To16(), 0
+	}
+	for i := 0; i < len(address)*8 && node < l.nodeCount; i++ {
+		bit := (address[i/8] >> (7 - uint(i%8))) & 1
+		node = l.record(node, bit)
+	}
+	if node <= l.nodeCount {
+		return models.GeoLocation{}, nil
+	}
+
+	value, _, err := decode(l.data, node-l.nodeCount-dataSectionSeparator)
+	if err != nil {
+		return models.GeoLocation{}, fmt.Errorf("maxmind database record of %s not valid: %w", ip, err)
+	}
+	record, _ := value.(map[string]interface{})
+	country := lookup(record, "country", "iso_code")
+	if country == "" {
+		country = lookup(record, "registered_country", "iso_code")
+	}
+	return models.GeoLocation{Country: country, City: lookup(record, "city", "names", "en")}, nil
+}
+
+// record returns the left record of the node, or the right one when the bit is set
+func (l *maxMindLocator) record(node uint, bit byte) uint {
+	size := l.recordSize / 4
+	b := l.tree[node*size : (node+1)*size]
+	switch l.recordSize {
+	case 24:
+		if bit == 0 {
+			return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3])<<16 | uint(b[4])<<8 | uint(b[5])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xf0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0f)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		if bit == 0 {
+			return uint(binary.BigEndian.Uint32(b[:4]))
+		}
+		return uint(binary.BigEndian.Uint32(b[4:]))
+	}
+}
+
+// decode decodes the field of the data section at the offset, returning its value and the offset of the next field.
+// The pointers are followed, the next field being the one after the pointer.
+func decode(data []byte, offset uint) (interface{}, uint, error) {
+	if offset >= uint(len(data)) {
+		return nil, 0, errors.New("offset out of the data section")
+	}
+	control := data[offset]
+	offset++
+	kind := uint(control >> 5)
+	if kind == mmdbPointer {
+		pointer, next, err := decodePointer(data, control, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := decode(data, pointer)
+		return value, next, err
+	}
+	if kind == mmdbExtended {
+		if offset >= uint(len(data)) {
+			return nil, 0, errors.New("extended type out of the data section")
+		}
+		kind = 7 + uint(data[offset])
+		offset++
+	}
+
+	size := uint(control & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		if offset+n > uint(len(data)) {
+			return nil, 0, errors.New("size out of the data section")
+		}
+		extra := uint(0)
+		for _, b := range data[offset : offset+n] {
+			extra = extra<<8 | uint(b)
+		}
+		offset += n
+		size = [...]uint{29, 285, 65821}[n-1] + extra
+	}
+
+	switch kind {
+	case mmdbMap:
+		values := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := decode(data, offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, errors.New("map key not a string")
+			}
+			values[name], offset, err = decode(data, next)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		return values, offset, nil
+	case mmdbArray:
+		values := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			value, next, err := decode(data, offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			values = append(values, value)
+			offset = next
+		}
+		return values, offset, nil
+	case mmdbBoolean:
+		return size != 0, offset, nil
+	case mmdbEndMarker, mmdbContainer:
+		return nil, offset, nil
+	}
+
+	if offset+size > uint(len(data)) {
+		return nil, 0, errors.New("value out of the data section")
+	}
+	b := data[offset : offset+size]
+	offset += size
+	switch kind {
+	case mmdbString:
+		return string(b), offset, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, errors.New("double not of 8 bytes")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), offset, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, errors.New("float not of 4 bytes")
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), offset, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64, mmdbInt32:
+		var n uint64
+		for _, v := range b {
+			n = n<<8 | uint64(v)
+		}
+		if kind == mmdbInt32 {
+			return int64(int32(n)), offset, nil
+		}
+		return n, offset, nil
+	case mmdbBytes, mmdbUint128:
+		return b, offset, nil
+	default:
+		return nil, 0, fmt.Errorf("type %d not valid", kind)
+	}
+}
+
+// decodePointer returns the offset in the data section the pointer points to, and the offset of the field after the pointer
+func decodePointer(data []byte, control byte, offset uint) (uint, uint, error) {
+	n := uint(control>>3)&0x3 + 1
+	if offset+n > uint(len(data)) {
+		return 0, 0, errors.New("pointer out of the data section")
+	}
+	b := data[offset : offset+n]
+	var pointer uint
+	if n < 4 {
+		pointer = uint(control & 0x7)
+	}
+	for _, v := range b {
+		pointer = pointer<<8 | uint(v)
+	}
+	pointer += [...]uint{0, 2048, 526336, 0}[n-1]
+	return pointer, offset + n, nil
+}
+
+// lookup returns the string at the path of nested maps of the record, empty when not found
+func lookup(record map[string]interface{}, path ...string) string {
+	var value interface{} = record
+	for _, key := range path {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = fields[key]
+	}
+	s, _ := value.(string)
+	return s
+}
+
+// toUint returns the unsigned integer of the metadata field, zero when not one
+func toUint(value interface{}) uint {
+	n, _ := value.(uint64)
+	return uint(n)
+}
@@ -0,0 +1,225 @@
+package geoip
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPointer a pointer to the offset, below 2048, of a field of the data section
+type testPointer uint
+
+// encodeField encodes the value as a field of the data section of a MaxMind database, the maps with their keys sorted
+func encodeField(value interface{}) []byte {
+	header := func(kind, size int) []byte {
+		if kind > 7 {
+			return []byte{byte(size), byte(kind - 7)}
+		}
+		return []byte{byte(kind<<5 | size)}
+	}
+	switch v := value.(type) {
+	case string:
+		return append(header(mmdbString, len(v)), v...)
+	case uint16:
+		return append(header(mmdbUint16, 2), byte(v>>8), byte(v))
+	case uint32:
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, v)
+		return append(header(mmdbUint32, 4), b...)
+	case testPointer:
+		return []byte{byte(mmdbPointer<<5 | int(v>>8)&0x7), byte(v)}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		b := header(mmdbMap, len(v))
+		for _, key := range keys {
+			b = append(b, encodeField(key)...)
+			b = append(b, encodeField(v[key])...)
+		}
+		return b
+	default:
+		panic("type not supported")
+	}
+}
+
+// testNetwork a network of a test database, with the offset of its record in the data section
+type testNetwork struct {
+	ip     string
+	bits   int
+	record uint
+}
+
+// writeTestDatabase writes a MaxMind database of the given IP version and record size, mapping the networks to the records of the data section
+func writeTestDatabase(t *testing.T, ipVersion uint16, recordSize uint16, data []byte, networks ...testNetwork) string {
+	t.Helper()
+
+	const empty, leaf = -1, -2
+	type node struct {
+		children [2]int
+		records  [2]uint
+	}
+	nodes := []node{{children: [2]int{empty, empty}}}
+	for _, n := range networks {
+		address := net.ParseIP(n.ip).To16()
+		if ipVersion == 4 {
+			address = address.To4()
+		}
+		current := 0
+		for i := 0; i < n.bits; i++ {
+			bit := (address[i/8] >> (7 - uint(i%8))) & 1
+			if i == n.bits-1 {
+				nodes[current].children[bit] = leaf
+				nodes[current].records[bit] = n.record
+				break
+			}
+			if nodes[current].children[bit] == empty {
+				nodes = append(nodes, node{children: [2]int{empty, empty}})
+				nodes[current].children[bit] = len(nodes) - 1
+			}
+			current = nodes[current].children[bit]
+		}
+	}
+
+	count := uint(len(nodes))
+	var tree []byte
+	for _, n := range nodes {
+		var records [2]uint
+		for i, child := range n.children {
+			switch child {
+			case empty:
+				records[i] = count
+			case leaf:
+				records[i] = count + dataSectionSeparator + n.records[i]
+			default:
+				records[i] = uint(child)
+			}
+		}
+		switch recordSize {
+		case 24:
+			tree = append(tree, byte(records[0]>>16), byte(records[0]>>8), byte(records[0]), byte(records[1]>>16), byte(records[1]>>8), byte(records[1]))
+		case 28:
+			tree = append(tree, byte(records[0]>>16), byte(records[0]>>8), byte(records[0]), byte(records[0]>>20&0xf0|records[1]>>24&0x0f), byte(records[1]>>16), byte(records[1]>>8), byte(records[1]))
+		}
+	}
+
+	db := append(tree, make([]byte, dataSectionSeparator)...)
+	db = append(db, data...)
+	db = append(db, metadataMarker...)
+	db = append(db, encodeField(map[string]interface{}{
+		"node_count":    uint32(count),
+		"record_size":   recordSize,
+		"ip_version":    ipVersion,
+		"database_type": "Test-City",
+	})...)
+
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	if err := os.WriteFile(path, db, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// testRecords the data section of the test databases, returning the offsets of its records: a city of Great Britain,
+// and a network registered to Spain without its country, naming its registered country through a pointer to a country shared by the records
+func testRecords() ([]byte, uint, uint) {
+	data := encodeField(map[string]interface{}{"iso_code": "ES"})
+	london := uint(len(data))
+	data = append(data, encodeField(map[string]interface{}{
+		"city":    map[string]interface{}{"names": map[string]interface{}{"en": "London", "es": "Londres"}},
+		"country": map[string]interface{}{"iso_code": "GB"},
+	})...)
+	spain := uint(len(data))
+	data = append(data, encodeField(map[string]interface{}{
+		"registered_country": testPointer(0),
+	})...)
+	return data, london, spain
+}
+
+// TestMaxMindLocate_IPv6Database checks that Locate looks up the IPv4 and IPv6 addresses in an IPv6 database
+func TestMaxMindLocate_IPv6Database(t *testing.T) {
+	// Arrange
+	data, london, spain := testRecords()
+	path := writeTestDatabase(t, 6, 28, data,
+		testNetwork{ip: "::81.2.69.0", bits: 120, record: london},
+		testNetwork{ip: "2a02:9000::", bits: 32, record: spain},
+	)
+	locator, err := NewMaxMindLocator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	gb, gbErr := locator.Locate(context.Background(), "81.2.69.142")
+	es, esErr := locator.Locate(context.Background(), "2a02:9000::1")
+	unknown, unknownErr := locator.Locate(context.Background(), "10.0.0.1")
+
+	// Assert
+	assert.Nil(t, gbErr)
+	assert.Equal(t, models.GeoLocation{Country: "GB", City: "London"}, gb)
+	assert.Nil(t, esErr)
+	assert.Equal(t, models.GeoLocation{Country: "ES"}, es)
+	assert.Nil(t, unknownErr)
+	assert.Equal(t, models.GeoLocation{}, unknown)
+}
+
+// TestMaxMindLocate_IPv4Database checks that Locate looks up the IPv4 addresses in an IPv4 database, not locating the IPv6 ones
+func TestMaxMindLocate_IPv4Database(t *testing.T) {
+	// Arrange
+	data, london, _ := testRecords()
+	path := writeTestDatabase(t, 4, 24, data, testNetwork{ip: "81.2.69.0", bits: 24, record: london})
+	locator, err := NewMaxMindLocator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	gb, gbErr := locator.Locate(context.Background(), "81.2.69.1")
+	ipv6, ipv6Err := locator.Locate(context.Background(), "2a02:9000::1")
+
+	// Assert
+	assert.Nil(t, gbErr)
+	assert.Equal(t, models.GeoLocation{Country: "GB", City: "London"}, gb)
+	assert.Nil(t, ipv6Err)
+	assert.Equal(t, models.GeoLocation{}, ipv6)
+}
+
+// TestMaxMindLocate_InvalidIP checks that Locate returns an error when the IP is not valid
+func TestMaxMindLocate_InvalidIP(t *testing.T) {
+	// Arrange
+	data, _, _ := testRecords()
+	locator, err := NewMaxMindLocator(writeTestDatabase(t, 4, 24, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err = locator.Locate(context.Background(), "unknown")
+
+	// Assert
+	assert.Equal(t, `ip "unknown" not valid`, err.Error())
+}
+
+// TestNewMaxMindLocator_NotADatabase checks that NewMaxMindLocator returns an error when the file is not a MaxMind database
+func TestNewMaxMindLocator_NotADatabase(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	if err := os.WriteFile(path, []byte("not a database"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err := NewMaxMindLocator(path)
+
+	// Assert
+	assert.Equal(t, "maxmind database metadata not found", err.Error())
+}
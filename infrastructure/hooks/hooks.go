@@ -0,0 +1,33 @@
+package hooks
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// chain runs the hooks around the repository operations, the Before hooks in order and the After hooks in reverse order
+type chain []ports.RepositoryHook
+
+// run runs the operation between the hooks. When a Before hook fails the operation is not run,
+// and only the hooks that were already run receive the error in their After hook.
+func (c chain) run(ctx context.Context, collection, name string, operation func(ctx context.Context) error) (err error) {
+	op := ports.RepositoryOperation{Collection: collection, Name: name}
+
+	contexts := make([]context.Context, 0, len(c))
+	defer func() {
+		for i := len(contexts) - 1; i >= 0; i-- {
+			c[i].After(contexts[i], op, err)
+		}
+	}()
+
+	for _, h := range c {
+		ctx, err = h.Before(ctx, op)
+		if err != nil {
+			return err
+		}
+		contexts = append(contexts, ctx)
+	}
+
+	return operation(ctx)
+}
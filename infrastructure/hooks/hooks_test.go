@@ -0,0 +1,88 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorderHook records the calls it receives, failing the Before hook when beforeErr is set
+type recorderHook struct {
+	name      string
+	calls     *[]string
+	beforeErr error
+	afterErr  error
+}
+
+func (h *recorderHook) Before(ctx context.Context, op ports.RepositoryOperation) (context.Context, error) {
+	*h.calls = append(*h.calls, h.name+".Before."+op.Collection+"."+op.Name)
+	return ctx, h.beforeErr
+}
+
+func (h *recorderHook) After(ctx context.Context, op ports.RepositoryOperation, err error) {
+	*h.calls = append(*h.calls, h.name+".After."+op.Collection+"."+op.Name)
+	h.afterErr = err
+}
+
+// TestRun_Order checks that run runs the Before hooks in order, then the operation and then the After hooks in reverse order
+func TestRun_Order(t *testing.T) {
+	// Arrange
+	var calls []string
+	first := &recorderHook{name: "first", calls: &calls}
+	second := &recorderHook{name: "second", calls: &calls}
+	c := chain{first, second}
+	expectedErr := errors.New("operation error")
+
+	// Act
+	err := c.run(context.Background(), "test", "Op", func(ctx context.Context) error {
+		calls = append(calls, "operation")
+		return expectedErr
+	})
+
+	// Assert
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, []string{"first.Before.test.Op", "second.Before.test.Op", "operation", "second.After.test.Op", "first.After.test.Op"}, calls)
+	assert.Equal(t, expectedErr, first.afterErr)
+	assert.Equal(t, expectedErr, second.afterErr)
+}
+
+// TestRun_BeforeError checks that run does not run the operation when a Before hook fails, only running the After hooks of the previous ones
+func TestRun_BeforeError(t *testing.T) {
+	// Arrange
+	var calls []string
+	expectedErr := errors.New("before error")
+	first := &recorderHook{name: "first", calls: &calls}
+	second := &recorderHook{name: "second", calls: &calls, beforeErr: expectedErr}
+	c := chain{first, second}
+
+	// Act
+	err := c.run(context.Background(), "test", "Op", func(ctx context.Context) error {
+		calls = append(calls, "operation")
+		return nil
+	})
+
+	// Assert
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, []string{"first.Before.test.Op", "second.Before.test.Op", "first.After.test.Op"}, calls)
+	assert.Equal(t, expectedErr, first.afterErr)
+}
+
+// TestRun_NoHooks checks that run only runs the operation when there are no hooks
+func TestRun_NoHooks(t *testing.T) {
+	// Arrange
+	var c chain
+	ran := false
+
+	// Act
+	err := c.run(context.Background(), "test", "Op", func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, ran)
+}
@@ -0,0 +1,81 @@
+package hooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// jobRepository decorator of a job repository that runs the hooks around every operation
+type jobRepository struct {
+	repo  ports.JobRepository
+	hooks chain
+}
+
+// NewJobRepository wraps a job repository running the given hooks around every operation
+func NewJobRepository(repo ports.JobRepository, hooks ...ports.RepositoryHook) ports.JobRepository {
+	return &jobRepository{
+		repo:  repo,
+		hooks: hooks,
+	}
+}
+
+func (r *jobRepository) run(ctx context.Context, name string, operation func(ctx context.Context) error) error {
+	return r.hooks.run(ctx, entities.EntityNameJob, name, operation)
+}
+
+func (r *jobRepository) Create(ctx context.Context, job entities.Job) (id string, err error) {
+	err = r.run(ctx, "Create", func(ctx context.Context) (err error) {
+		id, err = r.repo.Create(ctx, job)
+		return err
+	})
+	return
+}
+
+func (r *jobRepository) Update(ctx context.Context, job entities.Job) error {
+	return r.run(ctx, "Update", func(ctx context.Context) error {
+		return r.repo.Update(ctx, job)
+	})
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, ID string) (job entities.Job, err error) {
+	err = r.run(ctx, "GetByID", func(ctx context.Context) (err error) {
+		job, err = r.repo.GetByID(ctx, ID)
+		return err
+	})
+	return
+}
+
+func (r *jobRepository) Get(ctx context.Context, filter ports.JobFilter, skip, take *int) (jobs []entities.Job, err error) {
+	err = r.run(ctx, "Get", func(ctx context.Context) (err error) {
+		jobs, err = r.repo.Get(ctx, filter, skip, take)
+		return err
+	})
+	return
+}
+
+func (r *jobRepository) Claim(ctx context.Context, types []string, worker string, now, lockedUntil time.Time) (job entities.Job, err error) {
+	err = r.run(ctx, "Claim", func(ctx context.Context) (err error) {
+		job, err = r.repo.Claim(ctx, types, worker, now, lockedUntil)
+		return err
+	})
+	return
+}
+
+func (r *jobRepository) Count(ctx context.Context, statuses []entities.JobStatus) (counts []ports.JobCount, err error) {
+	err = r.run(ctx, "Count", func(ctx context.Context) (err error) {
+		counts, err = r.repo.Count(ctx, statuses)
+		return err
+	})
+	return
+}
+
+func (r *jobRepository) Expire(ctx context.Context, action entities.RetentionAction, before time.Time, dryRun bool) (expired int64, err error) {
+	err = r.run(ctx, "Expire", func(ctx context.Context) (err error) {
+		expired, err = r.repo.Expire(ctx, action, before, dryRun)
+		return err
+	})
+	return
+}
@@ -0,0 +1,33 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetByIDJob_Ok checks that GetByID runs the hooks around the wrapped repository and returns its result
+func TestGetByIDJob_Ok(t *testing.T) {
+	// Arrange
+	var calls []string
+	hook := &recorderHook{name: "hook", calls: &calls}
+	expectedJob := entities.Job{ID: "test-id"}
+
+	jobRepositoryMock := mocks.NewJobRepository(t)
+	jobRepositoryMock.On(testutils.FunctionName(t, ports.JobRepository.GetByID), context.Background(), "test-id").Return(expectedJob, nil).Once()
+
+	repo := NewJobRepository(jobRepositoryMock, hook)
+
+	// Act
+	job, err := repo.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedJob, job)
+	assert.Equal(t, []string{"hook.Before.jobs.GetByID", "hook.After.jobs.GetByID"}, calls)
+}
@@ -0,0 +1,93 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// operations holds the duration metrics of the repository operations run with a metrics hook, exported as repository_operations
+var operations = newOperationMetrics()
+
+func init() {
+	expvar.Publish("repository_operations", operations)
+}
+
+type startKey struct{}
+
+// metricsHook records the duration of every repository operation per collection and operation
+type metricsHook struct{}
+
+// NewMetricsHook creates a hook recording the count, failures and duration of the repository operations, whatever the database
+func NewMetricsHook() ports.RepositoryHook {
+	return metricsHook{}
+}
+
+func (metricsHook) Before(ctx context.Context, op ports.RepositoryOperation) (context.Context, error) {
+	return context.WithValue(ctx, startKey{}, time.Now()), nil
+}
+
+func (metricsHook) After(ctx context.Context, op ports.RepositoryOperation, err error) {
+	start, ok := ctx.Value(startKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	operations.observe(op.Collection+"."+op.Name, time.Since(start), err != nil)
+}
+
+// operationStats duration metrics of the operations run against a collection
+type operationStats struct {
+	Count    int64   `json:"count"`
+	Failures int64   `json:"failures"`
+	TotalMS  float64 `json:"total_ms"`
+	MaxMS    float64 `json:"max_ms"`
+}
+
+// operationMetrics duration metrics of the repository operations per collection and operation, like users.GetByID
+type operationMetrics struct {
+	mu    sync.Mutex
+	stats map[string]*operationStats
+}
+
+func newOperationMetrics() *operationMetrics {
+	return &operationMetrics{
+		stats: make(map[string]*operationStats),
+	}
+}
+
+func (m *operationMetrics) observe(key string, duration time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.stats[key]
+	if !ok {
+		s = &operationStats{}
+		m.stats[key] = s
+	}
+
+	ms := float64(duration) / float64(time.Millisecond)
+	s.Count++
+	s.TotalMS += ms
+	if ms > s.MaxMS {
+		s.MaxMS = ms
+	}
+	if failed {
+		s.Failures++
+	}
+}
+
+// String implements the expvar.Var interface
+func (m *operationMetrics) String() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, err := json.Marshal(m.stats)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
@@ -0,0 +1,42 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMetricsHook_Ok checks that the metrics hook records the operations per collection and operation, counting the failed ones
+func TestMetricsHook_Ok(t *testing.T) {
+	// Arrange
+	hook := NewMetricsHook()
+	op := ports.RepositoryOperation{Collection: "metrics_test", Name: "Get"}
+
+	// Act
+	for _, err := range []error{nil, errors.New("get error")} {
+		ctx, beforeErr := hook.Before(context.Background(), op)
+		assert.Nil(t, beforeErr)
+		hook.After(ctx, op, err)
+	}
+
+	// Assert
+	stats := operations.stats["metrics_test.Get"]
+	assert.Equal(t, int64(2), stats.Count)
+	assert.Equal(t, int64(1), stats.Failures)
+}
+
+// TestMetricsHook_NoStart checks that the metrics hook does not record an operation whose Before hook was not run
+func TestMetricsHook_NoStart(t *testing.T) {
+	// Arrange
+	hook := NewMetricsHook()
+	op := ports.RepositoryOperation{Collection: "metrics_test", Name: "NoStart"}
+
+	// Act
+	hook.After(context.Background(), op, nil)
+
+	// Assert
+	assert.Nil(t, operations.stats["metrics_test.NoStart"])
+}
@@ -0,0 +1,49 @@
+package hooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+type cancelKey struct{}
+
+// timeoutHook bounds every repository operation with a deadline
+type timeoutHook struct {
+	timeout  time.Duration
+	timeouts map[string]time.Duration
+}
+
+// NewTimeoutHook creates a hook giving every repository operation at most the timeout, or the one of timeouts for the operation,
+// named after its collection and operation, like users.Dump, unless the context sets its own with ports.WithOperationTimeout, whatever the database.
+// A 0 timeout sets no deadline, and the deadline of the context, if earlier, is never extended.
+func NewTimeoutHook(timeout time.Duration, timeouts map[string]time.Duration) ports.RepositoryHook {
+	return timeoutHook{
+		timeout:  timeout,
+		timeouts: timeouts,
+	}
+}
+
+func (h timeoutHook) Before(ctx context.Context, op ports.RepositoryOperation) (context.Context, error) {
+	timeout := h.timeout
+	if t, ok := h.timeouts[op.Collection+"."+op.Name]; ok {
+		timeout = t
+	}
+	if t, ok := ports.OperationTimeoutFrom(ctx); ok {
+		timeout = t
+	}
+
+	// the cancel is always set by its own key, so the After hook of an operation nested in another one never cancels the outer one
+	if timeout <= 0 {
+		return context.WithValue(ctx, cancelKey{}, context.CancelFunc(func() {})), nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return context.WithValue(ctx, cancelKey{}, cancel), nil
+}
+
+func (timeoutHook) After(ctx context.Context, op ports.RepositoryOperation, err error) {
+	if cancel, ok := ctx.Value(cancelKey{}).(context.CancelFunc); ok {
+		cancel()
+	}
+}
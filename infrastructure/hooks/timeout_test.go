@@ -0,0 +1,98 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTimeoutHook_Default checks that the timeout hook gives the operation the default timeout, cancelling its context once it finishes
+func TestTimeoutHook_Default(t *testing.T) {
+	// Arrange
+	c := chain{NewTimeoutHook(time.Minute, nil)}
+	var opCtx context.Context
+
+	// Act
+	err := c.run(context.Background(), "users", "Get", func(ctx context.Context) error {
+		opCtx = ctx
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	deadline, ok := opCtx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	assert.Equal(t, context.Canceled, opCtx.Err())
+}
+
+// TestTimeoutHook_Operation checks that the timeout hook gives the operation the timeout configured for it instead of the default one
+func TestTimeoutHook_Operation(t *testing.T) {
+	// Arrange
+	c := chain{NewTimeoutHook(time.Minute, map[string]time.Duration{"users.Dump": time.Hour})}
+	var deadline time.Time
+
+	// Act
+	c.run(context.Background(), "users", "Dump", func(ctx context.Context) error {
+		deadline, _ = ctx.Deadline()
+		return nil
+	})
+
+	// Assert
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Second)
+}
+
+// TestTimeoutHook_Override checks that the timeout of the context overrides the configured ones, a 0 one setting no deadline
+func TestTimeoutHook_Override(t *testing.T) {
+	// Arrange
+	c := chain{NewTimeoutHook(time.Minute, map[string]time.Duration{"users.Dump": time.Hour})}
+	ctx := ports.WithOperationTimeout(context.Background(), 0)
+	var ok bool
+
+	// Act
+	c.run(ctx, "users", "Dump", func(ctx context.Context) error {
+		_, ok = ctx.Deadline()
+		return nil
+	})
+
+	// Assert
+	assert.False(t, ok)
+}
+
+// TestTimeoutHook_Expired checks that the operation fails with the deadline exceeded error once its timeout expires
+func TestTimeoutHook_Expired(t *testing.T) {
+	// Arrange
+	c := chain{NewTimeoutHook(time.Millisecond, nil)}
+
+	// Act
+	err := c.run(context.Background(), "users", "Get", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	// Assert
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+// TestTimeoutHook_Nested checks that an operation run inside another one, like in a transaction, does not cancel the context of the outer one
+// when it finishes, even when it has no deadline
+func TestTimeoutHook_Nested(t *testing.T) {
+	// Arrange
+	c := chain{NewTimeoutHook(time.Minute, map[string]time.Duration{"users.Get": 0})}
+	var outerErr error
+
+	// Act
+	c.run(context.Background(), "users", "WithTransaction", func(ctx context.Context) error {
+		c.run(ctx, "users", "Get", func(ctx context.Context) error {
+			return nil
+		})
+		outerErr = ctx.Err()
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, outerErr)
+}
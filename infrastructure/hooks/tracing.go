@@ -0,0 +1,49 @@
+package hooks
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer of the repository spans
+const tracerName = "github.com/sergicanet9/go-hexagonal-api/infrastructure/hooks"
+
+type spanKey struct{}
+
+// tracingHook starts a span for every repository operation
+type tracingHook struct {
+	tracer trace.Tracer
+}
+
+// NewTracingHook creates a hook tracing every repository operation as a span named after its collection and operation, like users.GetByID,
+// child of the span of the context, whatever the database
+func NewTracingHook(tp trace.TracerProvider) ports.RepositoryHook {
+	return tracingHook{
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (h tracingHook) Before(ctx context.Context, op ports.RepositoryOperation) (context.Context, error) {
+	ctx, span := h.tracer.Start(ctx, op.Collection+"."+op.Name, trace.WithAttributes(
+		attribute.String("db.collection", op.Collection),
+		attribute.String("db.operation", op.Name),
+	))
+	return context.WithValue(ctx, spanKey{}, span), nil
+}
+
+func (tracingHook) After(ctx context.Context, op ports.RepositoryOperation, err error) {
+	// the span is looked up by its own key, as the one of the context could be a parent one when the Before hook was not run
+	span, ok := ctx.Value(spanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
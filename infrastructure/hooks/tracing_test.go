@@ -0,0 +1,54 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestTracingHook_Ok checks that the tracing hook ends a span per operation, child of the span of the context, with the error of the operation
+func TestTracingHook_Ok(t *testing.T) {
+	// Arrange
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	parentCtx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	hook := NewTracingHook(tp)
+	op := ports.RepositoryOperation{Collection: "users", Name: "GetByID"}
+	expectedErr := errors.New("get error")
+
+	// Act
+	ctx, err := hook.Before(parentCtx, op)
+	hook.After(ctx, op, expectedErr)
+
+	// Assert
+	assert.Nil(t, err)
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "users.GetByID", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.collection", "users"))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, expectedErr.Error(), spans[0].Status().Description)
+}
+
+// TestTracingHook_NoStart checks that the tracing hook does not end the span of the context when its Before hook was not run
+func TestTracingHook_NoStart(t *testing.T) {
+	// Arrange
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, _ := tp.Tracer("test").Start(context.Background(), "parent")
+	hook := NewTracingHook(tp)
+
+	// Act
+	hook.After(ctx, ports.RepositoryOperation{Collection: "users", Name: "GetByID"}, nil)
+
+	// Assert
+	assert.Empty(t, recorder.Ended())
+}
@@ -0,0 +1,181 @@
+package hooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// userRepository decorator of an user repository that runs the hooks around every operation
+type userRepository struct {
+	repo  ports.UserRepository
+	hooks chain
+}
+
+// NewUserRepository wraps a user repository running the given hooks around every operation
+func NewUserRepository(repo ports.UserRepository, hooks ...ports.RepositoryHook) ports.UserRepository {
+	return &userRepository{
+		repo:  repo,
+		hooks: hooks,
+	}
+}
+
+func (r *userRepository) run(ctx context.Context, name string, operation func(ctx context.Context) error) error {
+	return r.hooks.run(ctx, entities.EntityNameUser, name, operation)
+}
+
+func (r *userRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.run(ctx, "WithTransaction", func(ctx context.Context) error {
+		return r.repo.WithTransaction(ctx, fn)
+	})
+}
+
+func (r *userRepository) Create(ctx context.Context, user interface{}) (id string, err error) {
+	err = r.run(ctx, "Create", func(ctx context.Context) (err error) {
+		id, err = r.repo.Create(ctx, user)
+		return err
+	})
+	return
+}
+
+func (r *userRepository) CreateMany(ctx context.Context, users []interface{}) (ids []string, err error) {
+	err = r.run(ctx, "CreateMany", func(ctx context.Context) (err error) {
+		ids, err = r.repo.CreateMany(ctx, users)
+		return err
+	})
+	return
+}
+
+func (r *userRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) (result []interface{}, err error) {
+	err = r.run(ctx, "Get", func(ctx context.Context) (err error) {
+		result, err = r.repo.Get(ctx, filter, skip, take)
+		return err
+	})
+	return
+}
+
+func (r *userRepository) GetProjected(ctx context.Context, filter map[string]interface{}, projection map[string]interface{}, skip, take *int) (result []interface{}, err error) {
+	err = r.run(ctx, "GetProjected", func(ctx context.Context) (err error) {
+		result, err = r.repo.GetProjected(ctx, filter, projection, skip, take)
+		return err
+	})
+	return
+}
+
+func (r *userRepository) GetByID(ctx context.Context, ID string) (result interface{}, err error) {
+	err = r.run(ctx, "GetByID", func(ctx context.Context) (err error) {
+		result, err = r.repo.GetByID(ctx, ID)
+		return err
+	})
+	return
+}
+
+func (r *userRepository) GetByIDProjected(ctx context.Context, ID string, projection map[string]interface{}) (result interface{}, err error) {
+	err = r.run(ctx, "GetByIDProjected", func(ctx context.Context) (err error) {
+		result, err = r.repo.GetByIDProjected(ctx, ID, projection)
+		return err
+	})
+	return
+}
+
+func (r *userRepository) Search(ctx context.Context, text string, autocomplete bool, projection map[string]interface{}, skip, take *int) (result []interface{}, err error) {
+	err = r.run(ctx, "Search", func(ctx context.Context) (err error) {
+		result, err = r.repo.Search(ctx, text, autocomplete, projection, skip, take)
+		return err
+	})
+	return
+}
+
+func (r *userRepository) GetNearby(ctx context.Context, longitude, latitude, radius float64, projection map[string]interface{}, skip, take *int) (result []interface{}, err error) {
+	err = r.run(ctx, "GetNearby", func(ctx context.Context) (err error) {
+		result, err = r.repo.GetNearby(ctx, longitude, latitude, radius, projection, skip, take)
+		return err
+	})
+	return
+}
+
+func (r *userRepository) Upsert(ctx context.Context, filter map[string]interface{}, user interface{}) (id string, err error) {
+	err = r.run(ctx, "Upsert", func(ctx context.Context) (err error) {
+		id, err = r.repo.Upsert(ctx, filter, user)
+		return err
+	})
+	return
+}
+
+func (r *userRepository) Update(ctx context.Context, ID string, user interface{}) error {
+	return r.run(ctx, "Update", func(ctx context.Context) error {
+		return r.repo.Update(ctx, ID, user)
+	})
+}
+
+func (r *userRepository) UpdateLastLogin(ctx context.Context, ID string, at time.Time) error {
+	return r.run(ctx, "UpdateLastLogin", func(ctx context.Context) error {
+		return r.repo.UpdateLastLogin(ctx, ID, at)
+	})
+}
+
+func (r *userRepository) UpdateNames(ctx context.Context, ID, name, surnames string, at time.Time) error {
+	return r.run(ctx, "UpdateNames", func(ctx context.Context) error {
+		return r.repo.UpdateNames(ctx, ID, name, surnames, at)
+	})
+}
+
+func (r *userRepository) UpdateBillingCustomer(ctx context.Context, ID, customerID string) error {
+	return r.run(ctx, "UpdateBillingCustomer", func(ctx context.Context) error {
+		return r.repo.UpdateBillingCustomer(ctx, ID, customerID)
+	})
+}
+
+func (r *userRepository) UpdateSubscription(ctx context.Context, customerID, status string, at time.Time) error {
+	return r.run(ctx, "UpdateSubscription", func(ctx context.Context) error {
+		return r.repo.UpdateSubscription(ctx, customerID, status, at)
+	})
+}
+
+func (r *userRepository) Delete(ctx context.Context, ID string) error {
+	return r.run(ctx, "Delete", func(ctx context.Context) error {
+		return r.repo.Delete(ctx, ID)
+	})
+}
+
+func (r *userRepository) Archive(ctx context.Context, inactiveSince, at time.Time) (archived int64, err error) {
+	err = r.run(ctx, "Archive", func(ctx context.Context) (err error) {
+		archived, err = r.repo.Archive(ctx, inactiveSince, at)
+		return err
+	})
+	return
+}
+
+func (r *userRepository) Stats(ctx context.Context, activeSince time.Time) (stats entities.UserStats, err error) {
+	err = r.run(ctx, "Stats", func(ctx context.Context) (err error) {
+		stats, err = r.repo.Stats(ctx, activeSince)
+		return err
+	})
+	return
+}
+
+func (r *userRepository) ArchiveByID(ctx context.Context, ID string, at time.Time) error {
+	return r.run(ctx, "ArchiveByID", func(ctx context.Context) error {
+		return r.repo.ArchiveByID(ctx, ID, at)
+	})
+}
+
+func (r *userRepository) Unarchive(ctx context.Context, ID string, at time.Time) error {
+	return r.run(ctx, "Unarchive", func(ctx context.Context) error {
+		return r.repo.Unarchive(ctx, ID, at)
+	})
+}
+
+func (r *userRepository) Dump(ctx context.Context, fn func(user entities.User) error) error {
+	return r.run(ctx, "Dump", func(ctx context.Context) error {
+		return r.repo.Dump(ctx, fn)
+	})
+}
+
+func (r *userRepository) Restore(ctx context.Context, users []entities.User) error {
+	return r.run(ctx, "Restore", func(ctx context.Context) error {
+		return r.repo.Restore(ctx, users)
+	})
+}
@@ -0,0 +1,99 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestGetByIDUser_Ok checks that GetByID runs the hooks around the wrapped repository and returns its result
+func TestGetByIDUser_Ok(t *testing.T) {
+	// Arrange
+	var calls []string
+	hook := &recorderHook{name: "hook", calls: &calls}
+	expectedUser := &entities.User{ID: "test-id"}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(expectedUser, nil).Once()
+
+	repo := NewUserRepository(userRepositoryMock, hook)
+
+	// Act
+	user, err := repo.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedUser, user)
+	assert.Equal(t, []string{"hook.Before.users.GetByID", "hook.After.users.GetByID"}, calls)
+}
+
+// TestCreateUser_Error checks that Create returns the error of the wrapped repository and passes it to the After hooks
+func TestCreateUser_Error(t *testing.T) {
+	// Arrange
+	var calls []string
+	hook := &recorderHook{name: "hook", calls: &calls}
+	expectedErr := errors.New("create error")
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), context.Background(), entities.User{}).Return("", expectedErr).Once()
+
+	repo := NewUserRepository(userRepositoryMock, hook)
+
+	// Act
+	_, err := repo.Create(context.Background(), entities.User{})
+
+	// Assert
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, expectedErr, hook.afterErr)
+}
+
+// TestUpdateLastLoginUser_BeforeError checks that UpdateLastLogin does not call the wrapped repository when a Before hook fails
+func TestUpdateLastLoginUser_BeforeError(t *testing.T) {
+	// Arrange
+	var calls []string
+	expectedErr := errors.New("before error")
+	hook := &recorderHook{name: "hook", calls: &calls, beforeErr: expectedErr}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+
+	repo := NewUserRepository(userRepositoryMock, hook)
+
+	// Act
+	err := repo.UpdateLastLogin(context.Background(), "test-id", time.Now())
+
+	// Assert
+	assert.Equal(t, expectedErr, err)
+	userRepositoryMock.AssertNotCalled(t, testutils.FunctionName(t, ports.UserRepository.UpdateLastLogin), mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestWithTransactionUser_Ok checks that WithTransaction runs the hooks around the transaction and the ones of the operations inside it
+func TestWithTransactionUser_Ok(t *testing.T) {
+	// Arrange
+	var calls []string
+	hook := &recorderHook{name: "hook", calls: &calls}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.WithTransaction), context.Background(), mock.Anything).Return(
+		func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) },
+	).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), context.Background(), "test-id").Return(nil).Once()
+
+	repo := NewUserRepository(userRepositoryMock, hook)
+
+	// Act
+	err := repo.WithTransaction(context.Background(), func(ctx context.Context) error {
+		return repo.Delete(ctx, "test-id")
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"hook.Before.users.WithTransaction", "hook.Before.users.Delete", "hook.After.users.Delete", "hook.After.users.WithTransaction"}, calls)
+}
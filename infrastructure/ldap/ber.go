@@ -0,0 +1,148 @@
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// BER tags of the universal types of the LDAP messages
+const (
+	berBoolean     = 0x01
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berEnumerated  = 0x0a
+	berSequence    = 0x30
+	berSet         = 0x31
+)
+
+// berMaxSize is the size of the largest element read from a server
+const berMaxSize = 16 << 20
+
+// element a BER element, its tag holding its class and whether it is constructed
+type element struct {
+	tag     byte
+	content []byte
+}
+
+// encode returns the element of the tag with the given content, concatenated
+func encode(tag byte, content ...[]byte) []byte {
+	var size int
+	for _, c := range content {
+		size += len(c)
+	}
+	out := append([]byte{tag}, encodeLength(size)...)
+	for _, c := range content {
+		out = append(out, c...)
+	}
+	return out
+}
+
+// encodeLength returns the length of a content, in the short form below 128 bytes and in the long one otherwise
+func encodeLength(size int) []byte {
+	if size < 0x80 {
+		return []byte{byte(size)}
+	}
+	var digits []byte
+	for n := size; n > 0; n >>= 8 {
+		digits = append([]byte{byte(n)}, digits...)
+	}
+	return append([]byte{0x80 | byte(len(digits))}, digits...)
+}
+
+// encodeInt returns the element of the tag with the integer in its shortest two's complement form
+func encodeInt(tag byte, n int64) []byte {
+	content := []byte{byte(n)}
+	for (n > 0x7f || n < -0x80) && len(content) < 8 {
+		n >>= 8
+		content = append([]byte{byte(n)}, content...)
+	}
+	return encode(tag, content)
+}
+
+// encodeString returns the element of the tag with the string as content
+func encodeString(tag byte, s string) []byte {
+	return encode(tag, []byte(s))
+}
+
+// encodeBool returns a BOOLEAN element
+func encodeBool(b bool) []byte {
+	if b {
+		return encode(berBoolean, []byte{0xff})
+	}
+	return encode(berBoolean, []byte{0x00})
+}
+
+// readElement reads the next element, its tag being a single byte as are the ones of LDAP
+func readElement(r *bufio.Reader) (element, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return element{}, err
+	}
+	size, err := readLength(r)
+	if err != nil {
+		return element{}, err
+	}
+	content := make([]byte, size)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return element{}, err
+	}
+	return element{tag: tag, content: content}, nil
+}
+
+// readLength reads the length of the content of an element, in its short or its long definite form
+func readLength(r *bufio.Reader) (int, error) {
+	first, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if first < 0x80 {
+		return int(first), nil
+	}
+	digits := int(first & 0x7f)
+	if digits == 0 || digits > 4 {
+		return 0, fmt.Errorf("ber length form %#x not supported", first)
+	}
+	var size int
+	for i := 0; i < digits; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		size = size<<8 | int(b)
+	}
+	if size > berMaxSize {
+		return 0, fmt.Errorf("ber length %d not valid", size)
+	}
+	return size, nil
+}
+
+// children returns the elements of the content of a constructed element
+func (e element) children() ([]element, error) {
+	var elements []element
+	r := bufio.NewReader(bytes.NewReader(e.content))
+	for {
+		child, err := readElement(r)
+		if errors.Is(err, io.EOF) {
+			return elements, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ber element %#x not valid: %w", e.tag, err)
+		}
+		elements = append(elements, child)
+	}
+}
+
+// int returns the content of an INTEGER or ENUMERATED element
+func (e element) int() int64 {
+	if len(e.content) == 0 {
+		return 0
+	}
+	n := int64(int8(e.content[0]))
+	for _, b := range e.content[1:] {
+		n = n<<8 | int64(b)
+	}
+	return n
+}
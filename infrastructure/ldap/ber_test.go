@@ -0,0 +1,53 @@
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEncode_BindRequest checks that a simple bind request is encoded as the LDAP messages are
+func TestEncode_BindRequest(t *testing.T) {
+	// Arrange
+	expected := append([]byte{0x30, 0x1a, 0x02, 0x01, 0x01, 0x60, 0x15, 0x02, 0x01, 0x03, 0x04, 0x08}, "cn=admin"...)
+	expected = append(append(expected, 0x80, 0x06), "secret"...)
+
+	// Act
+	msg := encode(berSequence, encodeInt(berInteger, 1), encode(opBindRequest, encodeInt(berInteger, 3), encodeString(berOctetString, "cn=admin"), encodeString(authSimple, "secret")))
+
+	// Assert
+	assert.Equal(t, expected, msg)
+}
+
+// TestReadElement_LongLength checks that readElement reads the elements whose length is in the long form, as encode writes them
+func TestReadElement_LongLength(t *testing.T) {
+	// Arrange
+	value := strings.Repeat("a", 300)
+	encoded := encodeString(berOctetString, value)
+
+	// Act
+	e, err := readElement(bufio.NewReader(bytes.NewReader(encoded)))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x04, 0x82, 0x01, 0x2c}, encoded[:4])
+	assert.Equal(t, value, string(e.content))
+}
+
+// TestEncodeInt_Ok checks that encodeInt encodes the integers in their shortest two's complement form, as int decodes them
+func TestEncodeInt_Ok(t *testing.T) {
+	// Act
+	positive, positiveErr := readElement(bufio.NewReader(bytes.NewReader(encodeInt(berInteger, 128))))
+	negative, negativeErr := readElement(bufio.NewReader(bytes.NewReader(encodeInt(berInteger, -129))))
+
+	// Assert
+	assert.Nil(t, positiveErr)
+	assert.Nil(t, negativeErr)
+	assert.Equal(t, []byte{0x00, 0x80}, positive.content)
+	assert.Equal(t, int64(128), positive.int())
+	assert.Equal(t, []byte{0xff, 0x7f}, negative.content)
+	assert.Equal(t, int64(-129), negative.int())
+}
@@ -0,0 +1,451 @@
+package ldap
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// tags of the LDAP operations
+const (
+	opBindRequest       = 0x60
+	opBindResponse      = 0x61
+	opUnbindRequest     = 0x42
+	opSearchRequest     = 0x63
+	opSearchEntry       = 0x64
+	opSearchDone        = 0x65
+	opSearchReference   = 0x73
+	opExtendedRequest   = 0x77
+	opExtendedResponse  = 0x78
+	authSimple          = 0x80
+	filterAnd           = 0xa0
+	filterEquality      = 0xa3
+	filterPresent       = 0x87
+	extendedRequestName = 0x80
+)
+
+// result codes of the LDAP operations
+const (
+	resultSuccess            = 0
+	resultInvalidCredentials = 49
+)
+
+// startTLSOID is the name of the extended operation starting TLS on the connection
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// default attributes of the directory entries of the users
+const (
+	defaultUserAttribute     = "mail"
+	defaultNameAttribute     = "givenName"
+	defaultSurnamesAttribute = "sn"
+)
+
+// Directory settings of an LDAP or Active Directory server verifying the passwords of the users of the email Domains of its tenant
+type Directory struct {
+	Domains           []string
+	URL               string
+	StartTLS          bool
+	BindDN            string
+	BindPassword      string
+	BaseDN            string
+	UserAttribute     string
+	ObjectClass       string
+	NameAttribute     string
+	SurnamesAttribute string
+}
+
+// resultError a result of an LDAP operation other than success
+type resultError struct {
+	code    int64
+	message string
+}
+
+func (e *resultError) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf("ldap result code %d", e.code)
+	}
+	return fmt.Sprintf("ldap result code %d: %s", e.code, e.message)
+}
+
+// directory adapter of the credential verifier of a tenant, binding to its server as the user of the email with the password.
+// The entry of the user is searched under BaseDN with its UserAttribute, mail or userPrincipalName for Active Directory, and ObjectClass when set,
+// bound as BindDN, or anonymously when empty, as the DN of the user is not the email.
+type directory struct {
+	Directory
+	url    *url.URL
+	dialer net.Dialer
+}
+
+// verifier adapter of a credential verifier routing the emails to the directory of the tenant of their domain
+type verifier struct {
+	domains map[string]*directory
+	timeout time.Duration
+}
+
+// NewVerifier creates a credential verifier of the given directories, each one verifying the passwords of the emails of its domains,
+// every verification given at most the timeout
+func NewVerifier(directories []Directory, timeout time.Duration) (ports.CredentialVerifier, error) {
+	v := &verifier{domains: map[string]*directory{}, timeout: timeout}
+	for _, d := range directories {
+		dir, err := newDirectory(d, timeout)
+		if err != nil {
+			return nil, err
+		}
+		for _, domain := range d.Domains {
+			v.domains[strings.ToLower(domain)] = dir
+		}
+	}
+	return v, nil
+}
+
+// newDirectory creates the adapter of the directory, defaulting its attributes, its connections opened within the timeout
+func newDirectory(d Directory, timeout time.Duration) (*directory, error) {
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return nil, fmt.Errorf("ldap url not valid: %w", err)
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return nil, fmt.Errorf("ldap url scheme %s not valid", u.Scheme)
+	}
+	if d.UserAttribute == "" {
+		d.UserAttribute = defaultUserAttribute
+	}
+	if d.NameAttribute == "" {
+		d.NameAttribute = defaultNameAttribute
+	}
+	if d.SurnamesAttribute == "" {
+		d.SurnamesAttribute = defaultSurnamesAttribute
+	}
+	return &directory{Directory: d, url: u, dialer: net.Dialer{Timeout: timeout}}, nil
+}
+
+// Handles reports whether the domain of the email belongs to a directory
+func (v *verifier) Handles(email string) bool {
+	_, ok := v.domains[domainOf(email)]
+	return ok
+}
+
+// Verify checks the password of the email against the directory of its domain
+func (v *verifier) Verify(ctx context.Context, email, password string) (ports.DirectoryUser, error) {
+	d, ok := v.domains[domainOf(email)]
+	if !ok {
+		return ports.DirectoryUser{}, fmt.Errorf("directory of %s not found", email)
+	}
+	// an empty password makes an unauthenticated bind, which the servers accept for any DN
+	if password == "" {
+		return ports.DirectoryUser{}, ports.ErrDirectoryCredentialsNotValid
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+	user, err := d.verify(ctx, strings.TrimSpace(email), password)
+	if err != nil && !errors.Is(err, ports.ErrDirectoryCredentialsNotValid) {
+		return user, fmt.Errorf("ldap server %s: %w", d.url.Host, err)
+	}
+	return user, err
+}
+
+// domainOf returns the lower-cased domain of the email
+func domainOf(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+// verify searches the entry of the email and binds as its DN with the password
+func (d *directory) verify(ctx context.Context, email, password string) (ports.DirectoryUser, error) {
+	c, err := d.connect(ctx)
+	if err != nil {
+		return ports.DirectoryUser{}, err
+	}
+	defer c.close()
+
+	if d.BindDN != "" {
+		if err := c.bind(d.BindDN, d.BindPassword); err != nil {
+			return ports.DirectoryUser{}, fmt.Errorf("bind as %s: %w", d.BindDN, err)
+		}
+	}
+
+	entries, err := c.search(d.BaseDN, d.filter(email), []string{d.NameAttribute, d.SurnamesAttribute}, 2)
+	if err != nil {
+		return ports.DirectoryUser{}, err
+	}
+	switch len(entries) {
+	case 0:
+		return ports.DirectoryUser{}, ports.ErrDirectoryCredentialsNotValid
+	case 1:
+	default:
+		return ports.DirectoryUser{}, fmt.Errorf("%d entries found for %s", len(entries), email)
+	}
+
+	err = c.bind(entries[0].dn, password)
+	var result *resultError
+	if errors.As(err, &result) && result.code == resultInvalidCredentials {
+		return ports.DirectoryUser{}, ports.ErrDirectoryCredentialsNotValid
+	}
+	if err != nil {
+		return ports.DirectoryUser{}, err
+	}
+	return ports.DirectoryUser{
+		Name:     entries[0].first(d.NameAttribute),
+		Surnames: entries[0].first(d.SurnamesAttribute),
+	}, nil
+}
+
+// filter returns the filter of the entry of the email, its value being encoded as is, so it needs no escaping
+func (d *directory) filter(email string) []byte {
+	return d.withObjectClass(encode(filterEquality, encodeString(berOctetString, d.UserAttribute), encodeString(berOctetString, email)))
+}
+
+// withObjectClass returns the filter ANDed with the equality of the ObjectClass, when set
+func (d *directory) withObjectClass(filter []byte) []byte {
+	if d.ObjectClass == "" {
+		return filter
+	}
+	return encode(filterAnd, encode(filterEquality, encodeString(berOctetString, "objectClass"), encodeString(berOctetString, d.ObjectClass)), filter)
+}
+
+// connect opens a connection to the server, with TLS when the scheme is ldaps or after starting it when StartTLS is set
+func (d *directory) connect(ctx context.Context) (*conn, error) {
+	address := d.url.Host
+	if d.url.Port() == "" {
+		port := "389"
+		if d.url.Scheme == "ldaps" {
+			port = "636"
+		}
+		address = net.JoinHostPort(d.url.Hostname(), port)
+	}
+	netConn, err := d.dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		netConn.SetDeadline(deadline)
+	}
+
+	tlsConfig := &tls.Config{ServerName: d.url.Hostname(), MinVersion: tls.VersionTLS12}
+	if d.url.Scheme == "ldaps" {
+		tlsConn := tls.Client(netConn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+		netConn = tlsConn
+	}
+
+	c := newConn(netConn)
+	if d.StartTLS && d.url.Scheme == "ldap" {
+		if err := c.startTLS(ctx, tlsConfig); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("start tls: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// conn a connection to an LDAP server, sending one operation at a time
+type conn struct {
+	net.Conn
+	reader    *bufio.Reader
+	messageID int64
+}
+
+func newConn(c net.Conn) *conn {
+	return &conn{Conn: c, reader: bufio.NewReader(c)}
+}
+
+// send writes the operation in a new message
+func (c *conn) send(op []byte) error {
+	c.messageID++
+	_, err := c.Write(encode(berSequence, encodeInt(berInteger, c.messageID), op))
+	return err
+}
+
+// receive reads the operation of the next message answering the last one sent
+func (c *conn) receive() (element, error) {
+	for {
+		msg, err := readElement(c.reader)
+		if err != nil {
+			return element{}, err
+		}
+		parts, err := msg.children()
+		if err != nil {
+			return element{}, err
+		}
+		if msg.tag != berSequence || len(parts) < 2 {
+			return element{}, errors.New("ldap message not valid")
+		}
+		// the unsolicited notifications, like the notice of disconnection, have the ID 0
+		if id := parts[0].int(); id == 0 {
+			return element{}, fmt.Errorf("ldap notice of disconnection: %v", parseResult(parts[1]))
+		} else if id != c.messageID {
+			continue
+		}
+		return parts[1], nil
+	}
+}
+
+// bind authenticates the connection as the DN with the simple password
+func (c *conn) bind(dn, password string) error {
+	err := c.send(encode(opBindRequest, encodeInt(berInteger, 3), encodeString(berOctetString, dn), encodeString(authSimple, password)))
+	if err != nil {
+		return err
+	}
+	op, err := c.receive()
+	if err != nil {
+		return err
+	}
+	if op.tag != opBindResponse {
+		return fmt.Errorf("ldap operation %#x not expected", op.tag)
+	}
+	return parseResult(op)
+}
+
+// startTLS asks the server to start TLS and makes the handshake on the connection
+func (c *conn) startTLS(ctx context.Context, config *tls.Config) error {
+	if err := c.send(encode(opExtendedRequest, encodeString(extendedRequestName, startTLSOID))); err != nil {
+		return err
+	}
+	op, err := c.receive()
+	if err != nil {
+		return err
+	}
+	if op.tag != opExtendedResponse {
+		return fmt.Errorf("ldap operation %#x not expected", op.tag)
+	}
+	if err := parseResult(op); err != nil {
+		return err
+	}
+
+	tlsConn := tls.Client(c.Conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return err
+	}
+	c.Conn, c.reader = tlsConn, bufio.NewReader(tlsConn)
+	return nil
+}
+
+// entry a directory entry found by a search
+type entry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+// first returns the first value of the attribute, matched regardless of its case
+func (e entry) first(attribute string) string {
+	for name, values := range e.attributes {
+		if strings.EqualFold(name, attribute) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// search returns the entries of the whole subtree of the base DN matching the filter, with the given attributes,
+// at most sizeLimit or, when 0, as many as the server returns, failing when the server limits them
+func (c *conn) search(baseDN string, filter []byte, attributes []string, sizeLimit int64) ([]entry, error) {
+	var selection [][]byte
+	for _, attribute := range attributes {
+		selection = append(selection, encodeString(berOctetString, attribute))
+	}
+	err := c.send(encode(opSearchRequest,
+		encodeString(berOctetString, baseDN),
+		encodeInt(berEnumerated, 2),      // wholeSubtree
+		encodeInt(berEnumerated, 0),      // neverDerefAliases
+		encodeInt(berInteger, sizeLimit), // sizeLimit
+		encodeInt(berInteger, 0),         // timeLimit
+		encodeBool(false),                // typesOnly
+		filter,
+		encode(berSequence, selection...),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entry
+	for {
+		op, err := c.receive()
+		if err != nil {
+			return nil, err
+		}
+		switch op.tag {
+		case opSearchEntry:
+			e, err := parseEntry(op)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, e)
+		case opSearchReference:
+		case opSearchDone:
+			return entries, parseResult(op)
+		default:
+			return nil, fmt.Errorf("ldap operation %#x not expected", op.tag)
+		}
+	}
+}
+
+// parseEntry returns the entry of a search result entry
+func parseEntry(op element) (entry, error) {
+	parts, err := op.children()
+	if err != nil {
+		return entry{}, err
+	}
+	if len(parts) < 2 {
+		return entry{}, errors.New("ldap search entry not valid")
+	}
+	attributes, err := parts[1].children()
+	if err != nil {
+		return entry{}, err
+	}
+
+	e := entry{dn: string(parts[0].content), attributes: map[string][]string{}}
+	for _, attribute := range attributes {
+		fields, err := attribute.children()
+		if err != nil {
+			return entry{}, err
+		}
+		if len(fields) < 2 {
+			return entry{}, errors.New("ldap search entry attribute not valid")
+		}
+		values, err := fields[1].children()
+		if err != nil {
+			return entry{}, err
+		}
+		name := string(fields[0].content)
+		for _, value := range values {
+			e.attributes[name] = append(e.attributes[name], string(value.content))
+		}
+	}
+	return e, nil
+}
+
+// parseResult returns the error of the result of an operation, nil when it succeeded
+func parseResult(op element) error {
+	parts, err := op.children()
+	if err != nil {
+		return err
+	}
+	if len(parts) < 3 {
+		return errors.New("ldap result not valid")
+	}
+	if code := parts[0].int(); code != resultSuccess {
+		return &resultError{code: code, message: string(parts[2].content)}
+	}
+	return nil
+}
+
+// close unbinds and closes the connection
+func (c *conn) close() error {
+	c.send(encode(opUnbindRequest))
+	return c.Conn.Close()
+}
@@ -0,0 +1,199 @@
+package ldap
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDirectory server with the entries of the given passwords by DN and of the given emails by DN, recording the DNs bound as
+type fakeDirectory struct {
+	listener  net.Listener
+	passwords map[string]string
+	emails    map[string]string
+	binds     chan string
+}
+
+func newFakeDirectory(t *testing.T, passwords, emails map[string]string) *fakeDirectory {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	f := &fakeDirectory{listener: listener, passwords: passwords, emails: emails, binds: make(chan string, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+// serve answers the binds and the searches of the equality or the presence of the mail attribute, ANDed or not with an object class
+func (f *fakeDirectory) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		msg, err := readElement(r)
+		if err != nil {
+			return
+		}
+		parts, _ := msg.children()
+		id := encodeInt(berInteger, parts[0].int())
+		op := parts[1]
+		fields, _ := op.children()
+
+		switch op.tag {
+		case opBindRequest:
+			dn, password := string(fields[1].content), string(fields[2].content)
+			f.binds <- dn
+			code := int64(resultSuccess)
+			if stored, ok := f.passwords[dn]; !ok || stored != password {
+				code = resultInvalidCredentials
+			}
+			conn.Write(encode(berSequence, id, encode(opBindResponse, encodeInt(berEnumerated, code), encodeString(berOctetString, ""), encodeString(berOctetString, ""))))
+		case opSearchRequest:
+			filter := fields[6]
+			if filter.tag == filterAnd {
+				filters, _ := filter.children()
+				filter = filters[1]
+			}
+			var email string
+			if filter.tag != filterPresent {
+				assertion, _ := filter.children()
+				email = string(assertion[1].content)
+			}
+			for dn, e := range f.emails {
+				if filter.tag == filterPresent || e == email {
+					attributes := encode(berSequence,
+						encode(berSequence, encodeString(berOctetString, "mail"), encode(berSet, encodeString(berOctetString, e))),
+						encode(berSequence, encodeString(berOctetString, "givenName"), encode(berSet, encodeString(berOctetString, "Test"))),
+						encode(berSequence, encodeString(berOctetString, "sn"), encode(berSet, encodeString(berOctetString, "User"))),
+					)
+					conn.Write(encode(berSequence, id, encode(opSearchEntry, encodeString(berOctetString, dn), attributes)))
+				}
+			}
+			conn.Write(encode(berSequence, id, encode(opSearchDone, encodeInt(berEnumerated, resultSuccess), encodeString(berOctetString, ""), encodeString(berOctetString, ""))))
+		case opUnbindRequest:
+			return
+		}
+	}
+}
+
+// newTestVerifier returns a verifier of the fake directory for the example.com domain, binding as the service account
+func newTestVerifier(t *testing.T, f *fakeDirectory) ports.CredentialVerifier {
+	v, err := NewVerifier([]Directory{{
+		Domains:      []string{"Example.com"},
+		URL:          "ldap://" + f.listener.Addr().String(),
+		BindDN:       "cn=service,dc=example,dc=com",
+		BindPassword: "service-password",
+		BaseDN:       "dc=example,dc=com",
+		ObjectClass:  "person",
+	}}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// TestVerify_Ok checks that Verify binds as the service account, then as the DN of the entry of the email with the password, returning its attributes
+func TestVerify_Ok(t *testing.T) {
+	// Arrange
+	f := newFakeDirectory(t,
+		map[string]string{"cn=service,dc=example,dc=com": "service-password", "uid=test,dc=example,dc=com": "test-password"},
+		map[string]string{"uid=test,dc=example,dc=com": "test@example.com"})
+	v := newTestVerifier(t, f)
+
+	// Act
+	user, err := v.Verify(context.Background(), "test@example.com", "test-password")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, ports.DirectoryUser{Name: "Test", Surnames: "User"}, user)
+	assert.Equal(t, "cn=service,dc=example,dc=com", <-f.binds)
+	assert.Equal(t, "uid=test,dc=example,dc=com", <-f.binds)
+}
+
+// TestVerify_PasswordIncorrect checks that Verify returns ErrDirectoryCredentialsNotValid when the directory refuses the password of the user
+func TestVerify_PasswordIncorrect(t *testing.T) {
+	// Arrange
+	f := newFakeDirectory(t,
+		map[string]string{"cn=service,dc=example,dc=com": "service-password", "uid=test,dc=example,dc=com": "test-password"},
+		map[string]string{"uid=test,dc=example,dc=com": "test@example.com"})
+	v := newTestVerifier(t, f)
+
+	// Act
+	_, err := v.Verify(context.Background(), "test@example.com", "wrong-password")
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrDirectoryCredentialsNotValid)
+}
+
+// TestVerify_UserNotFound checks that Verify returns ErrDirectoryCredentialsNotValid when the directory has no entry of the email
+func TestVerify_UserNotFound(t *testing.T) {
+	// Arrange
+	f := newFakeDirectory(t, map[string]string{"cn=service,dc=example,dc=com": "service-password"}, nil)
+	v := newTestVerifier(t, f)
+
+	// Act
+	_, err := v.Verify(context.Background(), "test@example.com", "test-password")
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrDirectoryCredentialsNotValid)
+}
+
+// TestVerify_ServiceBindFailed checks that Verify returns an error other than ErrDirectoryCredentialsNotValid when the service account cannot bind,
+// so the users are not told their password is incorrect
+func TestVerify_ServiceBindFailed(t *testing.T) {
+	// Arrange
+	f := newFakeDirectory(t, map[string]string{}, nil)
+	v := newTestVerifier(t, f)
+
+	// Act
+	_, err := v.Verify(context.Background(), "test@example.com", "test-password")
+
+	// Assert
+	assert.NotErrorIs(t, err, ports.ErrDirectoryCredentialsNotValid)
+	assert.ErrorContains(t, err, "bind as cn=service,dc=example,dc=com: ldap result code 49")
+}
+
+// TestVerify_EmptyPassword checks that Verify refuses an empty password without binding, as it would make an unauthenticated bind
+func TestVerify_EmptyPassword(t *testing.T) {
+	// Arrange
+	f := newFakeDirectory(t, nil, nil)
+	v := newTestVerifier(t, f)
+
+	// Act
+	_, err := v.Verify(context.Background(), "test@example.com", "")
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrDirectoryCredentialsNotValid)
+	assert.Empty(t, f.binds)
+}
+
+// TestHandles_Ok checks that Handles reports the emails of the domains of the directories, regardless of their case
+func TestHandles_Ok(t *testing.T) {
+	// Arrange
+	v, err := NewVerifier([]Directory{{Domains: []string{"example.com"}, URL: "ldaps://ldap.example.com"}}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	handled := v.Handles("Test@EXAMPLE.com")
+	notHandled := v.Handles("test@example.org")
+
+	// Assert
+	assert.True(t, handled)
+	assert.False(t, notHandled)
+}
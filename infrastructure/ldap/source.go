@@ -0,0 +1,81 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// source adapter of a directory source listing the users of every directory
+type source struct {
+	directories []*directory
+	timeout     time.Duration
+}
+
+// NewSource creates a directory source listing the entries of the given directories having an email in their UserAttribute,
+// the listing of each directory given at most the timeout
+func NewSource(directories []Directory, timeout time.Duration) (ports.DirectorySource, error) {
+	s := &source{timeout: timeout}
+	for _, d := range directories {
+		dir, err := newDirectory(d, timeout)
+		if err != nil {
+			return nil, err
+		}
+		s.directories = append(s.directories, dir)
+	}
+	return s, nil
+}
+
+// Users lists the users of every directory, failing when any of them cannot be listed in full
+func (s *source) Users(ctx context.Context) ([]ports.DirectoryEntry, error) {
+	var users []ports.DirectoryEntry
+	for _, d := range s.directories {
+		entries, err := s.list(ctx, d)
+		if err != nil {
+			return nil, fmt.Errorf("ldap server %s: %w", d.url.Host, err)
+		}
+		users = append(users, entries...)
+	}
+	return users, nil
+}
+
+// list returns the users of the directory, the entries without a value in UserAttribute being left out
+func (s *source) list(ctx context.Context, d *directory) ([]ports.DirectoryEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	c, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	if d.BindDN != "" {
+		if err := c.bind(d.BindDN, d.BindPassword); err != nil {
+			return nil, fmt.Errorf("bind as %s: %w", d.BindDN, err)
+		}
+	}
+
+	filter := d.withObjectClass(encodeString(filterPresent, d.UserAttribute))
+	entries, err := c.search(d.BaseDN, filter, []string{d.UserAttribute, d.NameAttribute, d.SurnamesAttribute}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]ports.DirectoryEntry, 0, len(entries))
+	for _, e := range entries {
+		email := strings.TrimSpace(e.first(d.UserAttribute))
+		if email == "" {
+			continue
+		}
+		users = append(users, ports.DirectoryEntry{
+			Email:    email,
+			Name:     e.first(d.NameAttribute),
+			Surnames: e.first(d.SurnamesAttribute),
+		})
+	}
+	return users, nil
+}
@@ -0,0 +1,63 @@
+package ldap
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUsers_Ok checks that Users binds as the service account and lists the entries having an email
+func TestUsers_Ok(t *testing.T) {
+	// Arrange
+	f := newFakeDirectory(t, map[string]string{"cn=service,dc=example,dc=com": "service-password"},
+		map[string]string{"uid=first,dc=example,dc=com": "first@example.com", "uid=second,dc=example,dc=com": "second@example.com"})
+	s, err := NewSource([]Directory{{
+		Domains:      []string{"example.com"},
+		URL:          "ldap://" + f.listener.Addr().String(),
+		BindDN:       "cn=service,dc=example,dc=com",
+		BindPassword: "service-password",
+		BaseDN:       "dc=example,dc=com",
+		ObjectClass:  "person",
+	}}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	users, err := s.Users(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	sort.Slice(users, func(i, j int) bool { return users[i].Email < users[j].Email })
+	assert.Equal(t, []ports.DirectoryEntry{
+		{Email: "first@example.com", Name: "Test", Surnames: "User"},
+		{Email: "second@example.com", Name: "Test", Surnames: "User"},
+	}, users)
+	assert.Equal(t, "cn=service,dc=example,dc=com", <-f.binds)
+}
+
+// TestUsers_ServiceBindFailed checks that Users returns an error when the service account cannot bind, instead of an empty list
+func TestUsers_ServiceBindFailed(t *testing.T) {
+	// Arrange
+	f := newFakeDirectory(t, map[string]string{}, map[string]string{"uid=first,dc=example,dc=com": "first@example.com"})
+	s, err := NewSource([]Directory{{
+		URL:          "ldap://" + f.listener.Addr().String(),
+		BindDN:       "cn=service,dc=example,dc=com",
+		BindPassword: "wrong-password",
+		BaseDN:       "dc=example,dc=com",
+	}}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	users, err := s.Users(context.Background())
+
+	// Assert
+	assert.Nil(t, users)
+	assert.Contains(t, err.Error(), "bind as cn=service,dc=example,dc=com: ldap result code 49")
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// errFileNotFound is the error of the non existent errors of the storage
+var errFileNotFound = errors.New("file not found")
+
+// storedFile a file along with its content
+type storedFile struct {
+	file    entities.File
+	content []byte
+}
+
+// fileStorage adapter of a file storage kept in memory, for the unit tests and the development without a database
+type fileStorage struct {
+	mu    sync.RWMutex
+	files map[string]storedFile
+}
+
+// NewFileStorage creates a file storage kept in memory, empty
+func NewFileStorage() ports.FileStorage {
+	return &fileStorage{
+		files: map[string]storedFile{},
+	}
+}
+
+// Store reads the whole content before replacing the file with the same key, so a failed read leaves it as it was
+func (s *fileStorage) Store(ctx context.Context, file entities.File, content io.Reader) (entities.File, error) {
+	b, err := io.ReadAll(content)
+	if err != nil {
+		return entities.File{}, err
+	}
+
+	file.Size = int64(len(b))
+	file.CreatedAt = time.Now().UTC()
+	file.Metadata = copyMetadata(file.Metadata)
+
+	s.mu.Lock()
+	s.files[file.Key] = storedFile{file: file, content: b}
+	s.mu.Unlock()
+
+	file.Metadata = copyMetadata(file.Metadata)
+	return file, nil
+}
+
+func (s *fileStorage) Open(ctx context.Context, key string) (io.ReadCloser, entities.File, error) {
+	s.mu.RLock()
+	stored, ok := s.files[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, entities.File{}, wrappers.NewNonExistentErr(errFileNotFound)
+	}
+
+	// the content of a stored file is never changed, a new one replacing it
+	file := stored.file
+	file.Metadata = copyMetadata(file.Metadata)
+	return io.NopCloser(bytes.NewReader(stored.content)), file, nil
+}
+
+func (s *fileStorage) Stat(ctx context.Context, key string) (entities.File, error) {
+	s.mu.RLock()
+	stored, ok := s.files[key]
+	s.mu.RUnlock()
+	if !ok {
+		return entities.File{}, wrappers.NewNonExistentErr(errFileNotFound)
+	}
+
+	file := stored.file
+	file.Metadata = copyMetadata(file.Metadata)
+	return file, nil
+}
+
+func (s *fileStorage) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.files[key]; !ok {
+		return wrappers.NewNonExistentErr(errFileNotFound)
+	}
+	delete(s.files, key)
+	return nil
+}
+
+// copyMetadata returns a copy of the metadata, so the stored files are not changed through the files returned
+func copyMetadata(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStore_Replaces checks that Store replaces the file with the same key, opened with its content
+func TestStore_Replaces(t *testing.T) {
+	// Arrange
+	storage := NewFileStorage()
+	file := entities.File{Key: "avatars/test", Name: "avatar.png", ContentType: "image/png"}
+	if _, err := storage.Store(context.Background(), file, strings.NewReader("old")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	stored, err := storage.Store(context.Background(), file, strings.NewReader("new content"))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(11), stored.Size)
+	content, opened, err := storage.Open(context.Background(), file.Key)
+	assert.Nil(t, err)
+	defer content.Close()
+	b, err := io.ReadAll(content)
+	assert.Nil(t, err)
+	assert.Equal(t, "new content", string(b))
+	assert.Equal(t, stored, opened)
+}
+
+// TestStat_NotFound checks that Stat returns a non existent error when the file is not found
+func TestStat_NotFound(t *testing.T) {
+	// Arrange
+	storage := NewFileStorage()
+
+	// Act
+	_, err := storage.Stat(context.Background(), "test")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestDelete_Ok checks that Delete removes the file
+func TestDelete_Ok(t *testing.T) {
+	// Arrange
+	storage := NewFileStorage()
+	if _, err := storage.Store(context.Background(), entities.File{Key: "test"}, strings.NewReader("content")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err := storage.Delete(context.Background(), "test")
+
+	// Assert
+	assert.Nil(t, err)
+	_, _, err = storage.Open(context.Background(), "test")
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
@@ -0,0 +1,629 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// earthRadius is the mean radius of the Earth in meters, the same one used by mongo for spherical queries
+const earthRadius = 6378100.0
+
+// errUserNotFound is the error of the non existent errors of the repository
+var errUserNotFound = errors.New("user not found")
+
+// txKey is the context key of the ongoing transaction
+type txKey struct{}
+
+// archivedUser a user moved to the archive
+type archivedUser struct {
+	user       entities.User
+	archivedAt time.Time
+}
+
+// userRepository adapter of a user repository kept in memory, for the unit tests and the development without a database.
+// It follows the semantics of the mongo adapter: the users are kept in the order they were created, the emails are unique ignoring case,
+// the filters and the projections name the fields as their bson tags, and the reads finding no users fail with a non existent error.
+type userRepository struct {
+	mu      sync.RWMutex
+	users   []entities.User
+	archive []archivedUser
+	// tx serializes the transactions, so each one only sees its own changes
+	tx sync.Mutex
+}
+
+// NewUserRepository creates a user repository kept in memory, empty
+func NewUserRepository() ports.UserRepository {
+	return &userRepository{}
+}
+
+func (r *userRepository) Create(ctx context.Context, user interface{}) (string, error) {
+	u := clone(user.(entities.User))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.emailTaken(u.Email, "") {
+		return "", apierror.Conflict(fmt.Errorf("email %s already in use", u.Email))
+	}
+	u.ID = primitive.NewObjectID().Hex()
+	r.users = append(r.users, u)
+	return u.ID, nil
+}
+
+func (r *userRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	return r.GetProjected(ctx, filter, nil, skip, take)
+}
+
+func (r *userRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	return r.GetByIDProjected(ctx, ID, nil)
+}
+
+// GetProjected gets the users matching the filter, returning only the fields allowed by the projection
+func (r *userRepository) GetProjected(ctx context.Context, filter map[string]interface{}, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	r.mu.RLock()
+	var matched []entities.User
+	for _, u := range r.users {
+		ok, err := matches(u, filter)
+		if err != nil {
+			r.mu.RUnlock()
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, u)
+		}
+	}
+	r.mu.RUnlock()
+
+	return project(paginate(matched, skip, take), projection)
+}
+
+// GetByIDProjected gets the user with the specified ID, returning only the fields allowed by the projection
+func (r *userRepository) GetByIDProjected(ctx context.Context, ID string, projection map[string]interface{}) (interface{}, error) {
+	r.mu.RLock()
+	i := r.index(ID)
+	if i < 0 {
+		r.mu.RUnlock()
+		return nil, wrappers.NewNonExistentErr(errUserNotFound)
+	}
+	u := r.users[i]
+	r.mu.RUnlock()
+
+	users, err := project([]entities.User{u}, projection)
+	if err != nil {
+		return nil, err
+	}
+	return users[0], nil
+}
+
+// Update updates the user with the specified ID. As the $set of the mongo adapter, the fields omitted when empty,
+// like the location or the last login date, are kept when not set.
+func (r *userRepository) Update(ctx context.Context, ID string, user interface{}) error {
+	u := clone(user.(entities.User))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(ID)
+	if i < 0 {
+		return wrappers.NewNonExistentErr(errUserNotFound)
+	}
+	if r.emailTaken(u.Email, ID) {
+		return apierror.Conflict(fmt.Errorf("email %s already in use", u.Email))
+	}
+
+	current := &r.users[i]
+	current.Name, current.Surnames, current.Email = u.Name, u.Surnames, u.Email
+	current.PasswordHash, current.Claims = u.PasswordHash, u.Claims
+	current.CreatedAt, current.UpdatedAt = u.CreatedAt, u.UpdatedAt
+	if u.Location != nil {
+		current.Location = u.Location
+	}
+	if u.LastLoginAt != nil {
+		current.LastLoginAt = u.LastLoginAt
+	}
+	if u.BillingCustomerID != "" {
+		current.BillingCustomerID = u.BillingCustomerID
+	}
+	if u.SubscriptionStatus != "" {
+		current.SubscriptionStatus = u.SubscriptionStatus
+	}
+	if u.SubscriptionUpdatedAt != nil {
+		current.SubscriptionUpdatedAt = u.SubscriptionUpdatedAt
+	}
+	return nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, ID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(ID)
+	if i < 0 {
+		return wrappers.NewNonExistentErr(errUserNotFound)
+	}
+	r.users = append(r.users[:i], r.users[i+1:]...)
+	return nil
+}
+
+func (r *userRepository) CreateMany(ctx context.Context, users []interface{}) ([]string, error) {
+	var result []string
+	err := r.WithTransaction(ctx, func(ctx context.Context) error {
+		result = nil
+		for _, entity := range users {
+			id, err := r.Create(ctx, entity)
+			if err != nil {
+				return err
+			}
+			result = append(result, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WithTransaction runs fn, restoring the users and the archive as they were before when it fails.
+// The transactions are serialized, but the changes made meanwhile outside of them are also undone by a failed one.
+func (r *userRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	r.tx.Lock()
+	defer r.tx.Unlock()
+
+	r.mu.RLock()
+	users := make([]entities.User, len(r.users))
+	for i, u := range r.users {
+		users[i] = clone(u)
+	}
+	archive := append([]archivedUser(nil), r.archive...)
+	r.mu.RUnlock()
+
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		r.mu.Lock()
+		r.users, r.archive = users, archive
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Upsert updates the user matching the filter or creates it if none matches, returning its ID.
+// The creation date is only set on insert, and an empty password hash never overrides an existing one.
+func (r *userRepository) Upsert(ctx context.Context, filter map[string]interface{}, user interface{}) (string, error) {
+	u := clone(user.(entities.User))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.users {
+		ok, err := matches(r.users[i], filter)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+
+		current := &r.users[i]
+		if r.emailTaken(u.Email, current.ID) {
+			return "", apierror.Conflict(fmt.Errorf("email %s already in use", u.Email))
+		}
+		current.Name, current.Surnames, current.Email, current.Claims, current.UpdatedAt = u.Name, u.Surnames, u.Email, u.Claims, u.UpdatedAt
+		if u.Location != nil {
+			current.Location = u.Location
+		}
+		if u.PasswordHash != "" {
+			current.PasswordHash = u.PasswordHash
+		}
+		return current.ID, nil
+	}
+
+	if r.emailTaken(u.Email, "") {
+		return "", apierror.Conflict(fmt.Errorf("email %s already in use", u.Email))
+	}
+	u.ID = primitive.NewObjectID().Hex()
+	r.users = append(r.users, u)
+	return u.ID, nil
+}
+
+// Search gets the users whose name, surnames or email contain the text, or start with it in autocomplete mode, sorted by name and surnames.
+// Matching is case-insensitive, typo tolerance is only available on the mongo adapter.
+func (r *userRepository) Search(ctx context.Context, text string, autocomplete bool, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	text = strings.ToLower(text)
+	match := strings.Contains
+	if autocomplete {
+		match = strings.HasPrefix
+	}
+
+	r.mu.RLock()
+	var matched []entities.User
+	for _, u := range r.users {
+		for _, field := range []string{u.Name, u.Surnames, u.Email} {
+			if match(strings.ToLower(field), text) {
+				matched = append(matched, u)
+				break
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].Name != matched[j].Name {
+			return matched[i].Name < matched[j].Name
+		}
+		return matched[i].Surnames < matched[j].Surnames
+	})
+	return project(paginate(matched, skip, take), projection)
+}
+
+// GetNearby gets the users located within the radius, in meters, of a point, sorted by distance, computed with the haversine formula
+func (r *userRepository) GetNearby(ctx context.Context, longitude, latitude, radius float64, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	type nearby struct {
+		user     entities.User
+		distance float64
+	}
+
+	r.mu.RLock()
+	var matched []nearby
+	for _, u := range r.users {
+		if u.Location == nil || len(u.Location.Coordinates) != 2 {
+			continue
+		}
+		if d := distance(longitude, latitude, u.Location.Coordinates[0], u.Location.Coordinates[1]); d <= radius {
+			matched = append(matched, nearby{user: u, distance: d})
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].distance < matched[j].distance })
+	users := make([]entities.User, len(matched))
+	for i, m := range matched {
+		users[i] = m.user
+	}
+	return project(paginate(users, skip, take), projection)
+}
+
+// UpdateLastLogin sets the last login date of the user with the specified ID
+func (r *userRepository) UpdateLastLogin(ctx context.Context, ID string, at time.Time) error {
+	return r.update(ID, func(u *entities.User) { u.LastLoginAt = &at })
+}
+
+// UpdateNames sets the name, the surnames and the update date of the user with the specified ID
+func (r *userRepository) UpdateNames(ctx context.Context, ID, name, surnames string, at time.Time) error {
+	return r.update(ID, func(u *entities.User) { u.Name, u.Surnames, u.UpdatedAt = name, surnames, at })
+}
+
+// UpdateBillingCustomer sets the ID of the customer in the billing provider of the user with the specified ID
+func (r *userRepository) UpdateBillingCustomer(ctx context.Context, ID, customerID string) error {
+	return r.update(ID, func(u *entities.User) { u.BillingCustomerID = customerID })
+}
+
+// UpdateSubscription sets the subscription status of the user of the customer unless it was set as of a later time
+func (r *userRepository) UpdateSubscription(ctx context.Context, customerID, status string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.users {
+		u := &r.users[i]
+		if u.BillingCustomerID != customerID || (u.SubscriptionUpdatedAt != nil && u.SubscriptionUpdatedAt.After(at)) {
+			continue
+		}
+		u.SubscriptionStatus, u.SubscriptionUpdatedAt = status, &at
+		return nil
+	}
+	return wrappers.NewNonExistentErr(errUserNotFound)
+}
+
+// Archive moves the users whose last login, or last update if they never logged in, is older than inactiveSince to the archive
+func (r *userRepository) Archive(ctx context.Context, inactiveSince, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	kept := r.users[:0]
+	for _, u := range r.users {
+		if lastActive(u).Before(inactiveSince) {
+			r.archive = append(r.archive, archivedUser{user: u, archivedAt: at})
+			count++
+			continue
+		}
+		kept = append(kept, u)
+	}
+	r.users = kept
+	return count, nil
+}
+
+// ArchiveByID moves the user with the specified ID to the archive
+func (r *userRepository) ArchiveByID(ctx context.Context, ID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(ID)
+	if i < 0 {
+		return wrappers.NewNonExistentErr(errUserNotFound)
+	}
+	r.archive = append(r.archive, archivedUser{user: r.users[i], archivedAt: at})
+	r.users = append(r.users[:i], r.users[i+1:]...)
+	return nil
+}
+
+// Unarchive moves the archived user with the specified ID back to the users
+func (r *userRepository) Unarchive(ctx context.Context, ID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, archived := range r.archive {
+		if archived.user.ID != ID {
+			continue
+		}
+		u := archived.user
+		u.UpdatedAt = at
+		r.archive = append(r.archive[:i], r.archive[i+1:]...)
+		r.users = append(r.users, u)
+		return nil
+	}
+	return wrappers.NewNonExistentErr(errUserNotFound)
+}
+
+// Dump calls fn with every user, with all its fields, stopping at the first error.
+// The users are the ones found when called, so fn can change them.
+func (r *userRepository) Dump(ctx context.Context, fn func(user entities.User) error) error {
+	r.mu.RLock()
+	users := make([]entities.User, len(r.users))
+	for i, u := range r.users {
+		users[i] = clone(u)
+	}
+	r.mu.RUnlock()
+
+	for _, u := range users {
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restore replaces all the users with the given ones, keeping their IDs
+func (r *userRepository) Restore(ctx context.Context, users []entities.User) error {
+	restored := make([]entities.User, len(users))
+	for i, u := range users {
+		if _, err := primitive.ObjectIDFromHex(u.ID); err != nil {
+			return err
+		}
+		restored[i] = clone(u)
+	}
+
+	r.mu.Lock()
+	r.users = restored
+	r.mu.Unlock()
+	return nil
+}
+
+// Stats counts the users, the active ones and the archived ones, the active ones having logged in or, when they never did, been updated since the given time
+func (r *userRepository) Stats(ctx context.Context, activeSince time.Time) (stats entities.UserStats, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats.Total = int64(len(r.users))
+	for _, u := range r.users {
+		if !lastActive(u).Before(activeSince) {
+			stats.Active++
+		}
+	}
+	stats.Archived = int64(len(r.archive))
+	return
+}
+
+// update calls fn with the user with the specified ID, failing with a non existent error when it is not found
+func (r *userRepository) update(ID string, fn func(u *entities.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(ID)
+	if i < 0 {
+		return wrappers.NewNonExistentErr(errUserNotFound)
+	}
+	fn(&r.users[i])
+	return nil
+}
+
+// index returns the index of the user with the specified ID, -1 when not found. It must be called holding the lock.
+func (r *userRepository) index(ID string) int {
+	for i, u := range r.users {
+		if u.ID == ID {
+			return i
+		}
+	}
+	return -1
+}
+
+// emailTaken reports whether a user other than the one with the specified ID has the email, ignoring case. It must be called holding the lock.
+func (r *userRepository) emailTaken(email, ID string) bool {
+	for _, u := range r.users {
+		if u.ID != ID && strings.EqualFold(u.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastActive returns the last login of the user or, when it never logged in, its last update
+func lastActive(u entities.User) time.Time {
+	if u.LastLoginAt != nil {
+		return *u.LastLoginAt
+	}
+	return u.UpdatedAt
+}
+
+// matches reports whether the user has every field of the filter, named as its bson tag, the email being compared ignoring case
+func matches(u entities.User, filter map[string]interface{}) (bool, error) {
+	if len(filter) == 0 {
+		return true, nil
+	}
+
+	doc, err := toDocument(u)
+	if err != nil {
+		return false, err
+	}
+	for field, value := range filter {
+		if field == "email" {
+			email, ok := value.(string)
+			if !ok || !strings.EqualFold(u.Email, email) {
+				return false, nil
+			}
+			continue
+		}
+
+		want, err := toDocument(bson.M{"value": value})
+		if err != nil {
+			return false, err
+		}
+		if !reflect.DeepEqual(doc[field], want["value"]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// paginate returns the users after skipping skip of them, at most take
+func paginate(users []entities.User, skip, take *int) []entities.User {
+	if skip != nil {
+		if *skip >= len(users) {
+			return nil
+		}
+		users = users[*skip:]
+	}
+	if take != nil && *take > 0 && *take < len(users) {
+		users = users[:*take]
+	}
+	return users
+}
+
+// project returns copies of the users with only the fields allowed by the projection, as a mongo projection does,
+// failing with a non existent error when there are none
+func project(users []entities.User, projection map[string]interface{}) ([]interface{}, error) {
+	if len(users) < 1 {
+		return nil, wrappers.NewNonExistentErr(errUserNotFound)
+	}
+
+	result := make([]interface{}, len(users))
+	for i := range users {
+		u := clone(users[i])
+		if len(projection) > 0 {
+			doc, err := toDocument(u)
+			if err != nil {
+				return nil, err
+			}
+			projected := projectDocument(doc, projection)
+			raw, err := bson.Marshal(projected)
+			if err != nil {
+				return nil, err
+			}
+			u = entities.User{}
+			if err := bson.Unmarshal(raw, &u); err != nil {
+				return nil, err
+			}
+		}
+		result[i] = &u
+	}
+	return result, nil
+}
+
+// projectDocument returns the fields of the document allowed by the projection: only the included ones, and the _id unless excluded,
+// when any field is included, or every field but the excluded ones otherwise
+func projectDocument(doc bson.M, projection map[string]interface{}) bson.M {
+	var inclusive bool
+	for field, v := range projection {
+		if field != "_id" && included(v) {
+			inclusive = true
+			break
+		}
+	}
+
+	projected := bson.M{}
+	for field, value := range doc {
+		v, ok := projection[field]
+		switch {
+		case ok && !included(v):
+		case field == "_id", !inclusive, ok:
+			projected[field] = value
+		}
+	}
+	return projected
+}
+
+func included(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case int:
+		return t != 0
+	case int32:
+		return t != 0
+	case int64:
+		return t != 0
+	case float64:
+		return t != 0
+	default:
+		return false
+	}
+}
+
+// toDocument returns the document of the value, as stored by the mongo adapter
+func toDocument(v interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	err = bson.Unmarshal(raw, &doc)
+	return doc, err
+}
+
+// distance returns the distance in meters between two points, with the haversine formula
+func distance(lng1, lat1, lng2, lat2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Pow(math.Sin(dLng/2), 2)
+	return earthRadius * 2 * math.Asin(math.Sqrt(a))
+}
+
+// clone returns a copy of the user not sharing its claims, location or dates with it
+func clone(u entities.User) entities.User {
+	if u.Claims != nil {
+		u.Claims = append([]int64{}, u.Claims...)
+	}
+	if u.Location != nil {
+		location := *u.Location
+		location.Coordinates = append([]float64(nil), u.Location.Coordinates...)
+		u.Location = &location
+	}
+	if u.LastLoginAt != nil {
+		at := *u.LastLoginAt
+		u.LastLoginAt = &at
+	}
+	if u.SubscriptionUpdatedAt != nil {
+		at := *u.SubscriptionUpdatedAt
+		u.SubscriptionUpdatedAt = &at
+	}
+	return u
+}
@@ -0,0 +1,283 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreate_Ok checks that Create stores the user with a new ID, returned by GetByID
+func TestCreate_Ok(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	user := entities.User{Name: "test", Email: "test@test.com", Claims: []int64{0}}
+
+	// Act
+	ID, err := repo.Create(context.Background(), user)
+
+	// Assert
+	assert.Nil(t, err)
+	got, err := repo.GetByID(context.Background(), ID)
+	assert.Nil(t, err)
+	user.ID = ID
+	assert.Equal(t, &user, got)
+}
+
+// TestCreate_EmailTaken checks that Create returns a conflict when another user has the email, ignoring case
+func TestCreate_EmailTaken(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	if _, err := repo.Create(context.Background(), entities.User{Email: "test@test.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err := repo.Create(context.Background(), entities.User{Email: "TEST@test.com"})
+
+	// Assert
+	assert.True(t, errors.Is(err, apierror.ErrConflict))
+}
+
+// TestGetProjected_FilterAndPagination checks that GetProjected returns the users matching the filter, paginated, with only the projected fields
+func TestGetProjected_FilterAndPagination(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	for _, email := range []string{"a@test.com", "b@test.com", "c@test.com"} {
+		if _, err := repo.Create(context.Background(), entities.User{Name: "test", Email: email, PasswordHash: "hash"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := repo.Create(context.Background(), entities.User{Name: "other", Email: "d@test.com"}); err != nil {
+		t.Fatal(err)
+	}
+	skip, take := 1, 1
+
+	// Act
+	result, err := repo.GetProjected(context.Background(), map[string]interface{}{"name": "test"}, map[string]interface{}{"password_hash": 0}, &skip, &take)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	user := result[0].(*entities.User)
+	assert.Equal(t, "b@test.com", user.Email)
+	assert.Empty(t, user.PasswordHash)
+	assert.NotEmpty(t, user.ID)
+}
+
+// TestGet_EmailIgnoringCase checks that Get matches the email ignoring case
+func TestGet_EmailIgnoringCase(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	if _, err := repo.Create(context.Background(), entities.User{Email: "test@test.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	result, err := repo.Get(context.Background(), map[string]interface{}{"email": "Test@Test.com"}, nil, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+}
+
+// TestGet_NotFound checks that Get returns a non existent error when no user matches the filter
+func TestGet_NotFound(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+
+	// Act
+	_, err := repo.Get(context.Background(), map[string]interface{}{"email": "test@test.com"}, nil, nil)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestUpdate_KeepsLocation checks that Update keeps the location of the user when not set, as the mongo adapter does
+func TestUpdate_KeepsLocation(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	location := entities.NewGeoPoint(2.17, 41.38)
+	ID, err := repo.Create(context.Background(), entities.User{Name: "test", Email: "test@test.com", Location: &location})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = repo.Update(context.Background(), ID, entities.User{Name: "new", Email: "test@test.com"})
+
+	// Assert
+	assert.Nil(t, err)
+	got, err := repo.GetByID(context.Background(), ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "new", got.(*entities.User).Name)
+	assert.Equal(t, &location, got.(*entities.User).Location)
+}
+
+// TestDelete_NotFound checks that Delete returns a non existent error when the user is not found
+func TestDelete_NotFound(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+
+	// Act
+	err := repo.Delete(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestCreateMany_RolledBack checks that CreateMany creates none of the users when any of them fails
+func TestCreateMany_RolledBack(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	users := []interface{}{entities.User{Email: "a@test.com"}, entities.User{Email: "A@test.com"}}
+
+	// Act
+	_, err := repo.CreateMany(context.Background(), users)
+
+	// Assert
+	assert.NotNil(t, err)
+	_, err = repo.Get(context.Background(), nil, nil, nil)
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestUpsert_KeepsPassword checks that Upsert updates the user matching the filter without overriding its password with an empty one
+func TestUpsert_KeepsPassword(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	ID, err := repo.Create(context.Background(), entities.User{Name: "test", Email: "test@test.com", PasswordHash: "hash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	upsertedID, err := repo.Upsert(context.Background(), map[string]interface{}{"email": "test@test.com"}, entities.User{Name: "new", Email: "test@test.com"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, ID, upsertedID)
+	got, err := repo.GetByID(context.Background(), ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "new", got.(*entities.User).Name)
+	assert.Equal(t, "hash", got.(*entities.User).PasswordHash)
+}
+
+// TestSearch_Autocomplete checks that Search returns the users whose fields start with the text, ignoring case
+func TestSearch_Autocomplete(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	for _, name := range []string{"Maria", "Mark", "Anna"} {
+		if _, err := repo.Create(context.Background(), entities.User{Name: name, Email: name + "@test.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Act
+	result, err := repo.Search(context.Background(), "mar", true, nil, nil, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "Maria", result[0].(*entities.User).Name)
+}
+
+// TestGetNearby_SortedByDistance checks that GetNearby returns the users within the radius, the nearest first
+func TestGetNearby_SortedByDistance(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	for email, point := range map[string]entities.GeoPoint{
+		"far@test.com":     entities.NewGeoPoint(2.20, 41.40),
+		"near@test.com":    entities.NewGeoPoint(2.171, 41.381),
+		"outside@test.com": entities.NewGeoPoint(-3.70, 40.41),
+	} {
+		point := point
+		if _, err := repo.Create(context.Background(), entities.User{Email: email, Location: &point}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Act
+	result, err := repo.GetNearby(context.Background(), 2.17, 41.38, 10000, nil, nil, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "near@test.com", result[0].(*entities.User).Email)
+}
+
+// TestArchive_Unarchive checks that the inactive users are moved to the archive and back
+func TestArchive_Unarchive(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	now := time.Now().UTC()
+	ID, err := repo.Create(context.Background(), entities.User{Email: "old@test.com", UpdatedAt: now.Add(-time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Create(context.Background(), entities.User{Email: "new@test.com", UpdatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	archived, err := repo.Archive(context.Background(), now.Add(-time.Minute), now)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), archived)
+	stats, err := repo.Stats(context.Background(), now.Add(-time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, entities.UserStats{Total: 1, Active: 1, Archived: 1}, stats)
+	assert.Nil(t, repo.Unarchive(context.Background(), ID, now))
+	_, err = repo.GetByID(context.Background(), ID)
+	assert.Nil(t, err)
+}
+
+// TestUpdateNames_Ok checks that UpdateNames sets the name and the surnames of the user, leaving its other fields untouched
+func TestUpdateNames_Ok(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	ID, err := repo.Create(context.Background(), entities.User{Name: "old", Email: "test@test.com", PasswordHash: "test-hash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = repo.UpdateNames(context.Background(), ID, "new", "surnames", time.Now().UTC())
+
+	// Assert
+	assert.Nil(t, err)
+	result, err := repo.GetByID(context.Background(), ID)
+	assert.Nil(t, err)
+	user := result.(*entities.User)
+	assert.Equal(t, "new", user.Name)
+	assert.Equal(t, "surnames", user.Surnames)
+	assert.Equal(t, "test-hash", user.PasswordHash)
+}
+
+// TestUpdateSubscription_OutOfOrder checks that UpdateSubscription returns a non existent error when the status was set as of a later time
+func TestUpdateSubscription_OutOfOrder(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository()
+	ID, err := repo.Create(context.Background(), entities.User{Email: "test@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.UpdateBillingCustomer(context.Background(), ID, "cus_test"); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	if err := repo.UpdateSubscription(context.Background(), "cus_test", entities.SubscriptionStatusActive, now); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = repo.UpdateSubscription(context.Background(), "cus_test", "canceled", now.Add(-time.Minute))
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
@@ -0,0 +1,107 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// apiKeyRepository adapter of an API key repository for mongo
+type apiKeyRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewAPIKeyRepository creates an API key repository for mongo, creating the unique index of the hashes of the secrets and the index of the keys by user
+func NewAPIKeyRepository(ctx context.Context, db *mongo.Database) (ports.APIKeyRepository, error) {
+	r := &apiKeyRepository{
+		MongoRepository: infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameAPIKey),
+			Target:     entities.APIKey{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "secret_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+	return r, err
+}
+
+func (r *apiKeyRepository) GetByUser(ctx context.Context, userID string) ([]entities.APIKey, error) {
+	cur, err := r.Collection.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []entities.APIKey{}
+	err = cur.All(ctx, &keys)
+	return keys, err
+}
+
+func (r *apiKeyRepository) GetBySecretHash(ctx context.Context, secretHash string) (entities.APIKey, error) {
+	var key entities.APIKey
+	err := r.Collection.FindOne(ctx, bson.M{"secret_hash": secretHash}).Decode(&key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("API key not found"))
+	}
+	return key, err
+}
+
+func (r *apiKeyRepository) Rotate(ctx context.Context, ID, prefix, secretHash string, at time.Time) error {
+	update := bson.M{"$set": bson.M{"prefix": prefix, "secret_hash": secretHash, "updated_at": at}}
+	return r.updateActive(ctx, ID, update)
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, ID string, at time.Time) error {
+	update := bson.M{"$set": bson.M{"revoked_at": at, "updated_at": at}}
+	return r.updateActive(ctx, ID, update)
+}
+
+// updateActive updates the API key with the specified ID unless it is revoked, failing with a non existent error otherwise
+func (r *apiKeyRepository) updateActive(ctx context.Context, ID string, update bson.M) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.Collection.UpdateOne(ctx, bson.M{"_id": _id, "revoked_at": nil}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("API key ID %s not found or revoked", ID))
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) Touch(ctx context.Context, ID string, at time.Time) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Collection.UpdateOne(ctx, bson.M{"_id": _id}, bson.M{"$set": bson.M{"last_used_at": at}})
+	return err
+}
+
+func (r *apiKeyRepository) RevokeByUser(ctx context.Context, userID string, at time.Time) error {
+	update := bson.M{"$set": bson.M{"revoked_at": at, "updated_at": at}}
+	_, err := r.Collection.UpdateMany(ctx, bson.M{"user_id": userID, "revoked_at": nil}, update)
+	return err
+}
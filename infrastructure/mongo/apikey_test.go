@@ -0,0 +1,134 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// testAPIKeyID an API key ID in the format of the IDs of Mongo
+const testAPIKeyID = "64b7f0c2a1b2c3d4e5f6071a"
+
+// newTestAPIKeyRepository returns an API key repository on the collection of the mocked database
+func newTestAPIKeyRepository(mt *mtest.T) apiKeyRepository {
+	return apiKeyRepository{
+		MongoRepository: infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.DB.Collection(entities.EntityNameAPIKey),
+			Target:     entities.APIKey{},
+		},
+	}
+}
+
+// TestNewAPIKeyRepository_Ok checks that NewAPIKeyRepository creates the unique index of the hashes of the secrets
+func TestNewAPIKeyRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		_, err := NewAPIKeyRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.Nil(t, err)
+		index := mt.GetStartedEvent().Command.Lookup("indexes").Array().Index(0).Value().Document()
+		assert.Equal(t, "secret_hash_1", index.Lookup("name").StringValue())
+		assert.True(t, index.Lookup("unique").Boolean())
+	})
+}
+
+// TestGetAPIKeyBySecretHash_Ok checks that GetBySecretHash returns the API key found by the hash of its secret
+func TestGetAPIKeyBySecretHash_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestAPIKeyRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "test-key"}, {Key: "user_id", Value: "test-user"}, {Key: "scopes", Value: bson.A{"admin"}}},
+		))
+
+		// Act
+		key, err := repo.GetBySecretHash(context.Background(), "test-hash")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "test-user", key.UserID)
+		assert.Equal(t, []string{"admin"}, key.Scopes)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "test-hash", filter.Lookup("secret_hash").StringValue())
+	})
+}
+
+// TestGetAPIKeyBySecretHash_NotFound checks that GetBySecretHash returns a non existent error when no API key has the hash
+func TestGetAPIKeyBySecretHash_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestAPIKeyRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.GetBySecretHash(context.Background(), "test-hash")
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	})
+}
+
+// TestRevokeAPIKey_Revoked checks that Revoke returns a non existent error when the API key is revoked already
+func TestRevokeAPIKey_Revoked(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestAPIKeyRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.Revoke(context.Background(), testAPIKeyID, time.Now().UTC())
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, bson.TypeNull, update.Lookup("q", "revoked_at").Type)
+	})
+}
+
+// TestRotateAPIKey_Ok checks that Rotate sets the new prefix and hash of the secret of the API key
+func TestRotateAPIKey_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestAPIKeyRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.Rotate(context.Background(), testAPIKeyID, "hak_prefix", "test-hash", time.Now().UTC())
+
+		// Assert
+		assert.Nil(t, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "test-hash", update.Lookup("u", "$set", "secret_hash").StringValue())
+		assert.Equal(t, "hak_prefix", update.Lookup("u", "$set", "prefix").StringValue())
+	})
+}
@@ -0,0 +1,140 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// codeNamespaceExists is returned when creating a collection that already exists
+const codeNamespaceExists = 48
+
+// auditRepository adapter of an audit repository for mongo.
+// Events are written to a capped collection when a maximum size is set, which keeps them in insertion order
+// and drops the oldest ones once full, or otherwise to a collection expiring them with a TTL index.
+type auditRepository struct {
+	collection *mongo.Collection
+	capped     bool
+}
+
+// NewAuditRepository creates an audit repository for mongo, creating the capped collection, when maxSize is set, or the TTL index, when retention is set.
+// An existing collection is not converted, so switching between both modes requires dropping it first.
+func NewAuditRepository(ctx context.Context, db *mongo.Database, maxSize, maxDocuments int64, retention time.Duration) (ports.AuditRepository, error) {
+	r := &auditRepository{
+		collection: db.Collection(entities.EntityNameAuditEvent),
+		capped:     maxSize > 0,
+	}
+
+	if r.capped {
+		opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(maxSize)
+		if maxDocuments > 0 {
+			opts.SetMaxDocuments(maxDocuments)
+		}
+		err := db.CreateCollection(ctx, entities.EntityNameAuditEvent, opts)
+		if err != nil && !hasErrorCode(err, codeNamespaceExists) {
+			return nil, err
+		}
+	} else if retention > 0 {
+		_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(retention.Seconds())),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "type", Value: 1}}},
+		{Keys: bson.D{{Key: "actor_id", Value: 1}}},
+	})
+	return r, err
+}
+
+func (r *auditRepository) Write(ctx context.Context, event entities.AuditEvent) error {
+	event.ID = ""
+	_, err := r.collection.InsertOne(ctx, event)
+	return err
+}
+
+func (r *auditRepository) GetByID(ctx context.Context, ID string) (entities.AuditEvent, error) {
+	var event entities.AuditEvent
+
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return event, wrappers.NewNonExistentErr(err)
+	}
+
+	err = r.collection.FindOne(ctx, bson.M{"_id": _id}, findOneComment(ctx)).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = wrappers.NewNonExistentErr(err)
+	}
+	return event, err
+}
+
+func (r *auditRepository) Get(ctx context.Context, filter ports.AuditFilter, skip, take *int) ([]entities.AuditEvent, error) {
+	// the natural order of capped collections is the insertion order, otherwise the ObjectIDs follow it
+	sort := bson.D{{Key: "_id", Value: -1}}
+	if r.capped {
+		sort = bson.D{{Key: "$natural", Value: -1}}
+	}
+
+	opts := options.Find().SetSort(sort)
+	if skip != nil {
+		opts.SetSkip(int64(*skip))
+	}
+	if take != nil {
+		opts.SetLimit(int64(*take))
+	}
+
+	cur, err := r.collection.Find(ctx, auditQuery(filter), opts, findComment(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	events := []entities.AuditEvent{}
+	err = cur.All(ctx, &events)
+	return events, err
+}
+
+func (r *auditRepository) Count(ctx context.Context, filter ports.AuditFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, auditQuery(filter), countComment(ctx))
+}
+
+// auditQuery returns the query of the events matching the filter
+func auditQuery(filter ports.AuditFilter) bson.M {
+	query := bson.M{}
+	for field, value := range map[string]string{"type": filter.Type, "outcome": filter.Outcome, "user_id": filter.UserID, "actor_id": filter.ActorID} {
+		if value != "" {
+			query[field] = value
+		}
+	}
+
+	createdAt := bson.M{}
+	if filter.From != nil {
+		createdAt["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		createdAt["$lte"] = *filter.To
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+	return query
+}
+
+// Expire purges or anonymizes the events created before the given time, anonymized ones losing their user, actor, IP and details.
+// On capped collections it requires MongoDB 5.0 or later, as older versions do not allow deleting documents from them.
+func (r *auditRepository) Expire(ctx context.Context, action entities.RetentionAction, before time.Time, dryRun bool) (int64, error) {
+	return expire(ctx, r.collection, bson.M{"created_at": bson.M{"$lt": before}}, action, dryRun, bson.A{
+		bson.M{"$unset": bson.A{"user_id", "actor_id", "ip", "details"}},
+	})
+}
@@ -0,0 +1,198 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewAuditRepository_Capped checks that NewAuditRepository creates a capped collection when a maximum size is set
+func TestNewAuditRepository_Capped(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewAuditRepository(context.Background(), mt.DB, 1024, 10, time.Hour)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.True(t, repo.(*auditRepository).capped)
+		create := mt.GetStartedEvent().Command
+		assert.True(t, create.Lookup("capped").Boolean())
+		assert.Equal(t, int64(1024), create.Lookup("size").AsInt64())
+		assert.Equal(t, int64(10), create.Lookup("max").AsInt64())
+	})
+}
+
+// TestNewAuditRepository_AlreadyExists checks that NewAuditRepository does not fail when the capped collection already exists
+func TestNewAuditRepository_AlreadyExists(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: codeNamespaceExists, Message: "collection already exists"}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		// Act
+		_, err := NewAuditRepository(context.Background(), mt.DB, 1024, 0, 0)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestNewAuditRepository_Retention checks that NewAuditRepository creates a TTL index when only the retention is set
+func TestNewAuditRepository_Retention(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewAuditRepository(context.Background(), mt.DB, 0, 0, time.Hour)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.False(t, repo.(*auditRepository).capped)
+		index := mt.GetStartedEvent().Command.Lookup("indexes").Array().Index(0).Value().Document()
+		assert.Equal(t, int32(3600), index.Lookup("expireAfterSeconds").Int32())
+	})
+}
+
+// TestWriteAuditEvent_Ok checks that Write inserts the event
+func TestWriteAuditEvent_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := auditRepository{collection: mt.DB.Collection(entities.EntityNameAuditEvent)}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.Write(context.Background(), entities.AuditEvent{Type: entities.AuditLoginSucceeded, UserID: "test-id", CreatedAt: time.Now().UTC()})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestGetAuditEventByID_Ok checks that GetByID returns the event with the given ID
+func TestGetAuditEventByID_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := auditRepository{collection: mt.DB.Collection(entities.EntityNameAuditEvent)}
+		ns := mt.DB.Name() + "." + entities.EntityNameAuditEvent
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: id}, {Key: "type", Value: entities.AuditUserDeleted}}))
+
+		// Act
+		event, err := repo.GetByID(context.Background(), id.Hex())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, entities.AuditEvent{ID: id.Hex(), Type: entities.AuditUserDeleted}, event)
+	})
+}
+
+// TestGetAuditEventByID_InvalidID checks that GetByID returns a non existent error when the ID is not an ObjectID
+func TestGetAuditEventByID_InvalidID(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := auditRepository{collection: mt.DB.Collection(entities.EntityNameAuditEvent)}
+
+		// Act
+		_, err := repo.GetByID(context.Background(), "invalid-id")
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	})
+}
+
+// TestGetAuditEvents_Capped checks that Get returns the events in reverse natural order from a capped collection
+func TestGetAuditEvents_Capped(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := auditRepository{collection: mt.DB.Collection(entities.EntityNameAuditEvent), capped: true}
+		ns := mt.DB.Name() + "." + entities.EntityNameAuditEvent
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: id}, {Key: "type", Value: entities.AuditLoginFailed}}))
+		take := 10
+
+		// Act
+		events, err := repo.Get(context.Background(), ports.AuditFilter{Type: entities.AuditLoginFailed}, nil, &take)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, []entities.AuditEvent{{ID: id.Hex(), Type: entities.AuditLoginFailed}}, events)
+		sort := mt.GetStartedEvent().Command.Lookup("sort").Document()
+		assert.Equal(t, int32(-1), sort.Lookup("$natural").Int32())
+	})
+}
+
+// TestCountAuditEvents_Ok checks that Count returns the count of the events matching the filter
+func TestCountAuditEvents_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := auditRepository{collection: mt.DB.Collection(entities.EntityNameAuditEvent)}
+		ns := mt.DB.Name() + "." + entities.EntityNameAuditEvent
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(4)}}))
+
+		// Act
+		count, err := repo.Count(context.Background(), ports.AuditFilter{Type: entities.AuditLoginFailed})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), count)
+		match := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Index(0).Value().Document()
+		assert.Equal(t, entities.AuditLoginFailed, match.Lookup("$match", "type").StringValue())
+	})
+}
+
+// TestAuditQuery_Ok checks that auditQuery matches the set fields and the creation range of the filter
+func TestAuditQuery_Ok(t *testing.T) {
+	// Arrange
+	from := time.Now().UTC().Add(-time.Hour)
+	to := time.Now().UTC()
+	filter := ports.AuditFilter{Outcome: entities.AuditOutcomeFailure, ActorID: "test-id", From: &from, To: &to}
+
+	// Act
+	query := auditQuery(filter)
+
+	// Assert
+	assert.Equal(t, bson.M{
+		"outcome":    entities.AuditOutcomeFailure,
+		"actor_id":   "test-id",
+		"created_at": bson.M{"$gte": from, "$lte": to},
+	}, query)
+}
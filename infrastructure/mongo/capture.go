@@ -0,0 +1,55 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// captureRepository adapter of a capture repository for mongo, whose captures are expired by a TTL index
+type captureRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCaptureRepository creates a capture repository for mongo, creating the TTL index expiring the captures older than the given TTL
+func NewCaptureRepository(ctx context.Context, db *mongo.Database, ttl time.Duration) (ports.CaptureRepository, error) {
+	r := &captureRepository{
+		collection: db.Collection(entities.EntityNameCapture),
+	}
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(ttl.Seconds())),
+	})
+	return r, err
+}
+
+func (r *captureRepository) Write(ctx context.Context, capture entities.Capture) error {
+	capture.ID = ""
+	_, err := r.collection.InsertOne(ctx, capture)
+	return err
+}
+
+func (r *captureRepository) Get(ctx context.Context, skip, take *int) ([]entities.Capture, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if skip != nil {
+		opts.SetSkip(int64(*skip))
+	}
+	if take != nil {
+		opts.SetLimit(int64(*take))
+	}
+
+	cur, err := r.collection.Find(ctx, bson.M{}, opts, findComment(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	captures := []entities.Capture{}
+	err = cur.All(ctx, &captures)
+	return captures, err
+}
@@ -0,0 +1,75 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewCaptureRepository_Ok checks that NewCaptureRepository creates the TTL index of the captures
+func TestNewCaptureRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		_, err := NewCaptureRepository(context.Background(), mt.DB, time.Hour)
+
+		// Assert
+		assert.Nil(t, err)
+		index := mt.GetStartedEvent().Command.Lookup("indexes").Array().Index(0).Value().Document()
+		assert.Equal(t, int32(3600), index.Lookup("expireAfterSeconds").Int32())
+	})
+}
+
+// TestWriteCapture_Ok checks that Write inserts the capture
+func TestWriteCapture_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := captureRepository{collection: mt.DB.Collection(entities.EntityNameCapture)}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.Write(context.Background(), entities.Capture{RequestID: "test-request-id", Method: "GET", Path: "/v1/users", Status: 200, CreatedAt: time.Now().UTC()})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestGetCaptures_Ok checks that Get returns the captures newest first
+func TestGetCaptures_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := captureRepository{collection: mt.DB.Collection(entities.EntityNameCapture)}
+		ns := mt.DB.Name() + "." + entities.EntityNameCapture
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: id}, {Key: "request_id", Value: "test-request-id"}}))
+		take := 10
+
+		// Act
+		captures, err := repo.Get(context.Background(), nil, &take)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, []entities.Capture{{ID: id.Hex(), RequestID: "test-request-id"}}, captures)
+		sort := mt.GetStartedEvent().Command.Lookup("sort").Document()
+		assert.Equal(t, int32(-1), sort.Lookup("_id").Int32())
+	})
+}
@@ -0,0 +1,107 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// userChangeStream adapter of a user change stream for mongo, watching the change stream of the users collection,
+// only available on replica sets and sharded clusters, and storing its resume token once every change is handled
+type userChangeStream struct {
+	users  *mongo.Collection
+	tokens *mongo.Collection
+	name   string
+}
+
+// userChangeEvent is the part of an event of the change stream of the users collection telling the user changed
+type userChangeEvent struct {
+	OperationType string `bson:"operationType"`
+	DocumentKey   struct {
+		ID interface{} `bson:"_id"`
+	} `bson:"documentKey"`
+}
+
+// NewUserChangeStream creates a user change stream for mongo, whose resume token is stored under the name of its consumer
+func NewUserChangeStream(db *mongo.Database, name string) ports.UserChangeStream {
+	return &userChangeStream{
+		users:  db.Collection(entities.EntityNameUser),
+		tokens: db.Collection(entities.EntityNameResumeToken),
+		name:   name,
+	}
+}
+
+func (s *userChangeStream) Watch(ctx context.Context, backfill func(ctx context.Context) error, handle func(ctx context.Context, change ports.UserChange) error) error {
+	var saved entities.ResumeToken
+	err := s.tokens.FindOne(ctx, bson.M{"_id": s.name}).Decode(&saved)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	opts := options.ChangeStream()
+	if saved.Token != nil {
+		opts.SetResumeAfter(bson.Raw(saved.Token))
+	}
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}}}}}}
+	stream, err := s.users.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	if saved.Token == nil {
+		if err := backfill(ctx); err != nil {
+			return err
+		}
+		if err := s.save(ctx, stream.ResumeToken()); err != nil {
+			return err
+		}
+	}
+
+	for stream.Next(ctx) {
+		var event userChangeEvent
+		if err := stream.Decode(&event); err != nil {
+			return err
+		}
+		change := ports.UserChange{Operation: ports.UserChangeSaved, ID: documentID(event.DocumentKey.ID)}
+		if event.OperationType == "delete" {
+			change.Operation = ports.UserChangeDeleted
+		}
+		if err := handle(ctx, change); err != nil {
+			return err
+		}
+		if err := s.save(ctx, stream.ResumeToken()); err != nil {
+			return err
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("change stream of %s closed", entities.EntityNameUser)
+}
+
+// save stores the resume token, unless the server did not report any yet
+func (s *userChangeStream) save(ctx context.Context, token bson.Raw) error {
+	if token == nil {
+		return nil
+	}
+	update := bson.M{"$set": bson.M{"token": []byte(token), "updated_at": time.Now().UTC()}}
+	_, err := s.tokens.UpdateOne(ctx, bson.M{"_id": s.name}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// documentID returns the ID of a document as a string, in hex for the object IDs
+func documentID(ID interface{}) string {
+	if oid, ok := ID.(primitive.ObjectID); ok {
+		return oid.Hex()
+	}
+	return fmt.Sprint(ID)
+}
@@ -0,0 +1,126 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestWatchUserChanges_Resumed checks that Watch resumes after the stored resume token, without backfilling,
+// handling the saved and deleted users and storing the resume token of every change handled
+func TestWatchUserChanges_Resumed(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		stream := NewUserChangeStream(mt.DB, "test-consumer")
+		token, _ := bson.Marshal(bson.M{"_data": "test-token"})
+		ID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.resume_tokens", mtest.FirstBatch, bson.D{{Key: "_id", Value: "test-consumer"}, {Key: "token", Value: token}}),
+			mtest.CreateCursorResponse(0, "test.users", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: bson.M{"_data": "token-1"}}, {Key: "operationType", Value: "update"}, {Key: "documentKey", Value: bson.M{"_id": ID}}},
+				bson.D{{Key: "_id", Value: bson.M{"_data": "token-2"}}, {Key: "operationType", Value: "delete"}, {Key: "documentKey", Value: bson.M{"_id": ID}}},
+			),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+		var changes []ports.UserChange
+		backfill := func(ctx context.Context) error {
+			t.Fatal("unexpected backfill")
+			return nil
+		}
+		handle := func(ctx context.Context, change ports.UserChange) error {
+			changes = append(changes, change)
+			return nil
+		}
+
+		// Act
+		err := stream.Watch(context.Background(), backfill, handle)
+
+		// Assert
+		assert.EqualError(t, err, "change stream of users closed")
+		assert.Equal(t, []ports.UserChange{{Operation: ports.UserChangeSaved, ID: ID.Hex()}, {Operation: ports.UserChangeDeleted, ID: ID.Hex()}}, changes)
+		mt.GetStartedEvent()
+		watch := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Index(0).Value().Document()
+		assert.Equal(t, "test-token", watch.Lookup("$changeStream", "resumeAfter", "_data").StringValue())
+		save := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "test-consumer", save.Lookup("q", "_id").StringValue())
+		assert.True(t, save.Lookup("upsert").Boolean())
+	})
+}
+
+// TestWatchUserChanges_Backfill checks that Watch backfills the users when no resume token is stored
+func TestWatchUserChanges_Backfill(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		stream := NewUserChangeStream(mt.DB, "test-consumer")
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.resume_tokens", mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, "test.users", mtest.FirstBatch),
+		)
+		backfilled := false
+		backfill := func(ctx context.Context) error {
+			backfilled = true
+			return nil
+		}
+
+		// Act
+		err := stream.Watch(context.Background(), backfill, nil)
+
+		// Assert
+		assert.EqualError(t, err, "change stream of users closed")
+		assert.True(t, backfilled)
+	})
+}
+
+// TestWatchUserChanges_HandleError checks that Watch returns the error of a change not handled, without storing its resume token
+func TestWatchUserChanges_HandleError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		stream := NewUserChangeStream(mt.DB, "test-consumer")
+		token, _ := bson.Marshal(bson.M{"_data": "test-token"})
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.resume_tokens", mtest.FirstBatch, bson.D{{Key: "_id", Value: "test-consumer"}, {Key: "token", Value: token}}),
+			mtest.CreateCursorResponse(0, "test.users", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: bson.M{"_data": "token-1"}}, {Key: "operationType", Value: "insert"}, {Key: "documentKey", Value: bson.M{"_id": primitive.NewObjectID()}}},
+			),
+		)
+		expectedError := errors.New("index not reachable")
+		handle := func(ctx context.Context, change ports.UserChange) error {
+			return expectedError
+		}
+
+		// Act
+		err := stream.Watch(context.Background(), nil, handle)
+
+		// Assert
+		assert.Equal(t, expectedError, err)
+		mt.GetStartedEvent()
+		mt.GetStartedEvent()
+		assert.Nil(t, mt.GetStartedEvent())
+	})
+}
+
+// TestDocumentID_String checks that documentID returns the IDs not being object IDs as they are
+func TestDocumentID_String(t *testing.T) {
+	// Act
+	ID := documentID("test-id")
+
+	// Assert
+	assert.Equal(t, "test-id", ID)
+}
@@ -0,0 +1,75 @@
+package mongo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestComment returns the comment tying the operations run for a request to it in currentOp, the profiler and the slow query logs,
+// like request_id=4bf92f35 trace_id=0af7651916cd43dd8448eb211c80319c, empty when the context carries neither
+func requestComment(ctx context.Context) string {
+	var fields []string
+	if requestID := models.RequestInfoFrom(ctx).RequestID; requestID != "" {
+		fields = append(fields, "request_id="+requestID)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields, "trace_id="+sc.TraceID().String())
+	}
+	return strings.Join(fields, " ")
+}
+
+// the following functions return the options setting the request comment of the context on an operation, to be appended to its other options,
+// being nil when there is no comment so the operation is sent without it
+
+func findComment(ctx context.Context) *options.FindOptions {
+	if comment := requestComment(ctx); comment != "" {
+		return options.Find().SetComment(comment)
+	}
+	return nil
+}
+
+func findOneComment(ctx context.Context) *options.FindOneOptions {
+	if comment := requestComment(ctx); comment != "" {
+		return options.FindOne().SetComment(comment)
+	}
+	return nil
+}
+
+func findOneAndUpdateComment(ctx context.Context) *options.FindOneAndUpdateOptions {
+	if comment := requestComment(ctx); comment != "" {
+		return options.FindOneAndUpdate().SetComment(comment)
+	}
+	return nil
+}
+
+func aggregateComment(ctx context.Context) *options.AggregateOptions {
+	if comment := requestComment(ctx); comment != "" {
+		return options.Aggregate().SetComment(comment)
+	}
+	return nil
+}
+
+func countComment(ctx context.Context) *options.CountOptions {
+	if comment := requestComment(ctx); comment != "" {
+		return options.Count().SetComment(comment)
+	}
+	return nil
+}
+
+func updateComment(ctx context.Context) *options.UpdateOptions {
+	if comment := requestComment(ctx); comment != "" {
+		return options.Update().SetComment(comment)
+	}
+	return nil
+}
+
+func deleteComment(ctx context.Context) *options.DeleteOptions {
+	if comment := requestComment(ctx); comment != "" {
+		return options.Delete().SetComment(comment)
+	}
+	return nil
+}
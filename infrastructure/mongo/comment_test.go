@@ -0,0 +1,57 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// TestRequestComment_Ok checks that requestComment returns the request ID and the trace ID carried by the context
+func TestRequestComment_Ok(t *testing.T) {
+	// Arrange
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "test-span")
+	ctx = models.WithRequestInfo(ctx, models.RequestInfo{RequestID: "test-request-id"})
+
+	// Act
+	comment := requestComment(ctx)
+
+	// Assert
+	assert.Equal(t, "request_id=test-request-id trace_id="+span.SpanContext().TraceID().String(), comment)
+}
+
+// TestRequestComment_Empty checks that requestComment returns an empty comment when the context does not carry a request
+func TestRequestComment_Empty(t *testing.T) {
+	// Act
+	comment := requestComment(context.Background())
+
+	// Assert
+	assert.Empty(t, comment)
+	assert.Nil(t, findComment(context.Background()))
+}
+
+// TestRequestComment_Command checks that the commands run for a request carry its comment
+func TestRequestComment_Command(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := jobRepository{collection: mt.DB.Collection(entities.EntityNameJob)}
+		ns := mt.DB.Name() + "." + entities.EntityNameJob
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+		ctx := models.WithRequestInfo(context.Background(), models.RequestInfo{RequestID: "test-request-id"})
+
+		// Act
+		repo.GetByID(ctx, primitive.NewObjectID().Hex())
+
+		// Assert
+		assert.Equal(t, "request_id=test-request-id", mt.GetStartedEvent().Command.Lookup("comment").StringValue())
+	})
+}
@@ -0,0 +1,72 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// deviceRepository adapter of a device repository for mongo, whose devices are documents keyed by their ID
+type deviceRepository struct {
+	collection *mongo.Collection
+}
+
+// NewDeviceRepository creates a device repository for mongo, creating the index of the devices by user
+func NewDeviceRepository(ctx context.Context, db *mongo.Database) (ports.DeviceRepository, error) {
+	r := &deviceRepository{
+		collection: db.Collection(entities.EntityNameDevice),
+	}
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	return r, err
+}
+
+func (r *deviceRepository) Upsert(ctx context.Context, device entities.Device) error {
+	update := bson.M{
+		"$set": bson.M{
+			"user_id":    device.UserID,
+			"platform":   device.Platform,
+			"token":      device.Token,
+			"name":       device.Name,
+			"updated_at": device.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{"created_at": device.CreatedAt},
+	}
+	_, err := r.collection.UpdateByID(ctx, device.ID, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *deviceRepository) GetByUser(ctx context.Context, userID string) ([]entities.Device, error) {
+	cur, err := r.collection.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	devices := []entities.Device{}
+	err = cur.All(ctx, &devices)
+	return devices, err
+}
+
+func (r *deviceRepository) Delete(ctx context.Context, userID, ID string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": ID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("device ID %s of user ID %s not found", ID, userID))
+	}
+	return nil
+}
+
+func (r *deviceRepository) DeleteByUser(ctx context.Context, userID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
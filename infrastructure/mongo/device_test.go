@@ -0,0 +1,120 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewDeviceRepository_Ok checks that NewDeviceRepository creates the index of the devices by user
+func TestNewDeviceRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		_, err := NewDeviceRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.Nil(t, err)
+		index := mt.GetStartedEvent().Command.Lookup("indexes").Array().Index(0).Value().Document()
+		assert.Equal(t, "user_id_1_created_at_-1", index.Lookup("name").StringValue())
+	})
+}
+
+// TestUpsertDevice_Ok checks that Upsert upserts the device by its ID, keeping its creation time when it exists
+func TestUpsertDevice_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := deviceRepository{collection: mt.DB.Collection(entities.EntityNameDevice)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+		now := time.Now().UTC()
+		device := entities.Device{ID: "test-id", UserID: "test-user", Platform: entities.DevicePlatformFCM, Token: "test-token", CreatedAt: now, UpdatedAt: now}
+
+		// Act
+		err := repo.Upsert(context.Background(), device)
+
+		// Assert
+		assert.Nil(t, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.True(t, update.Lookup("upsert").Boolean())
+		assert.Equal(t, "test-user", update.Lookup("u", "$set", "user_id").StringValue())
+		_, err = update.LookupErr("u", "$setOnInsert", "created_at")
+		assert.Nil(t, err)
+	})
+}
+
+// TestGetDevicesByUser_Ok checks that GetByUser returns the devices of the user found in the collection
+func TestGetDevicesByUser_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := deviceRepository{collection: mt.DB.Collection(entities.EntityNameDevice)}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "new-device"}, {Key: "user_id", Value: "test-user"}, {Key: "platform", Value: "apns"}},
+			bson.D{{Key: "_id", Value: "old-device"}, {Key: "user_id", Value: "test-user"}, {Key: "platform", Value: "fcm"}},
+		))
+
+		// Act
+		devices, err := repo.GetByUser(context.Background(), "test-user")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, devices, 2)
+		assert.Equal(t, "new-device", devices[0].ID)
+		assert.Equal(t, entities.DevicePlatformFCM, devices[1].Platform)
+	})
+}
+
+// TestDeleteDevice_Ok checks that Delete deletes the device of the user
+func TestDeleteDevice_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := deviceRepository{collection: mt.DB.Collection(entities.EntityNameDevice)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "acknowledged", Value: true}, {Key: "n", Value: 1}})
+
+		// Act
+		err := repo.Delete(context.Background(), "test-user", "test-id")
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestDeleteDevice_NotFound checks that Delete returns a non existent error when the user has no device with the ID
+func TestDeleteDevice_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := deviceRepository{collection: mt.DB.Collection(entities.EntityNameDevice)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "acknowledged", Value: true}, {Key: "n", Value: 0}})
+
+		// Act
+		err := repo.Delete(context.Background(), "test-user", "test-id")
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+		assert.EqualError(t, err, "device ID test-id of user ID test-user not found")
+	})
+}
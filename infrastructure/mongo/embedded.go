@@ -0,0 +1,127 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// embeddedDatabase is the name of the database of the embedded MongoDB
+const embeddedDatabase = "gohexagonalapi"
+
+// EmbeddedMongo MongoDB server run in a disposable docker container for development,
+// as a single node replica set so the transactions work as with the deployed databases
+type EmbeddedMongo struct {
+	DSN      string
+	pool     *dockertest.Pool
+	resource *dockertest.Resource
+}
+
+// StartEmbedded runs the given MongoDB image, like mongo:6.0, pulling it when not present, and waits up to the timeout for it to be writable.
+// The container is removed when stopped, or once the expiry is reached if the process exits without stopping it, a 0 expiry disabling it.
+func StartEmbedded(image string, timeout, expiry time.Duration) (*EmbeddedMongo, error) {
+	repository, tag, _ := strings.Cut(image, ":")
+	if tag == "" {
+		tag = "latest"
+	}
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to docker: %w", err)
+	}
+	if err := pool.Client.Ping(); err != nil {
+		return nil, fmt.Errorf("cannot connect to docker: %w", err)
+	}
+	pool.MaxWait = timeout
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: repository,
+		Tag:        tag,
+		Cmd:        []string{"--replSet", "rs0", "--bind_ip_all"},
+	}, func(config *docker.HostConfig) {
+		// set AutoRemove to true so that stopped container goes away by itself
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{
+			Name: "no",
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot start the container: %w", err)
+	}
+
+	e := &EmbeddedMongo{
+		DSN:      fmt.Sprintf("mongodb://localhost:%s/%s?connect=direct", resource.GetPort("27017/tcp"), embeddedDatabase),
+		pool:     pool,
+		resource: resource,
+	}
+	if err := e.init(expiry); err != nil {
+		e.Stop()
+		return nil, err
+	}
+	return e, nil
+}
+
+// init sets the expiry of the container and initiates the replica set once the server accepts connections
+func (e *EmbeddedMongo) init(expiry time.Duration) error {
+	if expiry > 0 {
+		if err := e.resource.Expire(uint(expiry.Seconds())); err != nil {
+			return fmt.Errorf("cannot set the expiry of the container: %w", err)
+		}
+	}
+
+	// exponential backoff-retry, because the server in the container might not be ready to accept connections yet
+	err := e.pool.Retry(func() error {
+		_, err := e.writablePrimary()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("server not reachable: %w", err)
+	}
+
+	exitCode, err := e.resource.Exec([]string{"mongosh", "--quiet", "--eval", "rs.initiate().ok"}, dockertest.ExecOptions{})
+	if err != nil {
+		return fmt.Errorf("cannot initiate the replica set: %w", err)
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("cannot initiate the replica set, exit code was %d", exitCode)
+	}
+
+	// the member is elected primary shortly after the replica set is initiated
+	return e.pool.Retry(func() error {
+		primary, err := e.writablePrimary()
+		if err == nil && !primary {
+			err = fmt.Errorf("replica set member not elected primary yet")
+		}
+		return err
+	})
+}
+
+// writablePrimary reports whether the server is the primary of its replica set
+func (e *EmbeddedMongo) writablePrimary() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(e.DSN))
+	if err != nil {
+		return false, err
+	}
+	defer client.Disconnect(context.Background())
+
+	var hello struct {
+		IsWritablePrimary bool `bson:"isWritablePrimary"`
+	}
+	err = client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	return hello.IsWritablePrimary, err
+}
+
+// Stop kills and removes the container, losing its data
+func (e *EmbeddedMongo) Stop() error {
+	return e.pool.Purge(e.resource)
+}
@@ -0,0 +1,177 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// fileStorage adapter of a file storage for mongo, backed by GridFS.
+// The key of the file is used as GridFS filename.
+type fileStorage struct {
+	db *mongo.Database
+}
+
+// fileMetadata is the metadata document stored with every GridFS file
+type fileMetadata struct {
+	Name        string            `bson:"name"`
+	ContentType string            `bson:"content_type"`
+	Metadata    map[string]string `bson:"metadata"`
+}
+
+// NewFileStorage creates a file storage for mongo
+func NewFileStorage(db *mongo.Database) ports.FileStorage {
+	return &fileStorage{
+		db: db,
+	}
+}
+
+// bucket returns a GridFS bucket with the deadlines of the received context.
+// A new bucket is created on every call since deadlines are set at bucket level.
+func (s *fileStorage) bucket(ctx context.Context) (*gridfs.Bucket, error) {
+	bucket, err := gridfs.NewBucket(s.db, options.GridFSBucket().SetName(entities.EntityNameFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		bucket.SetReadDeadline(deadline)
+		bucket.SetWriteDeadline(deadline)
+	}
+	return bucket, nil
+}
+
+func (s *fileStorage) Store(ctx context.Context, file entities.File, content io.Reader) (entities.File, error) {
+	bucket, err := s.bucket(ctx)
+	if err != nil {
+		return entities.File{}, err
+	}
+
+	metadata := fileMetadata{
+		Name:        file.Name,
+		ContentType: file.ContentType,
+		Metadata:    file.Metadata,
+	}
+	id, err := bucket.UploadFromStream(file.Key, content, options.GridFSUpload().SetMetadata(metadata))
+	if err != nil {
+		return entities.File{}, err
+	}
+
+	// previous revisions are removed once the new one has been fully uploaded
+	filter := bson.M{"filename": file.Key, "_id": bson.M{"$ne": id}}
+	previous, err := s.find(ctx, bucket, filter, nil)
+	if err != nil {
+		return entities.File{}, err
+	}
+	for _, p := range previous {
+		if err = bucket.DeleteContext(ctx, p.ID); err != nil {
+			return entities.File{}, err
+		}
+	}
+
+	return s.Stat(ctx, file.Key)
+}
+
+func (s *fileStorage) Open(ctx context.Context, key string) (io.ReadCloser, entities.File, error) {
+	bucket, err := s.bucket(ctx)
+	if err != nil {
+		return nil, entities.File{}, err
+	}
+
+	stream, err := bucket.OpenDownloadStreamByName(key)
+	if err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, entities.File{}, err
+	}
+
+	file, err := toFile(stream.GetFile())
+	if err != nil {
+		stream.Close()
+		return nil, entities.File{}, err
+	}
+	return stream, file, nil
+}
+
+func (s *fileStorage) Stat(ctx context.Context, key string) (entities.File, error) {
+	bucket, err := s.bucket(ctx)
+	if err != nil {
+		return entities.File{}, err
+	}
+
+	opts := options.GridFSFind().SetSort(bson.D{{Key: "uploadDate", Value: -1}}).SetLimit(1)
+	files, err := s.find(ctx, bucket, bson.M{"filename": key}, opts)
+	if err != nil {
+		return entities.File{}, err
+	}
+	if len(files) < 1 {
+		return entities.File{}, wrappers.NewNonExistentErr(gridfs.ErrFileNotFound)
+	}
+
+	return toFile(&files[0])
+}
+
+func (s *fileStorage) Delete(ctx context.Context, key string) error {
+	bucket, err := s.bucket(ctx)
+	if err != nil {
+		return err
+	}
+
+	files, err := s.find(ctx, bucket, bson.M{"filename": key}, nil)
+	if err != nil {
+		return err
+	}
+	if len(files) < 1 {
+		return wrappers.NewNonExistentErr(gridfs.ErrFileNotFound)
+	}
+
+	for _, f := range files {
+		if err = bucket.DeleteContext(ctx, f.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileStorage) find(ctx context.Context, bucket *gridfs.Bucket, filter interface{}, opts *options.GridFSFindOptions) ([]gridfs.File, error) {
+	var findOpts []*options.GridFSFindOptions
+	if opts != nil {
+		findOpts = append(findOpts, opts)
+	}
+
+	cursor, err := bucket.FindContext(ctx, filter, findOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []gridfs.File
+	err = cursor.All(ctx, &files)
+	return files, err
+}
+
+func toFile(f *gridfs.File) (entities.File, error) {
+	var metadata fileMetadata
+	if len(f.Metadata) > 0 {
+		if err := bson.Unmarshal(f.Metadata, &metadata); err != nil {
+			return entities.File{}, err
+		}
+	}
+
+	return entities.File{
+		Key:         f.Name,
+		Name:        metadata.Name,
+		ContentType: metadata.ContentType,
+		Size:        f.Length,
+		Metadata:    metadata.Metadata,
+		CreatedAt:   f.UploadDate,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewFileStorage_Ok checks that NewFileStorage creates a new fileStorage struct
+func TestNewFileStorage_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		storage := NewFileStorage(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, storage)
+	})
+}
+
+// TestStatFile_Ok checks that Stat returns the info of the latest revision of the file when everything goes as expected
+func TestStatFile_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		storage := fileStorage{db: mt.DB}
+
+		expectedFile := entities.File{Key: "test-key", Name: "test.png", ContentType: "image/png", Size: 12, Metadata: map[string]string{"user_id": "test-id"}}
+		ns := mt.DB.Name() + "." + entities.EntityNameFile + ".files"
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "length", Value: expectedFile.Size},
+			{Key: "filename", Value: expectedFile.Key},
+			{Key: "metadata", Value: bson.D{
+				{Key: "name", Value: expectedFile.Name},
+				{Key: "content_type", Value: expectedFile.ContentType},
+				{Key: "metadata", Value: bson.D{{Key: "user_id", Value: "test-id"}}},
+			}},
+		}))
+
+		// Act
+		file, err := storage.Stat(context.Background(), expectedFile.Key)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, expectedFile.Key, file.Key)
+		assert.Equal(t, expectedFile.Name, file.Name)
+		assert.Equal(t, expectedFile.ContentType, file.ContentType)
+		assert.Equal(t, expectedFile.Size, file.Size)
+		assert.Equal(t, expectedFile.Metadata, file.Metadata)
+	})
+}
+
+// TestStatFile_NotFound checks that Stat returns a non existent error when the file does not exist
+func TestStatFile_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		storage := fileStorage{db: mt.DB}
+
+		ns := mt.DB.Name() + "." + entities.EntityNameFile + ".files"
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := storage.Stat(context.Background(), "test-key")
+
+		// Assert
+		assert.Equal(t, wrappers.NewNonExistentErr(gridfs.ErrFileNotFound), err)
+	})
+}
+
+// TestDeleteFile_NotFound checks that Delete returns a non existent error when the file does not exist
+func TestDeleteFile_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		storage := fileStorage{db: mt.DB}
+
+		ns := mt.DB.Name() + "." + entities.EntityNameFile + ".files"
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		err := storage.Delete(context.Background(), "test-key")
+
+		// Assert
+		assert.Equal(t, wrappers.NewNonExistentErr(gridfs.ErrFileNotFound), err)
+	})
+}
@@ -0,0 +1,31 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// NewHealthChecker creates a health checker pinging the primary of the database, which has to be reachable to serve the writes
+func NewHealthChecker(db *mongo.Database) ports.HealthChecker {
+	return ports.HealthCheckerFunc(func(ctx context.Context) error {
+		return db.Client().Ping(ctx, readpref.Primary())
+	})
+}
+
+// NewStorageHealthChecker creates a health checker reading the GridFS files collection of the file storage
+func NewStorageHealthChecker(db *mongo.Database) ports.HealthChecker {
+	return ports.HealthCheckerFunc(func(ctx context.Context) error {
+		err := db.Collection(entities.EntityNameFile+".files").FindOne(ctx, bson.M{}, options.FindOne().SetProjection(bson.M{"_id": 1})).Err()
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		return err
+	})
+}
@@ -0,0 +1,62 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestHealthChecker_Ok checks that the health checker succeeds when the database answers the ping
+func TestHealthChecker_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := NewHealthChecker(mt.DB).Check(context.Background())
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestStorageHealthChecker_NoFiles checks that the storage health checker succeeds when there are no files
+func TestStorageHealthChecker_NoFiles(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+"."+entities.EntityNameFile+".files", mtest.FirstBatch))
+
+		// Act
+		err := NewStorageHealthChecker(mt.DB).Check(context.Background())
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestStorageHealthChecker_Error checks that the storage health checker fails when the files cannot be read
+func TestStorageHealthChecker_Error(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 13, Message: "unauthorized"}))
+
+		// Act
+		err := NewStorageHealthChecker(mt.DB).Check(context.Background())
+
+		// Assert
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,157 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// jobRepository adapter of a job repository for mongo
+type jobRepository struct {
+	collection *mongo.Collection
+}
+
+// NewJobRepository creates a job repository for mongo, with the index of the jobs due to be claimed
+func NewJobRepository(ctx context.Context, db *mongo.Database) (ports.JobRepository, error) {
+	r := &jobRepository{
+		collection: db.Collection(entities.EntityNameJob),
+	}
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "run_at", Value: 1}},
+	})
+	return r, err
+}
+
+func (r *jobRepository) Create(ctx context.Context, job entities.Job) (string, error) {
+	job.ID = ""
+	result, err := r.collection.InsertOne(ctx, job)
+	if err != nil {
+		return "", err
+	}
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *jobRepository) Update(ctx context.Context, job entities.Job) error {
+	_id, err := primitive.ObjectIDFromHex(job.ID)
+	if err != nil {
+		return err
+	}
+
+	job.ID = ""
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": _id}, bson.M{"$set": job}, updateComment(ctx))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, ID string) (entities.Job, error) {
+	var job entities.Job
+
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return job, err
+	}
+
+	err = r.collection.FindOne(ctx, bson.M{"_id": _id}, findOneComment(ctx)).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = wrappers.NewNonExistentErr(err)
+	}
+	return job, err
+}
+
+func (r *jobRepository) Get(ctx context.Context, filter ports.JobFilter, skip, take *int) ([]entities.Job, error) {
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if skip != nil {
+		opts.SetSkip(int64(*skip))
+	}
+	if take != nil {
+		opts.SetLimit(int64(*take))
+	}
+
+	cur, err := r.collection.Find(ctx, query, opts, findComment(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := []entities.Job{}
+	err = cur.All(ctx, &jobs)
+	return jobs, err
+}
+
+func (r *jobRepository) Claim(ctx context.Context, types []string, worker string, now, lockedUntil time.Time) (entities.Job, error) {
+	filter := bson.M{
+		"type": bson.M{"$in": types},
+		"$or": bson.A{
+			bson.M{"status": bson.M{"$in": bson.A{entities.JobStatusQueued, entities.JobStatusFailed}}, "run_at": bson.M{"$lte": now}},
+			bson.M{"status": entities.JobStatusRunning, "locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": entities.JobStatusRunning, "worker": worker, "locked_until": lockedUntil, "updated_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "run_at", Value: 1}}).SetReturnDocument(options.After)
+
+	var job entities.Job
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = wrappers.NewNonExistentErr(err)
+	}
+	return job, err
+}
+
+func (r *jobRepository) Count(ctx context.Context, statuses []entities.JobStatus) ([]ports.JobCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": bson.M{"$in": statuses}}}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"type": "$type", "status": "$status"}, "count": bson.M{"$sum": 1}}}},
+	}
+	cur, err := r.collection.Aggregate(ctx, pipeline, aggregateComment(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		ID struct {
+			Type   string             `bson:"type"`
+			Status entities.JobStatus `bson:"status"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err = cur.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	counts := []ports.JobCount{}
+	for _, result := range results {
+		counts = append(counts, ports.JobCount{Type: result.ID.Type, Status: result.ID.Status, Count: result.Count})
+	}
+	return counts, nil
+}
+
+// Expire purges or anonymizes the jobs finished before the given time, anonymized ones losing their metadata and error
+func (r *jobRepository) Expire(ctx context.Context, action entities.RetentionAction, before time.Time, dryRun bool) (int64, error) {
+	return expire(ctx, r.collection, bson.M{"finished_at": bson.M{"$lt": before}}, action, dryRun, bson.A{
+		bson.M{"$unset": bson.A{"metadata", "error"}},
+	})
+}
@@ -0,0 +1,246 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewJobRepository_Ok checks that NewJobRepository creates a new jobRepository struct with the index of the due jobs
+func TestNewJobRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewJobRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.NotEmpty(t, repo)
+	})
+}
+
+// TestCreateJob_Ok checks that Create returns the ID of the inserted job
+func TestCreateJob_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := jobRepository{collection: mt.DB.Collection(entities.EntityNameJob)}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		id, err := repo.Create(context.Background(), entities.Job{Type: entities.JobTypeBackup, Status: entities.JobStatusRunning})
+
+		// Assert
+		assert.Nil(t, err)
+		_, err = primitive.ObjectIDFromHex(id)
+		assert.Nil(t, err)
+	})
+}
+
+// TestUpdateJob_Ok checks that Update does not return an error when the job exists
+func TestUpdateJob_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := jobRepository{collection: mt.DB.Collection(entities.EntityNameJob)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.Update(context.Background(), entities.Job{ID: primitive.NewObjectID().Hex(), Status: entities.JobStatusSucceeded})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestUpdateJob_NotFound checks that Update returns a NonExistent error when the job does not exist
+func TestUpdateJob_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := jobRepository{collection: mt.DB.Collection(entities.EntityNameJob)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.Update(context.Background(), entities.Job{ID: primitive.NewObjectID().Hex()})
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestGetJobByID_Ok checks that GetByID returns the expected job
+func TestGetJobByID_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := jobRepository{collection: mt.DB.Collection(entities.EntityNameJob)}
+		id := primitive.NewObjectID()
+		ns := mt.DB.Name() + "." + entities.EntityNameJob
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "type", Value: entities.JobTypeBackup},
+			{Key: "status", Value: entities.JobStatusRunning},
+			{Key: "processed", Value: int64(10)},
+		}))
+
+		// Act
+		job, err := repo.GetByID(context.Background(), id.Hex())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, id.Hex(), job.ID)
+		assert.Equal(t, entities.JobStatusRunning, job.Status)
+		assert.Equal(t, int64(10), job.Processed)
+	})
+}
+
+// TestGetJobByID_NotFound checks that GetByID returns a NonExistent error when the job does not exist
+func TestGetJobByID_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := jobRepository{collection: mt.DB.Collection(entities.EntityNameJob)}
+		ns := mt.DB.Name() + "." + entities.EntityNameJob
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.GetByID(context.Background(), primitive.NewObjectID().Hex())
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestGetJobs_Ok checks that Get returns the jobs matching the filter, newest first
+func TestGetJobs_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := jobRepository{collection: mt.DB.Collection(entities.EntityNameJob)}
+		ns := mt.DB.Name() + "." + entities.EntityNameJob
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "type", Value: entities.JobTypeNotification},
+			{Key: "status", Value: entities.JobStatusDead},
+		}))
+		skip, take := 0, 10
+
+		// Act
+		jobs, err := repo.Get(context.Background(), ports.JobFilter{Status: entities.JobStatusDead}, &skip, &take)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, jobs, 1)
+		assert.Equal(t, entities.JobStatusDead, jobs[0].Status)
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, string(entities.JobStatusDead), command.Lookup("filter", "status").StringValue())
+		assert.Equal(t, int32(-1), command.Lookup("sort", "_id").Int32())
+	})
+}
+
+// TestCountJobs_Ok checks that Count returns the count of the jobs in the statuses by type and status
+func TestCountJobs_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := jobRepository{collection: mt.DB.Collection(entities.EntityNameJob)}
+		ns := mt.DB.Name() + "." + entities.EntityNameJob
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: bson.D{{Key: "type", Value: entities.JobTypeEmail}, {Key: "status", Value: entities.JobStatusQueued}}}, {Key: "count", Value: int32(5)}},
+			bson.D{{Key: "_id", Value: bson.D{{Key: "type", Value: entities.JobTypeAdminNotice}, {Key: "status", Value: entities.JobStatusDead}}}, {Key: "count", Value: int32(1)}},
+		))
+
+		// Act
+		counts, err := repo.Count(context.Background(), []entities.JobStatus{entities.JobStatusQueued, entities.JobStatusDead})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, []ports.JobCount{
+			{Type: entities.JobTypeEmail, Status: entities.JobStatusQueued, Count: 5},
+			{Type: entities.JobTypeAdminNotice, Status: entities.JobStatusDead, Count: 1},
+		}, counts)
+		match := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Index(0).Value().Document()
+		assert.Equal(t, string(entities.JobStatusDead), match.Lookup("$match", "status", "$in").Array().Index(1).Value().StringValue())
+	})
+}
+
+// TestClaimJob_Ok checks that Claim returns the due job locked for the worker
+func TestClaimJob_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := jobRepository{collection: mt.DB.Collection(entities.EntityNameJob)}
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+			{Key: "_id", Value: id},
+			{Key: "type", Value: entities.JobTypeNotification},
+			{Key: "status", Value: entities.JobStatusRunning},
+			{Key: "attempts", Value: 1},
+			{Key: "worker", Value: "test-worker"},
+		}}))
+		now := time.Now().UTC()
+
+		// Act
+		job, err := repo.Claim(context.Background(), []string{entities.JobTypeNotification}, "test-worker", now, now.Add(time.Minute))
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, id.Hex(), job.ID)
+		assert.Equal(t, 1, job.Attempts)
+		assert.Equal(t, "test-worker", job.Worker)
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, int32(1), command.Lookup("update", "$inc", "attempts").Int32())
+		assert.Equal(t, int32(1), command.Lookup("sort", "run_at").Int32())
+	})
+}
+
+// TestClaimJob_NoJobDue checks that Claim returns a NonExistent error when no job is due
+func TestClaimJob_NoJobDue(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := jobRepository{collection: mt.DB.Collection(entities.EntityNameJob)}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}))
+		now := time.Now().UTC()
+
+		// Act
+		_, err := repo.Claim(context.Background(), []string{entities.JobTypeBackup}, "test-worker", now, now.Add(time.Minute))
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
@@ -0,0 +1,49 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// leaseStore adapter of a lease store for mongo, whose leases are documents keyed by their name
+type leaseStore struct {
+	collection *mongo.Collection
+}
+
+// NewLeaseStore creates a lease store for mongo
+func NewLeaseStore(db *mongo.Database) ports.LeaseStore {
+	return &leaseStore{
+		collection: db.Collection(entities.EntityNameLease),
+	}
+}
+
+func (s *leaseStore) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	// only the lease held by the holder or expired matches, so the upsert of a lease held by another holder
+	// fails with a duplicate key instead of taking it
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"holder": holder},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"holder": holder, "expires_at": now.Add(ttl)}}
+
+	_, err := s.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *leaseStore) Release(ctx context.Context, name, holder string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": name, "holder": holder})
+	return err
+}
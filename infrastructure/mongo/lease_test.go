@@ -0,0 +1,72 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestAcquireLease_Ok checks that Acquire upserts the lease for the holder and reports it acquired
+func TestAcquireLease_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := leaseStore{collection: mt.DB.Collection(entities.EntityNameLease)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		acquired, err := store.Acquire(context.Background(), "test-lease", "test-holder", time.Minute)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.True(t, acquired)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.True(t, update.Lookup("upsert").Boolean())
+	})
+}
+
+// TestAcquireLease_HeldByAnother checks that Acquire reports the lease not acquired when the upsert fails with a duplicate key,
+// as the lease is held by another holder and not expired
+func TestAcquireLease_HeldByAnother(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := leaseStore{collection: mt.DB.Collection(entities.EntityNameLease)}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key error"}))
+
+		// Act
+		acquired, err := store.Acquire(context.Background(), "test-lease", "test-holder", time.Minute)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.False(t, acquired)
+	})
+}
+
+// TestReleaseLease_Ok checks that Release deletes the lease held by the holder
+func TestReleaseLease_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := leaseStore{collection: mt.DB.Collection(entities.EntityNameLease)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "acknowledged", Value: true}, {Key: "n", Value: 1}})
+
+		// Act
+		err := store.Release(context.Background(), "test-lease", "test-holder")
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,74 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// limitStore adapter of a limit store for mongo, whose windows are counted with atomic updates and removed once ended by a TTL index
+type limitStore struct {
+	collection *mongo.Collection
+}
+
+// NewLimitStore creates a limit store for mongo, creating the TTL index removing the ended windows
+func NewLimitStore(ctx context.Context, db *mongo.Database) (ports.LimitStore, error) {
+	s := &limitStore{
+		collection: db.Collection(entities.EntityNameLimit),
+	}
+
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return s, err
+}
+
+func (s *limitStore) Hit(ctx context.Context, key string, window time.Duration) (entities.Limit, error) {
+	now := time.Now().UTC()
+	// the window is restarted in the same update counting the hit, so the concurrent hits of every replica are counted once,
+	// as the TTL index may not have removed it yet
+	ended := bson.D{{Key: "$lte", Value: bson.A{"$expires_at", now}}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.D{
+		{Key: "count", Value: bson.D{{Key: "$cond", Value: bson.A{ended, 1, bson.D{{Key: "$add", Value: bson.A{"$count", 1}}}}}}},
+		{Key: "expires_at", Value: bson.D{{Key: "$cond", Value: bson.A{ended, now.Add(window), "$expires_at"}}}},
+	}}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var limit entities.Limit
+	err := s.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&limit)
+	if mongo.IsDuplicateKeyError(err) {
+		// another replica inserted the first hit of the key meanwhile, so it is updated instead
+		err = s.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&limit)
+	}
+	return limit, err
+}
+
+func (s *limitStore) Get(ctx context.Context, key string) (entities.Limit, error) {
+	var limit entities.Limit
+	err := s.collection.FindOne(ctx, bson.M{"_id": key, "expires_at": bson.M{"$gt": time.Now().UTC()}}).Decode(&limit)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return entities.Limit{Key: key}, nil
+	}
+	return limit, err
+}
+
+func (s *limitStore) Reset(ctx context.Context, key string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
+
+func (s *limitStore) Purge(ctx context.Context) (int64, error) {
+	// the TTL index removes them as well, though only once a minute
+	result, err := s.collection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lte": time.Now().UTC()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
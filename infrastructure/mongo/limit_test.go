@@ -0,0 +1,115 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewLimitStore_Ok checks that NewLimitStore creates the TTL index removing the ended windows
+func TestNewLimitStore_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		_, err := NewLimitStore(context.Background(), mt.DB)
+
+		// Assert
+		assert.Nil(t, err)
+		index := mt.GetStartedEvent().Command.Lookup("indexes").Array().Index(0).Value().Document()
+		assert.Equal(t, int32(0), index.Lookup("expireAfterSeconds").Int32())
+	})
+}
+
+// TestHitLimit_Ok checks that Hit upserts the window of the key and returns its hits
+func TestHitLimit_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := limitStore{collection: mt.DB.Collection(entities.EntityNameLimit)}
+		expiresAt := time.Now().UTC().Add(time.Minute).Truncate(time.Millisecond)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+			{Key: "_id", Value: "ip:10.0.0.1"},
+			{Key: "count", Value: int64(2)},
+			{Key: "expires_at", Value: expiresAt},
+		}}))
+
+		// Act
+		limit, err := store.Hit(context.Background(), "ip:10.0.0.1", time.Minute)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, entities.Limit{Key: "ip:10.0.0.1", Count: 2, ExpiresAt: expiresAt}, limit)
+		command := mt.GetStartedEvent().Command
+		assert.True(t, command.Lookup("upsert").Boolean())
+		assert.Equal(t, bson.TypeArray, command.Lookup("update").Type)
+	})
+}
+
+// TestGetLimit_NotFound checks that Get returns no hits when the window of the key has ended or the key was never hit
+func TestGetLimit_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := limitStore{collection: mt.DB.Collection(entities.EntityNameLimit)}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.limits", mtest.FirstBatch))
+
+		// Act
+		limit, err := store.Get(context.Background(), "login:test@example.com")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, entities.Limit{Key: "login:test@example.com"}, limit)
+	})
+}
+
+// TestResetLimit_Ok checks that Reset deletes the window of the key
+func TestResetLimit_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := limitStore{collection: mt.DB.Collection(entities.EntityNameLimit)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "acknowledged", Value: true}, {Key: "n", Value: 1}})
+
+		// Act
+		err := store.Reset(context.Background(), "login:test@example.com")
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestPurgeLimit_Ok checks that Purge deletes the ended windows and returns how many were deleted
+func TestPurgeLimit_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := limitStore{collection: mt.DB.Collection(entities.EntityNameLimit)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "acknowledged", Value: true}, {Key: "n", Value: 2}})
+
+		// Act
+		count, err := store.Purge(context.Background())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
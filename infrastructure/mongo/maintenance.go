@@ -0,0 +1,41 @@
+package mongo
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maintenanceStore adapter of a maintenance store for mongo
+type maintenanceStore struct {
+	db *mongo.Database
+}
+
+// NewMaintenanceStore creates a maintenance store for mongo
+func NewMaintenanceStore(db *mongo.Database) ports.MaintenanceStore {
+	return &maintenanceStore{db: db}
+}
+
+// Collections returns the collections of the database, leaving out the views and the system collections
+func (s *maintenanceStore) Collections(ctx context.Context) ([]string, error) {
+	filter := bson.M{
+		"type": "collection",
+		"name": bson.M{"$not": primitive.Regex{Pattern: `^system\.`}},
+	}
+	names, err := s.db.ListCollectionNames(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// RebuildIndexes compacts the collection, rewriting its data and rebuilding its indexes,
+// as reIndex is only allowed on standalone servers
+func (s *maintenanceStore) RebuildIndexes(ctx context.Context, collection string) error {
+	return s.db.RunCommand(ctx, bson.D{{Key: "compact", Value: collection}}).Err()
+}
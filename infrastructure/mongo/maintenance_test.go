@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestCollections_Ok checks that Collections returns the names of the collections in alphabetical order
+func TestCollections_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := maintenanceStore{db: mt.DB}
+		ns := mt.DB.Name() + ".$cmd.listCollections"
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "name", Value: "users"}, {Key: "type", Value: "collection"}},
+			bson.D{{Key: "name", Value: "jobs"}, {Key: "type", Value: "collection"}},
+		))
+
+		// Act
+		names, err := store.Collections(context.Background())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"jobs", "users"}, names)
+	})
+}
+
+// TestRebuildIndexes_Ok checks that RebuildIndexes compacts the collection
+func TestRebuildIndexes_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := maintenanceStore{db: mt.DB}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := store.RebuildIndexes(context.Background(), "users")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "users", mt.GetStartedEvent().Command.Lookup("compact").StringValue())
+	})
+}
@@ -0,0 +1,287 @@
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// redactedValue replaces the values of the filters and documents of the logged commands
+const redactedValue = "?"
+
+// serverStatusTimeout is the maximum time given to the serverStatus command when the metrics are read
+const serverStatusTimeout = 5 * time.Second
+
+// tracerName is the name of the tracer of the command spans
+const tracerName = "github.com/sergicanet9/go-hexagonal-api/infrastructure/mongo"
+
+// sessionFields are the command fields added by the driver, not worth logging
+var sessionFields = map[string]bool{
+	"lsid":             true,
+	"txnNumber":        true,
+	"autocommit":       true,
+	"startTransaction": true,
+	"$db":              true,
+	"$clusterTime":     true,
+	"$readPreference":  true,
+}
+
+// serverStatusFields are the sections of the serverStatus command exported as metrics
+var serverStatusFields = []string{"uptime", "connections", "opcounters", "network", "mem"}
+
+// commands holds the duration metrics of the commands run by every monitored client, exported as mongo_commands
+var commands = newCommandMetrics()
+
+// statusDB is the database whose server status is exported as mongo_server_status
+var statusDB atomic.Pointer[mongo.Database]
+
+func init() {
+	expvar.Publish("mongo_commands", commands)
+	expvar.Publish("mongo_server_status", expvar.Func(serverStatus))
+}
+
+// Connect opens a connection to the MongoDB with the given command monitor and ensures that the db is reachable,
+// closing the connection when it is not so that it can be retried
+func Connect(ctx context.Context, dsn string, monitor *event.CommandMonitor) (*mongo.Database, error) {
+	cs, err := connstring.ParseAndValidate(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("DSN not valid: %s", err)
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(dsn).SetMonitor(monitor))
+	if err != nil {
+		return nil, fmt.Errorf("an unexpected error happened while opening the connection: %s", err)
+	}
+
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+	return client.Database(cs.Database), nil
+}
+
+// ExportServerStatus sets the database whose server status is exported with the metrics
+func ExportServerStatus(db *mongo.Database) {
+	statusDB.Store(db)
+}
+
+func serverStatus() interface{} {
+	db := statusDB.Load()
+	if db == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), serverStatusTimeout)
+	defer cancel()
+
+	var status bson.M
+	err := db.RunCommand(ctx, bson.D{{Key: "serverStatus", Value: 1}}).Decode(&status)
+	if err != nil {
+		return map[string]string{"error": err.Error()}
+	}
+
+	result := make(bson.M, len(serverStatusFields))
+	for _, f := range serverStatusFields {
+		if v, ok := status[f]; ok {
+			result[f] = v
+		}
+	}
+	return result
+}
+
+// commandStats duration metrics of the commands run against a namespace
+type commandStats struct {
+	Count    int64   `json:"count"`
+	Failures int64   `json:"failures"`
+	TotalMS  float64 `json:"total_ms"`
+	MaxMS    float64 `json:"max_ms"`
+}
+
+// commandMetrics duration metrics of the commands per namespace and operation, like users.find
+type commandMetrics struct {
+	mu    sync.Mutex
+	stats map[string]*commandStats
+}
+
+func newCommandMetrics() *commandMetrics {
+	return &commandMetrics{
+		stats: make(map[string]*commandStats),
+	}
+}
+
+func (m *commandMetrics) observe(key string, duration time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.stats[key]
+	if !ok {
+		s = &commandStats{}
+		m.stats[key] = s
+	}
+
+	ms := float64(duration) / float64(time.Millisecond)
+	s.Count++
+	s.TotalMS += ms
+	if ms > s.MaxMS {
+		s.MaxMS = ms
+	}
+	if failed {
+		s.Failures++
+	}
+}
+
+// String implements the expvar.Var interface
+func (m *commandMetrics) String() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, err := json.Marshal(m.stats)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+// startedCommand the data of a started command needed once it finishes
+type startedCommand struct {
+	key     string
+	command bson.Raw
+	span    trace.Span
+	timings *models.RequestTimings
+}
+
+// NewCommandMonitor creates a command monitor that records the duration of every command, also adding it to the database time of its request, traces it as a span child of the span of its context
+// and logs as warnings, with their filters redacted, the ones lasting at least the given threshold.
+// A zero threshold disables the slow query logging.
+func NewCommandMonitor(threshold time.Duration, logger zerolog.Logger, tp trace.TracerProvider) *event.CommandMonitor {
+	var started sync.Map
+	tracer := tp.Tracer(tracerName)
+
+	finished := func(e event.CommandFinishedEvent, failure string) {
+		v, ok := started.LoadAndDelete(e.RequestID)
+		if !ok {
+			return
+		}
+		c := v.(startedCommand)
+
+		duration := time.Duration(e.DurationNanos)
+		commands.observe(c.key, duration, failure != "")
+		c.timings.AddDatabase(duration)
+
+		if failure != "" {
+			c.span.SetStatus(codes.Error, failure)
+		}
+		c.span.End()
+
+		if threshold > 0 && duration >= threshold {
+			event := logger.Warn().Str("command", c.key).Dur("duration", duration).Str("query", redactCommand(c.command))
+			if failure != "" {
+				event = event.Str("error", failure)
+			}
+			event.Msg("slow query")
+		}
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			key := commandKey(e)
+			_, span := tracer.Start(ctx, key, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+				attribute.String("db.system", "mongodb"),
+				attribute.String("db.name", e.DatabaseName),
+				attribute.String("db.operation", e.CommandName),
+			))
+			if span.IsRecording() {
+				span.SetAttributes(attribute.String("db.statement", redactCommand(e.Command)))
+			}
+
+			started.Store(e.RequestID, startedCommand{
+				key:     key,
+				command: append(bson.Raw(nil), e.Command...),
+				span:    span,
+				timings: models.RequestTimingsFrom(ctx),
+			})
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			finished(e.CommandFinishedEvent, "")
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			finished(e.CommandFinishedEvent, e.Failure)
+		},
+	}
+}
+
+// commandKey returns the namespace and operation of a command, using the database as namespace for the commands not run against a collection
+func commandKey(e *event.CommandStartedEvent) string {
+	namespace := e.DatabaseName
+	if collection, ok := e.Command.Lookup(e.CommandName).StringValueOK(); ok {
+		namespace = collection
+	}
+	return namespace + "." + e.CommandName
+}
+
+// redactCommand returns the command as extended JSON without the session fields
+// and with every value nested in documents or arrays, such as filters, replaced
+func redactCommand(command bson.Raw) string {
+	elems, err := command.Elements()
+	if err != nil {
+		return ""
+	}
+
+	redacted := bson.D{}
+	for _, e := range elems {
+		if sessionFields[e.Key()] {
+			continue
+		}
+		v := e.Value()
+		switch v.Type {
+		case bsontype.EmbeddedDocument, bsontype.Array:
+			redacted = append(redacted, bson.E{Key: e.Key(), Value: redactValue(v)})
+		default:
+			redacted = append(redacted, bson.E{Key: e.Key(), Value: v})
+		}
+	}
+
+	b, err := bson.MarshalExtJSON(redacted, false, false)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// redactValue keeps the shape of documents and arrays replacing every other value
+func redactValue(v bson.RawValue) interface{} {
+	switch v.Type {
+	case bsontype.EmbeddedDocument:
+		elems, _ := v.Document().Elements()
+		doc := bson.D{}
+		for _, e := range elems {
+			doc = append(doc, bson.E{Key: e.Key(), Value: redactValue(e.Value())})
+		}
+		return doc
+	case bsontype.Array:
+		values, _ := v.Array().Values()
+		arr := bson.A{}
+		for _, e := range values {
+			arr = append(arr, redactValue(e))
+		}
+		return arr
+	default:
+		return redactedValue
+	}
+}
@@ -0,0 +1,187 @@
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func startedEvent(t *testing.T, requestID int64, command bson.D) *event.CommandStartedEvent {
+	t.Helper()
+
+	raw, err := bson.Marshal(command)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &event.CommandStartedEvent{
+		Command:      raw,
+		DatabaseName: "test-db",
+		CommandName:  command[0].Key,
+		RequestID:    requestID,
+	}
+}
+
+// TestNewCommandMonitor_SucceededCommand checks that the monitor records the duration of a succeeded command under its collection and operation
+func TestNewCommandMonitor_SucceededCommand(t *testing.T) {
+	// Arrange
+	monitor := NewCommandMonitor(0, zerolog.Nop(), noop.NewTracerProvider())
+	started := startedEvent(t, 1, bson.D{{Key: "find", Value: "monitor_succeeded"}, {Key: "filter", Value: bson.D{{Key: "email", Value: "test@test.com"}}}})
+	duration := 5 * time.Millisecond
+
+	// Act
+	monitor.Started(context.Background(), started)
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{DurationNanos: duration.Nanoseconds(), CommandName: "find", RequestID: 1},
+	})
+
+	// Assert
+	stats := commands.stats["monitor_succeeded.find"]
+	assert.Equal(t, int64(1), stats.Count)
+	assert.Equal(t, int64(0), stats.Failures)
+	assert.Equal(t, float64(5), stats.MaxMS)
+}
+
+// TestNewCommandMonitor_FailedCommand checks that the monitor records a failed command as a failure
+func TestNewCommandMonitor_FailedCommand(t *testing.T) {
+	// Arrange
+	monitor := NewCommandMonitor(0, zerolog.Nop(), noop.NewTracerProvider())
+	started := startedEvent(t, 2, bson.D{{Key: "insert", Value: "monitor_failed"}})
+
+	// Act
+	monitor.Started(context.Background(), started)
+	monitor.Failed(context.Background(), &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{DurationNanos: time.Millisecond.Nanoseconds(), CommandName: "insert", RequestID: 2},
+		Failure:              "duplicate key",
+	})
+
+	// Assert
+	stats := commands.stats["monitor_failed.insert"]
+	assert.Equal(t, int64(1), stats.Count)
+	assert.Equal(t, int64(1), stats.Failures)
+}
+
+// TestNewCommandMonitor_Span checks that the monitor traces a command as a span child of the span of its context, with its statement redacted and its failure
+func TestNewCommandMonitor_Span(t *testing.T) {
+	// Arrange
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	monitor := NewCommandMonitor(0, zerolog.Nop(), tp)
+	started := startedEvent(t, 3, bson.D{{Key: "find", Value: "monitor_span"}, {Key: "filter", Value: bson.D{{Key: "email", Value: "test@test.com"}}}})
+
+	// Act
+	monitor.Started(ctx, started)
+	monitor.Failed(ctx, &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{DurationNanos: time.Millisecond.Nanoseconds(), CommandName: "find", RequestID: 3},
+		Failure:              "timeout",
+	})
+
+	// Assert
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "monitor_span.find", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.statement", `{"find":"monitor_span","filter":{"email":"?"}}`))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+// TestNewCommandMonitor_SlowQuery checks that the monitor logs the commands exceeding the threshold with their filters redacted
+func TestNewCommandMonitor_SlowQuery(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	monitor := NewCommandMonitor(time.Millisecond, zerolog.New(&buf), noop.NewTracerProvider())
+	started := startedEvent(t, 3, bson.D{{Key: "find", Value: "monitor_slow"}, {Key: "filter", Value: bson.D{{Key: "email", Value: "test@test.com"}}}})
+
+	// Act
+	monitor.Started(context.Background(), started)
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{DurationNanos: (2 * time.Millisecond).Nanoseconds(), CommandName: "find", RequestID: 3},
+	})
+
+	// Assert
+	assert.Contains(t, buf.String(), `"command":"monitor_slow.find","duration":2`)
+	assert.Contains(t, buf.String(), `"message":"slow query"`)
+	assert.NotContains(t, buf.String(), "test@test.com")
+}
+
+// TestNewCommandMonitor_FastQuery checks that the monitor does not log the commands under the threshold
+func TestNewCommandMonitor_FastQuery(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	monitor := NewCommandMonitor(time.Second, zerolog.New(&buf), noop.NewTracerProvider())
+	started := startedEvent(t, 4, bson.D{{Key: "find", Value: "monitor_fast"}})
+
+	// Act
+	monitor.Started(context.Background(), started)
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{DurationNanos: time.Millisecond.Nanoseconds(), CommandName: "find", RequestID: 4},
+	})
+
+	// Assert
+	assert.Empty(t, buf.String())
+}
+
+// TestNewCommandMonitor_RequestTimings checks that the monitor adds the duration of a command to the database time of the request timings of its context
+func TestNewCommandMonitor_RequestTimings(t *testing.T) {
+	// Arrange
+	timings := &models.RequestTimings{}
+	ctx := models.WithRequestTimings(context.Background(), timings)
+	monitor := NewCommandMonitor(0, zerolog.Nop(), noop.NewTracerProvider())
+	started := startedEvent(t, 6, bson.D{{Key: "find", Value: "monitor_timings"}})
+
+	// Act
+	monitor.Started(ctx, started)
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{DurationNanos: (3 * time.Millisecond).Nanoseconds(), CommandName: "find", RequestID: 6},
+	})
+
+	// Assert
+	assert.Equal(t, 3*time.Millisecond, timings.Database())
+}
+
+// TestCommandKey_NoCollection checks that commandKey uses the database as namespace for the commands not run against a collection
+func TestCommandKey_NoCollection(t *testing.T) {
+	// Arrange
+	started := startedEvent(t, 5, bson.D{{Key: "ping", Value: 1}})
+
+	// Act
+	key := commandKey(started)
+
+	// Assert
+	assert.Equal(t, "test-db.ping", key)
+}
+
+// TestRedactCommand_Ok checks that redactCommand replaces the nested values and removes the session fields
+func TestRedactCommand_Ok(t *testing.T) {
+	// Arrange
+	command, err := bson.Marshal(bson.D{
+		{Key: "find", Value: "users"},
+		{Key: "filter", Value: bson.D{{Key: "email", Value: "test@test.com"}, {Key: "claims", Value: bson.D{{Key: "$in", Value: bson.A{0, 1}}}}}},
+		{Key: "limit", Value: int64(10)},
+		{Key: "$db", Value: "test-db"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"find":"users","filter":{"email":"?","claims":{"$in":["?","?"]}},"limit":10}`
+
+	// Act
+	result := redactCommand(command)
+
+	// Assert
+	assert.Equal(t, expected, result)
+}
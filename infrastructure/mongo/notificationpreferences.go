@@ -0,0 +1,54 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// notificationPreferencesRepository adapter of a notification preferences repository for mongo, storing the preferences by the ID of their user
+type notificationPreferencesRepository struct {
+	collection *mongo.Collection
+}
+
+// NewNotificationPreferencesRepository creates a notification preferences repository for mongo
+func NewNotificationPreferencesRepository(db *mongo.Database) ports.NotificationPreferencesRepository {
+	return &notificationPreferencesRepository{
+		collection: db.Collection(entities.EntityNameNotificationPreferences),
+	}
+}
+
+func (r *notificationPreferencesRepository) Get(ctx context.Context, userID string) (entities.NotificationPreferences, error) {
+	var preferences entities.NotificationPreferences
+	err := r.collection.FindOne(ctx, bson.M{"_id": userID}, findOneComment(ctx)).Decode(&preferences)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("notification preferences of user ID %s not found", userID))
+	}
+	return preferences, err
+}
+
+// Update sets the channels in a single update, merging them into the ones stored, or into every channel enabled for the categories never set
+func (r *notificationPreferencesRepository) Update(ctx context.Context, userID string, channels map[string]map[string]bool, at time.Time) (entities.NotificationPreferences, error) {
+	set := bson.M{"updated_at": at}
+	for category, toggles := range channels {
+		set["categories."+category] = bson.M{"$mergeObjects": bson.A{entities.AllNotificationChannels(), "$categories." + category, toggles}}
+	}
+
+	var preferences entities.NotificationPreferences
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": userID}, mongo.Pipeline{{{Key: "$set", Value: set}}}, opts).Decode(&preferences)
+	return preferences, err
+}
+
+func (r *notificationPreferencesRepository) Delete(ctx context.Context, userID string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": userID})
+	return err
+}
@@ -0,0 +1,87 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestGetNotificationPreferences_Ok checks that Get returns the preferences stored by the ID of the user
+func TestGetNotificationPreferences_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := NewNotificationPreferencesRepository(mt.DB)
+		ns := mt.DB.Name() + "." + entities.EntityNameNotificationPreferences
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "test-user"},
+			{Key: "categories", Value: bson.D{{Key: entities.NotificationCategorySecurity, Value: bson.D{{Key: "email", Value: true}}}}},
+		}))
+
+		// Act
+		preferences, err := repo.Get(context.Background(), "test-user")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "test-user", preferences.UserID)
+		assert.Equal(t, entities.NotificationChannels{Email: true}, preferences.Categories[entities.NotificationCategorySecurity])
+	})
+}
+
+// TestGetNotificationPreferences_NotFound checks that Get returns a non existent error when the user never set the preferences
+func TestGetNotificationPreferences_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := NewNotificationPreferencesRepository(mt.DB)
+		ns := mt.DB.Name() + "." + entities.EntityNameNotificationPreferences
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.Get(context.Background(), "test-user")
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	})
+}
+
+// TestUpdateNotificationPreferences_Ok checks that Update merges the channels given into the stored ones in a single upsert, returning the preferences updated
+func TestUpdateNotificationPreferences_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := NewNotificationPreferencesRepository(mt.DB)
+		updated := bson.D{
+			{Key: "_id", Value: "test-user"},
+			{Key: "categories", Value: bson.D{{Key: entities.NotificationCategorySecurity, Value: bson.D{{Key: "email", Value: true}, {Key: "sms", Value: true}, {Key: "push", Value: false}}}}},
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: updated}})
+
+		// Act
+		preferences, err := repo.Update(context.Background(), "test-user", map[string]map[string]bool{entities.NotificationCategorySecurity: {entities.NotificationChannelPush: false}}, time.Now())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, entities.NotificationChannels{Email: true, SMS: true}, preferences.Categories[entities.NotificationCategorySecurity])
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, "test-user", command.Lookup("query", "_id").StringValue())
+		assert.True(t, command.Lookup("upsert").Boolean())
+		set := command.Lookup("update").Array().Index(0).Value().Document().Lookup("$set").Document()
+		merged := set.Lookup("categories."+entities.NotificationCategorySecurity, "$mergeObjects").Array()
+		assert.Equal(t, "$categories."+entities.NotificationCategorySecurity, merged.Index(1).Value().StringValue())
+		assert.False(t, merged.Index(2).Value().Document().Lookup(entities.NotificationChannelPush).Boolean())
+	})
+}
@@ -0,0 +1,207 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// organizationRepository adapter of an organization repository for mongo, whose members and invitations are stored in the document of their organization
+type organizationRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewOrganizationRepository creates an organization repository for mongo, creating the indexes of the organizations by member and by invitee
+func NewOrganizationRepository(ctx context.Context, db *mongo.Database) (ports.OrganizationRepository, error) {
+	r := &organizationRepository{
+		MongoRepository: infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameOrganization),
+			Target:     entities.Organization{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "members.user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "invitations.user_id", Value: 1}}},
+	})
+	return r, err
+}
+
+func (r *organizationRepository) GetByMember(ctx context.Context, userID string) ([]entities.Organization, error) {
+	cur, err := r.Collection.Find(ctx, bson.M{"members.user_id": userID}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	organizations := []entities.Organization{}
+	err = cur.All(ctx, &organizations)
+	return organizations, err
+}
+
+func (r *organizationRepository) GetByInvitee(ctx context.Context, userID string) ([]entities.Organization, error) {
+	cur, err := r.Collection.Find(ctx, bson.M{"invitations.user_id": userID}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	organizations := []entities.Organization{}
+	err = cur.All(ctx, &organizations)
+	return organizations, err
+}
+
+func (r *organizationRepository) Invite(ctx context.Context, ID string, invitation entities.OrganizationInvitation) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	// the filter does not match when the user is a member or invited already, so concurrent invitations invite it once
+	filter := bson.M{
+		"_id":                 _id,
+		"members.user_id":     bson.M{"$ne": invitation.UserID},
+		"invitations.user_id": bson.M{"$ne": invitation.UserID},
+	}
+	update := bson.M{
+		"$push": bson.M{"invitations": invitation},
+		"$set":  bson.M{"updated_at": time.Now().UTC()},
+	}
+	result, err := r.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("organization ID %s not found or user ID %s a member or invited already", ID, invitation.UserID))
+	}
+	return nil
+}
+
+func (r *organizationRepository) AcceptInvitation(ctx context.Context, ID, userID string, joinedAt time.Time) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	// the invitation is moved to the members in a single update, so a concurrent revocation either prevents it or finds it accepted
+	invited := bson.M{"$eq": bson.A{"$$this.user_id", userID}}
+	filter := bson.M{"_id": _id, "invitations.user_id": userID}
+	update := bson.A{bson.M{"$set": bson.M{
+		"members": bson.M{"$concatArrays": bson.A{"$members", bson.M{"$map": bson.M{
+			"input": bson.M{"$filter": bson.M{"input": "$invitations", "cond": invited}},
+			"in":    bson.M{"user_id": "$$this.user_id", "role": "$$this.role", "joined_at": joinedAt},
+		}}}},
+		"invitations": bson.M{"$filter": bson.M{"input": "$invitations", "cond": bson.M{"$not": bson.A{invited}}}},
+		"updated_at":  joinedAt,
+	}}}
+	result, err := r.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("user ID %s not invited to organization ID %s", userID, ID))
+	}
+	return nil
+}
+
+func (r *organizationRepository) RemoveInvitation(ctx context.Context, ID, userID string) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": _id, "invitations.user_id": userID}
+	update := bson.M{
+		"$pull": bson.M{"invitations": bson.M{"user_id": userID}},
+		"$set":  bson.M{"updated_at": time.Now().UTC()},
+	}
+	result, err := r.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("user ID %s not invited to organization ID %s", userID, ID))
+	}
+	return nil
+}
+
+func (r *organizationRepository) TransferOwnership(ctx context.Context, ID, ownerID, userID string) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": _id, "owner_id": ownerID, "members.user_id": userID}
+	update := bson.M{"$set": bson.M{
+		"owner_id":             userID,
+		"members.$[next].role": entities.OrganizationRoleOwner,
+		"members.$[prev].role": entities.OrganizationRoleAdmin,
+		"updated_at":           time.Now().UTC(),
+	}}
+	arrayFilters := options.ArrayFilters{Filters: []interface{}{bson.M{"next.user_id": userID}, bson.M{"prev.user_id": ownerID}}}
+	result, err := r.Collection.UpdateOne(ctx, filter, update, options.Update().SetArrayFilters(arrayFilters))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("organization ID %s not owned by user ID %s or user ID %s not a member", ID, ownerID, userID))
+	}
+	return nil
+}
+
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, ID, userID, role string) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": _id, "members.user_id": userID}
+	update := bson.M{"$set": bson.M{"members.$.role": role, "updated_at": time.Now().UTC()}}
+	result, err := r.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("user ID %s not a member of organization ID %s", userID, ID))
+	}
+	return nil
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, ID, userID string) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": _id, "members.user_id": userID}
+	update := bson.M{
+		"$pull": bson.M{"members": bson.M{"user_id": userID}},
+		"$set":  bson.M{"updated_at": time.Now().UTC()},
+	}
+	result, err := r.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("user ID %s not a member of organization ID %s", userID, ID))
+	}
+	return nil
+}
+
+func (r *organizationRepository) RemoveUser(ctx context.Context, userID string) error {
+	filter := bson.M{"$or": bson.A{bson.M{"members.user_id": userID}, bson.M{"invitations.user_id": userID}}}
+	update := bson.M{
+		"$pull": bson.M{"members": bson.M{"user_id": userID}, "invitations": bson.M{"user_id": userID}},
+		"$set":  bson.M{"updated_at": time.Now().UTC()},
+	}
+	_, err := r.Collection.UpdateMany(ctx, filter, update)
+	return err
+}
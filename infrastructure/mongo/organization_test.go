@@ -0,0 +1,242 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// testOrganizationID an organization ID in the format of the IDs of Mongo
+const testOrganizationID = "64b7f0c2a1b2c3d4e5f60718"
+
+// newTestOrganizationRepository returns an organization repository on the collection of the mocked database
+func newTestOrganizationRepository(mt *mtest.T) organizationRepository {
+	return organizationRepository{
+		MongoRepository: infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.DB.Collection(entities.EntityNameOrganization),
+			Target:     entities.Organization{},
+		},
+	}
+}
+
+// TestNewOrganizationRepository_Ok checks that NewOrganizationRepository creates the indexes of the organizations by member and by invitee
+func TestNewOrganizationRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		_, err := NewOrganizationRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.Nil(t, err)
+		indexes := mt.GetStartedEvent().Command.Lookup("indexes").Array()
+		assert.Equal(t, "members.user_id_1", indexes.Index(0).Value().Document().Lookup("name").StringValue())
+		assert.Equal(t, "invitations.user_id_1", indexes.Index(1).Value().Document().Lookup("name").StringValue())
+	})
+}
+
+// TestGetOrganizationsByMember_Ok checks that GetByMember returns the organizations of the member found in the collection
+func TestGetOrganizationsByMember_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestOrganizationRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "first"}, {Key: "name", Value: "first"}, {Key: "members", Value: bson.A{bson.D{{Key: "user_id", Value: "test-user"}, {Key: "role", Value: "owner"}}}}},
+			bson.D{{Key: "_id", Value: "second"}, {Key: "name", Value: "second"}, {Key: "members", Value: bson.A{bson.D{{Key: "user_id", Value: "test-user"}, {Key: "role", Value: "member"}}}}},
+		))
+
+		// Act
+		organizations, err := repo.GetByMember(context.Background(), "test-user")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, organizations, 2)
+		assert.Equal(t, entities.OrganizationRoleOwner, organizations[0].Members[0].Role)
+		assert.Equal(t, "second", organizations[1].ID)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "test-user", filter.Lookup("members.user_id").StringValue())
+	})
+}
+
+// TestInviteOrganizationMember_Ok checks that Invite pushes the invitation to the organization, unless the user is a member or invited already
+func TestInviteOrganizationMember_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestOrganizationRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+		invitation := entities.OrganizationInvitation{UserID: "test-user", Role: entities.OrganizationRoleMember, InvitedBy: "test-owner", InvitedAt: time.Now().UTC()}
+
+		// Act
+		err := repo.Invite(context.Background(), testOrganizationID, invitation)
+
+		// Assert
+		assert.Nil(t, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "test-user", update.Lookup("q", "members.user_id", "$ne").StringValue())
+		assert.Equal(t, "test-user", update.Lookup("q", "invitations.user_id", "$ne").StringValue())
+		assert.Equal(t, "test-user", update.Lookup("u", "$push", "invitations", "user_id").StringValue())
+	})
+}
+
+// TestInviteOrganizationMember_NotFound checks that Invite returns a non existent error when the organization is not found or the user is a member or invited already
+func TestInviteOrganizationMember_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestOrganizationRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.Invite(context.Background(), testOrganizationID, entities.OrganizationInvitation{UserID: "test-user"})
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	})
+}
+
+// TestAcceptOrganizationInvitation_Ok checks that AcceptInvitation moves the invitation of the user to the members in a single update
+func TestAcceptOrganizationInvitation_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestOrganizationRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.AcceptInvitation(context.Background(), testOrganizationID, "test-user", time.Now().UTC())
+
+		// Assert
+		assert.Nil(t, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "test-user", update.Lookup("q", "invitations.user_id").StringValue())
+		set := update.Lookup("u").Array().Index(0).Value().Document().Lookup("$set").Document()
+		assert.NotNil(t, set.Lookup("members", "$concatArrays").Array())
+		assert.NotNil(t, set.Lookup("invitations", "$filter").Document())
+	})
+}
+
+// TestAcceptOrganizationInvitation_NotFound checks that AcceptInvitation returns a non existent error when the user is not invited
+func TestAcceptOrganizationInvitation_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestOrganizationRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.AcceptInvitation(context.Background(), testOrganizationID, "test-user", time.Now().UTC())
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	})
+}
+
+// TestTransferOrganizationOwnership_Ok checks that TransferOwnership swaps the roles of the owner and the member along with the owner ID
+func TestTransferOrganizationOwnership_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestOrganizationRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.TransferOwnership(context.Background(), testOrganizationID, "test-owner", "test-user")
+
+		// Assert
+		assert.Nil(t, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "test-owner", update.Lookup("q", "owner_id").StringValue())
+		assert.Equal(t, "test-user", update.Lookup("u", "$set", "owner_id").StringValue())
+		assert.Equal(t, entities.OrganizationRoleOwner, update.Lookup("u", "$set", "members.$[next].role").StringValue())
+		assert.Equal(t, entities.OrganizationRoleAdmin, update.Lookup("u", "$set", "members.$[prev].role").StringValue())
+	})
+}
+
+// TestUpdateOrganizationMemberRole_Ok checks that UpdateMemberRole sets the role of the member matched in the organization
+func TestUpdateOrganizationMemberRole_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestOrganizationRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.UpdateMemberRole(context.Background(), testOrganizationID, "test-user", entities.OrganizationRoleAdmin)
+
+		// Assert
+		assert.Nil(t, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, entities.OrganizationRoleAdmin, update.Lookup("u", "$set", "members.$.role").StringValue())
+	})
+}
+
+// TestRemoveOrganizationMember_NotFound checks that RemoveMember returns a non existent error when the user is not a member
+func TestRemoveOrganizationMember_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestOrganizationRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.RemoveMember(context.Background(), testOrganizationID, "test-user")
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+		assert.EqualError(t, err, "user ID test-user not a member of organization ID "+testOrganizationID)
+	})
+}
+
+// TestRemoveOrganizationUser_Ok checks that RemoveUser pulls the user from every organization it is a member of or invited to
+func TestRemoveOrganizationUser_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestOrganizationRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}, {Key: "nModified", Value: 2}})
+
+		// Act
+		err := repo.RemoveUser(context.Background(), "test-user")
+
+		// Assert
+		assert.Nil(t, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.True(t, update.Lookup("multi").Boolean())
+		assert.Equal(t, "test-user", update.Lookup("u", "$pull", "members", "user_id").StringValue())
+		assert.Equal(t, "test-user", update.Lookup("u", "$pull", "invitations", "user_id").StringValue())
+	})
+}
@@ -0,0 +1,99 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongosMsg is the msg of the hello response of a mongos router
+const mongosMsg = "isdbgrid"
+
+// CheckServer verifies that the server version and its feature compatibility version are at least minVersion
+// and, when requireReplicaSet is set, that the server is a replica set member or a mongos router, as the transactions require them.
+// It returns the version, the feature compatibility version and the topology of the server, the feature compatibility version
+// being left out when it cannot be read, as it requires privileges on the admin database.
+func CheckServer(ctx context.Context, db *mongo.Database, minVersion string, requireReplicaSet bool) (string, error) {
+	admin := db.Client().Database("admin")
+
+	var build struct {
+		Version string `bson:"version"`
+	}
+	if err := admin.RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&build); err != nil {
+		return "", err
+	}
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return "", err
+	}
+	var param struct {
+		FCV struct {
+			Version string `bson:"version"`
+		} `bson:"featureCompatibilityVersion"`
+	}
+	fcvErr := admin.RunCommand(ctx, bson.D{{Key: "getParameter", Value: 1}, {Key: "featureCompatibilityVersion", Value: 1}}).Decode(&param)
+
+	topology := "standalone"
+	switch {
+	case hello.Msg == mongosMsg:
+		topology = "mongos"
+	case hello.SetName != "":
+		topology = "replica set " + hello.SetName
+	}
+	details := []string{"version " + build.Version}
+	if fcvErr == nil {
+		details = append(details, "feature compatibility "+param.FCV.Version)
+	}
+	details = append(details, topology)
+	detail := strings.Join(details, ", ")
+
+	if older, err := olderVersion(build.Version, minVersion); err != nil {
+		return detail, err
+	} else if older {
+		return detail, fmt.Errorf("server version %s older than %s", build.Version, minVersion)
+	}
+	if fcvErr == nil {
+		if older, err := olderVersion(param.FCV.Version, minVersion); err != nil {
+			return detail, err
+		} else if older {
+			return detail, fmt.Errorf("feature compatibility version %s older than %s", param.FCV.Version, minVersion)
+		}
+	}
+	if requireReplicaSet && topology == "standalone" {
+		return detail, fmt.Errorf("standalone server not supported, the transactions require a replica set or a mongos router")
+	}
+	return detail, nil
+}
+
+// olderVersion reports whether the dotted version is older than min, comparing the numbers of min only,
+// so 6.0.5 is not older than 6.0, and ignoring the suffix of the last number, like -rc1
+func olderVersion(version, min string) (bool, error) {
+	if min == "" {
+		return false, nil
+	}
+	parts := strings.Split(version, ".")
+	for i, m := range strings.Split(min, ".") {
+		want, err := strconv.Atoi(m)
+		if err != nil {
+			return false, fmt.Errorf("version %s not valid", min)
+		}
+		if i >= len(parts) {
+			return want > 0, nil
+		}
+		got, err := strconv.Atoi(strings.SplitN(parts[i], "-", 2)[0])
+		if err != nil {
+			return false, fmt.Errorf("version %s not valid", version)
+		}
+		if got != want {
+			return got < want, nil
+		}
+	}
+	return false, nil
+}
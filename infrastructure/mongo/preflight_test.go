@@ -0,0 +1,93 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestCheckServer_Ok checks that CheckServer returns the version, the feature compatibility version and the replica set of the server
+func TestCheckServer_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			bson.D{{Key: "ok", Value: 1}, {Key: "version", Value: "6.0.5"}},
+			bson.D{{Key: "ok", Value: 1}, {Key: "setName", Value: "rs0"}},
+			bson.D{{Key: "ok", Value: 1}, {Key: "featureCompatibilityVersion", Value: bson.D{{Key: "version", Value: "6.0"}}}},
+		)
+
+		// Act
+		detail, err := CheckServer(context.Background(), mt.DB, "4.2", true)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "version 6.0.5, feature compatibility 6.0, replica set rs0", detail)
+	})
+}
+
+// TestCheckServer_Standalone checks that CheckServer fails on a standalone server when a replica set is required,
+// leaving out the feature compatibility version when it cannot be read
+func TestCheckServer_Standalone(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			bson.D{{Key: "ok", Value: 1}, {Key: "version", Value: "6.0.5"}},
+			bson.D{{Key: "ok", Value: 1}},
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 13, Message: "not authorized on admin"}),
+		)
+
+		// Act
+		detail, err := CheckServer(context.Background(), mt.DB, "4.2", true)
+
+		// Assert
+		assert.Equal(t, "version 6.0.5, standalone", detail)
+		assert.ErrorContains(t, err, "standalone server not supported")
+	})
+}
+
+// TestCheckServer_OldFeatureCompatibility checks that CheckServer fails when the feature compatibility version is older than the minimum
+func TestCheckServer_OldFeatureCompatibility(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			bson.D{{Key: "ok", Value: 1}, {Key: "version", Value: "4.2.1"}},
+			bson.D{{Key: "ok", Value: 1}, {Key: "msg", Value: mongosMsg}},
+			bson.D{{Key: "ok", Value: 1}, {Key: "featureCompatibilityVersion", Value: bson.D{{Key: "version", Value: "4.0"}}}},
+		)
+
+		// Act
+		_, err := CheckServer(context.Background(), mt.DB, "4.2", true)
+
+		// Assert
+		assert.Equal(t, "feature compatibility version 4.0 older than 4.2", err.Error())
+	})
+}
+
+// TestOlderVersion_Ok checks that olderVersion compares the numbers of the minimum version only, ignoring the suffixes
+func TestOlderVersion_Ok(t *testing.T) {
+	// Act
+	older, err := olderVersion("6.0.5", "6.0")
+	older2, err2 := olderVersion("4.0.28", "4.2")
+	older3, err3 := olderVersion("7.0.0-rc1", "7")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, older)
+	assert.Nil(t, err2)
+	assert.True(t, older2)
+	assert.Nil(t, err3)
+	assert.False(t, older3)
+}
@@ -0,0 +1,67 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// expire purges or anonymizes, with the given update pipeline, the documents matching the filter, or only counts them in dry run.
+// Anonymized documents are marked with anonymized_at so they are not anonymized again.
+func expire(ctx context.Context, coll *mongo.Collection, filter bson.M, action entities.RetentionAction, dryRun bool, anonymization bson.A) (int64, error) {
+	if action == entities.RetentionAnonymize {
+		filter["anonymized_at"] = bson.M{"$exists": false}
+	}
+	if dryRun {
+		return coll.CountDocuments(ctx, filter, countComment(ctx))
+	}
+
+	switch action {
+	case entities.RetentionPurge:
+		result, err := coll.DeleteMany(ctx, filter, deleteComment(ctx))
+		if err != nil {
+			return 0, err
+		}
+		return result.DeletedCount, nil
+	case entities.RetentionAnonymize:
+		update := append(bson.A{bson.M{"$set": bson.M{"anonymized_at": "$$NOW"}}}, anonymization...)
+		result, err := coll.UpdateMany(ctx, filter, update, updateComment(ctx))
+		if err != nil {
+			return 0, err
+		}
+		return result.ModifiedCount, nil
+	default:
+		return 0, fmt.Errorf("retention action %q not valid", action)
+	}
+}
+
+// userArchiveRepository adapter of the archived users repository for mongo, whose users only expire with the retention policies
+type userArchiveRepository struct {
+	collection *mongo.Collection
+}
+
+// NewUserArchiveRepository creates an archived users repository for mongo
+func NewUserArchiveRepository(db *mongo.Database) ports.RetentionRepository {
+	return &userArchiveRepository{
+		collection: db.Collection(entities.EntityNameUserArchive),
+	}
+}
+
+// Expire purges or anonymizes the users archived before the given time.
+// Anonymized users keep their ID and dates, with an email derived from the ID so it stays unique, and can no longer be unarchived.
+func (r *userArchiveRepository) Expire(ctx context.Context, action entities.RetentionAction, before time.Time, dryRun bool) (int64, error) {
+	return expire(ctx, r.collection, bson.M{"archived_at": bson.M{"$lt": before}}, action, dryRun, bson.A{
+		bson.M{"$set": bson.M{
+			"name":          "",
+			"surnames":      "",
+			"email":         bson.M{"$concat": bson.A{"anonymized-", bson.M{"$toString": "$_id"}}},
+			"password_hash": "",
+		}},
+		bson.M{"$unset": bson.A{"location", "last_login_at", "billing_customer_id"}},
+	})
+}
@@ -0,0 +1,155 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestExpire_Purge checks that expire deletes the documents matching the filter
+func TestExpire_Purge(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}))
+
+		// Act
+		count, err := expire(context.Background(), mt.Coll, bson.M{"created_at": bson.M{"$lt": time.Now()}}, entities.RetentionPurge, false, nil)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+		assert.Equal(t, "delete", mt.GetStartedEvent().CommandName)
+	})
+}
+
+// TestExpire_Anonymize checks that expire updates with the anonymization the documents matching the filter that are not anonymized yet
+func TestExpire_Anonymize(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}))
+
+		// Act
+		count, err := expire(context.Background(), mt.Coll, bson.M{"created_at": bson.M{"$lt": time.Now()}}, entities.RetentionAnonymize, false, bson.A{bson.M{"$unset": bson.A{"details"}}})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		_, err = update.LookupErr("q", "anonymized_at", "$exists")
+		assert.Nil(t, err)
+		assert.Equal(t, "$$NOW", update.Lookup("u").Array().Index(0).Value().Document().Lookup("$set", "anonymized_at").StringValue())
+	})
+}
+
+// TestExpire_DryRun checks that expire only counts the documents matching the filter in dry run
+func TestExpire_DryRun(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+"."+mt.Coll.Name(), mtest.FirstBatch, bson.D{{Key: "n", Value: int32(4)}}))
+
+		// Act
+		count, err := expire(context.Background(), mt.Coll, bson.M{}, entities.RetentionPurge, true, nil)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), count)
+		assert.Equal(t, "aggregate", mt.GetStartedEvent().CommandName)
+	})
+}
+
+// TestExpire_InvalidAction checks that expire returns an error when the action is not valid
+func TestExpire_InvalidAction(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		_, err := expire(context.Background(), mt.Coll, bson.M{}, entities.RetentionAction("archive"), false, nil)
+
+		// Assert
+		assert.Equal(t, `retention action "archive" not valid`, err.Error())
+	})
+}
+
+// TestExpireUserArchive_Anonymize checks that Expire anonymizes the users archived before the given time
+func TestExpireUserArchive_Anonymize(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		repo := NewUserArchiveRepository(mt.DB)
+
+		// Act
+		count, err := repo.Expire(context.Background(), entities.RetentionAnonymize, time.Now(), false)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, entities.EntityNameUserArchive, command.Lookup("update").StringValue())
+		update := command.Lookup("updates").Array().Index(0).Value().Document()
+		_, err = update.LookupErr("q", "archived_at", "$lt")
+		assert.Nil(t, err)
+	})
+}
+
+// TestExpireAuditEvents_Purge checks that Expire deletes the events created before the given time
+func TestExpireAuditEvents_Purge(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 5}))
+		repo := &auditRepository{collection: mt.Coll}
+
+		// Act
+		count, err := repo.Expire(context.Background(), entities.RetentionPurge, time.Now(), false)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(5), count)
+		deletion := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document()
+		_, err = deletion.LookupErr("q", "created_at", "$lt")
+		assert.Nil(t, err)
+	})
+}
+
+// TestExpireJobs_Purge checks that Expire deletes the jobs finished before the given time
+func TestExpireJobs_Purge(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+		repo := &jobRepository{collection: mt.Coll}
+
+		// Act
+		count, err := repo.Expire(context.Background(), entities.RetentionPurge, time.Now(), false)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+		deletion := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document()
+		_, err = deletion.LookupErr("q", "finished_at", "$lt")
+		assert.Nil(t, err)
+	})
+}
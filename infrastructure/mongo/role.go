@@ -0,0 +1,162 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// roleAssigneeFields the fields of the roles storing the IDs of their assignees, by kind
+var roleAssigneeFields = map[string]string{
+	entities.RoleAssigneeUser:         "user_ids",
+	entities.RoleAssigneeOrganization: "organization_ids",
+}
+
+// roleRepository adapter of a role repository for mongo, whose assignees are stored in the document of their role
+type roleRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewRoleRepository creates a role repository for mongo, creating the unique index of the names and the indexes of the roles by assignee
+func NewRoleRepository(ctx context.Context, db *mongo.Database) (ports.RoleRepository, error) {
+	r := &roleRepository{
+		MongoRepository: infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameRole),
+			Target:     entities.Role{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_ids", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "organization_ids", Value: 1}},
+		},
+	})
+	return r, err
+}
+
+// Create creates the role, failing with a conflict when its name is already in use
+func (r *roleRepository) Create(ctx context.Context, role interface{}) (string, error) {
+	ID, err := r.MongoRepository.Create(ctx, role)
+	return ID, nameConflict(err)
+}
+
+// Update updates the role, failing with a conflict when its new name is already in use
+func (r *roleRepository) Update(ctx context.Context, ID string, role interface{}) error {
+	return nameConflict(r.MongoRepository.Update(ctx, ID, role))
+}
+
+// nameConflict returns a conflict when the error is a duplicate key of the unique name index, or the error as is otherwise
+func nameConflict(err error) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return apierror.Conflict(errors.New("role name already in use"))
+}
+
+func (r *roleRepository) GetByAssignees(ctx context.Context, userID string, organizationIDs []string) ([]entities.Role, error) {
+	filter := bson.M{"user_ids": userID}
+	if len(organizationIDs) > 0 {
+		filter = bson.M{"$or": bson.A{
+			bson.M{"user_ids": userID},
+			bson.M{"organization_ids": bson.M{"$in": organizationIDs}},
+		}}
+	}
+	cur, err := r.Collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []entities.Role{}
+	err = cur.All(ctx, &roles)
+	return roles, err
+}
+
+func (r *roleRepository) Assign(ctx context.Context, ID, kind, assigneeID string) error {
+	field, err := assigneeField(kind)
+	if err != nil {
+		return err
+	}
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$addToSet": bson.M{field: assigneeID},
+		"$set":      bson.M{"updated_at": time.Now().UTC()},
+	}
+	result, err := r.Collection.UpdateOne(ctx, bson.M{"_id": _id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("role ID %s not found", ID))
+	}
+	return nil
+}
+
+func (r *roleRepository) Unassign(ctx context.Context, ID, kind, assigneeID string) error {
+	field, err := assigneeField(kind)
+	if err != nil {
+		return err
+	}
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$pull": bson.M{field: assigneeID},
+		"$set":  bson.M{"updated_at": time.Now().UTC()},
+	}
+	result, err := r.Collection.UpdateOne(ctx, bson.M{"_id": _id, field: assigneeID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("role ID %s not assigned to %s ID %s", ID, kind, assigneeID))
+	}
+	return nil
+}
+
+func (r *roleRepository) RemoveAssignee(ctx context.Context, kind, assigneeID string) error {
+	field, err := assigneeField(kind)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$pull": bson.M{field: assigneeID},
+		"$set":  bson.M{"updated_at": time.Now().UTC()},
+	}
+	_, err = r.Collection.UpdateMany(ctx, bson.M{field: assigneeID}, update)
+	return err
+}
+
+// assigneeField returns the field of the roles storing the IDs of the assignees of the kind
+func assigneeField(kind string) (string, error) {
+	field, ok := roleAssigneeFields[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown kind of assignee %s", kind)
+	}
+	return field, nil
+}
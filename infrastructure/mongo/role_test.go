@@ -0,0 +1,169 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// testRoleID a role ID in the format of the IDs of Mongo
+const testRoleID = "64b7f0c2a1b2c3d4e5f60719"
+
+// newTestRoleRepository returns a role repository on the collection of the mocked database
+func newTestRoleRepository(mt *mtest.T) roleRepository {
+	return roleRepository{
+		MongoRepository: infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.DB.Collection(entities.EntityNameRole),
+			Target:     entities.Role{},
+		},
+	}
+}
+
+// TestNewRoleRepository_Ok checks that NewRoleRepository creates the unique index of the names
+func TestNewRoleRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		_, err := NewRoleRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.Nil(t, err)
+		index := mt.GetStartedEvent().Command.Lookup("indexes").Array().Index(0).Value().Document()
+		assert.Equal(t, "name_1", index.Lookup("name").StringValue())
+		assert.True(t, index.Lookup("unique").Boolean())
+	})
+}
+
+// TestCreateRole_Conflict checks that Create returns a conflict when the name of the role is already in use
+func TestCreateRole_Conflict(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestRoleRepository(mt)
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key error"}))
+
+		// Act
+		_, err := repo.Create(context.Background(), entities.Role{Name: "test-role"})
+
+		// Assert
+		assert.ErrorIs(t, err, apierror.ErrConflict)
+	})
+}
+
+// TestGetRolesByAssignees_Ok checks that GetByAssignees matches the roles of the user or of its organizations
+func TestGetRolesByAssignees_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestRoleRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "first"}, {Key: "name", Value: "first"}, {Key: "permissions", Value: bson.A{"admin"}}},
+		))
+
+		// Act
+		roles, err := repo.GetByAssignees(context.Background(), "test-user", []string{"test-organization"})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, roles, 1)
+		assert.Equal(t, []string{"admin"}, roles[0].Permissions)
+		or := mt.GetStartedEvent().Command.Lookup("filter", "$or").Array()
+		assert.Equal(t, "test-user", or.Index(0).Value().Document().Lookup("user_ids").StringValue())
+		assert.Equal(t, "test-organization", or.Index(1).Value().Document().Lookup("organization_ids", "$in").Array().Index(0).Value().StringValue())
+	})
+}
+
+// TestAssignRole_Ok checks that Assign adds the assignee to the field of its kind
+func TestAssignRole_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestRoleRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.Assign(context.Background(), testRoleID, entities.RoleAssigneeOrganization, "test-organization")
+
+		// Assert
+		assert.Nil(t, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "test-organization", update.Lookup("u", "$addToSet", "organization_ids").StringValue())
+	})
+}
+
+// TestAssignRole_UnknownKind checks that Assign returns an error when the kind of the assignee is not known
+func TestAssignRole_UnknownKind(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestRoleRepository(mt)
+
+		// Act
+		err := repo.Assign(context.Background(), testRoleID, "groups", "test-group")
+
+		// Assert
+		assert.NotNil(t, err)
+	})
+}
+
+// TestUnassignRole_NotFound checks that Unassign returns a non existent error when the role is not assigned to the assignee
+func TestUnassignRole_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestRoleRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.Unassign(context.Background(), testRoleID, entities.RoleAssigneeUser, "test-user")
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	})
+}
+
+// TestRemoveRoleAssignee_Ok checks that RemoveAssignee pulls the assignee from every role assigned to it
+func TestRemoveRoleAssignee_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestRoleRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}, {Key: "nModified", Value: 2}})
+
+		// Act
+		err := repo.RemoveAssignee(context.Background(), entities.RoleAssigneeUser, "test-user")
+
+		// Assert
+		assert.Nil(t, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "test-user", update.Lookup("q", "user_ids").StringValue())
+		assert.True(t, update.Lookup("multi").Boolean())
+	})
+}
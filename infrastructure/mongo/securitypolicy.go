@@ -0,0 +1,62 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// securityPolicyRepository adapter of a security policy repository for mongo, storing the policies by their tenant
+type securityPolicyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewSecurityPolicyRepository creates a security policy repository for mongo
+func NewSecurityPolicyRepository(db *mongo.Database) ports.SecurityPolicyRepository {
+	return &securityPolicyRepository{
+		collection: db.Collection(entities.EntityNameSecurityPolicy),
+	}
+}
+
+func (r *securityPolicyRepository) GetAll(ctx context.Context) ([]entities.SecurityPolicy, error) {
+	cur, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	policies := []entities.SecurityPolicy{}
+	err = cur.All(ctx, &policies)
+	return policies, err
+}
+
+func (r *securityPolicyRepository) Get(ctx context.Context, tenant string) (entities.SecurityPolicy, error) {
+	var policy entities.SecurityPolicy
+	err := r.collection.FindOne(ctx, bson.M{"_id": tenant}, findOneComment(ctx)).Decode(&policy)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("security policy of tenant %s not found", tenant))
+	}
+	return policy, err
+}
+
+func (r *securityPolicyRepository) Upsert(ctx context.Context, policy entities.SecurityPolicy) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": policy.Tenant}, policy, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *securityPolicyRepository) Delete(ctx context.Context, tenant string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": tenant})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("security policy of tenant %s not found", tenant))
+	}
+	return nil
+}
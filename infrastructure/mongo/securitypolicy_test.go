@@ -0,0 +1,123 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestGetAllSecurityPolicies_Ok checks that GetAll returns every policy stored
+func TestGetAllSecurityPolicies_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := NewSecurityPolicyRepository(mt.DB)
+		ns := mt.DB.Name() + "." + entities.EntityNameSecurityPolicy
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "a.com"}, {Key: "mfa_required", Value: true}},
+				bson.D{{Key: "_id", Value: "b.com"}, {Key: "session_lifetime", Value: int64(time.Hour)}},
+			),
+		)
+
+		// Act
+		policies, err := repo.GetAll(context.Background())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, []entities.SecurityPolicy{{Tenant: "a.com", MFARequired: true}, {Tenant: "b.com", SessionLifetime: time.Hour}}, policies)
+	})
+}
+
+// TestGetSecurityPolicy_Ok checks that Get returns the policy stored by its tenant
+func TestGetSecurityPolicy_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := NewSecurityPolicyRepository(mt.DB)
+		ns := mt.DB.Name() + "." + entities.EntityNameSecurityPolicy
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "example.com"},
+			{Key: "password", Value: bson.D{{Key: "min_length", Value: 12}}},
+			{Key: "lockout_max_failures", Value: int64(3)},
+		}))
+
+		// Act
+		policy, err := repo.Get(context.Background(), "example.com")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "example.com", policy.Tenant)
+		assert.Equal(t, 12, policy.Password.MinLength)
+		assert.Equal(t, int64(3), policy.LockoutMaxFailures)
+	})
+}
+
+// TestGetSecurityPolicy_NotFound checks that Get returns a non existent error when the tenant has no policy
+func TestGetSecurityPolicy_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := NewSecurityPolicyRepository(mt.DB)
+		ns := mt.DB.Name() + "." + entities.EntityNameSecurityPolicy
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.Get(context.Background(), "example.com")
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	})
+}
+
+// TestUpsertSecurityPolicy_Ok checks that Upsert replaces the policy of the tenant, creating it when it has none
+func TestUpsertSecurityPolicy_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := NewSecurityPolicyRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+
+		// Act
+		err := repo.Upsert(context.Background(), entities.SecurityPolicy{Tenant: "example.com"})
+
+		// Assert
+		assert.Nil(t, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "example.com", update.Lookup("q", "_id").StringValue())
+		assert.True(t, update.Lookup("upsert").Boolean())
+	})
+}
+
+// TestDeleteSecurityPolicy_NotFound checks that Delete returns a non existent error when the tenant has no policy
+func TestDeleteSecurityPolicy_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := NewSecurityPolicyRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+
+		// Act
+		err := repo.Delete(context.Background(), "example.com")
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	})
+}
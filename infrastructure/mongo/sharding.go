@@ -0,0 +1,58 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongo error codes tolerated while sharding
+const (
+	codeAlreadyInitialized    = 23
+	codeIndexOptionsConflict  = 85
+	codeIndexKeySpecsConflict = 86
+)
+
+// ShardUsers shards the users collection by the given key, hashed or ranged, creating the index supporting it when missing.
+// It must run once the indexes of the users collection exist, and does nothing when the collection is already sharded by the same key.
+func ShardUsers(ctx context.Context, db *mongo.Database, key string, hashed bool) error {
+	var value interface{} = 1
+	if hashed {
+		value = "hashed"
+	}
+	keys := bson.D{{Key: key, Value: value}}
+
+	// an index on the same keys with other options supports the shard key as well, unless it has a non simple collation
+	// like the case insensitive unique email one, which is created with another name so both can coexist
+	_, err := db.Collection(entities.EntityNameUser).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys})
+	if err != nil && !hasErrorCode(err, codeIndexOptionsConflict, codeIndexKeySpecsConflict) {
+		return err
+	}
+
+	admin := db.Client().Database("admin")
+	err = admin.RunCommand(ctx, bson.D{{Key: "enableSharding", Value: db.Name()}}).Err()
+	if err != nil && !hasErrorCode(err, codeAlreadyInitialized) {
+		return err
+	}
+
+	return admin.RunCommand(ctx, bson.D{
+		{Key: "shardCollection", Value: db.Name() + "." + entities.EntityNameUser},
+		{Key: "key", Value: keys},
+	}).Err()
+}
+
+func hasErrorCode(err error, codes ...int) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	for _, c := range codes {
+		if serverErr.HasErrorCode(c) {
+			return true
+		}
+	}
+	return false
+}
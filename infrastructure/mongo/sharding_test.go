@@ -0,0 +1,75 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestShardUsers_Ok checks that ShardUsers shards the users collection by the given key
+func TestShardUsers_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		// Act
+		err := ShardUsers(context.Background(), mt.DB, "_id", true)
+
+		// Assert
+		assert.Nil(t, err)
+		mt.GetStartedEvent()
+		mt.GetStartedEvent()
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, mt.DB.Name()+"."+entities.EntityNameUser, command.Lookup("shardCollection").StringValue())
+		assert.Equal(t, "hashed", command.Lookup("key", "_id").StringValue())
+	})
+}
+
+// TestShardUsers_AlreadyEnabled checks that ShardUsers goes on sharding the collection when sharding is already enabled for the database
+func TestShardUsers_AlreadyEnabled(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: codeIndexOptionsConflict, Name: "IndexOptionsConflict", Message: "index already exists"}),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: codeAlreadyInitialized, Name: "AlreadyInitialized", Message: "already enabled"}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		// Act
+		err := ShardUsers(context.Background(), mt.DB, "email", false)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestShardUsers_ShardCollectionError checks that ShardUsers returns an error when the collection cannot be sharded
+func TestShardUsers_ShardCollectionError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 72, Name: "InvalidOptions", Message: "unique index not prefixed by the shard key"}),
+		)
+
+		// Act
+		err := ShardUsers(context.Background(), mt.DB, "_id", true)
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
@@ -0,0 +1,64 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// signingKeyStore adapter of a signing key store for mongo, whose keys are documents keyed by their ID
+type signingKeyStore struct {
+	collection *mongo.Collection
+}
+
+// NewSigningKeyStore creates a signing key store for mongo
+func NewSigningKeyStore(db *mongo.Database) ports.SigningKeyStore {
+	return &signingKeyStore{
+		collection: db.Collection(entities.EntityNameSigningKey),
+	}
+}
+
+func (s *signingKeyStore) Get(ctx context.Context) ([]entities.SigningKey, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$exists": false}},
+			bson.M{"expires_at": bson.M{"$gt": time.Now().UTC()}},
+		},
+	}
+	cur, err := s.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []entities.SigningKey{}
+	err = cur.All(ctx, &keys)
+	return keys, err
+}
+
+func (s *signingKeyStore) Rotate(ctx context.Context, key entities.SigningKey, overlap time.Duration) error {
+	if _, err := s.collection.InsertOne(ctx, key); err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": bson.M{"$ne": key.ID}, "expires_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"expires_at": key.CreatedAt.Add(overlap)}}
+	if _, err := s.collection.UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+
+	_, err := s.Purge(ctx)
+	return err
+}
+
+func (s *signingKeyStore) Purge(ctx context.Context) (int64, error) {
+	result, err := s.collection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lte": time.Now().UTC()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
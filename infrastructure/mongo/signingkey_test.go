@@ -0,0 +1,99 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestGetSigningKeys_Ok checks that Get returns the keys not expired found in the collection
+func TestGetSigningKeys_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := signingKeyStore{collection: mt.DB.Collection(entities.EntityNameSigningKey)}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "new-key"}, {Key: "secret", Value: "bmV3"}},
+			bson.D{{Key: "_id", Value: "old-key"}, {Key: "secret", Value: "b2xk"}},
+		))
+
+		// Act
+		keys, err := store.Get(context.Background())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, keys, 2)
+		assert.Equal(t, "new-key", keys[0].ID)
+		assert.Equal(t, "old-key", keys[1].ID)
+	})
+}
+
+// TestRotateSigningKeys_Ok checks that Rotate inserts the new key, sets the expiry of the previous ones and deletes the expired ones
+func TestRotateSigningKeys_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := signingKeyStore{collection: mt.DB.Collection(entities.EntityNameSigningKey)}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}},
+			bson.D{{Key: "ok", Value: 1}, {Key: "acknowledged", Value: true}, {Key: "n", Value: 1}},
+		)
+		key := entities.SigningKey{ID: "new-key", Secret: "bmV3", CreatedAt: time.Now().UTC()}
+
+		// Act
+		err := store.Rotate(context.Background(), key, time.Hour)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestRotateSigningKeys_InsertError checks that Rotate returns an error when the new key cannot be inserted
+func TestRotateSigningKeys_InsertError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := signingKeyStore{collection: mt.DB.Collection(entities.EntityNameSigningKey)}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key error"}))
+		key := entities.SigningKey{ID: "new-key", Secret: "bmV3", CreatedAt: time.Now().UTC()}
+
+		// Act
+		err := store.Rotate(context.Background(), key, time.Hour)
+
+		// Assert
+		assert.NotNil(t, err)
+	})
+}
+
+// TestPurgeSigningKeys_Ok checks that Purge deletes the expired keys and returns how many were deleted
+func TestPurgeSigningKeys_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store := signingKeyStore{collection: mt.DB.Collection(entities.EntityNameSigningKey)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "acknowledged", Value: true}, {Key: "n", Value: 2}})
+
+		// Act
+		count, err := store.Purge(context.Background())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
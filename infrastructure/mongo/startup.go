@@ -0,0 +1,46 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// requiredIndexes are the names of the indexes created by the repositories per collection that the API relies on
+var requiredIndexes = []struct {
+	collection string
+	names      []string
+}{
+	{collection: entities.EntityNameUser, names: []string{"email_ci", "location_2dsphere"}},
+	{collection: entities.EntityNameAuditEvent, names: []string{"user_id_1_type_1", "actor_id_1"}},
+}
+
+// VerifyIndexes ensures that the indexes the API relies on exist, so that it is not reported ready without them
+func VerifyIndexes(ctx context.Context, db *mongo.Database) error {
+	for _, required := range requiredIndexes {
+		cursor, err := db.Collection(required.collection).Indexes().List(ctx)
+		if err != nil {
+			return err
+		}
+
+		var indexes []struct {
+			Name string `bson:"name"`
+		}
+		if err := cursor.All(ctx, &indexes); err != nil {
+			return err
+		}
+
+		existing := make(map[string]bool, len(indexes))
+		for _, index := range indexes {
+			existing[index.Name] = true
+		}
+		for _, name := range required.names {
+			if !existing[name] {
+				return fmt.Errorf("index %s of collection %s not found", name, required.collection)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func indexesResponse(mt *mtest.T, collection string, names ...string) bson.D {
+	batch := []bson.D{}
+	for _, name := range names {
+		batch = append(batch, bson.D{{Key: "name", Value: name}})
+	}
+	return mtest.CreateCursorResponse(0, mt.DB.Name()+"."+collection, mtest.FirstBatch, batch...)
+}
+
+// TestVerifyIndexes_Ok checks that VerifyIndexes does not return an error when every required index exists
+func TestVerifyIndexes_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		for _, required := range requiredIndexes {
+			mt.AddMockResponses(indexesResponse(mt, required.collection, append([]string{"_id_"}, required.names...)...))
+		}
+
+		// Act
+		err := VerifyIndexes(context.Background(), mt.DB)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestVerifyIndexes_Missing checks that VerifyIndexes returns an error when a required index does not exist
+func TestVerifyIndexes_Missing(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		for _, required := range requiredIndexes {
+			mt.AddMockResponses(indexesResponse(mt, required.collection, "_id_"))
+		}
+
+		// Act
+		err := VerifyIndexes(context.Background(), mt.DB)
+
+		// Assert
+		assert.ErrorContains(t, err, "not found")
+	})
+}
+
+// TestVerifyIndexes_ListError checks that VerifyIndexes returns an error when the indexes cannot be listed
+func TestVerifyIndexes_ListError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "list error"}))
+
+		// Act
+		err := VerifyIndexes(context.Background(), mt.DB)
+
+		// Assert
+		assert.ErrorContains(t, err, "list error")
+	})
+}
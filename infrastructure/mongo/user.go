@@ -2,65 +2,659 @@ package mongo
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+	"time"
 
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
 	"github.com/sergicanet9/go-hexagonal-api/core/entities"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
+// atlasSearchIndex is the name of the Atlas Search index of the users collection
+const atlasSearchIndex = "default"
+
+// searchFields are the fields matched by user searches
+var searchFields = []string{"name", "surnames", "email"}
+
+// emailCollation compares strings ignoring case, used by the unique email index and the lookups by email
+// so addresses only differing in case can neither be registered twice nor be told apart
+var emailCollation = &options.Collation{Locale: "en", Strength: 2}
+
 // userRepository adapter of an user repository for mongo.
 type userRepository struct {
 	infrastructure.MongoRepository
+	// atlasSearchUnavailable is set once the server rejects a $search stage, so following searches go straight to the text index
+	atlasSearchUnavailable atomic.Bool
 }
 
 // NewUserRepository creates a user repository for mongo
 func NewUserRepository(ctx context.Context, db *mongo.Database) (ports.UserRepository, error) {
 	r := &userRepository{
-		infrastructure.MongoRepository{
+		MongoRepository: infrastructure.MongoRepository{
 			DB:         db,
 			Collection: db.Collection(entities.EntityNameUser),
 			Target:     entities.User{},
 		},
 	}
 
-	_, err := r.Collection.Indexes().CreateOne(
+	textKeys := bson.D{}
+	for _, f := range searchFields {
+		textKeys = append(textKeys, bson.E{Key: f, Value: "text"})
+	}
+
+	_, err := r.Collection.Indexes().CreateMany(
 		ctx,
-		mongo.IndexModel{
-			Keys:    bson.D{{Key: "email", Value: 1}},
-			Options: options.Index().SetUnique(true),
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetUnique(true).SetCollation(emailCollation).SetName("email_ci"),
+			},
+			{
+				Keys: textKeys,
+			},
+			{
+				Keys: bson.D{{Key: "location", Value: "2dsphere"}},
+			},
+			{
+				Keys:    bson.D{{Key: "billing_customer_id", Value: 1}},
+				Options: options.Index().SetSparse(true),
+			},
 		},
 	)
 	return r, err
 }
 
+// Create creates the user, failing with a conflict when its email is already in use
+func (r *userRepository) Create(ctx context.Context, user interface{}) (string, error) {
+	ID, err := r.MongoRepository.Create(ctx, user)
+	return ID, emailConflict(err, user)
+}
+
+// emailConflict returns a conflict when the error is a duplicate key of the unique email index, the only unique one of the users besides their ID,
+// or the error as is otherwise
+func emailConflict(err error, user interface{}) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	if u, ok := user.(entities.User); ok {
+		return apierror.Conflict(fmt.Errorf("email %s already in use", u.Email))
+	}
+	return apierror.Conflict(errors.New("email already in use"))
+}
+
 func (r *userRepository) CreateMany(ctx context.Context, users []interface{}) ([]string, error) {
+	var result []string
+	err := r.WithTransaction(ctx, func(ctx context.Context) error {
+		result = nil
+		for _, entity := range users {
+			id, err := r.Create(ctx, entity)
+			if err != nil {
+				return err
+			}
+			result = append(result, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WithTransaction runs fn inside a multi-document transaction, committing it if fn succeeds and aborting it otherwise
+func (r *userRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
 	wc := writeconcern.New(writeconcern.WMajority())
 	rc := readconcern.Snapshot()
 	txnOpts := options.Transaction().SetWriteConcern(wc).SetReadConcern(rc)
 
 	session, err := r.DB.Client().StartSession()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer session.EndSession(ctx)
 
-	var result []string
 	callback := func(sessionContext mongo.SessionContext) (interface{}, error) {
-		for _, entity := range users {
-			id, err := r.Create(sessionContext, entity)
+		return nil, fn(sessionContext)
+	}
+
+	_, err = session.WithTransaction(ctx, callback, txnOpts)
+	return err
+}
+
+// GetProjected gets the users matching the filter, returning only the fields allowed by the projection
+func (r *userRepository) GetProjected(ctx context.Context, filter map[string]interface{}, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	opts := options.Find()
+	if projection != nil {
+		opts.SetProjection(projection)
+	}
+	if skip != nil {
+		opts.SetSkip(int64(*skip))
+	}
+	if take != nil {
+		opts.SetLimit(int64(*take))
+	}
+	if _, ok := filter["email"]; ok {
+		opts.SetCollation(emailCollation)
+	}
+
+	coll, err := r.collection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := coll.Find(ctx, filter, opts, findComment(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return decodeUsers(ctx, cur)
+}
+
+// collection returns the users collection with the read preference of the context, if any.
+// Reads inside a transaction keep the primary, as transactions cannot read from secondaries.
+func (r *userRepository) collection(ctx context.Context) (*mongo.Collection, error) {
+	rp, ok := ports.ReadPreferenceFrom(ctx)
+	if !ok || mongo.SessionFromContext(ctx) != nil {
+		return r.Collection, nil
+	}
+
+	mode, err := readpref.ModeFromString(string(rp))
+	if err != nil {
+		return nil, err
+	}
+	pref, err := readpref.New(mode)
+	if err != nil {
+		return nil, err
+	}
+	return r.Collection.Clone(options.Collection().SetReadPreference(pref))
+}
+
+// GetByIDProjected gets the user with the specified ID, returning only the fields allowed by the projection
+func (r *userRepository) GetByIDProjected(ctx context.Context, ID string, projection map[string]interface{}) (interface{}, error) {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.FindOne()
+	if projection != nil {
+		opts.SetProjection(projection)
+	}
+
+	var u entities.User
+	err = r.Collection.FindOne(ctx, bson.M{"_id": _id}, opts, findOneComment(ctx)).Decode(&u)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// Update updates the user with the specified ID.
+// The update is filtered by _id only, without looking up the current shard key value first, as such a lookup by _id would be broadcast to every shard.
+func (r *userRepository) Update(ctx context.Context, ID string, user interface{}) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.Collection.UpdateOne(ctx, bson.M{"_id": _id}, bson.M{"$set": user}, updateComment(ctx))
+	if err != nil {
+		return emailConflict(err, user)
+	}
+	if result.ModifiedCount < 1 && result.UpsertedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+// Upsert updates the user matching the filter or creates it if none matches, returning its ID.
+// The creation date is only set on insert, and an empty password hash never overrides an existing one.
+func (r *userRepository) Upsert(ctx context.Context, filter map[string]interface{}, user interface{}) (string, error) {
+	u := user.(entities.User)
+
+	set := bson.M{
+		"name":       u.Name,
+		"surnames":   u.Surnames,
+		"email":      u.Email,
+		"claims":     u.Claims,
+		"updated_at": u.UpdatedAt,
+	}
+	if u.Location != nil {
+		set["location"] = u.Location
+	}
+	setOnInsert := bson.M{
+		"created_at": u.CreatedAt,
+	}
+	if u.PasswordHash != "" {
+		set["password_hash"] = u.PasswordHash
+	} else {
+		setOnInsert["password_hash"] = u.PasswordHash
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if _, ok := filter["email"]; ok {
+		opts.SetCollation(emailCollation)
+	}
+	update := bson.M{"$set": set, "$setOnInsert": setOnInsert}
+
+	var result entities.User
+	err := r.Collection.FindOneAndUpdate(ctx, filter, update, opts, findOneAndUpdateComment(ctx)).Decode(&result)
+	if err != nil {
+		return "", emailConflict(err, u)
+	}
+
+	return result.ID, nil
+}
+
+// Search gets the users whose name, surnames or email match the text.
+// Atlas Search is used when available, falling back to the text index (or a prefix match for autocomplete) on plain Mongo.
+func (r *userRepository) Search(ctx context.Context, text string, autocomplete bool, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	if !r.atlasSearchUnavailable.Load() {
+		result, err := r.atlasSearch(ctx, text, autocomplete, projection, skip, take)
+		if !isSearchUnsupported(err) {
+			return result, err
+		}
+		r.atlasSearchUnavailable.Store(true)
+	}
+
+	var filter bson.M
+	opts := options.Find()
+	if projection != nil {
+		opts.SetProjection(projection)
+	}
+	if skip != nil {
+		opts.SetSkip(int64(*skip))
+	}
+	if take != nil {
+		opts.SetLimit(int64(*take))
+	}
+	if autocomplete {
+		var conditions bson.A
+		for _, f := range searchFields {
+			conditions = append(conditions, bson.M{f: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(text), Options: "i"}})
+		}
+		filter = bson.M{"$or": conditions}
+	} else {
+		filter = bson.M{"$text": bson.M{"$search": text}}
+		opts.SetSort(bson.M{"score": bson.M{"$meta": "textScore"}})
+	}
+
+	coll, err := r.collection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := coll.Find(ctx, filter, opts, findComment(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return decodeUsers(ctx, cur)
+}
+
+func (r *userRepository) atlasSearch(ctx context.Context, text string, autocomplete bool, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	search := bson.M{"index": atlasSearchIndex}
+	if autocomplete {
+		// autocomplete operators only accept a single path
+		var should bson.A
+		for _, f := range searchFields {
+			should = append(should, bson.M{"autocomplete": bson.M{"query": text, "path": f}})
+		}
+		search["compound"] = bson.M{"should": should, "minimumShouldMatch": 1}
+	} else {
+		search["text"] = bson.M{"query": text, "path": searchFields, "fuzzy": bson.M{"maxEdits": 2}}
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$search", Value: search}}}
+	if skip != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: *skip}})
+	}
+	if take != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: *take}})
+	}
+	if len(projection) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: projection}})
+	}
+
+	coll, err := r.collection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := coll.Aggregate(ctx, pipeline, aggregateComment(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return decodeUsers(ctx, cur)
+}
+
+// GetNearby gets the users located within the radius, in meters, of a point, sorted by distance, using the 2dsphere index
+func (r *userRepository) GetNearby(ctx context.Context, longitude, latitude, radius float64, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	filter := bson.M{
+		"location": bson.M{
+			"$nearSphere": bson.M{
+				"$geometry":    entities.NewGeoPoint(longitude, latitude),
+				"$maxDistance": radius,
+			},
+		},
+	}
+	return r.GetProjected(ctx, filter, projection, skip, take)
+}
+
+// isSearchUnsupported checks if the error was caused by a server without Atlas Search
+func isSearchUnsupported(err error) bool {
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) {
+		return false
+	}
+	// 40324: unrecognized pipeline stage name, 31082: search not enabled
+	return cmdErr.Code == 40324 || cmdErr.Code == 31082
+}
+
+// decodeUsers decodes all the users of the cursor, returning a non existent error if there are none
+func decodeUsers(ctx context.Context, cur *mongo.Cursor) ([]interface{}, error) {
+	defer cur.Close(ctx)
+
+	var result []interface{}
+	for cur.Next(ctx) {
+		var u entities.User
+		if err := cur.Decode(&u); err != nil {
+			return nil, err
+		}
+		result = append(result, &u)
+	}
+
+	if len(result) < 1 {
+		return nil, wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+
+	return result, nil
+}
+
+// UpdateLastLogin sets the last login date of the user with the specified ID
+func (r *userRepository) UpdateLastLogin(ctx context.Context, ID string, at time.Time) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.Collection.UpdateOne(ctx, bson.M{"_id": _id}, bson.M{"$set": bson.M{"last_login_at": at}}, updateComment(ctx))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+// UpdateNames sets the name, the surnames and the update date of the user with the specified ID
+func (r *userRepository) UpdateNames(ctx context.Context, ID, name, surnames string, at time.Time) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.Collection.UpdateOne(ctx, bson.M{"_id": _id}, bson.M{"$set": bson.M{"name": name, "surnames": surnames, "updated_at": at}}, updateComment(ctx))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+// UpdateBillingCustomer sets the ID of the customer in the billing provider of the user with the specified ID
+func (r *userRepository) UpdateBillingCustomer(ctx context.Context, ID, customerID string) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.Collection.UpdateOne(ctx, bson.M{"_id": _id}, bson.M{"$set": bson.M{"billing_customer_id": customerID}}, updateComment(ctx))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+// UpdateSubscription sets the subscription status of the user of the customer unless it was set as of a later time.
+// The user is found by the customer first, so the update is filtered by its ID as required in a sharded collection.
+func (r *userRepository) UpdateSubscription(ctx context.Context, customerID, status string, at time.Time) error {
+	var user struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err := r.Collection.FindOne(ctx, bson.M{"billing_customer_id": customerID}, options.FindOne().SetProjection(bson.M{"_id": 1}), findOneComment(ctx)).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return err
+	}
+
+	filter := bson.M{
+		"_id": user.ID,
+		"$or": bson.A{
+			bson.M{"subscription_updated_at": bson.M{"$exists": false}},
+			bson.M{"subscription_updated_at": bson.M{"$lte": at}},
+		},
+	}
+	result, err := r.Collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"subscription_status": status, "subscription_updated_at": at}}, updateComment(ctx))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+// Archive moves the users whose last login, or last update if they never logged in, is older than inactiveSince to the archive collection.
+// Every user is moved in its own transaction, so a failure only stops the archival without leaving users duplicated or lost.
+func (r *userRepository) Archive(ctx context.Context, inactiveSince, at time.Time) (int64, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"last_login_at": bson.M{"$lt": inactiveSince}},
+			bson.M{"last_login_at": bson.M{"$exists": false}, "updated_at": bson.M{"$lt": inactiveSince}},
+		},
+	}
+
+	cur, err := r.Collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}), findComment(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer cur.Close(ctx)
+
+	var ids []primitive.ObjectID
+	for cur.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cur.Decode(&doc); err != nil {
+			return 0, err
+		}
+		ids = append(ids, doc.ID)
+	}
+	if err := cur.Err(); err != nil {
+		return 0, err
+	}
+
+	archive := r.DB.Collection(entities.EntityNameUserArchive)
+	var count int64
+	for _, id := range ids {
+		var archived bool
+		err = r.WithTransaction(ctx, func(ctx context.Context) error {
+			archived = false
+
+			var doc bson.M
+			err := r.Collection.FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&doc)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				// deleted after being selected
+				return nil
+			}
 			if err != nil {
-				return nil, err
+				return err
 			}
-			result = append(result, id)
+
+			doc["archived_at"] = at
+			_, err = archive.InsertOne(ctx, doc)
+			archived = err == nil
+			return err
+		})
+		if err != nil {
+			return count, err
+		}
+		if archived {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Stats counts the users, the active ones and the archived ones, the active ones having logged in or, when they never did, been updated since the given time
+func (r *userRepository) Stats(ctx context.Context, activeSince time.Time) (stats entities.UserStats, err error) {
+	stats.Total, err = r.Collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return
+	}
+
+	active := bson.M{
+		"$or": bson.A{
+			bson.M{"last_login_at": bson.M{"$gte": activeSince}},
+			bson.M{"last_login_at": bson.M{"$exists": false}, "updated_at": bson.M{"$gte": activeSince}},
+		},
+	}
+	stats.Active, err = r.Collection.CountDocuments(ctx, active)
+	if err != nil {
+		return
+	}
+
+	stats.Archived, err = r.DB.Collection(entities.EntityNameUserArchive).CountDocuments(ctx, bson.M{})
+	return
+}
+
+// ArchiveByID moves the user with the specified ID to the archive collection in a transaction
+func (r *userRepository) ArchiveByID(ctx context.Context, ID string, at time.Time) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	archive := r.DB.Collection(entities.EntityNameUserArchive)
+	return r.WithTransaction(ctx, func(ctx context.Context) error {
+		var doc bson.M
+		err := r.Collection.FindOneAndDelete(ctx, bson.M{"_id": _id}).Decode(&doc)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				err = wrappers.NewNonExistentErr(err)
+			}
+			return err
 		}
-		return nil, nil
+
+		doc["archived_at"] = at
+		_, err = archive.InsertOne(ctx, doc)
+		return err
+	})
+}
+
+// Unarchive moves the archived user with the specified ID back to the users collection, unless it has been anonymized
+func (r *userRepository) Unarchive(ctx context.Context, ID string, at time.Time) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
 	}
 
-	_, err = session.WithTransaction(context.Background(), callback, txnOpts)
-	return result, err
+	archive := r.DB.Collection(entities.EntityNameUserArchive)
+	return r.WithTransaction(ctx, func(ctx context.Context) error {
+		var doc bson.M
+		err := archive.FindOneAndDelete(ctx, bson.M{"_id": _id, "anonymized_at": bson.M{"$exists": false}}).Decode(&doc)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				err = wrappers.NewNonExistentErr(err)
+			}
+			return err
+		}
+
+		delete(doc, "archived_at")
+		doc["updated_at"] = at
+		_, err = r.Collection.InsertOne(ctx, doc)
+		return err
+	})
+}
+
+// Dump calls fn with every user, with all its fields, stopping at the first error
+func (r *userRepository) Dump(ctx context.Context, fn func(user entities.User) error) error {
+	cur, err := r.Collection.Find(ctx, bson.M{}, findComment(ctx))
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var u entities.User
+		if err := cur.Decode(&u); err != nil {
+			return err
+		}
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+// Restore replaces all the users with the given ones in a single transaction, keeping their IDs
+func (r *userRepository) Restore(ctx context.Context, users []entities.User) error {
+	docs := make([]interface{}, 0, len(users))
+	for _, u := range users {
+		_id, err := primitive.ObjectIDFromHex(u.ID)
+		if err != nil {
+			return err
+		}
+
+		// the ID is stored as an ObjectID instead of as the string of the entity
+		u.ID = ""
+		raw, err := bson.Marshal(u)
+		if err != nil {
+			return err
+		}
+		var doc bson.D
+		if err = bson.Unmarshal(raw, &doc); err != nil {
+			return err
+		}
+		docs = append(docs, append(bson.D{{Key: "_id", Value: _id}}, doc...))
+	}
+
+	return r.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := r.Collection.DeleteMany(ctx, bson.M{}); err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+		_, err := r.Collection.InsertMany(ctx, docs)
+		return err
+	})
 }
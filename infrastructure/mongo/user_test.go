@@ -2,13 +2,20 @@ package mongo
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 )
 
@@ -38,7 +45,7 @@ func TestCreateMany_Ok(t *testing.T) {
 	mt.Run("", func(mt *mtest.T) {
 		// Arrange
 		repo := userRepository{
-			infrastructure.MongoRepository{
+			MongoRepository: infrastructure.MongoRepository{
 				DB:         mt.DB,
 				Collection: mt.DB.Collection(entities.EntityNameUser),
 				Target:     entities.User{},
@@ -69,7 +76,7 @@ func TestInsertMany_CreateError(t *testing.T) {
 	mt.Run("", func(mt *mtest.T) {
 		// Arrange
 		repo := userRepository{
-			infrastructure.MongoRepository{
+			MongoRepository: infrastructure.MongoRepository{
 				DB:         mt.DB,
 				Collection: mt.DB.Collection(entities.EntityNameUser),
 				Target:     entities.User{},
@@ -87,3 +94,1028 @@ func TestInsertMany_CreateError(t *testing.T) {
 		assert.NotEmpty(t, err)
 	})
 }
+
+// TestGetProjected_Ok checks that GetProjected returns the expected response when everything goes as expected
+func TestGetProjected_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		expectedUser := entities.User{Email: "test@test.com"}
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "email", Value: expectedUser.Email}}))
+
+		skip, take := 0, 1
+
+		// Act
+		result, err := repo.GetProjected(context.Background(), map[string]interface{}{}, map[string]interface{}{"password_hash": 0}, &skip, &take)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.True(t, len(result) == 1)
+		assert.Equal(t, expectedUser, *(result[0].(*entities.User)))
+	})
+}
+
+// TestGetProjected_NoResourcesFound checks that GetProjected returns an error when no documents are found
+func TestGetProjected_NoResourcesFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.GetProjected(context.Background(), map[string]interface{}{}, nil, nil, nil)
+
+		// Assert
+		assert.Equal(t, wrappers.NewNonExistentErr(mongo.ErrNoDocuments), err)
+	})
+}
+
+// TestGetProjected_ReadPreference checks that GetProjected reads with the read preference of the context
+func TestGetProjected_ReadPreference(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+		ctx := ports.WithReadPreference(context.Background(), ports.ReadSecondaryPreferred)
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "name", Value: "test"}}))
+
+		// Act
+		_, err := repo.GetProjected(ctx, map[string]interface{}{}, nil, nil, nil)
+
+		// Assert
+		assert.Nil(t, err)
+		mode := mt.GetStartedEvent().Command.Lookup("$readPreference", "mode").StringValue()
+		assert.Equal(t, string(ports.ReadSecondaryPreferred), mode)
+	})
+}
+
+// TestGetProjected_EmailCollation checks that GetProjected compares emails ignoring case
+func TestGetProjected_EmailCollation(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "email", Value: "Test@Test.com"}}))
+
+		// Act
+		_, err := repo.GetProjected(context.Background(), map[string]interface{}{"email": "test@test.com"}, nil, nil, nil)
+
+		// Assert
+		assert.Nil(t, err)
+		collation := mt.GetStartedEvent().Command.Lookup("collation")
+		assert.Equal(t, int32(emailCollation.Strength), collation.Document().Lookup("strength").Int32())
+	})
+}
+
+// TestCollection_InvalidReadPreference checks that collection returns an error when the read preference of the context is not valid
+func TestCollection_InvalidReadPreference(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+		ctx := ports.WithReadPreference(context.Background(), "invalid")
+
+		// Act
+		_, err := repo.collection(ctx)
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
+
+// TestGetByIDProjected_Ok checks that GetByIDProjected returns the expected response when everything goes as expected
+func TestGetByIDProjected_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		id := primitive.NewObjectID()
+		expectedUser := entities.User{ID: id.Hex(), Email: "test@test.com"}
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: id}, {Key: "email", Value: expectedUser.Email}}))
+
+		// Act
+		result, err := repo.GetByIDProjected(context.Background(), id.Hex(), map[string]interface{}{"password_hash": 0})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, expectedUser, *(result.(*entities.User)))
+	})
+}
+
+// TestGetByIDProjected_InvalidID checks that GetByIDProjected returns an error when the received ID is not valid
+func TestGetByIDProjected_InvalidID(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		// Act
+		_, err := repo.GetByIDProjected(context.Background(), "invalid-id", nil)
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
+
+// TestUpsert_Ok checks that Upsert returns the ID of the upserted document when everything goes as expected
+func TestUpsert_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: bson.D{{Key: "_id", Value: id}}}})
+
+		// Act
+		result, err := repo.Upsert(context.Background(), map[string]interface{}{"email": "test@test.com"}, entities.User{})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, id.Hex(), result)
+	})
+}
+
+// TestUpsert_UpdateError checks that Upsert returns an error when the update fails
+func TestUpsert_UpdateError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+
+		// Act
+		_, err := repo.Upsert(context.Background(), map[string]interface{}{"email": "test@test.com"}, entities.User{})
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
+
+// TestWithTransaction_Ok checks that WithTransaction runs the received function with a session context and does not return an error when everything goes as expected
+func TestWithTransaction_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		var insideSession bool
+
+		// Act
+		err := repo.WithTransaction(context.Background(), func(ctx context.Context) error {
+			insideSession = mongo.SessionFromContext(ctx) != nil
+			_, err := repo.Create(ctx, entities.User{})
+			return err
+		})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.True(t, insideSession)
+	})
+}
+
+// TestWithTransaction_FunctionError checks that WithTransaction returns the error of the received function
+func TestWithTransaction_FunctionError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		expectedError := "function error"
+
+		// Act
+		err := repo.WithTransaction(context.Background(), func(ctx context.Context) error {
+			return fmt.Errorf(expectedError)
+		})
+
+		// Assert
+		assert.Equal(t, expectedError, err.Error())
+	})
+}
+
+// TestSearch_AtlasSearch checks that Search returns the users found by Atlas Search when it is available
+func TestSearch_AtlasSearch(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		expectedUser := entities.User{Name: "test"}
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "name", Value: expectedUser.Name}}))
+
+		// Act
+		result, err := repo.Search(context.Background(), "tset", false, map[string]interface{}{"password_hash": 0}, nil, nil)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, expectedUser, *(result[0].(*entities.User)))
+		assert.Equal(t, "aggregate", mt.GetStartedEvent().CommandName)
+		assert.False(t, repo.atlasSearchUnavailable.Load())
+	})
+}
+
+// TestSearch_TextIndexFallback checks that Search falls back to the text index when the server does not support Atlas Search
+func TestSearch_TextIndexFallback(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		expectedUser := entities.User{Name: "test"}
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 40324, Message: "Unrecognized pipeline stage name: '$search'"}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "name", Value: expectedUser.Name}}),
+		)
+
+		// Act
+		result, err := repo.Search(context.Background(), "test", false, nil, nil, nil)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, expectedUser, *(result[0].(*entities.User)))
+		assert.True(t, repo.atlasSearchUnavailable.Load())
+		mt.GetStartedEvent()
+		findCommand := mt.GetStartedEvent()
+		assert.Equal(t, "find", findCommand.CommandName)
+		assert.Equal(t, "test", findCommand.Command.Lookup("filter", "$text", "$search").StringValue())
+	})
+}
+
+// TestSearch_AutocompleteFallback checks that Search matches the prefix of the fields when Atlas Search is not available in autocomplete mode
+func TestSearch_AutocompleteFallback(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+		repo.atlasSearchUnavailable.Store(true)
+
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.Search(context.Background(), "te.", true, nil, nil, nil)
+
+		// Assert
+		assert.Equal(t, wrappers.NewNonExistentErr(mongo.ErrNoDocuments), err)
+		findCommand := mt.GetStartedEvent()
+		assert.Equal(t, "find", findCommand.CommandName)
+		pattern, _ := findCommand.Command.Lookup("filter", "$or", "0", "name").Regex()
+		assert.Equal(t, `^te\.`, pattern)
+	})
+}
+
+// TestSearch_AggregateError checks that Search returns an error when the aggregation fails for a reason other than Atlas Search not being available
+func TestSearch_AggregateError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "bad value"}))
+
+		// Act
+		_, err := repo.Search(context.Background(), "test", false, nil, nil, nil)
+
+		// Assert
+		assert.NotEmpty(t, err)
+		assert.False(t, repo.atlasSearchUnavailable.Load())
+	})
+}
+
+// TestGetNearby_Ok checks that GetNearby queries the location with a $nearSphere filter and returns the found users
+func TestGetNearby_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		location := entities.NewGeoPoint(2.17, 41.38)
+		expectedUser := entities.User{Name: "test", Location: &location}
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{
+			{Key: "name", Value: expectedUser.Name},
+			{Key: "location", Value: bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{2.17, 41.38}}}},
+		}))
+
+		// Act
+		result, err := repo.GetNearby(context.Background(), 2.17, 41.38, 1000, nil, nil, nil)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, expectedUser, *(result[0].(*entities.User)))
+		maxDistance := mt.GetStartedEvent().Command.Lookup("filter", "location", "$nearSphere", "$maxDistance").Double()
+		assert.Equal(t, float64(1000), maxDistance)
+	})
+}
+
+// TestUpdateLastLogin_Ok checks that UpdateLastLogin does not return an error when the user exists
+func TestUpdateLastLogin_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.UpdateLastLogin(context.Background(), primitive.NewObjectID().Hex(), time.Now().UTC())
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestUpdateNames_Ok checks that UpdateNames sets only the name, the surnames and the update date of the user
+func TestUpdateNames_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.UpdateNames(context.Background(), primitive.NewObjectID().Hex(), "test-name", "test-surnames", time.Now().UTC())
+
+		// Assert
+		assert.Nil(t, err)
+		set := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+		elements, _ := set.Elements()
+		assert.Equal(t, 3, len(elements))
+		assert.Equal(t, "test-name", set.Lookup("name").StringValue())
+		assert.Equal(t, "test-surnames", set.Lookup("surnames").StringValue())
+	})
+}
+
+// TestUpdateNames_NotFound checks that UpdateNames returns a non existent error when no user has the ID
+func TestUpdateNames_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.UpdateNames(context.Background(), primitive.NewObjectID().Hex(), "test-name", "test-surnames", time.Now().UTC())
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestUpdateBillingCustomer_Ok checks that UpdateBillingCustomer sets the customer of the user
+func TestUpdateBillingCustomer_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.UpdateBillingCustomer(context.Background(), primitive.NewObjectID().Hex(), "cus_123")
+
+		// Assert
+		assert.Nil(t, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "cus_123", update.Lookup("u", "$set", "billing_customer_id").StringValue())
+	})
+}
+
+// TestUpdateSubscription_Ok checks that UpdateSubscription sets the status of the user of the customer found, unless set as of a later time
+func TestUpdateSubscription_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.users", mtest.FirstBatch, bson.D{{Key: "_id", Value: id}}),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}},
+		)
+
+		// Act
+		err := repo.UpdateSubscription(context.Background(), "cus_123", "active", time.Now().UTC())
+
+		// Assert
+		assert.Nil(t, err)
+		mt.GetStartedEvent() // the find of the user of the customer
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, id, update.Lookup("q", "_id").ObjectID())
+		assert.Equal(t, "active", update.Lookup("u", "$set", "subscription_status").StringValue())
+	})
+}
+
+// TestUpdateSubscription_NotFound checks that UpdateSubscription returns a NonExistent error when no user has the customer
+func TestUpdateSubscription_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.users", mtest.FirstBatch))
+
+		// Act
+		err := repo.UpdateSubscription(context.Background(), "cus_123", "active", time.Now().UTC())
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestUpdateLastLogin_NotFound checks that UpdateLastLogin returns a NonExistent error when the user does not exist
+func TestUpdateLastLogin_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.UpdateLastLogin(context.Background(), primitive.NewObjectID().Hex(), time.Now().UTC())
+
+		// Assert
+		assert.NotEmpty(t, err)
+		assert.IsType(t, wrappers.NonExistentErr, err)
+	})
+}
+
+// TestArchive_Ok checks that Archive moves the inactive users to the archive collection and returns how many were moved
+func TestArchive_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		id := primitive.NewObjectID()
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: id}}))
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: bson.D{{Key: "_id", Value: id}, {Key: "name", Value: "test"}}}})
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		count, err := repo.Archive(context.Background(), time.Now().UTC().Add(-time.Hour), time.Now().UTC())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+// TestArchive_FindError checks that Archive returns an error when the inactive users cannot be found
+func TestArchive_FindError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+
+		// Act
+		_, err := repo.Archive(context.Background(), time.Now().UTC().Add(-time.Hour), time.Now().UTC())
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
+
+// TestArchiveByID_Ok checks that ArchiveByID moves the user to the archive collection
+func TestArchiveByID_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: bson.D{{Key: "_id", Value: id}}}})
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.ArchiveByID(context.Background(), id.Hex(), time.Now().UTC())
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestArchiveByID_NotFound checks that ArchiveByID returns a NonExistent error when the user is not found
+func TestArchiveByID_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.ArchiveByID(context.Background(), primitive.NewObjectID().Hex(), time.Now().UTC())
+
+		// Assert
+		assert.NotEmpty(t, err)
+		assert.IsType(t, wrappers.NonExistentErr, err)
+	})
+}
+
+// TestUnarchive_Ok checks that Unarchive moves the archived user back to the users collection
+func TestUnarchive_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: bson.D{{Key: "_id", Value: id}, {Key: "archived_at", Value: time.Now().UTC()}}}})
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.Unarchive(context.Background(), id.Hex(), time.Now().UTC())
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestUnarchive_NotFound checks that Unarchive returns a NonExistent error when the user is not archived
+func TestUnarchive_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.Unarchive(context.Background(), primitive.NewObjectID().Hex(), time.Now().UTC())
+
+		// Assert
+		assert.NotEmpty(t, err)
+		assert.IsType(t, wrappers.NonExistentErr, err)
+	})
+}
+
+// TestUpdate_Ok checks that Update filters only by ID, without looking up the shard key of the user first
+func TestUpdate_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.Update(context.Background(), id.Hex(), entities.User{Email: "new@test.com"})
+
+		// Assert
+		assert.Nil(t, err)
+		started := mt.GetStartedEvent()
+		assert.Equal(t, "update", started.CommandName)
+		filter, _ := started.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document().Elements()
+		assert.Equal(t, 1, len(filter))
+		assert.Equal(t, id, filter[0].Value().ObjectID())
+	})
+}
+
+// TestUpdate_NotFound checks that Update returns a NonExistent error when no user is updated
+func TestUpdate_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.Update(context.Background(), primitive.NewObjectID().Hex(), entities.User{Name: "test"})
+
+		// Assert
+		assert.NotEmpty(t, err)
+		assert.IsType(t, wrappers.NonExistentErr, err)
+	})
+}
+
+// TestDump_Ok checks that Dump calls the received function with every user
+func TestDump_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		first := mtest.CreateCursorResponse(1, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "password_hash", Value: "test-hash"}})
+		killCursors := mtest.CreateCursorResponse(0, ns, mtest.NextBatch, bson.D{{Key: "_id", Value: primitive.NewObjectID()}})
+		mt.AddMockResponses(first, killCursors)
+
+		var users []entities.User
+
+		// Act
+		err := repo.Dump(context.Background(), func(user entities.User) error {
+			users = append(users, user)
+			return nil
+		})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, "test-hash", users[0].PasswordHash)
+	})
+}
+
+// TestDump_FnError checks that Dump stops and returns the error of the received function
+func TestDump_FnError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		expectedError := "fn-error"
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: primitive.NewObjectID()}}))
+
+		// Act
+		err := repo.Dump(context.Background(), func(user entities.User) error {
+			return fmt.Errorf(expectedError)
+		})
+
+		// Assert
+		assert.Equal(t, expectedError, err.Error())
+	})
+}
+
+// TestRestore_Ok checks that Restore replaces the users keeping their IDs as ObjectIDs
+func TestRestore_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.Restore(context.Background(), []entities.User{{ID: id.Hex(), Email: "test@test.com"}})
+
+		// Assert
+		assert.Nil(t, err)
+		var inserted bool
+		for _, e := range mt.GetAllStartedEvents() {
+			if e.CommandName == "insert" {
+				inserted = true
+				doc := e.Command.Lookup("documents").Array().Index(0).Value().Document()
+				assert.Equal(t, id, doc.Lookup("_id").ObjectID())
+			}
+		}
+		assert.True(t, inserted)
+	})
+}
+
+// TestRestore_InvalidID checks that Restore returns an error without deleting the users when an ID is not valid
+func TestRestore_InvalidID(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		// Act
+		err := repo.Restore(context.Background(), []entities.User{{ID: "invalid"}})
+
+		// Assert
+		assert.NotNil(t, err)
+		assert.Empty(t, mt.GetAllStartedEvents())
+	})
+}
+
+// TestStats_Ok checks that Stats counts the users, the active ones and the archived ones
+func TestStats_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+	defer mt.Close()
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			MongoRepository: infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		ns := mt.DB.Name() + "." + entities.EntityNameUser
+		for _, n := range []int32{3, 2, 1} {
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: n}}))
+		}
+
+		// Act
+		stats, err := repo.Stats(context.Background(), time.Now().UTC().Add(-time.Hour))
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, entities.UserStats{Total: 3, Active: 2, Archived: 1}, stats)
+	})
+}
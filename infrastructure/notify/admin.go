@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// headers of the requests of the generic webhooks, the signature being the hex HMAC-SHA256 of the timestamp, a dot and the body
+const (
+	headerWebhookEvent     = "X-Webhook-Event"
+	headerWebhookTimestamp = "X-Webhook-Timestamp"
+	headerWebhookSignature = "X-Webhook-Signature"
+)
+
+// slackAdminNotifier adapter of an admin notifier posting the notices to a Slack incoming webhook
+type slackAdminNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackAdminNotifier creates an admin notifier posting the notices to the given Slack incoming webhook
+func NewSlackAdminNotifier(webhookURL string) ports.AdminNotifier {
+	return &slackAdminNotifier{
+		webhookURL: webhookURL,
+		client:     http.DefaultClient,
+	}
+}
+
+// NotifyAdmins posts the text of the notice followed by its fields, in the order of their names
+func (n *slackAdminNotifier) NotifyAdmins(ctx context.Context, notice ports.AdminNotice) error {
+	lines := []string{fmt.Sprintf("[%s] %s", notice.Event, notice.Text)}
+	for _, name := range sortedFields(notice.Fields) {
+		lines = append(lines, fmt.Sprintf("• %s: %s", name, notice.Fields[name]))
+	}
+
+	body, err := json.Marshal(map[string]string{"text": strings.Join(lines, "\n")})
+	if err != nil {
+		return err
+	}
+	return post(ctx, n.client, "slack webhook", n.webhookURL, body, nil)
+}
+
+// webhookAdminNotifier adapter of an admin notifier posting the notices as JSON to a generic webhook
+type webhookAdminNotifier struct {
+	URL    string
+	secret string
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewWebhookAdminNotifier creates an admin notifier posting the notices to the given URL, signing them with the secret when set
+func NewWebhookAdminNotifier(URL, secret string) ports.AdminNotifier {
+	return &webhookAdminNotifier{
+		URL:    URL,
+		secret: secret,
+		client: http.DefaultClient,
+		now:    time.Now,
+	}
+}
+
+// NotifyAdmins posts the notice, naming its event in the X-Webhook-Event header, and signing it in the X-Webhook-Signature header
+// along with the X-Webhook-Timestamp one, so the receivers can tell it apart from forged or replayed ones
+func (n *webhookAdminNotifier) NotifyAdmins(ctx context.Context, notice ports.AdminNotice) error {
+	body, err := json.Marshal(struct {
+		Event  string            `json:"event"`
+		Text   string            `json:"text"`
+		Fields map[string]string `json:"fields,omitempty"`
+		At     time.Time         `json:"at"`
+	}{notice.Event, notice.Text, notice.Fields, notice.At.UTC()})
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{headerWebhookEvent: notice.Event}
+	if n.secret != "" {
+		timestamp := strconv.FormatInt(n.now().Unix(), 10)
+		headers[headerWebhookTimestamp] = timestamp
+		headers[headerWebhookSignature] = "sha256=" + Signature(n.secret, timestamp, body)
+	}
+	return post(ctx, n.client, "webhook", n.URL, body, headers)
+}
+
+// Signature returns the hex HMAC-SHA256 with the secret of the timestamp, a dot and the body, as the generic webhooks are signed
+func Signature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// adminNotifiers adapter of an admin notifier posting the notices to every one of its notifiers
+type adminNotifiers []ports.AdminNotifier
+
+// NewAdminNotifier creates an admin notifier posting the notices to the Slack incoming webhook, when set,
+// and to every one of the generic webhooks, signed with the webhook secret when set
+func NewAdminNotifier(slackWebhookURL string, webhookURLs []string, webhookSecret string) ports.AdminNotifier {
+	var notifiers adminNotifiers
+	if slackWebhookURL != "" {
+		notifiers = append(notifiers, NewSlackAdminNotifier(slackWebhookURL))
+	}
+	for _, URL := range webhookURLs {
+		notifiers = append(notifiers, NewWebhookAdminNotifier(URL, webhookSecret))
+	}
+	return notifiers
+}
+
+// NotifyAdmins posts the notice to every notifier, even when some of them fail, returning their errors joined
+func (n adminNotifiers) NotifyAdmins(ctx context.Context, notice ports.AdminNotice) error {
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.NotifyAdmins(ctx, notice); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// alertNotifier adapter of a notifier posting the alerts as admin notices
+type alertNotifier struct {
+	admins ports.AdminNotifier
+}
+
+// NewAlertNotifier creates a notifier posting the alerts as notices of the alert event to the given admin notifier
+func NewAlertNotifier(admins ports.AdminNotifier) ports.Notifier {
+	return &alertNotifier{admins: admins}
+}
+
+// Notify posts the message of the alert, its values being the fields of the notice
+func (n *alertNotifier) Notify(ctx context.Context, alert ports.Alert) error {
+	return n.admins.NotifyAdmins(ctx, ports.AdminNotice{
+		Event: ports.AdminEventAlert,
+		Text:  message(alert),
+		Fields: map[string]string{
+			"alert":     alert.Name,
+			"firing":    strconv.FormatBool(alert.Firing),
+			"value":     formatValue(alert.Value),
+			"threshold": formatValue(alert.Threshold),
+			"window":    alert.Window.String(),
+		},
+		At: alert.At,
+	})
+}
+
+func sortedFields(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
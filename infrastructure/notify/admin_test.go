@@ -0,0 +1,140 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSlackNotifyAdmins_Ok checks that NotifyAdmins posts the text of the notice followed by its fields to the webhook
+func TestSlackNotifyAdmins_Ok(t *testing.T) {
+	// Arrange
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	notice := ports.AdminNotice{
+		Event:  ports.AdminEventLockout,
+		Text:   "logins of test@example.com locked out after 5 failures",
+		Fields: map[string]string{"until": "2023-07-15T09:15:00Z", "email": "test@example.com"},
+		At:     time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC),
+	}
+
+	// Act
+	err := NewSlackAdminNotifier(server.URL).NotifyAdmins(context.Background(), notice)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "[lockout] logins of test@example.com locked out after 5 failures\n• email: test@example.com\n• until: 2023-07-15T09:15:00Z", body["text"])
+}
+
+// TestWebhookNotifyAdmins_Ok checks that NotifyAdmins posts the notice as JSON, signing it with the secret
+func TestWebhookNotifyAdmins_Ok(t *testing.T) {
+	// Arrange
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+	}))
+	defer server.Close()
+
+	notice := ports.AdminNotice{
+		Event:  ports.AdminEventMigration,
+		Text:   "database migrated from version 3 to 4",
+		Fields: map[string]string{"from": "3", "to": "4"},
+		At:     time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC),
+	}
+	notifier := NewWebhookAdminNotifier(server.URL, "test-secret").(*webhookAdminNotifier)
+	notifier.now = func() time.Time { return time.Unix(1689411600, 0) }
+
+	// Act
+	err := notifier.NotifyAdmins(context.Background(), notice)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"event":"migration","text":"database migrated from version 3 to 4","fields":{"from":"3","to":"4"},"at":"2023-07-15T09:00:00Z"}`, string(body))
+	assert.Equal(t, "migration", header.Get("X-Webhook-Event"))
+	assert.Equal(t, "1689411600", header.Get("X-Webhook-Timestamp"))
+	assert.Equal(t, "sha256="+Signature("test-secret", "1689411600", body), header.Get("X-Webhook-Signature"))
+}
+
+// TestWebhookNotifyAdmins_NoSecret checks that NotifyAdmins does not sign the notice when no secret is set
+func TestWebhookNotifyAdmins_NoSecret(t *testing.T) {
+	// Arrange
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer server.Close()
+
+	// Act
+	err := NewWebhookAdminNotifier(server.URL, "").NotifyAdmins(context.Background(), ports.AdminNotice{Event: ports.AdminEventLockout})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, header.Get("X-Webhook-Signature"))
+}
+
+// TestSignature_Ok checks that Signature returns the hex HMAC-SHA256 of the timestamp, a dot and the body
+func TestSignature_Ok(t *testing.T) {
+	// Act
+	signature := Signature("key", "1689411600", []byte("{}"))
+
+	// Assert
+	assert.Equal(t, "c912b710ab1402d6bcf3231b3f08c7060ac37e70b342a6e4f9fb435017f7ab43", signature)
+}
+
+// TestAdminNotifier_SomeFailing checks that NotifyAdmins posts the notice to every target, returning the errors of the failing ones
+func TestAdminNotifier_SomeFailing(t *testing.T) {
+	// Arrange
+	var posted int
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted++
+	}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	notifier := NewAdminNotifier(failing.URL, []string{ok.URL, failing.URL}, "")
+
+	// Act
+	err := notifier.NotifyAdmins(context.Background(), ports.AdminNotice{Event: ports.AdminEventLockout})
+
+	// Assert
+	assert.EqualError(t, err, "slack webhook responded with status 502\nwebhook responded with status 502")
+	assert.Equal(t, 3, posted)
+}
+
+// TestAlertNotify_Ok checks that Notify posts the alert as a notice of the alert event
+func TestAlertNotify_Ok(t *testing.T) {
+	// Arrange
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	alert := ports.Alert{Name: "error_rate", Firing: true, Value: 0.125, Threshold: 0.05, Window: 5 * time.Minute, At: time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC)}
+
+	// Act
+	err := NewAlertNotifier(NewWebhookAdminNotifier(server.URL, "")).Notify(context.Background(), alert)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "alert", body["event"])
+	assert.Equal(t, "[FIRING] error_rate is 0.125 over the last 5m0s, reaching the threshold of 0.05 at 2023-07-15T09:00:00Z", body["text"])
+	assert.Equal(t, map[string]interface{}{"alert": "error_rate", "firing": "true", "value": "0.125", "threshold": "0.05", "window": "5m0s"}, body["fields"])
+}
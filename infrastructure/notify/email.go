@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+)
+
+// emailNotifier adapter of a notifier sending the alerts by email through an SMTP server
+type emailNotifier struct {
+	address string
+	auth    smtp.Auth
+	from    string
+	to      []string
+	send    func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier creates a notifier sending the alerts from the given address to the given recipients through the SMTP server at address,
+// authenticating with the username and password when set
+func NewEmailNotifier(address, username, password, from string, to []string) ports.Notifier {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := net.SplitHostPort(address)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &emailNotifier{
+		address: address,
+		auth:    auth,
+		from:    from,
+		to:      to,
+		send:    smtp.SendMail,
+	}
+}
+
+// Notify sends the alert, the SMTP client not supporting the cancellation of the context once started.
+// The trace of the context, if any, is propagated in the Traceparent and Tracestate headers of the email.
+func (n *emailNotifier) Notify(ctx context.Context, alert ports.Alert) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n%sContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		n.from, strings.Join(n.to, ", "), subject(alert), tracing.MIMEHeaders(ctx), message(alert))
+	return n.send(n.address, n.auth, n.from, n.to, []byte(msg))
+}
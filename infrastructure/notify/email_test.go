@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"net/smtp"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestEmailNotify_Ok checks that Notify sends the alert from the configured address to every recipient
+func TestEmailNotify_Ok(t *testing.T) {
+	// Arrange
+	n := NewEmailNotifier("localhost:25", "", "", "api@test.com", []string{"ops@test.com", "oncall@test.com"}).(*emailNotifier)
+	var sentTo []string
+	var sentMsg string
+	n.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sentTo = to
+		sentMsg = string(msg)
+		return nil
+	}
+
+	// Act
+	err := n.Notify(context.Background(), ports.Alert{Name: "failed_logins", Value: 3, Threshold: 50})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ops@test.com", "oncall@test.com"}, sentTo)
+	assert.Contains(t, sentMsg, "To: ops@test.com, oncall@test.com\r\n")
+	assert.Contains(t, sentMsg, "Subject: [RESOLVED] failed_logins\r\n")
+	assert.Contains(t, sentMsg, "back below the threshold of 50")
+}
+
+// TestEmailNotify_TraceContext checks that Notify propagates the trace of the context in the headers of the email
+func TestEmailNotify_TraceContext(t *testing.T) {
+	// Arrange
+	n := NewEmailNotifier("localhost:25", "", "", "api@test.com", []string{"ops@test.com"}).(*emailNotifier)
+	var sentMsg string
+	n.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sentMsg = string(msg)
+		return nil
+	}
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9},
+		SpanID:     trace.SpanID{0x01},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+
+	// Act
+	err := n.Notify(ctx, ports.Alert{Name: "failed_logins"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Contains(t, sentMsg, "Subject: [RESOLVED] failed_logins\r\nTraceparent: 00-4bf90000000000000000000000000000-0100000000000000-01\r\nContent-Type:")
+}
+
+// TestNewEmailNotifier_Auth checks that NewEmailNotifier authenticates only when a username is set
+func TestNewEmailNotifier_Auth(t *testing.T) {
+	// Act
+	withAuth := NewEmailNotifier("localhost:587", "user", "password", "api@test.com", nil).(*emailNotifier)
+	withoutAuth := NewEmailNotifier("localhost:25", "", "", "api@test.com", nil).(*emailNotifier)
+
+	// Assert
+	assert.NotNil(t, withAuth.auth)
+	assert.Nil(t, withoutAuth.auth)
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// subject returns the one line summary of an alert, like [FIRING] error_rate
+func subject(alert ports.Alert) string {
+	state := "RESOLVED"
+	if alert.Firing {
+		state = "FIRING"
+	}
+	return fmt.Sprintf("[%s] %s", state, alert.Name)
+}
+
+// message returns the description of an alert, like [FIRING] error_rate is 0.12 over the last 5m0s, reaching the threshold of 0.05
+func message(alert ports.Alert) string {
+	verb := "reaching"
+	if !alert.Firing {
+		verb = "back below"
+	}
+	return fmt.Sprintf("%s is %s over the last %s, %s the threshold of %s at %s",
+		subject(alert), formatValue(alert.Value), alert.Window, verb, formatValue(alert.Threshold), alert.At.UTC().Format("2006-01-02T15:04:05Z"))
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// CheckSMTP verifies that the SMTP server at address accepts the connections, upgrading them with STARTTLS when offered,
+// and the login with the username and password when set, the same way as the email notifier.
+// It returns whether the connection was encrypted and authenticated.
+func CheckSMTP(ctx context.Context, address, username, password string) (string, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return "", err
+	}
+	defer client.Close()
+
+	details := []string{"plain"}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return "", err
+		}
+		details[0] = "starttls"
+	}
+	if username != "" {
+		if err := client.Auth(smtp.PlainAuth("", username, password, host)); err != nil {
+			return strings.Join(details, ", "), err
+		}
+		details = append(details, "authenticated")
+	}
+	return strings.Join(details, ", "), client.Quit()
+}
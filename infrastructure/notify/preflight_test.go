@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// serveSMTP starts an SMTP server offering the plain authentication, replying authReply to it, and returns its address
+func serveSMTP(t *testing.T, authReply string) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("220 test ESMTP\r\n"))
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				conn.Write([]byte("250-test\r\n250 AUTH PLAIN\r\n"))
+			case strings.HasPrefix(line, "AUTH"):
+				conn.Write([]byte(authReply))
+			case strings.HasPrefix(line, "QUIT"):
+				conn.Write([]byte("221 bye\r\n"))
+				return
+			default:
+				conn.Write([]byte("250 ok\r\n"))
+			}
+		}
+	}()
+	return l.Addr().String()
+}
+
+// TestCheckSMTP_Ok checks that CheckSMTP logs in to the SMTP server
+func TestCheckSMTP_Ok(t *testing.T) {
+	// Arrange
+	address := serveSMTP(t, "235 accepted\r\n")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	detail, err := CheckSMTP(ctx, address, "test-user", "test-password")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "plain, authenticated", detail)
+}
+
+// TestCheckSMTP_AuthError checks that CheckSMTP returns the error of the login rejected by the SMTP server
+func TestCheckSMTP_AuthError(t *testing.T) {
+	// Arrange
+	address := serveSMTP(t, "535 authentication failed\r\n")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	detail, err := CheckSMTP(ctx, address, "test-user", "wrong-password")
+
+	// Assert
+	assert.Equal(t, "plain", detail)
+	assert.ErrorContains(t, err, "authentication failed")
+}
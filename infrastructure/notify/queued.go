@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// channels the queued notifications are delivered through
+const (
+	ChannelSlack = "slack"
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelAdmin = "admin"
+)
+
+// queuedNotifier adapter of a notifier queuing the alerts as notification jobs, delivered through their channel by the workers
+// and retried when the channel is not reachable
+type queuedNotifier struct {
+	jobs    ports.JobService
+	channel string
+}
+
+// NewQueuedNotifier creates a notifier queuing the alerts to be delivered through the given channel
+func NewQueuedNotifier(jobs ports.JobService, channel string) ports.Notifier {
+	return &queuedNotifier{
+		jobs:    jobs,
+		channel: channel,
+	}
+}
+
+// Notify queues the notification of the alert
+func (n *queuedNotifier) Notify(ctx context.Context, alert ports.Alert) error {
+	_, err := n.jobs.Enqueue(ctx, entities.JobTypeNotification, map[string]string{
+		"channel":   n.channel,
+		"alert":     alert.Name,
+		"firing":    strconv.FormatBool(alert.Firing),
+		"value":     formatValue(alert.Value),
+		"threshold": formatValue(alert.Threshold),
+		"window":    alert.Window.String(),
+		"at":        alert.At.UTC().Format(time.RFC3339Nano),
+	})
+	return err
+}
+
+// Deliver delivers a notification job through the notifier of its channel
+func Deliver(ctx context.Context, notifiers map[string]ports.Notifier, job *entities.Job) error {
+	channel := job.Metadata["channel"]
+	notifier, ok := notifiers[channel]
+	if !ok {
+		return fmt.Errorf("notification channel %q not configured", channel)
+	}
+
+	alert, err := decodeAlert(job.Metadata)
+	if err != nil {
+		return fmt.Errorf("notification not valid: %w", err)
+	}
+	return notifier.Notify(ctx, alert)
+}
+
+// decodeAlert returns the alert of the metadata of a notification job
+func decodeAlert(metadata map[string]string) (alert ports.Alert, err error) {
+	alert.Name = metadata["alert"]
+	if alert.Firing, err = strconv.ParseBool(metadata["firing"]); err != nil {
+		return
+	}
+	if alert.Value, err = strconv.ParseFloat(metadata["value"], 64); err != nil {
+		return
+	}
+	if alert.Threshold, err = strconv.ParseFloat(metadata["threshold"], 64); err != nil {
+		return
+	}
+	if alert.Window, err = time.ParseDuration(metadata["window"]); err != nil {
+		return
+	}
+	alert.At, err = time.Parse(time.RFC3339Nano, metadata["at"])
+	return
+}
+
+// fieldPrefix prefixes the metadata of the admin notice jobs holding the fields of the notice
+const fieldPrefix = "field."
+
+// queuedAdminNotifier adapter of an admin notifier queuing the notices as admin notice jobs, posted by the workers
+// and retried when the targets are not reachable
+type queuedAdminNotifier struct {
+	jobs ports.JobService
+}
+
+// NewQueuedAdminNotifier creates an admin notifier queuing the notices to be posted by the workers
+func NewQueuedAdminNotifier(jobs ports.JobService) ports.AdminNotifier {
+	return &queuedAdminNotifier{jobs: jobs}
+}
+
+// NotifyAdmins queues the notice
+func (n *queuedAdminNotifier) NotifyAdmins(ctx context.Context, notice ports.AdminNotice) error {
+	metadata := map[string]string{
+		"event": notice.Event,
+		"text":  notice.Text,
+		"at":    notice.At.UTC().Format(time.RFC3339Nano),
+	}
+	for name, value := range notice.Fields {
+		metadata[fieldPrefix+name] = value
+	}
+	_, err := n.jobs.Enqueue(ctx, entities.JobTypeAdminNotice, metadata)
+	return err
+}
+
+// DeliverNotice posts an admin notice job through the admin notifier
+func DeliverNotice(ctx context.Context, notifier ports.AdminNotifier, job *entities.Job) error {
+	at, err := time.Parse(time.RFC3339Nano, job.Metadata["at"])
+	if err != nil {
+		return fmt.Errorf("admin notice not valid: %w", err)
+	}
+
+	notice := ports.AdminNotice{Event: job.Metadata["event"], Text: job.Metadata["text"], At: at}
+	for key, value := range job.Metadata {
+		if name, ok := strings.CutPrefix(key, fieldPrefix); ok {
+			if notice.Fields == nil {
+				notice.Fields = map[string]string{}
+			}
+			notice.Fields[name] = value
+		}
+	}
+	return notifier.NotifyAdmins(ctx, notice)
+}
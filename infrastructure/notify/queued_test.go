@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestQueuedNotify_Ok checks that Notify queues a notification job that Deliver delivers as the same alert through its channel
+func TestQueuedNotify_Ok(t *testing.T) {
+	// Arrange
+	alert := ports.Alert{Name: "error_rate", Firing: true, Value: 0.125, Threshold: 0.05, Window: 5 * time.Minute, At: time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC)}
+
+	var job entities.Job
+	jobServiceMock := mocks.NewJobService(t)
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Enqueue), context.Background(), entities.JobTypeNotification, mock.Anything).Run(func(args mock.Arguments) {
+		job = entities.Job{Type: entities.JobTypeNotification, Metadata: args.Get(2).(map[string]string)}
+	}).Return(models.JobResp{}, nil).Once()
+
+	notifierMock := mocks.NewNotifier(t)
+	notifierMock.On(testutils.FunctionName(t, ports.Notifier.Notify), context.Background(), alert).Return(nil).Once()
+
+	// Act
+	err := NewQueuedNotifier(jobServiceMock, ChannelSlack).Notify(context.Background(), alert)
+	deliverErr := Deliver(context.Background(), map[string]ports.Notifier{ChannelSlack: notifierMock}, &job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, deliverErr)
+}
+
+// TestDeliver_ChannelNotConfigured checks that Deliver returns an error when the channel of the notification is not configured
+func TestDeliver_ChannelNotConfigured(t *testing.T) {
+	// Arrange
+	job := entities.Job{Metadata: map[string]string{"channel": ChannelEmail}}
+
+	// Act
+	err := Deliver(context.Background(), map[string]ports.Notifier{}, &job)
+
+	// Assert
+	assert.EqualError(t, err, `notification channel "email" not configured`)
+}
+
+// TestQueuedNotifyAdmins_Ok checks that NotifyAdmins queues an admin notice job that DeliverNotice posts as the same notice
+func TestQueuedNotifyAdmins_Ok(t *testing.T) {
+	// Arrange
+	notice := ports.AdminNotice{
+		Event:  ports.AdminEventLockout,
+		Text:   "logins of test@example.com locked out after 5 failures",
+		Fields: map[string]string{"email": "test@example.com"},
+		At:     time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC),
+	}
+
+	var job entities.Job
+	jobServiceMock := mocks.NewJobService(t)
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Enqueue), context.Background(), entities.JobTypeAdminNotice, mock.Anything).Run(func(args mock.Arguments) {
+		job = entities.Job{Type: entities.JobTypeAdminNotice, Metadata: args.Get(2).(map[string]string)}
+	}).Return(models.JobResp{}, nil).Once()
+
+	adminNotifierMock := mocks.NewAdminNotifier(t)
+	adminNotifierMock.On(testutils.FunctionName(t, ports.AdminNotifier.NotifyAdmins), context.Background(), notice).Return(nil).Once()
+
+	// Act
+	err := NewQueuedAdminNotifier(jobServiceMock).NotifyAdmins(context.Background(), notice)
+	deliverErr := DeliverNotice(context.Background(), adminNotifierMock, &job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, deliverErr)
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+)
+
+// slackNotifier adapter of a notifier posting the alerts to a Slack incoming webhook
+type slackNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackNotifier creates a notifier posting the alerts to the given Slack incoming webhook
+func NewSlackNotifier(webhookURL string) ports.Notifier {
+	return &slackNotifier{
+		webhookURL: webhookURL,
+		client:     http.DefaultClient,
+	}
+}
+
+// Notify posts the alert, propagating the trace of the context, if any
+func (n *slackNotifier) Notify(ctx context.Context, alert ports.Alert) error {
+	body, err := json.Marshal(map[string]string{"text": message(alert)})
+	if err != nil {
+		return err
+	}
+
+	return post(ctx, n.client, "slack webhook", n.webhookURL, body, nil)
+}
+
+// post posts the JSON body to the URL with the given headers, propagating the trace of the context, if any,
+// and failing when the named target does not accept it
+func post(ctx context.Context, client *http.Client, target, URL string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	tracing.InjectHeader(ctx, req.Header)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%s responded with status %d", target, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestSlackNotify_Ok checks that Notify posts the message of the alert to the webhook
+func TestSlackNotify_Ok(t *testing.T) {
+	// Arrange
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	alert := ports.Alert{Name: "error_rate", Firing: true, Value: 0.125, Threshold: 0.05, Window: 5 * time.Minute, At: time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC)}
+
+	// Act
+	err := NewSlackNotifier(server.URL).Notify(context.Background(), alert)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "[FIRING] error_rate is 0.125 over the last 5m0s, reaching the threshold of 0.05 at 2023-07-15T09:00:00Z", body["text"])
+}
+
+// TestSlackNotify_ErrorStatus checks that Notify returns an error when the webhook does not accept the message
+func TestSlackNotify_ErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	// Act
+	err := NewSlackNotifier(server.URL).Notify(context.Background(), ports.Alert{Name: "error_rate"})
+
+	// Assert
+	assert.EqualError(t, err, "slack webhook responded with status 403")
+}
+
+// TestSlackNotify_TraceContext checks that Notify propagates the trace of the context in the W3C trace context headers
+func TestSlackNotify_TraceContext(t *testing.T) {
+	// Arrange
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9},
+		SpanID:     trace.SpanID{0x01},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+
+	// Act
+	err := NewSlackNotifier(server.URL).Notify(ctx, ports.Alert{Name: "error_rate"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "00-4bf90000000000000000000000000000-0100000000000000-01", traceparent)
+}
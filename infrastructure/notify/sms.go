@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// smsNotifier adapter of a notifier sending the alerts by text message through an SMS sender
+type smsNotifier struct {
+	sender ports.SMSSender
+	to     []string
+}
+
+// NewSMSNotifier creates a notifier sending the alerts to the given phone numbers through the sender
+func NewSMSNotifier(sender ports.SMSSender, to []string) ports.Notifier {
+	return &smsNotifier{
+		sender: sender,
+		to:     to,
+	}
+}
+
+// Notify sends the alert to every phone number, trying all of them even when some fail, so the notification is retried as a whole
+func (n *smsNotifier) Notify(ctx context.Context, alert ports.Alert) error {
+	var failures []string
+	for _, to := range n.to {
+		if _, err := n.sender.Send(ctx, to, message(alert)); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", to, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("sms alert not sent to %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestSMSNotify_Ok checks that Notify sends the message of the alert to every phone number
+func TestSMSNotify_Ok(t *testing.T) {
+	// Arrange
+	alert := ports.Alert{Name: "failed_logins", Value: 3, Threshold: 50}
+	senderMock := mocks.NewSMSSender(t)
+	senderMock.On(testutils.FunctionName(t, ports.SMSSender.Send), mock.Anything, "+34600000001", message(alert)).Return("SM1", nil).Once()
+	senderMock.On(testutils.FunctionName(t, ports.SMSSender.Send), mock.Anything, "+34600000002", message(alert)).Return("SM2", nil).Once()
+
+	// Act
+	err := NewSMSNotifier(senderMock, []string{"+34600000001", "+34600000002"}).Notify(context.Background(), alert)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestSMSNotify_Failure checks that Notify sends the alert to every phone number even when some fail, returning their errors
+func TestSMSNotify_Failure(t *testing.T) {
+	// Arrange
+	senderMock := mocks.NewSMSSender(t)
+	senderMock.On(testutils.FunctionName(t, ports.SMSSender.Send), mock.Anything, "+34600000001", mock.Anything).Return("", fmt.Errorf("unreachable")).Once()
+	senderMock.On(testutils.FunctionName(t, ports.SMSSender.Send), mock.Anything, "+34600000002", mock.Anything).Return("SM2", nil).Once()
+
+	// Act
+	err := NewSMSNotifier(senderMock, []string{"+34600000001", "+34600000002"}).Notify(context.Background(), ports.Alert{Name: "failed_logins"})
+
+	// Assert
+	assert.EqualError(t, err, "sms alert not sent to +34600000001: unreachable")
+}
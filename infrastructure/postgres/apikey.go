@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// apiKeyColumns contains all the columns of the api_keys table, in select order
+const apiKeyColumns = "id, user_id, name, prefix, secret_hash, scopes, expires_at, last_used_at, revoked_at, created_at, updated_at"
+
+// apiKeyRepository adapter of an API key repository for postgres
+type apiKeyRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewAPIKeyRepository creates an API key repository for postgres
+func NewAPIKeyRepository(db *sql.DB) ports.APIKeyRepository {
+	return &apiKeyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key interface{}) (string, error) {
+	q := `
+	INSERT INTO api_keys (user_id, name, prefix, secret_hash, scopes, expires_at, last_used_at, revoked_at, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING id;
+	`
+	k := key.(entities.APIKey)
+	err := r.DB.QueryRowContext(ctx, q, k.UserID, k.Name, k.Prefix, k.SecretHash, pq.Array(nonNil(k.Scopes)), k.ExpiresAt, k.LastUsedAt, k.RevokedAt, k.CreatedAt, k.UpdatedAt).
+		Scan(&k.ID)
+	return k.ID, err
+}
+
+func (r *apiKeyRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	where, args := whereClause(filter)
+	q := fmt.Sprintf(`SELECT %s FROM api_keys %s ORDER BY created_at DESC`, apiKeyColumns, where)
+	if skip != nil {
+		q = fmt.Sprintf("%s OFFSET %d", q, *skip)
+	}
+	if take != nil {
+		q = fmt.Sprintf("%s LIMIT %d", q, *take)
+	}
+	keys, err := r.query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	result := make([]interface{}, 0, len(keys))
+	for i := range keys {
+		result = append(result, &keys[i])
+	}
+	return result, nil
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	key, err := scanAPIKey(r.DB.QueryRowContext(ctx, fmt.Sprintf(`SELECT %s FROM api_keys WHERE id = $1;`, apiKeyColumns), ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) Update(ctx context.Context, ID string, key interface{}) error {
+	q := `
+	UPDATE api_keys SET name = $1, prefix = $2, secret_hash = $3, scopes = $4, expires_at = $5, last_used_at = $6, revoked_at = $7, updated_at = $8
+	WHERE id = $9;
+	`
+	k := key.(entities.APIKey)
+	result, err := r.DB.ExecContext(ctx, q, k.Name, k.Prefix, k.SecretHash, pq.Array(nonNil(k.Scopes)), k.ExpiresAt, k.LastUsedAt, k.RevokedAt, k.UpdatedAt, ID)
+	return affected(result, err, wrappers.NewNonExistentErr(sql.ErrNoRows))
+}
+
+func (r *apiKeyRepository) Delete(ctx context.Context, ID string) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM api_keys WHERE id = $1`, ID)
+	return affected(result, err, wrappers.NewNonExistentErr(sql.ErrNoRows))
+}
+
+func (r *apiKeyRepository) GetByUser(ctx context.Context, userID string) ([]entities.APIKey, error) {
+	return r.query(ctx, fmt.Sprintf(`SELECT %s FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC;`, apiKeyColumns), userID)
+}
+
+func (r *apiKeyRepository) GetBySecretHash(ctx context.Context, secretHash string) (entities.APIKey, error) {
+	key, err := scanAPIKey(r.DB.QueryRowContext(ctx, fmt.Sprintf(`SELECT %s FROM api_keys WHERE secret_hash = $1;`, apiKeyColumns), secretHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("API key not found"))
+	}
+	return key, err
+}
+
+func (r *apiKeyRepository) Rotate(ctx context.Context, ID, prefix, secretHash string, at time.Time) error {
+	result, err := r.DB.ExecContext(ctx, `UPDATE api_keys SET prefix = $1, secret_hash = $2, updated_at = $3 WHERE id = $4 AND revoked_at IS NULL`,
+		prefix, secretHash, at, ID)
+	return affected(result, err, wrappers.NewNonExistentErr(fmt.Errorf("API key ID %s not found or revoked", ID)))
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, ID string, at time.Time) error {
+	result, err := r.DB.ExecContext(ctx, `UPDATE api_keys SET revoked_at = $1, updated_at = $1 WHERE id = $2 AND revoked_at IS NULL`, at, ID)
+	return affected(result, err, wrappers.NewNonExistentErr(fmt.Errorf("API key ID %s not found or revoked", ID)))
+}
+
+func (r *apiKeyRepository) Touch(ctx context.Context, ID string, at time.Time) error {
+	_, err := r.DB.ExecContext(ctx, `UPDATE api_keys SET last_used_at = $1 WHERE id = $2`, at, ID)
+	return err
+}
+
+func (r *apiKeyRepository) RevokeByUser(ctx context.Context, userID string, at time.Time) error {
+	_, err := r.DB.ExecContext(ctx, `UPDATE api_keys SET revoked_at = $1, updated_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`, at, userID)
+	return err
+}
+
+// query returns the API keys of the rows of apiKeyColumns selected by the query
+func (r *apiKeyRepository) query(ctx context.Context, q string, args ...interface{}) ([]entities.APIKey, error) {
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []entities.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// scanAPIKey scans a row of apiKeyColumns
+func scanAPIKey(row rowScanner) (entities.APIKey, error) {
+	var key entities.APIKey
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.SecretHash, pq.Array(&key.Scopes), &key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt,
+		&key.CreatedAt, &key.UpdatedAt)
+	return key, err
+}
+
+// affected returns the error of the statement, or notFound when it affected no row
+func affected(result sql.Result, err error, notFound error) error {
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return notFound
+	}
+	return nil
+}
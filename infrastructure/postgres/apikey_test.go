@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// apiKeyRows returns the rows of apiKeyColumns
+func apiKeyRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "user_id", "name", "prefix", "secret_hash", "scopes", "expires_at", "last_used_at", "revoked_at", "created_at", "updated_at"})
+}
+
+// TestNewAPIKeyRepository_Ok checks that NewAPIKeyRepository creates a new apiKeyRepository struct
+func TestNewAPIKeyRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewAPIKeyRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateAPIKey_Ok checks that Create inserts the API key and returns its ID
+func TestCreateAPIKey_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &apiKeyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	key := entities.APIKey{UserID: "test-user", Name: "ci", Prefix: "hak_abcd", SecretHash: "test-hash", CreatedAt: now, UpdatedAt: now}
+	mock.ExpectQuery(`INSERT INTO api_keys .* RETURNING id`).
+		WithArgs("test-user", "ci", "hak_abcd", "test-hash", pq.Array([]string{}), nil, nil, nil, now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("test-id"))
+
+	// Act
+	ID, err := repo.Create(context.Background(), key)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", ID)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestGetAPIKeyBySecretHash_Ok checks that GetBySecretHash returns the API key whose secret has the hash
+func TestGetAPIKeyBySecretHash_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &apiKeyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectQuery(`SELECT id, .* FROM api_keys WHERE secret_hash = \$1`).
+		WithArgs("test-hash").
+		WillReturnRows(apiKeyRows().AddRow("test-id", "test-user", "ci", "hak_abcd", "test-hash", "{admin}", nil, now, nil, now, now))
+
+	// Act
+	key, err := repo.GetBySecretHash(context.Background(), "test-hash")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", key.ID)
+	assert.Equal(t, []string{"admin"}, key.Scopes)
+	assert.Nil(t, key.ExpiresAt)
+	assert.NotNil(t, key.LastUsedAt)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestGetAPIKeyBySecretHash_NotFound checks that GetBySecretHash returns a non existent error when no API key has the hash
+func TestGetAPIKeyBySecretHash_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &apiKeyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery(`SELECT id, .* FROM api_keys WHERE secret_hash = \$1`).
+		WithArgs("test-hash").
+		WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.GetBySecretHash(context.Background(), "test-hash")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestGetAPIKeys_Empty checks that Get returns a non existent error when there are no API keys
+func TestGetAPIKeys_Empty(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &apiKeyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery(`SELECT id, .* FROM api_keys ORDER BY created_at DESC`).
+		WillReturnRows(apiKeyRows())
+
+	// Act
+	_, err := repo.Get(context.Background(), map[string]interface{}{}, nil, nil)
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestRevokeAPIKey_Revoked checks that Revoke returns a non existent error when the API key is not found or revoked already
+func TestRevokeAPIKey_Revoked(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &apiKeyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectExec(`UPDATE api_keys SET revoked_at = \$1, updated_at = \$1 WHERE id = \$2 AND revoked_at IS NULL`).
+		WithArgs(now, "test-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Revoke(context.Background(), "test-id", now)
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestRevokeAPIKeysByUser_Ok checks that RevokeByUser revokes the API keys of the user not revoked yet
+func TestRevokeAPIKeysByUser_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &apiKeyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectExec(`UPDATE api_keys SET revoked_at = \$1, updated_at = \$1 WHERE user_id = \$2 AND revoked_at IS NULL`).
+		WithArgs(now, "test-user").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Act
+	err := repo.RevokeByUser(context.Background(), "test-user", now)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
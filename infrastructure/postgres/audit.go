@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// auditRepository adapter of an audit repository for postgres.
+// Events are ordered by their serial ID, and the ones older than the retention are purged on every write.
+// The table rejects any update other than the anonymization, so events cannot be altered once written.
+type auditRepository struct {
+	infrastructure.PostgresRepository
+	retention time.Duration
+}
+
+// NewAuditRepository creates an audit repository for postgres, keeping the events for the given retention, or forever if zero
+func NewAuditRepository(db *sql.DB, retention time.Duration) ports.AuditRepository {
+	return &auditRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		retention: retention,
+	}
+}
+
+func (r *auditRepository) Write(ctx context.Context, event entities.AuditEvent) error {
+	details, err := json.Marshal(event.Details)
+	if err != nil {
+		return err
+	}
+
+	if r.retention <= 0 {
+		q := `INSERT INTO audit_events (type, outcome, user_id, actor_id, ip, details, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7);`
+		_, err = r.DB.ExecContext(ctx, q, event.Type, event.Outcome, event.UserID, event.ActorID, event.IP, details, event.CreatedAt)
+		return err
+	}
+
+	q := `
+	WITH expired AS (
+	    DELETE FROM audit_events WHERE created_at < $8
+	)
+	INSERT INTO audit_events (type, outcome, user_id, actor_id, ip, details, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+	_, err = r.DB.ExecContext(ctx, q, event.Type, event.Outcome, event.UserID, event.ActorID, event.IP, details, event.CreatedAt, event.CreatedAt.Add(-r.retention))
+	return err
+}
+
+func (r *auditRepository) GetByID(ctx context.Context, ID string) (entities.AuditEvent, error) {
+	// the IDs are serial, so the ones not being integers cannot match any event
+	if _, err := strconv.ParseInt(ID, 10, 64); err != nil {
+		return entities.AuditEvent{}, wrappers.NewNonExistentErr(err)
+	}
+
+	q := `SELECT id, type, outcome, user_id, actor_id, ip, details, created_at FROM audit_events WHERE id = $1;`
+	rows, err := r.DB.QueryContext(ctx, q, ID)
+	if err != nil {
+		return entities.AuditEvent{}, err
+	}
+	defer rows.Close()
+
+	events, err := scanAuditEvents(rows)
+	if err != nil {
+		return entities.AuditEvent{}, err
+	}
+	if len(events) == 0 {
+		return entities.AuditEvent{}, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return events[0], nil
+}
+
+func (r *auditRepository) Get(ctx context.Context, filter ports.AuditFilter, skip, take *int) ([]entities.AuditEvent, error) {
+	where, args := auditWhereClause(filter)
+	q := fmt.Sprintf(`SELECT id, type, outcome, user_id, actor_id, ip, details, created_at FROM audit_events %s ORDER BY id DESC`, where)
+	if skip != nil {
+		q = fmt.Sprintf("%s OFFSET %d", q, *skip)
+	}
+	if take != nil {
+		q = fmt.Sprintf("%s LIMIT %d", q, *take)
+	}
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAuditEvents(rows)
+}
+
+func (r *auditRepository) Count(ctx context.Context, filter ports.AuditFilter) (int64, error) {
+	where, args := auditWhereClause(filter)
+	q := fmt.Sprintf(`SELECT COUNT(*) FROM audit_events %s;`, where)
+
+	var count int64
+	err := r.DB.QueryRowContext(ctx, q, args...).Scan(&count)
+	return count, err
+}
+
+// scanAuditEvents returns the events of the rows, the ones missing a user, actor or IP having them empty
+func scanAuditEvents(rows *sql.Rows) ([]entities.AuditEvent, error) {
+	events := []entities.AuditEvent{}
+	for rows.Next() {
+		var e entities.AuditEvent
+		var userID, actorID, ip sql.NullString
+		var details []byte
+		if err := rows.Scan(&e.ID, &e.Type, &e.Outcome, &userID, &actorID, &ip, &details, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.UserID = userID.String
+		e.ActorID = actorID.String
+		e.IP = ip.String
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &e.Details); err != nil {
+				return nil, err
+			}
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// auditWhereClause returns the where clause and arguments of the events matching the filter
+func auditWhereClause(filter ports.AuditFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Type != "" {
+		add("type = $%d", filter.Type)
+	}
+	if filter.Outcome != "" {
+		add("outcome = $%d", filter.Outcome)
+	}
+	if filter.UserID != "" {
+		add("user_id = $%d", filter.UserID)
+	}
+	if filter.ActorID != "" {
+		add("actor_id = $%d", filter.ActorID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at <= $%d", *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// Expire purges or anonymizes the events created before the given time, anonymized ones losing their user, actor, IP and details
+func (r *auditRepository) Expire(ctx context.Context, action entities.RetentionAction, before time.Time, dryRun bool) (int64, error) {
+	return expire(ctx, r.DB, "audit_events", "created_at < $1", "user_id = NULL, actor_id = NULL, ip = NULL, details = NULL", action, before, dryRun)
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewAuditRepository_Ok checks that NewAuditRepository creates a new auditRepository struct
+func TestNewAuditRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewAuditRepository(db, time.Hour)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestWriteAuditEvent_Ok checks that Write inserts the event without purging when there is no retention
+func TestWriteAuditEvent_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &auditRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	event := entities.AuditEvent{Type: entities.AuditLoginSucceeded, Outcome: entities.AuditOutcomeSuccess, UserID: "test-id", ActorID: "test-id", IP: "127.0.0.1", CreatedAt: time.Now().UTC()}
+	mock.ExpectExec(`^INSERT INTO audit_events`).WithArgs(event.Type, event.Outcome, event.UserID, event.ActorID, event.IP, []byte("null"), event.CreatedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Act
+	err := repo.Write(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestWriteAuditEvent_Retention checks that Write purges the events older than the retention
+func TestWriteAuditEvent_Retention(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &auditRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		retention: time.Hour,
+	}
+
+	event := entities.AuditEvent{Type: entities.AuditLoginSucceeded, Outcome: entities.AuditOutcomeSuccess, CreatedAt: time.Now().UTC()}
+	mock.ExpectExec(`DELETE FROM audit_events WHERE created_at < \$8`).
+		WithArgs(event.Type, event.Outcome, event.UserID, event.ActorID, event.IP, []byte("null"), event.CreatedAt, event.CreatedAt.Add(-time.Hour)).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Act
+	err := repo.Write(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestGetAuditEventByID_Ok checks that GetByID returns the event with the given ID
+func TestGetAuditEventByID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &auditRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	rows := sqlmock.NewRows([]string{"id", "type", "outcome", "user_id", "actor_id", "ip", "details", "created_at"}).
+		AddRow("7", entities.AuditUserDeleted, entities.AuditOutcomeSuccess, "test-id", "admin-id", nil, nil, now)
+	mock.ExpectQuery(`FROM audit_events WHERE id = \$1`).WithArgs("7").WillReturnRows(rows)
+
+	// Act
+	event, err := repo.GetByID(context.Background(), "7")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, entities.AuditEvent{ID: "7", Type: entities.AuditUserDeleted, Outcome: entities.AuditOutcomeSuccess, UserID: "test-id", ActorID: "admin-id", CreatedAt: now}, event)
+}
+
+// TestGetAuditEventByID_NotFound checks that GetByID returns a non existent error when no event has the given ID
+func TestGetAuditEventByID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &auditRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery(`FROM audit_events WHERE id = \$1`).WithArgs("7").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Act
+	_, err := repo.GetByID(context.Background(), "7")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+}
+
+// TestGetAuditEvents_Ok checks that Get returns the events matching the filter, newest first
+func TestGetAuditEvents_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &auditRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	from := now.Add(-time.Hour)
+	rows := sqlmock.NewRows([]string{"id", "type", "outcome", "user_id", "actor_id", "ip", "details", "created_at"}).
+		AddRow("2", entities.AuditLoginFailed, entities.AuditOutcomeFailure, nil, nil, "127.0.0.1", []byte(`{"email":"test@test.com"}`), now).
+		AddRow("1", entities.AuditLoginFailed, entities.AuditOutcomeFailure, "test-id", "test-id", nil, nil, now)
+	mock.ExpectQuery(`WHERE type = \$1 AND created_at >= \$2 ORDER BY id DESC OFFSET 0 LIMIT 10`).WithArgs(entities.AuditLoginFailed, from).WillReturnRows(rows)
+	skip, take := 0, 10
+
+	// Act
+	events, err := repo.Get(context.Background(), ports.AuditFilter{Type: entities.AuditLoginFailed, From: &from}, &skip, &take)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []entities.AuditEvent{
+		{ID: "2", Type: entities.AuditLoginFailed, Outcome: entities.AuditOutcomeFailure, IP: "127.0.0.1", Details: map[string]string{"email": "test@test.com"}, CreatedAt: now},
+		{ID: "1", Type: entities.AuditLoginFailed, Outcome: entities.AuditOutcomeFailure, UserID: "test-id", ActorID: "test-id", CreatedAt: now},
+	}, events)
+}
+
+// TestCountAuditEvents_Ok checks that Count returns the count of the events matching the filter
+func TestCountAuditEvents_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &auditRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	from := time.Now().UTC().Add(-time.Hour)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM audit_events WHERE type = \$1 AND created_at >= \$2`).WithArgs(entities.AuditLoginFailed, from).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	// Act
+	count, err := repo.Count(context.Background(), ports.AuditFilter{Type: entities.AuditLoginFailed, From: &from})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), count)
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+)
+
+// captureRepository adapter of a capture repository for postgres.
+// Captures are ordered by their serial ID, and the ones older than the TTL are purged on every write and never returned.
+type captureRepository struct {
+	infrastructure.PostgresRepository
+	ttl time.Duration
+}
+
+// NewCaptureRepository creates a capture repository for postgres, keeping the captures for the given TTL
+func NewCaptureRepository(db *sql.DB, ttl time.Duration) ports.CaptureRepository {
+	return &captureRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		ttl: ttl,
+	}
+}
+
+func (r *captureRepository) Write(ctx context.Context, capture entities.Capture) error {
+	q := `
+	WITH expired AS (
+	    DELETE FROM captures WHERE created_at < $10
+	)
+	INSERT INTO captures (request_id, actor_id, method, path, query, status, request_body, response_body, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+	`
+	_, err := r.DB.ExecContext(ctx, q, capture.RequestID, capture.ActorID, capture.Method, capture.Path, capture.Query, capture.Status,
+		capture.RequestBody, capture.ResponseBody, capture.CreatedAt, capture.CreatedAt.Add(-r.ttl))
+	return err
+}
+
+func (r *captureRepository) Get(ctx context.Context, skip, take *int) ([]entities.Capture, error) {
+	q := `SELECT id, request_id, actor_id, method, path, query, status, request_body, response_body, created_at FROM captures WHERE created_at >= $1 ORDER BY id DESC`
+	if skip != nil {
+		q = fmt.Sprintf("%s OFFSET %d", q, *skip)
+	}
+	if take != nil {
+		q = fmt.Sprintf("%s LIMIT %d", q, *take)
+	}
+
+	rows, err := r.DB.QueryContext(ctx, q, time.Now().UTC().Add(-r.ttl))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	captures := []entities.Capture{}
+	for rows.Next() {
+		var c entities.Capture
+		var actorID, query, requestBody, responseBody sql.NullString
+		if err := rows.Scan(&c.ID, &c.RequestID, &actorID, &c.Method, &c.Path, &query, &c.Status, &requestBody, &responseBody, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.ActorID = actorID.String
+		c.Query = query.String
+		c.RequestBody = requestBody.String
+		c.ResponseBody = responseBody.String
+		captures = append(captures, c)
+	}
+	return captures, rows.Err()
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCaptureRepository_Ok checks that NewCaptureRepository creates a new captureRepository struct
+func TestNewCaptureRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewCaptureRepository(db, time.Hour)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestWriteCapture_Ok checks that Write inserts the capture purging the ones older than the TTL
+func TestWriteCapture_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &captureRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		ttl: time.Hour,
+	}
+
+	capture := entities.Capture{RequestID: "test-request-id", Method: "POST", Path: "/v1/users", Status: 201, RequestBody: `{"name":"test"}`, CreatedAt: time.Now().UTC()}
+	mock.ExpectExec(`DELETE FROM captures WHERE created_at < \$10`).
+		WithArgs(capture.RequestID, capture.ActorID, capture.Method, capture.Path, capture.Query, capture.Status, capture.RequestBody, capture.ResponseBody, capture.CreatedAt, capture.CreatedAt.Add(-time.Hour)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Act
+	err := repo.Write(context.Background(), capture)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestGetCaptures_Ok checks that Get returns the captures not older than the TTL, newest first
+func TestGetCaptures_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &captureRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		ttl: time.Hour,
+	}
+
+	now := time.Now().UTC()
+	rows := sqlmock.NewRows([]string{"id", "request_id", "actor_id", "method", "path", "query", "status", "request_body", "response_body", "created_at"}).
+		AddRow("2", "test-request-id", nil, "GET", "/v1/users", "take=1", 200, nil, "[]", now).
+		AddRow("1", "test-request-id", "test-id", "POST", "/v1/users", nil, 201, `{"name":"test"}`, nil, now)
+	mock.ExpectQuery(`WHERE created_at >= \$1 ORDER BY id DESC OFFSET 0 LIMIT 10`).WithArgs(sqlmock.AnyArg()).WillReturnRows(rows)
+	skip, take := 0, 10
+
+	// Act
+	captures, err := repo.Get(context.Background(), &skip, &take)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []entities.Capture{
+		{ID: "2", RequestID: "test-request-id", Method: "GET", Path: "/v1/users", Query: "take=1", Status: 200, ResponseBody: "[]", CreatedAt: now},
+		{ID: "1", RequestID: "test-request-id", ActorID: "test-id", Method: "POST", Path: "/v1/users", Status: 201, RequestBody: `{"name":"test"}`, CreatedAt: now},
+	}, captures)
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// deviceRepository adapter of a device repository for postgres, whose devices are rows keyed by their ID
+type deviceRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewDeviceRepository creates a device repository for postgres
+func NewDeviceRepository(db *sql.DB) ports.DeviceRepository {
+	return &deviceRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *deviceRepository) Upsert(ctx context.Context, device entities.Device) error {
+	q := `
+	INSERT INTO devices (id, user_id, platform, token, name, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (id) DO UPDATE SET user_id = EXCLUDED.user_id, name = EXCLUDED.name, updated_at = EXCLUDED.updated_at;
+	`
+	_, err := r.DB.ExecContext(ctx, q, device.ID, device.UserID, device.Platform, device.Token, device.Name, device.CreatedAt, device.UpdatedAt)
+	return err
+}
+
+func (r *deviceRepository) GetByUser(ctx context.Context, userID string) ([]entities.Device, error) {
+	q := `
+	SELECT id, user_id, platform, token, name, created_at, updated_at FROM devices
+	WHERE user_id = $1
+	ORDER BY created_at DESC;
+	`
+	rows, err := r.DB.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := []entities.Device{}
+	for rows.Next() {
+		var device entities.Device
+		if err := rows.Scan(&device.ID, &device.UserID, &device.Platform, &device.Token, &device.Name, &device.CreatedAt, &device.UpdatedAt); err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, rows.Err()
+}
+
+func (r *deviceRepository) Delete(ctx context.Context, userID, ID string) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM devices WHERE id = $1 AND user_id = $2`, ID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("device ID %s of user ID %s not found", ID, userID))
+	}
+	return nil
+}
+
+func (r *deviceRepository) DeleteByUser(ctx context.Context, userID string) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM devices WHERE user_id = $1`, userID)
+	return err
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewDeviceRepository_Ok checks that NewDeviceRepository creates a new deviceRepository struct
+func TestNewDeviceRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewDeviceRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestUpsertDevice_Ok checks that Upsert inserts the device or updates the existing one with its ID
+func TestUpsertDevice_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &deviceRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	device := entities.Device{ID: "test-id", UserID: "test-user", Platform: entities.DevicePlatformAPNs, Token: "test-token", Name: "iPhone", CreatedAt: now, UpdatedAt: now}
+	mock.ExpectExec(`INSERT INTO devices .* ON CONFLICT \(id\) DO UPDATE`).
+		WithArgs(device.ID, device.UserID, device.Platform, device.Token, device.Name, device.CreatedAt, device.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Upsert(context.Background(), device)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestGetDevicesByUser_Ok checks that GetByUser returns the devices of the user
+func TestGetDevicesByUser_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &deviceRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	rows := sqlmock.NewRows([]string{"id", "user_id", "platform", "token", "name", "created_at", "updated_at"}).
+		AddRow("new-device", "test-user", "apns", "new-token", "iPhone", now, now).
+		AddRow("old-device", "test-user", "fcm", "old-token", "", now.Add(-time.Hour), now.Add(-time.Hour))
+	mock.ExpectQuery(`SELECT id, user_id, platform, token, name, created_at, updated_at FROM devices`).
+		WithArgs("test-user").
+		WillReturnRows(rows)
+
+	// Act
+	devices, err := repo.GetByUser(context.Background(), "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, devices, 2)
+	assert.Equal(t, "new-device", devices[0].ID)
+	assert.Equal(t, "old-token", devices[1].Token)
+}
+
+// TestDeleteDevice_NotFound checks that Delete returns a non existent error when the user has no device with the ID
+func TestDeleteDevice_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &deviceRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM devices WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("test-id", "test-user").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Delete(context.Background(), "test-user", "test-id")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestDeleteDevicesByUser_Ok checks that DeleteByUser deletes every device of the user
+func TestDeleteDevicesByUser_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &deviceRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM devices WHERE user_id = \$1`).
+		WithArgs("test-user").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	// Act
+	err := repo.DeleteByUser(context.Background(), "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// fileStorage adapter of a file storage for postgres, backed by a bytea column
+type fileStorage struct {
+	infrastructure.PostgresRepository
+}
+
+// NewFileStorage creates a file storage for postgres
+func NewFileStorage(db *sql.DB) ports.FileStorage {
+	return &fileStorage{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (s *fileStorage) Store(ctx context.Context, file entities.File, content io.Reader) (entities.File, error) {
+	q := `
+	INSERT INTO files (key, name, content_type, size, metadata, content, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (key) DO UPDATE
+        SET name=EXCLUDED.name, content_type=EXCLUDED.content_type, size=EXCLUDED.size,
+        metadata=EXCLUDED.metadata, content=EXCLUDED.content, created_at=EXCLUDED.created_at;
+    `
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return entities.File{}, err
+	}
+
+	metadata, err := json.Marshal(file.Metadata)
+	if err != nil {
+		return entities.File{}, err
+	}
+
+	file.Size = int64(len(data))
+	file.CreatedAt = time.Now().UTC()
+	_, err = s.DB.ExecContext(ctx, q, file.Key, file.Name, file.ContentType, file.Size, metadata, data, file.CreatedAt)
+	if err != nil {
+		return entities.File{}, err
+	}
+
+	return file, nil
+}
+
+func (s *fileStorage) Open(ctx context.Context, key string) (io.ReadCloser, entities.File, error) {
+	q := `
+	SELECT key, name, content_type, size, metadata, created_at, content
+        FROM files WHERE key = $1;
+    `
+
+	var file entities.File
+	var metadata, content []byte
+	row := s.DB.QueryRowContext(ctx, q, key)
+	if err := row.Scan(&file.Key, &file.Name, &file.ContentType, &file.Size, &metadata, &file.CreatedAt, &content); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, entities.File{}, err
+	}
+
+	if err := unmarshalMetadata(metadata, &file); err != nil {
+		return nil, entities.File{}, err
+	}
+
+	return io.NopCloser(bytes.NewReader(content)), file, nil
+}
+
+func (s *fileStorage) Stat(ctx context.Context, key string) (entities.File, error) {
+	q := `
+	SELECT key, name, content_type, size, metadata, created_at
+        FROM files WHERE key = $1;
+    `
+
+	var file entities.File
+	var metadata []byte
+	row := s.DB.QueryRowContext(ctx, q, key)
+	if err := row.Scan(&file.Key, &file.Name, &file.ContentType, &file.Size, &metadata, &file.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.File{}, err
+	}
+
+	err := unmarshalMetadata(metadata, &file)
+	return file, err
+}
+
+func (s *fileStorage) Delete(ctx context.Context, key string) error {
+	q := `DELETE FROM files WHERE key=$1;`
+
+	result, err := s.DB.ExecContext(ctx, q, key)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func unmarshalMetadata(metadata []byte, file *entities.File) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	return json.Unmarshal(metadata, &file.Metadata)
+}
@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewFileStorage_Ok checks that NewFileStorage creates a new fileStorage struct
+func TestNewFileStorage_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	storage := NewFileStorage(db)
+
+	// Assert
+	assert.NotEmpty(t, storage)
+}
+
+// TestStoreFile_Ok checks that Store returns the stored file with its size when everything goes as expected
+func TestStoreFile_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	storage := &fileStorage{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	content := "test-content"
+	file := entities.File{Key: "test-key", Name: "test.png", ContentType: "image/png"}
+	mock.ExpectExec("INSERT INTO files").WithArgs(file.Key, file.Name, file.ContentType, int64(len(content)), []byte("null"), []byte(content), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	result, err := storage.Store(context.Background(), file, strings.NewReader(content))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, file.Key, result.Key)
+	assert.Equal(t, int64(len(content)), result.Size)
+	assert.NotEmpty(t, result.CreatedAt)
+}
+
+// TestStoreFile_InsertError checks that Store returns an error when the insert statement fails
+func TestStoreFile_InsertError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	storage := &fileStorage{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "insert error"
+	mock.ExpectExec("INSERT INTO files").WillReturnError(fmt.Errorf(expectedError))
+
+	// Act
+	_, err := storage.Store(context.Background(), entities.File{}, strings.NewReader(""))
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestOpenFile_Ok checks that Open returns the content and the info of the file when everything goes as expected
+func TestOpenFile_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	storage := &fileStorage{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedFile := entities.File{Key: "test-key", Name: "test.png", ContentType: "image/png", Size: 12, Metadata: map[string]string{"user_id": "test-id"}, CreatedAt: time.Now().UTC()}
+	expectedContent := "test-content"
+	rows := sqlmock.NewRows([]string{"key", "name", "content_type", "size", "metadata", "created_at", "content"}).
+		AddRow(expectedFile.Key, expectedFile.Name, expectedFile.ContentType, expectedFile.Size, []byte(`{"user_id":"test-id"}`), expectedFile.CreatedAt, []byte(expectedContent))
+	mock.ExpectQuery("SELECT (.+) FROM files").WithArgs(expectedFile.Key).WillReturnRows(rows)
+
+	// Act
+	content, file, err := storage.Open(context.Background(), expectedFile.Key)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedFile, file)
+	data, _ := io.ReadAll(content)
+	assert.Equal(t, expectedContent, string(data))
+}
+
+// TestOpenFile_NotFound checks that Open returns a non existent error when the file does not exist
+func TestOpenFile_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	storage := &fileStorage{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM files").WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, _, err := storage.Open(context.Background(), "test-key")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestStatFile_Ok checks that Stat returns the info of the file when everything goes as expected
+func TestStatFile_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	storage := &fileStorage{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedFile := entities.File{Key: "test-key", Name: "test.png", ContentType: "image/png", Size: 12, CreatedAt: time.Now().UTC()}
+	rows := sqlmock.NewRows([]string{"key", "name", "content_type", "size", "metadata", "created_at"}).
+		AddRow(expectedFile.Key, expectedFile.Name, expectedFile.ContentType, expectedFile.Size, nil, expectedFile.CreatedAt)
+	mock.ExpectQuery("SELECT (.+) FROM files").WithArgs(expectedFile.Key).WillReturnRows(rows)
+
+	// Act
+	file, err := storage.Stat(context.Background(), expectedFile.Key)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedFile, file)
+}
+
+// TestDeleteFile_Ok checks that Delete does not return an error when everything goes as expected
+func TestDeleteFile_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	storage := &fileStorage{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM files").WithArgs("test-key").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := storage.Delete(context.Background(), "test-key")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeleteFile_NotFound checks that Delete returns a non existent error when the file does not exist
+func TestDeleteFile_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	storage := &fileStorage{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM files").WithArgs("test-key").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := storage.Delete(context.Background(), "test-key")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// NewHealthChecker creates a health checker pinging the database
+func NewHealthChecker(db *sql.DB) ports.HealthChecker {
+	return ports.HealthCheckerFunc(func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	})
+}
+
+// NewStorageHealthChecker creates a health checker reading the files table of the file storage
+func NewStorageHealthChecker(db *sql.DB) ports.HealthChecker {
+	return ports.HealthCheckerFunc(func(ctx context.Context) error {
+		var one int
+		err := db.QueryRowContext(ctx, `SELECT 1 FROM files LIMIT 1;`).Scan(&one)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	})
+}
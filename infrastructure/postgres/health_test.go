@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStorageHealthChecker_NoFiles checks that the storage health checker succeeds when there are no files
+func TestStorageHealthChecker_NoFiles(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT 1 FROM files LIMIT 1;`).WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+
+	// Act
+	err := NewStorageHealthChecker(db).Check(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestStorageHealthChecker_Error checks that the storage health checker fails when the files cannot be read
+func TestStorageHealthChecker_Error(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT 1 FROM files LIMIT 1;`).WillReturnError(errors.New("test-error"))
+
+	// Act
+	err := NewStorageHealthChecker(db).Check(context.Background())
+
+	// Assert
+	assert.EqualError(t, err, "test-error")
+}
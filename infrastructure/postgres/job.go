@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// jobColumns are the columns of the jobs table, in the order they are scanned
+const jobColumns = "id, type, status, processed, metadata, error, attempts, max_attempts, run_at, worker, locked_until, created_at, updated_at, finished_at"
+
+// jobRepository adapter of a job repository for postgres
+type jobRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewJobRepository creates a job repository for postgres
+func NewJobRepository(db *sql.DB) ports.JobRepository {
+	return &jobRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *jobRepository) Create(ctx context.Context, job entities.Job) (string, error) {
+	q := `
+	INSERT INTO jobs (type, status, processed, metadata, error, attempts, max_attempts, run_at, worker, locked_until, created_at, updated_at, finished_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        RETURNING id;
+    `
+
+	metadata, err := json.Marshal(job.Metadata)
+	if err != nil {
+		return "", err
+	}
+
+	row := r.DB.QueryRowContext(
+		ctx, q, job.Type, job.Status, job.Processed, metadata, job.Error, job.Attempts, job.MaxAttempts, job.RunAt, job.Worker, job.LockedUntil, job.CreatedAt, job.UpdatedAt, job.FinishedAt,
+	)
+
+	err = row.Scan(&job.ID)
+	if err != nil {
+		return "", err
+	}
+
+	return job.ID, nil
+}
+
+func (r *jobRepository) Update(ctx context.Context, job entities.Job) error {
+	q := `
+	UPDATE jobs set status=$1, processed=$2, metadata=$3, error=$4, attempts=$5, max_attempts=$6, run_at=$7, worker=$8, locked_until=$9, updated_at=$10, finished_at=$11
+	    WHERE id=$12;
+	`
+
+	metadata, err := json.Marshal(job.Metadata)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.DB.ExecContext(
+		ctx, q, job.Status, job.Processed, metadata, job.Error, job.Attempts, job.MaxAttempts, job.RunAt, job.Worker, job.LockedUntil, job.UpdatedAt, job.FinishedAt, job.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, ID string) (entities.Job, error) {
+	q := fmt.Sprintf(`SELECT %s FROM jobs WHERE id = $1;`, jobColumns)
+
+	job, err := scanJob(r.DB.QueryRowContext(ctx, q, ID))
+	if errors.Is(err, sql.ErrNoRows) {
+		err = wrappers.NewNonExistentErr(err)
+	}
+	return job, err
+}
+
+func (r *jobRepository) Get(ctx context.Context, filter ports.JobFilter, skip, take *int) ([]entities.Job, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+	}
+	var where string
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	q := fmt.Sprintf(`SELECT %s FROM jobs %s ORDER BY created_at DESC`, jobColumns, where)
+	if skip != nil {
+		q = fmt.Sprintf("%s OFFSET %d", q, *skip)
+	}
+	if take != nil {
+		q = fmt.Sprintf("%s LIMIT %d", q, *take)
+	}
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []entities.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *jobRepository) Claim(ctx context.Context, types []string, worker string, now, lockedUntil time.Time) (entities.Job, error) {
+	// the jobs locked by the claims of other workers are skipped instead of waited for
+	q := fmt.Sprintf(`
+	UPDATE jobs SET status = $2, worker = $3, locked_until = $5, attempts = attempts + 1, updated_at = $4
+	WHERE id = (
+	    SELECT id FROM jobs
+	    WHERE type = ANY($1) AND ((status IN ($6, $7) AND run_at <= $4) OR (status = $2 AND locked_until <= $4))
+	    ORDER BY run_at
+	    LIMIT 1
+	    FOR UPDATE SKIP LOCKED
+	)
+	RETURNING %s;
+	`, jobColumns)
+
+	job, err := scanJob(r.DB.QueryRowContext(ctx, q, pq.Array(types), entities.JobStatusRunning, worker, now, lockedUntil, entities.JobStatusQueued, entities.JobStatusFailed))
+	if errors.Is(err, sql.ErrNoRows) {
+		err = wrappers.NewNonExistentErr(err)
+	}
+	return job, err
+}
+
+func (r *jobRepository) Count(ctx context.Context, statuses []entities.JobStatus) ([]ports.JobCount, error) {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+
+	q := `SELECT type, status, COUNT(*) FROM jobs WHERE status = ANY($1) GROUP BY type, status;`
+	rows, err := r.DB.QueryContext(ctx, q, pq.Array(values))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []ports.JobCount{}
+	for rows.Next() {
+		var count ports.JobCount
+		if err := rows.Scan(&count.Type, &count.Status, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// Expire purges or anonymizes the jobs finished before the given time, anonymized ones losing their metadata and error
+func (r *jobRepository) Expire(ctx context.Context, action entities.RetentionAction, before time.Time, dryRun bool) (int64, error) {
+	return expire(ctx, r.DB, "jobs", "finished_at < $1", "metadata = NULL, error = ''", action, before, dryRun)
+}
+
+// rowScanner is either a single row or the current row of a result set
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanJob scans a job selected with the jobColumns
+func scanJob(row rowScanner) (entities.Job, error) {
+	var job entities.Job
+	var metadata []byte
+	err := row.Scan(&job.ID, &job.Type, &job.Status, &job.Processed, &metadata, &job.Error, &job.Attempts, &job.MaxAttempts, &job.RunAt, &job.Worker, &job.LockedUntil, &job.CreatedAt, &job.UpdatedAt, &job.FinishedAt)
+	if err != nil {
+		return entities.Job{}, err
+	}
+
+	if len(metadata) > 0 {
+		err = json.Unmarshal(metadata, &job.Metadata)
+	}
+	return job, err
+}
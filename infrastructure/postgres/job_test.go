@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// jobColumnNames are the columns of the jobs returned by the queries
+var jobColumnNames = []string{"id", "type", "status", "processed", "metadata", "error", "attempts", "max_attempts", "run_at", "worker", "locked_until", "created_at", "updated_at", "finished_at"}
+
+// TestNewJobRepository_Ok checks that NewJobRepository creates a new jobRepository struct
+func TestNewJobRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewJobRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateJob_Ok checks that Create returns the ID of the inserted job
+func TestCreateJob_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &jobRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectQuery("INSERT INTO jobs").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), entities.Job{Type: entities.JobTypeBackup, Status: entities.JobStatusRunning})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedID, id)
+}
+
+// TestUpdateJob_Ok checks that Update does not return an error when the job exists
+func TestUpdateJob_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &jobRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("UPDATE jobs").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Update(context.Background(), entities.Job{ID: "f8352727-231e-4de1-8257-c235a0af5c4a", Status: entities.JobStatusSucceeded})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdateJob_NotFound checks that Update returns a NonExistent error when the job does not exist
+func TestUpdateJob_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &jobRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("UPDATE jobs").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Update(context.Background(), entities.Job{ID: "f8352727-231e-4de1-8257-c235a0af5c4a"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestGetJobByID_Ok checks that GetByID returns the expected job
+func TestGetJobByID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &jobRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	ID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	now := time.Now().UTC()
+	mock.ExpectQuery("SELECT (.+) FROM jobs").WithArgs(ID).
+		WillReturnRows(sqlmock.NewRows(jobColumnNames).AddRow(ID, entities.JobTypeBackup, entities.JobStatusSucceeded, 10, []byte(`{"collection":"users"}`), "", 1, 3, now, "", nil, now, now, now))
+
+	// Act
+	job, err := repo.GetByID(context.Background(), ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, ID, job.ID)
+	assert.Equal(t, entities.JobStatusSucceeded, job.Status)
+	assert.Equal(t, int64(10), job.Processed)
+	assert.Equal(t, map[string]string{"collection": "users"}, job.Metadata)
+	assert.NotNil(t, job.FinishedAt)
+}
+
+// TestGetJobByID_NotFound checks that GetByID returns a NonExistent error when the job does not exist
+func TestGetJobByID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &jobRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM jobs").WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.GetByID(context.Background(), "f8352727-231e-4de1-8257-c235a0af5c4a")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestGetJobs_Ok checks that Get returns the jobs matching the filter, newest first
+func TestGetJobs_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &jobRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectQuery(`SELECT (.+) FROM jobs WHERE status = \$1 AND type = \$2 ORDER BY created_at DESC OFFSET 0 LIMIT 10`).WithArgs(entities.JobStatusDead, entities.JobTypeNotification).
+		WillReturnRows(sqlmock.NewRows(jobColumnNames).AddRow("f8352727-231e-4de1-8257-c235a0af5c4a", entities.JobTypeNotification, entities.JobStatusDead, 0, nil, "job-error", 5, 5, now, "", nil, now, now, now))
+	skip, take := 0, 10
+
+	// Act
+	jobs, err := repo.Get(context.Background(), ports.JobFilter{Status: entities.JobStatusDead, Type: entities.JobTypeNotification}, &skip, &take)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, "job-error", jobs[0].Error)
+	assert.Equal(t, 5, jobs[0].Attempts)
+}
+
+// TestClaimJob_Ok checks that Claim returns the due job locked for the worker, skipping the ones locked by other claims
+func TestClaimJob_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &jobRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	ID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	now := time.Now().UTC()
+	lockedUntil := now.Add(time.Minute)
+	mock.ExpectQuery(`UPDATE jobs SET (.+) FOR UPDATE SKIP LOCKED`).
+		WithArgs(pq.Array([]string{entities.JobTypeBackup}), entities.JobStatusRunning, "test-worker", now, lockedUntil, entities.JobStatusQueued, entities.JobStatusFailed).
+		WillReturnRows(sqlmock.NewRows(jobColumnNames).AddRow(ID, entities.JobTypeBackup, entities.JobStatusRunning, 0, nil, "", 1, 3, now, "test-worker", lockedUntil, now, now, nil))
+
+	// Act
+	job, err := repo.Claim(context.Background(), []string{entities.JobTypeBackup}, "test-worker", now, lockedUntil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, ID, job.ID)
+	assert.Equal(t, "test-worker", job.Worker)
+	assert.Equal(t, &lockedUntil, job.LockedUntil)
+}
+
+// TestCountJobs_Ok checks that Count returns the count of the jobs in the statuses by type and status
+func TestCountJobs_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &jobRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery(`SELECT type, status, COUNT\(\*\) FROM jobs WHERE status = ANY\(\$1\) GROUP BY type, status`).
+		WithArgs(pq.Array([]string{string(entities.JobStatusQueued), string(entities.JobStatusDead)})).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "status", "count"}).
+			AddRow(entities.JobTypeEmail, entities.JobStatusQueued, 5).
+			AddRow(entities.JobTypeAdminNotice, entities.JobStatusDead, 1))
+
+	// Act
+	counts, err := repo.Count(context.Background(), []entities.JobStatus{entities.JobStatusQueued, entities.JobStatusDead})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []ports.JobCount{
+		{Type: entities.JobTypeEmail, Status: entities.JobStatusQueued, Count: 5},
+		{Type: entities.JobTypeAdminNotice, Status: entities.JobStatusDead, Count: 1},
+	}, counts)
+}
+
+// TestClaimJob_NoJobDue checks that Claim returns a NonExistent error when no job is due
+func TestClaimJob_NoJobDue(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &jobRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("UPDATE jobs SET").WillReturnError(sql.ErrNoRows)
+	now := time.Now().UTC()
+
+	// Act
+	_, err := repo.Claim(context.Background(), []string{entities.JobTypeBackup}, "test-worker", now, now.Add(time.Minute))
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+)
+
+// leaseStore adapter of a lease store for postgres, whose leases are rows keyed by their name
+type leaseStore struct {
+	infrastructure.PostgresRepository
+}
+
+// NewLeaseStore creates a lease store for postgres
+func NewLeaseStore(db *sql.DB) ports.LeaseStore {
+	return &leaseStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (s *leaseStore) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	// the lease held by another holder and not expired is left as it is, so no row is affected
+	q := `
+	INSERT INTO leases (name, holder, expires_at) VALUES ($1, $2, $4)
+	ON CONFLICT (name) DO UPDATE SET holder = $2, expires_at = $4
+	WHERE leases.holder = $2 OR leases.expires_at <= $3;
+	`
+	result, err := s.DB.ExecContext(ctx, q, name, holder, now, now.Add(ttl))
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (s *leaseStore) Release(ctx context.Context, name, holder string) error {
+	_, err := s.DB.ExecContext(ctx, `DELETE FROM leases WHERE name = $1 AND holder = $2`, name, holder)
+	return err
+}
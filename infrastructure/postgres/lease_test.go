@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewLeaseStore_Ok checks that NewLeaseStore creates a new leaseStore struct
+func TestNewLeaseStore_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	store := NewLeaseStore(db)
+
+	// Assert
+	assert.NotEmpty(t, store)
+}
+
+// TestAcquireLease_Ok checks that Acquire reports the lease acquired when its row is upserted
+func TestAcquireLease_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &leaseStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`INSERT INTO leases`).
+		WithArgs("test-lease", "test-holder", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	acquired, err := store.Acquire(context.Background(), "test-lease", "test-holder", time.Minute)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, acquired)
+}
+
+// TestAcquireLease_HeldByAnother checks that Acquire reports the lease not acquired when no row is affected,
+// as the lease is held by another holder and not expired
+func TestAcquireLease_HeldByAnother(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &leaseStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`INSERT INTO leases`).
+		WithArgs("test-lease", "test-holder", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	acquired, err := store.Acquire(context.Background(), "test-lease", "test-holder", time.Minute)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, acquired)
+}
+
+// TestReleaseLease_Ok checks that Release deletes the lease held by the holder
+func TestReleaseLease_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &leaseStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM leases WHERE name = \$1 AND holder = \$2`).
+		WithArgs("test-lease", "test-holder").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := store.Release(context.Background(), "test-lease", "test-holder")
+
+	// Assert
+	assert.Nil(t, err)
+}
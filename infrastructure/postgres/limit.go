@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+)
+
+// limitStore adapter of a limit store for postgres, whose windows are counted with atomic upserts.
+// The ended windows of the other keys are purged on every hit.
+type limitStore struct {
+	infrastructure.PostgresRepository
+}
+
+// NewLimitStore creates a limit store for postgres
+func NewLimitStore(db *sql.DB) ports.LimitStore {
+	return &limitStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (s *limitStore) Hit(ctx context.Context, key string, window time.Duration) (entities.Limit, error) {
+	now := time.Now().UTC()
+	q := `
+	WITH ended AS (
+	    DELETE FROM limits WHERE expires_at <= $2 AND key <> $1
+	)
+	INSERT INTO limits (key, count, expires_at) VALUES ($1, 1, $3)
+	ON CONFLICT (key) DO UPDATE SET
+	    count = CASE WHEN limits.expires_at <= $2 THEN 1 ELSE limits.count + 1 END,
+	    expires_at = CASE WHEN limits.expires_at <= $2 THEN $3 ELSE limits.expires_at END
+	RETURNING key, count, expires_at;
+	`
+	limit := entities.Limit{}
+	err := s.DB.QueryRowContext(ctx, q, key, now, now.Add(window)).Scan(&limit.Key, &limit.Count, &limit.ExpiresAt)
+	return limit, err
+}
+
+func (s *limitStore) Get(ctx context.Context, key string) (entities.Limit, error) {
+	q := `SELECT key, count, expires_at FROM limits WHERE key = $1 AND expires_at > $2`
+	limit := entities.Limit{}
+	err := s.DB.QueryRowContext(ctx, q, key, time.Now().UTC()).Scan(&limit.Key, &limit.Count, &limit.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.Limit{Key: key}, nil
+	}
+	return limit, err
+}
+
+func (s *limitStore) Reset(ctx context.Context, key string) error {
+	_, err := s.DB.ExecContext(ctx, `DELETE FROM limits WHERE key = $1`, key)
+	return err
+}
+
+func (s *limitStore) Purge(ctx context.Context) (int64, error) {
+	result, err := s.DB.ExecContext(ctx, `DELETE FROM limits WHERE expires_at <= $1`, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
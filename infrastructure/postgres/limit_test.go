@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewLimitStore_Ok checks that NewLimitStore creates a new limitStore struct
+func TestNewLimitStore_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	store := NewLimitStore(db)
+
+	// Assert
+	assert.NotEmpty(t, store)
+}
+
+// TestHitLimit_Ok checks that Hit upserts the window of the key, purging the ended ones, and returns its hits
+func TestHitLimit_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &limitStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expected := entities.Limit{Key: "ip:10.0.0.1", Count: 2, ExpiresAt: time.Now().UTC().Add(time.Minute)}
+	mock.ExpectQuery(`DELETE FROM limits WHERE expires_at <= \$2 AND key <> \$1`).
+		WithArgs(expected.Key, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "count", "expires_at"}).AddRow(expected.Key, expected.Count, expected.ExpiresAt))
+
+	// Act
+	limit, err := store.Hit(context.Background(), expected.Key, time.Minute)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expected, limit)
+}
+
+// TestGetLimit_NotFound checks that Get returns no hits when the window of the key has ended or the key was never hit
+func TestGetLimit_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &limitStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery(`SELECT key, count, expires_at FROM limits WHERE key = \$1 AND expires_at > \$2`).
+		WithArgs("login:test@example.com", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "count", "expires_at"}))
+
+	// Act
+	limit, err := store.Get(context.Background(), "login:test@example.com")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, entities.Limit{Key: "login:test@example.com"}, limit)
+}
+
+// TestResetLimit_Ok checks that Reset deletes the window of the key
+func TestResetLimit_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &limitStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM limits WHERE key = \$1`).
+		WithArgs("login:test@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := store.Reset(context.Background(), "login:test@example.com")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestPurgeLimit_Ok checks that Purge deletes the ended windows and returns how many were deleted
+func TestPurgeLimit_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &limitStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM limits WHERE expires_at <= \$1`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Act
+	count, err := store.Purge(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
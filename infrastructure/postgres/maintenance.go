@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+)
+
+// maintenanceStore adapter of a maintenance store for postgres
+type maintenanceStore struct {
+	infrastructure.PostgresRepository
+}
+
+// NewMaintenanceStore creates a maintenance store for postgres
+func NewMaintenanceStore(db *sql.DB) ports.MaintenanceStore {
+	return &maintenanceStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// Collections returns the tables of the current schema, leaving out the versions of the migrations
+func (s *maintenanceStore) Collections(ctx context.Context) ([]string, error) {
+	q := `
+	SELECT tablename FROM pg_tables
+	WHERE schemaname = current_schema() AND tablename <> 'goose_db_version'
+	ORDER BY tablename;
+	`
+	rows, err := s.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// RebuildIndexes reindexes the table, locking its writes while its indexes are rebuilt
+func (s *maintenanceStore) RebuildIndexes(ctx context.Context, collection string) error {
+	_, err := s.DB.ExecContext(ctx, "REINDEX TABLE "+pq.QuoteIdentifier(collection))
+	return err
+}
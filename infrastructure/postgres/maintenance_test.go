@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewMaintenanceStore_Ok checks that NewMaintenanceStore creates a new maintenanceStore struct
+func TestNewMaintenanceStore_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	store := NewMaintenanceStore(db)
+
+	// Assert
+	assert.NotEmpty(t, store)
+}
+
+// TestCollections_Ok checks that Collections returns the names of the tables
+func TestCollections_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &maintenanceStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery(`SELECT tablename FROM pg_tables`).
+		WillReturnRows(sqlmock.NewRows([]string{"tablename"}).AddRow("jobs").AddRow("users"))
+
+	// Act
+	names, err := store.Collections(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"jobs", "users"}, names)
+}
+
+// TestRebuildIndexes_Ok checks that RebuildIndexes reindexes the table, quoting its name
+func TestRebuildIndexes_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &maintenanceStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`REINDEX TABLE "users"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := store.RebuildIndexes(context.Background(), "users")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
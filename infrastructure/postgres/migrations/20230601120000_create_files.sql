@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE public.files (
+    key text NOT NULL,
+    name text NOT NULL,
+    content_type text NOT NULL,
+    size bigint NOT NULL,
+    metadata jsonb,
+    content bytea NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    CONSTRAINT files_pkey PRIMARY KEY (key)
+);
+
+ALTER TABLE public.files OWNER TO postgres;
+
+-- +goose Down
+DROP TABLE public.files;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE public.users ADD COLUMN location jsonb;
+
+-- +goose Down
+ALTER TABLE public.users DROP COLUMN location;
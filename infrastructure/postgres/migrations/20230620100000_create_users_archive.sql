@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE public.users ADD COLUMN last_login_at timestamp;
+
+-- users_archive mirrors the users table, so new columns of users must be added to it as well
+CREATE TABLE public.users_archive (
+    LIKE public.users INCLUDING ALL,
+    archived_at timestamp NOT NULL
+);
+
+ALTER TABLE public.users_archive OWNER TO postgres;
+
+-- +goose Down
+DROP TABLE public.users_archive;
+
+ALTER TABLE public.users DROP COLUMN last_login_at;
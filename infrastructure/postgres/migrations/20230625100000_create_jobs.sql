@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE public.jobs (
+    id uuid DEFAULT uuid_generate_v4 () NOT NULL,
+    type text NOT NULL,
+    status text NOT NULL,
+    processed bigint NOT NULL,
+    metadata jsonb,
+    error text,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    finished_at timestamp without time zone,
+    CONSTRAINT jobs_pkey PRIMARY KEY (id)
+);
+
+ALTER TABLE public.jobs OWNER TO postgres;
+
+-- +goose Down
+DROP TABLE public.jobs;
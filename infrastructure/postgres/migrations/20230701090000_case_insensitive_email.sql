@@ -0,0 +1,11 @@
+-- +goose Up
+-- emails only differing in case belong to the same user, so uniqueness is checked on their lower case
+CREATE UNIQUE INDEX users_email_lower_key ON public.users (lower(email));
+
+ALTER TABLE public.users DROP CONSTRAINT email_unique;
+
+-- +goose Down
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT email_unique UNIQUE (email);
+
+DROP INDEX public.users_email_lower_key;
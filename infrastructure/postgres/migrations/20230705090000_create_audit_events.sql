@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE public.audit_events (
+    id bigserial NOT NULL,
+    type text NOT NULL,
+    user_id text,
+    details jsonb,
+    created_at timestamp without time zone NOT NULL,
+    CONSTRAINT audit_events_pkey PRIMARY KEY (id)
+);
+
+ALTER TABLE public.audit_events OWNER TO postgres;
+
+CREATE INDEX audit_events_created_at_idx ON public.audit_events (created_at);
+
+CREATE INDEX audit_events_user_id_type_idx ON public.audit_events (user_id, type);
+
+-- +goose Down
+DROP TABLE public.audit_events;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE public.audit_events ADD COLUMN anonymized_at timestamp;
+
+ALTER TABLE public.jobs ADD COLUMN anonymized_at timestamp;
+
+ALTER TABLE public.users_archive ADD COLUMN anonymized_at timestamp;
+
+-- +goose Down
+ALTER TABLE public.users_archive DROP COLUMN anonymized_at;
+
+ALTER TABLE public.jobs DROP COLUMN anonymized_at;
+
+ALTER TABLE public.audit_events DROP COLUMN anonymized_at;
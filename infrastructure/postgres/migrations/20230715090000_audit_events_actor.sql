@@ -0,0 +1,37 @@
+-- +goose Up
+ALTER TABLE public.audit_events ADD COLUMN outcome text NOT NULL DEFAULT 'success';
+
+ALTER TABLE public.audit_events ADD COLUMN actor_id text;
+
+ALTER TABLE public.audit_events ADD COLUMN ip text;
+
+CREATE INDEX audit_events_actor_id_idx ON public.audit_events (actor_id);
+
+-- +goose StatementBegin
+CREATE FUNCTION public.audit_events_immutable() RETURNS trigger AS $$
+BEGIN
+    IF OLD.anonymized_at IS NULL AND NEW.anonymized_at IS NOT NULL
+        AND NEW.id = OLD.id AND NEW.type = OLD.type AND NEW.outcome = OLD.outcome AND NEW.created_at = OLD.created_at
+        AND NEW.user_id IS NULL AND NEW.actor_id IS NULL AND NEW.ip IS NULL AND NEW.details IS NULL THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit events are immutable';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_events_immutable BEFORE UPDATE ON public.audit_events
+    FOR EACH ROW EXECUTE FUNCTION public.audit_events_immutable();
+
+-- +goose Down
+DROP TRIGGER audit_events_immutable ON public.audit_events;
+
+DROP FUNCTION public.audit_events_immutable();
+
+DROP INDEX public.audit_events_actor_id_idx;
+
+ALTER TABLE public.audit_events DROP COLUMN ip;
+
+ALTER TABLE public.audit_events DROP COLUMN actor_id;
+
+ALTER TABLE public.audit_events DROP COLUMN outcome;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE public.captures (
+    id bigserial NOT NULL,
+    request_id text NOT NULL,
+    actor_id text,
+    method text NOT NULL,
+    path text NOT NULL,
+    query text,
+    status integer NOT NULL,
+    request_body text,
+    response_body text,
+    created_at timestamp without time zone NOT NULL,
+    CONSTRAINT captures_pkey PRIMARY KEY (id)
+);
+
+ALTER TABLE public.captures OWNER TO postgres;
+
+CREATE INDEX captures_created_at_idx ON public.captures (created_at);
+
+-- +goose Down
+DROP TABLE public.captures;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE public.limits (
+    key text NOT NULL,
+    count bigint NOT NULL,
+    expires_at timestamp without time zone NOT NULL,
+    CONSTRAINT limits_pkey PRIMARY KEY (key)
+);
+
+ALTER TABLE public.limits OWNER TO postgres;
+
+CREATE INDEX limits_expires_at_idx ON public.limits (expires_at);
+
+-- +goose Down
+DROP TABLE public.limits;
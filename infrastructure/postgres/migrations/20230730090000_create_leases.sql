@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE public.leases (
+    name text NOT NULL,
+    holder text NOT NULL,
+    expires_at timestamp without time zone NOT NULL,
+    CONSTRAINT leases_pkey PRIMARY KEY (name)
+);
+
+ALTER TABLE public.leases OWNER TO postgres;
+
+-- +goose Down
+DROP TABLE public.leases;
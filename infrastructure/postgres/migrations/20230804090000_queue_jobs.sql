@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE public.jobs
+    ADD COLUMN attempts integer NOT NULL DEFAULT 0,
+    ADD COLUMN max_attempts integer NOT NULL DEFAULT 1,
+    ADD COLUMN run_at timestamp without time zone,
+    ADD COLUMN worker text NOT NULL DEFAULT '',
+    ADD COLUMN locked_until timestamp without time zone;
+
+-- the jobs run before the queue were run once, their failures being final
+UPDATE public.jobs SET run_at = created_at, attempts = 1;
+UPDATE public.jobs SET status = 'dead' WHERE status = 'failed';
+ALTER TABLE public.jobs ALTER COLUMN run_at SET NOT NULL;
+
+CREATE INDEX jobs_status_run_at ON public.jobs (status, run_at);
+
+-- +goose Down
+DROP INDEX public.jobs_status_run_at;
+
+UPDATE public.jobs SET status = 'failed' WHERE status = 'dead';
+
+ALTER TABLE public.jobs
+    DROP COLUMN attempts,
+    DROP COLUMN max_attempts,
+    DROP COLUMN run_at,
+    DROP COLUMN worker,
+    DROP COLUMN locked_until;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE public.signing_keys (
+    id text NOT NULL,
+    secret text NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    expires_at timestamp without time zone,
+    CONSTRAINT signing_keys_pkey PRIMARY KEY (id)
+);
+
+ALTER TABLE public.signing_keys OWNER TO postgres;
+
+-- +goose Down
+DROP TABLE public.signing_keys;
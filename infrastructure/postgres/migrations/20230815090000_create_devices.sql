@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE public.devices (
+    id text NOT NULL,
+    user_id text NOT NULL,
+    platform text NOT NULL,
+    token text NOT NULL,
+    name text NOT NULL DEFAULT '',
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    CONSTRAINT devices_pkey PRIMARY KEY (id)
+);
+
+ALTER TABLE public.devices OWNER TO postgres;
+
+CREATE INDEX devices_user_id_created_at_idx ON public.devices (user_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE public.devices;
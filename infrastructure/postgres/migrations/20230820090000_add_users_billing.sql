@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE public.users ADD COLUMN billing_customer_id text NOT NULL DEFAULT '';
+ALTER TABLE public.users ADD COLUMN subscription_status text NOT NULL DEFAULT '';
+ALTER TABLE public.users ADD COLUMN subscription_updated_at timestamp;
+
+CREATE INDEX users_billing_customer_id_idx ON public.users (billing_customer_id) WHERE billing_customer_id <> '';
+
+ALTER TABLE public.users_archive ADD COLUMN billing_customer_id text NOT NULL DEFAULT '';
+ALTER TABLE public.users_archive ADD COLUMN subscription_status text NOT NULL DEFAULT '';
+ALTER TABLE public.users_archive ADD COLUMN subscription_updated_at timestamp;
+
+-- +goose Down
+ALTER TABLE public.users_archive DROP COLUMN subscription_updated_at;
+ALTER TABLE public.users_archive DROP COLUMN subscription_status;
+ALTER TABLE public.users_archive DROP COLUMN billing_customer_id;
+
+DROP INDEX public.users_billing_customer_id_idx;
+
+ALTER TABLE public.users DROP COLUMN subscription_updated_at;
+ALTER TABLE public.users DROP COLUMN subscription_status;
+ALTER TABLE public.users DROP COLUMN billing_customer_id;
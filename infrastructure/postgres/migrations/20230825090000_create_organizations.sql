@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE public.organizations (
+    id uuid DEFAULT uuid_generate_v4 () NOT NULL,
+    name text NOT NULL,
+    owner_id text NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    CONSTRAINT organizations_pkey PRIMARY KEY (id)
+);
+
+ALTER TABLE public.organizations OWNER TO postgres;
+
+CREATE TABLE public.organization_members (
+    organization_id uuid NOT NULL,
+    user_id text NOT NULL,
+    role text NOT NULL,
+    joined_at timestamp without time zone NOT NULL,
+    CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT organization_members_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES public.organizations (id) ON DELETE CASCADE
+);
+
+ALTER TABLE public.organization_members OWNER TO postgres;
+
+CREATE INDEX organization_members_user_id_idx ON public.organization_members (user_id);
+
+-- +goose Down
+DROP TABLE public.organization_members;
+DROP TABLE public.organizations;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE public.roles (
+    id uuid DEFAULT uuid_generate_v4 () NOT NULL,
+    name text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    permissions text[] NOT NULL DEFAULT '{}',
+    user_ids text[] NOT NULL DEFAULT '{}',
+    organization_ids text[] NOT NULL DEFAULT '{}',
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    CONSTRAINT roles_pkey PRIMARY KEY (id),
+    CONSTRAINT roles_name_key UNIQUE (name)
+);
+
+ALTER TABLE public.roles OWNER TO postgres;
+
+CREATE INDEX roles_user_ids_idx ON public.roles USING gin (user_ids);
+CREATE INDEX roles_organization_ids_idx ON public.roles USING gin (organization_ids);
+
+-- +goose Down
+DROP TABLE public.roles;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE public.api_keys (
+    id uuid DEFAULT uuid_generate_v4 () NOT NULL,
+    user_id text NOT NULL,
+    name text NOT NULL,
+    prefix text NOT NULL,
+    secret_hash text NOT NULL,
+    scopes text[] NOT NULL DEFAULT '{}',
+    expires_at timestamp without time zone,
+    last_used_at timestamp without time zone,
+    revoked_at timestamp without time zone,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    CONSTRAINT api_keys_pkey PRIMARY KEY (id),
+    CONSTRAINT api_keys_secret_hash_key UNIQUE (secret_hash)
+);
+
+ALTER TABLE public.api_keys OWNER TO postgres;
+
+CREATE INDEX api_keys_user_id_created_at_idx ON public.api_keys (user_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE public.api_keys;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE public.notification_preferences (
+    user_id text NOT NULL,
+    categories jsonb NOT NULL DEFAULT '{}',
+    updated_at timestamp without time zone NOT NULL,
+    CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id)
+);
+
+ALTER TABLE public.notification_preferences OWNER TO postgres;
+
+-- +goose Down
+DROP TABLE public.notification_preferences;
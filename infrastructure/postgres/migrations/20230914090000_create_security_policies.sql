@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE public.security_policies (
+    tenant text NOT NULL,
+    password_min_length integer NOT NULL DEFAULT 0,
+    password_require_uppercase boolean NOT NULL DEFAULT false,
+    password_require_lowercase boolean NOT NULL DEFAULT false,
+    password_require_digit boolean NOT NULL DEFAULT false,
+    password_require_symbol boolean NOT NULL DEFAULT false,
+    session_lifetime bigint NOT NULL DEFAULT 0,
+    mfa_required boolean NOT NULL DEFAULT false,
+    lockout_max_failures bigint NOT NULL DEFAULT 0,
+    lockout_duration bigint NOT NULL DEFAULT 0,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    CONSTRAINT security_policies_pkey PRIMARY KEY (tenant)
+);
+
+ALTER TABLE public.security_policies OWNER TO postgres;
+
+-- +goose Down
+DROP TABLE public.security_policies;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE public.organization_invitations (
+    organization_id uuid NOT NULL,
+    user_id text NOT NULL,
+    role text NOT NULL,
+    invited_by text NOT NULL,
+    invited_at timestamp without time zone NOT NULL,
+    CONSTRAINT organization_invitations_pkey PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT organization_invitations_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES public.organizations (id) ON DELETE CASCADE
+);
+
+ALTER TABLE public.organization_invitations OWNER TO postgres;
+
+CREATE INDEX organization_invitations_user_id_idx ON public.organization_invitations (user_id);
+
+-- +goose Down
+DROP TABLE public.organization_invitations;
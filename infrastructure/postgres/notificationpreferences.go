@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// notificationPreferencesRepository adapter of a notification preferences repository for postgres, whose preferences are rows keyed by the ID of their user,
+// storing the channels of their categories as a jsonb object
+type notificationPreferencesRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewNotificationPreferencesRepository creates a notification preferences repository for postgres
+func NewNotificationPreferencesRepository(db *sql.DB) ports.NotificationPreferencesRepository {
+	return &notificationPreferencesRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *notificationPreferencesRepository) Get(ctx context.Context, userID string) (entities.NotificationPreferences, error) {
+	row := r.DB.QueryRowContext(ctx, `SELECT user_id, categories, updated_at FROM notification_preferences WHERE user_id = $1;`, userID)
+	preferences, err := scanNotificationPreferences(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("notification preferences of user ID %s not found", userID))
+	}
+	return preferences, err
+}
+
+// Update sets the channels in a single upsert, merging them into the ones stored, or into every channel enabled for the categories never set
+func (r *notificationPreferencesRepository) Update(ctx context.Context, userID string, channels map[string]map[string]bool, at time.Time) (entities.NotificationPreferences, error) {
+	q := `
+	INSERT INTO notification_preferences (user_id, categories, updated_at)
+	VALUES ($1, (SELECT coalesce(jsonb_object_agg(c.key, $3::jsonb || c.value), '{}') FROM jsonb_each($2::jsonb) AS c), $4)
+	ON CONFLICT (user_id) DO UPDATE SET categories = notification_preferences.categories || (
+		SELECT coalesce(jsonb_object_agg(c.key, $3::jsonb || coalesce(notification_preferences.categories -> c.key, '{}') || c.value), '{}')
+		FROM jsonb_each($2::jsonb) AS c
+	), updated_at = EXCLUDED.updated_at
+	RETURNING user_id, categories, updated_at;
+	`
+	changed, err := json.Marshal(channels)
+	if err != nil {
+		return entities.NotificationPreferences{}, err
+	}
+	defaults, err := json.Marshal(channelToggles(entities.AllNotificationChannels()))
+	if err != nil {
+		return entities.NotificationPreferences{}, err
+	}
+	return scanNotificationPreferences(r.DB.QueryRowContext(ctx, q, userID, string(changed), string(defaults), at))
+}
+
+func (r *notificationPreferencesRepository) Delete(ctx context.Context, userID string) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM notification_preferences WHERE user_id = $1`, userID)
+	return err
+}
+
+// scanNotificationPreferences scans a row of the user ID, the categories and the update time of the preferences
+func scanNotificationPreferences(row rowScanner) (entities.NotificationPreferences, error) {
+	var preferences entities.NotificationPreferences
+	var categories []byte
+	if err := row.Scan(&preferences.UserID, &categories, &preferences.UpdatedAt); err != nil {
+		return preferences, err
+	}
+
+	var toggles map[string]map[string]bool
+	if err := json.Unmarshal(categories, &toggles); err != nil {
+		return preferences, err
+	}
+	preferences.Categories = make(map[string]entities.NotificationChannels, len(toggles))
+	for category, channels := range toggles {
+		preferences.Categories[category] = entities.NotificationChannels{
+			Email: channels[entities.NotificationChannelEmail],
+			SMS:   channels[entities.NotificationChannelSMS],
+			Push:  channels[entities.NotificationChannelPush],
+		}
+	}
+	return preferences, nil
+}
+
+// channelToggles returns the toggles of the channels by their name, as stored in the categories
+func channelToggles(channels entities.NotificationChannels) map[string]bool {
+	return map[string]bool{
+		entities.NotificationChannelEmail: channels.Email,
+		entities.NotificationChannelSMS:   channels.SMS,
+		entities.NotificationChannelPush:  channels.Push,
+	}
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewNotificationPreferencesRepository_Ok checks that NewNotificationPreferencesRepository creates a new notificationPreferencesRepository struct
+func TestNewNotificationPreferencesRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewNotificationPreferencesRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestGetNotificationPreferences_NotFound checks that Get returns a non existent error when the user never set the preferences
+func TestGetNotificationPreferences_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &notificationPreferencesRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery(`SELECT user_id, categories, updated_at FROM notification_preferences WHERE user_id = \$1`).
+		WithArgs("test-user").
+		WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.Get(context.Background(), "test-user")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateNotificationPreferences_Ok checks that Update merges the channels given into the stored ones, or into the defaults, in a single upsert,
+// returning the preferences updated
+func TestUpdateNotificationPreferences_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &notificationPreferencesRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectQuery(`INSERT INTO notification_preferences .* ON CONFLICT \(user_id\) DO UPDATE .* RETURNING user_id, categories, updated_at`).
+		WithArgs("test-user", `{"security":{"push":false}}`, `{"email":true,"push":true,"sms":true}`, now).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "categories", "updated_at"}).
+			AddRow("test-user", []byte(`{"security":{"email":true,"sms":true,"push":false}}`), now))
+
+	// Act
+	preferences, err := repo.Update(context.Background(), "test-user", map[string]map[string]bool{entities.NotificationCategorySecurity: {entities.NotificationChannelPush: false}}, now)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, entities.NotificationChannels{Email: true, SMS: true}, preferences.Categories[entities.NotificationCategorySecurity])
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestDeleteNotificationPreferences_Ok checks that Delete deletes the preferences of the user
+func TestDeleteNotificationPreferences_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &notificationPreferencesRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM notification_preferences WHERE user_id = \$1`).
+		WithArgs("test-user").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Delete(context.Background(), "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
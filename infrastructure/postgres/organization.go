@@ -0,0 +1,316 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// organizationColumns contains all the columns of the organizations table, in select order
+const organizationColumns = "id, name, owner_id, created_at, updated_at"
+
+// organizationUpdatableColumns the columns of the organizations set by Update
+var organizationUpdatableColumns = map[string]bool{"name": true, "updated_at": true}
+
+// organizationRepository adapter of an organization repository for postgres, whose members and invitations are rows of
+// the organization_members and organization_invitations tables, deleted along with their organization
+type organizationRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewOrganizationRepository creates an organization repository for postgres
+func NewOrganizationRepository(db *sql.DB) ports.OrganizationRepository {
+	return &organizationRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// Create creates the organization along with its members, in a transaction
+func (r *organizationRepository) Create(ctx context.Context, organization interface{}) (string, error) {
+	o := organization.(entities.Organization)
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	q := `
+	INSERT INTO organizations (name, owner_id, created_at, updated_at)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id;
+	`
+	if err = tx.QueryRowContext(ctx, q, o.Name, o.OwnerID, o.CreatedAt, o.UpdatedAt).Scan(&o.ID); err != nil {
+		return "", err
+	}
+	for _, m := range o.Members {
+		q = `INSERT INTO organization_members (organization_id, user_id, role, joined_at) VALUES ($1, $2, $3, $4);`
+		if _, err = tx.ExecContext(ctx, q, o.ID, m.UserID, m.Role, m.JoinedAt); err != nil {
+			return "", err
+		}
+	}
+	return o.ID, tx.Commit()
+}
+
+func (r *organizationRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	where, args := whereClause(filter)
+	q := fmt.Sprintf(`SELECT %s FROM organizations %s ORDER BY name`, organizationColumns, where)
+	if skip != nil {
+		q = fmt.Sprintf("%s OFFSET %d", q, *skip)
+	}
+	if take != nil {
+		q = fmt.Sprintf("%s LIMIT %d", q, *take)
+	}
+	organizations, err := r.query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(organizations) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	result := make([]interface{}, 0, len(organizations))
+	for i := range organizations {
+		result = append(result, &organizations[i])
+	}
+	return result, nil
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	organizations, err := r.query(ctx, fmt.Sprintf(`SELECT %s FROM organizations WHERE id = $1;`, organizationColumns), ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(organizations) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return &organizations[0], nil
+}
+
+// Update sets the fields of the organization, its members being left as they are
+func (r *organizationRepository) Update(ctx context.Context, ID string, fields interface{}) error {
+	set, args, err := setClause(fields.(map[string]interface{}), organizationUpdatableColumns)
+	if err != nil {
+		return err
+	}
+	result, err := r.DB.ExecContext(ctx, fmt.Sprintf(`UPDATE organizations SET %s WHERE id = $%d`, set, len(args)+1), append(args, ID)...)
+	return affected(result, err, wrappers.NewNonExistentErr(sql.ErrNoRows))
+}
+
+func (r *organizationRepository) Delete(ctx context.Context, ID string) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM organizations WHERE id = $1`, ID)
+	return affected(result, err, wrappers.NewNonExistentErr(sql.ErrNoRows))
+}
+
+func (r *organizationRepository) GetByMember(ctx context.Context, userID string) ([]entities.Organization, error) {
+	q := fmt.Sprintf(`
+	SELECT %s FROM organizations
+	WHERE id IN (SELECT organization_id FROM organization_members WHERE user_id = $1)
+	ORDER BY name;
+	`, organizationColumns)
+	return r.query(ctx, q, userID)
+}
+
+func (r *organizationRepository) GetByInvitee(ctx context.Context, userID string) ([]entities.Organization, error) {
+	q := fmt.Sprintf(`
+	SELECT %s FROM organizations
+	WHERE id IN (SELECT organization_id FROM organization_invitations WHERE user_id = $1)
+	ORDER BY name;
+	`, organizationColumns)
+	return r.query(ctx, q, userID)
+}
+
+func (r *organizationRepository) Invite(ctx context.Context, ID string, invitation entities.OrganizationInvitation) error {
+	// the invitation is not inserted when the user is a member or invited already, so concurrent invitations invite it once
+	q := `
+	WITH invited AS (
+		INSERT INTO organization_invitations (organization_id, user_id, role, invited_by, invited_at)
+		SELECT id, $2, $3, $4, $5 FROM organizations
+		WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM organization_members WHERE organization_id = $1 AND user_id = $2)
+		ON CONFLICT DO NOTHING
+		RETURNING organization_id
+	)
+	UPDATE organizations SET updated_at = $6 WHERE id IN (SELECT organization_id FROM invited);
+	`
+	result, err := r.DB.ExecContext(ctx, q, ID, invitation.UserID, invitation.Role, invitation.InvitedBy, invitation.InvitedAt, time.Now().UTC())
+	return affected(result, err, wrappers.NewNonExistentErr(fmt.Errorf("organization ID %s not found or user ID %s a member or invited already", ID, invitation.UserID)))
+}
+
+func (r *organizationRepository) AcceptInvitation(ctx context.Context, ID, userID string, joinedAt time.Time) error {
+	// the invitation is moved to the members in a single statement, so a concurrent revocation either prevents it or finds it accepted
+	q := `
+	WITH accepted AS (
+		DELETE FROM organization_invitations WHERE organization_id = $1 AND user_id = $2
+		RETURNING organization_id, user_id, role
+	), joined AS (
+		INSERT INTO organization_members (organization_id, user_id, role, joined_at)
+		SELECT organization_id, user_id, role, $3 FROM accepted
+		ON CONFLICT DO NOTHING
+		RETURNING organization_id
+	)
+	UPDATE organizations SET updated_at = $3 WHERE id IN (SELECT organization_id FROM joined);
+	`
+	result, err := r.DB.ExecContext(ctx, q, ID, userID, joinedAt)
+	return affected(result, err, wrappers.NewNonExistentErr(fmt.Errorf("user ID %s not invited to organization ID %s", userID, ID)))
+}
+
+func (r *organizationRepository) RemoveInvitation(ctx context.Context, ID, userID string) error {
+	q := `
+	WITH removed AS (
+		DELETE FROM organization_invitations WHERE organization_id = $1 AND user_id = $2
+		RETURNING organization_id
+	)
+	UPDATE organizations SET updated_at = $3 WHERE id IN (SELECT organization_id FROM removed);
+	`
+	result, err := r.DB.ExecContext(ctx, q, ID, userID, time.Now().UTC())
+	return affected(result, err, wrappers.NewNonExistentErr(fmt.Errorf("user ID %s not invited to organization ID %s", userID, ID)))
+}
+
+func (r *organizationRepository) TransferOwnership(ctx context.Context, ID, ownerID, userID string) error {
+	q := `
+	WITH transferred AS (
+		UPDATE organizations SET owner_id = $3, updated_at = $4
+		WHERE id = $1 AND owner_id = $2 AND EXISTS (SELECT 1 FROM organization_members WHERE organization_id = $1 AND user_id = $3)
+		RETURNING id
+	)
+	UPDATE organization_members SET role = CASE WHEN user_id = $3 THEN $5 ELSE $6 END
+	WHERE organization_id IN (SELECT id FROM transferred) AND user_id IN ($2, $3);
+	`
+	result, err := r.DB.ExecContext(ctx, q, ID, ownerID, userID, time.Now().UTC(), entities.OrganizationRoleOwner, entities.OrganizationRoleAdmin)
+	return affected(result, err, wrappers.NewNonExistentErr(fmt.Errorf("organization ID %s not owned by user ID %s or user ID %s not a member", ID, ownerID, userID)))
+}
+
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, ID, userID, role string) error {
+	q := `
+	WITH updated AS (
+		UPDATE organization_members SET role = $3 WHERE organization_id = $1 AND user_id = $2
+		RETURNING organization_id
+	)
+	UPDATE organizations SET updated_at = $4 WHERE id IN (SELECT organization_id FROM updated);
+	`
+	result, err := r.DB.ExecContext(ctx, q, ID, userID, role, time.Now().UTC())
+	return affected(result, err, wrappers.NewNonExistentErr(fmt.Errorf("user ID %s not a member of organization ID %s", userID, ID)))
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, ID, userID string) error {
+	q := `
+	WITH removed AS (
+		DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2
+		RETURNING organization_id
+	)
+	UPDATE organizations SET updated_at = $3 WHERE id IN (SELECT organization_id FROM removed);
+	`
+	result, err := r.DB.ExecContext(ctx, q, ID, userID, time.Now().UTC())
+	return affected(result, err, wrappers.NewNonExistentErr(fmt.Errorf("user ID %s not a member of organization ID %s", userID, ID)))
+}
+
+func (r *organizationRepository) RemoveUser(ctx context.Context, userID string) error {
+	q := `
+	WITH removed AS (
+		DELETE FROM organization_members WHERE user_id = $1
+		RETURNING organization_id
+	), uninvited AS (
+		DELETE FROM organization_invitations WHERE user_id = $1
+		RETURNING organization_id
+	)
+	UPDATE organizations SET updated_at = $2
+	WHERE id IN (SELECT organization_id FROM removed UNION SELECT organization_id FROM uninvited);
+	`
+	_, err := r.DB.ExecContext(ctx, q, userID, time.Now().UTC())
+	return err
+}
+
+// query returns the organizations of the rows of organizationColumns selected by the query, along with their members and invitations
+func (r *organizationRepository) query(ctx context.Context, q string, args ...interface{}) ([]entities.Organization, error) {
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizations := []entities.Organization{}
+	var IDs []string
+	for rows.Next() {
+		var o entities.Organization
+		if err := rows.Scan(&o.ID, &o.Name, &o.OwnerID, &o.CreatedAt, &o.UpdatedAt); err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, o)
+		IDs = append(IDs, o.ID)
+	}
+	if err = rows.Err(); err != nil || len(organizations) < 1 {
+		return organizations, err
+	}
+
+	members, err := r.members(ctx, IDs)
+	if err != nil {
+		return nil, err
+	}
+	invitations, err := r.invitations(ctx, IDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range organizations {
+		organizations[i].Members = members[organizations[i].ID]
+		organizations[i].Invitations = invitations[organizations[i].ID]
+	}
+	return organizations, nil
+}
+
+// members returns the members of the organizations with the IDs, by organization, in the order they joined
+func (r *organizationRepository) members(ctx context.Context, IDs []string) (map[string][]entities.OrganizationMember, error) {
+	q := `
+	SELECT organization_id, user_id, role, joined_at FROM organization_members
+	WHERE organization_id = ANY($1::uuid[])
+	ORDER BY joined_at;
+	`
+	rows, err := r.DB.QueryContext(ctx, q, pq.Array(IDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make(map[string][]entities.OrganizationMember, len(IDs))
+	for rows.Next() {
+		var ID string
+		var m entities.OrganizationMember
+		if err := rows.Scan(&ID, &m.UserID, &m.Role, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		members[ID] = append(members[ID], m)
+	}
+	return members, rows.Err()
+}
+
+// invitations returns the pending invitations to the organizations with the IDs, by organization, in the order they were sent
+func (r *organizationRepository) invitations(ctx context.Context, IDs []string) (map[string][]entities.OrganizationInvitation, error) {
+	q := `
+	SELECT organization_id, user_id, role, invited_by, invited_at FROM organization_invitations
+	WHERE organization_id = ANY($1::uuid[])
+	ORDER BY invited_at;
+	`
+	rows, err := r.DB.QueryContext(ctx, q, pq.Array(IDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := make(map[string][]entities.OrganizationInvitation, len(IDs))
+	for rows.Next() {
+		var ID string
+		var i entities.OrganizationInvitation
+		if err := rows.Scan(&ID, &i.UserID, &i.Role, &i.InvitedBy, &i.InvitedAt); err != nil {
+			return nil, err
+		}
+		invitations[ID] = append(invitations[ID], i)
+	}
+	return invitations, rows.Err()
+}
@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewOrganizationRepository_Ok checks that NewOrganizationRepository creates a new organizationRepository struct
+func TestNewOrganizationRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewOrganizationRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateOrganization_Ok checks that Create inserts the organization and its members in a transaction
+func TestCreateOrganization_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &organizationRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	organization := entities.Organization{
+		Name:      "test-organization",
+		OwnerID:   "test-user",
+		Members:   []entities.OrganizationMember{{UserID: "test-user", Role: entities.OrganizationRoleOwner, JoinedAt: now}},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO organizations .* RETURNING id`).
+		WithArgs("test-organization", "test-user", now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("test-id"))
+	mock.ExpectExec(`INSERT INTO organization_members`).
+		WithArgs("test-id", "test-user", entities.OrganizationRoleOwner, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	ID, err := repo.Create(context.Background(), organization)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", ID)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestGetOrganizationByID_Ok checks that GetByID returns the organization along with its members and invitations
+func TestGetOrganizationByID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &organizationRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectQuery(`SELECT id, name, owner_id, created_at, updated_at FROM organizations WHERE id = \$1`).
+		WithArgs("test-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner_id", "created_at", "updated_at"}).AddRow("test-id", "test-organization", "test-user", now, now))
+	mock.ExpectQuery(`SELECT organization_id, user_id, role, joined_at FROM organization_members`).
+		WithArgs(pq.Array([]string{"test-id"})).
+		WillReturnRows(sqlmock.NewRows([]string{"organization_id", "user_id", "role", "joined_at"}).
+			AddRow("test-id", "test-user", entities.OrganizationRoleOwner, now).
+			AddRow("test-id", "other-user", entities.OrganizationRoleMember, now))
+	mock.ExpectQuery(`SELECT organization_id, user_id, role, invited_by, invited_at FROM organization_invitations`).
+		WithArgs(pq.Array([]string{"test-id"})).
+		WillReturnRows(sqlmock.NewRows([]string{"organization_id", "user_id", "role", "invited_by", "invited_at"}).
+			AddRow("test-id", "invited-user", entities.OrganizationRoleAdmin, "test-user", now))
+
+	// Act
+	result, err := repo.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	organization := result.(*entities.Organization)
+	assert.Equal(t, "test-organization", organization.Name)
+	assert.Len(t, organization.Members, 2)
+	assert.Equal(t, []entities.OrganizationInvitation{{UserID: "invited-user", Role: entities.OrganizationRoleAdmin, InvitedBy: "test-user", InvitedAt: now}}, organization.Invitations)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestGetOrganizationByID_NotFound checks that GetByID returns a non existent error when the organization is not found
+func TestGetOrganizationByID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &organizationRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery(`SELECT id, name, owner_id, created_at, updated_at FROM organizations WHERE id = \$1`).
+		WithArgs("test-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner_id", "created_at", "updated_at"}))
+
+	// Act
+	_, err := repo.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestInviteOrganizationMember_AlreadyMember checks that Invite returns a non existent error when the user is a member or invited already
+func TestInviteOrganizationMember_AlreadyMember(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &organizationRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectExec(`INSERT INTO organization_invitations .* NOT EXISTS \(SELECT 1 FROM organization_members .* ON CONFLICT DO NOTHING .* UPDATE organizations SET updated_at`).
+		WithArgs("test-id", "test-user", entities.OrganizationRoleMember, "test-owner", now, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Invite(context.Background(), "test-id", entities.OrganizationInvitation{UserID: "test-user", Role: entities.OrganizationRoleMember, InvitedBy: "test-owner", InvitedAt: now})
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestAcceptOrganizationInvitation_Ok checks that AcceptInvitation moves the invitation to the members in a single statement
+func TestAcceptOrganizationInvitation_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &organizationRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectExec(`DELETE FROM organization_invitations .* INSERT INTO organization_members .* FROM accepted .* UPDATE organizations SET updated_at`).
+		WithArgs("test-id", "test-user", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.AcceptInvitation(context.Background(), "test-id", "test-user", now)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestTransferOrganizationOwnership_NotOwner checks that TransferOwnership returns a non existent error when the organization
+// is not owned by the owner or the user is not a member
+func TestTransferOrganizationOwnership_NotOwner(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &organizationRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`UPDATE organizations SET owner_id = \$3.* UPDATE organization_members SET role`).
+		WithArgs("test-id", "test-owner", "test-user", sqlmock.AnyArg(), entities.OrganizationRoleOwner, entities.OrganizationRoleAdmin).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.TransferOwnership(context.Background(), "test-id", "test-owner", "test-user")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestRemoveOrganizationMember_Ok checks that RemoveMember deletes the member and updates its organization
+func TestRemoveOrganizationMember_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &organizationRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM organization_members WHERE organization_id = \$1 AND user_id = \$2 .* UPDATE organizations SET updated_at`).
+		WithArgs("test-id", "test-user", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.RemoveMember(context.Background(), "test-id", "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestRemoveOrganizationUser_Ok checks that RemoveUser deletes the user from every organization it is a member of or invited to
+func TestRemoveOrganizationUser_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &organizationRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM organization_members WHERE user_id = \$1 .* DELETE FROM organization_invitations WHERE user_id = \$1`).
+		WithArgs("test-user", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Act
+	err := repo.RemoveUser(context.Background(), "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
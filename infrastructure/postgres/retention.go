@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// expire purges or anonymizes, setting the given assignments, the rows of the table matching the condition on before, bound to $1, or only counts them in dry run.
+// Anonymized rows are marked with anonymized_at so they are not anonymized again.
+func expire(ctx context.Context, db *sql.DB, table, condition, anonymization string, action entities.RetentionAction, before time.Time, dryRun bool) (int64, error) {
+	where := condition
+	if action == entities.RetentionAnonymize {
+		where += " AND anonymized_at IS NULL"
+	}
+	if dryRun {
+		var count int64
+		err := db.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s;`, table, where), before).Scan(&count)
+		return count, err
+	}
+
+	var q string
+	switch action {
+	case entities.RetentionPurge:
+		q = fmt.Sprintf(`DELETE FROM %s WHERE %s;`, table, where)
+	case entities.RetentionAnonymize:
+		q = fmt.Sprintf(`UPDATE %s SET %s, anonymized_at = timezone('utc', now()) WHERE %s;`, table, anonymization, where)
+	default:
+		return 0, fmt.Errorf("retention action %q not valid", action)
+	}
+
+	result, err := db.ExecContext(ctx, q, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// userArchiveRepository adapter of the archived users repository for postgres, whose users only expire with the retention policies
+type userArchiveRepository struct {
+	db *sql.DB
+}
+
+// NewUserArchiveRepository creates an archived users repository for postgres
+func NewUserArchiveRepository(db *sql.DB) ports.RetentionRepository {
+	return &userArchiveRepository{
+		db: db,
+	}
+}
+
+// Expire purges or anonymizes the users archived before the given time.
+// Anonymized users keep their ID and dates, with an email derived from the ID so it stays unique, and can no longer be unarchived.
+func (r *userArchiveRepository) Expire(ctx context.Context, action entities.RetentionAction, before time.Time, dryRun bool) (int64, error) {
+	return expire(ctx, r.db, "users_archive", "archived_at < $1",
+		`name = '', surnames = '', email = 'anonymized-' || id, password_hash = '', location = NULL, last_login_at = NULL, billing_customer_id = ''`,
+		action, before, dryRun)
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExpire_Purge checks that expire deletes the rows matching the condition
+func TestExpire_Purge(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	before := time.Now()
+	mock.ExpectExec(`DELETE FROM jobs WHERE finished_at < \$1;`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	// Act
+	count, err := expire(context.Background(), db, "jobs", "finished_at < $1", "", entities.RetentionPurge, before, false)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+// TestExpire_Anonymize checks that expire updates with the anonymization the rows matching the condition that are not anonymized yet
+func TestExpire_Anonymize(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	before := time.Now()
+	mock.ExpectExec(`UPDATE audit_events SET details = NULL, anonymized_at = (.+) WHERE created_at < \$1 AND anonymized_at IS NULL;`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Act
+	count, err := expire(context.Background(), db, "audit_events", "created_at < $1", "details = NULL", entities.RetentionAnonymize, before, false)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+// TestExpire_DryRun checks that expire only counts the rows matching the condition in dry run
+func TestExpire_DryRun(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	before := time.Now()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM jobs WHERE finished_at < \$1;`).WithArgs(before).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	// Act
+	count, err := expire(context.Background(), db, "jobs", "finished_at < $1", "", entities.RetentionPurge, before, true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), count)
+}
+
+// TestExpire_InvalidAction checks that expire returns an error when the action is not valid
+func TestExpire_InvalidAction(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	_, err := expire(context.Background(), db, "jobs", "finished_at < $1", "", entities.RetentionAction("archive"), time.Now(), false)
+
+	// Assert
+	assert.Equal(t, `retention action "archive" not valid`, err.Error())
+}
+
+// TestExpire_Error checks that expire returns an error when the query fails
+func TestExpire_Error(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	expectedError := "delete error"
+	mock.ExpectExec("DELETE FROM jobs").WillReturnError(errors.New(expectedError))
+
+	// Act
+	_, err := expire(context.Background(), db, "jobs", "finished_at < $1", "", entities.RetentionPurge, time.Now(), false)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestExpireUserArchive_Anonymize checks that Expire anonymizes the users archived before the given time
+func TestExpireUserArchive_Anonymize(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	before := time.Now()
+	mock.ExpectExec(`UPDATE users_archive SET (.+) email = 'anonymized-' \|\| id(.+) WHERE archived_at < \$1 AND anonymized_at IS NULL;`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 1))
+	repo := NewUserArchiveRepository(db)
+
+	// Act
+	count, err := repo.Expire(context.Background(), entities.RetentionAnonymize, before, false)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestExpireAuditEvents_Purge checks that Expire deletes the events created before the given time
+func TestExpireAuditEvents_Purge(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	before := time.Now()
+	mock.ExpectExec(`DELETE FROM audit_events WHERE created_at < \$1;`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 5))
+	repo := &auditRepository{PostgresRepository: infrastructure.PostgresRepository{DB: db}}
+
+	// Act
+	count, err := repo.Expire(context.Background(), entities.RetentionPurge, before, false)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), count)
+}
+
+// TestExpireJobs_Purge checks that Expire deletes the jobs finished before the given time
+func TestExpireJobs_Purge(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	before := time.Now()
+	mock.ExpectExec(`DELETE FROM jobs WHERE finished_at < \$1;`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 1))
+	repo := &jobRepository{infrastructure.PostgresRepository{DB: db}}
+
+	// Act
+	count, err := repo.Expire(context.Background(), entities.RetentionPurge, before, false)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// roleColumns contains all the columns of the roles table, in select order
+const roleColumns = "id, name, description, permissions, user_ids, organization_ids, created_at, updated_at"
+
+// roleUpdatableColumns the columns of the roles set by Update
+var roleUpdatableColumns = map[string]bool{"name": true, "description": true, "permissions": true, "updated_at": true}
+
+// roleAssigneeColumns the array columns of the roles storing the IDs of their assignees, by kind
+var roleAssigneeColumns = map[string]string{
+	entities.RoleAssigneeUser:         "user_ids",
+	entities.RoleAssigneeOrganization: "organization_ids",
+}
+
+// roleRepository adapter of a role repository for postgres, whose assignees are stored in array columns of their role
+type roleRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewRoleRepository creates a role repository for postgres
+func NewRoleRepository(db *sql.DB) ports.RoleRepository {
+	return &roleRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// Create creates the role, failing with a conflict when its name is already in use
+func (r *roleRepository) Create(ctx context.Context, role interface{}) (string, error) {
+	q := `
+	INSERT INTO roles (name, description, permissions, user_ids, organization_ids, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id;
+	`
+	ro := role.(entities.Role)
+	err := r.DB.QueryRowContext(ctx, q, ro.Name, ro.Description, pq.Array(nonNil(ro.Permissions)), pq.Array(nonNil(ro.UserIDs)),
+		pq.Array(nonNil(ro.OrganizationIDs)), ro.CreatedAt, ro.UpdatedAt).Scan(&ro.ID)
+	return ro.ID, roleNameConflict(err)
+}
+
+func (r *roleRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	where, args := whereClause(filter)
+	q := fmt.Sprintf(`SELECT %s FROM roles %s ORDER BY name`, roleColumns, where)
+	if skip != nil {
+		q = fmt.Sprintf("%s OFFSET %d", q, *skip)
+	}
+	if take != nil {
+		q = fmt.Sprintf("%s LIMIT %d", q, *take)
+	}
+	roles, err := r.query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	result := make([]interface{}, 0, len(roles))
+	for i := range roles {
+		result = append(result, &roles[i])
+	}
+	return result, nil
+}
+
+func (r *roleRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	role, err := scanRole(r.DB.QueryRowContext(ctx, fmt.Sprintf(`SELECT %s FROM roles WHERE id = $1;`, roleColumns), ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// Update sets the fields of the role, failing with a conflict when its new name is already in use
+func (r *roleRepository) Update(ctx context.Context, ID string, fields interface{}) error {
+	set, args, err := setClause(fields.(map[string]interface{}), roleUpdatableColumns)
+	if err != nil {
+		return err
+	}
+	result, err := r.DB.ExecContext(ctx, fmt.Sprintf(`UPDATE roles SET %s WHERE id = $%d`, set, len(args)+1), append(args, ID)...)
+	return affected(result, roleNameConflict(err), wrappers.NewNonExistentErr(sql.ErrNoRows))
+}
+
+func (r *roleRepository) Delete(ctx context.Context, ID string) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM roles WHERE id = $1`, ID)
+	return affected(result, err, wrappers.NewNonExistentErr(sql.ErrNoRows))
+}
+
+func (r *roleRepository) GetByAssignees(ctx context.Context, userID string, organizationIDs []string) ([]entities.Role, error) {
+	q := fmt.Sprintf(`SELECT %s FROM roles WHERE $1 = ANY(user_ids) OR organization_ids && $2 ORDER BY name;`, roleColumns)
+	return r.query(ctx, q, userID, pq.Array(nonNil(organizationIDs)))
+}
+
+func (r *roleRepository) Assign(ctx context.Context, ID, kind, assigneeID string) error {
+	column, err := assigneeColumn(kind)
+	if err != nil {
+		return err
+	}
+
+	// the assignee is appended only when missing, so concurrent assignments add it once
+	q := fmt.Sprintf(`
+	UPDATE roles SET %[1]s = CASE WHEN $1 = ANY(%[1]s) THEN %[1]s ELSE array_append(%[1]s, $1) END, updated_at = $2
+	WHERE id = $3;
+	`, column)
+	result, err := r.DB.ExecContext(ctx, q, assigneeID, time.Now().UTC(), ID)
+	return affected(result, err, wrappers.NewNonExistentErr(fmt.Errorf("role ID %s not found", ID)))
+}
+
+func (r *roleRepository) Unassign(ctx context.Context, ID, kind, assigneeID string) error {
+	column, err := assigneeColumn(kind)
+	if err != nil {
+		return err
+	}
+
+	q := fmt.Sprintf(`UPDATE roles SET %[1]s = array_remove(%[1]s, $1), updated_at = $2 WHERE id = $3 AND $1 = ANY(%[1]s);`, column)
+	result, err := r.DB.ExecContext(ctx, q, assigneeID, time.Now().UTC(), ID)
+	return affected(result, err, wrappers.NewNonExistentErr(fmt.Errorf("role ID %s not assigned to %s ID %s", ID, kind, assigneeID)))
+}
+
+func (r *roleRepository) RemoveAssignee(ctx context.Context, kind, assigneeID string) error {
+	column, err := assigneeColumn(kind)
+	if err != nil {
+		return err
+	}
+
+	q := fmt.Sprintf(`UPDATE roles SET %[1]s = array_remove(%[1]s, $1), updated_at = $2 WHERE $1 = ANY(%[1]s);`, column)
+	_, err = r.DB.ExecContext(ctx, q, assigneeID, time.Now().UTC())
+	return err
+}
+
+// query returns the roles of the rows of roleColumns selected by the query
+func (r *roleRepository) query(ctx context.Context, q string, args ...interface{}) ([]entities.Role, error) {
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []entities.Role{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// scanRole scans a row of roleColumns
+func scanRole(row rowScanner) (entities.Role, error) {
+	var role entities.Role
+	err := row.Scan(&role.ID, &role.Name, &role.Description, pq.Array(&role.Permissions), pq.Array(&role.UserIDs), pq.Array(&role.OrganizationIDs),
+		&role.CreatedAt, &role.UpdatedAt)
+	return role, err
+}
+
+// roleNameConflict returns a conflict when the error is a violation of the unique name constraint, or the error as is otherwise
+func roleNameConflict(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return apierror.Conflict(errors.New("role name already in use"))
+	}
+	return err
+}
+
+// assigneeColumn returns the column of the roles storing the IDs of the assignees of the kind
+func assigneeColumn(kind string) (string, error) {
+	column, ok := roleAssigneeColumns[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown kind of assignee %s", kind)
+	}
+	return column, nil
+}
+
+// setClause returns the assignments of the fields, sorted by column, and their arguments, failing when any of them is not one of the columns.
+// The lists of strings are set as arrays.
+func setClause(fields map[string]interface{}, columns map[string]bool) (string, []interface{}, error) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if !columns[k] {
+			return "", nil, fmt.Errorf("column %s cannot be updated", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	assignments := make([]string, 0, len(keys))
+	args := make([]interface{}, 0, len(keys))
+	for i, k := range keys {
+		assignments = append(assignments, fmt.Sprintf("%s = $%d", pq.QuoteIdentifier(k), i+1))
+		if values, ok := fields[k].([]string); ok {
+			args = append(args, pq.Array(nonNil(values)))
+			continue
+		}
+		args = append(args, fields[k])
+	}
+	return strings.Join(assignments, ", "), args, nil
+}
+
+// nonNil returns the list, or an empty one when nil, as the array columns are not nullable
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewRoleRepository_Ok checks that NewRoleRepository creates a new roleRepository struct
+func TestNewRoleRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewRoleRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateRole_NameConflict checks that Create returns a conflict when the name of the role is already in use
+func TestCreateRole_NameConflict(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &roleRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	role := entities.Role{Name: "auditors", Permissions: []string{"audit"}, CreatedAt: now, UpdatedAt: now}
+	mock.ExpectQuery(`INSERT INTO roles .* RETURNING id`).
+		WithArgs("auditors", "", pq.Array([]string{"audit"}), pq.Array([]string{}), pq.Array([]string{}), now, now).
+		WillReturnError(&pq.Error{Code: uniqueViolation})
+
+	// Act
+	_, err := repo.Create(context.Background(), role)
+
+	// Assert
+	assert.ErrorIs(t, err, apierror.ErrConflict)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateRole_Ok checks that Update only sets the fields given, sorted by column
+func TestUpdateRole_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &roleRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectExec(`UPDATE roles SET "permissions" = \$1, "updated_at" = \$2 WHERE id = \$3`).
+		WithArgs(pq.Array([]string{"audit"}), now, "test-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Update(context.Background(), "test-id", map[string]interface{}{"permissions": []string{"audit"}, "updated_at": now})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateRole_UnknownColumn checks that Update returns an error when a field is not an updatable column
+func TestUpdateRole_UnknownColumn(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &roleRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	// Act
+	err := repo.Update(context.Background(), "test-id", map[string]interface{}{"user_ids": []string{"test-user"}})
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+// TestGetRolesByAssignees_Ok checks that GetByAssignees returns the roles assigned to the user or to any of the organizations
+func TestGetRolesByAssignees_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &roleRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "permissions", "user_ids", "organization_ids", "created_at", "updated_at"}).
+		AddRow("test-id", "auditors", "", "{audit}", "{}", "{test-organization}", now, now)
+	mock.ExpectQuery(`SELECT id, .* FROM roles WHERE \$1 = ANY\(user_ids\) OR organization_ids && \$2 ORDER BY name`).
+		WithArgs("test-user", pq.Array([]string{"test-organization"})).
+		WillReturnRows(rows)
+
+	// Act
+	roles, err := repo.GetByAssignees(context.Background(), "test-user", []string{"test-organization"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, roles, 1)
+	assert.Equal(t, []string{"audit"}, roles[0].Permissions)
+	assert.Equal(t, []string{"test-organization"}, roles[0].OrganizationIDs)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestAssignRole_NotFound checks that Assign returns a non existent error when the role is not found
+func TestAssignRole_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &roleRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`UPDATE roles SET user_ids = CASE WHEN \$1 = ANY\(user_ids\) THEN user_ids ELSE array_append\(user_ids, \$1\) END`).
+		WithArgs("test-user", sqlmock.AnyArg(), "test-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Assign(context.Background(), "test-id", entities.RoleAssigneeUser, "test-user")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUnassignRole_Ok checks that Unassign removes the assignee from the role
+func TestUnassignRole_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &roleRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`UPDATE roles SET organization_ids = array_remove\(organization_ids, \$1\), updated_at = \$2 WHERE id = \$3 AND \$1 = ANY\(organization_ids\)`).
+		WithArgs("test-organization", sqlmock.AnyArg(), "test-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Unassign(context.Background(), "test-id", entities.RoleAssigneeOrganization, "test-organization")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestRemoveRoleAssignee_UnknownKind checks that RemoveAssignee returns an error when the kind of assignee is not known
+func TestRemoveRoleAssignee_UnknownKind(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &roleRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	// Act
+	err := repo.RemoveAssignee(context.Background(), "groups", "test-group")
+
+	// Assert
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// securityPolicyColumns contains all the columns of the security_policies table, in select order
+const securityPolicyColumns = `tenant, password_min_length, password_require_uppercase, password_require_lowercase, password_require_digit, password_require_symbol,
+	session_lifetime, mfa_required, lockout_max_failures, lockout_duration, created_at, updated_at`
+
+// securityPolicyRepository adapter of a security policy repository for postgres, whose policies are rows keyed by their tenant
+type securityPolicyRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewSecurityPolicyRepository creates a security policy repository for postgres
+func NewSecurityPolicyRepository(db *sql.DB) ports.SecurityPolicyRepository {
+	return &securityPolicyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *securityPolicyRepository) GetAll(ctx context.Context) ([]entities.SecurityPolicy, error) {
+	rows, err := r.DB.QueryContext(ctx, fmt.Sprintf(`SELECT %s FROM security_policies ORDER BY tenant;`, securityPolicyColumns))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := []entities.SecurityPolicy{}
+	for rows.Next() {
+		policy, err := scanSecurityPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, rows.Err()
+}
+
+func (r *securityPolicyRepository) Get(ctx context.Context, tenant string) (entities.SecurityPolicy, error) {
+	row := r.DB.QueryRowContext(ctx, fmt.Sprintf(`SELECT %s FROM security_policies WHERE tenant = $1;`, securityPolicyColumns), tenant)
+	policy, err := scanSecurityPolicy(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("security policy of tenant %s not found", tenant))
+	}
+	return policy, err
+}
+
+func (r *securityPolicyRepository) Upsert(ctx context.Context, policy entities.SecurityPolicy) error {
+	q := fmt.Sprintf(`
+	INSERT INTO security_policies (%s)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	ON CONFLICT (tenant) DO UPDATE SET password_min_length = EXCLUDED.password_min_length, password_require_uppercase = EXCLUDED.password_require_uppercase,
+		password_require_lowercase = EXCLUDED.password_require_lowercase, password_require_digit = EXCLUDED.password_require_digit,
+		password_require_symbol = EXCLUDED.password_require_symbol, session_lifetime = EXCLUDED.session_lifetime, mfa_required = EXCLUDED.mfa_required,
+		lockout_max_failures = EXCLUDED.lockout_max_failures, lockout_duration = EXCLUDED.lockout_duration,
+		created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at;
+	`, securityPolicyColumns)
+	_, err := r.DB.ExecContext(ctx, q, policy.Tenant, policy.Password.MinLength, policy.Password.RequireUppercase, policy.Password.RequireLowercase,
+		policy.Password.RequireDigit, policy.Password.RequireSymbol, int64(policy.SessionLifetime), policy.MFARequired, policy.LockoutMaxFailures,
+		int64(policy.LockoutDuration), policy.CreatedAt, policy.UpdatedAt)
+	return err
+}
+
+func (r *securityPolicyRepository) Delete(ctx context.Context, tenant string) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM security_policies WHERE tenant = $1`, tenant)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("security policy of tenant %s not found", tenant))
+	}
+	return nil
+}
+
+// scanSecurityPolicy scans a row of securityPolicyColumns, the durations being stored in nanoseconds
+func scanSecurityPolicy(row rowScanner) (entities.SecurityPolicy, error) {
+	var policy entities.SecurityPolicy
+	var sessionLifetime, lockoutDuration int64
+	err := row.Scan(&policy.Tenant, &policy.Password.MinLength, &policy.Password.RequireUppercase, &policy.Password.RequireLowercase,
+		&policy.Password.RequireDigit, &policy.Password.RequireSymbol, &sessionLifetime, &policy.MFARequired, &policy.LockoutMaxFailures,
+		&lockoutDuration, &policy.CreatedAt, &policy.UpdatedAt)
+	policy.SessionLifetime = time.Duration(sessionLifetime)
+	policy.LockoutDuration = time.Duration(lockoutDuration)
+	return policy, err
+}
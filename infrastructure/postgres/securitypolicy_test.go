@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// securityPolicyRows returns the rows of securityPolicyColumns
+func securityPolicyRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"tenant", "password_min_length", "password_require_uppercase", "password_require_lowercase", "password_require_digit",
+		"password_require_symbol", "session_lifetime", "mfa_required", "lockout_max_failures", "lockout_duration", "created_at", "updated_at"})
+}
+
+// TestNewSecurityPolicyRepository_Ok checks that NewSecurityPolicyRepository creates a new securityPolicyRepository struct
+func TestNewSecurityPolicyRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewSecurityPolicyRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestGetSecurityPolicy_Ok checks that Get returns the policy of the tenant, with its durations
+func TestGetSecurityPolicy_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &securityPolicyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectQuery(`SELECT tenant, .* FROM security_policies WHERE tenant = \$1`).
+		WithArgs("example.com").
+		WillReturnRows(securityPolicyRows().AddRow("example.com", 12, true, false, true, false, int64(8*time.Hour), true, int64(5), int64(time.Minute), now, now))
+
+	// Act
+	policy, err := repo.Get(context.Background(), "example.com")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, entities.PasswordPolicy{MinLength: 12, RequireUppercase: true, RequireDigit: true}, policy.Password)
+	assert.Equal(t, 8*time.Hour, policy.SessionLifetime)
+	assert.Equal(t, time.Minute, policy.LockoutDuration)
+	assert.True(t, policy.MFARequired)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestGetSecurityPolicy_NotFound checks that Get returns a non existent error when the tenant has no policy
+func TestGetSecurityPolicy_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &securityPolicyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery(`SELECT tenant, .* FROM security_policies WHERE tenant = \$1`).
+		WithArgs("example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.Get(context.Background(), "example.com")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUpsertSecurityPolicy_Ok checks that Upsert inserts the policy or replaces the one of its tenant
+func TestUpsertSecurityPolicy_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &securityPolicyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	policy := entities.SecurityPolicy{Tenant: "example.com", Password: entities.PasswordPolicy{MinLength: 12}, SessionLifetime: time.Hour, CreatedAt: now, UpdatedAt: now}
+	mock.ExpectExec(`INSERT INTO security_policies .* ON CONFLICT \(tenant\) DO UPDATE`).
+		WithArgs("example.com", 12, false, false, false, false, int64(time.Hour), false, int64(0), int64(0), now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Upsert(context.Background(), policy)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestDeleteSecurityPolicy_NotFound checks that Delete returns a non existent error when the tenant has no policy
+func TestDeleteSecurityPolicy_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &securityPolicyRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM security_policies WHERE tenant = \$1`).
+		WithArgs("example.com").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Delete(context.Background(), "example.com")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+)
+
+// signingKeyStore adapter of a signing key store for postgres, whose keys are rows keyed by their ID
+type signingKeyStore struct {
+	infrastructure.PostgresRepository
+}
+
+// NewSigningKeyStore creates a signing key store for postgres
+func NewSigningKeyStore(db *sql.DB) ports.SigningKeyStore {
+	return &signingKeyStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (s *signingKeyStore) Get(ctx context.Context) ([]entities.SigningKey, error) {
+	q := `
+	SELECT id, secret, created_at, expires_at FROM signing_keys
+	WHERE expires_at IS NULL OR expires_at > $1
+	ORDER BY created_at DESC;
+	`
+	rows, err := s.DB.QueryContext(ctx, q, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []entities.SigningKey{}
+	for rows.Next() {
+		var key entities.SigningKey
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&key.ID, &key.Secret, &key.CreatedAt, &expiresAt); err != nil {
+			return nil, err
+		}
+		if expiresAt.Valid {
+			key.ExpiresAt = &expiresAt.Time
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (s *signingKeyStore) Rotate(ctx context.Context, key entities.SigningKey, overlap time.Duration) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO signing_keys (id, secret, created_at) VALUES ($1, $2, $3)`, key.ID, key.Secret, key.CreatedAt); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE signing_keys SET expires_at = $1 WHERE id <> $2 AND expires_at IS NULL`, key.CreatedAt.Add(overlap), key.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM signing_keys WHERE expires_at <= $1`, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *signingKeyStore) Purge(ctx context.Context) (int64, error) {
+	result, err := s.DB.ExecContext(ctx, `DELETE FROM signing_keys WHERE expires_at <= $1`, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewSigningKeyStore_Ok checks that NewSigningKeyStore creates a new signingKeyStore struct
+func TestNewSigningKeyStore_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	store := NewSigningKeyStore(db)
+
+	// Assert
+	assert.NotEmpty(t, store)
+}
+
+// TestGetSigningKeys_Ok checks that Get returns the keys not expired, with the expiry of the ones having it
+func TestGetSigningKeys_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &signingKeyStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(time.Hour)
+	rows := sqlmock.NewRows([]string{"id", "secret", "created_at", "expires_at"}).
+		AddRow("new-key", "bmV3", now, nil).
+		AddRow("old-key", "b2xk", now.Add(-time.Hour), expiresAt)
+	mock.ExpectQuery(`SELECT id, secret, created_at, expires_at FROM signing_keys`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(rows)
+
+	// Act
+	keys, err := store.Get(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, keys, 2)
+	assert.Nil(t, keys[0].ExpiresAt)
+	assert.Equal(t, expiresAt, *keys[1].ExpiresAt)
+}
+
+// TestRotateSigningKeys_Ok checks that Rotate inserts the new key, sets the expiry of the previous ones and deletes the expired ones in a transaction
+func TestRotateSigningKeys_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &signingKeyStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	key := entities.SigningKey{ID: "new-key", Secret: "bmV3", CreatedAt: time.Now().UTC()}
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO signing_keys`).
+		WithArgs(key.ID, key.Secret, key.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE signing_keys SET expires_at`).
+		WithArgs(key.CreatedAt.Add(time.Hour), key.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM signing_keys`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Act
+	err := store.Rotate(context.Background(), key, time.Hour)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestRotateSigningKeys_InsertError checks that Rotate rolls back the transaction when the new key cannot be inserted
+func TestRotateSigningKeys_InsertError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &signingKeyStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	key := entities.SigningKey{ID: "new-key", Secret: "bmV3", CreatedAt: time.Now().UTC()}
+	expectedError := "insert error"
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO signing_keys`).
+		WithArgs(key.ID, key.Secret, key.CreatedAt).
+		WillReturnError(fmt.Errorf(expectedError))
+	mock.ExpectRollback()
+
+	// Act
+	err := store.Rotate(context.Background(), key, time.Hour)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestPurgeSigningKeys_Ok checks that Purge deletes the expired keys and returns how many were deleted
+func TestPurgeSigningKeys_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	store := &signingKeyStore{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM signing_keys`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Act
+	count, err := store.Purge(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+// VerifyMigrations ensures that the database is at the version of the last migration found in the given directory,
+// so that the API is not reported ready with a partially migrated schema. It must run once the migrations have been applied.
+func VerifyMigrations(db *sql.DB, migrationsDir string) error {
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		return err
+	}
+	last, err := migrations.Last()
+	if err != nil {
+		return err
+	}
+
+	version, err := goose.GetDBVersion(db)
+	if err != nil {
+		return err
+	}
+	if version != last.Version {
+		return fmt.Errorf("database at migration version %d, expected %d", version, last.Version)
+	}
+	return nil
+}
+
+// MigrationVersion returns the version of the last migration applied to the database, 0 when none was
+func MigrationVersion(db *sql.DB) (int64, error) {
+	return goose.GetDBVersion(db)
+}
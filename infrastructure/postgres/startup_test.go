@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pressly/goose/v3"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const migrationsDir = "migrations"
+
+func lastMigrationVersion(t *testing.T) int64 {
+	t.Helper()
+
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := migrations.Last()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return last.Version
+}
+
+// TestVerifyMigrations_Ok checks that VerifyMigrations does not return an error when the database is at the last migration version
+func TestVerifyMigrations_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"version_id", "is_applied"}).AddRow(lastMigrationVersion(t), true)
+	mock.ExpectQuery("SELECT version_id, is_applied").WillReturnRows(rows)
+
+	// Act
+	err := VerifyMigrations(db, migrationsDir)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestVerifyMigrations_Pending checks that VerifyMigrations returns an error when the database is not at the last migration version
+func TestVerifyMigrations_Pending(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"version_id", "is_applied"}).AddRow(1, true)
+	mock.ExpectQuery("SELECT version_id, is_applied").WillReturnRows(rows)
+
+	// Act
+	err := VerifyMigrations(db, migrationsDir)
+
+	// Assert
+	assert.ErrorContains(t, err, "database at migration version 1")
+}
+
+// TestVerifyMigrations_NotValidDirectory checks that VerifyMigrations returns an error when the given directory does not exist
+func TestVerifyMigrations_NotValidDirectory(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	err := VerifyMigrations(db, "invalid-directory")
+
+	// Assert
+	assert.NotNil(t, err)
+}
@@ -3,10 +3,16 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
 	"github.com/sergicanet9/go-hexagonal-api/core/entities"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
@@ -18,6 +24,16 @@ type userRepository struct {
 	infrastructure.PostgresRepository
 }
 
+// txKey is the context key of the ongoing transaction
+type txKey struct{}
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 // NewUserRepository creates a user repository for postgres
 func NewUserRepository(db *sql.DB) ports.UserRepository {
 	return &userRepository{
@@ -29,34 +45,48 @@ func NewUserRepository(db *sql.DB) ports.UserRepository {
 
 func (r *userRepository) Create(ctx context.Context, user interface{}) (string, error) {
 	q := `
-	INSERT INTO users (name, surnames, email, password_hash, claims, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+	INSERT INTO users (name, surnames, email, password_hash, claims, location, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
         RETURNING id;
     `
 
 	u := user.(entities.User)
-	row := r.DB.QueryRowContext(
-		ctx, q, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.Claims), u.CreatedAt, u.UpdatedAt,
+	row := r.querier(ctx).QueryRowContext(
+		ctx, q, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.Claims), jsonLocation{&u.Location}, u.CreatedAt, u.UpdatedAt,
 	)
 
 	err := row.Scan(&u.ID)
 	if err != nil {
-		return "", err
+		return "", emailConflict(err, u.Email)
 	}
 
 	return u.ID, nil
 }
 
-func (r *userRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
-	var where string
-	for k, v := range filter {
-		if where == "" {
-			where = "WHERE"
-		} else {
-			where = fmt.Sprintf("%s AND", where)
-		}
-		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+// uniqueViolation is the code of the errors of the unique constraints
+const uniqueViolation = "23505"
+
+// emailConflict returns a conflict when the error is a violation of the unique email index, the only unique one of the users besides their ID,
+// or the error as is otherwise
+func emailConflict(err error, email string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return apierror.Conflict(fmt.Errorf("email %s already in use", email))
 	}
+	return err
+}
+
+func (r *userRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	return r.GetProjected(ctx, filter, nil, skip, take)
+}
+
+func (r *userRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	return r.GetByIDProjected(ctx, ID, nil)
+}
+
+// GetProjected gets the users matching the filter, selecting only the columns allowed by the projection
+func (r *userRepository) GetProjected(ctx context.Context, filter map[string]interface{}, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	where, args := whereClause(filter)
 	if skip != nil {
 		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
 	}
@@ -64,12 +94,13 @@ func (r *userRepository) Get(ctx context.Context, filter map[string]interface{},
 		where = fmt.Sprintf("%s LIMIT %d", where, *take)
 	}
 
+	columns := projectColumns(projection)
 	q := fmt.Sprintf(`
-	SELECT id, name, surnames, email, password_hash, claims, created_at, updated_at
+	SELECT %s
 	    FROM users %s;
-	`, where)
+	`, strings.Join(columns, ", "), where)
 
-	rows, err := r.DB.QueryContext(ctx, q)
+	rows, err := r.querier(ctx).QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +110,7 @@ func (r *userRepository) Get(ctx context.Context, filter map[string]interface{},
 	var users []interface{}
 	for rows.Next() {
 		var u entities.User
-		err = rows.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.PasswordHash, pq.Array(&u.Claims), &u.CreatedAt, &u.UpdatedAt)
+		err = rows.Scan(scanTargets(&u, columns)...)
 		if err != nil {
 			return nil, err
 		}
@@ -93,16 +124,18 @@ func (r *userRepository) Get(ctx context.Context, filter map[string]interface{},
 	return users, nil
 }
 
-func (r *userRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
-	q := `
-    SELECT id, name, surnames, email, password_hash, claims, created_at, updated_at
+// GetByIDProjected gets the user with the specified ID, selecting only the columns allowed by the projection
+func (r *userRepository) GetByIDProjected(ctx context.Context, ID string, projection map[string]interface{}) (interface{}, error) {
+	columns := projectColumns(projection)
+	q := fmt.Sprintf(`
+    SELECT %s
         FROM users WHERE id = $1;
-    `
+    `, strings.Join(columns, ", "))
 
-	row := r.DB.QueryRowContext(ctx, q, ID)
+	row := r.querier(ctx).QueryRowContext(ctx, q, ID)
 
 	var u entities.User
-	err := row.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.PasswordHash, pq.Array(&u.Claims), &u.CreatedAt, &u.UpdatedAt)
+	err := row.Scan(scanTargets(&u, columns)...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -115,16 +148,16 @@ func (r *userRepository) GetByID(ctx context.Context, ID string) (interface{}, e
 
 func (r *userRepository) Update(ctx context.Context, ID string, user interface{}) error {
 	q := `
-	UPDATE users set name=$1, surnames=$2, email=$3, password_hash=$4, claims=$5, updated_at=$6
-	    WHERE id=$7;
+	UPDATE users set name=$1, surnames=$2, email=$3, password_hash=$4, claims=$5, location=$6, updated_at=$7
+	    WHERE id=$8;
 	`
 
 	u := user.(entities.User)
-	result, err := r.DB.ExecContext(
-		ctx, q, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.Claims), u.UpdatedAt, ID,
+	result, err := r.querier(ctx).ExecContext(
+		ctx, q, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.Claims), jsonLocation{&u.Location}, u.UpdatedAt, ID,
 	)
 	if err != nil {
-		return err
+		return emailConflict(err, u.Email)
 	}
 
 	rows, err := result.RowsAffected()
@@ -140,7 +173,7 @@ func (r *userRepository) Update(ctx context.Context, ID string, user interface{}
 func (r *userRepository) Delete(ctx context.Context, ID string) error {
 	q := `DELETE FROM users WHERE id=$1;`
 
-	result, err := r.DB.ExecContext(ctx, q, ID)
+	result, err := r.querier(ctx).ExecContext(ctx, q, ID)
 	if err != nil {
 		return err
 	}
@@ -156,38 +189,549 @@ func (r *userRepository) Delete(ctx context.Context, ID string) error {
 }
 
 func (r *userRepository) CreateMany(ctx context.Context, users []interface{}) ([]string, error) {
-	// `tx` is an instance of `*sql.Tx` through which we can execute our queries
-	tx, err := r.DB.BeginTx(ctx, nil)
+	var result []string
+	err := r.WithTransaction(ctx, func(ctx context.Context) error {
+		for _, entity := range users {
+			id, err := r.Create(ctx, entity)
+			if err != nil {
+				return err
+			}
+			result = append(result, id)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return result, nil
+}
 
-	var result []string
-	for _, entity := range users {
-		u := entity.(entities.User)
+// WithTransaction runs fn inside a database transaction, committing it if fn succeeds and rolling it back otherwise
+func (r *userRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
 
-		q := `
-		INSERT INTO users (name, surnames, email, password_hash, claims, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-			RETURNING id;`
+// querier returns the ongoing transaction of the context if any, or the database otherwise
+func (r *userRepository) querier(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return r.DB
+}
+
+// Upsert updates the user matching the filter or creates it if none matches, returning its ID.
+// The creation date is only set on insert, and an empty password hash never overrides an existing one.
+func (r *userRepository) Upsert(ctx context.Context, filter map[string]interface{}, user interface{}) (string, error) {
+	u := user.(entities.User)
 
-		// Here, the query is executed on the transaction instance, and not applied to the database yet
-		row := tx.QueryRowContext(
-			ctx, q, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.Claims), u.CreatedAt, u.UpdatedAt,
+	var id string
+	err := r.WithTransaction(ctx, func(ctx context.Context) error {
+		where, args := whereClause(filter)
+		q := fmt.Sprintf(`SELECT id FROM users %s FOR UPDATE;`, where)
+
+		err := r.querier(ctx).QueryRowContext(ctx, q, args...).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			q = `
+			INSERT INTO users (name, surnames, email, password_hash, claims, location, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING id;`
+
+			return r.querier(ctx).QueryRowContext(
+				ctx, q, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.Claims), jsonLocation{&u.Location}, u.CreatedAt, u.UpdatedAt,
+			).Scan(&id)
+		}
+		if err != nil {
+			return err
+		}
+
+		q = `
+		UPDATE users SET name=$1, surnames=$2, email=$3, password_hash=COALESCE(NULLIF($4, ''), password_hash), claims=$5,
+			location=COALESCE($6::jsonb, location), updated_at=$7
+			WHERE id=$8;`
+
+		_, err = r.querier(ctx).ExecContext(
+			ctx, q, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.Claims), jsonLocation{&u.Location}, u.UpdatedAt, id,
 		)
-		err := row.Scan(&u.ID)
+		return err
+	})
+	if err != nil {
+		return "", emailConflict(err, u.Email)
+	}
+	return id, nil
+}
+
+// Search gets the users whose name, surnames or email contain the text, or start with it in autocomplete mode.
+// Matching is case-insensitive, typo tolerance is only available on the mongo adapter.
+func (r *userRepository) Search(ctx context.Context, text string, autocomplete bool, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	pattern := likeEscaper.Replace(text) + "%"
+	if !autocomplete {
+		pattern = "%" + pattern
+	}
+
+	var pagination string
+	if skip != nil {
+		pagination = fmt.Sprintf("%s OFFSET %d", pagination, *skip)
+	}
+	if take != nil {
+		pagination = fmt.Sprintf("%s LIMIT %d", pagination, *take)
+	}
+
+	columns := projectColumns(projection)
+	q := fmt.Sprintf(`
+	SELECT %s
+	    FROM users WHERE name ILIKE $1 OR surnames ILIKE $1 OR email ILIKE $1
+	    ORDER BY name, surnames%s;
+	`, strings.Join(columns, ", "), pagination)
+
+	rows, err := r.querier(ctx).QueryContext(ctx, q, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var users []interface{}
+	for rows.Next() {
+		var u entities.User
+		err = rows.Scan(scanTargets(&u, columns)...)
 		if err != nil {
-			// Incase we find any error in the query execution, rollback the transaction
-			tx.Rollback()
 			return nil, err
 		}
-		result = append(result, u.ID)
+		users = append(users, &u)
 	}
 
-	// Finally, if no errors are recieved from the queries, commit the transaction
-	// this applies the above changes to our database
-	if err := tx.Commit(); err != nil {
+	if len(users) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return users, nil
+}
+
+// GetNearby gets the users located within the radius, in meters, of a point, sorted by distance.
+// Distances are computed with the haversine formula over the GeoJSON coordinates stored in the location column.
+func (r *userRepository) GetNearby(ctx context.Context, longitude, latitude, radius float64, projection map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var pagination string
+	if skip != nil {
+		pagination = fmt.Sprintf("%s OFFSET %d", pagination, *skip)
+	}
+	if take != nil {
+		pagination = fmt.Sprintf("%s LIMIT %d", pagination, *take)
+	}
+
+	columns := projectColumns(projection)
+	q := fmt.Sprintf(`
+	SELECT %s FROM (
+	    SELECT *, %f * 2 * ASIN(SQRT(
+	        POWER(SIN(RADIANS((location->'coordinates'->>1)::float8 - $2) / 2), 2) +
+	        COS(RADIANS($2)) * COS(RADIANS((location->'coordinates'->>1)::float8)) *
+	        POWER(SIN(RADIANS((location->'coordinates'->>0)::float8 - $1) / 2), 2)
+	    )) AS distance
+	        FROM users WHERE location IS NOT NULL
+	) AS nearby WHERE distance <= $3
+	    ORDER BY distance%s;
+	`, strings.Join(columns, ", "), earthRadius, pagination)
+
+	rows, err := r.querier(ctx).QueryContext(ctx, q, longitude, latitude, radius)
+	if err != nil {
 		return nil, err
 	}
-	return result, nil
+
+	defer rows.Close()
+
+	var users []interface{}
+	for rows.Next() {
+		var u entities.User
+		err = rows.Scan(scanTargets(&u, columns)...)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, &u)
+	}
+
+	if len(users) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return users, nil
+}
+
+// UpdateLastLogin sets the last login date of the user with the specified ID
+func (r *userRepository) UpdateLastLogin(ctx context.Context, ID string, at time.Time) error {
+	q := `UPDATE users SET last_login_at=$1 WHERE id=$2;`
+
+	result, err := r.querier(ctx).ExecContext(ctx, q, at, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// UpdateNames sets the name, the surnames and the update date of the user with the specified ID
+func (r *userRepository) UpdateNames(ctx context.Context, ID, name, surnames string, at time.Time) error {
+	q := `UPDATE users SET name=$1, surnames=$2, updated_at=$3 WHERE id=$4;`
+
+	result, err := r.querier(ctx).ExecContext(ctx, q, name, surnames, at, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// UpdateBillingCustomer sets the ID of the customer in the billing provider of the user with the specified ID
+func (r *userRepository) UpdateBillingCustomer(ctx context.Context, ID, customerID string) error {
+	q := `UPDATE users SET billing_customer_id=$1 WHERE id=$2;`
+
+	result, err := r.querier(ctx).ExecContext(ctx, q, customerID, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// UpdateSubscription sets the subscription status of the user of the customer unless it was set as of a later time
+func (r *userRepository) UpdateSubscription(ctx context.Context, customerID, status string, at time.Time) error {
+	q := `
+	UPDATE users SET subscription_status=$1, subscription_updated_at=$2
+	    WHERE billing_customer_id=$3 AND (subscription_updated_at IS NULL OR subscription_updated_at <= $2);
+	`
+
+	result, err := r.querier(ctx).ExecContext(ctx, q, status, at, customerID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// Archive moves the users whose last login, or last update if they never logged in, is older than inactiveSince to the archive table
+func (r *userRepository) Archive(ctx context.Context, inactiveSince, at time.Time) (int64, error) {
+	columns := strings.Join(userColumns, ", ")
+	q := fmt.Sprintf(`
+	WITH moved AS (
+	    DELETE FROM users WHERE COALESCE(last_login_at, updated_at) < $1
+	        RETURNING %s
+	)
+	INSERT INTO users_archive (%s, archived_at)
+	    SELECT %s, $2::timestamp FROM moved;
+	`, columns, columns, columns)
+
+	result, err := r.querier(ctx).ExecContext(ctx, q, inactiveSince, at)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// Stats counts the users, the active ones and the archived ones, the active ones having logged in or, when they never did, been updated since the given time
+func (r *userRepository) Stats(ctx context.Context, activeSince time.Time) (stats entities.UserStats, err error) {
+	q := `
+	SELECT COUNT(*),
+	    COUNT(*) FILTER (WHERE COALESCE(last_login_at, updated_at) >= $1),
+	    (SELECT COUNT(*) FROM users_archive)
+	FROM users;
+	`
+	err = r.querier(ctx).QueryRowContext(ctx, q, activeSince).Scan(&stats.Total, &stats.Active, &stats.Archived)
+	return
+}
+
+// ArchiveByID moves the user with the specified ID to the archive table
+func (r *userRepository) ArchiveByID(ctx context.Context, ID string, at time.Time) error {
+	columns := strings.Join(userColumns, ", ")
+	q := fmt.Sprintf(`
+	WITH moved AS (
+	    DELETE FROM users WHERE id = $1
+	        RETURNING %s
+	)
+	INSERT INTO users_archive (%s, archived_at)
+	    SELECT %s, $2::timestamp FROM moved;
+	`, columns, columns, columns)
+
+	result, err := r.querier(ctx).ExecContext(ctx, q, ID, at)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// Unarchive moves the archived user with the specified ID back to the users table, unless it has been anonymized
+func (r *userRepository) Unarchive(ctx context.Context, ID string, at time.Time) error {
+	columns := strings.Join(userColumns, ", ")
+	selected := strings.Replace(columns, "updated_at", "$2::timestamp", 1)
+	q := fmt.Sprintf(`
+	WITH moved AS (
+	    DELETE FROM users_archive WHERE id = $1 AND anonymized_at IS NULL
+	        RETURNING %s
+	)
+	INSERT INTO users (%s)
+	    SELECT %s FROM moved;
+	`, columns, columns, selected)
+
+	result, err := r.querier(ctx).ExecContext(ctx, q, ID, at)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// Dump calls fn with every user, with all its fields, stopping at the first error
+func (r *userRepository) Dump(ctx context.Context, fn func(user entities.User) error) error {
+	q := fmt.Sprintf(`SELECT %s FROM users;`, strings.Join(userColumns, ", "))
+
+	rows, err := r.querier(ctx).QueryContext(ctx, q)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u entities.User
+		if err := rows.Scan(scanTargets(&u, userColumns)...); err != nil {
+			return err
+		}
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Restore replaces all the users with the given ones in a single transaction, keeping their IDs
+func (r *userRepository) Restore(ctx context.Context, users []entities.User) error {
+	q := fmt.Sprintf(`INSERT INTO users (%s) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);`, strings.Join(userColumns, ", "))
+
+	return r.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := r.querier(ctx).ExecContext(ctx, `DELETE FROM users;`); err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			_, err := r.querier(ctx).ExecContext(
+				ctx, q, u.ID, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.Claims), jsonLocation{&u.Location}, u.LastLoginAt, u.CreatedAt, u.UpdatedAt,
+				u.BillingCustomerID, u.SubscriptionStatus, u.SubscriptionUpdatedAt,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// earthRadius is the mean radius of the Earth in meters, the same one used by mongo for spherical queries
+const earthRadius = 6378100.0
+
+// jsonLocation adapts the location of a user to the nullable jsonb location column
+type jsonLocation struct {
+	location **entities.GeoPoint
+}
+
+// Value implements the driver.Valuer interface
+func (l jsonLocation) Value() (driver.Value, error) {
+	if *l.location == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(*l.location)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (l jsonLocation) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*l.location = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into a location", src)
+	}
+
+	var p entities.GeoPoint
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*l.location = &p
+	return nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// whereClause builds a parametrized WHERE clause matching all the fields of the filter
+func whereClause(filter map[string]interface{}) (string, []interface{}) {
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var conditions []string
+	var args []interface{}
+	for i, k := range keys {
+		if caseInsensitiveColumns[k] {
+			conditions = append(conditions, fmt.Sprintf("lower(%s) = lower($%d)", pq.QuoteIdentifier(k), i+1))
+			args = append(args, filter[k])
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", pq.QuoteIdentifier(k), i+1))
+		args = append(args, filter[k])
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// caseInsensitiveColumns are compared ignoring case, matching the expression of their unique index
+var caseInsensitiveColumns = map[string]bool{"email": true}
+
+// userColumns contains all the columns of the users table, in select order
+var userColumns = []string{"id", "name", "surnames", "email", "password_hash", "claims", "location", "last_login_at", "created_at", "updated_at",
+	"billing_customer_id", "subscription_status", "subscription_updated_at"}
+
+// projectColumns returns the columns allowed by a mongo-like projection.
+// If any field is included only those fields (and the id) are returned, otherwise all columns except the excluded ones.
+func projectColumns(projection map[string]interface{}) []string {
+	var inclusive bool
+	for _, v := range projection {
+		if included(v) {
+			inclusive = true
+			break
+		}
+	}
+
+	var columns []string
+	for _, c := range userColumns {
+		v, ok := projection[c]
+		switch {
+		case c == "id" && !(ok && !included(v)):
+			columns = append(columns, c)
+		case inclusive && ok && included(v):
+			columns = append(columns, c)
+		case !inclusive && !ok:
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+func included(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case int:
+		return t != 0
+	case int32:
+		return t != 0
+	case int64:
+		return t != 0
+	case float64:
+		return t != 0
+	default:
+		return false
+	}
+}
+
+// scanTargets returns pointers to the fields of the given user matching the received columns
+func scanTargets(u *entities.User, columns []string) []interface{} {
+	targets := make([]interface{}, len(columns))
+	for i, c := range columns {
+		switch c {
+		case "id":
+			targets[i] = &u.ID
+		case "name":
+			targets[i] = &u.Name
+		case "surnames":
+			targets[i] = &u.Surnames
+		case "email":
+			targets[i] = &u.Email
+		case "password_hash":
+			targets[i] = &u.PasswordHash
+		case "claims":
+			targets[i] = pq.Array(&u.Claims)
+		case "location":
+			targets[i] = jsonLocation{&u.Location}
+		case "last_login_at":
+			targets[i] = &u.LastLoginAt
+		case "created_at":
+			targets[i] = &u.CreatedAt
+		case "updated_at":
+			targets[i] = &u.UpdatedAt
+		case "billing_customer_id":
+			targets[i] = &u.BillingCustomerID
+		case "subscription_status":
+			targets[i] = &u.SubscriptionStatus
+		case "subscription_updated_at":
+			targets[i] = &u.SubscriptionUpdatedAt
+		}
+	}
+	return targets
 }
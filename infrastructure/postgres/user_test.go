@@ -3,11 +3,14 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/apierror"
 	"github.com/sergicanet9/go-hexagonal-api/core/entities"
 	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v3/mocks"
@@ -75,6 +78,29 @@ func TestCreate_InsertError(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestCreate_EmailInUse checks that Create returns a conflict when the email is already in use
+func TestCreate_EmailInUse(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	newUser := entities.User{Email: "test@test.com"}
+	mock.ExpectQuery("INSERT INTO users").WillReturnError(&pq.Error{Code: uniqueViolation})
+
+	// Act
+	_, err := repo.Create(context.Background(), newUser)
+
+	// Assert
+	assert.True(t, errors.Is(err, apierror.ErrConflict))
+	assert.Equal(t, "email test@test.com already in use", err.Error())
+}
+
 // TestGet_Ok checks that Get returns the expected response when a valid filter is received
 func TestGet_Ok(t *testing.T) {
 	// Arrange
@@ -93,8 +119,8 @@ func TestGet_Ok(t *testing.T) {
 	filter := map[string]interface{}{"email": "test-email", "name": "test-name"}
 	skip := 1
 	take := 1
-	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "password_hash", "claims", "created_at", "updated_at"}).
-		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Surnames, expectedUser.Email, expectedUser.PasswordHash, pq.Array(expectedUser.Claims), expectedUser.CreatedAt, expectedUser.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows(userColumns).
+		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Surnames, expectedUser.Email, expectedUser.PasswordHash, pq.Array(expectedUser.Claims), nil, nil, expectedUser.CreatedAt, expectedUser.UpdatedAt, "", "", nil))
 
 	// Act
 	result, err := repo.Get(context.Background(), filter, &skip, &take)
@@ -139,7 +165,7 @@ func TestGet_NoResourcesFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "password_hash", "claims", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows(userColumns))
 
 	// Act
 	_, err := repo.Get(context.Background(), map[string]interface{}{}, nil, nil)
@@ -163,8 +189,8 @@ func TestGetByID_Ok(t *testing.T) {
 	expectedUser := entities.User{
 		ID: "f8352727-231e-4de1-8257-c235a0af5c4a",
 	}
-	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "password_hash", "claims", "created_at", "updated_at"}).
-		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Surnames, expectedUser.Email, expectedUser.PasswordHash, pq.Array(expectedUser.Claims), expectedUser.CreatedAt, expectedUser.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows(userColumns).
+		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Surnames, expectedUser.Email, expectedUser.PasswordHash, pq.Array(expectedUser.Claims), nil, nil, expectedUser.CreatedAt, expectedUser.UpdatedAt, "", "", nil))
 
 	// Act
 	result, err := repo.GetByID(context.Background(), expectedUser.ID)
@@ -208,7 +234,7 @@ func TestGetByID_ResourceNotFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "password_hash", "claims", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows(userColumns))
 
 	// Act
 	_, err := repo.GetByID(context.Background(), "")
@@ -444,3 +470,749 @@ func TestCreateMany_CommitError(t *testing.T) {
 	// Assert
 	assert.Equal(t, expectedError, err.Error())
 }
+
+// TestGetProjected_ExcludedColumns checks that GetProjected does not select the columns excluded in the projection
+func TestGetProjected_ExcludedColumns(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedUser := entities.User{
+		ID: "f8352727-231e-4de1-8257-c235a0af5c4a",
+	}
+	projection := map[string]interface{}{"password_hash": 0}
+	mock.ExpectQuery(`SELECT id, name, surnames, email, claims, location, last_login_at, created_at, updated_at, billing_customer_id, subscription_status, subscription_updated_at\s+FROM users`).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "claims", "location", "last_login_at", "created_at", "updated_at", "billing_customer_id", "subscription_status", "subscription_updated_at"}).
+		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Surnames, expectedUser.Email, pq.Array(expectedUser.Claims), nil, nil, expectedUser.CreatedAt, expectedUser.UpdatedAt, "", "", nil))
+
+	// Act
+	result, err := repo.GetProjected(context.Background(), map[string]interface{}{}, projection, nil, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, len(result) == 1)
+
+	entity := *(result[0].(*entities.User))
+	assert.Equal(t, expectedUser, entity)
+}
+
+// TestGetByIDProjected_IncludedColumns checks that GetByIDProjected only selects the id and the columns included in the projection
+func TestGetByIDProjected_IncludedColumns(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedUser := entities.User{
+		ID:    "f8352727-231e-4de1-8257-c235a0af5c4a",
+		Email: "test@test.com",
+	}
+	projection := map[string]interface{}{"email": 1}
+	mock.ExpectQuery(`SELECT id, email\s+FROM users`).WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).
+		AddRow(expectedUser.ID, expectedUser.Email))
+
+	// Act
+	result, err := repo.GetByIDProjected(context.Background(), expectedUser.ID, projection)
+
+	// Assert
+	assert.Nil(t, err)
+
+	entity := *(result.(*entities.User))
+	assert.Equal(t, expectedUser, entity)
+}
+
+// TestUpsert_Insert checks that Upsert inserts the user when none matches the filter
+func TestUpsert_Insert(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM users").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+	mock.ExpectCommit()
+
+	// Act
+	id, err := repo.Upsert(context.Background(), map[string]interface{}{"email": "test@test.com"}, entities.User{})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedID, id)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUpsert_Update checks that Upsert updates the user matching the filter
+func TestUpsert_Update(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM users").WithArgs("test@test.com").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+	mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	id, err := repo.Upsert(context.Background(), map[string]interface{}{"email": "test@test.com"}, entities.User{})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedID, id)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUpsert_InsertError checks that Upsert rolls back and returns an error when the insert statement fails
+func TestUpsert_InsertError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "insert error"
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM users").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO users").WillReturnError(fmt.Errorf(expectedError))
+	mock.ExpectRollback()
+
+	// Act
+	_, err := repo.Upsert(context.Background(), map[string]interface{}{"email": "test@test.com"}, entities.User{})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestSearch_Ok checks that Search returns the users containing the text when everything goes as expected
+func TestSearch_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedUser := entities.User{
+		ID:   "f8352727-231e-4de1-8257-c235a0af5c4a",
+		Name: "test_name",
+	}
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE name ILIKE").WithArgs(`%test\_%`).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "claims", "location", "last_login_at", "created_at", "updated_at", "billing_customer_id", "subscription_status", "subscription_updated_at"}).
+		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Surnames, expectedUser.Email, pq.Array(expectedUser.Claims), nil, nil, expectedUser.CreatedAt, expectedUser.UpdatedAt, "", "", nil))
+
+	// Act
+	result, err := repo.Search(context.Background(), "test_", false, map[string]interface{}{"password_hash": 0}, nil, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, len(result) == 1)
+	assert.Equal(t, expectedUser, *(result[0].(*entities.User)))
+}
+
+// TestSearch_Autocomplete checks that Search only matches the users starting with the text in autocomplete mode
+func TestSearch_Autocomplete(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	take := 5
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE (.+) LIMIT 5").WithArgs("test%").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Act
+	_, err := repo.Search(context.Background(), "test", true, map[string]interface{}{"id": 1}, nil, &take)
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestGetNearby_Ok checks that GetNearby returns the users within the radius with their location
+func TestGetNearby_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	location := entities.NewGeoPoint(2.17, 41.38)
+	expectedUser := entities.User{
+		ID:       "f8352727-231e-4de1-8257-c235a0af5c4a",
+		Location: &location,
+	}
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE location IS NOT NULL(.+) WHERE distance <= \\$3").WithArgs(2.17, 41.38, float64(1000)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "location"}).AddRow(expectedUser.ID, []byte(`{"type":"Point","coordinates":[2.17,41.38]}`)))
+
+	// Act
+	result, err := repo.GetNearby(context.Background(), 2.17, 41.38, 1000, map[string]interface{}{"location": 1}, nil, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedUser, *(result[0].(*entities.User)))
+}
+
+// TestGetNearby_NoResourcesFound checks that GetNearby returns an error when no users are found within the radius
+func TestGetNearby_NoResourcesFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "location"}))
+
+	// Act
+	_, err := repo.GetNearby(context.Background(), 2.17, 41.38, 1000, map[string]interface{}{"location": 1}, nil, nil)
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestWithTransaction_Ok checks that WithTransaction commits the transaction when the received function succeeds
+func TestWithTransaction_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.WithTransaction(context.Background(), func(ctx context.Context) error {
+		return repo.Delete(ctx, "test-id")
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestWithTransaction_FunctionError checks that WithTransaction rolls back the transaction and returns the error when the received function fails
+func TestWithTransaction_FunctionError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "function error"
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	// Act
+	err := repo.WithTransaction(context.Background(), func(ctx context.Context) error {
+		return fmt.Errorf(expectedError)
+	})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestWithTransaction_Nested checks that WithTransaction joins the ongoing transaction instead of starting a new one
+func TestWithTransaction_Nested(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.WithTransaction(context.Background(), func(ctx context.Context) error {
+		return repo.WithTransaction(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateLastLogin_Ok checks that UpdateLastLogin does not return an error when the user exists
+func TestUpdateLastLogin_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	at := time.Now().UTC()
+	mock.ExpectExec("UPDATE users SET last_login_at").WithArgs(at, "test-id").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.UpdateLastLogin(context.Background(), "test-id", at)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdateLastLogin_NotUpdatedError checks that UpdateLastLogin returns an error when the user does not exist
+func TestUpdateLastLogin_NotUpdatedError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("UPDATE users SET last_login_at").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.UpdateLastLogin(context.Background(), "test-id", time.Now().UTC())
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestUpdateNames_Ok checks that UpdateNames sets only the name, the surnames and the update date of the user
+func TestUpdateNames_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectExec("UPDATE users SET name").WithArgs("test-name", "test-surnames", now, "test-id").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.UpdateNames(context.Background(), "test-id", "test-name", "test-surnames", now)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdateBillingCustomer_Ok checks that UpdateBillingCustomer sets the customer of the user
+func TestUpdateBillingCustomer_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("UPDATE users SET billing_customer_id").WithArgs("cus_123", "test-id").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.UpdateBillingCustomer(context.Background(), "test-id", "cus_123")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdateSubscription_Ok checks that UpdateSubscription sets the status of the user of the customer unless it was set as of a later time
+func TestUpdateSubscription_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	at := time.Now().UTC()
+	mock.ExpectExec(`UPDATE users SET subscription_status=\$1, subscription_updated_at=\$2\s+WHERE billing_customer_id=\$3 AND \(subscription_updated_at IS NULL OR subscription_updated_at <= \$2\)`).
+		WithArgs("active", at, "cus_123").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.UpdateSubscription(context.Background(), "cus_123", "active", at)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdateSubscription_NotUpdatedError checks that UpdateSubscription returns a NonExistent error when no user of the customer has an older status
+func TestUpdateSubscription_NotUpdatedError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("UPDATE users SET subscription_status").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.UpdateSubscription(context.Background(), "cus_123", "active", time.Now().UTC())
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestArchive_Ok checks that Archive returns the number of users moved to the archive table
+func TestArchive_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	inactiveSince, at := time.Now().UTC().Add(-time.Hour), time.Now().UTC()
+	mock.ExpectExec(`DELETE FROM users WHERE (.+) INSERT INTO users_archive`).WithArgs(inactiveSince, at).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Act
+	count, err := repo.Archive(context.Background(), inactiveSince, at)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+// TestArchive_ArchiveError checks that Archive returns an error when the statement fails
+func TestArchive_ArchiveError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "archive error"
+	mock.ExpectExec("INSERT INTO users_archive").WillReturnError(fmt.Errorf(expectedError))
+
+	// Act
+	_, err := repo.Archive(context.Background(), time.Now().UTC(), time.Now().UTC())
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestArchiveByID_Ok checks that ArchiveByID does not return an error when the user is moved to the archive
+func TestArchiveByID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	at := time.Now().UTC()
+	mock.ExpectExec(`DELETE FROM users WHERE (.+) INSERT INTO users_archive`).WithArgs("test-id", at).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.ArchiveByID(context.Background(), "test-id", at)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestArchiveByID_NotFoundError checks that ArchiveByID returns a NonExistent error when the user is not found
+func TestArchiveByID_NotFoundError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM users WHERE").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.ArchiveByID(context.Background(), "test-id", time.Now().UTC())
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestUnarchive_Ok checks that Unarchive does not return an error when the user is archived
+func TestUnarchive_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	at := time.Now().UTC()
+	mock.ExpectExec(`DELETE FROM users_archive WHERE (.+) INSERT INTO users`).WithArgs("test-id", at).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Unarchive(context.Background(), "test-id", at)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUnarchive_NotArchivedError checks that Unarchive returns an error when the user is not archived
+func TestUnarchive_NotArchivedError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM users_archive").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Unarchive(context.Background(), "test-id", time.Now().UTC())
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestDump_Ok checks that Dump calls the received function with every user
+func TestDump_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	rows := sqlmock.NewRows(userColumns).
+		AddRow("test-id-1", "test", "test", "test1@test.com", "test-hash", pq.Array([]int64{}), nil, nil, time.Now(), time.Now(), "cus_123", "active", nil).
+		AddRow("test-id-2", "test", "test", "test2@test.com", "test-hash", pq.Array([]int64{}), nil, nil, time.Now(), time.Now(), "cus_123", "active", nil)
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(rows)
+
+	var users []entities.User
+
+	// Act
+	err := repo.Dump(context.Background(), func(user entities.User) error {
+		users = append(users, user)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "test-hash", users[1].PasswordHash)
+}
+
+// TestDump_QueryError checks that Dump returns an error when the query fails
+func TestDump_QueryError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "query error"
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnError(fmt.Errorf(expectedError))
+
+	// Act
+	err := repo.Dump(context.Background(), func(user entities.User) error {
+		return nil
+	})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestRestore_Ok checks that Restore replaces the users in a transaction
+func TestRestore_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	users := []entities.User{{ID: "f8352727-231e-4de1-8257-c235a0af5c4a"}}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("INSERT INTO users").WithArgs(users[0].ID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.Restore(context.Background(), users)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRestore_InsertError checks that Restore rolls back the transaction when an insert fails
+func TestRestore_InsertError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "insert error"
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("INSERT INTO users").WillReturnError(fmt.Errorf(expectedError))
+	mock.ExpectRollback()
+
+	// Act
+	err := repo.Restore(context.Background(), []entities.User{{}})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestWhereClause_CaseInsensitiveEmail checks that whereClause compares the email ignoring case and the other columns exactly
+func TestWhereClause_CaseInsensitiveEmail(t *testing.T) {
+	// Arrange
+	filter := map[string]interface{}{"email": "Test@Test.com", "name": "test"}
+
+	// Act
+	where, args := whereClause(filter)
+
+	// Assert
+	assert.Equal(t, `WHERE lower("email") = lower($1) AND "name" = $2`, where)
+	assert.Equal(t, []interface{}{"Test@Test.com", "test"}, args)
+}
+
+// TestGetProjected_CaseInsensitiveEmail checks that GetProjected filters by email ignoring case, passing it as a parameter
+func TestGetProjected_CaseInsensitiveEmail(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	email := "Test@Test.com"
+	mock.ExpectQuery(`WHERE lower\("email"\) = lower\(\$1\)`).WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow("test-id", "test@test.com"))
+
+	// Act
+	result, err := repo.GetProjected(context.Background(), map[string]interface{}{"email": email}, map[string]interface{}{"email": 1}, nil, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test@test.com", result[0].(*entities.User).Email)
+}
+
+// TestStats_Ok checks that Stats counts the users, the active ones and the archived ones in a single query
+func TestStats_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	activeSince := time.Now().UTC().Add(-time.Hour)
+	mock.ExpectQuery(`SELECT COUNT\(\*\)`).WithArgs(activeSince).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "active", "archived"}).AddRow(3, 2, 1))
+
+	// Act
+	stats, err := repo.Stats(context.Background(), activeSince)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, entities.UserStats{Total: 3, Active: 2, Archived: 1}, stats)
+}
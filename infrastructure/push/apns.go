@@ -0,0 +1,150 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+)
+
+// endpoints of the production and sandbox environments of the Apple Push Notification service
+const (
+	apnsProductionEndpoint = "https://api.push.apple.com"
+	apnsSandboxEndpoint    = "https://api.sandbox.push.apple.com"
+)
+
+// apnsTokenLifetime is the time a provider token is reused for, APNs rejecting the tokens older than an hour
+// and the ones renewed more than once every 20 minutes
+const apnsTokenLifetime = 50 * time.Minute
+
+// apnsSender adapter of a push sender sending the notifications through the HTTP/2 API of the Apple Push Notification service,
+// authorized with provider tokens signed with the key of the team, cached for apnsTokenLifetime
+type apnsSender struct {
+	endpoint string
+	keyID    string
+	teamID   string
+	topic    string
+	key      interface{}
+	client   *http.Client
+	now      func() time.Time
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNsSender creates a sender sending the notifications to the app of the given topic, its bundle ID, authorized with the PEM of the .p8 key of the given ID of the team,
+// through the production environment of APNs or the sandbox one
+func NewAPNsSender(keyID, teamID, privateKey, topic string, production bool, client *http.Client) (ports.PushSender, error) {
+	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("apns private key not valid: %w", err)
+	}
+
+	endpoint := apnsSandboxEndpoint
+	if production {
+		endpoint = apnsProductionEndpoint
+	}
+	return &apnsSender{
+		endpoint: endpoint,
+		keyID:    keyID,
+		teamID:   teamID,
+		topic:    topic,
+		key:      key,
+		client:   client,
+		now:      time.Now,
+	}, nil
+}
+
+// Send sends the notification to the token of the device, failing with ErrDeviceTokenNotValid when APNs reports it no longer valid
+func (s *apnsSender) Send(ctx context.Context, device entities.Device, notification ports.PushNotification) error {
+	token, err := s.providerToken()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{"title": notification.Title, "body": notification.Body},
+			"sound": "default",
+		},
+	}
+	for key, value := range notification.Data {
+		if key != "aps" {
+			payload[key] = value
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/3/device/"+url.PathEscape(device.Token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("apns-topic", s.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	tracing.InjectHeader(ctx, req.Header)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var failure struct {
+		Reason string `json:"reason"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&failure)
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		resp.StatusCode == http.StatusBadRequest && failure.Reason == "BadDeviceToken":
+		return ports.ErrDeviceTokenNotValid
+	case resp.StatusCode == http.StatusForbidden && failure.Reason == "ExpiredProviderToken":
+		s.mu.Lock()
+		s.token = ""
+		s.mu.Unlock()
+	}
+	return fmt.Errorf("apns responded with status %d: %s", resp.StatusCode, failure.Reason)
+}
+
+// providerToken returns the cached provider token, or a new one signed with the key of the team once older than apnsTokenLifetime
+func (s *apnsSender) providerToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.token != "" && now.Sub(s.issuedAt) < apnsTokenLifetime {
+		return s.token, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": s.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = s.keyID
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", err
+	}
+
+	s.token, s.issuedAt = signed, now
+	return s.token, nil
+}
@@ -0,0 +1,121 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// testAPNsKey returns the PEM of a new .p8 key
+func testAPNsKey(t *testing.T) (string, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), key
+}
+
+// TestAPNsSend_Ok checks that Send sends the alert to the token of the device, authorized with a provider token of the team naming the key
+func TestAPNsSend_Ok(t *testing.T) {
+	// Arrange
+	var path string
+	var header http.Header
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		header = r.Header
+		json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	privateKey, key := testAPNsKey(t)
+	sender, err := NewAPNsSender("TESTKEYID", "TESTTEAM", privateKey, "com.example.app", false, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sender.(*apnsSender)
+	s.endpoint = server.URL
+	device := entities.Device{Platform: entities.DevicePlatformAPNs, Token: "test-token"}
+	notification := ports.PushNotification{Title: "test title", Body: "test body", Data: map[string]string{"event": "test"}}
+
+	// Act
+	err = s.Send(context.Background(), device, notification)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "/3/device/test-token", path)
+	assert.Equal(t, "com.example.app", header.Get("apns-topic"))
+	assert.Equal(t, "alert", header.Get("apns-push-type"))
+	assert.Equal(t, "10", header.Get("apns-priority"))
+	assert.Equal(t, map[string]interface{}{"title": "test title", "body": "test body"}, payload["aps"].(map[string]interface{})["alert"])
+	assert.Equal(t, "test", payload["event"])
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(strings.TrimPrefix(header.Get("Authorization"), "bearer "), claims, func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+	assert.Nil(t, err)
+	assert.Equal(t, "TESTKEYID", token.Header["kid"])
+	assert.Equal(t, "TESTTEAM", claims["iss"])
+}
+
+// TestAPNsSend_Unregistered checks that Send returns ErrDeviceTokenNotValid when APNs reports the token no longer active
+func TestAPNsSend_Unregistered(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+		w.Write([]byte(`{"reason":"Unregistered","timestamp":1692090000000}`))
+	}))
+	defer server.Close()
+
+	privateKey, _ := testAPNsKey(t)
+	sender, err := NewAPNsSender("TESTKEYID", "TESTTEAM", privateKey, "com.example.app", false, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender.(*apnsSender).endpoint = server.URL
+
+	// Act
+	err = sender.Send(context.Background(), entities.Device{Token: "test-token"}, ports.PushNotification{})
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrDeviceTokenNotValid)
+}
+
+// TestAPNsSend_Error checks that Send returns the reason of the failure when APNs rejects the notification
+func TestAPNsSend_Error(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"reason":"TopicDisallowed"}`))
+	}))
+	defer server.Close()
+
+	privateKey, _ := testAPNsKey(t)
+	sender, err := NewAPNsSender("TESTKEYID", "TESTTEAM", privateKey, "com.example.app", false, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender.(*apnsSender).endpoint = server.URL
+
+	// Act
+	err = sender.Send(context.Background(), entities.Device{Token: "test-token"}, ports.PushNotification{})
+
+	// Assert
+	assert.EqualError(t, err, "apns responded with status 400: TopicDisallowed")
+}
@@ -0,0 +1,192 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+)
+
+// fcmEndpoint is the endpoint of the HTTP v1 API of Firebase Cloud Messaging
+const fcmEndpoint = "https://fcm.googleapis.com"
+
+// fcmScope is the OAuth 2.0 scope of the access tokens sending the messages
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// tokenExpiryMargin is the time before their expiry the access tokens are renewed, so they do not expire while in flight
+const tokenExpiryMargin = time.Minute
+
+// fcmSender adapter of a push sender sending the notifications through the HTTP v1 API of Firebase Cloud Messaging,
+// authorized with the access tokens granted to a service account, cached until about to expire
+type fcmSender struct {
+	endpoint    string
+	projectID   string
+	clientEmail string
+	tokenURI    string
+	key         interface{}
+	client      *http.Client
+	now         func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// serviceAccount is the part of the JSON key of a Google service account signing the grants of the access tokens
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	PrivateKey  string `json:"private_key"`
+	ClientEmail string `json:"client_email"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// NewFCMSender creates a sender sending the notifications to the FCM project with the given ID, or the one of the service account when empty,
+// authorized with the JSON key of the service account
+func NewFCMSender(projectID, credentials string, client *http.Client) (ports.PushSender, error) {
+	var account serviceAccount
+	if err := json.Unmarshal([]byte(credentials), &account); err != nil {
+		return nil, fmt.Errorf("fcm credentials not valid: %w", err)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("fcm private key not valid: %w", err)
+	}
+	if projectID == "" {
+		projectID = account.ProjectID
+	}
+	if projectID == "" || account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, errors.New("fcm credentials not valid: project_id, client_email and token_uri must be set")
+	}
+
+	return &fcmSender{
+		endpoint:    fcmEndpoint,
+		projectID:   projectID,
+		clientEmail: account.ClientEmail,
+		tokenURI:    account.TokenURI,
+		key:         key,
+		client:      client,
+		now:         time.Now,
+	}, nil
+}
+
+// Send sends the notification to the token of the device, failing with ErrDeviceTokenNotValid when FCM reports it unregistered
+func (s *fcmSender) Send(ctx context.Context, device entities.Device, notification ports.PushNotification) error {
+	accessToken, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token":        device.Token,
+			"notification": map[string]string{"title": notification.Title, "body": notification.Body},
+			"data":         notification.Data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/messages:send", s.endpoint, url.PathEscape(s.projectID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	tracing.InjectHeader(ctx, req.Header)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		s.mu.Lock()
+		s.accessToken = ""
+		s.mu.Unlock()
+	}
+
+	var failure struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&failure)
+	for _, detail := range failure.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return ports.ErrDeviceTokenNotValid
+		}
+	}
+	return fmt.Errorf("fcm responded with status %d: %s %s", resp.StatusCode, failure.Error.Status, failure.Error.Message)
+}
+
+// token returns the cached access token, or the one granted for a new assertion signed with the key of the service account once it is about to expire
+func (s *fcmSender) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.accessToken != "" && now.Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.clientEmail,
+		"scope": fcmScope,
+		"aud":   s.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(s.key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"}, "assertion": {assertion}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("fcm access token not granted, status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	var grant struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&grant); err != nil {
+		return "", err
+	}
+
+	s.accessToken = grant.AccessToken
+	s.expiresAt = now.Add(time.Duration(grant.ExpiresIn)*time.Second - tokenExpiryMargin)
+	return s.accessToken, nil
+}
@@ -0,0 +1,145 @@
+package push
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// testFCMCredentials returns the JSON key of a service account with a new RSA key, its token URI being the given one
+func testFCMCredentials(t *testing.T, tokenURI string) (string, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials, _ := json.Marshal(serviceAccount{
+		ProjectID:   "test-project",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		ClientEmail: "push@test-project.iam.gserviceaccount.com",
+		TokenURI:    tokenURI,
+	})
+	return string(credentials), key
+}
+
+// TestFCMSend_Ok checks that Send sends the message to the token of the device, authorized with an access token granted for an assertion of the service account
+// that is reused for the next messages
+func TestFCMSend_Ok(t *testing.T) {
+	// Arrange
+	var grants int
+	var assertion, path, authorization string
+	var message map[string]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			grants++
+			r.ParseForm()
+			assertion = r.PostForm.Get("assertion")
+			w.Write([]byte(`{"access_token":"test-access-token","expires_in":3600}`))
+			return
+		}
+		path = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&message)
+		w.Write([]byte(`{"name":"projects/test-project/messages/1"}`))
+	}))
+	defer server.Close()
+
+	credentials, key := testFCMCredentials(t, server.URL+"/token")
+	sender, err := NewFCMSender("", credentials, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sender.(*fcmSender)
+	s.endpoint = server.URL
+	device := entities.Device{Platform: entities.DevicePlatformFCM, Token: "test-token"}
+	notification := ports.PushNotification{Title: "test title", Body: "test body", Data: map[string]string{"event": "test"}}
+
+	// Act
+	err = s.Send(context.Background(), device, notification)
+	secondErr := s.Send(context.Background(), device, notification)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, secondErr)
+	assert.Equal(t, 1, grants)
+	assert.Equal(t, "/v1/projects/test-project/messages:send", path)
+	assert.Equal(t, "Bearer test-access-token", authorization)
+	assert.Equal(t, "test-token", message["message"]["token"])
+	assert.Equal(t, map[string]interface{}{"title": "test title", "body": "test body"}, message["message"]["notification"])
+	assert.Equal(t, map[string]interface{}{"event": "test"}, message["message"]["data"])
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(assertion, claims, func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+	assert.Nil(t, err)
+	assert.Equal(t, "push@test-project.iam.gserviceaccount.com", claims["iss"])
+	assert.Equal(t, fcmScope, claims["scope"])
+}
+
+// TestFCMSend_Unregistered checks that Send returns ErrDeviceTokenNotValid when FCM reports the token unregistered
+func TestFCMSend_Unregistered(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Write([]byte(`{"access_token":"test-access-token","expires_in":3600}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"status":"NOT_FOUND","message":"Requested entity was not found.","details":[{"errorCode":"UNREGISTERED"}]}}`))
+	}))
+	defer server.Close()
+
+	credentials, _ := testFCMCredentials(t, server.URL+"/token")
+	sender, err := NewFCMSender("", credentials, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sender.(*fcmSender)
+	s.endpoint = server.URL
+
+	// Act
+	err = s.Send(context.Background(), entities.Device{Token: "test-token"}, ports.PushNotification{})
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrDeviceTokenNotValid)
+}
+
+// TestFCMSend_TokenNotGranted checks that Send returns an error when the access token is not granted
+func TestFCMSend_TokenNotGranted(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+	}))
+	defer server.Close()
+
+	credentials, _ := testFCMCredentials(t, server.URL+"/token")
+	sender, err := NewFCMSender("", credentials, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = sender.Send(context.Background(), entities.Device{Token: "test-token"}, ports.PushNotification{})
+
+	// Assert
+	assert.EqualError(t, err, `fcm access token not granted, status 400: {"error":"invalid_grant"}`)
+}
+
+// TestNewFCMSender_CredentialsNotValid checks that NewFCMSender returns an error when the credentials are not a JSON key
+func TestNewFCMSender_CredentialsNotValid(t *testing.T) {
+	// Act
+	_, err := NewFCMSender("", "not json", http.DefaultClient)
+
+	// Assert
+	assert.ErrorContains(t, err, "fcm credentials not valid")
+}
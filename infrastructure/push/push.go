@@ -0,0 +1,45 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// platformSender adapter of a push sender sending the notifications through the sender of the platform of each device
+type platformSender struct {
+	senders map[string]ports.PushSender
+}
+
+// NewSender creates the PushSender of the configured platforms, FCM when its credentials are set and APNs when its private key is set,
+// routing the notifications by the platform of the devices and failing for the devices of a platform not configured
+func NewSender(fcmProjectID, fcmCredentials, apnsKeyID, apnsTeamID, apnsPrivateKey, apnsTopic string, apnsProduction bool) (ports.PushSender, error) {
+	senders := map[string]ports.PushSender{}
+	if fcmCredentials != "" {
+		sender, err := NewFCMSender(fcmProjectID, fcmCredentials, http.DefaultClient)
+		if err != nil {
+			return nil, err
+		}
+		senders[entities.DevicePlatformFCM] = sender
+	}
+	if apnsPrivateKey != "" {
+		sender, err := NewAPNsSender(apnsKeyID, apnsTeamID, apnsPrivateKey, apnsTopic, apnsProduction, http.DefaultClient)
+		if err != nil {
+			return nil, err
+		}
+		senders[entities.DevicePlatformAPNs] = sender
+	}
+	return &platformSender{senders: senders}, nil
+}
+
+// Send sends the notification through the sender of the platform of the device
+func (s *platformSender) Send(ctx context.Context, device entities.Device, notification ports.PushNotification) error {
+	sender, ok := s.senders[device.Platform]
+	if !ok {
+		return fmt.Errorf("push platform %q not configured", device.Platform)
+	}
+	return sender.Send(ctx, device, notification)
+}
@@ -0,0 +1,32 @@
+package push
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSend_PlatformNotConfigured checks that Send returns an error for the devices of a platform without sender
+func TestSend_PlatformNotConfigured(t *testing.T) {
+	// Arrange
+	s := &platformSender{senders: map[string]ports.PushSender{entities.DevicePlatformFCM: mocks.NewPushSender(t)}}
+
+	// Act
+	err := s.Send(context.Background(), entities.Device{Platform: entities.DevicePlatformAPNs}, ports.PushNotification{})
+
+	// Assert
+	assert.EqualError(t, err, `push platform "apns" not configured`)
+}
+
+// TestNewSender_KeyNotValid checks that NewSender returns an error when the private key of APNs is not valid
+func TestNewSender_KeyNotValid(t *testing.T) {
+	// Act
+	_, err := NewSender("", "", "TESTKEYID", "TESTTEAM", "not a key", "com.example.app", false)
+
+	// Assert
+	assert.ErrorContains(t, err, "apns private key not valid")
+}
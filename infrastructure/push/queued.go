@@ -0,0 +1,68 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// queuedSender adapter of a push sender queuing the notifications as push jobs, one per device, sent by the workers
+// and retried when the platform is not reachable
+type queuedSender struct {
+	jobs ports.JobService
+}
+
+// NewQueuedSender creates a sender queuing the notifications to be sent by the workers
+func NewQueuedSender(jobs ports.JobService) ports.PushSender {
+	return &queuedSender{jobs: jobs}
+}
+
+// Send queues the notification to the device
+func (s *queuedSender) Send(ctx context.Context, device entities.Device, notification ports.PushNotification) error {
+	data, err := json.Marshal(notification.Data)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.jobs.Enqueue(ctx, entities.JobTypePush, map[string]string{
+		"device_id": device.ID,
+		"user_id":   device.UserID,
+		"platform":  device.Platform,
+		"token":     device.Token,
+		"title":     notification.Title,
+		"body":      notification.Body,
+		"data":      string(data),
+	})
+	return err
+}
+
+// Deliver sends a push job through the sender, deleting the device instead when its token is no longer valid, unless already deleted
+func Deliver(ctx context.Context, sender ports.PushSender, devices ports.DeviceService, job *entities.Job) error {
+	device := entities.Device{
+		ID:       job.Metadata["device_id"],
+		UserID:   job.Metadata["user_id"],
+		Platform: job.Metadata["platform"],
+		Token:    job.Metadata["token"],
+	}
+	notification := ports.PushNotification{
+		Title: job.Metadata["title"],
+		Body:  job.Metadata["body"],
+	}
+	if err := json.Unmarshal([]byte(job.Metadata["data"]), &notification.Data); err != nil {
+		return fmt.Errorf("push notification not valid: %w", err)
+	}
+
+	err := sender.Send(ctx, device, notification)
+	if errors.Is(err, ports.ErrDeviceTokenNotValid) {
+		if err := devices.Delete(ctx, device.UserID, device.ID); err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+			return err
+		}
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestQueuedSend_Ok checks that Send queues a push job that Deliver sends as the same notification to the same device
+func TestQueuedSend_Ok(t *testing.T) {
+	// Arrange
+	device := entities.Device{ID: "test-device", UserID: "test-user", Platform: entities.DevicePlatformFCM, Token: "test-token"}
+	notification := ports.PushNotification{Title: "test title", Body: "test body", Data: map[string]string{"event": "test"}}
+
+	var job entities.Job
+	jobServiceMock := mocks.NewJobService(t)
+	jobServiceMock.On(testutils.FunctionName(t, ports.JobService.Enqueue), context.Background(), entities.JobTypePush, mock.Anything).Run(func(args mock.Arguments) {
+		job = entities.Job{Type: entities.JobTypePush, Metadata: args.Get(2).(map[string]string)}
+	}).Return(models.JobResp{}, nil).Once()
+
+	pushSenderMock := mocks.NewPushSender(t)
+	pushSenderMock.On(testutils.FunctionName(t, ports.PushSender.Send), context.Background(), device, notification).Return(nil).Once()
+
+	// Act
+	err := NewQueuedSender(jobServiceMock).Send(context.Background(), device, notification)
+	deliverErr := Deliver(context.Background(), pushSenderMock, mocks.NewDeviceService(t), &job)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, deliverErr)
+}
+
+// TestDeliver_TokenNotValid checks that Deliver deletes the device when its token is no longer valid, without failing the job
+func TestDeliver_TokenNotValid(t *testing.T) {
+	// Arrange
+	job := entities.Job{Metadata: map[string]string{"device_id": "test-device", "user_id": "test-user", "platform": entities.DevicePlatformAPNs, "token": "test-token", "data": "{}"}}
+
+	pushSenderMock := mocks.NewPushSender(t)
+	pushSenderMock.On(testutils.FunctionName(t, ports.PushSender.Send), context.Background(), mock.Anything, mock.Anything).Return(ports.ErrDeviceTokenNotValid).Once()
+	deviceServiceMock := mocks.NewDeviceService(t)
+	deviceServiceMock.On(testutils.FunctionName(t, ports.DeviceService.Delete), context.Background(), "test-user", "test-device").Return(nil).Once()
+
+	// Act
+	err := Deliver(context.Background(), pushSenderMock, deviceServiceMock, &job)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeliver_Error checks that Deliver returns the error of the sender, so the job is retried
+func TestDeliver_Error(t *testing.T) {
+	// Arrange
+	job := entities.Job{Metadata: map[string]string{"data": "{}"}}
+	expectedError := errors.New("platform not reachable")
+
+	pushSenderMock := mocks.NewPushSender(t)
+	pushSenderMock.On(testutils.FunctionName(t, ports.PushSender.Send), context.Background(), mock.Anything, mock.Anything).Return(expectedError).Once()
+
+	// Act
+	err := Deliver(context.Background(), pushSenderMock, mocks.NewDeviceService(t), &job)
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
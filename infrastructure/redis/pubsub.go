@@ -0,0 +1,152 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// backoffs of the subscription to the channel after it fails, doubled after every failure
+const (
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// invalidator adapter of an invalidator broadcasting the keys through a Redis pub/sub channel, every key being a message,
+// published through a connection kept open, and opened again after any failure
+type invalidator struct {
+	address  string
+	password string
+	channel  string
+	logger   zerolog.Logger
+	dialer   net.Dialer
+	mu       sync.Mutex
+	conn     net.Conn
+	reader   *bufio.Reader
+}
+
+// NewInvalidator creates an invalidator broadcasting the keys through the channel of the Redis server at address, authenticating with the password when set
+func NewInvalidator(address, password, channel string, logger zerolog.Logger) ports.Invalidator {
+	return &invalidator{
+		address:  address,
+		password: password,
+		channel:  channel,
+		logger:   logger,
+		dialer:   net.Dialer{Timeout: 10 * time.Second},
+	}
+}
+
+// Invalidate publishes the keys to the channel
+func (i *invalidator) Invalidate(ctx context.Context, keys ...string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.conn == nil {
+		conn, reader, err := i.connect(ctx)
+		if err != nil {
+			return err
+		}
+		i.conn, i.reader = conn, reader
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		i.conn.SetDeadline(deadline)
+	} else {
+		i.conn.SetDeadline(time.Time{})
+	}
+	for _, key := range keys {
+		if _, err := command(i.conn, i.reader, "PUBLISH", i.channel, key); err != nil {
+			i.conn.Close()
+			i.conn, i.reader = nil, nil
+			return err
+		}
+	}
+	return nil
+}
+
+// Listen subscribes to the channel, passing the keys published to evict, until the context is done.
+// The subscription is renewed after any failure, passing InvalidateAll to evict every time it is subscribed, as the keys published meanwhile are lost.
+func (i *invalidator) Listen(ctx context.Context, evict func(key string)) error {
+	backoff := initialBackoff
+	for {
+		subscribed, err := i.subscribe(ctx, evict)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if subscribed {
+			backoff = initialBackoff
+		}
+		i.logger.Warn().Err(err).Dur("backoff", backoff).Str("channel", i.channel).Msg("cache invalidation channel subscription failed, renewing it")
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// subscribe subscribes to the channel and passes the keys published to evict until the connection fails or the context is done,
+// reporting whether it got subscribed
+func (i *invalidator) subscribe(ctx context.Context, evict func(key string)) (bool, error) {
+	conn, reader, err := i.connect(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := send(conn, "SUBSCRIBE", i.channel); err != nil {
+		return false, err
+	}
+	if _, err := array(reader, "SUBSCRIBE"); err != nil {
+		return false, err
+	}
+	evict(ports.InvalidateAll)
+
+	for {
+		msg, err := array(reader, "SUBSCRIBE")
+		if err != nil {
+			return true, err
+		}
+		if len(msg) == 3 && msg[0] == "message" && msg[1] == i.channel {
+			evict(msg[2])
+		}
+	}
+}
+
+// connect opens a connection to the server, authenticated with the password when set
+func (i *invalidator) connect(ctx context.Context) (net.Conn, *bufio.Reader, error) {
+	conn, err := i.dialer.DialContext(ctx, "tcp", i.address)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader := bufio.NewReader(conn)
+	if i.password != "" {
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		if _, err := command(conn, reader, "AUTH", i.password); err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		conn.SetDeadline(time.Time{})
+	}
+	return conn, reader, nil
+}
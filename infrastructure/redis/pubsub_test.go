@@ -0,0 +1,128 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// servePubSub starts a server forwarding the messages published to the subscribers of their channel, returning its address
+func servePubSub(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	var mu sync.Mutex
+	subscribers := map[string][]net.Conn{}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					header, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					count, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+					var args []string
+					for i := 0; i < count; i++ {
+						r.ReadString('\n')
+						arg, _ := r.ReadString('\n')
+						args = append(args, strings.TrimSuffix(arg, "\r\n"))
+					}
+
+					mu.Lock()
+					switch args[0] {
+					case "SUBSCRIBE":
+						subscribers[args[1]] = append(subscribers[args[1]], conn)
+						fmt.Fprintf(conn, "*3\r\n$9\r\nsubscribe\r\n$%d\r\n%s\r\n:1\r\n", len(args[1]), args[1])
+					case "PUBLISH":
+						for _, s := range subscribers[args[1]] {
+							fmt.Fprintf(s, "*3\r\n$7\r\nmessage\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(args[1]), args[1], len(args[2]), args[2])
+						}
+						fmt.Fprintf(conn, ":%d\r\n", len(subscribers[args[1]]))
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+// TestInvalidator_Ok checks that the keys invalidated by a replica are evicted by the replicas listening to the channel,
+// every key being evicted once subscribed
+func TestInvalidator_Ok(t *testing.T) {
+	// Arrange
+	address := servePubSub(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	evicted := make(chan string, 10)
+	listener := NewInvalidator(address, "", "invalidations", zerolog.Nop())
+	go listener.Listen(ctx, func(key string) { evicted <- key })
+	assert.Equal(t, ports.InvalidateAll, <-evicted)
+
+	// Act
+	err := NewInvalidator(address, "", "invalidations", zerolog.Nop()).Invalidate(ctx, "1", "2")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "1", <-evicted)
+	assert.Equal(t, "2", <-evicted)
+}
+
+// TestInvalidate_NotReachable checks that Invalidate returns an error when the server is not reachable
+func TestInvalidate_NotReachable(t *testing.T) {
+	// Arrange
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	l.Close()
+
+	// Act
+	err = NewInvalidator(address, "", "invalidations", zerolog.Nop()).Invalidate(context.Background(), "1")
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+// TestListen_Done checks that Listen returns once the context is done, while the server is not reachable
+func TestListen_Done(t *testing.T) {
+	// Arrange
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	l.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// Act
+	err = NewInvalidator(address, "", "invalidations", zerolog.Nop()).Listen(ctx, func(string) {})
+
+	// Assert
+	assert.Nil(t, err)
+}
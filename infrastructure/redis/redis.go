@@ -0,0 +1,126 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Ping verifies that the Redis server at address is reachable, authenticating with the password when set,
+// and returns its version, read from the server section of its info
+func Ping(ctx context.Context, address, password string) (string, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	r := bufio.NewReader(conn)
+	if password != "" {
+		if _, err := command(conn, r, "AUTH", password); err != nil {
+			return "", err
+		}
+	}
+	reply, err := command(conn, r, "PING")
+	if err != nil {
+		return "", err
+	}
+	if reply != "PONG" {
+		return "", fmt.Errorf("unexpected reply %q to PING", reply)
+	}
+
+	info, err := command(conn, r, "INFO", "server")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(info, "\r\n") {
+		if version, ok := strings.CutPrefix(line, "redis_version:"); ok {
+			return "version " + version, nil
+		}
+	}
+	return "", nil
+}
+
+// command sends the command as an array of bulk strings and returns its reply, either a simple string, an integer or a bulk string,
+// the error replies being returned as errors
+func command(w io.Writer, r *bufio.Reader, args ...string) (string, error) {
+	if err := send(w, args...); err != nil {
+		return "", err
+	}
+	return reply(r, args[0])
+}
+
+// send sends the command as an array of bulk strings
+func send(w io.Writer, args ...string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// reply reads the reply to the command of the given name, either a simple string, an integer or a bulk string, the error replies being returned as errors
+func reply(r *bufio.Reader, name string) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return "", fmt.Errorf("empty reply to %s", name)
+	}
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", fmt.Errorf("%s failed: %s", name, line[1:])
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return "", fmt.Errorf("unexpected reply %q to %s", line, name)
+		}
+		bulk := make([]byte, size+2)
+		if _, err := io.ReadFull(r, bulk); err != nil {
+			return "", err
+		}
+		return string(bulk[:size]), nil
+	default:
+		return "", fmt.Errorf("unexpected reply %q to %s", line, name)
+	}
+}
+
+// array reads an array reply to the command of the given name, like the messages pushed to the subscribers, whose elements are not arrays
+func array(r *bufio.Reader, name string) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if !strings.HasPrefix(line, "*") {
+		if strings.HasPrefix(line, "-") {
+			return nil, fmt.Errorf("%s failed: %s", name, line[1:])
+		}
+		return nil, fmt.Errorf("unexpected reply %q to %s", line, name)
+	}
+	size, err := strconv.Atoi(line[1:])
+	if err != nil || size < 0 {
+		return nil, fmt.Errorf("unexpected reply %q to %s", line, name)
+	}
+	elements := make([]string, size)
+	for i := range elements {
+		if elements[i], err = reply(r, name); err != nil {
+			return nil, err
+		}
+	}
+	return elements, nil
+}
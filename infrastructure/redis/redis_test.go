@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// serve starts a server replying to the commands with the replies keyed by their name, returning its address
+func serve(t *testing.T, replies map[string]string) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			// every command is an array of bulk strings, its name being the first one
+			header, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			var args []string
+			for i := 0; i < int(header[1]-'0'); i++ {
+				r.ReadString('\n')
+				arg, _ := r.ReadString('\n')
+				args = append(args, strings.TrimSuffix(arg, "\r\n"))
+			}
+			conn.Write([]byte(replies[args[0]]))
+		}
+	}()
+	return l.Addr().String()
+}
+
+// TestPing_Ok checks that Ping authenticates, pings the server and returns its version
+func TestPing_Ok(t *testing.T) {
+	// Arrange
+	info := "# Server\r\nredis_version:7.2.4\r\n"
+	address := serve(t, map[string]string{
+		"AUTH": "+OK\r\n",
+		"PING": "+PONG\r\n",
+		"INFO": fmt.Sprintf("$%d\r\n%s\r\n", len(info), info),
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	detail, err := Ping(ctx, address, "test-password")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "version 7.2.4", detail)
+}
+
+// TestPing_AuthError checks that Ping returns the error replied by the server
+func TestPing_AuthError(t *testing.T) {
+	// Arrange
+	address := serve(t, map[string]string{
+		"PING": "-NOAUTH Authentication required.\r\n",
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	_, err := Ping(ctx, address, "")
+
+	// Assert
+	assert.Equal(t, "PING failed: NOAUTH Authentication required.", err.Error())
+}
+
+// TestPing_NotReachable checks that Ping returns an error when the server cannot be reached
+func TestPing_NotReachable(t *testing.T) {
+	// Arrange
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	l.Close()
+
+	// Act
+	_, err = Ping(context.Background(), address, "")
+
+	// Assert
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,62 @@
+package reporting
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// sentryReporter adapter of an error reporter sending the errors to Sentry
+type sentryReporter struct {
+	hub *sentry.Hub
+}
+
+// NewSentryReporter creates an error reporter sending the errors, as events, to the Sentry project of the DSN,
+// tagged with the environment and the release. The sample rate, from 0 to 1, is the ratio of the errors sent.
+func NewSentryReporter(dsn, environment, release string, sampleRate float64) (ports.ErrorReporter, error) {
+	if sampleRate <= 0 || sampleRate > 1 {
+		return nil, fmt.Errorf("sample rate %v not valid, it must be greater than 0 and up to 1", sampleRate)
+	}
+	return newSentryReporter(sentry.ClientOptions{
+		Dsn:         dsn,
+		Environment: environment,
+		Release:     release,
+		SampleRate:  sampleRate,
+	})
+}
+
+func newSentryReporter(options sentry.ClientOptions) (ports.ErrorReporter, error) {
+	client, err := sentry.NewClient(options)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the Sentry client: %w", err)
+	}
+	return &sentryReporter{
+		hub: sentry.NewHub(client, sentry.NewScope()),
+	}, nil
+}
+
+// Report sends the error without waiting for it to be delivered
+func (r *sentryReporter) Report(report ports.ErrorReport) {
+	event := sentry.NewEvent()
+	event.Level = sentry.LevelError
+	event.Message = report.Message
+	if report.Error != "" {
+		event.Exception = []sentry.Exception{{Type: report.Message, Value: report.Error}}
+	}
+	if report.UserID != "" {
+		event.User = sentry.User{ID: report.UserID}
+	}
+	if report.Stack != "" {
+		event.Extra = map[string]interface{}{"stack": report.Stack}
+	}
+	event.Tags = report.Tags
+
+	r.hub.CaptureEvent(event)
+}
+
+// Flush waits for the reported errors to be delivered until the timeout is reached, returning false when they are not
+func (r *sentryReporter) Flush(timeout time.Duration) bool {
+	return r.hub.Flush(timeout)
+}
@@ -0,0 +1,86 @@
+package reporting
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorderTransport records the events sent to Sentry
+type recorderTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *recorderTransport) Flush(timeout time.Duration) bool { return true }
+
+func (t *recorderTransport) Configure(options sentry.ClientOptions) {}
+
+func (t *recorderTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+// TestNewSentryReporter_InvalidSampleRate checks that NewSentryReporter returns an error when the sample rate is not greater than 0 and up to 1
+func TestNewSentryReporter_InvalidSampleRate(t *testing.T) {
+	// Arrange
+	expectedError := "sample rate 0 not valid, it must be greater than 0 and up to 1"
+
+	// Act
+	_, err := NewSentryReporter("", "test", "test-version", 0)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestNewSentryReporter_InvalidDSN checks that NewSentryReporter returns an error when the DSN is not valid
+func TestNewSentryReporter_InvalidDSN(t *testing.T) {
+	// Act
+	_, err := NewSentryReporter("not-a-dsn", "test", "test-version", 1)
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+// TestReport_Ok checks that Report sends the error as an event with its user, stack, tags, environment and release
+func TestReport_Ok(t *testing.T) {
+	// Arrange
+	transport := &recorderTransport{}
+	reporter, err := newSentryReporter(sentry.ClientOptions{
+		Dsn:         "https://key@sentry.test/1",
+		Environment: "test",
+		Release:     "test-version",
+		Transport:   transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := ports.ErrorReport{
+		Message: "job failed",
+		Error:   "test error",
+		UserID:  "test-id",
+		Stack:   "test stack",
+		Tags:    map[string]string{"job_id": "test-job"},
+	}
+
+	// Act
+	reporter.Report(report)
+
+	// Assert
+	assert.True(t, reporter.Flush(time.Second))
+	assert.Len(t, transport.events, 1)
+	event := transport.events[0]
+	assert.Equal(t, sentry.LevelError, event.Level)
+	assert.Equal(t, report.Message, event.Message)
+	assert.Equal(t, []sentry.Exception{{Type: report.Message, Value: report.Error}}, event.Exception)
+	assert.Equal(t, report.UserID, event.User.ID)
+	assert.Equal(t, "test-job", event.Tags["job_id"])
+	assert.Equal(t, report.Stack, event.Extra["stack"])
+	assert.Equal(t, "test", event.Environment)
+	assert.Equal(t, "test-version", event.Release)
+}
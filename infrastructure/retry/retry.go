@@ -0,0 +1,75 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// defaultInitialBackoff is the wait after the first failed attempt when the policy does not set it
+const defaultInitialBackoff = time.Second
+
+// Policy retries an operation with exponential backoff until it succeeds or the deadline is reached
+type Policy struct {
+	// Timeout is the deadline of all the attempts, zero means a single attempt
+	Timeout time.Duration
+	// AttemptTimeout bounds every attempt, zero means that only the deadline bounds it
+	AttemptTimeout time.Duration
+	// InitialBackoff is the wait after the first failed attempt, doubled after every other failure, one second when zero
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts, zero means no cap
+	MaxBackoff time.Duration
+	// Logger logs the failed attempts that are retried
+	Logger zerolog.Logger
+}
+
+// Do runs the operation until it succeeds, the deadline is reached or the context is done, returning the last error of the operation in the last two cases
+func (p Policy) Do(ctx context.Context, name string, operation func(ctx context.Context) error) error {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	backoff := p.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultInitialBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		err := p.attempt(ctx, operation)
+		if err == nil {
+			return nil
+		}
+		if p.Timeout <= 0 {
+			return err
+		}
+
+		deadline, _ := ctx.Deadline()
+		if ctx.Err() != nil || time.Until(deadline) <= backoff {
+			return fmt.Errorf("%s not succeeded after %d attempts: %w", name, attempt, err)
+		}
+
+		p.Logger.Warn().Err(err).Int("attempt", attempt).Dur("backoff", backoff).Msgf("%s failed, retrying", name)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("%s not succeeded after %d attempts: %w", name, attempt, err)
+		}
+
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+}
+
+func (p Policy) attempt(ctx context.Context, operation func(ctx context.Context) error) error {
+	if p.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.AttemptTimeout)
+		defer cancel()
+	}
+	return operation(ctx)
+}
@@ -0,0 +1,105 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDo_Ok checks that Do retries a failing operation until it succeeds
+func TestDo_Ok(t *testing.T) {
+	// Arrange
+	p := Policy{Timeout: time.Second, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	attempts := 0
+	operation := func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("not reachable")
+		}
+		return nil
+	}
+
+	// Act
+	err := p.Do(context.Background(), "test operation", operation)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+// TestDo_DeadlineReached checks that Do returns the last error of the operation when the deadline is reached
+func TestDo_DeadlineReached(t *testing.T) {
+	// Arrange
+	p := Policy{Timeout: 50 * time.Millisecond, InitialBackoff: 10 * time.Millisecond}
+	operation := func(ctx context.Context) error {
+		return errors.New("not reachable")
+	}
+
+	// Act
+	err := p.Do(context.Background(), "test operation", operation)
+
+	// Assert
+	assert.ErrorContains(t, err, "test operation not succeeded after")
+	assert.ErrorContains(t, err, "not reachable")
+}
+
+// TestDo_NoTimeout checks that Do runs the operation only once when the policy has no timeout
+func TestDo_NoTimeout(t *testing.T) {
+	// Arrange
+	p := Policy{InitialBackoff: time.Millisecond}
+	attempts := 0
+	expectedErr := errors.New("not reachable")
+	operation := func(ctx context.Context) error {
+		attempts++
+		return expectedErr
+	}
+
+	// Act
+	err := p.Do(context.Background(), "test operation", operation)
+
+	// Assert
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, 1, attempts)
+}
+
+// TestDo_AttemptTimeout checks that every attempt is bounded by the attempt timeout
+func TestDo_AttemptTimeout(t *testing.T) {
+	// Arrange
+	p := Policy{Timeout: time.Second, AttemptTimeout: 10 * time.Millisecond, InitialBackoff: time.Millisecond}
+	attempts := 0
+	operation := func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}
+
+	// Act
+	err := p.Do(context.Background(), "test operation", operation)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+// TestDo_ContextCancelled checks that Do stops retrying when the context is cancelled
+func TestDo_ContextCancelled(t *testing.T) {
+	// Arrange
+	p := Policy{Timeout: time.Minute, InitialBackoff: time.Minute}
+	ctx, cancel := context.WithCancel(context.Background())
+	operation := func(ctx context.Context) error {
+		cancel()
+		return errors.New("not reachable")
+	}
+
+	// Act
+	err := p.Do(ctx, "test operation", operation)
+
+	// Assert
+	assert.ErrorContains(t, err, "test operation not succeeded after 1 attempts")
+}
@@ -0,0 +1,364 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/awsauth"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// defaultPartSize is the size of the parts of the multipart uploads, the files up to it being uploaded at once
+const defaultPartSize = 8 << 20
+
+// abortTimeout is the time given to abort a failed multipart upload
+const abortTimeout = 30 * time.Second
+
+// headers of the metadata of the objects, the name of the file and its metadata as JSON, both query-escaped as they must be ASCII
+const (
+	headerName     = "X-Amz-Meta-Name"
+	headerMetadata = "X-Amz-Meta-Metadata"
+)
+
+// fileStorage adapter of a file storage for Amazon S3 and the S3 compatible services, like MinIO, signing the requests with Signature Version 4.
+// The key of the file, headed by the prefix, is used as object key.
+type fileStorage struct {
+	endpoint    *url.URL
+	region      string
+	bucket      string
+	prefix      string
+	pathStyle   bool
+	credentials awsauth.Credentials
+	client      *http.Client
+	now         func() time.Time
+	partSize    int
+}
+
+// s3Error the error returned by S3
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// NewFileStorage creates a file storage for the bucket of S3 in the given region, at the given endpoint when set, like http://localhost:9000 for MinIO,
+// addressing the bucket in the path instead of the host when pathStyle is set, as most S3 compatible services require it
+func NewFileStorage(endpoint, region, bucket, prefix string, pathStyle bool, credentials awsauth.Credentials, client *http.Client) (ports.FileStorage, error) {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint %s not valid", endpoint)
+	}
+	return &fileStorage{
+		endpoint:    u,
+		region:      region,
+		bucket:      bucket,
+		prefix:      prefix,
+		pathStyle:   pathStyle,
+		credentials: credentials,
+		client:      client,
+		now:         time.Now,
+		partSize:    defaultPartSize,
+	}, nil
+}
+
+// NewHealthChecker creates a health checker reading the bucket of the file storage
+func NewHealthChecker(storage ports.FileStorage) ports.HealthChecker {
+	return ports.HealthCheckerFunc(func(ctx context.Context) error {
+		s, ok := storage.(*fileStorage)
+		if !ok {
+			return errors.New("not an s3 file storage")
+		}
+		resp, err := s.do(ctx, http.MethodHead, s.url("", nil), nil, nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	})
+}
+
+// Store uploads the file at once when up to the part size, or in parts of it otherwise, replacing the previous one with the same key
+func (s *fileStorage) Store(ctx context.Context, file entities.File, content io.Reader) (entities.File, error) {
+	headers, err := s.headers(file)
+	if err != nil {
+		return entities.File{}, err
+	}
+
+	part, err := s.readPart(content)
+	if err != nil {
+		return entities.File{}, err
+	}
+	if len(part) < s.partSize {
+		resp, err := s.do(ctx, http.MethodPut, s.url(file.Key, nil), headers, part)
+		if err != nil {
+			return entities.File{}, err
+		}
+		resp.Body.Close()
+	} else if err := s.storeParts(ctx, file.Key, headers, part, content); err != nil {
+		return entities.File{}, err
+	}
+
+	return s.Stat(ctx, file.Key)
+}
+
+func (s *fileStorage) Open(ctx context.Context, key string) (io.ReadCloser, entities.File, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.url(key, nil), nil, nil)
+	if err != nil {
+		return nil, entities.File{}, err
+	}
+	file, err := toFile(key, resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, entities.File{}, err
+	}
+	return resp.Body, file, nil
+}
+
+func (s *fileStorage) Stat(ctx context.Context, key string) (entities.File, error) {
+	resp, err := s.do(ctx, http.MethodHead, s.url(key, nil), nil, nil)
+	if err != nil {
+		return entities.File{}, err
+	}
+	resp.Body.Close()
+	return toFile(key, resp)
+}
+
+// Delete deletes the file, failing when it does not exist, as S3 deletes the missing objects without failing
+func (s *fileStorage) Delete(ctx context.Context, key string) error {
+	if _, err := s.Stat(ctx, key); err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodDelete, s.url(key, nil), nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// PresignGet returns a URL downloading the file, valid for expires
+func (s *fileStorage) PresignGet(ctx context.Context, key string, expires time.Duration) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url(key, nil).String(), nil)
+	if err != nil {
+		return "", err
+	}
+	return awsauth.PresignV4(req, "s3", s.region, s.credentials, s.now(), expires), nil
+}
+
+// PresignPut returns a URL uploading the file, valid for expires, and the headers to upload it with: its content type,
+// its metadata and its size, so no other content can be uploaded with it
+func (s *fileStorage) PresignPut(ctx context.Context, file entities.File, expires time.Duration) (string, map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.url(file.Key, nil).String(), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	headers, err := s.headers(file)
+	if err != nil {
+		return "", nil, err
+	}
+	headers["Content-Length"] = strconv.FormatInt(file.Size, 10)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return awsauth.PresignV4(req, "s3", s.region, s.credentials, s.now(), expires), headers, nil
+}
+
+// storeParts uploads the file in parts with a multipart upload, starting with the part already read, aborting it when any part fails
+func (s *fileStorage) storeParts(ctx context.Context, key string, headers map[string]string, part []byte, content io.Reader) (err error) {
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := s.doXML(ctx, http.MethodPost, s.url(key, url.Values{"uploads": {""}}), headers, nil, &initiated); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			// the upload is aborted even when the context is done, so its parts are not kept
+			abortCtx, cancel := context.WithTimeout(context.Background(), abortTimeout)
+			defer cancel()
+			resp, abortErr := s.do(abortCtx, http.MethodDelete, s.url(key, url.Values{"uploadId": {initiated.UploadID}}), nil, nil)
+			if abortErr == nil {
+				resp.Body.Close()
+			}
+		}
+	}()
+
+	type completedPart struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+	var completed struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}
+	for number := 1; len(part) > 0; number++ {
+		query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {initiated.UploadID}}
+		resp, err := s.do(ctx, http.MethodPut, s.url(key, query), nil, part)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		completed.Parts = append(completed.Parts, completedPart{PartNumber: number, ETag: resp.Header.Get("ETag")})
+
+		if part, err = s.readPart(content); err != nil {
+			return err
+		}
+	}
+
+	body, err := xml.Marshal(completed)
+	if err != nil {
+		return err
+	}
+	// the completion can fail after responding with a 200, reporting the error in the body instead
+	var result struct {
+		s3Error
+		XMLName xml.Name
+	}
+	if err := s.doXML(ctx, http.MethodPost, s.url(key, url.Values{"uploadId": {initiated.UploadID}}), nil, body, &result); err != nil {
+		return err
+	}
+	if result.XMLName.Local == "Error" {
+		return fmt.Errorf("s3 multipart upload of %s failed: %s %s", key, result.Code, result.Message)
+	}
+	return nil
+}
+
+// readPart reads up to a part of the content, the part being shorter only when the content is over
+func (s *fileStorage) readPart(content io.Reader) ([]byte, error) {
+	part := make([]byte, s.partSize)
+	n, err := io.ReadFull(content, part)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	return part[:n], nil
+}
+
+// headers returns the headers of the object of the file: its content type and its metadata
+func (s *fileStorage) headers(file entities.File) (map[string]string, error) {
+	metadata, err := json.Marshal(file.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return map[string]string{
+		"Content-Type": contentType,
+		headerName:     url.QueryEscape(file.Name),
+		headerMetadata: url.QueryEscape(string(metadata)),
+	}, nil
+}
+
+// url returns the URL of the object of the key with the given query, or of the bucket when the key is empty
+func (s *fileStorage) url(key string, query url.Values) *url.URL {
+	u := *s.endpoint
+	path := ""
+	if key != "" {
+		path = "/" + s.prefix + key
+	}
+	if s.pathStyle {
+		path = "/" + s.bucket + path
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsauth.Escape(segment)
+	}
+	u.Path, u.RawPath = path, strings.Join(segments, "/")
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+// do sends the signed request, returning the response when succeeded, a NonExistentErr when not found, and the error of S3 otherwise
+func (s *fileStorage) do(ctx context.Context, method string, u *url.URL, headers map[string]string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	sum := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	awsauth.SignV4(req, body, "s3", s.region, s.credentials, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusMultipleChoices {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	// the responses to HEAD have no body, so their error is only the status
+	var s3Err s3Error
+	xml.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&s3Err)
+	err = fmt.Errorf("s3 %s %s responded with status %d", method, u.Path, resp.StatusCode)
+	if s3Err.Code != "" {
+		err = fmt.Errorf("%w: %s %s", err, s3Err.Code, s3Err.Message)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, wrappers.NewNonExistentErr(err)
+	}
+	return nil, err
+}
+
+// doXML sends the signed request and decodes its XML response into v
+func (s *fileStorage) doXML(ctx context.Context, method string, u *url.URL, headers map[string]string, body []byte, v interface{}) error {
+	resp, err := s.do(ctx, method, u, headers, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return xml.NewDecoder(resp.Body).Decode(v)
+}
+
+// toFile returns the file of the key from the headers of the response to a HEAD or GET of its object
+func toFile(key string, resp *http.Response) (entities.File, error) {
+	file := entities.File{
+		Key:         key,
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
+	}
+	if lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		file.CreatedAt = lastModified.UTC()
+	}
+
+	var err error
+	if file.Name, err = url.QueryUnescape(resp.Header.Get(headerName)); err != nil {
+		return entities.File{}, fmt.Errorf("s3 object %s name not valid: %w", key, err)
+	}
+	if metadata := resp.Header.Get(headerMetadata); metadata != "" {
+		unescaped, err := url.QueryUnescape(metadata)
+		if err != nil {
+			return entities.File{}, fmt.Errorf("s3 object %s metadata not valid: %w", key, err)
+		}
+		if err := json.Unmarshal([]byte(unescaped), &file.Metadata); err != nil {
+			return entities.File{}, fmt.Errorf("s3 object %s metadata not valid: %w", key, err)
+		}
+	}
+	return file, nil
+}
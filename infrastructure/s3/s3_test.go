@@ -0,0 +1,259 @@
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/awsauth"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 in-memory S3 serving the path style requests to a bucket, keeping the headers of the objects and the parts of the multipart uploads
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	uploads map[string]map[string][]byte
+}
+
+// fakeObject an object of the fake S3
+type fakeObject struct {
+	header http.Header
+	body   []byte
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{objects: map[string]fakeObject{}, uploads: map[string]map[string][]byte{}}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-key/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	key := r.URL.Path
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		f.uploads["1"] = map[string][]byte{}
+		f.objects[key] = fakeObject{header: r.Header}
+		io.WriteString(w, "<InitiateMultipartUploadResult><UploadId>1</UploadId></InitiateMultipartUploadResult>")
+	case r.Method == http.MethodPut && query.Has("partNumber"):
+		f.uploads[query.Get("uploadId")][query.Get("partNumber")] = body
+		w.Header().Set("ETag", `"`+query.Get("partNumber")+`"`)
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		var completed struct {
+			Parts []struct {
+				PartNumber string `xml:"PartNumber"`
+			} `xml:"Part"`
+		}
+		xml.Unmarshal(body, &completed)
+		var content []byte
+		for _, part := range completed.Parts {
+			content = append(content, f.uploads[query.Get("uploadId")][part.PartNumber]...)
+		}
+		f.objects[key] = fakeObject{header: f.objects[key].header, body: content}
+		io.WriteString(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+	case r.Method == http.MethodPut:
+		f.objects[key] = fakeObject{header: r.Header, body: body}
+	case r.Method == http.MethodHead && key == "/bucket":
+	case r.Method == http.MethodHead, r.Method == http.MethodGet:
+		object, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>")
+			return
+		}
+		for _, name := range []string{"Content-Type", headerName, headerMetadata} {
+			w.Header().Set(name, object.header.Get(name))
+		}
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2026 07:28:00 GMT")
+		w.Write(object.body)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newTestStorage(t *testing.T, server *httptest.Server) *fileStorage {
+	storage, err := NewFileStorage(server.URL, "us-east-1", "bucket", "test/", true, awsauth.Credentials{AccessKeyID: "test-key", SecretAccessKey: "test-secret"}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return storage.(*fileStorage)
+}
+
+// TestStore_Ok checks that Store uploads the file at once with its metadata, Open reading it back
+func TestStore_Ok(t *testing.T) {
+	// Arrange
+	_, server := newFakeS3(t)
+	storage := newTestStorage(t, server)
+	file := entities.File{Key: "avatars/1", Name: "foto ñ.png", ContentType: "image/png", Metadata: map[string]string{"user_id": "1"}}
+
+	// Act
+	stored, err := storage.Store(context.Background(), file, strings.NewReader("content"))
+
+	// Assert
+	assert.Nil(t, err)
+	content, opened, openErr := storage.Open(context.Background(), "avatars/1")
+	assert.Nil(t, openErr)
+	defer content.Close()
+	body, _ := io.ReadAll(content)
+	assert.Equal(t, "content", string(body))
+	expectedFile := entities.File{Key: "avatars/1", Name: "foto ñ.png", ContentType: "image/png", Size: 7, Metadata: map[string]string{"user_id": "1"}, CreatedAt: time.Date(2026, 10, 21, 7, 28, 0, 0, time.UTC)}
+	assert.Equal(t, expectedFile, stored)
+	assert.Equal(t, expectedFile, opened)
+}
+
+// TestStore_Multipart checks that Store uploads the files larger than the part size in parts
+func TestStore_Multipart(t *testing.T) {
+	// Arrange
+	fake, server := newFakeS3(t)
+	storage := newTestStorage(t, server)
+	storage.partSize = 4
+
+	// Act
+	stored, err := storage.Store(context.Background(), entities.File{Key: "backups/users"}, strings.NewReader("0123456789"))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), stored.Size)
+	assert.Equal(t, "0123456789", string(fake.objects["/bucket/test/backups/users"].body))
+	parts := make([]string, 0, len(fake.uploads["1"]))
+	for number := range fake.uploads["1"] {
+		parts = append(parts, number)
+	}
+	sort.Strings(parts)
+	assert.Equal(t, []string{"1", "2", "3"}, parts)
+}
+
+// TestStat_NotFound checks that Stat returns a NonExistentErr when the file does not exist
+func TestStat_NotFound(t *testing.T) {
+	// Arrange
+	_, server := newFakeS3(t)
+	storage := newTestStorage(t, server)
+
+	// Act
+	_, err := storage.Stat(context.Background(), "avatars/1")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestOpen_NotFound checks that Open returns a NonExistentErr with the error of S3 when the file does not exist
+func TestOpen_NotFound(t *testing.T) {
+	// Arrange
+	_, server := newFakeS3(t)
+	storage := newTestStorage(t, server)
+
+	// Act
+	_, _, err := storage.Open(context.Background(), "avatars/1")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.ErrorContains(t, err, "s3 GET /bucket/test/avatars/1 responded with status 404: NoSuchKey The specified key does not exist.")
+}
+
+// TestDelete_NotFound checks that Delete returns a NonExistentErr when the file does not exist
+func TestDelete_NotFound(t *testing.T) {
+	// Arrange
+	_, server := newFakeS3(t)
+	storage := newTestStorage(t, server)
+
+	// Act
+	err := storage.Delete(context.Background(), "avatars/1")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestDelete_Ok checks that Delete deletes the file
+func TestDelete_Ok(t *testing.T) {
+	// Arrange
+	fake, server := newFakeS3(t)
+	storage := newTestStorage(t, server)
+	fake.objects["/bucket/test/avatars/1"] = fakeObject{header: http.Header{}}
+
+	// Act
+	err := storage.Delete(context.Background(), "avatars/1")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, fake.objects)
+}
+
+// TestPresignPut_Ok checks that PresignPut returns a URL of the object signing the headers to upload it with, including its size
+func TestPresignPut_Ok(t *testing.T) {
+	// Arrange
+	_, server := newFakeS3(t)
+	storage := newTestStorage(t, server)
+
+	// Act
+	presigned, headers, err := storage.PresignPut(context.Background(), entities.File{Key: "avatars/1", Name: "a.png", ContentType: "image/png", Size: 10}, 15*time.Minute)
+
+	// Assert
+	assert.Nil(t, err)
+	u, _ := url.Parse(presigned)
+	assert.Equal(t, "/bucket/test/avatars/1", u.Path)
+	assert.Equal(t, "900", u.Query().Get("X-Amz-Expires"))
+	assert.Equal(t, "content-length;content-type;host;x-amz-meta-metadata;x-amz-meta-name", u.Query().Get("X-Amz-SignedHeaders"))
+	assert.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+	assert.Equal(t, map[string]string{"Content-Type": "image/png", "Content-Length": "10", headerName: "a.png", headerMetadata: "null"}, headers)
+}
+
+// TestPresignGet_VirtualHosted checks that PresignGet addresses the bucket in the host when not path style
+func TestPresignGet_VirtualHosted(t *testing.T) {
+	// Arrange
+	storage, err := NewFileStorage("", "eu-west-1", "bucket", "", false, awsauth.Credentials{AccessKeyID: "test-key"}, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	presigned, err := storage.(ports.FilePresigner).PresignGet(context.Background(), "avatars/1", time.Minute)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(presigned, "https://bucket.s3.eu-west-1.amazonaws.com/avatars/1?X-Amz-Algorithm=AWS4-HMAC-SHA256"))
+}
+
+// TestHealthChecker_Ok checks that the health checker reads the bucket
+func TestHealthChecker_Ok(t *testing.T) {
+	// Arrange
+	_, server := newFakeS3(t)
+	storage := newTestStorage(t, server)
+
+	// Act
+	err := NewHealthChecker(storage).Check(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestNewFileStorage_NotValid checks that NewFileStorage returns an error when the endpoint is not an absolute URL
+func TestNewFileStorage_NotValid(t *testing.T) {
+	// Act
+	_, err := NewFileStorage("localhost", "us-east-1", "bucket", "", true, awsauth.Credentials{}, http.DefaultClient)
+
+	// Assert
+	assert.EqualError(t, err, "s3 endpoint localhost not valid")
+}
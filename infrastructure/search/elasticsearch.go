@@ -0,0 +1,239 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+)
+
+// facetSize is the maximum number of values returned by facet
+const facetSize = 10
+
+// indexMappings are the mappings of the index, created with it: the name, surnames and email are analyzed for full text queries,
+// while the domain and the claims are only matched as they are, for the filters and the facets
+const indexMappings = `{"mappings":{"properties":{` +
+	`"name":{"type":"text"},"surnames":{"type":"text"},"email":{"type":"text","analyzer":"simple"},` +
+	`"domain":{"type":"keyword"},"claims":{"type":"long"},"created_at":{"type":"date"},"updated_at":{"type":"date"}}}}`
+
+// elasticsearchIndex adapter of a search index for Elasticsearch, or OpenSearch, through their common REST API
+type elasticsearchIndex struct {
+	endpoint string
+	index    string
+	username string
+	password string
+	client   *http.Client
+	// created is set once the index is known to exist, so it is only created on the first document indexed
+	created atomic.Bool
+}
+
+// NewElasticsearchIndex creates a search index storing the documents in the given index of the cluster at the endpoint,
+// authenticated with the username and the password when set
+func NewElasticsearchIndex(endpoint, index, username, password string, client *http.Client) ports.SearchIndex {
+	return &elasticsearchIndex{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		index:    index,
+		username: username,
+		password: password,
+		client:   client,
+	}
+}
+
+// document is the source of a document of the index
+type document struct {
+	Name      string    `json:"name"`
+	Surnames  string    `json:"surnames"`
+	Email     string    `json:"email"`
+	Domain    string    `json:"domain"`
+	Claims    []int64   `json:"claims"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// searchResponse is the part of the response of a search read
+type searchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			ID     string   `json:"_id"`
+			Source document `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations map[string]struct {
+		Buckets []struct {
+			Key      json.RawMessage `json:"key"`
+			DocCount int64           `json:"doc_count"`
+		} `json:"buckets"`
+	} `json:"aggregations"`
+}
+
+// Index creates the index with its mappings when it does not exist yet, then puts the document under the ID of the user
+func (e *elasticsearchIndex) Index(ctx context.Context, doc ports.SearchDocument) error {
+	if !e.created.Load() {
+		if err := e.create(ctx); err != nil {
+			return err
+		}
+		e.created.Store(true)
+	}
+
+	body, err := json.Marshal(document{
+		Name:      doc.Name,
+		Surnames:  doc.Surnames,
+		Email:     doc.Email,
+		Domain:    doc.Domain,
+		Claims:    doc.Claims,
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = e.do(ctx, http.MethodPut, "/_doc/"+url.PathEscape(doc.ID), body)
+	return err
+}
+
+// Delete deletes the document of the user, a 404 meaning it was not indexed
+func (e *elasticsearchIndex) Delete(ctx context.Context, ID string) error {
+	_, err := e.do(ctx, http.MethodDelete, "/_doc/"+url.PathEscape(ID), nil, http.StatusNotFound)
+	return err
+}
+
+// Search matches the text against the name, the surnames and the email of the documents, tolerating typos, and aggregates the facets of the matches
+func (e *elasticsearchIndex) Search(ctx context.Context, query ports.SearchQuery) (ports.SearchResult, error) {
+	filters := []interface{}{}
+	if query.Domain != "" {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{ports.SearchFacetDomain: strings.ToLower(query.Domain)}})
+	}
+	if query.Claim != nil {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{ports.SearchFacetClaims: *query.Claim}})
+	}
+	if query.IDs != nil {
+		filters = append(filters, map[string]interface{}{"ids": map[string]interface{}{"values": query.IDs}})
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"from":             query.Skip,
+		"size":             query.Take,
+		"track_total_hits": true,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"multi_match": map[string]interface{}{
+						"query":     query.Text,
+						"fields":    []string{"name^2", "surnames^2", "email"},
+						"fuzziness": "AUTO",
+					},
+				},
+				"filter": filters,
+			},
+		},
+		"aggs": map[string]interface{}{
+			ports.SearchFacetDomain: map[string]interface{}{"terms": map[string]interface{}{"field": ports.SearchFacetDomain, "size": facetSize}},
+			ports.SearchFacetClaims: map[string]interface{}{"terms": map[string]interface{}{"field": ports.SearchFacetClaims, "size": facetSize}},
+		},
+	})
+	if err != nil {
+		return ports.SearchResult{}, err
+	}
+
+	respBody, err := e.do(ctx, http.MethodPost, "/_search", body)
+	if err != nil {
+		return ports.SearchResult{}, err
+	}
+	var resp searchResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return ports.SearchResult{}, err
+	}
+
+	result := ports.SearchResult{
+		Total:     resp.Hits.Total.Value,
+		Documents: make([]ports.SearchDocument, len(resp.Hits.Hits)),
+		Facets:    make(map[string][]ports.SearchFacet, len(resp.Aggregations)),
+	}
+	for i, hit := range resp.Hits.Hits {
+		result.Documents[i] = ports.SearchDocument{
+			ID:        hit.ID,
+			Name:      hit.Source.Name,
+			Surnames:  hit.Source.Surnames,
+			Email:     hit.Source.Email,
+			Domain:    hit.Source.Domain,
+			Claims:    hit.Source.Claims,
+			CreatedAt: hit.Source.CreatedAt,
+			UpdatedAt: hit.Source.UpdatedAt,
+		}
+	}
+	for name, aggregation := range resp.Aggregations {
+		facets := make([]ports.SearchFacet, len(aggregation.Buckets))
+		for i, bucket := range aggregation.Buckets {
+			facets[i] = ports.SearchFacet{Value: bucketKey(bucket.Key), Count: bucket.DocCount}
+		}
+		result.Facets[name] = facets
+	}
+	return result, nil
+}
+
+// create creates the index with its mappings, an index already existing being left as it is
+func (e *elasticsearchIndex) create(ctx context.Context) error {
+	_, err := e.do(ctx, http.MethodPut, "", []byte(indexMappings))
+	if err != nil && strings.Contains(err.Error(), "resource_already_exists_exception") {
+		return nil
+	}
+	return err
+}
+
+// do sends a request to the path of the index, returning the body of the response unless its status is not a 2xx or any of the ones accepted
+func (e *elasticsearchIndex) do(ctx context.Context, method, path string, body []byte, accepted ...int) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, e.endpoint+"/"+url.PathEscape(e.index)+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if e.username != "" {
+		req.SetBasicAuth(e.username, e.password)
+	}
+	tracing.InjectHeader(ctx, req.Header)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 == 2 {
+		return respBody, nil
+	}
+	for _, status := range accepted {
+		if resp.StatusCode == status {
+			return respBody, nil
+		}
+	}
+	if len(respBody) > 512 {
+		respBody = respBody[:512]
+	}
+	return nil, fmt.Errorf("search index responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+// bucketKey returns the key of a bucket of a terms aggregation as a string, a string for the keywords and a number for the longs
+func bucketKey(key json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(key, &s); err == nil {
+		return s
+	}
+	return string(key)
+}
@@ -0,0 +1,137 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestElasticsearchIndex_Ok checks that Index creates the index with its mappings on the first document, then puts the documents under the ID of their user
+func TestElasticsearchIndex_Ok(t *testing.T) {
+	// Arrange
+	var requests []string
+	var source map[string]interface{}
+	var username, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		username, password, _ = r.BasicAuth()
+		if r.URL.Path == "/users/_doc/test-id" {
+			json.NewDecoder(r.Body).Decode(&source)
+		}
+		w.Write([]byte(`{"acknowledged":true}`))
+	}))
+	defer server.Close()
+
+	index := NewElasticsearchIndex(server.URL, "users", "elastic", "test-password", server.Client())
+	document := ports.SearchDocument{ID: "test-id", Name: "test", Email: "test@example.com", Domain: "example.com", Claims: []int64{1}, CreatedAt: time.Date(2023, 8, 20, 9, 0, 0, 0, time.UTC)}
+
+	// Act
+	err := index.Index(context.Background(), document)
+	secondErr := index.Index(context.Background(), document)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, secondErr)
+	assert.Equal(t, []string{"PUT /users", "PUT /users/_doc/test-id", "PUT /users/_doc/test-id"}, requests)
+	assert.Equal(t, "elastic", username)
+	assert.Equal(t, "test-password", password)
+	assert.Equal(t, "example.com", source["domain"])
+	assert.Equal(t, "2023-08-20T09:00:00Z", source["created_at"])
+}
+
+// TestElasticsearchIndex_AlreadyExists checks that Index puts the document when the index already exists
+func TestElasticsearchIndex_AlreadyExists(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"type":"resource_already_exists_exception"},"status":400}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	index := NewElasticsearchIndex(server.URL, "users", "", "", server.Client())
+
+	// Act
+	err := index.Index(context.Background(), ports.SearchDocument{ID: "test-id"})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestElasticsearchDelete_NotFound checks that Delete does not fail when the document was not indexed
+func TestElasticsearchDelete_NotFound(t *testing.T) {
+	// Arrange
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"result":"not_found"}`))
+	}))
+	defer server.Close()
+
+	index := NewElasticsearchIndex(server.URL, "users", "", "", server.Client())
+
+	// Act
+	err := index.Delete(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "DELETE /users/_doc/test-id", path)
+}
+
+// TestElasticsearchSearch_Ok checks that Search sends the query with its filters, the IDs included, and facets, returning the documents, the total and the facets of the matches
+func TestElasticsearchSearch_Ok(t *testing.T) {
+	// Arrange
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		query = string(body)
+		w.Write([]byte(`{"hits":{"total":{"value":12},"hits":[{"_id":"test-id","_source":{"name":"test","email":"test@example.com","domain":"example.com","claims":[1]}}]},` +
+			`"aggregations":{"domain":{"buckets":[{"key":"example.com","doc_count":12}]},"claims":{"buckets":[{"key":1,"doc_count":3}]}}}`))
+	}))
+	defer server.Close()
+
+	index := NewElasticsearchIndex(server.URL, "users", "", "", server.Client())
+	claim := int64(1)
+
+	// Act
+	result, err := index.Search(context.Background(), ports.SearchQuery{Text: "tets", Domain: "Example.com", Claim: &claim, IDs: []string{"test-id"}, Skip: 10, Take: 10})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(12), result.Total)
+	assert.Equal(t, []ports.SearchDocument{{ID: "test-id", Name: "test", Email: "test@example.com", Domain: "example.com", Claims: []int64{1}}}, result.Documents)
+	assert.Equal(t, []ports.SearchFacet{{Value: "example.com", Count: 12}}, result.Facets[ports.SearchFacetDomain])
+	assert.Equal(t, []ports.SearchFacet{{Value: "1", Count: 3}}, result.Facets[ports.SearchFacetClaims])
+	assert.Contains(t, query, `"from":10`)
+	assert.Contains(t, query, `"fuzziness":"AUTO"`)
+	assert.Contains(t, query, `{"term":{"domain":"example.com"}},{"term":{"claims":1}},{"ids":{"values":["test-id"]}}`)
+}
+
+// TestElasticsearchSearch_Error checks that Search returns an error when the cluster does not respond with a 2xx
+func TestElasticsearchSearch_Error(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("unauthorized"))
+	}))
+	defer server.Close()
+
+	index := NewElasticsearchIndex(server.URL, "users", "", "", server.Client())
+
+	// Act
+	_, err := index.Search(context.Background(), ports.SearchQuery{Text: "test", Take: 10})
+
+	// Assert
+	assert.EqualError(t, err, "search index responded with status 401: unauthorized")
+}
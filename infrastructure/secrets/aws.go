@@ -0,0 +1,158 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/awsauth"
+)
+
+// awsClient calls the JSON APIs of AWS, like the ones of Secrets Manager and Systems Manager, signing the requests with Signature Version 4
+type awsClient struct {
+	endpoint    string
+	region      string
+	service     string
+	credentials awsauth.Credentials
+	client      *http.Client
+	now         func() time.Time
+}
+
+// call calls the target operation, like secretsmanager.GetSecretValue, with the input, decoding its output into target
+func (c *awsClient) call(ctx context.Context, operation string, input, target interface{}) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", operation)
+	awsauth.SignV4(req, body, c.service, c.region, c.credentials, c.now())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	return json.Unmarshal(respBody, target)
+}
+
+// secretsManagerProvider fetches the secrets from a secret of AWS Secrets Manager holding them as a JSON object
+type secretsManagerProvider struct {
+	client   *awsClient
+	secretID string
+}
+
+// NewSecretsManagerProvider creates a Provider reading the current version of the secret with the given ID or ARN of AWS Secrets Manager
+// in the given region, authenticating with the standard AWS environment variables
+func NewSecretsManagerProvider(region, secretID string, client *http.Client) Provider {
+	return &secretsManagerProvider{
+		client: &awsClient{
+			endpoint:    fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region),
+			region:      region,
+			service:     "secretsmanager",
+			credentials: awsauth.EnvCredentials(),
+			client:      client,
+			now:         time.Now,
+		},
+		secretID: secretID,
+	}
+}
+
+// Secrets reads the current version of the secret, the values not being strings, like numbers, being returned as JSON
+func (p *secretsManagerProvider) Secrets(ctx context.Context) (map[string]string, error) {
+	var output struct {
+		SecretString string `json:"SecretString"`
+	}
+	err := p.client.call(ctx, "secretsmanager.GetSecretValue", map[string]string{"SecretId": p.secretID}, &output)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read aws secret %s: %w", p.secretID, err)
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(output.SecretString), &values); err != nil {
+		return nil, fmt.Errorf("aws secret %s not valid, it must be a JSON object: %w", p.secretID, err)
+	}
+	return stringValues(values)
+}
+
+// Renew does nothing, as the credentials of the environment are not renewed by the API
+func (p *secretsManagerProvider) Renew(ctx context.Context) error {
+	return nil
+}
+
+// parameterStoreProvider fetches the secrets from the parameters under a path of AWS Systems Manager Parameter Store
+type parameterStoreProvider struct {
+	client *awsClient
+	path   string
+}
+
+// NewParameterStoreProvider creates a Provider reading the parameters under the given path of AWS Systems Manager Parameter Store
+// in the given region, decrypting the secure strings, authenticating with the standard AWS environment variables.
+// The parameters are named after the settings they set, with slashes separating their sections, like /go-hexagonal-api/Alerting/SMTPPassword.
+func NewParameterStoreProvider(region, path string, client *http.Client) Provider {
+	return &parameterStoreProvider{
+		client: &awsClient{
+			endpoint:    fmt.Sprintf("https://ssm.%s.amazonaws.com", region),
+			region:      region,
+			service:     "ssm",
+			credentials: awsauth.EnvCredentials(),
+			client:      client,
+			now:         time.Now,
+		},
+		path: "/" + strings.Trim(path, "/") + "/",
+	}
+}
+
+// Secrets reads every parameter under the path, page by page
+func (p *parameterStoreProvider) Secrets(ctx context.Context) (map[string]string, error) {
+	secrets := make(map[string]string)
+	var nextToken string
+	for {
+		input := map[string]interface{}{"Path": p.path, "Recursive": true, "WithDecryption": true}
+		if nextToken != "" {
+			input["NextToken"] = nextToken
+		}
+		var output struct {
+			Parameters []struct {
+				Name  string `json:"Name"`
+				Value string `json:"Value"`
+			} `json:"Parameters"`
+			NextToken string `json:"NextToken"`
+		}
+		if err := p.client.call(ctx, "AmazonSSM.GetParametersByPath", input, &output); err != nil {
+			return nil, fmt.Errorf("cannot read aws parameters %s: %w", p.path, err)
+		}
+
+		for _, parameter := range output.Parameters {
+			key := strings.ReplaceAll(strings.TrimPrefix(parameter.Name, p.path), "/", ".")
+			secrets[key] = parameter.Value
+		}
+		if output.NextToken == "" {
+			return secrets, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// Renew does nothing, as the credentials of the environment are not renewed by the API
+func (p *parameterStoreProvider) Renew(ctx context.Context) error {
+	return nil
+}
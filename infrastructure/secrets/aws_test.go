@@ -0,0 +1,128 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/awsauth"
+	"github.com/stretchr/testify/assert"
+)
+
+// testAWSClient returns a client of the given server with fixed credentials and time
+func testAWSClient(server *httptest.Server, service string) *awsClient {
+	return &awsClient{
+		endpoint:    server.URL,
+		region:      "eu-west-1",
+		service:     service,
+		credentials: awsauth.Credentials{AccessKeyID: "test-key-id", SecretAccessKey: "test-secret-key", SessionToken: "test-session-token"},
+		client:      server.Client(),
+		now:         func() time.Time { return time.Date(2023, 7, 15, 9, 0, 0, 0, time.UTC) },
+	}
+}
+
+// TestSecretsManagerProvider_Secrets checks that Secrets reads the secret as a JSON object, signing the request with the session token
+func TestSecretsManagerProvider_Secrets(t *testing.T) {
+	// Arrange
+	var target, token, authorization string
+	var input map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.Header.Get("X-Amz-Target")
+		token = r.Header.Get("X-Amz-Security-Token")
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&input)
+		w.Write([]byte(`{"Name": "go-hexagonal-api", "SecretString": "{\"JWTSecret\": \"test-secret\", \"Hashing.Workers\": 4}"}`))
+	}))
+	defer server.Close()
+
+	p := &secretsManagerProvider{client: testAWSClient(server, "secretsmanager"), secretID: "go-hexagonal-api"}
+
+	// Act
+	secrets, err := p.Secrets(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "secretsmanager.GetSecretValue", target)
+	assert.Equal(t, map[string]string{"SecretId": "go-hexagonal-api"}, input)
+	assert.Equal(t, "test-session-token", token)
+	assert.Contains(t, authorization, "Credential=test-key-id/20230715/eu-west-1/secretsmanager/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target")
+	assert.Equal(t, map[string]string{"JWTSecret": "test-secret", "Hashing.Workers": "4"}, secrets)
+}
+
+// TestSecretsManagerProvider_NotObject checks that Secrets returns an error when the secret is not a JSON object
+func TestSecretsManagerProvider_NotObject(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"SecretString": "plain-secret"}`))
+	}))
+	defer server.Close()
+
+	p := &secretsManagerProvider{client: testAWSClient(server, "secretsmanager"), secretID: "go-hexagonal-api"}
+
+	// Act
+	_, err := p.Secrets(context.Background())
+
+	// Assert
+	assert.ErrorContains(t, err, "aws secret go-hexagonal-api not valid, it must be a JSON object")
+}
+
+// TestSecretsManagerProvider_Error checks that Secrets returns an error when AWS does not return the secret
+func TestSecretsManagerProvider_Error(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type": "ResourceNotFoundException"}`))
+	}))
+	defer server.Close()
+
+	p := &secretsManagerProvider{client: testAWSClient(server, "secretsmanager"), secretID: "go-hexagonal-api"}
+	expectedError := `cannot read aws secret go-hexagonal-api: unexpected status code 400: {"__type": "ResourceNotFoundException"}`
+
+	// Act
+	_, err := p.Secrets(context.Background())
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestParameterStoreProvider_Secrets checks that Secrets reads every parameter under the path, page by page, keyed by their setting
+func TestParameterStoreProvider_Secrets(t *testing.T) {
+	// Arrange
+	var inputs []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&input)
+		inputs = append(inputs, input)
+		if input["NextToken"] == nil {
+			w.Write([]byte(`{"Parameters": [{"Name": "/go-hexagonal-api/JWTSecret", "Value": "test-secret"}], "NextToken": "test-token"}`))
+			return
+		}
+		w.Write([]byte(`{"Parameters": [{"Name": "/go-hexagonal-api/Alerting/SMTPPassword", "Value": "test-password"}]}`))
+	}))
+	defer server.Close()
+
+	p := &parameterStoreProvider{client: testAWSClient(server, "ssm"), path: "/go-hexagonal-api/"}
+
+	// Act
+	secrets, err := p.Secrets(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, inputs, 2)
+	assert.Equal(t, map[string]interface{}{"Path": "/go-hexagonal-api/", "Recursive": true, "WithDecryption": true}, inputs[0])
+	assert.Equal(t, "test-token", inputs[1]["NextToken"])
+	assert.Equal(t, map[string]string{"JWTSecret": "test-secret", "Alerting.SMTPPassword": "test-password"}, secrets)
+}
+
+// TestNewParameterStoreProvider_Path checks that NewParameterStoreProvider reads the parameters under the path however its slashes are set
+func TestNewParameterStoreProvider_Path(t *testing.T) {
+	// Act
+	p := NewParameterStoreProvider("eu-west-1", "go-hexagonal-api/", http.DefaultClient).(*parameterStoreProvider)
+
+	// Assert
+	assert.Equal(t, "/go-hexagonal-api/", p.path)
+	assert.Equal(t, "https://ssm.eu-west-1.amazonaws.com", p.client.endpoint)
+}
@@ -0,0 +1,157 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Provider fetches the secrets of the API from a secrets manager, keyed by the settings they set, like JWTSecret, DSN or Alerting.SMTPPassword
+type Provider interface {
+	Secrets(ctx context.Context) (map[string]string, error)
+	// Renew extends the credentials of the provider, so they do not expire while the API runs
+	Renew(ctx context.Context) error
+}
+
+// NewProvider creates the Provider with the given name, caching its secrets for the given TTL, if any
+func NewProvider(name, vaultAddress, vaultToken, vaultMount, vaultPath, awsRegion, awsSecretID, awsParameterPath string, cacheTTL time.Duration) (Provider, error) {
+	var p Provider
+	switch name {
+	case "vault":
+		p = NewVaultProvider(vaultAddress, vaultToken, vaultMount, vaultPath, http.DefaultClient)
+	case "aws-secretsmanager":
+		p = NewSecretsManagerProvider(awsRegion, awsSecretID, http.DefaultClient)
+	case "aws-ssm":
+		p = NewParameterStoreProvider(awsRegion, awsParameterPath, http.DefaultClient)
+	default:
+		return nil, fmt.Errorf("secrets provider %s not valid", name)
+	}
+
+	if cacheTTL > 0 {
+		p = NewCachingProvider(p, cacheTTL)
+	}
+	return p, nil
+}
+
+// cachingProvider caches the secrets of a provider, so they are not fetched again until they are older than the TTL
+type cachingProvider struct {
+	Provider
+	ttl       time.Duration
+	mu        sync.Mutex
+	secrets   map[string]string
+	fetchedAt time.Time
+	now       func() time.Time
+}
+
+// NewCachingProvider creates a Provider caching the secrets of the given one for the given TTL.
+// When they cannot be fetched again, the cached ones are kept, so an outage of the secrets manager does not fail their readers.
+func NewCachingProvider(p Provider, ttl time.Duration) Provider {
+	return &cachingProvider{
+		Provider: p,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// Secrets returns the cached secrets, fetching them when older than the TTL
+func (p *cachingProvider) Secrets(ctx context.Context) (map[string]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.secrets != nil && p.now().Sub(p.fetchedAt) < p.ttl {
+		return p.secrets, nil
+	}
+
+	secrets, err := p.Provider.Secrets(ctx)
+	if err != nil {
+		if p.secrets != nil {
+			return p.secrets, nil
+		}
+		return nil, err
+	}
+	p.secrets = secrets
+	p.fetchedAt = p.now()
+	return secrets, nil
+}
+
+// KeepRenewed renews the credentials of the provider every interval until the context is done, logging the failures.
+// Every renewal is given until the next one to complete.
+// The secrets are then read again, logging as a warning the ones rotated since they were first read, without their values,
+// as they are only applied on restart.
+func KeepRenewed(ctx context.Context, p Provider, interval time.Duration, initial map[string]string, logger zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	reported := make(map[string]string, len(initial))
+	for k, v := range initial {
+		reported[k] = v
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		renewCtx, cancel := context.WithTimeout(ctx, interval)
+		err := p.Renew(renewCtx)
+		if err != nil {
+			cancel()
+			logger.Error().Err(err).Msg("secrets provider credentials cannot be renewed")
+			continue
+		}
+		secrets, err := p.Secrets(renewCtx)
+		cancel()
+		if err != nil {
+			logger.Error().Err(err).Msg("secrets cannot be read")
+			continue
+		}
+		logger.Debug().Msg("secrets provider credentials renewed")
+
+		if rotated := changedKeys(reported, secrets); len(rotated) > 0 {
+			logger.Warn().Strs("secrets", rotated).Msg("secrets rotated, restart to apply them")
+			reported = secrets
+		}
+	}
+}
+
+// changedKeys returns the sorted keys added, removed or changed from the old secrets to the new ones
+func changedKeys(old, new map[string]string) []string {
+	var keys []string
+	for k, v := range new {
+		if o, ok := old[k]; !ok || o != v {
+			keys = append(keys, k)
+		}
+	}
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stringValues returns the values of a JSON object as strings, the ones not being strings, like numbers, as JSON
+func stringValues(values map[string]interface{}) (map[string]string, error) {
+	secrets := make(map[string]string, len(values))
+	for key, value := range values {
+		if s, ok := value.(string); ok {
+			secrets[key] = s
+			continue
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		secrets[key] = string(b)
+	}
+	return secrets, nil
+}
@@ -0,0 +1,161 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testProvider provider counting its renewals and reads, failing them with the given errors
+type testProvider struct {
+	renewals   atomic.Int64
+	reads      atomic.Int64
+	err        error
+	secrets    map[string]string
+	secretsErr error
+}
+
+func (p *testProvider) Secrets(ctx context.Context) (map[string]string, error) {
+	p.reads.Add(1)
+	return p.secrets, p.secretsErr
+}
+
+func (p *testProvider) Renew(ctx context.Context) error {
+	p.renewals.Add(1)
+	return p.err
+}
+
+// TestKeepRenewed_Ok checks that KeepRenewed renews the credentials periodically, even after failing, until the context is done
+func TestKeepRenewed_Ok(t *testing.T) {
+	// Arrange
+	p := &testProvider{err: errors.New("renew-error")}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		KeepRenewed(ctx, p, time.Millisecond, nil, zerolog.Nop())
+		close(done)
+	}()
+
+	// Assert
+	assert.Eventually(t, func() bool { return p.renewals.Load() >= 2 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
+
+// TestKeepRenewed_Rotated checks that KeepRenewed logs as a warning the secrets rotated since they were first read, without their values
+func TestKeepRenewed_Rotated(t *testing.T) {
+	// Arrange
+	p := &testProvider{secrets: map[string]string{"JWTSecret": "new-secret", "DSN": "test-dsn"}}
+	initial := map[string]string{"JWTSecret": "old-secret", "DSN": "test-dsn"}
+	out := &syncBuffer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		KeepRenewed(ctx, p, time.Millisecond, initial, zerolog.New(out).Level(zerolog.WarnLevel))
+		close(done)
+	}()
+
+	// Assert
+	assert.Eventually(t, func() bool { return p.reads.Load() >= 2 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+	assert.Equal(t, `{"level":"warn","secrets":["JWTSecret"],"message":"secrets rotated, restart to apply them"}`+"\n", out.String())
+}
+
+// TestCachingProvider_Cached checks that Secrets returns the cached secrets until they are older than the TTL
+func TestCachingProvider_Cached(t *testing.T) {
+	// Arrange
+	p := &testProvider{secrets: map[string]string{"JWTSecret": "test-secret"}}
+	now := time.Now()
+	cp := NewCachingProvider(p, time.Minute).(*cachingProvider)
+	cp.now = func() time.Time { return now }
+
+	// Act
+	cp.Secrets(context.Background())
+	secrets, err := cp.Secrets(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, p.secrets, secrets)
+	assert.Equal(t, int64(1), p.reads.Load())
+}
+
+// TestCachingProvider_Expired checks that Secrets fetches the secrets again once they are older than the TTL
+func TestCachingProvider_Expired(t *testing.T) {
+	// Arrange
+	p := &testProvider{secrets: map[string]string{"JWTSecret": "test-secret"}}
+	now := time.Now()
+	cp := NewCachingProvider(p, time.Minute).(*cachingProvider)
+	cp.now = func() time.Time { return now }
+
+	// Act
+	cp.Secrets(context.Background())
+	now = now.Add(time.Minute)
+	secrets, err := cp.Secrets(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, p.secrets, secrets)
+	assert.Equal(t, int64(2), p.reads.Load())
+}
+
+// TestCachingProvider_Stale checks that Secrets keeps returning the cached secrets when they cannot be fetched again
+func TestCachingProvider_Stale(t *testing.T) {
+	// Arrange
+	p := &testProvider{secrets: map[string]string{"JWTSecret": "test-secret"}}
+	now := time.Now()
+	cp := NewCachingProvider(p, time.Minute).(*cachingProvider)
+	cp.now = func() time.Time { return now }
+
+	// Act
+	cp.Secrets(context.Background())
+	p.secrets, p.secretsErr = nil, errors.New("secrets-error")
+	now = now.Add(time.Minute)
+	secrets, err := cp.Secrets(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"JWTSecret": "test-secret"}, secrets)
+}
+
+// TestCachingProvider_Error checks that Secrets returns the error of the provider when there are no cached secrets
+func TestCachingProvider_Error(t *testing.T) {
+	// Arrange
+	p := &testProvider{secretsErr: errors.New("secrets-error")}
+	cp := NewCachingProvider(p, time.Minute)
+
+	// Act
+	_, err := cp.Secrets(context.Background())
+
+	// Assert
+	assert.Equal(t, p.secretsErr, err)
+}
+
+// syncBuffer buffer safe to write from a goroutine while read from another
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
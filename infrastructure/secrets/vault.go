@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// vaultProvider fetches the secrets from a key value version 2 secrets engine of HashiCorp Vault, authenticating with a token
+type vaultProvider struct {
+	address string
+	token   string
+	mount   string
+	path    string
+	client  *http.Client
+}
+
+// NewVaultProvider creates a Provider reading the secrets at the given path of the key value engine mounted at mount,
+// like secret and go-hexagonal-api, of the Vault server at address
+func NewVaultProvider(address, token, mount, path string, client *http.Client) Provider {
+	return &vaultProvider{
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+		mount:   strings.Trim(mount, "/"),
+		path:    strings.Trim(path, "/"),
+		client:  client,
+	}
+}
+
+// Secrets reads the latest version of the secret, the values not being strings, like numbers, being returned as JSON
+func (p *vaultProvider) Secrets(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s/data/%s", p.address, url.PathEscape(p.mount), p.path), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := p.do(req, &result); err != nil {
+		return nil, fmt.Errorf("cannot read vault secret %s/%s: %w", p.mount, p.path, err)
+	}
+
+	return stringValues(result.Data.Data)
+}
+
+// Renew renews the token, extending its lease by its increment
+func (p *vaultProvider) Renew(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.address+"/v1/auth/token/renew-self", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := p.do(req, &struct{}{}); err != nil {
+		return fmt.Errorf("cannot renew vault token: %w", err)
+	}
+	return nil
+}
+
+func (p *vaultProvider) do(req *http.Request, target interface{}) error {
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, target)
+}
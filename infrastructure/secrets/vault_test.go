@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewProvider_InvalidProvider checks that NewProvider returns an error when the provider is not supported
+func TestNewProvider_InvalidProvider(t *testing.T) {
+	// Arrange
+	expectedError := "secrets provider unknown not valid"
+
+	// Act
+	_, err := NewProvider("unknown", "", "", "", "", "", "", "", 0)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestVaultProvider_Secrets checks that Secrets reads the secret with the token, returning the values not being strings as JSON
+func TestVaultProvider_Secrets(t *testing.T) {
+	// Arrange
+	var path, token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		token = r.Header.Get("X-Vault-Token")
+		w.Write([]byte(`{"data": {"data": {"JWTSecret": "test-secret", "Hashing.Workers": 4}, "metadata": {"version": 2}}}`))
+	}))
+	defer server.Close()
+
+	p := NewVaultProvider(server.URL+"/", "test-token", "secret", "/go-hexagonal-api", server.Client())
+
+	// Act
+	secrets, err := p.Secrets(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "/v1/secret/data/go-hexagonal-api", path)
+	assert.Equal(t, "test-token", token)
+	assert.Equal(t, map[string]string{"JWTSecret": "test-secret", "Hashing.Workers": "4"}, secrets)
+}
+
+// TestVaultProvider_SecretsError checks that Secrets returns an error when Vault does not return the secret
+func TestVaultProvider_SecretsError(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors": ["permission denied"]}`))
+	}))
+	defer server.Close()
+
+	p := NewVaultProvider(server.URL, "test-token", "secret", "go-hexagonal-api", server.Client())
+	expectedError := `cannot read vault secret secret/go-hexagonal-api: unexpected status code 403: {"errors": ["permission denied"]}`
+
+	// Act
+	_, err := p.Secrets(context.Background())
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestVaultProvider_Renew checks that Renew renews the token
+func TestVaultProvider_Renew(t *testing.T) {
+	// Arrange
+	var method, path, token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		token = r.Header.Get("X-Vault-Token")
+		w.Write([]byte(`{"auth": {"lease_duration": 3600, "renewable": true}}`))
+	}))
+	defer server.Close()
+
+	p := NewVaultProvider(server.URL, "test-token", "secret", "go-hexagonal-api", server.Client())
+
+	// Act
+	err := p.Renew(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/v1/auth/token/renew-self", path)
+	assert.Equal(t, "test-token", token)
+}
@@ -0,0 +1,64 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// httpSink adapter of a SIEM sink posting the events to an HTTP collector, like the HTTP Event Collector of Splunk or a Logstash http input
+type httpSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPSink creates a sink posting the events to the URL, authenticated with the token as a bearer one when set
+func NewHTTPSink(URL, token string, client *http.Client) ports.SIEMSink {
+	return &httpSink{
+		url:    URL,
+		token:  token,
+		client: client,
+	}
+}
+
+// Send posts the events as a JSON array, shaped like the ones returned by the audit endpoints, any 2xx status accepting them
+func (s *httpSink) Send(ctx context.Context, events []entities.AuditEvent) error {
+	resp := make([]models.AuditEventResp, len(events))
+	for i, event := range events {
+		resp[i] = models.AuditEventResp(event)
+	}
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("siem endpoint responded with status %d: %s", res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package siem
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHTTPSend_Posted checks that Send posts the events as a JSON array authenticated with the token
+func TestHTTPSend_Posted(t *testing.T) {
+	// Arrange
+	var authorization string
+	var events []models.AuditEventResp
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&events)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	sink := NewHTTPSink(server.URL, "test-token", server.Client())
+
+	// Act
+	err := sink.Send(context.Background(), []entities.AuditEvent{testEvent()})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer test-token", authorization)
+	assert.Equal(t, []models.AuditEventResp{models.AuditEventResp(testEvent())}, events)
+}
+
+// TestHTTPSend_ErrorStatus checks that Send returns an error when the endpoint does not respond with a 2xx status
+func TestHTTPSend_ErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusForbidden)
+	}))
+	defer server.Close()
+	sink := NewHTTPSink(server.URL, "test-token", server.Client())
+
+	// Act
+	err := sink.Send(context.Background(), []entities.AuditEvent{testEvent()})
+
+	// Assert
+	assert.Equal(t, "siem endpoint responded with status 403: invalid token", err.Error())
+}
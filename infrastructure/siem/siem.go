@@ -0,0 +1,21 @@
+package siem
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// NewSink creates the SIEMSink of the given kind, syslog sending CEF messages to the syslog server at the address over the network,
+// or http posting the events as JSON to the URL with the token, the version of the API being reported in the CEF messages
+func NewSink(kind, syslogNetwork, syslogAddress, URL, token, version string) (ports.SIEMSink, error) {
+	switch kind {
+	case "syslog":
+		return NewSyslogSink(syslogNetwork, syslogAddress, version)
+	case "http":
+		return NewHTTPSink(URL, token, http.DefaultClient), nil
+	default:
+		return nil, fmt.Errorf("siem sink %s not valid", kind)
+	}
+}
@@ -0,0 +1,168 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// CEF header fields identifying the API as the device reporting the events
+const (
+	cefVendor  = "sergicanet9"
+	cefProduct = "go-hexagonal-api"
+)
+
+// syslog facility of the messages, security and authorization messages
+const facilityAuthPriv = 10
+
+// syslog severities of the messages
+const (
+	severityWarning = 4
+	severityNotice  = 5
+	severityInfo    = 6
+)
+
+// alertTypes types of the events signalling an attack rather than an operation, reported with the highest severity
+var alertTypes = map[string]bool{
+	entities.AuditLoginSuspicious: true,
+	entities.AuditUploadInfected:  true,
+}
+
+// syslogSink adapter of a SIEM sink sending the events as CEF messages to a syslog server, in RFC 5424 messages
+type syslogSink struct {
+	network  string
+	address  string
+	version  string
+	hostname string
+	dialer   net.Dialer
+	tls      *tls.Config
+}
+
+// NewSyslogSink creates a sink sending the messages to the syslog server at the address over the network: udp, a datagram per message,
+// or tcp and tls, framed by their length as of RFC 6587
+func NewSyslogSink(network, address, version string) (ports.SIEMSink, error) {
+	if network != "udp" && network != "tcp" && network != "tls" {
+		return nil, fmt.Errorf("syslog network %s not valid", network)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	return &syslogSink{
+		network:  network,
+		address:  address,
+		version:  version,
+		hostname: hostname,
+		tls:      &tls.Config{MinVersion: tls.VersionTLS12},
+	}, nil
+}
+
+// Send sends the events over a connection opened for the batch, so a server restarted meanwhile is reached again on the next one
+func (s *syslogSink) Send(ctx context.Context, events []entities.AuditEvent) error {
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("syslog server cannot be reached: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	var frames bytes.Buffer
+	for _, event := range events {
+		message := s.message(event)
+		if s.network == "udp" {
+			if _, err := conn.Write([]byte(message)); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprintf(&frames, "%d %s", len(message), message)
+	}
+	if frames.Len() > 0 {
+		_, err = conn.Write(frames.Bytes())
+	}
+	return err
+}
+
+// dial opens a connection to the syslog server
+func (s *syslogSink) dial(ctx context.Context) (net.Conn, error) {
+	if s.network == "tls" {
+		dialer := tls.Dialer{NetDialer: &s.dialer, Config: s.tls}
+		return dialer.DialContext(ctx, "tcp", s.address)
+	}
+	return s.dialer.DialContext(ctx, s.network, s.address)
+}
+
+// message formats the event as a RFC 5424 message whose content is a CEF event
+func (s *syslogSink) message(event entities.AuditEvent) string {
+	severity, cefSeverity := severityInfo, 3
+	switch {
+	case alertTypes[event.Type]:
+		severity, cefSeverity = severityWarning, 8
+	case event.Outcome == entities.AuditOutcomeFailure:
+		severity, cefSeverity = severityNotice, 5
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s - %s - %s",
+		facilityAuthPriv*8+severity,
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		s.hostname,
+		cefProduct,
+		event.Type,
+		s.cef(event, cefSeverity),
+	)
+}
+
+// cef formats the event as a CEF event, its type being its signature and name
+func (s *syslogSink) cef(event entities.AuditEvent, severity int) string {
+	extension := []string{"rt=" + strconv.FormatInt(event.CreatedAt.UnixMilli(), 10)}
+	add := func(key, value string) {
+		if value != "" {
+			extension = append(extension, key+"="+escapeExtension(value))
+		}
+	}
+	add("outcome", event.Outcome)
+	add("duid", event.UserID)
+	add("suid", event.ActorID)
+	add("src", event.IP)
+	add("externalId", event.ID)
+	if len(event.Details) > 0 {
+		// the keys of the details are sorted by the marshalling, so the same details are always formatted alike
+		details, _ := json.Marshal(event.Details)
+		add("cs1Label", "details")
+		add("cs1", string(details))
+	}
+
+	return fmt.Sprintf("CEF:0|%s|%s|%s|%s|%s|%d|%s",
+		escapeHeader(cefVendor),
+		escapeHeader(cefProduct),
+		escapeHeader(s.version),
+		escapeHeader(event.Type),
+		escapeHeader(event.Type),
+		severity,
+		strings.Join(extension, " "),
+	)
+}
+
+// escapeHeader escapes the backslashes and pipes of a CEF header field
+func escapeHeader(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ").Replace(value)
+}
+
+// escapeExtension escapes the backslashes, equal signs and line breaks of a CEF extension value
+func escapeExtension(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`).Replace(value)
+}
@@ -0,0 +1,141 @@
+package siem
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// testEvent returns a failed login of the test user
+func testEvent() entities.AuditEvent {
+	return entities.AuditEvent{
+		Type:      entities.AuditLoginFailed,
+		Outcome:   entities.AuditOutcomeFailure,
+		UserID:    "test-id",
+		IP:        "81.2.69.142",
+		Details:   map[string]string{"reason": "password=incorrect"},
+		CreatedAt: time.Date(2023, 6, 25, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+// TestSyslogSend_TCP checks that Send frames the messages by their length over tcp, formatting the events as CEF in RFC 5424 messages
+func TestSyslogSend_TCP(t *testing.T) {
+	// Arrange
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		content, _ := io.ReadAll(conn)
+		received <- string(content)
+	}()
+
+	sink, err := NewSyslogSink("tcp", l.Addr().String(), "1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.(*syslogSink).hostname = "test-host"
+
+	// Act
+	err = sink.Send(context.Background(), []entities.AuditEvent{testEvent(), {Type: entities.AuditLoginSuspicious, Outcome: entities.AuditOutcomeSuccess}})
+
+	// Assert
+	assert.Nil(t, err)
+	r := bufio.NewReader(strings.NewReader(<-received))
+	var messages []string
+	for {
+		var length int
+		if _, err := fmt.Fscanf(r, "%d ", &length); err != nil {
+			break
+		}
+		message := make([]byte, length)
+		io.ReadFull(r, message)
+		messages = append(messages, string(message))
+	}
+	assert.Equal(t, 2, len(messages))
+	assert.Equal(t, `<85>1 2023-06-25T10:00:00Z test-host go-hexagonal-api - login_failed - CEF:0|sergicanet9|go-hexagonal-api|1.2.3|login_failed|login_failed|5|`+
+		`rt=1687687200000 outcome=failure duid=test-id src=81.2.69.142 cs1Label=details cs1={"reason":"password\=incorrect"}`, messages[0])
+	assert.Contains(t, messages[1], "<84>1 ")
+	assert.Contains(t, messages[1], "|login_suspicious|login_suspicious|8|")
+}
+
+// TestSyslogSend_UDP checks that Send sends a datagram per message over udp
+func TestSyslogSend_UDP(t *testing.T) {
+	// Arrange
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	sink, err := NewSyslogSink("udp", conn.LocalAddr().String(), "1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = sink.Send(context.Background(), []entities.AuditEvent{testEvent(), testEvent()})
+
+	// Assert
+	assert.Nil(t, err)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	for i := 0; i < 2; i++ {
+		buf := make([]byte, 2048)
+		n, _, err := conn.ReadFrom(buf)
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(buf[:n]), "<85>1 2023-06-25T10:00:00Z "))
+	}
+}
+
+// TestSyslogSend_Unreachable checks that Send returns an error when the syslog server cannot be reached
+func TestSyslogSend_Unreachable(t *testing.T) {
+	// Arrange
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	l.Close()
+	sink, err := NewSyslogSink("tcp", address, "1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = sink.Send(context.Background(), []entities.AuditEvent{testEvent()})
+
+	// Assert
+	assert.Contains(t, err.Error(), "syslog server cannot be reached")
+}
+
+// TestNewSyslogSink_InvalidNetwork checks that NewSyslogSink returns an error when the network is not udp, tcp nor tls
+func TestNewSyslogSink_InvalidNetwork(t *testing.T) {
+	// Act
+	_, err := NewSyslogSink("unix", "/dev/log", "1.2.3")
+
+	// Assert
+	assert.Equal(t, "syslog network unix not valid", err.Error())
+}
+
+// TestEscapeHeader_Pipes checks that escapeHeader escapes the backslashes and pipes of a CEF header field
+func TestEscapeHeader_Pipes(t *testing.T) {
+	// Act
+	escaped := escapeHeader(`a|b\c`)
+
+	// Assert
+	assert.Equal(t, `a\|b\\c`, escaped)
+}
@@ -0,0 +1,18 @@
+package sms
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// NewSender creates the SMSSender of the given provider, only twilio for now, sending the messages from the given number
+func NewSender(provider, from, twilioAccountSID, twilioAuthToken, statusCallbackURL string) (ports.SMSSender, error) {
+	switch provider {
+	case "twilio":
+		return NewTwilioSender(twilioAccountSID, twilioAuthToken, from, statusCallbackURL, http.DefaultClient), nil
+	default:
+		return nil, fmt.Errorf("sms provider %s not valid", provider)
+	}
+}
@@ -0,0 +1,105 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/tracing"
+)
+
+// twilioEndpoint is the endpoint of the REST API of Twilio
+const twilioEndpoint = "https://api.twilio.com"
+
+// twilioSender adapter of an SMS sender sending the messages through the Programmable Messaging API of Twilio
+type twilioSender struct {
+	endpoint          string
+	accountSID        string
+	authToken         string
+	from              string
+	statusCallbackURL string
+	client            *http.Client
+}
+
+// NewTwilioSender creates a sender sending the messages of the given Twilio account from the given phone number,
+// or messaging service when it is an SID starting with MG, asking Twilio to post their delivery statuses to the callback URL, when set
+func NewTwilioSender(accountSID, authToken, from, statusCallbackURL string, client *http.Client) ports.SMSSender {
+	return &twilioSender{
+		endpoint:          twilioEndpoint,
+		accountSID:        accountSID,
+		authToken:         authToken,
+		from:              from,
+		statusCallbackURL: statusCallbackURL,
+		client:            client,
+	}
+}
+
+// Send creates the message, returning its SID
+func (s *twilioSender) Send(ctx context.Context, to, body string) (string, error) {
+	form := url.Values{"To": {to}, "Body": {body}}
+	if strings.HasPrefix(s.from, "MG") {
+		form.Set("MessagingServiceSid", s.from)
+	} else {
+		form.Set("From", s.from)
+	}
+	if s.statusCallbackURL != "" {
+		form.Set("StatusCallback", s.statusCallbackURL)
+	}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", s.endpoint, s.accountSID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.accountSID, s.authToken)
+	tracing.InjectHeader(ctx, req.Header)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("twilio responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var message struct {
+		SID string `json:"sid"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		return "", err
+	}
+	return message.SID, nil
+}
+
+// VerifyCallback checks the X-Twilio-Signature of the callback: the HMAC-SHA1 with the auth token of the URL
+// followed by every parameter of the form, sorted by name, with its value
+func (s *twilioSender) VerifyCallback(callbackURL string, form url.Values, signature string) bool {
+	names := make([]string, 0, len(form))
+	for name := range form {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mac := hmac.New(sha1.New, []byte(s.authToken))
+	mac.Write([]byte(callbackURL))
+	for _, name := range names {
+		for _, value := range form[name] {
+			mac.Write([]byte(name + value))
+		}
+	}
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
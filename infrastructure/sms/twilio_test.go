@@ -0,0 +1,129 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTwilioSend_Ok checks that Send creates the message in the account, authenticated with its SID and token, returning its SID
+func TestTwilioSend_Ok(t *testing.T) {
+	// Arrange
+	var path, username, password string
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		username, password, _ = r.BasicAuth()
+		r.ParseForm()
+		form = r.PostForm
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM123","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	s := NewTwilioSender("AC123", "test-token", "+15005550006", "https://api.test.com/v1/sms/status", server.Client()).(*twilioSender)
+	s.endpoint = server.URL
+
+	// Act
+	sid, err := s.Send(context.Background(), "+34600000000", "test message")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "SM123", sid)
+	assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", path)
+	assert.Equal(t, "AC123", username)
+	assert.Equal(t, "test-token", password)
+	assert.Equal(t, "+34600000000", form.Get("To"))
+	assert.Equal(t, "+15005550006", form.Get("From"))
+	assert.Equal(t, "test message", form.Get("Body"))
+	assert.Equal(t, "https://api.test.com/v1/sms/status", form.Get("StatusCallback"))
+}
+
+// TestTwilioSend_MessagingService checks that Send sends the messages through the messaging service when From is its SID
+func TestTwilioSend_MessagingService(t *testing.T) {
+	// Arrange
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM123"}`))
+	}))
+	defer server.Close()
+
+	s := NewTwilioSender("AC123", "test-token", "MG123", "", server.Client()).(*twilioSender)
+	s.endpoint = server.URL
+
+	// Act
+	_, err := s.Send(context.Background(), "+34600000000", "test message")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "MG123", form.Get("MessagingServiceSid"))
+	assert.Empty(t, form.Get("From"))
+	assert.Empty(t, form.Get("StatusCallback"))
+}
+
+// TestTwilioSend_ErrorStatus checks that Send returns an error with the response of Twilio when it does not create the message
+func TestTwilioSend_ErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":21211,"message":"Invalid 'To' Phone Number"}`))
+	}))
+	defer server.Close()
+
+	s := NewTwilioSender("AC123", "test-token", "+15005550006", "", server.Client()).(*twilioSender)
+	s.endpoint = server.URL
+
+	// Act
+	_, err := s.Send(context.Background(), "+34600000000", "test message")
+
+	// Assert
+	assert.EqualError(t, err, `twilio responded with status 400: {"code":21211,"message":"Invalid 'To' Phone Number"}`)
+}
+
+// TestTwilioVerifyCallback_Ok checks that VerifyCallback accepts the signature of the example of the Twilio docs
+func TestTwilioVerifyCallback_Ok(t *testing.T) {
+	// Arrange
+	s := NewTwilioSender("AC123", "12345", "", "", nil)
+	form := url.Values{
+		"CallSid": {"CA1234567890ABCDE"},
+		"Caller":  {"+12349013030"},
+		"Digits":  {"1234"},
+		"From":    {"+12349013030"},
+		"To":      {"+18005551212"},
+	}
+
+	// Act
+	valid := s.VerifyCallback("https://mycompany.com/myapp.php?foo=1&bar=2", form, "0/KCTR6DLpKmkAf8muzZqo1nDgQ=")
+
+	// Assert
+	assert.True(t, valid)
+}
+
+// TestTwilioVerifyCallback_NotValid checks that VerifyCallback refuses a callback whose form does not match its signature
+func TestTwilioVerifyCallback_NotValid(t *testing.T) {
+	// Arrange
+	s := NewTwilioSender("AC123", "12345", "", "", nil)
+	form := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"delivered"}}
+
+	// Act
+	valid := s.VerifyCallback("https://mycompany.com/myapp.php?foo=1&bar=2", form, "0/KCTR6DLpKmkAf8muzZqo1nDgQ=")
+
+	// Assert
+	assert.False(t, valid)
+}
+
+// TestNewSender_NotValid checks that NewSender returns an error when the provider is not known
+func TestNewSender_NotValid(t *testing.T) {
+	// Act
+	_, err := NewSender("vonage", "", "", "", "")
+
+	// Assert
+	assert.EqualError(t, err, "sms provider vonage not valid")
+}
@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Propagator propagates the trace context as the W3C traceparent and tracestate headers
+var Propagator propagation.TextMapPropagator = propagation.TraceContext{}
+
+// Propagation continues in the context of the requests the trace of their W3C trace context headers, when valid,
+// so that it is logged and propagated to the outbound calls even when the requests are not traced
+func Propagation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := Propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// InjectHeader sets the W3C trace context headers of the trace of the context in the header of an outbound request, nothing without a trace
+func InjectHeader(ctx context.Context, header http.Header) {
+	Propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// MIMEHeaders returns the W3C trace context of the trace of the context as MIME header lines, like the ones of an email,
+// empty without a trace
+func MIMEHeaders(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	Propagator.Inject(ctx, carrier)
+
+	keys := carrier.Keys()
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(textproto.CanonicalMIMEHeaderKey(key) + ": " + carrier.Get(key) + "\r\n")
+	}
+	return b.String()
+}
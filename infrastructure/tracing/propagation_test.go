@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// testTraceparent is a valid W3C traceparent header of a sampled trace
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// remoteContext returns a context carrying the remote span context of the test traceparent and the given tracestate
+func remoteContext(t *testing.T, tracestate string) context.Context {
+	t.Helper()
+
+	header := http.Header{}
+	header.Set("traceparent", testTraceparent)
+	header.Set("tracestate", tracestate)
+	var ctx context.Context
+	Propagation(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), &http.Request{Header: header})
+	return ctx
+}
+
+// TestPropagation_Ok checks that Propagation continues in the request context the trace of the W3C trace context headers
+func TestPropagation_Ok(t *testing.T) {
+	// Act
+	ctx := remoteContext(t, "vendor=value")
+
+	// Assert
+	sc := trace.SpanContextFromContext(ctx)
+	assert.True(t, sc.IsRemote())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID().String())
+	assert.True(t, sc.IsSampled())
+	assert.Equal(t, "vendor=value", sc.TraceState().String())
+}
+
+// TestPropagation_InvalidHeader checks that Propagation ignores the trace context headers not being valid
+func TestPropagation_InvalidHeader(t *testing.T) {
+	// Arrange
+	req := httptest.NewRequest(http.MethodGet, "http://testing/v1/users", nil)
+	req.Header.Set("traceparent", "not-valid")
+	var ctx context.Context
+
+	// Act
+	Propagation(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
+
+// TestInjectHeader_Ok checks that InjectHeader sets the trace context headers of the trace of the context
+func TestInjectHeader_Ok(t *testing.T) {
+	// Arrange
+	ctx := remoteContext(t, "vendor=value")
+	header := http.Header{}
+
+	// Act
+	InjectHeader(ctx, header)
+
+	// Assert
+	assert.Equal(t, testTraceparent, header.Get("traceparent"))
+	assert.Equal(t, "vendor=value", header.Get("tracestate"))
+}
+
+// TestInjectHeader_NoTrace checks that InjectHeader does not set any header without a trace
+func TestInjectHeader_NoTrace(t *testing.T) {
+	// Arrange
+	header := http.Header{}
+
+	// Act
+	InjectHeader(context.Background(), header)
+
+	// Assert
+	assert.Empty(t, header)
+}
+
+// TestMIMEHeaders_Ok checks that MIMEHeaders returns the trace context headers of the trace of the context as MIME header lines
+func TestMIMEHeaders_Ok(t *testing.T) {
+	// Arrange
+	ctx := remoteContext(t, "vendor=value")
+
+	// Act
+	headers := MIMEHeaders(ctx)
+
+	// Assert
+	assert.Equal(t, "Traceparent: "+testTraceparent+"\r\nTracestate: vendor=value\r\n", headers)
+}
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"fmt"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// routeSampler samples the spans with the ratio of the route they are named after, or with the default ratio for the other routes
+type routeSampler struct {
+	defaultSampler sdktrace.Sampler
+	routes         map[string]sdktrace.Sampler
+}
+
+// newRouteSampler creates a route sampler with the given ratios, from 0 to 1
+func newRouteSampler(sampleRatio float64, routeSampleRatios map[string]float64) (sdktrace.Sampler, error) {
+	if sampleRatio < 0 || sampleRatio > 1 {
+		return nil, fmt.Errorf("sample ratio %v not valid, it must be between 0 and 1", sampleRatio)
+	}
+
+	s := &routeSampler{
+		defaultSampler: sdktrace.TraceIDRatioBased(sampleRatio),
+		routes:         make(map[string]sdktrace.Sampler, len(routeSampleRatios)),
+	}
+	for route, ratio := range routeSampleRatios {
+		if ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("sample ratio %v of route %s not valid, it must be between 0 and 1", ratio, route)
+		}
+		s.routes[route] = sdktrace.TraceIDRatioBased(ratio)
+	}
+	return s, nil
+}
+
+// ShouldSample implements the sdktrace.Sampler interface
+func (s *routeSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if sampler, ok := s.routes[p.Name]; ok {
+		return sampler.ShouldSample(p)
+	}
+	return s.defaultSampler.ShouldSample(p)
+}
+
+// Description implements the sdktrace.Sampler interface
+func (s *routeSampler) Description() string {
+	return fmt.Sprintf("RouteSampler{default:%s,routes:%d}", s.defaultSampler.Description(), len(s.routes))
+}
@@ -0,0 +1,38 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestRouteSampler_Route checks that the route sampler samples the spans named after a route with the ratio of the route
+func TestRouteSampler_Route(t *testing.T) {
+	// Arrange
+	sampler, err := newRouteSampler(1, map[string]float64{"POST /v1/users/login": 0})
+	assert.Nil(t, err)
+	traceID := trace.TraceID{0x01}
+
+	// Act
+	route := sampler.ShouldSample(sdktrace.SamplingParameters{TraceID: traceID, Name: "POST /v1/users/login"})
+	other := sampler.ShouldSample(sdktrace.SamplingParameters{TraceID: traceID, Name: "GET /v1/users"})
+
+	// Assert
+	assert.Equal(t, sdktrace.Drop, route.Decision)
+	assert.Equal(t, sdktrace.RecordAndSample, other.Decision)
+}
+
+// TestRouteSampler_Default checks that the route sampler samples the spans of the other routes with the default ratio
+func TestRouteSampler_Default(t *testing.T) {
+	// Arrange
+	sampler, err := newRouteSampler(0, map[string]float64{"POST /v1/users/login": 1})
+	assert.Nil(t, err)
+
+	// Act
+	result := sampler.ShouldSample(sdktrace.SamplingParameters{TraceID: trace.TraceID{0x01}, Name: "GET /v1/users"})
+
+	// Assert
+	assert.Equal(t, sdktrace.Drop, result.Decision)
+}
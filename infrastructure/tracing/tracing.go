@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// NewTracerProvider creates a tracer provider exporting the spans in batches with OTLP over HTTP to the given endpoint, like localhost:4318.
+// The traces not continuing a remote one are sampled with the ratio of the route their root span is named after, like POST /v1/users/login,
+// or with the given sample ratio for the other routes, all of them from 0 to 1, while the remote ones keep the decision of their parent.
+func NewTracerProvider(ctx context.Context, endpoint string, insecure bool, serviceName, version string, sampleRatio float64, routeSampleRatios map[string]float64) (*sdktrace.TracerProvider, error) {
+	sampler, err := newRouteSampler(sampleRatio, routeSampleRatios)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the tracing resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	), nil
+}
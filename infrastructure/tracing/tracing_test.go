@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewTracerProvider_Ok checks that NewTracerProvider creates a tracer provider without connecting to the endpoint
+func TestNewTracerProvider_Ok(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	// Act
+	tp, err := NewTracerProvider(ctx, "localhost:4318", true, "test-service", "test-version", 0.5, map[string]float64{"POST /v1/users/login": 0.1})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotNil(t, tp)
+	assert.Nil(t, tp.Shutdown(ctx))
+}
+
+// TestNewTracerProvider_InvalidSampleRatio checks that NewTracerProvider returns an error when the sample ratio is not between 0 and 1
+func TestNewTracerProvider_InvalidSampleRatio(t *testing.T) {
+	// Arrange
+	expectedError := "sample ratio 1.5 not valid, it must be between 0 and 1"
+
+	// Act
+	_, err := NewTracerProvider(context.Background(), "localhost:4318", true, "test-service", "test-version", 1.5, nil)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestNewTracerProvider_InvalidRouteSampleRatio checks that NewTracerProvider returns an error when the sample ratio of a route is not between 0 and 1
+func TestNewTracerProvider_InvalidRouteSampleRatio(t *testing.T) {
+	// Arrange
+	expectedError := "sample ratio -1 of route POST /v1/users/login not valid, it must be between 0 and 1"
+
+	// Act
+	_, err := NewTracerProvider(context.Background(), "localhost:4318", true, "test-service", "test-version", 1, map[string]float64{"POST /v1/users/login": -1})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
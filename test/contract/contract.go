@@ -0,0 +1,362 @@
+// Package contract verifies that an instance of the API honors an OpenAPI document, so the consumers relying on the document can check
+// a build against it before it is deployed. Every operation is sent a request built from the schemas of its parameters and body,
+// checking that it responds with a documented status and, when it succeeds, with a body matching the documented schema.
+// The operations are then fuzzed with requests whose parameters and bodies are mutated into hostile values, checking that they never fail
+// with a server error nor respond with an undocumented status.
+// The operations are sent as documented, the deletes, merges and restores included, so the verification must run against a test instance.
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/app/clientgen"
+	_ "github.com/sergicanet9/go-hexagonal-api/app/docs"
+	"github.com/swaggo/swag"
+)
+
+// maxBodySize the size read at most from a response body
+const maxBodySize = 1 << 20
+
+// Config of a verification
+type Config struct {
+	// BaseURL of the test instance, like http://localhost:8080
+	BaseURL string
+	// Token sent as a bearer token with the secured operations, of an admin so all of them are reached
+	Token string
+	// Document verified, the OpenAPI document of this build when not set, or the one a consumer was built against
+	Document []byte
+	// HTTPClient sending the requests, http.DefaultClient when not set
+	HTTPClient *http.Client
+	// Iterations fuzzing every operation, none when zero
+	Iterations int
+	// Seed of the values generated, so a failure can be reproduced
+	Seed int64
+	// Skip the names of the operations not verified, like DeleteUser
+	Skip []string
+}
+
+// Failure of an operation to honor the document, with the request failing
+type Failure struct {
+	Operation string
+	Request   string
+	Body      string
+	Status    int
+	Reason    string
+}
+
+func (f Failure) String() string {
+	s := fmt.Sprintf("%s: %s responded with %d: %s", f.Operation, f.Request, f.Status, f.Reason)
+	if f.Body != "" {
+		s += fmt.Sprintf(", body %s", f.Body)
+	}
+	return s
+}
+
+// Report of a verification, the rate limited requests not being verified
+type Report struct {
+	Requests    int
+	RateLimited int
+	Failures    []Failure
+}
+
+// Err returns an error listing the failures of the report, nil when there are none
+func (r Report) Err() error {
+	if len(r.Failures) == 0 {
+		return nil
+	}
+	lines := make([]string, len(r.Failures))
+	for i, f := range r.Failures {
+		lines[i] = f.String()
+	}
+	return errors.New(strings.Join(lines, "\n"))
+}
+
+// verifier sends the requests of a verification
+type verifier struct {
+	cfg    Config
+	client *http.Client
+	types  map[string]clientgen.Type
+	rand   *rand.Rand
+	report Report
+}
+
+// call a request built for an operation
+type call struct {
+	path        string
+	query       url.Values
+	body        []byte
+	contentType string
+}
+
+// Verify sends every operation of the document, but the skipped ones and the webhooks called by the providers, to the instance,
+// first with a request built from its schemas and then with as many fuzzed requests as iterations, returning the failures found.
+// An error is returned when the document is not valid or the instance cannot be reached.
+func Verify(ctx context.Context, cfg Config) (Report, error) {
+	doc := cfg.Document
+	if doc == nil {
+		read, err := swag.ReadDoc()
+		if err != nil {
+			return Report{}, err
+		}
+		doc = []byte(read)
+	}
+	api, err := clientgen.Parse(doc)
+	if err != nil {
+		return Report{}, err
+	}
+
+	v := &verifier{
+		cfg:    cfg,
+		client: cfg.HTTPClient,
+		types:  make(map[string]clientgen.Type, len(api.Types)),
+		rand:   rand.New(rand.NewSource(cfg.Seed)),
+	}
+	if v.client == nil {
+		v.client = http.DefaultClient
+	}
+	for _, t := range api.Types {
+		v.types[t.Name] = t
+	}
+	skip := make(map[string]bool, len(cfg.Skip))
+	for _, name := range cfg.Skip {
+		skip[name] = true
+	}
+
+	for _, op := range api.Operations {
+		if skip[op.Name] {
+			continue
+		}
+		if err := v.send(ctx, op, v.build(op, false), true); err != nil {
+			return v.report, err
+		}
+		for i := 0; i < cfg.Iterations; i++ {
+			if err := v.send(ctx, op, v.build(op, true), false); err != nil {
+				return v.report, err
+			}
+		}
+	}
+	return v.report, nil
+}
+
+// build builds a request of the operation, with hostile values when fuzzed
+func (v *verifier) build(op clientgen.Operation, fuzz bool) call {
+	c := call{path: op.Path, query: url.Values{}}
+	for _, p := range op.PathParams {
+		value := v.format(v.generate(p.Schema, p.Name, 0))
+		if fuzz && v.rand.Intn(2) == 0 {
+			value = v.pick(pathHostileStrings)
+		}
+		c.path = strings.Replace(c.path, "{"+p.Name+"}", url.PathEscape(value), 1)
+	}
+
+	for _, p := range op.QueryParams {
+		switch {
+		case fuzz && v.rand.Intn(5) == 0:
+		case fuzz && v.rand.Intn(3) == 0:
+			c.query.Set(p.Name, v.pick(hostileStrings))
+		case p.Required || v.rand.Intn(2) == 0:
+			c.query.Set(p.Name, v.format(v.generate(p.Schema, p.Name, 0)))
+		}
+	}
+
+	switch {
+	case op.Body != nil && fuzz && v.rand.Intn(10) == 0:
+		c.body, c.contentType = []byte(`{"`), "application/json"
+	case op.Body != nil:
+		value := v.generate(*op.Body, "", 0)
+		if fuzz {
+			value = v.mutate(value)
+		}
+		c.body, _ = json.Marshal(value)
+		c.contentType = "application/json"
+	case op.File != "":
+		contentType := "image/png"
+		if fuzz {
+			contentType = v.pick(hostileStrings)
+		}
+		c.body, c.contentType = v.multipart(op.File, contentType)
+	}
+	return c
+}
+
+// send sends the request of the operation and verifies its response, its body too when the request was built from the schemas
+func (v *verifier) send(ctx context.Context, op clientgen.Operation, c call, checkBody bool) error {
+	endpoint := strings.TrimSuffix(v.cfg.BaseURL, "/") + c.path
+	if len(c.query) > 0 {
+		endpoint += "?" + c.query.Encode()
+	}
+	var body io.Reader
+	if c.body != nil {
+		body = bytes.NewReader(c.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, op.Method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	if c.contentType != "" {
+		req.Header.Set("Content-Type", c.contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if op.Secured && v.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+v.cfg.Token)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("instance cannot be reached: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return fmt.Errorf("response of %s cannot be read: %w", op.Name, err)
+	}
+	v.report.Requests++
+
+	fail := func(reason string) {
+		request := op.Method + " " + c.path
+		if len(c.query) > 0 {
+			request += "?" + c.query.Encode()
+		}
+		failure := Failure{Operation: op.Name, Request: request, Status: resp.StatusCode, Reason: reason}
+		if c.contentType == "application/json" {
+			failure.Body = string(c.body)
+		}
+		v.report.Failures = append(v.report.Failures, failure)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		v.report.RateLimited++
+	case resp.StatusCode == http.StatusInternalServerError:
+		fail("server error " + strings.TrimSpace(string(respBody)))
+	case !documented(op, resp.StatusCode):
+		fail("status not documented")
+	case checkBody && resp.StatusCode == op.Status && op.Result != nil && op.Result.Kind != clientgen.KindFile:
+		decoder := json.NewDecoder(bytes.NewReader(respBody))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			fail("body not JSON: " + err.Error())
+			break
+		}
+		if problems := v.validate(value, *op.Result, "body"); len(problems) > 0 {
+			fail(strings.Join(problems, ", "))
+		}
+	}
+	return nil
+}
+
+// validate returns the problems of the value not matching the schema, where at is the location of the value.
+// A null is accepted anywhere, as Swagger 2.0 cannot document the nullable values, like the empty lists of the Go handlers.
+func (v *verifier) validate(value interface{}, s clientgen.Schema, at string) []string {
+	if value == nil {
+		return nil
+	}
+
+	switch s.Kind {
+	case clientgen.KindString:
+		str, ok := value.(string)
+		if !ok {
+			return []string{at + " is not a string"}
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			return []string{fmt.Sprintf("%s %q is not one of %s", at, str, strings.Join(s.Enum, ", "))}
+		}
+	case clientgen.KindInteger:
+		n, ok := value.(json.Number)
+		if _, err := n.Int64(); !ok || err != nil {
+			return []string{at + " is not an integer"}
+		}
+	case clientgen.KindNumber:
+		if _, ok := value.(json.Number); !ok {
+			return []string{at + " is not a number"}
+		}
+	case clientgen.KindBoolean:
+		if _, ok := value.(bool); !ok {
+			return []string{at + " is not a boolean"}
+		}
+	case clientgen.KindArray:
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{at + " is not an array"}
+		}
+		var problems []string
+		for i, item := range items {
+			problems = append(problems, v.validate(item, *s.Items, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+		return problems
+	case clientgen.KindMap:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{at + " is not an object"}
+		}
+		var problems []string
+		for _, key := range sortedKeys(object) {
+			problems = append(problems, v.validate(object[key], *s.Items, at+"."+key)...)
+		}
+		return problems
+	case clientgen.KindRef:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{at + " is not an object"}
+		}
+		fields := make(map[string]clientgen.Schema)
+		for _, f := range v.types[s.Ref].Fields {
+			fields[f.Name] = f.Schema
+		}
+		var problems []string
+		for _, key := range sortedKeys(object) {
+			field, ok := fields[key]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s.%s is not documented in %s", at, key, s.Ref))
+				continue
+			}
+			problems = append(problems, v.validate(object[key], field, at+"."+key)...)
+		}
+		return problems
+	}
+	return nil
+}
+
+// documented reports whether the operation documents the status
+func documented(op clientgen.Operation, status int) bool {
+	if status == op.Status {
+		return true
+	}
+	for _, failure := range op.Failures {
+		if status == failure {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether the values contain the value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of the object sorted, so the problems are reported in the same order
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
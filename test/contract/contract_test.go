@@ -0,0 +1,200 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testDoc a document with an operation reading an item and another one creating items
+const testDoc = `{
+	"paths": {
+		"/v1/items/{id}": {
+			"get": {
+				"summary": "Get item by ID",
+				"parameters": [
+					{"in": "path", "name": "id", "type": "string", "required": true},
+					{"in": "query", "name": "take", "type": "integer"}
+				],
+				"responses": {"200": {"schema": {"$ref": "#/definitions/models.ItemResp"}}, "400": {}},
+				"security": [{"Bearer": []}]
+			}
+		},
+		"/v1/items": {
+			"post": {
+				"summary": "Create items",
+				"parameters": [{"in": "body", "name": "items", "schema": {"type": "array", "items": {"$ref": "#/definitions/models.ItemReq"}}}],
+				"responses": {"201": {}, "400": {}}
+			}
+		}
+	},
+	"definitions": {
+		"models.ItemReq": {"type": "object", "properties": {"name": {"type": "string"}, "size": {"type": "integer"}}},
+		"models.ItemResp": {
+			"type": "object",
+			"properties": {
+				"id": {"type": "string"},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"status": {"type": "string", "enum": ["on", "off"]}
+			}
+		}
+	}
+}`
+
+// itemReq the body of the create items operation of the test document
+type itemReq struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// newTestServer creates a server of the test document, responding to the get item by ID operation with the body
+func newTestServer(item string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var items []itemReq
+			if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(item))
+	}))
+}
+
+// TestVerify_Ok checks that Verify reports no failures for an instance honoring the document, with the fuzzed requests too
+func TestVerify_Ok(t *testing.T) {
+	// Arrange
+	server := newTestServer(`{"id":"test-id","tags":null,"status":"on"}`)
+	defer server.Close()
+
+	// Act
+	report, err := Verify(context.Background(), Config{BaseURL: server.URL, Token: "test-token", Document: []byte(testDoc), Iterations: 20, Seed: 1})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, report.Err())
+	assert.Equal(t, 42, report.Requests)
+}
+
+// TestVerify_BodyNotMatching checks that Verify reports the fields of a response not matching the documented schema
+func TestVerify_BodyNotMatching(t *testing.T) {
+	// Arrange
+	server := newTestServer(`{"id":1,"tags":["a",2],"status":"maybe","extra":true}`)
+	defer server.Close()
+
+	// Act
+	report, err := Verify(context.Background(), Config{BaseURL: server.URL, Token: "test-token", Document: []byte(testDoc), Seed: 1})
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, report.Failures, 1) {
+		assert.Equal(t, "GetItemByID", report.Failures[0].Operation)
+		assert.Equal(t, http.StatusOK, report.Failures[0].Status)
+		assert.Equal(t, `body.extra is not documented in ItemResp, body.id is not a string, body.status "maybe" is not one of on, off, body.tags[1] is not a string`, report.Failures[0].Reason)
+	}
+}
+
+// TestVerify_UndocumentedStatus checks that Verify reports the responses with a status not documented by the operation
+func TestVerify_UndocumentedStatus(t *testing.T) {
+	// Arrange
+	server := newTestServer(`{}`)
+	defer server.Close()
+
+	// Act
+	report, err := Verify(context.Background(), Config{BaseURL: server.URL, Document: []byte(testDoc), Skip: []string{"CreateItems"}})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Requests)
+	if assert.Len(t, report.Failures, 1) {
+		assert.Equal(t, http.StatusUnauthorized, report.Failures[0].Status)
+		assert.Equal(t, "status not documented", report.Failures[0].Reason)
+	}
+}
+
+// TestVerify_ServerError checks that Verify reports the fuzzed requests failing with a server error, along with their body
+func TestVerify_ServerError(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var items []itemReq
+		if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"items cannot be decoded"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	// Act
+	report, err := Verify(context.Background(), Config{BaseURL: server.URL, Document: []byte(testDoc), Iterations: 20, Seed: 1, Skip: []string{"GetItemByID"}})
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.NotEmpty(t, report.Failures) {
+		assert.Equal(t, "CreateItems", report.Failures[0].Operation)
+		assert.Equal(t, `server error {"error":"items cannot be decoded"}`, report.Failures[0].Reason)
+		assert.NotEmpty(t, report.Failures[0].Body)
+	}
+}
+
+// TestVerify_RateLimited checks that Verify counts the rate limited requests without reporting them as failures
+func TestVerify_RateLimited(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	// Act
+	report, err := Verify(context.Background(), Config{BaseURL: server.URL, Document: []byte(testDoc)})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, report.Failures)
+	assert.Equal(t, 2, report.RateLimited)
+}
+
+// TestVerify_Unreachable checks that Verify returns an error when the instance cannot be reached
+func TestVerify_Unreachable(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	// Act
+	_, err := Verify(context.Background(), Config{BaseURL: server.URL, Document: []byte(testDoc)})
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+// TestVerify_ServedDocument checks that Verify sends the operations of the OpenAPI document of this build when no document is given
+func TestVerify_ServedDocument(t *testing.T) {
+	// Arrange
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	// Act
+	report, err := Verify(context.Background(), Config{BaseURL: server.URL})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Greater(t, report.Requests, 30)
+	assert.Equal(t, report.Requests, report.RateLimited)
+	assert.Contains(t, paths, "/v1/users/login")
+	assert.NotContains(t, paths, "/v1/billing/webhook")
+}
@@ -0,0 +1,182 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/app/clientgen"
+)
+
+// maxDepth the depth of the values generated, so the recursive types end
+const maxDepth = 4
+
+// hostileStrings sent by the fuzzed requests
+var hostileStrings = []string{
+	"",
+	" ",
+	strings.Repeat("a", 4096),
+	"ünïcødé ✓ 🚀",
+	"\x00",
+	"' OR '1'='1",
+	`{"$gt": ""}`,
+	"<script>alert(1)</script>",
+	"../../etc/passwd",
+	"-1",
+	"9223372036854775808",
+	"1e309",
+	"NaN",
+	"null",
+	"true",
+}
+
+// pathHostileStrings the hostile strings sent as path parameters, not splitting nor emptying a segment so the request is routed
+var pathHostileStrings = []string{
+	" ",
+	strings.Repeat("a", 1024),
+	"ünïcødé ✓ 🚀",
+	"\x00",
+	"' OR '1'='1",
+	`{"$gt": ""}`,
+	"<script>alert(1)</script>",
+	"-1",
+	"NaN",
+	"null",
+}
+
+// hostileValues the JSON values replacing the ones of the fuzzed bodies
+var hostileValues = []interface{}{
+	nil,
+	-1,
+	1e308,
+	int64(9223372036854775807),
+	"",
+	strings.Repeat("a", 4096),
+	"ünïcødé ✓ 🚀",
+	true,
+	[]interface{}{},
+	map[string]interface{}{},
+	map[string]interface{}{"$gt": ""},
+	[]interface{}{map[string]interface{}{"$where": "1"}},
+}
+
+// generate generates a value of the schema, plausible for the field or parameter named name so the requests can succeed
+func (v *verifier) generate(s clientgen.Schema, name string, depth int) interface{} {
+	switch s.Kind {
+	case clientgen.KindString:
+		if len(s.Enum) > 0 {
+			return v.pick(s.Enum)
+		}
+		return v.generateString(name)
+	case clientgen.KindInteger:
+		return v.rand.Intn(100)
+	case clientgen.KindNumber:
+		return float64(v.rand.Intn(18000)-9000) / 100
+	case clientgen.KindBoolean:
+		return v.rand.Intn(2) == 0
+	case clientgen.KindArray:
+		items := []interface{}{}
+		if depth < maxDepth {
+			for i := v.rand.Intn(3); i > 0; i-- {
+				items = append(items, v.generate(*s.Items, name, depth+1))
+			}
+		}
+		return items
+	case clientgen.KindMap:
+		values := map[string]interface{}{}
+		if depth < maxDepth {
+			for i := v.rand.Intn(3); i > 0; i-- {
+				values[fmt.Sprintf("key%d", i)] = v.generate(*s.Items, "", depth+1)
+			}
+		}
+		return values
+	case clientgen.KindRef:
+		object := map[string]interface{}{}
+		if depth < maxDepth {
+			for _, f := range v.types[s.Ref].Fields {
+				object[f.Name] = v.generate(f.Schema, f.Name, depth+1)
+			}
+		}
+		return object
+	default:
+		return "contract"
+	}
+}
+
+// generateString generates a string plausible for the field or parameter named name
+func (v *verifier) generateString(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.Contains(name, "email"):
+		return fmt.Sprintf("contract-%d@example.com", v.rand.Int63())
+	case strings.Contains(name, "password"):
+		return fmt.Sprintf("Contract-%d!", v.rand.Int63())
+	case strings.Contains(name, "phone"):
+		return fmt.Sprintf("+3460%07d", v.rand.Intn(10000000))
+	case name == "duration":
+		return fmt.Sprintf("%dm", 1+v.rand.Intn(10))
+	case name == "from" || name == "to" || name == "before" || strings.HasSuffix(name, "_at"):
+		return fmt.Sprintf("20%02d-01-02T15:04:05Z", 10+v.rand.Intn(20))
+	case name == "id" || strings.HasSuffix(name, "_id"):
+		return fmt.Sprintf("%024x", v.rand.Int63())
+	default:
+		return fmt.Sprintf("contract %d", v.rand.Intn(1000))
+	}
+}
+
+// mutate replaces a value nested in the value, or the value itself, with a hostile one, or adds an undocumented field to an object
+func (v *verifier) mutate(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		keys := sortedKeys(typed)
+		switch {
+		case len(keys) > 0 && v.rand.Intn(3) != 0:
+			key := v.pick(keys)
+			typed[key] = v.mutate(typed[key])
+			return typed
+		case v.rand.Intn(2) == 0:
+			typed["$where"] = "1"
+			return typed
+		}
+	case []interface{}:
+		if len(typed) > 0 && v.rand.Intn(3) != 0 {
+			i := v.rand.Intn(len(typed))
+			typed[i] = v.mutate(typed[i])
+			return typed
+		}
+	}
+	return hostileValues[v.rand.Intn(len(hostileValues))]
+}
+
+// format formats a generated value as a path or query parameter
+func (v *verifier) format(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// multipart returns a multipart form uploading random content in the field, with its content type
+func (v *verifier) multipart(field, contentType string) ([]byte, string) {
+	content := make([]byte, 64+v.rand.Intn(1024))
+	v.rand.Read(content)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename="contract.png"`, field))
+	header.Set("Content-Type", contentType)
+	part, _ := writer.CreatePart(header)
+	part.Write(content)
+	writer.Close()
+	return body.Bytes(), writer.FormDataContentType()
+}
+
+// pick picks one of the values
+func (v *verifier) pick(values []string) string {
+	return values[v.rand.Intn(len(values))]
+}
@@ -0,0 +1,156 @@
+// Package factory builds the entities the unit and integration tests are set up with, valid and random,
+// but deterministic for a seed, so a test built on them builds the same ones on every run.
+package factory
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/mapping"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// Password the password of the users built, hashed in their PasswordHash
+const Password = "test"
+
+// passwordHash the bcrypt hash of Password
+const passwordHash = "$2a$10$Q71DDcyvQhzt2K1EbRp1cOh4ToUh9de9ETsixwXGOVeRorTh8tjN2"
+
+// Epoch the time the clock of the factories starts at
+var Epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	names    = []string{"Anna", "Marc", "Laura", "Pau", "Julia", "Jordi", "Marta", "David", "Nuria", "Oriol"}
+	surnames = []string{"Garcia", "Puig", "Serra", "Vidal", "Soler", "Ferrer", "Roca", "Pujol", "Font", "Mas"}
+)
+
+// Factory builds the entities, numbering them so that their emails are unique, and setting their times with a clock
+// advancing a second per entity built. It is not safe for concurrent use.
+type Factory struct {
+	rand *rand.Rand
+	now  time.Time
+	seq  int
+}
+
+// New creates a factory building the entities of the seed
+func New(seed int64) *Factory {
+	return &Factory{
+		rand: rand.New(rand.NewSource(seed)),
+		now:  Epoch,
+	}
+}
+
+// ID returns an ID in the format of the IDs of Mongo
+func (f *Factory) ID() string {
+	return f.hex(12)
+}
+
+// User returns a user not created yet, without an ID, with Password as its password and located in Catalonia
+func (f *Factory) User() entities.User {
+	f.seq++
+	now := f.tick()
+	name, surname := names[f.rand.Intn(len(names))], surnames[f.rand.Intn(len(surnames))]
+	return entities.User{
+		Name:         name,
+		Surnames:     surname,
+		Email:        fmt.Sprintf("%s.%s.%d@test.com", strings.ToLower(name), strings.ToLower(surname), f.seq),
+		PasswordHash: passwordHash,
+		Claims:       []int64{},
+		Location: &entities.GeoPoint{
+			Type:        entities.GeoPointType,
+			Coordinates: []float64{0.5 + f.rand.Float64()*2.5, 40.5 + f.rand.Float64()*2},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Admin returns a user like User, with the admin claim
+func (f *Factory) Admin() entities.User {
+	user := f.User()
+	user.Claims = []int64{int64(entities.AdminClaim)}
+	return user
+}
+
+// Users returns n users like User
+func (f *Factory) Users(n int) []entities.User {
+	users := make([]entities.User, n)
+	for i := range users {
+		users[i] = f.User()
+	}
+	return users
+}
+
+// Token returns the token of the user signed with the secret, with the claims of the tokens signed at login.
+// It is issued and expires relative to the current time, not the clock of the factory, so it is valid while the test runs.
+func (f *Factory) Token(secret string, user entities.User) string {
+	now := time.Now().UTC()
+	claims := jwt.MapClaims{
+		"authorized": true,
+		"user_id":    user.ID,
+		"sub":        user.ID,
+		"iat":        now.Unix(),
+		"exp":        now.Add(models.TokenLifetime).Unix(),
+	}
+	for _, claim := range user.Claims {
+		claims[entities.UserClaim(claim).String()] = true
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+// Session returns the response of a login of the user, with its token signed with the secret
+func (f *Factory) Session(secret string, user entities.User) models.LoginUserResp {
+	lastLogin := f.tick()
+	user.LastLoginAt = &lastLogin
+	return models.LoginUserResp{
+		User:  mapping.UserResp(user),
+		Token: f.Token(secret, user),
+	}
+}
+
+// AuditEvent returns an event of the type, succeeded, of a user acting on its own from an IP of a private network
+func (f *Factory) AuditEvent(eventType string) entities.AuditEvent {
+	userID := f.ID()
+	return entities.AuditEvent{
+		Type:      eventType,
+		Outcome:   entities.AuditOutcomeSuccess,
+		UserID:    userID,
+		ActorID:   userID,
+		IP:        fmt.Sprintf("10.%d.%d.%d", f.rand.Intn(256), f.rand.Intn(256), 1+f.rand.Intn(254)),
+		CreatedAt: f.tick(),
+	}
+}
+
+// Event returns a lifecycle event of the type, of a user
+func (f *Factory) Event(eventType string) ports.Event {
+	return ports.Event{
+		ID:         f.hex(16),
+		Type:       eventType,
+		UserID:     f.ID(),
+		OccurredAt: f.tick(),
+	}
+}
+
+// hex returns n random bytes, hex encoded
+func (f *Factory) hex(n int) string {
+	b := make([]byte, n)
+	f.rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// tick advances the clock, returning its time
+func (f *Factory) tick() time.Time {
+	f.now = f.now.Add(time.Second)
+	return f.now
+}
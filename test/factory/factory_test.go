@@ -0,0 +1,90 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestUser_SameSeed checks that the factories of the same seed build the same users
+func TestUser_SameSeed(t *testing.T) {
+	// Arrange
+	first, second := New(42), New(42)
+
+	// Act
+	firstUsers, secondUsers := first.Users(5), second.Users(5)
+
+	// Assert
+	assert.Equal(t, firstUsers, secondUsers)
+}
+
+// TestUser_Valid checks that User builds users with unique emails, a valid location and Password as their password
+func TestUser_Valid(t *testing.T) {
+	// Arrange
+	f := New(1)
+
+	// Act
+	users := f.Users(100)
+
+	// Assert
+	emails := map[string]bool{}
+	for _, user := range users {
+		assert.False(t, emails[user.Email])
+		emails[user.Email] = true
+		assert.True(t, user.Location.IsValid())
+		assert.True(t, user.UpdatedAt.After(Epoch))
+	}
+	assert.Nil(t, bcrypt.CompareHashAndPassword([]byte(users[0].PasswordHash), []byte(Password)))
+}
+
+// TestToken_Ok checks that Token signs the claims of the user with the secret
+func TestToken_Ok(t *testing.T) {
+	// Arrange
+	f := New(1)
+	admin := f.Admin()
+	admin.ID = f.ID()
+
+	// Act
+	token := f.Token("test-secret", admin)
+
+	// Assert
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte("test-secret"), nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, admin.ID, claims["user_id"])
+	assert.Equal(t, true, claims[entities.AdminClaim.String()])
+}
+
+// TestSession_Ok checks that Session returns the user logged in along with its token
+func TestSession_Ok(t *testing.T) {
+	// Arrange
+	f := New(1)
+	user := f.User()
+
+	// Act
+	session := f.Session("test-secret", user)
+
+	// Assert
+	assert.Equal(t, user.Email, session.User.Email)
+	assert.NotNil(t, session.User.LastLoginAt)
+	assert.NotEmpty(t, session.Token)
+}
+
+// TestAuditEvent_SameSeed checks that the factories of the same seed build the same events
+func TestAuditEvent_SameSeed(t *testing.T) {
+	// Arrange
+	first, second := New(7), New(7)
+
+	// Act
+	firstEvent, secondEvent := first.AuditEvent(entities.AuditLoginSucceeded), second.AuditEvent(entities.AuditLoginSucceeded)
+
+	// Assert
+	assert.Equal(t, firstEvent, secondEvent)
+	assert.Equal(t, entities.AuditLoginSucceeded, firstEvent.Type)
+	assert.Len(t, first.Event("user.created").ID, 32)
+}
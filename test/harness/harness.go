@@ -0,0 +1,297 @@
+// Package harness runs the containers of MongoDB and Redis the integration tests of the service layer run against,
+// creating a database of their own for every test with the indexes of the repositories, and seeding it.
+package harness
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/rs/zerolog"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/core/services"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/mongo"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/redis"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	mongoRepository    = "mongo"
+	mongoTag           = "5.0"
+	mongoContainerPort = "27017/tcp"
+	mongoReplicaSet    = "rs0"
+	redisRepository    = "redis"
+	redisTag           = "7.0"
+	redisContainerPort = "6379/tcp"
+	jwtSecret          = "harness-secret"
+)
+
+// maxWait bounds the wait for the containers to accept connections
+const maxWait = time.Minute
+
+// invalidName matches the characters of the names of the tests not valid in the names of the databases
+var invalidName = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// Harness containers of MongoDB, run as a single node replica set so that the transactions are supported, and Redis,
+// shared by the tests of a package, started by its TestMain and closed once they have run
+type Harness struct {
+	MongoDSN     string
+	RedisAddress string
+	pool         *dockertest.Pool
+	resources    []*dockertest.Resource
+	client       *mongodriver.Client
+}
+
+// Env the environment of a test: its database, with the indexes of the repositories created, the repositories of the database
+// and a config of the containers, built from the config of the harness
+type Env struct {
+	Config  config.Config
+	DB      *mongodriver.Database
+	Users   ports.UserRepository
+	Jobs    ports.JobRepository
+	Audit   ports.AuditRepository
+	Storage ports.FileStorage
+	Limits  ports.LimitStore
+	keys    ports.SigningKeyStore
+}
+
+// Case a case of a table-driven test, run on an environment of its own seeded with its Users
+type Case struct {
+	Name  string
+	Users []entities.User
+	Run   func(t *testing.T, env *Env)
+}
+
+// Start pulls the images, runs the containers and waits for them to accept connections, initiating the replica set.
+// The containers already started are removed when any of them fails.
+func Start() (h *Harness, err error) {
+	// uses a sensible default on windows (tcp/http) and linux/osx (socket)
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to docker: %w", err)
+	}
+	if err = pool.Client.Ping(); err != nil {
+		return nil, fmt.Errorf("could not connect to docker: %w", err)
+	}
+	pool.MaxWait = maxWait
+
+	h = &Harness{pool: pool}
+	defer func() {
+		if err != nil {
+			h.Close()
+			h = nil
+		}
+	}()
+
+	if err = h.startMongo(); err != nil {
+		return
+	}
+	err = h.startRedis()
+	return
+}
+
+// Close disconnects from MongoDB and removes the containers
+func (h *Harness) Close() error {
+	if h.client != nil {
+		h.client.Disconnect(context.Background())
+	}
+
+	var errs []string
+	for _, resource := range h.resources {
+		if err := h.pool.Purge(resource); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	h.resources = nil
+	if len(errs) > 0 {
+		return fmt.Errorf("could not purge the containers: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Env creates the environment of the test, on a database named after it, dropped once the test and its subtests have run
+func (h *Harness) Env(t *testing.T) *Env {
+	t.Helper()
+
+	ctx := context.Background()
+	name := invalidName.ReplaceAllString(t.Name(), "_")
+	if len(name) > 60 {
+		name = name[len(name)-60:]
+	}
+	db := h.client.Database(name)
+	if err := db.Drop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Drop(context.Background())
+	})
+
+	env := &Env{Config: h.Config(), DB: db}
+	var err error
+	if env.Users, err = mongo.NewUserRepository(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	if env.Jobs, err = mongo.NewJobRepository(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	if env.Audit, err = mongo.NewAuditRepository(ctx, db, env.Config.Audit.MaxSize, env.Config.Audit.MaxDocuments, env.Config.Audit.Retention.Duration); err != nil {
+		t.Fatal(err)
+	}
+	if env.Limits, err = mongo.NewLimitStore(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	env.Storage = mongo.NewFileStorage(db)
+	env.keys = mongo.NewSigningKeyStore(db)
+	return env
+}
+
+// Run runs every case as a subtest, on an environment of its own seeded with the users of the case
+func (h *Harness) Run(t *testing.T, cases ...Case) {
+	t.Helper()
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			env := h.Env(t)
+			env.Seed(t, c.Users...)
+			c.Run(t, env)
+		})
+	}
+}
+
+// Config returns the config of the API on the containers, with the settings the services need to run
+func (h *Harness) Config() config.Config {
+	var c config.Config
+	c.Version = "Integration tests"
+	c.Environment = "Integration tests"
+	c.Database = "mongo"
+	c.DSN = h.MongoDSN
+	c.JWTSecret = jwtSecret
+	c.Timeout = utils.Duration{Duration: 30 * time.Second}
+	c.Storage.MaxUploadSize = 1 << 20
+	c.Audit.MaxSize = 1 << 20
+	c.Cache.RedisAddress = h.RedisAddress
+	c.Queue = config.Queue{
+		Workers:        1,
+		PollInterval:   utils.Duration{Duration: 50 * time.Millisecond},
+		MaxAttempts:    1,
+		InitialBackoff: utils.Duration{Duration: time.Second},
+		MaxBackoff:     utils.Duration{Duration: time.Second},
+		Timeout:        utils.Duration{Duration: time.Minute},
+	}
+	return c
+}
+
+// Seed creates the users as given, so their passwords must be hashed already, setting the IDs they are created with
+func (e *Env) Seed(t *testing.T, users ...entities.User) {
+	t.Helper()
+
+	now := time.Now().UTC()
+	for i := range users {
+		if users[i].CreatedAt.IsZero() {
+			users[i].CreatedAt, users[i].UpdatedAt = now, now
+		}
+		ID, err := e.Users.Create(context.Background(), users[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		users[i].ID = ID
+	}
+}
+
+// UserService returns a user service on the repositories of the environment, signing the tokens with the JWT secret of its config
+func (e *Env) UserService() ports.UserService {
+	keys := services.NewKeyService(e.Config, zerolog.Nop(), e.keys)
+	return services.NewUserService(e.Config, zerolog.Nop(), e.Users, e.Storage, e.Audit, keys, nil)
+}
+
+// JobService returns a job service on the job repository of the environment
+func (e *Env) JobService() ports.JobService {
+	return services.NewJobService(e.Config, e.Jobs)
+}
+
+// BackupService returns a backup service on the repositories of the environment
+func (e *Env) BackupService() ports.BackupService {
+	return services.NewBackupService(e.Config, zerolog.Nop(), e.Users, e.Storage, e.Jobs, e.Audit)
+}
+
+func (h *Harness) startMongo() error {
+	resource, err := h.pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: mongoRepository,
+		Tag:        mongoTag,
+		Cmd:        []string{"--replSet", mongoReplicaSet, "--bind_ip_all"},
+	}, autoRemove)
+	if err != nil {
+		return fmt.Errorf("could not start mongo: %w", err)
+	}
+	h.resources = append(h.resources, resource)
+
+	// the replica set is initiated with the address the container is reached at, so the driver can connect to its primary
+	port := resource.GetPort(mongoContainerPort)
+	initiate := fmt.Sprintf("rs.initiate({_id: '%s', members: [{_id: 0, host: 'localhost:27017'}]}).ok", mongoReplicaSet)
+	err = h.pool.Retry(func() error {
+		exitCode, err := resource.Exec([]string{"mongosh", "--quiet", "--eval", initiate}, dockertest.ExecOptions{})
+		if err != nil {
+			return err
+		}
+		if exitCode != 0 {
+			return fmt.Errorf("replica set initiation exited with code %d", exitCode)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not initiate the replica set: %w", err)
+	}
+
+	h.MongoDSN = fmt.Sprintf("mongodb://localhost:%s/harness?directConnection=true", port)
+	err = h.pool.Retry(func() error {
+		db, err := infrastructure.ConnectMongoDB(context.Background(), h.MongoDSN)
+		if err != nil {
+			return err
+		}
+		h.client = db.Client()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not connect to mongo: %w", err)
+	}
+	return nil
+}
+
+func (h *Harness) startRedis() error {
+	resource, err := h.pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: redisRepository,
+		Tag:        redisTag,
+	}, autoRemove)
+	if err != nil {
+		return fmt.Errorf("could not start redis: %w", err)
+	}
+	h.resources = append(h.resources, resource)
+
+	h.RedisAddress = fmt.Sprintf("localhost:%s", resource.GetPort(redisContainerPort))
+	err = h.pool.Retry(func() error {
+		_, err := redis.Ping(context.Background(), h.RedisAddress, "")
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("could not connect to redis: %w", err)
+	}
+	return nil
+}
+
+// autoRemove sets the containers to be removed once stopped, never restarting them
+func autoRemove(config *docker.HostConfig) {
+	config.AutoRemove = true
+	config.RestartPolicy = docker.RestartPolicy{
+		Name: "no",
+	}
+}
@@ -0,0 +1,67 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetAuditEvents_LoginFailed checks that Audit endpoint returns the failed logins, newest first
+func TestGetAuditEvents_LoginFailed(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser := getNewTestUser()
+
+		body := models.LoginUserReq{
+			Email:    testUser.Email,
+			Password: "test",
+		}
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		loginResp, err := http.Post(fmt.Sprintf("http://:%d/v1/users/login", cfg.Port), contentType, bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		loginResp.Body.Close()
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/audit?type=%s&take=1", cfg.Port, entities.AuditLoginFailed)
+
+		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		var response []models.AuditEventResp
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+		if assert.Len(t, response, 1) {
+			assert.Equal(t, testUser.Email, response[0].Details["email"])
+		}
+	})
+}
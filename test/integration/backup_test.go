@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBackupAndRestore_Ok checks that a backup of the users collection can be restored, discarding the users created after it
+func TestBackupAndRestore_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		backedUpUser := getNewTestUser()
+		err := insertUser(&backedUpUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		backup := startJob(t, cfg, fmt.Sprintf("http://:%d/v1/backups/users", cfg.Port))
+		waitJob(t, cfg, backup.ID)
+
+		newUser := getNewTestUser()
+		err = insertUser(&newUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		restore := startJob(t, cfg, fmt.Sprintf("http://:%d/v1/backups/users/%s/restore", cfg.Port, backup.Metadata["backup"]))
+		job := waitJob(t, cfg, restore.ID)
+
+		// Assert
+		assert.Equal(t, entities.JobStatusSucceeded, job.Status)
+		assert.GreaterOrEqual(t, job.Processed, int64(1))
+		_, err = findUser(backedUpUser.ID, cfg)
+		assert.Nil(t, err)
+		_, err = findUser(newUser.ID, cfg)
+		assert.NotNil(t, err)
+	})
+}
+
+// TestRestoreBackup_NotFound checks that Restore endpoint returns a bad request when the backup does not exist
+func TestRestoreBackup_NotFound(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/backups/users/nonexistent/restore", cfg.Port)
+
+		req, err := http.NewRequest(http.MethodPost, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusBadRequest, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+	})
+}
+
+// startJob calls an endpoint starting a job, returning the job once accepted
+func startJob(t *testing.T, cfg config.Config, url string) models.JobResp {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", nonExpiryToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if want, got := http.StatusAccepted, resp.StatusCode; want != got {
+		t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+	}
+
+	var job models.JobResp
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+	}
+	return job
+}
+
+// waitJob polls the job status endpoint until the job finishes, failing the test if it does not succeed
+func waitJob(t *testing.T, cfg config.Config, ID string) models.JobResp {
+	t.Helper()
+
+	url := fmt.Sprintf("http://:%d/v1/jobs/%s", cfg.Port, ID)
+	for i := 0; i < 50; i++ {
+		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var job models.JobResp
+		err = json.NewDecoder(resp.Body).Decode(&job)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", url, err)
+		}
+
+		switch job.Status {
+		case entities.JobStatusSucceeded:
+			return job
+		case entities.JobStatusFailed, entities.JobStatusDead:
+			t.Fatalf("job %s failed: %s", ID, job.Error)
+		}
+		<-time.After(100 * time.Millisecond)
+	}
+
+	t.Fatalf("job %s did not finish in time", ID)
+	return models.JobResp{}
+}
@@ -0,0 +1,31 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/test/contract"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestContract_Ok checks that the API honors its OpenAPI document, with the requests built from it and the fuzzed ones
+func TestContract_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+
+		// Act
+		report, err := contract.Verify(context.Background(), contract.Config{
+			BaseURL:    fmt.Sprintf("http://:%d", cfg.Port),
+			Token:      strings.TrimPrefix(nonExpiryToken, "Bearer "),
+			Iterations: 10,
+			Seed:       1,
+		})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Nil(t, report.Err())
+	})
+}
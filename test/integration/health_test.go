@@ -1,9 +1,13 @@
 package integration
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
 )
 
 // TestHealthCheck_Ok checks that Health endpoint returns the expected response when everything goes as expected
@@ -35,3 +39,37 @@ func TestHealthCheck_Ok(t *testing.T) {
 		}
 	})
 }
+
+// TestReadinessCheck_Ok checks that Readiness endpoint reports every dependency up when everything goes as expected
+func TestReadinessCheck_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+
+		// Act
+		url := fmt.Sprintf("http://:%d/readyz", cfg.Port)
+
+		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+		var response models.ReadinessResp
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+		assert.Equal(t, models.HealthStatusUp, response.Status)
+		assert.Contains(t, response.Checks, "database")
+	})
+}
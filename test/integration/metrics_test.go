@@ -0,0 +1,51 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetMetrics_Ok checks that Metrics endpoint returns the duration metrics of the commands run against MongoDB and its server status
+func TestGetMetrics_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/metrics", cfg.Port)
+
+		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		var response struct {
+			Commands     map[string]json.RawMessage `json:"mongo_commands"`
+			ServerStatus map[string]json.RawMessage `json:"mongo_server_status"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+		assert.Contains(t, response.Commands, entities.EntityNameUser+".createIndexes")
+		assert.Contains(t, response.ServerStatus, "connections")
+	}, "mongo")
+}
@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyRetention_DryRun checks that Retention endpoint reports the documents expired by every policy without expiring them
+func TestApplyRetention_DryRun(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/retention", cfg.Port)
+
+		req, err := http.NewRequest(http.MethodPost, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		var response models.RetentionResp
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+		assert.True(t, response.DryRun)
+		if assert.Len(t, response.Results, 1) {
+			assert.Equal(t, entities.EntityNameJob, response.Results[0].Collection)
+			assert.Empty(t, response.Results[0].Error)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/factory"
+	"github.com/sergicanet9/go-hexagonal-api/test/harness"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUserServiceCreate_Cases checks that the user service creates the users with a new email and refuses the taken ones
+func TestUserServiceCreate_Cases(t *testing.T) {
+	taken := factory.New(1).Users(1)
+	containers.Run(t,
+		harness.Case{
+			Name: "new email",
+			Run: func(t *testing.T, env *harness.Env) {
+				// Act
+				resp, err := env.UserService().Create(context.Background(), models.CreateUserReq{Name: "test", Surnames: "test", Email: "new@test.com", Password: "test"})
+
+				// Assert
+				assert.Nil(t, err)
+				user, err := env.UserService().GetByEmail(context.Background(), "new@test.com")
+				assert.Nil(t, err)
+				assert.Equal(t, resp.InsertedID, user.ID)
+			},
+		},
+		harness.Case{
+			Name:  "taken email",
+			Users: taken,
+			Run: func(t *testing.T, env *harness.Env) {
+				// Act
+				_, err := env.UserService().Create(context.Background(), models.CreateUserReq{Name: "test", Surnames: "test", Email: taken[0].Email, Password: "test"})
+
+				// Assert
+				assert.NotNil(t, err)
+			},
+		},
+	)
+}
+
+// TestUserServiceLogin_Ok checks that the user service logs in a seeded user, recording the login to the audit log
+func TestUserServiceLogin_Ok(t *testing.T) {
+	// Arrange
+	env := containers.Env(t)
+	user := factory.New(1).User()
+	env.Seed(t, user)
+
+	// Act
+	resp, err := env.UserService().Login(context.Background(), models.LoginUserReq{Email: user.Email, Password: factory.Password})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotEmpty(t, resp.Token)
+	events, err := env.Audit.Get(context.Background(), ports.AuditFilter{Type: entities.AuditLoginSucceeded}, nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+}
@@ -6,8 +6,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"strings"
 	"testing"
 	"time"
 
@@ -35,8 +39,8 @@ func TestLoginUser_Ok(t *testing.T) {
 
 		// Act
 		body := models.CreateUserReq{
-			Email:        "testlogin@test.com",
-			PasswordHash: "test",
+			Email:    "testlogin@test.com",
+			Password: "test",
 		}
 		b, err := json.Marshal(body)
 		if err != nil {
@@ -72,6 +76,56 @@ func TestLoginUser_Ok(t *testing.T) {
 	})
 }
 
+// TestLoginUser_CaseInsensitiveEmail checks that LoginUser endpoint finds the user when the email only differs in case from the stored one
+func TestLoginUser_CaseInsensitiveEmail(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser := getNewTestUser()
+		testUser.Email = strings.ToUpper(testUser.Email)
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		body := models.LoginUserReq{
+			Email:    strings.ToLower(testUser.Email),
+			Password: "test",
+		}
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		url := fmt.Sprintf("http://:%d/v1/users/login", cfg.Port)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+		var response models.LoginUserResp
+		if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+		assert.Equal(t, testUser.ID, response.User.ID)
+	})
+}
+
 // TestCreateUser checks that CreateUser endpoint returns the expected response when everything goes as expected
 func TestCreateUser_Ok(t *testing.T) {
 	Databases(t, func(t *testing.T, database string) {
@@ -80,7 +134,7 @@ func TestCreateUser_Ok(t *testing.T) {
 		testUser := getNewTestUser()
 
 		// Act
-		body := models.CreateUserReq(testUser)
+		body := createUserReq(testUser)
 		b, err := json.Marshal(body)
 		if err != nil {
 			t.Fatal(err)
@@ -130,8 +184,8 @@ func TestCreateManyUsers_Ok(t *testing.T) {
 
 		// Act
 		body := []models.CreateUserReq{
-			models.CreateUserReq(users[0]),
-			models.CreateUserReq(users[1]),
+			createUserReq(users[0]),
+			createUserReq(users[1]),
 		}
 		b, err := json.Marshal(body)
 		if err != nil {
@@ -211,6 +265,95 @@ func TestGetAllUsers_Ok(t *testing.T) {
 	})
 }
 
+// TestSearchUsers_Ok checks that SearchUsers endpoint returns the expected response when everything goes as expected
+func TestSearchUsers_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/users/search?q=%s&mode=autocomplete", cfg.Port, testUser.Email[:5])
+
+		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+		var response []models.UserResp
+		if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+		var found bool
+		for _, u := range response {
+			found = found || u.ID == testUser.ID
+		}
+		assert.True(t, found)
+	})
+}
+
+// TestGetNearbyUsers_Ok checks that GetNearbyUsers endpoint returns the expected response when everything goes as expected
+func TestGetNearbyUsers_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser := getNewTestUser()
+		location := entities.NewGeoPoint(rand.Float64()*360-180, rand.Float64()*180-90)
+		testUser.Location = &location
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/users/nearby?lng=%f&lat=%f&radius=100", cfg.Port, location.Coordinates[0], location.Coordinates[1])
+
+		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+		var response []models.UserResp
+		if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+		assert.Len(t, response, 1)
+		assert.Equal(t, testUser.ID, response[0].ID)
+	})
+}
+
 // TestGetUserByEmail_Ok checks that GetUserByEmail endpoint returns the expected response when everything goes as expected
 func TestGetUserByEmail_Ok(t *testing.T) {
 	Databases(t, func(t *testing.T, database string) {
@@ -307,7 +450,7 @@ func TestUpdateUser_Ok(t *testing.T) {
 		// Act
 		testUser.Name = "modified"
 		testUser.Surnames = "modified"
-		body := models.CreateUserReq(testUser)
+		body := createUserReq(testUser)
 		b, err := json.Marshal(body)
 		if err != nil {
 			t.Fatal(err)
@@ -344,6 +487,248 @@ func TestUpdateUser_Ok(t *testing.T) {
 	})
 }
 
+// TestUpsertUser_Ok checks that UpsertUser endpoint returns the expected response when everything goes as expected
+func TestUpsertUser_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		body := models.UpsertUserReq{
+			Name:     "upserted",
+			Surnames: "upserted",
+		}
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		url := fmt.Sprintf("http://:%d/v1/users/email/%s", cfg.Port, testUser.Email)
+
+		req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+		var response models.UpsertionResp
+		if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+		assert.Equal(t, testUser.ID, response.ID)
+
+		upsertedUser, err := findUser(testUser.ID, cfg)
+		if err != nil {
+			t.Fatalf("unexpected error while finding the upserted user: %s", err)
+		}
+		assert.Equal(t, body.Name, upsertedUser.Name)
+		assert.Equal(t, testUser.PasswordHash, upsertedUser.PasswordHash)
+	})
+}
+
+// TestMergeUsers_Ok checks that MergeUsers endpoint returns the expected response when everything goes as expected
+func TestMergeUsers_Ok(t *testing.T) {
+	notFoundError := map[string]error{"mongo": mongo.ErrNoDocuments, "postgres": sql.ErrNoRows}
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		targetUser := getNewTestUser()
+		err := insertUser(&targetUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sourceUser := getNewTestUser()
+		err = insertUser(&sourceUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		body := models.MergeUsersReq{
+			SourceID: sourceUser.ID,
+		}
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		url := fmt.Sprintf("http://:%d/v1/users/%s/merge", cfg.Port, targetUser.ID)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+		_, err = findUser(targetUser.ID, cfg)
+		assert.Nil(t, err)
+		_, err = findUser(sourceUser.ID, cfg)
+		assert.Equal(t, notFoundError[database], err)
+	})
+}
+
+// TestUnarchiveUser_NotArchived checks that UnarchiveUser endpoint returns a not found when the user is not archived
+func TestUnarchiveUser_NotArchived(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/users/%s/unarchive", cfg.Port, testUser.ID)
+
+		req, err := http.NewRequest(http.MethodPost, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusNotFound, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+		_, err = findUser(testUser.ID, cfg)
+		assert.Nil(t, err)
+	})
+}
+
+// TestUserAvatar_Ok checks that the avatar endpoints upload, return and delete the avatar of a user when everything goes as expected
+func TestUserAvatar_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedContent := []byte("test-avatar-content")
+
+		// Act
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="file"; filename="avatar.png"`)
+		header.Set("Content-Type", "image/png")
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = part.Write(expectedContent); err != nil {
+			t.Fatal(err)
+		}
+		if err = writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		url := fmt.Sprintf("http://:%d/v1/users/%s/avatar", cfg.Port, testUser.ID)
+
+		req, err := http.NewRequest(http.MethodPut, url, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		uploadResp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer uploadResp.Body.Close()
+
+		req, err = http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		getResp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer getResp.Body.Close()
+
+		req, err = http.NewRequest(http.MethodDelete, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		deleteResp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer deleteResp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, uploadResp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", uploadResp.Request.URL, want, got)
+		}
+		var file models.FileResp
+		if err := json.NewDecoder(uploadResp.Body).Decode(&file); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", uploadResp.Request.URL, err)
+		}
+		assert.Equal(t, int64(len(expectedContent)), file.Size)
+
+		if want, got := http.StatusOK, getResp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", getResp.Request.URL, want, got)
+		}
+		content, err := io.ReadAll(getResp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expectedContent, content)
+		assert.Equal(t, "image/png", getResp.Header.Get("Content-Type"))
+
+		if want, got := http.StatusOK, deleteResp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", deleteResp.Request.URL, want, got)
+		}
+	})
+}
+
 // TestDeleteUser_Ok checks that DeleteUser endpoint returns the expected response when everything goes as expected
 func TestDeleteUser_Ok(t *testing.T) {
 	notFoundError := map[string]error{"mongo": mongo.ErrNoDocuments, "postgres": sql.ErrNoRows}
@@ -357,7 +742,7 @@ func TestDeleteUser_Ok(t *testing.T) {
 		}
 
 		// Act
-		body := models.CreateUserReq(testUser)
+		body := createUserReq(testUser)
 		b, err := json.Marshal(body)
 		if err != nil {
 			t.Fatal(err)
@@ -417,11 +802,12 @@ func TestGetUserClaims_Ok(t *testing.T) {
 		if want, got := http.StatusOK, resp.StatusCode; want != got {
 			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
 		}
-		var response map[int]string
+		var response []models.ClaimResp
 		if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
 			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
 		}
 		assert.NotEmpty(t, response)
+		assert.Equal(t, "admin", response[0].Name)
 	})
 }
 
@@ -436,6 +822,18 @@ func getNewTestUser() entities.User {
 	}
 }
 
+// createUserReq returns the creation request of the user, with its password hash as the password
+func createUserReq(u entities.User) models.CreateUserReq {
+	return models.CreateUserReq{
+		Name:     u.Name,
+		Surnames: u.Surnames,
+		Email:    u.Email,
+		Password: u.PasswordHash,
+		Claims:   u.Claims,
+		Location: u.Location,
+	}
+}
+
 func insertUser(u *entities.User, cfg config.Config) error {
 	switch cfg.Database {
 	case "mongo":
@@ -454,14 +852,23 @@ func insertUser(u *entities.User, cfg config.Config) error {
 			return err
 		}
 
+		var location interface{}
+		if u.Location != nil {
+			b, err := json.Marshal(u.Location)
+			if err != nil {
+				return err
+			}
+			location = string(b)
+		}
+
 		q := `
-		INSERT INTO users (name, surnames, email, password_hash, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (name, surnames, email, password_hash, location, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
 			RETURNING id, name, surnames, email, password_hash, created_at, updated_at;
 		`
 
 		row := db.QueryRowContext(
-			context.Background(), q, u.Name, u.Surnames, u.Email, u.PasswordHash, time.Now().UTC(), time.Now().UTC(),
+			context.Background(), q, u.Name, u.Surnames, u.Email, u.PasswordHash, location, time.Now().UTC(), time.Now().UTC(),
 		)
 
 		err = row.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
@@ -4,7 +4,9 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
+	entities "github.com/sergicanet9/go-hexagonal-api/core/entities"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,6 +15,41 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// Archive provides a mock function with given fields: ctx, inactiveSince, at
+func (_m *UserRepository) Archive(ctx context.Context, inactiveSince time.Time, at time.Time) (int64, error) {
+	ret := _m.Called(ctx, inactiveSince, at)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) int64); ok {
+		r0 = rf(ctx, inactiveSince, at)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, inactiveSince, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ArchiveByID provides a mock function with given fields: ctx, ID, at
+func (_m *UserRepository) ArchiveByID(ctx context.Context, ID string, at time.Time) error {
+	ret := _m.Called(ctx, ID, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, ID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, entity
 func (_m *UserRepository) Create(ctx context.Context, entity interface{}) (string, error) {
 	ret := _m.Called(ctx, entity)
@@ -71,6 +108,20 @@ func (_m *UserRepository) Delete(ctx context.Context, ID string) error {
 	return r0
 }
 
+// Dump provides a mock function with given fields: ctx, fn
+func (_m *UserRepository) Dump(ctx context.Context, fn func(user entities.User) error) error {
+	ret := _m.Called(ctx, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(user entities.User) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ctx, filter, skip, take
 func (_m *UserRepository) Get(ctx context.Context, filter map[string]interface{}, skip *int, take *int) ([]interface{}, error) {
 	ret := _m.Called(ctx, filter, skip, take)
@@ -117,6 +168,147 @@ func (_m *UserRepository) GetByID(ctx context.Context, ID string) (interface{},
 	return r0, r1
 }
 
+// GetByIDProjected provides a mock function with given fields: ctx, ID, projection
+func (_m *UserRepository) GetByIDProjected(ctx context.Context, ID string, projection map[string]interface{}) (interface{}, error) {
+	ret := _m.Called(ctx, ID, projection)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]interface{}) interface{}); ok {
+		r0 = rf(ctx, ID, projection)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, map[string]interface{}) error); ok {
+		r1 = rf(ctx, ID, projection)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNearby provides a mock function with given fields: ctx, longitude, latitude, radius, projection, skip, take
+func (_m *UserRepository) GetNearby(ctx context.Context, longitude float64, latitude float64, radius float64, projection map[string]interface{}, skip *int, take *int) ([]interface{}, error) {
+	ret := _m.Called(ctx, longitude, latitude, radius, projection, skip, take)
+
+	var r0 []interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, float64, float64, float64, map[string]interface{}, *int, *int) []interface{}); ok {
+		r0 = rf(ctx, longitude, latitude, radius, projection, skip, take)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, float64, float64, float64, map[string]interface{}, *int, *int) error); ok {
+		r1 = rf(ctx, longitude, latitude, radius, projection, skip, take)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProjected provides a mock function with given fields: ctx, filter, projection, skip, take
+func (_m *UserRepository) GetProjected(ctx context.Context, filter map[string]interface{}, projection map[string]interface{}, skip *int, take *int) ([]interface{}, error) {
+	ret := _m.Called(ctx, filter, projection, skip, take)
+
+	var r0 []interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}, map[string]interface{}, *int, *int) []interface{}); ok {
+		r0 = rf(ctx, filter, projection, skip, take)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}, map[string]interface{}, *int, *int) error); ok {
+		r1 = rf(ctx, filter, projection, skip, take)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, users
+func (_m *UserRepository) Restore(ctx context.Context, users []entities.User) error {
+	ret := _m.Called(ctx, users)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []entities.User) error); ok {
+		r0 = rf(ctx, users)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, text, autocomplete, projection, skip, take
+func (_m *UserRepository) Search(ctx context.Context, text string, autocomplete bool, projection map[string]interface{}, skip *int, take *int) ([]interface{}, error) {
+	ret := _m.Called(ctx, text, autocomplete, projection, skip, take)
+
+	var r0 []interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, map[string]interface{}, *int, *int) []interface{}); ok {
+		r0 = rf(ctx, text, autocomplete, projection, skip, take)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, map[string]interface{}, *int, *int) error); ok {
+		r1 = rf(ctx, text, autocomplete, projection, skip, take)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Stats provides a mock function with given fields: ctx, activeSince
+func (_m *UserRepository) Stats(ctx context.Context, activeSince time.Time) (entities.UserStats, error) {
+	ret := _m.Called(ctx, activeSince)
+
+	var r0 entities.UserStats
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) entities.UserStats); ok {
+		r0 = rf(ctx, activeSince)
+	} else {
+		r0 = ret.Get(0).(entities.UserStats)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, activeSince)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unarchive provides a mock function with given fields: ctx, ID, at
+func (_m *UserRepository) Unarchive(ctx context.Context, ID string, at time.Time) error {
+	ret := _m.Called(ctx, ID, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, ID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, ID, entity
 func (_m *UserRepository) Update(ctx context.Context, ID string, entity interface{}) error {
 	ret := _m.Called(ctx, ID, entity)
@@ -131,6 +323,97 @@ func (_m *UserRepository) Update(ctx context.Context, ID string, entity interfac
 	return r0
 }
 
+// UpdateBillingCustomer provides a mock function with given fields: ctx, ID, customerID
+func (_m *UserRepository) UpdateBillingCustomer(ctx context.Context, ID string, customerID string) error {
+	ret := _m.Called(ctx, ID, customerID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, ID, customerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLastLogin provides a mock function with given fields: ctx, ID, at
+func (_m *UserRepository) UpdateLastLogin(ctx context.Context, ID string, at time.Time) error {
+	ret := _m.Called(ctx, ID, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, ID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateNames provides a mock function with given fields: ctx, ID, name, surnames, at
+func (_m *UserRepository) UpdateNames(ctx context.Context, ID string, name string, surnames string, at time.Time) error {
+	ret := _m.Called(ctx, ID, name, surnames, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Time) error); ok {
+		r0 = rf(ctx, ID, name, surnames, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSubscription provides a mock function with given fields: ctx, customerID, status, at
+func (_m *UserRepository) UpdateSubscription(ctx context.Context, customerID string, status string, at time.Time) error {
+	ret := _m.Called(ctx, customerID, status, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, customerID, status, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Upsert provides a mock function with given fields: ctx, filter, entity
+func (_m *UserRepository) Upsert(ctx context.Context, filter map[string]interface{}, entity interface{}) (string, error) {
+	ret := _m.Called(ctx, filter, entity)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}, interface{}) string); ok {
+		r0 = rf(ctx, filter, entity)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}, interface{}) error); ok {
+		r1 = rf(ctx, filter, entity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithTransaction provides a mock function with given fields: ctx, fn
+func (_m *UserRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(ctx context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewUserRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 
 	models "github.com/sergicanet9/go-hexagonal-api/core/models"
 	mock "github.com/stretchr/testify/mock"
@@ -14,6 +15,76 @@ type UserService struct {
 	mock.Mock
 }
 
+// ArchiveInactive provides a mock function with given fields: ctx
+func (_m *UserService) ArchiveInactive(ctx context.Context) (models.ArchivalResp, error) {
+	ret := _m.Called(ctx)
+
+	var r0 models.ArchivalResp
+	if rf, ok := ret.Get(0).(func(context.Context) models.ArchivalResp); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(models.ArchivalResp)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Authorize provides a mock function with given fields: ctx, caller, ID
+func (_m *UserService) Authorize(ctx context.Context, caller models.Caller, ID string) error {
+	ret := _m.Called(ctx, caller, ID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Caller, string) error); ok {
+		r0 = rf(ctx, caller, ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AuthorizeUpdate provides a mock function with given fields: ctx, caller, ID, user
+func (_m *UserService) AuthorizeUpdate(ctx context.Context, caller models.Caller, ID string, user models.UpdateUserReq) error {
+	ret := _m.Called(ctx, caller, ID, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Caller, string, models.UpdateUserReq) error); ok {
+		r0 = rf(ctx, caller, ID, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ComputeStats provides a mock function with given fields: ctx
+func (_m *UserService) ComputeStats(ctx context.Context) (models.UserStatsResp, error) {
+	ret := _m.Called(ctx)
+
+	var r0 models.UserStatsResp
+	if rf, ok := ret.Get(0).(func(context.Context) models.UserStatsResp); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(models.UserStatsResp)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, user
 func (_m *UserService) Create(ctx context.Context, user models.CreateUserReq) (models.CreationResp, error) {
 	ret := _m.Called(ctx, user)
@@ -35,6 +106,27 @@ func (_m *UserService) Create(ctx context.Context, user models.CreateUserReq) (m
 	return r0, r1
 }
 
+// CreateAvatarUploadURL provides a mock function with given fields: ctx, ID, req
+func (_m *UserService) CreateAvatarUploadURL(ctx context.Context, ID string, req models.AvatarUploadURLReq) (models.PresignedURLResp, error) {
+	ret := _m.Called(ctx, ID, req)
+
+	var r0 models.PresignedURLResp
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.AvatarUploadURLReq) models.PresignedURLResp); ok {
+		r0 = rf(ctx, ID, req)
+	} else {
+		r0 = ret.Get(0).(models.PresignedURLResp)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.AvatarUploadURLReq) error); ok {
+		r1 = rf(ctx, ID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateMany provides a mock function with given fields: ctx, users
 func (_m *UserService) CreateMany(ctx context.Context, users []models.CreateUserReq) (models.MultiCreationResp, error) {
 	ret := _m.Called(ctx, users)
@@ -70,13 +162,27 @@ func (_m *UserService) Delete(ctx context.Context, ID string) error {
 	return r0
 }
 
-// GetAll provides a mock function with given fields: ctx
-func (_m *UserService) GetAll(ctx context.Context) ([]models.UserResp, error) {
-	ret := _m.Called(ctx)
+// DeleteAvatar provides a mock function with given fields: ctx, ID
+func (_m *UserService) DeleteAvatar(ctx context.Context, ID string) error {
+	ret := _m.Called(ctx, ID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx, caller
+func (_m *UserService) GetAll(ctx context.Context, caller models.Caller) ([]models.UserResp, error) {
+	ret := _m.Called(ctx, caller)
 
 	var r0 []models.UserResp
-	if rf, ok := ret.Get(0).(func(context.Context) []models.UserResp); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Caller) []models.UserResp); ok {
+		r0 = rf(ctx, caller)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.UserResp)
@@ -84,8 +190,59 @@ func (_m *UserService) GetAll(ctx context.Context) ([]models.UserResp, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, models.Caller) error); ok {
+		r1 = rf(ctx, caller)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAvatar provides a mock function with given fields: ctx, ID
+func (_m *UserService) GetAvatar(ctx context.Context, ID string) (io.ReadCloser, models.FileResp, error) {
+	ret := _m.Called(ctx, ID)
+
+	var r0 io.ReadCloser
+	if rf, ok := ret.Get(0).(func(context.Context, string) io.ReadCloser); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	var r1 models.FileResp
+	if rf, ok := ret.Get(1).(func(context.Context, string) models.FileResp); ok {
+		r1 = rf(ctx, ID)
+	} else {
+		r1 = ret.Get(1).(models.FileResp)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, ID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAvatarURL provides a mock function with given fields: ctx, ID
+func (_m *UserService) GetAvatarURL(ctx context.Context, ID string) (models.PresignedURLResp, error) {
+	ret := _m.Called(ctx, ID)
+
+	var r0 models.PresignedURLResp
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.PresignedURLResp); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Get(0).(models.PresignedURLResp)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -135,16 +292,39 @@ func (_m *UserService) GetByID(ctx context.Context, ID string) (models.UserResp,
 	return r0, r1
 }
 
-// GetUserClaims provides a mock function with given fields: ctx
-func (_m *UserService) GetUserClaims(ctx context.Context) map[int]string {
-	ret := _m.Called(ctx)
+// GetNearby provides a mock function with given fields: ctx, caller, req
+func (_m *UserService) GetNearby(ctx context.Context, caller models.Caller, req models.NearbyUsersReq) ([]models.UserResp, error) {
+	ret := _m.Called(ctx, caller, req)
 
-	var r0 map[int]string
-	if rf, ok := ret.Get(0).(func(context.Context) map[int]string); ok {
-		r0 = rf(ctx)
+	var r0 []models.UserResp
+	if rf, ok := ret.Get(0).(func(context.Context, models.Caller, models.NearbyUsersReq) []models.UserResp); ok {
+		r0 = rf(ctx, caller, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UserResp)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.Caller, models.NearbyUsersReq) error); ok {
+		r1 = rf(ctx, caller, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserClaims provides a mock function with given fields: ctx, req
+func (_m *UserService) GetUserClaims(ctx context.Context, req models.GetClaimsReq) []models.ClaimResp {
+	ret := _m.Called(ctx, req)
+
+	var r0 []models.ClaimResp
+	if rf, ok := ret.Get(0).(func(context.Context, models.GetClaimsReq) []models.ClaimResp); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[int]string)
+			r0 = ret.Get(0).([]models.ClaimResp)
 		}
 	}
 
@@ -172,6 +352,57 @@ func (_m *UserService) Login(ctx context.Context, credentials models.LoginUserRe
 	return r0, r1
 }
 
+// Merge provides a mock function with given fields: ctx, ID, req
+func (_m *UserService) Merge(ctx context.Context, ID string, req models.MergeUsersReq) error {
+	ret := _m.Called(ctx, ID, req)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.MergeUsersReq) error); ok {
+		r0 = rf(ctx, ID, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, caller, req
+func (_m *UserService) Search(ctx context.Context, caller models.Caller, req models.SearchUsersReq) ([]models.UserResp, error) {
+	ret := _m.Called(ctx, caller, req)
+
+	var r0 []models.UserResp
+	if rf, ok := ret.Get(0).(func(context.Context, models.Caller, models.SearchUsersReq) []models.UserResp); ok {
+		r0 = rf(ctx, caller, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UserResp)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.Caller, models.SearchUsersReq) error); ok {
+		r1 = rf(ctx, caller, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unarchive provides a mock function with given fields: ctx, ID
+func (_m *UserService) Unarchive(ctx context.Context, ID string) error {
+	ret := _m.Called(ctx, ID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, ID, user
 func (_m *UserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) error {
 	ret := _m.Called(ctx, ID, user)
@@ -186,6 +417,48 @@ func (_m *UserService) Update(ctx context.Context, ID string, user models.Update
 	return r0
 }
 
+// UploadAvatar provides a mock function with given fields: ctx, ID, avatar
+func (_m *UserService) UploadAvatar(ctx context.Context, ID string, avatar models.UploadAvatarReq) (models.FileResp, error) {
+	ret := _m.Called(ctx, ID, avatar)
+
+	var r0 models.FileResp
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.UploadAvatarReq) models.FileResp); ok {
+		r0 = rf(ctx, ID, avatar)
+	} else {
+		r0 = ret.Get(0).(models.FileResp)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.UploadAvatarReq) error); ok {
+		r1 = rf(ctx, ID, avatar)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Upsert provides a mock function with given fields: ctx, email, user
+func (_m *UserService) Upsert(ctx context.Context, email string, user models.UpsertUserReq) (models.UpsertionResp, error) {
+	ret := _m.Called(ctx, email, user)
+
+	var r0 models.UpsertionResp
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.UpsertUserReq) models.UpsertionResp); ok {
+		r0 = rf(ctx, email, user)
+	} else {
+		r0 = ret.Get(0).(models.UpsertionResp)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.UpsertUserReq) error); ok {
+		r1 = rf(ctx, email, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewUserService interface {
 	mock.TestingT
 	Cleanup(func())
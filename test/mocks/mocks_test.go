@@ -0,0 +1,69 @@
+package mocks
+
+import "github.com/sergicanet9/go-hexagonal-api/core/ports"
+
+// the mocks are asserted to implement their ports, so a port changed without regenerating its mock fails the tests
+var (
+	_ ports.APIKeyRepository                  = (*APIKeyRepository)(nil)
+	_ ports.APIKeyService                     = (*APIKeyService)(nil)
+	_ ports.AdminNotifier                     = (*AdminNotifier)(nil)
+	_ ports.AnalyticsTracker                  = (*AnalyticsTracker)(nil)
+	_ ports.AuditRepository                   = (*AuditRepository)(nil)
+	_ ports.AuditService                      = (*AuditService)(nil)
+	_ ports.BackupService                     = (*BackupService)(nil)
+	_ ports.BillingProvider                   = (*BillingProvider)(nil)
+	_ ports.BillingService                    = (*BillingService)(nil)
+	_ ports.CaptureRepository                 = (*CaptureRepository)(nil)
+	_ ports.CaptureService                    = (*CaptureService)(nil)
+	_ ports.CredentialVerifier                = (*CredentialVerifier)(nil)
+	_ ports.DashboardService                  = (*DashboardService)(nil)
+	_ ports.DeviceRepository                  = (*DeviceRepository)(nil)
+	_ ports.DeviceService                     = (*DeviceService)(nil)
+	_ ports.DirectorySource                   = (*DirectorySource)(nil)
+	_ ports.DirectorySyncService              = (*DirectorySyncService)(nil)
+	_ ports.EmailSender                       = (*EmailSender)(nil)
+	_ ports.EmailVerifier                     = (*EmailVerifier)(nil)
+	_ ports.ErrorReporter                     = (*ErrorReporter)(nil)
+	_ ports.EventPublisher                    = (*EventPublisher)(nil)
+	_ ports.FilePresigner                     = (*FilePresigner)(nil)
+	_ ports.FileStorage                       = (*FileStorage)(nil)
+	_ ports.GeoIPLocator                      = (*GeoIPLocator)(nil)
+	_ ports.HealthChecker                     = (*HealthChecker)(nil)
+	_ ports.HealthService                     = (*HealthService)(nil)
+	_ ports.Invalidator                       = (*Invalidator)(nil)
+	_ ports.JobRepository                     = (*JobRepository)(nil)
+	_ ports.JobService                        = (*JobService)(nil)
+	_ ports.KeyService                        = (*KeyService)(nil)
+	_ ports.LeaseStore                        = (*LeaseStore)(nil)
+	_ ports.LimitStore                        = (*LimitStore)(nil)
+	_ ports.Mailer                            = (*Mailer)(nil)
+	_ ports.MaintenanceService                = (*MaintenanceService)(nil)
+	_ ports.MaintenanceStore                  = (*MaintenanceStore)(nil)
+	_ ports.MalwareScanner                    = (*MalwareScanner)(nil)
+	_ ports.NotificationPreferencesRepository = (*NotificationPreferencesRepository)(nil)
+	_ ports.NotificationPreferencesService    = (*NotificationPreferencesService)(nil)
+	_ ports.Notifier                          = (*Notifier)(nil)
+	_ ports.OrganizationRepository            = (*OrganizationRepository)(nil)
+	_ ports.OrganizationService               = (*OrganizationService)(nil)
+	_ ports.PushSender                        = (*PushSender)(nil)
+	_ ports.RepositoryHook                    = (*RepositoryHook)(nil)
+	_ ports.RoleRepository                    = (*RoleRepository)(nil)
+	_ ports.RoleService                       = (*RoleService)(nil)
+	_ ports.RetentionRepository               = (*RetentionRepository)(nil)
+	_ ports.RetentionService                  = (*RetentionService)(nil)
+	_ ports.SIEMSink                          = (*SIEMSink)(nil)
+	_ ports.SMSSender                         = (*SMSSender)(nil)
+	_ ports.SMSService                        = (*SMSService)(nil)
+	_ ports.ScanService                       = (*ScanService)(nil)
+	_ ports.Scheduler                         = (*Scheduler)(nil)
+	_ ports.SearchIndex                       = (*SearchIndex)(nil)
+	_ ports.SearchService                     = (*SearchService)(nil)
+	_ ports.SecurityPolicyRepository          = (*SecurityPolicyRepository)(nil)
+	_ ports.SecurityPolicyService             = (*SecurityPolicyService)(nil)
+	_ ports.SigningKeyStore                   = (*SigningKeyStore)(nil)
+	_ ports.TokenKeys                         = (*TokenKeys)(nil)
+	_ ports.Transactor                        = (*Transactor)(nil)
+	_ ports.UserChangeStream                  = (*UserChangeStream)(nil)
+	_ ports.UserRepository                    = (*UserRepository)(nil)
+	_ ports.UserService                       = (*UserService)(nil)
+)